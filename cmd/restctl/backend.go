@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	pgdb "github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/client"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// backend is the set of operations restctl needs; both *client.Client and directBackend implement it.
+type backend interface {
+	ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page usecase.PageRequest) (*usecase.PageResponse[*domain.Restaurant], error)
+	CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error)
+	ExportRestaurant(ctx context.Context, id string) (*usecase.RestaurantBundle, error)
+	ImportRestaurantBundle(ctx context.Context, bundle *usecase.RestaurantBundle) (string, error)
+	CancelBooking(ctx context.Context, id string) error
+	GenerateAvailability(ctx context.Context, params usecase.GenerateAvailabilityParams) ([]*domain.Availability, error)
+	ResendNotification(ctx context.Context, notificationID string) error
+	ExportChanges(ctx context.Context, since time.Time, entities []domain.ExportEntity, fn func(*domain.ExportRecord) error) (time.Time, error)
+}
+
+type directBackend struct {
+	restaurants  usecase.RestaurantUseCase
+	bookings     usecase.BookingUseCase
+	availability usecase.AvailabilityUseCase
+	notification usecase.NotificationUseCase
+	export       usecase.ExportUseCase
+	log          ports.LoggerPort
+}
+
+func newBackend(ctx context.Context, opts globalOptions) (backend, func(), error) {
+	if !opts.direct {
+		return client.New(opts.apiURL), func() {}, nil
+	}
+
+	log, err := logger.NewLogger()
+	if err != nil {
+		return nil, nil, err
+	}
+	log.SetLevel(ports.WarnLevel)
+
+	var cfg configs.PostgresConfig
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("read database config: %w", err)
+	}
+
+	var contacts configs.ContactsConfig
+	if err := cleanenv.ReadEnv(&contacts); err != nil {
+		return nil, nil, fmt.Errorf("read contacts config: %w", err)
+	}
+
+	db, err := pgdb.New(logger.NewContext(ctx, log), &cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repoFactory := postgres.NewRepositoryFactory(db, idgen.UUID{})
+	restaurantRepo := repoFactory.Restaurant()
+	workingHoursRepo := repoFactory.WorkingHours()
+	availabilityRepo := repoFactory.Availability()
+	bookingRepo := repoFactory.Booking()
+	notificationService := postgres.NewNotificationService(repoFactory.Notification())
+
+	b := &directBackend{
+		restaurants:  usecase.NewRestaurantUseCase(restaurantRepo, workingHoursRepo, repoFactory.RestaurantClosure(), repoFactory.SpecialDay(), repoFactory.Transactor(), contacts.PhoneRegion, clock.System{}),
+		bookings:     usecase.NewBookingUseCase(bookingRepo, availabilityRepo, repoFactory.Table(), notificationService, repoFactory.Transactor(), clock.System{}),
+		availability: usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, repoFactory.RestaurantClosure(), repoFactory.SpecialDay(), bookingRepo, repoFactory.Transactor(), clock.System{}),
+		notification: usecase.NewNotificationUseCase(postgres.NewMockEmailService(), notificationService, repoFactory.NotificationSettings(), nil),
+		export:       usecase.NewExportUseCase(repoFactory.Export(), clock.System{}),
+		log:          log,
+	}
+
+	return b, func() { _ = db.Close(ctx) }, nil
+}
+
+func (b *directBackend) ctx(ctx context.Context) context.Context {
+	return logger.NewContext(ctx, b.log)
+}
+
+func (b *directBackend) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page usecase.PageRequest) (*usecase.PageResponse[*domain.Restaurant], error) {
+	return b.restaurants.ListRestaurants(b.ctx(ctx), filter, page)
+}
+
+func (b *directBackend) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
+	ctx = b.ctx(ctx)
+
+	id, err := b.restaurants.CreateRestaurant(ctx, restaurant)
+	if err != nil {
+		return "", err
+	}
+
+	for _, fact := range restaurant.Facts {
+		if _, err := b.restaurants.AddFact(ctx, domain.RestaurantID(id), fact.Content, fact.Locale); err != nil {
+			return id, err
+		}
+	}
+
+	return id, nil
+}
+
+func (b *directBackend) ExportRestaurant(ctx context.Context, id string) (*usecase.RestaurantBundle, error) {
+	return b.restaurants.ExportRestaurant(b.ctx(ctx), domain.RestaurantID(id))
+}
+
+func (b *directBackend) ImportRestaurantBundle(ctx context.Context, bundle *usecase.RestaurantBundle) (string, error) {
+	return b.restaurants.ImportRestaurantBundle(b.ctx(ctx), bundle)
+}
+
+func (b *directBackend) CancelBooking(ctx context.Context, id string) error {
+	return b.bookings.CancelBooking(b.ctx(ctx), domain.BookingID(id))
+}
+
+func (b *directBackend) GenerateAvailability(ctx context.Context, params usecase.GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	return b.availability.GenerateAvailability(b.ctx(ctx), params)
+}
+
+func (b *directBackend) ResendNotification(ctx context.Context, notificationID string) error {
+	return b.notification.ResendNotification(b.ctx(ctx), notificationID)
+}
+
+func (b *directBackend) ExportChanges(ctx context.Context, since time.Time, entities []domain.ExportEntity, fn func(*domain.ExportRecord) error) (time.Time, error) {
+	// The export reads every tenant's records, as admins do.
+	return b.export.ExportChanges(tenant.SystemContext(b.ctx(ctx)), since, entities, fn)
+}
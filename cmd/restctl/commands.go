@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+type command func(ctx context.Context, b backend, p *printer, args []string) error
+
+var commands = map[string]command{
+	"restaurant list":       restaurantList,
+	"restaurant create":     restaurantCreate,
+	"restaurant export":     restaurantExport,
+	"restaurant import":     restaurantImport,
+	"booking cancel":        bookingCancel,
+	"availability generate": availabilityGenerate,
+	"notification resend":   notificationResend,
+	"data export":           dataExport,
+}
+
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func restaurantList(ctx context.Context, b backend, p *printer, args []string) error {
+	fs := flag.NewFlagSet("restaurant list", flag.ContinueOnError)
+	cursor := fs.String("cursor", "", "next cursor printed by the previous page")
+	limit := fs.Int("limit", 20, "maximum number of restaurants")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	page, err := b.ListRestaurants(ctx, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: *cursor, Limit: *limit})
+	if err != nil {
+		return err
+	}
+	if page.HasMore() {
+		fmt.Fprintf(os.Stderr, "next cursor: %s\n", page.NextCursor)
+	}
+	restaurants := page.Items
+
+	rows := make([][]string, 0, len(restaurants))
+	for _, r := range restaurants {
+		rows = append(rows, []string{r.ID, r.Name, string(r.Cuisine), r.Address, r.ContactEmail, r.ContactPhone})
+	}
+
+	return p.print(restaurants, []string{"ID", "NAME", "CUISINE", "ADDRESS", "EMAIL", "PHONE"}, rows)
+}
+
+func restaurantCreate(ctx context.Context, b backend, p *printer, args []string) error {
+	fs := flag.NewFlagSet("restaurant create", flag.ContinueOnError)
+	restaurant := &domain.Restaurant{}
+	var cuisine string
+	var facts stringsFlag
+	fs.StringVar(&restaurant.Name, "name", "", "restaurant name")
+	fs.StringVar(&restaurant.Address, "address", "", "restaurant address")
+	fs.StringVar(&cuisine, "cuisine", "", "cuisine")
+	fs.StringVar(&restaurant.Description, "description", "", "description")
+	fs.StringVar(&restaurant.ContactEmail, "email", "", "contact email")
+	fs.StringVar(&restaurant.ContactPhone, "phone", "", "contact phone")
+	fs.Var(&facts, "fact", "fact about the restaurant, may be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if restaurant.Name == "" || restaurant.Address == "" || cuisine == "" ||
+		restaurant.ContactEmail == "" || restaurant.ContactPhone == "" {
+		return fmt.Errorf("%w: -name, -address, -cuisine, -email and -phone are required", errUsage)
+	}
+
+	restaurant.Cuisine = domain.Cuisine(cuisine)
+	for _, fact := range facts {
+		restaurant.Facts = append(restaurant.Facts, domain.Fact{Content: fact})
+	}
+
+	id, err := b.CreateRestaurant(ctx, restaurant)
+	if err != nil {
+		return err
+	}
+
+	return p.status(id, "created")
+}
+
+func bookingCancel(ctx context.Context, b backend, p *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: booking cancel expects a booking ID", errUsage)
+	}
+
+	id, err := domain.ParseBookingID(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	if err := b.CancelBooking(ctx, id.String()); err != nil {
+		return err
+	}
+
+	return p.status(id.String(), string(domain.BookingStatusCancelled))
+}
+
+func availabilityGenerate(ctx context.Context, b backend, p *printer, args []string) error {
+	fs := flag.NewFlagSet("availability generate", flag.ContinueOnError)
+	restaurantID := fs.String("restaurant", "", "restaurant ID")
+	from := fs.String("from", "", "first date, YYYY-MM-DD")
+	to := fs.String("to", "", "last date, YYYY-MM-DD")
+	slot := fs.Duration("slot", time.Hour, "slot length")
+	capacity := fs.Int("capacity", 0, "seats per slot")
+	dryRun := fs.Bool("dry-run", false, "print the slots without saving them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fromDate, err := time.Parse(time.DateOnly, *from)
+	if err != nil {
+		return fmt.Errorf("%w: invalid -from: %v", errUsage, err)
+	}
+	toDate, err := time.Parse(time.DateOnly, *to)
+	if err != nil {
+		return fmt.Errorf("%w: invalid -to: %v", errUsage, err)
+	}
+	if *restaurantID == "" || *capacity < 1 {
+		return fmt.Errorf("%w: -restaurant and a positive -capacity are required", errUsage)
+	}
+
+	generated, err := b.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+		RestaurantID: *restaurantID,
+		From:         fromDate,
+		To:           toDate,
+		SlotDuration: *slot,
+		Capacity:     *capacity,
+		DryRun:       *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(generated))
+	for _, a := range generated {
+		rows = append(rows, []string{a.Date.Format(time.DateOnly), a.TimeSlot, strconv.Itoa(a.Capacity), strconv.Itoa(a.Reserved)})
+	}
+
+	return p.print(generated, []string{"DATE", "SLOT", "CAPACITY", "RESERVED"}, rows)
+}
+
+func restaurantExport(ctx context.Context, b backend, p *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: restaurant export expects a restaurant ID", errUsage)
+	}
+
+	id, err := domain.ParseRestaurantID(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	bundle, err := b.ExportRestaurant(ctx, id.String())
+	if err != nil {
+		return err
+	}
+
+	return p.json(bundle)
+}
+
+func restaurantImport(ctx context.Context, b backend, p *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: restaurant import expects a bundle file or - for stdin", errUsage)
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var bundle usecase.RestaurantBundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return fmt.Errorf("read bundle: %w", err)
+	}
+
+	id, err := b.ImportRestaurantBundle(ctx, &bundle)
+	if err != nil {
+		return err
+	}
+
+	return p.status(id, "imported")
+}
+
+func notificationResend(ctx context.Context, b backend, p *printer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: notification resend expects a notification ID", errUsage)
+	}
+
+	if err := b.ResendNotification(ctx, args[0]); err != nil {
+		return err
+	}
+
+	return p.status(args[0], "resent")
+}
+
+// dataExport writes the records changed since the checkpoint to stdout as newline-delimited JSON.
+// The checkpoint is read from and, once the export is complete, saved to the -checkpoint file, so
+// running the command again exports only what changed in between.
+func dataExport(ctx context.Context, b backend, p *printer, args []string) error {
+	fs := flag.NewFlagSet("data export", flag.ContinueOnError)
+	checkpointFile := fs.String("checkpoint", "", "file keeping the checkpoint between exports")
+	sinceValue := fs.String("since", "", "export records updated after this RFC 3339 time instead of the checkpoint")
+	var entities stringsFlag
+	fs.Var(&entities, "entity", "entity to export, may be repeated; all by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var since time.Time
+	if *checkpointFile != "" && *sinceValue == "" {
+		data, err := os.ReadFile(*checkpointFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		*sinceValue = strings.TrimSpace(string(data))
+	}
+	if *sinceValue != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339Nano, *sinceValue); err != nil {
+			return fmt.Errorf("%w: invalid checkpoint: %v", errUsage, err)
+		}
+	}
+
+	selected := make([]domain.ExportEntity, 0, len(entities))
+	for _, entity := range entities {
+		selected = append(selected, domain.ExportEntity(entity))
+	}
+
+	enc := json.NewEncoder(p.w)
+	checkpoint, err := b.ExportChanges(ctx, since, selected, func(record *domain.ExportRecord) error {
+		return enc.Encode(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	if *checkpointFile == "" {
+		fmt.Fprintf(os.Stderr, "checkpoint: %s\n", checkpoint.Format(time.RFC3339Nano))
+		return nil
+	}
+
+	return writeFileAtomic(*checkpointFile, []byte(checkpoint.Format(time.RFC3339Nano)+"\n"))
+}
+
+// writeFileAtomic replaces the file with data, leaving the old content in place on failure.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
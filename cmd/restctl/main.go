@@ -0,0 +1,127 @@
+// Command restctl is an administration tool of the restaurant booking service. It talks to
+// the REST API through pkg/client or, with -direct, to the use cases over the database.
+//
+// Usage:
+//
+//	restctl [-api URL] [-direct] [-o table|json] <command> <subcommand> [flags]
+//
+// Commands:
+//
+//	restaurant list [-cursor CURSOR] [-limit N]
+//	restaurant create -name NAME -address ADDRESS -cuisine CUISINE -email EMAIL -phone PHONE [-description TEXT] [-fact TEXT]...
+//	booking cancel <booking-id>
+//	restaurant export <restaurant-id>
+//	restaurant import <bundle-file|->
+//	availability generate -restaurant ID -from YYYY-MM-DD -to YYYY-MM-DD [-slot 1h] [-capacity N] [-dry-run]
+//	notification resend <notification-id>
+//	data export [-checkpoint FILE] [-since TIME] [-entity NAME]...
+//	shard list
+//	shard locate <restaurant-id>...
+//	shard migrate [-shard NAME]... [-to VERSION] [-path SOURCE]
+//
+// The shard commands read STORAGE_SHARDS and the POSTGRES_* environment and connect to the
+// shards directly.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const defaultAPIURL = "http://localhost:8080/api/v1"
+
+var errUsage = errors.New("invalid usage")
+
+type globalOptions struct {
+	apiURL string
+	direct bool
+	output string
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			if !errors.Is(err, flag.ErrHelp) && err != errUsage {
+				fmt.Fprintf(os.Stderr, "restctl: %v\n", err)
+			}
+			usage()
+			os.Exit(2)
+		}
+
+		fmt.Fprintf(os.Stderr, "restctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	opts := globalOptions{}
+
+	fs := flag.NewFlagSet("restctl", flag.ContinueOnError)
+	fs.Usage = usage
+	fs.StringVar(&opts.apiURL, "api", envOrDefault("RESTCTL_API_URL", defaultAPIURL), "base URL of the REST API")
+	fs.BoolVar(&opts.direct, "direct", false, "call the use cases directly using the POSTGRES_* environment")
+	fs.StringVar(&opts.output, "o", "table", "output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if opts.output != outputTable && opts.output != outputJSON {
+		return fmt.Errorf("%w: unknown output format %q", errUsage, opts.output)
+	}
+
+	rest := fs.Args()
+	if len(rest) < 2 {
+		return errUsage
+	}
+
+	if cmd, ok := shardCommands[rest[0]+" "+rest[1]]; ok {
+		return cmd(ctx, newPrinter(os.Stdout, opts.output), rest[2:])
+	}
+
+	cmd, ok := commands[rest[0]+" "+rest[1]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, rest[0]+" "+rest[1])
+	}
+
+	b, closeBackend, err := newBackend(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer closeBackend()
+
+	return cmd(ctx, b, newPrinter(os.Stdout, opts.output), rest[2:])
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: restctl [-api URL] [-direct] [-o table|json] <command> <subcommand> [flags]
+
+Commands:
+  restaurant list [-cursor CURSOR] [-limit N]
+  restaurant create -name NAME -address ADDRESS -cuisine CUISINE -email EMAIL -phone PHONE [-description TEXT] [-fact TEXT]...
+  booking cancel <booking-id>
+  restaurant export <restaurant-id>
+  restaurant import <bundle-file|->
+  availability generate -restaurant ID -from YYYY-MM-DD -to YYYY-MM-DD [-slot 1h] [-capacity N] [-dry-run]
+  notification resend <notification-id>
+  data export [-checkpoint FILE] [-since TIME] [-entity NAME]...
+  shard list
+  shard locate <restaurant-id>...
+  shard migrate [-shard NAME]... [-to VERSION] [-path SOURCE]
+`)
+}
+
+func envOrDefault(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return def
+}
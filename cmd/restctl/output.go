@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printer renders command results either as an aligned table for humans or as JSON for scripts.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, format: format}
+}
+
+// print writes v as JSON, or the header and rows as a table.
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	if p.format == outputJSON {
+		return p.json(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// json writes v as indented JSON regardless of the output format, for results that are files themselves.
+func (p *printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// status prints the result of an operation without payload.
+func (p *printer) status(id, status string) error {
+	return p.print(map[string]string{"id": id, "status": status}, []string{"ID", "STATUS"}, [][]string{{id, status}})
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/ilyakaznacheev/cleanenv"
+
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	"github.com/flexer2006/case-back-restaurant-go/db/postgres/migrate"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+)
+
+// shardCommand works on the shards themselves, with the STORAGE_SHARDS and POSTGRES_* environment
+// of the service rather than through the API.
+type shardCommand func(ctx context.Context, p *printer, args []string) error
+
+var shardCommands = map[string]shardCommand{
+	"shard list":    shardList,
+	"shard locate":  shardLocate,
+	"shard migrate": shardMigrate,
+}
+
+// readShards returns the configured shards, in the order of STORAGE_SHARDS, with their
+// connections.
+func readShards() ([]migrate.Shard, configs.StorageConfig, error) {
+	var storage configs.StorageConfig
+	if err := cleanenv.ReadEnv(&storage); err != nil {
+		return nil, storage, fmt.Errorf("read storage config: %w", err)
+	}
+
+	var database configs.PostgresConfig
+	if err := cleanenv.ReadEnv(&database); err != nil {
+		return nil, storage, fmt.Errorf("read database config: %w", err)
+	}
+
+	shards := make([]migrate.Shard, 0, len(storage.Shards))
+	for _, name := range storage.Shards {
+		cfg, err := configs.ShardDatabase(database, name)
+		if err != nil {
+			return nil, storage, err
+		}
+		shards = append(shards, migrate.Shard{Name: name, Config: &cfg})
+	}
+	return shards, storage, nil
+}
+
+func shardList(_ context.Context, p *printer, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: shard list takes no arguments", errUsage)
+	}
+
+	shards, _, err := readShards()
+	if err != nil {
+		return err
+	}
+
+	type shardInfo struct {
+		Name     string `json:"name"`
+		Host     string `json:"host"`
+		Port     int    `json:"port"`
+		Database string `json:"database"`
+	}
+	infos := make([]shardInfo, 0, len(shards))
+	rows := make([][]string, 0, len(shards))
+	for _, shard := range shards {
+		infos = append(infos, shardInfo{Name: shard.Name, Host: shard.Config.Host, Port: shard.Config.Port, Database: shard.Config.Database})
+		rows = append(rows, []string{shard.Name, shard.Config.Host, strconv.Itoa(shard.Config.Port), shard.Config.Database})
+	}
+
+	return p.print(infos, []string{"SHARD", "HOST", "PORT", "DATABASE"}, rows)
+}
+
+// shardLocate prints the shard each restaurant belongs to. Run with the STORAGE_SHARDS of a
+// planned cluster, it tells which restaurants would move.
+func shardLocate(_ context.Context, p *printer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: shard locate expects restaurant IDs", errUsage)
+	}
+
+	_, storage, err := readShards()
+	if err != nil {
+		return err
+	}
+	ring := repository.NewHashRing(storage.Shards, storage.ShardVirtualNodes)
+
+	located := make(map[string]string, len(args))
+	rows := make([][]string, 0, len(args))
+	for _, restaurantID := range args {
+		shard := ring.Locate(restaurantID)
+		located[restaurantID] = shard
+		rows = append(rows, []string{restaurantID, shard})
+	}
+
+	return p.print(located, []string{"RESTAURANT", "SHARD"}, rows)
+}
+
+// shardMigrate applies the migrations to every shard, or to the ones given with -shard, one
+// after another, and prints the version each was left at.
+func shardMigrate(ctx context.Context, p *printer, args []string) error {
+	fs := flag.NewFlagSet("shard migrate", flag.ContinueOnError)
+	var only stringsFlag
+	fs.Var(&only, "shard", "shard to migrate, may be repeated; all by default")
+	to := fs.Uint("to", 0, "version to migrate to; the latest by default")
+	path := fs.String("path", migrate.DefaultMigrationsPath, "migrations source")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	shards, _, err := readShards()
+	if err != nil {
+		return err
+	}
+	if len(only) > 0 {
+		shards = slices.DeleteFunc(shards, func(shard migrate.Shard) bool {
+			return !slices.Contains(only, shard.Name)
+		})
+		if len(shards) != len(only) {
+			return fmt.Errorf("%w: unknown shard in %q", errUsage, only.String())
+		}
+	}
+
+	versions, migrateErr := migrate.MigrateShards(ctx, shards, *to, *path)
+
+	rows := make([][]string, 0, len(versions))
+	for _, version := range versions {
+		rows = append(rows, []string{version.Shard, strconv.FormatUint(uint64(version.Version), 10), strconv.FormatBool(version.Dirty)})
+	}
+	if err := p.print(versions, []string{"SHARD", "VERSION", "DIRTY"}, rows); err != nil {
+		return err
+	}
+
+	return migrateErr
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -27,11 +28,18 @@ import (
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/configs"
-	pgdb "github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/diagnostics"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/jobs"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/adapters/zap_adapter"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 )
 
@@ -72,29 +80,61 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	zapLogger.Info(ctx, common.MsgConnectingToPostgres)
+	// Lines up to here are written with the defaults, the rest with the configured level and sampling.
+	logger.SetLoggerFactory(zap_adapter.NewZapLoggerFactoryWithConfig(cfg.Logging.ZapConfig(cfg.LogLevel)))
+	if zapLogger, err = logger.NewLogger(); err != nil {
+		return fmt.Errorf(common.ErrInitLogger+": %w", err)
+	}
+	ctx = logger.NewContext(ctx, zapLogger)
 
-	db, err := pgdb.New(ctx, &cfg.Database)
+	tenant.Strict = cfg.Server.TenantGuardStrict
+
+	ids, err := newIDGenerator(cfg)
 	if err != nil {
-		zapLogger.Fatal(ctx, common.ErrPostgresConnect, zap.Error(err))
+		zapLogger.Fatal(ctx, common.ErrWireDependencies, zap.Error(err))
 
 		return err
 	}
 
-	defer closeDB(ctx, zapLogger, db)
+	faultInjection := usecase.NewFaultInjectionUseCase(cfg.Faults.Enabled, ids, clock.System{})
+	if cfg.Faults.Enabled {
+		zapLogger.Warn(ctx, common.MsgFaultInjectionEnabled)
+	}
 
-	if err := db.Ping(ctx); err != nil {
-		zapLogger.Fatal(ctx, common.ErrPingPostgresPool, zap.Error(err))
+	deps, closeDeps, err := newDependencies(ctx, zapLogger, cfg, ids, faultInjection)
+	if err != nil {
+		zapLogger.Fatal(ctx, common.ErrWireDependencies, zap.Error(err))
 
 		return err
 	}
+	defer closeDeps()
+
+	if cfg.Diagnostics.Enabled {
+		stopDiagnostics, err := diagnostics.Start(ctx, cfg.Diagnostics.Addr)
+		if err != nil {
+			zapLogger.Fatal(ctx, common.ErrStartDiagnostics, zap.Error(err))
 
-	zapLogger.Info(ctx, common.MsgPostgresConnected)
+			return err
+		}
+		defer stopDiagnostics()
+	}
 
-	useCases, err := setupUseCases(db)
+	useCases, err := setupUseCases(cfg, deps, faultInjection)
 	if err != nil {
 		return err
 	}
+	defer useCases.restaurantNotifier.Flush()
+
+	stopJobs := startJobs(ctx, zapLogger, cfg, useCases, deps.clock)
+	defer stopJobs()
+
+	geoLocator, closeGeoLocator, err := openGeoLocator(cfg)
+	if err != nil {
+		zapLogger.Fatal(ctx, common.ErrOpenGeoIPDatabase, zap.Error(err))
+
+		return err
+	}
+	defer closeGeoLocator()
 
 	srv, err := server.NewServer(
 		ctx,
@@ -105,6 +145,40 @@ func run(ctx context.Context) error {
 		useCases.facts,
 		useCases.availability,
 		useCases.notification,
+		useCases.catalog,
+		useCases.bookingLink,
+		useCases.requestReplay,
+		useCases.notificationReceipt,
+		useCases.menu,
+		useCases.image,
+		useCases.review,
+		useCases.abuse,
+		geoLocator,
+		useCases.retention,
+		useCases.export,
+		useCases.sync,
+		useCases.notificationRetry,
+		useCases.analytics,
+		useCases.quota,
+		useCases.billing,
+		useCases.incentive,
+		useCases.geocoding,
+		useCases.availabilityAlert,
+		useCases.bulkCancellation,
+		useCases.organization,
+		useCases.bookingTransfer,
+		useCases.bookingSheet,
+		useCases.displayBoard,
+		useCases.slo,
+		useCases.faultInjection,
+		useCases.bookingSync,
+		useCases.restaurantClaim,
+		useCases.customDomain,
+		useCases.widgetSettings,
+		useCases.bookingDraft,
+		useCases.auth,
+		useCases.restaurantClosure,
+		useCases.table,
 	)
 	if err != nil {
 		zapLogger.Fatal(ctx, common.ErrCreateServer, zap.Error(err))
@@ -126,43 +200,289 @@ type useCases struct {
 	facts        usecase.FactsUseCase
 	availability usecase.AvailabilityUseCase
 	notification usecase.NotificationUseCase
+	catalog      usecase.CatalogUseCase
+	bookingLink  usecase.BookingLinkUseCase
+
+	requestReplay       usecase.RequestReplayUseCase
+	notificationReceipt usecase.NotificationReceiptUseCase
+	restaurantDigest    usecase.RestaurantDigestUseCase
+	weeklyReport        usecase.WeeklyReportUseCase
+	reengagement        usecase.ReengagementUseCase
+	menu                usecase.MenuUseCase
+	image               usecase.ImageUseCase
+	review              usecase.ReviewUseCase
+	abuse               usecase.AbuseUseCase
+	retention           usecase.RetentionUseCase
+	export              usecase.ExportUseCase
+	sync                usecase.SyncUseCase
+	notificationRetry   usecase.NotificationRetryUseCase
+	deferrals           usecase.NotificationDeferralUseCase
+	analytics           usecase.AnalyticsUseCase
+	quota               usecase.QuotaUseCase
+	billing             usecase.BillingUseCase
+	incentive           usecase.IncentiveUseCase
+	geocoding           usecase.GeocodingUseCase
+	availabilityAlert   usecase.AvailabilityAlertUseCase
+	bulkCancellation    usecase.BulkCancellationUseCase
+	organization        usecase.OrganizationUseCase
+	bookingTransfer     usecase.BookingTransferUseCase
+	bookingSheet        usecase.BookingSheetUseCase
+	displayBoard        usecase.DisplayBoardUseCase
+	slo                 usecase.SLOUseCase
+	faultInjection      usecase.FaultInjectionUseCase
+	bookingSync         usecase.BookingSyncUseCase
+	restaurantClaim     usecase.RestaurantClaimUseCase
+	customDomain        usecase.CustomDomainUseCase
+	restaurantClosure   usecase.RestaurantClosureUseCase
+	table               usecase.TableUseCase
+	widgetSettings      usecase.WidgetSettingsUseCase
+	bookingDraft        usecase.BookingDraftUseCase
+	indexAdvisor        usecase.IndexAdvisorUseCase
+	auth                usecase.AuthUseCase
+	// cacheWarmer is nil when no cache backend is configured.
+	cacheWarmer usecase.CacheWarmerUseCase
+
+	restaurantNotifier *notification.DebouncedNotificationService
 }
 
-func setupUseCases(db pgdb.Database) (*useCases, error) {
-	repoFactory := postgres.NewRepositoryFactory(db)
-
+func setupUseCases(cfg *configs.Config, deps *dependencies, faultInjection usecase.FaultInjectionUseCase) (*useCases, error) {
+	repoFactory := deps.repositories
 	restaurantRepo := repoFactory.Restaurant()
 	workingHoursRepo := repoFactory.WorkingHours()
 	availabilityRepo := repoFactory.Availability()
 	bookingRepo := repoFactory.Booking()
+	tableRepo := repoFactory.Table()
 	userRepo := repoFactory.User()
 	notificationRepo := repoFactory.Notification()
 
 	notificationService := postgres.NewNotificationService(notificationRepo)
+	notificationSettingsRepo := repoFactory.NotificationSettings()
+	restaurantNotifier := notification.NewDebouncedNotificationService(notificationService, cfg.Notifications.RestaurantWindow)
+	notificationDeferral := usecase.NewNotificationDeferralUseCase(repoFactory.DeferredNotification(), notificationSettingsRepo,
+		userRepo, restaurantRepo, restaurantNotifier, deps.email, deps.sms, deps.clock)
+	notificationRouter := notification.NewRouter(restaurantNotifier, notificationSettingsRepo, notificationDeferral)
+
+	emailService := deps.email
+
+	bookingLinks := usecase.NewBookingLinkUseCase(
+		repoFactory.BookingLink(),
+		bookingRepo,
+		repoFactory.Transactor(),
+		cfg.Notifications.BookingLinkSecret,
+		cfg.Server.PublicURL,
+		cfg.Notifications.BookingLinkTTL,
+		deps.clock,
+	)
+	smsService := deps.sms
+	smsNotifier := notification.NewSMSNotifier(notificationRouter, smsService, userRepo, notificationSettingsRepo, bookingLinks, notificationRepo)
+	deliveringNotifier := notification.NewTestModeRouter(smsNotifier, notification.NewLogSink(), restaurantRepo, bookingRepo)
+	notificationRetry := usecase.NewNotificationRetryUseCase(repoFactory.NotificationFailure(), deliveringNotifier, usecase.NotificationRetryPolicy{
+		MaxAttempts: cfg.Notifications.RetryMaxAttempts,
+		Backoff:     cfg.Notifications.RetryBackoff,
+		MaxBackoff:  cfg.Notifications.RetryMaxBackoff,
+	}, deps.clock)
+	notifier := notification.NewFailureRecorder(deliveringNotifier, notificationRetry)
+
+	facts := usecase.NewFactsUseCase(restaurantRepo, deps.clock)
+
+	abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{
+		Window:             cfg.Abuse.Window,
+		BookingsPerClient:  cfg.Abuse.BookingsPerClient,
+		CancelRebookCycles: cfg.Abuse.CancelRebookCycles,
+		RateLimit:          cfg.Abuse.RateLimit,
+		AutoTighten:        cfg.Abuse.AutoTighten,
+		TightenedRateLimit: cfg.Abuse.TightenedRateLimit,
+		TightenFor:         cfg.Abuse.TightenFor,
+	}, deps.ids, deps.clock)
+
+	retention := usecase.NewRetentionUseCase(repoFactory.Retention(), repoFactory.Transactor(), usecase.RetentionPolicies{
+		ReadNotifications: cfg.Retention.ReadNotifications,
+		CompletedBookings: cfg.Retention.CompletedBookings,
+	}, deps.clock)
+
+	reengagement := usecase.NewReengagementUseCase(repoFactory.Reengagement(), notificationSettingsRepo, userRepo, notifier, emailService, smsService, notificationDeferral, usecase.ReengagementPolicy{
+		RebookAfterWeeks:    cfg.Jobs.RebookAfterWeeks,
+		AnniversaryLeadDays: cfg.Jobs.AnniversaryLeadDays,
+	})
+
+	quotas := usecase.NewQuotaUseCase(repoFactory.Quota(), restaurantRepo, repoFactory.Transactor(), map[domain.Plan]domain.QuotaLimits{
+		domain.PlanFree: {ActiveSlots: cfg.Quotas.FreeActiveSlots, MonthlyBookings: cfg.Quotas.FreeMonthlyBookings},
+		domain.PlanPro:  {ActiveSlots: cfg.Quotas.ProActiveSlots, MonthlyBookings: cfg.Quotas.ProMonthlyBookings},
+	})
+	availability := usecase.NewQuotaLimitedAvailabilityUseCase(
+		usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, repoFactory.RestaurantClosure(), repoFactory.SpecialDay(), bookingRepo, repoFactory.Transactor(), deps.clock),
+		quotas, deps.clock)
+	incentives := usecase.NewIncentiveUseCase(repoFactory.Incentive(), bookingRepo)
+	bookings := usecase.NewQuotaLimitedBookingUseCase(
+		usecase.NewIncentiveBookingUseCase(usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notifier, repoFactory.Transactor(), deps.clock), incentives, deps.clock),
+		quotas, deps.clock)
+	closures := usecase.NewRestaurantClosureUseCase(repoFactory.RestaurantClosure(), restaurantRepo, repoFactory.RestaurantLocation(), repoFactory.Transactor(), deps.clock)
+	monitoredBookings := usecase.NewAbuseMonitoredBookingUseCase(
+		usecase.NewClosedRestaurantBookingUseCase(usecase.NewAgeRestrictedBookingUseCase(bookings, restaurantRepo), closures), abuse)
+
+	analytics := usecase.NewAnalyticsUseCase(repoFactory.Analytics(), availabilityRepo, restaurantRepo,
+		cfg.Analytics.ForecastWeeks, cfg.Analytics.HeatmapWeeks, usecase.SlowResponderPolicy{
+			Days:         cfg.Analytics.SlowResponseDays,
+			Threshold:    cfg.Analytics.SlowResponseThreshold,
+			MinResponses: cfg.Analytics.SlowResponseMinResponses,
+		}, deps.clock)
+
+	billing := usecase.NewBillingUseCase(repoFactory.Billing(), repoFactory.Quota(), restaurantRepo, repoFactory.Transactor(), deps.clock, usecase.BillingSettings{
+		Prices:          map[domain.Plan]int64{domain.PlanPro: cfg.Billing.ProPrice},
+		Currency:        cfg.Billing.Currency,
+		PaymentTermDays: cfg.Billing.PaymentTermDays,
+		WebhookSecret:   cfg.Billing.WebhookSecret,
+		Issuer:          cfg.Billing.Issuer,
+	})
+
+	users := usecase.NewUserUseCase(userRepo, cfg.Contacts.PhoneRegion, deps.clock)
+	auth := usecase.NewAuthUseCase(repoFactory.Account(), restaurantRepo, repoFactory.RestaurantClaim(), users, emailService, repoFactory.Transactor(), deps.clock, usecase.AuthSettings{
+		Secret:            cfg.Auth.JWTSecret,
+		Issuer:            cfg.Auth.Issuer,
+		AccessTokenTTL:    cfg.Auth.AccessTokenTTL,
+		RefreshTokenTTL:   cfg.Auth.RefreshTokenTTL,
+		MinPasswordLength: cfg.Auth.MinPasswordLen,
+		AdminEmails:       cfg.Auth.AdminEmails,
+		BaseURL:           cfg.Server.PublicURL,
+	})
+
+	geocoding := usecase.NewGeocodingUseCase(repoFactory.RestaurantLocation(), deps.geocoder, notifier, deps.clock)
+	restaurants := usecase.NewRestaurantUseCase(restaurantRepo, workingHoursRepo, repoFactory.RestaurantClosure(), repoFactory.SpecialDay(), repoFactory.Transactor(), cfg.Contacts.PhoneRegion, deps.clock)
+	if deps.geocoder != nil {
+		restaurants = usecase.NewGeocodedRestaurantUseCase(restaurants, geocoding)
+	}
 
-	// Using mock email service
-	// smtpConfig, err := configs.NewSMTPConfig()
-	// if err != nil {
-	// 	return nil, fmt.Errorf("%s: %w", common.ErrSMTPInvalidConfig, err)
-	// }
-
-	// emailService := notification.NewSMTPMailer(smtpConfig)
-	emailService := postgres.NewMockEmailService()
+	var cacheWarmer usecase.CacheWarmerUseCase
+	if deps.readCache != nil {
+		restaurants = usecase.NewCachedRestaurantUseCase(restaurants, deps.readCache)
+		availability = usecase.NewCachedAvailabilityUseCase(availability, deps.readCache)
+		cacheWarmer = usecase.NewCacheWarmerUseCase(restaurantRepo, availabilityRepo, deps.readCache, usecase.CacheWarmerSettings{
+			Cities:         cfg.Cache.WarmCities,
+			Days:           cfg.Cache.WarmDays,
+			TTL:            cfg.Cache.TTL,
+			MaxRestaurants: cfg.Cache.WarmMaxRestaurants,
+		})
+	}
 
 	return &useCases{
-		restaurant:   usecase.NewRestaurantUseCase(restaurantRepo, workingHoursRepo),
-		facts:        usecase.NewFactsUseCase(restaurantRepo),
-		availability: usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo),
-		notification: usecase.NewNotificationUseCase(emailService, notificationService),
-		booking:      usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationService),
-		user:         usecase.NewUserUseCase(userRepo),
+		restaurant:   restaurants,
+		facts:        facts,
+		availability: availability,
+		notification: usecase.NewNotificationUseCase(emailService, notifier, notificationSettingsRepo, facts),
+		booking:      monitoredBookings,
+		user:         users,
+		catalog:      usecase.NewCatalogUseCase(restaurantRepo, deps.clock),
+		bookingLink:  bookingLinks,
+
+		requestReplay:       usecase.NewRequestReplayUseCase(cfg.Replay.RequestsPerKey, cfg.Replay.StagingURL, cfg.Replay.StagingAPIKey, deps.ids, deps.clock),
+		notificationReceipt: usecase.NewNotificationReceiptUseCase(notificationRepo, deps.clock),
+		restaurantDigest:    usecase.NewRestaurantDigestUseCase(bookingRepo, notifier, cfg.Jobs.DigestOccasionDays),
+		weeklyReport:        usecase.NewWeeklyReportUseCase(repoFactory.RestaurantPerformance(), notificationSettingsRepo, emailService, notificationDeferral),
+		reengagement:        reengagement,
+		menu:                usecase.NewMenuUseCase(repoFactory.Menu(), restaurantRepo, bookingRepo, cfg.Bookings.PreOrderCutoff, deps.clock),
+		image:               usecase.NewImageUseCase(repoFactory.Image(), restaurantRepo, deps.imageCache, cfg.Images.MaxDimension, cfg.Images.VariantFormats, deps.clock),
+		review:              usecase.NewReviewUseCase(repoFactory.Review(), restaurantRepo, bookingRepo, notifier, repoFactory.Transactor()),
+		abuse:               abuse,
+		retention:           retention,
+		export:              usecase.NewExportUseCase(repoFactory.Export(), deps.clock),
+		sync:                usecase.NewSyncUseCase(repoFactory.Sync()),
+		notificationRetry:   notificationRetry,
+		deferrals:           notificationDeferral,
+		analytics:           analytics,
+		quota:               quotas,
+		billing:             billing,
+		incentive:           incentives,
+		geocoding:           geocoding,
+		availabilityAlert:   usecase.NewAvailabilityAlertUseCase(repoFactory.AvailabilityAlert(), availabilityRepo, restaurantRepo, notifier, deps.clock),
+		bulkCancellation:    usecase.NewBulkCancellationUseCase(bookingRepo, availabilityRepo, restaurantRepo, repoFactory.RestaurantLocation(), notifier, repoFactory.Transactor(), deps.clock),
+		organization:        usecase.NewOrganizationUseCase(repoFactory.Organization(), restaurantRepo),
+		bookingTransfer:     usecase.NewBookingTransferUseCase(repoFactory.BookingTransfer(), repoFactory.Organization(), bookingRepo, availabilityRepo, tableRepo, restaurantRepo, notifier, repoFactory.Transactor(), deps.clock),
+		bookingSheet:        usecase.NewBookingSheetUseCase(restaurantRepo, bookingRepo, userRepo, repoFactory.Menu()),
+		displayBoard:        usecase.NewDisplayBoardUseCase(bookingRepo, userRepo),
+		slo: usecase.NewSLOUseCase(repoFactory.SLO(), notifier, deps.clock, usecase.SLOSettings{
+			Objectives: domain.SLOObjectives{
+				AvailabilityTarget: cfg.SLO.AvailabilityTarget,
+				LatencyTarget:      cfg.SLO.LatencyTarget,
+				LatencyThreshold:   cfg.SLO.LatencyThreshold,
+			},
+			FastBurnRate: cfg.SLO.FastBurnRate,
+			MinRequests:  cfg.SLO.MinRequests,
+			AlertUserIDs: cfg.SLO.AlertUserIDs,
+		}),
+		faultInjection:  faultInjection,
+		bookingSync:     usecase.NewBookingSyncUseCase(repoFactory.BookingSync(), bookingRepo, monitoredBookings, repoFactory.Transactor(), deps.clock),
+		restaurantClaim: usecase.NewRestaurantClaimUseCase(repoFactory.RestaurantClaim(), restaurantRepo, notifier, repoFactory.Transactor(), deps.clock),
+		customDomain: usecase.NewCustomDomainUseCase(repoFactory.CustomDomain(), restaurantRepo, net.DefaultResolver,
+			cfg.Domains.MaxPerRestaurant, []string{cfg.Server.PublicHost()}, deps.clock),
+		restaurantClosure: closures,
+		table:             usecase.NewTableUseCase(tableRepo, restaurantRepo, bookingRepo),
+		widgetSettings:    usecase.NewWidgetSettingsUseCase(repoFactory.WidgetSettings(), restaurantRepo),
+		bookingDraft: usecase.NewBookingDraftUseCase(repoFactory.BookingDraft(), availabilityRepo, restaurantRepo, repoFactory.Menu(),
+			monitoredBookings, repoFactory.Transactor(), cfg.Bookings.DraftTTL, cfg.Bookings.PreOrderCutoff, deps.clock),
+		indexAdvisor: usecase.NewIndexAdvisorUseCase(repoFactory.QueryStats(), usecase.IndexAdvisorSettings{
+			MinRows:  cfg.Jobs.IndexAdvisorMinRows,
+			MinCalls: cfg.Jobs.IndexAdvisorMinCalls,
+			Limit:    cfg.Jobs.IndexAdvisorLimit,
+		}),
+		auth:        auth,
+		cacheWarmer: cacheWarmer,
+
+		restaurantNotifier: restaurantNotifier,
 	}, nil
 }
 
-func closeDB(ctx context.Context, log ports.LoggerPort, db pgdb.Database) {
-	log.Info(ctx, common.MsgClosingPostgresPool)
+func startJobs(ctx context.Context, log ports.LoggerPort, cfg *configs.Config, useCases *useCases, clock clock.Clock) func() {
+	scheduler := jobs.NewScheduler()
+	scheduler.Every(cfg.Jobs.FactOfTheDayInterval, jobs.NewFactOfTheDayJob(useCases.facts, cfg.Jobs.FactOfTheDayLocales))
+	scheduler.Daily(cfg.Jobs.ReservedSeatsReconciliationAt, jobs.NewReservedSeatsReconciliationJob(useCases.availability))
+	scheduler.Daily(cfg.Jobs.RestaurantDigestAt, jobs.NewRestaurantDigestJob(useCases.restaurantDigest, useCases.weeklyReport, cfg.Jobs.WeeklyReportDay, clock))
+	scheduler.Daily(cfg.Jobs.ReengagementAt, jobs.NewReengagementJob(useCases.reengagement, clock))
+	scheduler.Every(cfg.Jobs.ImageVariantsInterval, jobs.NewImageVariantsJob(useCases.image, cfg.Jobs.ImageVariantsBatchSize))
+	scheduler.Daily(cfg.Retention.At, jobs.NewDataRetentionJob(useCases.retention, cfg.Retention.DryRun))
+	scheduler.Every(cfg.Jobs.NotificationRetryInterval, jobs.NewNotificationRetryJob(useCases.notificationRetry, cfg.Jobs.NotificationRetryBatchSize))
+	scheduler.Every(cfg.Jobs.DeferredNotificationInterval, jobs.NewDeferredNotificationJob(useCases.deferrals, cfg.Jobs.DeferredNotificationBatchSize))
+	scheduler.Daily(cfg.Billing.InvoiceAt, jobs.NewInvoiceJob(useCases.billing, clock))
+	scheduler.Every(cfg.Jobs.AvailabilityAlertInterval, jobs.NewAvailabilityAlertJob(useCases.availabilityAlert, clock))
+	scheduler.Every(cfg.Jobs.BookingDraftExpiryInterval, jobs.NewBookingDraftExpiryJob(useCases.bookingDraft, clock))
+	for _, at := range cfg.Jobs.TableOptimizationAt {
+		scheduler.Daily(at, jobs.NewTableOptimizationJob(useCases.table, clock))
+	}
+	scheduler.Daily(cfg.Jobs.IndexAdvisorAt, jobs.NewIndexAdvisorJob(useCases.indexAdvisor, clock))
+	scheduler.Every(cfg.SLO.FlushInterval, jobs.NewSLOMetricsJob(useCases.slo, clock))
+	scheduler.Daily(cfg.SLO.ReportAt, jobs.NewSLOReportJob(useCases.slo, clock))
+	if cfg.Geocoding.Provider != "" {
+		scheduler.Every(cfg.Jobs.GeocodingInterval, jobs.NewGeocodingJob(useCases.geocoding, cfg.Jobs.GeocodingBatchSize))
+	}
+	if len(cfg.Cache.WarmCities) > 0 {
+		if useCases.cacheWarmer != nil {
+			scheduler.Every(cfg.Cache.WarmInterval, jobs.NewCacheWarmerJob(useCases.cacheWarmer, clock))
+		} else {
+			log.Warn(ctx, common.MsgCacheWarmingWithoutCache)
+		}
+	}
+
+	log.Info(ctx, common.MsgSchedulerStarting)
+
+	jobsCtx, cancel := context.WithCancel(ctx)
+	scheduler.Start(jobsCtx)
+
+	return func() {
+		cancel()
+		scheduler.Wait()
+		log.Info(ctx, common.MsgSchedulerStopped)
+	}
+}
+
+// openGeoLocator opens the configured geoip database; without one clients are never located.
+func openGeoLocator(cfg *configs.Config) (geo.Locator, func(), error) {
+	if cfg.Geo.DatabasePath == "" {
+		return geo.NopLocator{}, func() {}, nil
+	}
 
-	if err := db.Close(ctx); err != nil {
-		log.Error(ctx, common.ErrDBClose, zap.Error(err))
+	locator, err := geo.OpenMaxMind(cfg.Geo.DatabasePath)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	return locator, func() { _ = locator.Close() }, nil
 }
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	pgdb "github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/cache"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/diagnostics"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/memory"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// dependencies are the parts of the service chosen by configuration: where records are stored,
+// where resized images and warmed reads are cached and the channels notifications leave through.
+// The use cases are composed from them in setupUseCases without knowing which were chosen.
+type dependencies struct {
+	repositories repository.Factory
+	imageCache   usecase.ImageVariantCache
+	email        domain.EmailSender
+	sms          domain.SMSSender
+	// readCache is nil when no cache backend is configured.
+	readCache usecase.ReadCache
+	// geocoder is nil when geocoding is off.
+	geocoder geo.Geocoder
+	clock    clock.Clock
+	ids      idgen.Generator
+}
+
+// newDependencies opens the configured dependencies and returns them with a function closing
+// them. Database calls fail as injected when fault injection is enabled.
+func newDependencies(
+	ctx context.Context,
+	log ports.LoggerPort,
+	cfg *configs.Config,
+	ids idgen.Generator,
+	faultInjection usecase.FaultInjectionUseCase,
+) (*dependencies, func(), error) {
+	imageCache, err := newImageCache(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrOpenImageCache, err)
+	}
+
+	email, err := newEmailSender(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sms, err := newSMSSender(log, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	geocoder, err := newGeocoder(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrCreateGeocoder, err)
+	}
+
+	readCache, closeReadCache, err := openReadCache(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrConnectCache, err)
+	}
+
+	repositories, closeStorage, err := openStorage(ctx, log, cfg, ids, faultInjection)
+	if err != nil {
+		closeReadCache()
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrOpenStorage, err)
+	}
+
+	closeAll := func() {
+		closeStorage()
+		closeReadCache()
+	}
+
+	return &dependencies{
+		repositories: repositories,
+		imageCache:   imageCache,
+		readCache:    readCache,
+		email:        email,
+		sms:          sms,
+		geocoder:     geocoder,
+		clock:        clock.System{},
+		ids:          ids,
+	}, closeAll, nil
+}
+
+// newIDGenerator returns the configured generator of the IDs of new records.
+func newIDGenerator(cfg *configs.Config) (idgen.Generator, error) {
+	switch cfg.Storage.IDGenerator {
+	case "uuid":
+		return idgen.UUID{}, nil
+	case "sequence":
+		if cfg.Storage.Backend != "memory" {
+			return nil, errors.New(common.ErrSequenceIDsNeedMemory)
+		}
+		return idgen.NewSequence(), nil
+	default:
+		return nil, fmt.Errorf("%s: %q", common.ErrUnknownIDGenerator, cfg.Storage.IDGenerator)
+	}
+}
+
+// openStorage opens the configured storage backend and returns the factory of its repositories
+// with a function closing it. The repositories work on one database, so the service runs on one
+// shard and more than one is refused.
+func openStorage(
+	ctx context.Context,
+	log ports.LoggerPort,
+	cfg *configs.Config,
+	ids idgen.Generator,
+	faultInjection usecase.FaultInjectionUseCase,
+) (repository.Factory, func(), error) {
+	if len(cfg.Storage.Shards) != 1 {
+		return nil, nil, fmt.Errorf("%s: %q", common.ErrMultipleShards, cfg.Storage.Shards)
+	}
+
+	switch cfg.Storage.Backend {
+	case "postgres":
+	case "memory":
+		log.Warn(ctx, common.MsgMemoryStorage)
+
+		return memory.NewRepositoryFactory(memory.NewStore(ids, clock.System{})), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("%s: %q", common.ErrUnknownStorageBackend, cfg.Storage.Backend)
+	}
+
+	database, err := configs.ShardDatabase(cfg.Database, cfg.Storage.Shards[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Info(ctx, common.MsgConnectingToPostgres, zap.String("shard", cfg.Storage.Shards[0]))
+
+	db, err := pgdb.New(ctx, &database)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrPostgresConnect, err)
+	}
+
+	if err := db.Ping(ctx); err != nil {
+		closeDB(ctx, log, db)
+
+		return nil, nil, fmt.Errorf("%s: %w", common.ErrPingPostgresPool, err)
+	}
+
+	log.Info(ctx, common.MsgPostgresConnected)
+
+	if adapter, ok := db.GetPool().(*pgdb.PgxPoolAdapter); ok {
+		diagnostics.SetPool(adapter.GetInternalPool())
+	}
+
+	closeStorage := func() { closeDB(ctx, log, db) }
+	if cfg.Faults.Enabled {
+		db = postgres.NewFaultInjectingDatabase(db, faultInjection)
+	}
+
+	return postgres.NewRepositoryFactory(db, ids), closeStorage, nil
+}
+
+func closeDB(ctx context.Context, log ports.LoggerPort, db pgdb.Database) {
+	log.Info(ctx, common.MsgClosingPostgresPool)
+
+	if err := db.Close(ctx); err != nil {
+		log.Error(ctx, common.ErrDBClose, zap.Error(err))
+	}
+}
+
+// newImageCache returns the cache of the configured backend.
+func newImageCache(cfg *configs.Config) (usecase.ImageVariantCache, error) {
+	switch cfg.Images.CacheBackend {
+	case "disk":
+		return imaging.NewDiskCache(cfg.Images.CacheDir, cfg.Images.CacheSize)
+	case "memory":
+		return imaging.NewMemoryCache(cfg.Images.CacheSize), nil
+	default:
+		return nil, fmt.Errorf("%s: %q", common.ErrUnknownImageCacheBackend, cfg.Images.CacheBackend)
+	}
+}
+
+// openReadCache connects to the configured read cache and returns it with a function closing it;
+// without a backend the cache is nil.
+func openReadCache(ctx context.Context, cfg *configs.Config) (usecase.ReadCache, func(), error) {
+	switch cfg.Cache.Backend {
+	case "":
+		return nil, func() {}, nil
+	case "memory":
+		return cache.NewMemory(clock.System{}), func() {}, nil
+	case "redis":
+	default:
+		return nil, nil, fmt.Errorf("%s: %q", common.ErrUnknownCacheBackend, cfg.Cache.Backend)
+	}
+
+	redis := cache.NewRedis(cache.RedisOptions{
+		Addr:     cfg.Cache.RedisAddr,
+		Password: cfg.Cache.RedisPassword,
+		DB:       cfg.Cache.RedisDB,
+		PoolSize: cfg.Cache.RedisPoolSize,
+		Timeout:  cfg.Cache.RedisTimeout,
+	})
+	if err := redis.Ping(ctx); err != nil {
+		_ = redis.Close()
+		return nil, nil, err
+	}
+
+	return redis, func() { _ = redis.Close() }, nil
+}
+
+// newEmailSender returns the sender of the configured email channel.
+func newEmailSender(cfg *configs.Config) (domain.EmailSender, error) {
+	switch cfg.Notifications.EmailChannel {
+	case "log":
+		return postgres.NewMockEmailService(), nil
+	case "smtp":
+		return notification.NewSMTPMailer(cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("%s: %q", common.ErrUnknownEmailChannel, cfg.Notifications.EmailChannel)
+	}
+}
+
+// newSMSSender returns the sender of the configured SMS channel.
+func newSMSSender(log ports.LoggerPort, cfg *configs.Config) (domain.SMSSender, error) {
+	switch cfg.Notifications.SMSChannel {
+	case "log":
+		return postgres.NewMockSMSService(log), nil
+	case "http":
+		if cfg.Notifications.SMSGatewayURL == "" {
+			return nil, errors.New(common.ErrSMSGatewayURLRequired)
+		}
+		return notification.NewSMSGateway(nil, cfg.Notifications.SMSGatewayURL,
+			cfg.Notifications.SMSGatewayToken, cfg.Notifications.SMSSender), nil
+	default:
+		return nil, fmt.Errorf("%s: %q", common.ErrUnknownSMSChannel, cfg.Notifications.SMSChannel)
+	}
+}
+
+// newGeocoder returns the geocoder of the configured provider, or nil when geocoding is off.
+func newGeocoder(cfg *configs.Config) (geo.Geocoder, error) {
+	if cfg.Geocoding.Provider == "" {
+		return nil, nil
+	}
+
+	return geo.NewGeocoder(cfg.Geocoding.Provider, geo.GeocoderSettings{
+		NominatimURL: cfg.Geocoding.NominatimURL,
+		UserAgent:    cfg.Geocoding.UserAgent,
+		GoogleAPIKey: cfg.Geocoding.GoogleAPIKey,
+		Language:     cfg.Geocoding.Language,
+	})
+}
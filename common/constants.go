@@ -12,8 +12,12 @@ const (
 	ErrMigrateDown                  = "failed to run down migration"
 	ErrMigrateVersion               = "failed to get migration version"
 	ErrMigrateDirtyState            = "migration is in a dirty state"
+	ErrMigrateShard                 = "failed to migrate shard"
 	ErrInternalServer               = "internal server error"
 	ErrParsePoolConfig              = "failed to parse pool config"
+	ErrInvalidQueryExecMode         = "invalid Postgres query exec mode"
+	ErrInvalidPooler                = "invalid Postgres connection pooler"
+	ErrDetectPooler                 = "failed to detect a Postgres connection pooler"
 	ErrCreateConnectionPool         = "failed to create connection pool"
 	ErrPingPostgresPool             = "failed to ping Postgres connection pool"
 	ErrApplyDBMigrations            = "failed to apply database migrations"
@@ -32,6 +36,8 @@ const (
 	ErrUpdateRestaurant             = "failed to update restaurant"
 	ErrGetRestaurant                = "failed to get restaurant"
 	ErrListRestaurants              = "failed to list restaurants"
+	ErrSearchRestaurants            = "failed to search restaurants"
+	ErrEstimateRestaurants          = "failed to estimate the number of restaurants"
 	ErrDeleteRestaurant             = "failed to delete restaurant"
 	ErrAddFact                      = "failed to add fact"
 	ErrGetFacts                     = "failed to get facts"
@@ -47,11 +53,14 @@ const (
 	ErrMigrateToVersion             = "Failed to migrate to version"
 	ErrAvailabilityNotFound         = "availability not found"
 	ErrInsufficientCapacity         = "insufficient capacity"
+	ErrCapacityBelowReserved        = "capacity is below the number of reserved seats"
+	ErrReconcileReservedSeats       = "failed to reconcile reserved seats"
 	ErrGetQueryExecutor             = "failed to get query executor"
 	ErrExecuteAvailabilityQuery     = "failed to execute availability query"
 	ErrScanAvailability             = "failed to scan availability"
 	ErrIterateAvailability          = "failed to iterate through availability list"
 	ErrCheckRestaurantExistence     = "failed to check restaurant existence"
+	ErrCheckRestaurantSlug          = "failed to check restaurant slug"
 	ErrUpdateAvailability           = "failed to update availability"
 	ErrCheckAvailabilityExistence   = "failed to check availability existence"
 	ErrInsertAvailability           = "failed to insert new availability"
@@ -64,6 +73,7 @@ const (
 	ErrExecuteBookingsQuery         = "failed to execute bookings query"
 	ErrIterateBookings              = "failed to iterate through bookings list"
 	ErrGetRestaurantBookings        = "failed to get restaurant bookings"
+	ErrGetActiveBookings            = "failed to get active bookings"
 	ErrCheckUserExistence           = "failed to check user existence"
 	ErrGetCurrentBookingStatus      = "failed to get current booking status"
 	ErrUpdateBookingStatus          = "failed to update booking status"
@@ -82,6 +92,10 @@ const (
 	ErrCheckNotificationExistence   = "failed to check notification existence"
 	ErrCreateNotification           = "failed to create notification"
 	ErrMarkNotificationAsRead       = "failed to mark notification as read"
+	ErrGetNotificationSettings      = "failed to get notification settings"
+	ErrTenantIsolation              = "record of another tenant reached the request principal"
+	ErrAccessDenied                 = "access denied"
+	ErrSaveNotificationSettings     = "failed to save notification settings"
 	ErrAcquireConnection            = "failed to acquire connection"
 	ErrUnknownConnectionType        = "unknown connection type"
 	ErrBeginTransaction             = "failed to begin transaction"
@@ -97,6 +111,7 @@ const (
 	ErrIterateFacts                 = "failed to iterate over facts list"
 	ErrExecuteRandomFactsQuery      = "failed to execute random facts query"
 	ErrIterateRandomFacts           = "failed to iterate over random facts list"
+	ErrEstimateFactsCount           = "failed to estimate facts count"
 	ErrScanUser                     = "failed to scan user"
 	ErrCheckEmailExistence          = "failed to check email existence"
 	ErrEmailAlreadyExists           = "user with this email already exists"
@@ -129,6 +144,7 @@ const (
 	ErrCreateRestaurantNotification = "error creating notification for restaurant"
 	ErrCreateUserNotification       = "error creating notification for user"
 	ErrContextNotFoundRu            = "context not found"
+	ErrRequestBodyTooLarge          = "request body exceeds the limit"
 	ErrLoggerCreationRu             = "error when creating a logger"
 	ErrGetLoggerFromContextRu       = "error of getting logger from context"
 	ErrServerShutdownRu             = "server shutdown error"
@@ -147,28 +163,302 @@ const (
 	ErrSMTPInvalidSenderEmail       = "invalid sender email address"
 	ErrSMTPInvalidRecipient         = "invalid recipient email address"
 	ErrSMTPTimeout                  = "SMTP operation timed out"
+	ErrJobFailed                    = "background job failed"
+	ErrNoFactsAvailable             = "no facts available"
+	ErrGetFactOfTheDay              = "failed to get fact of the day"
+	ErrBuildSitemap                 = "failed to build sitemap"
+	ErrBuildRestaurantsFeed         = "failed to build restaurants feed"
+	ErrGenerateAvailability         = "failed to generate availability"
+	ErrResendNotification           = "failed to resend notification"
+	ErrGetNotificationDeliveries    = "failed to get notification deliveries"
+	ErrConfirmNotificationRead      = "failed to confirm notification read"
+	ErrImportRestaurants            = "failed to import restaurants"
+	ErrReadImportFile               = "failed to read import file"
+	ErrExportRestaurant             = "failed to export restaurant"
+	ErrImportRestaurantBundle       = "failed to import restaurant bundle"
+	ErrBookingLinkNotFound          = "booking link not found"
+	ErrBookingLinkAlreadyUsed       = "booking link already used"
+	ErrCreateBookingLink            = "failed to create booking link"
+	ErrGetBookingLink               = "failed to get booking link"
+	ErrMarkBookingLinkUsed          = "failed to mark booking link as used"
+	ErrResolveBookingLink           = "failed to resolve booking link"
+	ErrGenerateQRCode               = "failed to generate QR code"
+	ErrReplayRequest                = "failed to replay request"
+	ErrGetMenu                      = "failed to get menu"
+	ErrSaveMenu                     = "failed to save menu"
+	ErrUpdateMenu                   = "failed to update menu"
+	ErrImportMenu                   = "failed to import menu"
+	ErrGetPreOrder                  = "failed to get pre-order"
+	ErrSavePreOrder                 = "failed to save pre-order"
+	ErrUpdatePreOrder               = "failed to update pre-order"
+	ErrImageNotFound                = "image not found"
+	ErrCreateImage                  = "failed to create image"
+	ErrGetImage                     = "failed to get image"
+	ErrListImages                   = "failed to list images"
+	ErrDeleteImage                  = "failed to delete image"
+	ErrUploadImage                  = "failed to upload image"
+	ErrResizeImage                  = "failed to resize image"
+	ErrOpenImageCache               = "failed to open image cache"
+	ErrGetImageVariants             = "failed to get image variants"
+	ErrClaimImageVariants           = "failed to claim image variants"
+	ErrSaveImageVariant             = "failed to save image variant"
+	ErrGenerateImageVariant         = "failed to generate image variant"
+	ErrReviewNotFound               = "review not found"
+	ErrReviewFlagNotFound           = "review flag not found"
+	ErrReviewExists                 = "booking already has a review"
+	ErrReviewAlreadyFlagged         = "review already has an open flag"
+	ErrReviewFlagResolved           = "review flag already resolved"
+	ErrCreateReview                 = "failed to create review"
+	ErrGetReview                    = "failed to get review"
+	ErrListReviews                  = "failed to list reviews"
+	ErrUpdateReview                 = "failed to update review"
+	ErrSaveReviewReply              = "failed to save review reply"
+	ErrReplyToReview                = "failed to reply to review"
+	ErrCreateReviewFlag             = "failed to create review flag"
+	ErrGetReviewFlag                = "failed to get review flag"
+	ErrListReviewFlags              = "failed to list review flags"
+	ErrResolveReviewFlag            = "failed to resolve review flag"
+	ErrTooManyRequests              = "too many requests"
+	ErrListAbuseEvents              = "failed to list abuse events"
+	ErrLocateClient                 = "failed to locate client address"
+	ErrOpenGeoIPDatabase            = "failed to open geoip database"
+	ErrInvalidCurrency              = "invalid currency code"
+	ErrPurgeNotifications           = "failed to purge read notifications"
+	ErrAnonymizeBookings            = "failed to anonymize completed bookings"
+	ErrCreateRetentionRun           = "failed to create retention run"
+	ErrListRetentionRuns            = "failed to list retention runs"
+	ErrApplyRetention               = "failed to apply retention policies"
+	ErrListExportChanges            = "failed to list changed records for export"
+	ErrExportChanges                = "failed to export changed records"
+	ErrListSyncChanges              = "failed to list sync changes"
+	ErrInvalidSyncCursor            = "invalid sync cursor"
+	ErrInvalidPageCursor            = "invalid page cursor"
+	ErrOffsetPagination             = "offset is not supported, pass the next cursor of the previous page as cursor"
+	ErrListAvailabilityChanges      = "failed to list availability changes"
+	ErrInvalidAvailabilityVersion   = "invalid availability version"
+	ErrSaveBookingSyncResult        = "failed to save booking sync result"
+	ErrGetBookingSyncResult         = "failed to get booking sync result"
+	ErrBookingSyncResultNotFound    = "booking sync result not found"
+	ErrBookingSyncKeyUsed           = "booking sync key already used"
+	ErrSyncBookings                 = "failed to sync bookings"
+	ErrAgeAttestationRequired       = "age attestation required for an adult-only restaurant"
+	ErrAvailabilityReserved         = "availability has reserved seats"
+	ErrDeleteAvailability           = "failed to delete availability"
+	ErrListAvailabilityBookings     = "failed to list bookings of availability"
+	ErrGetSchedule                  = "failed to get restaurant schedule"
+	ErrCreateRestaurantClaim        = "failed to create restaurant claim"
+	ErrRestaurantClaimExists        = "the user already has a pending claim of the restaurant"
+	ErrGetRestaurantClaim           = "failed to get restaurant claim"
+	ErrListRestaurantClaims         = "failed to list restaurant claims"
+	ErrRestaurantClaimNotFound      = "restaurant claim not found"
+	ErrUpdateRestaurantClaim        = "failed to update restaurant claim"
+	ErrGetRestaurantOwner           = "failed to get restaurant owner"
+	ErrRestaurantOwnerNotFound      = "restaurant owner not found"
+	ErrSetRestaurantOwner           = "failed to set restaurant owner"
+	ErrRestaurantAlreadyOwned       = "restaurant already has an owner"
+	ErrAddOwnershipAuditRecord      = "failed to add ownership audit record"
+	ErrListOwnershipAuditRecords    = "failed to list ownership audit records"
+	ErrCreateCustomDomain           = "failed to create custom domain"
+	ErrCustomDomainTaken            = "the domain is already registered"
+	ErrGetCustomDomain              = "failed to get custom domain"
+	ErrCustomDomainNotFound         = "custom domain not found"
+	ErrListCustomDomains            = "failed to list custom domains"
+	ErrUpdateCustomDomain           = "failed to update custom domain"
+	ErrDeleteCustomDomain           = "failed to delete custom domain"
+	ErrCreateRestaurantClosure      = "failed to create restaurant closure"
+	ErrRestaurantClosureOverlaps    = "the closure overlaps another closure of the restaurant"
+	ErrGetRestaurantClosure         = "failed to get restaurant closure"
+	ErrRestaurantClosureNotFound    = "restaurant closure not found"
+	ErrListRestaurantClosures       = "failed to list restaurant closures"
+	ErrDeleteRestaurantClosure      = "failed to delete restaurant closure"
+	ErrRestaurantClosed             = "the restaurant is closed on the date of the booking"
+	ErrRenderClosureReply           = "failed to render closure reply"
+	ErrSetSpecialDay                = "failed to set special day"
+	ErrListSpecialDays              = "failed to list special days"
+	ErrSpecialDayNotFound           = "special day not found"
+	ErrDeleteSpecialDay             = "failed to delete special day"
+	ErrCreateTable                  = "failed to create table"
+	ErrGetTable                     = "failed to get table"
+	ErrTableNotFound                = "table not found"
+	ErrListTables                   = "failed to list tables"
+	ErrUpdateTable                  = "failed to update table"
+	ErrDeleteTable                  = "failed to delete table"
+	ErrTableBooked                  = "the table is held by upcoming bookings"
+	ErrTableUnavailable             = "the table is not free for the booking"
+	ErrReseatBookings               = "failed to seat bookings at other tables"
+	ErrRenderBookingPage            = "failed to render booking page"
+	ErrGetWidgetSettings            = "failed to get widget settings"
+	ErrWidgetSettingsNotFound       = "widget settings not found"
+	ErrSaveWidgetSettings           = "failed to save widget settings"
+	ErrRenderEmbedWidget            = "failed to render availability widget"
+	ErrConfigureCORS                = "failed to configure CORS"
+	ErrConfigureGatewayTrust        = "failed to configure gateway trust"
+	ErrRequestTimeout               = "request timed out"
+	ErrServerOverloaded             = "server is overloaded, retry later"
+	ErrCreateNotificationFailure    = "failed to record notification failure"
+	ErrUpdateNotificationFailure    = "failed to update notification failure"
+	ErrClaimNotificationFailures    = "failed to claim notification failures due for retry"
+	ErrListNotificationFailures     = "failed to list notification failures"
+	ErrCountNotificationFailures    = "failed to count notification failures"
+	ErrNotificationFailureNotFound  = "notification failure not found"
+	ErrRetryNotifications           = "failed to retry notifications"
+	ErrCreateDeferredNotification   = "failed to defer notification"
+	ErrUpdateDeferredNotification   = "failed to update deferred notification"
+	ErrClaimDeferredNotifications   = "failed to claim deferred notifications due for delivery"
+	ErrDeleteDeferredNotification   = "failed to delete deferred notification"
+	ErrDeferredNotificationNotFound = "deferred notification not found"
+	ErrGetRestaurantPerformance     = "failed to get restaurant performance"
+	ErrRenderWeeklyReport           = "failed to render weekly report"
+	ErrListReengagementCandidates   = "failed to list guests to invite back"
+	ErrListBookingHistory           = "failed to list booking history"
+	ErrGetOccupancyForecast         = "failed to get occupancy forecast"
+	ErrCountHourlyDemand            = "failed to count booking demand by hour"
+	ErrCountSeatedBookings          = "failed to count seated bookings"
+	ErrGetOccupancyNow              = "failed to get live occupancy"
+	ErrGetDemandHeatmap             = "failed to get demand heatmap"
+	ErrGetResponseLatency           = "failed to get booking response latency"
+	ErrListSlowResponders           = "failed to list slow responding restaurants"
+	ErrListCampaignPerformance      = "failed to list campaign performance"
+	ErrGetRestaurantPlan            = "failed to get restaurant plan"
+	ErrSetRestaurantPlan            = "failed to set restaurant plan"
+	ErrCountQuotaUsage              = "failed to count quota usage"
+	ErrQuotaExceeded                = "quota exceeded"
+	ErrCreateSubscription           = "failed to create subscription"
+	ErrGetSubscription              = "failed to get subscription"
+	ErrCancelSubscription           = "failed to cancel subscription"
+	ErrListSubscriptions            = "failed to list subscriptions"
+	ErrSubscriptionNotFound         = "subscription not found"
+	ErrCreateInvoices               = "failed to create invoices"
+	ErrGetInvoice                   = "failed to get invoice"
+	ErrListInvoices                 = "failed to list invoices"
+	ErrUpdateInvoice                = "failed to update invoice"
+	ErrInvoiceNotFound              = "invoice not found"
+	ErrRecordPaymentEvent           = "failed to record payment event"
+	ErrRenderInvoice                = "failed to render invoice"
+	ErrHandlePaymentEvent           = "failed to handle payment event"
+	ErrListIncentiveRules           = "failed to list incentive rules"
+	ErrGetIncentiveRule             = "failed to get incentive rule"
+	ErrCreateIncentiveRule          = "failed to create incentive rule"
+	ErrUpdateIncentiveRule          = "failed to update incentive rule"
+	ErrDeleteIncentiveRule          = "failed to delete incentive rule"
+	ErrIncentiveRuleNotFound        = "incentive rule not found"
+	ErrEvaluateIncentives           = "failed to evaluate incentives"
+	ErrCreateIncentiveEvaluation    = "failed to create incentive evaluation"
+	ErrGetIncentiveEvaluation       = "failed to get incentive evaluation"
+	ErrListIncentiveEvaluations     = "failed to list incentive evaluations"
+	ErrIncentiveEvaluationNotFound  = "incentive evaluation not found"
+	ErrEnqueueGeocoding             = "failed to enqueue restaurant geocoding"
+	ErrGetRestaurantLocation        = "failed to get restaurant location"
+	ErrRestaurantLocationNotFound   = "restaurant location not found"
+	ErrClaimRestaurantLocations     = "failed to claim restaurant locations"
+	ErrSaveRestaurantLocation       = "failed to save restaurant location"
+	ErrGeocodeRestaurant            = "failed to geocode restaurant address"
+	ErrCreateGeocoder               = "failed to create geocoder"
+	ErrListCities                   = "failed to list cities"
+	ErrCreateAvailabilityAlert      = "failed to create availability alert"
+	ErrAvailabilityAlertExists      = "an availability alert for the restaurant and date is already active"
+	ErrListAvailabilityAlerts       = "failed to list availability alerts"
+	ErrUpdateAvailabilityAlert      = "failed to update availability alert"
+	ErrAvailabilityAlertNotFound    = "availability alert not found"
+	ErrExpireAvailabilityAlerts     = "failed to expire availability alerts"
+	ErrCheckAvailabilityAlerts      = "failed to check availability alerts"
+	ErrCreateBookingDraft           = "failed to create booking draft"
+	ErrGetBookingDraft              = "failed to get booking draft"
+	ErrBookingDraftNotFound         = "booking draft not found"
+	ErrUpdateBookingDraft           = "failed to update booking draft"
+	ErrDeleteBookingDraft           = "failed to delete booking draft"
+	ErrListBookingDrafts            = "failed to list booking drafts"
+	ErrExpireBookingDrafts          = "failed to expire booking drafts"
+	ErrListSeqScanTables            = "failed to list sequentially scanned tables"
+	ErrListScanHeavyQueries         = "failed to list scan-heavy queries"
+	ErrQueryStatsUnavailable        = "pg_stat_statements is not available"
+	ErrBulkCancelBookings           = "failed to cancel bookings in bulk"
+	ErrCreateOrganization           = "failed to create organization"
+	ErrGetOrganization              = "failed to get organization"
+	ErrOrganizationNotFound         = "organization not found"
+	ErrAddOrganizationRestaurant    = "failed to add restaurant to organization"
+	ErrListSisterRestaurants        = "failed to list sister restaurants"
+	ErrCreateBookingTransfer        = "failed to create booking transfer"
+	ErrBookingTransferExists        = "booking already has a pending transfer"
+	ErrGetBookingTransfer           = "failed to get booking transfer"
+	ErrBookingTransferNotFound      = "booking transfer not found"
+	ErrRenderBookingSheet           = "failed to render booking sheet"
+	ErrUpdateBookingTransfer        = "failed to update booking transfer"
+	ErrGetDisplayBoard              = "failed to get display board"
+	ErrInvalidBoardVersion          = "invalid board version"
+	ErrAddRequestMetrics            = "failed to add request metrics"
+	ErrSumRequestMetrics            = "failed to sum request metrics"
+	ErrSaveSLOReport                = "failed to save SLO report"
+	ErrListSLOReports               = "failed to list SLO reports"
+	ErrFlushRequestMetrics          = "failed to flush request metrics"
+	ErrSendSLOAlert                 = "failed to send SLO alert"
+	ErrManageFaults                 = "failed to manage injected faults"
+	ErrInjectedFault                = "injected fault"
+	ErrStartDiagnostics             = "failed to start diagnostics server"
+	ErrServeDiagnostics             = "diagnostics server error"
+	ErrUnknownStorageBackend        = "unknown storage backend"
+	ErrOpenStorage                  = "failed to open storage"
+	ErrUnknownImageCacheBackend     = "unknown image cache backend"
+	ErrUnknownCacheBackend          = "unknown cache backend"
+	ErrConnectCache                 = "failed to connect to the cache"
+	ErrUnknownEmailChannel          = "unknown email channel"
+	ErrUnknownSMSChannel            = "unknown SMS channel"
+	ErrSMSGatewayURLRequired        = "the http SMS channel needs SMS_GATEWAY_URL"
+	ErrBookingLinkSecretRequired    = "BOOKING_LINK_SECRET must be set, and outside development to a secret of its own"
+	ErrSendSMS                      = "failed to send SMS"
+	ErrWireDependencies             = "failed to wire dependencies"
+	ErrUnknownIDGenerator           = "unknown ID generator"
+	ErrSequenceIDsNeedMemory        = "sequence IDs need the memory storage backend"
+	ErrMultipleShards               = "the service runs on one shard, STORAGE_SHARDS lists"
+	ErrCreateAccount                = "failed to create account"
+	ErrGetAccount                   = "failed to get account"
+	ErrAccountNotFound              = "account not found"
+	ErrAccountExists                = "an account with the email already exists"
+	ErrCreateRefreshToken           = "failed to create refresh token"
+	ErrGetRefreshToken              = "failed to get refresh token"
+	ErrRefreshTokenNotFound         = "refresh token not found"
+	ErrRevokeRefreshToken           = "failed to revoke refresh token"
+	ErrVerifyEmail                  = "failed to verify email"
+	ErrVerificationTokenNotFound    = "email verification token not found"
+	ErrSendVerificationEmail        = "failed to send verification email"
+	ErrInvalidBearerToken           = "invalid bearer token"
+	ErrAuthenticationRequired       = "authentication required"
 )
 
 const (
-	MsgShutdownSignal       = "shutdown signal received"
-	MsgConfigLoading        = "configuration loading"
-	MsgConfigLoaded         = "configuration successfully loaded"
-	MsgDBMigrationStarted   = "starting database migration"
-	MsgDBMigrationCompleted = "database migration completed successfully"
-	MsgIncomingRequest      = "incoming request"
-	MsgConnectingToPostgres = "connecting to Postgres database"
-	MsgPostgresConnected    = "successfully connected to Postgres"
-	MsgDBMigrationsApplied  = "database migrations successfully applied"
-	MsgClosingPostgresPool  = "closing Postgres connection pool"
-	MsgHTTPError            = "HTTP error"
-	MsgNotifyRestaurant     = "notifying restaurant"
-	MsgNotifyUser           = "notifying user"
-	MsgServerStarting       = "starting server"
-	MsgServerStartError     = "error starting server"
-	MsgServerShuttingDown   = "shutting down server"
-	MsgServerForcedShutdown = "server forced to shutdown"
-	MsgServerGracefulStop   = "server gracefully stopped"
-	MsgServerStopping       = "stopping server"
-	MsgSuccess              = "success"
-	MsgUpdateAvailability   = "setting availability for restaurant"
+	MsgShutdownSignal           = "shutdown signal received"
+	MsgConfigLoading            = "configuration loading"
+	MsgConfigLoaded             = "configuration successfully loaded"
+	MsgDBMigrationStarted       = "starting database migration"
+	MsgDBMigrationCompleted     = "database migration completed successfully"
+	MsgShardMigrationStarted    = "migrating shard"
+	MsgIncomingRequest          = "incoming request"
+	MsgConnectingToPostgres     = "connecting to Postgres database"
+	MsgPostgresConnected        = "successfully connected to Postgres"
+	MsgPostgresQueryExecMode    = "Postgres statements are sent in query exec mode"
+	MsgPoolerDetected           = "Postgres is reached through a connection pooler"
+	MsgPoolerPreparedStatements = "statements are prepared behind a connection pooler, set POSTGRES_POOLER=pgbouncer or a query exec mode that does not prepare them"
+	MsgPoolerMigrations         = "migrations take a session advisory lock, run them against Postgres directly when the pooler uses transaction pooling"
+	MsgPoolerNotDetected        = "POSTGRES_POOLER is set but no connection pooler was detected"
+	MsgDBMigrationsApplied      = "database migrations successfully applied"
+	MsgClosingPostgresPool      = "closing Postgres connection pool"
+	MsgFaultInjectionEnabled    = "fault injection is enabled, never use it in production"
+	MsgMemoryStorage            = "storing records in memory, nothing is kept across restarts"
+	MsgCacheWarmingWithoutCache = "CACHE_WARM_CITIES is set but CACHE_BACKEND is not, nothing is warmed"
+	MsgHTTPError                = "HTTP error"
+	MsgNotifyRestaurant         = "notifying restaurant"
+	MsgNotifyUser               = "notifying user"
+	MsgServerStarting           = "starting server"
+	MsgDiagnosticsStarting      = "starting diagnostics server"
+	MsgServerStartError         = "error starting server"
+	MsgServerShuttingDown       = "shutting down server"
+	MsgServerForcedShutdown     = "server forced to shutdown"
+	MsgServerGracefulStop       = "server gracefully stopped"
+	MsgServerStopping           = "stopping server"
+	MsgSuccess                  = "success"
+	MsgUpdateAvailability       = "setting availability for restaurant"
+	MsgJobStarted               = "background job started"
+	MsgJobCompleted             = "background job completed"
+	MsgSchedulerStarting        = "starting background jobs scheduler"
+	MsgSchedulerStopped         = "background jobs scheduler stopped"
 )
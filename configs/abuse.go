@@ -0,0 +1,25 @@
+package configs
+
+import "time"
+
+type AbuseConfig struct {
+	// Window is how far back booking patterns are looked at.
+	Window time.Duration `env:"ABUSE_WINDOW" env-default:"1h"`
+
+	// BookingsPerClient is how many bookings one client address may make within the window
+	// before it is flagged; zero turns the check off.
+	BookingsPerClient int `env:"ABUSE_BOOKINGS_PER_CLIENT" env-default:"20"`
+
+	// CancelRebookCycles is how many times one client may cancel a booking and book the same
+	// restaurant again within the window before it is flagged; zero turns the check off.
+	CancelRebookCycles int `env:"ABUSE_CANCEL_REBOOK_CYCLES" env-default:"3"`
+
+	// RateLimit is how many write API requests one client address may make per minute; zero
+	// leaves clients unlimited until they are flagged.
+	RateLimit int `env:"ABUSE_RATE_LIMIT" env-default:"0"`
+
+	// AutoTighten lowers the rate limit of a flagged client to TightenedRateLimit for TightenFor.
+	AutoTighten        bool          `env:"ABUSE_AUTO_TIGHTEN"          env-default:"false"`
+	TightenedRateLimit int           `env:"ABUSE_TIGHTENED_RATE_LIMIT"  env-default:"5"`
+	TightenFor         time.Duration `env:"ABUSE_TIGHTEN_FOR"           env-default:"1h"`
+}
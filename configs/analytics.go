@@ -0,0 +1,19 @@
+package configs
+
+import "time"
+
+type AnalyticsConfig struct {
+	// ForecastWeeks is how many past weeks of bookings the occupancy forecast averages over.
+	ForecastWeeks int `env:"ANALYTICS_FORECAST_WEEKS" env-default:"4"`
+
+	// HeatmapWeeks is how many past weeks of bookings the demand heatmap counts when the request
+	// does not ask for a number.
+	HeatmapWeeks int `env:"ANALYTICS_HEATMAP_WEEKS" env-default:"12"`
+
+	// Restaurants answering at least SlowResponseMinResponses of the bookings requested over the
+	// last SlowResponseDays days with a median response time above SlowResponseThreshold are
+	// flagged as slow responders to admins.
+	SlowResponseDays         int           `env:"ANALYTICS_SLOW_RESPONSE_DAYS" env-default:"28"`
+	SlowResponseThreshold    time.Duration `env:"ANALYTICS_SLOW_RESPONSE_THRESHOLD" env-default:"2h"`
+	SlowResponseMinResponses int           `env:"ANALYTICS_SLOW_RESPONSE_MIN_RESPONSES" env-default:"10"`
+}
@@ -0,0 +1,29 @@
+package configs
+
+import "time"
+
+type AuthConfig struct {
+	// JWTSecret signs the access tokens; empty turns registration and login off and rejects every
+	// bearer token. Changing it signs every account out.
+	JWTSecret       string        `env:"AUTH_JWT_SECRET"`
+	Issuer          string        `env:"AUTH_JWT_ISSUER"         env-default:"restaurant-booking"`
+	AccessTokenTTL  time.Duration `env:"AUTH_ACCESS_TOKEN_TTL"   env-default:"15m"`
+	RefreshTokenTTL time.Duration `env:"AUTH_REFRESH_TOKEN_TTL"  env-default:"720h"`
+	MinPasswordLen  int           `env:"AUTH_MIN_PASSWORD_LENGTH" env-default:"8"`
+	// AdminEmails are the accounts signed in with the admin role once they verified their email
+	// through the link mailed to it.
+	AdminEmails []string `env:"AUTH_ADMIN_EMAILS" env-separator:","`
+
+	// Required turns away requests without a principal from /users, /bookings and /admin and the
+	// changes of /restaurants. Off by default so that existing clients keep working while they move
+	// to tokens.
+	Required bool `env:"AUTH_REQUIRED" env-default:"false"`
+	// TrustGatewayHeaders reads the principal from the X-User-ID, X-Restaurant-IDs and X-Roles
+	// headers of an authenticating gateway for requests without a bearer token. Only requests from
+	// GatewayNetworks carrying GatewaySecret in X-Gateway-Secret are trusted, whichever of the two
+	// are set; at least one must be, or anyone could name themselves.
+	TrustGatewayHeaders bool `env:"AUTH_TRUST_GATEWAY_HEADERS" env-default:"false"`
+	// GatewayNetworks are the networks in CIDR notation the gateway reaches the service from.
+	GatewayNetworks []string `env:"AUTH_GATEWAY_NETWORKS" env-separator:","`
+	GatewaySecret   string   `env:"AUTH_GATEWAY_SECRET"`
+}
@@ -0,0 +1,17 @@
+package configs
+
+import "time"
+
+type BillingConfig struct {
+	// ProPrice is the monthly price of the pro plan in minor units of Currency.
+	ProPrice        int64  `env:"BILLING_PRO_PRICE"         env-default:"4900"`
+	Currency        string `env:"BILLING_CURRENCY"          env-default:"EUR"`
+	PaymentTermDays int    `env:"BILLING_PAYMENT_TERM_DAYS" env-default:"14"`
+	// WebhookSecret signs the events of the payment provider; empty rejects them all.
+	WebhookSecret string `env:"BILLING_WEBHOOK_SECRET"`
+	Issuer        string `env:"BILLING_ISSUER" env-default:"Restaurant Booking Platform"`
+
+	// InvoiceAt is the offset from local midnight at which the subscriptions of the month before
+	// that were not billed yet are invoiced.
+	InvoiceAt time.Duration `env:"BILLING_INVOICE_AT" env-default:"4h"`
+}
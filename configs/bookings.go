@@ -0,0 +1,11 @@
+package configs
+
+import "time"
+
+type BookingsConfig struct {
+	// PreOrderCutoff is how long before a booking starts its pre-order can no longer be changed.
+	PreOrderCutoff time.Duration `env:"PRE_ORDER_CUTOFF" env-default:"3h"`
+
+	// DraftTTL is how long a booking draft and the seats it holds are kept after its last step.
+	DraftTTL time.Duration `env:"BOOKING_DRAFT_TTL" env-default:"15m"`
+}
@@ -0,0 +1,26 @@
+package configs
+
+import "time"
+
+type CacheConfig struct {
+	// Backend is "redis", shared by the instances of a region, "memory" for a single instance, or
+	// empty to read everything from the database.
+	Backend string `env:"CACHE_BACKEND"`
+
+	RedisAddr     string        `env:"REDIS_ADDR"      env-default:"localhost:6379"`
+	RedisPassword string        `env:"REDIS_PASSWORD"`
+	RedisDB       int           `env:"REDIS_DB"        env-default:"0"`
+	RedisPoolSize int           `env:"REDIS_POOL_SIZE" env-default:"10"`
+	RedisTimeout  time.Duration `env:"REDIS_TIMEOUT"   env-default:"2s"`
+
+	// WarmCities are the IDs of the cities whose restaurants and availability for the next
+	// WarmDays days are written to the cache every WarmInterval, at most WarmMaxRestaurants
+	// restaurants of each. Each region lists its own popular cities; nothing is warmed without.
+	WarmCities         []string      `env:"CACHE_WARM_CITIES"          env-separator:","`
+	WarmDays           int           `env:"CACHE_WARM_DAYS"            env-default:"7"`
+	WarmInterval       time.Duration `env:"CACHE_WARM_INTERVAL"        env-default:"1m"`
+	WarmMaxRestaurants int           `env:"CACHE_WARM_MAX_RESTAURANTS" env-default:"500"`
+	// TTL is how long a warmed value is served; keep it above WarmInterval so that the values are
+	// replaced before they expire. It is also the longest a served value lags the database.
+	TTL time.Duration `env:"CACHE_TTL" env-default:"3m"`
+}
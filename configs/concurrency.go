@@ -0,0 +1,21 @@
+package configs
+
+import "time"
+
+type ConcurrencyConfig struct {
+	// MaxInFlight is how many requests are handled at once; keep it below POSTGRES_MAX_CONNECTIONS
+	// so that background jobs still get connections. Zero leaves the server unlimited.
+	MaxInFlight int `env:"CONCURRENCY_MAX_IN_FLIGHT" env-default:"80"`
+
+	// MaxInFlightPerRoute keeps one slow endpoint from taking every slot; zero turns it off.
+	MaxInFlightPerRoute int `env:"CONCURRENCY_MAX_IN_FLIGHT_PER_ROUTE" env-default:"40"`
+
+	// MaxQueue is how many requests may wait for a slot; the rest are rejected at once.
+	MaxQueue int `env:"CONCURRENCY_MAX_QUEUE" env-default:"200"`
+
+	// QueueTimeout is how long a request waits for a slot before it is rejected.
+	QueueTimeout time.Duration `env:"CONCURRENCY_QUEUE_TIMEOUT" env-default:"1s"`
+
+	// RetryAfter is sent to rejected clients in the Retry-After header.
+	RetryAfter time.Duration `env:"CONCURRENCY_RETRY_AFTER" env-default:"1s"`
+}
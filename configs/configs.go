@@ -3,6 +3,7 @@ package configs
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"go.uber.org/zap"
@@ -12,11 +13,46 @@ import (
 )
 
 type Config struct {
-	Database PostgresConfig `yaml:"postgres"`
-	Shutdown ShutdownConfig `yaml:"shutdown"`
-	Server   ServerConfig   `yaml:"server"`
-	SMTP     *SMTPConfig    `yaml:"smtp"`
-	LogLevel string         `env:"LOG_LEVEL" env-default:"info" yaml:"log_level"`
+	Storage       StorageConfig        `yaml:"storage"`
+	Database      PostgresConfig       `yaml:"postgres"`
+	Shutdown      ShutdownConfig       `yaml:"shutdown"`
+	Server        ServerConfig         `yaml:"server"`
+	SMTP          *SMTPConfig          `yaml:"smtp"`
+	Jobs          JobsConfig           `yaml:"jobs"`
+	Notifications NotificationsConfig  `yaml:"notifications"`
+	Replay        ReplayConfig         `yaml:"replay"`
+	Bookings      BookingsConfig       `yaml:"bookings"`
+	Images        ImagesConfig         `yaml:"images"`
+	Abuse         AbuseConfig          `yaml:"abuse"`
+	Geo           GeoConfig            `yaml:"geo"`
+	Retention     RetentionConfig      `yaml:"retention"`
+	Embed         EmbedConfig          `yaml:"embed"`
+	Domains       DomainsConfig        `yaml:"domains"`
+	CORS          CORSConfig           `yaml:"cors"`
+	Concurrency   ConcurrencyConfig    `yaml:"concurrency"`
+	Analytics     AnalyticsConfig      `yaml:"analytics"`
+	Quotas        QuotasConfig         `yaml:"quotas"`
+	Billing       BillingConfig        `yaml:"billing"`
+	Contacts      ContactsConfig       `yaml:"contacts"`
+	Geocoding     GeocodingConfig      `yaml:"geocoding"`
+	Logging       LoggingConfig        `yaml:"logging"`
+	SLO           SLOConfig            `yaml:"slo"`
+	Faults        FaultInjectionConfig `yaml:"faults"`
+	Diagnostics   DiagnosticsConfig    `yaml:"diagnostics"`
+	Auth          AuthConfig           `yaml:"auth"`
+	Cache         CacheConfig          `yaml:"cache"`
+	LogLevel      string               `env:"LOG_LEVEL" env-default:"info" yaml:"log_level"`
+	// Environment is "development" on the machine of a developer; anything else is a deployment,
+	// which refuses the defaults only development gets away with.
+	Environment string `env:"APP_ENV" env-default:"production" yaml:"environment"`
+}
+
+// EnvironmentDevelopment is the Environment of the machine of a developer.
+const EnvironmentDevelopment = "development"
+
+// IsDevelopment reports whether the service runs on the machine of a developer.
+func (c *Config) IsDevelopment() bool {
+	return strings.EqualFold(strings.TrimSpace(c.Environment), EnvironmentDevelopment)
 }
 
 func Load(ctx context.Context) (*Config, error) {
@@ -44,6 +80,11 @@ func Load(ctx context.Context) (*Config, error) {
 	}
 	cfg.SMTP = smtpConfig
 
+	if err := cfg.Notifications.CheckBookingLinkSecret(cfg.IsDevelopment()); err != nil {
+		log.Error(ctx, common.ErrConfigLoading, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrConfigLoad, err)
+	}
+
 	log.Info(ctx, common.MsgConfigLoaded)
 
 	return &cfg, nil
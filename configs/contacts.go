@@ -0,0 +1,8 @@
+package configs
+
+// ContactsConfig sets how the phones of users and restaurants are read.
+type ContactsConfig struct {
+	// PhoneRegion is the ISO 3166-1 code of the country whose numbers may be written without a
+	// country code.
+	PhoneRegion string `env:"CONTACTS_PHONE_REGION" env-default:"RU"`
+}
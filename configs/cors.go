@@ -0,0 +1,24 @@
+package configs
+
+import "time"
+
+type CORSConfig struct {
+	// AllowedOrigins are the origins of the web frontends allowed to call the API; "*" allows
+	// every origin and cannot be combined with AllowCredentials.
+	AllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" env-default:"*" env-separator:","`
+
+	AllowedMethods []string `env:"CORS_ALLOWED_METHODS" env-default:"GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS" env-separator:","`
+
+	// AllowedHeaders are the request headers browsers may send; empty allows the headers of the
+	// API clients. The principal headers of the gateway can't be allowed.
+	AllowedHeaders []string `env:"CORS_ALLOWED_HEADERS" env-default:"" env-separator:","`
+
+	// ExposedHeaders are the response headers scripts may read besides the safelisted ones.
+	ExposedHeaders []string `env:"CORS_EXPOSED_HEADERS" env-default:"ETag,Last-Modified,Location,Retry-After,X-Next-Cursor" env-separator:","`
+
+	// AllowCredentials lets browsers send cookies and authorization with cross-origin requests.
+	AllowCredentials bool `env:"CORS_ALLOW_CREDENTIALS" env-default:"false"`
+
+	// MaxAge is how long browsers may cache a preflight response; zero leaves it to the browser.
+	MaxAge time.Duration `env:"CORS_MAX_AGE" env-default:"10m"`
+}
@@ -9,4 +9,15 @@ type PostgresConfig struct {
 	SSLMode        string `env:"POSTGRES_SSLMODE"         env-default:"disable"`
 	MaxConnections int    `env:"POSTGRES_MAX_CONNECTIONS" env-default:"100"`
 	MinConnections int    `env:"POSTGRES_MIN_CONNECTIONS" env-default:"3"`
+	// QueryExecMode is how pgx sends statements. cache_statement (the default) prepares each one
+	// once per connection; cache_describe, describe_exec, exec and simple_protocol leave no
+	// prepared statement on the server, which is what a transaction pooler such as PgBouncer needs.
+	// The capacities bound the per-connection caches of cache_statement and cache_describe.
+	QueryExecMode            string `env:"POSTGRES_QUERY_EXEC_MODE"            env-default:"cache_statement"`
+	StatementCacheCapacity   int    `env:"POSTGRES_STATEMENT_CACHE_CAPACITY"   env-default:"512"`
+	DescriptionCacheCapacity int    `env:"POSTGRES_DESCRIPTION_CACHE_CAPACITY" env-default:"512"`
+	// Pooler is the connection pooler between the service and PostgreSQL: none, or pgbouncer to
+	// stop preparing statements, keep no idle connections and hold at most PoolerMaxConnections.
+	Pooler               string `env:"POSTGRES_POOLER"                 env-default:"none"`
+	PoolerMaxConnections int    `env:"POSTGRES_POOLER_MAX_CONNECTIONS" env-default:"20"`
 }
@@ -0,0 +1,8 @@
+package configs
+
+type DiagnosticsConfig struct {
+	// Enabled serves the pprof profiles and the expvar metrics of the process on Addr. The port
+	// must not be reachable from outside; it listens on localhost only by default.
+	Enabled bool   `env:"DIAGNOSTICS_ENABLED" env-default:"false"`
+	Addr    string `env:"DIAGNOSTICS_ADDR"    env-default:"localhost:6060"`
+}
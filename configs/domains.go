@@ -0,0 +1,20 @@
+package configs
+
+type DomainsConfig struct {
+	// TLS serves HTTPS on the server port with certificates obtained from Let's Encrypt, through
+	// the TLS-ALPN challenge, for the public host and the verified custom domains of restaurants.
+	TLS bool `env:"DOMAINS_TLS" env-default:"false"`
+
+	// CertCacheDir keeps the obtained certificates across restarts.
+	CertCacheDir string `env:"DOMAINS_CERT_CACHE_DIR" env-default:"certs"`
+
+	// ACMEEmail is given to the certificate authority to be told about expiring certificates.
+	ACMEEmail string `env:"DOMAINS_ACME_EMAIL"`
+
+	// CNAMETarget is the host restaurants point their domains at; the host of SERVER_PUBLIC_URL
+	// when empty.
+	CNAMETarget string `env:"DOMAINS_CNAME_TARGET"`
+
+	// MaxPerRestaurant bounds the domains a restaurant can add.
+	MaxPerRestaurant int `env:"DOMAINS_MAX_PER_RESTAURANT" env-default:"5"`
+}
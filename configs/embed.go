@@ -0,0 +1,14 @@
+package configs
+
+type EmbedConfig struct {
+	// AllowedOrigins are the origins whose pages may fetch the availability widget; "*" allows
+	// every website.
+	AllowedOrigins []string `env:"EMBED_ALLOWED_ORIGINS" env-default:"*" env-separator:","`
+
+	// FrameAncestors are the sources, in the syntax of the CSP frame-ancestors directive, allowed
+	// to iframe the HTML widget.
+	FrameAncestors []string `env:"EMBED_FRAME_ANCESTORS" env-default:"*" env-separator:","`
+
+	// Days is how many days the widget shows when the page does not ask for a number.
+	Days int `env:"EMBED_DAYS" env-default:"7"`
+}
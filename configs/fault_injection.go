@@ -0,0 +1,7 @@
+package configs
+
+type FaultInjectionConfig struct {
+	// Enabled lets admins inject latency, errors and database failures for resilience testing.
+	// It must stay off in production.
+	Enabled bool `env:"FAULT_INJECTION_ENABLED" env-default:"false"`
+}
@@ -0,0 +1,7 @@
+package configs
+
+type GeoConfig struct {
+	// DatabasePath is the MaxMind database, such as GeoLite2-City.mmdb, client addresses are
+	// located with. Empty turns the location-based defaults off.
+	DatabasePath string `env:"GEOIP_DATABASE_PATH" env-default:""`
+}
@@ -0,0 +1,15 @@
+package configs
+
+// GeocodingConfig sets the provider restaurant addresses are geocoded with.
+type GeocodingConfig struct {
+	// Provider is "nominatim" or "google"; empty turns geocoding off.
+	Provider string `env:"GEOCODING_PROVIDER" env-default:""`
+
+	NominatimURL string `env:"GEOCODING_NOMINATIM_URL"  env-default:"https://nominatim.openstreetmap.org"`
+	// UserAgent identifies the platform to Nominatim, whose usage policy requires it.
+	UserAgent    string `env:"GEOCODING_USER_AGENT"     env-default:"case-back-restaurant-go"`
+	GoogleAPIKey string `env:"GEOCODING_GOOGLE_API_KEY" env-default:""`
+
+	// Language is the preferred language of the address components.
+	Language string `env:"GEOCODING_LANGUAGE" env-default:"en"`
+}
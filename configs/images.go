@@ -0,0 +1,20 @@
+package configs
+
+type ImagesConfig struct {
+	// CacheBackend is "disk", keeping resized images in CacheDir across restarts, or "memory".
+	CacheBackend string `env:"IMAGE_CACHE_BACKEND" env-default:"disk"`
+
+	// CacheDir is the directory resized restaurant images are kept in.
+	CacheDir string `env:"IMAGE_CACHE_DIR" env-default:"./var/image-cache"`
+
+	// CacheSize is the largest total size of the cached images in bytes; the least recently used
+	// ones are removed beyond it. Zero turns the cache off.
+	CacheSize int64 `env:"IMAGE_CACHE_SIZE" env-default:"268435456"`
+
+	// MaxDimension is the largest width or height a resized image may be asked for.
+	MaxDimension int `env:"IMAGE_MAX_DIMENSION" env-default:"2048"`
+
+	// VariantFormats are the content types, most preferred first, every upload is converted to in
+	// the background; clients accepting one of them get it instead of the original format.
+	VariantFormats []string `env:"IMAGE_VARIANT_FORMATS" env-default:"image/webp" env-separator:","`
+}
@@ -0,0 +1,69 @@
+package configs
+
+import "time"
+
+type JobsConfig struct {
+	FactOfTheDayLocales  []string      `env:"FACT_OF_THE_DAY_LOCALES"  env-default:"en" env-separator:","`
+	FactOfTheDayInterval time.Duration `env:"FACT_OF_THE_DAY_INTERVAL" env-default:"1h"`
+
+	// ReservedSeatsReconciliationAt is the offset from local midnight of the nightly run.
+	ReservedSeatsReconciliationAt time.Duration `env:"RESERVED_SEATS_RECONCILIATION_AT" env-default:"3h"`
+
+	// RestaurantDigestAt is the offset from local midnight at which restaurants get their daily digest.
+	RestaurantDigestAt time.Duration `env:"RESTAURANT_DIGEST_AT" env-default:"8h"`
+
+	// DigestOccasionDays is how many days after the day of a digest its occasion notes look ahead.
+	DigestOccasionDays int `env:"DIGEST_OCCASION_DAYS" env-default:"3"`
+
+	// WeeklyReportDay is the day, 0 for Sunday to 6 for Saturday, on which the digest run also
+	// emails restaurants the report of the week before.
+	WeeklyReportDay time.Weekday `env:"WEEKLY_REPORT_DAY" env-default:"1"`
+
+	// ReengagementAt is the offset from local midnight at which guests who opted in to marketing
+	// are invited back, RebookAfterWeeks after their last visit or AnniversaryLeadDays before the
+	// anniversary of one celebrated at a restaurant; zero turns either invitation off.
+	ReengagementAt      time.Duration `env:"REENGAGEMENT_AT"       env-default:"11h"`
+	RebookAfterWeeks    int           `env:"REBOOK_AFTER_WEEKS"    env-default:"6"`
+	AnniversaryLeadDays int           `env:"ANNIVERSARY_LEAD_DAYS" env-default:"14"`
+
+	// ImageVariantsInterval is how often queued image variants are generated, up to
+	// ImageVariantsBatchSize per run.
+	ImageVariantsInterval  time.Duration `env:"IMAGE_VARIANTS_INTERVAL"   env-default:"30s"`
+	ImageVariantsBatchSize int           `env:"IMAGE_VARIANTS_BATCH_SIZE" env-default:"20"`
+
+	// NotificationRetryInterval is how often failed notifications due for a retry are delivered
+	// again, up to NotificationRetryBatchSize per run.
+	NotificationRetryInterval  time.Duration `env:"NOTIFICATION_RETRY_INTERVAL"   env-default:"30s"`
+	NotificationRetryBatchSize int           `env:"NOTIFICATION_RETRY_BATCH_SIZE" env-default:"50"`
+
+	// DeferredNotificationInterval is how often notifications held back by quiet hours are checked
+	// and delivered once due, up to DeferredNotificationBatchSize per run.
+	DeferredNotificationInterval  time.Duration `env:"DEFERRED_NOTIFICATION_INTERVAL"   env-default:"1m"`
+	DeferredNotificationBatchSize int           `env:"DEFERRED_NOTIFICATION_BATCH_SIZE" env-default:"100"`
+
+	// GeocodingInterval is how often queued restaurant addresses are geocoded, up to
+	// GeocodingBatchSize per run.
+	GeocodingInterval  time.Duration `env:"GEOCODING_INTERVAL"   env-default:"1m"`
+	GeocodingBatchSize int           `env:"GEOCODING_BATCH_SIZE" env-default:"20"`
+
+	// AvailabilityAlertInterval is how often the alerts of guests waiting for a table are checked
+	// against the freed seats.
+	AvailabilityAlertInterval time.Duration `env:"AVAILABILITY_ALERT_INTERVAL" env-default:"5m"`
+
+	// BookingDraftExpiryInterval is how often the expired booking drafts are deleted and the
+	// seats they held released.
+	BookingDraftExpiryInterval time.Duration `env:"BOOKING_DRAFT_EXPIRY_INTERVAL" env-default:"1m"`
+
+	// TableOptimizationAt are the offsets from local midnight, one before each service, at which
+	// the bookings of the day yet to start are seated again at the tables that fit them best.
+	TableOptimizationAt []time.Duration `env:"TABLE_OPTIMIZATION_AT" env-default:"10h,16h" env-separator:","`
+
+	// IndexAdvisorAt is the offset from local midnight at which the tables scanned sequentially and
+	// the statements reading the most blocks per row are reported: tables of at least
+	// IndexAdvisorMinRows live rows and statements called at least IndexAdvisorMinCalls times, up
+	// to IndexAdvisorLimit of each.
+	IndexAdvisorAt       time.Duration `env:"INDEX_ADVISOR_AT"        env-default:"4h"`
+	IndexAdvisorMinRows  int64         `env:"INDEX_ADVISOR_MIN_ROWS"  env-default:"10000"`
+	IndexAdvisorMinCalls int64         `env:"INDEX_ADVISOR_MIN_CALLS" env-default:"100"`
+	IndexAdvisorLimit    int           `env:"INDEX_ADVISOR_LIMIT"     env-default:"10"`
+}
@@ -0,0 +1,43 @@
+package configs
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type LoggingConfig struct {
+	// SampleInitial is how many lines with the same level and message are written each second
+	// before only every SampleThereafter-th one is; zero writes every line.
+	SampleInitial    int `env:"LOG_SAMPLE_INITIAL" env-default:"100"`
+	SampleThereafter int `env:"LOG_SAMPLE_THEREAFTER" env-default:"100"`
+
+	// SampledReadRoutes are the path prefixes of the hot read endpoints, such as the ones polled by
+	// displays and widgets, whose GET requests write their info and debug lines for one request in
+	// ReadSampleRate only. Warnings and errors are always written.
+	SampledReadRoutes []string `env:"LOG_SAMPLED_READ_ROUTES" env-default:"/api/v1/restaurants,/embed,/images" env-separator:","`
+
+	// ReadSampleRate of 1 or less writes the lines of every request.
+	ReadSampleRate int `env:"LOG_READ_SAMPLE_RATE" env-default:"10"`
+}
+
+// ZapConfig returns the production configuration of zap writing from the level, "info" when it is
+// not a known one, and sampling as configured.
+func (c LoggingConfig) ZapConfig(level string) zap.Config {
+	config := zap.NewProductionConfig()
+
+	zapLevel, err := zapcore.ParseLevel(level)
+	if err != nil {
+		zapLevel = zapcore.InfoLevel
+	}
+	config.Level = zap.NewAtomicLevelAt(zapLevel)
+
+	config.Sampling = nil
+	if c.SampleInitial > 0 {
+		config.Sampling = &zap.SamplingConfig{
+			Initial:    c.SampleInitial,
+			Thereafter: max(c.SampleThereafter, 1),
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,53 @@
+package configs
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+)
+
+// DevelopmentBookingLinkSecret is the BOOKING_LINK_SECRET when none is set. Anyone can read it
+// here, and with it forge the links that show and cancel bookings.
+const DevelopmentBookingLinkSecret = "change-me"
+
+type NotificationsConfig struct {
+	// RestaurantWindow is the time within which further notifications of a restaurant are
+	// combined into one summary; zero sends every notification on its own.
+	RestaurantWindow time.Duration `env:"RESTAURANT_NOTIFICATION_WINDOW" env-default:"1m"`
+
+	// BookingLinkSecret signs the short links to bookings sent in SMS; changing it invalidates
+	// every link handed out before. The default is only accepted in development.
+	BookingLinkSecret string `env:"BOOKING_LINK_SECRET" env-default:"change-me"`
+
+	// BookingLinkTTL is how long a booking link stays valid when no expiry is given.
+	BookingLinkTTL time.Duration `env:"BOOKING_LINK_TTL" env-default:"72h"`
+
+	// EmailChannel is "smtp", sending emails through the SMTP server, or "log", printing them
+	// instead.
+	EmailChannel string `env:"NOTIFICATION_EMAIL_CHANNEL" env-default:"log"`
+
+	// SMSChannel is "http", posting SMS to the gateway at SMSGatewayURL, or "log", logging them
+	// instead with the links they carry redacted.
+	SMSChannel      string `env:"NOTIFICATION_SMS_CHANNEL" env-default:"log"`
+	SMSGatewayURL   string `env:"SMS_GATEWAY_URL"          env-default:""`
+	SMSGatewayToken string `env:"SMS_GATEWAY_TOKEN"        env-default:""`
+	// SMSSender is the name the messages are sent from, as registered with the gateway.
+	SMSSender string `env:"SMS_SENDER" env-default:""`
+
+	// RetryMaxAttempts is how many times a failed notification is delivered in all, the first
+	// delivery included, before it is given up. The wait before a retry starts at RetryBackoff
+	// and doubles with every attempt up to RetryMaxBackoff.
+	RetryMaxAttempts int           `env:"NOTIFICATION_RETRY_MAX_ATTEMPTS" env-default:"5"`
+	RetryBackoff     time.Duration `env:"NOTIFICATION_RETRY_BACKOFF"      env-default:"1m"`
+	RetryMaxBackoff  time.Duration `env:"NOTIFICATION_RETRY_MAX_BACKOFF"  env-default:"1h"`
+}
+
+// CheckBookingLinkSecret refuses an empty booking link secret, and outside development the
+// default one.
+func (c NotificationsConfig) CheckBookingLinkSecret(development bool) error {
+	if c.BookingLinkSecret == "" || (c.BookingLinkSecret == DevelopmentBookingLinkSecret && !development) {
+		return errors.New(common.ErrBookingLinkSecretRequired)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package configs
+
+// QuotasConfig sets the limits of the plans restaurants are on; zero is unlimited.
+type QuotasConfig struct {
+	FreeActiveSlots     int `env:"QUOTA_FREE_ACTIVE_SLOTS"      env-default:"500"`
+	FreeMonthlyBookings int `env:"QUOTA_FREE_MONTHLY_BOOKINGS"  env-default:"300"`
+	ProActiveSlots      int `env:"QUOTA_PRO_ACTIVE_SLOTS"       env-default:"0"`
+	ProMonthlyBookings  int `env:"QUOTA_PRO_MONTHLY_BOOKINGS"   env-default:"0"`
+}
@@ -0,0 +1,14 @@
+package configs
+
+type ReplayConfig struct {
+	// RequestsPerKey is how many of the latest API requests are kept for each API key; zero
+	// turns recording off.
+	RequestsPerKey int `env:"REQUEST_LOG_SIZE" env-default:"50"`
+
+	// StagingURL is the base URL recorded requests are replayed against; replaying is off when
+	// it is empty.
+	StagingURL string `env:"REPLAY_STAGING_URL"`
+
+	// StagingAPIKey is sent as X-API-Key with replayed requests.
+	StagingAPIKey string `env:"REPLAY_STAGING_API_KEY"`
+}
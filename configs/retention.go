@@ -0,0 +1,18 @@
+package configs
+
+import "time"
+
+type RetentionConfig struct {
+	// ReadNotifications is how long notifications are kept after they were read; zero keeps them.
+	ReadNotifications time.Duration `env:"RETENTION_READ_NOTIFICATIONS" env-default:"2160h"`
+
+	// CompletedBookings is how long completed bookings keep the guest before they are
+	// anonymized; zero keeps it.
+	CompletedBookings time.Duration `env:"RETENTION_COMPLETED_BOOKINGS" env-default:"17520h"`
+
+	// At is the offset from local midnight of the nightly run.
+	At time.Duration `env:"RETENTION_AT" env-default:"4h"`
+
+	// DryRun makes the nightly run only report what it would purge and anonymize.
+	DryRun bool `env:"RETENTION_DRY_RUN" env-default:"false"`
+}
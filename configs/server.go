@@ -1,6 +1,42 @@
 package configs
 
+import (
+	"net/url"
+	"time"
+)
+
 type ServerConfig struct {
-	Host string `env:"SERVER_HOST" env-default:"localhost"`
-	Port int    `env:"SERVER_PORT" env-default:"8080"`
+	Host      string `env:"SERVER_HOST"       env-default:"localhost"`
+	Port      int    `env:"SERVER_PORT"       env-default:"8080"`
+	PublicURL string `env:"SERVER_PUBLIC_URL" env-default:"http://localhost:8080"`
+
+	// TenantGuardStrict makes the repositories panic when a record of another tenant reaches
+	// the request principal; meant for development and tests.
+	TenantGuardStrict bool `env:"TENANT_GUARD_STRICT" env-default:"false"`
+
+	// ResponseEnvelope wraps API responses into {data, meta, error}. It is off by default so that
+	// v1 clients keep bare bodies; a client can still choose with the X-Response-Envelope header.
+	ResponseEnvelope bool `env:"RESPONSE_ENVELOPE" env-default:"false"`
+
+	// BodyLimit is the largest accepted request body in bytes; larger requests get a 413.
+	BodyLimit int `env:"SERVER_BODY_LIMIT" env-default:"4194304"`
+
+	// CompressionLevel of gzip, deflate and brotli responses: -1 turns compression off, 0 is the
+	// default level, 1 favours speed and 2 size. Bodies under 200 bytes are sent as they are.
+	CompressionLevel int `env:"SERVER_COMPRESSION_LEVEL" env-default:"0"`
+
+	// RequestTimeout bounds the handling of a request, database queries included; requests running
+	// out of it get a 504. LongRequestTimeout is for imports, availability generation, reports and
+	// retention runs. Zero turns the timeout off.
+	RequestTimeout     time.Duration `env:"SERVER_REQUEST_TIMEOUT"      env-default:"15s"`
+	LongRequestTimeout time.Duration `env:"SERVER_LONG_REQUEST_TIMEOUT" env-default:"2m"`
+}
+
+// PublicHost is the host name of PublicURL, without the port; empty when the URL has none.
+func (c ServerConfig) PublicHost() string {
+	parsed, err := url.Parse(c.PublicURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
 }
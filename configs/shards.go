@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ShardDatabase returns the connection of the named shard: base with the host, port, database,
+// user and password replaced by POSTGRES_SHARD_<NAME>_HOST, _PORT, _DB, _USER and _PASSWORD where
+// they are set. The name is upper-cased with dashes turned into underscores, so the shard eu-1
+// reads POSTGRES_SHARD_EU_1_HOST.
+func ShardDatabase(base PostgresConfig, shard string) (PostgresConfig, error) {
+	prefix := "POSTGRES_SHARD_" + strings.ToUpper(strings.ReplaceAll(shard, "-", "_")) + "_"
+
+	if value, ok := os.LookupEnv(prefix + "HOST"); ok {
+		base.Host = value
+	}
+	if value, ok := os.LookupEnv(prefix + "PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return base, fmt.Errorf("%sPORT: %w", prefix, err)
+		}
+		base.Port = port
+	}
+	if value, ok := os.LookupEnv(prefix + "DB"); ok {
+		base.Database = value
+	}
+	if value, ok := os.LookupEnv(prefix + "USER"); ok {
+		base.Username = value
+	}
+	if value, ok := os.LookupEnv(prefix + "PASSWORD"); ok {
+		base.Password = value
+	}
+	return base, nil
+}
@@ -0,0 +1,23 @@
+package configs
+
+import "time"
+
+type SLOConfig struct {
+	// AvailabilityTarget is the share of requests to answer without a server error, and
+	// LatencyTarget the share to answer within LatencyThreshold.
+	AvailabilityTarget float64       `env:"SLO_AVAILABILITY_TARGET" env-default:"0.999"`
+	LatencyTarget      float64       `env:"SLO_LATENCY_TARGET"      env-default:"0.99"`
+	LatencyThreshold   time.Duration `env:"SLO_LATENCY_THRESHOLD"   env-default:"500ms"`
+
+	// FlushInterval is how often the counted requests are stored and the burn rates checked.
+	FlushInterval time.Duration `env:"SLO_FLUSH_INTERVAL" env-default:"1m"`
+
+	// ReportAt is the offset from local midnight at which the report of the day before is made.
+	ReportAt time.Duration `env:"SLO_REPORT_AT" env-default:"1h"`
+
+	// FastBurnRate is the burn rate of the last hours at which AlertUserIDs are alerted, unless
+	// there were fewer than MinRequests requests; zero turns the alerts off.
+	FastBurnRate float64  `env:"SLO_FAST_BURN_RATE" env-default:"14.4"`
+	MinRequests  int64    `env:"SLO_MIN_REQUESTS"   env-default:"100"`
+	AlertUserIDs []string `env:"SLO_ALERT_USER_IDS" env-default:"" env-separator:","`
+}
@@ -0,0 +1,16 @@
+package configs
+
+type StorageConfig struct {
+	// Backend is "postgres" or "memory". The memory backend needs no database and keeps nothing
+	// across restarts; it is meant for demos and tests.
+	Backend string `env:"STORAGE_BACKEND" env-default:"postgres"`
+	// IDGenerator is "uuid", for time-ordered UUIDs, or "sequence", for the same IDs on every run
+	// so that fixtures and tests can refer to records by ID. Sequence IDs start over on restart,
+	// so they need the memory backend.
+	IDGenerator string `env:"STORAGE_ID_GENERATOR" env-default:"uuid"`
+	// Shards are the names of the Postgres clusters restaurants are spread over by consistent
+	// hashing of their IDs, each owning ShardVirtualNodes points of the ring. A shard other than
+	// the default one reads its connection from POSTGRES_SHARD_<NAME>_* (see ShardDatabase).
+	Shards            []string `env:"STORAGE_SHARDS"              env-default:"default" env-separator:","`
+	ShardVirtualNodes int      `env:"STORAGE_SHARD_VIRTUAL_NODES" env-default:"128"`
+}
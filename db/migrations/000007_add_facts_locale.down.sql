@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_facts_locale;
+
+ALTER TABLE facts DROP COLUMN IF EXISTS locale;
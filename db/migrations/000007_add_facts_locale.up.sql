@@ -0,0 +1,3 @@
+ALTER TABLE facts ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+CREATE INDEX idx_facts_locale ON facts(locale);
@@ -0,0 +1,3 @@
+ALTER TABLE availability
+    DROP CONSTRAINT IF EXISTS availability_capacity_non_negative,
+    DROP CONSTRAINT IF EXISTS availability_reserved_non_negative;
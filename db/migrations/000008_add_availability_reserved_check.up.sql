@@ -0,0 +1,5 @@
+UPDATE availability SET reserved = 0 WHERE reserved < 0;
+
+ALTER TABLE availability
+    ADD CONSTRAINT availability_reserved_non_negative CHECK (reserved >= 0),
+    ADD CONSTRAINT availability_capacity_non_negative CHECK (capacity >= 0);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    recipient_type VARCHAR(20) NOT NULL, -- user или restaurant
+    recipient_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL, -- in_app, email, sms или push
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipient_type, recipient_id, type, channel)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS restaurant_slug_redirects;
+
+DROP INDEX IF EXISTS idx_restaurants_slug;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS slug;
@@ -0,0 +1,28 @@
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS slug VARCHAR(64);
+
+-- Существующим ресторанам слаг строится из названия, повторы получают номер
+WITH generated AS (
+    SELECT id,
+           COALESCE(NULLIF(TRIM(BOTH '-' FROM LEFT(REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g'), 56)), ''), 'restaurant') AS base
+    FROM restaurants
+),
+numbered AS (
+    SELECT id, base, ROW_NUMBER() OVER (PARTITION BY base ORDER BY id) AS n
+    FROM generated
+)
+UPDATE restaurants r
+SET slug = CASE WHEN n.n = 1 THEN n.base ELSE n.base || '-' || n.n END
+FROM numbered n
+WHERE r.id = n.id;
+
+ALTER TABLE restaurants ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restaurants_slug ON restaurants(slug);
+
+-- Прежние слаги ведут на ресторан после смены слага
+CREATE TABLE IF NOT EXISTS restaurant_slug_redirects (
+    slug VARCHAR(64) PRIMARY KEY,
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_slug_redirects_restaurant_id ON restaurant_slug_redirects(restaurant_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS booking_links (
+    id VARCHAR(32) PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL, -- view или cancel
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_links_booking_id ON booking_links(booking_id);
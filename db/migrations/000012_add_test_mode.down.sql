@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_restaurants_live_name;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS is_test;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS is_test;
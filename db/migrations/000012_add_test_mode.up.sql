@@ -0,0 +1,5 @@
+-- Тестовые рестораны и бронирования партнёров, не попадающие в каталог и реальные уведомления
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS is_test BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_live_name ON restaurants(name) WHERE NOT is_test;
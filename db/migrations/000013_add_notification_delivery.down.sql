@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_related;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS read_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS channel;
@@ -0,0 +1,10 @@
+-- Канал доставки уведомления и время его доставки и прочтения получателем
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS channel VARCHAR(20) NOT NULL DEFAULT 'in_app';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS read_at TIMESTAMP WITH TIME ZONE;
+
+-- Уведомления в приложении доставлены в момент создания
+UPDATE notifications SET delivered_at = created_at WHERE channel = 'in_app' AND delivered_at IS NULL;
+UPDATE notifications SET read_at = created_at WHERE is_read AND read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_related ON notifications(related_id);
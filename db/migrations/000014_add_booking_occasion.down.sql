@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_occasion_date;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS occasion;
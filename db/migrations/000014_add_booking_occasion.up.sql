@@ -0,0 +1,6 @@
+-- Повод бронирования: день рождения, годовщина или деловая встреча
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS occasion VARCHAR(20)
+    CHECK (occasion IN ('birthday', 'anniversary', 'business'));
+
+-- Предстоящие поводы для ежедневной сводки ресторана
+CREATE INDEX IF NOT EXISTS idx_bookings_occasion_date ON bookings(date) WHERE occasion IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS booking_pre_order_items;
+DROP TABLE IF EXISTS menu_items;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS menu_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category VARCHAR(100) NOT NULL DEFAULT '',
+    price BIGINT NOT NULL CHECK (price >= 0), -- в минимальных единицах валюты
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_items_restaurant_id ON menu_items(restaurant_id, position);
+
+-- Предзаказ к бронированию; название и цена блюда сохраняются на момент заказа
+CREATE TABLE IF NOT EXISTS booking_pre_order_items (
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    menu_item_id UUID REFERENCES menu_items(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+    unit_price BIGINT NOT NULL CHECK (unit_price >= 0),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    notes TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (booking_id, position)
+);
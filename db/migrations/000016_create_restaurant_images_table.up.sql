@@ -0,0 +1,13 @@
+-- Изображения ресторанов хранятся в исходном виде; уменьшенные копии создаются по запросу
+CREATE TABLE IF NOT EXISTS restaurant_images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    content_type VARCHAR(50) NOT NULL, -- image/jpeg, image/png или image/gif
+    width INT NOT NULL CHECK (width > 0),
+    height INT NOT NULL CHECK (height > 0),
+    size_bytes INT NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_images_restaurant_id ON restaurant_images(restaurant_id, created_at);
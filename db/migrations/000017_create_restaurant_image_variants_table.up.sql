@@ -0,0 +1,17 @@
+-- Варианты изображений в других форматах (например, WebP) создаются фоновой задачей после загрузки
+CREATE TABLE IF NOT EXISTS restaurant_image_variants (
+    image_id UUID NOT NULL REFERENCES restaurant_images(id) ON DELETE CASCADE,
+    content_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, processing, ready, failed или discarded
+    width INT NOT NULL DEFAULT 0,
+    height INT NOT NULL DEFAULT 0,
+    size_bytes INT NOT NULL DEFAULT 0,
+    data BYTEA,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (image_id, content_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_image_variants_queue ON restaurant_image_variants(updated_at)
+    WHERE status IN ('pending', 'processing');
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS review_flags;
+DROP TABLE IF EXISTS review_replies;
+DROP TABLE IF EXISTS reviews;
@@ -0,0 +1,38 @@
+-- Отзывы гостей о ресторанах; отзыв можно оставить один раз на завершённое бронирование
+CREATE TABLE IF NOT EXISTS reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    booking_id UUID NOT NULL UNIQUE REFERENCES bookings(id) ON DELETE CASCADE,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    text TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'published', -- published или removed
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_restaurant_id ON reviews(restaurant_id, created_at DESC);
+
+-- Публичный ответ ресторана на отзыв, не больше одного
+CREATE TABLE IF NOT EXISTS review_replies (
+    review_id UUID PRIMARY KEY REFERENCES reviews(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Жалобы ресторанов на отзывы, которые разбирает администратор
+CREATE TABLE IF NOT EXISTS review_flags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, upheld или dismissed
+    resolution_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+-- По отзыву может быть открыта только одна жалоба
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_flags_open ON review_flags(review_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_review_flags_status ON review_flags(status, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE booking_pre_order_items DROP COLUMN IF EXISTS currency;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS currency;
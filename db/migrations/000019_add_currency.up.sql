@@ -0,0 +1,7 @@
+-- Валюта ресторана; все суммы хранятся в минимальных единицах этой валюты
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB'
+    CHECK (currency ~ '^[A-Z]{3}$');
+
+-- Валюта предзаказа сохраняется вместе с ценой на момент заказа
+ALTER TABLE booking_pre_order_items ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'RUB'
+    CHECK (currency ~ '^[A-Z]{3}$');
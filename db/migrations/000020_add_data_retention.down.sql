@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS retention_runs;
+
+DROP INDEX IF EXISTS idx_notifications_read_at;
+DROP INDEX IF EXISTS idx_bookings_completed_not_anonymized;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS anonymized_at;
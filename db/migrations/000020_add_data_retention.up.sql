@@ -0,0 +1,19 @@
+-- Время обезличивания бронирования по истечении срока хранения
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_completed_not_anonymized ON bookings(completed_at) WHERE status = 'completed' AND anonymized_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_read_at ON notifications(read_at) WHERE read_at IS NOT NULL;
+
+-- Журнал применения политик хранения, включая пробные запуски
+CREATE TABLE IF NOT EXISTS retention_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    policy VARCHAR(50) NOT NULL, -- purge_read_notifications или anonymize_completed_bookings
+    dry_run BOOLEAN NOT NULL,
+    cutoff TIMESTAMP WITH TIME ZONE NOT NULL,
+    affected INT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_runs_started_at ON retention_runs(started_at DESC);
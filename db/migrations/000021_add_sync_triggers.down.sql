@@ -0,0 +1,17 @@
+DO $$
+DECLARE
+    entity TEXT;
+BEGIN
+    FOREACH entity IN ARRAY ARRAY['restaurants', 'users', 'availability', 'bookings', 'menu_items', 'reviews', 'review_replies']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', entity || '_sync_updated_at', entity);
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', entity || '_sync_tombstone', entity);
+        EXECUTE format('DROP INDEX IF EXISTS %I', 'idx_' || entity || '_sync');
+    END LOOP;
+END
+$$;
+
+DROP FUNCTION IF EXISTS sync_record_tombstone();
+DROP FUNCTION IF EXISTS sync_set_updated_at();
+
+DROP TABLE IF EXISTS sync_tombstones;
@@ -0,0 +1,54 @@
+-- updated_at выставляется базой при каждом изменении строки и только растёт, даже если часы сервера
+-- отстают, поэтому клиенты синхронизации могут продолжать с последнего увиденного значения
+CREATE OR REPLACE FUNCTION sync_set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at := GREATEST(clock_timestamp(), OLD.updated_at + INTERVAL '1 microsecond');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Надгробия удалённых строк, по которым клиенты синхронизации узнают об удалениях
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    entity VARCHAR(50) NOT NULL, -- restaurants, users, availability, bookings, menu_items, reviews или review_replies
+    id TEXT NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_entity ON sync_tombstones(entity, deleted_at, id);
+
+-- Первый аргумент - имя сущности, второй - столбец с идентификатором строки
+CREATE OR REPLACE FUNCTION sync_record_tombstone() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity, id) VALUES (TG_ARGV[0], to_jsonb(OLD) ->> TG_ARGV[1]);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    source RECORD;
+BEGIN
+    FOR source IN
+        SELECT * FROM (VALUES
+            ('restaurants', 'id'),
+            ('users', 'id'),
+            ('availability', 'id'),
+            ('bookings', 'id'),
+            ('menu_items', 'id'),
+            ('reviews', 'id'),
+            ('review_replies', 'review_id')
+        ) AS t(entity, id_column)
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', source.entity || '_sync_updated_at', source.entity);
+        EXECUTE format('CREATE TRIGGER %I BEFORE UPDATE ON %I FOR EACH ROW EXECUTE FUNCTION sync_set_updated_at()',
+            source.entity || '_sync_updated_at', source.entity);
+
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', source.entity || '_sync_tombstone', source.entity);
+        EXECUTE format('CREATE TRIGGER %I AFTER DELETE ON %I FOR EACH ROW EXECUTE FUNCTION sync_record_tombstone(%L, %L)',
+            source.entity || '_sync_tombstone', source.entity, source.entity, source.id_column);
+
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I(updated_at, (%I::text))',
+            'idx_' || source.entity || '_sync', source.entity, source.id_column);
+    END LOOP;
+END
+$$;
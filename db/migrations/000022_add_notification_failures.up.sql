@@ -0,0 +1,19 @@
+-- Уведомления, которые не удалось доставить, с данными для повторной отправки
+CREATE TABLE IF NOT EXISTS notification_failures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipient_type VARCHAR(20) NOT NULL, -- user или restaurant
+    recipient_id VARCHAR(255) NOT NULL, -- текстом, чтобы сохранить и уведомления с неверным получателем
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    related_id VARCHAR(255) NOT NULL DEFAULT '',
+    cause TEXT NOT NULL, -- ошибка последней попытки
+    attempts INT NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, delivered или exhausted
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_failures_due ON notification_failures(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_notification_failures_status ON notification_failures(status, created_at DESC);
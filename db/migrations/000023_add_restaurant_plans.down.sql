@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookings_restaurant_created;
+DROP TABLE IF EXISTS restaurant_plans;
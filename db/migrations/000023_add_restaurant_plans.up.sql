@@ -0,0 +1,11 @@
+-- Тарифы ресторанов и лимиты, заданные администратором вместо лимитов тарифа
+CREATE TABLE IF NOT EXISTS restaurant_plans (
+    restaurant_id UUID PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    plan VARCHAR(20) NOT NULL DEFAULT 'free', -- free или pro
+    max_active_slots INT CHECK (max_active_slots >= 0), -- NULL - лимит тарифа, 0 - без лимита
+    max_monthly_bookings INT CHECK (max_monthly_bookings >= 0),
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_restaurant_created ON bookings(restaurant_id, created_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS invoices;
+DROP SEQUENCE IF EXISTS invoice_number_seq;
+DROP TABLE IF EXISTS subscriptions;
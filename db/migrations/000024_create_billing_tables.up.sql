@@ -0,0 +1,49 @@
+-- Подписки ресторанов на тарифы
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    plan VARCHAR(20) NOT NULL,
+    monthly_price BIGINT NOT NULL CHECK (monthly_price >= 0), -- в минимальных единицах валюты
+    currency CHAR(3) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Не больше одной активной подписки на ресторан
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_active ON subscriptions(restaurant_id) WHERE cancelled_at IS NULL;
+
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq;
+
+-- Ежемесячные счета по подпискам
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    number VARCHAR(32) NOT NULL UNIQUE,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    plan VARCHAR(20) NOT NULL,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL, -- не включительно
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, paid или failed
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, period_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_restaurant ON invoices(restaurant_id, issued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_invoices_status ON invoices(status, issued_at DESC);
+
+-- События платёжного провайдера, по одному на идентификатор
+CREATE TABLE IF NOT EXISTS payment_events (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    payment_id VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS incentive_evaluations;
+DROP TABLE IF EXISTS incentive_rules;
@@ -0,0 +1,28 @@
+-- Правила поощрений платформы, применяемые к бронированиям
+CREATE TABLE IF NOT EXISTS incentive_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    effect VARCHAR(50) NOT NULL, -- waive_deposit или bonus_points
+    points INTEGER NOT NULL DEFAULT 0 CHECK (points >= 0),
+    conditions JSONB NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    valid_from TIMESTAMP WITH TIME ZONE,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Журнал вычисления правил для аудита
+CREATE TABLE IF NOT EXISTS incentive_evaluations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    facts JSONB NOT NULL,
+    results JSONB NOT NULL,
+    waive_deposit BOOLEAN NOT NULL DEFAULT FALSE,
+    bonus_points INTEGER NOT NULL DEFAULT 0,
+    evaluated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incentive_evaluations_booking ON incentive_evaluations(booking_id);
+CREATE INDEX IF NOT EXISTS idx_incentive_evaluations_user ON incentive_evaluations(user_id, evaluated_at DESC);
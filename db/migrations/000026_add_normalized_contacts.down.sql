@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_normalized_email;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS normalized_contact_phone;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS normalized_contact_email;
+ALTER TABLE users DROP COLUMN IF EXISTS normalized_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS normalized_email;
@@ -0,0 +1,17 @@
+-- Нормализованные контакты: email в нижнем регистре, телефон в E.164
+ALTER TABLE users ADD COLUMN IF NOT EXISTS normalized_email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS normalized_phone VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS normalized_contact_email VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS normalized_contact_phone VARCHAR(20) NOT NULL DEFAULT '';
+
+-- Email существующих пользователей, совпадающие без учёта регистра, остаются ненормализованными
+-- до следующего изменения пользователя
+UPDATE users u SET normalized_email = LOWER(TRIM(u.email))
+WHERE NOT EXISTS (
+    SELECT 1 FROM users o WHERE o.id <> u.id AND LOWER(TRIM(o.email)) = LOWER(TRIM(u.email))
+);
+
+UPDATE restaurants SET normalized_contact_email = LOWER(TRIM(contact_email)) WHERE contact_email IS NOT NULL;
+
+-- Пользователи различаются по нормализованному email
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_normalized_email ON users(normalized_email) WHERE normalized_email <> '';
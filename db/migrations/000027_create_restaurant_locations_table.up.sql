@@ -0,0 +1,28 @@
+-- Геокодированные адреса ресторанов: компоненты адреса и координаты, заполняемые в фоне
+CREATE TABLE IF NOT EXISTS restaurant_locations (
+    restaurant_id UUID PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    address TEXT NOT NULL, -- адрес, который геокодируется
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, processing, done или failed
+    formatted_address TEXT NOT NULL DEFAULT '',
+    street VARCHAR(255) NOT NULL DEFAULT '',
+    house_number VARCHAR(50) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL DEFAULT '',
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(20) NOT NULL DEFAULT '',
+    country_code VARCHAR(2) NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    longitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    provider VARCHAR(50) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    geocoded_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_locations_pending ON restaurant_locations(updated_at)
+    WHERE status IN ('pending', 'processing');
+
+-- Адреса существующих ресторанов ставятся в очередь на геокодирование
+INSERT INTO restaurant_locations (restaurant_id, address)
+SELECT id, address FROM restaurants
+ON CONFLICT (restaurant_id) DO NOTHING;
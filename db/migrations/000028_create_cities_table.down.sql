@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_restaurant_locations_city_id;
+ALTER TABLE restaurant_locations DROP COLUMN IF EXISTS city_id;
+DROP TABLE IF EXISTS cities;
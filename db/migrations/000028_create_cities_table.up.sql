@@ -0,0 +1,26 @@
+-- Справочник городов, пополняемый при геокодировании адресов ресторанов
+CREATE TABLE IF NOT EXISTS cities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    country_code VARCHAR(2) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- Одноимённые города различаются регионом и страной
+    UNIQUE (country_code, region, name)
+);
+
+ALTER TABLE restaurant_locations
+    ADD COLUMN IF NOT EXISTS city_id UUID REFERENCES cities(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_locations_city_id ON restaurant_locations(city_id);
+
+-- Города уже геокодированных адресов
+INSERT INTO cities (name, region, country_code)
+SELECT DISTINCT city, region, country_code FROM restaurant_locations
+WHERE status = 'done' AND city <> ''
+ON CONFLICT (country_code, region, name) DO NOTHING;
+
+UPDATE restaurant_locations l
+SET city_id = c.id
+FROM cities c
+WHERE l.status = 'done' AND c.name = l.city AND c.region = l.region AND c.country_code = l.country_code;
@@ -0,0 +1,20 @@
+-- Подписки гостей на освободившиеся столики: гость получает уведомление, когда в окне времени
+-- на дату появляется достаточно мест
+CREATE TABLE IF NOT EXISTS availability_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    date DATE NOT NULL,
+    from_time VARCHAR(5) NOT NULL, -- начало окна, ЧЧ:ММ
+    to_time VARCHAR(5) NOT NULL, -- конец окна включительно, ЧЧ:ММ
+    party_size INT NOT NULL CHECK (party_size > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, notified или expired
+    time_slot VARCHAR(5) NOT NULL DEFAULT '', -- слот, о котором гость уведомлён
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMP WITH TIME ZONE
+);
+
+-- У гостя одна активная подписка на ресторан и дату
+CREATE UNIQUE INDEX IF NOT EXISTS idx_availability_alerts_active
+    ON availability_alerts(user_id, restaurant_id, date) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_availability_alerts_date ON availability_alerts(date) WHERE status = 'active';
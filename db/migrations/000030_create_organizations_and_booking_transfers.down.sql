@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS booking_transfers;
+DROP TABLE IF EXISTS organization_restaurants;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,34 @@
+-- Организации объединяют рестораны-партнёры одного владельца; ресторан входит не более чем в одну
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_restaurants (
+    restaurant_id UUID PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_restaurants_organization ON organization_restaurants(organization_id);
+
+-- Переносы бронирований в ресторан той же организации. Перенос ждёт согласия гостя; после него
+-- исходное бронирование отменяется, а new_booking_id ссылается на созданное в целевом ресторане
+CREATE TABLE IF NOT EXISTS booking_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    source_restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    target_restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    time VARCHAR(5) NOT NULL, -- ЧЧ:ММ
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted или declined
+    new_booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+-- У бронирования не больше одного ожидающего переноса
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_transfers_pending ON booking_transfers(booking_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_booking_transfers_new_booking ON booking_transfers(new_booking_id);
@@ -0,0 +1,2 @@
+-- Табло хостес опрашивает брони ресторана на сегодня каждые несколько секунд
+CREATE INDEX IF NOT EXISTS idx_bookings_restaurant_date ON bookings(restaurant_id, date);
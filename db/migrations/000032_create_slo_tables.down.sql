@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS slo_reports;
+DROP TABLE IF EXISTS request_metrics;
@@ -0,0 +1,20 @@
+-- Запросы к API по часам: все, завершённые ошибкой сервера и отвеченные медленнее порога задержки.
+-- Каждый экземпляр сервиса прибавляет свои счётчики к строке часа
+CREATE TABLE IF NOT EXISTS request_metrics (
+    hour TIMESTAMP WITH TIME ZONE PRIMARY KEY,
+    requests BIGINT NOT NULL DEFAULT 0,
+    errors BIGINT NOT NULL DEFAULT 0,
+    slow BIGINT NOT NULL DEFAULT 0
+);
+
+-- Ежедневные отчёты о соблюдении целевых уровней доступности и задержки
+CREATE TABLE IF NOT EXISTS slo_reports (
+    date DATE PRIMARY KEY,
+    availability_target DOUBLE PRECISION NOT NULL,
+    latency_target DOUBLE PRECISION NOT NULL,
+    latency_threshold_ms INT NOT NULL,
+    requests BIGINT NOT NULL,
+    errors BIGINT NOT NULL,
+    slow BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_availability_restaurant_changes;
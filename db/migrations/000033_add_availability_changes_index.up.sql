@@ -0,0 +1,2 @@
+-- Мобильные приложения запрашивают слоты ресторана, изменённые после известной им версии
+CREATE INDEX IF NOT EXISTS idx_availability_restaurant_changes ON availability(restaurant_id, updated_at, id);
@@ -0,0 +1,12 @@
+-- Итоги операций с бронями, присланных приложениями после работы офлайн, по ключу операции:
+-- повторная отправка операции получает тот же итог, а не вторую бронь
+CREATE TABLE IF NOT EXISTS booking_sync_results (
+    user_id UUID NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    operation VARCHAR(20) NOT NULL, -- create или cancel
+    booking_id TEXT NOT NULL DEFAULT '',
+    status VARCHAR(30) NOT NULL, -- applied, slot_filled, already_cancelled, not_cancellable, not_found или rejected
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
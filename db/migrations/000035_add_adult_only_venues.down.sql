@@ -0,0 +1,2 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS age_attested;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS is_adult_only;
@@ -0,0 +1,3 @@
+-- Заведения только для взрослых и подтверждение возраста гостя в бронированиях
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS is_adult_only BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS age_attested BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS restaurant_ownership_audit;
+DROP TABLE IF EXISTS restaurant_owners;
+DROP TABLE IF EXISTS restaurant_claims;
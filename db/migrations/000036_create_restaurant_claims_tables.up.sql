@@ -0,0 +1,38 @@
+-- Заявки владельцев на профили ресторанов, импортированные администраторами. Администратор
+-- проверяет доказательство и одобряет заявку или отклоняет её
+CREATE TABLE IF NOT EXISTS restaurant_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    proof TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved или rejected
+    reviewer_id TEXT NOT NULL DEFAULT '',
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+-- У пользователя не больше одной ожидающей заявки на ресторан
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restaurant_claims_pending ON restaurant_claims(restaurant_id, user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_restaurant_claims_status ON restaurant_claims(status, created_at);
+
+-- Владельцы ресторанов; ресторан без строки принадлежит платформе
+CREATE TABLE IF NOT EXISTS restaurant_owners (
+    restaurant_id UUID PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    owner_id UUID NOT NULL,
+    claim_id UUID NOT NULL,
+    since TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Журнал передачи ресторанов владельцам. Записи не меняются и не удаляются, даже вместе с
+-- рестораном, поэтому внешних ключей нет
+CREATE TABLE IF NOT EXISTS restaurant_ownership_audit (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL,
+    claim_id UUID NOT NULL,
+    owner_id UUID NOT NULL,
+    approved_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_ownership_audit_restaurant ON restaurant_ownership_audit(restaurant_id, created_at DESC);
@@ -0,0 +1,13 @@
+-- Собственные домены ресторанов, по которым открывается страница бронирования. Домен
+-- обслуживается после проверки TXT-записи _booking-verification.<домен>
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    domain VARCHAR(253) NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending или verified
+    verification_token VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    verified_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_custom_domains_restaurant ON custom_domains(restaurant_id, created_at);
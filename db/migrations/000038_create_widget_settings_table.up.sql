@@ -0,0 +1,11 @@
+-- Настройки виджета бронирования, который ресторан встраивает на свой сайт. Ресторан без строки
+-- использует настройки по умолчанию
+CREATE TABLE IF NOT EXISTS widget_settings (
+    restaurant_id UUID PRIMARY KEY REFERENCES restaurants(id) ON DELETE CASCADE,
+    primary_color VARCHAR(7) NOT NULL,
+    background_color VARCHAR(7) NOT NULL,
+    text_color VARCHAR(7) NOT NULL,
+    default_party_size INTEGER NOT NULL,
+    locale VARCHAR(35) NOT NULL, -- языковой тег BCP 47
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+ALTER TABLE menu_items DROP COLUMN IF EXISTS dietary_labels;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS allergens;
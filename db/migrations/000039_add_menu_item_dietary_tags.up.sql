@@ -0,0 +1,3 @@
+-- Аллергены и диетические метки блюда из фиксированных словарей
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS allergens TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS dietary_labels TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS menu_item_daily_reservations;
+
+ALTER TABLE menu_items DROP COLUMN IF EXISTS daily_limit;
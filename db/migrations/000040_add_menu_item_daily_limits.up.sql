@@ -0,0 +1,19 @@
+-- Дневной лимит порций блюда для предзаказов; 0 — без лимита
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS daily_limit INT NOT NULL DEFAULT 0 CHECK (daily_limit >= 0);
+
+-- Сколько порций блюда предзаказано на день активными бронированиями
+CREATE TABLE IF NOT EXISTS menu_item_daily_reservations (
+    menu_item_id UUID NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    reserved INT NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    PRIMARY KEY (menu_item_id, date)
+);
+
+-- Учитываем предзаказы, сделанные до появления лимитов
+INSERT INTO menu_item_daily_reservations (menu_item_id, date, reserved)
+SELECT p.menu_item_id, b.date, SUM(p.quantity)
+FROM booking_pre_order_items p
+JOIN bookings b ON b.id = p.booking_id
+WHERE p.menu_item_id IS NOT NULL AND b.status IN ('pending', 'confirmed')
+GROUP BY p.menu_item_id, b.date
+ON CONFLICT (menu_item_id, date) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Откуда пришло бронирование: UTM-метки ссылки и реферальный код
+CREATE TABLE IF NOT EXISTS booking_attributions (
+    booking_id UUID PRIMARY KEY REFERENCES bookings(id) ON DELETE CASCADE,
+    utm_source VARCHAR(100) NOT NULL DEFAULT '',
+    utm_medium VARCHAR(100) NOT NULL DEFAULT '',
+    utm_campaign VARCHAR(100) NOT NULL DEFAULT '',
+    utm_term VARCHAR(100) NOT NULL DEFAULT '',
+    utm_content VARCHAR(100) NOT NULL DEFAULT '',
+    referral_code VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Для отчёта по кампаниям за период
+CREATE INDEX IF NOT EXISTS idx_booking_attributions_created_at ON booking_attributions(created_at);
@@ -0,0 +1,27 @@
+-- Черновики бронирований: гость выбирает слот, оформляет предзаказ и подтверждает бронирование по шагам
+CREATE TABLE IF NOT EXISTS booking_drafts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    guests_count INT NOT NULL CHECK (guests_count > 0),
+    duration INT NOT NULL DEFAULT 0,
+    comment TEXT NOT NULL DEFAULT '',
+    occasion VARCHAR(20) NOT NULL DEFAULT '',
+    age_attested BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    -- Слот, места в котором удерживает черновик
+    date DATE,
+    time VARCHAR(5) NOT NULL DEFAULT '',
+    availability_id UUID REFERENCES availability(id) ON DELETE SET NULL,
+    pre_order JSONB NOT NULL DEFAULT '[]',
+    booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Для удаления просроченных черновиков
+CREATE INDEX IF NOT EXISTS idx_booking_drafts_expires_at ON booking_drafts(expires_at);
+
+-- Для сверки занятых мест с удерживаемыми черновиками
+CREATE INDEX IF NOT EXISTS idx_booking_drafts_availability ON booking_drafts(availability_id) WHERE status = 'held';
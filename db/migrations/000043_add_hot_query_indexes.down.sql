@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient ON notifications(recipient_type, recipient_id);
+DROP INDEX IF EXISTS idx_notifications_recipient_read;
+
+CREATE INDEX IF NOT EXISTS idx_availability_restaurant_date ON availability(restaurant_id, date);
+DROP INDEX IF EXISTS idx_availability_restaurant_date_slots;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_restaurant_date ON bookings(restaurant_id, date);
+DROP INDEX IF EXISTS idx_bookings_restaurant_date_status;
@@ -0,0 +1,14 @@
+-- Брони ресторана на дату с фильтром по статусу: табло хостес, сверка занятых мест, бронирование
+CREATE INDEX IF NOT EXISTS idx_bookings_restaurant_date_status ON bookings(restaurant_id, date, status);
+-- Новый индекс начинается с тех же колонок и заменяет прежний
+DROP INDEX IF EXISTS idx_bookings_restaurant_date;
+
+-- Слоты ресторана на дату: ёмкость и занятые места читаются из индекса без обращения к таблице;
+-- он заменяет индекс по ресторану и дате
+CREATE INDEX IF NOT EXISTS idx_availability_restaurant_date_slots ON availability(restaurant_id, date, time_slot)
+    INCLUDE (capacity, reserved);
+DROP INDEX IF EXISTS idx_availability_restaurant_date;
+
+-- Уведомления получателя, в том числе только непрочитанные
+CREATE INDEX IF NOT EXISTS idx_notifications_recipient_read ON notifications(recipient_type, recipient_id, is_read);
+DROP INDEX IF EXISTS idx_notifications_recipient;
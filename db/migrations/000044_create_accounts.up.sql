@@ -0,0 +1,35 @@
+-- Учётные записи для входа: гостя (user) или сотрудника ресторана (restaurant)
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL, -- user или restaurant
+    email VARCHAR(255) NOT NULL,
+    normalized_email VARCHAR(255) NOT NULL UNIQUE,
+    -- Хеш bcrypt, сам пароль не хранится
+    password_hash TEXT NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    restaurant_id UUID REFERENCES restaurants(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (
+        (kind = 'user' AND user_id IS NOT NULL AND restaurant_id IS NULL)
+        OR (kind = 'restaurant' AND restaurant_id IS NOT NULL AND user_id IS NULL)
+    )
+);
+
+-- У пользователя не больше одной учётной записи
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user ON accounts(user_id) WHERE user_id IS NOT NULL;
+
+-- Токены обновления хранятся в виде хеша; при обновлении токен отзывается и выдаётся новый
+-- из того же семейства
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Для отзыва всего семейства при повторном использовании токена
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_restaurants_cuisine;
+DROP INDEX IF EXISTS idx_restaurants_address_tsv;
+DROP INDEX IF EXISTS idx_restaurants_search_vector;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS search_vector;
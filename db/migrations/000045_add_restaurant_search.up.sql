@@ -0,0 +1,12 @@
+-- Полнотекстовый поиск по названию и описанию ресторана; конфигурация simple не зависит от языка
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_restaurants_search_vector ON restaurants USING GIN (search_vector);
+
+-- Фильтр по словам адреса
+CREATE INDEX IF NOT EXISTS idx_restaurants_address_tsv ON restaurants USING GIN (to_tsvector('simple', address));
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_cuisine ON restaurants(lower(cuisine));
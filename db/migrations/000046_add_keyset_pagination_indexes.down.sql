@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_user_date_time_id;
+DROP INDEX IF EXISTS idx_bookings_restaurant_date_time_id;
+DROP INDEX IF EXISTS idx_restaurants_name_id;
@@ -0,0 +1,4 @@
+-- Страницы списков читаются после курсора, а не через OFFSET: индексы отдают строки в порядке списков
+CREATE INDEX IF NOT EXISTS idx_restaurants_name_id ON restaurants(name, id);
+CREATE INDEX IF NOT EXISTS idx_bookings_restaurant_date_time_id ON bookings(restaurant_id, date DESC, time DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_bookings_user_date_time_id ON bookings(user_id, date DESC, time DESC, id DESC);
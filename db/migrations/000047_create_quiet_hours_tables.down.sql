@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS deferred_notifications;
+DROP TABLE IF EXISTS notification_quiet_hours;
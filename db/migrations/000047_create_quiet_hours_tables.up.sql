@@ -0,0 +1,27 @@
+-- Тихие часы получателя по каналу: ежедневное окно и/или откладывание до момента
+CREATE TABLE IF NOT EXISTS notification_quiet_hours (
+    recipient_type VARCHAR(20) NOT NULL, -- user или restaurant
+    recipient_id UUID NOT NULL,
+    channel VARCHAR(20) NOT NULL, -- in_app, email, sms или push
+    start_time VARCHAR(5) NOT NULL DEFAULT '', -- Формат: "HH:MM", пусто без окна
+    end_time VARCHAR(5) NOT NULL DEFAULT '',
+    snoozed_until TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (recipient_type, recipient_id, channel)
+);
+
+-- Несрочные уведомления, отложенные до конца тихих часов получателя
+CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    recipient_type VARCHAR(20) NOT NULL,
+    recipient_id VARCHAR(255) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    related_id VARCHAR(255) NOT NULL DEFAULT '',
+    deliver_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deferred_notifications_deliver_at ON deferred_notifications(deliver_at);
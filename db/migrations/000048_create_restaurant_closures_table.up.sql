@@ -0,0 +1,14 @@
+-- Периоды, когда ресторан закрыт (отпуск, ремонт): с starts_on до reopens_on, не включая его.
+-- На запросы бронирований на эти дни отвечает автоответчик, а запросы, поступившие в эти дни,
+-- не учитываются во времени ответа ресторана
+CREATE TABLE IF NOT EXISTS restaurant_closures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    starts_on DATE NOT NULL,
+    reopens_on DATE NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (reopens_on > starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_closures_restaurant ON restaurant_closures(restaurant_id, reopens_on);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_table_slot;
+ALTER TABLE bookings DROP COLUMN IF EXISTS table_id;
+DROP TABLE IF EXISTS restaurant_tables;
@@ -0,0 +1,21 @@
+-- Столы в плане зала ресторана: число мест, зона, курящий и уличный стол
+CREATE TABLE IF NOT EXISTS restaurant_tables (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    seats INT NOT NULL CHECK (seats > 0),
+    zone VARCHAR(100) NOT NULL DEFAULT '',
+    is_smoking BOOLEAN NOT NULL DEFAULT FALSE,
+    is_outdoor BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_restaurant_tables_restaurant ON restaurant_tables(restaurant_id, zone, name);
+
+-- Стол, за который посажены гости бронирования; прошлые бронирования удалённого стола остаются без него
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS table_id UUID REFERENCES restaurant_tables(id) ON DELETE SET NULL;
+
+-- Два активных бронирования одного времени не занимают один стол
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_table_slot ON bookings(table_id, date, time)
+    WHERE table_id IS NOT NULL AND status IN ('pending', 'confirmed');
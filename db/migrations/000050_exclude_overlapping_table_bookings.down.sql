@@ -0,0 +1,5 @@
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS bookings_table_seating_excl;
+DROP FUNCTION IF EXISTS booking_seating(DATE, VARCHAR, INT);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookings_table_slot ON bookings(table_id, date, time)
+    WHERE table_id IS NOT NULL AND status IN ('pending', 'confirmed');
@@ -0,0 +1,19 @@
+-- Стол занят бронированием на всё его время, а не только в минуту начала: пересекающиеся по времени
+-- активные бронирования одного стола исключаются ограничением, которое проверяется и при гонке
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Время от посадки гостей до их ухода; бронирование без длительности занимает стол на два часа.
+-- Строка "HH:MM" разбирается make_time, так как приведение строки к time не является неизменяемым
+CREATE OR REPLACE FUNCTION booking_seating(day DATE, clock VARCHAR, duration INT) RETURNS TSRANGE AS $$
+    SELECT TSRANGE(
+        day + make_time(split_part(clock, ':', 1)::INT, split_part(clock, ':', 2)::INT, 0),
+        day + make_time(split_part(clock, ':', 1)::INT, split_part(clock, ':', 2)::INT, 0)
+            + make_interval(mins => CASE WHEN duration > 0 THEN duration ELSE 120 END)
+    )
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+DROP INDEX IF EXISTS idx_bookings_table_slot;
+
+ALTER TABLE bookings ADD CONSTRAINT bookings_table_seating_excl
+    EXCLUDE USING gist (table_id WITH =, booking_seating(date, time, duration) WITH &&)
+    WHERE (table_id IS NOT NULL AND status IN ('pending', 'confirmed'));
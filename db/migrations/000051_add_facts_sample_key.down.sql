@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_facts_locale_sample_key;
+DROP INDEX IF EXISTS idx_facts_sample_key;
+
+ALTER TABLE facts DROP COLUMN IF EXISTS sample_key;
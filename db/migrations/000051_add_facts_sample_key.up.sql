@@ -0,0 +1,7 @@
+-- Случайный ключ выборки факта: случайные факты читаются отрезком индекса от случайной точки,
+-- а не сортировкой всех подходящих фактов по RANDOM()
+ALTER TABLE facts ADD COLUMN IF NOT EXISTS sample_key DOUBLE PRECISION NOT NULL DEFAULT random();
+
+CREATE INDEX IF NOT EXISTS idx_facts_sample_key ON facts(sample_key);
+-- Факты на одном языке: пул фактов дня и фильтр по языку
+CREATE INDEX IF NOT EXISTS idx_facts_locale_sample_key ON facts(locale, sample_key);
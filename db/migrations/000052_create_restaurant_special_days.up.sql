@@ -0,0 +1,13 @@
+-- Особые дни ресторана: часы работы на конкретную дату (праздник, поздний вечер), которые
+-- заменяют часы работы дня недели; закрытие ресторана (restaurant_closures) важнее особого дня
+CREATE TABLE IF NOT EXISTS restaurant_special_days (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    restaurant_id UUID NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    open_time VARCHAR(5) NOT NULL DEFAULT '', -- Формат: "HH:MM", пусто в закрытый день
+    close_time VARCHAR(5) NOT NULL DEFAULT '',
+    is_closed BOOLEAN NOT NULL DEFAULT FALSE,
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (restaurant_id, date)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS needs_accessible;
+ALTER TABLE bookings DROP COLUMN IF EXISTS seating_zone;
+ALTER TABLE bookings DROP COLUMN IF EXISTS table_requested;
+ALTER TABLE restaurant_tables DROP COLUMN IF EXISTS is_accessible;
@@ -0,0 +1,10 @@
+-- Стол, к которому можно подъехать на инвалидной коляске
+ALTER TABLE restaurant_tables ADD COLUMN IF NOT EXISTS is_accessible BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Пожелания гостя к столу: зона зала и доступный стол. Стол, выбранный гостем, не пересаживается
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS table_requested BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS seating_zone VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS needs_accessible BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Про уже сделанные бронирования неизвестно, выбирал ли гость стол сам, поэтому они не пересаживаются
+UPDATE bookings SET table_requested = TRUE WHERE table_id IS NOT NULL AND status IN ('pending', 'confirmed');
@@ -0,0 +1,19 @@
+CREATE OR REPLACE FUNCTION sync_set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at := GREATEST(clock_timestamp(), OLD.updated_at + INTERVAL '1 microsecond');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    entity TEXT;
+BEGIN
+    FOREACH entity IN ARRAY ARRAY['restaurants', 'users', 'availability', 'bookings', 'menu_items', 'reviews', 'review_replies']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS %I ON %I', entity || '_sync_updated_at', entity);
+        EXECUTE format('CREATE TRIGGER %I BEFORE UPDATE ON %I FOR EACH ROW EXECUTE FUNCTION sync_set_updated_at()',
+            entity || '_sync_updated_at', entity);
+    END LOOP;
+END
+$$;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_accounts_email_verification;
+ALTER TABLE accounts DROP COLUMN IF EXISTS email_verification_hash;
+ALTER TABLE accounts DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,7 @@
+-- Подтверждение email учётной записи: роль admin из AUTH_ADMIN_EMAILS выдаётся только после него
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+-- Хеш ещё не использованного токена из письма; сам токен не хранится
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS email_verification_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_email_verification
+    ON accounts(email_verification_hash) WHERE email_verification_hash IS NOT NULL;
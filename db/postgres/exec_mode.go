@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// ParseQueryExecMode maps the name of POSTGRES_QUERY_EXEC_MODE to the pgx mode. An empty name is
+// the pgx default, cache_statement.
+func ParseQueryExecMode(name string) (pgx.QueryExecMode, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return pgx.QueryExecModeCacheStatement, nil
+	}
+
+	mode, ok := queryExecModes[name]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", common.ErrInvalidQueryExecMode, errors.New("unknown mode "+name))
+	}
+	return mode, nil
+}
+
+// PreparesStatements tells whether the mode leaves named prepared statements on the server
+// connection, which a transaction pooler does not carry from one transaction to the next.
+func PreparesStatements(mode pgx.QueryExecMode) bool {
+	return mode == pgx.QueryExecModeCacheStatement
+}
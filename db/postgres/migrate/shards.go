@@ -0,0 +1,88 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"go.uber.org/zap"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+)
+
+// Shard is one Postgres cluster of a sharded deployment.
+type Shard struct {
+	Name   string
+	Config *configs.PostgresConfig
+}
+
+// ShardVersion is the schema version a shard was left at.
+type ShardVersion struct {
+	Shard   string `json:"shard"`
+	Version uint   `json:"version"`
+	Dirty   bool   `json:"dirty"`
+}
+
+// Version returns the version of the last migration applied to the database and whether it
+// failed halfway; a database without migrations is at version 0.
+func Version(cfg *configs.PostgresConfig, migrationsPath string) (uint, bool, error) {
+	if migrationsPath == "" {
+		migrationsPath = DefaultMigrationsPath
+	}
+
+	m, err := NewHandler().Migrate(migrationsPath, createDSN(cfg))
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { _, _ = m.Close() }()
+
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
+
+// MigrateShards brings the shards one after another to the latest migration, or to version when
+// it is not zero, and returns the versions they were left at. It stops at the first shard that
+// fails, so that at most one shard is between versions; the shards after it are untouched and
+// listed with the versions they had.
+func MigrateShards(ctx context.Context, shards []Shard, version uint, migrationsPath string) ([]ShardVersion, error) {
+	log, err := logger.FromContext(ctx)
+	if err != nil {
+		if log, err = logger.NewLogger(); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrLoggerCreation, err)
+		}
+		ctx = logger.NewContext(ctx, log)
+	}
+
+	versions := make([]ShardVersion, 0, len(shards))
+	var failed error
+	for _, shard := range shards {
+		if failed == nil {
+			log.Info(ctx, common.MsgShardMigrationStarted, zap.String("shard", shard.Name))
+			if version == 0 {
+				failed = Migrate(ctx, shard.Config, migrationsPath)
+			} else {
+				failed = MigrateTo(ctx, shard.Config, version, migrationsPath)
+			}
+			if failed != nil {
+				failed = fmt.Errorf("%s %s: %w", common.ErrMigrateShard, shard.Name, failed)
+			}
+		}
+
+		current, dirty, err := Version(shard.Config, migrationsPath)
+		if err != nil {
+			if failed == nil {
+				failed = fmt.Errorf("%s %s: %w", common.ErrMigrateShard, shard.Name, err)
+			}
+			continue
+		}
+		versions = append(versions, ShardVersion{Shard: shard.Name, Version: current, Dirty: dirty})
+	}
+
+	return versions, failed
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	PoolerNone      = "none"
+	PoolerPgBouncer = "pgbouncer"
+)
+
+// pgBouncerQueryExecMode replaces a mode preparing statements behind PgBouncer: it still asks
+// the server for the parameter types, but through an unnamed statement that lives only as long
+// as the query.
+const pgBouncerQueryExecMode = "describe_exec"
+
+// ApplyPoolerProfile returns the configuration adjusted for the pooler it names. Behind PgBouncer
+// no statement is prepared, no idle connection is kept open and at most PoolerMaxConnections are
+// opened, since PgBouncer already multiplexes them onto its own server connections.
+func ApplyPoolerProfile(cfg configs.PostgresConfig) (configs.PostgresConfig, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Pooler)) {
+	case "", PoolerNone:
+		return cfg, nil
+	case PoolerPgBouncer:
+	default:
+		return cfg, fmt.Errorf("%s: %w", common.ErrInvalidPooler, errors.New("unknown pooler "+cfg.Pooler))
+	}
+
+	mode, err := ParseQueryExecMode(cfg.QueryExecMode)
+	if err != nil {
+		return cfg, err
+	}
+	if PreparesStatements(mode) {
+		cfg.QueryExecMode = pgBouncerQueryExecMode
+	}
+
+	if cfg.PoolerMaxConnections > 0 && cfg.MaxConnections > cfg.PoolerMaxConnections {
+		cfg.MaxConnections = cfg.PoolerMaxConnections
+	}
+	cfg.MinConnections = 0
+
+	return cfg, nil
+}
+
+// PoolerDetection is what the startup check found out about the connection.
+type PoolerDetection struct {
+	Detected bool
+	// ClientPID is the backend process ID the server announced to pgx on connect, BackendPID the
+	// one of the session actually running the queries. A pooler announces its own.
+	ClientPID  uint32
+	BackendPID uint32
+	// ConfiguredPort is the port pgx connects to, ServerPort the one PostgreSQL listens on.
+	ConfiguredPort int
+	ServerPort     int
+}
+
+// DetectPooler tells whether the connections reach PostgreSQL through a pooler: either the
+// backend that runs the queries is not the one the connection was opened with, or the server
+// listens on another port than the one connected to.
+func DetectPooler(ctx context.Context, pool *pgxpool.Pool, configuredPort int) (*PoolerDetection, error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrDetectPooler, err)
+	}
+	defer conn.Release()
+
+	const query = `SELECT pg_backend_pid(), COALESCE(inet_server_port(), 0)`
+
+	detection := &PoolerDetection{
+		ClientPID:      conn.Conn().PgConn().PID(),
+		ConfiguredPort: configuredPort,
+	}
+	var backendPID int64
+	if err := conn.QueryRow(ctx, query).Scan(&backendPID, &detection.ServerPort); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrDetectPooler, err)
+	}
+	detection.BackendPID = uint32(backendPID)
+
+	// A Unix socket connection has no server port to compare.
+	portDiffers := detection.ServerPort != 0 && detection.ServerPort != configuredPort
+	detection.Detected = detection.ClientPID != detection.BackendPID || portDiffers
+
+	return detection, nil
+}
@@ -6,15 +6,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/configs"
 	migrate2 "github.com/flexer2006/case-back-restaurant-go/db/postgres/migrate"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
@@ -34,12 +37,21 @@ func New(ctx context.Context, cfg *configs.PostgresConfig) (Database, error) {
 		ctx = logger.NewContext(ctx, log)
 	}
 
+	profiled, err := ApplyPoolerProfile(*cfg)
+	if err != nil {
+		log.Error(ctx, common.ErrInvalidPooler, zap.String("pooler", cfg.Pooler), zap.Error(err))
+
+		return nil, err
+	}
+	cfg = &profiled
+
 	log.Info(ctx, common.MsgConnectingToPostgres,
 		zap.String("host", cfg.Host),
 		zap.Int("port", cfg.Port),
 		zap.String("database", cfg.Database),
 		zap.Int("maxConnections", cfg.MaxConnections),
-		zap.Int("minConnections", cfg.MinConnections))
+		zap.Int("minConnections", cfg.MinConnections),
+		zap.String("pooler", cfg.Pooler))
 
 	poolCfg, err := pgxpool.ParseConfig(fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -54,6 +66,27 @@ func New(ctx context.Context, cfg *configs.PostgresConfig) (Database, error) {
 	poolCfg.MaxConns = int32(cfg.MaxConnections)
 	poolCfg.MinConns = int32(cfg.MinConnections)
 
+	execMode, err := ParseQueryExecMode(cfg.QueryExecMode)
+	if err != nil {
+		log.Error(ctx, common.ErrInvalidQueryExecMode, zap.String("queryExecMode", cfg.QueryExecMode), zap.Error(err))
+
+		return nil, err
+	}
+
+	poolCfg.ConnConfig.DefaultQueryExecMode = execMode
+	if cfg.StatementCacheCapacity > 0 {
+		poolCfg.ConnConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	}
+	if cfg.DescriptionCacheCapacity > 0 {
+		poolCfg.ConnConfig.DescriptionCacheCapacity = cfg.DescriptionCacheCapacity
+	}
+
+	log.Info(ctx, common.MsgPostgresQueryExecMode,
+		zap.String("queryExecMode", execMode.String()),
+		zap.Bool("preparesStatements", PreparesStatements(execMode)),
+		zap.Int("statementCacheCapacity", poolCfg.ConnConfig.StatementCacheCapacity),
+		zap.Int("descriptionCacheCapacity", poolCfg.ConnConfig.DescriptionCacheCapacity))
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		log.Error(ctx, common.ErrCreateConnectionPool, zap.Error(err))
@@ -71,6 +104,8 @@ func New(ctx context.Context, cfg *configs.PostgresConfig) (Database, error) {
 
 	log.Info(ctx, common.MsgPostgresConnected)
 
+	warnAboutPooler(ctx, log, pool, cfg, execMode)
+
 	if err := migrate2.Migrate(ctx, cfg); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		log.Error(ctx, common.ErrApplyDBMigrations, zap.Error(err))
 		pgxAdapter.Close()
@@ -123,3 +158,32 @@ func (db *DB) Ping(ctx context.Context) error {
 
 	return nil
 }
+
+// warnAboutPooler logs the settings that break behind a connection pooler. A failed check only
+// costs the warnings, so it does not stop the startup.
+func warnAboutPooler(ctx context.Context, log ports.LoggerPort, pool *pgxpool.Pool, cfg *configs.PostgresConfig, execMode pgx.QueryExecMode) {
+	detection, err := DetectPooler(ctx, pool, cfg.Port)
+	if err != nil {
+		log.Warn(ctx, common.ErrDetectPooler, zap.Error(err))
+		return
+	}
+
+	profiled := strings.EqualFold(strings.TrimSpace(cfg.Pooler), PoolerPgBouncer)
+	if !detection.Detected {
+		if profiled {
+			log.Warn(ctx, common.MsgPoolerNotDetected, zap.String("pooler", cfg.Pooler))
+		}
+		return
+	}
+
+	log.Info(ctx, common.MsgPoolerDetected,
+		zap.Uint32("clientPID", detection.ClientPID),
+		zap.Uint32("backendPID", detection.BackendPID),
+		zap.Int("configuredPort", detection.ConfiguredPort),
+		zap.Int("serverPort", detection.ServerPort))
+
+	if PreparesStatements(execMode) {
+		log.Warn(ctx, common.MsgPoolerPreparedStatements, zap.String("queryExecMode", execMode.String()))
+	}
+	log.Warn(ctx, common.MsgPoolerMigrations)
+}
@@ -0,0 +1,41 @@
+// Package auth hashes passwords and issues the tokens accounts sign in with: short-lived access
+// tokens, JWTs signed with HMAC-SHA256, and opaque refresh tokens kept as a hash.
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength is the most bcrypt reads of a password; longer ones are rejected rather than
+// silently cut.
+const MaxPasswordLength = 72
+
+var ErrPasswordTooLong = errors.New("password is longer than 72 bytes")
+
+// dummyHash is compared against when an account does not exist, so that a failed login takes as
+// long for an unknown email as for a wrong password.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+func HashPassword(password string) (string, error) {
+	if len(password) > MaxPasswordLength {
+		return "", ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword tells whether the password matches the hash. An empty hash never matches, but
+// takes as long to check as a real one.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
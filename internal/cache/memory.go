@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+)
+
+// memorySweepInterval is how often storing a value also drops every expired one.
+const memorySweepInterval = time.Minute
+
+// Memory is a Store in process memory for a single instance and for tests. Expired values are
+// dropped when they are read and, at most once a minute, when a value is stored.
+type Memory struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func NewMemory(clock clock.Clock) *Memory {
+	return &Memory{
+		clock:   clock,
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.clock.Now().Before(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	if now.Sub(m.lastSweep) >= memorySweepInterval {
+		for k, entry := range m.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	m.entries[key] = memoryEntry{
+		value:     append([]byte(nil), value...),
+		expiresAt: now.Add(ttl),
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Package cache keeps serialized read results shared by the instances of a region, so that the
+// catalogue of its popular cities is served without a database round trip.
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ErrRedisReply is returned for replies of Redis other than the ones the commands expect.
+var ErrRedisReply = errors.New("unexpected redis reply")
+
+// RedisOptions tell where the Redis server is. PoolSize connections are kept open between
+// commands; each command gives up after Timeout unless its context ends sooner.
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
+	PoolSize int
+	Timeout  time.Duration
+}
+
+// Redis is a Store on a Redis server, spoken to over RESP with only the commands the store needs.
+type Redis struct {
+	options RedisOptions
+	idle    chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// redisReply is one RESP reply: a simple string, an integer or a bulk string, which is nil when
+// the key is missing. Error replies are returned as errors.
+type redisReply struct {
+	kind  byte
+	data  []byte
+	value int64
+	null  bool
+}
+
+func NewRedis(options RedisOptions) *Redis {
+	if options.PoolSize <= 0 {
+		options.PoolSize = 1
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 2 * time.Second
+	}
+
+	return &Redis{
+		options: options,
+		idle:    make(chan *redisConn, options.PoolSize),
+	}
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply.kind != '$' {
+		return nil, false, fmt.Errorf("%w: %q to GET", ErrRedisReply, reply.kind)
+	}
+	if reply.null {
+		return nil, false, nil
+	}
+	return reply.data, true, nil
+}
+
+// Set stores the value for ttl, rounded up to whole milliseconds.
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ms := (ttl + time.Millisecond - 1).Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+
+	reply, err := r.do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ms, 10))
+	if err != nil {
+		return err
+	}
+	if reply.kind != '+' {
+		return fmt.Errorf("%w: %q to SET", ErrRedisReply, reply.kind)
+	}
+	return nil
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	_, err := r.do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections; commands still running close theirs when they finish.
+func (r *Redis) Close() error {
+	for {
+		select {
+		case c := <-r.idle:
+			_ = c.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends one command and reads its reply. A connection that failed is dropped rather than
+// returned to the pool, as a reply may still be on its way.
+func (r *Redis) do(ctx context.Context, args ...string) (redisReply, error) {
+	c, err := r.conn(ctx)
+	if err != nil {
+		return redisReply{}, err
+	}
+
+	reply, err := c.roundTrip(ctx, r.options.Timeout, args)
+	if err != nil {
+		var replyErr redisError
+		if !errors.As(err, &replyErr) {
+			_ = c.conn.Close()
+			return redisReply{}, err
+		}
+	}
+
+	select {
+	case r.idle <- c:
+	default:
+		_ = c.conn.Close()
+	}
+	return reply, err
+}
+
+func (r *Redis) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case c := <-r.idle:
+		return c, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: r.options.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.options.Addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if r.options.Password != "" {
+		if _, err := c.roundTrip(ctx, r.options.Timeout, []string{"AUTH", r.options.Password}); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	if r.options.DB != 0 {
+		if _, err := c.roundTrip(ctx, r.options.Timeout, []string{"SELECT", strconv.Itoa(r.options.DB)}); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) roundTrip(ctx context.Context, timeout time.Duration, args []string) (redisReply, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return redisReply{}, err
+	}
+
+	command := make([]byte, 0, 64)
+	command = append(command, '*')
+	command = strconv.AppendInt(command, int64(len(args)), 10)
+	command = append(command, '\r', '\n')
+	for _, arg := range args {
+		command = append(command, '$')
+		command = strconv.AppendInt(command, int64(len(arg)), 10)
+		command = append(command, '\r', '\n')
+		command = append(command, arg...)
+		command = append(command, '\r', '\n')
+	}
+	if _, err := c.conn.Write(command); err != nil {
+		return redisReply{}, err
+	}
+
+	return c.readReply()
+}
+
+// redisError is an error reply of the server, after which the connection is still usable.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (c *redisConn) readReply() (redisReply, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return redisReply{}, err
+	}
+	if len(line) == 0 {
+		return redisReply{}, fmt.Errorf("%w: empty line", ErrRedisReply)
+	}
+
+	reply := redisReply{kind: line[0]}
+	switch reply.kind {
+	case '+':
+		reply.data = line[1:]
+	case '-':
+		return reply, redisError(line[1:])
+	case ':':
+		if reply.value, err = strconv.ParseInt(string(line[1:]), 10, 64); err != nil {
+			return reply, fmt.Errorf("%w: %w", ErrRedisReply, err)
+		}
+	case '$':
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil {
+			return reply, fmt.Errorf("%w: %w", ErrRedisReply, err)
+		}
+		if size < 0 {
+			reply.null = true
+			return reply, nil
+		}
+		reply.data = make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, reply.data); err != nil {
+			return reply, err
+		}
+		reply.data = reply.data[:size]
+	default:
+		return reply, fmt.Errorf("%w: %q", ErrRedisReply, reply.kind)
+	}
+	return reply, nil
+}
+
+func (c *redisConn) readLine() ([]byte, error) {
+	line, err := c.reader.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("%w: line without CRLF", ErrRedisReply)
+	}
+	return append([]byte(nil), line[:len(line)-2]...), nil
+}
@@ -0,0 +1,75 @@
+package contact
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidEmail = errors.New("invalid email address")
+
+// Limits of RFC 5321 on the forward path and the local part.
+const (
+	maxEmailLength     = 254
+	maxLocalPartLength = 64
+	maxLabelLength     = 63
+)
+
+// NormalizeEmail checks that raw is a plain address a message could be delivered to, with a
+// domain that has a top-level domain, and returns it in lower case. The domain is not looked up.
+func NormalizeEmail(raw string) (string, error) {
+	address := strings.TrimSpace(raw)
+	if address == "" {
+		return "", fmt.Errorf("%w: the address is empty", ErrInvalidEmail)
+	}
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Address != address {
+		return "", fmt.Errorf("%w: %q is not a plain address", ErrInvalidEmail, address)
+	}
+	if len(address) > maxEmailLength {
+		return "", fmt.Errorf("%w: addresses have at most %d characters", ErrInvalidEmail, maxEmailLength)
+	}
+
+	at := strings.LastIndexByte(address, '@')
+	local, domain := address[:at], address[at+1:]
+	if len(local) > maxLocalPartLength {
+		return "", fmt.Errorf("%w: the part before @ has at most %d characters", ErrInvalidEmail, maxLocalPartLength)
+	}
+
+	if err := checkDomain(domain); err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(address), nil
+}
+
+func checkDomain(domain string) error {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("%w: the domain %q has no top-level domain", ErrInvalidEmail, domain)
+	}
+
+	for _, label := range labels {
+		if label == "" || len(label) > maxLabelLength ||
+			strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("%w: the domain %q is malformed", ErrInvalidEmail, domain)
+		}
+		for _, r := range label {
+			if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return fmt.Errorf("%w: the domain %q is malformed", ErrInvalidEmail, domain)
+			}
+		}
+	}
+
+	tld := labels[len(labels)-1]
+	if strings.HasPrefix(strings.ToLower(tld), "xn--") {
+		return nil
+	}
+	if len([]rune(tld)) < 2 || strings.IndexFunc(tld, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+		return fmt.Errorf("%w: %q is not a top-level domain", ErrInvalidEmail, tld)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Package contact normalizes and validates the phone numbers and email addresses of users and
+// restaurants, so that the same contact written differently is stored and compared the same way.
+//
+// Phone numbers are brought to E.164 ("+79161234567") following the rules of libphonenumber for
+// the regions in the table below: international and trunk prefixes are dropped and the length of
+// the national number is checked. Numbers of other countries, which must then be written with
+// "+", are only checked against the length E.164 allows.
+package contact
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// region is how numbers are written in a region: the country calling code, the prefix dialled
+// before an international number and the trunk prefix dialled before a national one, and the
+// lengths of the national significant number.
+type region struct {
+	code        string
+	idd         string
+	trunkPrefix string
+	minLength   int
+	maxLength   int
+}
+
+var regions = map[string]region{
+	"RU": {code: "7", idd: "810", trunkPrefix: "8", minLength: 10, maxLength: 10},
+	"KZ": {code: "7", idd: "810", trunkPrefix: "8", minLength: 10, maxLength: 10},
+	"BY": {code: "375", idd: "810", trunkPrefix: "80", minLength: 9, maxLength: 9},
+	"UA": {code: "380", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	"UZ": {code: "998", idd: "00", minLength: 9, maxLength: 9},
+	"AM": {code: "374", idd: "00", trunkPrefix: "0", minLength: 8, maxLength: 8},
+	"AZ": {code: "994", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	"GE": {code: "995", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	"TR": {code: "90", idd: "00", trunkPrefix: "0", minLength: 10, maxLength: 10},
+	"US": {code: "1", idd: "011", trunkPrefix: "1", minLength: 10, maxLength: 10},
+	"CA": {code: "1", idd: "011", trunkPrefix: "1", minLength: 10, maxLength: 10},
+	"GB": {code: "44", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 10},
+	"DE": {code: "49", idd: "00", trunkPrefix: "0", minLength: 6, maxLength: 13},
+	"FR": {code: "33", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	// Italian numbers keep their leading 0 after the country code.
+	"IT": {code: "39", idd: "00", minLength: 6, maxLength: 11},
+	"ES": {code: "34", idd: "00", minLength: 9, maxLength: 9},
+	"PT": {code: "351", idd: "00", minLength: 9, maxLength: 9},
+	"NL": {code: "31", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	"BE": {code: "32", idd: "00", trunkPrefix: "0", minLength: 8, maxLength: 9},
+	"CH": {code: "41", idd: "00", trunkPrefix: "0", minLength: 9, maxLength: 9},
+	"AT": {code: "43", idd: "00", trunkPrefix: "0", minLength: 4, maxLength: 13},
+	"PL": {code: "48", idd: "00", minLength: 9, maxLength: 9},
+	"CZ": {code: "420", idd: "00", minLength: 9, maxLength: 9},
+	"FI": {code: "358", idd: "00", trunkPrefix: "0", minLength: 5, maxLength: 12},
+	"SE": {code: "46", idd: "00", trunkPrefix: "0", minLength: 7, maxLength: 10},
+	"NO": {code: "47", idd: "00", minLength: 8, maxLength: 8},
+	"DK": {code: "45", idd: "00", minLength: 8, maxLength: 8},
+	"IL": {code: "972", idd: "00", trunkPrefix: "0", minLength: 8, maxLength: 9},
+	"AE": {code: "971", idd: "00", trunkPrefix: "0", minLength: 8, maxLength: 9},
+	"IN": {code: "91", idd: "00", trunkPrefix: "0", minLength: 10, maxLength: 10},
+	"CN": {code: "86", idd: "00", trunkPrefix: "0", minLength: 7, maxLength: 11},
+	"JP": {code: "81", idd: "010", trunkPrefix: "0", minLength: 9, maxLength: 10},
+	"AU": {code: "61", idd: "0011", trunkPrefix: "0", minLength: 9, maxLength: 9},
+}
+
+// nationalLengths are the national number lengths of every country calling code in the table.
+var nationalLengths = func() map[string][2]int {
+	lengths := make(map[string][2]int)
+	for _, r := range regions {
+		known, ok := lengths[r.code]
+		if !ok {
+			known = [2]int{r.minLength, r.maxLength}
+		}
+		lengths[r.code] = [2]int{min(known[0], r.minLength), max(known[1], r.maxLength)}
+	}
+	return lengths
+}()
+
+const (
+	// minE164Digits is the shortest number of other countries accepted, country code included.
+	minE164Digits = 8
+	maxE164Digits = 15
+)
+
+// IsRegion reports whether numbers written without a country code can be read for the region,
+// an ISO 3166-1 alpha-2 code such as "RU".
+func IsRegion(code string) bool {
+	_, ok := regions[strings.ToUpper(code)]
+	return ok
+}
+
+// NormalizePhone brings a phone number to E.164. Numbers without "+" or an international prefix
+// are read as numbers of defaultRegion. Spaces, dashes, dots, slashes and parentheses are allowed
+// between the digits.
+func NormalizePhone(raw, defaultRegion string) (string, error) {
+	digits, international, err := phoneDigits(raw)
+	if err != nil {
+		return "", err
+	}
+
+	home, ok := regions[strings.ToUpper(defaultRegion)]
+	if !international {
+		switch {
+		case ok && home.idd != "" && strings.HasPrefix(digits, home.idd):
+			digits = strings.TrimPrefix(digits, home.idd)
+			international = true
+		case strings.HasPrefix(digits, "00"):
+			digits = strings.TrimPrefix(digits, "00")
+			international = true
+		case !ok:
+			return "", fmt.Errorf("%w: a country code is required", ErrInvalidPhone)
+		}
+	}
+
+	if international {
+		return internationalNumber(digits)
+	}
+	return nationalNumber(digits, home)
+}
+
+// phoneDigits strips the formatting of a number and reports whether it started with "+".
+func phoneDigits(raw string) (string, bool, error) {
+	value := strings.TrimSpace(raw)
+	if value == "" {
+		return "", false, fmt.Errorf("%w: the number is empty", ErrInvalidPhone)
+	}
+
+	international := strings.HasPrefix(value, "+")
+	value = strings.TrimPrefix(value, "+")
+
+	var digits strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune(" \u00a0-./()", r):
+		default:
+			return "", false, fmt.Errorf("%w: unexpected %q", ErrInvalidPhone, r)
+		}
+	}
+
+	if digits.Len() == 0 {
+		return "", false, fmt.Errorf("%w: the number has no digits", ErrInvalidPhone)
+	}
+	return digits.String(), international, nil
+}
+
+func internationalNumber(digits string) (string, error) {
+	if strings.HasPrefix(digits, "0") {
+		return "", fmt.Errorf("%w: country codes do not start with 0", ErrInvalidPhone)
+	}
+	if len(digits) > maxE164Digits {
+		return "", fmt.Errorf("%w: E.164 numbers have at most %d digits", ErrInvalidPhone, maxE164Digits)
+	}
+
+	// Country calling codes are prefix-free, so at most one of one to three digits is known.
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		lengths, ok := nationalLengths[digits[:size]]
+		if !ok {
+			continue
+		}
+
+		national := digits[size:]
+		if len(national) < lengths[0] || len(national) > lengths[1] {
+			return "", fmt.Errorf("%w: numbers of +%s have %s digits after the country code",
+				ErrInvalidPhone, digits[:size], lengthRange(lengths[0], lengths[1]))
+		}
+		return "+" + digits, nil
+	}
+
+	if len(digits) < minE164Digits {
+		return "", fmt.Errorf("%w: the number is too short", ErrInvalidPhone)
+	}
+	return "+" + digits, nil
+}
+
+func nationalNumber(digits string, home region) (string, error) {
+	national := digits
+	switch {
+	case home.trunkPrefix != "" && strings.HasPrefix(national, home.trunkPrefix) &&
+		len(national)-len(home.trunkPrefix) >= home.minLength:
+		national = strings.TrimPrefix(national, home.trunkPrefix)
+	case len(national) > home.maxLength && strings.HasPrefix(national, home.code) &&
+		len(national)-len(home.code) >= home.minLength:
+		// The country code written without "+", as in "79161234567".
+		national = strings.TrimPrefix(national, home.code)
+	}
+
+	if len(national) < home.minLength || len(national) > home.maxLength {
+		return "", fmt.Errorf("%w: national numbers of +%s have %s digits",
+			ErrInvalidPhone, home.code, lengthRange(home.minLength, home.maxLength))
+	}
+	return "+" + home.code + national, nil
+}
+
+func lengthRange(minLength, maxLength int) string {
+	if minLength == maxLength {
+		return fmt.Sprint(minLength)
+	}
+	return fmt.Sprintf("%d to %d", minLength, maxLength)
+}
@@ -0,0 +1,136 @@
+// Package diagnostics serves the profiles and runtime metrics of the process on a port of its own,
+// kept apart from the public API, so that a hung or slow server can be inspected without a
+// redeploy.
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// pool is the connection pool whose statistics are published; nil until SetPool.
+var pool atomic.Pointer[pgxpool.Pool]
+
+// The runtime metrics are published next to "memstats" and "cmdline", which expvar publishes
+// itself: the goroutines running, the garbage collections and the connections of the pool.
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("gc", expvar.Func(gcStats))
+	expvar.Publish("postgres_pool", expvar.Func(poolStats))
+}
+
+func gcStats() any {
+	var stats debug.GCStats
+	debug.ReadGCStats(&stats)
+
+	var lastPause time.Duration
+	if len(stats.Pause) > 0 {
+		lastPause = stats.Pause[0]
+	}
+
+	return map[string]any{
+		"num_gc":         stats.NumGC,
+		"last_gc":        stats.LastGC,
+		"pause_total_ns": stats.PauseTotal.Nanoseconds(),
+		"last_pause_ns":  lastPause.Nanoseconds(),
+	}
+}
+
+func poolStats() any {
+	p := pool.Load()
+	if p == nil {
+		return nil
+	}
+
+	stat := p.Stat()
+	return map[string]any{
+		"max_conns":                  stat.MaxConns(),
+		"total_conns":                stat.TotalConns(),
+		"acquired_conns":             stat.AcquiredConns(),
+		"idle_conns":                 stat.IdleConns(),
+		"constructing_conns":         stat.ConstructingConns(),
+		"acquire_count":              stat.AcquireCount(),
+		"acquire_duration_ns":        stat.AcquireDuration().Nanoseconds(),
+		"empty_acquire_count":        stat.EmptyAcquireCount(),
+		"canceled_acquire_count":     stat.CanceledAcquireCount(),
+		"new_conns_count":            stat.NewConnsCount(),
+		"max_lifetime_destroy_count": stat.MaxLifetimeDestroyCount(),
+		"max_idle_destroy_count":     stat.MaxIdleDestroyCount(),
+	}
+}
+
+// SetPool publishes the statistics of the connection pool under "postgres_pool".
+func SetPool(p *pgxpool.Pool) {
+	pool.Store(p)
+}
+
+// NewHandler returns the handler of the diagnostics port: the profiles of net/http/pprof under
+// /debug/pprof/ and the expvar metrics at /debug/vars.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// Start serves NewHandler on addr until the returned function is called. There is no write
+// timeout, as CPU profiles and traces are written for as long as they were asked to run.
+func Start(ctx context.Context, addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrStartDiagnostics, err)
+	}
+
+	log, err := logger.FromContext(ctx)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("%s: %w", common.ErrStartDiagnostics, err)
+	}
+
+	server := &http.Server{
+		Handler:           NewHandler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	log.Info(ctx, common.MsgDiagnosticsStarting, zap.String("address", listener.Addr().String()))
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(ctx, common.ErrServeDiagnostics, zap.Error(err))
+		}
+	}()
+
+	return func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(ctx, common.ErrServeDiagnostics, zap.Error(err))
+		}
+	}, nil
+}
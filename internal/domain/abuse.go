@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type AbuseEventKind string
+
+const (
+	// AbuseEventBookingBurst is a client making more bookings than a person plausibly would.
+	AbuseEventBookingBurst AbuseEventKind = "booking_burst"
+
+	// AbuseEventCancelRebook is a client repeatedly cancelling and booking the same restaurant
+	// again, which holds its seats away from other guests.
+	AbuseEventCancelRebook AbuseEventKind = "cancel_rebook"
+)
+
+// AbuseEvent is a suspicious booking pattern of one client, kept for admins to look into.
+type AbuseEvent struct {
+	ID       string         `json:"id"`
+	Kind     AbuseEventKind `json:"kind"`
+	ClientIP string         `json:"client_ip,omitempty"`
+	UserID   string         `json:"user_id,omitempty"`
+	// RestaurantID is set for patterns against one restaurant.
+	RestaurantID string `json:"restaurant_id,omitempty"`
+	// Count is how many bookings or cancel-rebook cycles were seen within Window.
+	Count  int           `json:"count"`
+	Window time.Duration `json:"window"`
+	// TightenedUntil is set when the rate limit of the client was lowered because of the event.
+	TightenedUntil *time.Time `json:"tightened_until,omitempty"`
+	DetectedAt     time.Time  `json:"detected_at"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type AccountKind string
+
+const (
+	// AccountUser signs a guest in; the account belongs to a user.
+	AccountUser AccountKind = "user"
+
+	// AccountRestaurant signs the staff of a restaurant in; the account belongs to the restaurant.
+	AccountRestaurant AccountKind = "restaurant"
+)
+
+// Account is a login with an email and a password. A user has at most one account, a restaurant
+// any number, one per member of the staff. Only the bcrypt hash of the password is kept.
+// EmailVerifiedAt is set once the token mailed to the email came back; until then
+// EmailVerificationHash holds the SHA-256 of that token.
+type Account struct {
+	ID                    string      `json:"id"`
+	Kind                  AccountKind `json:"kind"`
+	Email                 string      `json:"email"`
+	NormalizedEmail       string      `json:"-"`
+	PasswordHash          string      `json:"-"`
+	UserID                string      `json:"user_id,omitempty"`
+	RestaurantID          string      `json:"restaurant_id,omitempty"`
+	EmailVerifiedAt       *time.Time  `json:"email_verified_at,omitempty"`
+	EmailVerificationHash string      `json:"-"`
+	CreatedAt             time.Time   `json:"created_at"`
+	UpdatedAt             time.Time   `json:"updated_at"`
+}
+
+// RefreshToken lets an account get a new access token without the password. Only the SHA-256
+// hash of the token is kept. Every refresh revokes the token and issues a new one of the same
+// family, so a revoked token coming back means it was stolen and the whole family is revoked.
+type RefreshToken struct {
+	ID        string     `json:"id"`
+	AccountID string     `json:"account_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxAttributionLength = 100
+
+// BookingAttribution says where a booking came from: the UTM parameters of the campaign link the
+// guest followed and the referral code they brought.
+type BookingAttribution struct {
+	Source       string `json:"utm_source,omitempty"`
+	Medium       string `json:"utm_medium,omitempty"`
+	Campaign     string `json:"utm_campaign,omitempty"`
+	Term         string `json:"utm_term,omitempty"`
+	Content      string `json:"utm_content,omitempty"`
+	ReferralCode string `json:"referral_code,omitempty"`
+}
+
+// Normalize trims the values and lowercases the source, medium and campaign, which links tag
+// inconsistently, so that "Instagram" and "instagram" count as one campaign. Referral codes are
+// uppercased. A value longer than 100 characters or with control characters is invalid.
+func (a *BookingAttribution) Normalize() error {
+	fields := []struct {
+		name  string
+		value *string
+		fold  func(string) string
+	}{
+		{"utm_source", &a.Source, strings.ToLower},
+		{"utm_medium", &a.Medium, strings.ToLower},
+		{"utm_campaign", &a.Campaign, strings.ToLower},
+		{"utm_term", &a.Term, nil},
+		{"utm_content", &a.Content, nil},
+		{"referral_code", &a.ReferralCode, strings.ToUpper},
+	}
+
+	for _, field := range fields {
+		value := strings.TrimSpace(*field.value)
+		if field.fold != nil {
+			value = field.fold(value)
+		}
+		if utf8.RuneCountInString(value) > maxAttributionLength {
+			return &ValidationError{Entity: "booking", Field: field.name, Reason: "is longer than 100 characters"}
+		}
+		if strings.ContainsFunc(value, unicode.IsControl) {
+			return &ValidationError{Entity: "booking", Field: field.name, Reason: "has control characters"}
+		}
+		*field.value = value
+	}
+	return nil
+}
+
+// IsZero reports whether the booking came with no attribution at all.
+func (a BookingAttribution) IsZero() bool {
+	return a == BookingAttribution{}
+}
+
+// CampaignPerformance sums up the bookings requested over a period through one campaign: one
+// combination of UTM source, medium and campaign and referral code.
+type CampaignPerformance struct {
+	Source       string
+	Medium       string
+	Campaign     string
+	ReferralCode string
+	// Bookings were requested through the campaign; Completed of them were honoured, with
+	// Guests guests, and Cancelled were cancelled or rejected.
+	Bookings  int
+	Completed int
+	Cancelled int
+	Guests    int
+}
+
+// ConversionRate is the share of the bookings that were completed, from 0 to 1.
+func (p CampaignPerformance) ConversionRate() float64 {
+	if p.Bookings == 0 {
+		return 0
+	}
+	return float64(p.Completed) / float64(p.Bookings)
+}
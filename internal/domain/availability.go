@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -30,3 +32,68 @@ func (a *Availability) AvailabilityStatus() string {
 func (a *Availability) AvailableSeats() int {
 	return a.Capacity - a.Reserved
 }
+
+// ReserveHeldTables counts against the slot of a restaurant with tables the seats its free tables
+// cannot offer at the time of the slot, up to its capacity. A table is free when a booking of the
+// default duration could be seated at it at the time of the slot.
+func (a *Availability) ReserveHeldTables(tables []*Table, holds []TableHold) {
+	free := 0
+	for _, table := range FreeTables(tables, holds, &Booking{Date: a.Date, Time: a.TimeSlot}) {
+		free += table.Seats
+	}
+	a.Reserved = min(a.Capacity, a.Reserved+a.Capacity-min(a.Capacity, free))
+}
+
+// Validate checks that the slot belongs to a restaurant, is on a date at a time of day, and that
+// its reserved seats are between zero and its capacity.
+func (a *Availability) Validate() error {
+	switch {
+	case a.RestaurantID == "":
+		return &ValidationError{Entity: "availability", Field: "restaurant_id", Reason: "is required"}
+	case a.Date.IsZero():
+		return &ValidationError{Entity: "availability", Field: "date", Reason: "is required"}
+	case !validClock(a.TimeSlot):
+		return &ValidationError{Entity: "availability", Field: "time_slot", Reason: "is not a time of day"}
+	case a.Capacity < 0:
+		return &ValidationError{Entity: "availability", Field: "capacity", Reason: "cannot be negative"}
+	case a.Reserved < 0:
+		return &ValidationError{Entity: "availability", Field: "reserved", Reason: "cannot be negative"}
+	case a.Reserved > a.Capacity:
+		return &ValidationError{Entity: "availability", Field: "reserved", Reason: "cannot exceed capacity"}
+	}
+	return nil
+}
+
+var (
+	ErrReservedSeatsNegative = errors.New("reserved seats cannot be negative")
+	ErrReservedSeatsOverflow = errors.New("reserved seats cannot exceed capacity")
+)
+
+// ReservedSeatsError is returned when a change of reserved seats would move them below zero
+// or above the capacity of the slot. Err is ErrReservedSeatsNegative or ErrReservedSeatsOverflow.
+type ReservedSeatsError struct {
+	AvailabilityID string
+	Capacity       int
+	Reserved       int
+	Delta          int
+	Err            error
+}
+
+func (e *ReservedSeatsError) Error() string {
+	return fmt.Sprintf("%s: availability %s has %d of %d seats reserved, delta %d",
+		e.Err, e.AvailabilityID, e.Reserved, e.Capacity, e.Delta)
+}
+
+func (e *ReservedSeatsError) Unwrap() error {
+	return e.Err
+}
+
+// ReservedSeatsDrift is a slot whose recorded reserved seats differed from its active bookings.
+type ReservedSeatsDrift struct {
+	AvailabilityID string    `json:"availability_id"`
+	RestaurantID   string    `json:"restaurant_id"`
+	Date           time.Time `json:"date"`
+	TimeSlot       string    `json:"time_slot"`
+	Recorded       int       `json:"recorded"`
+	Actual         int       `json:"actual"`
+}
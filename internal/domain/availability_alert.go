@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type AvailabilityAlertStatus string
+
+const (
+	AvailabilityAlertActive AvailabilityAlertStatus = "active"
+
+	// AvailabilityAlertNotified is an alert whose guest was told about a freed table.
+	AvailabilityAlertNotified AvailabilityAlertStatus = "notified"
+
+	// AvailabilityAlertExpired is an alert whose date passed without a table freeing up.
+	AvailabilityAlertExpired AvailabilityAlertStatus = "expired"
+)
+
+// AvailabilityAlert asks to tell a guest once a restaurant has room for PartySize guests in a
+// slot from FromTime to ToTime, both included, on Date. A guest has at most one active alert per
+// restaurant and date.
+type AvailabilityAlert struct {
+	ID           string                  `json:"id"`
+	RestaurantID string                  `json:"restaurant_id"`
+	UserID       string                  `json:"user_id"`
+	Date         time.Time               `json:"date"`
+	FromTime     string                  `json:"from_time"`
+	ToTime       string                  `json:"to_time"`
+	PartySize    int                     `json:"party_size"`
+	Status       AvailabilityAlertStatus `json:"status"`
+	// TimeSlot is the slot the guest was told about once the alert is notified.
+	TimeSlot   string     `json:"time_slot,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
+
+// Matches reports whether the slot is in the window of the alert and has room for its party.
+func (a *AvailabilityAlert) Matches(slot *Availability) bool {
+	return slot.TimeSlot >= a.FromTime && slot.TimeSlot <= a.ToTime && slot.AvailableSeats() >= a.PartySize
+}
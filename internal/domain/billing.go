@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// Subscription is a restaurant paying for a plan. A restaurant has at most one active
+// subscription; cancelled ones are kept for the invoices they were billed on.
+type Subscription struct {
+	ID           string
+	RestaurantID string
+	Plan         Plan
+	// MonthlyPrice is in minor units of Currency.
+	MonthlyPrice int64
+	Currency     string
+	StartedAt    time.Time
+	CancelledAt  *time.Time
+	CreatedAt    time.Time
+}
+
+func (s *Subscription) Active() bool {
+	return s.CancelledAt == nil
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusOpen   InvoiceStatus = "open"
+	InvoiceStatusPaid   InvoiceStatus = "paid"
+	InvoiceStatusFailed InvoiceStatus = "failed"
+)
+
+var InvoiceStatuses = []InvoiceStatus{InvoiceStatusOpen, InvoiceStatusPaid, InvoiceStatusFailed}
+
+// Invoice bills a subscription for a calendar month, from PeriodStart up to PeriodEnd, exclusive.
+type Invoice struct {
+	ID             string
+	Number         string
+	SubscriptionID string
+	RestaurantID   string
+	RestaurantName string
+	Plan           Plan
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	// Amount is in minor units of Currency.
+	Amount   int64
+	Currency string
+	Status   InvoiceStatus
+	IssuedAt time.Time
+	DueAt    time.Time
+	PaidAt   *time.Time
+	// PaymentID is the reference of the last payment the provider reported for the invoice.
+	PaymentID string
+	UpdatedAt time.Time
+}
+
+type InvoiceFilter struct {
+	RestaurantID string
+	Status       InvoiceStatus
+}
+
+type PaymentEventType string
+
+const (
+	PaymentEventSucceeded PaymentEventType = "payment.succeeded"
+	PaymentEventFailed    PaymentEventType = "payment.failed"
+)
+
+// PaymentEvent is a notification of the payment provider about the payment of an invoice. Events
+// are kept by ID so that a redelivered one is applied once.
+type PaymentEvent struct {
+	ID         string           `json:"id"`
+	Type       PaymentEventType `json:"type"`
+	InvoiceID  string           `json:"invoice_id"`
+	PaymentID  string           `json:"payment_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	ReceivedAt time.Time        `json:"-"`
+}
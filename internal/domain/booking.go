@@ -18,6 +18,24 @@ const (
 	BookingStatusCompleted BookingStatus = "completed"
 )
 
+// BookingOccasion is what a booking celebrates, so the restaurant can prepare for it; it is empty
+// for a booking without one.
+type BookingOccasion string
+
+const (
+	BookingOccasionBirthday BookingOccasion = "birthday"
+
+	BookingOccasionAnniversary BookingOccasion = "anniversary"
+
+	BookingOccasionBusiness BookingOccasion = "business"
+)
+
+var BookingOccasions = []BookingOccasion{
+	BookingOccasionBirthday,
+	BookingOccasionAnniversary,
+	BookingOccasionBusiness,
+}
+
 type BookingAlternative struct {
 	ID         string     `json:"id"`
 	BookingID  string     `json:"booking_id"`
@@ -39,10 +57,113 @@ type Booking struct {
 	GuestsCount  int                  `json:"guests_count"`
 	Status       BookingStatus        `json:"status"`
 	Comment      string               `json:"comment"`
+	Occasion     BookingOccasion      `json:"occasion,omitempty"`
 	CreatedAt    time.Time            `json:"created_at"`
 	UpdatedAt    time.Time            `json:"updated_at"`
 	ConfirmedAt  *time.Time           `json:"confirmed_at,omitempty"`
 	RejectedAt   *time.Time           `json:"rejected_at,omitempty"`
 	CompletedAt  *time.Time           `json:"completed_at,omitempty"`
 	Alternatives []BookingAlternative `json:"alternatives,omitempty"`
+	// IsTest is set for bookings made in test mode and for every booking of a test restaurant.
+	IsTest bool `json:"is_test"`
+	// AgeAttested is set when the guest attested that everyone in their party is of age, which a
+	// booking of an adult-only venue requires.
+	AgeAttested bool `json:"age_attested"`
+	// Attribution is the campaign the booking came through; it is nil for bookings without one and
+	// only read with the booking on its own.
+	Attribution *BookingAttribution `json:"attribution,omitempty"`
+	// TableID is the table the party is seated at in a restaurant with tables, empty in one without.
+	// A guest may ask for a table when booking.
+	TableID string `json:"table_id,omitempty"`
+	// TableRequested is set when the guest asked for the table; such a booking keeps it when the
+	// tables are seated again.
+	TableRequested bool `json:"table_requested,omitempty"`
+	// SeatingZone is the zone of the floor the guest would like to sit in, and NeedsAccessible is
+	// set for a party that needs a table a wheelchair can reach. The zone is a wish, accessibility a
+	// requirement.
+	SeatingZone     string `json:"seating_zone,omitempty"`
+	NeedsAccessible bool   `json:"needs_accessible,omitempty"`
+}
+
+// DefaultBookingDuration is how long a booking without a duration of its own holds its table.
+const DefaultBookingDuration = 120 * time.Minute
+
+// Seating returns when the party of the booking is expected to sit down and to leave. Duration is
+// in minutes; a booking without one holds its table for DefaultBookingDuration.
+func (b *Booking) Seating() (time.Time, time.Time, bool) {
+	clock, err := time.Parse(clockLayout, b.Time)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	start := startOfDay(b.Date).Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+	duration := DefaultBookingDuration
+	if b.Duration > 0 {
+		duration = time.Duration(b.Duration) * time.Minute
+	}
+	return start, start.Add(duration), true
+}
+
+// Validate checks that the booking is for a restaurant and a user, on a date at a time of day,
+// for at least one guest.
+func (b *Booking) Validate() error {
+	switch {
+	case b.RestaurantID == "":
+		return &ValidationError{Entity: "booking", Field: "restaurant_id", Reason: "is required"}
+	case b.UserID == "":
+		return &ValidationError{Entity: "booking", Field: "user_id", Reason: "is required"}
+	case b.Date.IsZero():
+		return &ValidationError{Entity: "booking", Field: "date", Reason: "is required"}
+	case !validClock(b.Time):
+		return &ValidationError{Entity: "booking", Field: "time", Reason: "is not a time of day"}
+	case b.GuestsCount < 1:
+		return &ValidationError{Entity: "booking", Field: "guests_count", Reason: "must be positive"}
+	case b.Duration < 0:
+		return &ValidationError{Entity: "booking", Field: "duration", Reason: "cannot be negative"}
+	case len([]rune(b.SeatingZone)) > MaxTableZoneLength:
+		return &ValidationError{Entity: "booking", Field: "seating_zone", Reason: "is too long"}
+	}
+	return nil
+}
+
+// BookingCursor is the position after a booking in a list ordered by date, time and ID, the
+// latest first. The zero cursor is the start.
+type BookingCursor struct {
+	Date time.Time
+	Time string
+	ID   string
+}
+
+// CursorAfter returns the cursor right after the booking.
+func (b *Booking) CursorAfter() BookingCursor {
+	return BookingCursor{Date: b.Date, Time: b.Time, ID: b.ID}
+}
+
+// IsZero reports whether the cursor is at the start.
+func (c BookingCursor) IsZero() bool {
+	return c.ID == ""
+}
+
+// BookingFilter narrows a list of bookings; empty fields match every booking.
+type BookingFilter struct {
+	Status   BookingStatus
+	Date     *time.Time
+	Occasion BookingOccasion
+}
+
+// Matches reports whether the booking passes the filter. Dates are compared by calendar day.
+func (f BookingFilter) Matches(booking *Booking) bool {
+	if f.Status != "" && booking.Status != f.Status {
+		return false
+	}
+	if f.Occasion != "" && booking.Occasion != f.Occasion {
+		return false
+	}
+	if f.Date != nil {
+		y1, m1, d1 := f.Date.Date()
+		y2, m2, d2 := booking.Date.Date()
+		if y1 != y2 || m1 != m2 || d1 != d2 {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type BookingDraftStatus string
+
+const (
+	// BookingDraftOpen is a draft with the party filled in and no slot picked yet.
+	BookingDraftOpen BookingDraftStatus = "open"
+
+	// BookingDraftHeld is a draft holding the seats of its slot until it expires.
+	BookingDraftHeld BookingDraftStatus = "held"
+
+	// BookingDraftConfirmed is a draft turned into the booking BookingID.
+	BookingDraftConfirmed BookingDraftStatus = "confirmed"
+)
+
+// BookingDraft is a booking made step by step: the party first, then the slot, whose seats the
+// draft holds, then an optional pre-order, and finally the confirmation that creates the booking.
+// A draft left alone until ExpiresAt is deleted and its seats released.
+type BookingDraft struct {
+	ID           string             `json:"id"`
+	UserID       string             `json:"user_id"`
+	RestaurantID string             `json:"restaurant_id"`
+	GuestsCount  int                `json:"guests_count"`
+	Duration     int                `json:"duration"`
+	Comment      string             `json:"comment"`
+	Occasion     BookingOccasion    `json:"occasion,omitempty"`
+	AgeAttested  bool               `json:"age_attested"`
+	Status       BookingDraftStatus `json:"status"`
+	// Date, Time and AvailabilityID are the slot held by the draft, set once it is held.
+	Date           time.Time      `json:"date,omitempty"`
+	Time           string         `json:"time,omitempty"`
+	AvailabilityID string         `json:"availability_id,omitempty"`
+	PreOrder       []PreOrderItem `json:"pre_order"`
+	BookingID      string         `json:"booking_id,omitempty"`
+	ExpiresAt      time.Time      `json:"expires_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+func (d *BookingDraft) Expired(now time.Time) bool {
+	return !now.Before(d.ExpiresAt)
+}
+
+// Booking returns the booking the draft stands for, not yet created.
+func (d *BookingDraft) Booking() *Booking {
+	return &Booking{
+		RestaurantID: d.RestaurantID,
+		UserID:       d.UserID,
+		Date:         d.Date,
+		Time:         d.Time,
+		Duration:     d.Duration,
+		GuestsCount:  d.GuestsCount,
+		Comment:      d.Comment,
+		Occasion:     d.Occasion,
+		AgeAttested:  d.AgeAttested,
+	}
+}
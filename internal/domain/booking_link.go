@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type BookingLinkAction string
+
+const (
+	// BookingLinkActionView opens the booking details.
+	BookingLinkActionView BookingLinkAction = "view"
+
+	// BookingLinkActionCancel opens the cancellation page and allows cancelling the booking.
+	BookingLinkActionCancel BookingLinkAction = "cancel"
+)
+
+// BookingLink grants access to a single booking without logging in. It is handed out as a signed
+// token, so the ID alone is not enough to use it.
+type BookingLink struct {
+	ID        string            `json:"id"`
+	BookingID string            `json:"booking_id"`
+	Action    BookingLinkAction `json:"action"`
+	SingleUse bool              `json:"single_use"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	UsedAt    *time.Time        `json:"used_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+func (l *BookingLink) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Consumed reports whether a single-use link has already been used.
+func (l *BookingLink) Consumed() bool {
+	return l.SingleUse && l.UsedAt != nil
+}
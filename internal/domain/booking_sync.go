@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// BookingSyncOperation is what a guest did to a booking while their app was offline.
+type BookingSyncOperation string
+
+const (
+	BookingSyncCreate BookingSyncOperation = "create"
+	BookingSyncCancel BookingSyncOperation = "cancel"
+)
+
+// BookingSyncStatus is the outcome of an operation an app submitted after an offline period.
+type BookingSyncStatus string
+
+const (
+	// BookingSyncApplied is an operation carried out as the guest asked.
+	BookingSyncApplied BookingSyncStatus = "applied"
+	// BookingSyncSlotFilled is a booking whose slot had no room left for the party by the time the
+	// app came back online.
+	BookingSyncSlotFilled BookingSyncStatus = "slot_filled"
+	// BookingSyncAlreadyCancelled is a cancellation of a booking cancelled in the meantime.
+	BookingSyncAlreadyCancelled BookingSyncStatus = "already_cancelled"
+	// BookingSyncNotCancellable is a cancellation of a booking rejected or completed in the meantime.
+	BookingSyncNotCancellable BookingSyncStatus = "not_cancellable"
+	// BookingSyncNotFound is an operation on a restaurant, user or booking that does not exist or
+	// is not the guest's.
+	BookingSyncNotFound BookingSyncStatus = "not_found"
+	// BookingSyncRejected is a booking that is invalid or over the quota of the restaurant.
+	BookingSyncRejected BookingSyncStatus = "rejected"
+	// BookingSyncFailed is an operation that could not be carried out for a reason on the side of
+	// the service. Its result is not kept, so submitting it again retries it.
+	BookingSyncFailed BookingSyncStatus = "failed"
+)
+
+// IsValid reports whether the operation is known.
+func (o BookingSyncOperation) IsValid() bool {
+	return o == BookingSyncCreate || o == BookingSyncCancel
+}
+
+// BookingSyncResult is the outcome of an operation submitted by the app of a user under a key of
+// its choosing. Results are kept by user and key, so that an operation submitted again, for
+// example after its response was lost, gets the same result instead of being carried out twice.
+type BookingSyncResult struct {
+	UserID    string               `json:"user_id"`
+	Key       string               `json:"key"`
+	Operation BookingSyncOperation `json:"operation"`
+	BookingID string               `json:"booking_id,omitempty"`
+	Status    BookingSyncStatus    `json:"status"`
+	// Error tells why an operation was rejected.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Replayed is set on a result kept from an earlier submission of the operation.
+	Replayed bool `json:"replayed"`
+}
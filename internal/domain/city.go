@@ -0,0 +1,12 @@
+package domain
+
+// City is a city of the catalogue, added when an address in it is geocoded. Cities of the same
+// name are told apart by their region and country.
+type City struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+	// RestaurantCount counts the restaurants placed in the city, test ones aside.
+	RestaurantCount int `json:"restaurant_count"`
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of restaurants that never set one.
+const DefaultCurrency = "RUB"
+
+// minorUnitsByCurrency holds the ISO 4217 currencies whose minor unit is not a hundredth of the
+// major one.
+var minorUnitsByCurrency = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IQD": 3,
+	"ISK": 0,
+	"JOD": 3,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"LYD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"UGX": 0,
+	"VND": 0,
+}
+
+// NormalizeCurrency returns the upper-case form of an ISO 4217 currency code, and false when code
+// is not three latin letters.
+func NormalizeCurrency(code string) (string, bool) {
+	if len(code) != 3 {
+		return "", false
+	}
+	code = strings.ToUpper(code)
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return code, true
+}
+
+// CurrencyMinorUnits returns how many decimal places the minor unit of the currency has.
+func CurrencyMinorUnits(currency string) int {
+	if units, ok := minorUnitsByCurrency[currency]; ok {
+		return units
+	}
+	return 2
+}
+
+// FormatAmount writes an amount in minor units of the currency with its decimal places and code,
+// such as "1250.00 RUB" for 125000. Amounts are never converted between currencies.
+func FormatAmount(amount int64, currency string) string {
+	units := CurrencyMinorUnits(currency)
+
+	sign := ""
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = uint64(-amount)
+	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	if units > 0 {
+		if len(digits) <= units {
+			digits = strings.Repeat("0", units-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-units] + "." + digits[len(digits)-units:]
+	}
+
+	return sign + digits + " " + currency
+}
+
+// ParseAmount reads a price as people and point-of-sale exports write it, such as "1 250,50 ₽",
+// "$1,250.50" or "1250", into minor units of the currency. Symbols, letters and spaces are ignored.
+// The last comma or dot separates the decimal places unless it is the only one and is followed by
+// three digits, as in "1,250"; the other commas and dots group thousands.
+func ParseAmount(value, currency string) (int64, error) {
+	var cleaned strings.Builder
+	for _, r := range value {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' {
+			cleaned.WriteRune(r)
+		}
+	}
+	number := cleaned.String()
+
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimPrefix(number, "-")
+	if number == "" || strings.Contains(number, "-") {
+		return 0, errors.New("is not an amount")
+	}
+
+	units := CurrencyMinorUnits(currency)
+	whole, fraction := number, ""
+	if i := strings.LastIndexAny(number, ".,"); i >= 0 {
+		separator := number[i : i+1]
+		grouping := strings.Count(number, separator) > 1 ||
+			(len(number)-i-1 == 3 && units != 3 && !strings.ContainsAny(number[:i], ".,"))
+		if !grouping {
+			whole, fraction = number[:i], number[i+1:]
+		}
+	}
+
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) > 1 || strings.ContainsAny(whole, ".,") {
+		if len(groups) == 0 || len(groups[0]) == 0 || len(groups[0]) > 3 ||
+			strings.Count(whole, ".")+strings.Count(whole, ",") != len(groups)-1 {
+			return 0, errors.New("is not an amount")
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return 0, errors.New("is not an amount")
+			}
+		}
+	}
+	whole = strings.Join(groups, "")
+	if len(fraction) > units {
+		return 0, errors.New("has more decimal places than the currency")
+	}
+	if whole == "" && fraction == "" {
+		return 0, errors.New("is not an amount")
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	amount, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", units-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, errors.New("is not an amount")
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
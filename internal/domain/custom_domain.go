@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type CustomDomainStatus string
+
+const (
+	// CustomDomainPending is a domain waiting for the DNS record that proves the restaurant
+	// controls it; it is not served yet.
+	CustomDomainPending CustomDomainStatus = "pending"
+
+	CustomDomainVerified CustomDomainStatus = "verified"
+)
+
+// CustomDomainVerificationPrefix is prepended to a domain to name the TXT record that has to hold
+// its verification token.
+const CustomDomainVerificationPrefix = "_booking-verification."
+
+// CustomDomain maps a domain or subdomain of a restaurant, such as book.example.com, to its hosted
+// booking page. A domain belongs to one restaurant; it is served, with its own certificate, once
+// the restaurant published VerificationToken in the TXT record named by VerificationRecord.
+type CustomDomain struct {
+	ID                string             `json:"id"`
+	RestaurantID      string             `json:"restaurant_id"`
+	Domain            string             `json:"domain"`
+	Status            CustomDomainStatus `json:"status"`
+	VerificationToken string             `json:"verification_token"`
+	CreatedAt         time.Time          `json:"created_at"`
+	VerifiedAt        *time.Time         `json:"verified_at,omitempty"`
+}
+
+// VerificationRecord is the name of the TXT record proving control of the domain.
+func (d *CustomDomain) VerificationRecord() string {
+	return CustomDomainVerificationPrefix + d.Domain
+}
+
+// NormalizeHostname returns the host name in lower case without a trailing dot, and whether it is
+// a valid fully qualified name of at least two labels. Addresses, ports and non-ASCII names are
+// not valid; internationalized names are given in their xn-- form.
+func NormalizeHostname(value string) (string, bool) {
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+	if len(host) == 0 || len(host) > 253 {
+		return "", false
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", false
+			}
+		}
+	}
+
+	// A top-level domain is never numeric, which leaves out IPv4 addresses.
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return "", false
+	}
+	return host, true
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// DeferredNotification is a notification held back by the quiet hours of its recipient, kept with
+// everything needed to send it over its channel once DeliverAt comes.
+type DeferredNotification struct {
+	ID               string              `json:"id"`
+	RecipientType    RecipientType       `json:"recipient_type"`
+	RecipientID      string              `json:"recipient_id"`
+	NotificationType NotificationType    `json:"type"`
+	Channel          NotificationChannel `json:"channel"`
+	Title            string              `json:"title"`
+	Message          string              `json:"message"`
+	RelatedID        string              `json:"related_id,omitempty"`
+	DeliverAt        time.Time           `json:"deliver_at"`
+	CreatedAt        time.Time           `json:"created_at"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ExportEntity names a kind of record of the change export.
+type ExportEntity string
+
+const (
+	ExportRestaurants   ExportEntity = "restaurants"
+	ExportUsers         ExportEntity = "users"
+	ExportAvailability  ExportEntity = "availability"
+	ExportBookings      ExportEntity = "bookings"
+	ExportMenuItems     ExportEntity = "menu_items"
+	ExportReviews       ExportEntity = "reviews"
+	ExportReviewReplies ExportEntity = "review_replies"
+)
+
+// ExportEntities lists every exported entity in the order they are exported, referenced records
+// first.
+var ExportEntities = []ExportEntity{
+	ExportRestaurants,
+	ExportUsers,
+	ExportAvailability,
+	ExportBookings,
+	ExportMenuItems,
+	ExportReviews,
+	ExportReviewReplies,
+}
+
+// IsValid reports whether e is one of ExportEntities.
+func (e ExportEntity) IsValid() bool {
+	for _, entity := range ExportEntities {
+		if e == entity {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportRecord is the current state of a record changed since a checkpoint. Data holds the row
+// as it is stored, so warehouses see every column, including ones the API does not expose.
+type ExportRecord struct {
+	Entity    ExportEntity    `json:"entity"`
+	ID        string          `json:"id"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+}
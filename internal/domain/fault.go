@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// RouteFault is a fault injected into the requests of a route for resilience testing: every
+// request is delayed by Latency and a share ErrorRate of them is answered with ErrorStatus.
+type RouteFault struct {
+	ID string `json:"id"`
+	// Method is the HTTP method of the requests; empty matches every method.
+	Method string `json:"method,omitempty"`
+	// Path is a route path as registered, e.g. "/api/v1/restaurants/:id/availability".
+	Path        string        `json:"path"`
+	Latency     time.Duration `json:"latency"`
+	ErrorRate   float64       `json:"error_rate"`
+	ErrorStatus int           `json:"error_status"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// FaultInjection is the faults injected at the moment: the faults of routes and the share of
+// database calls failed.
+type FaultInjection struct {
+	Enabled       bool         `json:"enabled"`
+	Routes        []RouteFault `json:"routes"`
+	DBFailureRate float64      `json:"db_failure_rate"`
+}
@@ -0,0 +1,138 @@
+package domain
+
+import "time"
+
+// OccupancyForecast projects the occupancy of the upcoming days of a restaurant from how its
+// bookings came in over the last weeks.
+type OccupancyForecast struct {
+	RestaurantID string
+	// Weeks is how many past weeks the projection averages over.
+	Weeks int
+	Days  []DayForecast
+}
+
+type DayForecast struct {
+	Date  time.Time
+	Slots []SlotForecast
+}
+
+// ProjectedOccupancy is the projected share of the seats of the day that will be reserved.
+func (d *DayForecast) ProjectedOccupancy() float64 {
+	var capacity int
+	var reserved float64
+	for _, slot := range d.Slots {
+		capacity += slot.Capacity
+		reserved += slot.ProjectedReserved
+	}
+	if capacity == 0 {
+		return 0
+	}
+	return reserved / float64(capacity)
+}
+
+// SlotForecast is the projection of one slot: the seats reserved now and the ones expected once
+// the bookings still to come are added, from Samples past slots at the same weekday and time.
+type SlotForecast struct {
+	TimeSlot          string
+	Capacity          int
+	Reserved          int
+	ProjectedReserved float64
+	Samples           int
+}
+
+// ProjectedOccupancy is the projected share of the seats of the slot that will be reserved.
+func (s *SlotForecast) ProjectedOccupancy() float64 {
+	if s.Capacity == 0 {
+		return 0
+	}
+	return s.ProjectedReserved / float64(s.Capacity)
+}
+
+// HourlyDemand counts the bookings requested for one weekday and hour.
+type HourlyDemand struct {
+	Weekday  time.Weekday
+	Hour     int
+	Bookings int
+	Guests   int
+}
+
+// DemandHeatmap counts the bookings requested at a restaurant for every weekday and hour over
+// whole weeks, whatever became of them, for staffing decisions.
+type DemandHeatmap struct {
+	RestaurantID string
+	From         time.Time
+	To           time.Time
+	Weeks        int
+	// Cells are indexed by weekday, Sunday first as in time.Weekday, then by hour.
+	Cells       [7][24]DemandCell
+	GeneratedAt time.Time
+}
+
+type DemandCell struct {
+	Bookings int
+	Guests   int
+}
+
+// ResponseLatency sums up how fast a restaurant answered the bookings requested over a period:
+// the time from the request to its confirmation or rejection.
+type ResponseLatency struct {
+	RestaurantID   string
+	RestaurantName string
+	From           time.Time
+	To             time.Time
+	// Requests are the bookings requested in the period; Responses of them were confirmed or
+	// rejected and Unanswered are still pending.
+	Requests   int
+	Responses  int
+	Unanswered int
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+}
+
+// BusyLevel tells at a glance how full a restaurant is right now.
+type BusyLevel string
+
+const (
+	BusyLevelUnknown BusyLevel = "unknown"
+
+	BusyLevelQuiet BusyLevel = "quiet"
+
+	BusyLevelModerate BusyLevel = "moderate"
+
+	BusyLevelBusy BusyLevel = "busy"
+)
+
+// LiveOccupancy counts the confirmed bookings of a restaurant whose parties are seated at At, for
+// a busyness badge on listings. Capacity is the seats of the slot under way, 0 when none is.
+type LiveOccupancy struct {
+	RestaurantID string
+	Bookings     int
+	Guests       int
+	Capacity     int
+	At           time.Time
+}
+
+// Occupancy is the share of the seats of the slot under way taken by the seated guests.
+func (o *LiveOccupancy) Occupancy() float64 {
+	if o.Capacity == 0 {
+		return 0
+	}
+	return float64(o.Guests) / float64(o.Capacity)
+}
+
+// Level buckets the occupancy: below 40% is quiet and below 75% moderate. A restaurant with
+// guests but no slot under way has no capacity to compare with and its level is unknown.
+func (o *LiveOccupancy) Level() BusyLevel {
+	switch occupancy := o.Occupancy(); {
+	case o.Guests == 0:
+		return BusyLevelQuiet
+	case o.Capacity == 0:
+		return BusyLevelUnknown
+	case occupancy < 0.4:
+		return BusyLevelQuiet
+	case occupancy < 0.75:
+		return BusyLevelModerate
+	}
+	return BusyLevelBusy
+}
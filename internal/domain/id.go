@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidID is wrapped by every error of the Parse functions of identifiers.
+var ErrInvalidID = errors.New("invalid id")
+
+// RestaurantID, BookingID and UserID identify restaurants, bookings and users. Lookups of records
+// by the restaurant or the user they belong to take them typed, so that passing one in place of
+// the other does not compile. Parse them from requests; IDs read from stored records are converted.
+type (
+	RestaurantID string
+	BookingID    string
+	UserID       string
+)
+
+func (id RestaurantID) String() string { return string(id) }
+func (id BookingID) String() string    { return string(id) }
+func (id UserID) String() string       { return string(id) }
+
+// ParseRestaurantID returns the identifier of a restaurant in its canonical form.
+func ParseRestaurantID(value string) (RestaurantID, error) {
+	return parseID[RestaurantID]("restaurant", value)
+}
+
+// ParseBookingID returns the identifier of a booking in its canonical form.
+func ParseBookingID(value string) (BookingID, error) {
+	return parseID[BookingID]("booking", value)
+}
+
+// ParseUserID returns the identifier of a user in its canonical form.
+func ParseUserID(value string) (UserID, error) {
+	return parseID[UserID]("user", value)
+}
+
+// parseID accepts the identifiers the repositories generate: UUIDv7 and, for records created
+// before the switch to it, the random UUIDv4 generated then.
+func parseID[T ~string](kind, value string) (T, error) {
+	id, err := uuid.Parse(value)
+	if err != nil || (id.Version() != 4 && id.Version() != 7) {
+		return "", fmt.Errorf("%w: %s %q is not a UUID", ErrInvalidID, kind, value)
+	}
+	return T(id.String()), nil
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type ImageVariantStatus string
+
+const (
+	ImageVariantPending    ImageVariantStatus = "pending"
+	ImageVariantProcessing ImageVariantStatus = "processing"
+	ImageVariantReady      ImageVariantStatus = "ready"
+	ImageVariantFailed     ImageVariantStatus = "failed"
+
+	// ImageVariantDiscarded marks a variant that came out no smaller than the original upload,
+	// so serving it would not help anyone.
+	ImageVariantDiscarded ImageVariantStatus = "discarded"
+)
+
+// RestaurantImage is a picture uploaded for a restaurant. Data holds the original upload; clients
+// get resized variants of it from the image endpoint.
+type RestaurantImage struct {
+	ID           string                   `json:"id"`
+	RestaurantID string                   `json:"restaurant_id"`
+	ContentType  string                   `json:"content_type"`
+	Width        int                      `json:"width"`
+	Height       int                      `json:"height"`
+	Size         int                      `json:"size"`
+	Data         []byte                   `json:"-"`
+	Variants     []RestaurantImageVariant `json:"variants"`
+	CreatedAt    time.Time                `json:"created_at"`
+}
+
+// RestaurantImageVariant is the full-size image converted to another format, such as WebP, in the
+// background after the upload.
+type RestaurantImageVariant struct {
+	ImageID     string             `json:"image_id"`
+	ContentType string             `json:"content_type"`
+	Status      ImageVariantStatus `json:"status"`
+	Width       int                `json:"width"`
+	Height      int                `json:"height"`
+	Size        int                `json:"size"`
+	Data        []byte             `json:"-"`
+	Error       string             `json:"error,omitempty"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+type IncentiveEffect string
+
+const (
+	// IncentiveWaiveDeposit lets the guest book without paying a deposit.
+	IncentiveWaiveDeposit IncentiveEffect = "waive_deposit"
+
+	// IncentiveBonusPoints credits the guest with the points of the rule.
+	IncentiveBonusPoints IncentiveEffect = "bonus_points"
+)
+
+var IncentiveEffects = []IncentiveEffect{IncentiveWaiveDeposit, IncentiveBonusPoints}
+
+// IncentiveConditions are what a booking must meet for a rule to apply; conditions left empty
+// match every booking.
+type IncentiveConditions struct {
+	// FirstBooking matches the first booking of a user on the platform.
+	FirstBooking bool `json:"first_booking,omitempty"`
+	// Weekdays the booked date falls on, Sunday being 0.
+	Weekdays      []time.Weekday `json:"weekdays,omitempty"`
+	MinGuests     int            `json:"min_guests,omitempty"`
+	RestaurantIDs []string       `json:"restaurant_ids,omitempty"`
+}
+
+// IncentiveRule is a platform incentive granted to the bookings meeting its conditions.
+type IncentiveRule struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name"`
+	Effect IncentiveEffect `json:"effect"`
+	// Points are credited by a bonus_points rule.
+	Points     int                 `json:"points,omitempty"`
+	Conditions IncentiveConditions `json:"conditions"`
+	Enabled    bool                `json:"enabled"`
+	// ValidFrom and ValidUntil bound when bookings are made for the rule to apply; nil is open.
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IncentiveFacts are what the rules are evaluated against when a booking is made.
+type IncentiveFacts struct {
+	UserID       string       `json:"user_id"`
+	RestaurantID string       `json:"restaurant_id"`
+	Date         time.Time    `json:"date"`
+	Weekday      time.Weekday `json:"weekday"`
+	GuestsCount  int          `json:"guests_count"`
+	// PreviousBookings counts the bookings the user made before, rejected and cancelled ones aside.
+	PreviousBookings int       `json:"previous_bookings"`
+	BookedAt         time.Time `json:"booked_at"`
+}
+
+// NewIncentiveFacts gathers the facts of a booking made at bookedAt by a user with
+// previousBookings earlier bookings.
+func NewIncentiveFacts(booking *Booking, previousBookings int, bookedAt time.Time) IncentiveFacts {
+	return IncentiveFacts{
+		UserID:           booking.UserID,
+		RestaurantID:     booking.RestaurantID,
+		Date:             booking.Date,
+		Weekday:          booking.Date.Weekday(),
+		GuestsCount:      booking.GuestsCount,
+		PreviousBookings: previousBookings,
+		BookedAt:         bookedAt,
+	}
+}
+
+// IncentiveRuleResult is the outcome of one rule for a booking.
+type IncentiveRuleResult struct {
+	RuleID   string          `json:"rule_id"`
+	RuleName string          `json:"rule_name"`
+	Effect   IncentiveEffect `json:"effect"`
+	Points   int             `json:"points,omitempty"`
+	Matched  bool            `json:"matched"`
+	// Failed names the conditions the booking did not meet, e.g. "first_booking".
+	Failed []string `json:"failed,omitempty"`
+}
+
+// Evaluate checks every condition of the rule against the facts.
+func (r *IncentiveRule) Evaluate(facts IncentiveFacts) IncentiveRuleResult {
+	result := IncentiveRuleResult{
+		RuleID:   r.ID,
+		RuleName: r.Name,
+		Effect:   r.Effect,
+	}
+
+	conditions := r.Conditions
+	if r.ValidFrom != nil && facts.BookedAt.Before(*r.ValidFrom) {
+		result.Failed = append(result.Failed, "valid_from")
+	}
+	if r.ValidUntil != nil && !facts.BookedAt.Before(*r.ValidUntil) {
+		result.Failed = append(result.Failed, "valid_until")
+	}
+	if conditions.FirstBooking && facts.PreviousBookings > 0 {
+		result.Failed = append(result.Failed, "first_booking")
+	}
+	if len(conditions.Weekdays) > 0 && !slices.Contains(conditions.Weekdays, facts.Weekday) {
+		result.Failed = append(result.Failed, "weekdays")
+	}
+	if facts.GuestsCount < conditions.MinGuests {
+		result.Failed = append(result.Failed, "min_guests")
+	}
+	if len(conditions.RestaurantIDs) > 0 && !slices.Contains(conditions.RestaurantIDs, facts.RestaurantID) {
+		result.Failed = append(result.Failed, "restaurant_ids")
+	}
+
+	result.Matched = len(result.Failed) == 0
+	if result.Matched && r.Effect == IncentiveBonusPoints {
+		result.Points = r.Points
+	}
+	return result
+}
+
+// IncentiveEvaluation is the audit trace of the rules evaluated for a booking and the incentives
+// it was granted: the deposit is waived when any waive_deposit rule matched, and the points of
+// every matching bonus_points rule add up.
+type IncentiveEvaluation struct {
+	ID           string                `json:"id"`
+	BookingID    string                `json:"booking_id"`
+	Facts        IncentiveFacts        `json:"facts"`
+	Results      []IncentiveRuleResult `json:"results"`
+	WaiveDeposit bool                  `json:"waive_deposit"`
+	BonusPoints  int                   `json:"bonus_points"`
+	EvaluatedAt  time.Time             `json:"evaluated_at"`
+}
+
+// EvaluateIncentives evaluates the rules for a booking with the facts.
+func EvaluateIncentives(rules []*IncentiveRule, facts IncentiveFacts) *IncentiveEvaluation {
+	evaluation := &IncentiveEvaluation{
+		Facts:   facts,
+		Results: make([]IncentiveRuleResult, 0, len(rules)),
+	}
+
+	for _, rule := range rules {
+		result := rule.Evaluate(facts)
+		evaluation.Results = append(evaluation.Results, result)
+		if !result.Matched {
+			continue
+		}
+
+		switch rule.Effect {
+		case IncentiveWaiveDeposit:
+			evaluation.WaiveDeposit = true
+		case IncentiveBonusPoints:
+			evaluation.BonusPoints += result.Points
+		}
+	}
+	return evaluation
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// SeqScanTable is a table read by sequential scans more often than through an index.
+type SeqScanTable struct {
+	Name        string `json:"name"`
+	SeqScans    int64  `json:"seq_scans"`
+	SeqRowsRead int64  `json:"seq_rows_read"`
+	IndexScans  int64  `json:"index_scans"`
+	LiveRows    int64  `json:"live_rows"`
+}
+
+// ScanHeavyQuery is a statement that reads many blocks for every row it returns, the mark of a
+// sequential scan an index could save. Query is normalized, its constants replaced with $n.
+type ScanHeavyQuery struct {
+	QueryID      int64   `json:"query_id"`
+	Query        string  `json:"query"`
+	Calls        int64   `json:"calls"`
+	Rows         int64   `json:"rows"`
+	Blocks       int64   `json:"blocks"`
+	BlocksPerRow float64 `json:"blocks_per_row"`
+	MeanTimeMs   float64 `json:"mean_time_ms"`
+}
+
+// IndexAdvice is what the database statistics tell about missing indexes. Queries is empty when
+// StatementsAvailable is false, i.e. pg_stat_statements is not installed.
+type IndexAdvice struct {
+	Tables              []SeqScanTable   `json:"tables"`
+	Queries             []ScanHeavyQuery `json:"queries"`
+	StatementsAvailable bool             `json:"statements_available"`
+	CheckedAt           time.Time        `json:"checked_at"`
+}
@@ -0,0 +1,222 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Allergen is one of the allergens menus have to declare, as listed by EU food law.
+type Allergen string
+
+const (
+	AllergenGluten      Allergen = "gluten"
+	AllergenCrustaceans Allergen = "crustaceans"
+	AllergenEggs        Allergen = "eggs"
+	AllergenFish        Allergen = "fish"
+	AllergenPeanuts     Allergen = "peanuts"
+	AllergenSoy         Allergen = "soy"
+	AllergenMilk        Allergen = "milk"
+	AllergenTreeNuts    Allergen = "tree_nuts"
+	AllergenCelery      Allergen = "celery"
+	AllergenMustard     Allergen = "mustard"
+	AllergenSesame      Allergen = "sesame"
+	AllergenSulphites   Allergen = "sulphites"
+	AllergenLupin       Allergen = "lupin"
+	AllergenMolluscs    Allergen = "molluscs"
+)
+
+var Allergens = []Allergen{
+	AllergenGluten,
+	AllergenCrustaceans,
+	AllergenEggs,
+	AllergenFish,
+	AllergenPeanuts,
+	AllergenSoy,
+	AllergenMilk,
+	AllergenTreeNuts,
+	AllergenCelery,
+	AllergenMustard,
+	AllergenSesame,
+	AllergenSulphites,
+	AllergenLupin,
+	AllergenMolluscs,
+}
+
+// DietaryLabel is a diet a menu item suits.
+type DietaryLabel string
+
+const (
+	DietaryVegetarian  DietaryLabel = "vegetarian"
+	DietaryVegan       DietaryLabel = "vegan"
+	DietaryGlutenFree  DietaryLabel = "gluten_free"
+	DietaryLactoseFree DietaryLabel = "lactose_free"
+	DietaryHalal       DietaryLabel = "halal"
+	DietaryKosher      DietaryLabel = "kosher"
+)
+
+var DietaryLabels = []DietaryLabel{
+	DietaryVegetarian,
+	DietaryVegan,
+	DietaryGlutenFree,
+	DietaryLactoseFree,
+	DietaryHalal,
+	DietaryKosher,
+}
+
+// dietaryExclusions are the allergens an item with the label cannot contain.
+var dietaryExclusions = map[DietaryLabel][]Allergen{
+	DietaryVegetarian: {AllergenFish, AllergenCrustaceans, AllergenMolluscs},
+	DietaryVegan:      {AllergenFish, AllergenCrustaceans, AllergenMolluscs, AllergenEggs, AllergenMilk},
+	DietaryGlutenFree: {AllergenGluten},
+}
+
+// MenuItem is a dish or drink on the menu of a restaurant. Prices are in minor units of the
+// currency of the restaurant, which is filled in when the menu is read and never saved with it.
+type MenuItem struct {
+	ID           string `json:"id"`
+	RestaurantID string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Category     string `json:"category"`
+	Price        int64  `json:"price"`
+	Currency     string `json:"currency"`
+	IsAvailable  bool   `json:"is_available"`
+	// DailyLimit caps how many of the item the bookings of a day may pre-order; zero is no limit.
+	DailyLimit int `json:"daily_limit"`
+	// Allergens and DietaryLabels are kept in the order of Allergens and DietaryLabels.
+	Allergens     []Allergen     `json:"allergens"`
+	DietaryLabels []DietaryLabel `json:"dietary_labels"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NormalizeTags checks the allergens and dietary labels of the item against their vocabularies
+// and against each other, and puts them in vocabulary order without duplicates.
+func (m *MenuItem) NormalizeTags() error {
+	for _, allergen := range m.Allergens {
+		if !slices.Contains(Allergens, allergen) {
+			return fmt.Errorf("unknown allergen %q", allergen)
+		}
+	}
+	for _, label := range m.DietaryLabels {
+		if !slices.Contains(DietaryLabels, label) {
+			return fmt.Errorf("unknown dietary label %q", label)
+		}
+		for _, allergen := range dietaryExclusions[label] {
+			if slices.Contains(m.Allergens, allergen) {
+				return fmt.Errorf("an item containing %s cannot be %s", allergen, label)
+			}
+		}
+	}
+
+	m.Allergens = inVocabularyOrder(Allergens, m.Allergens)
+	m.DietaryLabels = inVocabularyOrder(DietaryLabels, m.DietaryLabels)
+	return nil
+}
+
+// HasDietaryLabel reports whether the item suits the diet; vegan items suit vegetarians too.
+func (m *MenuItem) HasDietaryLabel(label DietaryLabel) bool {
+	return slices.Contains(m.DietaryLabels, label) ||
+		(label == DietaryVegetarian && slices.Contains(m.DietaryLabels, DietaryVegan))
+}
+
+func inVocabularyOrder[T comparable](vocabulary, values []T) []T {
+	result := make([]T, 0, len(values))
+	for _, value := range vocabulary {
+		if slices.Contains(values, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// MenuFilter narrows a menu to the items free of the excluded allergens and suiting every one of
+// the diets; an empty filter matches every item.
+type MenuFilter struct {
+	ExcludeAllergens []Allergen
+	DietaryLabels    []DietaryLabel
+}
+
+// Validate checks the filter against the vocabularies.
+func (f MenuFilter) Validate() error {
+	for _, allergen := range f.ExcludeAllergens {
+		if !slices.Contains(Allergens, allergen) {
+			return fmt.Errorf("unknown allergen %q", allergen)
+		}
+	}
+	for _, label := range f.DietaryLabels {
+		if !slices.Contains(DietaryLabels, label) {
+			return fmt.Errorf("unknown dietary label %q", label)
+		}
+	}
+	return nil
+}
+
+func (f MenuFilter) Matches(item *MenuItem) bool {
+	for _, allergen := range f.ExcludeAllergens {
+		if slices.Contains(item.Allergens, allergen) {
+			return false
+		}
+	}
+	for _, label := range f.DietaryLabels {
+		if !item.HasDietaryLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+var ErrPreOrderLimitReached = errors.New("daily pre-order limit of the menu item reached")
+
+// PreOrderLimitError is returned when a pre-order would take more of a menu item than its daily
+// limit leaves for the date of the booking.
+type PreOrderLimitError struct {
+	MenuItemID string
+	Name       string
+	Date       time.Time
+	Limit      int
+	Reserved   int
+	Delta      int
+}
+
+func (e *PreOrderLimitError) Error() string {
+	return fmt.Sprintf("%s: %d of %d %q reserved for %s, %d more requested",
+		ErrPreOrderLimitReached, e.Reserved, e.Limit, e.Name, e.Date.Format(time.DateOnly), e.Delta)
+}
+
+func (e *PreOrderLimitError) Unwrap() error {
+	return ErrPreOrderLimitReached
+}
+
+// PreOrderItem is a menu item ordered in advance for a booking. The name and the unit price are
+// kept as they were when the item was ordered, so later menu changes do not alter the order.
+type PreOrderItem struct {
+	MenuItemID string `json:"menu_item_id"`
+	Name       string `json:"name"`
+	UnitPrice  int64  `json:"unit_price"`
+	Currency   string `json:"currency"`
+	Quantity   int    `json:"quantity"`
+	Notes      string `json:"notes,omitempty"`
+}
+
+// PreOrder is what the guests of a booking ordered from the menu ahead of their visit. Its currency
+// is the one the items were ordered in, empty when nothing is ordered.
+type PreOrder struct {
+	BookingID      string         `json:"booking_id"`
+	Items          []PreOrderItem `json:"items"`
+	EstimatedTotal int64          `json:"estimated_total"`
+	Currency       string         `json:"currency,omitempty"`
+	EditableUntil  time.Time      `json:"editable_until"`
+	Editable       bool           `json:"editable"`
+}
+
+// Estimate returns the total of the items at their ordered prices; the bill may differ.
+func (p *PreOrder) Estimate() int64 {
+	var total int64
+	for _, item := range p.Items {
+		total += item.UnitPrice * int64(item.Quantity)
+	}
+	return total
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -21,8 +22,108 @@ const (
 	NotificationTypeAlternativeAccepted NotificationType = "alternative_accepted"
 
 	NotificationTypeAlternativeRejected NotificationType = "alternative_rejected"
+
+	// NotificationTypeSummary combines several notifications sent to a restaurant in a short time.
+	NotificationTypeSummary NotificationType = "summary"
+
+	// NotificationTypeDailyDigest sums up the bookings and occasions of the day for a restaurant.
+	NotificationTypeDailyDigest NotificationType = "daily_digest"
+
+	// NotificationTypeWeeklyReport is the weekly performance report emailed to a restaurant.
+	NotificationTypeWeeklyReport NotificationType = "weekly_report"
+
+	NotificationTypeNewReview NotificationType = "new_review"
+
+	NotificationTypeReviewReply NotificationType = "review_reply"
+
+	// NotificationTypeReviewFlagResolved tells a restaurant, and the author when the review was
+	// removed, how an admin settled a flag on a review.
+	NotificationTypeReviewFlagResolved NotificationType = "review_flag_resolved"
+
+	// NotificationTypeGeocodingFailed tells a restaurant that its address could not be placed on
+	// the map.
+	NotificationTypeGeocodingFailed NotificationType = "geocoding_failed"
+
+	// NotificationTypeAvailabilityAlert tells a user that a table they asked to be alerted about
+	// freed up.
+	NotificationTypeAvailabilityAlert NotificationType = "availability_alert"
+
+	// NotificationTypeBookingTransfer asks a user to agree to move their booking to a sister
+	// restaurant, and tells the restaurants what the user decided.
+	NotificationTypeBookingTransfer NotificationType = "booking_transfer"
+
+	// NotificationTypeSLOAlert tells an admin that an error budget of the service level
+	// objectives is burning fast or that an objective was missed for a day.
+	NotificationTypeSLOAlert NotificationType = "slo_alert"
+
+	// NotificationTypeRestaurantClaim tells a user whether an admin approved their claim of a
+	// restaurant profile.
+	NotificationTypeRestaurantClaim NotificationType = "restaurant_claim"
+
+	// NotificationTypeMarketing invites a user back to a restaurant. Unlike the other types it is
+	// opt-in on every channel.
+	NotificationTypeMarketing NotificationType = "marketing"
+)
+
+// NotificationTypes are the event types a recipient can set preferences for.
+var NotificationTypes = []NotificationType{
+	NotificationTypeNewBooking,
+	NotificationTypeBookingConfirmed,
+	NotificationTypeBookingRejected,
+	NotificationTypeBookingCancelled,
+	NotificationTypeAlternativeOffer,
+	NotificationTypeAlternativeAccepted,
+	NotificationTypeAlternativeRejected,
+	NotificationTypeDailyDigest,
+	NotificationTypeWeeklyReport,
+	NotificationTypeNewReview,
+	NotificationTypeReviewReply,
+	NotificationTypeReviewFlagResolved,
+	NotificationTypeGeocodingFailed,
+	NotificationTypeAvailabilityAlert,
+	NotificationTypeBookingTransfer,
+	NotificationTypeSLOAlert,
+	NotificationTypeRestaurantClaim,
+	NotificationTypeMarketing,
+}
+
+// MarketingNotificationTypes are sent only to recipients who turned them on.
+var MarketingNotificationTypes = []NotificationType{
+	NotificationTypeMarketing,
+}
+
+// DeferrableNotificationTypes can wait for the quiet hours of the recipient to end. The other
+// types, booking changes above all, are urgent and delivered right away.
+var DeferrableNotificationTypes = []NotificationType{
+	NotificationTypeDailyDigest,
+	NotificationTypeWeeklyReport,
+	NotificationTypeMarketing,
+}
+
+// IsUrgent reports whether notifications of the type are delivered during quiet hours.
+func (t NotificationType) IsUrgent() bool {
+	return !slices.Contains(DeferrableNotificationTypes, t)
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "in_app"
+
+	NotificationChannelEmail NotificationChannel = "email"
+
+	NotificationChannelSMS NotificationChannel = "sms"
+
+	NotificationChannelPush NotificationChannel = "push"
 )
 
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelPush,
+}
+
 type RecipientType string
 
 const (
@@ -32,26 +133,221 @@ const (
 )
 
 type Notification struct {
-	ID            string           `json:"id"`
-	RecipientType RecipientType    `json:"recipient_type"`
-	RecipientID   string           `json:"recipient_id"`
-	Type          NotificationType `json:"type"`
-	Title         string           `json:"title"`
-	Message       string           `json:"message"`
-	IsRead        bool             `json:"is_read"`
-	RelatedID     string           `json:"related_id"`
-	CreatedAt     time.Time        `json:"created_at"`
+	ID            string              `json:"id"`
+	RecipientType RecipientType       `json:"recipient_type"`
+	RecipientID   string              `json:"recipient_id"`
+	Type          NotificationType    `json:"type"`
+	Channel       NotificationChannel `json:"channel"`
+	Title         string              `json:"title"`
+	Message       string              `json:"message"`
+	IsRead        bool                `json:"is_read"`
+	RelatedID     string              `json:"related_id"`
+	CreatedAt     time.Time           `json:"created_at"`
+	DeliveredAt   *time.Time          `json:"delivered_at,omitempty"`
+	ReadAt        *time.Time          `json:"read_at,omitempty"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "pending"
+
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+
+	DeliveryStatusRead DeliveryStatus = "read"
+)
+
+// DeliveryStatus reports how far the notification got towards its recipient.
+func (n *Notification) DeliveryStatus() DeliveryStatus {
+	switch {
+	case n.IsRead || n.ReadAt != nil:
+		return DeliveryStatusRead
+	case n.DeliveredAt != nil:
+		return DeliveryStatusDelivered
+	default:
+		return DeliveryStatusPending
+	}
+}
+
+// NotificationDelivery is the receipt of a notification: who it went to, over which channel and
+// when it was delivered and read, without its content.
+type NotificationDelivery struct {
+	NotificationID string              `json:"notification_id"`
+	RecipientType  RecipientType       `json:"recipient_type"`
+	Type           NotificationType    `json:"type"`
+	Channel        NotificationChannel `json:"channel"`
+	Status         DeliveryStatus      `json:"status"`
+	CreatedAt      time.Time           `json:"created_at"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"`
+	ReadAt         *time.Time          `json:"read_at,omitempty"`
+}
+
+// Delivery returns the receipt of the notification.
+func (n *Notification) Delivery() NotificationDelivery {
+	return NotificationDelivery{
+		NotificationID: n.ID,
+		RecipientType:  n.RecipientType,
+		Type:           n.Type,
+		Channel:        n.Channel,
+		Status:         n.DeliveryStatus(),
+		CreatedAt:      n.CreatedAt,
+		DeliveredAt:    n.DeliveredAt,
+		ReadAt:         n.ReadAt,
+	}
+}
+
+// NotificationPreference turns one channel on or off for one event type.
+type NotificationPreference struct {
+	Type    NotificationType    `json:"type"`
+	Channel NotificationChannel `json:"channel"`
+	Enabled bool                `json:"enabled"`
+}
+
+// QuietHours hold back the notifications that can wait over one channel every day from Start to
+// End, past midnight when End is before Start, and until SnoozedUntil. Start and End are times of
+// day such as "22:00" in the time zone of the service, like working hours; they are both empty for
+// a snooze alone.
+type QuietHours struct {
+	Channel      NotificationChannel `json:"channel"`
+	Start        string              `json:"start,omitempty"`
+	End          string              `json:"end,omitempty"`
+	SnoozedUntil *time.Time          `json:"snoozed_until,omitempty"`
+}
+
+// Validate checks that the quiet hours are a window of two different times of day, a snooze, or
+// both.
+func (q *QuietHours) Validate() error {
+	window := q.Start != "" || q.End != ""
+	switch {
+	case !slices.Contains(NotificationChannels, q.Channel):
+		return &ValidationError{Entity: "quiet hours", Field: "channel", Reason: "is not a known channel"}
+	case !window && q.SnoozedUntil == nil:
+		return &ValidationError{Entity: "quiet hours", Field: "start", Reason: "is required without snoozed_until"}
+	case window && !validClock(q.Start):
+		return &ValidationError{Entity: "quiet hours", Field: "start", Reason: "is not a time of day"}
+	case window && !validClock(q.End):
+		return &ValidationError{Entity: "quiet hours", Field: "end", Reason: "is not a time of day"}
+	case window && q.Start == q.End:
+		return &ValidationError{Entity: "quiet hours", Field: "end", Reason: "must differ from start"}
+	}
+	return nil
+}
+
+// Until returns when the quiet hours holding at t end, and false when they do not hold at t. A
+// snooze ending within the window keeps the channel quiet until the window ends.
+func (q *QuietHours) Until(t time.Time) (time.Time, bool) {
+	if q.SnoozedUntil == nil || !q.SnoozedUntil.After(t) {
+		return q.windowEnd(t)
+	}
+	if end, ok := q.windowEnd(*q.SnoozedUntil); ok {
+		return end, true
+	}
+	return *q.SnoozedUntil, true
+}
+
+// windowEnd returns the end of the daily window holding at t.
+func (q *QuietHours) windowEnd(t time.Time) (time.Time, bool) {
+	start, err := time.Parse(clockLayout, q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse(clockLayout, q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	at := func(day time.Time, clock time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, t.Location())
+	}
+	today := at(t, time.Time{})
+	startsAt, endsAt := at(today, start), at(today, end)
+
+	switch {
+	case startsAt.Before(endsAt):
+		return endsAt, !t.Before(startsAt) && t.Before(endsAt)
+	case !t.Before(startsAt):
+		return at(today.AddDate(0, 0, 1), end), true
+	default:
+		return endsAt, t.Before(endsAt)
+	}
+}
+
+// NotificationSettings are the channel preferences and quiet hours of a user or a restaurant.
+// Combinations without a preference use DefaultNotificationPreference.
+type NotificationSettings struct {
+	RecipientType RecipientType            `json:"recipient_type"`
+	RecipientID   string                   `json:"recipient_id"`
+	Preferences   []NotificationPreference `json:"preferences"`
+	QuietHours    []QuietHours             `json:"quiet_hours"`
+}
+
+// DefaultNotificationPreference reports whether a channel is on before the recipient changes it:
+// in-app and email notifications are opt-out, SMS and push are opt-in, and marketing is opt-in on
+// every channel.
+func DefaultNotificationPreference(notificationType NotificationType, channel NotificationChannel) bool {
+	if slices.Contains(MarketingNotificationTypes, notificationType) {
+		return false
+	}
+	return channel == NotificationChannelInApp || channel == NotificationChannelEmail
+}
+
+// Allows reports whether notifications of the type may be delivered over the channel.
+func (s *NotificationSettings) Allows(notificationType NotificationType, channel NotificationChannel) bool {
+	for _, p := range s.Preferences {
+		if p.Type == notificationType && p.Channel == channel {
+			return p.Enabled
+		}
+	}
+	return DefaultNotificationPreference(notificationType, channel)
+}
+
+// DeferUntil returns when a notification of the type sent over the channel at now is to be
+// delivered, and false when it is delivered right away: urgent types always are.
+func (s *NotificationSettings) DeferUntil(notificationType NotificationType, channel NotificationChannel, now time.Time) (time.Time, bool) {
+	if notificationType.IsUrgent() {
+		return time.Time{}, false
+	}
+	for i := range s.QuietHours {
+		if s.QuietHours[i].Channel == channel {
+			return s.QuietHours[i].Until(now)
+		}
+	}
+	return time.Time{}, false
+}
+
+// marketingChannels are the channels marketing is delivered over, most preferred first; it goes
+// over one channel only.
+var marketingChannels = []NotificationChannel{
+	NotificationChannelEmail,
+	NotificationChannelSMS,
+	NotificationChannelInApp,
+}
+
+// PreferredMarketingChannel returns the channel marketing is delivered over: the first one the
+// recipient turned on of email, SMS and in-app. There is none until the recipient opts in.
+func (s *NotificationSettings) PreferredMarketingChannel() (NotificationChannel, bool) {
+	for _, channel := range marketingChannels {
+		if s.Allows(NotificationTypeMarketing, channel) {
+			return channel, true
+		}
+	}
+	return "", false
 }
 
 type EmailSender interface {
 	SendEmail(to, subject, body string) error
 }
 
+type SMSSender interface {
+	SendSMS(to, body string) error
+}
+
 type NotificationService interface {
 	NotifyRestaurant(ctx context.Context, restaurantID string, notificationType NotificationType,
 		title, message string, relatedID string) error
 	NotifyUser(ctx context.Context, userID string, notificationType NotificationType,
 		title, message string, relatedID string) error
 	GetUserNotifications(ctx context.Context, userID string) ([]Notification, error)
+	GetNotification(ctx context.Context, notificationID string) (*Notification, error)
 	MarkAsRead(ctx context.Context, notificationID string) error
 }
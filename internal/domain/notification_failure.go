@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+type NotificationFailureStatus string
+
+const (
+	// NotificationFailureStatusPending failures are retried once their next attempt is due.
+	NotificationFailureStatusPending NotificationFailureStatus = "pending"
+
+	// NotificationFailureStatusDelivered means a retry delivered the notification.
+	NotificationFailureStatusDelivered NotificationFailureStatus = "delivered"
+
+	// NotificationFailureStatusExhausted means every attempt failed; the notification is given up.
+	NotificationFailureStatusExhausted NotificationFailureStatus = "exhausted"
+)
+
+var NotificationFailureStatuses = []NotificationFailureStatus{
+	NotificationFailureStatusPending,
+	NotificationFailureStatusDelivered,
+	NotificationFailureStatusExhausted,
+}
+
+// NotificationFailure is a notification that could not be delivered, kept with everything needed
+// to send it again. Attempts counts the failed deliveries, the first one included, and Cause is the
+// error of the last one.
+type NotificationFailure struct {
+	ID               string                    `json:"id"`
+	RecipientType    RecipientType             `json:"recipient_type"`
+	RecipientID      string                    `json:"recipient_id"`
+	NotificationType NotificationType          `json:"type"`
+	Title            string                    `json:"title"`
+	Message          string                    `json:"message"`
+	RelatedID        string                    `json:"related_id,omitempty"`
+	Cause            string                    `json:"cause"`
+	Attempts         int                       `json:"attempts"`
+	Status           NotificationFailureStatus `json:"status"`
+	NextAttemptAt    *time.Time                `json:"next_attempt_at,omitempty"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	UpdatedAt        time.Time                 `json:"updated_at"`
+}
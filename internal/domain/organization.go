@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Organization groups the sister restaurants of one owner. A restaurant belongs to at most one.
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BookingTransferStatus string
+
+const (
+	BookingTransferPending BookingTransferStatus = "pending"
+
+	// BookingTransferAccepted is a transfer the guest agreed to: the original booking is
+	// cancelled and NewBookingID is its replacement at the target restaurant.
+	BookingTransferAccepted BookingTransferStatus = "accepted"
+
+	// BookingTransferDeclined is a transfer the guest refused; the original booking stays.
+	BookingTransferDeclined BookingTransferStatus = "declined"
+)
+
+// BookingTransfer proposes to move a booking to a sister restaurant of the same organization on
+// Date at Time. It waits for the guest's consent; a booking has at most one pending transfer.
+type BookingTransfer struct {
+	ID                 string                `json:"id"`
+	BookingID          string                `json:"booking_id"`
+	SourceRestaurantID string                `json:"source_restaurant_id"`
+	TargetRestaurantID string                `json:"target_restaurant_id"`
+	Date               time.Time             `json:"date"`
+	Time               string                `json:"time"`
+	Message            string                `json:"message"`
+	Status             BookingTransferStatus `json:"status"`
+	NewBookingID       string                `json:"new_booking_id,omitempty"`
+	CreatedAt          time.Time             `json:"created_at"`
+	DecidedAt          *time.Time            `json:"decided_at,omitempty"`
+}
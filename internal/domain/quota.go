@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// Plan is the subscription tier of a restaurant; restaurants are on the free plan until an admin
+// moves them.
+type Plan string
+
+const (
+	PlanFree Plan = "free"
+	PlanPro  Plan = "pro"
+)
+
+var Plans = []Plan{PlanFree, PlanPro}
+
+// QuotaResource is what a quota limits.
+type QuotaResource string
+
+const (
+	// QuotaActiveSlots are the availability slots of today and later.
+	QuotaActiveSlots QuotaResource = "active_slots"
+	// QuotaMonthlyBookings are the bookings requested in a calendar month, test bookings aside.
+	QuotaMonthlyBookings QuotaResource = "monthly_bookings"
+)
+
+// QuotaLimits bound the resources of a restaurant; zero is unlimited.
+type QuotaLimits struct {
+	ActiveSlots     int
+	MonthlyBookings int
+}
+
+// Limit returns the limit of the resource.
+func (l QuotaLimits) Limit(resource QuotaResource) int {
+	switch resource {
+	case QuotaActiveSlots:
+		return l.ActiveSlots
+	case QuotaMonthlyBookings:
+		return l.MonthlyBookings
+	default:
+		return 0
+	}
+}
+
+// RestaurantPlan is the plan of a restaurant with the limits an admin set in place of those of
+// the plan; nil limits follow the plan.
+type RestaurantPlan struct {
+	RestaurantID    string
+	Plan            Plan
+	ActiveSlots     *int
+	MonthlyBookings *int
+	Note            string
+	UpdatedAt       time.Time
+}
+
+// Limits returns the limits of the restaurant given those of its plan.
+func (p *RestaurantPlan) Limits(plan QuotaLimits) QuotaLimits {
+	if p.ActiveSlots != nil {
+		plan.ActiveSlots = *p.ActiveSlots
+	}
+	if p.MonthlyBookings != nil {
+		plan.MonthlyBookings = *p.MonthlyBookings
+	}
+	return plan
+}
+
+type QuotaUsage struct {
+	ActiveSlots     int
+	MonthlyBookings int
+}
+
+// Quota is the plan of a restaurant with its limits and how much of them it uses.
+type Quota struct {
+	Plan   *RestaurantPlan
+	Limits QuotaLimits
+	Usage  QuotaUsage
+}
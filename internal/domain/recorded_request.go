@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+)
+
+// RecordedRequest is an inbound API request kept for debugging a partner integration. Credentials
+// are removed before it is stored, and only JSON bodies are kept.
+type RecordedRequest struct {
+	ID       string `json:"id"`
+	APIKeyID string `json:"api_key_id"`
+	Method   string `json:"method"`
+	// Path includes the query string.
+	Path        string            `json:"path"`
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body,omitempty"`
+	BodyOmitted bool              `json:"body_omitted,omitempty"`
+	Status      int               `json:"status"`
+	DurationMS  int64             `json:"duration_ms"`
+	RecordedAt  time.Time         `json:"recorded_at"`
+}
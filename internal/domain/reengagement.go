@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type ReengagementReason string
+
+const (
+	// ReengagementReasonLapsed is a guest who has not been back since a visit some weeks ago.
+	ReengagementReasonLapsed ReengagementReason = "lapsed"
+
+	// ReengagementReasonAnniversary is a guest who celebrated an anniversary at the restaurant a
+	// year before.
+	ReengagementReasonAnniversary ReengagementReason = "anniversary"
+)
+
+// ReengagementCandidate is a guest to invite back to a restaurant, with the completed visit the
+// invitation refers to.
+type ReengagementCandidate struct {
+	UserID         string
+	RestaurantID   string
+	RestaurantName string
+	Reason         ReengagementReason
+	VisitDate      time.Time
+}
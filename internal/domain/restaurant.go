@@ -1,14 +1,31 @@
 package domain
 
 import (
+	"slices"
+	"strings"
 	"time"
 )
 
 type Cuisine string
 
+// Cuisines are the cuisines a restaurant may be listed under; a cuisine is matched regardless of
+// case.
+var Cuisines = []Cuisine{
+	"american", "asian", "bakery", "bbq", "cafe", "caucasian", "chinese", "european", "french",
+	"fusion", "georgian", "greek", "indian", "international", "italian", "japanese", "korean",
+	"mediterranean", "mexican", "middle eastern", "pizza", "russian", "seafood", "spanish",
+	"steak", "sushi", "thai", "turkish", "uzbek", "vegan", "vegetarian", "vietnamese",
+}
+
+// IsKnown reports whether the cuisine is one of Cuisines.
+func (c Cuisine) IsKnown() bool {
+	return slices.Contains(Cuisines, Cuisine(strings.ToLower(strings.TrimSpace(string(c)))))
+}
+
 type Restaurant struct {
 	ID           string    `json:"id"`
 	Name         string    `json:"name"`
+	Slug         string    `json:"slug"`
 	Address      string    `json:"address"`
 	Cuisine      Cuisine   `json:"cuisine"`
 	Description  string    `json:"description"`
@@ -17,11 +34,88 @@ type Restaurant struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 	ContactEmail string    `json:"contact_email"`
 	ContactPhone string    `json:"contact_phone"`
+	// NormalizedContactEmail and NormalizedContactPhone are the contacts in lower case and E.164.
+	NormalizedContactEmail string `json:"normalized_contact_email"`
+	NormalizedContactPhone string `json:"normalized_contact_phone"`
+	// Currency is the ISO 4217 code every price of the restaurant is in; amounts are kept in its
+	// minor units.
+	Currency string `json:"currency"`
+	// IsTest marks a sandbox restaurant of a partner integrating against production. It and its
+	// bookings are left out of the catalogue, public facts and real notifications.
+	IsTest bool `json:"is_test"`
+	// IsAdultOnly marks a venue that admits adults only, such as a bar. Every booking of it needs
+	// the guest to attest their age.
+	IsAdultOnly bool `json:"is_adult_only"`
+}
+
+// Validate checks that the restaurant has a name and, when they are given, a known cuisine and an
+// ISO 4217 currency.
+func (r *Restaurant) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return &ValidationError{Entity: "restaurant", Field: "name", Reason: "is required"}
+	case r.Cuisine != "" && !r.Cuisine.IsKnown():
+		return &ValidationError{Entity: "restaurant", Field: "cuisine", Reason: "is not a known cuisine"}
+	case r.Currency != "":
+		if _, ok := NormalizeCurrency(r.Currency); !ok {
+			return &ValidationError{Entity: "restaurant", Field: "currency", Reason: "is not an ISO 4217 code"}
+		}
+	}
+	return nil
 }
 
+const DefaultFactLocale = "en"
+
 type Fact struct {
 	ID           string    `json:"id"`
 	RestaurantID string    `json:"restaurant_id"`
 	Content      string    `json:"content"`
+	Locale       string    `json:"locale"`
 	CreatedAt    time.Time `json:"created_at"`
 }
+
+type FactFilter struct {
+	RestaurantID string
+	Cuisine      Cuisine
+	Locale       string
+}
+
+func (f FactFilter) IsEmpty() bool {
+	return f.RestaurantID == "" && f.Cuisine == "" && f.Locale == ""
+}
+
+// RestaurantCursor is the position after a restaurant in a list ordered by name and ID. The zero
+// cursor is the start.
+type RestaurantCursor struct {
+	Name string
+	ID   string
+}
+
+// CursorAfter returns the cursor right after the restaurant.
+func (r *Restaurant) CursorAfter() RestaurantCursor {
+	return RestaurantCursor{Name: r.Name, ID: r.ID}
+}
+
+// IsZero reports whether the cursor is at the start.
+func (c RestaurantCursor) IsZero() bool {
+	return c.ID == ""
+}
+
+// RestaurantFilter narrows a list of restaurants down; empty fields match every restaurant.
+type RestaurantFilter struct {
+	CityID string
+	// AdultOnly, when set, keeps only the adult-only venues or only the others.
+	AdultOnly *bool
+	// Query is searched for in the name and description, in the syntax of web search engines:
+	// every word must occur, "quoted phrases" in order, -word not at all and "or" between
+	// alternatives. Matches are ranked by relevance instead of by name.
+	Query   string
+	Cuisine Cuisine
+	// Address keeps the restaurants with every word of it in their address.
+	Address string
+	// OpenAt, when set, keeps the restaurants open at its time of day according to their working
+	// hours.
+	OpenAt *time.Time
+	// LiveOnly leaves the test restaurants out.
+	LiveOnly bool
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+type RestaurantClaimStatus string
+
+const (
+	RestaurantClaimPending RestaurantClaimStatus = "pending"
+
+	// RestaurantClaimApproved is a claim an admin accepted: the claimant owns the restaurant.
+	RestaurantClaimApproved RestaurantClaimStatus = "approved"
+
+	// RestaurantClaimRejected is a claim an admin turned down, or one left pending when another
+	// claim of the restaurant was approved.
+	RestaurantClaimRejected RestaurantClaimStatus = "rejected"
+)
+
+func (s RestaurantClaimStatus) IsValid() bool {
+	switch s {
+	case RestaurantClaimPending, RestaurantClaimApproved, RestaurantClaimRejected:
+		return true
+	}
+	return false
+}
+
+// RestaurantClaim asks for a restaurant profile an admin imported to be handed over to the user
+// who owns the restaurant. Proof tells the reviewing admin why the user is the owner, such as a
+// registration number or a link to documents; a user has at most one pending claim of a
+// restaurant.
+type RestaurantClaim struct {
+	ID           string                `json:"id"`
+	RestaurantID string                `json:"restaurant_id"`
+	UserID       string                `json:"user_id"`
+	Proof        string                `json:"proof"`
+	Status       RestaurantClaimStatus `json:"status"`
+	ReviewerID   string                `json:"reviewer_id,omitempty"`
+	ReviewNote   string                `json:"review_note,omitempty"`
+	CreatedAt    time.Time             `json:"created_at"`
+	DecidedAt    *time.Time            `json:"decided_at,omitempty"`
+}
+
+type RestaurantClaimFilter struct {
+	RestaurantID string
+	UserID       string
+	Status       RestaurantClaimStatus
+}
+
+// RestaurantOwnership names the owner of a restaurant and the approved claim that made them so.
+// A restaurant without one is still held by the platform.
+type RestaurantOwnership struct {
+	RestaurantID string    `json:"restaurant_id"`
+	OwnerID      string    `json:"owner_id"`
+	ClaimID      string    `json:"claim_id"`
+	Since        time.Time `json:"since"`
+}
+
+// OwnershipAuditRecord is kept for every hand-over of a restaurant to an owner: who the owner is,
+// the claim that made them so and the admin who approved it. Records are never changed or
+// removed.
+type OwnershipAuditRecord struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	ClaimID      string    `json:"claim_id"`
+	OwnerID      string    `json:"owner_id"`
+	ApprovedBy   string    `json:"approved_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxClosureMessageLength bounds the message the restaurant leaves to the guests during a closure.
+const MaxClosureMessageLength = 500
+
+// RestaurantClosure is a period the restaurant is closed, such as a vacation or a renovation, from
+// StartsOn up to ReopensOn, exclusive; both are dates. Booking requests for a day of the period are
+// answered on the restaurant's behalf with its Message and the day it reopens.
+type RestaurantClosure struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	StartsOn     time.Time `json:"starts_on"`
+	ReopensOn    time.Time `json:"reopens_on"`
+	Message      string    `json:"message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Validate checks that the closure lasts at least a day and that its message is not too long.
+func (c *RestaurantClosure) Validate() error {
+	switch {
+	case c.StartsOn.IsZero():
+		return &ValidationError{Entity: "restaurant closure", Field: "starts_on", Reason: "is required"}
+	case c.ReopensOn.IsZero():
+		return &ValidationError{Entity: "restaurant closure", Field: "reopens_on", Reason: "is required"}
+	case !closureDate(c.ReopensOn).After(closureDate(c.StartsOn)):
+		return &ValidationError{Entity: "restaurant closure", Field: "reopens_on", Reason: "must be after starts_on"}
+	case len([]rune(strings.TrimSpace(c.Message))) > MaxClosureMessageLength:
+		return &ValidationError{Entity: "restaurant closure", Field: "message", Reason: "is too long"}
+	}
+	return nil
+}
+
+// Covers reports whether the restaurant is closed on the day of date.
+func (c *RestaurantClosure) Covers(date time.Time) bool {
+	day := closureDate(date)
+	return !day.Before(closureDate(c.StartsOn)) && day.Before(closureDate(c.ReopensOn))
+}
+
+// ClosureOn returns the closure among closures that covers the day of date, nil when the
+// restaurant is open on it.
+func ClosureOn(closures []*RestaurantClosure, date time.Time) *RestaurantClosure {
+	for _, closure := range closures {
+		if closure.Covers(date) {
+			return closure
+		}
+	}
+	return nil
+}
+
+// Overlaps reports whether the closures share a day.
+func (c *RestaurantClosure) Overlaps(other *RestaurantClosure) bool {
+	return closureDate(c.StartsOn).Before(closureDate(other.ReopensOn)) &&
+		closureDate(other.StartsOn).Before(closureDate(c.ReopensOn))
+}
+
+// closureDate is the day of t as the dates of bookings are kept, at midnight UTC.
+func closureDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
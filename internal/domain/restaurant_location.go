@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type GeocodingStatus string
+
+const (
+	GeocodingPending    GeocodingStatus = "pending"
+	GeocodingProcessing GeocodingStatus = "processing"
+	GeocodingDone       GeocodingStatus = "done"
+	GeocodingFailed     GeocodingStatus = "failed"
+)
+
+// RestaurantLocation is the address of a restaurant as geocoded in the background after it was
+// set: its components and coordinates once Status is done, or why it could not be placed once it
+// failed.
+type RestaurantLocation struct {
+	RestaurantID string          `json:"restaurant_id"`
+	Address      string          `json:"address"`
+	Status       GeocodingStatus `json:"status"`
+	Formatted    string          `json:"formatted_address,omitempty"`
+	Street       string          `json:"street,omitempty"`
+	HouseNumber  string          `json:"house_number,omitempty"`
+	City         string          `json:"city,omitempty"`
+	// CityID is the city of the catalogue the address was placed in.
+	CityID      string  `json:"city_id,omitempty"`
+	Region      string  `json:"region,omitempty"`
+	PostalCode  string  `json:"postal_code,omitempty"`
+	CountryCode string  `json:"country_code,omitempty"`
+	Latitude    float64 `json:"latitude,omitempty"`
+	Longitude   float64 `json:"longitude,omitempty"`
+	Provider    string  `json:"provider,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	// Attempts counts the tries to geocode the address, failed ones included.
+	Attempts   int        `json:"attempts"`
+	GeocodedAt *time.Time `json:"geocoded_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// RestaurantPerformance sums up how a restaurant did over a period, for its weekly report.
+type RestaurantPerformance struct {
+	RestaurantID   string
+	RestaurantName string
+	ContactEmail   string
+	From           time.Time
+	To             time.Time
+
+	// Bookings are those for a date in the period; Guests and Cancellations count among them.
+	Bookings      int
+	Guests        int
+	Cancellations int
+
+	// SeatsReserved of SeatsOffered in the availability of the period.
+	SeatsReserved int
+	SeatsOffered  int
+
+	// Reviews published in the period and their average rating.
+	Reviews       int
+	AverageRating float64
+
+	// Responses to the bookings requested in the period, confirmed or rejected, and the average
+	// time the restaurant took to give them.
+	Responses           int
+	AverageResponseTime time.Duration
+}
+
+// Occupancy is the share of the offered seats that were reserved, from 0 to 1.
+func (p *RestaurantPerformance) Occupancy() float64 {
+	if p.SeatsOffered == 0 {
+		return 0
+	}
+	return float64(p.SeatsReserved) / float64(p.SeatsOffered)
+}
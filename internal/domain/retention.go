@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type RetentionPolicy string
+
+const (
+	// RetentionPurgeReadNotifications deletes notifications read before the cutoff.
+	RetentionPurgeReadNotifications RetentionPolicy = "purge_read_notifications"
+
+	// RetentionAnonymizeCompletedBookings strips the guest from bookings completed before the
+	// cutoff: the user, comment, occasion and pre-order notes are cleared, the booking links are
+	// deleted and the reviews of the bookings no longer name their author. The booking itself is
+	// kept for the statistics of the restaurant.
+	RetentionAnonymizeCompletedBookings RetentionPolicy = "anonymize_completed_bookings"
+)
+
+// AnonymousUserID replaces the user of anonymized records.
+const AnonymousUserID = "00000000-0000-0000-0000-000000000000"
+
+// RetentionRun is the audit entry of one application of a retention policy. Dry runs report how
+// many records would have been affected and change nothing.
+type RetentionRun struct {
+	ID       string          `json:"id"`
+	Policy   RetentionPolicy `json:"policy"`
+	DryRun   bool            `json:"dry_run"`
+	Cutoff   time.Time       `json:"cutoff"`
+	Affected int             `json:"affected"`
+	// Error is set when the run failed; nothing was changed then.
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
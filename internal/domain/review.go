@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPublished ReviewStatus = "published"
+
+	// ReviewStatusRemoved marks a review an admin took down after upholding a flag on it.
+	ReviewStatusRemoved ReviewStatus = "removed"
+)
+
+// Review is what a guest wrote about a restaurant after a completed booking, one per booking.
+type Review struct {
+	ID           string       `json:"id"`
+	RestaurantID string       `json:"restaurant_id"`
+	UserID       string       `json:"user_id"`
+	BookingID    string       `json:"booking_id"`
+	Rating       int          `json:"rating"`
+	Text         string       `json:"text"`
+	Status       ReviewStatus `json:"status"`
+	Reply        *ReviewReply `json:"reply,omitempty"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// ReviewReply is the public answer of the restaurant to a review. A review has at most one; the
+// restaurant may edit it.
+type ReviewReply struct {
+	ReviewID  string    `json:"review_id"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReviewFlagStatus string
+
+const (
+	ReviewFlagStatusOpen ReviewFlagStatus = "open"
+
+	// ReviewFlagStatusUpheld means an admin agreed with the restaurant and removed the review.
+	ReviewFlagStatusUpheld ReviewFlagStatus = "upheld"
+
+	// ReviewFlagStatusDismissed means an admin kept the review.
+	ReviewFlagStatusDismissed ReviewFlagStatus = "dismissed"
+)
+
+// ReviewFlag is a complaint of a restaurant about an abusive review, waiting for or settled by
+// admin arbitration. A review has at most one open flag.
+type ReviewFlag struct {
+	ID             string           `json:"id"`
+	ReviewID       string           `json:"review_id"`
+	RestaurantID   string           `json:"restaurant_id"`
+	Reason         string           `json:"reason"`
+	Status         ReviewFlagStatus `json:"status"`
+	ResolutionNote string           `json:"resolution_note,omitempty"`
+	Review         *Review          `json:"review,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+}
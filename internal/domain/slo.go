@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// RequestMetrics counts the API requests answered in an hour: all of them, the ones failed with a
+// server error and the ones answered slower than the latency threshold.
+type RequestMetrics struct {
+	Hour     time.Time `json:"hour"`
+	Requests int64     `json:"requests"`
+	Errors   int64     `json:"errors"`
+	Slow     int64     `json:"slow"`
+}
+
+// SLOObjectives are the service level objectives: the share of requests answered without a server
+// error, and the share answered within LatencyThreshold.
+type SLOObjectives struct {
+	AvailabilityTarget float64       `json:"availability_target"`
+	LatencyTarget      float64       `json:"latency_target"`
+	LatencyThreshold   time.Duration `json:"latency_threshold"`
+}
+
+// SLOReport is the compliance of the requests of a day, or of a period starting on Date, with the
+// objectives. A burn rate of 1 spends the error budget exactly; above it the objective is missed.
+type SLOReport struct {
+	Date                 time.Time     `json:"date"`
+	Objectives           SLOObjectives `json:"objectives"`
+	Requests             int64         `json:"requests"`
+	Errors               int64         `json:"errors"`
+	Slow                 int64         `json:"slow"`
+	Availability         float64       `json:"availability"`
+	LatencyCompliance    float64       `json:"latency_compliance"`
+	AvailabilityBurnRate float64       `json:"availability_burn_rate"`
+	LatencyBurnRate      float64       `json:"latency_burn_rate"`
+	CreatedAt            time.Time     `json:"created_at"`
+}
+
+// NewSLOReport measures the requests counted in metrics against the objectives. Without requests
+// both objectives are met.
+func NewSLOReport(date time.Time, metrics RequestMetrics, objectives SLOObjectives) *SLOReport {
+	report := &SLOReport{
+		Date:              date,
+		Objectives:        objectives,
+		Requests:          metrics.Requests,
+		Errors:            metrics.Errors,
+		Slow:              metrics.Slow,
+		Availability:      1,
+		LatencyCompliance: 1,
+	}
+	if metrics.Requests > 0 {
+		report.Availability = 1 - float64(metrics.Errors)/float64(metrics.Requests)
+		report.LatencyCompliance = 1 - float64(metrics.Slow)/float64(metrics.Requests)
+	}
+	report.AvailabilityBurnRate = BurnRate(report.Availability, objectives.AvailabilityTarget)
+	report.LatencyBurnRate = BurnRate(report.LatencyCompliance, objectives.LatencyTarget)
+	return report
+}
+
+// BurnRate tells how many times faster than sustainable the error budget of the target is spent
+// at the achieved share of good requests. A target of 1 has no budget, so any bad request burns
+// it as fast as can be told.
+func BurnRate(achieved, target float64) float64 {
+	bad := 1 - achieved
+	if bad <= 0 {
+		return 0
+	}
+	budget := 1 - target
+	if budget <= 0 {
+		return math.MaxFloat64
+	}
+	return bad / budget
+}
+
+// AvailabilityMet reports whether the availability objective was met.
+func (r *SLOReport) AvailabilityMet() bool {
+	return r.AvailabilityBurnRate <= 1
+}
+
+// LatencyMet reports whether the latency objective was met.
+func (r *SLOReport) LatencyMet() bool {
+	return r.LatencyBurnRate <= 1
+}
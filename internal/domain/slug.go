@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	MaxSlugLength     = 64
+	DefaultSlugPrefix = "restaurant"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var cyrillicTransliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Slugify turns a restaurant name into a URL slug: lower case latin letters and digits separated
+// by single dashes. Cyrillic is transliterated, accents are stripped and other characters separate
+// words.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r == 'ъ' || r == 'ь' || r == '\'' {
+			continue
+		}
+
+		latin, ok := cyrillicTransliteration[r]
+		if !ok {
+			latin = asciiAlphanumeric(norm.NFD.String(string(r)))
+		}
+
+		if latin != "" {
+			b.WriteString(latin)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+	}
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return DefaultSlugPrefix
+	}
+
+	return slug
+}
+
+func asciiAlphanumeric(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func IsValidSlug(slug string) bool {
+	return len(slug) <= MaxSlugLength && slugPattern.MatchString(slug)
+}
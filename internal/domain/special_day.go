@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxSpecialDayNoteLength bounds the note a restaurant leaves on a special day.
+const MaxSpecialDayNoteLength = 200
+
+// SpecialDay replaces the working hours of a restaurant on one date, such as a holiday it is
+// closed on or a night it stays open late. Closing at or before opening means closing after
+// midnight, as with working hours.
+type SpecialDay struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Date         time.Time `json:"date"`
+	OpenTime     string    `json:"open_time,omitempty"`
+	CloseTime    string    `json:"close_time,omitempty"`
+	IsClosed     bool      `json:"is_closed"`
+	Note         string    `json:"note,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks the date and, unless the restaurant is closed on it, the times of day it opens
+// and closes at.
+func (d *SpecialDay) Validate() error {
+	switch {
+	case d.Date.IsZero():
+		return &ValidationError{Entity: "special day", Field: "date", Reason: "is required"}
+	case !d.IsClosed && !validClock(d.OpenTime):
+		return &ValidationError{Entity: "special day", Field: "open_time", Reason: "is not a time of day"}
+	case !d.IsClosed && !validClock(d.CloseTime):
+		return &ValidationError{Entity: "special day", Field: "close_time", Reason: "is not a time of day"}
+	case len([]rune(strings.TrimSpace(d.Note))) > MaxSpecialDayNoteLength:
+		return &ValidationError{Entity: "special day", Field: "note", Reason: "is too long"}
+	}
+	return nil
+}
+
+// SpecialDayOn returns the special day among days on the day of date, nil when there is none.
+func SpecialDayOn(days []*SpecialDay, date time.Time) *SpecialDay {
+	day := startOfDay(date)
+	for _, special := range days {
+		if startOfDay(special.Date).Equal(day) {
+			return special
+		}
+	}
+	return nil
+}
+
+// ScheduleOn resolves when the restaurant is open on the date: a closure closes the day whatever
+// its hours, a special day replaces the working hours of the weekday, and otherwise the working
+// hours valid on the date apply.
+func ScheduleOn(workingHours []*WorkingHours, specialDays []*SpecialDay, closures []*RestaurantClosure, date time.Time) ScheduleDay {
+	if closure := ClosureOn(closures, date); closure != nil {
+		return ScheduleDay{Date: date, Hours: []OpeningHours{}, Closure: closure}
+	}
+
+	if special := SpecialDayOn(specialDays, date); special != nil {
+		hours := make([]OpeningHours, 0, 1)
+		if !special.IsClosed {
+			if opening, ok := openingHoursAt(date, special.OpenTime, special.CloseTime); ok {
+				hours = append(hours, opening)
+			}
+		}
+		return ScheduleDay{Date: date, Hours: hours, SpecialDay: special}
+	}
+
+	hours := OpeningHoursOn(workingHours, date)
+	slices.SortFunc(hours, func(a, b OpeningHours) int {
+		return a.OpensAt.Compare(b.OpensAt)
+	})
+	return ScheduleDay{Date: date, Hours: hours}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSyncCursor = errors.New("invalid sync cursor")
+
+// SyncChange is a change of a record of an entity: its current state or, for a deleted record,
+// a tombstone without data.
+type SyncChange struct {
+	ID        string          `json:"id"`
+	ChangedAt time.Time       `json:"changed_at"`
+	Deleted   bool            `json:"deleted"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// SyncCursor is the position of a sync client in the changes of an entity: the last change it
+// has seen. The zero cursor is the start.
+type SyncCursor struct {
+	ChangedAt time.Time
+	ID        string
+}
+
+// CursorAfter returns the cursor right after the change.
+func (c *SyncChange) CursorAfter() SyncCursor {
+	return SyncCursor{ChangedAt: c.ChangedAt, ID: c.ID}
+}
+
+// IsZero reports whether the cursor is at the start.
+func (c SyncCursor) IsZero() bool {
+	return c.ChangedAt.IsZero() && c.ID == ""
+}
+
+// String encodes the cursor as an opaque URL-safe token, or as a time for a cursor made of one.
+func (c SyncCursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	if c.ID == "" {
+		return c.ChangedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(c.ChangedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID))
+}
+
+// ParseSyncCursor decodes a token of SyncCursor.String. A plain RFC 3339 time is accepted too and
+// starts after every change made up to it.
+func ParseSyncCursor(value string) (SyncCursor, error) {
+	if value == "" {
+		return SyncCursor{}, nil
+	}
+	if changedAt, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return SyncCursor{ChangedAt: changedAt}, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	changedAt, id, ok := strings.Cut(string(decoded), "|")
+	if !ok || id == "" {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	cursor := SyncCursor{ID: id}
+	if cursor.ChangedAt, err = time.Parse(time.RFC3339Nano, changedAt); err != nil {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxTableNameLength and MaxTableZoneLength bound the name and the zone of a table.
+	MaxTableNameLength = 50
+	MaxTableZoneLength = 100
+
+	// MaxTableSeats bounds the seats of a table.
+	MaxTableSeats = 50
+)
+
+// Table is a table of the floor plan of a restaurant. Every booking of a restaurant with tables is
+// seated at one of them.
+type Table struct {
+	ID           string `json:"id"`
+	RestaurantID string `json:"restaurant_id"`
+	// Name tells the staff which table it is, e.g. its number.
+	Name  string `json:"name"`
+	Seats int    `json:"seats"`
+	// Zone is the part of the floor the table is in, such as the main hall or the terrace; it is
+	// empty for a restaurant with a single room.
+	Zone      string `json:"zone,omitempty"`
+	IsSmoking bool   `json:"is_smoking"`
+	IsOutdoor bool   `json:"is_outdoor"`
+	// IsAccessible is set for a table a guest in a wheelchair can be seated at.
+	IsAccessible bool      `json:"is_accessible"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Validate checks that the table belongs to a restaurant, has a name and seats between one and
+// MaxTableSeats.
+func (t *Table) Validate() error {
+	switch {
+	case t.RestaurantID == "":
+		return &ValidationError{Entity: "table", Field: "restaurant_id", Reason: "is required"}
+	case strings.TrimSpace(t.Name) == "":
+		return &ValidationError{Entity: "table", Field: "name", Reason: "is required"}
+	case len([]rune(t.Name)) > MaxTableNameLength:
+		return &ValidationError{Entity: "table", Field: "name", Reason: "is too long"}
+	case len([]rune(t.Zone)) > MaxTableZoneLength:
+		return &ValidationError{Entity: "table", Field: "zone", Reason: "is too long"}
+	case t.Seats < 1:
+		return &ValidationError{Entity: "table", Field: "seats", Reason: "must be positive"}
+	case t.Seats > MaxTableSeats:
+		return &ValidationError{Entity: "table", Field: "seats", Reason: "is too large"}
+	}
+	return nil
+}
+
+// Fits reports whether a party of guests can be seated at the table.
+func (t *Table) Fits(guests int) bool {
+	return guests <= t.Seats
+}
+
+// TableHold is a table held by a pending or confirmed booking, from the time of the booking for its
+// duration in minutes.
+type TableHold struct {
+	TableID   string
+	BookingID string
+	Date      time.Time
+	Time      string
+	Duration  int
+}
+
+// Seating returns when the party holding the table sits down and leaves.
+func (h TableHold) Seating() (time.Time, time.Time, bool) {
+	return (&Booking{Date: h.Date, Time: h.Time, Duration: h.Duration}).Seating()
+}
+
+// FreeTables returns the tables that none of the holds keeps during the seating of booking.
+func FreeTables(tables []*Table, holds []TableHold, booking *Booking) []*Table {
+	start, end, ok := booking.Seating()
+	if !ok {
+		return nil
+	}
+
+	held := make(map[string]bool)
+	for _, hold := range holds {
+		if hold.BookingID == booking.ID {
+			continue
+		}
+		if holdStart, holdEnd, ok := hold.Seating(); ok && holdStart.Before(end) && start.Before(holdEnd) {
+			held[hold.TableID] = true
+		}
+	}
+
+	free := make([]*Table, 0, len(tables))
+	for _, table := range tables {
+		if !held[table.ID] {
+			free = append(free, table)
+		}
+	}
+	return free
+}
+
+const (
+	// zoneMissCost is what seating a party outside the zone it asked for costs: more than any
+	// table is too large for a party by, so that a party sits in its zone whenever a table there
+	// fits it.
+	zoneMissCost = MaxTableSeats
+
+	// accessibleSpareCost is what seating a party that does not need it at an accessible table
+	// costs, to keep those tables for the parties that do.
+	accessibleSpareCost = 1
+
+	// misseatedCost is what a booking seated at a table it no longer fits, or one missing from the
+	// floor plan, costs: more than any seating it fits.
+	misseatedCost = 4 * MaxTableSeats
+)
+
+// seatingCost returns what seating the party of the booking at the table costs: the seats left
+// empty at it, plus zoneMissCost outside the zone the party asked for and accessibleSpareCost for
+// an accessible table it does not need. It reports false for a table the party does not fit at or
+// can't reach.
+func seatingCost(table *Table, booking *Booking) (int, bool) {
+	if !table.Fits(booking.GuestsCount) || booking.NeedsAccessible && !table.IsAccessible {
+		return 0, false
+	}
+	cost := table.Seats - booking.GuestsCount
+	if booking.SeatingZone != "" && !strings.EqualFold(table.Zone, booking.SeatingZone) {
+		cost += zoneMissCost
+	}
+	if table.IsAccessible && !booking.NeedsAccessible {
+		cost += accessibleSpareCost
+	}
+	return cost, true
+}
+
+// PickTable returns the table the party of the booking is best seated at, or nil when it fits at
+// none: an accessible table for a party that needs one, in the zone it asked for when a table
+// there fits it, and the smallest of those, so that larger tables stay free for larger parties.
+// Tables costing the same are picked by size, then by name.
+func PickTable(tables []*Table, booking *Booking) *Table {
+	var best *Table
+	bestCost := 0
+	for _, table := range tables {
+		cost, ok := seatingCost(table, booking)
+		if !ok {
+			continue
+		}
+		if best == nil || cmp.Or(cmp.Compare(cost, bestCost), cmp.Compare(table.Seats, best.Seats), cmp.Compare(table.Name, best.Name)) < 0 {
+			best, bestCost = table, cost
+		}
+	}
+	return best
+}
+
+// OptimizeSeating seats the bookings again at the tables, around the holds that stay where they
+// are, and returns the table of every booking that moves, keyed by booking ID. The parties that
+// need an accessible table are seated first and larger parties before smaller ones, each at the
+// table PickTable picks for it. It returns nil when a booking would not fit anywhere or when the
+// new seating would cost no less than the one the bookings have.
+func OptimizeSeating(tables []*Table, holds []TableHold, bookings []*Booking) map[string]string {
+	byID := make(map[string]*Table, len(tables))
+	for _, table := range tables {
+		byID[table.ID] = table
+	}
+
+	current := 0
+	for _, booking := range bookings {
+		cost, ok := 0, false
+		if table := byID[booking.TableID]; table != nil {
+			cost, ok = seatingCost(table, booking)
+		}
+		if !ok {
+			cost = misseatedCost
+		}
+		current += cost
+	}
+
+	ordered := slices.Clone(bookings)
+	slices.SortStableFunc(ordered, func(a, b *Booking) int {
+		if a.NeedsAccessible != b.NeedsAccessible {
+			if a.NeedsAccessible {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(b.GuestsCount, a.GuestsCount), cmp.Compare(a.Time, b.Time), cmp.Compare(a.ID, b.ID))
+	})
+
+	seated := slices.Clone(holds)
+	optimized := 0
+	moves := make(map[string]string)
+	for _, booking := range ordered {
+		table := PickTable(FreeTables(tables, seated, booking), booking)
+		if table == nil {
+			return nil
+		}
+		cost, _ := seatingCost(table, booking)
+		optimized += cost
+		seated = append(seated, TableHold{
+			TableID:   table.ID,
+			BookingID: booking.ID,
+			Date:      booking.Date,
+			Time:      booking.Time,
+			Duration:  booking.Duration,
+		})
+		if table.ID != booking.TableID {
+			moves[booking.ID] = table.ID
+		}
+	}
+
+	if optimized >= current || len(moves) == 0 {
+		return nil
+	}
+	return moves
+}
@@ -11,4 +11,8 @@ type User struct {
 	Phone     string    `json:"phone"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// NormalizedEmail is Email in lower case; no two users share it.
+	NormalizedEmail string `json:"normalized_email"`
+	// NormalizedPhone is Phone in E.164.
+	NormalizedPhone string `json:"normalized_phone"`
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidEntity is wrapped by every error of the Validate methods of entities.
+var ErrInvalidEntity = errors.New("invalid entity")
+
+// ValidationError names the field of an entity that breaks one of its invariants.
+type ValidationError struct {
+	Entity string
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s %s", e.Entity, e.Field, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidEntity
+}
+
+// clockLayout is the layout of the times of day of bookings, slots and working hours.
+const clockLayout = "15:04"
+
+// validClock reports whether value is a time of day such as "09:30".
+func validClock(value string) bool {
+	_, err := time.Parse(clockLayout, value)
+	return err == nil
+}
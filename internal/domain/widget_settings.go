@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	DefaultWidgetPrimaryColor    = "#22aa77"
+	DefaultWidgetBackgroundColor = "#ffffff"
+	DefaultWidgetTextColor       = "#222222"
+	DefaultWidgetPartySize       = 2
+	DefaultWidgetLocale          = "en"
+
+	// MaxWidgetPartySize bounds the party size a widget preselects.
+	MaxWidgetPartySize = 20
+)
+
+var ErrInvalidWidgetSettings = errors.New("invalid widget settings")
+
+// WidgetTheme holds the colors of a booking widget as #rgb or #rrggbb.
+type WidgetTheme struct {
+	PrimaryColor    string `json:"primary_color"`
+	BackgroundColor string `json:"background_color"`
+	TextColor       string `json:"text_color"`
+}
+
+// WidgetSettings configure the booking widget a restaurant embeds into its website; a restaurant
+// that never saved any has the defaults.
+type WidgetSettings struct {
+	RestaurantID     string      `json:"restaurant_id"`
+	Theme            WidgetTheme `json:"theme"`
+	DefaultPartySize int         `json:"default_party_size"`
+	// Locale is the BCP 47 tag of the language of the widget, such as en or ru-RU.
+	Locale    string    `json:"locale"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func DefaultWidgetSettings(restaurantID string) *WidgetSettings {
+	return &WidgetSettings{
+		RestaurantID: restaurantID,
+		Theme: WidgetTheme{
+			PrimaryColor:    DefaultWidgetPrimaryColor,
+			BackgroundColor: DefaultWidgetBackgroundColor,
+			TextColor:       DefaultWidgetTextColor,
+		},
+		DefaultPartySize: DefaultWidgetPartySize,
+		Locale:           DefaultWidgetLocale,
+	}
+}
+
+// Normalize checks the settings, wrapping ErrInvalidWidgetSettings, and brings the colors to lower
+// case and the locale to its canonical form.
+func (s *WidgetSettings) Normalize() error {
+	for name, color := range map[string]*string{
+		"primary_color":    &s.Theme.PrimaryColor,
+		"background_color": &s.Theme.BackgroundColor,
+		"text_color":       &s.Theme.TextColor,
+	} {
+		normalized, ok := normalizeHexColor(*color)
+		if !ok {
+			return fmt.Errorf("%w: %s %q is not a #rgb or #rrggbb color", ErrInvalidWidgetSettings, name, *color)
+		}
+		*color = normalized
+	}
+
+	if s.DefaultPartySize < 1 || s.DefaultPartySize > MaxWidgetPartySize {
+		return fmt.Errorf("%w: default_party_size must be between 1 and %d", ErrInvalidWidgetSettings, MaxWidgetPartySize)
+	}
+
+	tag, err := language.Parse(s.Locale)
+	if err != nil {
+		return fmt.Errorf("%w: locale %q is not a BCP 47 language tag", ErrInvalidWidgetSettings, s.Locale)
+	}
+	s.Locale = tag.String()
+
+	return nil
+}
+
+func normalizeHexColor(value string) (string, bool) {
+	if (len(value) != 4 && len(value) != 7) || value[0] != '#' {
+		return "", false
+	}
+	b := []byte(value)
+	for i := 1; i < len(b); i++ {
+		switch c := b[i]; {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f':
+		case c >= 'A' && c <= 'F':
+			b[i] = c + 'a' - 'A'
+		default:
+			return "", false
+		}
+	}
+	return string(b), true
+}
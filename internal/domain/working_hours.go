@@ -38,3 +38,113 @@ type WorkingHours struct {
 	ValidFrom    time.Time `json:"valid_from"`
 	ValidTo      time.Time `json:"valid_to"`
 }
+
+// Validate checks the day of the week and, unless the restaurant is closed on it, the times of day
+// it opens and closes at; closing at or before opening means closing after midnight. The hours
+// must not stop being valid before they start.
+func (w *WorkingHours) Validate() error {
+	switch {
+	case w.WeekDay < Monday || w.WeekDay > Sunday:
+		return &ValidationError{Entity: "working hours", Field: "week_day", Reason: "must be from 1 to 7"}
+	case !w.IsClosed && !validClock(w.OpenTime):
+		return &ValidationError{Entity: "working hours", Field: "open_time", Reason: "is not a time of day"}
+	case !w.IsClosed && !validClock(w.CloseTime):
+		return &ValidationError{Entity: "working hours", Field: "close_time", Reason: "is not a time of day"}
+	case !w.ValidTo.IsZero() && w.ValidTo.Before(w.ValidFrom):
+		return &ValidationError{Entity: "working hours", Field: "valid_to", Reason: "cannot be before valid_from"}
+	}
+	return nil
+}
+
+// OpeningHours is a stretch of time a restaurant is open; it ends on the next day when the
+// restaurant stays open past midnight.
+type OpeningHours struct {
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+// ScheduleDay is when a restaurant is open on a date according to the working hours valid on it,
+// earliest opening first. A day without hours is closed; Closure is set when the restaurant is
+// closed for a closure on the date, whatever its working hours, and SpecialDay when a special day
+// replaces its working hours on the date.
+type ScheduleDay struct {
+	Date       time.Time          `json:"date"`
+	Hours      []OpeningHours     `json:"hours"`
+	Closure    *RestaurantClosure `json:"closure,omitempty"`
+	SpecialDay *SpecialDay        `json:"special_day,omitempty"`
+}
+
+func (d ScheduleDay) IsClosed() bool {
+	return len(d.Hours) == 0
+}
+
+// WeekDayOf returns the day of the week of t.
+func WeekDayOf(t time.Time) WeekDay {
+	return WeekDay((int(t.Weekday())+6)%7 + 1)
+}
+
+// OpeningHoursOn returns when the restaurant is open on the date, in the order of its working
+// hours: the hours of the weekday of the date that are valid on it and not closed. Hours with a
+// time of day that does not parse are skipped.
+func OpeningHoursOn(workingHours []*WorkingHours, date time.Time) []OpeningHours {
+	weekDay := WeekDayOf(date)
+
+	opening := make([]OpeningHours, 0)
+	for _, hours := range workingHours {
+		if hours.WeekDay != weekDay || hours.IsClosed {
+			continue
+		}
+		if !hours.ValidFrom.IsZero() && date.Before(startOfDay(hours.ValidFrom)) {
+			continue
+		}
+		if !hours.ValidTo.IsZero() && date.After(startOfDay(hours.ValidTo)) {
+			continue
+		}
+
+		if hours, ok := openingHoursAt(date, hours.OpenTime, hours.CloseTime); ok {
+			opening = append(opening, hours)
+		}
+	}
+
+	return opening
+}
+
+// openingHoursAt returns the stretch of the date from the time of day openTime to closeTime,
+// ending on the next day when closeTime is not after openTime; false when a time does not parse.
+func openingHoursAt(date time.Time, openTime, closeTime string) (OpeningHours, bool) {
+	open, err := time.Parse("15:04", openTime)
+	if err != nil {
+		return OpeningHours{}, false
+	}
+	closing, err := time.Parse("15:04", closeTime)
+	if err != nil {
+		return OpeningHours{}, false
+	}
+
+	opensAt := date.Add(time.Duration(open.Hour())*time.Hour + time.Duration(open.Minute())*time.Minute)
+	closesAt := date.Add(time.Duration(closing.Hour())*time.Hour + time.Duration(closing.Minute())*time.Minute)
+	if !closesAt.After(opensAt) {
+		closesAt = closesAt.Add(24 * time.Hour)
+	}
+	return OpeningHours{OpensAt: opensAt, ClosesAt: closesAt}, true
+}
+
+// IsOpenAt reports whether the restaurant is open at the time of day of t by its schedule, also
+// when it opened the day before and stays open past midnight. Each of the two days is resolved as
+// ScheduleOn does: a closure closes it, a special day replaces its working hours.
+func IsOpenAt(workingHours []*WorkingHours, specialDays []*SpecialDay, closures []*RestaurantClosure, t time.Time) bool {
+	at := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	today := startOfDay(t)
+	for _, date := range []time.Time{today, today.AddDate(0, 0, -1)} {
+		for _, hours := range ScheduleOn(workingHours, specialDays, closures, date).Hours {
+			if !at.Before(hours.OpensAt) && at.Before(hours.ClosesAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,59 @@
+package geo
+
+import "strings"
+
+type countryDefaults struct {
+	currency string
+	locale   string
+}
+
+// defaultsByCountry holds the currency and locale of the countries guests come from most; the
+// rest get neither and fall back to the defaults of the service.
+var defaultsByCountry = map[string]countryDefaults{
+	"AE": {currency: "AED", locale: "ar"},
+	"AM": {currency: "AMD", locale: "hy"},
+	"AT": {currency: "EUR", locale: "de"},
+	"AU": {currency: "AUD", locale: "en"},
+	"AZ": {currency: "AZN", locale: "az"},
+	"BE": {currency: "EUR", locale: "nl"},
+	"BR": {currency: "BRL", locale: "pt"},
+	"BY": {currency: "BYN", locale: "ru"},
+	"CA": {currency: "CAD", locale: "en"},
+	"CH": {currency: "CHF", locale: "de"},
+	"CN": {currency: "CNY", locale: "zh"},
+	"CZ": {currency: "CZK", locale: "cs"},
+	"DE": {currency: "EUR", locale: "de"},
+	"DK": {currency: "DKK", locale: "da"},
+	"ES": {currency: "EUR", locale: "es"},
+	"FI": {currency: "EUR", locale: "fi"},
+	"FR": {currency: "EUR", locale: "fr"},
+	"GB": {currency: "GBP", locale: "en"},
+	"GE": {currency: "GEL", locale: "ka"},
+	"GR": {currency: "EUR", locale: "el"},
+	"IE": {currency: "EUR", locale: "en"},
+	"IN": {currency: "INR", locale: "en"},
+	"IT": {currency: "EUR", locale: "it"},
+	"JP": {currency: "JPY", locale: "ja"},
+	"KG": {currency: "KGS", locale: "ru"},
+	"KR": {currency: "KRW", locale: "ko"},
+	"KZ": {currency: "KZT", locale: "ru"},
+	"MX": {currency: "MXN", locale: "es"},
+	"NL": {currency: "EUR", locale: "nl"},
+	"NO": {currency: "NOK", locale: "nb"},
+	"PL": {currency: "PLN", locale: "pl"},
+	"PT": {currency: "EUR", locale: "pt"},
+	"RS": {currency: "RSD", locale: "sr"},
+	"RU": {currency: "RUB", locale: "ru"},
+	"SE": {currency: "SEK", locale: "sv"},
+	"TR": {currency: "TRY", locale: "tr"},
+	"UA": {currency: "UAH", locale: "uk"},
+	"US": {currency: "USD", locale: "en"},
+	"UZ": {currency: "UZS", locale: "uz"},
+}
+
+// CountryDefaults returns the currency and locale of the country, or empty strings for a country
+// without known defaults.
+func CountryDefaults(countryCode string) (currency, locale string) {
+	defaults := defaultsByCountry[strings.ToUpper(countryCode)]
+	return defaults.currency, defaults.locale
+}
@@ -0,0 +1,50 @@
+// Package geo locates the client of a request by its address and derives the defaults a client
+// without explicit preferences gets from its location: where to search for restaurants, which
+// currency to display prices in and which locale to use.
+package geo
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotFound = errors.New("location not found")
+
+// Location is where a client is, as far as its address tells, with the defaults of its country.
+type Location struct {
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country.
+	CountryCode string
+	City        string
+	Latitude    float64
+	Longitude   float64
+	// HasCoordinates tells a location at 0,0 from one whose coordinates are unknown.
+	HasCoordinates bool
+
+	Currency string
+	Locale   string
+}
+
+// Locator finds the location of an address. It returns ErrNotFound for addresses it knows nothing
+// about, such as private ones.
+type Locator interface {
+	Locate(ip string) (*Location, error)
+}
+
+type NopLocator struct{}
+
+func (NopLocator) Locate(string) (*Location, error) {
+	return nil, ErrNotFound
+}
+
+type locationKey struct{}
+
+func NewContext(ctx context.Context, location *Location) context.Context {
+	return context.WithValue(ctx, locationKey{}, location)
+}
+
+// FromContext returns the location of the client of the request; it is missing when the address
+// could not be located and in background jobs.
+func FromContext(ctx context.Context) (*Location, bool) {
+	location, ok := ctx.Value(locationKey{}).(*Location)
+	return location, ok && location != nil
+}
@@ -0,0 +1,68 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrAddressNotFound is returned by a Geocoder for addresses it cannot place; trying again
+	// does not help until the address changes.
+	ErrAddressNotFound = errors.New("address not found")
+
+	ErrUnknownGeocoder = errors.New("unknown geocoding provider")
+)
+
+// geocodingTimeout bounds a request to a geocoding provider.
+const geocodingTimeout = 10 * time.Second
+
+// Address is an address as a geocoding provider understood it, split into its components, with
+// the coordinates of the place.
+type Address struct {
+	Formatted   string
+	Street      string
+	HouseNumber string
+	City        string
+	Region      string
+	PostalCode  string
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country.
+	CountryCode string
+	Latitude    float64
+	Longitude   float64
+}
+
+// Geocoder places a free-form address. It returns ErrAddressNotFound when the provider has no
+// match and other errors when the provider could not be asked, which may pass.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*Address, error)
+
+	// Provider names the geocoding service, e.g. "nominatim".
+	Provider() string
+}
+
+// GeocoderSettings configure the geocoding providers.
+type GeocoderSettings struct {
+	// NominatimURL is the Nominatim server to use, e.g. "https://nominatim.openstreetmap.org".
+	NominatimURL string
+	// UserAgent identifies the application to Nominatim, whose usage policy requires one.
+	UserAgent string
+	// GoogleAPIKey is the key of the Google Geocoding API.
+	GoogleAPIKey string
+	// Language is the preferred language of the components, e.g. "en".
+	Language string
+}
+
+// NewGeocoder returns the geocoder of the provider, "nominatim" or "google".
+func NewGeocoder(provider string, settings GeocoderSettings) (Geocoder, error) {
+	client := &http.Client{Timeout: geocodingTimeout}
+
+	switch provider {
+	case ProviderNominatim:
+		return NewNominatimGeocoder(client, settings.NominatimURL, settings.UserAgent, settings.Language), nil
+	case ProviderGoogle:
+		return NewGoogleGeocoder(client, GoogleGeocodeURL, settings.GoogleAPIKey, settings.Language), nil
+	}
+	return nil, ErrUnknownGeocoder
+}
@@ -0,0 +1,126 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+const (
+	ProviderGoogle = "google"
+
+	GoogleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+)
+
+// GoogleGeocoder geocodes with the Google Geocoding API.
+type GoogleGeocoder struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	language string
+}
+
+// NewGoogleGeocoder creates the geocoder; endpoint is GoogleGeocodeURL or another server speaking
+// the same API.
+func NewGoogleGeocoder(client *http.Client, endpoint, apiKey, language string) *GoogleGeocoder {
+	return &GoogleGeocoder{
+		client:   client,
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		language: language,
+	}
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		FormattedAddress  string `json:"formatted_address"`
+		AddressComponents []struct {
+			LongName  string   `json:"long_name"`
+			ShortName string   `json:"short_name"`
+			Types     []string `json:"types"`
+		} `json:"address_components"`
+		Geometry struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func (g *GoogleGeocoder) Provider() string {
+	return ProviderGoogle
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) (*Address, error) {
+	query := url.Values{
+		"address": {address},
+		"key":     {g.apiKey},
+	}
+	if g.language != "" {
+		query.Set("language", g.language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create google geocoding request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("google geocoding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google geocoding responded with status %d", resp.StatusCode)
+	}
+
+	var body googleGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode google geocoding response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrAddressNotFound
+	default:
+		return nil, fmt.Errorf("google geocoding status %s: %s", body.Status, body.ErrorMessage)
+	}
+	if len(body.Results) == 0 {
+		return nil, ErrAddressNotFound
+	}
+
+	result := body.Results[0]
+	geocoded := &Address{
+		Formatted: result.FormattedAddress,
+		Latitude:  result.Geometry.Location.Lat,
+		Longitude: result.Geometry.Location.Lng,
+	}
+	for _, component := range result.AddressComponents {
+		switch {
+		case slices.Contains(component.Types, "route"):
+			geocoded.Street = component.LongName
+		case slices.Contains(component.Types, "street_number"):
+			geocoded.HouseNumber = component.LongName
+		case slices.Contains(component.Types, "locality"):
+			geocoded.City = component.LongName
+		case slices.Contains(component.Types, "postal_town") && geocoded.City == "":
+			geocoded.City = component.LongName
+		case slices.Contains(component.Types, "administrative_area_level_1"):
+			geocoded.Region = component.LongName
+		case slices.Contains(component.Types, "postal_code"):
+			geocoded.PostalCode = component.LongName
+		case slices.Contains(component.Types, "country"):
+			geocoded.CountryCode = component.ShortName
+		}
+	}
+
+	return geocoded, nil
+}
@@ -0,0 +1,70 @@
+package geo
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// cityRecord is the part of a GeoLite2 or GeoIP2 City record the locator reads. Country databases
+// have no city and location, which are then left empty.
+type cityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// MaxMindLocator locates addresses with a MaxMind database file in the MMDB format.
+type MaxMindLocator struct {
+	reader *maxminddb.Reader
+}
+
+func OpenMaxMind(path string) (*MaxMindLocator, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open geoip database %s: %w", path, err)
+	}
+
+	return &MaxMindLocator{reader: reader}, nil
+}
+
+func (l *MaxMindLocator) Locate(ip string) (*Location, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, ErrNotFound
+	}
+
+	var record cityRecord
+	_, found, err := l.reader.LookupNetwork(addr, &record)
+	if err != nil {
+		return nil, fmt.Errorf("look up %s: %w", ip, err)
+	}
+	if !found || record.Country.ISOCode == "" {
+		return nil, ErrNotFound
+	}
+
+	location := &Location{
+		CountryCode: record.Country.ISOCode,
+		City:        record.City.Names["en"],
+	}
+	if record.Location.Latitude != nil && record.Location.Longitude != nil {
+		location.Latitude = *record.Location.Latitude
+		location.Longitude = *record.Location.Longitude
+		location.HasCoordinates = true
+	}
+	location.Currency, location.Locale = CountryDefaults(location.CountryCode)
+
+	return location, nil
+}
+
+func (l *MaxMindLocator) Close() error {
+	return l.reader.Close()
+}
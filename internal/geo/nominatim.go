@@ -0,0 +1,159 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ProviderNominatim = "nominatim"
+
+	// nominatimInterval is the pause between requests the usage policy of the public Nominatim
+	// server asks for.
+	nominatimInterval = time.Second
+)
+
+// NominatimGeocoder geocodes with the search API of Nominatim, the OpenStreetMap geocoder.
+type NominatimGeocoder struct {
+	client    *http.Client
+	baseURL   string
+	userAgent string
+	language  string
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func NewNominatimGeocoder(client *http.Client, baseURL, userAgent, language string) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		client:    client,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		userAgent: userAgent,
+		language:  language,
+	}
+}
+
+type nominatimPlace struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Address     struct {
+		Road         string `json:"road"`
+		Pedestrian   string `json:"pedestrian"`
+		HouseNumber  string `json:"house_number"`
+		City         string `json:"city"`
+		Town         string `json:"town"`
+		Village      string `json:"village"`
+		Municipality string `json:"municipality"`
+		State        string `json:"state"`
+		Postcode     string `json:"postcode"`
+		CountryCode  string `json:"country_code"`
+	} `json:"address"`
+}
+
+func (g *NominatimGeocoder) Provider() string {
+	return ProviderNominatim
+}
+
+func (g *NominatimGeocoder) Geocode(ctx context.Context, address string) (*Address, error) {
+	query := url.Values{
+		"q":              {address},
+		"format":         {"jsonv2"},
+		"addressdetails": {"1"},
+		"limit":          {"1"},
+	}
+	if g.language != "" {
+		query.Set("accept-language", g.language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create nominatim request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("nominatim responded with status %d", resp.StatusCode)
+	}
+
+	var places []nominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("decode nominatim response: %w", err)
+	}
+	if len(places) == 0 {
+		return nil, ErrAddressNotFound
+	}
+
+	return places[0].toAddress()
+}
+
+// wait keeps requests nominatimInterval apart.
+func (g *NominatimGeocoder) wait(ctx context.Context) error {
+	g.mu.Lock()
+	next := g.last.Add(nominatimInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	g.last = next
+	g.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p nominatimPlace) toAddress() (*Address, error) {
+	lat, err := strconv.ParseFloat(p.Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim latitude %q: %w", p.Lat, err)
+	}
+	lon, err := strconv.ParseFloat(p.Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("nominatim longitude %q: %w", p.Lon, err)
+	}
+
+	return &Address{
+		Formatted:   p.DisplayName,
+		Street:      firstNonEmpty(p.Address.Road, p.Address.Pedestrian),
+		HouseNumber: p.Address.HouseNumber,
+		City:        firstNonEmpty(p.Address.City, p.Address.Town, p.Address.Village, p.Address.Municipality),
+		Region:      p.Address.State,
+		PostalCode:  p.Address.Postcode,
+		CountryCode: strings.ToUpper(p.Address.CountryCode),
+		Latitude:    lat,
+		Longitude:   lon,
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
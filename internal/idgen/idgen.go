@@ -0,0 +1,40 @@
+// Package idgen generates the identifiers of new records. Repositories and use cases take a
+// Generator, so that tests and fixtures can have predictable identifiers and the format of
+// identifiers can change without touching them.
+package idgen
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+)
+
+// Generator returns a new identifier on every call. It is safe for concurrent use.
+type Generator interface {
+	NewID() string
+}
+
+// UUID generates UUIDv7 identifiers. Their leading bits are the time they were generated at, so
+// they sort in the order their records were created and new rows are appended to the end of
+// primary key indexes instead of being spread across them.
+type UUID struct{}
+
+func (UUID) NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// Sequence generates the identifiers 00000000-0000-7000-8000-000000000001,
+// 00000000-0000-7000-8000-000000000002 and so on. They are valid UUIDv7 that sort in the order
+// they were generated, the same on every run; they are unique within a sequence only.
+type Sequence struct {
+	last atomic.Uint64
+}
+
+func NewSequence() *Sequence {
+	return &Sequence{}
+}
+
+func (s *Sequence) NewID() string {
+	return fmt.Sprintf("00000000-0000-7000-8000-%012x", s.last.Add(1))
+}
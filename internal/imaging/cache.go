@@ -0,0 +1,200 @@
+package imaging
+
+import (
+	"container/list"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const tempFilePrefix = ".tmp-"
+
+// DiskCache keeps encoded variants as files in one directory and removes the least recently used
+// ones once their total size exceeds the limit. After a restart the files found in the directory
+// are taken over, oldest written first in line for removal.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewDiskCache opens the cache in dir, creating the directory when needed. A maxBytes of zero or
+// less turns caching off: Get always misses and Put stores nothing.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	cache := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	if maxBytes <= 0 {
+		return cache, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type found struct {
+		info fs.FileInfo
+		name string
+	}
+	existing := make([]found, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(file.Name(), tempFilePrefix) {
+			// Left behind by a write that did not finish.
+			_ = os.Remove(filepath.Join(dir, file.Name()))
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		existing = append(existing, found{info: info, name: file.Name()})
+	}
+	slices.SortFunc(existing, func(a, b found) int {
+		return b.info.ModTime().Compare(a.info.ModTime())
+	})
+
+	for _, file := range existing {
+		cache.entries[file.name] = cache.order.PushBack(&cacheEntry{key: file.name, size: file.info.Size()})
+		cache.size += file.info.Size()
+	}
+	cache.evict()
+
+	return cache, nil
+}
+
+// Get returns the cached variant stored under the key.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	element, ok := c.entries[key]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		c.remove(key)
+		return nil, false
+	}
+	return data, true
+}
+
+// Put stores the variant under the key, which must be usable as a file name. Variants larger than
+// the whole cache are not stored.
+func (c *DiskCache) Put(key string, data []byte) error {
+	if int64(len(data)) > c.maxBytes {
+		return nil
+	}
+	if key == "" || key != filepath.Base(key) || strings.HasPrefix(key, tempFilePrefix) {
+		return errors.New("invalid cache key")
+	}
+
+	file, err := os.CreateTemp(c.dir, tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.size -= element.Value.(*cacheEntry).size
+		c.order.Remove(element)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, size: int64(len(data))})
+	c.size += int64(len(data))
+	c.evict()
+
+	return nil
+}
+
+// RemovePrefix drops every variant whose key starts with the prefix, such as all the variants of
+// a deleted image.
+func (c *DiskCache) RemovePrefix(prefix string) {
+	c.mu.Lock()
+	keys := make([]string, 0)
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		c.remove(key)
+	}
+}
+
+// Size returns the total size of the cached variants in bytes.
+func (c *DiskCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *DiskCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.drop(element)
+	}
+}
+
+// evict removes the least recently used variants until the cache fits its limit. The caller holds
+// the lock.
+func (c *DiskCache) evict() {
+	for c.size > c.maxBytes {
+		element := c.order.Back()
+		if element == nil {
+			return
+		}
+		c.drop(element)
+	}
+}
+
+func (c *DiskCache) drop(element *list.Element) {
+	entry := element.Value.(*cacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+	_ = os.Remove(filepath.Join(c.dir, entry.key))
+}
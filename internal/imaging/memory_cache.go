@@ -0,0 +1,96 @@
+package imaging
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// MemoryCache keeps encoded variants in process memory and removes the least recently used ones
+// once their total size exceeds the limit. Nothing is kept across restarts, which suits instances
+// without a writable disk.
+type MemoryCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key  string
+	data []byte
+}
+
+// NewMemoryCache returns an empty cache. A maxBytes of zero or less turns caching off: Get always
+// misses and Put stores nothing.
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached variant stored under the key.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).data, true
+}
+
+// Put stores the variant under the key. Variants larger than the whole cache are not stored.
+func (c *MemoryCache) Put(key string, data []byte) error {
+	if int64(len(data)) > c.maxBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.drop(element)
+	}
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, data: data})
+	c.size += int64(len(data))
+	for c.size > c.maxBytes {
+		c.drop(c.order.Back())
+	}
+
+	return nil
+}
+
+// RemovePrefix drops every variant whose key starts with the prefix, such as all the variants of
+// a deleted image.
+func (c *MemoryCache) RemovePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.drop(element)
+		}
+	}
+}
+
+// Size returns the total size of the cached variants in bytes.
+func (c *MemoryCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// drop removes the variant of the element. The caller holds the lock.
+func (c *MemoryCache) drop(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.size -= int64(len(entry.data))
+}
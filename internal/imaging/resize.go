@@ -0,0 +1,268 @@
+// Package imaging resizes and crops the images uploaded for restaurants and keeps the resized
+// variants in a disk cache. JPEG, PNG and GIF are read with the standard library codecs. Unless
+// another format is asked for, JPEG sources are written back as JPEG and everything else as PNG;
+// lossless WebP is available too, and encoders for other formats can be registered.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"sync"
+
+	// Registers the GIF decoder; only the first frame of an animation is used.
+	_ "image/gif"
+)
+
+// Fit is how an image is brought into the requested box.
+type Fit string
+
+const (
+	// FitContain scales the image down to fit inside the box, keeping its aspect ratio.
+	FitContain Fit = "contain"
+	// FitCover scales the image down to cover the box and crops what sticks out, centred.
+	FitCover Fit = "cover"
+	// FitFill stretches the image to the box, ignoring its aspect ratio.
+	FitFill Fit = "fill"
+)
+
+const jpegQuality = 85
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeWebP = "image/webp"
+	ContentTypeAVIF = "image/avif"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrInvalidFit        = errors.New("invalid fit")
+)
+
+// EncodeFunc writes an image in one format.
+type EncodeFunc func(img image.Image) ([]byte, error)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]EncodeFunc{
+		ContentTypeJPEG: func(img image.Image) ([]byte, error) {
+			var buf bytes.Buffer
+			err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+			return buf.Bytes(), err
+		},
+		ContentTypePNG: func(img image.Image) ([]byte, error) {
+			var buf bytes.Buffer
+			err := png.Encode(&buf, img)
+			return buf.Bytes(), err
+		},
+		ContentTypeWebP: EncodeWebP,
+	}
+)
+
+// RegisterEncoder makes the format with the content type available to Resize, e.g. an AVIF
+// encoder built on a native library. It replaces the encoder registered for the type before.
+func RegisterEncoder(contentType string, encode EncodeFunc) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[contentType] = encode
+}
+
+// CanEncode reports whether images can be written with the content type.
+func CanEncode(contentType string) bool {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	_, ok := encoders[contentType]
+	return ok
+}
+
+// ParseFit returns the fit with the given name; an empty name means FitContain.
+func ParseFit(name string) (Fit, error) {
+	switch fit := Fit(name); fit {
+	case "":
+		return FitContain, nil
+	case FitContain, FitCover, FitFill:
+		return fit, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidFit, name)
+	}
+}
+
+// Options describe a variant of an image. A zero Width or Height leaves that side to follow from
+// the aspect ratio; images are never scaled up. An empty Format keeps JPEG images JPEG and writes
+// others as PNG.
+type Options struct {
+	Width  int
+	Height int
+	Fit    Fit
+	Format string
+}
+
+// Original reports whether the options ask for the image as it was uploaded.
+func (o Options) Original() bool {
+	return o.Width == 0 && o.Height == 0
+}
+
+// Key names the variant of the image with the given ID, e.g. for caching.
+func (o Options) Key(imageID string) string {
+	fit := o.Fit
+	if fit == "" {
+		fit = FitContain
+	}
+	key := fmt.Sprintf("%s_%dx%d_%s", imageID, o.Width, o.Height, fit)
+	if o.Format != "" {
+		key += "_" + strings.TrimPrefix(o.Format, "image/")
+	}
+	return key
+}
+
+// Resize decodes the image, brings it to the size the options ask for and encodes it again. It
+// returns the encoded variant and its content type.
+func Resize(data []byte, opts Options) ([]byte, string, error) {
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+
+	contentType := opts.Format
+	switch {
+	case contentType != "":
+	case format == "jpeg":
+		contentType = ContentTypeJPEG
+	default:
+		contentType = ContentTypePNG
+	}
+
+	encodersMu.RLock()
+	encode, ok := encoders[contentType]
+	encodersMu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("%w: no encoder for %s", ErrUnsupportedFormat, contentType)
+	}
+
+	encoded, err := encode(transform(src, opts))
+	if err != nil {
+		return nil, "", err
+	}
+	return encoded, contentType, nil
+}
+
+func transform(src image.Image, opts Options) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if opts.Original() || srcW == 0 || srcH == 0 {
+		return src
+	}
+
+	w, h := opts.Width, opts.Height
+	switch {
+	case w == 0:
+		w = max(1, srcW*h/srcH)
+	case h == 0:
+		h = max(1, srcH*w/srcW)
+	}
+
+	crop := bounds
+	switch opts.Fit {
+	case FitFill:
+		w, h = min(w, srcW), min(h, srcH)
+	case FitCover:
+		// The largest centred part of the source with the aspect ratio of the box.
+		cropW, cropH := srcW, srcW*h/w
+		if cropH > srcH {
+			cropW, cropH = srcH*w/h, srcH
+		}
+		cropW, cropH = max(1, cropW), max(1, cropH)
+		x0 := bounds.Min.X + (srcW-cropW)/2
+		y0 := bounds.Min.Y + (srcH-cropH)/2
+		crop = image.Rect(x0, y0, x0+cropW, y0+cropH)
+		if w > cropW {
+			w, h = cropW, max(1, h*cropW/w)
+		}
+	default:
+		scaleW, scaleH := float64(w)/float64(srcW), float64(h)/float64(srcH)
+		scale := min(scaleW, scaleH, 1)
+		w = max(1, int(float64(srcW)*scale+0.5))
+		h = max(1, int(float64(srcH)*scale+0.5))
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, crop.Min, draw.Src)
+	if w == crop.Dx() && h == crop.Dy() {
+		return rgba
+	}
+	return downscale(rgba, w, h)
+}
+
+// axisWeights holds, for every pixel of the destination along one axis, the first source pixel
+// it covers and how much of each covered source pixel goes into it.
+type axisWeights struct {
+	first   int
+	weights []float64
+}
+
+// boxWeights splits n source pixels evenly over m destination pixels (m <= n). Every destination
+// pixel is the average of the source pixels it covers, partially covered pixels counting by the
+// covered fraction, which avoids the aliasing of nearest neighbour sampling when shrinking.
+func boxWeights(n, m int) []axisWeights {
+	ratio := float64(n) / float64(m)
+	result := make([]axisWeights, m)
+	for i := range result {
+		start, end := float64(i)*ratio, float64(i+1)*ratio
+		first := int(start)
+		last := min(n-1, int(end-1e-9))
+
+		weights := make([]float64, 0, last-first+1)
+		for j := first; j <= last; j++ {
+			covered := min(end, float64(j+1)) - max(start, float64(j))
+			weights = append(weights, covered/ratio)
+		}
+		result[i] = axisWeights{first: first, weights: weights}
+	}
+	return result
+}
+
+// downscale shrinks the image to w×h with a box filter, first along rows and then along columns.
+// Colours are premultiplied, so transparent pixels do not bleed into their neighbours.
+func downscale(src *image.RGBA, w, h int) *image.RGBA {
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	columns, rows := boxWeights(srcW, w), boxWeights(srcH, h)
+
+	tmp := make([]float64, w*srcH*4)
+	for y := 0; y < srcH; y++ {
+		line := src.Pix[y*src.Stride:]
+		for x, column := range columns {
+			var acc [4]float64
+			for k, weight := range column.weights {
+				p := line[(column.first+k)*4:]
+				for c := range acc {
+					acc[c] += float64(p[c]) * weight
+				}
+			}
+			copy(tmp[(y*w+x)*4:], acc[:])
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y, row := range rows {
+		out := dst.Pix[y*dst.Stride:]
+		for x := 0; x < w; x++ {
+			var acc [4]float64
+			for k, weight := range row.weights {
+				p := tmp[((row.first+k)*w+x)*4:]
+				for c := range acc {
+					acc[c] += p[c] * weight
+				}
+			}
+			for c, v := range acc {
+				out[x*4+c] = uint8(min(255, v+0.5))
+			}
+		}
+	}
+	return dst
+}
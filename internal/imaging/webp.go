@@ -0,0 +1,414 @@
+package imaging
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"sort"
+)
+
+// WebP lossless (VP8L, RFC 9649) bitstream constants.
+const (
+	vp8lSignature      = 0x2f
+	vp8lMaxDimension   = 1 << 14
+	vp8lMaxCodeLength  = 15
+	vp8lMaxCodeLenCode = 7
+
+	vp8lTransformPredictor     = 0
+	vp8lTransformSubtractGreen = 2
+
+	// vp8lPredictorBits is the largest predictor block, 512×512 pixels; every block uses the same mode.
+	vp8lPredictorBits = 9
+	// vp8lPredictorGradient is ClampAddSubtractFull(L, T, TL), which suits photos and gradients.
+	vp8lPredictorGradient = 12
+
+	vp8lGreenAlphabet    = 256 + 24
+	vp8lLiteralAlphabet  = 256
+	vp8lDistanceAlphabet = 40
+)
+
+// vp8lCodeLengthOrder is the order the lengths of the code length code are written in.
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+var ErrImageTooLarge = errors.New("image is too large for the format")
+
+// EncodeWebP writes the image as lossless WebP. The encoder is deliberately simple: it applies the
+// subtract green and gradient predictor transforms and Huffman codes the residuals without
+// backward references, which is fast and usually beats PNG, though not lossy JPEG for photos.
+func EncodeWebP(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 || w > vp8lMaxDimension || h > vp8lMaxDimension {
+		return nil, ErrImageTooLarge
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+
+	alphaUsed := false
+	for i := 3; i < len(nrgba.Pix); i += 4 {
+		if nrgba.Pix[i] != 0xff {
+			alphaUsed = true
+			break
+		}
+	}
+
+	residuals := vp8lResiduals(nrgba)
+
+	var bw bitWriter
+	bw.writeBits(vp8lSignature, 8)
+	bw.writeBits(uint32(w-1), 14)
+	bw.writeBits(uint32(h-1), 14)
+	if alphaUsed {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3) // version
+
+	bw.writeBits(1, 1)
+	bw.writeBits(vp8lTransformSubtractGreen, 2)
+
+	bw.writeBits(1, 1)
+	bw.writeBits(vp8lTransformPredictor, 2)
+	bw.writeBits(vp8lPredictorBits-2, 3)
+	// The predictor modes form an image of their own with one pixel per block. Every block uses
+	// the same mode, so every code of it has a single symbol and the pixels take no bits.
+	bw.writeBits(0, 1) // no colour cache
+	writeSimpleCode(&bw, []int{vp8lPredictorGradient})
+	for range 4 {
+		writeSimpleCode(&bw, []int{0})
+	}
+
+	bw.writeBits(0, 1) // no more transforms
+	bw.writeBits(0, 1) // no colour cache
+	bw.writeBits(0, 1) // no meta prefix codes
+
+	counts := [4][]int{
+		make([]int, vp8lGreenAlphabet),
+		make([]int, vp8lLiteralAlphabet),
+		make([]int, vp8lLiteralAlphabet),
+		make([]int, vp8lLiteralAlphabet),
+	}
+	for i := 0; i < len(residuals); i += 4 {
+		// Residuals are stored as R, G, B, A; codes are written as green, red, blue, alpha.
+		counts[0][residuals[i+1]]++
+		counts[1][residuals[i]]++
+		counts[2][residuals[i+2]]++
+		counts[3][residuals[i+3]]++
+	}
+
+	var codes [4]prefixCode
+	for i := range codes {
+		codes[i] = writePrefixCode(&bw, counts[i])
+	}
+	writeSimpleCode(&bw, []int{0}) // distances, unused without backward references
+
+	for i := 0; i < len(residuals); i += 4 {
+		codes[0].write(&bw, int(residuals[i+1]))
+		codes[1].write(&bw, int(residuals[i]))
+		codes[2].write(&bw, int(residuals[i+2]))
+		codes[3].write(&bw, int(residuals[i+3]))
+	}
+
+	return riffWebP(bw.bytes()), nil
+}
+
+// vp8lResiduals applies the subtract green transform and then the gradient predictor, returning
+// the residuals in R, G, B, A order.
+func vp8lResiduals(img *image.NRGBA) []byte {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+
+	pix := make([]byte, w*h*4)
+	for y := 0; y < h; y++ {
+		copy(pix[y*w*4:(y+1)*w*4], img.Pix[y*img.Stride:])
+	}
+	for i := 0; i < len(pix); i += 4 {
+		pix[i] -= pix[i+1]
+		pix[i+2] -= pix[i+1]
+	}
+
+	residuals := make([]byte, len(pix))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := (y*w + x) * 4
+			var predicted [4]byte
+			switch {
+			case x == 0 && y == 0:
+				predicted = [4]byte{0, 0, 0, 0xff}
+			case y == 0:
+				copy(predicted[:], pix[i-4:i])
+			case x == 0:
+				copy(predicted[:], pix[i-w*4:i-w*4+4])
+			default:
+				left, top, topLeft := pix[i-4:i], pix[i-w*4:i-w*4+4], pix[i-w*4-4:i-w*4]
+				for c := range predicted {
+					predicted[c] = byte(min(255, max(0, int(left[c])+int(top[c])-int(topLeft[c]))))
+				}
+			}
+			for c := range predicted {
+				residuals[i+c] = pix[i+c] - predicted[c]
+			}
+		}
+	}
+	return residuals
+}
+
+func riffWebP(vp8l []byte) []byte {
+	padded := len(vp8l) + len(vp8l)%2
+	out := make([]byte, 0, 20+padded)
+	out = append(out, "RIFF"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(4+8+padded))
+	out = append(out, "WEBP"...)
+	out = append(out, "VP8L"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(vp8l)))
+	out = append(out, vp8l...)
+	if len(vp8l)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+// prefixCode holds the bit-reversed canonical code of every symbol, ready to be written LSB first.
+type prefixCode struct {
+	codes   []uint32
+	lengths []int
+}
+
+func (p prefixCode) write(bw *bitWriter, symbol int) {
+	if n := p.lengths[symbol]; n > 0 {
+		bw.writeBits(p.codes[symbol], n)
+	}
+}
+
+// writePrefixCode writes the code for the symbol counts and returns it. One or two symbols below
+// 256 use the simple form, anything else the normal form with literal code lengths.
+func writePrefixCode(bw *bitWriter, counts []int) prefixCode {
+	used := make([]int, 0, 2)
+	for symbol, count := range counts {
+		if count > 0 {
+			used = append(used, symbol)
+			if len(used) > 2 {
+				break
+			}
+		}
+	}
+	if len(used) == 0 {
+		used = append(used, 0)
+	}
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		return writeSimpleCode(bw, used)
+	}
+
+	lengths := huffmanLengths(counts, vp8lMaxCodeLength)
+
+	lengthCounts := make([]int, 19)
+	for _, n := range lengths {
+		lengthCounts[n]++
+	}
+	lengthCodeLengths := huffmanLengths(lengthCounts, vp8lMaxCodeLenCode)
+	lengthCode := canonicalCode(lengthCodeLengths)
+
+	bw.writeBits(0, 1) // normal code
+	bw.writeBits(uint32(len(vp8lCodeLengthOrder)-4), 4)
+	for _, symbol := range vp8lCodeLengthOrder {
+		bw.writeBits(uint32(lengthCodeLengths[symbol]), 3)
+	}
+	bw.writeBits(0, 1) // every symbol has its length written
+	for _, n := range lengths {
+		lengthCode.write(bw, n)
+	}
+
+	return canonicalCode(lengths)
+}
+
+// writeSimpleCode writes a code of one or two symbols below 256. A single symbol takes no bits.
+func writeSimpleCode(bw *bitWriter, symbols []int) prefixCode {
+	sorted := append([]int(nil), symbols...)
+	sort.Ints(sorted)
+
+	bw.writeBits(1, 1) // simple code
+	bw.writeBits(uint32(len(sorted)-1), 1)
+	if sorted[0] <= 1 {
+		bw.writeBits(0, 1)
+		bw.writeBits(uint32(sorted[0]), 1)
+	} else {
+		bw.writeBits(1, 1)
+		bw.writeBits(uint32(sorted[0]), 8)
+	}
+	if len(sorted) == 2 {
+		bw.writeBits(uint32(sorted[1]), 8)
+	}
+
+	code := prefixCode{codes: make([]uint32, 256), lengths: make([]int, 256)}
+	if len(sorted) == 2 {
+		code.lengths[sorted[0]], code.lengths[sorted[1]] = 1, 1
+		code.codes[sorted[1]] = 1
+	}
+	return code
+}
+
+// canonicalCode assigns canonical codes to the lengths: shorter codes first, ties by symbol.
+func canonicalCode(lengths []int) prefixCode {
+	var lengthCount [vp8lMaxCodeLength + 1]uint32
+	for _, n := range lengths {
+		if n > 0 {
+			lengthCount[n]++
+		}
+	}
+
+	var next [vp8lMaxCodeLength + 2]uint32
+	var code uint32
+	for n := 1; n <= vp8lMaxCodeLength; n++ {
+		code = (code + lengthCount[n-1]) << 1
+		next[n] = code
+	}
+
+	result := prefixCode{codes: make([]uint32, len(lengths)), lengths: lengths}
+	for symbol, n := range lengths {
+		if n == 0 {
+			continue
+		}
+		result.codes[symbol] = reverseBits(next[n], n)
+		next[n]++
+	}
+	return result
+}
+
+func reverseBits(code uint32, n int) uint32 {
+	var reversed uint32
+	for range n {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	return reversed
+}
+
+// huffmanLengths returns the code lengths of a Huffman code for the counts, no longer than
+// maxLength. When the plain code is too deep, rare symbols are counted as more frequent until it
+// fits, which keeps the code complete. At least two symbols get a length.
+func huffmanLengths(counts []int, maxLength int) []int {
+	lengths := make([]int, len(counts))
+
+	used := 0
+	for _, count := range counts {
+		if count > 0 {
+			used++
+		}
+	}
+	switch used {
+	case 0:
+		lengths[0], lengths[1] = 1, 1
+		return lengths
+	case 1:
+		for symbol, count := range counts {
+			if count > 0 {
+				lengths[symbol] = 1
+				other := 0
+				if symbol == 0 {
+					other = 1
+				}
+				lengths[other] = 1
+				return lengths
+			}
+		}
+	}
+
+	for minCount := 1; ; minCount *= 2 {
+		adjusted := make([]int, len(counts))
+		for symbol, count := range counts {
+			if count > 0 {
+				adjusted[symbol] = max(count, minCount)
+			}
+		}
+		if buildHuffmanLengths(adjusted, lengths) <= maxLength {
+			return lengths
+		}
+	}
+}
+
+type huffmanNode struct {
+	count       int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].symbol < h[j].symbol
+}
+func (h huffmanHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x any)   { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() any {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
+
+// buildHuffmanLengths fills lengths with the depths of a Huffman tree over the non-zero counts and
+// returns the largest depth.
+func buildHuffmanLengths(counts, lengths []int) int {
+	nodes := make(huffmanHeap, 0, len(counts))
+	for symbol, count := range counts {
+		lengths[symbol] = 0
+		if count > 0 {
+			nodes = append(nodes, &huffmanNode{count: count, symbol: symbol})
+		}
+	}
+	heap.Init(&nodes)
+
+	next := len(counts)
+	for nodes.Len() > 1 {
+		a := heap.Pop(&nodes).(*huffmanNode)
+		b := heap.Pop(&nodes).(*huffmanNode)
+		heap.Push(&nodes, &huffmanNode{count: a.count + b.count, symbol: next, left: a, right: b})
+		next++
+	}
+
+	deepest := 0
+	var walk func(node *huffmanNode, depth int)
+	walk = func(node *huffmanNode, depth int) {
+		if node.left == nil {
+			lengths[node.symbol] = depth
+			deepest = max(deepest, depth)
+			return
+		}
+		walk(node.left, depth+1)
+		walk(node.right, depth+1)
+	}
+	walk(nodes[0], 0)
+	return deepest
+}
+
+// bitWriter packs bits least significant first, as VP8L reads them.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits int
+}
+
+func (b *bitWriter) writeBits(value uint32, n int) {
+	b.acc |= uint64(value) << b.nbits
+	b.nbits += n
+	for b.nbits >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.nbits -= 8
+	}
+}
+
+func (b *bitWriter) bytes() []byte {
+	if b.nbits > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.nbits = 0, 0
+	}
+	return b.buf
+}
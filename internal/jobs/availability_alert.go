@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// AvailabilityAlertJob tells the guests waiting for a table when one frees up, e.g. after a
+// cancellation, and expires the alerts whose date passed.
+type AvailabilityAlertJob struct {
+	availabilityAlertUseCase usecase.AvailabilityAlertUseCase
+	clock                    clock.Clock
+}
+
+func NewAvailabilityAlertJob(availabilityAlertUseCase usecase.AvailabilityAlertUseCase, clock clock.Clock) *AvailabilityAlertJob {
+	return &AvailabilityAlertJob{
+		availabilityAlertUseCase: availabilityAlertUseCase,
+		clock:                    clock,
+	}
+}
+
+func (j *AvailabilityAlertJob) Name() string {
+	return "availability_alerts"
+}
+
+func (j *AvailabilityAlertJob) Run(ctx context.Context) error {
+	_, err := j.availabilityAlertUseCase.CheckAlerts(ctx, j.clock.Now())
+	return err
+}
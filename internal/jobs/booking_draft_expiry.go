@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// BookingDraftExpiryJob deletes the booking drafts left alone for longer than their lifetime and
+// gives back the seats they held.
+type BookingDraftExpiryJob struct {
+	bookingDraftUseCase usecase.BookingDraftUseCase
+	clock               clock.Clock
+}
+
+func NewBookingDraftExpiryJob(bookingDraftUseCase usecase.BookingDraftUseCase, clock clock.Clock) *BookingDraftExpiryJob {
+	return &BookingDraftExpiryJob{
+		bookingDraftUseCase: bookingDraftUseCase,
+		clock:               clock,
+	}
+}
+
+func (j *BookingDraftExpiryJob) Name() string {
+	return "booking_draft_expiry"
+}
+
+func (j *BookingDraftExpiryJob) Run(ctx context.Context) error {
+	_, err := j.bookingDraftUseCase.ExpireDrafts(ctx, j.clock.Now())
+	return err
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// CacheWarmerJob writes the restaurants and the coming availability of the popular cities to the
+// read cache, replacing the values before they expire.
+type CacheWarmerJob struct {
+	cacheWarmerUseCase usecase.CacheWarmerUseCase
+	clock              clock.Clock
+}
+
+func NewCacheWarmerJob(cacheWarmerUseCase usecase.CacheWarmerUseCase, clock clock.Clock) *CacheWarmerJob {
+	return &CacheWarmerJob{
+		cacheWarmerUseCase: cacheWarmerUseCase,
+		clock:              clock,
+	}
+}
+
+func (j *CacheWarmerJob) Name() string {
+	return "cache_warmer"
+}
+
+func (j *CacheWarmerJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	report, err := j.cacheWarmerUseCase.WarmCache(ctx, j.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	log.Info(ctx, "cache warmed",
+		zap.Int("cities", report.Cities),
+		zap.Int("restaurants", report.Restaurants),
+		zap.Int("slots", report.Slots),
+		zap.Strings("failedCities", report.Failed))
+	return nil
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// DataRetentionJob purges and anonymizes the data past its retention period. In dry-run mode it
+// only reports, in the log and the audit entries, what it would have done.
+type DataRetentionJob struct {
+	retentionUseCase usecase.RetentionUseCase
+	dryRun           bool
+}
+
+func NewDataRetentionJob(retentionUseCase usecase.RetentionUseCase, dryRun bool) *DataRetentionJob {
+	return &DataRetentionJob{
+		retentionUseCase: retentionUseCase,
+		dryRun:           dryRun,
+	}
+}
+
+func (j *DataRetentionJob) Name() string {
+	return "data_retention"
+}
+
+func (j *DataRetentionJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	runs, err := j.retentionUseCase.ApplyRetention(tenant.SystemContext(ctx), j.dryRun)
+	for _, run := range runs {
+		log.Info(ctx, "data retention run finished",
+			zap.String("policy", string(run.Policy)),
+			zap.Bool("dryRun", run.DryRun),
+			zap.Time("cutoff", run.Cutoff),
+			zap.Int("affected", run.Affected))
+	}
+	return err
+}
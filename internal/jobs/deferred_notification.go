@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// DeferredNotificationJob delivers the notifications held back by quiet hours once they are due,
+// a batch per run.
+type DeferredNotificationJob struct {
+	notificationDeferralUseCase usecase.NotificationDeferralUseCase
+	batchSize                   int
+}
+
+func NewDeferredNotificationJob(notificationDeferralUseCase usecase.NotificationDeferralUseCase, batchSize int) *DeferredNotificationJob {
+	return &DeferredNotificationJob{
+		notificationDeferralUseCase: notificationDeferralUseCase,
+		batchSize:                   batchSize,
+	}
+}
+
+func (j *DeferredNotificationJob) Name() string {
+	return "deferred_notifications"
+}
+
+func (j *DeferredNotificationJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	report, err := j.notificationDeferralUseCase.DeliverDue(ctx, j.batchSize)
+	if report.Claimed > 0 {
+		log.Info(ctx, "deferred notifications handled",
+			zap.Int("claimed", report.Claimed),
+			zap.Int("delivered", report.Delivered),
+			zap.Int("deferred", report.Deferred),
+			zap.Int("dropped", report.Dropped))
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// FactOfTheDayJob rotates the fact of the day for the configured locales so that
+// the first request of a new day does not pay for the selection.
+type FactOfTheDayJob struct {
+	factsUseCase usecase.FactsUseCase
+	locales      []string
+}
+
+func NewFactOfTheDayJob(factsUseCase usecase.FactsUseCase, locales []string) *FactOfTheDayJob {
+	return &FactOfTheDayJob{
+		factsUseCase: factsUseCase,
+		locales:      locales,
+	}
+}
+
+func (j *FactOfTheDayJob) Name() string {
+	return "fact_of_the_day"
+}
+
+func (j *FactOfTheDayJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	var errs []error
+	for _, locale := range j.locales {
+		fact, err := j.factsUseCase.GetFactOfTheDay(ctx, locale)
+		if err != nil {
+			if errors.Is(err, usecase.ErrNoFactsAvailable) {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		log.Debug(ctx, "fact of the day is up to date",
+			zap.String("locale", locale),
+			zap.String("factID", fact.ID))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// GeocodingJob geocodes the queued restaurant addresses, a batch per run.
+type GeocodingJob struct {
+	geocodingUseCase usecase.GeocodingUseCase
+	batchSize        int
+}
+
+func NewGeocodingJob(geocodingUseCase usecase.GeocodingUseCase, batchSize int) *GeocodingJob {
+	return &GeocodingJob{
+		geocodingUseCase: geocodingUseCase,
+		batchSize:        batchSize,
+	}
+}
+
+func (j *GeocodingJob) Name() string {
+	return "geocoding"
+}
+
+func (j *GeocodingJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	geocoded, err := j.geocodingUseCase.GeocodePending(ctx, j.batchSize)
+	if geocoded > 0 {
+		log.Info(ctx, "restaurant addresses geocoded", zap.Int("count", geocoded))
+	}
+	return err
+}
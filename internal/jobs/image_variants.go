@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// ImageVariantsJob converts uploaded images to the configured formats, such as WebP, a batch of
+// variants per run.
+type ImageVariantsJob struct {
+	imageUseCase usecase.ImageUseCase
+	batchSize    int
+}
+
+func NewImageVariantsJob(imageUseCase usecase.ImageUseCase, batchSize int) *ImageVariantsJob {
+	return &ImageVariantsJob{
+		imageUseCase: imageUseCase,
+		batchSize:    batchSize,
+	}
+}
+
+func (j *ImageVariantsJob) Name() string {
+	return "image_variants"
+}
+
+func (j *ImageVariantsJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	generated, err := j.imageUseCase.GenerateVariants(ctx, j.batchSize)
+	if generated > 0 {
+		log.Info(ctx, "image variants generated", zap.Int("count", generated))
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// IndexAdvisorJob reports the tables scanned sequentially more often than through an index and
+// the statements reading the most blocks per row, the candidates for a new index.
+type IndexAdvisorJob struct {
+	indexAdvisorUseCase usecase.IndexAdvisorUseCase
+	clock               clock.Clock
+}
+
+func NewIndexAdvisorJob(indexAdvisorUseCase usecase.IndexAdvisorUseCase, clock clock.Clock) *IndexAdvisorJob {
+	return &IndexAdvisorJob{
+		indexAdvisorUseCase: indexAdvisorUseCase,
+		clock:               clock,
+	}
+}
+
+func (j *IndexAdvisorJob) Name() string {
+	return "index_advisor"
+}
+
+func (j *IndexAdvisorJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	advice, err := j.indexAdvisorUseCase.AdviseIndexes(ctx, j.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, t := range advice.Tables {
+		log.Warn(ctx, "table scanned sequentially more often than through an index",
+			zap.String("table", t.Name),
+			zap.Int64("seqScans", t.SeqScans),
+			zap.Int64("seqRowsRead", t.SeqRowsRead),
+			zap.Int64("indexScans", t.IndexScans),
+			zap.Int64("liveRows", t.LiveRows))
+	}
+
+	for _, q := range advice.Queries {
+		log.Warn(ctx, "query reading many blocks per row",
+			zap.Int64("queryID", q.QueryID),
+			zap.String("query", q.Query),
+			zap.Int64("calls", q.Calls),
+			zap.Int64("rows", q.Rows),
+			zap.Float64("blocksPerRow", q.BlocksPerRow),
+			zap.Float64("meanTimeMs", q.MeanTimeMs))
+	}
+
+	log.Info(ctx, "index advisor finished",
+		zap.Int("tables", len(advice.Tables)),
+		zap.Int("queries", len(advice.Queries)),
+		zap.Bool("statementsAvailable", advice.StatementsAvailable))
+	return nil
+}
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// InvoiceJob bills the subscriptions of the month before. It runs every day so that a missed run
+// is made up for; subscriptions already billed for the month are skipped.
+type InvoiceJob struct {
+	billingUseCase usecase.BillingUseCase
+	clock          clock.Clock
+}
+
+func NewInvoiceJob(billingUseCase usecase.BillingUseCase, clock clock.Clock) *InvoiceJob {
+	return &InvoiceJob{
+		billingUseCase: billingUseCase,
+		clock:          clock,
+	}
+}
+
+func (j *InvoiceJob) Name() string {
+	return "invoices"
+}
+
+func (j *InvoiceJob) Run(ctx context.Context) error {
+	now := j.clock.Now()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	_, err := j.billingUseCase.GenerateInvoices(tenant.SystemContext(ctx), lastMonth, now)
+	return err
+}
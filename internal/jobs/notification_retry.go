@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// NotificationRetryJob delivers again the notifications that failed and are due for a retry, a
+// batch per run.
+type NotificationRetryJob struct {
+	notificationRetryUseCase usecase.NotificationRetryUseCase
+	batchSize                int
+}
+
+func NewNotificationRetryJob(notificationRetryUseCase usecase.NotificationRetryUseCase, batchSize int) *NotificationRetryJob {
+	return &NotificationRetryJob{
+		notificationRetryUseCase: notificationRetryUseCase,
+		batchSize:                batchSize,
+	}
+}
+
+func (j *NotificationRetryJob) Name() string {
+	return "notification_retry"
+}
+
+func (j *NotificationRetryJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	report, err := j.notificationRetryUseCase.RetryDue(ctx, j.batchSize)
+	if report.Retried > 0 {
+		log.Info(ctx, "failed notifications retried",
+			zap.Int("retried", report.Retried),
+			zap.Int("delivered", report.Delivered),
+			zap.Int("exhausted", report.Exhausted))
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// ReengagementJob invites back the guests who have not booked since a visit some weeks ago or whose
+// anniversary celebrated at a restaurant comes up, if they opted in to marketing.
+type ReengagementJob struct {
+	reengagementUseCase usecase.ReengagementUseCase
+	clock               clock.Clock
+}
+
+func NewReengagementJob(reengagementUseCase usecase.ReengagementUseCase, clock clock.Clock) *ReengagementJob {
+	return &ReengagementJob{
+		reengagementUseCase: reengagementUseCase,
+		clock:               clock,
+	}
+}
+
+func (j *ReengagementJob) Name() string {
+	return "reengagement"
+}
+
+func (j *ReengagementJob) Run(ctx context.Context) error {
+	_, err := j.reengagementUseCase.SendInvitations(ctx, j.clock.Now())
+	return err
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// ReservedSeatsReconciliationJob recomputes reserved seats from active bookings and reports
+// every slot whose recorded count had drifted, e.g. after a cancellation that did not release seats.
+type ReservedSeatsReconciliationJob struct {
+	availabilityUseCase usecase.AvailabilityUseCase
+}
+
+func NewReservedSeatsReconciliationJob(availabilityUseCase usecase.AvailabilityUseCase) *ReservedSeatsReconciliationJob {
+	return &ReservedSeatsReconciliationJob{
+		availabilityUseCase: availabilityUseCase,
+	}
+}
+
+func (j *ReservedSeatsReconciliationJob) Name() string {
+	return "reserved_seats_reconciliation"
+}
+
+func (j *ReservedSeatsReconciliationJob) Run(ctx context.Context) error {
+	log, _ := logger.FromContext(ctx)
+
+	drifts, err := j.availabilityUseCase.ReconcileReservedSeats(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range drifts {
+		log.Warn(ctx, "reserved seats drift corrected",
+			zap.String("availabilityID", d.AvailabilityID),
+			zap.String("restaurantID", d.RestaurantID),
+			zap.Time("date", d.Date),
+			zap.String("timeSlot", d.TimeSlot),
+			zap.Int("recorded", d.Recorded),
+			zap.Int("actual", d.Actual))
+	}
+
+	log.Info(ctx, "reserved seats reconciliation finished", zap.Int("drifted", len(drifts)))
+	return nil
+}
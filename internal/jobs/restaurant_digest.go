@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// RestaurantDigestJob sends every restaurant the digest of its bookings for the day, with the
+// occasions coming up, and on the weekly report day the report of the week before.
+type RestaurantDigestJob struct {
+	digestUseCase   usecase.RestaurantDigestUseCase
+	weeklyReports   usecase.WeeklyReportUseCase
+	weeklyReportDay time.Weekday
+	clock           clock.Clock
+}
+
+// NewRestaurantDigestJob creates the job; weeklyReports may be nil to send the daily digests only.
+func NewRestaurantDigestJob(
+	digestUseCase usecase.RestaurantDigestUseCase,
+	weeklyReports usecase.WeeklyReportUseCase,
+	weeklyReportDay time.Weekday,
+	clock clock.Clock,
+) *RestaurantDigestJob {
+	return &RestaurantDigestJob{
+		digestUseCase:   digestUseCase,
+		weeklyReports:   weeklyReports,
+		weeklyReportDay: weeklyReportDay,
+		clock:           clock,
+	}
+}
+
+func (j *RestaurantDigestJob) Name() string {
+	return "restaurant_digest"
+}
+
+func (j *RestaurantDigestJob) Run(ctx context.Context) error {
+	now := j.clock.Now()
+	_, err := j.digestUseCase.SendDailyDigests(ctx, now)
+
+	if j.weeklyReports != nil && now.Weekday() == j.weeklyReportDay {
+		_, reportErr := j.weeklyReports.SendWeeklyReports(ctx, now)
+		err = errors.Join(err, reportErr)
+	}
+	return err
+}
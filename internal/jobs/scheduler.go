@@ -0,0 +1,132 @@
+// Package jobs contains background jobs of the application and a simple scheduler running them periodically.
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+	daily    bool
+	at       time.Duration
+}
+
+type Scheduler struct {
+	jobs []scheduledJob
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers the job to be run right after Start and then once per interval.
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+// Daily registers the job to be run every day at the given offset from local midnight,
+// e.g. 3*time.Hour for 03:00. Unlike Every, the job does not run right after Start.
+func (s *Scheduler) Daily(at time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, daily: true, at: at})
+}
+
+// NextDailyRun returns the first moment after now that is at the given offset from local midnight.
+func NextDailyRun(now time.Time, at time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(at)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(at)
+	}
+	return next
+}
+
+// Start launches every registered job in its own goroutine; jobs stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, sj := range s.jobs {
+		s.wg.Add(1)
+		go func(sj scheduledJob) {
+			defer s.wg.Done()
+			s.loop(ctx, sj)
+		}(sj)
+	}
+}
+
+// Wait blocks until all jobs have returned after ctx cancellation.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, sj scheduledJob) {
+	if sj.daily {
+		s.dailyLoop(ctx, sj)
+		return
+	}
+
+	ticker := time.NewTicker(sj.interval)
+	defer ticker.Stop()
+
+	for {
+		runJob(ctx, sj.job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) dailyLoop(ctx context.Context, sj scheduledJob) {
+	for {
+		now := time.Now()
+		timer := time.NewTimer(NextDailyRun(now, sj.at).Sub(now))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		runJob(ctx, sj.job)
+	}
+}
+
+func runJob(ctx context.Context, job Job) {
+	log, err := logger.FromContext(ctx)
+	if err != nil {
+		log, err = logger.NewLogger()
+		if err != nil {
+			return
+		}
+		ctx = logger.NewContext(ctx, log)
+	}
+
+	log.Debug(ctx, common.MsgJobStarted, zap.String("job", job.Name()))
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		log.Error(ctx, common.ErrJobFailed,
+			zap.String("job", job.Name()),
+			zap.Error(err))
+		return
+	}
+
+	log.Debug(ctx, common.MsgJobCompleted,
+		zap.String("job", job.Name()),
+		zap.Duration("duration", time.Since(start)))
+}
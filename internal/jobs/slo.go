@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// SLOMetricsJob stores the requests counted since its last run and alerts when the error budgets
+// of the last hours burn too fast.
+type SLOMetricsJob struct {
+	sloUseCase usecase.SLOUseCase
+	clock      clock.Clock
+}
+
+func NewSLOMetricsJob(sloUseCase usecase.SLOUseCase, clock clock.Clock) *SLOMetricsJob {
+	return &SLOMetricsJob{
+		sloUseCase: sloUseCase,
+		clock:      clock,
+	}
+}
+
+func (j *SLOMetricsJob) Name() string {
+	return "slo_metrics"
+}
+
+func (j *SLOMetricsJob) Run(ctx context.Context) error {
+	if err := j.sloUseCase.FlushMetrics(ctx); err != nil {
+		return err
+	}
+	_, err := j.sloUseCase.CheckBurnRate(ctx, j.clock.Now())
+	return err
+}
+
+// SLOReportJob stores the report of the service level objectives of the day before.
+type SLOReportJob struct {
+	sloUseCase usecase.SLOUseCase
+	clock      clock.Clock
+}
+
+func NewSLOReportJob(sloUseCase usecase.SLOUseCase, clock clock.Clock) *SLOReportJob {
+	return &SLOReportJob{
+		sloUseCase: sloUseCase,
+		clock:      clock,
+	}
+}
+
+func (j *SLOReportJob) Name() string {
+	return "slo_report"
+}
+
+func (j *SLOReportJob) Run(ctx context.Context) error {
+	_, err := j.sloUseCase.GenerateDailyReport(ctx, j.clock.Now().AddDate(0, 0, -1))
+	return err
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+// TableOptimizationJob seats the bookings of the day yet to start again at the tables that fit
+// them best. It runs before each service, once its bookings are mostly in.
+type TableOptimizationJob struct {
+	tableUseCase usecase.TableUseCase
+	clock        clock.Clock
+}
+
+func NewTableOptimizationJob(tableUseCase usecase.TableUseCase, clock clock.Clock) *TableOptimizationJob {
+	return &TableOptimizationJob{
+		tableUseCase: tableUseCase,
+		clock:        clock,
+	}
+}
+
+func (j *TableOptimizationJob) Name() string {
+	return "table_optimization"
+}
+
+func (j *TableOptimizationJob) Run(ctx context.Context) error {
+	_, err := j.tableUseCase.OptimizeSeating(tenant.SystemContext(ctx), j.clock.Now())
+	return err
+}
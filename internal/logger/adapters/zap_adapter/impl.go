@@ -42,23 +42,29 @@ func (f *ZapLoggerFactory) NewLogger() (ports.LoggerPort, error) {
 }
 
 func (l *ZapLogger) Info(ctx context.Context, msg string, fields ...zap.Field) {
-	l.l.Info(msg, utils.AddRequestID(ctx, fields)...)
+	if utils.IsSampledOut(ctx) {
+		return
+	}
+	l.l.Info(msg, utils.AddContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
-	l.l.Warn(msg, utils.AddRequestID(ctx, fields)...)
+	l.l.Warn(msg, utils.AddContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) Error(ctx context.Context, msg string, fields ...zap.Field) {
-	l.l.Error(msg, utils.AddRequestID(ctx, fields)...)
+	l.l.Error(msg, utils.AddContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
-	l.l.Debug(msg, utils.AddRequestID(ctx, fields)...)
+	if utils.IsSampledOut(ctx) {
+		return
+	}
+	l.l.Debug(msg, utils.AddContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
-	l.l.Fatal(msg, utils.AddRequestID(ctx, fields)...)
+	l.l.Fatal(msg, utils.AddContextFields(ctx, fields)...)
 }
 
 func (l *ZapLogger) Sync() error {
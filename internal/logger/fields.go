@@ -1,6 +1,10 @@
 package logger
 
 import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/utils"
+
 	"go.uber.org/zap"
 )
 
@@ -37,3 +41,31 @@ func Fields(keysAndValues ...interface{}) []zap.Field {
 
 	return fields
 }
+
+// Keys of the business identifiers that log lines of a request carry once they are known.
+const (
+	RestaurantIDKey = "restaurant_id"
+	BookingIDKey    = "booking_id"
+	UserIDKey       = "user_id"
+)
+
+// WithFields returns a copy of ctx whose log lines carry the fields, replacing the fields with the
+// same keys added before.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return utils.WithFields(ctx, fields...)
+}
+
+// WithRestaurantID makes the log lines of ctx carry the restaurant ID.
+func WithRestaurantID(ctx context.Context, restaurantID string) context.Context {
+	return utils.WithFields(ctx, zap.String(RestaurantIDKey, restaurantID))
+}
+
+// WithBookingID makes the log lines of ctx carry the booking ID.
+func WithBookingID(ctx context.Context, bookingID string) context.Context {
+	return utils.WithFields(ctx, zap.String(BookingIDKey, bookingID))
+}
+
+// WithUserID makes the log lines of ctx carry the user ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return utils.WithFields(ctx, zap.String(UserIDKey, userID))
+}
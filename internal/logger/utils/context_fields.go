@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"context"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+type fieldsKey struct{}
+
+type sampledOutKey struct{}
+
+// WithFields returns a copy of ctx whose log lines carry the fields, replacing the fields with the
+// same keys added before.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	existing, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	merged := make([]zap.Field, 0, len(existing)+len(fields))
+	for _, field := range existing {
+		if !slices.ContainsFunc(fields, func(f zap.Field) bool { return f.Key == field.Key }) {
+			merged = append(merged, field)
+		}
+	}
+	return context.WithValue(ctx, fieldsKey{}, append(merged, fields...))
+}
+
+// AddContextFields appends the request ID and the fields added with WithFields.
+func AddContextFields(ctx context.Context, fields []zap.Field) []zap.Field {
+	fields = AddRequestID(ctx, fields)
+	if extra, ok := ctx.Value(fieldsKey{}).([]zap.Field); ok {
+		fields = append(fields, extra...)
+	}
+	return fields
+}
+
+// WithSampledOut marks the request of ctx as left out of sampling: its info and debug lines are
+// dropped, its warnings and errors are still written.
+func WithSampledOut(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sampledOutKey{}, true)
+}
+
+// IsSampledOut reports whether the info and debug lines of ctx are dropped.
+func IsSampledOut(ctx context.Context) bool {
+	sampledOut, _ := ctx.Value(sampledOutKey{}).(bool)
+	return sampledOut
+}
@@ -0,0 +1,77 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// FailureStore keeps the notifications that could not be delivered for a retry.
+type FailureStore interface {
+	RecordFailure(ctx context.Context, failure *domain.NotificationFailure, cause error) error
+}
+
+// FailureRecorder keeps every notification the next service fails to deliver, with its payload and
+// the cause, so that it is retried later. The error is still returned to the caller.
+type FailureRecorder struct {
+	domain.NotificationService
+
+	failures FailureStore
+}
+
+func NewFailureRecorder(next domain.NotificationService, failures FailureStore) *FailureRecorder {
+	return &FailureRecorder{
+		NotificationService: next,
+		failures:            failures,
+	}
+}
+
+func (r *FailureRecorder) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	err := r.NotificationService.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+	if err != nil {
+		r.record(ctx, domain.RecipientTypeRestaurant, restaurantID, notificationType, title, message, relatedID, err)
+	}
+	return err
+}
+
+func (r *FailureRecorder) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	err := r.NotificationService.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+	if err != nil {
+		r.record(ctx, domain.RecipientTypeUser, userID, notificationType, title, message, relatedID, err)
+	}
+	return err
+}
+
+func (r *FailureRecorder) record(
+	ctx context.Context,
+	recipientType domain.RecipientType,
+	recipientID string,
+	notificationType domain.NotificationType,
+	title, message, relatedID string,
+	cause error,
+) {
+	// The delivery may have failed because the request timed out; the failure is kept regardless.
+	ctx = context.WithoutCancel(ctx)
+
+	err := r.failures.RecordFailure(ctx, &domain.NotificationFailure{
+		RecipientType:    recipientType,
+		RecipientID:      recipientID,
+		NotificationType: notificationType,
+		Title:            title,
+		Message:          message,
+		RelatedID:        relatedID,
+	}, cause)
+	if err != nil {
+		if log, logErr := logger.FromContext(ctx); logErr == nil {
+			log.Error(ctx, "failed to record notification failure, it will not be retried",
+				zap.String("recipientType", string(recipientType)),
+				zap.String("recipientID", recipientID),
+				zap.String("type", string(notificationType)),
+				zap.NamedError("cause", cause),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// DebouncedNotificationService coalesces restaurant notifications, so that a flood of bookings and
+// cancellations does not spam the restaurant. The first notification of a restaurant is delivered
+// right away and opens a window; the ones arriving within the window are held back and delivered
+// as a single summary when it ends. User notifications are passed through unchanged.
+type DebouncedNotificationService struct {
+	domain.NotificationService
+
+	window  time.Duration
+	mu      sync.Mutex
+	windows map[string]*restaurantWindow
+}
+
+type restaurantWindow struct {
+	timer   *time.Timer
+	ctx     context.Context
+	pending []restaurantNotification
+}
+
+type restaurantNotification struct {
+	notificationType domain.NotificationType
+	title            string
+	message          string
+	relatedID        string
+}
+
+// NewDebouncedNotificationService wraps next; a window of zero disables coalescing.
+func NewDebouncedNotificationService(next domain.NotificationService, window time.Duration) *DebouncedNotificationService {
+	return &DebouncedNotificationService{
+		NotificationService: next,
+		window:              window,
+		windows:             make(map[string]*restaurantWindow),
+	}
+}
+
+func (s *DebouncedNotificationService) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	if s.window <= 0 {
+		return s.NotificationService.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+	}
+
+	s.mu.Lock()
+	if w, ok := s.windows[restaurantID]; ok {
+		w.pending = append(w.pending, restaurantNotification{
+			notificationType: notificationType,
+			title:            title,
+			message:          message,
+			relatedID:        relatedID,
+		})
+		// The summary is sent after the request has finished, so keep its values but not its deadline.
+		w.ctx = context.WithoutCancel(ctx)
+		s.mu.Unlock()
+
+		if log, err := logger.FromContext(ctx); err == nil {
+			log.Debug(ctx, "restaurant notification deferred to summary",
+				zap.String("restaurantID", restaurantID),
+				zap.String("type", string(notificationType)))
+		}
+		return nil
+	}
+
+	w := &restaurantWindow{}
+	w.timer = time.AfterFunc(s.window, func() { s.closeWindow(restaurantID, w) })
+	s.windows[restaurantID] = w
+	s.mu.Unlock()
+
+	return s.NotificationService.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+}
+
+// Flush delivers the held back notifications right away; it is called on shutdown.
+func (s *DebouncedNotificationService) Flush() {
+	s.mu.Lock()
+	windows := s.windows
+	s.windows = make(map[string]*restaurantWindow)
+	s.mu.Unlock()
+
+	for restaurantID, w := range windows {
+		w.timer.Stop()
+		if len(w.pending) > 0 {
+			s.deliver(w.ctx, restaurantID, w.pending)
+		}
+	}
+}
+
+// closeWindow sends the summary of a window. A window that had something to summarize stays open
+// for one more period, so a restaurant gets at most one summary per window.
+func (s *DebouncedNotificationService) closeWindow(restaurantID string, w *restaurantWindow) {
+	s.mu.Lock()
+	if s.windows[restaurantID] != w {
+		s.mu.Unlock()
+		return
+	}
+	if len(w.pending) == 0 {
+		delete(s.windows, restaurantID)
+		s.mu.Unlock()
+		return
+	}
+	ctx, pending := w.ctx, w.pending
+	w.pending = nil
+	w.timer.Reset(s.window)
+	s.mu.Unlock()
+
+	s.deliver(ctx, restaurantID, pending)
+}
+
+func (s *DebouncedNotificationService) deliver(ctx context.Context, restaurantID string, pending []restaurantNotification) {
+	n := summarizeRestaurantNotifications(pending)
+
+	err := s.NotificationService.NotifyRestaurant(ctx, restaurantID, n.notificationType, n.title, n.message, n.relatedID)
+	if err == nil {
+		return
+	}
+	if log, logErr := logger.FromContext(ctx); logErr == nil {
+		log.Error(ctx, "failed to send restaurant notification summary",
+			zap.String("restaurantID", restaurantID),
+			zap.Int("notifications", len(pending)),
+			zap.Error(err))
+	}
+}
+
+func summarizeRestaurantNotifications(pending []restaurantNotification) restaurantNotification {
+	if len(pending) == 1 {
+		return pending[0]
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "You have %d booking updates:", len(pending))
+	for _, n := range pending {
+		fmt.Fprintf(&message, "\n- %s: %s", n.title, n.message)
+	}
+
+	return restaurantNotification{
+		notificationType: domain.NotificationTypeSummary,
+		title:            fmt.Sprintf("%d booking updates", len(pending)),
+		message:          message.String(),
+		relatedID:        pending[len(pending)-1].relatedID,
+	}
+}
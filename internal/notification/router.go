@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// Deferrer keeps a notification the quiet hours of its recipient hold back and reports whether
+// it did.
+type Deferrer interface {
+	Defer(ctx context.Context, settings *domain.NotificationSettings, notification *domain.DeferredNotification) (bool, error)
+}
+
+// Router consults the notification preferences of the recipient before an in-app notification
+// is delivered and drops the ones the recipient has turned off. The notifications that can wait
+// are handed to the deferrer during the quiet hours of the recipient; with a nil deferrer they are
+// delivered right away.
+type Router struct {
+	domain.NotificationService
+
+	settings  repository.NotificationSettingsRepository
+	deferrals Deferrer
+}
+
+func NewRouter(next domain.NotificationService, settings repository.NotificationSettingsRepository, deferrals Deferrer) *Router {
+	return &Router{
+		NotificationService: next,
+		settings:            settings,
+		deferrals:           deferrals,
+	}
+}
+
+func (r *Router) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	notification := &domain.DeferredNotification{
+		RecipientType:    domain.RecipientTypeRestaurant,
+		RecipientID:      restaurantID,
+		NotificationType: notificationType,
+		Channel:          domain.NotificationChannelInApp,
+		Title:            title,
+		Message:          message,
+		RelatedID:        relatedID,
+	}
+	if !r.deliversNow(ctx, notification) {
+		return nil
+	}
+	return r.NotificationService.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+}
+
+func (r *Router) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	notification := &domain.DeferredNotification{
+		RecipientType:    domain.RecipientTypeUser,
+		RecipientID:      userID,
+		NotificationType: notificationType,
+		Channel:          domain.NotificationChannelInApp,
+		Title:            title,
+		Message:          message,
+		RelatedID:        relatedID,
+	}
+	if !r.deliversNow(ctx, notification) {
+		return nil
+	}
+	return r.NotificationService.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+}
+
+// deliversNow delivers the notification when the preferences cannot be read or it cannot be
+// deferred, as losing a booking update is worse than sending one the recipient did not want.
+func (r *Router) deliversNow(ctx context.Context, notification *domain.DeferredNotification) bool {
+	recipientType, recipientID, notificationType := notification.RecipientType, notification.RecipientID, notification.NotificationType
+
+	settings, err := r.settings.Get(ctx, recipientType, recipientID)
+	if err != nil {
+		if log, logErr := logger.FromContext(ctx); logErr == nil {
+			log.Warn(ctx, "failed to get notification settings, delivering anyway",
+				zap.String("recipientType", string(recipientType)),
+				zap.String("recipientID", recipientID),
+				zap.Error(err))
+		}
+		return true
+	}
+
+	if settings.Allows(notificationType, domain.NotificationChannelInApp) {
+		return !r.deferred(ctx, settings, notification)
+	}
+
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Debug(ctx, "in-app notification turned off by recipient",
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", recipientID),
+			zap.String("type", string(notificationType)))
+	}
+	return false
+}
+
+// deferred hands the notification to the deferrer and reports whether it was kept for later.
+func (r *Router) deferred(ctx context.Context, settings *domain.NotificationSettings, notification *domain.DeferredNotification) bool {
+	if r.deferrals == nil {
+		return false
+	}
+
+	deferred, err := r.deferrals.Defer(ctx, settings, notification)
+	if err != nil {
+		if log, logErr := logger.FromContext(ctx); logErr == nil {
+			log.Warn(ctx, "failed to defer notification, delivering anyway",
+				zap.String("recipientType", string(notification.RecipientType)),
+				zap.String("recipientID", notification.RecipientID),
+				zap.Error(err))
+		}
+		return false
+	}
+	return deferred
+}
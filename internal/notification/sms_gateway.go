@@ -0,0 +1,73 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+)
+
+// smsGatewayTimeout bounds a request to the SMS gateway.
+const smsGatewayTimeout = 10 * time.Second
+
+// SMSGateway sends SMS through an HTTP gateway: every message is posted to its URL as JSON with
+// the recipient, the sender name and the text, authorized by a bearer token.
+type SMSGateway struct {
+	client *http.Client
+	url    string
+	token  string
+	sender string
+}
+
+// NewSMSGateway returns the gateway at url; a nil client gets one with a timeout of its own.
+func NewSMSGateway(client *http.Client, url, token, sender string) *SMSGateway {
+	if client == nil {
+		client = &http.Client{Timeout: smsGatewayTimeout}
+	}
+	return &SMSGateway{
+		client: client,
+		url:    url,
+		token:  token,
+		sender: sender,
+	}
+}
+
+type smsGatewayMessage struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+func (g *SMSGateway) SendSMS(to, body string) error {
+	payload, err := json.Marshal(smsGatewayMessage{To: to, From: g.sender, Text: body})
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrSendSMS, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smsGatewayTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrSendSMS, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrSendSMS, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s: the gateway responded with status %d", common.ErrSendSMS, resp.StatusCode)
+	}
+	return nil
+}
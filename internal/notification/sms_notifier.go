@@ -0,0 +1,131 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"go.uber.org/zap"
+)
+
+// BookingLinkIssuer creates the links to the details and the cancellation of a booking that
+// are sent in SMS.
+type BookingLinkIssuer interface {
+	IssueConfirmationLinks(ctx context.Context, bookingID domain.BookingID) (view, cancel *usecase.IssuedBookingLink, err error)
+}
+
+// DeliveryLog stores the notifications sent over channels other than in-app, so their delivery can
+// be reported next to the in-app ones.
+type DeliveryLog interface {
+	Create(ctx context.Context, notification *domain.Notification) error
+}
+
+// SMSNotifier texts a user the confirmation of a booking with short links to its details and
+// cancellation, when the user has turned SMS on. The notification itself is passed through, and
+// a failed SMS never fails it. Every SMS attempt is kept in the delivery log, delivered once the
+// sender accepted it.
+type SMSNotifier struct {
+	domain.NotificationService
+
+	sms      domain.SMSSender
+	users    repository.UserRepository
+	settings repository.NotificationSettingsRepository
+	links    BookingLinkIssuer
+	log      DeliveryLog
+}
+
+func NewSMSNotifier(
+	next domain.NotificationService,
+	sms domain.SMSSender,
+	users repository.UserRepository,
+	settings repository.NotificationSettingsRepository,
+	links BookingLinkIssuer,
+	deliveries DeliveryLog,
+) *SMSNotifier {
+	return &SMSNotifier{
+		NotificationService: next,
+		sms:                 sms,
+		users:               users,
+		settings:            settings,
+		links:               links,
+		log:                 deliveries,
+	}
+}
+
+func (n *SMSNotifier) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	if err := n.NotificationService.NotifyUser(ctx, userID, notificationType, title, message, relatedID); err != nil {
+		return err
+	}
+
+	if notificationType == domain.NotificationTypeBookingConfirmed {
+		n.sendBookingSMS(ctx, userID, notificationType, title, message, relatedID)
+	}
+	return nil
+}
+
+func (n *SMSNotifier) sendBookingSMS(ctx context.Context, userID string, notificationType domain.NotificationType, title, message, bookingID string) {
+	log, err := logger.FromContext(ctx)
+	if err != nil {
+		return
+	}
+
+	settings, err := n.settings.Get(ctx, domain.RecipientTypeUser, userID)
+	if err != nil {
+		log.Warn(ctx, "failed to get notification settings, SMS not sent",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return
+	}
+	if !settings.Allows(notificationType, domain.NotificationChannelSMS) {
+		return
+	}
+
+	user, err := n.users.GetByID(ctx, domain.UserID(userID))
+	if err != nil || user.Phone == "" {
+		log.Warn(ctx, "no phone number to send SMS to",
+			zap.String("userID", userID),
+			zap.Error(err))
+		return
+	}
+
+	view, cancel, err := n.links.IssueConfirmationLinks(ctx, domain.BookingID(bookingID))
+	if err != nil {
+		log.Error(ctx, "failed to issue booking links",
+			zap.String("bookingID", bookingID),
+			zap.Error(err))
+		return
+	}
+
+	body := message + "\nDetails: " + view.URL + "\nCancel: " + cancel.URL
+	delivery := &domain.Notification{
+		RecipientType: domain.RecipientTypeUser,
+		RecipientID:   userID,
+		Type:          notificationType,
+		Channel:       domain.NotificationChannelSMS,
+		Title:         title,
+		Message:       body,
+		RelatedID:     bookingID,
+		CreatedAt:     time.Now(),
+	}
+
+	if err := n.sms.SendSMS(user.Phone, body); err != nil {
+		log.Error(ctx, "failed to send booking SMS",
+			zap.String("userID", userID),
+			zap.String("bookingID", bookingID),
+			zap.Error(err))
+	} else {
+		deliveredAt := time.Now()
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	if err := n.log.Create(ctx, delivery); err != nil {
+		log.Warn(ctx, "failed to record SMS delivery",
+			zap.String("userID", userID),
+			zap.String("bookingID", bookingID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,99 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// Sink receives the notifications of test restaurants and bookings in place of their recipients.
+type Sink interface {
+	NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error
+	NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message string, relatedID string) error
+}
+
+// TestModeRouter sends the notifications of test restaurants and test bookings to a sink, so
+// partners can integrate against production without anyone being notified for real. The related
+// ID of a notification is looked up as a booking; a notification it cannot be told about is
+// delivered as usual.
+type TestModeRouter struct {
+	domain.NotificationService
+
+	sink        Sink
+	restaurants repository.RestaurantRepository
+	bookings    repository.BookingRepository
+}
+
+func NewTestModeRouter(
+	next domain.NotificationService,
+	sink Sink,
+	restaurants repository.RestaurantRepository,
+	bookings repository.BookingRepository,
+) *TestModeRouter {
+	return &TestModeRouter{
+		NotificationService: next,
+		sink:                sink,
+		restaurants:         restaurants,
+		bookings:            bookings,
+	}
+}
+
+func (r *TestModeRouter) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	if r.isTestBooking(ctx, relatedID) || r.isTestRestaurant(ctx, restaurantID) {
+		return r.sink.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+	}
+	return r.NotificationService.NotifyRestaurant(ctx, restaurantID, notificationType, title, message, relatedID)
+}
+
+func (r *TestModeRouter) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	if r.isTestBooking(ctx, relatedID) {
+		return r.sink.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+	}
+	return r.NotificationService.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+}
+
+func (r *TestModeRouter) isTestBooking(ctx context.Context, bookingID string) bool {
+	if bookingID == "" {
+		return false
+	}
+	booking, err := r.bookings.GetByID(ctx, domain.BookingID(bookingID))
+	return err == nil && booking.IsTest
+}
+
+func (r *TestModeRouter) isTestRestaurant(ctx context.Context, restaurantID string) bool {
+	restaurant, err := r.restaurants.GetByID(ctx, domain.RestaurantID(restaurantID))
+	return err == nil && restaurant.IsTest
+}
+
+// LogSink logs the notifications of test entities and drops them.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, _ string, relatedID string) error {
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Info(ctx, "test notification to restaurant sent to sink",
+			zap.String("restaurantID", restaurantID),
+			zap.String("type", string(notificationType)),
+			zap.String("title", title),
+			zap.String("relatedID", relatedID))
+	}
+	return nil
+}
+
+func (s *LogSink) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, _ string, relatedID string) error {
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Info(ctx, "test notification to user sent to sink",
+			zap.String("userID", userID),
+			zap.String("type", string(notificationType)),
+			zap.String("title", title),
+			zap.String("relatedID", relatedID))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Package pdf writes plain text documents as PDF 1.4: A4 pages of monospaced Courier text, so that
+// columns laid out with spaces stay aligned. Only what invoices need is supported: lines, bold
+// headings and page breaks; text is Windows-1252, other characters are written as "?".
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 56
+	fontSize     = 10
+	headingSize  = 14
+	lineHeight   = 14
+	linesPerPage = (pageHeight - 2*margin) / lineHeight
+
+	// HeadingPrefix marks a line written bold and larger.
+	HeadingPrefix = "# "
+	// PageBreak on a line of its own starts a new page.
+	PageBreak = "\f"
+)
+
+// FromText lays out the lines of text on as many pages as they need.
+func FromText(text string) []byte {
+	var (
+		pages   [][]string
+		current []string
+	)
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if line == PageBreak || len(current) == linesPerPage {
+			pages = append(pages, current)
+			current = nil
+			if line == PageBreak {
+				continue
+			}
+		}
+		current = append(current, strings.TrimRight(line, "\r"))
+	}
+	pages = append(pages, current)
+
+	w := &writer{}
+	w.header()
+
+	// Objects 1 and 2 are the catalog and the page tree, 3 and 4 the fonts; every page is a page
+	// object followed by its content stream.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	w.object("<< /Type /Catalog /Pages 2 0 R >>")
+	w.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	w.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range pages {
+		w.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		content := pageContent(lines)
+		w.object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	return w.finish()
+}
+
+func pageContent(lines []string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "BT\n%d TL\n%d %d Td\n", lineHeight, margin, pageHeight-margin)
+	for _, line := range lines {
+		if heading, ok := strings.CutPrefix(line, HeadingPrefix); ok {
+			fmt.Fprintf(&b, "/F2 %d Tf\n(%s) Tj T*\n", headingSize, escape(heading))
+			continue
+		}
+		fmt.Fprintf(&b, "/F1 %d Tf\n(%s) Tj T*\n", fontSize, escape(line))
+	}
+	b.WriteString("ET")
+	return b.Bytes()
+}
+
+// escape encodes the text in Windows-1252 and escapes it for a PDF string literal.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\t':
+			b.WriteString("    ")
+		default:
+			if r < 0x20 {
+				continue
+			}
+			c, ok := charmap.Windows1252.EncodeRune(r)
+			if !ok {
+				c = '?'
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (w *writer) header() {
+	// The binary comment tells transfer tools the file is not text.
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+}
+
+func (w *writer) object(body string) {
+	w.offsets = append(w.offsets, w.buf.Len())
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", len(w.offsets), body)
+}
+
+func (w *writer) finish() []byte {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, xref)
+	return w.buf.Bytes()
+}
@@ -0,0 +1,206 @@
+// Package qr encodes short texts such as links into QR codes (ISO/IEC 18004) and renders them as
+// PNG or SVG. Only what the service needs is supported: byte mode, error correction level M and
+// versions 1 to 10, which hold up to 213 bytes.
+package qr
+
+import (
+	"errors"
+)
+
+// QuietZone is the number of light modules around a code required by the standard.
+const QuietZone = 4
+
+var ErrContentTooLong = errors.New("content is too long for a QR code")
+
+// Code is an encoded QR symbol of Size×Size modules.
+type Code struct {
+	Size    int
+	modules []bool
+}
+
+// Dark reports whether the module in column x and row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y*c.Size+x]
+}
+
+// versionBlocks describes the error correction of a version at level M.
+type versionBlocks struct {
+	ecPerBlock  int
+	group1      int
+	group1Data  int
+	group2      int
+	group2Data  int
+	alignCenter []int
+}
+
+func (v versionBlocks) dataCodewords() int {
+	return v.group1*v.group1Data + v.group2*v.group2Data
+}
+
+var versions = [...]versionBlocks{
+	1:  {10, 1, 16, 0, 0, nil},
+	2:  {16, 1, 28, 0, 0, []int{6, 18}},
+	3:  {26, 1, 44, 0, 0, []int{6, 22}},
+	4:  {18, 2, 32, 0, 0, []int{6, 26}},
+	5:  {24, 2, 43, 0, 0, []int{6, 30}},
+	6:  {16, 4, 27, 0, 0, []int{6, 34}},
+	7:  {18, 4, 31, 0, 0, []int{6, 22, 38}},
+	8:  {22, 2, 38, 2, 39, []int{6, 24, 42}},
+	9:  {22, 3, 36, 2, 37, []int{6, 26, 46}},
+	10: {26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+// Encode encodes content in the smallest version it fits, choosing the mask with the lowest penalty.
+func Encode(content string) (*Code, error) {
+	data := []byte(content)
+
+	version := 0
+	for v := 1; v < len(versions); v++ {
+		if 4+countBits(v)+8*len(data) <= versions[v].dataCodewords()*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrContentTooLong
+	}
+
+	codewords := addErrorCorrection(version, encodeData(version, data))
+
+	s := newSymbol(version)
+	s.drawFunctionPatterns()
+	s.drawCodewords(codewords)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := range 8 {
+		s.applyMask(mask)
+		s.drawFormatBits(mask)
+		if penalty := s.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		s.applyMask(mask)
+	}
+	s.applyMask(bestMask)
+	s.drawFormatBits(bestMask)
+
+	return &Code{Size: s.size, modules: s.modules}, nil
+}
+
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData builds the data codewords: byte mode indicator, length, content, terminator and padding.
+func encodeData(version int, data []byte) []byte {
+	capacity := versions[version].dataCodewords() * 8
+
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return codewords
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+// addErrorCorrection splits the data into blocks, adds their error correction codewords and
+// interleaves the result.
+func addErrorCorrection(version int, data []byte) []byte {
+	v := versions[version]
+	divisor := ReedSolomonDivisor(v.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	for i := range v.group1 + v.group2 {
+		length := v.group1Data
+		if i >= v.group1 {
+			length = v.group2Data
+		}
+		block := data[offset : offset+length]
+		offset += length
+
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, ReedSolomonRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, len(data)+len(ecBlocks)*v.ecPerBlock)
+	for i := range max(v.group1Data, v.group2Data) {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := range v.ecPerBlock {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// ReedSolomonDivisor returns the generator polynomial of the given degree over GF(2^8), without
+// its leading term, from the highest power down.
+func ReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// ReedSolomonRemainder returns the error correction codewords of data for the divisor.
+func ReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
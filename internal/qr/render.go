@@ -0,0 +1,56 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// PNG renders the code with its quiet zone, each module drawn as scale×scale pixels.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	scale = max(scale, 1)
+	side := (c.Size + 2*QuietZone) * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := range c.Size {
+		for x := range c.Size {
+			if !c.Dark(x, y) {
+				continue
+			}
+			for dy := range scale {
+				for dx := range scale {
+					img.SetColorIndex((x+QuietZone)*scale+dx, (y+QuietZone)*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the code with its quiet zone as a single path, each module scale×scale pixels.
+func (c *Code) SVG(scale int) []byte {
+	scale = max(scale, 1)
+	side := c.Size + 2*QuietZone
+
+	var path bytes.Buffer
+	for y := range c.Size {
+		for x := range c.Size {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x+QuietZone, y+QuietZone)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		side*scale, side*scale, side, side)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path d="%s" fill="#000"/></svg>`, side, side, path.String())
+	return buf.Bytes()
+}
@@ -0,0 +1,252 @@
+package qr
+
+// symbol is a code being drawn; function modules (finders, timing, alignment, format and
+// version information) are marked so that data and masks leave them alone.
+type symbol struct {
+	version  int
+	size     int
+	modules  []bool
+	function []bool
+}
+
+func newSymbol(version int) *symbol {
+	size := 17 + 4*version
+	return &symbol{
+		version:  version,
+		size:     size,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+}
+
+func (s *symbol) set(x, y int, dark bool) {
+	s.modules[y*s.size+x] = dark
+}
+
+func (s *symbol) setFunction(x, y int, dark bool) {
+	s.set(x, y, dark)
+	s.function[y*s.size+x] = true
+}
+
+func (s *symbol) drawFunctionPatterns() {
+	for i := range s.size {
+		s.setFunction(6, i, i%2 == 0)
+		s.setFunction(i, 6, i%2 == 0)
+	}
+
+	s.drawFinder(3, 3)
+	s.drawFinder(s.size-4, 3)
+	s.drawFinder(3, s.size-4)
+
+	centers := versions[s.version].alignCenter
+	last := len(centers) - 1
+	for i, x := range centers {
+		for j, y := range centers {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			s.drawAlignment(x, y)
+		}
+	}
+
+	// Reserve the format areas; the real bits are drawn once the mask is chosen.
+	s.drawFormatBits(0)
+	s.drawVersionBits()
+}
+
+// drawFinder draws a finder pattern with its separator around the center x, y.
+func (s *symbol) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= s.size || yy < 0 || yy >= s.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			s.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (s *symbol) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			s.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the level M format information for the mask.
+func (s *symbol) drawFormatBits(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		s.setFunction(8, i, bit(i))
+	}
+	s.setFunction(8, 7, bit(6))
+	s.setFunction(8, 8, bit(7))
+	s.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := range 8 {
+		s.setFunction(s.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.setFunction(8, s.size-15+i, bit(i))
+	}
+	s.setFunction(8, s.size-8, true)
+}
+
+// drawVersionBits draws both copies of the version information of versions 7 and above.
+func (s *symbol) drawVersionBits() {
+	if s.version < 7 {
+		return
+	}
+
+	rem := s.version
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := s.version<<12 | rem
+
+	for i := range 18 {
+		dark := (bits>>i)&1 == 1
+		a, b := s.size-11+i%3, i/3
+		s.setFunction(a, b, dark)
+		s.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places the codewords in the two-column zigzag from the bottom right corner.
+func (s *symbol) drawCodewords(codewords []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := range s.size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if upward {
+					y = s.size - 1 - vert
+				}
+				if s.function[y*s.size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				s.set(x, y, (codewords[i>>3]>>(7-(i&7)))&1 == 1)
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by the mask; applying it twice undoes it.
+func (s *symbol) applyMask(mask int) {
+	for y := range s.size {
+		for x := range s.size {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !s.function[y*s.size+x] {
+				s.modules[y*s.size+x] = !s.modules[y*s.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the four rules of the standard; lower is easier to scan.
+func (s *symbol) penalty() int {
+	dark := func(x, y int) bool { return s.modules[y*s.size+x] }
+	result := 0
+
+	for _, horizontal := range []bool{true, false} {
+		for a := range s.size {
+			run := 0
+			var previous bool
+			var pattern int
+			for b := range s.size {
+				x, y := b, a
+				if !horizontal {
+					x, y = a, b
+				}
+				current := dark(x, y)
+
+				if b > 0 && current == previous {
+					run++
+					if run == 5 {
+						result += 3
+					} else if run > 5 {
+						result++
+					}
+				} else {
+					run = 1
+				}
+				previous = current
+
+				pattern = (pattern<<1 | boolToInt(current)) & 0x7FF
+				if b >= 10 && (pattern == 0b10111010000 || pattern == 0b00001011101) {
+					result += 40
+				}
+			}
+		}
+	}
+
+	for y := 0; y < s.size-1; y++ {
+		for x := 0; x < s.size-1; x++ {
+			c := dark(x, y)
+			if c == dark(x+1, y) && c == dark(x, y+1) && c == dark(x+1, y+1) {
+				result += 3
+			}
+		}
+	}
+
+	darkCount := 0
+	for _, m := range s.modules {
+		darkCount += boolToInt(m)
+	}
+	percent := darkCount * 100 / len(s.modules)
+	result += 10 * (abs(percent-50) / 5)
+
+	return result
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
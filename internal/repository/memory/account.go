@@ -0,0 +1,202 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type AccountRepository struct {
+	*Store
+}
+
+func NewAccountRepository(store *Store) *AccountRepository {
+	return &AccountRepository{
+		Store: store,
+	}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
+	if account.ID == "" {
+		account.ID = r.ids.NewID()
+	}
+	now := r.clock.Now()
+	account.CreatedAt = now
+	account.UpdatedAt = now
+
+	return r.write(ctx, func(t *tables) error {
+		switch account.Kind {
+		case domain.AccountUser:
+			if _, ok := t.users.get(account.UserID); !ok {
+				return fmt.Errorf("%s: %w", common.ErrCreateAccount, errors.New(common.ErrUserNotFound))
+			}
+		case domain.AccountRestaurant:
+			if _, ok := t.restaurants.get(account.RestaurantID); !ok {
+				return fmt.Errorf("%s: %w", common.ErrCreateAccount, errors.New(common.ErrRestaurantNotFound))
+			}
+		default:
+			return fmt.Errorf("%s: %w", common.ErrCreateAccount, errors.New("unknown account kind "+string(account.Kind)))
+		}
+
+		for _, existing := range t.accounts.rows {
+			if existing.NormalizedEmail == account.NormalizedEmail ||
+				(account.UserID != "" && existing.UserID == account.UserID) {
+				return errors.New(common.ErrAccountExists)
+			}
+		}
+
+		t.accounts.put(account.ID, *account)
+		return nil
+	})
+}
+
+func (r *AccountRepository) GetByID(_ context.Context, id string) (*domain.Account, error) {
+	var account domain.Account
+	var ok bool
+	r.read(func(t *tables) {
+		account, ok = t.accounts.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrAccountNotFound)
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) GetByEmail(_ context.Context, email string) (*domain.Account, error) {
+	var account domain.Account
+	var ok bool
+	r.read(func(t *tables) {
+		for _, existing := range t.accounts.rows {
+			if existing.NormalizedEmail == email {
+				account, ok = existing, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return nil, errors.New(common.ErrAccountNotFound)
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) SetVerificationToken(ctx context.Context, id, tokenHash string) error {
+	return r.write(ctx, func(t *tables) error {
+		account, ok := t.accounts.get(id)
+		if !ok {
+			return errors.New(common.ErrAccountNotFound)
+		}
+
+		account.EmailVerificationHash = tokenHash
+		account.UpdatedAt = r.clock.Now()
+		t.accounts.put(id, account)
+		return nil
+	})
+}
+
+func (r *AccountRepository) VerifyEmail(ctx context.Context, tokenHash string, at time.Time) (*domain.Account, error) {
+	var account domain.Account
+	err := r.write(ctx, func(t *tables) error {
+		for id, existing := range t.accounts.rows {
+			if tokenHash != "" && existing.EmailVerificationHash == tokenHash {
+				existing.EmailVerifiedAt = &at
+				existing.EmailVerificationHash = ""
+				existing.UpdatedAt = at
+				t.accounts.put(id, existing)
+				account = existing
+				return nil
+			}
+		}
+		return errors.New(common.ErrVerificationTokenNotFound)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	if token.ID == "" {
+		token.ID = r.ids.NewID()
+	}
+	if token.FamilyID == "" {
+		token.FamilyID = token.ID
+	}
+	token.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.accounts.get(token.AccountID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateRefreshToken, errors.New(common.ErrAccountNotFound))
+		}
+
+		t.refreshTokens.put(token.ID, *token)
+		return nil
+	})
+}
+
+func (r *AccountRepository) GetRefreshToken(_ context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	var ok bool
+	r.read(func(t *tables) {
+		for _, existing := range t.refreshTokens.rows {
+			if existing.TokenHash == tokenHash {
+				token, ok = existing, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRefreshTokenNotFound)
+	}
+
+	return &token, nil
+}
+
+func (r *AccountRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	var revoked bool
+	err := r.write(ctx, func(t *tables) error {
+		token, ok := t.refreshTokens.get(id)
+		if !ok || token.RevokedAt != nil {
+			return nil
+		}
+
+		token.RevokedAt = &at
+		t.refreshTokens.put(id, token)
+		revoked = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (r *AccountRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		for id, token := range t.refreshTokens.rows {
+			if token.FamilyID == familyID && token.RevokedAt == nil {
+				token.RevokedAt = &at
+				t.refreshTokens.put(id, token)
+			}
+		}
+		return nil
+	})
+}
+
+// deleteAccount deletes the account with its refresh tokens, as the foreign keys of the database
+// cascade.
+func (t *tables) deleteAccount(id string) {
+	t.accounts.delete(id)
+	for tokenID, token := range t.refreshTokens.rows {
+		if token.AccountID == id {
+			t.refreshTokens.delete(tokenID)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type AnalyticsRepository struct {
+	*Store
+}
+
+func NewAnalyticsRepository(store *Store) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		Store: store,
+	}
+}
+
+func (r *AnalyticsRepository) ListBookings(_ context.Context, restaurantID string, from, to time.Time) ([]*domain.Booking, error) {
+	bookings := make([]*domain.Booking, 0)
+	r.read(func(t *tables) {
+		for _, booking := range t.datedBookings(restaurantID, from, to) {
+			bookings = append(bookings, &domain.Booking{
+				ID:           booking.ID,
+				RestaurantID: restaurantID,
+				Date:         booking.Date,
+				Time:         booking.Time,
+				GuestsCount:  booking.GuestsCount,
+				Status:       booking.Status,
+				CreatedAt:    booking.CreatedAt,
+			})
+		}
+	})
+
+	slices.SortFunc(bookings, func(a, b *domain.Booking) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.Time, b.Time))
+	})
+	return bookings, nil
+}
+
+func (r *AnalyticsRepository) CountHourlyDemand(_ context.Context, restaurantID string, from, to time.Time) ([]domain.HourlyDemand, error) {
+	type cell struct {
+		weekday time.Weekday
+		hour    int
+	}
+
+	byCell := make(map[cell]*domain.HourlyDemand)
+	r.read(func(t *tables) {
+		for _, booking := range t.datedBookings(restaurantID, from, to) {
+			hourText, _, _ := strings.Cut(booking.Time, ":")
+			hour, err := strconv.Atoi(hourText)
+			if err != nil {
+				continue
+			}
+
+			key := cell{weekday: booking.Date.Weekday(), hour: hour}
+			demand, ok := byCell[key]
+			if !ok {
+				demand = &domain.HourlyDemand{Weekday: key.weekday, Hour: hour}
+				byCell[key] = demand
+			}
+			demand.Bookings++
+			demand.Guests += booking.GuestsCount
+		}
+	})
+
+	demand := make([]domain.HourlyDemand, 0, len(byCell))
+	for _, hourly := range byCell {
+		demand = append(demand, *hourly)
+	}
+	slices.SortFunc(demand, func(a, b domain.HourlyDemand) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.Hour, b.Hour))
+	})
+	return demand, nil
+}
+
+func (r *AnalyticsRepository) CountSeated(_ context.Context, restaurantID string, at time.Time) (int, int, error) {
+	var bookings, guests int
+	r.read(func(t *tables) {
+		for _, booking := range t.datedBookings(restaurantID, at.AddDate(0, 0, -1), at.AddDate(0, 0, 1)) {
+			if booking.Status != domain.BookingStatusConfirmed {
+				continue
+			}
+			seated, leaves, ok := booking.Seating()
+			if ok && !at.Before(seated) && at.Before(leaves) {
+				bookings++
+				guests += booking.GuestsCount
+			}
+		}
+	})
+
+	return bookings, guests, nil
+}
+
+func (r *AnalyticsRepository) GetResponseLatency(_ context.Context, restaurantID string, from, to time.Time) (*domain.ResponseLatency, error) {
+	latency := &domain.ResponseLatency{RestaurantID: restaurantID, From: from, To: to}
+	r.read(func(t *tables) {
+		summarizeResponses(latency, t.requestedWhileOpen(from, to)[restaurantID])
+	})
+
+	return latency, nil
+}
+
+func (r *AnalyticsRepository) ListSlowResponders(_ context.Context, from, to time.Time, threshold time.Duration, minResponses int) ([]*domain.ResponseLatency, error) {
+	latencies := make([]*domain.ResponseLatency, 0)
+	r.read(func(t *tables) {
+		for restaurantID, bookings := range t.requestedWhileOpen(from, to) {
+			restaurant, ok := t.restaurants.get(restaurantID)
+			if !ok || restaurant.IsTest {
+				continue
+			}
+
+			latency := &domain.ResponseLatency{RestaurantID: restaurantID, RestaurantName: restaurant.Name, From: from, To: to}
+			summarizeResponses(latency, bookings)
+			if latency.Responses >= minResponses && latency.Responses > 0 && latency.P50 > threshold {
+				latencies = append(latencies, latency)
+			}
+		}
+	})
+
+	slices.SortFunc(latencies, func(a, b *domain.ResponseLatency) int {
+		return cmp.Or(cmp.Compare(b.P50, a.P50), cmp.Compare(a.RestaurantID, b.RestaurantID))
+	})
+	return latencies, nil
+}
+
+func (r *AnalyticsRepository) ListCampaignPerformance(_ context.Context, restaurantID string, from, to time.Time) ([]domain.CampaignPerformance, error) {
+	type campaignKey struct {
+		source, medium, campaign, referralCode string
+	}
+
+	byCampaign := make(map[campaignKey]*domain.CampaignPerformance)
+	r.read(func(t *tables) {
+		for restaurant, bookings := range t.requestedBookings(from, to) {
+			if restaurantID != "" && restaurant != restaurantID {
+				continue
+			}
+
+			for _, booking := range bookings {
+				attribution, ok := t.attributions.get(booking.ID)
+				if !ok {
+					continue
+				}
+
+				key := campaignKey{attribution.Source, attribution.Medium, attribution.Campaign, attribution.ReferralCode}
+				performance, ok := byCampaign[key]
+				if !ok {
+					performance = &domain.CampaignPerformance{
+						Source: key.source, Medium: key.medium, Campaign: key.campaign, ReferralCode: key.referralCode,
+					}
+					byCampaign[key] = performance
+				}
+				performance.Bookings++
+				switch booking.Status {
+				case domain.BookingStatusCompleted:
+					performance.Completed++
+					performance.Guests += booking.GuestsCount
+				case domain.BookingStatusCancelled, domain.BookingStatusRejected:
+					performance.Cancelled++
+				}
+			}
+		}
+	})
+
+	campaigns := make([]domain.CampaignPerformance, 0, len(byCampaign))
+	for _, performance := range byCampaign {
+		campaigns = append(campaigns, *performance)
+	}
+	slices.SortFunc(campaigns, func(a, b domain.CampaignPerformance) int {
+		return cmp.Or(cmp.Compare(b.Completed, a.Completed), cmp.Compare(b.Bookings, a.Bookings),
+			cmp.Compare(a.Source, b.Source), cmp.Compare(a.Medium, b.Medium),
+			cmp.Compare(a.Campaign, b.Campaign), cmp.Compare(a.ReferralCode, b.ReferralCode))
+	})
+	return campaigns, nil
+}
+
+// datedBookings returns the bookings of the restaurant dated from from up to to, test bookings
+// left out.
+func (t *tables) datedBookings(restaurantID string, from, to time.Time) []domain.Booking {
+	from, to = dateOf(from), dateOf(to)
+
+	bookings := make([]domain.Booking, 0)
+	for _, booking := range t.bookings.rows {
+		if booking.RestaurantID == restaurantID && !booking.IsTest &&
+			!booking.Date.Before(from) && booking.Date.Before(to) {
+			bookings = append(bookings, booking)
+		}
+	}
+	return bookings
+}
+
+// requestedBookings returns the bookings requested from from up to to by restaurant, test
+// bookings left out.
+func (t *tables) requestedBookings(from, to time.Time) map[string][]domain.Booking {
+	byRestaurant := make(map[string][]domain.Booking)
+	for _, booking := range t.bookings.rows {
+		if !booking.IsTest && !booking.CreatedAt.Before(from) && booking.CreatedAt.Before(to) {
+			byRestaurant[booking.RestaurantID] = append(byRestaurant[booking.RestaurantID], booking)
+		}
+	}
+	return byRestaurant
+}
+
+// requestedWhileOpen is requestedBookings without the bookings requested while their restaurant
+// was closed, which are not held against its response times.
+func (t *tables) requestedWhileOpen(from, to time.Time) map[string][]domain.Booking {
+	byRestaurant := t.requestedBookings(from, to)
+	for restaurantID, bookings := range byRestaurant {
+		byRestaurant[restaurantID] = slices.DeleteFunc(bookings, func(booking domain.Booking) bool {
+			return t.closedAt(restaurantID, booking.CreatedAt)
+		})
+	}
+	return byRestaurant
+}
+
+// summarizeResponses counts the requests, responses and unanswered bookings and sets the
+// percentiles of the time taken to respond.
+func summarizeResponses(latency *domain.ResponseLatency, bookings []domain.Booking) {
+	latencies := make([]float64, 0, len(bookings))
+	for _, booking := range bookings {
+		latency.Requests++
+		if booking.Status == domain.BookingStatusPending {
+			latency.Unanswered++
+		}
+		if respondedAt := respondedAt(booking); respondedAt != nil {
+			latencies = append(latencies, respondedAt.Sub(booking.CreatedAt).Seconds())
+		}
+	}
+	latency.Responses = len(latencies)
+
+	slices.Sort(latencies)
+	latency.P50 = seconds(percentile(latencies, 0.5))
+	latency.P90 = seconds(percentile(latencies, 0.9))
+	latency.P99 = seconds(percentile(latencies, 0.99))
+}
+
+// percentile interpolates the fraction of the sorted values like percentile_cont; it is 0 without
+// values.
+func percentile(sorted []float64, fraction float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	position := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
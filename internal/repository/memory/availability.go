@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type AvailabilityRepository struct {
+	*Store
+}
+
+func NewAvailabilityRepository(store *Store) *AvailabilityRepository {
+	return &AvailabilityRepository{
+		Store: store,
+	}
+}
+
+func (r *AvailabilityRepository) GetByRestaurantAndDate(_ context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
+	date = dateOf(date)
+
+	availabilities := make([]*domain.Availability, 0)
+	r.read(func(t *tables) {
+		for _, availability := range t.availability.rows {
+			if domain.RestaurantID(availability.RestaurantID) == restaurantID && availability.Date.Equal(date) {
+				availabilities = append(availabilities, &availability)
+			}
+		}
+
+		if restaurantTables := t.tablesOf(restaurantID.String()); len(restaurantTables) > 0 {
+			holds := t.tableHolds(restaurantID.String(), date)
+			for _, availability := range availabilities {
+				availability.ReserveHeldTables(restaurantTables, holds)
+			}
+		}
+	})
+
+	slices.SortFunc(availabilities, func(a, b *domain.Availability) int {
+		return cmp.Compare(a.TimeSlot, b.TimeSlot)
+	})
+
+	return availabilities, nil
+}
+
+// SetAvailability stores the capacity of the slot. The capacity of a slot can only go below its
+// reserved seats when force is set.
+func (r *AvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error {
+	if availability.ID == "" {
+		availability.ID = r.ids.NewID()
+	}
+	availability.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(availability.RestaurantID); !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+
+		date := dateOf(availability.Date)
+		for _, existing := range t.availability.rows {
+			if existing.RestaurantID != availability.RestaurantID || !existing.Date.Equal(date) ||
+				existing.TimeSlot != availability.TimeSlot {
+				continue
+			}
+
+			availability.ID = existing.ID
+			availability.Reserved = existing.Reserved
+			if availability.Capacity < existing.Reserved && !force {
+				return errors.New(common.ErrCapacityBelowReserved)
+			}
+
+			existing.Capacity = availability.Capacity
+			existing.UpdatedAt = availability.UpdatedAt
+			t.availability.put(existing.ID, existing)
+			return nil
+		}
+
+		availability.Reserved = 0
+		stored := *availability
+		stored.Date = date
+		t.availability.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) CreateBatch(ctx context.Context, slots []*domain.Availability) error {
+	now := r.clock.Now()
+	for _, slot := range slots {
+		if slot.ID == "" {
+			slot.ID = r.ids.NewID()
+		}
+		slot.Reserved = 0
+		slot.UpdatedAt = now
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		// Every slot is checked before any is stored, so that a rejected batch leaves nothing behind.
+		for _, slot := range slots {
+			if _, ok := t.restaurants.get(slot.RestaurantID); !ok {
+				return errors.New(common.ErrRestaurantNotFound)
+			}
+
+			date := dateOf(slot.Date)
+			for _, existing := range t.availability.rows {
+				if existing.RestaurantID == slot.RestaurantID && existing.Date.Equal(date) && existing.TimeSlot == slot.TimeSlot {
+					return errors.New(common.ErrInsertAvailability)
+				}
+			}
+		}
+
+		for _, slot := range slots {
+			stored := *slot
+			stored.Date = dateOf(slot.Date)
+			t.availability.put(stored.ID, stored)
+		}
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) GetByID(_ context.Context, id string) (*domain.Availability, error) {
+	var availability domain.Availability
+	var ok bool
+	r.read(func(t *tables) {
+		availability, ok = t.availability.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrAvailabilityNotFound)
+	}
+
+	return &availability, nil
+}
+
+// Delete deletes the slot unless seats of it are reserved.
+func (r *AvailabilityRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		availability, ok := t.availability.get(id)
+		if !ok {
+			return errors.New(common.ErrAvailabilityNotFound)
+		}
+		if availability.Reserved > 0 {
+			return errors.New(common.ErrAvailabilityReserved)
+		}
+
+		t.availability.delete(id)
+		t.bury(domain.ExportAvailability, id, r.clock.Now())
+		return nil
+	})
+}
+
+func (r *AvailabilityRepository) UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error {
+	return r.write(ctx, func(t *tables) error {
+		availability, ok := t.availability.get(availabilityID)
+		if !ok {
+			return errors.New(common.ErrAvailabilityNotFound)
+		}
+
+		reserved := availability.Reserved + delta
+		if reserved < 0 {
+			return &domain.ReservedSeatsError{
+				AvailabilityID: availabilityID, Capacity: availability.Capacity, Reserved: availability.Reserved, Delta: delta,
+				Err: domain.ErrReservedSeatsNegative,
+			}
+		}
+		// A forced capacity may already be below the reserved seats, so only growth is limited.
+		if delta > 0 && reserved > availability.Capacity {
+			return &domain.ReservedSeatsError{
+				AvailabilityID: availabilityID, Capacity: availability.Capacity, Reserved: availability.Reserved, Delta: delta,
+				Err: domain.ErrReservedSeatsOverflow,
+			}
+		}
+
+		availability.Reserved = reserved
+		availability.UpdatedAt = r.clock.Now()
+		t.availability.put(availabilityID, availability)
+		return nil
+	})
+}
+
+// ReconcileReservedSeats recomputes reserved seats of the slots dated between from and to from
+// their pending and confirmed bookings and the seats held by booking drafts, stores the recomputed
+// values and returns the slots that drifted. A zero to leaves the range open.
+func (r *AvailabilityRepository) ReconcileReservedSeats(ctx context.Context, from, to time.Time) ([]domain.ReservedSeatsDrift, error) {
+	from = dateOf(from)
+	if !to.IsZero() {
+		to = dateOf(to)
+	}
+
+	drifts := make([]domain.ReservedSeatsDrift, 0)
+	err := r.write(ctx, func(t *tables) error {
+		now := r.clock.Now()
+		for id, availability := range t.availability.rows {
+			if availability.Date.Before(from) || (!to.IsZero() && availability.Date.After(to)) {
+				continue
+			}
+
+			// The guests of a booking seated at a table are not counted against the slot.
+			actual := 0
+			for _, booking := range t.bookings.rows {
+				if booking.RestaurantID == availability.RestaurantID && booking.Date.Equal(availability.Date) &&
+					booking.Time == availability.TimeSlot && booking.TableID == "" && isActive(booking.Status) {
+					actual += booking.GuestsCount
+				}
+			}
+			for _, draft := range t.bookingDrafts.rows {
+				if draft.AvailabilityID == id && draft.Status == domain.BookingDraftHeld {
+					actual += draft.GuestsCount
+				}
+			}
+			if actual == availability.Reserved {
+				continue
+			}
+
+			drifts = append(drifts, domain.ReservedSeatsDrift{
+				AvailabilityID: id,
+				RestaurantID:   availability.RestaurantID,
+				Date:           availability.Date,
+				TimeSlot:       availability.TimeSlot,
+				Recorded:       availability.Reserved,
+				Actual:         actual,
+			})
+			availability.Reserved = actual
+			availability.UpdatedAt = now
+			t.availability.put(id, availability)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
+
+// ListChanges returns up to limit slots of the restaurant changed after the cursor, oldest change
+// first.
+func (r *AvailabilityRepository) ListChanges(_ context.Context, restaurantID domain.RestaurantID, after domain.SyncCursor, limit int) ([]*domain.Availability, error) {
+	availabilities := make([]*domain.Availability, 0)
+	r.read(func(t *tables) {
+		for _, availability := range t.availability.rows {
+			if domain.RestaurantID(availability.RestaurantID) == restaurantID &&
+				changedAfter(availability.UpdatedAt, availability.ID, after.ChangedAt, after.ID) {
+				availabilities = append(availabilities, &availability)
+			}
+		}
+	})
+
+	slices.SortFunc(availabilities, func(a, b *domain.Availability) int {
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(availabilities, 0, limit), nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type AvailabilityAlertRepository struct {
+	*Store
+}
+
+func NewAvailabilityAlertRepository(store *Store) *AvailabilityAlertRepository {
+	return &AvailabilityAlertRepository{
+		Store: store,
+	}
+}
+
+func (r *AvailabilityAlertRepository) Create(ctx context.Context, alert *domain.AvailabilityAlert) error {
+	if alert.ID == "" {
+		alert.ID = r.ids.NewID()
+	}
+	alert.Status = domain.AvailabilityAlertActive
+	alert.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(alert.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateAvailabilityAlert, errors.New(common.ErrRestaurantNotFound))
+		}
+		for _, existing := range t.availabilityAlerts.rows {
+			if existing.Status == domain.AvailabilityAlertActive && existing.UserID == alert.UserID &&
+				existing.RestaurantID == alert.RestaurantID && existing.Date.Equal(dateOf(alert.Date)) {
+				return errors.New(common.ErrAvailabilityAlertExists)
+			}
+		}
+
+		stored := *alert
+		stored.Date = dateOf(alert.Date)
+		t.availabilityAlerts.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *AvailabilityAlertRepository) ListActive(_ context.Context, from time.Time) ([]*domain.AvailabilityAlert, error) {
+	alerts := make([]*domain.AvailabilityAlert, 0)
+	r.read(func(t *tables) {
+		for _, alert := range t.availabilityAlerts.rows {
+			if alert.Status == domain.AvailabilityAlertActive && !alert.Date.Before(dateOf(from)) {
+				alerts = append(alerts, &alert)
+			}
+		}
+	})
+
+	slices.SortFunc(alerts, func(a, b *domain.AvailabilityAlert) int {
+		return cmp.Or(
+			cmp.Compare(a.RestaurantID, b.RestaurantID),
+			a.Date.Compare(b.Date),
+			a.CreatedAt.Compare(b.CreatedAt),
+		)
+	})
+
+	return alerts, nil
+}
+
+func (r *AvailabilityAlertRepository) MarkNotified(ctx context.Context, id, timeSlot string, notifiedAt time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		alert, ok := t.availabilityAlerts.get(id)
+		if !ok || alert.Status != domain.AvailabilityAlertActive {
+			return errors.New(common.ErrAvailabilityAlertNotFound)
+		}
+
+		alert.Status = domain.AvailabilityAlertNotified
+		alert.TimeSlot = timeSlot
+		alert.NotifiedAt = &notifiedAt
+		t.availabilityAlerts.put(id, alert)
+		return nil
+	})
+}
+
+func (r *AvailabilityAlertRepository) ExpireBefore(ctx context.Context, date time.Time) (int, error) {
+	var expired int
+	err := r.write(ctx, func(t *tables) error {
+		for id, alert := range t.availabilityAlerts.rows {
+			if alert.Status == domain.AvailabilityAlertActive && alert.Date.Before(dateOf(date)) {
+				alert.Status = domain.AvailabilityAlertExpired
+				t.availabilityAlerts.put(id, alert)
+				expired++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type BillingRepository struct {
+	*Store
+}
+
+func NewBillingRepository(store *Store) *BillingRepository {
+	return &BillingRepository{
+		Store: store,
+	}
+}
+
+func (r *BillingRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	if subscription.ID == "" {
+		subscription.ID = r.ids.NewID()
+	}
+	subscription.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(subscription.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateSubscription, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		t.subscriptions.put(subscription.ID, *subscription)
+		return nil
+	})
+}
+
+func (r *BillingRepository) GetSubscription(_ context.Context, id string) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	var ok bool
+	r.read(func(t *tables) {
+		subscription, ok = t.subscriptions.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrSubscriptionNotFound)
+	}
+
+	return &subscription, nil
+}
+
+// GetActiveSubscription returns the subscription of the restaurant that is not cancelled.
+func (r *BillingRepository) GetActiveSubscription(_ context.Context, restaurantID string) (*domain.Subscription, error) {
+	var subscription *domain.Subscription
+	r.read(func(t *tables) {
+		for _, s := range t.subscriptions.rows {
+			if s.RestaurantID == restaurantID && s.Active() {
+				subscription = &s
+				return
+			}
+		}
+	})
+	if subscription == nil {
+		return nil, errors.New(common.ErrSubscriptionNotFound)
+	}
+
+	return subscription, nil
+}
+
+// CancelSubscription ends the subscription at the time, unless it already ended.
+func (r *BillingRepository) CancelSubscription(ctx context.Context, id string, at time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		subscription, ok := t.subscriptions.get(id)
+		if !ok || !subscription.Active() {
+			return errors.New(common.ErrSubscriptionNotFound)
+		}
+
+		subscription.CancelledAt = &at
+		t.subscriptions.put(id, subscription)
+		return nil
+	})
+}
+
+// ListSubscriptions returns the subscriptions of the restaurant, or of every restaurant when it
+// is empty, newest first.
+func (r *BillingRepository) ListSubscriptions(_ context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error) {
+	subscriptions := make([]*domain.Subscription, 0)
+	r.read(func(t *tables) {
+		for _, subscription := range t.subscriptions.rows {
+			if restaurantID == "" || subscription.RestaurantID == restaurantID {
+				subscriptions = append(subscriptions, &subscription)
+			}
+		}
+	})
+
+	slices.SortFunc(subscriptions, func(a, b *domain.Subscription) int {
+		return cmp.Or(b.StartedAt.Compare(a.StartedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(subscriptions, offset, limit), nil
+}
+
+// CreateInvoices bills every subscription active in the month from periodStart up to periodEnd
+// that has no invoice for it yet, for the share of the month's days it was active.
+func (r *BillingRepository) CreateInvoices(ctx context.Context, periodStart, periodEnd, issuedAt, dueAt time.Time) ([]*domain.Invoice, error) {
+	periodStart, periodEnd = dateOf(periodStart), dateOf(periodEnd)
+	periodDays := days(periodStart, periodEnd)
+
+	invoices := make([]*domain.Invoice, 0)
+	err := r.write(ctx, func(t *tables) error {
+		if periodDays <= 0 {
+			return fmt.Errorf("%s: %w", common.ErrCreateInvoices, errors.New("empty billing period"))
+		}
+
+		subscriptions := make([]domain.Subscription, 0)
+		for _, subscription := range t.subscriptions.rows {
+			subscriptions = append(subscriptions, subscription)
+		}
+		slices.SortFunc(subscriptions, func(a, b domain.Subscription) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+
+		for _, subscription := range subscriptions {
+			started := dateOf(subscription.StartedAt)
+			end := periodEnd
+			if subscription.CancelledAt != nil && dateOf(*subscription.CancelledAt).Before(periodEnd) {
+				end = dateOf(*subscription.CancelledAt)
+			}
+			if !started.Before(periodEnd) || !end.After(periodStart) || t.invoiced(subscription.ID, periodStart) {
+				continue
+			}
+
+			start := periodStart
+			if started.After(periodStart) {
+				start = started
+			}
+			activeDays := days(start, end)
+			amount := int64(math.Round(float64(subscription.MonthlyPrice) * float64(activeDays) / float64(periodDays)))
+			if amount <= 0 {
+				continue
+			}
+
+			restaurant, _ := t.restaurants.get(subscription.RestaurantID)
+			invoice := domain.Invoice{
+				ID:             r.ids.NewID(),
+				Number:         fmt.Sprintf("INV-%s-%06d", periodStart.Format("200601"), t.invoiceSeq.next()),
+				SubscriptionID: subscription.ID,
+				RestaurantID:   subscription.RestaurantID,
+				RestaurantName: restaurant.Name,
+				Plan:           subscription.Plan,
+				PeriodStart:    periodStart,
+				PeriodEnd:      periodEnd,
+				Amount:         amount,
+				Currency:       subscription.Currency,
+				Status:         domain.InvoiceStatusOpen,
+				IssuedAt:       issuedAt,
+				DueAt:          dueAt,
+				UpdatedAt:      issuedAt,
+			}
+			t.invoices.put(invoice.ID, invoice)
+			invoices = append(invoices, &invoice)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(invoices, func(a, b *domain.Invoice) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+	return invoices, nil
+}
+
+func (r *BillingRepository) GetInvoice(_ context.Context, id string) (*domain.Invoice, error) {
+	var invoice *domain.Invoice
+	r.read(func(t *tables) {
+		invoice = t.invoice(id)
+	})
+	if invoice == nil {
+		return nil, errors.New(common.ErrInvoiceNotFound)
+	}
+
+	return invoice, nil
+}
+
+// ListInvoices returns the invoices matching the filter, newest first.
+func (r *BillingRepository) ListInvoices(_ context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error) {
+	invoices := make([]*domain.Invoice, 0)
+	r.read(func(t *tables) {
+		for id, invoice := range t.invoices.rows {
+			if (filter.RestaurantID == "" || invoice.RestaurantID == filter.RestaurantID) &&
+				(filter.Status == "" || invoice.Status == filter.Status) {
+				invoices = append(invoices, t.invoice(id))
+			}
+		}
+	})
+
+	slices.SortFunc(invoices, func(a, b *domain.Invoice) int {
+		return cmp.Or(b.IssuedAt.Compare(a.IssuedAt), cmp.Compare(b.Number, a.Number))
+	})
+
+	return page(invoices, offset, limit), nil
+}
+
+// UpdateInvoicePayment stores the status, payment time and payment reference of the invoice.
+func (r *BillingRepository) UpdateInvoicePayment(ctx context.Context, invoice *domain.Invoice) error {
+	invoice.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.invoices.get(invoice.ID)
+		if !ok {
+			return errors.New(common.ErrInvoiceNotFound)
+		}
+
+		stored.Status = invoice.Status
+		stored.PaidAt = invoice.PaidAt
+		stored.PaymentID = invoice.PaymentID
+		stored.UpdatedAt = invoice.UpdatedAt
+		t.invoices.put(invoice.ID, stored)
+		return nil
+	})
+}
+
+// RecordPaymentEvent keeps the event and reports false when one with its ID was already kept.
+func (r *BillingRepository) RecordPaymentEvent(ctx context.Context, event *domain.PaymentEvent) (bool, error) {
+	event.ReceivedAt = r.clock.Now()
+
+	var recorded bool
+	err := r.write(ctx, func(t *tables) error {
+		if _, ok := t.paymentEvents.get(event.ID); ok {
+			return nil
+		}
+
+		t.paymentEvents.put(event.ID, *event)
+		recorded = true
+		return nil
+	})
+
+	return recorded, err
+}
+
+// invoice returns the invoice with the name of its restaurant, or nil.
+func (t *tables) invoice(id string) *domain.Invoice {
+	invoice, ok := t.invoices.get(id)
+	if !ok {
+		return nil
+	}
+
+	restaurant, _ := t.restaurants.get(invoice.RestaurantID)
+	invoice.RestaurantName = restaurant.Name
+	return &invoice
+}
+
+// invoiced reports whether the subscription was billed for the period starting on the date.
+func (t *tables) invoiced(subscriptionID string, periodStart time.Time) bool {
+	for _, invoice := range t.invoices.rows {
+		if invoice.SubscriptionID == subscriptionID && invoice.PeriodStart.Equal(periodStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// days returns the number of days from one date to another.
+func days(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,421 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+)
+
+type BookingRepository struct {
+	*Store
+}
+
+func NewBookingRepository(store *Store) *BookingRepository {
+	return &BookingRepository{
+		Store: store,
+	}
+}
+
+func (r *BookingRepository) GetByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
+	var booking domain.Booking
+	var ok bool
+	r.read(func(t *tables) {
+		booking, ok = t.bookings.get(id.String())
+		booking.Alternatives = t.bookingAlternatives(id.String())
+		if attribution, attributed := t.attributions.get(id.String()); attributed {
+			booking.Attribution = &attribution
+		}
+	})
+	if !ok {
+		return nil, errors.New(common.ErrBookingNotFound)
+	}
+
+	if err := tenant.GuardOwner(ctx, "booking", booking.ID, booking.UserID, booking.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+func (r *BookingRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.Booking, error) {
+	return r.list(ctx, compareNewestBookingFirst, func(booking domain.Booking) bool {
+		return domain.RestaurantID(booking.RestaurantID) == restaurantID
+	})
+}
+
+// GetByRestaurantAndDate returns the bookings of the restaurant on the date in every status,
+// ordered by time.
+func (r *BookingRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Booking, error) {
+	date = dateOf(date)
+	return r.list(ctx, func(a, b *domain.Booking) int {
+		return cmp.Or(cmp.Compare(a.Time, b.Time), a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	}, func(booking domain.Booking) bool {
+		return domain.RestaurantID(booking.RestaurantID) == restaurantID && booking.Date.Equal(date)
+	})
+}
+
+func (r *BookingRepository) ListByRestaurant(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	after domain.BookingCursor,
+	limit int,
+) ([]*domain.Booking, error) {
+	bookings, err := r.list(ctx, compareNewestBookingFirst, func(booking domain.Booking) bool {
+		return domain.RestaurantID(booking.RestaurantID) == restaurantID && filter.Matches(&booking) && followsCursor(booking, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page(bookings, 0, limit), nil
+}
+
+func (r *BookingRepository) ListByUser(ctx context.Context, userID domain.UserID, after domain.BookingCursor, limit int) ([]*domain.Booking, error) {
+	bookings, err := r.list(ctx, compareNewestBookingFirst, func(booking domain.Booking) bool {
+		return domain.UserID(booking.UserID) == userID && followsCursor(booking, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page(bookings, 0, limit), nil
+}
+
+func (r *BookingRepository) GetActiveBetween(ctx context.Context, from, to time.Time) ([]*domain.Booking, error) {
+	from, to = dateOf(from), dateOf(to)
+	return r.list(ctx, func(a, b *domain.Booking) int {
+		return cmp.Or(cmp.Compare(a.RestaurantID, b.RestaurantID), a.Date.Compare(b.Date), cmp.Compare(a.Time, b.Time),
+			cmp.Compare(a.ID, b.ID))
+	}, func(booking domain.Booking) bool {
+		return !booking.Date.Before(from) && !booking.Date.After(to) && isActive(booking.Status)
+	})
+}
+
+// list returns the bookings matching the filter, without their alternatives.
+func (r *BookingRepository) list(ctx context.Context, compare func(a, b *domain.Booking) int, match func(booking domain.Booking) bool) ([]*domain.Booking, error) {
+	bookings := make([]*domain.Booking, 0)
+	r.read(func(t *tables) {
+		for _, booking := range t.bookings.rows {
+			if match(booking) {
+				bookings = append(bookings, &booking)
+			}
+		}
+	})
+
+	for _, booking := range bookings {
+		// Every booking must belong to the principal, otherwise the filter is missing its ownership check.
+		if err := tenant.GuardOwner(ctx, "booking", booking.ID, booking.UserID, booking.RestaurantID); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(bookings, compare)
+	return bookings, nil
+}
+
+// compareNewestBookingFirst orders bookings from the latest slot on. Bookings of one slot are
+// ordered by ID, which is generated in the order bookings are made.
+func compareNewestBookingFirst(a, b *domain.Booking) int {
+	return cmp.Or(b.Date.Compare(a.Date), cmp.Compare(b.Time, a.Time), cmp.Compare(b.ID, a.ID))
+}
+
+// followsCursor reports whether the booking comes after the cursor in a list of the latest first.
+func followsCursor(booking domain.Booking, after domain.BookingCursor) bool {
+	if after.IsZero() {
+		return true
+	}
+	return cmp.Or(after.Date.Compare(booking.Date), cmp.Compare(after.Time, booking.Time), cmp.Compare(after.ID, booking.ID)) > 0
+}
+
+func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
+	if booking.ID == "" {
+		booking.ID = r.ids.NewID()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		restaurant, ok := t.restaurants.get(booking.RestaurantID)
+		if !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+		if _, ok := t.users.get(booking.UserID); !ok {
+			return errors.New(common.ErrUserNotFound)
+		}
+		if _, ok := t.bookings.get(booking.ID); ok {
+			return errors.New(common.ErrCreateBooking)
+		}
+		if booking.TableID != "" && t.tableHeld(booking) {
+			return errors.New(common.ErrTableUnavailable)
+		}
+
+		// A booking of a test restaurant is always a test booking.
+		booking.IsTest = booking.IsTest || restaurant.IsTest
+
+		stored := *booking
+		stored.Date = dateOf(booking.Date)
+		stored.Alternatives = nil
+		stored.Attribution = nil
+		t.bookings.put(stored.ID, stored)
+		if booking.Attribution != nil {
+			t.attributions.put(stored.ID, *booking.Attribution)
+		}
+		return nil
+	})
+}
+
+func (r *BookingRepository) UpdateStatus(ctx context.Context, id domain.BookingID, status domain.BookingStatus) error {
+	if !slices.Contains(bookingStatuses, status) {
+		return errors.New(common.ErrInvalidBookingStatus)
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		booking, ok := t.bookings.get(id.String())
+		if !ok {
+			return errors.New(common.ErrBookingNotFound)
+		}
+
+		// A cancelled or rejected booking gives its pre-ordered units back to the day.
+		if isActive(booking.Status) && (status == domain.BookingStatusCancelled || status == domain.BookingStatusRejected) {
+			t.releasePreOrderItems(booking)
+		}
+
+		now := r.clock.Now()
+		booking.Status = status
+		booking.UpdatedAt = now
+		switch status {
+		case domain.BookingStatusConfirmed:
+			booking.ConfirmedAt = ptr(now)
+		case domain.BookingStatusRejected:
+			booking.RejectedAt = ptr(now)
+		case domain.BookingStatusCompleted:
+			booking.CompletedAt = ptr(now)
+		}
+		t.bookings.put(id.String(), booking)
+		return nil
+	})
+}
+
+// ChangeStatus takes the booking from one of the from statuses to the status, stamping the change
+// at the time, and reports false when the booking is in another status.
+func (r *BookingRepository) ChangeStatus(
+	ctx context.Context,
+	id domain.BookingID,
+	from []domain.BookingStatus,
+	status domain.BookingStatus,
+	at time.Time,
+) (bool, error) {
+	if !slices.Contains(bookingStatuses, status) {
+		return false, errors.New(common.ErrInvalidBookingStatus)
+	}
+
+	var changed bool
+	err := r.write(ctx, func(t *tables) error {
+		booking, ok := t.bookings.get(id.String())
+		if !ok {
+			return errors.New(common.ErrBookingNotFound)
+		}
+		if !slices.Contains(from, booking.Status) {
+			return nil
+		}
+
+		// A cancelled or rejected booking gives its pre-ordered units back to the day.
+		if isActive(booking.Status) && (status == domain.BookingStatusCancelled || status == domain.BookingStatusRejected) {
+			t.releasePreOrderItems(booking)
+		}
+
+		booking.Status = status
+		booking.UpdatedAt = at
+		switch status {
+		case domain.BookingStatusConfirmed:
+			booking.ConfirmedAt = ptr(at)
+		case domain.BookingStatusRejected:
+			booking.RejectedAt = ptr(at)
+		case domain.BookingStatusCompleted:
+			booking.CompletedAt = ptr(at)
+		}
+		t.bookings.put(id.String(), booking)
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+func (r *BookingRepository) AddAlternative(ctx context.Context, alternative *domain.BookingAlternative) error {
+	if alternative.ID == "" {
+		alternative.ID = r.ids.NewID()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.bookings.get(alternative.BookingID); !ok {
+			return errors.New(common.ErrBookingNotFound)
+		}
+
+		stored := *alternative
+		stored.Date = dateOf(alternative.Date)
+		t.alternatives.put(stored.ID, stored)
+		return nil
+	})
+}
+
+// AcceptAlternative moves the booking to the date and time of an open alternative, seated at the
+// table, and confirms it.
+func (r *BookingRepository) AcceptAlternative(ctx context.Context, alternativeID, tableID string) error {
+	return r.write(ctx, func(t *tables) error {
+		alternative, ok := t.alternatives.get(alternativeID)
+		if !ok || alternative.AcceptedAt != nil || alternative.RejectedAt != nil {
+			return errors.New(common.ErrAlternativeNotFound)
+		}
+
+		booking, ok := t.bookings.get(alternative.BookingID)
+		if ok {
+			moved := booking
+			moved.Date = dateOf(alternative.Date)
+			moved.Time = alternative.Time
+			moved.TableID = tableID
+			if tableID != "" && t.tableHeld(&moved) {
+				return errors.New(common.ErrTableUnavailable)
+			}
+			if err := t.movePreOrderItems(booking, alternative.Date); err != nil {
+				return err
+			}
+		}
+
+		now := r.clock.Now()
+		alternative.AcceptedAt = ptr(now)
+		t.alternatives.put(alternativeID, alternative)
+
+		if !ok {
+			return nil
+		}
+		booking.Date = alternative.Date
+		booking.Time = alternative.Time
+		booking.TableRequested = booking.TableRequested && booking.TableID == tableID
+		booking.TableID = tableID
+		booking.UpdatedAt = now
+		booking.Status = domain.BookingStatusConfirmed
+		booking.ConfirmedAt = ptr(now)
+		t.bookings.put(booking.ID, booking)
+		return nil
+	})
+}
+
+func (r *BookingRepository) RejectAlternative(ctx context.Context, alternativeID string) error {
+	return r.write(ctx, func(t *tables) error {
+		alternative, ok := t.alternatives.get(alternativeID)
+		if !ok || alternative.AcceptedAt != nil || alternative.RejectedAt != nil {
+			return errors.New(common.ErrAlternativeNotFound)
+		}
+
+		alternative.RejectedAt = ptr(r.clock.Now())
+		t.alternatives.put(alternativeID, alternative)
+		return nil
+	})
+}
+
+func (r *BookingRepository) GetAlternativeByID(_ context.Context, alternativeID string) (*domain.BookingAlternative, error) {
+	var alternative domain.BookingAlternative
+	var ok bool
+	r.read(func(t *tables) {
+		alternative, ok = t.alternatives.get(alternativeID)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrAlternativeNotFound)
+	}
+
+	return &alternative, nil
+}
+
+var bookingStatuses = []domain.BookingStatus{
+	domain.BookingStatusPending,
+	domain.BookingStatusConfirmed,
+	domain.BookingStatusRejected,
+	domain.BookingStatusCancelled,
+	domain.BookingStatusCompleted,
+}
+
+// isActive reports whether a booking in the status holds its seats.
+func isActive(status domain.BookingStatus) bool {
+	return status == domain.BookingStatusPending || status == domain.BookingStatusConfirmed
+}
+
+// tableHeld reports whether another active booking holds the table of the booking during a part
+// of its seating.
+func (t *tables) tableHeld(booking *domain.Booking) bool {
+	start, end, ok := booking.Seating()
+	if !ok {
+		return false
+	}
+	for _, other := range t.bookings.rows {
+		if other.ID == booking.ID || other.TableID != booking.TableID || !isActive(other.Status) {
+			continue
+		}
+		if otherStart, otherEnd, ok := other.Seating(); ok && otherStart.Before(end) && start.Before(otherEnd) {
+			return true
+		}
+	}
+	return false
+}
+
+// bookingAlternatives returns the alternatives offered for the booking, newest first.
+func (t *tables) bookingAlternatives(bookingID string) []domain.BookingAlternative {
+	alternatives := make([]domain.BookingAlternative, 0)
+	for _, alternative := range t.alternatives.rows {
+		if alternative.BookingID == bookingID {
+			alternatives = append(alternatives, alternative)
+		}
+	}
+
+	slices.SortFunc(alternatives, func(a, b domain.BookingAlternative) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return alternatives
+}
+
+// deleteBooking deletes the booking with every record that belongs to it.
+func (t *tables) deleteBooking(id string, at time.Time) {
+	t.bookings.delete(id)
+	t.bury(domain.ExportBookings, id, at)
+	t.anonymized.delete(id)
+	t.preOrders.delete(id)
+	t.attributions.delete(id)
+
+	for alternativeID, alternative := range t.alternatives.rows {
+		if alternative.BookingID == id {
+			t.alternatives.delete(alternativeID)
+		}
+	}
+	for linkID, link := range t.bookingLinks.rows {
+		if link.BookingID == id {
+			t.bookingLinks.delete(linkID)
+		}
+	}
+	for reviewID, review := range t.reviews.rows {
+		if review.BookingID == id {
+			t.deleteReview(reviewID, at)
+		}
+	}
+	for evaluationID, evaluation := range t.evaluations.rows {
+		if evaluation.BookingID == id {
+			t.evaluations.delete(evaluationID)
+		}
+	}
+	for transferID, transfer := range t.bookingTransfers.rows {
+		switch {
+		case transfer.BookingID == id:
+			t.bookingTransfers.delete(transferID)
+		case transfer.NewBookingID == id:
+			transfer.NewBookingID = ""
+			t.bookingTransfers.put(transferID, transfer)
+		}
+	}
+	for draftID, draft := range t.bookingDrafts.rows {
+		if draft.BookingID == id {
+			draft.BookingID = ""
+			t.bookingDrafts.put(draftID, draft)
+		}
+	}
+}
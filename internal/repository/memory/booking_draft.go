@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+)
+
+type BookingDraftRepository struct {
+	*Store
+}
+
+func NewBookingDraftRepository(store *Store) *BookingDraftRepository {
+	return &BookingDraftRepository{
+		Store: store,
+	}
+}
+
+func (r *BookingDraftRepository) Create(ctx context.Context, draft *domain.BookingDraft) error {
+	if draft.ID == "" {
+		draft.ID = r.ids.NewID()
+	}
+	now := r.clock.Now()
+	draft.CreatedAt = now
+	draft.UpdatedAt = now
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(draft.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateBookingDraft, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		t.bookingDrafts.put(draft.ID, cloneDraft(*draft))
+		return nil
+	})
+}
+
+func (r *BookingDraftRepository) GetByID(ctx context.Context, id string) (*domain.BookingDraft, error) {
+	var draft domain.BookingDraft
+	var ok bool
+	r.read(func(t *tables) {
+		draft, ok = t.bookingDrafts.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrBookingDraftNotFound)
+	}
+
+	if err := tenant.GuardOwner(ctx, "booking_draft", draft.ID, draft.UserID, draft.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	draft = cloneDraft(draft)
+	return &draft, nil
+}
+
+func (r *BookingDraftRepository) Update(ctx context.Context, draft *domain.BookingDraft) error {
+	draft.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.bookingDrafts.get(draft.ID)
+		if !ok {
+			return errors.New(common.ErrBookingDraftNotFound)
+		}
+
+		stored.Status = draft.Status
+		stored.Date = dateOf(draft.Date)
+		stored.Time = draft.Time
+		stored.AvailabilityID = draft.AvailabilityID
+		stored.PreOrder = draft.PreOrder
+		stored.BookingID = draft.BookingID
+		stored.ExpiresAt = draft.ExpiresAt
+		stored.UpdatedAt = draft.UpdatedAt
+		t.bookingDrafts.put(draft.ID, cloneDraft(stored))
+		return nil
+	})
+}
+
+func (r *BookingDraftRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.bookingDrafts.get(id); !ok {
+			return errors.New(common.ErrBookingDraftNotFound)
+		}
+
+		t.bookingDrafts.delete(id)
+		return nil
+	})
+}
+
+func (r *BookingDraftRepository) ListExpired(_ context.Context, now time.Time, limit int) ([]*domain.BookingDraft, error) {
+	drafts := make([]*domain.BookingDraft, 0)
+	r.read(func(t *tables) {
+		for _, draft := range t.bookingDrafts.rows {
+			if draft.Expired(now) {
+				draft = cloneDraft(draft)
+				drafts = append(drafts, &draft)
+			}
+		}
+	})
+
+	slices.SortFunc(drafts, func(a, b *domain.BookingDraft) int {
+		return cmp.Or(a.ExpiresAt.Compare(b.ExpiresAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(drafts, 0, limit), nil
+}
+
+// cloneDraft copies the pre-order of the draft, so that the stored draft is not shared with the
+// caller.
+func cloneDraft(draft domain.BookingDraft) domain.BookingDraft {
+	draft.PreOrder = slices.Clone(draft.PreOrder)
+	if draft.PreOrder == nil {
+		draft.PreOrder = make([]domain.PreOrderItem, 0)
+	}
+	return draft
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type BookingLinkRepository struct {
+	*Store
+}
+
+func NewBookingLinkRepository(store *Store) *BookingLinkRepository {
+	return &BookingLinkRepository{
+		Store: store,
+	}
+}
+
+func (r *BookingLinkRepository) Create(ctx context.Context, link *domain.BookingLink) error {
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = r.clock.Now()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.bookings.get(link.BookingID); !ok {
+			return errors.New(common.ErrBookingNotFound)
+		}
+		if _, ok := t.bookingLinks.get(link.ID); ok {
+			return errors.New(common.ErrCreateBookingLink)
+		}
+
+		t.bookingLinks.put(link.ID, *link)
+		return nil
+	})
+}
+
+func (r *BookingLinkRepository) GetByID(_ context.Context, id string) (*domain.BookingLink, error) {
+	var link domain.BookingLink
+	var ok bool
+	r.read(func(t *tables) {
+		link, ok = t.bookingLinks.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrBookingLinkNotFound)
+	}
+
+	return &link, nil
+}
+
+func (r *BookingLinkRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		link, ok := t.bookingLinks.get(id)
+		if !ok || link.UsedAt != nil {
+			return errors.New(common.ErrBookingLinkAlreadyUsed)
+		}
+
+		link.UsedAt = &usedAt
+		t.bookingLinks.put(id, link)
+		return nil
+	})
+}
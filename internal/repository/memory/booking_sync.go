@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type BookingSyncRepository struct {
+	*Store
+}
+
+func NewBookingSyncRepository(store *Store) *BookingSyncRepository {
+	return &BookingSyncRepository{
+		Store: store,
+	}
+}
+
+func (r *BookingSyncRepository) Save(ctx context.Context, result *domain.BookingSyncResult) error {
+	key := bookingSyncKey{UserID: result.UserID, Key: result.Key}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.bookingSyncResults.get(key); ok {
+			return errors.New(common.ErrBookingSyncKeyUsed)
+		}
+
+		stored := *result
+		stored.Replayed = false
+		t.bookingSyncResults.put(key, stored)
+		return nil
+	})
+}
+
+func (r *BookingSyncRepository) GetByKey(_ context.Context, userID, key string) (*domain.BookingSyncResult, error) {
+	var result domain.BookingSyncResult
+	var ok bool
+	r.read(func(t *tables) {
+		result, ok = t.bookingSyncResults.get(bookingSyncKey{UserID: userID, Key: key})
+	})
+	if !ok {
+		return nil, errors.New(common.ErrBookingSyncResultNotFound)
+	}
+
+	return &result, nil
+}
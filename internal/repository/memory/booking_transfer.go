@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type BookingTransferRepository struct {
+	*Store
+}
+
+func NewBookingTransferRepository(store *Store) *BookingTransferRepository {
+	return &BookingTransferRepository{
+		Store: store,
+	}
+}
+
+func (r *BookingTransferRepository) Create(ctx context.Context, transfer *domain.BookingTransfer) error {
+	if transfer.ID == "" {
+		transfer.ID = r.ids.NewID()
+	}
+	transfer.Status = domain.BookingTransferPending
+	transfer.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.bookings.get(transfer.BookingID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateBookingTransfer, errors.New(common.ErrBookingNotFound))
+		}
+		for _, existing := range t.bookingTransfers.rows {
+			if existing.BookingID == transfer.BookingID && existing.Status == domain.BookingTransferPending {
+				return errors.New(common.ErrBookingTransferExists)
+			}
+		}
+
+		stored := *transfer
+		stored.Date = dateOf(transfer.Date)
+		t.bookingTransfers.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *BookingTransferRepository) GetByID(_ context.Context, id string) (*domain.BookingTransfer, error) {
+	var transfer domain.BookingTransfer
+	var ok bool
+	r.read(func(t *tables) {
+		transfer, ok = t.bookingTransfers.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrBookingTransferNotFound)
+	}
+
+	return &transfer, nil
+}
+
+func (r *BookingTransferRepository) ListByBooking(_ context.Context, bookingID string) ([]*domain.BookingTransfer, error) {
+	transfers := make([]*domain.BookingTransfer, 0)
+	r.read(func(t *tables) {
+		for _, transfer := range t.bookingTransfers.rows {
+			if transfer.BookingID == bookingID || transfer.NewBookingID == bookingID {
+				transfers = append(transfers, &transfer)
+			}
+		}
+	})
+
+	slices.SortFunc(transfers, func(a, b *domain.BookingTransfer) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return transfers, nil
+}
+
+func (r *BookingTransferRepository) Decide(ctx context.Context, transfer *domain.BookingTransfer) error {
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.bookingTransfers.get(transfer.ID)
+		if !ok || stored.Status != domain.BookingTransferPending {
+			return errors.New(common.ErrBookingTransferNotFound)
+		}
+
+		stored.Status = transfer.Status
+		stored.NewBookingID = transfer.NewBookingID
+		stored.DecidedAt = transfer.DecidedAt
+		t.bookingTransfers.put(stored.ID, stored)
+		return nil
+	})
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type CustomDomainRepository struct {
+	*Store
+}
+
+func NewCustomDomainRepository(store *Store) *CustomDomainRepository {
+	return &CustomDomainRepository{
+		Store: store,
+	}
+}
+
+func (r *CustomDomainRepository) Create(ctx context.Context, customDomain *domain.CustomDomain) error {
+	if customDomain.ID == "" {
+		customDomain.ID = r.ids.NewID()
+	}
+	customDomain.Status = domain.CustomDomainPending
+	customDomain.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(customDomain.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateCustomDomain, errors.New(common.ErrRestaurantNotFound))
+		}
+		for _, existing := range t.customDomains.rows {
+			if existing.Domain == customDomain.Domain {
+				return errors.New(common.ErrCustomDomainTaken)
+			}
+		}
+
+		t.customDomains.put(customDomain.ID, *customDomain)
+		return nil
+	})
+}
+
+func (r *CustomDomainRepository) GetByID(_ context.Context, id string) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+	var ok bool
+	r.read(func(t *tables) {
+		customDomain, ok = t.customDomains.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrCustomDomainNotFound)
+	}
+
+	return &customDomain, nil
+}
+
+func (r *CustomDomainRepository) GetByDomain(_ context.Context, name string) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+	var ok bool
+	r.read(func(t *tables) {
+		for _, existing := range t.customDomains.rows {
+			if existing.Domain == name {
+				customDomain, ok = existing, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return nil, errors.New(common.ErrCustomDomainNotFound)
+	}
+
+	return &customDomain, nil
+}
+
+func (r *CustomDomainRepository) ListByRestaurant(_ context.Context, restaurantID string) ([]*domain.CustomDomain, error) {
+	customDomains := make([]*domain.CustomDomain, 0)
+	r.read(func(t *tables) {
+		for _, customDomain := range t.customDomains.rows {
+			if customDomain.RestaurantID == restaurantID {
+				customDomains = append(customDomains, &customDomain)
+			}
+		}
+	})
+
+	slices.SortFunc(customDomains, func(a, b *domain.CustomDomain) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return customDomains, nil
+}
+
+func (r *CustomDomainRepository) MarkVerified(ctx context.Context, id string, at time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		customDomain, ok := t.customDomains.get(id)
+		if !ok {
+			return errors.New(common.ErrCustomDomainNotFound)
+		}
+
+		customDomain.Status = domain.CustomDomainVerified
+		customDomain.VerifiedAt = &at
+		t.customDomains.put(id, customDomain)
+		return nil
+	})
+}
+
+func (r *CustomDomainRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.customDomains.get(id); !ok {
+			return errors.New(common.ErrCustomDomainNotFound)
+		}
+
+		t.customDomains.delete(id)
+		return nil
+	})
+}
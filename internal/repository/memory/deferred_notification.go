@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type DeferredNotificationRepository struct {
+	*Store
+}
+
+func NewDeferredNotificationRepository(store *Store) *DeferredNotificationRepository {
+	return &DeferredNotificationRepository{
+		Store: store,
+	}
+}
+
+func (r *DeferredNotificationRepository) Create(ctx context.Context, notification *domain.DeferredNotification) error {
+	if notification.ID == "" {
+		notification.ID = r.ids.NewID()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = r.clock.Now()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.deferred.get(notification.ID); ok {
+			return errors.New(common.ErrCreateDeferredNotification)
+		}
+
+		t.deferred.put(notification.ID, *notification)
+		return nil
+	})
+}
+
+func (r *DeferredNotificationRepository) Update(ctx context.Context, notification *domain.DeferredNotification) error {
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.deferred.get(notification.ID)
+		if !ok {
+			return errors.New(common.ErrDeferredNotificationNotFound)
+		}
+
+		stored.DeliverAt = notification.DeliverAt
+		t.deferred.put(notification.ID, stored)
+		return nil
+	})
+}
+
+func (r *DeferredNotificationRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.DeferredNotification, error) {
+	notifications := make([]*domain.DeferredNotification, 0)
+	err := r.write(ctx, func(t *tables) error {
+		for _, notification := range t.deferred.rows {
+			if !notification.DeliverAt.After(now) {
+				notifications = append(notifications, &notification)
+			}
+		}
+
+		slices.SortFunc(notifications, func(a, b *domain.DeferredNotification) int {
+			return cmp.Or(a.DeliverAt.Compare(b.DeliverAt), cmp.Compare(a.ID, b.ID))
+		})
+		notifications = page(notifications, 0, limit)
+
+		for _, notification := range notifications {
+			notification.DeliverAt = leaseUntil
+			t.deferred.put(notification.ID, *notification)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (r *DeferredNotificationRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.deferred.get(id); !ok {
+			return errors.New(common.ErrDeferredNotificationNotFound)
+		}
+
+		t.deferred.delete(id)
+		return nil
+	})
+}
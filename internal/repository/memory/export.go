@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+// exportRow is the current state of a record of an exported entity.
+type exportRow struct {
+	ID        string
+	UpdatedAt time.Time
+	Record    any
+}
+
+type ExportRepository struct {
+	*Store
+}
+
+func NewExportRepository(store *Store) *ExportRepository {
+	return &ExportRepository{
+		Store: store,
+	}
+}
+
+func (r *ExportRepository) ListChanges(
+	_ context.Context,
+	entity domain.ExportEntity,
+	after time.Time,
+	afterID string,
+	until time.Time,
+	limit int,
+) ([]*domain.ExportRecord, error) {
+	if !entity.IsValid() {
+		return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, errors.New("unknown entity "+string(entity)))
+	}
+
+	var rows []exportRow
+	r.read(func(t *tables) {
+		rows = t.exportRows(entity)
+	})
+
+	rows = slices.DeleteFunc(rows, func(row exportRow) bool {
+		return !changedAfter(row.UpdatedAt, row.ID, after, afterID) || row.UpdatedAt.After(until)
+	})
+	slices.SortFunc(rows, func(a, b exportRow) int {
+		return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	rows = page(rows, 0, limit)
+
+	records := make([]*domain.ExportRecord, 0, len(rows))
+	for _, row := range rows {
+		data, err := json.Marshal(row.Record)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, err)
+		}
+		records = append(records, &domain.ExportRecord{
+			Entity:    entity,
+			ID:        row.ID,
+			UpdatedAt: row.UpdatedAt,
+			Data:      data,
+		})
+	}
+
+	return records, nil
+}
+
+// changedAfter reports whether a change made at changedAt to the record with the ID comes after
+// the position; a non-empty afterID continues past the records changed exactly at after.
+func changedAfter(changedAt time.Time, id string, after time.Time, afterID string) bool {
+	return changedAt.After(after) || (afterID != "" && changedAt.Equal(after) && id > afterID)
+}
+
+// exportRows returns every record of the entity, which must be valid, but test restaurants and
+// bookings.
+func (t *tables) exportRows(entity domain.ExportEntity) []exportRow {
+	var rows []exportRow
+	switch entity {
+	case domain.ExportRestaurants:
+		for id, restaurant := range t.restaurants.rows {
+			if restaurant.IsTest {
+				continue
+			}
+			rows = append(rows, exportRow{ID: id, UpdatedAt: restaurant.UpdatedAt, Record: restaurant})
+		}
+	case domain.ExportUsers:
+		for id, user := range t.users.rows {
+			rows = append(rows, exportRow{ID: id, UpdatedAt: user.UpdatedAt, Record: user})
+		}
+	case domain.ExportAvailability:
+		for id, availability := range t.availability.rows {
+			rows = append(rows, exportRow{ID: id, UpdatedAt: availability.UpdatedAt, Record: availability})
+		}
+	case domain.ExportBookings:
+		for id, booking := range t.bookings.rows {
+			if booking.IsTest {
+				continue
+			}
+			rows = append(rows, exportRow{ID: id, UpdatedAt: booking.UpdatedAt, Record: booking})
+		}
+	case domain.ExportMenuItems:
+		for id, item := range t.menuItems.rows {
+			rows = append(rows, exportRow{ID: id, UpdatedAt: item.UpdatedAt, Record: item.MenuItem})
+		}
+	case domain.ExportReviews:
+		for id, review := range t.reviews.rows {
+			rows = append(rows, exportRow{ID: id, UpdatedAt: review.UpdatedAt, Record: review})
+		}
+	case domain.ExportReviewReplies:
+		for id, reply := range t.reviewReplies.rows {
+			rows = append(rows, exportRow{ID: id, UpdatedAt: reply.UpdatedAt, Record: reply})
+		}
+	}
+	return rows
+}
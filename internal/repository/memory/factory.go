@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+)
+
+// RepositoryFactory creates the repositories of one store.
+type RepositoryFactory struct {
+	store *Store
+}
+
+func NewRepositoryFactory(store *Store) *RepositoryFactory {
+	return &RepositoryFactory{
+		store: store,
+	}
+}
+
+func (f *RepositoryFactory) Restaurant() repository.RestaurantRepository {
+	return NewRestaurantRepository(f.store)
+}
+
+func (f *RepositoryFactory) WorkingHours() repository.WorkingHoursRepository {
+	return NewWorkingHoursRepository(f.store)
+}
+
+func (f *RepositoryFactory) Availability() repository.AvailabilityRepository {
+	return NewAvailabilityRepository(f.store)
+}
+
+func (f *RepositoryFactory) Booking() repository.BookingRepository {
+	return NewBookingRepository(f.store)
+}
+
+func (f *RepositoryFactory) User() repository.UserRepository {
+	return NewUserRepository(f.store)
+}
+
+func (f *RepositoryFactory) Notification() repository.NotificationRepository {
+	return NewNotificationRepository(f.store)
+}
+
+func (f *RepositoryFactory) NotificationSettings() repository.NotificationSettingsRepository {
+	return NewNotificationSettingsRepository(f.store)
+}
+
+func (f *RepositoryFactory) BookingLink() repository.BookingLinkRepository {
+	return NewBookingLinkRepository(f.store)
+}
+
+func (f *RepositoryFactory) Menu() repository.MenuRepository {
+	return NewMenuRepository(f.store)
+}
+
+func (f *RepositoryFactory) Image() repository.ImageRepository {
+	return NewImageRepository(f.store)
+}
+
+func (f *RepositoryFactory) Review() repository.ReviewRepository {
+	return NewReviewRepository(f.store)
+}
+
+func (f *RepositoryFactory) Retention() repository.RetentionRepository {
+	return NewRetentionRepository(f.store)
+}
+
+func (f *RepositoryFactory) Export() repository.ExportRepository {
+	return NewExportRepository(f.store)
+}
+
+func (f *RepositoryFactory) Sync() repository.SyncRepository {
+	return NewSyncRepository(f.store)
+}
+
+func (f *RepositoryFactory) NotificationFailure() repository.NotificationFailureRepository {
+	return NewNotificationFailureRepository(f.store)
+}
+
+func (f *RepositoryFactory) DeferredNotification() repository.DeferredNotificationRepository {
+	return NewDeferredNotificationRepository(f.store)
+}
+
+func (f *RepositoryFactory) RestaurantPerformance() repository.RestaurantPerformanceRepository {
+	return NewRestaurantPerformanceRepository(f.store)
+}
+
+func (f *RepositoryFactory) Reengagement() repository.ReengagementRepository {
+	return NewReengagementRepository(f.store)
+}
+
+func (f *RepositoryFactory) Analytics() repository.AnalyticsRepository {
+	return NewAnalyticsRepository(f.store)
+}
+
+func (f *RepositoryFactory) Quota() repository.QuotaRepository {
+	return NewQuotaRepository(f.store)
+}
+
+func (f *RepositoryFactory) Billing() repository.BillingRepository {
+	return NewBillingRepository(f.store)
+}
+
+func (f *RepositoryFactory) Incentive() repository.IncentiveRepository {
+	return NewIncentiveRepository(f.store)
+}
+
+func (f *RepositoryFactory) RestaurantLocation() repository.RestaurantLocationRepository {
+	return NewRestaurantLocationRepository(f.store)
+}
+
+func (f *RepositoryFactory) AvailabilityAlert() repository.AvailabilityAlertRepository {
+	return NewAvailabilityAlertRepository(f.store)
+}
+
+func (f *RepositoryFactory) BookingDraft() repository.BookingDraftRepository {
+	return NewBookingDraftRepository(f.store)
+}
+
+func (f *RepositoryFactory) Organization() repository.OrganizationRepository {
+	return NewOrganizationRepository(f.store)
+}
+
+func (f *RepositoryFactory) BookingTransfer() repository.BookingTransferRepository {
+	return NewBookingTransferRepository(f.store)
+}
+
+func (f *RepositoryFactory) SLO() repository.SLORepository {
+	return NewSLORepository(f.store)
+}
+
+func (f *RepositoryFactory) BookingSync() repository.BookingSyncRepository {
+	return NewBookingSyncRepository(f.store)
+}
+
+func (f *RepositoryFactory) RestaurantClaim() repository.RestaurantClaimRepository {
+	return NewRestaurantClaimRepository(f.store)
+}
+
+func (f *RepositoryFactory) CustomDomain() repository.CustomDomainRepository {
+	return NewCustomDomainRepository(f.store)
+}
+
+func (f *RepositoryFactory) RestaurantClosure() repository.RestaurantClosureRepository {
+	return NewRestaurantClosureRepository(f.store)
+}
+
+func (f *RepositoryFactory) SpecialDay() repository.SpecialDayRepository {
+	return NewSpecialDayRepository(f.store)
+}
+
+func (f *RepositoryFactory) Table() repository.TableRepository {
+	return NewTableRepository(f.store)
+}
+
+func (f *RepositoryFactory) WidgetSettings() repository.WidgetSettingsRepository {
+	return NewWidgetSettingsRepository(f.store)
+}
+
+func (f *RepositoryFactory) QueryStats() repository.QueryStatsRepository {
+	return NewQueryStatsRepository(f.store)
+}
+
+func (f *RepositoryFactory) Account() repository.AccountRepository {
+	return NewAccountRepository(f.store)
+}
+
+func (f *RepositoryFactory) Transactor() repository.Transactor {
+	return NewTransactor(f.store)
+}
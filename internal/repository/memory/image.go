@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type ImageRepository struct {
+	*Store
+}
+
+func NewImageRepository(store *Store) *ImageRepository {
+	return &ImageRepository{
+		Store: store,
+	}
+}
+
+func (r *ImageRepository) Create(ctx context.Context, image *domain.RestaurantImage) error {
+	if image.ID == "" {
+		image.ID = r.ids.NewID()
+	}
+	if image.CreatedAt.IsZero() {
+		image.CreatedAt = r.clock.Now()
+	}
+	image.Size = len(image.Data)
+
+	for i := range image.Variants {
+		image.Variants[i].ImageID = image.ID
+		image.Variants[i].UpdatedAt = image.CreatedAt
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(image.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateImage, errors.New(common.ErrRestaurantNotFound))
+		}
+		if _, ok := t.images.get(image.ID); ok {
+			return errors.New(common.ErrCreateImage)
+		}
+
+		stored := *image
+		stored.Data = bytes.Clone(image.Data)
+		stored.Variants = nil
+		t.images.put(stored.ID, stored)
+
+		for _, variant := range image.Variants {
+			t.imageVariants.put(variantKey{ImageID: image.ID, ContentType: variant.ContentType}, domain.RestaurantImageVariant{
+				ImageID:     variant.ImageID,
+				ContentType: variant.ContentType,
+				Status:      variant.Status,
+				UpdatedAt:   variant.UpdatedAt,
+			})
+		}
+		return nil
+	})
+}
+
+func (r *ImageRepository) GetByID(_ context.Context, id string) (*domain.RestaurantImage, error) {
+	var image domain.RestaurantImage
+	var ok bool
+	r.read(func(t *tables) {
+		image, ok = t.images.get(id)
+		image.Variants = t.variants(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrImageNotFound)
+	}
+
+	image.Data = bytes.Clone(image.Data)
+	return &image, nil
+}
+
+func (r *ImageRepository) ListByRestaurant(_ context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	images := make([]*domain.RestaurantImage, 0)
+	r.read(func(t *tables) {
+		for _, image := range t.images.rows {
+			if image.RestaurantID == restaurantID {
+				image.Data = nil
+				image.Variants = t.variants(image.ID)
+				images = append(images, &image)
+			}
+		}
+	})
+
+	slices.SortFunc(images, func(a, b *domain.RestaurantImage) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return images, nil
+}
+
+func (r *ImageRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.images.get(id); !ok {
+			return errors.New(common.ErrImageNotFound)
+		}
+
+		t.deleteImage(id)
+		return nil
+	})
+}
+
+func (r *ImageRepository) GetVariants(_ context.Context, imageID string) ([]domain.RestaurantImageVariant, error) {
+	var variants []domain.RestaurantImageVariant
+	r.read(func(t *tables) {
+		variants = t.variants(imageID)
+	})
+
+	return variants, nil
+}
+
+// GetVariantData returns the encoded variant; only ready variants are found.
+func (r *ImageRepository) GetVariantData(_ context.Context, imageID, contentType string) ([]byte, error) {
+	var variant domain.RestaurantImageVariant
+	var ok bool
+	r.read(func(t *tables) {
+		variant, ok = t.imageVariants.get(variantKey{ImageID: imageID, ContentType: contentType})
+	})
+	if !ok || variant.Status != domain.ImageVariantReady {
+		return nil, errors.New(common.ErrImageNotFound)
+	}
+
+	return bytes.Clone(variant.Data), nil
+}
+
+func (r *ImageRepository) ClaimPendingVariants(ctx context.Context, limit int, staleBefore time.Time) ([]domain.RestaurantImageVariant, error) {
+	variants := make([]domain.RestaurantImageVariant, 0)
+	err := r.write(ctx, func(t *tables) error {
+		for _, variant := range t.imageVariants.rows {
+			if variant.Status == domain.ImageVariantPending ||
+				(variant.Status == domain.ImageVariantProcessing && variant.UpdatedAt.Before(staleBefore)) {
+				variants = append(variants, variant)
+			}
+		}
+
+		slices.SortFunc(variants, func(a, b domain.RestaurantImageVariant) int {
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		})
+		variants = page(variants, 0, limit)
+
+		now := r.clock.Now()
+		for i := range variants {
+			variants[i].Status = domain.ImageVariantProcessing
+			variants[i].UpdatedAt = now
+			t.imageVariants.put(variantKey{ImageID: variants[i].ImageID, ContentType: variants[i].ContentType}, variants[i])
+			variants[i].Data = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (r *ImageRepository) SaveVariant(ctx context.Context, variant *domain.RestaurantImageVariant) error {
+	variant.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		key := variantKey{ImageID: variant.ImageID, ContentType: variant.ContentType}
+		// The image may have been deleted in the meantime; there is nothing left to update then.
+		if _, ok := t.imageVariants.get(key); !ok {
+			return nil
+		}
+
+		stored := *variant
+		stored.Data = bytes.Clone(variant.Data)
+		t.imageVariants.put(key, stored)
+		return nil
+	})
+}
+
+// variants returns the variants of the image by content type, without their data.
+func (t *tables) variants(imageID string) []domain.RestaurantImageVariant {
+	variants := make([]domain.RestaurantImageVariant, 0)
+	for key, variant := range t.imageVariants.rows {
+		if key.ImageID == imageID {
+			variant.Data = nil
+			variants = append(variants, variant)
+		}
+	}
+
+	slices.SortFunc(variants, func(a, b domain.RestaurantImageVariant) int {
+		return cmp.Compare(a.ContentType, b.ContentType)
+	})
+	return variants
+}
+
+func (t *tables) deleteImage(id string) {
+	t.images.delete(id)
+	for key := range t.imageVariants.rows {
+		if key.ImageID == id {
+			t.imageVariants.delete(key)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type IncentiveRepository struct {
+	*Store
+}
+
+func NewIncentiveRepository(store *Store) *IncentiveRepository {
+	return &IncentiveRepository{
+		Store: store,
+	}
+}
+
+func (r *IncentiveRepository) ListRules(_ context.Context, enabledOnly bool) ([]*domain.IncentiveRule, error) {
+	rules := make([]*domain.IncentiveRule, 0)
+	r.read(func(t *tables) {
+		for _, rule := range t.incentiveRules.rows {
+			if !enabledOnly || rule.Enabled {
+				rules = append(rules, &rule)
+			}
+		}
+	})
+
+	slices.SortFunc(rules, func(a, b *domain.IncentiveRule) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return rules, nil
+}
+
+func (r *IncentiveRepository) GetRule(_ context.Context, id string) (*domain.IncentiveRule, error) {
+	var rule domain.IncentiveRule
+	var ok bool
+	r.read(func(t *tables) {
+		rule, ok = t.incentiveRules.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrIncentiveRuleNotFound)
+	}
+
+	return &rule, nil
+}
+
+func (r *IncentiveRepository) CreateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	if rule.ID == "" {
+		rule.ID = r.ids.NewID()
+	}
+	now := r.clock.Now()
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	return r.write(ctx, func(t *tables) error {
+		t.incentiveRules.put(rule.ID, *rule)
+		return nil
+	})
+}
+
+func (r *IncentiveRepository) UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	return r.write(ctx, func(t *tables) error {
+		previous, ok := t.incentiveRules.get(rule.ID)
+		if !ok {
+			return errors.New(common.ErrIncentiveRuleNotFound)
+		}
+
+		rule.CreatedAt = previous.CreatedAt
+		rule.UpdatedAt = r.clock.Now()
+		t.incentiveRules.put(rule.ID, *rule)
+		return nil
+	})
+}
+
+func (r *IncentiveRepository) DeleteRule(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.incentiveRules.get(id); !ok {
+			return errors.New(common.ErrIncentiveRuleNotFound)
+		}
+
+		t.incentiveRules.delete(id)
+		return nil
+	})
+}
+
+// CountPreviousBookings counts the bookings the user made, test, rejected and cancelled ones
+// aside.
+func (r *IncentiveRepository) CountPreviousBookings(_ context.Context, userID string) (int, error) {
+	var count int
+	r.read(func(t *tables) {
+		for _, booking := range t.bookings.rows {
+			switch {
+			case booking.UserID != userID, booking.IsTest:
+			case booking.Status == domain.BookingStatusRejected, booking.Status == domain.BookingStatusCancelled:
+			default:
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+func (r *IncentiveRepository) CreateEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error {
+	if evaluation.ID == "" {
+		evaluation.ID = r.ids.NewID()
+	}
+	if evaluation.EvaluatedAt.IsZero() {
+		evaluation.EvaluatedAt = r.clock.Now()
+	}
+
+	stored := *evaluation
+	stored.Results = slices.Clone(evaluation.Results)
+	return r.write(ctx, func(t *tables) error {
+		t.evaluations.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *IncentiveRepository) GetEvaluation(_ context.Context, bookingID string) (*domain.IncentiveEvaluation, error) {
+	var latest *domain.IncentiveEvaluation
+	r.read(func(t *tables) {
+		for _, evaluation := range t.evaluations.rows {
+			if evaluation.BookingID == bookingID && (latest == nil || evaluation.EvaluatedAt.After(latest.EvaluatedAt)) {
+				latest = &evaluation
+			}
+		}
+	})
+	if latest == nil {
+		return nil, errors.New(common.ErrIncentiveEvaluationNotFound)
+	}
+
+	latest.Results = slices.Clone(latest.Results)
+	return latest, nil
+}
+
+func (r *IncentiveRepository) ListEvaluations(_ context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error) {
+	evaluations := make([]*domain.IncentiveEvaluation, 0)
+	r.read(func(t *tables) {
+		for _, evaluation := range t.evaluations.rows {
+			if userID == "" || evaluation.Facts.UserID == userID {
+				evaluation.Results = slices.Clone(evaluation.Results)
+				evaluations = append(evaluations, &evaluation)
+			}
+		}
+	})
+
+	slices.SortFunc(evaluations, func(a, b *domain.IncentiveEvaluation) int {
+		return cmp.Or(b.EvaluatedAt.Compare(a.EvaluatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(evaluations, offset, limit), nil
+}
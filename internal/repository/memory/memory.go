@@ -0,0 +1,358 @@
+// Package memory provides an implementation of repositories that keeps every record in process
+// memory. It has the error semantics of the postgres package, so the service can run without a
+// database for demos and use case tests run without one; nothing survives a restart.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+)
+
+// Store holds the records of every repository. Writes are serialized: a write outside a
+// transaction waits for running transactions, and a transaction keeps a journal of the writes
+// made in it to undo them on rollback. Reads are not isolated from running transactions.
+type Store struct {
+	txMu sync.Mutex
+	mu   sync.RWMutex
+
+	journal *journal
+	t       *tables
+	ids     idgen.Generator
+	clock   clock.Clock
+}
+
+// NewStore creates an empty store; ids generates the IDs of the records created without one, and
+// clock stamps the records and stands in for the time the database would compare them with.
+func NewStore(ids idgen.Generator, clock clock.Clock) *Store {
+	j := &journal{}
+	return &Store{
+		journal: j,
+		t:       newTables(j),
+		ids:     ids,
+		clock:   clock,
+	}
+}
+
+type txContextKey struct{}
+
+// inTransaction reports whether ctx was passed to fn by a Transactor of the store.
+func (s *Store) inTransaction(ctx context.Context) bool {
+	store, ok := ctx.Value(txContextKey{}).(*Store)
+	return ok && store == s
+}
+
+// read runs fn with the records locked for reading.
+func (s *Store) read(fn func(t *tables)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fn(s.t)
+}
+
+// write runs fn with the records locked for writing. fn must check everything that can fail before
+// it changes a record, so that a failed write leaves the records untouched.
+func (s *Store) write(ctx context.Context, fn func(t *tables) error) error {
+	if !s.inTransaction(ctx) {
+		s.txMu.Lock()
+		defer s.txMu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s.t)
+}
+
+// journal records how to undo the writes made in the running transaction.
+type journal struct {
+	depth int
+	undo  []func()
+}
+
+func (j *journal) remember(undo func()) {
+	if j.depth > 0 {
+		j.undo = append(j.undo, undo)
+	}
+}
+
+// rollback undoes the writes recorded since mark, newest first.
+func (j *journal) rollback(mark int) {
+	for i := len(j.undo) - 1; i >= mark; i-- {
+		j.undo[i]()
+	}
+	j.undo = j.undo[:mark]
+}
+
+// table is a set of records by key. Records are stored by value and replaced as a whole on
+// every change, so that records handed out are never changed behind the caller's back.
+type table[K comparable, V any] struct {
+	rows    map[K]V
+	journal *journal
+}
+
+func newTable[K comparable, V any](j *journal) *table[K, V] {
+	return &table[K, V]{
+		rows:    make(map[K]V),
+		journal: j,
+	}
+}
+
+func (t *table[K, V]) get(key K) (V, bool) {
+	row, ok := t.rows[key]
+	return row, ok
+}
+
+func (t *table[K, V]) put(key K, row V) {
+	t.rememberRow(key)
+	t.rows[key] = row
+}
+
+func (t *table[K, V]) delete(key K) {
+	if _, ok := t.rows[key]; !ok {
+		return
+	}
+	t.rememberRow(key)
+	delete(t.rows, key)
+}
+
+func (t *table[K, V]) rememberRow(key K) {
+	previous, existed := t.rows[key]
+	t.journal.remember(func() {
+		if existed {
+			t.rows[key] = previous
+		} else {
+			delete(t.rows, key)
+		}
+	})
+}
+
+// counter hands out increasing numbers, such as the numbers of invoices.
+type counter struct {
+	value   int64
+	journal *journal
+}
+
+func (c *counter) next() int64 {
+	previous := c.value
+	c.journal.remember(func() { c.value = previous })
+	c.value++
+	return c.value
+}
+
+// tombstone marks a deleted record of an exported entity for sync clients.
+type tombstone struct {
+	Entity    domain.ExportEntity
+	ID        string
+	DeletedAt time.Time
+}
+
+type recipientKey struct {
+	Type domain.RecipientType
+	ID   string
+}
+
+type bookingSyncKey struct {
+	UserID string
+	Key    string
+}
+
+// preOrderReservationKey is a menu item on a day, for the units pre-ordered by its bookings.
+type preOrderReservationKey struct {
+	MenuItemID string
+	Date       time.Time
+}
+
+// specialDayKey is a restaurant on a date, for its special day.
+type specialDayKey struct {
+	RestaurantID string
+	Date         time.Time
+}
+
+type variantKey struct {
+	ImageID     string
+	ContentType string
+}
+
+// tables are the records of every repository, the joins between them left to the repositories.
+type tables struct {
+	restaurants   *table[string, domain.Restaurant]
+	slugRedirects *table[string, string]
+	facts         *table[string, domain.Fact]
+	workingHours  *table[string, domain.WorkingHours]
+	availability  *table[string, domain.Availability]
+
+	bookings     *table[string, domain.Booking]
+	anonymized   *table[string, time.Time]
+	alternatives *table[string, domain.BookingAlternative]
+	bookingLinks *table[string, domain.BookingLink]
+	// attributions are the campaigns bookings came through, by booking.
+	attributions *table[string, domain.BookingAttribution]
+
+	bookingSyncResults *table[bookingSyncKey, domain.BookingSyncResult]
+
+	users                *table[string, domain.User]
+	notifications        *table[string, domain.Notification]
+	notificationSettings *table[recipientKey, []domain.NotificationPreference]
+	quietHours           *table[recipientKey, []domain.QuietHours]
+	notificationFailures *table[string, domain.NotificationFailure]
+	deferred             *table[string, domain.DeferredNotification]
+
+	menuItems *table[string, menuItem]
+	preOrders *table[string, []domain.PreOrderItem]
+	// preOrderReservations are the units of menu items pre-ordered by active bookings, by day.
+	preOrderReservations *table[preOrderReservationKey, int]
+
+	images        *table[string, domain.RestaurantImage]
+	imageVariants *table[variantKey, domain.RestaurantImageVariant]
+
+	reviews       *table[string, domain.Review]
+	reviewReplies *table[string, domain.ReviewReply]
+	reviewFlags   *table[string, domain.ReviewFlag]
+
+	retentionRuns *table[string, domain.RetentionRun]
+	tombstones    *table[int64, tombstone]
+	tombstoneSeq  *counter
+
+	plans          *table[string, domain.RestaurantPlan]
+	subscriptions  *table[string, domain.Subscription]
+	invoices       *table[string, domain.Invoice]
+	invoiceSeq     *counter
+	paymentEvents  *table[string, domain.PaymentEvent]
+	incentiveRules *table[string, domain.IncentiveRule]
+	evaluations    *table[string, domain.IncentiveEvaluation]
+
+	locations *table[string, domain.RestaurantLocation]
+	cities    *table[string, domain.City]
+
+	availabilityAlerts *table[string, domain.AvailabilityAlert]
+	bookingDrafts      *table[string, domain.BookingDraft]
+
+	organizations           *table[string, domain.Organization]
+	organizationRestaurants *table[string, string]
+	bookingTransfers        *table[string, domain.BookingTransfer]
+
+	restaurantClaims *table[string, domain.RestaurantClaim]
+	restaurantOwners *table[string, domain.RestaurantOwnership]
+	ownershipAudit   *table[string, domain.OwnershipAuditRecord]
+	customDomains    *table[string, domain.CustomDomain]
+	closures         *table[string, domain.RestaurantClosure]
+	specialDays      *table[specialDayKey, domain.SpecialDay]
+	restaurantTables *table[string, domain.Table]
+	widgetSettings   *table[string, domain.WidgetSettings]
+
+	accounts      *table[string, domain.Account]
+	refreshTokens *table[string, domain.RefreshToken]
+
+	requestMetrics *table[time.Time, domain.RequestMetrics]
+	sloReports     *table[time.Time, domain.SLOReport]
+}
+
+func newTables(j *journal) *tables {
+	return &tables{
+		restaurants:   newTable[string, domain.Restaurant](j),
+		slugRedirects: newTable[string, string](j),
+		facts:         newTable[string, domain.Fact](j),
+		workingHours:  newTable[string, domain.WorkingHours](j),
+		availability:  newTable[string, domain.Availability](j),
+
+		bookings:     newTable[string, domain.Booking](j),
+		anonymized:   newTable[string, time.Time](j),
+		alternatives: newTable[string, domain.BookingAlternative](j),
+		bookingLinks: newTable[string, domain.BookingLink](j),
+		attributions: newTable[string, domain.BookingAttribution](j),
+
+		bookingSyncResults: newTable[bookingSyncKey, domain.BookingSyncResult](j),
+
+		users:                newTable[string, domain.User](j),
+		notifications:        newTable[string, domain.Notification](j),
+		notificationSettings: newTable[recipientKey, []domain.NotificationPreference](j),
+		quietHours:           newTable[recipientKey, []domain.QuietHours](j),
+		notificationFailures: newTable[string, domain.NotificationFailure](j),
+		deferred:             newTable[string, domain.DeferredNotification](j),
+
+		menuItems: newTable[string, menuItem](j),
+		preOrders: newTable[string, []domain.PreOrderItem](j),
+
+		preOrderReservations: newTable[preOrderReservationKey, int](j),
+
+		images:        newTable[string, domain.RestaurantImage](j),
+		imageVariants: newTable[variantKey, domain.RestaurantImageVariant](j),
+
+		reviews:       newTable[string, domain.Review](j),
+		reviewReplies: newTable[string, domain.ReviewReply](j),
+		reviewFlags:   newTable[string, domain.ReviewFlag](j),
+
+		retentionRuns: newTable[string, domain.RetentionRun](j),
+		tombstones:    newTable[int64, tombstone](j),
+		tombstoneSeq:  &counter{journal: j},
+
+		plans:          newTable[string, domain.RestaurantPlan](j),
+		subscriptions:  newTable[string, domain.Subscription](j),
+		invoices:       newTable[string, domain.Invoice](j),
+		invoiceSeq:     &counter{journal: j},
+		paymentEvents:  newTable[string, domain.PaymentEvent](j),
+		incentiveRules: newTable[string, domain.IncentiveRule](j),
+		evaluations:    newTable[string, domain.IncentiveEvaluation](j),
+
+		locations: newTable[string, domain.RestaurantLocation](j),
+		cities:    newTable[string, domain.City](j),
+
+		availabilityAlerts: newTable[string, domain.AvailabilityAlert](j),
+		bookingDrafts:      newTable[string, domain.BookingDraft](j),
+
+		organizations:           newTable[string, domain.Organization](j),
+		organizationRestaurants: newTable[string, string](j),
+		bookingTransfers:        newTable[string, domain.BookingTransfer](j),
+
+		restaurantClaims: newTable[string, domain.RestaurantClaim](j),
+		restaurantOwners: newTable[string, domain.RestaurantOwnership](j),
+		ownershipAudit:   newTable[string, domain.OwnershipAuditRecord](j),
+		customDomains:    newTable[string, domain.CustomDomain](j),
+		closures:         newTable[string, domain.RestaurantClosure](j),
+		specialDays:      newTable[specialDayKey, domain.SpecialDay](j),
+		restaurantTables: newTable[string, domain.Table](j),
+		widgetSettings:   newTable[string, domain.WidgetSettings](j),
+
+		accounts:      newTable[string, domain.Account](j),
+		refreshTokens: newTable[string, domain.RefreshToken](j),
+
+		requestMetrics: newTable[time.Time, domain.RequestMetrics](j),
+		sloReports:     newTable[time.Time, domain.SLOReport](j),
+	}
+}
+
+// bury records the deletion of a record of an exported entity, as the sync triggers of the
+// database do.
+func (t *tables) bury(entity domain.ExportEntity, id string, at time.Time) {
+	t.tombstones.put(t.tombstoneSeq.next(), tombstone{Entity: entity, ID: id, DeletedAt: at})
+}
+
+// dateOf returns the calendar date of the time, as stored in a DATE column.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// page returns the records from offset on, at most limit of them.
+func page[T any](records []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(records) {
+		return records[:0]
+	}
+	records = records[offset:]
+	if limit >= 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return records
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
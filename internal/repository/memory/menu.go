@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+// menuItem is a stored menu item with its place on the menu.
+type menuItem struct {
+	domain.MenuItem
+	Position int
+}
+
+type MenuRepository struct {
+	*Store
+}
+
+func NewMenuRepository(store *Store) *MenuRepository {
+	return &MenuRepository{
+		Store: store,
+	}
+}
+
+func (r *MenuRepository) GetMenu(_ context.Context, restaurantID string) ([]domain.MenuItem, error) {
+	stored := make([]menuItem, 0)
+	var currency string
+	r.read(func(t *tables) {
+		restaurant, ok := t.restaurants.get(restaurantID)
+		if !ok {
+			return
+		}
+		currency = restaurant.Currency
+		for _, item := range t.menuItems.rows {
+			if item.RestaurantID == restaurantID {
+				stored = append(stored, item)
+			}
+		}
+	})
+
+	slices.SortFunc(stored, func(a, b menuItem) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.Name, b.Name))
+	})
+
+	items := make([]domain.MenuItem, 0, len(stored))
+	for _, item := range stored {
+		item.Currency = currency
+		item.Allergens = slices.Clone(item.Allergens)
+		item.DietaryLabels = slices.Clone(item.DietaryLabels)
+		items = append(items, item.MenuItem)
+	}
+	return items, nil
+}
+
+// SaveMenu stores the items in the listed order. Items without an ID get one; an ID of an item of
+// another restaurant is left untouched.
+func (r *MenuRepository) SaveMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) error {
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = r.ids.NewID()
+		}
+		items[i].RestaurantID = restaurantID
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(restaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrSaveMenu, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		now := r.clock.Now()
+		for id, item := range t.menuItems.rows {
+			if item.RestaurantID == restaurantID && !slices.ContainsFunc(items, func(listed domain.MenuItem) bool {
+				return listed.ID == id
+			}) {
+				t.deleteMenuItem(id, now)
+			}
+		}
+
+		for i, item := range items {
+			stored, ok := t.menuItems.get(item.ID)
+			switch {
+			case !ok:
+				item.CreatedAt = now
+			case stored.RestaurantID != restaurantID:
+				continue
+			default:
+				item.CreatedAt = stored.CreatedAt
+			}
+			item.Currency = ""
+			item.Allergens = slices.Clone(item.Allergens)
+			item.DietaryLabels = slices.Clone(item.DietaryLabels)
+			item.UpdatedAt = now
+			t.menuItems.put(item.ID, menuItem{MenuItem: item, Position: i})
+		}
+		return nil
+	})
+}
+
+func (r *MenuRepository) GetPreOrder(_ context.Context, bookingID string) ([]domain.PreOrderItem, error) {
+	var items []domain.PreOrderItem
+	r.read(func(t *tables) {
+		stored, _ := t.preOrders.get(bookingID)
+		items = slices.Clone(stored)
+	})
+	if items == nil {
+		items = make([]domain.PreOrderItem, 0)
+	}
+
+	return items, nil
+}
+
+// SavePreOrder replaces the pre-order of the booking. While the booking is active, the units it
+// adds are reserved for its date and the ones it drops are released; it fails with a
+// *domain.PreOrderLimitError when an item has fewer units left for the day than it adds.
+func (r *MenuRepository) SavePreOrder(ctx context.Context, bookingID string, items []domain.PreOrderItem) error {
+	return r.write(ctx, func(t *tables) error {
+		booking, ok := t.bookings.get(bookingID)
+		if !ok {
+			return fmt.Errorf("%s: %w", common.ErrSavePreOrder, errors.New(common.ErrBookingNotFound))
+		}
+
+		if isActive(booking.Status) {
+			previous, _ := t.preOrders.get(bookingID)
+			deltas := preOrderQuantities(items)
+			for id, quantity := range preOrderQuantities(previous) {
+				deltas[id] -= quantity
+			}
+			if err := t.reservePreOrderItems(booking.Date, deltas); err != nil {
+				return err
+			}
+		}
+
+		if len(items) == 0 {
+			t.preOrders.delete(bookingID)
+			return nil
+		}
+		t.preOrders.put(bookingID, slices.Clone(items))
+		return nil
+	})
+}
+
+// preOrderQuantities sums the quantities of the pre-ordered items by menu item.
+func preOrderQuantities(items []domain.PreOrderItem) map[string]int {
+	quantities := make(map[string]int, len(items))
+	for _, item := range items {
+		if item.MenuItemID != "" {
+			quantities[item.MenuItemID] += item.Quantity
+		}
+	}
+	return quantities
+}
+
+// reservePreOrderItems changes the units of the menu items reserved for the date by the deltas,
+// changing none when any item is short. Only growth is checked against the daily limits, as a
+// lowered limit may already be below the units reserved.
+func (t *tables) reservePreOrderItems(date time.Time, deltas map[string]int) error {
+	date = dateOf(date)
+	for _, id := range slices.Sorted(maps.Keys(deltas)) {
+		item, ok := t.menuItems.get(id)
+		if delta := deltas[id]; ok && delta > 0 && item.DailyLimit > 0 {
+			reserved, _ := t.preOrderReservations.get(preOrderReservationKey{MenuItemID: id, Date: date})
+			if reserved+delta > item.DailyLimit {
+				return &domain.PreOrderLimitError{
+					MenuItemID: id, Name: item.Name, Date: date, Limit: item.DailyLimit, Reserved: reserved, Delta: delta,
+				}
+			}
+		}
+	}
+
+	for id, delta := range deltas {
+		if _, ok := t.menuItems.get(id); !ok || delta == 0 {
+			continue
+		}
+		key := preOrderReservationKey{MenuItemID: id, Date: date}
+		reserved, _ := t.preOrderReservations.get(key)
+		if reserved = max(reserved+delta, 0); reserved == 0 {
+			t.preOrderReservations.delete(key)
+		} else {
+			t.preOrderReservations.put(key, reserved)
+		}
+	}
+	return nil
+}
+
+// releasePreOrderItems releases the units pre-ordered by the booking for its date.
+func (t *tables) releasePreOrderItems(booking domain.Booking) {
+	items, _ := t.preOrders.get(booking.ID)
+	deltas := preOrderQuantities(items)
+	for id, quantity := range deltas {
+		deltas[id] = -quantity
+	}
+	_ = t.reservePreOrderItems(booking.Date, deltas)
+}
+
+// movePreOrderItems reserves the pre-order of the booking for the date it moves to as a confirmed
+// booking, releasing the units it held on its old date.
+func (t *tables) movePreOrderItems(booking domain.Booking, date time.Time) error {
+	active := isActive(booking.Status)
+	if active && dateOf(booking.Date).Equal(dateOf(date)) {
+		return nil
+	}
+
+	items, _ := t.preOrders.get(booking.ID)
+	if err := t.reservePreOrderItems(date, preOrderQuantities(items)); err != nil {
+		return err
+	}
+	if active {
+		t.releasePreOrderItems(booking)
+	}
+	return nil
+}
+
+// deleteMenuItem deletes the item; pre-orders keep the items ordered, without the reference.
+func (t *tables) deleteMenuItem(id string, at time.Time) {
+	t.menuItems.delete(id)
+	t.bury(domain.ExportMenuItems, id, at)
+	for key := range t.preOrderReservations.rows {
+		if key.MenuItemID == id {
+			t.preOrderReservations.delete(key)
+		}
+	}
+
+	for bookingID, items := range t.preOrders.rows {
+		if !slices.ContainsFunc(items, func(item domain.PreOrderItem) bool { return item.MenuItemID == id }) {
+			continue
+		}
+
+		items = slices.Clone(items)
+		for i := range items {
+			if items[i].MenuItemID == id {
+				items[i].MenuItemID = ""
+			}
+		}
+		t.preOrders.put(bookingID, items)
+	}
+}
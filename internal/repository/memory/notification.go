@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+)
+
+type NotificationRepository struct {
+	*Store
+}
+
+func NewNotificationRepository(store *Store) *NotificationRepository {
+	return &NotificationRepository{
+		Store: store,
+	}
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	if notification.ID == "" {
+		notification.ID = r.ids.NewID()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = r.clock.Now()
+	}
+	if notification.Channel == "" {
+		notification.Channel = domain.NotificationChannelInApp
+	}
+	// In-app notifications are delivered as soon as they are stored.
+	if notification.Channel == domain.NotificationChannelInApp && notification.DeliveredAt == nil {
+		notification.DeliveredAt = ptr(notification.CreatedAt)
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.notifications.get(notification.ID); ok {
+			return errors.New(common.ErrCreateNotification)
+		}
+
+		t.notifications.put(notification.ID, *notification)
+		return nil
+	})
+}
+
+// getByRecipient returns the in-app notifications of the recipient, newest first.
+func (r *NotificationRepository) getByRecipient(ctx context.Context, recipientType domain.RecipientType, recipientID string) ([]domain.Notification, error) {
+	notifications := make([]domain.Notification, 0)
+	r.read(func(t *tables) {
+		for _, notification := range t.notifications.rows {
+			if notification.RecipientType == recipientType && notification.RecipientID == recipientID &&
+				notification.Channel == domain.NotificationChannelInApp {
+				notifications = append(notifications, notification)
+			}
+		}
+	})
+
+	for i := range notifications {
+		if err := guardNotification(ctx, &notifications[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortFunc(notifications, func(a, b domain.Notification) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return notifications, nil
+}
+
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID string) ([]domain.Notification, error) {
+	return r.getByRecipient(ctx, domain.RecipientTypeUser, userID)
+}
+
+func (r *NotificationRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]domain.Notification, error) {
+	return r.getByRecipient(ctx, domain.RecipientTypeRestaurant, restaurantID)
+}
+
+func (r *NotificationRepository) MarkAsRead(ctx context.Context, notificationID string) error {
+	return r.MarkRead(ctx, notificationID, r.clock.Now())
+}
+
+func (r *NotificationRepository) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message, relatedID string) error {
+	return r.Create(ctx, &domain.Notification{
+		RecipientType: domain.RecipientTypeUser,
+		RecipientID:   userID,
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		RelatedID:     relatedID,
+	})
+}
+
+func (r *NotificationRepository) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message, relatedID string) error {
+	return r.Create(ctx, &domain.Notification{
+		RecipientType: domain.RecipientTypeRestaurant,
+		RecipientID:   restaurantID,
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		RelatedID:     relatedID,
+	})
+}
+
+func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*domain.Notification, error) {
+	var notification domain.Notification
+	var ok bool
+	r.read(func(t *tables) {
+		notification, ok = t.notifications.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrNotificationNotFound)
+	}
+
+	if err := guardNotification(ctx, &notification); err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+func (r *NotificationRepository) GetByRelatedID(_ context.Context, relatedID string) ([]domain.Notification, error) {
+	notifications := make([]domain.Notification, 0)
+	r.read(func(t *tables) {
+		for _, notification := range t.notifications.rows {
+			if notification.RelatedID == relatedID {
+				notifications = append(notifications, notification)
+			}
+		}
+	})
+
+	slices.SortFunc(notifications, func(a, b domain.Notification) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return notifications, nil
+}
+
+// MarkRead records the first time the notification was read; reading it again keeps that time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, id string, readAt time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		notification, ok := t.notifications.get(id)
+		if !ok {
+			return errors.New(common.ErrNotificationNotFound)
+		}
+
+		notification.IsRead = true
+		if notification.ReadAt == nil {
+			notification.ReadAt = &readAt
+		}
+		t.notifications.put(id, notification)
+		return nil
+	})
+}
+
+// guardNotification checks that the notification was sent to the principal of ctx.
+func guardNotification(ctx context.Context, notification *domain.Notification) error {
+	if notification.RecipientType == domain.RecipientTypeRestaurant {
+		return tenant.GuardRestaurant(ctx, "notification", notification.ID, notification.RecipientID)
+	}
+	return tenant.GuardUser(ctx, "notification", notification.ID, notification.RecipientID)
+}
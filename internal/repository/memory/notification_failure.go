@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type NotificationFailureRepository struct {
+	*Store
+}
+
+func NewNotificationFailureRepository(store *Store) *NotificationFailureRepository {
+	return &NotificationFailureRepository{
+		Store: store,
+	}
+}
+
+func (r *NotificationFailureRepository) Create(ctx context.Context, failure *domain.NotificationFailure) error {
+	if failure.ID == "" {
+		failure.ID = r.ids.NewID()
+	}
+	if failure.CreatedAt.IsZero() {
+		failure.CreatedAt = r.clock.Now()
+	}
+	failure.UpdatedAt = failure.CreatedAt
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.notificationFailures.get(failure.ID); ok {
+			return errors.New(common.ErrCreateNotificationFailure)
+		}
+
+		t.notificationFailures.put(failure.ID, *failure)
+		return nil
+	})
+}
+
+func (r *NotificationFailureRepository) Update(ctx context.Context, failure *domain.NotificationFailure) error {
+	failure.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.notificationFailures.get(failure.ID)
+		if !ok {
+			return errors.New(common.ErrNotificationFailureNotFound)
+		}
+
+		stored.Attempts = failure.Attempts
+		stored.Cause = failure.Cause
+		stored.Status = failure.Status
+		stored.NextAttemptAt = failure.NextAttemptAt
+		stored.UpdatedAt = failure.UpdatedAt
+		t.notificationFailures.put(failure.ID, stored)
+		return nil
+	})
+}
+
+func (r *NotificationFailureRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.NotificationFailure, error) {
+	failures := make([]*domain.NotificationFailure, 0)
+	err := r.write(ctx, func(t *tables) error {
+		for _, failure := range t.notificationFailures.rows {
+			if failure.Status == domain.NotificationFailureStatusPending && failure.NextAttemptAt != nil &&
+				!failure.NextAttemptAt.After(now) {
+				failures = append(failures, &failure)
+			}
+		}
+
+		slices.SortFunc(failures, func(a, b *domain.NotificationFailure) int {
+			return a.NextAttemptAt.Compare(*b.NextAttemptAt)
+		})
+		failures = page(failures, 0, limit)
+
+		for _, failure := range failures {
+			failure.NextAttemptAt = ptr(leaseUntil)
+			t.notificationFailures.put(failure.ID, *failure)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return failures, nil
+}
+
+func (r *NotificationFailureRepository) List(_ context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error) {
+	failures := make([]*domain.NotificationFailure, 0)
+	r.read(func(t *tables) {
+		for _, failure := range t.notificationFailures.rows {
+			if status == "" || failure.Status == status {
+				failures = append(failures, &failure)
+			}
+		}
+	})
+
+	slices.SortFunc(failures, func(a, b *domain.NotificationFailure) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(failures, offset, limit), nil
+}
+
+func (r *NotificationFailureRepository) CountByStatus(_ context.Context) (map[domain.NotificationFailureStatus]int, error) {
+	counts := make(map[domain.NotificationFailureStatus]int)
+	r.read(func(t *tables) {
+		for _, failure := range t.notificationFailures.rows {
+			counts[failure.Status]++
+		}
+	})
+
+	return counts, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type NotificationSettingsRepository struct {
+	*Store
+}
+
+func NewNotificationSettingsRepository(store *Store) *NotificationSettingsRepository {
+	return &NotificationSettingsRepository{
+		Store: store,
+	}
+}
+
+func (r *NotificationSettingsRepository) Get(_ context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	var (
+		preferences []domain.NotificationPreference
+		quietHours  []domain.QuietHours
+	)
+	r.read(func(t *tables) {
+		key := recipientKey{Type: recipientType, ID: recipientID}
+		stored, _ := t.notificationSettings.get(key)
+		preferences = slices.Clone(stored)
+		quiet, _ := t.quietHours.get(key)
+		quietHours = slices.Clone(quiet)
+	})
+	if preferences == nil {
+		preferences = make([]domain.NotificationPreference, 0)
+	}
+	if quietHours == nil {
+		quietHours = make([]domain.QuietHours, 0)
+	}
+
+	slices.SortFunc(preferences, func(a, b domain.NotificationPreference) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Channel, b.Channel))
+	})
+	slices.SortFunc(quietHours, func(a, b domain.QuietHours) int {
+		return cmp.Compare(a.Channel, b.Channel)
+	})
+
+	return &domain.NotificationSettings{
+		RecipientType: recipientType,
+		RecipientID:   recipientID,
+		Preferences:   preferences,
+		QuietHours:    quietHours,
+	}, nil
+}
+
+func (r *NotificationSettingsRepository) Save(ctx context.Context, settings *domain.NotificationSettings) error {
+	return r.write(ctx, func(t *tables) error {
+		key := recipientKey{Type: settings.RecipientType, ID: settings.RecipientID}
+		if len(settings.Preferences) == 0 {
+			t.notificationSettings.delete(key)
+		} else {
+			t.notificationSettings.put(key, slices.Clone(settings.Preferences))
+		}
+
+		if len(settings.QuietHours) == 0 {
+			t.quietHours.delete(key)
+		} else {
+			t.quietHours.put(key, slices.Clone(settings.QuietHours))
+		}
+		return nil
+	})
+}
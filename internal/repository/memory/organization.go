@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type OrganizationRepository struct {
+	*Store
+}
+
+func NewOrganizationRepository(store *Store) *OrganizationRepository {
+	return &OrganizationRepository{
+		Store: store,
+	}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	if organization.ID == "" {
+		organization.ID = r.ids.NewID()
+	}
+	organization.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		t.organizations.put(organization.ID, *organization)
+		return nil
+	})
+}
+
+// AddRestaurant adds the restaurant to the organization, moving it from the one it belonged to.
+func (r *OrganizationRepository) AddRestaurant(ctx context.Context, organizationID, restaurantID string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.organizations.get(organizationID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrAddOrganizationRestaurant, errors.New(common.ErrOrganizationNotFound))
+		}
+		if _, ok := t.restaurants.get(restaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrAddOrganizationRestaurant, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		t.organizationRestaurants.put(restaurantID, organizationID)
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) GetByRestaurant(_ context.Context, restaurantID string) (*domain.Organization, error) {
+	var organization domain.Organization
+	var ok bool
+	r.read(func(t *tables) {
+		var organizationID string
+		if organizationID, ok = t.organizationRestaurants.get(restaurantID); ok {
+			organization, ok = t.organizations.get(organizationID)
+		}
+	})
+	if !ok {
+		return nil, errors.New(common.ErrOrganizationNotFound)
+	}
+
+	return &organization, nil
+}
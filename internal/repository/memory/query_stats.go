@@ -0,0 +1,28 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+// QueryStatsRepository has nothing to report: the store scans no tables and runs no statements.
+type QueryStatsRepository struct {
+	*Store
+}
+
+func NewQueryStatsRepository(store *Store) *QueryStatsRepository {
+	return &QueryStatsRepository{
+		Store: store,
+	}
+}
+
+func (r *QueryStatsRepository) ListSeqScanTables(_ context.Context, _ int64, _ int) ([]domain.SeqScanTable, error) {
+	return make([]domain.SeqScanTable, 0), nil
+}
+
+func (r *QueryStatsRepository) ListScanHeavyQueries(_ context.Context, _ int64, _ int) ([]domain.ScanHeavyQuery, error) {
+	return nil, errors.New(common.ErrQueryStatsUnavailable)
+}
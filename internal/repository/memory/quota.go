@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type QuotaRepository struct {
+	*Store
+}
+
+func NewQuotaRepository(store *Store) *QuotaRepository {
+	return &QuotaRepository{
+		Store: store,
+	}
+}
+
+func (r *QuotaRepository) GetPlan(_ context.Context, restaurantID string) (*domain.RestaurantPlan, error) {
+	var plan domain.RestaurantPlan
+	var ok bool
+	r.read(func(t *tables) {
+		plan, ok = t.plans.get(restaurantID)
+	})
+	if !ok {
+		return &domain.RestaurantPlan{RestaurantID: restaurantID, Plan: domain.PlanFree}, nil
+	}
+
+	return &plan, nil
+}
+
+func (r *QuotaRepository) SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error {
+	plan.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		t.plans.put(plan.RestaurantID, *plan)
+		return nil
+	})
+}
+
+func (r *QuotaRepository) CountActiveSlots(_ context.Context, restaurantID string, today time.Time) (int, error) {
+	today = dateOf(today)
+
+	var count int
+	r.read(func(t *tables) {
+		for _, availability := range t.availability.rows {
+			if availability.RestaurantID == restaurantID && !availability.Date.Before(today) {
+				count++
+			}
+		}
+	})
+
+	return count, nil
+}
+
+func (r *QuotaRepository) CountBookings(_ context.Context, restaurantID string, from, to time.Time) (int, error) {
+	var count int
+	r.read(func(t *tables) {
+		count = len(t.requestedBookings(from, to)[restaurantID])
+	})
+
+	return count, nil
+}
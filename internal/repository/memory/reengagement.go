@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type ReengagementRepository struct {
+	*Store
+}
+
+func NewReengagementRepository(store *Store) *ReengagementRepository {
+	return &ReengagementRepository{
+		Store: store,
+	}
+}
+
+// ListLapsed returns the guests whose last completed visit was on the date, each with the
+// restaurant they visited most, the latest visited one on a tie.
+func (r *ReengagementRepository) ListLapsed(_ context.Context, lastVisit, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	lastVisit, today = dateOf(lastVisit), dateOf(today)
+
+	type visits struct {
+		count int
+		last  time.Time
+	}
+
+	candidates := make([]*domain.ReengagementCandidate, 0)
+	r.read(func(t *tables) {
+		byUser := make(map[string]map[string]*visits)
+		for _, booking := range t.bookings.rows {
+			if !t.liveVisit(booking) {
+				continue
+			}
+			restaurants, ok := byUser[booking.UserID]
+			if !ok {
+				restaurants = make(map[string]*visits)
+				byUser[booking.UserID] = restaurants
+			}
+			v, ok := restaurants[booking.RestaurantID]
+			if !ok {
+				v = &visits{}
+				restaurants[booking.RestaurantID] = v
+			}
+			v.count++
+			if booking.Date.After(v.last) {
+				v.last = booking.Date
+			}
+		}
+
+		for userID, restaurants := range byUser {
+			var favorite string
+			var last time.Time
+			for restaurantID, v := range restaurants {
+				if v.last.After(last) {
+					last = v.last
+				}
+				if best, ok := restaurants[favorite]; !ok || v.count > best.count ||
+					(v.count == best.count && v.last.After(best.last)) ||
+					(v.count == best.count && v.last.Equal(best.last) && restaurantID < favorite) {
+					favorite = restaurantID
+				}
+			}
+			if !last.Equal(lastVisit) || t.hasUpcomingBooking(userID, today) {
+				continue
+			}
+
+			restaurant, _ := t.restaurants.get(favorite)
+			candidates = append(candidates, &domain.ReengagementCandidate{
+				UserID:         userID,
+				RestaurantID:   favorite,
+				RestaurantName: restaurant.Name,
+				Reason:         domain.ReengagementReasonLapsed,
+				VisitDate:      last,
+			})
+		}
+	})
+
+	slices.SortFunc(candidates, func(a, b *domain.ReengagementCandidate) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return candidates, nil
+}
+
+func (r *ReengagementRepository) ListAnniversaries(_ context.Context, visitDate, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	visitDate, today = dateOf(visitDate), dateOf(today)
+
+	byUser := make(map[string]*domain.ReengagementCandidate)
+	r.read(func(t *tables) {
+		for _, booking := range t.bookings.rows {
+			if !t.liveVisit(booking) || booking.Occasion != domain.BookingOccasionAnniversary ||
+				!booking.Date.Equal(visitDate) || t.hasUpcomingBooking(booking.UserID, today) {
+				continue
+			}
+			if candidate, ok := byUser[booking.UserID]; ok && candidate.RestaurantID < booking.RestaurantID {
+				continue
+			}
+
+			restaurant, _ := t.restaurants.get(booking.RestaurantID)
+			byUser[booking.UserID] = &domain.ReengagementCandidate{
+				UserID:         booking.UserID,
+				RestaurantID:   booking.RestaurantID,
+				RestaurantName: restaurant.Name,
+				Reason:         domain.ReengagementReasonAnniversary,
+				VisitDate:      booking.Date,
+			}
+		}
+	})
+
+	candidates := make([]*domain.ReengagementCandidate, 0, len(byUser))
+	for _, candidate := range byUser {
+		candidates = append(candidates, candidate)
+	}
+	slices.SortFunc(candidates, func(a, b *domain.ReengagementCandidate) int {
+		return cmp.Compare(a.UserID, b.UserID)
+	})
+	return candidates, nil
+}
+
+// liveVisit reports whether the booking is a completed visit to a live restaurant.
+func (t *tables) liveVisit(booking domain.Booking) bool {
+	if booking.Status != domain.BookingStatusCompleted || booking.IsTest {
+		return false
+	}
+	restaurant, ok := t.restaurants.get(booking.RestaurantID)
+	return ok && !restaurant.IsTest
+}
+
+// hasUpcomingBooking reports whether the user holds a booking for today or later.
+func (t *tables) hasUpcomingBooking(userID string, today time.Time) bool {
+	for _, booking := range t.bookings.rows {
+		if booking.UserID == userID && isActive(booking.Status) && !booking.Date.Before(today) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,486 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type RestaurantRepository struct {
+	*Store
+}
+
+func NewRestaurantRepository(store *Store) *RestaurantRepository {
+	return &RestaurantRepository{
+		Store: store,
+	}
+}
+
+func (r *RestaurantRepository) GetByID(_ context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
+	var restaurant domain.Restaurant
+	var ok bool
+	r.read(func(t *tables) {
+		restaurant, ok = t.restaurants.get(id.String())
+		restaurant.Facts = t.restaurantFacts(id.String())
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRestaurantNotFound)
+	}
+
+	return &restaurant, nil
+}
+
+// ListLive returns a page of the restaurants by name, without test restaurants.
+func (r *RestaurantRepository) ListLive(_ context.Context, offset, limit int) ([]*domain.Restaurant, error) {
+	return r.list(offset, limit, func(_ *tables, restaurant domain.Restaurant) bool {
+		return !restaurant.IsTest
+	}), nil
+}
+
+func (r *RestaurantRepository) ListAfter(_ context.Context, filter domain.RestaurantFilter, after domain.RestaurantCursor, limit int) ([]*domain.Restaurant, error) {
+	return r.list(0, limit, func(t *tables, restaurant domain.Restaurant) bool {
+		if filter.CityID != "" {
+			location, ok := t.locations.get(restaurant.ID)
+			if !ok || location.CityID != filter.CityID {
+				return false
+			}
+		}
+		if filter.AdultOnly != nil && restaurant.IsAdultOnly != *filter.AdultOnly {
+			return false
+		}
+		return after.IsZero() || cmp.Or(cmp.Compare(restaurant.Name, after.Name), cmp.Compare(restaurant.ID, after.ID)) > 0
+	}), nil
+}
+
+// ListByCity returns a page of the restaurants whose address was placed in the city.
+func (r *RestaurantRepository) ListByCity(_ context.Context, cityID string, offset, limit int) ([]*domain.Restaurant, error) {
+	return r.list(offset, limit, func(t *tables, restaurant domain.Restaurant) bool {
+		location, ok := t.locations.get(restaurant.ID)
+		return ok && location.CityID == cityID
+	}), nil
+}
+
+// Search returns a page of the restaurants matching the filter. The query is matched word by word:
+// every word must be one of the name or description, a word starting with "-" must not, and the
+// matches are not ranked.
+func (r *RestaurantRepository) Search(_ context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	return r.list(offset, limit, func(t *tables, restaurant domain.Restaurant) bool {
+		if filter.CityID != "" {
+			location, ok := t.locations.get(restaurant.ID)
+			if !ok || location.CityID != filter.CityID {
+				return false
+			}
+		}
+		switch {
+		case filter.AdultOnly != nil && restaurant.IsAdultOnly != *filter.AdultOnly,
+			filter.Cuisine != "" && !strings.EqualFold(string(restaurant.Cuisine), string(filter.Cuisine)),
+			filter.LiveOnly && restaurant.IsTest,
+			!matchesQuery(filter.Query, restaurant.Name+" "+restaurant.Description),
+			!matchesQuery(filter.Address, restaurant.Address):
+			return false
+		}
+		if filter.OpenAt != nil {
+			hours := make([]*domain.WorkingHours, 0)
+			for _, h := range t.workingHours.rows {
+				if h.RestaurantID == restaurant.ID {
+					hours = append(hours, &h)
+				}
+			}
+			specialDays := make([]*domain.SpecialDay, 0)
+			for _, day := range t.specialDays.rows {
+				if day.RestaurantID == restaurant.ID {
+					specialDays = append(specialDays, &day)
+				}
+			}
+			closures := make([]*domain.RestaurantClosure, 0)
+			for _, closure := range t.closures.rows {
+				if closure.RestaurantID == restaurant.ID {
+					closures = append(closures, &closure)
+				}
+			}
+			return domain.IsOpenAt(hours, specialDays, closures, *filter.OpenAt)
+		}
+		return true
+	}), nil
+}
+
+// matchesQuery reports whether every word of the query is a word of the text, and none of the
+// words of the query starting with "-" is, in any case.
+func matchesQuery(query, text string) bool {
+	words := searchWords(text)
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		excluded := strings.HasPrefix(word, "-")
+		for _, w := range searchWords(word) {
+			if slices.Contains(words, w) == excluded {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// searchWords splits text into lower-case words of letters and digits.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ListSisters returns a page of the other restaurants of the organization the restaurant
+// belongs to.
+func (r *RestaurantRepository) ListSisters(_ context.Context, restaurantID domain.RestaurantID, offset, limit int) ([]*domain.Restaurant, error) {
+	return r.list(offset, limit, func(t *tables, restaurant domain.Restaurant) bool {
+		own, ok := t.organizationRestaurants.get(restaurantID.String())
+		if !ok || domain.RestaurantID(restaurant.ID) == restaurantID {
+			return false
+		}
+		sister, ok := t.organizationRestaurants.get(restaurant.ID)
+		return ok && sister == own
+	}), nil
+}
+
+// EstimateCount is exact, the store has no statistics to estimate from.
+func (r *RestaurantRepository) EstimateCount(_ context.Context) (int64, error) {
+	var count int64
+	r.read(func(t *tables) {
+		count = int64(len(t.restaurants.rows))
+	})
+	return count, nil
+}
+
+// list returns the restaurants matching the filter by name, without their facts.
+func (r *RestaurantRepository) list(offset, limit int, match func(t *tables, restaurant domain.Restaurant) bool) []*domain.Restaurant {
+	restaurants := make([]*domain.Restaurant, 0)
+	r.read(func(t *tables) {
+		for _, restaurant := range t.restaurants.rows {
+			if match(t, restaurant) {
+				restaurants = append(restaurants, &restaurant)
+			}
+		}
+	})
+
+	slices.SortFunc(restaurants, func(a, b *domain.Restaurant) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(restaurants, offset, limit)
+}
+
+func (r *RestaurantRepository) Create(ctx context.Context, restaurant *domain.Restaurant) error {
+	return r.CreateBatch(ctx, []*domain.Restaurant{restaurant})
+}
+
+func (r *RestaurantRepository) CreateBatch(ctx context.Context, restaurants []*domain.Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+
+	now := r.clock.Now()
+	return r.write(ctx, func(t *tables) error {
+		for _, restaurant := range restaurants {
+			if restaurant.ID == "" {
+				restaurant.ID = r.ids.NewID()
+			}
+			if _, ok := t.restaurants.get(restaurant.ID); ok {
+				return errors.New(common.ErrCreateRestaurant)
+			}
+		}
+
+		for _, restaurant := range restaurants {
+			restaurant.CreatedAt = now
+			restaurant.UpdatedAt = now
+
+			stored := *restaurant
+			stored.Facts = nil
+			t.restaurants.put(stored.ID, stored)
+		}
+		return nil
+	})
+}
+
+// Update saves the restaurant. When its slug changes, the previous one is kept as a redirect, so
+// links using it keep working.
+func (r *RestaurantRepository) Update(ctx context.Context, restaurant *domain.Restaurant) error {
+	restaurant.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		previous, ok := t.restaurants.get(restaurant.ID)
+		if !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+
+		if restaurant.Slug == "" {
+			restaurant.Slug = previous.Slug
+		}
+
+		stored := *restaurant
+		stored.Facts = nil
+		stored.CreatedAt = previous.CreatedAt
+		t.restaurants.put(stored.ID, stored)
+
+		if previous.Slug != restaurant.Slug {
+			t.slugRedirects.delete(restaurant.Slug)
+			t.slugRedirects.put(previous.Slug, restaurant.ID)
+		}
+		return nil
+	})
+}
+
+// GetBySlug finds the restaurant by its current slug or by one it had before. The returned
+// restaurant always carries its current slug.
+func (r *RestaurantRepository) GetBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	var id string
+	r.read(func(t *tables) {
+		for _, restaurant := range t.restaurants.rows {
+			if restaurant.Slug == slug {
+				id = restaurant.ID
+				return
+			}
+		}
+		id, _ = t.slugRedirects.get(slug)
+	})
+	if id == "" {
+		return nil, errors.New(common.ErrRestaurantNotFound)
+	}
+
+	return r.GetByID(ctx, domain.RestaurantID(id))
+}
+
+// IsSlugTaken reports whether the slug is used, currently or as a redirect, by a restaurant other
+// than restaurantID.
+func (r *RestaurantRepository) IsSlugTaken(_ context.Context, slug, restaurantID string) (bool, error) {
+	var taken bool
+	r.read(func(t *tables) {
+		for _, restaurant := range t.restaurants.rows {
+			if restaurant.Slug == slug && restaurant.ID != restaurantID {
+				taken = true
+				return
+			}
+		}
+		owner, ok := t.slugRedirects.get(slug)
+		taken = ok && owner != restaurantID
+	})
+
+	return taken, nil
+}
+
+func (r *RestaurantRepository) Delete(ctx context.Context, id domain.RestaurantID) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(id.String()); !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+
+		t.deleteRestaurant(id.String(), r.clock.Now())
+		return nil
+	})
+}
+
+func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID domain.RestaurantID, fact domain.Fact) (*domain.Fact, error) {
+	if fact.ID == "" {
+		fact.ID = r.ids.NewID()
+	}
+	if fact.CreatedAt.IsZero() {
+		fact.CreatedAt = r.clock.Now()
+	}
+	if fact.Locale == "" {
+		fact.Locale = domain.DefaultFactLocale
+	}
+	fact.RestaurantID = restaurantID.String()
+
+	err := r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(restaurantID.String()); !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+
+		t.facts.put(fact.ID, fact)
+		t.touchRestaurant(restaurantID.String(), fact.CreatedAt)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &fact, nil
+}
+
+func (r *RestaurantRepository) GetFacts(_ context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
+	var facts []domain.Fact
+	r.read(func(t *tables) {
+		facts = t.restaurantFacts(restaurantID.String())
+	})
+
+	return facts, nil
+}
+
+func (r *RestaurantRepository) GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error) {
+	return r.GetFactsPool(ctx, domain.FactFilter{}, count)
+}
+
+func (r *RestaurantRepository) GetFactsPool(_ context.Context, filter domain.FactFilter, limit int) ([]domain.Fact, error) {
+	facts := make([]domain.Fact, 0)
+	r.read(func(t *tables) {
+		for _, fact := range t.facts.rows {
+			restaurant, ok := t.restaurants.get(fact.RestaurantID)
+			switch {
+			case !ok:
+			case filter.RestaurantID != "" && fact.RestaurantID != filter.RestaurantID:
+			case filter.RestaurantID == "" && restaurant.IsTest:
+			case filter.Cuisine != "" && restaurant.Cuisine != filter.Cuisine:
+			case filter.Locale != "" && fact.Locale != filter.Locale:
+			default:
+				facts = append(facts, fact)
+			}
+		}
+	})
+
+	rand.Shuffle(len(facts), func(i, j int) {
+		facts[i], facts[j] = facts[j], facts[i]
+	})
+
+	return page(facts, 0, limit), nil
+}
+
+// restaurantFacts returns the facts of the restaurant, newest first.
+func (t *tables) restaurantFacts(restaurantID string) []domain.Fact {
+	facts := make([]domain.Fact, 0)
+	for _, fact := range t.facts.rows {
+		if fact.RestaurantID == restaurantID {
+			facts = append(facts, fact)
+		}
+	}
+
+	slices.SortFunc(facts, func(a, b domain.Fact) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return facts
+}
+
+// touchRestaurant moves the modification time of a restaurant forward when data shown with it,
+// such as its facts or working hours, changes; HTTP caches revalidate against that time.
+func (t *tables) touchRestaurant(restaurantID string, at time.Time) {
+	restaurant, ok := t.restaurants.get(restaurantID)
+	if !ok || !at.After(restaurant.UpdatedAt) {
+		return
+	}
+
+	restaurant.UpdatedAt = at
+	t.restaurants.put(restaurantID, restaurant)
+}
+
+// deleteRestaurant deletes the restaurant with every record that belongs to it, as the foreign
+// keys of the database cascade.
+func (t *tables) deleteRestaurant(id string, at time.Time) {
+	t.restaurants.delete(id)
+	t.bury(domain.ExportRestaurants, id, at)
+
+	for slug, restaurantID := range t.slugRedirects.rows {
+		if restaurantID == id {
+			t.slugRedirects.delete(slug)
+		}
+	}
+	for factID, fact := range t.facts.rows {
+		if fact.RestaurantID == id {
+			t.facts.delete(factID)
+		}
+	}
+	for hoursID, hours := range t.workingHours.rows {
+		if hours.RestaurantID == id {
+			t.workingHours.delete(hoursID)
+		}
+	}
+	for availabilityID, availability := range t.availability.rows {
+		if availability.RestaurantID == id {
+			t.availability.delete(availabilityID)
+			t.bury(domain.ExportAvailability, availabilityID, at)
+		}
+	}
+	for bookingID, booking := range t.bookings.rows {
+		if booking.RestaurantID == id {
+			t.deleteBooking(bookingID, at)
+		}
+	}
+	for itemID, item := range t.menuItems.rows {
+		if item.RestaurantID == id {
+			t.menuItems.delete(itemID)
+			t.bury(domain.ExportMenuItems, itemID, at)
+		}
+	}
+	for imageID, image := range t.images.rows {
+		if image.RestaurantID == id {
+			t.deleteImage(imageID)
+		}
+	}
+	for reviewID, review := range t.reviews.rows {
+		if review.RestaurantID == id {
+			t.deleteReview(reviewID, at)
+		}
+	}
+	for subscriptionID, subscription := range t.subscriptions.rows {
+		if subscription.RestaurantID == id {
+			t.subscriptions.delete(subscriptionID)
+		}
+	}
+	for invoiceID, invoice := range t.invoices.rows {
+		if invoice.RestaurantID == id {
+			t.invoices.delete(invoiceID)
+			for eventID, event := range t.paymentEvents.rows {
+				if event.InvoiceID == invoiceID {
+					t.paymentEvents.delete(eventID)
+				}
+			}
+		}
+	}
+	for alertID, alert := range t.availabilityAlerts.rows {
+		if alert.RestaurantID == id {
+			t.availabilityAlerts.delete(alertID)
+		}
+	}
+	for transferID, transfer := range t.bookingTransfers.rows {
+		if transfer.SourceRestaurantID == id || transfer.TargetRestaurantID == id {
+			t.bookingTransfers.delete(transferID)
+		}
+	}
+	for claimID, claim := range t.restaurantClaims.rows {
+		if claim.RestaurantID == id {
+			t.restaurantClaims.delete(claimID)
+		}
+	}
+	t.restaurantOwners.delete(id)
+	for domainID, customDomain := range t.customDomains.rows {
+		if customDomain.RestaurantID == id {
+			t.customDomains.delete(domainID)
+		}
+	}
+	for closureID, closure := range t.closures.rows {
+		if closure.RestaurantID == id {
+			t.closures.delete(closureID)
+		}
+	}
+	for key := range t.specialDays.rows {
+		if key.RestaurantID == id {
+			t.specialDays.delete(key)
+		}
+	}
+	for tableID, table := range t.restaurantTables.rows {
+		if table.RestaurantID == id {
+			t.restaurantTables.delete(tableID)
+		}
+	}
+	t.widgetSettings.delete(id)
+	for accountID, account := range t.accounts.rows {
+		if account.RestaurantID == id {
+			t.deleteAccount(accountID)
+		}
+	}
+	t.plans.delete(id)
+	t.locations.delete(id)
+	t.organizationRestaurants.delete(id)
+}
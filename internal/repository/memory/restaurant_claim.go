@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type RestaurantClaimRepository struct {
+	*Store
+}
+
+func NewRestaurantClaimRepository(store *Store) *RestaurantClaimRepository {
+	return &RestaurantClaimRepository{
+		Store: store,
+	}
+}
+
+func (r *RestaurantClaimRepository) Create(ctx context.Context, claim *domain.RestaurantClaim) error {
+	if claim.ID == "" {
+		claim.ID = r.ids.NewID()
+	}
+	claim.Status = domain.RestaurantClaimPending
+	claim.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(claim.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateRestaurantClaim, errors.New(common.ErrRestaurantNotFound))
+		}
+		for _, existing := range t.restaurantClaims.rows {
+			if existing.RestaurantID == claim.RestaurantID && existing.UserID == claim.UserID &&
+				existing.Status == domain.RestaurantClaimPending {
+				return errors.New(common.ErrRestaurantClaimExists)
+			}
+		}
+
+		t.restaurantClaims.put(claim.ID, *claim)
+		return nil
+	})
+}
+
+func (r *RestaurantClaimRepository) GetByID(_ context.Context, id string) (*domain.RestaurantClaim, error) {
+	var claim domain.RestaurantClaim
+	var ok bool
+	r.read(func(t *tables) {
+		claim, ok = t.restaurantClaims.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRestaurantClaimNotFound)
+	}
+
+	return &claim, nil
+}
+
+func (r *RestaurantClaimRepository) List(_ context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error) {
+	claims := make([]*domain.RestaurantClaim, 0)
+	r.read(func(t *tables) {
+		for _, claim := range t.restaurantClaims.rows {
+			if (filter.RestaurantID == "" || claim.RestaurantID == filter.RestaurantID) &&
+				(filter.UserID == "" || claim.UserID == filter.UserID) &&
+				(filter.Status == "" || claim.Status == filter.Status) {
+				claims = append(claims, &claim)
+			}
+		}
+	})
+
+	slices.SortFunc(claims, func(a, b *domain.RestaurantClaim) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return page(claims, offset, limit), nil
+}
+
+func (r *RestaurantClaimRepository) Decide(ctx context.Context, claim *domain.RestaurantClaim) error {
+	return r.write(ctx, func(t *tables) error {
+		stored, ok := t.restaurantClaims.get(claim.ID)
+		if !ok || stored.Status != domain.RestaurantClaimPending {
+			return errors.New(common.ErrRestaurantClaimNotFound)
+		}
+
+		stored.Status = claim.Status
+		stored.ReviewerID = claim.ReviewerID
+		stored.ReviewNote = claim.ReviewNote
+		stored.DecidedAt = claim.DecidedAt
+		t.restaurantClaims.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *RestaurantClaimRepository) GetOwner(_ context.Context, restaurantID string) (*domain.RestaurantOwnership, error) {
+	var ownership domain.RestaurantOwnership
+	var ok bool
+	r.read(func(t *tables) {
+		ownership, ok = t.restaurantOwners.get(restaurantID)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRestaurantOwnerNotFound)
+	}
+
+	return &ownership, nil
+}
+
+func (r *RestaurantClaimRepository) SetOwner(ctx context.Context, ownership *domain.RestaurantOwnership) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(ownership.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrSetRestaurantOwner, errors.New(common.ErrRestaurantNotFound))
+		}
+		if _, ok := t.restaurantOwners.get(ownership.RestaurantID); ok {
+			return errors.New(common.ErrRestaurantAlreadyOwned)
+		}
+
+		t.restaurantOwners.put(ownership.RestaurantID, *ownership)
+		return nil
+	})
+}
+
+func (r *RestaurantClaimRepository) AddAuditRecord(ctx context.Context, record *domain.OwnershipAuditRecord) error {
+	if record.ID == "" {
+		record.ID = r.ids.NewID()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = r.clock.Now()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		t.ownershipAudit.put(record.ID, *record)
+		return nil
+	})
+}
+
+func (r *RestaurantClaimRepository) ListAuditRecords(_ context.Context, restaurantID string) ([]*domain.OwnershipAuditRecord, error) {
+	records := make([]*domain.OwnershipAuditRecord, 0)
+	r.read(func(t *tables) {
+		for _, record := range t.ownershipAudit.rows {
+			if record.RestaurantID == restaurantID {
+				records = append(records, &record)
+			}
+		}
+	})
+
+	slices.SortFunc(records, func(a, b *domain.OwnershipAuditRecord) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return records, nil
+}
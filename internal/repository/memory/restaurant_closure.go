@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type RestaurantClosureRepository struct {
+	*Store
+}
+
+func NewRestaurantClosureRepository(store *Store) *RestaurantClosureRepository {
+	return &RestaurantClosureRepository{
+		Store: store,
+	}
+}
+
+func (r *RestaurantClosureRepository) Create(ctx context.Context, closure *domain.RestaurantClosure) error {
+	if closure.ID == "" {
+		closure.ID = r.ids.NewID()
+	}
+	closure.StartsOn = dateOf(closure.StartsOn)
+	closure.ReopensOn = dateOf(closure.ReopensOn)
+	closure.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(closure.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateRestaurantClosure, errors.New(common.ErrRestaurantNotFound))
+		}
+		for _, existing := range t.closures.rows {
+			if existing.RestaurantID == closure.RestaurantID && existing.Overlaps(closure) {
+				return errors.New(common.ErrRestaurantClosureOverlaps)
+			}
+		}
+
+		t.closures.put(closure.ID, *closure)
+		return nil
+	})
+}
+
+func (r *RestaurantClosureRepository) GetByID(_ context.Context, id string) (*domain.RestaurantClosure, error) {
+	var closure domain.RestaurantClosure
+	var ok bool
+	r.read(func(t *tables) {
+		closure, ok = t.closures.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRestaurantClosureNotFound)
+	}
+
+	return &closure, nil
+}
+
+func (r *RestaurantClosureRepository) ListByRestaurant(_ context.Context, restaurantID string, from time.Time) ([]*domain.RestaurantClosure, error) {
+	from = dateOf(from)
+
+	closures := make([]*domain.RestaurantClosure, 0)
+	r.read(func(t *tables) {
+		for _, closure := range t.closures.rows {
+			if closure.RestaurantID == restaurantID && closure.ReopensOn.After(from) {
+				closures = append(closures, &closure)
+			}
+		}
+	})
+
+	slices.SortFunc(closures, func(a, b *domain.RestaurantClosure) int {
+		return cmp.Or(a.StartsOn.Compare(b.StartsOn), cmp.Compare(a.ID, b.ID))
+	})
+	return closures, nil
+}
+
+func (r *RestaurantClosureRepository) ListCovering(_ context.Context, restaurantIDs []string, date time.Time) ([]*domain.RestaurantClosure, error) {
+	closures := make([]*domain.RestaurantClosure, 0)
+	r.read(func(t *tables) {
+		for _, closure := range t.closures.rows {
+			if slices.Contains(restaurantIDs, closure.RestaurantID) && closure.Covers(date) {
+				closures = append(closures, &closure)
+			}
+		}
+	})
+	return closures, nil
+}
+
+func (r *RestaurantClosureRepository) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.closures.get(id); !ok {
+			return errors.New(common.ErrRestaurantClosureNotFound)
+		}
+
+		t.closures.delete(id)
+		return nil
+	})
+}
+
+// closedAt reports whether the restaurant was closed on the day of at, in UTC.
+func (t *tables) closedAt(restaurantID string, at time.Time) bool {
+	for _, closure := range t.closures.rows {
+		if closure.RestaurantID == restaurantID && closure.Covers(at.UTC()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+)
+
+type RestaurantLocationRepository struct {
+	*Store
+}
+
+func NewRestaurantLocationRepository(store *Store) *RestaurantLocationRepository {
+	return &RestaurantLocationRepository{
+		Store: store,
+	}
+}
+
+// Enqueue queues the address unless the restaurant already has it queued or geocoded; a failed
+// address is queued again. The components of a previous address are cleared.
+func (r *RestaurantLocationRepository) Enqueue(ctx context.Context, restaurantID, address string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(restaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrEnqueueGeocoding, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		previous, ok := t.locations.get(restaurantID)
+		if ok && previous.Address == address && previous.Status != domain.GeocodingFailed {
+			return nil
+		}
+
+		t.locations.put(restaurantID, domain.RestaurantLocation{
+			RestaurantID: restaurantID,
+			Address:      address,
+			Status:       domain.GeocodingPending,
+			UpdatedAt:    r.clock.Now(),
+		})
+		return nil
+	})
+}
+
+func (r *RestaurantLocationRepository) GetByRestaurant(_ context.Context, restaurantID string) (*domain.RestaurantLocation, error) {
+	var location domain.RestaurantLocation
+	var ok bool
+	r.read(func(t *tables) {
+		location, ok = t.locations.get(restaurantID)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrRestaurantLocationNotFound)
+	}
+
+	return &location, nil
+}
+
+// ClaimPending marks up to limit queued addresses, and those left processing since before
+// staleBefore by a worker that stopped, as processing and returns them, the longest waiting first.
+func (r *RestaurantLocationRepository) ClaimPending(ctx context.Context, limit int, staleBefore time.Time) ([]*domain.RestaurantLocation, error) {
+	claimed := make([]*domain.RestaurantLocation, 0)
+	err := r.write(ctx, func(t *tables) error {
+		for _, location := range t.locations.rows {
+			if location.Status == domain.GeocodingPending ||
+				(location.Status == domain.GeocodingProcessing && location.UpdatedAt.Before(staleBefore)) {
+				claimed = append(claimed, &location)
+			}
+		}
+
+		slices.SortFunc(claimed, func(a, b *domain.RestaurantLocation) int {
+			return cmp.Or(a.UpdatedAt.Compare(b.UpdatedAt), cmp.Compare(a.RestaurantID, b.RestaurantID))
+		})
+		claimed = page(claimed, 0, limit)
+
+		now := r.clock.Now()
+		for _, location := range claimed {
+			location.Status = domain.GeocodingProcessing
+			location.UpdatedAt = now
+			t.locations.put(location.RestaurantID, *location)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// Save stores the outcome of geocoding a claimed address. The city of a geocoded address is added
+// to the catalogue unless it is already there. An address changed or claimed again in the
+// meantime is left alone.
+func (r *RestaurantLocationRepository) Save(ctx context.Context, location *domain.RestaurantLocation) error {
+	location.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		previous, ok := t.locations.get(location.RestaurantID)
+		if !ok || previous.Address != location.Address || previous.Status != domain.GeocodingProcessing {
+			return errors.New(common.ErrRestaurantLocationNotFound)
+		}
+
+		location.CityID = ""
+		if location.Status == domain.GeocodingDone && location.City != "" {
+			location.CityID = t.city(r.ids, location.CountryCode, location.Region, location.City)
+		}
+
+		t.locations.put(location.RestaurantID, *location)
+		return nil
+	})
+}
+
+// ListCities returns the cities with at least one restaurant placed in them, test restaurants
+// aside, in the country or in every country when it is empty, those with the most restaurants
+// first.
+func (r *RestaurantLocationRepository) ListCities(_ context.Context, countryCode string) ([]*domain.City, error) {
+	counts := make(map[string]int)
+	cities := make([]*domain.City, 0)
+	r.read(func(t *tables) {
+		for _, location := range t.locations.rows {
+			restaurant, ok := t.restaurants.get(location.RestaurantID)
+			if location.CityID != "" && ok && !restaurant.IsTest {
+				counts[location.CityID]++
+			}
+		}
+
+		for id, count := range counts {
+			city, ok := t.cities.get(id)
+			if !ok || (countryCode != "" && city.CountryCode != countryCode) {
+				continue
+			}
+			city.RestaurantCount = count
+			cities = append(cities, &city)
+		}
+	})
+
+	slices.SortFunc(cities, func(a, b *domain.City) int {
+		return cmp.Or(
+			cmp.Compare(b.RestaurantCount, a.RestaurantCount),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Region, b.Region),
+		)
+	})
+
+	return cities, nil
+}
+
+// city returns the ID of the city in the catalogue, adding the city when it is not there.
+func (t *tables) city(ids idgen.Generator, countryCode, region, name string) string {
+	for _, city := range t.cities.rows {
+		if city.CountryCode == countryCode && city.Region == region && city.Name == name {
+			return city.ID
+		}
+	}
+
+	city := domain.City{
+		ID:          ids.NewID(),
+		Name:        name,
+		Region:      region,
+		CountryCode: countryCode,
+	}
+	t.cities.put(city.ID, city)
+	return city.ID
+}
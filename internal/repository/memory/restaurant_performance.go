@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type RestaurantPerformanceRepository struct {
+	*Store
+}
+
+func NewRestaurantPerformanceRepository(store *Store) *RestaurantPerformanceRepository {
+	return &RestaurantPerformanceRepository{
+		Store: store,
+	}
+}
+
+// GetBetween sums up bookings and availability dated from from up to to, and responses and reviews
+// made in that time.
+func (r *RestaurantPerformanceRepository) GetBetween(_ context.Context, from, to time.Time) ([]*domain.RestaurantPerformance, error) {
+	fromDate, toDate := dateOf(from), dateOf(to)
+	dated := func(date time.Time) bool {
+		return !date.Before(fromDate) && date.Before(toDate)
+	}
+	made := func(at time.Time) bool {
+		return !at.Before(from) && at.Before(to)
+	}
+
+	performances := make([]*domain.RestaurantPerformance, 0)
+	r.read(func(t *tables) {
+		byRestaurant := make(map[string]*domain.RestaurantPerformance)
+		for id, restaurant := range t.restaurants.rows {
+			if restaurant.IsTest {
+				continue
+			}
+			performance := &domain.RestaurantPerformance{
+				RestaurantID:   id,
+				RestaurantName: restaurant.Name,
+				ContactEmail:   restaurant.ContactEmail,
+				From:           from,
+				To:             to,
+			}
+			byRestaurant[id] = performance
+			performances = append(performances, performance)
+		}
+
+		responseTimes := make(map[string]time.Duration)
+		for _, booking := range t.bookings.rows {
+			performance, ok := byRestaurant[booking.RestaurantID]
+			if !ok || booking.IsTest {
+				continue
+			}
+
+			if dated(booking.Date) {
+				performance.Bookings++
+				switch booking.Status {
+				case domain.BookingStatusCancelled:
+					performance.Cancellations++
+				case domain.BookingStatusRejected:
+				default:
+					performance.Guests += booking.GuestsCount
+				}
+			}
+			if respondedAt := respondedAt(booking); made(booking.CreatedAt) && respondedAt != nil {
+				performance.Responses++
+				responseTimes[booking.RestaurantID] += respondedAt.Sub(booking.CreatedAt)
+			}
+		}
+		for id, total := range responseTimes {
+			byRestaurant[id].AverageResponseTime = total / time.Duration(byRestaurant[id].Responses)
+		}
+
+		for _, availability := range t.availability.rows {
+			if performance, ok := byRestaurant[availability.RestaurantID]; ok && dated(availability.Date) {
+				performance.SeatsReserved += availability.Reserved
+				performance.SeatsOffered += availability.Capacity
+			}
+		}
+
+		ratings := make(map[string]int)
+		for _, review := range t.reviews.rows {
+			performance, ok := byRestaurant[review.RestaurantID]
+			if ok && review.Status == domain.ReviewStatusPublished && made(review.CreatedAt) {
+				performance.Reviews++
+				ratings[review.RestaurantID] += review.Rating
+			}
+		}
+		for id, total := range ratings {
+			byRestaurant[id].AverageRating = float64(total) / float64(byRestaurant[id].Reviews)
+		}
+	})
+
+	slices.SortFunc(performances, func(a, b *domain.RestaurantPerformance) int {
+		return cmp.Compare(a.RestaurantID, b.RestaurantID)
+	})
+	return performances, nil
+}
+
+// respondedAt returns when the restaurant confirmed or rejected the booking, or nil.
+func respondedAt(booking domain.Booking) *time.Time {
+	if booking.ConfirmedAt != nil {
+		return booking.ConfirmedAt
+	}
+	return booking.RejectedAt
+}
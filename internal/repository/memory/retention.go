@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type RetentionRepository struct {
+	*Store
+}
+
+func NewRetentionRepository(store *Store) *RetentionRepository {
+	return &RetentionRepository{
+		Store: store,
+	}
+}
+
+func (r *RetentionRepository) PurgeReadNotifications(ctx context.Context, readBefore time.Time) (int, error) {
+	var purged int
+	err := r.write(ctx, func(t *tables) error {
+		for id, notification := range t.notifications.rows {
+			if notification.ReadAt != nil && notification.ReadAt.Before(readBefore) {
+				t.notifications.delete(id)
+				purged++
+			}
+		}
+		return nil
+	})
+
+	return purged, err
+}
+
+// AnonymizeCompletedBookings detaches the bookings completed before the time from their guests:
+// the bookings and their reviews pass to the anonymous user, free text the guests entered is
+// cleared and the links granting access to the bookings are deleted.
+func (r *RetentionRepository) AnonymizeCompletedBookings(ctx context.Context, completedBefore time.Time) (int, error) {
+	var anonymized int
+	err := r.write(ctx, func(t *tables) error {
+		now := r.clock.Now()
+		for id, booking := range t.bookings.rows {
+			if booking.Status != domain.BookingStatusCompleted {
+				continue
+			}
+			if _, ok := t.anonymized.get(id); ok {
+				continue
+			}
+			// Bookings completed before the completion time was recorded count from their date.
+			completedAt := booking.Date
+			if booking.CompletedAt != nil {
+				completedAt = *booking.CompletedAt
+			}
+			if !completedAt.Before(completedBefore) {
+				continue
+			}
+
+			booking.UserID = domain.AnonymousUserID
+			booking.Comment = ""
+			booking.Occasion = ""
+			booking.UpdatedAt = now
+			t.bookings.put(id, booking)
+			t.anonymized.put(id, now)
+			anonymized++
+
+			if items, ok := t.preOrders.get(id); ok {
+				items = slices.Clone(items)
+				for i := range items {
+					items[i].Notes = ""
+				}
+				t.preOrders.put(id, items)
+			}
+			for linkID, link := range t.bookingLinks.rows {
+				if link.BookingID == id {
+					t.bookingLinks.delete(linkID)
+				}
+			}
+			for reviewID, review := range t.reviews.rows {
+				if review.BookingID == id {
+					review.UserID = domain.AnonymousUserID
+					t.reviews.put(reviewID, review)
+				}
+			}
+		}
+		return nil
+	})
+
+	return anonymized, err
+}
+
+func (r *RetentionRepository) CreateRun(ctx context.Context, run *domain.RetentionRun) error {
+	if run.ID == "" {
+		run.ID = r.ids.NewID()
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		t.retentionRuns.put(run.ID, *run)
+		return nil
+	})
+}
+
+func (r *RetentionRepository) ListRuns(_ context.Context, offset, limit int) ([]*domain.RetentionRun, error) {
+	runs := make([]*domain.RetentionRun, 0)
+	r.read(func(t *tables) {
+		for _, run := range t.retentionRuns.rows {
+			runs = append(runs, &run)
+		}
+	})
+
+	slices.SortFunc(runs, func(a, b *domain.RetentionRun) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+
+	return page(runs, offset, limit), nil
+}
@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type ReviewRepository struct {
+	*Store
+}
+
+func NewReviewRepository(store *Store) *ReviewRepository {
+	return &ReviewRepository{
+		Store: store,
+	}
+}
+
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	if review.ID == "" {
+		review.ID = r.ids.NewID()
+	}
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = r.clock.Now()
+	}
+	review.UpdatedAt = review.CreatedAt
+
+	return r.write(ctx, func(t *tables) error {
+		for _, existing := range t.reviews.rows {
+			if existing.BookingID == review.BookingID {
+				return errors.New(common.ErrReviewExists)
+			}
+		}
+		if _, ok := t.bookings.get(review.BookingID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateReview, errors.New(common.ErrBookingNotFound))
+		}
+
+		stored := *review
+		stored.Reply = nil
+		t.reviews.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *ReviewRepository) GetByID(_ context.Context, id string) (*domain.Review, error) {
+	var review *domain.Review
+	r.read(func(t *tables) {
+		review = t.review(id)
+	})
+	if review == nil {
+		return nil, errors.New(common.ErrReviewNotFound)
+	}
+
+	return review, nil
+}
+
+// ListByRestaurant returns the published reviews of the restaurant, newest first.
+func (r *ReviewRepository) ListByRestaurant(_ context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	reviews := make([]*domain.Review, 0)
+	r.read(func(t *tables) {
+		for id, review := range t.reviews.rows {
+			if review.RestaurantID == restaurantID && review.Status == domain.ReviewStatusPublished {
+				reviews = append(reviews, t.review(id))
+			}
+		}
+	})
+
+	slices.SortFunc(reviews, func(a, b *domain.Review) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(reviews, offset, limit), nil
+}
+
+func (r *ReviewRepository) UpdateStatus(ctx context.Context, id string, status domain.ReviewStatus) error {
+	return r.write(ctx, func(t *tables) error {
+		review, ok := t.reviews.get(id)
+		if !ok {
+			return errors.New(common.ErrReviewNotFound)
+		}
+
+		review.Status = status
+		review.UpdatedAt = r.clock.Now()
+		t.reviews.put(id, review)
+		return nil
+	})
+}
+
+// SaveReply stores the reply to the review, replacing the previous one but keeping the time the
+// review was first replied to.
+func (r *ReviewRepository) SaveReply(ctx context.Context, reply *domain.ReviewReply) error {
+	reply.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		review, ok := t.reviews.get(reply.ReviewID)
+		if !ok {
+			return fmt.Errorf("%s: %w", common.ErrSaveReviewReply, errors.New(common.ErrReviewNotFound))
+		}
+
+		reply.CreatedAt = reply.UpdatedAt
+		if previous, ok := t.reviewReplies.get(reply.ReviewID); ok {
+			reply.CreatedAt = previous.CreatedAt
+		}
+		t.reviewReplies.put(reply.ReviewID, *reply)
+
+		review.UpdatedAt = reply.UpdatedAt
+		t.reviews.put(review.ID, review)
+		return nil
+	})
+}
+
+// CreateFlag stores an open flag; a review can have only one open flag at a time.
+func (r *ReviewRepository) CreateFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	if flag.ID == "" {
+		flag.ID = r.ids.NewID()
+	}
+	flag.Status = domain.ReviewFlagStatusOpen
+	flag.CreatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		for _, existing := range t.reviewFlags.rows {
+			if existing.ReviewID == flag.ReviewID && existing.Status == domain.ReviewFlagStatusOpen {
+				return errors.New(common.ErrReviewAlreadyFlagged)
+			}
+		}
+		if _, ok := t.reviews.get(flag.ReviewID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateReviewFlag, errors.New(common.ErrReviewNotFound))
+		}
+
+		stored := *flag
+		stored.Review = nil
+		t.reviewFlags.put(stored.ID, stored)
+		return nil
+	})
+}
+
+func (r *ReviewRepository) GetFlag(_ context.Context, id string) (*domain.ReviewFlag, error) {
+	var flag domain.ReviewFlag
+	var ok bool
+	r.read(func(t *tables) {
+		flag, ok = t.reviewFlags.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrReviewFlagNotFound)
+	}
+
+	return &flag, nil
+}
+
+// ListFlags returns the flags with the status, oldest first, each with the review it is about.
+func (r *ReviewRepository) ListFlags(_ context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	flags := make([]*domain.ReviewFlag, 0)
+	r.read(func(t *tables) {
+		for _, flag := range t.reviewFlags.rows {
+			if flag.Status != status {
+				continue
+			}
+			if flag.Review = t.review(flag.ReviewID); flag.Review != nil {
+				flags = append(flags, &flag)
+			}
+		}
+	})
+
+	slices.SortFunc(flags, func(a, b *domain.ReviewFlag) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(flags, offset, limit), nil
+}
+
+// ResolveFlag stores the outcome of an open flag; a flag resolved in the meantime fails with
+// ErrReviewFlagResolved.
+func (r *ReviewRepository) ResolveFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	resolvedAt := r.clock.Now()
+
+	err := r.write(ctx, func(t *tables) error {
+		stored, ok := t.reviewFlags.get(flag.ID)
+		if !ok || stored.Status != domain.ReviewFlagStatusOpen {
+			return errors.New(common.ErrReviewFlagResolved)
+		}
+
+		stored.Status = flag.Status
+		stored.ResolutionNote = flag.ResolutionNote
+		stored.ResolvedAt = &resolvedAt
+		t.reviewFlags.put(flag.ID, stored)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	flag.ResolvedAt = &resolvedAt
+	return nil
+}
+
+// review returns the review with its reply, or nil.
+func (t *tables) review(id string) *domain.Review {
+	review, ok := t.reviews.get(id)
+	if !ok {
+		return nil
+	}
+
+	if reply, ok := t.reviewReplies.get(id); ok {
+		review.Reply = &reply
+	}
+	return &review
+}
+
+// deleteReview deletes the review with its reply and flags.
+func (t *tables) deleteReview(id string, at time.Time) {
+	t.reviews.delete(id)
+	t.bury(domain.ExportReviews, id, at)
+
+	if _, ok := t.reviewReplies.get(id); ok {
+		t.reviewReplies.delete(id)
+		t.bury(domain.ExportReviewReplies, id, at)
+	}
+	for flagID, flag := range t.reviewFlags.rows {
+		if flag.ReviewID == id {
+			t.reviewFlags.delete(flagID)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type SLORepository struct {
+	*Store
+}
+
+func NewSLORepository(store *Store) *SLORepository {
+	return &SLORepository{
+		Store: store,
+	}
+}
+
+// AddRequestMetrics adds the counts to those already kept for each hour.
+func (r *SLORepository) AddRequestMetrics(ctx context.Context, metrics []*domain.RequestMetrics) error {
+	return r.write(ctx, func(t *tables) error {
+		for _, m := range metrics {
+			hour := m.Hour.UTC()
+			total, _ := t.requestMetrics.get(hour)
+			total.Hour = hour
+			total.Requests += m.Requests
+			total.Errors += m.Errors
+			total.Slow += m.Slow
+			t.requestMetrics.put(hour, total)
+		}
+		return nil
+	})
+}
+
+func (r *SLORepository) SumRequestMetrics(_ context.Context, from, to time.Time) (*domain.RequestMetrics, error) {
+	metrics := domain.RequestMetrics{Hour: from}
+	r.read(func(t *tables) {
+		for hour, m := range t.requestMetrics.rows {
+			if !hour.Before(from) && hour.Before(to) {
+				metrics.Requests += m.Requests
+				metrics.Errors += m.Errors
+				metrics.Slow += m.Slow
+			}
+		}
+	})
+
+	return &metrics, nil
+}
+
+// SaveReport stores the report, replacing the one of the same date. As in the database, only the
+// counts are kept and the latency threshold is kept in milliseconds.
+func (r *SLORepository) SaveReport(ctx context.Context, report *domain.SLOReport) error {
+	stored := *report
+	stored.Date = dateOf(report.Date)
+	stored.Objectives.LatencyThreshold = report.Objectives.LatencyThreshold.Truncate(time.Millisecond)
+
+	return r.write(ctx, func(t *tables) error {
+		t.sloReports.put(stored.Date, stored)
+		return nil
+	})
+}
+
+func (r *SLORepository) ListReports(_ context.Context, from, to time.Time) ([]*domain.SLOReport, error) {
+	reports := make([]*domain.SLOReport, 0)
+	r.read(func(t *tables) {
+		for date, stored := range t.sloReports.rows {
+			if date.Before(dateOf(from)) || date.After(dateOf(to)) {
+				continue
+			}
+
+			// The shares and burn rates are derived from the counts, as when the reports are read back
+			// from the database.
+			report := domain.NewSLOReport(date, domain.RequestMetrics{
+				Requests: stored.Requests,
+				Errors:   stored.Errors,
+				Slow:     stored.Slow,
+			}, stored.Objectives)
+			report.CreatedAt = stored.CreatedAt
+			reports = append(reports, report)
+		}
+	})
+
+	slices.SortFunc(reports, func(a, b *domain.SLOReport) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return reports, nil
+}
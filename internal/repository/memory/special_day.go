@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type SpecialDayRepository struct {
+	*Store
+}
+
+func NewSpecialDayRepository(store *Store) *SpecialDayRepository {
+	return &SpecialDayRepository{
+		Store: store,
+	}
+}
+
+func (r *SpecialDayRepository) Set(ctx context.Context, day *domain.SpecialDay) error {
+	day.Date = dateOf(day.Date)
+	day.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(day.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrSetSpecialDay, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		key := specialDayKey{RestaurantID: day.RestaurantID, Date: day.Date}
+		// The special day the restaurant had on the date keeps its ID.
+		if existing, ok := t.specialDays.get(key); ok {
+			day.ID = existing.ID
+		} else if day.ID == "" {
+			day.ID = r.ids.NewID()
+		}
+
+		t.specialDays.put(key, *day)
+		return nil
+	})
+}
+
+func (r *SpecialDayRepository) ListByRestaurant(_ context.Context, restaurantID string, from time.Time) ([]*domain.SpecialDay, error) {
+	from = dateOf(from)
+
+	days := make([]*domain.SpecialDay, 0)
+	r.read(func(t *tables) {
+		for key, day := range t.specialDays.rows {
+			if key.RestaurantID == restaurantID && !key.Date.Before(from) {
+				days = append(days, &day)
+			}
+		}
+	})
+
+	slices.SortFunc(days, func(a, b *domain.SpecialDay) int {
+		return a.Date.Compare(b.Date)
+	})
+	return days, nil
+}
+
+func (r *SpecialDayRepository) Delete(ctx context.Context, restaurantID string, date time.Time) error {
+	key := specialDayKey{RestaurantID: restaurantID, Date: dateOf(date)}
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.specialDays.get(key); !ok {
+			return errors.New(common.ErrSpecialDayNotFound)
+		}
+
+		t.specialDays.delete(key)
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type SyncRepository struct {
+	*Store
+}
+
+func NewSyncRepository(store *Store) *SyncRepository {
+	return &SyncRepository{
+		Store: store,
+	}
+}
+
+func (r *SyncRepository) ListChanges(_ context.Context, entity domain.ExportEntity, after domain.SyncCursor, limit int) ([]*domain.SyncChange, error) {
+	if !entity.IsValid() {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, errors.New("unknown entity "+string(entity)))
+	}
+
+	// Records and the tombstones left for deleted records, merged in the order of change.
+	var rows []exportRow
+	changes := make([]*domain.SyncChange, 0)
+	r.read(func(t *tables) {
+		rows = t.exportRows(entity)
+		for _, tombstone := range t.tombstones.rows {
+			if tombstone.Entity == entity && changedAfter(tombstone.DeletedAt, tombstone.ID, after.ChangedAt, after.ID) {
+				changes = append(changes, &domain.SyncChange{ID: tombstone.ID, ChangedAt: tombstone.DeletedAt, Deleted: true})
+			}
+		}
+	})
+
+	for _, row := range rows {
+		if !changedAfter(row.UpdatedAt, row.ID, after.ChangedAt, after.ID) {
+			continue
+		}
+		data, err := json.Marshal(row.Record)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, err)
+		}
+		changes = append(changes, &domain.SyncChange{ID: row.ID, ChangedAt: row.UpdatedAt, Data: data})
+	}
+
+	slices.SortFunc(changes, func(a, b *domain.SyncChange) int {
+		return cmp.Or(a.ChangedAt.Compare(b.ChangedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return page(changes, 0, limit), nil
+}
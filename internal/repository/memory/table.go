@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type TableRepository struct {
+	*Store
+}
+
+func NewTableRepository(store *Store) *TableRepository {
+	return &TableRepository{
+		Store: store,
+	}
+}
+
+func (r *TableRepository) Create(ctx context.Context, table *domain.Table) error {
+	if table.ID == "" {
+		table.ID = r.ids.NewID()
+	}
+	now := r.clock.Now()
+	table.CreatedAt = now
+	table.UpdatedAt = now
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(table.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrCreateTable, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		t.restaurantTables.put(table.ID, *table)
+		return nil
+	})
+}
+
+func (r *TableRepository) GetByID(_ context.Context, id string) (*domain.Table, error) {
+	var table domain.Table
+	var ok bool
+	r.read(func(t *tables) {
+		table, ok = t.restaurantTables.get(id)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrTableNotFound)
+	}
+
+	return &table, nil
+}
+
+func (r *TableRepository) ListByRestaurant(_ context.Context, restaurantID string) ([]*domain.Table, error) {
+	var restaurantTables []*domain.Table
+	r.read(func(t *tables) {
+		restaurantTables = t.tablesOf(restaurantID)
+	})
+	return restaurantTables, nil
+}
+
+func (r *TableRepository) Update(ctx context.Context, table *domain.Table) error {
+	return r.write(ctx, func(t *tables) error {
+		existing, ok := t.restaurantTables.get(table.ID)
+		if !ok {
+			return errors.New(common.ErrTableNotFound)
+		}
+
+		table.RestaurantID = existing.RestaurantID
+		table.CreatedAt = existing.CreatedAt
+		table.UpdatedAt = r.clock.Now()
+		t.restaurantTables.put(table.ID, *table)
+		return nil
+	})
+}
+
+func (r *TableRepository) ListHolds(_ context.Context, restaurantID string, date time.Time) ([]domain.TableHold, error) {
+	var holds []domain.TableHold
+	r.read(func(t *tables) {
+		holds = t.tableHolds(restaurantID, date)
+	})
+	return holds, nil
+}
+
+func (r *TableRepository) ListSeatedRestaurants(_ context.Context, date time.Time) ([]string, error) {
+	date = dateOf(date)
+
+	restaurantIDs := make([]string, 0)
+	r.read(func(t *tables) {
+		for _, booking := range t.bookings.rows {
+			if booking.TableID != "" && booking.Date.Equal(date) && isActive(booking.Status) && !slices.Contains(restaurantIDs, booking.RestaurantID) {
+				restaurantIDs = append(restaurantIDs, booking.RestaurantID)
+			}
+		}
+	})
+	slices.Sort(restaurantIDs)
+	return restaurantIDs, nil
+}
+
+// Reseat checks every move against the bookings that stay and the other moves before it moves
+// any booking.
+func (r *TableRepository) Reseat(ctx context.Context, moves map[string]string, at time.Time) error {
+	return r.write(ctx, func(t *tables) error {
+		moved := make([]domain.Booking, 0, len(moves))
+		for bookingID, tableID := range moves {
+			booking, ok := t.bookings.get(bookingID)
+			if !ok || !isActive(booking.Status) {
+				return errors.New(common.ErrTableUnavailable)
+			}
+			booking.TableID = tableID
+			moved = append(moved, booking)
+		}
+
+		for _, booking := range moved {
+			start, end, _ := booking.Seating()
+			for _, other := range t.bookings.rows {
+				if other.ID == booking.ID || !isActive(other.Status) {
+					continue
+				}
+				if tableID, ok := moves[other.ID]; ok {
+					other.TableID = tableID
+				}
+				if other.TableID != booking.TableID {
+					continue
+				}
+				if otherStart, otherEnd, ok := other.Seating(); ok && otherStart.Before(end) && start.Before(otherEnd) {
+					return errors.New(common.ErrTableUnavailable)
+				}
+			}
+		}
+
+		for _, booking := range moved {
+			booking.UpdatedAt = at
+			t.bookings.put(booking.ID, booking)
+		}
+		return nil
+	})
+}
+
+// tablesOf returns the tables of the restaurant ordered by zone and name.
+func (t *tables) tablesOf(restaurantID string) []*domain.Table {
+	restaurantTables := make([]*domain.Table, 0)
+	for _, table := range t.restaurantTables.rows {
+		if table.RestaurantID == restaurantID {
+			restaurantTables = append(restaurantTables, &table)
+		}
+	}
+
+	slices.SortFunc(restaurantTables, func(a, b *domain.Table) int {
+		return cmp.Or(cmp.Compare(a.Zone, b.Zone), cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+	return restaurantTables
+}
+
+// tableHolds returns the tables of the restaurant held by its active bookings of the date.
+func (t *tables) tableHolds(restaurantID string, date time.Time) []domain.TableHold {
+	date = dateOf(date)
+
+	holds := make([]domain.TableHold, 0)
+	for _, booking := range t.bookings.rows {
+		if booking.RestaurantID != restaurantID || booking.TableID == "" || !booking.Date.Equal(date) || !isActive(booking.Status) {
+			continue
+		}
+		holds = append(holds, domain.TableHold{
+			TableID:   booking.TableID,
+			BookingID: booking.ID,
+			Date:      booking.Date,
+			Time:      booking.Time,
+			Duration:  booking.Duration,
+		})
+	}
+
+	slices.SortFunc(holds, func(a, b domain.TableHold) int {
+		return cmp.Or(cmp.Compare(a.Time, b.Time), cmp.Compare(a.BookingID, b.BookingID))
+	})
+	return holds
+}
+
+func (r *TableRepository) Delete(ctx context.Context, id string) error {
+	today := dateOf(r.clock.Now())
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurantTables.get(id); !ok {
+			return errors.New(common.ErrTableNotFound)
+		}
+		for _, booking := range t.bookings.rows {
+			if booking.TableID == id && !booking.Date.Before(today) && isActive(booking.Status) {
+				return errors.New(common.ErrTableBooked)
+			}
+		}
+
+		t.restaurantTables.delete(id)
+		for bookingID, booking := range t.bookings.rows {
+			if booking.TableID == id {
+				booking.TableID = ""
+				t.bookings.put(bookingID, booking)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+)
+
+// Transactor runs several repository calls in one transaction. Writes outside the transaction wait
+// until it ends; the writes made in it are undone when fn fails.
+type Transactor struct {
+	*Store
+}
+
+func NewTransactor(store *Store) *Transactor {
+	return &Transactor{
+		Store: store,
+	}
+}
+
+func (t *Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.run(ctx, false, fn)
+}
+
+// DryRun runs fn in a transaction that is always rolled back, so the caller can see
+// the changes fn would make without persisting them.
+func (t *Transactor) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.run(ctx, true, fn)
+}
+
+// run nests a transaction started in another one like a savepoint: rolling it back undoes only
+// the writes made in it.
+func (t *Transactor) run(ctx context.Context, rollback bool, fn func(ctx context.Context) error) error {
+	if !t.inTransaction(ctx) {
+		t.txMu.Lock()
+		defer t.txMu.Unlock()
+		ctx = context.WithValue(ctx, txContextKey{}, t.Store)
+	}
+
+	t.mu.Lock()
+	mark := len(t.journal.undo)
+	t.journal.depth++
+	t.mu.Unlock()
+
+	err := fn(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.journal.depth--
+	if err != nil || rollback {
+		t.journal.rollback(mark)
+	}
+	if t.journal.depth == 0 {
+		t.journal.undo = nil
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type UserRepository struct {
+	*Store
+}
+
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{
+		Store: store,
+	}
+}
+
+func (r *UserRepository) GetByID(_ context.Context, id domain.UserID) (*domain.User, error) {
+	var user domain.User
+	var ok bool
+	r.read(func(t *tables) {
+		user, ok = t.users.get(id.String())
+	})
+	if !ok {
+		return nil, errors.New(common.ErrUserNotFound)
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	var user *domain.User
+	r.read(func(t *tables) {
+		user = t.userByEmail(email)
+	})
+	if user == nil {
+		return nil, errors.New(common.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	if user.ID == "" {
+		user.ID = r.ids.NewID()
+	}
+	now := r.clock.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		if t.userByEmail(user.NormalizedEmail) != nil {
+			return errors.New(common.ErrEmailAlreadyExists)
+		}
+		if _, ok := t.users.get(user.ID); ok {
+			return errors.New(common.ErrCreateUser)
+		}
+
+		t.users.put(user.ID, *user)
+		return nil
+	})
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	user.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		current, ok := t.users.get(user.ID)
+		if !ok {
+			return errors.New(common.ErrUserNotFound)
+		}
+		if current.Email != user.Email {
+			if owner := t.userByEmail(user.NormalizedEmail); owner != nil && owner.ID != user.ID {
+				return errors.New(common.ErrEmailAlreadyExists)
+			}
+		}
+
+		stored := *user
+		stored.CreatedAt = current.CreatedAt
+		t.users.put(user.ID, stored)
+		return nil
+	})
+}
+
+// userByEmail returns the user with the normalized email, or nil.
+func (t *tables) userByEmail(normalizedEmail string) *domain.User {
+	for _, user := range t.users.rows {
+		if user.NormalizedEmail == normalizedEmail {
+			return &user
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type WidgetSettingsRepository struct {
+	*Store
+}
+
+func NewWidgetSettingsRepository(store *Store) *WidgetSettingsRepository {
+	return &WidgetSettingsRepository{
+		Store: store,
+	}
+}
+
+func (r *WidgetSettingsRepository) Get(_ context.Context, restaurantID string) (*domain.WidgetSettings, error) {
+	var settings domain.WidgetSettings
+	var ok bool
+	r.read(func(t *tables) {
+		settings, ok = t.widgetSettings.get(restaurantID)
+	})
+	if !ok {
+		return nil, errors.New(common.ErrWidgetSettingsNotFound)
+	}
+
+	return &settings, nil
+}
+
+func (r *WidgetSettingsRepository) Save(ctx context.Context, settings *domain.WidgetSettings) error {
+	settings.UpdatedAt = r.clock.Now()
+
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(settings.RestaurantID); !ok {
+			return fmt.Errorf("%s: %w", common.ErrSaveWidgetSettings, errors.New(common.ErrRestaurantNotFound))
+		}
+
+		t.widgetSettings.put(settings.RestaurantID, *settings)
+		return nil
+	})
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+type WorkingHoursRepository struct {
+	*Store
+}
+
+func NewWorkingHoursRepository(store *Store) *WorkingHoursRepository {
+	return &WorkingHoursRepository{
+		Store: store,
+	}
+}
+
+// GetByRestaurantID returns the working hours of the restaurant still valid today.
+func (r *WorkingHoursRepository) GetByRestaurantID(_ context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
+	today := dateOf(r.clock.Now())
+
+	hours := make([]*domain.WorkingHours, 0)
+	r.read(func(t *tables) {
+		for _, h := range t.workingHours.rows {
+			if domain.RestaurantID(h.RestaurantID) == restaurantID && (h.ValidTo.IsZero() || h.ValidTo.After(today)) {
+				hours = append(hours, &h)
+			}
+		}
+	})
+
+	slices.SortFunc(hours, func(a, b *domain.WorkingHours) int {
+		return cmp.Or(cmp.Compare(a.WeekDay, b.WeekDay), cmp.Compare(a.OpenTime, b.OpenTime))
+	})
+
+	return hours, nil
+}
+
+// SetWorkingHours stores the hours, ending the validity of those set before for the weekday.
+func (r *WorkingHoursRepository) SetWorkingHours(ctx context.Context, hours *domain.WorkingHours) error {
+	if hours.ID == "" {
+		hours.ID = r.ids.NewID()
+	}
+
+	now := r.clock.Now()
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.restaurants.get(hours.RestaurantID); !ok {
+			return errors.New(common.ErrRestaurantNotFound)
+		}
+
+		for id, h := range t.workingHours.rows {
+			if h.RestaurantID == hours.RestaurantID && h.WeekDay == hours.WeekDay &&
+				(h.ValidTo.IsZero() || h.ValidTo.After(now)) && !h.ValidFrom.After(now) {
+				h.ValidTo = now
+				t.workingHours.put(id, h)
+				break
+			}
+		}
+
+		t.workingHours.put(hours.ID, *hours)
+		t.touchRestaurant(hours.RestaurantID, now)
+		return nil
+	})
+}
+
+func (r *WorkingHoursRepository) CreateBatch(ctx context.Context, hours []*domain.WorkingHours) error {
+	if len(hours) == 0 {
+		return nil
+	}
+
+	return r.write(ctx, func(t *tables) error {
+		for _, h := range hours {
+			if h.ID == "" {
+				h.ID = r.ids.NewID()
+			}
+			if _, ok := t.restaurants.get(h.RestaurantID); !ok {
+				return errors.New(common.ErrRestaurantNotFound)
+			}
+		}
+
+		for _, h := range hours {
+			t.workingHours.put(h.ID, *h)
+		}
+		return nil
+	})
+}
+
+func (r *WorkingHoursRepository) DeleteWorkingHours(ctx context.Context, id string) error {
+	return r.write(ctx, func(t *tables) error {
+		if _, ok := t.workingHours.get(id); !ok {
+			return errors.New(common.ErrWorkingHoursNotFound)
+		}
+
+		t.workingHours.delete(id)
+		return nil
+	})
+}
@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const accountColumns = `id, kind, email, normalized_email, password_hash, COALESCE(user_id::text, ''),
+	COALESCE(restaurant_id::text, ''), email_verified_at, COALESCE(email_verification_hash, ''), created_at, updated_at`
+
+const refreshTokenColumns = `id, account_id, family_id, token_hash, expires_at, created_at, revoked_at`
+
+type AccountRepository struct {
+	*Repository
+}
+
+func NewAccountRepository(repository *Repository) *AccountRepository {
+	return &AccountRepository{
+		Repository: repository,
+	}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) error {
+	log, _ := logger.FromContext(ctx)
+
+	// Both the email and the user of an account are unique, a conflict on either means the
+	// account exists.
+	const query = `
+		INSERT INTO accounts (id, kind, email, normalized_email, password_hash, user_id, restaurant_id,
+			email_verification_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::uuid, NULLIF($7, '')::uuid, NULLIF($8, ''), $9, $9)
+		ON CONFLICT DO NOTHING
+	`
+
+	if account.ID == "" {
+		account.ID = r.ids.NewID()
+	}
+	now := time.Now()
+	account.CreatedAt = now
+	account.UpdatedAt = now
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		account.ID,
+		account.Kind,
+		account.Email,
+		account.NormalizedEmail,
+		account.PasswordHash,
+		account.UserID,
+		account.RestaurantID,
+		account.EmailVerificationHash,
+		now,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateAccount,
+			zap.String("kind", string(account.Kind)),
+			zap.String("userID", account.UserID),
+			zap.String("restaurantID", account.RestaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateAccount, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrAccountExists)
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) GetByID(ctx context.Context, id string) (*domain.Account, error) {
+	return r.get(ctx, `SELECT `+accountColumns+` FROM accounts WHERE id::text = $1`, id)
+}
+
+func (r *AccountRepository) GetByEmail(ctx context.Context, email string) (*domain.Account, error) {
+	return r.get(ctx, `SELECT `+accountColumns+` FROM accounts WHERE normalized_email = $1`, email)
+}
+
+func (r *AccountRepository) SetVerificationToken(ctx context.Context, id, tokenHash string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE accounts
+		SET email_verification_hash = $2, updated_at = NOW()
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, tokenHash)
+	if err != nil {
+		log.Error(ctx, common.ErrVerifyEmail, zap.String("accountID", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrVerifyEmail, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrAccountNotFound)
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) VerifyEmail(ctx context.Context, tokenHash string, at time.Time) (*domain.Account, error) {
+	// The token is cleared in the same statement, so that of two requests racing with it only one
+	// finds it.
+	const query = `
+		UPDATE accounts
+		SET email_verified_at = $2, email_verification_hash = NULL, updated_at = $2
+		WHERE email_verification_hash = $1
+		RETURNING ` + accountColumns
+
+	account, err := r.get(ctx, query, tokenHash, at)
+	if err != nil && err.Error() == common.ErrAccountNotFound {
+		return nil, errors.New(common.ErrVerificationTokenNotFound)
+	}
+	return account, err
+}
+
+func (r *AccountRepository) get(ctx context.Context, query string, args ...any) (*domain.Account, error) {
+	log, _ := logger.FromContext(ctx)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var account domain.Account
+	err = executor.QueryRow(ctx, query, args...).Scan(
+		&account.ID,
+		&account.Kind,
+		&account.Email,
+		&account.NormalizedEmail,
+		&account.PasswordHash,
+		&account.UserID,
+		&account.RestaurantID,
+		&account.EmailVerifiedAt,
+		&account.EmailVerificationHash,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrAccountNotFound)
+		}
+		log.Error(ctx, common.ErrGetAccount, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetAccount, err)
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO refresh_tokens (id, account_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if token.ID == "" {
+		token.ID = r.ids.NewID()
+	}
+	if token.FamilyID == "" {
+		token.FamilyID = token.ID
+	}
+	token.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		token.ID,
+		token.AccountID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateRefreshToken, zap.String("accountID", token.AccountID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateRefreshToken, err)
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `SELECT ` + refreshTokenColumns + ` FROM refresh_tokens WHERE token_hash = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var token domain.RefreshToken
+	err = executor.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.AccountID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.RevokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRefreshTokenNotFound)
+		}
+		log.Error(ctx, common.ErrGetRefreshToken, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRefreshToken, err)
+	}
+
+	return &token, nil
+}
+
+func (r *AccountRepository) RevokeRefreshToken(ctx context.Context, id string, at time.Time) (bool, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE id::text = $1 AND revoked_at IS NULL
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return false, err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, at)
+	if err != nil {
+		log.Error(ctx, common.ErrRevokeRefreshToken, zap.String("id", id), zap.Error(err))
+		return false, fmt.Errorf("%s: %w", common.ErrRevokeRefreshToken, err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *AccountRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id::text = $1 AND revoked_at IS NULL
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	if _, err := executor.Exec(ctx, query, familyID, at); err != nil {
+		log.Error(ctx, common.ErrRevokeRefreshToken, zap.String("familyID", familyID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrRevokeRefreshToken, err)
+	}
+
+	return nil
+}
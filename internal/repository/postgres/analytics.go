@@ -0,0 +1,328 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type AnalyticsRepository struct {
+	*Repository
+}
+
+func NewAnalyticsRepository(repository *Repository) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		Repository: repository,
+	}
+}
+
+// ListBookings returns the bookings with only the columns analytics need: the date, time, guests,
+// status and creation time.
+func (r *AnalyticsRepository) ListBookings(ctx context.Context, restaurantID string, from, to time.Time) ([]*domain.Booking, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, date, time, guests_count, status, created_at
+		FROM bookings
+		WHERE restaurant_id::text = $1 AND date >= $2 AND date < $3 AND NOT is_test
+		ORDER BY date, time
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListBookingHistory,
+			zap.String("restaurantID", restaurantID),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListBookingHistory, err)
+	}
+	defer rows.Close()
+
+	bookings := make([]*domain.Booking, 0)
+	for rows.Next() {
+		booking := &domain.Booking{RestaurantID: restaurantID}
+		if err := rows.Scan(&booking.ID, &booking.Date, &booking.Time, &booking.GuestsCount, &booking.Status, &booking.CreatedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListBookingHistory, err)
+		}
+		bookings = append(bookings, booking)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListBookingHistory, err)
+	}
+
+	return bookings, nil
+}
+
+func (r *AnalyticsRepository) CountHourlyDemand(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.HourlyDemand, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT EXTRACT(DOW FROM date)::int AS weekday, split_part(time, ':', 1)::int AS hour,
+			   COUNT(*), SUM(guests_count)
+		FROM bookings
+		WHERE restaurant_id::text = $1 AND date >= $2 AND date < $3 AND NOT is_test
+		GROUP BY weekday, hour
+		ORDER BY weekday, hour
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrCountHourlyDemand,
+			zap.String("restaurantID", restaurantID),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrCountHourlyDemand, err)
+	}
+	defer rows.Close()
+
+	demand := make([]domain.HourlyDemand, 0)
+	for rows.Next() {
+		var hourly domain.HourlyDemand
+		if err := rows.Scan(&hourly.Weekday, &hourly.Hour, &hourly.Bookings, &hourly.Guests); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrCountHourlyDemand, err)
+		}
+		demand = append(demand, hourly)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrCountHourlyDemand, err)
+	}
+
+	return demand, nil
+}
+
+func (r *AnalyticsRepository) CountSeated(ctx context.Context, restaurantID string, at time.Time) (int, int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COUNT(*), COALESCE(SUM(guests_count), 0)
+		FROM bookings
+		WHERE restaurant_id::text = $1 AND date >= $2 AND date <= $3 AND NOT is_test AND status = 'confirmed'
+		  AND date + time::time <= $4::timestamp
+		  AND date + time::time + make_interval(mins => CASE WHEN duration > 0 THEN duration ELSE $5 END) > $4::timestamp
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, 0, err
+	}
+	defer release()
+
+	at = at.UTC()
+	var bookings, guests int
+	err = executor.QueryRow(ctx, query, restaurantID, at.AddDate(0, 0, -1).Format("2006-01-02"), at.Format("2006-01-02"),
+		at.Format("2006-01-02 15:04:05"), int(domain.DefaultBookingDuration/time.Minute)).Scan(&bookings, &guests)
+	if err != nil {
+		log.Error(ctx, common.ErrCountSeatedBookings,
+			zap.String("restaurantID", restaurantID),
+			zap.Time("at", at),
+			zap.Error(err))
+		return 0, 0, fmt.Errorf("%s: %w", common.ErrCountSeatedBookings, err)
+	}
+
+	return bookings, guests, nil
+}
+
+// responseLatencies are the bookings requested from $2 up to $3 with the seconds the restaurant
+// took to confirm or reject them, NULL while they are unanswered. Bookings requested on a day the
+// restaurant was closed are left out.
+const responseLatencies = `
+	SELECT restaurant_id, status,
+		   EXTRACT(EPOCH FROM COALESCE(confirmed_at, rejected_at) - created_at)::float8 AS latency
+	FROM bookings
+	WHERE created_at >= $2 AND created_at < $3 AND NOT is_test
+	  AND NOT EXISTS (
+		  SELECT 1 FROM restaurant_closures c
+		  WHERE c.restaurant_id = bookings.restaurant_id
+		    AND c.starts_on <= (bookings.created_at AT TIME ZONE 'UTC')::date
+		    AND c.reopens_on > (bookings.created_at AT TIME ZONE 'UTC')::date
+	  )
+`
+
+const responseLatencyColumns = `
+	COUNT(*), COUNT(b.latency), COUNT(*) FILTER (WHERE b.status = 'pending'),
+	COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY b.latency), 0),
+	COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY b.latency), 0),
+	COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY b.latency), 0)
+`
+
+func (r *AnalyticsRepository) GetResponseLatency(ctx context.Context, restaurantID string, from, to time.Time) (*domain.ResponseLatency, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + responseLatencyColumns + `
+		FROM (` + responseLatencies + `) b
+		WHERE b.restaurant_id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	latency := &domain.ResponseLatency{RestaurantID: restaurantID, From: from, To: to}
+	err = scanResponseLatency(executor.QueryRow(ctx, query, restaurantID, from, to), latency)
+	if err != nil {
+		log.Error(ctx, common.ErrGetResponseLatency,
+			zap.String("restaurantID", restaurantID),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetResponseLatency, err)
+	}
+
+	return latency, nil
+}
+
+func (r *AnalyticsRepository) ListSlowResponders(
+	ctx context.Context,
+	from, to time.Time,
+	threshold time.Duration,
+	minResponses int,
+) ([]*domain.ResponseLatency, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT r.id, r.name, ` + responseLatencyColumns + `
+		FROM (` + responseLatencies + `) b
+		JOIN restaurants r ON r.id = b.restaurant_id
+		WHERE NOT r.is_test
+		GROUP BY r.id, r.name
+		HAVING COUNT(b.latency) >= $1 AND percentile_cont(0.5) WITHIN GROUP (ORDER BY b.latency) > $4
+		ORDER BY percentile_cont(0.5) WITHIN GROUP (ORDER BY b.latency) DESC, r.id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, minResponses, from, to, threshold.Seconds())
+	if err != nil {
+		log.Error(ctx, common.ErrListSlowResponders,
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSlowResponders, err)
+	}
+	defer rows.Close()
+
+	latencies := make([]*domain.ResponseLatency, 0)
+	for rows.Next() {
+		latency := &domain.ResponseLatency{From: from, To: to}
+		if err := scanResponseLatency(rows, latency, &latency.RestaurantID, &latency.RestaurantName); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSlowResponders, err)
+		}
+		latencies = append(latencies, latency)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSlowResponders, err)
+	}
+
+	return latencies, nil
+}
+
+func (r *AnalyticsRepository) ListCampaignPerformance(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.CampaignPerformance, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT a.utm_source, a.utm_medium, a.utm_campaign, a.referral_code,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE b.status = 'completed'),
+		       COUNT(*) FILTER (WHERE b.status IN ('cancelled', 'rejected')),
+		       COALESCE(SUM(b.guests_count) FILTER (WHERE b.status = 'completed'), 0)
+		FROM booking_attributions a
+		JOIN bookings b ON b.id = a.booking_id
+		WHERE a.created_at >= $1 AND a.created_at < $2 AND NOT b.is_test
+		  AND ($3 = '' OR b.restaurant_id::text = $3)
+		GROUP BY a.utm_source, a.utm_medium, a.utm_campaign, a.referral_code
+		ORDER BY 6 DESC, 5 DESC, a.utm_source, a.utm_medium, a.utm_campaign, a.referral_code
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, from, to, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListCampaignPerformance,
+			zap.String("restaurantID", restaurantID),
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListCampaignPerformance, err)
+	}
+	defer rows.Close()
+
+	campaigns := make([]domain.CampaignPerformance, 0)
+	for rows.Next() {
+		var performance domain.CampaignPerformance
+		err := rows.Scan(
+			&performance.Source,
+			&performance.Medium,
+			&performance.Campaign,
+			&performance.ReferralCode,
+			&performance.Bookings,
+			&performance.Completed,
+			&performance.Cancelled,
+			&performance.Guests,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListCampaignPerformance, err)
+		}
+		campaigns = append(campaigns, performance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListCampaignPerformance, err)
+	}
+
+	return campaigns, nil
+}
+
+// scanResponseLatency scans the leading columns into dest and then the responseLatencyColumns.
+func scanResponseLatency(row pgx.Row, latency *domain.ResponseLatency, dest ...any) error {
+	var p50, p90, p99 float64
+	dest = append(dest, &latency.Requests, &latency.Responses, &latency.Unanswered, &p50, &p90, &p99)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+
+	latency.P50 = time.Duration(p50 * float64(time.Second))
+	latency.P90 = time.Duration(p90 * float64(time.Second))
+	latency.P99 = time.Duration(p99 * float64(time.Second))
+	return nil
+}
@@ -10,14 +10,15 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
 var (
-	ErrAvailabilityNotFound = errors.New(common.ErrAvailabilityNotFound)
-	ErrInsufficientCapacity = errors.New(common.ErrInsufficientCapacity)
+	ErrAvailabilityNotFound  = errors.New(common.ErrAvailabilityNotFound)
+	ErrInsufficientCapacity  = errors.New(common.ErrInsufficientCapacity)
+	ErrCapacityBelowReserved = errors.New(common.ErrCapacityBelowReserved)
+	ErrAvailabilityReserved  = errors.New(common.ErrAvailabilityReserved)
 )
 
 type AvailabilityRepository struct {
@@ -30,17 +31,32 @@ func NewAvailabilityRepository(repository *Repository) *AvailabilityRepository {
 	}
 }
 
-func (r *AvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (r *AvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	logger, err := logger.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", common.ErrExecuteAvailabilityQuery, err)
 	}
 
+	// The seats the free tables of a restaurant with tables cannot offer at the time of a slot count
+	// as reserved, as its bookings are seated at tables rather than counted against the slots.
 	const query = `
-		SELECT id, restaurant_id, date, time_slot, capacity, reserved
-		FROM availability
-		WHERE restaurant_id = $1 AND date = $2
-		ORDER BY time_slot
+		SELECT a.id, a.restaurant_id, a.date, a.time_slot, a.capacity,
+			CASE WHEN floor_plan.seats IS NULL THEN a.reserved
+				ELSE LEAST(a.capacity, a.reserved + a.capacity - LEAST(a.capacity, floor_plan.free))
+			END
+		FROM availability a
+		LEFT JOIN LATERAL (
+			SELECT SUM(t.seats) AS seats,
+				COALESCE(SUM(t.seats) FILTER (WHERE NOT EXISTS (
+					SELECT 1 FROM bookings b
+					WHERE b.table_id = t.id AND b.status IN ('pending', 'confirmed')
+						AND booking_seating(b.date, b.time, b.duration) && booking_seating(a.date, a.time_slot, 0)
+				)), 0) AS free
+			FROM restaurant_tables t
+			WHERE t.restaurant_id = a.restaurant_id
+		) floor_plan ON TRUE
+		WHERE a.restaurant_id = $1 AND a.date = $2
+		ORDER BY a.time_slot
 	`
 
 	executor, release, err := r.GetExecutor(ctx)
@@ -51,7 +67,7 @@ func (r *AvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, res
 	defer release()
 
 	formattedDate := date.Format("2006-01-02")
-	rows, err := executor.Query(ctx, query, restaurantID, formattedDate)
+	rows, err := executor.Query(ctx, query, restaurantID.String(), formattedDate)
 	if err != nil {
 		logger.Error(ctx, common.ErrExecuteAvailabilityQuery, zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", common.ErrExecuteAvailabilityQuery, err)
@@ -85,11 +101,14 @@ func (r *AvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, res
 	return availabilities, nil
 }
 
-func (r *AvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability) error {
+// SetAvailability creates or updates the slot. Lowering the capacity of an existing slot below its
+// reserved seats fails with ErrCapacityBelowReserved unless force is set; availability.Reserved
+// is filled with the current reserved seats either way.
+func (r *AvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error {
 	log, _ := logger.FromContext(ctx)
 
 	if availability.ID == "" {
-		availability.ID = uuid.New().String()
+		availability.ID = r.ids.NewID()
 	}
 
 	executor, release, err := r.GetExecutor(ctx)
@@ -113,70 +132,108 @@ func (r *AvailabilityRepository) SetAvailability(ctx context.Context, availabili
 	const checkQuery = `
 		SELECT id, reserved FROM availability
 		WHERE restaurant_id = $1 AND date = $2 AND time_slot = $3
+		FOR UPDATE
 	`
 
 	formattedDate := availability.Date.Format("2006-01-02")
-	var existingID string
-	var reserved int
-	err = executor.QueryRow(ctx, checkQuery, availability.RestaurantID, formattedDate, availability.TimeSlot).Scan(&existingID, &reserved)
-
 	availability.UpdatedAt = time.Now()
 
-	if err == nil {
-		const updateQuery = `
-			UPDATE availability
-			SET capacity = $2, updated_at = $3
-			WHERE id = $1
+	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		var existingID string
+		var reserved int
+		err := tx.QueryRow(ctx, checkQuery, availability.RestaurantID, formattedDate, availability.TimeSlot).Scan(&existingID, &reserved)
+
+		if err == nil {
+			availability.ID = existingID
+			availability.Reserved = reserved
+
+			if availability.Capacity < reserved && !force {
+				return ErrCapacityBelowReserved
+			}
+
+			const updateQuery = `
+				UPDATE availability
+				SET capacity = $2, updated_at = $3
+				WHERE id = $1
+			`
+
+			_, err = tx.Exec(ctx, updateQuery, existingID, availability.Capacity, availability.UpdatedAt)
+			if err != nil {
+				log.Error(ctx, common.ErrUpdateAvailability,
+					zap.String("id", existingID),
+					zap.Int("capacity", availability.Capacity),
+					zap.Error(err))
+				return err
+			}
+
+			return nil
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error(ctx, common.ErrCheckAvailabilityExistence,
+				zap.String("restaurantID", availability.RestaurantID),
+				zap.String("date", formattedDate),
+				zap.String("timeSlot", availability.TimeSlot),
+				zap.Error(err))
+			return err
+		}
+
+		const insertQuery = `
+			INSERT INTO availability (id, restaurant_id, date, time_slot, capacity, reserved, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
 		`
 
-		_, err = executor.Exec(ctx, updateQuery, existingID, availability.Capacity, availability.UpdatedAt)
+		_, err = tx.Exec(ctx, insertQuery,
+			availability.ID,
+			availability.RestaurantID,
+			formattedDate,
+			availability.TimeSlot,
+			availability.Capacity,
+			0,
+			availability.UpdatedAt,
+		)
 		if err != nil {
-			log.Error(ctx, common.ErrUpdateAvailability,
-				zap.String("id", existingID),
-				zap.Int("capacity", availability.Capacity),
+			log.Error(ctx, common.ErrInsertAvailability,
+				zap.String("restaurantID", availability.RestaurantID),
+				zap.String("date", formattedDate),
+				zap.String("timeSlot", availability.TimeSlot),
 				zap.Error(err))
 			return err
 		}
 
-		availability.ID = existingID
-		availability.Reserved = reserved
+		availability.Reserved = 0
+		return nil
+	})
+}
 
+func (r *AvailabilityRepository) CreateBatch(ctx context.Context, slots []*domain.Availability) error {
+	if len(slots) == 0 {
 		return nil
 	}
 
-	if !errors.Is(err, pgx.ErrNoRows) {
-		log.Error(ctx, common.ErrCheckAvailabilityExistence,
-			zap.String("restaurantID", availability.RestaurantID),
-			zap.String("date", formattedDate),
-			zap.String("timeSlot", availability.TimeSlot),
-			zap.Error(err))
-		return err
-	}
+	log, _ := logger.FromContext(ctx)
 
-	const insertQuery = `
-		INSERT INTO availability (id, restaurant_id, date, time_slot, capacity, reserved, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`
+	columns := []string{"id", "restaurant_id", "date", "time_slot", "capacity", "reserved", "updated_at"}
 
-	_, err = executor.Exec(ctx, insertQuery,
-		availability.ID,
-		availability.RestaurantID,
-		formattedDate,
-		availability.TimeSlot,
-		availability.Capacity,
-		0,
-		availability.UpdatedAt,
-	)
-	if err != nil {
+	now := time.Now()
+	rows := make([][]any, 0, len(slots))
+	for _, slot := range slots {
+		if slot.ID == "" {
+			slot.ID = r.ids.NewID()
+		}
+		slot.Reserved = 0
+		slot.UpdatedAt = now
+
+		rows = append(rows, []any{slot.ID, slot.RestaurantID, slot.Date.Format("2006-01-02"), slot.TimeSlot, slot.Capacity, slot.Reserved, slot.UpdatedAt})
+	}
+
+	if err := r.copyRows(ctx, "availability", columns, rows); err != nil {
 		log.Error(ctx, common.ErrInsertAvailability,
-			zap.String("restaurantID", availability.RestaurantID),
-			zap.String("date", formattedDate),
-			zap.String("timeSlot", availability.TimeSlot),
+			zap.Int("count", len(slots)),
 			zap.Error(err))
 		return err
 	}
 
-	availability.Reserved = 0
 	return nil
 }
 
@@ -201,11 +258,18 @@ func (r *AvailabilityRepository) UpdateReservedSeats(ctx context.Context, availa
 		}
 
 		newReserved := reserved + delta
-		if newReserved > capacity {
-			return fmt.Errorf("%s: %w", common.ErrInsufficientCapacity, err)
-		}
 		if newReserved < 0 {
-			newReserved = 0
+			return &domain.ReservedSeatsError{
+				AvailabilityID: availabilityID, Capacity: capacity, Reserved: reserved, Delta: delta,
+				Err: domain.ErrReservedSeatsNegative,
+			}
+		}
+		// A forced capacity may already be below the reserved seats, so only growth is limited.
+		if delta > 0 && newReserved > capacity {
+			return &domain.ReservedSeatsError{
+				AvailabilityID: availabilityID, Capacity: capacity, Reserved: reserved, Delta: delta,
+				Err: domain.ErrReservedSeatsOverflow,
+			}
 		}
 
 		const updateQuery = `
@@ -227,6 +291,185 @@ func (r *AvailabilityRepository) UpdateReservedSeats(ctx context.Context, availa
 	})
 }
 
+// ReconcileReservedSeats recomputes reserved seats of the slots dated between from and to from
+// their pending and confirmed bookings not seated at a table and the seats held by booking
+// drafts, stores the recomputed values and returns the slots that drifted. A zero to leaves the
+// range open.
+func (r *AvailabilityRepository) ReconcileReservedSeats(ctx context.Context, from, to time.Time) ([]domain.ReservedSeatsDrift, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		WITH actual AS (
+			SELECT a.id, a.reserved AS recorded,
+				(COALESCE(SUM(b.guests_count), 0) + (
+					SELECT COALESCE(SUM(d.guests_count), 0)
+					FROM booking_drafts d
+					WHERE d.availability_id = a.id AND d.status = 'held'
+				))::INT AS actual
+			FROM availability a
+			LEFT JOIN bookings b ON b.restaurant_id = a.restaurant_id AND b.date = a.date
+				AND b.time = a.time_slot AND b.table_id IS NULL AND b.status IN ('pending', 'confirmed')
+			WHERE a.date >= $1 AND ($2::DATE IS NULL OR a.date <= $2::DATE)
+			GROUP BY a.id, a.reserved
+		)
+		UPDATE availability
+		SET reserved = actual.actual, updated_at = NOW()
+		FROM actual
+		WHERE availability.id = actual.id AND availability.reserved <> actual.actual
+		RETURNING availability.id, availability.restaurant_id, availability.date, availability.time_slot,
+			actual.recorded, actual.actual
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var until *string
+	if !to.IsZero() {
+		date := to.Format("2006-01-02")
+		until = &date
+	}
+
+	rows, err := executor.Query(ctx, query, from.Format("2006-01-02"), until)
+	if err != nil {
+		log.Error(ctx, common.ErrReconcileReservedSeats, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrReconcileReservedSeats, err)
+	}
+	defer rows.Close()
+
+	drifts := make([]domain.ReservedSeatsDrift, 0)
+	for rows.Next() {
+		var d domain.ReservedSeatsDrift
+		if err := rows.Scan(&d.AvailabilityID, &d.RestaurantID, &d.Date, &d.TimeSlot, &d.Recorded, &d.Actual); err != nil {
+			log.Error(ctx, common.ErrScanAvailability, zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", common.ErrScanAvailability, err)
+		}
+		drifts = append(drifts, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Error(ctx, common.ErrIterateAvailability, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrIterateAvailability, err)
+	}
+
+	return drifts, nil
+}
+
+// ListChanges returns up to limit slots of the restaurant changed after the cursor, oldest change
+// first. The database moves updated_at forward on every change, so a slot changed again comes
+// after the cursor.
+func (r *AvailabilityRepository) ListChanges(ctx context.Context, restaurantID domain.RestaurantID, after domain.SyncCursor, limit int) ([]*domain.Availability, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, restaurant_id, date, time_slot, capacity, reserved, updated_at
+		FROM availability
+		WHERE restaurant_id = $1
+			AND (updated_at > $2 OR ($3 <> '' AND updated_at = $2 AND id::text > $3))
+		ORDER BY updated_at, id
+		LIMIT $4
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID.String(), after.ChangedAt, after.ID, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListAvailabilityChanges, zap.String("restaurantID", restaurantID.String()), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListAvailabilityChanges, err)
+	}
+	defer rows.Close()
+
+	availabilities := make([]*domain.Availability, 0, limit)
+	for rows.Next() {
+		var a domain.Availability
+		if err := rows.Scan(&a.ID, &a.RestaurantID, &a.Date, &a.TimeSlot, &a.Capacity, &a.Reserved, &a.UpdatedAt); err != nil {
+			log.Error(ctx, common.ErrScanAvailability, zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", common.ErrScanAvailability, err)
+		}
+		availabilities = append(availabilities, &a)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Error(ctx, common.ErrIterateAvailability, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrIterateAvailability, err)
+	}
+
+	return availabilities, nil
+}
+
+func (r *AvailabilityRepository) GetByID(ctx context.Context, id string) (*domain.Availability, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, restaurant_id, date, time_slot, capacity, reserved, updated_at
+		FROM availability
+		WHERE id = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var a domain.Availability
+	err = executor.QueryRow(ctx, query, id).Scan(&a.ID, &a.RestaurantID, &a.Date, &a.TimeSlot, &a.Capacity, &a.Reserved, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAvailabilityNotFound
+		}
+		log.Error(ctx, common.ErrExecuteAvailabilityQuery, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrExecuteAvailabilityQuery, err)
+	}
+
+	return &a, nil
+}
+
+// Delete deletes the slot unless seats of it are reserved, which fails with ErrAvailabilityReserved.
+// The reserved seats are checked under the lock of the row, so that a booking made meanwhile is
+// never left without its slot.
+func (r *AvailabilityRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const getQuery = `
+			SELECT reserved FROM availability
+			WHERE id = $1 FOR UPDATE
+		`
+
+		var reserved int
+		if err := tx.QueryRow(ctx, getQuery, id).Scan(&reserved); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAvailabilityNotFound
+			}
+			log.Error(ctx, common.ErrGetCurrentAvailability, zap.String("id", id), zap.Error(err))
+			return err
+		}
+		if reserved > 0 {
+			return ErrAvailabilityReserved
+		}
+
+		const deleteQuery = `
+			DELETE FROM availability WHERE id = $1
+		`
+
+		if _, err := tx.Exec(ctx, deleteQuery, id); err != nil {
+			log.Error(ctx, common.ErrDeleteAvailability, zap.String("id", id), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
 func (r *AvailabilityRepository) checkRestaurantExists(ctx context.Context, id string, executor DBExecutor) (bool, error) {
 	const query = `
 		SELECT EXISTS(SELECT 1 FROM restaurants WHERE id = $1)
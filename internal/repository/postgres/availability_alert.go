@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const availabilityAlertColumns = `
+	id, restaurant_id, user_id, date, from_time, to_time, party_size, status, time_slot, created_at,
+	notified_at
+`
+
+type AvailabilityAlertRepository struct {
+	*Repository
+}
+
+func NewAvailabilityAlertRepository(repository *Repository) *AvailabilityAlertRepository {
+	return &AvailabilityAlertRepository{
+		Repository: repository,
+	}
+}
+
+func (r *AvailabilityAlertRepository) Create(ctx context.Context, alert *domain.AvailabilityAlert) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO availability_alerts (id, restaurant_id, user_id, date, from_time, to_time, party_size, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id, restaurant_id, date) WHERE status = 'active' DO NOTHING
+	`
+
+	if alert.ID == "" {
+		alert.ID = r.ids.NewID()
+	}
+	alert.Status = domain.AvailabilityAlertActive
+	alert.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		alert.ID,
+		alert.RestaurantID,
+		alert.UserID,
+		alert.Date,
+		alert.FromTime,
+		alert.ToTime,
+		alert.PartySize,
+		alert.Status,
+		alert.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateAvailabilityAlert,
+			zap.String("restaurantID", alert.RestaurantID),
+			zap.String("userID", alert.UserID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateAvailabilityAlert, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrAvailabilityAlertExists)
+	}
+
+	return nil
+}
+
+func (r *AvailabilityAlertRepository) ListActive(ctx context.Context, from time.Time) ([]*domain.AvailabilityAlert, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + availabilityAlertColumns + `
+		FROM availability_alerts
+		WHERE status = $1 AND date >= $2
+		ORDER BY restaurant_id, date, created_at
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, domain.AvailabilityAlertActive, from)
+	if err != nil {
+		log.Error(ctx, common.ErrListAvailabilityAlerts, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListAvailabilityAlerts, err)
+	}
+	defer rows.Close()
+
+	alerts := make([]*domain.AvailabilityAlert, 0)
+	for rows.Next() {
+		var alert domain.AvailabilityAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.RestaurantID,
+			&alert.UserID,
+			&alert.Date,
+			&alert.FromTime,
+			&alert.ToTime,
+			&alert.PartySize,
+			&alert.Status,
+			&alert.TimeSlot,
+			&alert.CreatedAt,
+			&alert.NotifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListAvailabilityAlerts, err)
+		}
+		alerts = append(alerts, &alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListAvailabilityAlerts, err)
+	}
+
+	return alerts, nil
+}
+
+func (r *AvailabilityAlertRepository) MarkNotified(ctx context.Context, id, timeSlot string, notifiedAt time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE availability_alerts
+		SET status = $2, time_slot = $3, notified_at = $4
+		WHERE id::text = $1 AND status = $5
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, domain.AvailabilityAlertNotified, timeSlot, notifiedAt, domain.AvailabilityAlertActive)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateAvailabilityAlert, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateAvailabilityAlert, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrAvailabilityAlertNotFound)
+	}
+
+	return nil
+}
+
+func (r *AvailabilityAlertRepository) ExpireBefore(ctx context.Context, date time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE availability_alerts
+		SET status = $1
+		WHERE status = $2 AND date < $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, domain.AvailabilityAlertExpired, domain.AvailabilityAlertActive, date)
+	if err != nil {
+		log.Error(ctx, common.ErrExpireAvailabilityAlerts, zap.Time("date", date), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrExpireAvailabilityAlerts, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,419 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const subscriptionColumns = `
+	id, restaurant_id, plan, monthly_price, currency, started_at, cancelled_at, created_at
+`
+
+// invoiceColumns are read from invoices i joined with restaurants r.
+const invoiceColumns = `
+	i.id, i.number, i.subscription_id, i.restaurant_id, r.name, i.plan, i.period_start, i.period_end,
+	i.amount, i.currency, i.status, i.issued_at, i.due_at, i.paid_at, i.payment_id, i.updated_at
+`
+
+type BillingRepository struct {
+	*Repository
+}
+
+func NewBillingRepository(repository *Repository) *BillingRepository {
+	return &BillingRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BillingRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO subscriptions (id, restaurant_id, plan, monthly_price, currency, started_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if subscription.ID == "" {
+		subscription.ID = r.ids.NewID()
+	}
+	subscription.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		subscription.ID,
+		subscription.RestaurantID,
+		subscription.Plan,
+		subscription.MonthlyPrice,
+		subscription.Currency,
+		subscription.StartedAt,
+		subscription.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateSubscription, zap.String("restaurantID", subscription.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateSubscription, err)
+	}
+
+	return nil
+}
+
+func (r *BillingRepository) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id::text = $1`
+	return r.getSubscription(ctx, query, id)
+}
+
+// GetActiveSubscription returns the subscription of the restaurant that is not cancelled.
+func (r *BillingRepository) GetActiveSubscription(ctx context.Context, restaurantID string) (*domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE restaurant_id::text = $1 AND cancelled_at IS NULL`
+	return r.getSubscription(ctx, query, restaurantID)
+}
+
+func (r *BillingRepository) getSubscription(ctx context.Context, query, arg string) (*domain.Subscription, error) {
+	log, _ := logger.FromContext(ctx)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	subscription, err := scanSubscription(executor.QueryRow(ctx, query, arg))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New(common.ErrSubscriptionNotFound)
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrGetSubscription, zap.String("id", arg), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetSubscription, err)
+	}
+
+	return subscription, nil
+}
+
+// CancelSubscription ends the subscription at the time, unless it already ended.
+func (r *BillingRepository) CancelSubscription(ctx context.Context, id string, at time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE subscriptions SET cancelled_at = $2 WHERE id::text = $1 AND cancelled_at IS NULL
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, at)
+	if err != nil {
+		log.Error(ctx, common.ErrCancelSubscription, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCancelSubscription, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrSubscriptionNotFound)
+	}
+
+	return nil
+}
+
+// ListSubscriptions returns the subscriptions of the restaurant, or of every restaurant when it is
+// empty, newest first.
+func (r *BillingRepository) ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE $1::text = '' OR restaurant_id::text = $1
+		ORDER BY started_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListSubscriptions, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSubscriptions, err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*domain.Subscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSubscriptions, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSubscriptions, err)
+	}
+
+	return subscriptions, nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.Subscription, error) {
+	var subscription domain.Subscription
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.RestaurantID,
+		&subscription.Plan,
+		&subscription.MonthlyPrice,
+		&subscription.Currency,
+		&subscription.StartedAt,
+		&subscription.CancelledAt,
+		&subscription.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// CreateInvoices bills every subscription active in the month from periodStart up to periodEnd
+// that has no invoice for it yet, for the share of the month's days it was active, and returns the
+// new invoices. Subscriptions that come to nothing are not billed.
+func (r *BillingRepository) CreateInvoices(ctx context.Context, periodStart, periodEnd, issuedAt, dueAt time.Time) ([]*domain.Invoice, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		WITH created AS (
+			INSERT INTO invoices (
+				number, subscription_id, restaurant_id, plan, period_start, period_end, amount, currency,
+				issued_at, due_at, updated_at
+			)
+			SELECT 'INV-' || to_char($1::date, 'YYYYMM') || '-' || lpad(nextval('invoice_number_seq')::text, 6, '0'),
+				   s.id, s.restaurant_id, s.plan, $1::date, $2::date, s.amount, s.currency, $3, $4, $3
+			FROM (
+				SELECT id, restaurant_id, plan, currency,
+					   ROUND(monthly_price * (LEAST(COALESCE(cancelled_at::date, $2::date), $2::date)
+						   - GREATEST(started_at::date, $1::date))::numeric / ($2::date - $1::date))::bigint AS amount
+				FROM subscriptions
+				WHERE started_at::date < $2::date AND (cancelled_at IS NULL OR cancelled_at::date > $1::date)
+			) s
+			WHERE s.amount > 0
+			  AND NOT EXISTS (SELECT 1 FROM invoices WHERE subscription_id = s.id AND period_start = $1::date)
+			ON CONFLICT (subscription_id, period_start) DO NOTHING
+			RETURNING *
+		)
+		SELECT ` + invoiceColumns + `
+		FROM created i
+		JOIN restaurants r ON r.id = i.restaurant_id
+		ORDER BY i.number
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query,
+		periodStart.Format("2006-01-02"),
+		periodEnd.Format("2006-01-02"),
+		issuedAt,
+		dueAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateInvoices, zap.Time("periodStart", periodStart), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrCreateInvoices, err)
+	}
+
+	invoices, err := collectInvoices(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrCreateInvoices, err)
+	}
+
+	return invoices, nil
+}
+
+func (r *BillingRepository) GetInvoice(ctx context.Context, id string) (*domain.Invoice, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices i
+		JOIN restaurants r ON r.id = i.restaurant_id
+		WHERE i.id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrGetInvoice, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetInvoice, err)
+	}
+
+	invoices, err := collectInvoices(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetInvoice, err)
+	}
+
+	if len(invoices) == 0 {
+		return nil, errors.New(common.ErrInvoiceNotFound)
+	}
+
+	return invoices[0], nil
+}
+
+// ListInvoices returns the invoices matching the filter, newest first.
+func (r *BillingRepository) ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices i
+		JOIN restaurants r ON r.id = i.restaurant_id
+		WHERE ($1::text = '' OR i.restaurant_id::text = $1) AND ($2::text = '' OR i.status = $2)
+		ORDER BY i.issued_at DESC, i.number DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, filter.RestaurantID, string(filter.Status), limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListInvoices, zap.String("restaurantID", filter.RestaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListInvoices, err)
+	}
+
+	invoices, err := collectInvoices(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListInvoices, err)
+	}
+
+	return invoices, nil
+}
+
+// UpdateInvoicePayment stores the status, payment time and payment reference of the invoice.
+func (r *BillingRepository) UpdateInvoicePayment(ctx context.Context, invoice *domain.Invoice) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE invoices SET status = $2, paid_at = $3, payment_id = $4, updated_at = $5 WHERE id::text = $1
+	`
+
+	invoice.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, invoice.ID, invoice.Status, invoice.PaidAt, invoice.PaymentID, invoice.UpdatedAt)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateInvoice, zap.String("id", invoice.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateInvoice, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrInvoiceNotFound)
+	}
+
+	return nil
+}
+
+// RecordPaymentEvent keeps the event and reports false when one with its ID was already kept.
+func (r *BillingRepository) RecordPaymentEvent(ctx context.Context, event *domain.PaymentEvent) (bool, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO payment_events (id, type, invoice_id, payment_id, occurred_at, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	event.ReceivedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return false, err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		event.ID,
+		event.Type,
+		event.InvoiceID,
+		event.PaymentID,
+		event.OccurredAt,
+		event.ReceivedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrRecordPaymentEvent, zap.String("eventID", event.ID), zap.Error(err))
+		return false, fmt.Errorf("%s: %w", common.ErrRecordPaymentEvent, err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+func collectInvoices(rows pgx.Rows) ([]*domain.Invoice, error) {
+	defer rows.Close()
+
+	invoices := make([]*domain.Invoice, 0)
+	for rows.Next() {
+		var invoice domain.Invoice
+		err := rows.Scan(
+			&invoice.ID,
+			&invoice.Number,
+			&invoice.SubscriptionID,
+			&invoice.RestaurantID,
+			&invoice.RestaurantName,
+			&invoice.Plan,
+			&invoice.PeriodStart,
+			&invoice.PeriodEnd,
+			&invoice.Amount,
+			&invoice.Currency,
+			&invoice.Status,
+			&invoice.IssuedAt,
+			&invoice.DueAt,
+			&invoice.PaidAt,
+			&invoice.PaymentID,
+			&invoice.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, rows.Err()
+}
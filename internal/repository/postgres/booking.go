@@ -4,17 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 )
 
+// pgExclusionViolation is raised when an active booking would hold its table during the seating
+// of another one.
+const pgExclusionViolation = "23P01"
+
 type BookingRepository struct {
 	*Repository
 }
@@ -25,17 +31,21 @@ func NewBookingRepository(repository *Repository) *BookingRepository {
 	}
 }
 
-func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Booking, error) {
+func (r *BookingRepository) GetByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
 	logger, err := logger.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingData, err)
 	}
 
 	const query = `
-		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
-			   created_at, updated_at, confirmed_at, rejected_at, completed_at
-		FROM bookings
-		WHERE id = $1
+		SELECT b.id, b.restaurant_id, b.user_id, b.date, b.time, b.duration, b.guests_count, b.status, b.comment,
+			   b.created_at, b.updated_at, b.confirmed_at, b.rejected_at, b.completed_at, b.is_test, COALESCE(b.occasion, ''), b.age_attested,
+			   COALESCE(b.table_id::text, ''), b.table_requested, b.seating_zone, b.needs_accessible,
+			   a.booking_id IS NOT NULL, COALESCE(a.utm_source, ''), COALESCE(a.utm_medium, ''), COALESCE(a.utm_campaign, ''),
+			   COALESCE(a.utm_term, ''), COALESCE(a.utm_content, ''), COALESCE(a.referral_code, '')
+		FROM bookings b
+		LEFT JOIN booking_attributions a ON a.booking_id = b.id
+		WHERE b.id = $1
 	`
 
 	executor, release, err := r.GetExecutor(ctx)
@@ -47,8 +57,10 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Boo
 
 	var booking domain.Booking
 	var confirmedAt, rejectedAt, completedAt *time.Time
+	var attributed bool
+	var attribution domain.BookingAttribution
 
-	err = executor.QueryRow(ctx, query, id).Scan(
+	err = executor.QueryRow(ctx, query, id.String()).Scan(
 		&booking.ID,
 		&booking.RestaurantID,
 		&booking.UserID,
@@ -63,6 +75,20 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Boo
 		&confirmedAt,
 		&rejectedAt,
 		&completedAt,
+		&booking.IsTest,
+		&booking.Occasion,
+		&booking.AgeAttested,
+		&booking.TableID,
+		&booking.TableRequested,
+		&booking.SeatingZone,
+		&booking.NeedsAccessible,
+		&attributed,
+		&attribution.Source,
+		&attribution.Medium,
+		&attribution.Campaign,
+		&attribution.Term,
+		&attribution.Content,
+		&attribution.ReferralCode,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -81,6 +107,14 @@ func (r *BookingRepository) GetByID(ctx context.Context, id string) (*domain.Boo
 	if completedAt != nil {
 		booking.CompletedAt = completedAt
 	}
+	if attributed {
+		booking.Attribution = &attribution
+	}
+
+	if err := tenant.GuardOwner(ctx, "booking", booking.ID, booking.UserID, booking.RestaurantID); err != nil {
+		logger.Warn(ctx, common.ErrTenantIsolation, zap.Error(err))
+		return nil, err
+	}
 
 	alternatives, err := r.getAlternatives(ctx, booking.ID, executor)
 	if err != nil {
@@ -113,6 +147,13 @@ func (r *BookingRepository) scanBooking(rows pgx.Rows) (*domain.Booking, error)
 		&confirmedAt,
 		&rejectedAt,
 		&completedAt,
+		&booking.IsTest,
+		&booking.Occasion,
+		&booking.AgeAttested,
+		&booking.TableID,
+		&booking.TableRequested,
+		&booking.SeatingZone,
+		&booking.NeedsAccessible,
 	)
 	if err != nil {
 		return nil, err
@@ -155,6 +196,11 @@ func (r *BookingRepository) getBookingsByQuery(ctx context.Context, query string
 			log.Error(ctx, common.ErrGetBookingData, zap.Error(err))
 			return nil, err
 		}
+		// Every row must belong to the principal, otherwise the query is missing its ownership filter.
+		if err := tenant.GuardOwner(ctx, "booking", booking.ID, booking.UserID, booking.RestaurantID); err != nil {
+			log.Warn(ctx, common.ErrTenantIsolation, zap.Error(err))
+			return nil, err
+		}
 		bookings = append(bookings, booking)
 	}
 
@@ -166,39 +212,143 @@ func (r *BookingRepository) getBookingsByQuery(ctx context.Context, query string
 	return bookings, nil
 }
 
-func (r *BookingRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.Booking, error) {
+func (r *BookingRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.Booking, error) {
 	const query = `
 		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
-			   created_at, updated_at, confirmed_at, rejected_at, completed_at
+			   created_at, updated_at, confirmed_at, rejected_at, completed_at, is_test, COALESCE(occasion, ''), age_attested,
+			   COALESCE(table_id::text, ''), table_requested, seating_zone, needs_accessible
 		FROM bookings
 		WHERE restaurant_id = $1
-		ORDER BY date DESC, time DESC
+		ORDER BY date DESC, time DESC, id DESC
 	`
 
 	log, _ := logger.FromContext(ctx)
-	bookings, err := r.getBookingsByQuery(ctx, query, restaurantID)
+	bookings, err := r.getBookingsByQuery(ctx, query, restaurantID.String())
 	if err != nil {
 		log.Error(ctx, common.ErrGetRestaurantBookings,
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 	}
 	return bookings, err
 }
 
-func (r *BookingRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Booking, error) {
+// GetByRestaurantAndDate returns the bookings of the restaurant on the date in every status,
+// ordered by time and creation.
+func (r *BookingRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Booking, error) {
 	const query = `
 		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
-			   created_at, updated_at, confirmed_at, rejected_at, completed_at
+			   created_at, updated_at, confirmed_at, rejected_at, completed_at, is_test, COALESCE(occasion, ''), age_attested,
+			   COALESCE(table_id::text, ''), table_requested, seating_zone, needs_accessible
 		FROM bookings
-		WHERE user_id = $1
-		ORDER BY date DESC, time DESC
+		WHERE restaurant_id = $1 AND date = $2
+		ORDER BY time, created_at, id
 	`
 
 	log, _ := logger.FromContext(ctx)
-	bookings, err := r.getBookingsByQuery(ctx, query, userID)
+	bookings, err := r.getBookingsByQuery(ctx, query, restaurantID.String(), date.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrGetRestaurantBookings,
+			zap.String("restaurantID", restaurantID.String()),
+			zap.Time("date", date),
+			zap.Error(err))
+	}
+	return bookings, err
+}
+
+// ListByRestaurant compares the date, time and ID of the rows with the cursor as a row value, which
+// the index on the restaurant and all three reads backwards from the cursor on. The first page is
+// a statement of its own, as in ListByUser.
+func (r *BookingRepository) ListByRestaurant(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	after domain.BookingCursor,
+	limit int,
+) ([]*domain.Booking, error) {
+	query := `
+		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
+			   created_at, updated_at, confirmed_at, rejected_at, completed_at, is_test, COALESCE(occasion, ''), age_attested,
+			   COALESCE(table_id::text, ''), table_requested, seating_zone, needs_accessible
+		FROM bookings
+		WHERE restaurant_id = $1
+		  AND ($3::text = '' OR status = $3)
+		  AND ($4::text = '' OR occasion = $4)
+		  AND ($5::date IS NULL OR date = $5)`
+
+	var date *string
+	if filter.Date != nil {
+		value := filter.Date.Format("2006-01-02")
+		date = &value
+	}
+	args := []any{restaurantID.String(), limit, string(filter.Status), string(filter.Occasion), date}
+	query, args = pageAfterBooking(query, args, after)
+
+	log, _ := logger.FromContext(ctx)
+	bookings, err := r.getBookingsByQuery(ctx, query, args...)
+	if err != nil {
+		log.Error(ctx, common.ErrGetRestaurantBookings,
+			zap.String("restaurantID", restaurantID.String()),
+			zap.Error(err))
+	}
+	return bookings, err
+}
+
+// ListByUser reads the index on the user, date, time and ID backwards from the cursor on. The first
+// page is a statement of its own: a cursor condition that is switched off by a null argument keeps
+// a generic plan of the statement from using the index.
+func (r *BookingRepository) ListByUser(ctx context.Context, userID domain.UserID, after domain.BookingCursor, limit int) ([]*domain.Booking, error) {
+	query := `
+		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
+			   created_at, updated_at, confirmed_at, rejected_at, completed_at, is_test, COALESCE(occasion, ''), age_attested,
+			   COALESCE(table_id::text, ''), table_requested, seating_zone, needs_accessible
+		FROM bookings
+		WHERE user_id = $1`
+	query, args := pageAfterBooking(query, []any{userID.String(), limit}, after)
+
+	log, _ := logger.FromContext(ctx)
+	bookings, err := r.getBookingsByQuery(ctx, query, args...)
 	if err != nil {
 		log.Error(ctx, common.ErrGetUserBookings,
-			zap.String("userID", userID),
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+	return bookings, err
+}
+
+// pageAfterBooking completes the query of a list of bookings, whose limit is $2, with the order of
+// the list and, past the first page, the rows before the cursor.
+func pageAfterBooking(query string, args []any, after domain.BookingCursor) (string, []any) {
+	if !after.IsZero() {
+		n := len(args)
+		query += fmt.Sprintf(`
+		  AND (date, time, id) < ($%d::date, $%d, $%d::uuid)`, n+1, n+2, n+3)
+		args = append(args, after.Date.Format("2006-01-02"), after.Time, after.ID)
+	}
+	query += `
+		ORDER BY date DESC, time DESC, id DESC
+		LIMIT $2
+	`
+	return query, args
+}
+
+// GetActiveBetween returns the pending and confirmed bookings of every restaurant from one date to
+// another, both included, ordered by restaurant, date and time.
+func (r *BookingRepository) GetActiveBetween(ctx context.Context, from, to time.Time) ([]*domain.Booking, error) {
+	const query = `
+		SELECT id, restaurant_id, user_id, date, time, duration, guests_count, status, comment,
+			   created_at, updated_at, confirmed_at, rejected_at, completed_at, is_test, COALESCE(occasion, ''), age_attested,
+			   COALESCE(table_id::text, ''), table_requested, seating_zone, needs_accessible
+		FROM bookings
+		WHERE date BETWEEN $1 AND $2 AND status IN ('pending', 'confirmed')
+		ORDER BY restaurant_id, date, time, id
+	`
+
+	log, _ := logger.FromContext(ctx)
+	bookings, err := r.getBookingsByQuery(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrGetActiveBookings,
+			zap.Time("from", from),
+			zap.Time("to", to),
 			zap.Error(err))
 	}
 	return bookings, err
@@ -208,12 +358,15 @@ func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking)
 	log, _ := logger.FromContext(ctx)
 
 	if booking.ID == "" {
-		booking.ID = uuid.New().String()
+		booking.ID = r.ids.NewID()
 	}
 
 	const query = `
-		INSERT INTO bookings (id, restaurant_id, user_id, date, time, duration, guests_count, status, comment, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO bookings (id, restaurant_id, user_id, date, time, duration, guests_count, status, comment, created_at, updated_at, is_test, occasion, age_attested, table_id,
+							  table_requested, seating_zone, needs_accessible)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+				$12 OR (SELECT is_test FROM restaurants WHERE id = $2), NULLIF($13, ''), $14, NULLIF($15, '')::uuid, $16, $17, $18)
+		RETURNING is_test
 	`
 
 	executor, release, err := r.GetExecutor(ctx)
@@ -247,20 +400,57 @@ func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking)
 
 	formattedDate := booking.Date.Format("2006-01-02")
 
-	_, err = executor.Exec(ctx, query,
-		booking.ID,
-		booking.RestaurantID,
-		booking.UserID,
-		formattedDate,
-		booking.Time,
-		booking.Duration,
-		booking.GuestsCount,
-		booking.Status,
-		booking.Comment,
-		booking.CreatedAt,
-		booking.UpdatedAt,
-	)
+	// A booking of a test restaurant is always a test booking.
+	err = r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query,
+			booking.ID,
+			booking.RestaurantID,
+			booking.UserID,
+			formattedDate,
+			booking.Time,
+			booking.Duration,
+			booking.GuestsCount,
+			booking.Status,
+			booking.Comment,
+			booking.CreatedAt,
+			booking.UpdatedAt,
+			booking.IsTest,
+			booking.Occasion,
+			booking.AgeAttested,
+			booking.TableID,
+			booking.TableRequested,
+			booking.SeatingZone,
+			booking.NeedsAccessible,
+		).Scan(&booking.IsTest)
+		if tableTaken(err) {
+			// Another booking took the table for a part of the seating first.
+			return errors.New(common.ErrTableUnavailable)
+		}
+		if err != nil || booking.Attribution == nil {
+			return err
+		}
+
+		const attributionQuery = `
+			INSERT INTO booking_attributions (booking_id, utm_source, utm_medium, utm_campaign, utm_term, utm_content, referral_code, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+		attribution := booking.Attribution
+		_, err = tx.Exec(ctx, attributionQuery,
+			booking.ID,
+			attribution.Source,
+			attribution.Medium,
+			attribution.Campaign,
+			attribution.Term,
+			attribution.Content,
+			attribution.ReferralCode,
+			booking.CreatedAt,
+		)
+		return err
+	})
 	if err != nil {
+		if err.Error() == common.ErrTableUnavailable {
+			return err
+		}
 		log.Error(ctx, common.ErrCreateBooking,
 			zap.String("userID", booking.UserID),
 			zap.String("restaurantID", booking.RestaurantID),
@@ -272,7 +462,7 @@ func (r *BookingRepository) Create(ctx context.Context, booking *domain.Booking)
 	return nil
 }
 
-func (r *BookingRepository) UpdateStatus(ctx context.Context, id string, status domain.BookingStatus) error {
+func (r *BookingRepository) UpdateStatus(ctx context.Context, id domain.BookingID, status domain.BookingStatus) error {
 	logger, err := logger.FromContext(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
@@ -284,23 +474,36 @@ func (r *BookingRepository) UpdateStatus(ctx context.Context, id string, status
 
 	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
 		const getQuery = `
-			SELECT status FROM bookings
+			SELECT status, date FROM bookings
 			WHERE id = $1 FOR UPDATE
 		`
-		var currentStatus string
-		err := tx.QueryRow(ctx, getQuery, id).Scan(&currentStatus)
+		var currentStatus domain.BookingStatus
+		var date time.Time
+		err := tx.QueryRow(ctx, getQuery, id.String()).Scan(&currentStatus, &date)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return fmt.Errorf("%s: %w", common.ErrBookingNotFound, err)
 			}
 			logger.Error(ctx, common.ErrGetCurrentBookingStatus,
-				zap.String("bookingID", id),
+				zap.String("bookingID", id.String()),
 				zap.Error(err))
 			return fmt.Errorf("%s: %w", common.ErrGetCurrentBookingStatus, err)
 		}
 
+		// A cancelled or rejected booking gives its pre-ordered units back to the day.
+		if (currentStatus == domain.BookingStatusPending || currentStatus == domain.BookingStatusConfirmed) &&
+			(status == domain.BookingStatusCancelled || status == domain.BookingStatusRejected) {
+			if err := releasePreOrderItems(ctx, tx, id.String(), date); err != nil {
+				logger.Error(ctx, common.ErrUpdateBookingStatus,
+					zap.String("bookingID", id.String()),
+					zap.String("newStatus", string(status)),
+					zap.Error(err))
+				return fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
+			}
+		}
+
 		query := "UPDATE bookings SET status = $2, updated_at = $3"
-		args := []interface{}{id, status, time.Now()}
+		args := []interface{}{id.String(), status, time.Now()}
 
 		switch status {
 		case domain.BookingStatusPending:
@@ -323,7 +526,7 @@ func (r *BookingRepository) UpdateStatus(ctx context.Context, id string, status
 		commandTag, err := tx.Exec(ctx, query, args...)
 		if err != nil {
 			logger.Error(ctx, common.ErrUpdateBookingStatus,
-				zap.String("bookingID", id),
+				zap.String("bookingID", id.String()),
 				zap.String("newStatus", string(status)),
 				zap.Error(err))
 			return fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
@@ -337,11 +540,95 @@ func (r *BookingRepository) UpdateStatus(ctx context.Context, id string, status
 	})
 }
 
+// ChangeStatus takes the booking from one of the from statuses to the status under the lock of its
+// row, stamping the change at the time. Of two concurrent changes only the first one finds the
+// booking in a from status; the other one changes nothing and reports false.
+func (r *BookingRepository) ChangeStatus(
+	ctx context.Context,
+	id domain.BookingID,
+	from []domain.BookingStatus,
+	status domain.BookingStatus,
+	at time.Time,
+) (bool, error) {
+	logger, err := logger.FromContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
+	}
+
+	if !isValidStatus(status) {
+		return false, fmt.Errorf("%s: %w", common.ErrInvalidBookingStatus, errors.New("неизвестный статус бронирования"))
+	}
+
+	statuses := make([]string, 0, len(from))
+	for _, current := range from {
+		statuses = append(statuses, string(current))
+	}
+
+	var changed bool
+	err = r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const getQuery = `
+			SELECT status, date FROM bookings
+			WHERE id = $1 FOR UPDATE
+		`
+		var currentStatus domain.BookingStatus
+		var date time.Time
+		err := tx.QueryRow(ctx, getQuery, id.String()).Scan(&currentStatus, &date)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%s: %w", common.ErrBookingNotFound, err)
+			}
+			logger.Error(ctx, common.ErrGetCurrentBookingStatus,
+				zap.String("bookingID", id.String()),
+				zap.Error(err))
+			return fmt.Errorf("%s: %w", common.ErrGetCurrentBookingStatus, err)
+		}
+		if !slices.Contains(from, currentStatus) {
+			return nil
+		}
+
+		// A cancelled or rejected booking gives its pre-ordered units back to the day.
+		if (currentStatus == domain.BookingStatusPending || currentStatus == domain.BookingStatusConfirmed) &&
+			(status == domain.BookingStatusCancelled || status == domain.BookingStatusRejected) {
+			if err := releasePreOrderItems(ctx, tx, id.String(), date); err != nil {
+				logger.Error(ctx, common.ErrUpdateBookingStatus,
+					zap.String("bookingID", id.String()),
+					zap.String("newStatus", string(status)),
+					zap.Error(err))
+				return fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
+			}
+		}
+
+		// Только бронирование в одном из исходных статусов меняет статус
+		const query = `
+			UPDATE bookings
+			SET status = $2, updated_at = $3,
+				confirmed_at = CASE WHEN $2 = 'confirmed' THEN $3 ELSE confirmed_at END,
+				rejected_at = CASE WHEN $2 = 'rejected' THEN $3 ELSE rejected_at END,
+				completed_at = CASE WHEN $2 = 'completed' THEN $3 ELSE completed_at END
+			WHERE id = $1 AND status = ANY($4::text[])
+		`
+		commandTag, err := tx.Exec(ctx, query, id.String(), status, at, statuses)
+		if err != nil {
+			logger.Error(ctx, common.ErrUpdateBookingStatus,
+				zap.String("bookingID", id.String()),
+				zap.String("newStatus", string(status)),
+				zap.Error(err))
+			return fmt.Errorf("%s: %w", common.ErrUpdateBookingStatus, err)
+		}
+		changed = commandTag.RowsAffected() == 1
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
 func (r *BookingRepository) AddAlternative(ctx context.Context, alternative *domain.BookingAlternative) error {
 	log, _ := logger.FromContext(ctx)
 
 	if alternative.ID == "" {
-		alternative.ID = uuid.New().String()
+		alternative.ID = r.ids.NewID()
 	}
 
 	const query = `
@@ -388,7 +675,7 @@ func (r *BookingRepository) AddAlternative(ctx context.Context, alternative *dom
 	return nil
 }
 
-func (r *BookingRepository) AcceptAlternative(ctx context.Context, alternativeID string) error {
+func (r *BookingRepository) AcceptAlternative(ctx context.Context, alternativeID, tableID string) error {
 	log, _ := logger.FromContext(ctx)
 
 	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
@@ -411,6 +698,10 @@ func (r *BookingRepository) AcceptAlternative(ctx context.Context, alternativeID
 			return err
 		}
 
+		if err := movePreOrderItems(ctx, tx, bookingID, date); err != nil {
+			return err
+		}
+
 		now := time.Now()
 		const updateAltQuery = `
 			UPDATE booking_alternatives
@@ -427,10 +718,14 @@ func (r *BookingRepository) AcceptAlternative(ctx context.Context, alternativeID
 
 		const updateBookingQuery = `
 			UPDATE bookings
-			SET date = $2, time = $3, updated_at = $4, status = $5, confirmed_at = $6
+			SET date = $2, time = $3, table_id = NULLIF($7, '')::uuid, updated_at = $4, status = $5, confirmed_at = $6,
+				table_requested = table_requested AND table_id IS NOT DISTINCT FROM NULLIF($7, '')::uuid
 			WHERE id = $1
 		`
-		_, err = tx.Exec(ctx, updateBookingQuery, bookingID, date, timeSlot, now, domain.BookingStatusConfirmed, now)
+		_, err = tx.Exec(ctx, updateBookingQuery, bookingID, date, timeSlot, now, domain.BookingStatusConfirmed, now, tableID)
+		if tableTaken(err) {
+			return errors.New(common.ErrTableUnavailable)
+		}
 		if err != nil {
 			log.Error(ctx, common.ErrUpdateBooking,
 				zap.String("bookingID", bookingID),
@@ -616,6 +911,13 @@ func (r *BookingRepository) checkBookingExists(ctx context.Context, id string, e
 	return exists, nil
 }
 
+// tableTaken reports whether err is the violation of the constraint that keeps two active bookings
+// from holding a table at once.
+func tableTaken(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgExclusionViolation
+}
+
 func isValidStatus(status domain.BookingStatus) bool {
 	validStatuses := []domain.BookingStatus{
 		domain.BookingStatusPending,
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const bookingDraftColumns = `
+	id, user_id, restaurant_id, guests_count, duration, comment, occasion, age_attested, status,
+	date, time, COALESCE(availability_id::text, ''), pre_order, COALESCE(booking_id::text, ''),
+	expires_at, created_at, updated_at
+`
+
+type BookingDraftRepository struct {
+	*Repository
+}
+
+func NewBookingDraftRepository(repository *Repository) *BookingDraftRepository {
+	return &BookingDraftRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BookingDraftRepository) Create(ctx context.Context, draft *domain.BookingDraft) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO booking_drafts (id, user_id, restaurant_id, guests_count, duration, comment, occasion,
+			age_attested, status, pre_order, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+	`
+
+	if draft.ID == "" {
+		draft.ID = r.ids.NewID()
+	}
+	if draft.PreOrder == nil {
+		draft.PreOrder = make([]domain.PreOrderItem, 0)
+	}
+	now := time.Now()
+	draft.CreatedAt = now
+	draft.UpdatedAt = now
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		draft.ID,
+		draft.UserID,
+		draft.RestaurantID,
+		draft.GuestsCount,
+		draft.Duration,
+		draft.Comment,
+		draft.Occasion,
+		draft.AgeAttested,
+		draft.Status,
+		draft.PreOrder,
+		draft.ExpiresAt,
+		now,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateBookingDraft,
+			zap.String("restaurantID", draft.RestaurantID),
+			zap.String("userID", draft.UserID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateBookingDraft, err)
+	}
+
+	return nil
+}
+
+func (r *BookingDraftRepository) GetByID(ctx context.Context, id string) (*domain.BookingDraft, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + bookingDraftColumns + `
+		FROM booking_drafts
+		WHERE id::text = $1
+		FOR UPDATE
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	draft, err := scanBookingDraft(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrBookingDraftNotFound)
+		}
+		log.Error(ctx, common.ErrGetBookingDraft, zap.String("draftID", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingDraft, err)
+	}
+
+	if err := tenant.GuardOwner(ctx, "booking_draft", draft.ID, draft.UserID, draft.RestaurantID); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+func (r *BookingDraftRepository) Update(ctx context.Context, draft *domain.BookingDraft) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE booking_drafts
+		SET status = $2, date = $3, time = $4, availability_id = NULLIF($5, '')::uuid, pre_order = $6,
+			booking_id = NULLIF($7, '')::uuid, expires_at = $8, updated_at = $9
+		WHERE id::text = $1
+	`
+
+	if draft.PreOrder == nil {
+		draft.PreOrder = make([]domain.PreOrderItem, 0)
+	}
+	draft.UpdatedAt = time.Now()
+
+	var date *time.Time
+	if !draft.Date.IsZero() {
+		date = &draft.Date
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		draft.ID,
+		draft.Status,
+		date,
+		draft.Time,
+		draft.AvailabilityID,
+		draft.PreOrder,
+		draft.BookingID,
+		draft.ExpiresAt,
+		draft.UpdatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateBookingDraft, zap.String("draftID", draft.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateBookingDraft, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingDraftNotFound)
+	}
+
+	return nil
+}
+
+func (r *BookingDraftRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM booking_drafts WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteBookingDraft, zap.String("draftID", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteBookingDraft, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingDraftNotFound)
+	}
+
+	return nil
+}
+
+func (r *BookingDraftRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.BookingDraft, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + bookingDraftColumns + `
+		FROM booking_drafts
+		WHERE expires_at <= $1
+		ORDER BY expires_at, id
+		LIMIT $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, now, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListBookingDrafts, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListBookingDrafts, err)
+	}
+	defer rows.Close()
+
+	drafts := make([]*domain.BookingDraft, 0)
+	for rows.Next() {
+		draft, err := scanBookingDraft(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListBookingDrafts, err)
+		}
+		drafts = append(drafts, draft)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListBookingDrafts, err)
+	}
+
+	return drafts, nil
+}
+
+func scanBookingDraft(row pgx.Row) (*domain.BookingDraft, error) {
+	var draft domain.BookingDraft
+	var date *time.Time
+	err := row.Scan(
+		&draft.ID,
+		&draft.UserID,
+		&draft.RestaurantID,
+		&draft.GuestsCount,
+		&draft.Duration,
+		&draft.Comment,
+		&draft.Occasion,
+		&draft.AgeAttested,
+		&draft.Status,
+		&date,
+		&draft.Time,
+		&draft.AvailabilityID,
+		&draft.PreOrder,
+		&draft.BookingID,
+		&draft.ExpiresAt,
+		&draft.CreatedAt,
+		&draft.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if date != nil {
+		draft.Date = *date
+	}
+	return &draft, nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type BookingLinkRepository struct {
+	*Repository
+}
+
+func NewBookingLinkRepository(repository *Repository) *BookingLinkRepository {
+	return &BookingLinkRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BookingLinkRepository) Create(ctx context.Context, link *domain.BookingLink) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO booking_links (id, booking_id, action, single_use, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		link.ID,
+		link.BookingID,
+		link.Action,
+		link.SingleUse,
+		link.ExpiresAt,
+		link.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateBookingLink,
+			zap.String("bookingID", link.BookingID),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (r *BookingLinkRepository) GetByID(ctx context.Context, id string) (*domain.BookingLink, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, booking_id, action, single_use, expires_at, used_at, created_at
+		FROM booking_links
+		WHERE id = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var link domain.BookingLink
+	err = executor.QueryRow(ctx, query, id).Scan(
+		&link.ID,
+		&link.BookingID,
+		&link.Action,
+		&link.SingleUse,
+		&link.ExpiresAt,
+		&link.UsedAt,
+		&link.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrBookingLinkNotFound)
+		}
+		log.Error(ctx, common.ErrGetBookingLink,
+			zap.String("linkID", id),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &link, nil
+}
+
+// MarkUsed records the first use of the link; a link that was used before is rejected, so
+// a single-use link cannot be used twice even by concurrent requests.
+func (r *BookingLinkRepository) MarkUsed(ctx context.Context, id string, usedAt time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE booking_links
+		SET used_at = $2
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	commandTag, err := executor.Exec(ctx, query, id, usedAt)
+	if err != nil {
+		log.Error(ctx, common.ErrMarkBookingLinkUsed,
+			zap.String("linkID", id),
+			zap.Error(err))
+		return err
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingLinkAlreadyUsed)
+	}
+
+	return nil
+}
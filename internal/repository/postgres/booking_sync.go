@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type BookingSyncRepository struct {
+	*Repository
+}
+
+func NewBookingSyncRepository(repository *Repository) *BookingSyncRepository {
+	return &BookingSyncRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BookingSyncRepository) Save(ctx context.Context, result *domain.BookingSyncResult) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO booking_sync_results (user_id, key, operation, booking_id, status, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, key) DO NOTHING
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		result.UserID,
+		result.Key,
+		result.Operation,
+		result.BookingID,
+		result.Status,
+		result.Error,
+		result.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSaveBookingSyncResult,
+			zap.String("userID", result.UserID),
+			zap.String("key", result.Key),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveBookingSyncResult, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingSyncKeyUsed)
+	}
+
+	return nil
+}
+
+func (r *BookingSyncRepository) GetByKey(ctx context.Context, userID, key string) (*domain.BookingSyncResult, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT user_id, key, operation, booking_id, status, error, created_at
+		FROM booking_sync_results
+		WHERE user_id::text = $1 AND key = $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var result domain.BookingSyncResult
+	err = executor.QueryRow(ctx, query, userID, key).Scan(
+		&result.UserID,
+		&result.Key,
+		&result.Operation,
+		&result.BookingID,
+		&result.Status,
+		&result.Error,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrBookingSyncResultNotFound)
+		}
+		log.Error(ctx, common.ErrGetBookingSyncResult,
+			zap.String("userID", userID),
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingSyncResult, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const bookingTransferColumns = `
+	id, booking_id, source_restaurant_id, target_restaurant_id, date, time, message, status,
+	COALESCE(new_booking_id::text, ''), created_at, decided_at
+`
+
+type BookingTransferRepository struct {
+	*Repository
+}
+
+func NewBookingTransferRepository(repository *Repository) *BookingTransferRepository {
+	return &BookingTransferRepository{
+		Repository: repository,
+	}
+}
+
+func (r *BookingTransferRepository) Create(ctx context.Context, transfer *domain.BookingTransfer) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO booking_transfers (id, booking_id, source_restaurant_id, target_restaurant_id, date, time, message, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (booking_id) WHERE status = 'pending' DO NOTHING
+	`
+
+	if transfer.ID == "" {
+		transfer.ID = r.ids.NewID()
+	}
+	transfer.Status = domain.BookingTransferPending
+	transfer.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		transfer.ID,
+		transfer.BookingID,
+		transfer.SourceRestaurantID,
+		transfer.TargetRestaurantID,
+		transfer.Date,
+		transfer.Time,
+		transfer.Message,
+		transfer.Status,
+		transfer.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateBookingTransfer,
+			zap.String("bookingID", transfer.BookingID),
+			zap.String("targetRestaurantID", transfer.TargetRestaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateBookingTransfer, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingTransferExists)
+	}
+
+	return nil
+}
+
+func (r *BookingTransferRepository) GetByID(ctx context.Context, id string) (*domain.BookingTransfer, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + bookingTransferColumns + `
+		FROM booking_transfers
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	transfer, err := scanBookingTransfer(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrBookingTransferNotFound)
+		}
+		log.Error(ctx, common.ErrGetBookingTransfer, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingTransfer, err)
+	}
+
+	return transfer, nil
+}
+
+func (r *BookingTransferRepository) ListByBooking(ctx context.Context, bookingID string) ([]*domain.BookingTransfer, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + bookingTransferColumns + `
+		FROM booking_transfers
+		WHERE booking_id::text = $1 OR new_booking_id::text = $1
+		ORDER BY created_at DESC
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, bookingID)
+	if err != nil {
+		log.Error(ctx, common.ErrGetBookingTransfer, zap.String("bookingID", bookingID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingTransfer, err)
+	}
+	defer rows.Close()
+
+	transfers := make([]*domain.BookingTransfer, 0)
+	for rows.Next() {
+		transfer, err := scanBookingTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrGetBookingTransfer, err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetBookingTransfer, err)
+	}
+
+	return transfers, nil
+}
+
+func (r *BookingTransferRepository) Decide(ctx context.Context, transfer *domain.BookingTransfer) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE booking_transfers
+		SET status = $2, new_booking_id = NULLIF($3, '')::uuid, decided_at = $4
+		WHERE id::text = $1 AND status = $5
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		transfer.ID,
+		transfer.Status,
+		transfer.NewBookingID,
+		transfer.DecidedAt,
+		domain.BookingTransferPending,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateBookingTransfer, zap.String("id", transfer.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateBookingTransfer, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrBookingTransferNotFound)
+	}
+
+	return nil
+}
+
+func scanBookingTransfer(row pgx.Row) (*domain.BookingTransfer, error) {
+	var transfer domain.BookingTransfer
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.BookingID,
+		&transfer.SourceRestaurantID,
+		&transfer.TargetRestaurantID,
+		&transfer.Date,
+		&transfer.Time,
+		&transfer.Message,
+		&transfer.Status,
+		&transfer.NewBookingID,
+		&transfer.CreatedAt,
+		&transfer.DecidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
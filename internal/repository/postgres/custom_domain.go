@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const customDomainColumns = `id, restaurant_id, domain, status, verification_token, created_at, verified_at`
+
+type CustomDomainRepository struct {
+	*Repository
+}
+
+func NewCustomDomainRepository(repository *Repository) *CustomDomainRepository {
+	return &CustomDomainRepository{
+		Repository: repository,
+	}
+}
+
+func (r *CustomDomainRepository) Create(ctx context.Context, customDomain *domain.CustomDomain) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO custom_domains (id, restaurant_id, domain, status, verification_token, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (domain) DO NOTHING
+	`
+
+	if customDomain.ID == "" {
+		customDomain.ID = r.ids.NewID()
+	}
+	customDomain.Status = domain.CustomDomainPending
+	customDomain.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		customDomain.ID,
+		customDomain.RestaurantID,
+		customDomain.Domain,
+		customDomain.Status,
+		customDomain.VerificationToken,
+		customDomain.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateCustomDomain,
+			zap.String("restaurantID", customDomain.RestaurantID),
+			zap.String("domain", customDomain.Domain),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateCustomDomain, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrCustomDomainTaken)
+	}
+
+	return nil
+}
+
+func (r *CustomDomainRepository) GetByID(ctx context.Context, id string) (*domain.CustomDomain, error) {
+	return r.get(ctx, `SELECT `+customDomainColumns+` FROM custom_domains WHERE id::text = $1`, id)
+}
+
+func (r *CustomDomainRepository) GetByDomain(ctx context.Context, name string) (*domain.CustomDomain, error) {
+	return r.get(ctx, `SELECT `+customDomainColumns+` FROM custom_domains WHERE domain = $1`, name)
+}
+
+func (r *CustomDomainRepository) get(ctx context.Context, query, key string) (*domain.CustomDomain, error) {
+	log, _ := logger.FromContext(ctx)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	customDomain, err := scanCustomDomain(executor.QueryRow(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrCustomDomainNotFound)
+		}
+		log.Error(ctx, common.ErrGetCustomDomain, zap.String("key", key), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetCustomDomain, err)
+	}
+
+	return customDomain, nil
+}
+
+func (r *CustomDomainRepository) ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.CustomDomain, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + customDomainColumns + `
+		FROM custom_domains
+		WHERE restaurant_id::text = $1
+		ORDER BY created_at, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListCustomDomains, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListCustomDomains, err)
+	}
+	defer rows.Close()
+
+	customDomains := make([]*domain.CustomDomain, 0)
+	for rows.Next() {
+		customDomain, err := scanCustomDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListCustomDomains, err)
+		}
+		customDomains = append(customDomains, customDomain)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListCustomDomains, err)
+	}
+
+	return customDomains, nil
+}
+
+func (r *CustomDomainRepository) MarkVerified(ctx context.Context, id string, at time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE custom_domains
+		SET status = $2, verified_at = $3
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, domain.CustomDomainVerified, at)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateCustomDomain, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateCustomDomain, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrCustomDomainNotFound)
+	}
+
+	return nil
+}
+
+func (r *CustomDomainRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM custom_domains WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteCustomDomain, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteCustomDomain, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrCustomDomainNotFound)
+	}
+
+	return nil
+}
+
+func scanCustomDomain(row pgx.Row) (*domain.CustomDomain, error) {
+	var customDomain domain.CustomDomain
+	err := row.Scan(
+		&customDomain.ID,
+		&customDomain.RestaurantID,
+		&customDomain.Domain,
+		&customDomain.Status,
+		&customDomain.VerificationToken,
+		&customDomain.CreatedAt,
+		&customDomain.VerifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &customDomain, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+type DeferredNotificationRepository struct {
+	*Repository
+}
+
+func NewDeferredNotificationRepository(repository *Repository) *DeferredNotificationRepository {
+	return &DeferredNotificationRepository{
+		Repository: repository,
+	}
+}
+
+func (r *DeferredNotificationRepository) Create(ctx context.Context, notification *domain.DeferredNotification) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO deferred_notifications (
+			id, recipient_type, recipient_id, type, channel, title, message, related_id, deliver_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	if notification.ID == "" {
+		notification.ID = r.ids.NewID()
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		notification.ID,
+		notification.RecipientType,
+		notification.RecipientID,
+		notification.NotificationType,
+		notification.Channel,
+		notification.Title,
+		notification.Message,
+		notification.RelatedID,
+		notification.DeliverAt,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateDeferredNotification,
+			zap.String("recipientType", string(notification.RecipientType)),
+			zap.String("recipientID", notification.RecipientID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateDeferredNotification, err)
+	}
+
+	return nil
+}
+
+// Update moves the delivery of the notification to its DeliverAt.
+func (r *DeferredNotificationRepository) Update(ctx context.Context, notification *domain.DeferredNotification) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE deferred_notifications
+		SET deliver_at = $2
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, notification.ID, notification.DeliverAt)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateDeferredNotification, zap.String("id", notification.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateDeferredNotification, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrDeferredNotificationNotFound)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit notifications due at now, oldest due first, and moves their delivery
+// to leaseUntil, so that concurrent deliveries skip them; a delivery that never reports back leaves
+// the notification due again at leaseUntil.
+func (r *DeferredNotificationRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.DeferredNotification, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE deferred_notifications
+		SET deliver_at = $2
+		WHERE id IN (
+			SELECT id FROM deferred_notifications
+			WHERE deliver_at <= $1
+			ORDER BY deliver_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, recipient_type, recipient_id, type, channel, title, message, related_id, deliver_at, created_at
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrClaimDeferredNotifications, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimDeferredNotifications, err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*domain.DeferredNotification, 0)
+	for rows.Next() {
+		var notification domain.DeferredNotification
+		err := rows.Scan(
+			&notification.ID,
+			&notification.RecipientType,
+			&notification.RecipientID,
+			&notification.NotificationType,
+			&notification.Channel,
+			&notification.Title,
+			&notification.Message,
+			&notification.RelatedID,
+			&notification.DeliverAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrClaimDeferredNotifications, err)
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimDeferredNotifications, err)
+	}
+
+	return notifications, nil
+}
+
+func (r *DeferredNotificationRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		DELETE FROM deferred_notifications WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteDeferredNotification, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteDeferredNotification, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrDeferredNotificationNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// exportTables maps every exported entity to its table, the column identifying its rows and the
+// condition leaving out the test records of the entities that have them. Only these are formatted
+// into the queries, the values are always bound, so every entity has a single statement text for
+// the statement cache.
+var exportTables = map[domain.ExportEntity]struct {
+	table    string
+	idColumn string
+	filter   string
+}{
+	domain.ExportRestaurants:   {"restaurants", "id", "NOT t.is_test"},
+	domain.ExportUsers:         {"users", "id", "TRUE"},
+	domain.ExportAvailability:  {"availability", "id", "TRUE"},
+	domain.ExportBookings:      {"bookings", "id", "NOT t.is_test"},
+	domain.ExportMenuItems:     {"menu_items", "id", "TRUE"},
+	domain.ExportReviews:       {"reviews", "id", "TRUE"},
+	domain.ExportReviewReplies: {"review_replies", "review_id", "TRUE"},
+}
+
+type ExportRepository struct {
+	*Repository
+}
+
+func NewExportRepository(repository *Repository) *ExportRepository {
+	return &ExportRepository{
+		Repository: repository,
+	}
+}
+
+func (r *ExportRepository) ListChanges(
+	ctx context.Context,
+	entity domain.ExportEntity,
+	after time.Time,
+	afterID string,
+	until time.Time,
+	limit int,
+) ([]*domain.ExportRecord, error) {
+	log, _ := logger.FromContext(ctx)
+
+	source, ok := exportTables[entity]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, errors.New("unknown entity "+string(entity)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.%[2]s::text, t.updated_at, to_jsonb(t)
+		FROM %[1]s t
+		WHERE (t.updated_at > $1 OR ($2 <> '' AND t.updated_at = $1 AND t.%[2]s::text > $2))
+		  AND t.updated_at <= $3
+		  AND %[3]s
+		ORDER BY t.updated_at, t.%[2]s::text
+		LIMIT $4
+	`, source.table, source.idColumn, source.filter)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, after, afterID, until, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListExportChanges, zap.String("entity", string(entity)), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, err)
+	}
+	defer rows.Close()
+
+	records := make([]*domain.ExportRecord, 0, limit)
+	for rows.Next() {
+		record := &domain.ExportRecord{Entity: entity}
+		if err := rows.Scan(&record.ID, &record.UpdatedAt, &record.Data); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListExportChanges, err)
+	}
+
+	return records, nil
+}
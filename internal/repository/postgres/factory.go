@@ -6,6 +6,8 @@ import (
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -43,37 +45,169 @@ func (a *PoolAdapter) Ping(ctx context.Context) error {
 }
 
 type RepositoryFactory struct {
-	db postgres.Database
+	db  postgres.Database
+	ids idgen.Generator
 }
 
-func NewRepositoryFactory(db postgres.Database) *RepositoryFactory {
+// NewRepositoryFactory creates the factory; ids generates the IDs of the records the repositories
+// create without one.
+func NewRepositoryFactory(db postgres.Database, ids idgen.Generator) *RepositoryFactory {
 	return &RepositoryFactory{
-		db: db,
+		db:  db,
+		ids: ids,
 	}
 }
 
-func (f *RepositoryFactory) Restaurant() *RestaurantRepository {
-	return NewRestaurantRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) Restaurant() repository.RestaurantRepository {
+	return NewRestaurantRepository(NewRepository(f.db.GetPool(), f.ids))
 }
 
-func (f *RepositoryFactory) WorkingHours() *WorkingHoursRepository {
-	return NewWorkingHoursRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) WorkingHours() repository.WorkingHoursRepository {
+	return NewWorkingHoursRepository(NewRepository(f.db.GetPool(), f.ids))
 }
 
-func (f *RepositoryFactory) Availability() *AvailabilityRepository {
-	return NewAvailabilityRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) Availability() repository.AvailabilityRepository {
+	return NewAvailabilityRepository(NewRepository(f.db.GetPool(), f.ids))
 }
 
-func (f *RepositoryFactory) Booking() *BookingRepository {
-	return NewBookingRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) Booking() repository.BookingRepository {
+	return NewBookingRepository(NewRepository(f.db.GetPool(), f.ids))
 }
 
-func (f *RepositoryFactory) User() *UserRepository {
-	return NewUserRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) User() repository.UserRepository {
+	return NewUserRepository(NewRepository(f.db.GetPool(), f.ids))
 }
 
-func (f *RepositoryFactory) Notification() *NotificationRepository {
-	return NewNotificationRepository(NewRepository(f.db.GetPool()))
+func (f *RepositoryFactory) Notification() repository.NotificationRepository {
+	return NewNotificationRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) NotificationSettings() repository.NotificationSettingsRepository {
+	return NewNotificationSettingsRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) BookingLink() repository.BookingLinkRepository {
+	return NewBookingLinkRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Menu() repository.MenuRepository {
+	return NewMenuRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Image() repository.ImageRepository {
+	return NewImageRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Review() repository.ReviewRepository {
+	return NewReviewRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Retention() repository.RetentionRepository {
+	return NewRetentionRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Export() repository.ExportRepository {
+	return NewExportRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Sync() repository.SyncRepository {
+	return NewSyncRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) NotificationFailure() repository.NotificationFailureRepository {
+	return NewNotificationFailureRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) DeferredNotification() repository.DeferredNotificationRepository {
+	return NewDeferredNotificationRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) RestaurantPerformance() repository.RestaurantPerformanceRepository {
+	return NewRestaurantPerformanceRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Reengagement() repository.ReengagementRepository {
+	return NewReengagementRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Analytics() repository.AnalyticsRepository {
+	return NewAnalyticsRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Quota() repository.QuotaRepository {
+	return NewQuotaRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Billing() repository.BillingRepository {
+	return NewBillingRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Incentive() repository.IncentiveRepository {
+	return NewIncentiveRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) RestaurantLocation() repository.RestaurantLocationRepository {
+	return NewRestaurantLocationRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) AvailabilityAlert() repository.AvailabilityAlertRepository {
+	return NewAvailabilityAlertRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) BookingDraft() repository.BookingDraftRepository {
+	return NewBookingDraftRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Organization() repository.OrganizationRepository {
+	return NewOrganizationRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) BookingTransfer() repository.BookingTransferRepository {
+	return NewBookingTransferRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) SLO() repository.SLORepository {
+	return NewSLORepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) BookingSync() repository.BookingSyncRepository {
+	return NewBookingSyncRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) RestaurantClaim() repository.RestaurantClaimRepository {
+	return NewRestaurantClaimRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) CustomDomain() repository.CustomDomainRepository {
+	return NewCustomDomainRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) RestaurantClosure() repository.RestaurantClosureRepository {
+	return NewRestaurantClosureRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) SpecialDay() repository.SpecialDayRepository {
+	return NewSpecialDayRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Table() repository.TableRepository {
+	return NewTableRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) WidgetSettings() repository.WidgetSettingsRepository {
+	return NewWidgetSettingsRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) QueryStats() repository.QueryStatsRepository {
+	return NewQueryStatsRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Account() repository.AccountRepository {
+	return NewAccountRepository(NewRepository(f.db.GetPool(), f.ids))
+}
+
+func (f *RepositoryFactory) Transactor() repository.Transactor {
+	return NewTransactor(NewRepository(f.db.GetPool(), f.ids))
 }
 
 type PostgresFactory struct {
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+)
+
+// DBFaultInjector decides whether a database call fails on purpose.
+type DBFaultInjector interface {
+	DBFault() error
+}
+
+// FaultInjectingDatabase fails acquiring a connection with the error of the injector, which the
+// repositories report as they would a database that is down. Calls inside a transaction already
+// hold their connection and are not failed.
+type FaultInjectingDatabase struct {
+	postgres.Database
+	injector DBFaultInjector
+}
+
+func NewFaultInjectingDatabase(db postgres.Database, injector DBFaultInjector) *FaultInjectingDatabase {
+	return &FaultInjectingDatabase{
+		Database: db,
+		injector: injector,
+	}
+}
+
+func (d *FaultInjectingDatabase) GetPool() postgres.Pool {
+	return &faultInjectingPool{
+		Pool:     d.Database.GetPool(),
+		injector: d.injector,
+	}
+}
+
+type faultInjectingPool struct {
+	postgres.Pool
+	injector DBFaultInjector
+}
+
+func (p *faultInjectingPool) Acquire(ctx context.Context) (postgres.Conn, error) {
+	if err := p.injector.DBFault(); err != nil {
+		return nil, err
+	}
+	return p.Pool.Acquire(ctx)
+}
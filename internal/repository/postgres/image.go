@@ -0,0 +1,405 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ImageRepository struct {
+	*Repository
+}
+
+func NewImageRepository(repository *Repository) *ImageRepository {
+	return &ImageRepository{
+		Repository: repository,
+	}
+}
+
+func (r *ImageRepository) Create(ctx context.Context, image *domain.RestaurantImage) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_images (id, restaurant_id, content_type, width, height, size_bytes, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if image.ID == "" {
+		image.ID = r.ids.NewID()
+	}
+	if image.CreatedAt.IsZero() {
+		image.CreatedAt = time.Now()
+	}
+	image.Size = len(image.Data)
+
+	const variantQuery = `
+		INSERT INTO restaurant_image_variants (image_id, content_type, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			image.ID,
+			image.RestaurantID,
+			image.ContentType,
+			image.Width,
+			image.Height,
+			image.Size,
+			image.Data,
+			image.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		for i := range image.Variants {
+			variant := &image.Variants[i]
+			variant.ImageID = image.ID
+			variant.UpdatedAt = image.CreatedAt
+			if _, err := tx.Exec(ctx, variantQuery, variant.ImageID, variant.ContentType, variant.Status, variant.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrCreateImage,
+			zap.String("restaurantID", image.RestaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateImage, err)
+	}
+
+	return nil
+}
+
+func (r *ImageRepository) GetByID(ctx context.Context, id string) (*domain.RestaurantImage, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, restaurant_id, content_type, width, height, size_bytes, data, created_at
+		FROM restaurant_images
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var image domain.RestaurantImage
+	err = executor.QueryRow(ctx, query, id).Scan(
+		&image.ID,
+		&image.RestaurantID,
+		&image.ContentType,
+		&image.Width,
+		&image.Height,
+		&image.Size,
+		&image.Data,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrImageNotFound)
+		}
+		log.Error(ctx, common.ErrGetImage, zap.String("imageID", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetImage, err)
+	}
+
+	if err := r.attachVariants(ctx, executor, []*domain.RestaurantImage{&image}); err != nil {
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+func (r *ImageRepository) ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, restaurant_id, content_type, width, height, size_bytes, created_at
+		FROM restaurant_images
+		WHERE restaurant_id = $1
+		ORDER BY created_at, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListImages, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListImages, err)
+	}
+	defer rows.Close()
+
+	images := make([]*domain.RestaurantImage, 0)
+	for rows.Next() {
+		var image domain.RestaurantImage
+		err := rows.Scan(
+			&image.ID,
+			&image.RestaurantID,
+			&image.ContentType,
+			&image.Width,
+			&image.Height,
+			&image.Size,
+			&image.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListImages, err)
+		}
+		images = append(images, &image)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListImages, err)
+	}
+	rows.Close()
+
+	if err := r.attachVariants(ctx, executor, images); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (r *ImageRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM restaurant_images WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	commandTag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteImage, zap.String("imageID", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteImage, err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.New(common.ErrImageNotFound)
+	}
+
+	return nil
+}
+
+const imageVariantColumns = `image_id, content_type, status, width, height, size_bytes, error, updated_at`
+
+func scanImageVariant(row pgx.Row) (domain.RestaurantImageVariant, error) {
+	var variant domain.RestaurantImageVariant
+	err := row.Scan(
+		&variant.ImageID,
+		&variant.ContentType,
+		&variant.Status,
+		&variant.Width,
+		&variant.Height,
+		&variant.Size,
+		&variant.Error,
+		&variant.UpdatedAt,
+	)
+	return variant, err
+}
+
+func (r *ImageRepository) GetVariants(ctx context.Context, imageID string) ([]domain.RestaurantImageVariant, error) {
+	log, _ := logger.FromContext(ctx)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	image := &domain.RestaurantImage{ID: imageID}
+	if err := r.attachVariants(ctx, executor, []*domain.RestaurantImage{image}); err != nil {
+		return nil, err
+	}
+
+	return image.Variants, nil
+}
+
+// attachVariants loads the variants of the images with one query on the executor already held.
+func (r *ImageRepository) attachVariants(ctx context.Context, executor DBExecutor, images []*domain.RestaurantImage) error {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + imageVariantColumns + `
+		FROM restaurant_image_variants
+		WHERE image_id::text = ANY($1)
+		ORDER BY content_type
+	`
+
+	byID := make(map[string]*domain.RestaurantImage, len(images))
+	ids := make([]string, 0, len(images))
+	for _, image := range images {
+		image.Variants = make([]domain.RestaurantImageVariant, 0)
+		byID[image.ID] = image
+		ids = append(ids, image.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	rows, err := executor.Query(ctx, query, ids)
+	if err != nil {
+		log.Error(ctx, common.ErrGetImageVariants, zap.Strings("imageIDs", ids), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrGetImageVariants, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		variant, err := scanImageVariant(rows)
+		if err != nil {
+			return fmt.Errorf("%s: %w", common.ErrGetImageVariants, err)
+		}
+		if image, ok := byID[variant.ImageID]; ok {
+			image.Variants = append(image.Variants, variant)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", common.ErrGetImageVariants, err)
+	}
+
+	return nil
+}
+
+// GetVariantData returns the encoded variant; only ready variants are found.
+func (r *ImageRepository) GetVariantData(ctx context.Context, imageID, contentType string) ([]byte, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT data
+		FROM restaurant_image_variants
+		WHERE image_id::text = $1 AND content_type = $2 AND status = $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var data []byte
+	err = executor.QueryRow(ctx, query, imageID, contentType, domain.ImageVariantReady).Scan(&data)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrImageNotFound)
+		}
+		log.Error(ctx, common.ErrGetImageVariants,
+			zap.String("imageID", imageID),
+			zap.String("contentType", contentType),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetImageVariants, err)
+	}
+
+	return data, nil
+}
+
+func (r *ImageRepository) ClaimPendingVariants(ctx context.Context, limit int, staleBefore time.Time) ([]domain.RestaurantImageVariant, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		UPDATE restaurant_image_variants v
+		SET status = $1, updated_at = NOW()
+		FROM (
+			SELECT image_id, content_type
+			FROM restaurant_image_variants
+			WHERE status = $2 OR (status = $1 AND updated_at < $3)
+			ORDER BY updated_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		) claimed
+		WHERE v.image_id = claimed.image_id AND v.content_type = claimed.content_type
+		RETURNING v.image_id, v.content_type, v.status, v.width, v.height, v.size_bytes, v.error, v.updated_at
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, domain.ImageVariantProcessing, domain.ImageVariantPending, staleBefore, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrClaimImageVariants, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimImageVariants, err)
+	}
+	defer rows.Close()
+
+	variants := make([]domain.RestaurantImageVariant, 0)
+	for rows.Next() {
+		variant, err := scanImageVariant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrClaimImageVariants, err)
+		}
+		variants = append(variants, variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimImageVariants, err)
+	}
+
+	return variants, nil
+}
+
+func (r *ImageRepository) SaveVariant(ctx context.Context, variant *domain.RestaurantImageVariant) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE restaurant_image_variants
+		SET status = $3, width = $4, height = $5, size_bytes = $6, data = $7, error = $8, updated_at = $9
+		WHERE image_id::text = $1 AND content_type = $2
+	`
+
+	variant.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	// The image may have been deleted in the meantime; there is nothing left to update then.
+	_, err = executor.Exec(ctx, query,
+		variant.ImageID,
+		variant.ContentType,
+		variant.Status,
+		variant.Width,
+		variant.Height,
+		variant.Size,
+		variant.Data,
+		variant.Error,
+		variant.UpdatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSaveImageVariant,
+			zap.String("imageID", variant.ImageID),
+			zap.String("contentType", variant.ContentType),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveImageVariant, err)
+	}
+
+	return nil
+}
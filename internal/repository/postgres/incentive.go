@@ -0,0 +1,386 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const incentiveRuleColumns = `
+	id, name, effect, points, conditions, enabled, valid_from, valid_until, created_at, updated_at
+`
+
+const incentiveEvaluationColumns = `
+	id, booking_id, facts, results, waive_deposit, bonus_points, evaluated_at
+`
+
+type IncentiveRepository struct {
+	*Repository
+}
+
+func NewIncentiveRepository(repository *Repository) *IncentiveRepository {
+	return &IncentiveRepository{
+		Repository: repository,
+	}
+}
+
+// ListRules returns the rules, only the enabled ones when enabledOnly is set, oldest first.
+func (r *IncentiveRepository) ListRules(ctx context.Context, enabledOnly bool) ([]*domain.IncentiveRule, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + incentiveRuleColumns + `
+		FROM incentive_rules
+		WHERE enabled OR NOT $1
+		ORDER BY created_at, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, enabledOnly)
+	if err != nil {
+		log.Error(ctx, common.ErrListIncentiveRules, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveRules, err)
+	}
+	defer rows.Close()
+
+	rules := make([]*domain.IncentiveRule, 0)
+	for rows.Next() {
+		rule, err := scanIncentiveRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveRules, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveRules, err)
+	}
+
+	return rules, nil
+}
+
+func (r *IncentiveRepository) GetRule(ctx context.Context, id string) (*domain.IncentiveRule, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `SELECT ` + incentiveRuleColumns + ` FROM incentive_rules WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rule, err := scanIncentiveRule(executor.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New(common.ErrIncentiveRuleNotFound)
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrGetIncentiveRule, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetIncentiveRule, err)
+	}
+
+	return rule, nil
+}
+
+func (r *IncentiveRepository) CreateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO incentive_rules (
+			id, name, effect, points, conditions, enabled, valid_from, valid_until, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`
+
+	if rule.ID == "" {
+		rule.ID = r.ids.NewID()
+	}
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Effect,
+		rule.Points,
+		rule.Conditions,
+		rule.Enabled,
+		rule.ValidFrom,
+		rule.ValidUntil,
+		rule.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateIncentiveRule, zap.String("name", rule.Name), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateIncentiveRule, err)
+	}
+
+	return nil
+}
+
+func (r *IncentiveRepository) UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE incentive_rules
+		SET name = $2, effect = $3, points = $4, conditions = $5, enabled = $6, valid_from = $7,
+			valid_until = $8, updated_at = $9
+		WHERE id::text = $1
+		RETURNING created_at
+	`
+
+	rule.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	err = executor.QueryRow(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Effect,
+		rule.Points,
+		rule.Conditions,
+		rule.Enabled,
+		rule.ValidFrom,
+		rule.ValidUntil,
+		rule.UpdatedAt,
+	).Scan(&rule.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New(common.ErrIncentiveRuleNotFound)
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateIncentiveRule, zap.String("id", rule.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateIncentiveRule, err)
+	}
+
+	return nil
+}
+
+func (r *IncentiveRepository) DeleteRule(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM incentive_rules WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteIncentiveRule, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteIncentiveRule, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrIncentiveRuleNotFound)
+	}
+
+	return nil
+}
+
+// CountPreviousBookings counts the bookings the user made, test, rejected and cancelled ones
+// aside.
+func (r *IncentiveRepository) CountPreviousBookings(ctx context.Context, userID string) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COUNT(*) FROM bookings
+		WHERE user_id::text = $1 AND NOT is_test AND status NOT IN ('rejected', 'cancelled')
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	var count int
+	if err := executor.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		log.Error(ctx, common.ErrEvaluateIncentives, zap.String("userID", userID), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrEvaluateIncentives, err)
+	}
+
+	return count, nil
+}
+
+func (r *IncentiveRepository) CreateEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO incentive_evaluations (
+			id, booking_id, user_id, facts, results, waive_deposit, bonus_points, evaluated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if evaluation.ID == "" {
+		evaluation.ID = r.ids.NewID()
+	}
+	if evaluation.EvaluatedAt.IsZero() {
+		evaluation.EvaluatedAt = time.Now()
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		evaluation.ID,
+		evaluation.BookingID,
+		evaluation.Facts.UserID,
+		evaluation.Facts,
+		evaluation.Results,
+		evaluation.WaiveDeposit,
+		evaluation.BonusPoints,
+		evaluation.EvaluatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateIncentiveEvaluation, zap.String("bookingID", evaluation.BookingID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateIncentiveEvaluation, err)
+	}
+
+	return nil
+}
+
+// GetEvaluation returns the latest evaluation of the booking.
+func (r *IncentiveRepository) GetEvaluation(ctx context.Context, bookingID string) (*domain.IncentiveEvaluation, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + incentiveEvaluationColumns + `
+		FROM incentive_evaluations
+		WHERE booking_id::text = $1
+		ORDER BY evaluated_at DESC
+		LIMIT 1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	evaluation, err := scanIncentiveEvaluation(executor.QueryRow(ctx, query, bookingID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.New(common.ErrIncentiveEvaluationNotFound)
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrGetIncentiveEvaluation, zap.String("bookingID", bookingID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetIncentiveEvaluation, err)
+	}
+
+	return evaluation, nil
+}
+
+// ListEvaluations returns the evaluations for the bookings of the user, or of every user when it
+// is empty, newest first.
+func (r *IncentiveRepository) ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + incentiveEvaluationColumns + `
+		FROM incentive_evaluations
+		WHERE $1::text = '' OR user_id::text = $1
+		ORDER BY evaluated_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, userID, limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListIncentiveEvaluations, zap.String("userID", userID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveEvaluations, err)
+	}
+	defer rows.Close()
+
+	evaluations := make([]*domain.IncentiveEvaluation, 0)
+	for rows.Next() {
+		evaluation, err := scanIncentiveEvaluation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveEvaluations, err)
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListIncentiveEvaluations, err)
+	}
+
+	return evaluations, nil
+}
+
+func scanIncentiveRule(row pgx.Row) (*domain.IncentiveRule, error) {
+	var rule domain.IncentiveRule
+	err := row.Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Effect,
+		&rule.Points,
+		&rule.Conditions,
+		&rule.Enabled,
+		&rule.ValidFrom,
+		&rule.ValidUntil,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func scanIncentiveEvaluation(row pgx.Row) (*domain.IncentiveEvaluation, error) {
+	var evaluation domain.IncentiveEvaluation
+	err := row.Scan(
+		&evaluation.ID,
+		&evaluation.BookingID,
+		&evaluation.Facts,
+		&evaluation.Results,
+		&evaluation.WaiveDeposit,
+		&evaluation.BonusPoints,
+		&evaluation.EvaluatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &evaluation, nil
+}
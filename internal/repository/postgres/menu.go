@@ -0,0 +1,427 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type MenuRepository struct {
+	*Repository
+}
+
+func NewMenuRepository(repository *Repository) *MenuRepository {
+	return &MenuRepository{
+		Repository: repository,
+	}
+}
+
+func (r *MenuRepository) GetMenu(ctx context.Context, restaurantID string) ([]domain.MenuItem, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT m.id, m.restaurant_id, m.name, m.description, m.category, m.price, r.currency, m.is_available,
+		       m.daily_limit, m.allergens, m.dietary_labels, m.created_at, m.updated_at
+		FROM menu_items m
+		JOIN restaurants r ON r.id = m.restaurant_id
+		WHERE m.restaurant_id = $1
+		ORDER BY m.position, m.name
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrGetMenu, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetMenu, err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.MenuItem, 0)
+	for rows.Next() {
+		var item domain.MenuItem
+		var allergens, dietaryLabels []string
+		err := rows.Scan(
+			&item.ID,
+			&item.RestaurantID,
+			&item.Name,
+			&item.Description,
+			&item.Category,
+			&item.Price,
+			&item.Currency,
+			&item.IsAvailable,
+			&item.DailyLimit,
+			&allergens,
+			&dietaryLabels,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrGetMenu, err)
+		}
+		item.Allergens = convertStrings[string, domain.Allergen](allergens)
+		item.DietaryLabels = convertStrings[string, domain.DietaryLabel](dietaryLabels)
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetMenu, err)
+	}
+
+	return items, nil
+}
+
+// SaveMenu stores the items in the listed order. Items without an ID get one; an ID of an item of
+// another restaurant is left untouched.
+func (r *MenuRepository) SaveMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) error {
+	log, _ := logger.FromContext(ctx)
+
+	ids := make([]string, 0, len(items))
+	for i := range items {
+		if items[i].ID == "" {
+			items[i].ID = r.ids.NewID()
+		}
+		items[i].RestaurantID = restaurantID
+		ids = append(ids, items[i].ID)
+	}
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const deleteQuery = `
+			DELETE FROM menu_items
+			WHERE restaurant_id = $1 AND NOT (id::text = ANY($2))
+		`
+		if _, err := tx.Exec(ctx, deleteQuery, restaurantID, ids); err != nil {
+			return err
+		}
+
+		const upsertQuery = `
+			INSERT INTO menu_items (id, restaurant_id, name, description, category, price, is_available, daily_limit, allergens, dietary_labels, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+			ON CONFLICT (id) DO UPDATE
+			SET name = EXCLUDED.name,
+			    description = EXCLUDED.description,
+			    category = EXCLUDED.category,
+			    price = EXCLUDED.price,
+			    is_available = EXCLUDED.is_available,
+			    daily_limit = EXCLUDED.daily_limit,
+			    allergens = EXCLUDED.allergens,
+			    dietary_labels = EXCLUDED.dietary_labels,
+			    position = EXCLUDED.position,
+			    updated_at = EXCLUDED.updated_at
+			WHERE menu_items.restaurant_id = EXCLUDED.restaurant_id
+		`
+		now := time.Now()
+		for i, item := range items {
+			_, err := tx.Exec(ctx, upsertQuery,
+				item.ID,
+				restaurantID,
+				item.Name,
+				item.Description,
+				item.Category,
+				item.Price,
+				item.IsAvailable,
+				item.DailyLimit,
+				convertStrings[domain.Allergen, string](item.Allergens),
+				convertStrings[domain.DietaryLabel, string](item.DietaryLabels),
+				i,
+				now,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrSaveMenu,
+			zap.String("restaurantID", restaurantID),
+			zap.Int("items", len(items)),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveMenu, err)
+	}
+
+	return nil
+}
+
+// convertStrings converts between string types, as text arrays are read and written as []string;
+// nil becomes an empty slice.
+func convertStrings[From, To ~string](values []From) []To {
+	result := make([]To, 0, len(values))
+	for _, value := range values {
+		result = append(result, To(value))
+	}
+	return result
+}
+
+func (r *MenuRepository) GetPreOrder(ctx context.Context, bookingID string) ([]domain.PreOrderItem, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COALESCE(menu_item_id::text, ''), name, unit_price, currency, quantity, notes
+		FROM booking_pre_order_items
+		WHERE booking_id = $1
+		ORDER BY position
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, bookingID)
+	if err != nil {
+		log.Error(ctx, common.ErrGetPreOrder, zap.String("bookingID", bookingID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetPreOrder, err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.PreOrderItem, 0)
+	for rows.Next() {
+		var item domain.PreOrderItem
+		if err := rows.Scan(&item.MenuItemID, &item.Name, &item.UnitPrice, &item.Currency, &item.Quantity, &item.Notes); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrGetPreOrder, err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetPreOrder, err)
+	}
+
+	return items, nil
+}
+
+// SavePreOrder replaces the pre-order of the booking. While the booking is active, the units it
+// adds are reserved for its date and the ones it drops are released; it fails with a
+// *domain.PreOrderLimitError when an item has fewer units left for the day than it adds.
+func (r *MenuRepository) SavePreOrder(ctx context.Context, bookingID string, items []domain.PreOrderItem) error {
+	log, _ := logger.FromContext(ctx)
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const bookingQuery = `
+			SELECT date, status FROM bookings
+			WHERE id = $1 FOR UPDATE
+		`
+		var date time.Time
+		var status domain.BookingStatus
+		if err := tx.QueryRow(ctx, bookingQuery, bookingID).Scan(&date, &status); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("%s: %w", common.ErrBookingNotFound, err)
+			}
+			return err
+		}
+
+		if status == domain.BookingStatusPending || status == domain.BookingStatusConfirmed {
+			previous, err := preOrderQuantities(ctx, tx, bookingID)
+			if err != nil {
+				return err
+			}
+			deltas := make(map[string]int, len(items)+len(previous))
+			for _, item := range items {
+				if item.MenuItemID != "" {
+					deltas[item.MenuItemID] += item.Quantity
+				}
+			}
+			for id, quantity := range previous {
+				deltas[id] -= quantity
+			}
+			if err := reservePreOrderItems(ctx, tx, date, deltas); err != nil {
+				return err
+			}
+		}
+
+		const deleteQuery = `
+			DELETE FROM booking_pre_order_items
+			WHERE booking_id = $1
+		`
+		if _, err := tx.Exec(ctx, deleteQuery, bookingID); err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		// The items travel as one array per column so the statement text is the same for any number
+		// of items and a single prepared statement serves them all.
+		const insertQuery = `
+			INSERT INTO booking_pre_order_items (booking_id, position, menu_item_id, name, unit_price, currency, quantity, notes)
+			SELECT $1, item.position, item.menu_item_id::uuid, item.name, item.unit_price, item.currency, item.quantity, item.notes
+			FROM unnest($2::int[], $3::text[], $4::text[], $5::bigint[], $6::text[], $7::int[], $8::text[])
+				AS item(position, menu_item_id, name, unit_price, currency, quantity, notes)
+		`
+
+		positions := make([]int, len(items))
+		menuItemIDs := make([]*string, len(items))
+		names := make([]string, len(items))
+		unitPrices := make([]int64, len(items))
+		currencies := make([]string, len(items))
+		quantities := make([]int, len(items))
+		notes := make([]string, len(items))
+		for i, item := range items {
+			positions[i] = i
+			if item.MenuItemID != "" {
+				menuItemIDs[i] = &item.MenuItemID
+			}
+			names[i] = item.Name
+			unitPrices[i] = item.UnitPrice
+			currencies[i] = item.Currency
+			quantities[i] = item.Quantity
+			notes[i] = item.Notes
+		}
+
+		_, err := tx.Exec(ctx, insertQuery, bookingID, positions, menuItemIDs, names, unitPrices, currencies, quantities, notes)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrSavePreOrder,
+			zap.String("bookingID", bookingID),
+			zap.Int("items", len(items)),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSavePreOrder, err)
+	}
+
+	return nil
+}
+
+// preOrderQuantities sums the quantities pre-ordered for the booking by menu item.
+func preOrderQuantities(ctx context.Context, tx pgx.Tx, bookingID string) (map[string]int, error) {
+	const query = `
+		SELECT menu_item_id::text, SUM(quantity)
+		FROM booking_pre_order_items
+		WHERE booking_id = $1 AND menu_item_id IS NOT NULL
+		GROUP BY menu_item_id
+	`
+	rows, err := tx.Query(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quantities := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var quantity int
+		if err := rows.Scan(&id, &quantity); err != nil {
+			return nil, err
+		}
+		quantities[id] = quantity
+	}
+	return quantities, rows.Err()
+}
+
+// reservePreOrderItems changes the units of the menu items reserved for the date by the deltas,
+// locking the rows in the order of the IDs so that concurrent bookings cannot deadlock. Only growth
+// is checked against the daily limits, as a lowered limit may already be below the units reserved.
+func reservePreOrderItems(ctx context.Context, tx pgx.Tx, date time.Time, deltas map[string]int) error {
+	const insertQuery = `
+		INSERT INTO menu_item_daily_reservations (menu_item_id, date, reserved)
+		SELECT id, $2, 0 FROM menu_items WHERE id = $1
+		ON CONFLICT (menu_item_id, date) DO NOTHING
+	`
+	const getQuery = `
+		SELECT r.reserved, m.daily_limit, m.name
+		FROM menu_item_daily_reservations r
+		JOIN menu_items m ON m.id = r.menu_item_id
+		WHERE r.menu_item_id = $1 AND r.date = $2
+		FOR UPDATE OF r
+	`
+	const updateQuery = `
+		UPDATE menu_item_daily_reservations
+		SET reserved = $3
+		WHERE menu_item_id = $1 AND date = $2
+	`
+
+	for _, id := range slices.Sorted(maps.Keys(deltas)) {
+		delta := deltas[id]
+		if delta == 0 {
+			continue
+		}
+		if delta > 0 {
+			if _, err := tx.Exec(ctx, insertQuery, id, date); err != nil {
+				return err
+			}
+		}
+
+		var reserved, limit int
+		var name string
+		err := tx.QueryRow(ctx, getQuery, id, date).Scan(&reserved, &limit, &name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if delta > 0 && limit > 0 && reserved+delta > limit {
+			return &domain.PreOrderLimitError{
+				MenuItemID: id, Name: name, Date: date, Limit: limit, Reserved: reserved, Delta: delta,
+			}
+		}
+		if _, err := tx.Exec(ctx, updateQuery, id, date, max(reserved+delta, 0)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releasePreOrderItems releases the units pre-ordered by the booking for the date.
+func releasePreOrderItems(ctx context.Context, tx pgx.Tx, bookingID string, date time.Time) error {
+	quantities, err := preOrderQuantities(ctx, tx, bookingID)
+	if err != nil {
+		return err
+	}
+	for id, quantity := range quantities {
+		quantities[id] = -quantity
+	}
+	return reservePreOrderItems(ctx, tx, date, quantities)
+}
+
+// movePreOrderItems reserves the pre-order of the booking for the date it moves to as a confirmed
+// booking, releasing the units it held on its old date.
+func movePreOrderItems(ctx context.Context, tx pgx.Tx, bookingID string, date time.Time) error {
+	const bookingQuery = `
+		SELECT date, status FROM bookings
+		WHERE id = $1 FOR UPDATE
+	`
+	var previousDate time.Time
+	var status domain.BookingStatus
+	if err := tx.QueryRow(ctx, bookingQuery, bookingID).Scan(&previousDate, &status); err != nil {
+		return err
+	}
+
+	active := status == domain.BookingStatusPending || status == domain.BookingStatusConfirmed
+	if active && previousDate.Equal(date) {
+		return nil
+	}
+
+	quantities, err := preOrderQuantities(ctx, tx, bookingID)
+	if err != nil {
+		return err
+	}
+	if err := reservePreOrderItems(ctx, tx, date, quantities); err != nil {
+		return err
+	}
+	if active {
+		return releasePreOrderItems(ctx, tx, bookingID, previousDate)
+	}
+	return nil
+}
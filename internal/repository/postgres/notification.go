@@ -9,8 +9,8 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +28,8 @@ func NewNotificationRepository(repository *Repository) *NotificationRepository {
 	}
 }
 
+const notificationColumns = `id, recipient_type, recipient_id, type, channel, title, message, related_id, created_at, is_read, delivered_at, read_at`
+
 func (r *NotificationRepository) scanNotification(rows interface{ Scan(dest ...any) error }) (domain.Notification, error) {
 	var notification domain.Notification
 	var isRead bool
@@ -37,11 +39,14 @@ func (r *NotificationRepository) scanNotification(rows interface{ Scan(dest ...a
 		&notification.RecipientType,
 		&notification.RecipientID,
 		&notification.Type,
+		&notification.Channel,
 		&notification.Title,
 		&notification.Message,
 		&notification.RelatedID,
 		&notification.CreatedAt,
 		&isRead,
+		&notification.DeliveredAt,
+		&notification.ReadAt,
 	)
 	if err != nil {
 		return notification, fmt.Errorf("%s: %w", common.ErrScanNotification, err)
@@ -56,17 +61,25 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 	log, _ := logger.FromContext(ctx)
 
 	if notification.ID == "" {
-		notification.ID = uuid.New().String()
+		notification.ID = r.ids.NewID()
 	}
 
 	const query = `
-		INSERT INTO notifications (id, recipient_type, recipient_id, type, title, message, is_read, related_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO notifications (id, recipient_type, recipient_id, type, channel, title, message, is_read, related_id, created_at, delivered_at, read_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
 	if notification.CreatedAt.IsZero() {
 		notification.CreatedAt = time.Now()
 	}
+	if notification.Channel == "" {
+		notification.Channel = domain.NotificationChannelInApp
+	}
+	// An in-app notification reaches the inbox of its recipient as soon as it is stored.
+	if notification.Channel == domain.NotificationChannelInApp && notification.DeliveredAt == nil {
+		deliveredAt := notification.CreatedAt
+		notification.DeliveredAt = &deliveredAt
+	}
 
 	executor, release, err := r.GetExecutor(ctx)
 	if err != nil {
@@ -80,11 +93,14 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 		notification.RecipientType,
 		notification.RecipientID,
 		notification.Type,
+		notification.Channel,
 		notification.Title,
 		notification.Message,
 		notification.IsRead,
 		notification.RelatedID,
 		notification.CreatedAt,
+		notification.DeliveredAt,
+		notification.ReadAt,
 	)
 	if err != nil {
 		log.Error(ctx, common.ErrCreateNotification,
@@ -101,9 +117,9 @@ func (r *NotificationRepository) getNotificationsByRecipient(ctx context.Context
 	log, _ := logger.FromContext(ctx)
 
 	const query = `
-		SELECT id, recipient_type, recipient_id, type, title, message, related_id, created_at, is_read
+		SELECT ` + notificationColumns + `
 		FROM notifications
-		WHERE recipient_type = $1 AND recipient_id = $2
+		WHERE recipient_type = $1 AND recipient_id = $2 AND channel = 'in_app'
 		ORDER BY created_at DESC
 	`
 
@@ -131,6 +147,10 @@ func (r *NotificationRepository) getNotificationsByRecipient(ctx context.Context
 			log.Error(ctx, common.ErrScanNotification, zap.Error(err))
 			return nil, err
 		}
+		if err := guardNotification(ctx, &notification); err != nil {
+			log.Warn(ctx, common.ErrTenantIsolation, zap.Error(err))
+			return nil, err
+		}
 		notifications = append(notifications, notification)
 	}
 
@@ -155,7 +175,7 @@ func (r *NotificationRepository) MarkAsRead(ctx context.Context, notificationID
 
 	const query = `
 		UPDATE notifications
-		SET is_read = true
+		SET is_read = true, read_at = COALESCE(read_at, NOW())
 		WHERE id = $1 AND is_read = false
 	`
 
@@ -190,7 +210,7 @@ func (r *NotificationRepository) MarkAsRead(ctx context.Context, notificationID
 
 func (r *NotificationRepository) NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message, relatedID string) error {
 	notification := domain.Notification{
-		ID:            uuid.New().String(),
+		ID:            r.ids.NewID(),
 		RecipientType: domain.RecipientTypeUser,
 		RecipientID:   userID,
 		Type:          notificationType,
@@ -206,7 +226,7 @@ func (r *NotificationRepository) NotifyUser(ctx context.Context, userID string,
 
 func (r *NotificationRepository) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message, relatedID string) error {
 	notification := domain.Notification{
-		ID:            uuid.New().String(),
+		ID:            r.ids.NewID(),
 		RecipientType: domain.RecipientTypeRestaurant,
 		RecipientID:   restaurantID,
 		Type:          notificationType,
@@ -241,7 +261,7 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*domai
 	}
 
 	const query = `
-		SELECT id, recipient_type, recipient_id, type, title, message, related_id, created_at, read_at
+		SELECT ` + notificationColumns + `
 		FROM notifications
 		WHERE id = $1
 	`
@@ -274,5 +294,95 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*domai
 		return nil, fmt.Errorf("%s: %w", common.ErrScanNotification, err)
 	}
 
+	if err := guardNotification(ctx, &notification); err != nil {
+		logger.Warn(ctx, common.ErrTenantIsolation, zap.Error(err))
+		return nil, err
+	}
+
 	return &notification, nil
 }
+
+// GetByRelatedID returns the notifications about an entity to any recipient, oldest first. It is not
+// guarded per recipient, as a booking is told about to both its user and its restaurant; callers
+// check the access to the entity itself.
+func (r *NotificationRepository) GetByRelatedID(ctx context.Context, relatedID string) ([]domain.Notification, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE related_id = $1
+		ORDER BY created_at, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, relatedID)
+	if err != nil {
+		log.Error(ctx, common.ErrExecuteNotificationsQuery,
+			zap.String("relatedID", relatedID),
+			zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]domain.Notification, 0)
+	for rows.Next() {
+		notification, err := r.scanNotification(rows)
+		if err != nil {
+			log.Error(ctx, common.ErrScanNotification, zap.Error(err))
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Error(ctx, common.ErrIterateNotifications, zap.Error(err))
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// MarkRead records the first time the notification was read; reading it again keeps that time.
+func (r *NotificationRepository) MarkRead(ctx context.Context, id string, readAt time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE notifications
+		SET is_read = true, read_at = COALESCE(read_at, $2)
+		WHERE id = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, readAt)
+	if err != nil {
+		log.Error(ctx, common.ErrMarkNotificationAsRead,
+			zap.String("notificationID", id),
+			zap.Error(err))
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func guardNotification(ctx context.Context, notification *domain.Notification) error {
+	if notification.RecipientType == domain.RecipientTypeRestaurant {
+		return tenant.GuardRestaurant(ctx, "notification", notification.ID, notification.RecipientID)
+	}
+	return tenant.GuardUser(ctx, "notification", notification.ID, notification.RecipientID)
+}
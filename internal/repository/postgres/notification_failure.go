@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const notificationFailureColumns = `
+	id, recipient_type, recipient_id, type, title, message, related_id, cause, attempts, status,
+	next_attempt_at, created_at, updated_at
+`
+
+type NotificationFailureRepository struct {
+	*Repository
+}
+
+func NewNotificationFailureRepository(repository *Repository) *NotificationFailureRepository {
+	return &NotificationFailureRepository{
+		Repository: repository,
+	}
+}
+
+func (r *NotificationFailureRepository) Create(ctx context.Context, failure *domain.NotificationFailure) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO notification_failures (
+			id, recipient_type, recipient_id, type, title, message, related_id, cause, attempts, status,
+			next_attempt_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $12)
+	`
+
+	if failure.ID == "" {
+		failure.ID = r.ids.NewID()
+	}
+	if failure.CreatedAt.IsZero() {
+		failure.CreatedAt = time.Now()
+	}
+	failure.UpdatedAt = failure.CreatedAt
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		failure.ID,
+		failure.RecipientType,
+		failure.RecipientID,
+		failure.NotificationType,
+		failure.Title,
+		failure.Message,
+		failure.RelatedID,
+		failure.Cause,
+		failure.Attempts,
+		failure.Status,
+		failure.NextAttemptAt,
+		failure.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateNotificationFailure,
+			zap.String("recipientType", string(failure.RecipientType)),
+			zap.String("recipientID", failure.RecipientID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateNotificationFailure, err)
+	}
+
+	return nil
+}
+
+// Update stores the outcome of a retry: the attempts, cause, status and next attempt.
+func (r *NotificationFailureRepository) Update(ctx context.Context, failure *domain.NotificationFailure) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE notification_failures
+		SET attempts = $2, cause = $3, status = $4, next_attempt_at = $5, updated_at = $6
+		WHERE id::text = $1
+	`
+
+	failure.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		failure.ID,
+		failure.Attempts,
+		failure.Cause,
+		failure.Status,
+		failure.NextAttemptAt,
+		failure.UpdatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateNotificationFailure, zap.String("id", failure.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateNotificationFailure, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrNotificationFailureNotFound)
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit pending failures due at now, oldest due first, and moves their next
+// attempt to leaseUntil, so that concurrent retries skip them; a retry that never reports back
+// leaves the failure due again at leaseUntil.
+func (r *NotificationFailureRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.NotificationFailure, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		UPDATE notification_failures
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM notification_failures
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationFailureColumns
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrClaimNotificationFailures, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimNotificationFailures, err)
+	}
+
+	failures, err := collectNotificationFailures(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimNotificationFailures, err)
+	}
+
+	return failures, nil
+}
+
+// List returns the failures with the status, or of every status when it is empty, newest first.
+func (r *NotificationFailureRepository) List(ctx context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + notificationFailureColumns + `
+		FROM notification_failures
+		WHERE $1::text = '' OR status = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, string(status), limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListNotificationFailures, zap.String("status", string(status)), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListNotificationFailures, err)
+	}
+
+	failures, err := collectNotificationFailures(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListNotificationFailures, err)
+	}
+
+	return failures, nil
+}
+
+func (r *NotificationFailureRepository) CountByStatus(ctx context.Context) (map[domain.NotificationFailureStatus]int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT status, COUNT(*) FROM notification_failures GROUP BY status
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		log.Error(ctx, common.ErrCountNotificationFailures, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrCountNotificationFailures, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.NotificationFailureStatus]int)
+	for rows.Next() {
+		var (
+			status domain.NotificationFailureStatus
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrCountNotificationFailures, err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrCountNotificationFailures, err)
+	}
+
+	return counts, nil
+}
+
+func collectNotificationFailures(rows pgx.Rows) ([]*domain.NotificationFailure, error) {
+	defer rows.Close()
+
+	failures := make([]*domain.NotificationFailure, 0)
+	for rows.Next() {
+		var failure domain.NotificationFailure
+		err := rows.Scan(
+			&failure.ID,
+			&failure.RecipientType,
+			&failure.RecipientID,
+			&failure.NotificationType,
+			&failure.Title,
+			&failure.Message,
+			&failure.RelatedID,
+			&failure.Cause,
+			&failure.Attempts,
+			&failure.Status,
+			&failure.NextAttemptAt,
+			&failure.CreatedAt,
+			&failure.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		failures = append(failures, &failure)
+	}
+
+	return failures, rows.Err()
+}
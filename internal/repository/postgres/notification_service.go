@@ -3,19 +3,22 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"regexp"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
 
 	"go.uber.org/zap"
 )
 
 type NotificationService struct {
-	repo *NotificationRepository
+	repo repository.NotificationRepository
 }
 
-func NewNotificationService(repo *NotificationRepository) domain.NotificationService {
+func NewNotificationService(repo repository.NotificationRepository) domain.NotificationService {
 	return &NotificationService{
 		repo: repo,
 	}
@@ -59,6 +62,10 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID s
 	return s.repo.GetByUserID(ctx, userID)
 }
 
+func (s *NotificationService) GetNotification(ctx context.Context, notificationID string) (*domain.Notification, error) {
+	return s.repo.GetByID(ctx, notificationID)
+}
+
 func (s *NotificationService) MarkAsRead(ctx context.Context, notificationID string) error {
 	return s.repo.MarkAsRead(ctx, notificationID)
 }
@@ -73,3 +80,25 @@ func (s *MockEmailService) SendEmail(to, subject, body string) error {
 	fmt.Printf("[MOCK EMAIL] To: %s, Subject: %s, Body: %s\n", to, subject, body)
 	return nil
 }
+
+// linkPath matches the path of every link in a message, which can carry a signed booking-link token.
+var linkPath = regexp.MustCompile(`(https?://[^/\s]+)/\S*`)
+
+// MockSMSService logs the messages instead of sending them, with the paths of their links
+// redacted so that the log does not hand out the booking links they carry.
+type MockSMSService struct {
+	log ports.LoggerPort
+}
+
+func NewMockSMSService(log ports.LoggerPort) *MockSMSService {
+	return &MockSMSService{
+		log: log,
+	}
+}
+
+func (s *MockSMSService) SendSMS(to, body string) error {
+	s.log.Info(context.Background(), "mock SMS",
+		zap.String("to", to),
+		zap.String("body", linkPath.ReplaceAllString(body, "$1/[REDACTED]")))
+	return nil
+}
@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type NotificationSettingsRepository struct {
+	*Repository
+}
+
+func NewNotificationSettingsRepository(repository *Repository) *NotificationSettingsRepository {
+	return &NotificationSettingsRepository{
+		Repository: repository,
+	}
+}
+
+// Get returns the stored preferences and quiet hours of the recipient; a recipient without any gets
+// empty settings.
+func (r *NotificationSettingsRepository) Get(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT type, channel, enabled
+		FROM notification_preferences
+		WHERE recipient_type = $1 AND recipient_id = $2
+		ORDER BY type, channel
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, recipientType, recipientID)
+	if err != nil {
+		log.Error(ctx, common.ErrGetNotificationSettings,
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", recipientID),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetNotificationSettings, err)
+	}
+	defer rows.Close()
+
+	settings := &domain.NotificationSettings{
+		RecipientType: recipientType,
+		RecipientID:   recipientID,
+		Preferences:   make([]domain.NotificationPreference, 0),
+	}
+	for rows.Next() {
+		var p domain.NotificationPreference
+		if err := rows.Scan(&p.Type, &p.Channel, &p.Enabled); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrGetNotificationSettings, err)
+		}
+		settings.Preferences = append(settings.Preferences, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetNotificationSettings, err)
+	}
+
+	settings.QuietHours, err = r.getQuietHours(ctx, executor, recipientType, recipientID)
+	if err != nil {
+		log.Error(ctx, common.ErrGetNotificationSettings,
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", recipientID),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetNotificationSettings, err)
+	}
+
+	return settings, nil
+}
+
+func (r *NotificationSettingsRepository) getQuietHours(
+	ctx context.Context,
+	executor DBExecutor,
+	recipientType domain.RecipientType,
+	recipientID string,
+) ([]domain.QuietHours, error) {
+	const query = `
+		SELECT channel, start_time, end_time, snoozed_until
+		FROM notification_quiet_hours
+		WHERE recipient_type = $1 AND recipient_id = $2
+		ORDER BY channel
+	`
+
+	rows, err := executor.Query(ctx, query, recipientType, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quietHours := make([]domain.QuietHours, 0)
+	for rows.Next() {
+		var q domain.QuietHours
+		if err := rows.Scan(&q.Channel, &q.Start, &q.End, &q.SnoozedUntil); err != nil {
+			return nil, err
+		}
+		quietHours = append(quietHours, q)
+	}
+
+	return quietHours, rows.Err()
+}
+
+func (r *NotificationSettingsRepository) Save(ctx context.Context, settings *domain.NotificationSettings) error {
+	log, _ := logger.FromContext(ctx)
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const deleteQuery = `
+			DELETE FROM notification_preferences
+			WHERE recipient_type = $1 AND recipient_id = $2
+		`
+		if _, err := tx.Exec(ctx, deleteQuery, settings.RecipientType, settings.RecipientID); err != nil {
+			return err
+		}
+
+		if err := saveQuietHours(ctx, tx, settings); err != nil {
+			return err
+		}
+
+		if len(settings.Preferences) == 0 {
+			return nil
+		}
+
+		const insertQuery = `
+			INSERT INTO notification_preferences (recipient_type, recipient_id, type, channel, enabled)
+			SELECT $1, $2, p.type, p.channel, p.enabled
+			FROM unnest($3::text[], $4::text[], $5::boolean[]) AS p(type, channel, enabled)
+		`
+
+		types := make([]string, len(settings.Preferences))
+		channels := make([]string, len(settings.Preferences))
+		enabled := make([]bool, len(settings.Preferences))
+		for i, p := range settings.Preferences {
+			types[i] = string(p.Type)
+			channels[i] = string(p.Channel)
+			enabled[i] = p.Enabled
+		}
+
+		_, err := tx.Exec(ctx, insertQuery, settings.RecipientType, settings.RecipientID, types, channels, enabled)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrSaveNotificationSettings,
+			zap.String("recipientType", string(settings.RecipientType)),
+			zap.String("recipientID", settings.RecipientID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveNotificationSettings, err)
+	}
+
+	return nil
+}
+
+// saveQuietHours replaces the quiet hours of the recipient within the transaction of Save.
+func saveQuietHours(ctx context.Context, tx pgx.Tx, settings *domain.NotificationSettings) error {
+	const deleteQuery = `
+		DELETE FROM notification_quiet_hours
+		WHERE recipient_type = $1 AND recipient_id = $2
+	`
+	if _, err := tx.Exec(ctx, deleteQuery, settings.RecipientType, settings.RecipientID); err != nil {
+		return err
+	}
+
+	if len(settings.QuietHours) == 0 {
+		return nil
+	}
+
+	const insertQuery = `
+		INSERT INTO notification_quiet_hours (recipient_type, recipient_id, channel, start_time, end_time, snoozed_until)
+		SELECT $1, $2, q.channel, q.start_time, q.end_time, q.snoozed_until
+		FROM unnest($3::text[], $4::text[], $5::text[], $6::timestamptz[]) AS q(channel, start_time, end_time, snoozed_until)
+	`
+
+	channels := make([]string, len(settings.QuietHours))
+	starts := make([]string, len(settings.QuietHours))
+	ends := make([]string, len(settings.QuietHours))
+	snoozes := make([]*time.Time, len(settings.QuietHours))
+	for i, q := range settings.QuietHours {
+		channels[i] = string(q.Channel)
+		starts[i] = q.Start
+		ends[i] = q.End
+		snoozes[i] = q.SnoozedUntil
+	}
+
+	_, err := tx.Exec(ctx, insertQuery, settings.RecipientType, settings.RecipientID, channels, starts, ends, snoozes)
+	return err
+}
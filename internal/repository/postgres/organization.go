@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type OrganizationRepository struct {
+	*Repository
+}
+
+func NewOrganizationRepository(repository *Repository) *OrganizationRepository {
+	return &OrganizationRepository{
+		Repository: repository,
+	}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO organizations (id, name, created_at)
+		VALUES ($1, $2, $3)
+	`
+
+	if organization.ID == "" {
+		organization.ID = r.ids.NewID()
+	}
+	organization.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	if _, err := executor.Exec(ctx, query, organization.ID, organization.Name, organization.CreatedAt); err != nil {
+		log.Error(ctx, common.ErrCreateOrganization, zap.String("name", organization.Name), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateOrganization, err)
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) AddRestaurant(ctx context.Context, organizationID, restaurantID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const existsQuery = `
+		SELECT
+			EXISTS (SELECT 1 FROM organizations WHERE id::text = $1),
+			EXISTS (SELECT 1 FROM restaurants WHERE id::text = $2)
+	`
+	const query = `
+		INSERT INTO organization_restaurants (restaurant_id, organization_id, added_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (restaurant_id) DO UPDATE
+		SET organization_id = EXCLUDED.organization_id, added_at = EXCLUDED.added_at
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	var organizationExists, restaurantExists bool
+	if err := executor.QueryRow(ctx, existsQuery, organizationID, restaurantID).Scan(&organizationExists, &restaurantExists); err != nil {
+		log.Error(ctx, common.ErrAddOrganizationRestaurant,
+			zap.String("organizationID", organizationID),
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrAddOrganizationRestaurant, err)
+	}
+	if !organizationExists {
+		return errors.New(common.ErrOrganizationNotFound)
+	}
+	if !restaurantExists {
+		return errors.New(common.ErrRestaurantNotFound)
+	}
+
+	if _, err := executor.Exec(ctx, query, restaurantID, organizationID); err != nil {
+		log.Error(ctx, common.ErrAddOrganizationRestaurant,
+			zap.String("organizationID", organizationID),
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrAddOrganizationRestaurant, err)
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) GetByRestaurant(ctx context.Context, restaurantID string) (*domain.Organization, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT o.id, o.name, o.created_at
+		FROM organizations o
+		JOIN organization_restaurants m ON m.organization_id = o.id
+		WHERE m.restaurant_id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var organization domain.Organization
+	err = executor.QueryRow(ctx, query, restaurantID).Scan(&organization.ID, &organization.Name, &organization.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrOrganizationNotFound)
+		}
+		log.Error(ctx, common.ErrGetOrganization, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetOrganization, err)
+	}
+
+	return &organization, nil
+}
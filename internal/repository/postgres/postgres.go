@@ -9,11 +9,17 @@ import (
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
 )
 
+// copyChunkSize is how many rows one COPY of a bulk insert streams.
+const copyChunkSize = 1000
+
 type DBExecutor interface {
 	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
@@ -22,15 +28,23 @@ type DBExecutor interface {
 
 type Repository struct {
 	pool postgres.Pool
+	ids  idgen.Generator
 }
 
-func NewRepository(pool postgres.Pool) *Repository {
+// NewRepository creates the base of the repositories; ids generates the IDs of the records they
+// create without one.
+func NewRepository(pool postgres.Pool, ids idgen.Generator) *Repository {
 	return &Repository{
 		pool: pool,
+		ids:  ids,
 	}
 }
 
 func (r *Repository) GetExecutor(ctx context.Context) (DBExecutor, func(), error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx, func() {}, nil
+	}
+
 	conn, err := r.pool.Acquire(ctx)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s: %w", common.ErrAcquireConnection, err)
@@ -57,6 +71,10 @@ func (r *Repository) GetPool() postgres.Pool {
 }
 
 func (r *Repository) WithTransaction(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if outer, ok := txFromContext(ctx); ok {
+		return withSavepoint(ctx, outer, fn)
+	}
+
 	_, release, err := r.GetExecutor(ctx)
 	if err != nil {
 		return fmt.Errorf("%s: %w", common.ErrGetQueryExecutor, err)
@@ -93,3 +111,51 @@ func (r *Repository) WithTransaction(ctx context.Context, fn func(tx pgx.Tx) err
 
 	return nil
 }
+
+// withSavepoint runs fn in a savepoint of the transaction the context already carries,
+// so repositories keep their own atomicity inside a Transactor transaction.
+func withSavepoint(ctx context.Context, outer pgx.Tx, fn func(tx pgx.Tx) error) error {
+	tx, err := outer.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrBeginTransaction, err)
+	}
+
+	if err := fn(tx); err != nil {
+		rbErr := tx.Rollback(ctx)
+		if rbErr != nil {
+			return fmt.Errorf("%s: %v, original error: %w", common.ErrRollbackTransaction, rbErr, err)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", common.ErrCommitTransaction, err)
+	}
+
+	return nil
+}
+
+// copyRows inserts the rows into the table with COPY, which is much faster than INSERT for
+// many rows. They are copied in chunks of copyChunkSize within one transaction and the progress
+// is logged after every chunk.
+func (r *Repository) copyRows(ctx context.Context, table string, columns []string, rows [][]any) error {
+	log, _ := logger.FromContext(ctx)
+
+	return r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		copied := 0
+		for start := 0; start < len(rows); start += copyChunkSize {
+			chunk := rows[start:min(start+copyChunkSize, len(rows))]
+			count, err := tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(chunk))
+			if err != nil {
+				return err
+			}
+
+			copied += int(count)
+			log.Info(ctx, "bulk insert progress",
+				zap.String("table", table),
+				zap.Int("copied", copied),
+				zap.Int("total", len(rows)))
+		}
+		return nil
+	})
+}
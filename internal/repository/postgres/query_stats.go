@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// pgObjectNotInPrerequisiteState is raised by pg_stat_statements when the extension is created
+// but the library is missing from shared_preload_libraries.
+const pgObjectNotInPrerequisiteState = "55000"
+
+type QueryStatsRepository struct {
+	*Repository
+}
+
+func NewQueryStatsRepository(repository *Repository) *QueryStatsRepository {
+	return &QueryStatsRepository{
+		Repository: repository,
+	}
+}
+
+func (r *QueryStatsRepository) ListSeqScanTables(ctx context.Context, minRows int64, limit int) ([]domain.SeqScanTable, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT relname, seq_scan, seq_tup_read, COALESCE(idx_scan, 0), n_live_tup
+		FROM pg_stat_user_tables
+		WHERE n_live_tup >= $1 AND seq_scan > COALESCE(idx_scan, 0)
+		ORDER BY seq_tup_read DESC, relname
+		LIMIT $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, minRows, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListSeqScanTables, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSeqScanTables, err)
+	}
+	defer rows.Close()
+
+	tables := make([]domain.SeqScanTable, 0)
+	for rows.Next() {
+		var table domain.SeqScanTable
+		if err := rows.Scan(&table.Name, &table.SeqScans, &table.SeqRowsRead, &table.IndexScans, &table.LiveRows); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSeqScanTables, err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSeqScanTables, err)
+	}
+
+	return tables, nil
+}
+
+// ListScanHeavyQueries reads the statements of the current database from pg_stat_statements,
+// leaving out the ones reading the statistics themselves.
+func (r *QueryStatsRepository) ListScanHeavyQueries(ctx context.Context, minCalls int64, limit int) ([]domain.ScanHeavyQuery, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const availableQuery = `SELECT to_regclass('pg_stat_statements') IS NOT NULL`
+
+	const query = `
+		SELECT queryid, query, calls, rows, shared_blks_hit + shared_blks_read,
+			(shared_blks_hit + shared_blks_read)::FLOAT8 / GREATEST(rows, 1), mean_exec_time
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+			AND calls >= $1
+			AND query NOT ILIKE '%pg_stat%'
+		ORDER BY 6 DESC, queryid
+		LIMIT $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var available bool
+	if err := executor.QueryRow(ctx, availableQuery).Scan(&available); err != nil {
+		log.Error(ctx, common.ErrListScanHeavyQueries, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListScanHeavyQueries, err)
+	}
+	if !available {
+		return nil, errors.New(common.ErrQueryStatsUnavailable)
+	}
+
+	rows, err := executor.Query(ctx, query, minCalls, limit)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgObjectNotInPrerequisiteState {
+			return nil, errors.New(common.ErrQueryStatsUnavailable)
+		}
+		log.Error(ctx, common.ErrListScanHeavyQueries, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListScanHeavyQueries, err)
+	}
+	defer rows.Close()
+
+	queries := make([]domain.ScanHeavyQuery, 0)
+	for rows.Next() {
+		var q domain.ScanHeavyQuery
+		if err := rows.Scan(&q.QueryID, &q.Query, &q.Calls, &q.Rows, &q.Blocks, &q.BlocksPerRow, &q.MeanTimeMs); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListScanHeavyQueries, err)
+		}
+		queries = append(queries, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgObjectNotInPrerequisiteState {
+			return nil, errors.New(common.ErrQueryStatsUnavailable)
+		}
+		return nil, fmt.Errorf("%s: %w", common.ErrListScanHeavyQueries, err)
+	}
+
+	return queries, nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type QuotaRepository struct {
+	*Repository
+}
+
+func NewQuotaRepository(repository *Repository) *QuotaRepository {
+	return &QuotaRepository{
+		Repository: repository,
+	}
+}
+
+// GetPlan returns the plan of the restaurant, the free plan without overrides when none was set.
+func (r *QuotaRepository) GetPlan(ctx context.Context, restaurantID string) (*domain.RestaurantPlan, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT plan, max_active_slots, max_monthly_bookings, note, updated_at
+		FROM restaurant_plans
+		WHERE restaurant_id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	plan := &domain.RestaurantPlan{RestaurantID: restaurantID}
+	err = executor.QueryRow(ctx, query, restaurantID).Scan(
+		&plan.Plan,
+		&plan.ActiveSlots,
+		&plan.MonthlyBookings,
+		&plan.Note,
+		&plan.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &domain.RestaurantPlan{RestaurantID: restaurantID, Plan: domain.PlanFree}, nil
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrGetRestaurantPlan, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantPlan, err)
+	}
+
+	return plan, nil
+}
+
+func (r *QuotaRepository) SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_plans (restaurant_id, plan, max_active_slots, max_monthly_bookings, note, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (restaurant_id) DO UPDATE
+		SET plan = EXCLUDED.plan,
+			max_active_slots = EXCLUDED.max_active_slots,
+			max_monthly_bookings = EXCLUDED.max_monthly_bookings,
+			note = EXCLUDED.note,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	plan.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		plan.RestaurantID,
+		plan.Plan,
+		plan.ActiveSlots,
+		plan.MonthlyBookings,
+		plan.Note,
+		plan.UpdatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSetRestaurantPlan, zap.String("restaurantID", plan.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSetRestaurantPlan, err)
+	}
+
+	return nil
+}
+
+// CountActiveSlots counts the availability slots of the restaurant on today and later.
+func (r *QuotaRepository) CountActiveSlots(ctx context.Context, restaurantID string, today time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COUNT(*) FROM availability WHERE restaurant_id::text = $1 AND date >= $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	var count int
+	if err := executor.QueryRow(ctx, query, restaurantID, today.Format("2006-01-02")).Scan(&count); err != nil {
+		log.Error(ctx, common.ErrCountQuotaUsage, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrCountQuotaUsage, err)
+	}
+
+	return count, nil
+}
+
+// CountBookings counts the bookings requested at the restaurant from from up to to, exclusive,
+// test bookings aside.
+func (r *QuotaRepository) CountBookings(ctx context.Context, restaurantID string, from, to time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COUNT(*) FROM bookings
+		WHERE restaurant_id::text = $1 AND created_at >= $2 AND created_at < $3 AND NOT is_test
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	var count int
+	if err := executor.QueryRow(ctx, query, restaurantID, from, to).Scan(&count); err != nil {
+		log.Error(ctx, common.ErrCountQuotaUsage, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrCountQuotaUsage, err)
+	}
+
+	return count, nil
+}
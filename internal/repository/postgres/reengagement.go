@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+type ReengagementRepository struct {
+	*Repository
+}
+
+func NewReengagementRepository(repository *Repository) *ReengagementRepository {
+	return &ReengagementRepository{
+		Repository: repository,
+	}
+}
+
+// ListLapsed picks as the restaurant of a guest the one with most completed visits, the most
+// recently visited of them on a tie.
+func (r *ReengagementRepository) ListLapsed(ctx context.Context, lastVisit, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	const query = `
+		WITH visits AS (
+			SELECT b.user_id, b.restaurant_id, COUNT(*) AS visits, MAX(b.date) AS last_visit
+			FROM bookings b
+			JOIN restaurants r ON r.id = b.restaurant_id
+			WHERE b.status = 'completed' AND NOT b.is_test AND NOT r.is_test
+			GROUP BY b.user_id, b.restaurant_id
+		), favorites AS (
+			SELECT DISTINCT ON (user_id) user_id, restaurant_id,
+				   MAX(last_visit) OVER (PARTITION BY user_id) AS last_visit
+			FROM visits
+			ORDER BY user_id, visits DESC, last_visit DESC
+		)
+		SELECT f.user_id, f.restaurant_id, r.name, f.last_visit
+		FROM favorites f
+		JOIN restaurants r ON r.id = f.restaurant_id
+		WHERE f.last_visit = $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM bookings b
+			WHERE b.user_id = f.user_id AND b.status IN ('pending', 'confirmed') AND b.date >= $2
+		  )
+		ORDER BY f.user_id
+	`
+
+	return r.listCandidates(ctx, query, domain.ReengagementReasonLapsed, lastVisit, today)
+}
+
+func (r *ReengagementRepository) ListAnniversaries(ctx context.Context, visitDate, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	const query = `
+		SELECT DISTINCT ON (b.user_id) b.user_id, b.restaurant_id, r.name, b.date
+		FROM bookings b
+		JOIN restaurants r ON r.id = b.restaurant_id
+		WHERE b.status = 'completed' AND b.occasion = 'anniversary' AND b.date = $1
+		  AND NOT b.is_test AND NOT r.is_test
+		  AND NOT EXISTS (
+			SELECT 1 FROM bookings a
+			WHERE a.user_id = b.user_id AND a.status IN ('pending', 'confirmed') AND a.date >= $2
+		  )
+		ORDER BY b.user_id, b.restaurant_id
+	`
+
+	return r.listCandidates(ctx, query, domain.ReengagementReasonAnniversary, visitDate, today)
+}
+
+func (r *ReengagementRepository) listCandidates(
+	ctx context.Context,
+	query string,
+	reason domain.ReengagementReason,
+	visitDate, today time.Time,
+) ([]*domain.ReengagementCandidate, error) {
+	log, _ := logger.FromContext(ctx)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, visitDate.Format("2006-01-02"), today.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListReengagementCandidates,
+			zap.String("reason", string(reason)),
+			zap.Time("visitDate", visitDate),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListReengagementCandidates, err)
+	}
+	defer rows.Close()
+
+	candidates := make([]*domain.ReengagementCandidate, 0)
+	for rows.Next() {
+		candidate := &domain.ReengagementCandidate{Reason: reason}
+		if err := rows.Scan(&candidate.UserID, &candidate.RestaurantID, &candidate.RestaurantName, &candidate.VisitDate); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListReengagementCandidates, err)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListReengagementCandidates, err)
+	}
+
+	return candidates, nil
+}
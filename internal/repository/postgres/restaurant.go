@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"strings"
 	"time"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
@@ -25,14 +26,14 @@ func NewRestaurantRepository(repository *Repository) *RestaurantRepository {
 	}
 }
 
-func (r *RestaurantRepository) GetByID(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (r *RestaurantRepository) GetByID(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	logger, err := logger.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurant, err)
 	}
 
 	const query = `
-		SELECT id, name, address, cuisine, description, created_at, updated_at, contact_email, contact_phone
+		SELECT id, name, slug, address, cuisine, description, created_at, updated_at, contact_email, contact_phone, is_test, currency, normalized_contact_email, normalized_contact_phone, is_adult_only
 		FROM restaurants
 		WHERE id = $1
 	`
@@ -44,11 +45,12 @@ func (r *RestaurantRepository) GetByID(ctx context.Context, id string) (*domain.
 	}
 	defer release()
 
-	row := executor.QueryRow(ctx, query, id)
+	row := executor.QueryRow(ctx, query, id.String())
 	var restaurant domain.Restaurant
 	err = row.Scan(
 		&restaurant.ID,
 		&restaurant.Name,
+		&restaurant.Slug,
 		&restaurant.Address,
 		&restaurant.Cuisine,
 		&restaurant.Description,
@@ -56,13 +58,18 @@ func (r *RestaurantRepository) GetByID(ctx context.Context, id string) (*domain.
 		&restaurant.UpdatedAt,
 		&restaurant.ContactEmail,
 		&restaurant.ContactPhone,
+		&restaurant.IsTest,
+		&restaurant.Currency,
+		&restaurant.NormalizedContactEmail,
+		&restaurant.NormalizedContactPhone,
+		&restaurant.IsAdultOnly,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, errors.New(common.ErrRestaurantNotFound)
 		}
 		logger.Error(ctx, common.ErrScanRestaurant,
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", common.ErrScanRestaurant, err)
 	}
@@ -70,7 +77,7 @@ func (r *RestaurantRepository) GetByID(ctx context.Context, id string) (*domain.
 	facts, err := r.GetFacts(ctx, id)
 	if err != nil {
 		logger.Warn(ctx, common.ErrGetRestaurantFacts,
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Error(err))
 	}
 	restaurant.Facts = facts
@@ -78,16 +85,169 @@ func (r *RestaurantRepository) GetByID(ctx context.Context, id string) (*domain.
 	return &restaurant, nil
 }
 
-func (r *RestaurantRepository) List(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
-	log, _ := logger.FromContext(ctx)
+// ListAfter compares the name and ID of the rows with the cursor as a row value, which the index on
+// both reads in order from the cursor on. The first page is a statement of its own: a cursor
+// condition that is switched off by a null argument keeps a generic plan of the statement from
+// using the index.
+func (r *RestaurantRepository) ListAfter(ctx context.Context, filter domain.RestaurantFilter, after domain.RestaurantCursor, limit int) ([]*domain.Restaurant, error) {
+	query := `
+		SELECT r.id, r.name, r.slug, r.address, r.cuisine, r.description, r.created_at, r.updated_at, r.contact_email, r.contact_phone, r.is_test, r.currency, r.normalized_contact_email, r.normalized_contact_phone, r.is_adult_only
+		FROM restaurants r
+		LEFT JOIN restaurant_locations l ON l.restaurant_id = r.id
+		WHERE ($2::text = '' OR l.city_id::text = $2)
+		  AND ($3::boolean IS NULL OR r.is_adult_only = $3)`
+	args := []any{limit, filter.CityID, filter.AdultOnly}
+	if !after.IsZero() {
+		query += `
+		  AND (r.name, r.id) > ($4, $5::uuid)`
+		args = append(args, after.Name, after.ID)
+	}
+	query += `
+		ORDER BY r.name, r.id
+		LIMIT $1
+	`
 
+	return r.queryRestaurants(ctx, query, args...)
+}
+
+// ListLive returns a page of the restaurants by name, without test restaurants.
+func (r *RestaurantRepository) ListLive(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
 	const query = `
-		SELECT id, name, address, cuisine, description, created_at, updated_at, contact_email, contact_phone
+		SELECT id, name, slug, address, cuisine, description, created_at, updated_at, contact_email, contact_phone, is_test, currency, normalized_contact_email, normalized_contact_phone, is_adult_only
 		FROM restaurants
+		WHERE NOT is_test
 		ORDER BY name
 		LIMIT $1 OFFSET $2
 	`
 
+	return r.list(ctx, query, offset, limit)
+}
+
+// ListByCity returns a page of the restaurants whose address was placed in the city.
+func (r *RestaurantRepository) ListByCity(ctx context.Context, cityID string, offset, limit int) ([]*domain.Restaurant, error) {
+	const query = `
+		SELECT r.id, r.name, r.slug, r.address, r.cuisine, r.description, r.created_at, r.updated_at, r.contact_email, r.contact_phone, r.is_test, r.currency, r.normalized_contact_email, r.normalized_contact_phone, r.is_adult_only
+		FROM restaurants r
+		JOIN restaurant_locations l ON l.restaurant_id = r.id
+		WHERE l.city_id::text = $3
+		ORDER BY r.name
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.list(ctx, query, offset, limit, cityID)
+}
+
+// Search returns a page of the restaurants matching the filter, the best matches of its query
+// first. The query and address are matched against the search_vector column and the address
+// expression index, both with the language-neutral simple configuration. A restaurant is open at
+// OpenAt by hours of its day that started by then, or of the day before that run past midnight.
+// The hours of a day are resolved as domain.ScheduleOn does: none during a closure, else those of
+// a special day on it, else the working hours of its weekday.
+func (r *RestaurantRepository) Search(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	const query = `
+		SELECT r.id, r.name, r.slug, r.address, r.cuisine, r.description, r.created_at, r.updated_at, r.contact_email, r.contact_phone, r.is_test, r.currency, r.normalized_contact_email, r.normalized_contact_phone, r.is_adult_only
+		FROM restaurants r
+		LEFT JOIN restaurant_locations l ON l.restaurant_id = r.id
+		WHERE ($3::text = '' OR l.city_id::text = $3)
+		  AND ($4::boolean IS NULL OR r.is_adult_only = $4)
+		  AND ($5::text = '' OR r.search_vector @@ websearch_to_tsquery('simple', $5))
+		  AND ($6::text = '' OR lower(r.cuisine) = lower($6))
+		  AND ($7::text = '' OR to_tsvector('simple', r.address) @@ plainto_tsquery('simple', $7))
+		  AND (NOT $8::boolean OR NOT r.is_test)
+		  AND ($9::date IS NULL OR EXISTS (
+		      SELECT 1
+		      FROM (VALUES ($9::date, $10::int, TRUE), ($9::date - 1, $11::int, FALSE)) AS d(day, week_day, is_today)
+		      CROSS JOIN LATERAL (
+		          SELECT sd.open_time, sd.close_time
+		          FROM restaurant_special_days sd
+		          WHERE sd.restaurant_id = r.id AND sd.date = d.day AND NOT sd.is_closed
+		          UNION ALL
+		          SELECT wh.open_time, wh.close_time
+		          FROM working_hours wh
+		          WHERE wh.restaurant_id = r.id AND NOT wh.is_closed AND wh.week_day = d.week_day
+		            AND wh.valid_from::date <= d.day AND (wh.valid_to IS NULL OR wh.valid_to::date >= d.day)
+		            AND NOT EXISTS (
+		                SELECT 1 FROM restaurant_special_days sd WHERE sd.restaurant_id = r.id AND sd.date = d.day
+		            )
+		      ) h
+		      WHERE NOT EXISTS (
+		          SELECT 1
+		          FROM restaurant_closures c
+		          WHERE c.restaurant_id = r.id AND c.starts_on <= d.day AND c.reopens_on > d.day
+		      )
+		        AND CASE WHEN d.is_today
+		            THEN h.open_time <= $12 AND (h.close_time > $12 OR h.close_time <= h.open_time)
+		            ELSE h.close_time <= h.open_time AND h.close_time > $12
+		        END
+		  ))
+		ORDER BY CASE WHEN $5::text = '' THEN 0 ELSE ts_rank(r.search_vector, websearch_to_tsquery('simple', $5)) END DESC, r.name
+		LIMIT $1 OFFSET $2
+	`
+
+	var (
+		openDate             *time.Time
+		openDay, previousDay domain.WeekDay
+		openTime             string
+	)
+	if filter.OpenAt != nil {
+		date := time.Date(filter.OpenAt.Year(), filter.OpenAt.Month(), filter.OpenAt.Day(), 0, 0, 0, 0, time.UTC)
+		openDate = &date
+		openDay = domain.WeekDayOf(date)
+		previousDay = domain.WeekDayOf(date.AddDate(0, 0, -1))
+		openTime = filter.OpenAt.Format("15:04")
+	}
+
+	return r.list(ctx, query, offset, limit, filter.CityID, filter.AdultOnly, filter.Query, string(filter.Cuisine),
+		filter.Address, filter.LiveOnly, openDate, int(openDay), int(previousDay), openTime)
+}
+
+// ListSisters returns a page of the other restaurants of the organization the restaurant
+// belongs to.
+func (r *RestaurantRepository) ListSisters(ctx context.Context, restaurantID domain.RestaurantID, offset, limit int) ([]*domain.Restaurant, error) {
+	const query = `
+		SELECT r.id, r.name, r.slug, r.address, r.cuisine, r.description, r.created_at, r.updated_at, r.contact_email, r.contact_phone, r.is_test, r.currency, r.normalized_contact_email, r.normalized_contact_phone, r.is_adult_only
+		FROM restaurants r
+		JOIN organization_restaurants sister ON sister.restaurant_id = r.id
+		JOIN organization_restaurants own ON own.organization_id = sister.organization_id
+		WHERE own.restaurant_id::text = $3 AND r.id <> own.restaurant_id
+		ORDER BY r.name
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.list(ctx, query, offset, limit, restaurantID.String())
+}
+
+// EstimateCount reads the row count of the restaurants table kept by ANALYZE and autovacuum in
+// pg_class, which is -1 until the table was first analyzed.
+func (r *RestaurantRepository) EstimateCount(ctx context.Context) (int64, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `SELECT reltuples::BIGINT FROM pg_class WHERE oid = 'restaurants'::regclass`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	var count int64
+	if err := executor.QueryRow(ctx, query).Scan(&count); err != nil {
+		log.Error(ctx, common.ErrEstimateRestaurants, zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrEstimateRestaurants, err)
+	}
+
+	return count, nil
+}
+
+// list runs a query taking the limit and offset as $1 and $2 and args from $3 on.
+func (r *RestaurantRepository) list(ctx context.Context, query string, offset, limit int, args ...any) ([]*domain.Restaurant, error) {
+	return r.queryRestaurants(ctx, query, append([]any{limit, offset}, args...)...)
+}
+
+func (r *RestaurantRepository) queryRestaurants(ctx context.Context, query string, args ...any) ([]*domain.Restaurant, error) {
+	log, _ := logger.FromContext(ctx)
+
 	executor, release, err := r.GetExecutor(ctx)
 	if err != nil {
 		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
@@ -95,19 +255,20 @@ func (r *RestaurantRepository) List(ctx context.Context, offset, limit int) ([]*
 	}
 	defer release()
 
-	rows, err := executor.Query(ctx, query, limit, offset)
+	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
 		log.Error(ctx, common.ErrExecuteRestaurantsQuery, zap.Error(err))
 		return nil, err
 	}
 	defer rows.Close()
 
-	restaurants := make([]*domain.Restaurant, 0, limit)
+	restaurants := make([]*domain.Restaurant, 0)
 	for rows.Next() {
 		var restaurant domain.Restaurant
 		err = rows.Scan(
 			&restaurant.ID,
 			&restaurant.Name,
+			&restaurant.Slug,
 			&restaurant.Address,
 			&restaurant.Cuisine,
 			&restaurant.Description,
@@ -115,6 +276,11 @@ func (r *RestaurantRepository) List(ctx context.Context, offset, limit int) ([]*
 			&restaurant.UpdatedAt,
 			&restaurant.ContactEmail,
 			&restaurant.ContactPhone,
+			&restaurant.IsTest,
+			&restaurant.Currency,
+			&restaurant.NormalizedContactEmail,
+			&restaurant.NormalizedContactPhone,
+			&restaurant.IsAdultOnly,
 		)
 		if err != nil {
 			log.Error(ctx, common.ErrScanRestaurant, zap.Error(err))
@@ -135,12 +301,12 @@ func (r *RestaurantRepository) Create(ctx context.Context, restaurant *domain.Re
 	log, _ := logger.FromContext(ctx)
 
 	const query = `
-		INSERT INTO restaurants (id, name, address, cuisine, description, created_at, updated_at, contact_email, contact_phone)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO restaurants (id, name, slug, address, cuisine, description, created_at, updated_at, contact_email, contact_phone, is_test, currency, normalized_contact_email, normalized_contact_phone, is_adult_only)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`
 
 	if restaurant.ID == "" {
-		restaurant.ID = uuid.New().String()
+		restaurant.ID = r.ids.NewID()
 	}
 
 	now := time.Now()
@@ -157,6 +323,7 @@ func (r *RestaurantRepository) Create(ctx context.Context, restaurant *domain.Re
 	_, err = executor.Exec(ctx, query,
 		restaurant.ID,
 		restaurant.Name,
+		restaurant.Slug,
 		restaurant.Address,
 		restaurant.Cuisine,
 		restaurant.Description,
@@ -164,6 +331,11 @@ func (r *RestaurantRepository) Create(ctx context.Context, restaurant *domain.Re
 		restaurant.UpdatedAt,
 		restaurant.ContactEmail,
 		restaurant.ContactPhone,
+		restaurant.IsTest,
+		restaurant.Currency,
+		restaurant.NormalizedContactEmail,
+		restaurant.NormalizedContactPhone,
+		restaurant.IsAdultOnly,
 	)
 	if err != nil {
 		log.Error(ctx, common.ErrCreateRestaurant,
@@ -175,49 +347,201 @@ func (r *RestaurantRepository) Create(ctx context.Context, restaurant *domain.Re
 	return nil
 }
 
+// CreateBatch inserts all restaurants with COPY.
+func (r *RestaurantRepository) CreateBatch(ctx context.Context, restaurants []*domain.Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+
+	log, _ := logger.FromContext(ctx)
+
+	columns := []string{"id", "name", "slug", "address", "cuisine", "description", "created_at", "updated_at", "contact_email", "contact_phone", "is_test", "currency", "normalized_contact_email", "normalized_contact_phone", "is_adult_only"}
+
+	now := time.Now()
+	rows := make([][]any, 0, len(restaurants))
+	for _, restaurant := range restaurants {
+		if restaurant.ID == "" {
+			restaurant.ID = r.ids.NewID()
+		}
+		restaurant.CreatedAt = now
+		restaurant.UpdatedAt = now
+
+		rows = append(rows, []any{
+			restaurant.ID,
+			restaurant.Name,
+			restaurant.Slug,
+			restaurant.Address,
+			restaurant.Cuisine,
+			restaurant.Description,
+			restaurant.CreatedAt,
+			restaurant.UpdatedAt,
+			restaurant.ContactEmail,
+			restaurant.ContactPhone,
+			restaurant.IsTest,
+			restaurant.Currency,
+			restaurant.NormalizedContactEmail,
+			restaurant.NormalizedContactPhone,
+			restaurant.IsAdultOnly,
+		})
+	}
+
+	if err := r.copyRows(ctx, "restaurants", columns, rows); err != nil {
+		log.Error(ctx, common.ErrCreateRestaurant,
+			zap.Int("count", len(restaurants)),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Update saves the restaurant. When its slug changes, the previous one is kept as a redirect, so
+// links using it keep working.
 func (r *RestaurantRepository) Update(ctx context.Context, restaurant *domain.Restaurant) error {
 	log, _ := logger.FromContext(ctx)
 
-	const query = `
+	const selectSlugQuery = `
+		SELECT slug FROM restaurants WHERE id = $1 FOR UPDATE
+	`
+
+	const updateQuery = `
 		UPDATE restaurants
-		SET name = $2, address = $3, cuisine = $4, description = $5, updated_at = $6, contact_email = $7, contact_phone = $8
+		SET name = $2, slug = $3, address = $4, cuisine = $5, description = $6, updated_at = $7, contact_email = $8, contact_phone = $9, is_test = $10, currency = $11,
+			normalized_contact_email = $12, normalized_contact_phone = $13, is_adult_only = $14
 		WHERE id = $1
 	`
 
+	const deleteRedirectQuery = `
+		DELETE FROM restaurant_slug_redirects WHERE slug = $1
+	`
+
+	const insertRedirectQuery = `
+		INSERT INTO restaurant_slug_redirects (slug, restaurant_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (slug) DO UPDATE SET restaurant_id = EXCLUDED.restaurant_id, created_at = EXCLUDED.created_at
+	`
+
 	restaurant.UpdatedAt = time.Now()
 
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		var previousSlug string
+		if err := tx.QueryRow(ctx, selectSlugQuery, restaurant.ID).Scan(&previousSlug); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errors.New(common.ErrRestaurantNotFound)
+			}
+			return err
+		}
+
+		if restaurant.Slug == "" {
+			restaurant.Slug = previousSlug
+		}
+
+		if _, err := tx.Exec(ctx, updateQuery,
+			restaurant.ID,
+			restaurant.Name,
+			restaurant.Slug,
+			restaurant.Address,
+			restaurant.Cuisine,
+			restaurant.Description,
+			restaurant.UpdatedAt,
+			restaurant.ContactEmail,
+			restaurant.ContactPhone,
+			restaurant.IsTest,
+			restaurant.Currency,
+			restaurant.NormalizedContactEmail,
+			restaurant.NormalizedContactPhone,
+			restaurant.IsAdultOnly,
+		); err != nil {
+			return err
+		}
+
+		if previousSlug == restaurant.Slug {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, deleteRedirectQuery, restaurant.Slug); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, insertRedirectQuery, previousSlug, restaurant.ID, restaurant.UpdatedAt)
+		return err
+	})
+	if err != nil {
+		if err.Error() != common.ErrRestaurantNotFound {
+			log.Error(ctx, common.ErrUpdateRestaurant,
+				zap.String("restaurantID", restaurant.ID),
+				zap.Error(err))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetBySlug finds the restaurant by its current slug or by one it had before. The returned
+// restaurant always carries its current slug.
+func (r *RestaurantRepository) GetBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id FROM (
+			SELECT id, 0 AS priority FROM restaurants WHERE slug = $1
+			UNION ALL
+			SELECT restaurant_id, 1 AS priority FROM restaurant_slug_redirects WHERE slug = $1
+		) s
+		ORDER BY priority
+		LIMIT 1
+	`
+
 	executor, release, err := r.GetExecutor(ctx)
 	if err != nil {
 		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
-		return err
+		return nil, err
 	}
 	defer release()
 
-	commandTag, err := executor.Exec(ctx, query,
-		restaurant.ID,
-		restaurant.Name,
-		restaurant.Address,
-		restaurant.Cuisine,
-		restaurant.Description,
-		restaurant.UpdatedAt,
-		restaurant.ContactEmail,
-		restaurant.ContactPhone,
-	)
-	if err != nil {
-		log.Error(ctx, common.ErrUpdateRestaurant,
-			zap.String("restaurantID", restaurant.ID),
+	var id string
+	if err := executor.QueryRow(ctx, query, slug).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRestaurantNotFound)
+		}
+		log.Error(ctx, common.ErrGetRestaurant,
+			zap.String("slug", slug),
 			zap.Error(err))
-		return err
+		return nil, err
 	}
 
-	if commandTag.RowsAffected() == 0 {
-		return errors.New(common.ErrRestaurantNotFound)
+	return r.GetByID(ctx, domain.RestaurantID(id))
+}
+
+// IsSlugTaken reports whether the slug is used, currently or as a redirect, by a restaurant other
+// than restaurantID.
+func (r *RestaurantRepository) IsSlugTaken(ctx context.Context, slug, restaurantID string) (bool, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT EXISTS(SELECT 1 FROM restaurants WHERE slug = $1 AND id::text <> $2)
+			OR EXISTS(SELECT 1 FROM restaurant_slug_redirects WHERE slug = $1 AND restaurant_id::text <> $2)
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return false, err
 	}
+	defer release()
 
-	return nil
+	var taken bool
+	if err := executor.QueryRow(ctx, query, slug, restaurantID).Scan(&taken); err != nil {
+		log.Error(ctx, common.ErrCheckRestaurantSlug,
+			zap.String("slug", slug),
+			zap.Error(err))
+		return false, err
+	}
+
+	return taken, nil
 }
 
-func (r *RestaurantRepository) Delete(ctx context.Context, id string) error {
+func (r *RestaurantRepository) Delete(ctx context.Context, id domain.RestaurantID) error {
 	log, _ := logger.FromContext(ctx)
 
 	const query = `
@@ -232,10 +556,10 @@ func (r *RestaurantRepository) Delete(ctx context.Context, id string) error {
 	}
 	defer release()
 
-	commandTag, err := executor.Exec(ctx, query, id)
+	commandTag, err := executor.Exec(ctx, query, id.String())
 	if err != nil {
 		log.Error(ctx, common.ErrDeleteRestaurant,
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Error(err))
 		return err
 	}
@@ -247,22 +571,26 @@ func (r *RestaurantRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID string, fact domain.Fact) (*domain.Fact, error) {
+func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID domain.RestaurantID, fact domain.Fact) (*domain.Fact, error) {
 	log, _ := logger.FromContext(ctx)
 
 	const query = `
-		INSERT INTO facts (id, restaurant_id, content, created_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO facts (id, restaurant_id, content, locale, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
 	if fact.ID == "" {
-		fact.ID = uuid.New().String()
+		fact.ID = r.ids.NewID()
 	}
 
 	if fact.CreatedAt.IsZero() {
 		fact.CreatedAt = time.Now()
 	}
 
+	if fact.Locale == "" {
+		fact.Locale = domain.DefaultFactLocale
+	}
+
 	executor, release, err := r.GetExecutor(ctx)
 	if err != nil {
 		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
@@ -270,10 +598,10 @@ func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID string,
 	}
 	defer release()
 
-	exist, err := r.checkRestaurantExists(ctx, restaurantID, executor)
+	exist, err := r.checkRestaurantExists(ctx, restaurantID.String(), executor)
 	if err != nil {
 		log.Error(ctx, common.ErrCheckRestaurantExistence,
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 		return nil, err
 	}
@@ -281,15 +609,23 @@ func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID string,
 		return nil, errors.New(common.ErrRestaurantNotFound)
 	}
 
-	_, err = executor.Exec(ctx, query,
-		fact.ID,
-		restaurantID,
-		fact.Content,
-		fact.CreatedAt,
-	)
+	err = r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, query,
+			fact.ID,
+			restaurantID.String(),
+			fact.Content,
+			fact.Locale,
+			fact.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		return touchRestaurant(ctx, tx, restaurantID.String(), fact.CreatedAt)
+	})
 	if err != nil {
 		log.Error(ctx, common.ErrAddRestaurantFact,
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 		return nil, err
 	}
@@ -297,11 +633,23 @@ func (r *RestaurantRepository) AddFact(ctx context.Context, restaurantID string,
 	return &fact, nil
 }
 
-func (r *RestaurantRepository) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+// touchRestaurant moves the modification time of a restaurant forward when data shown with it,
+// such as its facts or working hours, changes; HTTP caches revalidate against that time.
+func touchRestaurant(ctx context.Context, tx pgx.Tx, restaurantID string, at time.Time) error {
+	const query = `
+		UPDATE restaurants
+		SET updated_at = GREATEST(updated_at, $2)
+		WHERE id = $1
+	`
+	_, err := tx.Exec(ctx, query, restaurantID, at)
+	return err
+}
+
+func (r *RestaurantRepository) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	log, _ := logger.FromContext(ctx)
 
 	const query = `
-		SELECT id, restaurant_id, content, created_at
+		SELECT id, restaurant_id, content, locale, created_at
 		FROM facts
 		WHERE restaurant_id = $1
 		ORDER BY created_at DESC
@@ -314,10 +662,10 @@ func (r *RestaurantRepository) GetFacts(ctx context.Context, restaurantID string
 	}
 	defer release()
 
-	rows, err := executor.Query(ctx, query, restaurantID)
+	rows, err := executor.Query(ctx, query, restaurantID.String())
 	if err != nil {
 		log.Error(ctx, common.ErrExecuteFactsQuery,
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 		return nil, err
 	}
@@ -330,6 +678,7 @@ func (r *RestaurantRepository) GetFacts(ctx context.Context, restaurantID string
 			&fact.ID,
 			&fact.RestaurantID,
 			&fact.Content,
+			&fact.Locale,
 			&fact.CreatedAt,
 		)
 		if err != nil {
@@ -350,12 +699,17 @@ func (r *RestaurantRepository) GetFacts(ctx context.Context, restaurantID string
 func (r *RestaurantRepository) GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error) {
 	log, _ := logger.FromContext(ctx)
 
-	const query = `
-		SELECT f.id, f.restaurant_id, f.content, f.created_at
-		FROM facts f
+	const estimateQuery = `
+		SELECT reltuples::bigint FROM pg_class WHERE oid = 'facts'::regclass
+	`
+
+	const sampleQuery = `
+		SELECT f.id, f.restaurant_id, f.content, f.locale, f.created_at
+		FROM facts f TABLESAMPLE BERNOULLI ($1)
 		JOIN restaurants r ON f.restaurant_id = r.id
+		WHERE NOT r.is_test
 		ORDER BY RANDOM()
-		LIMIT $1
+		LIMIT $2
 	`
 
 	executor, release, err := r.GetExecutor(ctx)
@@ -365,22 +719,139 @@ func (r *RestaurantRepository) GetRandomFacts(ctx context.Context, count int) ([
 	}
 	defer release()
 
-	rows, err := executor.Query(ctx, query, count)
+	var estimated int64
+	if err := executor.QueryRow(ctx, estimateQuery).Scan(&estimated); err != nil {
+		log.Warn(ctx, common.ErrEstimateFactsCount, zap.Error(err))
+		estimated = 0
+	}
+
+	facts, err := r.queryFacts(ctx, executor, sampleQuery, factsSamplePercent(estimated, count), count)
 	if err != nil {
 		log.Error(ctx, common.ErrExecuteRandomFactsQuery,
 			zap.Int("count", count),
 			zap.Error(err))
 		return nil, err
 	}
+
+	if len(facts) < count {
+		facts, err = r.sampleFacts(ctx, executor, []string{"NOT r.is_test"}, nil, count)
+		if err != nil {
+			log.Error(ctx, common.ErrExecuteRandomFactsQuery,
+				zap.Int("count", count),
+				zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return facts, nil
+}
+
+// GetFactsPool returns up to limit random facts matching the filter. The facts of a restaurant are
+// few and read through its index; otherwise they are read as a window of the sample key index.
+func (r *RestaurantRepository) GetFactsPool(ctx context.Context, filter domain.FactFilter, limit int) ([]domain.Fact, error) {
+	log, _ := logger.FromContext(ctx)
+
+	var conditions []string
+	var args []interface{}
+	if filter.RestaurantID != "" {
+		restaurantID, err := domain.ParseRestaurantID(filter.RestaurantID)
+		if err != nil {
+			return []domain.Fact{}, nil
+		}
+		args = append(args, restaurantID.String())
+		conditions = append(conditions, fmt.Sprintf("f.restaurant_id = $%d", len(args)))
+	} else {
+		conditions = append(conditions, "NOT r.is_test")
+	}
+	if filter.Cuisine != "" {
+		args = append(args, string(filter.Cuisine))
+		conditions = append(conditions, fmt.Sprintf("r.cuisine = $%d", len(args)))
+	}
+	if filter.Locale != "" {
+		args = append(args, filter.Locale)
+		conditions = append(conditions, fmt.Sprintf("f.locale = $%d", len(args)))
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var facts []domain.Fact
+	if filter.RestaurantID != "" {
+		query := factsQuery + " WHERE " + strings.Join(conditions, " AND ") +
+			fmt.Sprintf(" ORDER BY RANDOM() LIMIT $%d", len(args)+1)
+		facts, err = r.queryFacts(ctx, executor, query, append(args, limit)...)
+	} else {
+		facts, err = r.sampleFacts(ctx, executor, conditions, args, limit)
+	}
+	if err != nil {
+		log.Error(ctx, common.ErrExecuteRandomFactsQuery,
+			zap.String("restaurantID", filter.RestaurantID),
+			zap.String("cuisine", string(filter.Cuisine)),
+			zap.String("locale", filter.Locale),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return facts, nil
+}
+
+// factsQuery selects the facts joined with their restaurants for the conditions that follow it.
+const factsQuery = `
+		SELECT f.id, f.restaurant_id, f.content, f.locale, f.created_at
+		FROM facts f
+		JOIN restaurants r ON f.restaurant_id = r.id`
+
+// sampleFacts returns up to limit facts matching the conditions in the order of their random sample
+// key, starting at a random key and wrapping around to the lowest keys, so that only the rows
+// returned are read instead of sorting every matching fact.
+func (r *RestaurantRepository) sampleFacts(
+	ctx context.Context,
+	executor DBExecutor,
+	conditions []string,
+	args []interface{},
+	limit int,
+) ([]domain.Fact, error) {
+	start := rand.Float64()
+	window := func(bound string) string {
+		return factsQuery + " WHERE " + strings.Join(append(conditions[:len(conditions):len(conditions)],
+			fmt.Sprintf("f.sample_key %s $%d", bound, len(args)+1)), " AND ") +
+			fmt.Sprintf(" ORDER BY f.sample_key LIMIT $%d", len(args)+2)
+	}
+
+	facts, err := r.queryFacts(ctx, executor, window(">="), append(args[:len(args):len(args)], start, limit)...)
+	if err != nil || len(facts) >= limit {
+		return facts, err
+	}
+
+	wrapped, err := r.queryFacts(ctx, executor, window("<"), append(args[:len(args):len(args)], start, limit-len(facts))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(facts, wrapped...), nil
+}
+
+func (r *RestaurantRepository) queryFacts(ctx context.Context, executor DBExecutor, query string, args ...interface{}) ([]domain.Fact, error) {
+	log, _ := logger.FromContext(ctx)
+
+	rows, err := executor.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
-	facts := make([]domain.Fact, 0, count)
+	facts := make([]domain.Fact, 0)
 	for rows.Next() {
 		var fact domain.Fact
 		err = rows.Scan(
 			&fact.ID,
 			&fact.RestaurantID,
 			&fact.Content,
+			&fact.Locale,
 			&fact.CreatedAt,
 		)
 		if err != nil {
@@ -398,6 +869,23 @@ func (r *RestaurantRepository) GetRandomFacts(ctx context.Context, count int) ([
 	return facts, nil
 }
 
+// factsSamplePercent returns the BERNOULLI sampling percentage that is expected to yield
+// a few times more rows than requested, so the sample stays small on large tables.
+func factsSamplePercent(estimatedRows int64, count int) float64 {
+	const oversampling = 10
+
+	if estimatedRows <= 0 {
+		return 100
+	}
+
+	percent := float64(count*oversampling) * 100 / float64(estimatedRows)
+	if percent > 100 {
+		return 100
+	}
+
+	return percent
+}
+
 func (r *RestaurantRepository) checkRestaurantExists(ctx context.Context, id string, executor DBExecutor) (bool, error) {
 	const query = `
 		SELECT EXISTS(SELECT 1 FROM restaurants WHERE id = $1)
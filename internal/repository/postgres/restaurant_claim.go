@@ -0,0 +1,363 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const restaurantClaimColumns = `
+	id, restaurant_id, user_id, proof, status, reviewer_id, review_note, created_at, decided_at
+`
+
+type RestaurantClaimRepository struct {
+	*Repository
+}
+
+func NewRestaurantClaimRepository(repository *Repository) *RestaurantClaimRepository {
+	return &RestaurantClaimRepository{
+		Repository: repository,
+	}
+}
+
+func (r *RestaurantClaimRepository) Create(ctx context.Context, claim *domain.RestaurantClaim) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_claims (id, restaurant_id, user_id, proof, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (restaurant_id, user_id) WHERE status = 'pending' DO NOTHING
+	`
+
+	if claim.ID == "" {
+		claim.ID = r.ids.NewID()
+	}
+	claim.Status = domain.RestaurantClaimPending
+	claim.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		claim.ID,
+		claim.RestaurantID,
+		claim.UserID,
+		claim.Proof,
+		claim.Status,
+		claim.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateRestaurantClaim,
+			zap.String("restaurantID", claim.RestaurantID),
+			zap.String("userID", claim.UserID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateRestaurantClaim, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrRestaurantClaimExists)
+	}
+
+	return nil
+}
+
+func (r *RestaurantClaimRepository) GetByID(ctx context.Context, id string) (*domain.RestaurantClaim, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + restaurantClaimColumns + `
+		FROM restaurant_claims
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	claim, err := scanRestaurantClaim(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRestaurantClaimNotFound)
+		}
+		log.Error(ctx, common.ErrGetRestaurantClaim, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantClaim, err)
+	}
+
+	return claim, nil
+}
+
+func (r *RestaurantClaimRepository) List(ctx context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + restaurantClaimColumns + `
+		FROM restaurant_claims
+		WHERE ($1::text = '' OR restaurant_id::text = $1)
+			AND ($2::text = '' OR user_id::text = $2)
+			AND ($3::text = '' OR status = $3)
+		ORDER BY created_at, id
+		LIMIT $4 OFFSET $5
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, filter.RestaurantID, filter.UserID, string(filter.Status), limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListRestaurantClaims,
+			zap.String("restaurantID", filter.RestaurantID),
+			zap.String("status", string(filter.Status)),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClaims, err)
+	}
+	defer rows.Close()
+
+	claims := make([]*domain.RestaurantClaim, 0)
+	for rows.Next() {
+		claim, err := scanRestaurantClaim(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClaims, err)
+		}
+		claims = append(claims, claim)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClaims, err)
+	}
+
+	return claims, nil
+}
+
+func (r *RestaurantClaimRepository) Decide(ctx context.Context, claim *domain.RestaurantClaim) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE restaurant_claims
+		SET status = $2, reviewer_id = $3, review_note = $4, decided_at = $5
+		WHERE id::text = $1 AND status = $6
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		claim.ID,
+		claim.Status,
+		claim.ReviewerID,
+		claim.ReviewNote,
+		claim.DecidedAt,
+		domain.RestaurantClaimPending,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateRestaurantClaim, zap.String("id", claim.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateRestaurantClaim, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrRestaurantClaimNotFound)
+	}
+
+	return nil
+}
+
+func (r *RestaurantClaimRepository) GetOwner(ctx context.Context, restaurantID string) (*domain.RestaurantOwnership, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT restaurant_id, owner_id, claim_id, since
+		FROM restaurant_owners
+		WHERE restaurant_id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var ownership domain.RestaurantOwnership
+	err = executor.QueryRow(ctx, query, restaurantID).Scan(
+		&ownership.RestaurantID,
+		&ownership.OwnerID,
+		&ownership.ClaimID,
+		&ownership.Since,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRestaurantOwnerNotFound)
+		}
+		log.Error(ctx, common.ErrGetRestaurantOwner, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantOwner, err)
+	}
+
+	return &ownership, nil
+}
+
+func (r *RestaurantClaimRepository) SetOwner(ctx context.Context, ownership *domain.RestaurantOwnership) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_owners (restaurant_id, owner_id, claim_id, since)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (restaurant_id) DO NOTHING
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		ownership.RestaurantID,
+		ownership.OwnerID,
+		ownership.ClaimID,
+		ownership.Since,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSetRestaurantOwner,
+			zap.String("restaurantID", ownership.RestaurantID),
+			zap.String("ownerID", ownership.OwnerID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSetRestaurantOwner, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrRestaurantAlreadyOwned)
+	}
+
+	return nil
+}
+
+func (r *RestaurantClaimRepository) AddAuditRecord(ctx context.Context, record *domain.OwnershipAuditRecord) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_ownership_audit (id, restaurant_id, claim_id, owner_id, approved_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if record.ID == "" {
+		record.ID = r.ids.NewID()
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		record.ID,
+		record.RestaurantID,
+		record.ClaimID,
+		record.OwnerID,
+		record.ApprovedBy,
+		record.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrAddOwnershipAuditRecord,
+			zap.String("restaurantID", record.RestaurantID),
+			zap.String("claimID", record.ClaimID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrAddOwnershipAuditRecord, err)
+	}
+
+	return nil
+}
+
+func (r *RestaurantClaimRepository) ListAuditRecords(ctx context.Context, restaurantID string) ([]*domain.OwnershipAuditRecord, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, restaurant_id, claim_id, owner_id, approved_by, created_at
+		FROM restaurant_ownership_audit
+		WHERE restaurant_id::text = $1
+		ORDER BY created_at DESC, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListOwnershipAuditRecords, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListOwnershipAuditRecords, err)
+	}
+	defer rows.Close()
+
+	records := make([]*domain.OwnershipAuditRecord, 0)
+	for rows.Next() {
+		var record domain.OwnershipAuditRecord
+		err := rows.Scan(
+			&record.ID,
+			&record.RestaurantID,
+			&record.ClaimID,
+			&record.OwnerID,
+			&record.ApprovedBy,
+			&record.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListOwnershipAuditRecords, err)
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListOwnershipAuditRecords, err)
+	}
+
+	return records, nil
+}
+
+func scanRestaurantClaim(row pgx.Row) (*domain.RestaurantClaim, error) {
+	var claim domain.RestaurantClaim
+	err := row.Scan(
+		&claim.ID,
+		&claim.RestaurantID,
+		&claim.UserID,
+		&claim.Proof,
+		&claim.Status,
+		&claim.ReviewerID,
+		&claim.ReviewNote,
+		&claim.CreatedAt,
+		&claim.DecidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
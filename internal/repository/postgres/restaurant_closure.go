@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const restaurantClosureColumns = `id, restaurant_id, starts_on, reopens_on, message, created_at`
+
+type RestaurantClosureRepository struct {
+	*Repository
+}
+
+func NewRestaurantClosureRepository(repository *Repository) *RestaurantClosureRepository {
+	return &RestaurantClosureRepository{
+		Repository: repository,
+	}
+}
+
+// Create locks the restaurant while it looks for an overlapping closure, so that two closures
+// created at once can't overlap.
+func (r *RestaurantClosureRepository) Create(ctx context.Context, closure *domain.RestaurantClosure) error {
+	log, _ := logger.FromContext(ctx)
+
+	if closure.ID == "" {
+		closure.ID = r.ids.NewID()
+	}
+	closure.CreatedAt = time.Now()
+	startsOn, reopensOn := closure.StartsOn.Format("2006-01-02"), closure.ReopensOn.Format("2006-01-02")
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const lockQuery = `SELECT 1 FROM restaurants WHERE id::text = $1 FOR UPDATE`
+
+		var found int
+		if err := tx.QueryRow(ctx, lockQuery, closure.RestaurantID).Scan(&found); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errors.New(common.ErrRestaurantNotFound)
+			}
+			return err
+		}
+
+		const overlapQuery = `
+			SELECT EXISTS (
+				SELECT 1 FROM restaurant_closures
+				WHERE restaurant_id::text = $1 AND starts_on < $3 AND reopens_on > $2
+			)
+		`
+
+		var overlaps bool
+		err := tx.QueryRow(ctx, overlapQuery, closure.RestaurantID, startsOn, reopensOn).Scan(&overlaps)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return errors.New(common.ErrRestaurantClosureOverlaps)
+		}
+
+		const insertQuery = `
+			INSERT INTO restaurant_closures (id, restaurant_id, starts_on, reopens_on, message, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`
+
+		_, err = tx.Exec(ctx, insertQuery,
+			closure.ID,
+			closure.RestaurantID,
+			startsOn,
+			reopensOn,
+			closure.Message,
+			closure.CreatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		switch err.Error() {
+		case common.ErrRestaurantNotFound, common.ErrRestaurantClosureOverlaps:
+			return err
+		}
+		log.Error(ctx, common.ErrCreateRestaurantClosure,
+			zap.String("restaurantID", closure.RestaurantID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateRestaurantClosure, err)
+	}
+
+	return nil
+}
+
+func (r *RestaurantClosureRepository) GetByID(ctx context.Context, id string) (*domain.RestaurantClosure, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `SELECT ` + restaurantClosureColumns + ` FROM restaurant_closures WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	closure, err := scanRestaurantClosure(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRestaurantClosureNotFound)
+		}
+		log.Error(ctx, common.ErrGetRestaurantClosure, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantClosure, err)
+	}
+
+	return closure, nil
+}
+
+func (r *RestaurantClosureRepository) ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.RestaurantClosure, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + restaurantClosureColumns + `
+		FROM restaurant_closures
+		WHERE restaurant_id::text = $1 AND reopens_on > $2
+		ORDER BY starts_on, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, from.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListRestaurantClosures, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+	}
+	defer rows.Close()
+
+	closures := make([]*domain.RestaurantClosure, 0)
+	for rows.Next() {
+		closure, err := scanRestaurantClosure(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+		}
+		closures = append(closures, closure)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+	}
+
+	return closures, nil
+}
+
+func (r *RestaurantClosureRepository) ListCovering(ctx context.Context, restaurantIDs []string, date time.Time) ([]*domain.RestaurantClosure, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if len(restaurantIDs) == 0 {
+		return []*domain.RestaurantClosure{}, nil
+	}
+
+	query := `
+		SELECT ` + restaurantClosureColumns + `
+		FROM restaurant_closures
+		WHERE restaurant_id = ANY($1::uuid[]) AND starts_on <= $2 AND reopens_on > $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantIDs, date.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListRestaurantClosures, zap.Int("restaurants", len(restaurantIDs)), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+	}
+	defer rows.Close()
+
+	closures := make([]*domain.RestaurantClosure, 0)
+	for rows.Next() {
+		closure, err := scanRestaurantClosure(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+		}
+		closures = append(closures, closure)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListRestaurantClosures, err)
+	}
+
+	return closures, nil
+}
+
+func (r *RestaurantClosureRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM restaurant_closures WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id)
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteRestaurantClosure, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteRestaurantClosure, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrRestaurantClosureNotFound)
+	}
+
+	return nil
+}
+
+func scanRestaurantClosure(row pgx.Row) (*domain.RestaurantClosure, error) {
+	var closure domain.RestaurantClosure
+	err := row.Scan(
+		&closure.ID,
+		&closure.RestaurantID,
+		&closure.StartsOn,
+		&closure.ReopensOn,
+		&closure.Message,
+		&closure.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &closure, nil
+}
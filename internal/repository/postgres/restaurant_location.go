@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const restaurantLocationColumns = `
+	restaurant_id, address, status, formatted_address, street, house_number, city,
+	COALESCE(city_id::text, ''), region, postal_code, country_code, latitude, longitude, provider, error, attempts, geocoded_at, updated_at
+`
+
+type RestaurantLocationRepository struct {
+	*Repository
+}
+
+func NewRestaurantLocationRepository(repository *Repository) *RestaurantLocationRepository {
+	return &RestaurantLocationRepository{
+		Repository: repository,
+	}
+}
+
+// Enqueue queues the address unless the restaurant already has it queued or geocoded; a failed
+// address is queued again. The components of a previous address are cleared.
+func (r *RestaurantLocationRepository) Enqueue(ctx context.Context, restaurantID, address string) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_locations (restaurant_id, address, status, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (restaurant_id) DO UPDATE
+		SET address = EXCLUDED.address, status = EXCLUDED.status, formatted_address = '', street = '',
+			house_number = '', city = '', city_id = NULL, region = '', postal_code = '', country_code = '',
+			latitude = 0, longitude = 0, provider = '', error = '', attempts = 0, geocoded_at = NULL,
+			updated_at = EXCLUDED.updated_at
+		WHERE restaurant_locations.address <> EXCLUDED.address OR restaurant_locations.status = $5
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query, restaurantID, address, domain.GeocodingPending, time.Now(), domain.GeocodingFailed)
+	if err != nil {
+		log.Error(ctx, common.ErrEnqueueGeocoding, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrEnqueueGeocoding, err)
+	}
+
+	return nil
+}
+
+func (r *RestaurantLocationRepository) GetByRestaurant(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + restaurantLocationColumns + `
+		FROM restaurant_locations
+		WHERE restaurant_id = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	location, err := scanRestaurantLocation(executor.QueryRow(ctx, query, restaurantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrRestaurantLocationNotFound)
+		}
+		log.Error(ctx, common.ErrGetRestaurantLocation, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantLocation, err)
+	}
+
+	return location, nil
+}
+
+func (r *RestaurantLocationRepository) ClaimPending(ctx context.Context, limit int, staleBefore time.Time) ([]*domain.RestaurantLocation, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		UPDATE restaurant_locations
+		SET status = $1, updated_at = NOW()
+		WHERE restaurant_id IN (
+			SELECT restaurant_id FROM restaurant_locations
+			WHERE status = $2 OR (status = $1 AND updated_at < $3)
+			ORDER BY updated_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + restaurantLocationColumns
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, domain.GeocodingProcessing, domain.GeocodingPending, staleBefore, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrClaimRestaurantLocations, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimRestaurantLocations, err)
+	}
+	defer rows.Close()
+
+	locations := make([]*domain.RestaurantLocation, 0)
+	for rows.Next() {
+		location, err := scanRestaurantLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrClaimRestaurantLocations, err)
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrClaimRestaurantLocations, err)
+	}
+
+	return locations, nil
+}
+
+func (r *RestaurantLocationRepository) Save(ctx context.Context, location *domain.RestaurantLocation) error {
+	log, _ := logger.FromContext(ctx)
+
+	// The city of a geocoded address is added to the catalogue unless it is already there.
+	const query = `
+		WITH city AS (
+			INSERT INTO cities (name, region, country_code)
+			SELECT $7::text, $8::text, $10::text
+			WHERE $3::text = $19::text AND $7::text <> ''
+			ON CONFLICT (country_code, region, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		)
+		UPDATE restaurant_locations
+		SET status = $3, formatted_address = $4, street = $5, house_number = $6, city = $7,
+			city_id = (SELECT id FROM city), region = $8, postal_code = $9, country_code = $10,
+			latitude = $11, longitude = $12, provider = $13, error = $14, attempts = $15,
+			geocoded_at = $16, updated_at = $17
+		WHERE restaurant_id = $1 AND address = $2 AND status = $18
+		RETURNING COALESCE(city_id::text, '')
+	`
+
+	location.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	err = executor.QueryRow(ctx, query,
+		location.RestaurantID,
+		location.Address,
+		location.Status,
+		location.Formatted,
+		location.Street,
+		location.HouseNumber,
+		location.City,
+		location.Region,
+		location.PostalCode,
+		location.CountryCode,
+		location.Latitude,
+		location.Longitude,
+		location.Provider,
+		location.Error,
+		location.Attempts,
+		location.GeocodedAt,
+		location.UpdatedAt,
+		domain.GeocodingProcessing,
+		domain.GeocodingDone,
+	).Scan(&location.CityID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(common.ErrRestaurantLocationNotFound)
+		}
+		log.Error(ctx, common.ErrSaveRestaurantLocation, zap.String("restaurantID", location.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveRestaurantLocation, err)
+	}
+
+	return nil
+}
+
+func scanRestaurantLocation(row pgx.Row) (*domain.RestaurantLocation, error) {
+	var location domain.RestaurantLocation
+	err := row.Scan(
+		&location.RestaurantID,
+		&location.Address,
+		&location.Status,
+		&location.Formatted,
+		&location.Street,
+		&location.HouseNumber,
+		&location.City,
+		&location.CityID,
+		&location.Region,
+		&location.PostalCode,
+		&location.CountryCode,
+		&location.Latitude,
+		&location.Longitude,
+		&location.Provider,
+		&location.Error,
+		&location.Attempts,
+		&location.GeocodedAt,
+		&location.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// ListCities returns the cities with at least one restaurant placed in them, test restaurants
+// aside, in the country or in every country when it is empty, those with the most restaurants
+// first.
+func (r *RestaurantLocationRepository) ListCities(ctx context.Context, countryCode string) ([]*domain.City, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT c.id, c.name, c.region, c.country_code, COUNT(*)
+		FROM cities c
+		JOIN restaurant_locations l ON l.city_id = c.id
+		JOIN restaurants r ON r.id = l.restaurant_id AND NOT r.is_test
+		WHERE $1::text = '' OR c.country_code = $1
+		GROUP BY c.id
+		ORDER BY COUNT(*) DESC, c.name, c.region
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, countryCode)
+	if err != nil {
+		log.Error(ctx, common.ErrListCities, zap.String("countryCode", countryCode), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListCities, err)
+	}
+	defer rows.Close()
+
+	cities := make([]*domain.City, 0)
+	for rows.Next() {
+		var city domain.City
+		if err := rows.Scan(&city.ID, &city.Name, &city.Region, &city.CountryCode, &city.RestaurantCount); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListCities, err)
+		}
+		cities = append(cities, &city)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListCities, err)
+	}
+
+	return cities, nil
+}
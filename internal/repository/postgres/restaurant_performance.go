@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+type RestaurantPerformanceRepository struct {
+	*Repository
+}
+
+func NewRestaurantPerformanceRepository(repository *Repository) *RestaurantPerformanceRepository {
+	return &RestaurantPerformanceRepository{
+		Repository: repository,
+	}
+}
+
+// GetBetween counts the bookings and seats by the date they are for, and the reviews and
+// responses by the time they were written and the booking was requested.
+func (r *RestaurantPerformanceRepository) GetBetween(ctx context.Context, from, to time.Time) ([]*domain.RestaurantPerformance, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT r.id, r.name, COALESCE(r.contact_email, ''),
+			   COALESCE(b.bookings, 0), COALESCE(b.guests, 0), COALESCE(b.cancellations, 0),
+			   COALESCE(b.responses, 0), COALESCE(b.response_seconds, 0),
+			   COALESCE(a.reserved, 0), COALESCE(a.capacity, 0),
+			   COALESCE(v.reviews, 0), COALESCE(v.rating, 0)
+		FROM restaurants r
+		LEFT JOIN (
+			SELECT restaurant_id,
+				   COUNT(*) FILTER (WHERE date >= $1 AND date < $2) AS bookings,
+				   SUM(guests_count) FILTER (WHERE date >= $1 AND date < $2
+				       AND status NOT IN ('cancelled', 'rejected')) AS guests,
+				   COUNT(*) FILTER (WHERE date >= $1 AND date < $2 AND status = 'cancelled') AS cancellations,
+				   COUNT(*) FILTER (WHERE created_at >= $3 AND created_at < $4
+				       AND COALESCE(confirmed_at, rejected_at) IS NOT NULL) AS responses,
+				   AVG(EXTRACT(EPOCH FROM COALESCE(confirmed_at, rejected_at) - created_at))
+				       FILTER (WHERE created_at >= $3 AND created_at < $4) AS response_seconds
+			FROM bookings
+			WHERE NOT is_test AND ((date >= $1 AND date < $2) OR (created_at >= $3 AND created_at < $4))
+			GROUP BY restaurant_id
+		) b ON b.restaurant_id = r.id
+		LEFT JOIN (
+			SELECT restaurant_id, SUM(reserved) AS reserved, SUM(capacity) AS capacity
+			FROM availability
+			WHERE date >= $1 AND date < $2
+			GROUP BY restaurant_id
+		) a ON a.restaurant_id = r.id
+		LEFT JOIN (
+			SELECT restaurant_id, COUNT(*) AS reviews, AVG(rating) AS rating
+			FROM reviews
+			WHERE status = 'published' AND created_at >= $3 AND created_at < $4
+			GROUP BY restaurant_id
+		) v ON v.restaurant_id = r.id
+		WHERE NOT r.is_test
+		ORDER BY r.id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"), from, to)
+	if err != nil {
+		log.Error(ctx, common.ErrGetRestaurantPerformance,
+			zap.Time("from", from),
+			zap.Time("to", to),
+			zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantPerformance, err)
+	}
+	defer rows.Close()
+
+	performances := make([]*domain.RestaurantPerformance, 0)
+	for rows.Next() {
+		performance := &domain.RestaurantPerformance{From: from, To: to}
+		var responseSeconds float64
+		err := rows.Scan(
+			&performance.RestaurantID,
+			&performance.RestaurantName,
+			&performance.ContactEmail,
+			&performance.Bookings,
+			&performance.Guests,
+			&performance.Cancellations,
+			&performance.Responses,
+			&responseSeconds,
+			&performance.SeatsReserved,
+			&performance.SeatsOffered,
+			&performance.Reviews,
+			&performance.AverageRating,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantPerformance, err)
+		}
+		performance.AverageResponseTime = time.Duration(responseSeconds * float64(time.Second))
+		performances = append(performances, performance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrGetRestaurantPerformance, err)
+	}
+
+	return performances, nil
+}
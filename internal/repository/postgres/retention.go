@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type RetentionRepository struct {
+	*Repository
+}
+
+func NewRetentionRepository(repository *Repository) *RetentionRepository {
+	return &RetentionRepository{
+		Repository: repository,
+	}
+}
+
+func (r *RetentionRepository) PurgeReadNotifications(ctx context.Context, readBefore time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		DELETE FROM notifications
+		WHERE read_at IS NOT NULL AND read_at < $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return 0, err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, readBefore)
+	if err != nil {
+		log.Error(ctx, common.ErrPurgeNotifications, zap.Time("readBefore", readBefore), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrPurgeNotifications, err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *RetentionRepository) AnonymizeCompletedBookings(ctx context.Context, completedBefore time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	// Bookings completed before completed_at was recorded count from their date.
+	const anonymizeBookingsQuery = `
+		UPDATE bookings
+		SET user_id = $2, comment = '', occasion = NULL, anonymized_at = $3, updated_at = $3
+		WHERE status = 'completed' AND anonymized_at IS NULL
+		  AND COALESCE(completed_at, date::timestamptz) < $1
+		RETURNING id
+	`
+
+	const clearPreOrderNotesQuery = `
+		UPDATE booking_pre_order_items SET notes = '' WHERE booking_id = ANY($1::uuid[])
+	`
+
+	const deleteLinksQuery = `
+		DELETE FROM booking_links WHERE booking_id = ANY($1::uuid[])
+	`
+
+	const anonymizeReviewsQuery = `
+		UPDATE reviews SET user_id = $2 WHERE booking_id = ANY($1::uuid[])
+	`
+
+	var ids []string
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, anonymizeBookingsQuery, completedBefore, domain.AnonymousUserID, time.Now())
+		if err != nil {
+			return err
+		}
+		ids, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, clearPreOrderNotesQuery, ids); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, deleteLinksQuery, ids); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, anonymizeReviewsQuery, ids, domain.AnonymousUserID)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrAnonymizeBookings, zap.Time("completedBefore", completedBefore), zap.Error(err))
+		return 0, fmt.Errorf("%s: %w", common.ErrAnonymizeBookings, err)
+	}
+
+	return len(ids), nil
+}
+
+func (r *RetentionRepository) CreateRun(ctx context.Context, run *domain.RetentionRun) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO retention_runs (id, policy, dry_run, cutoff, affected, error, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if run.ID == "" {
+		run.ID = r.ids.NewID()
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		run.ID,
+		run.Policy,
+		run.DryRun,
+		run.Cutoff,
+		run.Affected,
+		run.Error,
+		run.StartedAt,
+		run.FinishedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateRetentionRun, zap.String("policy", string(run.Policy)), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateRetentionRun, err)
+	}
+
+	return nil
+}
+
+func (r *RetentionRepository) ListRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT id, policy, dry_run, cutoff, affected, error, started_at, finished_at
+		FROM retention_runs
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, limit, offset)
+	if err != nil {
+		log.Error(ctx, common.ErrListRetentionRuns, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListRetentionRuns, err)
+	}
+	defer rows.Close()
+
+	runs := make([]*domain.RetentionRun, 0)
+	for rows.Next() {
+		var run domain.RetentionRun
+		err := rows.Scan(
+			&run.ID,
+			&run.Policy,
+			&run.DryRun,
+			&run.Cutoff,
+			&run.Affected,
+			&run.Error,
+			&run.StartedAt,
+			&run.FinishedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListRetentionRuns, err)
+		}
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListRetentionRuns, err)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,457 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type ReviewRepository struct {
+	*Repository
+}
+
+func NewReviewRepository(repository *Repository) *ReviewRepository {
+	return &ReviewRepository{
+		Repository: repository,
+	}
+}
+
+// reviewColumns selects a review with its reply; the reply columns are NULL without one.
+const reviewColumns = `
+	r.id, r.restaurant_id, r.user_id, r.booking_id, r.rating, r.text, r.status, r.created_at, r.updated_at,
+	rr.text, rr.created_at, rr.updated_at
+`
+
+func scanReview(row pgx.Row) (*domain.Review, error) {
+	var review domain.Review
+	var replyText *string
+	var replyCreatedAt, replyUpdatedAt *time.Time
+
+	err := row.Scan(
+		&review.ID,
+		&review.RestaurantID,
+		&review.UserID,
+		&review.BookingID,
+		&review.Rating,
+		&review.Text,
+		&review.Status,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&replyText,
+		&replyCreatedAt,
+		&replyUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if replyText != nil {
+		review.Reply = &domain.ReviewReply{
+			ReviewID:  review.ID,
+			Text:      *replyText,
+			CreatedAt: *replyCreatedAt,
+			UpdatedAt: *replyUpdatedAt,
+		}
+	}
+	return &review, nil
+}
+
+// Create stores the review; a second review of the same booking fails with ErrReviewExists.
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO reviews (id, restaurant_id, user_id, booking_id, rating, text, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (booking_id) DO NOTHING
+	`
+
+	if review.ID == "" {
+		review.ID = r.ids.NewID()
+	}
+	if review.CreatedAt.IsZero() {
+		review.CreatedAt = time.Now()
+	}
+	review.UpdatedAt = review.CreatedAt
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		review.ID,
+		review.RestaurantID,
+		review.UserID,
+		review.BookingID,
+		review.Rating,
+		review.Text,
+		review.Status,
+		review.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateReview,
+			zap.String("bookingID", review.BookingID),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateReview, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrReviewExists)
+	}
+
+	return nil
+}
+
+func (r *ReviewRepository) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		LEFT JOIN review_replies rr ON rr.review_id = r.id
+		WHERE r.id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	review, err := scanReview(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrReviewNotFound)
+		}
+		log.Error(ctx, common.ErrGetReview, zap.String("reviewID", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetReview, err)
+	}
+
+	return review, nil
+}
+
+// ListByRestaurant returns the published reviews of the restaurant, newest first.
+func (r *ReviewRepository) ListByRestaurant(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM reviews r
+		LEFT JOIN review_replies rr ON rr.review_id = r.id
+		WHERE r.restaurant_id::text = $1 AND r.status = $2
+		ORDER BY r.created_at DESC, r.id
+		OFFSET $3 LIMIT $4
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, domain.ReviewStatusPublished, offset, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListReviews, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListReviews, err)
+	}
+	defer rows.Close()
+
+	reviews := make([]*domain.Review, 0)
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListReviews, err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListReviews, err)
+	}
+
+	return reviews, nil
+}
+
+func (r *ReviewRepository) UpdateStatus(ctx context.Context, id string, status domain.ReviewStatus) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE reviews
+		SET status = $2, updated_at = NOW()
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, id, status)
+	if err != nil {
+		log.Error(ctx, common.ErrUpdateReview,
+			zap.String("reviewID", id),
+			zap.String("status", string(status)),
+			zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateReview, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrReviewNotFound)
+	}
+
+	return nil
+}
+
+// SaveReply creates or replaces the reply to a review. The review counts as updated, so cached
+// review lists are revalidated.
+func (r *ReviewRepository) SaveReply(ctx context.Context, reply *domain.ReviewReply) error {
+	log, _ := logger.FromContext(ctx)
+
+	const replyQuery = `
+		INSERT INTO review_replies (review_id, text, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (review_id) DO UPDATE
+		SET text = EXCLUDED.text, updated_at = EXCLUDED.updated_at
+		RETURNING created_at
+	`
+	const reviewQuery = `
+		UPDATE reviews
+		SET updated_at = $2
+		WHERE id = $1
+	`
+
+	reply.UpdatedAt = time.Now()
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, replyQuery, reply.ReviewID, reply.Text, reply.UpdatedAt).Scan(&reply.CreatedAt); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, reviewQuery, reply.ReviewID, reply.UpdatedAt)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrSaveReviewReply, zap.String("reviewID", reply.ReviewID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveReviewReply, err)
+	}
+
+	return nil
+}
+
+const reviewFlagColumns = `id, review_id, restaurant_id, reason, status, resolution_note, created_at, resolved_at`
+
+func scanReviewFlag(row pgx.Row) (*domain.ReviewFlag, error) {
+	var flag domain.ReviewFlag
+	err := row.Scan(
+		&flag.ID,
+		&flag.ReviewID,
+		&flag.RestaurantID,
+		&flag.Reason,
+		&flag.Status,
+		&flag.ResolutionNote,
+		&flag.CreatedAt,
+		&flag.ResolvedAt,
+	)
+	return &flag, err
+}
+
+// CreateFlag stores an open flag; a review with an open flag already fails with
+// ErrReviewAlreadyFlagged.
+func (r *ReviewRepository) CreateFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO review_flags (id, review_id, restaurant_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (review_id) WHERE status = 'open' DO NOTHING
+	`
+
+	if flag.ID == "" {
+		flag.ID = r.ids.NewID()
+	}
+	flag.Status = domain.ReviewFlagStatusOpen
+	flag.CreatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		flag.ID,
+		flag.ReviewID,
+		flag.RestaurantID,
+		flag.Reason,
+		flag.Status,
+		flag.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateReviewFlag, zap.String("reviewID", flag.ReviewID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateReviewFlag, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrReviewAlreadyFlagged)
+	}
+
+	return nil
+}
+
+func (r *ReviewRepository) GetFlag(ctx context.Context, id string) (*domain.ReviewFlag, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + reviewFlagColumns + `
+		FROM review_flags
+		WHERE id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	flag, err := scanReviewFlag(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrReviewFlagNotFound)
+		}
+		log.Error(ctx, common.ErrGetReviewFlag, zap.String("flagID", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetReviewFlag, err)
+	}
+
+	return flag, nil
+}
+
+// ListFlags returns the flags with the status, oldest first, each with the review it is about.
+func (r *ReviewRepository) ListFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT f.id, f.review_id, f.restaurant_id, f.reason, f.status, f.resolution_note, f.created_at, f.resolved_at,
+			` + reviewColumns + `
+		FROM review_flags f
+		JOIN reviews r ON r.id = f.review_id
+		LEFT JOIN review_replies rr ON rr.review_id = r.id
+		WHERE f.status = $1
+		ORDER BY f.created_at, f.id
+		OFFSET $2 LIMIT $3
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, status, offset, limit)
+	if err != nil {
+		log.Error(ctx, common.ErrListReviewFlags, zap.String("status", string(status)), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListReviewFlags, err)
+	}
+	defer rows.Close()
+
+	flags := make([]*domain.ReviewFlag, 0)
+	for rows.Next() {
+		var flag domain.ReviewFlag
+		var review domain.Review
+		var replyText *string
+		var replyCreatedAt, replyUpdatedAt *time.Time
+
+		err := rows.Scan(
+			&flag.ID,
+			&flag.ReviewID,
+			&flag.RestaurantID,
+			&flag.Reason,
+			&flag.Status,
+			&flag.ResolutionNote,
+			&flag.CreatedAt,
+			&flag.ResolvedAt,
+			&review.ID,
+			&review.RestaurantID,
+			&review.UserID,
+			&review.BookingID,
+			&review.Rating,
+			&review.Text,
+			&review.Status,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&replyText,
+			&replyCreatedAt,
+			&replyUpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListReviewFlags, err)
+		}
+
+		if replyText != nil {
+			review.Reply = &domain.ReviewReply{
+				ReviewID:  review.ID,
+				Text:      *replyText,
+				CreatedAt: *replyCreatedAt,
+				UpdatedAt: *replyUpdatedAt,
+			}
+		}
+		flag.Review = &review
+		flags = append(flags, &flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListReviewFlags, err)
+	}
+
+	return flags, nil
+}
+
+// ResolveFlag stores the outcome of an open flag; a flag resolved in the meantime fails with
+// ErrReviewFlagResolved.
+func (r *ReviewRepository) ResolveFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE review_flags
+		SET status = $2, resolution_note = $3, resolved_at = $4
+		WHERE id::text = $1 AND status = $5
+	`
+
+	resolvedAt := time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, flag.ID, flag.Status, flag.ResolutionNote, resolvedAt, domain.ReviewFlagStatusOpen)
+	if err != nil {
+		log.Error(ctx, common.ErrResolveReviewFlag, zap.String("flagID", flag.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrResolveReviewFlag, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrReviewFlagResolved)
+	}
+
+	flag.ResolvedAt = &resolvedAt
+	return nil
+}
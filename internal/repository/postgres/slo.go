@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+type SLORepository struct {
+	*Repository
+}
+
+func NewSLORepository(repository *Repository) *SLORepository {
+	return &SLORepository{
+		Repository: repository,
+	}
+}
+
+func (r *SLORepository) AddRequestMetrics(ctx context.Context, metrics []*domain.RequestMetrics) error {
+	log, _ := logger.FromContext(ctx)
+
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	// Every instance of the service adds its counts to the row of the hour.
+	const query = `
+		INSERT INTO request_metrics (hour, requests, errors, slow)
+		SELECT * FROM unnest($1::timestamptz[], $2::bigint[], $3::bigint[], $4::bigint[])
+		ON CONFLICT (hour) DO UPDATE
+		SET requests = request_metrics.requests + EXCLUDED.requests,
+			errors = request_metrics.errors + EXCLUDED.errors,
+			slow = request_metrics.slow + EXCLUDED.slow
+	`
+
+	hours := make([]time.Time, 0, len(metrics))
+	requests := make([]int64, 0, len(metrics))
+	errs := make([]int64, 0, len(metrics))
+	slow := make([]int64, 0, len(metrics))
+	for _, m := range metrics {
+		hours = append(hours, m.Hour)
+		requests = append(requests, m.Requests)
+		errs = append(errs, m.Errors)
+		slow = append(slow, m.Slow)
+	}
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	if _, err := executor.Exec(ctx, query, hours, requests, errs, slow); err != nil {
+		log.Error(ctx, common.ErrAddRequestMetrics, zap.Int("hours", len(metrics)), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrAddRequestMetrics, err)
+	}
+
+	return nil
+}
+
+func (r *SLORepository) SumRequestMetrics(ctx context.Context, from, to time.Time) (*domain.RequestMetrics, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT COALESCE(SUM(requests), 0), COALESCE(SUM(errors), 0), COALESCE(SUM(slow), 0)
+		FROM request_metrics
+		WHERE hour >= $1 AND hour < $2
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	metrics := domain.RequestMetrics{Hour: from}
+	if err := executor.QueryRow(ctx, query, from, to).Scan(&metrics.Requests, &metrics.Errors, &metrics.Slow); err != nil {
+		log.Error(ctx, common.ErrSumRequestMetrics, zap.Time("from", from), zap.Time("to", to), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrSumRequestMetrics, err)
+	}
+
+	return &metrics, nil
+}
+
+func (r *SLORepository) SaveReport(ctx context.Context, report *domain.SLOReport) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO slo_reports (date, availability_target, latency_target, latency_threshold_ms,
+			requests, errors, slow, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (date) DO UPDATE
+		SET availability_target = EXCLUDED.availability_target,
+			latency_target = EXCLUDED.latency_target,
+			latency_threshold_ms = EXCLUDED.latency_threshold_ms,
+			requests = EXCLUDED.requests,
+			errors = EXCLUDED.errors,
+			slow = EXCLUDED.slow,
+			created_at = EXCLUDED.created_at
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	date := report.Date.Format("2006-01-02")
+	_, err = executor.Exec(ctx, query,
+		date,
+		report.Objectives.AvailabilityTarget,
+		report.Objectives.LatencyTarget,
+		report.Objectives.LatencyThreshold.Milliseconds(),
+		report.Requests,
+		report.Errors,
+		report.Slow,
+		report.CreatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSaveSLOReport, zap.String("date", date), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveSLOReport, err)
+	}
+
+	return nil
+}
+
+func (r *SLORepository) ListReports(ctx context.Context, from, to time.Time) ([]*domain.SLOReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT date, availability_target, latency_target, latency_threshold_ms, requests, errors, slow, created_at
+		FROM slo_reports
+		WHERE date BETWEEN $1 AND $2
+		ORDER BY date
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListSLOReports, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSLOReports, err)
+	}
+	defer rows.Close()
+
+	reports := make([]*domain.SLOReport, 0)
+	for rows.Next() {
+		var (
+			date        time.Time
+			objectives  domain.SLOObjectives
+			thresholdMS int64
+			metrics     domain.RequestMetrics
+			createdAt   time.Time
+		)
+		if err := rows.Scan(&date, &objectives.AvailabilityTarget, &objectives.LatencyTarget, &thresholdMS,
+			&metrics.Requests, &metrics.Errors, &metrics.Slow, &createdAt); err != nil {
+			log.Error(ctx, common.ErrListSLOReports, zap.Error(err))
+			return nil, fmt.Errorf("%s: %w", common.ErrListSLOReports, err)
+		}
+		objectives.LatencyThreshold = time.Duration(thresholdMS) * time.Millisecond
+
+		// Only the counts are stored; the shares and burn rates are derived from them.
+		report := domain.NewSLOReport(date, metrics, objectives)
+		report.CreatedAt = createdAt
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(ctx, common.ErrListSLOReports, zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSLOReports, err)
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const specialDayColumns = `id, restaurant_id, date, open_time, close_time, is_closed, note, updated_at`
+
+type SpecialDayRepository struct {
+	*Repository
+}
+
+func NewSpecialDayRepository(repository *Repository) *SpecialDayRepository {
+	return &SpecialDayRepository{
+		Repository: repository,
+	}
+}
+
+func (r *SpecialDayRepository) Set(ctx context.Context, day *domain.SpecialDay) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_special_days (id, restaurant_id, date, open_time, close_time, is_closed, note, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (restaurant_id, date) DO UPDATE
+		SET open_time = EXCLUDED.open_time, close_time = EXCLUDED.close_time,
+			is_closed = EXCLUDED.is_closed, note = EXCLUDED.note, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+
+	if day.ID == "" {
+		day.ID = r.ids.NewID()
+	}
+	day.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	// The special day the restaurant had on the date keeps its ID.
+	err = executor.QueryRow(ctx, query,
+		day.ID,
+		day.RestaurantID,
+		day.Date.Format("2006-01-02"),
+		day.OpenTime,
+		day.CloseTime,
+		day.IsClosed,
+		day.Note,
+		day.UpdatedAt,
+	).Scan(&day.ID)
+	if err != nil {
+		log.Error(ctx, common.ErrSetSpecialDay, zap.String("restaurantID", day.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSetSpecialDay, err)
+	}
+
+	return nil
+}
+
+func (r *SpecialDayRepository) ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.SpecialDay, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + specialDayColumns + `
+		FROM restaurant_special_days
+		WHERE restaurant_id::text = $1 AND date >= $2
+		ORDER BY date
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, from.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListSpecialDays, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSpecialDays, err)
+	}
+	defer rows.Close()
+
+	days := make([]*domain.SpecialDay, 0)
+	for rows.Next() {
+		day, err := scanSpecialDay(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSpecialDays, err)
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSpecialDays, err)
+	}
+
+	return days, nil
+}
+
+func (r *SpecialDayRepository) Delete(ctx context.Context, restaurantID string, date time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `DELETE FROM restaurant_special_days WHERE restaurant_id::text = $1 AND date = $2`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query, restaurantID, date.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrDeleteSpecialDay, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteSpecialDay, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New(common.ErrSpecialDayNotFound)
+	}
+
+	return nil
+}
+
+func scanSpecialDay(row pgx.Row) (*domain.SpecialDay, error) {
+	var day domain.SpecialDay
+	err := row.Scan(
+		&day.ID,
+		&day.RestaurantID,
+		&day.Date,
+		&day.OpenTime,
+		&day.CloseTime,
+		&day.IsClosed,
+		&day.Note,
+		&day.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &day, nil
+}
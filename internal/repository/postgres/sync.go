@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// syncSafetyLag holds back the changes stamped in the last seconds. A row is stamped when it is
+// written but seen only when its transaction commits, so a transaction committing late could put
+// a change behind a cursor a client has already passed; the lag leaves the writes in flight time
+// to commit first.
+const syncSafetyLag = 10 * time.Second
+
+type SyncRepository struct {
+	*Repository
+}
+
+func NewSyncRepository(repository *Repository) *SyncRepository {
+	return &SyncRepository{
+		Repository: repository,
+	}
+}
+
+func (r *SyncRepository) ListChanges(ctx context.Context, entity domain.ExportEntity, after domain.SyncCursor, limit int) ([]*domain.SyncChange, error) {
+	log, _ := logger.FromContext(ctx)
+
+	source, ok := exportTables[entity]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, errors.New("unknown entity "+string(entity)))
+	}
+
+	// Rows and the tombstones the triggers leave for deleted rows, merged in the order of change, up
+	// to the safety lag.
+	query := fmt.Sprintf(`
+		SELECT id, changed_at, data, deleted
+		FROM (
+			SELECT t.%[2]s::text AS id, t.updated_at AS changed_at, to_jsonb(t) AS data, false AS deleted
+			FROM %[1]s t
+			UNION ALL
+			SELECT id, deleted_at, NULL, true
+			FROM sync_tombstones
+			WHERE entity = $4
+		) changes
+		WHERE (changed_at > $1 OR ($2 <> '' AND changed_at = $1 AND id > $2))
+			AND changed_at < statement_timestamp() - make_interval(secs => $5)
+		ORDER BY changed_at, id
+		LIMIT $3
+	`, source.table, source.idColumn)
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, after.ChangedAt, after.ID, limit, string(entity), syncSafetyLag.Seconds())
+	if err != nil {
+		log.Error(ctx, common.ErrListSyncChanges, zap.String("entity", string(entity)), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, err)
+	}
+	defer rows.Close()
+
+	changes := make([]*domain.SyncChange, 0, limit)
+	for rows.Next() {
+		var change domain.SyncChange
+		if err := rows.Scan(&change.ID, &change.ChangedAt, &change.Data, &change.Deleted); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, err)
+		}
+		changes = append(changes, &change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListSyncChanges, err)
+	}
+
+	return changes, nil
+}
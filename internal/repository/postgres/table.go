@@ -0,0 +1,379 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const tableColumns = `id, restaurant_id, name, seats, zone, is_smoking, is_outdoor, is_accessible, created_at, updated_at`
+
+type TableRepository struct {
+	*Repository
+}
+
+func NewTableRepository(repository *Repository) *TableRepository {
+	return &TableRepository{
+		Repository: repository,
+	}
+}
+
+func (r *TableRepository) Create(ctx context.Context, table *domain.Table) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO restaurant_tables (id, restaurant_id, name, seats, zone, is_smoking, is_outdoor, is_accessible, created_at, updated_at)
+		SELECT $1::uuid, id, $3::text, $4::int, $5::text, $6::boolean, $7::boolean, $9::boolean, $8::timestamptz, $8::timestamptz
+		FROM restaurants
+		WHERE id::text = $2
+	`
+
+	if table.ID == "" {
+		table.ID = r.ids.NewID()
+	}
+	now := time.Now()
+	table.CreatedAt = now
+	table.UpdatedAt = now
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	tag, err := executor.Exec(ctx, query,
+		table.ID,
+		table.RestaurantID,
+		table.Name,
+		table.Seats,
+		table.Zone,
+		table.IsSmoking,
+		table.IsOutdoor,
+		now,
+		table.IsAccessible,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrCreateTable, zap.String("restaurantID", table.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrCreateTable, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", common.ErrCreateTable, errors.New(common.ErrRestaurantNotFound))
+	}
+
+	return nil
+}
+
+func (r *TableRepository) GetByID(ctx context.Context, id string) (*domain.Table, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `SELECT ` + tableColumns + ` FROM restaurant_tables WHERE id::text = $1`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	table, err := scanTable(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrTableNotFound)
+		}
+		log.Error(ctx, common.ErrGetTable, zap.String("id", id), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetTable, err)
+	}
+
+	return table, nil
+}
+
+func (r *TableRepository) ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.Table, error) {
+	log, _ := logger.FromContext(ctx)
+
+	query := `
+		SELECT ` + tableColumns + `
+		FROM restaurant_tables
+		WHERE restaurant_id::text = $1
+		ORDER BY zone, name, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListTables, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+	defer rows.Close()
+
+	tables := make([]*domain.Table, 0)
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+
+	return tables, nil
+}
+
+func (r *TableRepository) Update(ctx context.Context, table *domain.Table) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		UPDATE restaurant_tables
+		SET name = $2, seats = $3, zone = $4, is_smoking = $5, is_outdoor = $6, updated_at = $7, is_accessible = $8
+		WHERE id::text = $1
+		RETURNING restaurant_id, created_at
+	`
+
+	table.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	err = executor.QueryRow(ctx, query,
+		table.ID,
+		table.Name,
+		table.Seats,
+		table.Zone,
+		table.IsSmoking,
+		table.IsOutdoor,
+		table.UpdatedAt,
+		table.IsAccessible,
+	).Scan(&table.RestaurantID, &table.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errors.New(common.ErrTableNotFound)
+		}
+		log.Error(ctx, common.ErrUpdateTable, zap.String("id", table.ID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrUpdateTable, err)
+	}
+
+	return nil
+}
+
+func (r *TableRepository) ListHolds(ctx context.Context, restaurantID string, date time.Time) ([]domain.TableHold, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT table_id::text, id::text, date, time, duration
+		FROM bookings
+		WHERE restaurant_id::text = $1 AND date = $2 AND table_id IS NOT NULL AND status IN ('pending', 'confirmed')
+		ORDER BY time, id
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, restaurantID, date.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListTables, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+	defer rows.Close()
+
+	holds := make([]domain.TableHold, 0)
+	for rows.Next() {
+		var hold domain.TableHold
+		if err := rows.Scan(&hold.TableID, &hold.BookingID, &hold.Date, &hold.Time, &hold.Duration); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+		}
+		holds = append(holds, hold)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+
+	return holds, nil
+}
+
+// ListSeatedRestaurants returns the restaurants with pending or confirmed bookings seated at
+// tables on the date.
+func (r *TableRepository) ListSeatedRestaurants(ctx context.Context, date time.Time) ([]string, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT DISTINCT restaurant_id::text
+		FROM bookings
+		WHERE date = $1 AND table_id IS NOT NULL AND status IN ('pending', 'confirmed')
+		ORDER BY 1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	rows, err := executor.Query(ctx, query, date.Format("2006-01-02"))
+	if err != nil {
+		log.Error(ctx, common.ErrListTables, zap.Time("date", date), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+	defer rows.Close()
+
+	restaurantIDs := make([]string, 0)
+	for rows.Next() {
+		var restaurantID string
+		if err := rows.Scan(&restaurantID); err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+		}
+		restaurantIDs = append(restaurantIDs, restaurantID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrListTables, err)
+	}
+
+	return restaurantIDs, nil
+}
+
+// Reseat moves the bookings to the tables, keyed by booking ID, all of them or none. The tables of
+// the bookings are let go of before any is taken again, as a table may pass from one of them to
+// another and the constraint keeping a table from overlapping bookings is checked row by row.
+func (r *TableRepository) Reseat(ctx context.Context, moves map[string]string, at time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	bookingIDs := make([]string, 0, len(moves))
+	for bookingID := range moves {
+		bookingIDs = append(bookingIDs, bookingID)
+	}
+	slices.Sort(bookingIDs)
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const releaseQuery = `
+			UPDATE bookings
+			SET table_id = NULL
+			WHERE id::text = ANY($1) AND status IN ('pending', 'confirmed')
+		`
+		tag, err := tx.Exec(ctx, releaseQuery, bookingIDs)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() != int64(len(bookingIDs)) {
+			// A booking was cancelled or completed since the seating was worked out.
+			return errors.New(common.ErrTableUnavailable)
+		}
+
+		const seatQuery = `UPDATE bookings SET table_id = $2::uuid, updated_at = $3 WHERE id::text = $1`
+		for _, bookingID := range bookingIDs {
+			_, err := tx.Exec(ctx, seatQuery, bookingID, moves[bookingID], at)
+			if tableTaken(err) {
+				return errors.New(common.ErrTableUnavailable)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if err.Error() == common.ErrTableUnavailable {
+			return err
+		}
+		log.Error(ctx, common.ErrReseatBookings, zap.Int("bookings", len(moves)), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrReseatBookings, err)
+	}
+
+	return nil
+}
+
+// Delete locks the table while it looks for bookings holding it, which keeps new bookings from
+// taking it until the table is gone. Past bookings of the table are kept without it.
+func (r *TableRepository) Delete(ctx context.Context, id string) error {
+	log, _ := logger.FromContext(ctx)
+
+	err := r.WithTransaction(ctx, func(tx pgx.Tx) error {
+		const lockQuery = `SELECT 1 FROM restaurant_tables WHERE id::text = $1 FOR UPDATE`
+
+		var found int
+		if err := tx.QueryRow(ctx, lockQuery, id).Scan(&found); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errors.New(common.ErrTableNotFound)
+			}
+			return err
+		}
+
+		const bookedQuery = `
+			SELECT EXISTS (
+				SELECT 1 FROM bookings
+				WHERE table_id::text = $1 AND date >= $2 AND status IN ('pending', 'confirmed')
+			)
+		`
+
+		var booked bool
+		if err := tx.QueryRow(ctx, bookedQuery, id, time.Now().Format("2006-01-02")).Scan(&booked); err != nil {
+			return err
+		}
+		if booked {
+			return errors.New(common.ErrTableBooked)
+		}
+
+		const deleteQuery = `DELETE FROM restaurant_tables WHERE id::text = $1`
+
+		_, err := tx.Exec(ctx, deleteQuery, id)
+		return err
+	})
+	if err != nil {
+		switch err.Error() {
+		case common.ErrTableNotFound, common.ErrTableBooked:
+			return err
+		}
+		log.Error(ctx, common.ErrDeleteTable, zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrDeleteTable, err)
+	}
+
+	return nil
+}
+
+func scanTable(row pgx.Row) (*domain.Table, error) {
+	var table domain.Table
+	err := row.Scan(
+		&table.ID,
+		&table.RestaurantID,
+		&table.Name,
+		&table.Seats,
+		&table.Zone,
+		&table.IsSmoking,
+		&table.IsOutdoor,
+		&table.IsAccessible,
+		&table.CreatedAt,
+		&table.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type txContextKey struct{}
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// Transactor runs several repository calls in one transaction. Repositories called with the
+// context passed to fn use that transaction instead of acquiring their own connection.
+type Transactor struct {
+	*Repository
+}
+
+func NewTransactor(repository *Repository) *Transactor {
+	return &Transactor{
+		Repository: repository,
+	}
+}
+
+func (t *Transactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.run(ctx, false, fn)
+}
+
+// DryRun runs fn in a transaction that is always rolled back, so the caller can see
+// the changes fn would make without persisting them.
+func (t *Transactor) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.run(ctx, true, fn)
+}
+
+func (t *Transactor) run(ctx context.Context, rollback bool, fn func(ctx context.Context) error) error {
+	if outer, ok := txFromContext(ctx); ok {
+		tx, err := outer.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", common.ErrBeginTransaction, err)
+		}
+		return finishTransaction(ctx, tx, rollback, fn)
+	}
+
+	conn, err := t.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrAcquireConnection, err)
+	}
+	defer conn.Release()
+
+	pgxConn, ok := conn.(*postgres.PgxConnWrapper)
+	if !ok {
+		return fmt.Errorf("%s: %w", common.ErrUnknownConnectionType, errors.New("expected PgxConnWrapper"))
+	}
+
+	tx, err := pgxConn.Conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", common.ErrBeginTransaction, err)
+	}
+
+	return finishTransaction(ctx, tx, rollback, fn)
+}
+
+func finishTransaction(ctx context.Context, tx pgx.Tx, rollback bool, fn func(ctx context.Context) error) error {
+	if err := fn(context.WithValue(ctx, txContextKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%s: %v, original error: %w", common.ErrRollbackTransaction, rbErr, err)
+		}
+		return err
+	}
+
+	if rollback {
+		if err := tx.Rollback(ctx); err != nil {
+			return fmt.Errorf("%s: %w", common.ErrRollbackTransaction, err)
+		}
+		return nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", common.ErrCommitTransaction, err)
+	}
+
+	return nil
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
@@ -27,21 +26,22 @@ func NewUserRepository(repository *Repository) *UserRepository {
 	}
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	_, err := logger.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", common.ErrScanUser, err)
 	}
 
 	const query = `
-		SELECT id, name, email, phone, created_at, updated_at
+		SELECT id, name, email, phone, created_at, updated_at, normalized_email, normalized_phone
 		FROM users
 		WHERE id = $1
 	`
 
-	return r.getUserByQuery(ctx, query, id)
+	return r.getUserByQuery(ctx, query, id.String())
 }
 
+// GetByEmail finds the user by the normalized email.
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	_, err := logger.FromContext(ctx)
 	if err != nil {
@@ -49,9 +49,9 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	}
 
 	const query = `
-		SELECT id, name, email, phone, created_at, updated_at
+		SELECT id, name, email, phone, created_at, updated_at, normalized_email, normalized_phone
 		FROM users
-		WHERE email = $1
+		WHERE normalized_email = $1
 	`
 
 	return r.getUserByQuery(ctx, query, email)
@@ -78,6 +78,8 @@ func (r *UserRepository) getUserByQuery(ctx context.Context, query string, param
 		&user.Phone,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&user.NormalizedEmail,
+		&user.NormalizedPhone,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -96,12 +98,12 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	log, _ := logger.FromContext(ctx)
 
 	if user.ID == "" {
-		user.ID = uuid.New().String()
+		user.ID = r.ids.NewID()
 	}
 
 	const query = `
-		INSERT INTO users (id, name, email, phone, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, name, email, phone, created_at, updated_at, normalized_email, normalized_phone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
 	now := time.Now()
@@ -119,7 +121,7 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	}
 	defer release()
 
-	exists, err := r.checkEmailExists(ctx, user.Email, executor)
+	exists, err := r.checkEmailExists(ctx, user.NormalizedEmail, executor)
 	if err != nil {
 		log.Error(ctx, common.ErrCheckEmailExistence,
 			zap.String("email", user.Email),
@@ -137,6 +139,8 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		user.Phone,
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.NormalizedEmail,
+		user.NormalizedPhone,
 	)
 	if err != nil {
 		log.Error(ctx, common.ErrCreateUser,
@@ -153,7 +157,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 
 	const query = `
 		UPDATE users
-		SET name = $2, email = $3, phone = $4, updated_at = $5
+		SET name = $2, email = $3, phone = $4, updated_at = $5, normalized_email = $6, normalized_phone = $7
 		WHERE id = $1
 	`
 
@@ -166,7 +170,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	}
 	defer release()
 
-	currentUser, err := r.GetByID(ctx, user.ID)
+	currentUser, err := r.GetByID(ctx, domain.UserID(user.ID))
 	if err != nil {
 		if errors.Is(err, ErrUserNotFound) {
 			log.Warn(ctx, common.WarnUserForUpdateNotFound,
@@ -180,7 +184,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	}
 
 	if currentUser.Email != user.Email {
-		exists, err := r.checkEmailExists(ctx, user.Email, executor)
+		exists, err := r.checkEmailExists(ctx, user.NormalizedEmail, executor)
 		if err != nil {
 			log.Error(ctx, common.ErrCheckEmailExistence,
 				zap.String("email", user.Email),
@@ -198,6 +202,8 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		user.Email,
 		user.Phone,
 		user.UpdatedAt,
+		user.NormalizedEmail,
+		user.NormalizedPhone,
 	)
 	if err != nil {
 		log.Error(ctx, common.ErrUpdateUser,
@@ -213,9 +219,10 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
+// checkEmailExists reports whether a user has the normalized email.
 func (r *UserRepository) checkEmailExists(ctx context.Context, email string, executor DBExecutor) (bool, error) {
 	const query = `
-		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)
+		SELECT EXISTS(SELECT 1 FROM users WHERE normalized_email = $1)
 	`
 
 	var exists bool
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type WidgetSettingsRepository struct {
+	*Repository
+}
+
+func NewWidgetSettingsRepository(repository *Repository) *WidgetSettingsRepository {
+	return &WidgetSettingsRepository{
+		Repository: repository,
+	}
+}
+
+func (r *WidgetSettingsRepository) Get(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error) {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		SELECT restaurant_id, primary_color, background_color, text_color, default_party_size, locale, updated_at
+		FROM widget_settings
+		WHERE restaurant_id::text = $1
+	`
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return nil, err
+	}
+	defer release()
+
+	var settings domain.WidgetSettings
+	err = executor.QueryRow(ctx, query, restaurantID).Scan(
+		&settings.RestaurantID,
+		&settings.Theme.PrimaryColor,
+		&settings.Theme.BackgroundColor,
+		&settings.Theme.TextColor,
+		&settings.DefaultPartySize,
+		&settings.Locale,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.New(common.ErrWidgetSettingsNotFound)
+		}
+		log.Error(ctx, common.ErrGetWidgetSettings, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return nil, fmt.Errorf("%s: %w", common.ErrGetWidgetSettings, err)
+	}
+
+	return &settings, nil
+}
+
+func (r *WidgetSettingsRepository) Save(ctx context.Context, settings *domain.WidgetSettings) error {
+	log, _ := logger.FromContext(ctx)
+
+	const query = `
+		INSERT INTO widget_settings (restaurant_id, primary_color, background_color, text_color, default_party_size, locale, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (restaurant_id) DO UPDATE
+		SET primary_color = EXCLUDED.primary_color, background_color = EXCLUDED.background_color,
+			text_color = EXCLUDED.text_color, default_party_size = EXCLUDED.default_party_size,
+			locale = EXCLUDED.locale, updated_at = EXCLUDED.updated_at
+	`
+
+	settings.UpdatedAt = time.Now()
+
+	executor, release, err := r.GetExecutor(ctx)
+	if err != nil {
+		log.Error(ctx, common.ErrGetQueryExecutor, zap.Error(err))
+		return err
+	}
+	defer release()
+
+	_, err = executor.Exec(ctx, query,
+		settings.RestaurantID,
+		settings.Theme.PrimaryColor,
+		settings.Theme.BackgroundColor,
+		settings.Theme.TextColor,
+		settings.DefaultPartySize,
+		settings.Locale,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		log.Error(ctx, common.ErrSaveWidgetSettings, zap.String("restaurantID", settings.RestaurantID), zap.Error(err))
+		return fmt.Errorf("%s: %w", common.ErrSaveWidgetSettings, err)
+	}
+
+	return nil
+}
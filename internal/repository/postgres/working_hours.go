@@ -10,7 +10,6 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 
-	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
@@ -29,14 +28,14 @@ func NewWorkingHoursRepository(repository *Repository) *WorkingHoursRepository {
 	}
 }
 
-func (r *WorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (r *WorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	logger, err := logger.FromContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", common.ErrExecuteWorkingHoursQuery, err)
 	}
 
 	const query = `
-		SELECT id, restaurant_id, week_day, open_time, close_time, valid_from, valid_to
+		SELECT id, restaurant_id, week_day, open_time, close_time, is_closed, valid_from, valid_to
 		FROM working_hours
 		WHERE restaurant_id = $1 AND (valid_to IS NULL OR valid_to > CURRENT_DATE)
 		ORDER BY week_day, open_time
@@ -49,7 +48,7 @@ func (r *WorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaura
 	}
 	defer release()
 
-	rows, err := executor.Query(ctx, query, restaurantID)
+	rows, err := executor.Query(ctx, query, restaurantID.String())
 	if err != nil {
 		logger.Error(ctx, common.ErrExecuteWorkingHoursQuery, zap.Error(err))
 		return nil, fmt.Errorf("%s: %w", common.ErrExecuteWorkingHoursQuery, err)
@@ -67,6 +66,7 @@ func (r *WorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaura
 			&h.WeekDay,
 			&h.OpenTime,
 			&h.CloseTime,
+			&h.IsClosed,
 			&h.ValidFrom,
 			&validTo,
 		)
@@ -94,7 +94,7 @@ func (r *WorkingHoursRepository) SetWorkingHours(ctx context.Context, hours *dom
 	log, _ := logger.FromContext(ctx)
 
 	if hours.ID == "" {
-		hours.ID = uuid.New().String()
+		hours.ID = r.ids.NewID()
 	}
 
 	const checkQuery = `
@@ -175,10 +175,44 @@ func (r *WorkingHoursRepository) SetWorkingHours(ctx context.Context, hours *dom
 			return err
 		}
 
-		return nil
+		return touchRestaurant(ctx, tx, hours.RestaurantID, now)
 	})
 }
 
+// CreateBatch inserts working hours of newly created restaurants with COPY. Unlike SetWorkingHours
+// it does not close previous periods, so it must only be used for restaurants without hours.
+func (r *WorkingHoursRepository) CreateBatch(ctx context.Context, hours []*domain.WorkingHours) error {
+	if len(hours) == 0 {
+		return nil
+	}
+
+	log, _ := logger.FromContext(ctx)
+
+	columns := []string{"id", "restaurant_id", "week_day", "open_time", "close_time", "is_closed", "valid_from", "valid_to"}
+
+	rows := make([][]any, 0, len(hours))
+	for _, h := range hours {
+		if h.ID == "" {
+			h.ID = r.ids.NewID()
+		}
+		var validTo *time.Time
+		if !h.ValidTo.IsZero() {
+			validTo = &h.ValidTo
+		}
+
+		rows = append(rows, []any{h.ID, h.RestaurantID, h.WeekDay, h.OpenTime, h.CloseTime, h.IsClosed, h.ValidFrom, validTo})
+	}
+
+	if err := r.copyRows(ctx, "working_hours", columns, rows); err != nil {
+		log.Error(ctx, common.ErrInsertWorkingHours,
+			zap.Int("count", len(hours)),
+			zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 func (r *WorkingHoursRepository) DeleteWorkingHours(ctx context.Context, id string) error {
 	log, _ := logger.FromContext(ctx)
 
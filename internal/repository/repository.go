@@ -8,44 +8,577 @@ import (
 )
 
 type RestaurantRepository interface {
-	GetByID(ctx context.Context, id string) (*domain.Restaurant, error)
-	List(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error)
+	GetByID(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error)
+	GetBySlug(ctx context.Context, slug string) (*domain.Restaurant, error)
+	IsSlugTaken(ctx context.Context, slug, restaurantID string) (bool, error)
+	// ListAfter returns up to limit restaurants after the cursor, ordered by name and ID, narrowed
+	// to the city and adult-only venues of the filter; its other fields are ignored.
+	ListAfter(ctx context.Context, filter domain.RestaurantFilter, after domain.RestaurantCursor, limit int) ([]*domain.Restaurant, error)
+	// ListLive returns up to limit restaurants from offset, ordered by name, without test
+	// restaurants. The other pages of restaurants below are ordered and cut the same way.
+	ListLive(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error)
+	// ListByCity returns a page of the restaurants whose address was placed in the city.
+	ListByCity(ctx context.Context, cityID string, offset, limit int) ([]*domain.Restaurant, error)
+	// Search returns a page of the restaurants matching the filter.
+	Search(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error)
+	// ListSisters returns a page of the other restaurants of the organization the restaurant
+	// belongs to.
+	ListSisters(ctx context.Context, restaurantID domain.RestaurantID, offset, limit int) ([]*domain.Restaurant, error)
+	// EstimateCount returns the number of restaurants from the table statistics instead of counting
+	// them, or -1 when the statistics are not gathered yet.
+	EstimateCount(ctx context.Context) (int64, error)
 	Create(ctx context.Context, restaurant *domain.Restaurant) error
+	CreateBatch(ctx context.Context, restaurants []*domain.Restaurant) error
 	Update(ctx context.Context, restaurant *domain.Restaurant) error
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id domain.RestaurantID) error
 
-	AddFact(ctx context.Context, restaurantID string, fact domain.Fact) (*domain.Fact, error)
-	GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error)
+	AddFact(ctx context.Context, restaurantID domain.RestaurantID, fact domain.Fact) (*domain.Fact, error)
+	GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error)
 	GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error)
+	GetFactsPool(ctx context.Context, filter domain.FactFilter, limit int) ([]domain.Fact, error)
+}
+
+// Transactor runs fn in one transaction shared by every repository call made with the context
+// passed to fn. DryRun always rolls the transaction back.
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	DryRun(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type WorkingHoursRepository interface {
-	GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error)
+	GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error)
 	SetWorkingHours(ctx context.Context, hours *domain.WorkingHours) error
+	CreateBatch(ctx context.Context, hours []*domain.WorkingHours) error
 	DeleteWorkingHours(ctx context.Context, id string) error
 }
 
 type AvailabilityRepository interface {
-	GetByRestaurantAndDate(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error)
-	SetAvailability(ctx context.Context, availability *domain.Availability) error
+	GetByID(ctx context.Context, id string) (*domain.Availability, error)
+	// GetByRestaurantAndDate returns the slots of the restaurant on the date. The bookings of a
+	// restaurant with tables are seated at tables rather than counted against the slots, so the
+	// seats its free tables cannot offer at the time of a slot count as reserved too.
+	GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error)
+	SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error
+	// CreateBatch inserts slots that do not exist yet, with no reserved seats.
+	CreateBatch(ctx context.Context, slots []*domain.Availability) error
 	UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error
+	// ReconcileReservedSeats recounts the reserved seats of the slots from the guests of the
+	// active bookings not seated at a table and of the held drafts.
+	ReconcileReservedSeats(ctx context.Context, from, to time.Time) ([]domain.ReservedSeatsDrift, error)
+	// ListChanges returns up to limit slots of the restaurant changed after the cursor, in the
+	// order of change.
+	ListChanges(ctx context.Context, restaurantID domain.RestaurantID, after domain.SyncCursor, limit int) ([]*domain.Availability, error)
+	// Delete deletes the slot; a slot with reserved seats is kept and the error is
+	// common.ErrAvailabilityReserved.
+	Delete(ctx context.Context, id string) error
 }
 
 type BookingRepository interface {
-	GetByID(ctx context.Context, id string) (*domain.Booking, error)
-	GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.Booking, error)
-	GetByUserID(ctx context.Context, userID string) ([]*domain.Booking, error)
+	GetByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error)
+	GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.Booking, error)
+	GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Booking, error)
+	// ListByRestaurant returns up to limit bookings of the restaurant passing the filter after the
+	// cursor, the latest first.
+	ListByRestaurant(ctx context.Context, restaurantID domain.RestaurantID, filter domain.BookingFilter, after domain.BookingCursor, limit int) ([]*domain.Booking, error)
+	// ListByUser returns up to limit bookings of the user after the cursor, the latest first.
+	ListByUser(ctx context.Context, userID domain.UserID, after domain.BookingCursor, limit int) ([]*domain.Booking, error)
+	GetActiveBetween(ctx context.Context, from, to time.Time) ([]*domain.Booking, error)
+	// Create fails with common.ErrTableUnavailable when another pending or confirmed booking of the
+	// same date and time holds the table of the booking.
 	Create(ctx context.Context, booking *domain.Booking) error
-	UpdateStatus(ctx context.Context, id string, status domain.BookingStatus) error
+	UpdateStatus(ctx context.Context, id domain.BookingID, status domain.BookingStatus) error
+	// ChangeStatus moves the booking to the status as of at only while it is in one of the from
+	// statuses, checked under the lock of the booking, and reports whether it did.
+	ChangeStatus(ctx context.Context, id domain.BookingID, from []domain.BookingStatus, status domain.BookingStatus, at time.Time) (bool, error)
 	AddAlternative(ctx context.Context, alternative *domain.BookingAlternative) error
 	GetAlternativeByID(ctx context.Context, alternativeID string) (*domain.BookingAlternative, error)
-	AcceptAlternative(ctx context.Context, alternativeID string) error
+	// AcceptAlternative moves the booking to the date and time of the alternative, seated at the
+	// table, none for a restaurant without tables, and confirms it. The error is
+	// common.ErrTableUnavailable when another booking holds the table meanwhile.
+	AcceptAlternative(ctx context.Context, alternativeID, tableID string) error
 	RejectAlternative(ctx context.Context, alternativeID string) error
 }
 
+// BookingLinkRepository stores the links that grant access to a booking without logging in.
+// MarkUsed fails for a link that has been used before.
+type BookingLinkRepository interface {
+	Create(ctx context.Context, link *domain.BookingLink) error
+	GetByID(ctx context.Context, id string) (*domain.BookingLink, error)
+	MarkUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
 type UserRepository interface {
-	GetByID(ctx context.Context, id string) (*domain.User, error)
+	GetByID(ctx context.Context, id domain.UserID) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	Create(ctx context.Context, user *domain.User) error
 	Update(ctx context.Context, user *domain.User) error
 }
+
+// NotificationSettingsRepository stores the notification preferences and quiet hours of users and
+// restaurants. Save replaces every stored preference and quiet hours of the recipient.
+type NotificationSettingsRepository interface {
+	Get(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error)
+	Save(ctx context.Context, settings *domain.NotificationSettings) error
+}
+
+// NotificationReceiptRepository tracks the delivery and reading of stored notifications.
+// GetByRelatedID returns the notifications of every recipient about one entity, such as a booking.
+type NotificationReceiptRepository interface {
+	GetByID(ctx context.Context, id string) (*domain.Notification, error)
+	GetByRelatedID(ctx context.Context, relatedID string) ([]domain.Notification, error)
+	MarkRead(ctx context.Context, id string, readAt time.Time) error
+}
+
+// NotificationRepository stores the notifications of users and restaurants.
+type NotificationRepository interface {
+	NotificationReceiptRepository
+	Create(ctx context.Context, notification *domain.Notification) error
+	NotifyUser(ctx context.Context, userID string, notificationType domain.NotificationType, title, message, relatedID string) error
+	NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message, relatedID string) error
+	GetByUserID(ctx context.Context, userID string) ([]domain.Notification, error)
+	GetByRestaurantID(ctx context.Context, restaurantID string) ([]domain.Notification, error)
+	MarkAsRead(ctx context.Context, notificationID string) error
+}
+
+// MenuRepository stores the menus of restaurants and the items pre-ordered for bookings.
+// SaveMenu keeps the IDs of listed items and removes the items left out; SavePreOrder replaces
+// every pre-ordered item of the booking.
+type MenuRepository interface {
+	GetMenu(ctx context.Context, restaurantID string) ([]domain.MenuItem, error)
+	SaveMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) error
+	GetPreOrder(ctx context.Context, bookingID string) ([]domain.PreOrderItem, error)
+	SavePreOrder(ctx context.Context, bookingID string, items []domain.PreOrderItem) error
+}
+
+// ImageRepository stores the images uploaded for restaurants and their variants in other formats.
+// Create also stores the variants listed with the image. ListByRestaurant and GetVariants return
+// no image data; ListByRestaurant returns the images oldest first. ClaimPendingVariants marks up to
+// limit pending variants, and those left processing since before staleBefore, as processing and
+// returns them, so that concurrent workers never get the same variant.
+type ImageRepository interface {
+	Create(ctx context.Context, image *domain.RestaurantImage) error
+	GetByID(ctx context.Context, id string) (*domain.RestaurantImage, error)
+	ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error)
+	Delete(ctx context.Context, id string) error
+	GetVariants(ctx context.Context, imageID string) ([]domain.RestaurantImageVariant, error)
+	GetVariantData(ctx context.Context, imageID, contentType string) ([]byte, error)
+	ClaimPendingVariants(ctx context.Context, limit int, staleBefore time.Time) ([]domain.RestaurantImageVariant, error)
+	SaveVariant(ctx context.Context, variant *domain.RestaurantImageVariant) error
+}
+
+// ReviewRepository stores the reviews of bookings, the restaurants' replies to them and the flags
+// raised for admin arbitration. GetByID, ListByRestaurant and ListFlags return the reviews with
+// their reply; ListByRestaurant returns only published reviews, newest first. A review can have at
+// most one open flag, and ResolveFlag only resolves open flags.
+type ReviewRepository interface {
+	Create(ctx context.Context, review *domain.Review) error
+	GetByID(ctx context.Context, id string) (*domain.Review, error)
+	ListByRestaurant(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error)
+	UpdateStatus(ctx context.Context, id string, status domain.ReviewStatus) error
+	SaveReply(ctx context.Context, reply *domain.ReviewReply) error
+	CreateFlag(ctx context.Context, flag *domain.ReviewFlag) error
+	GetFlag(ctx context.Context, id string) (*domain.ReviewFlag, error)
+	ListFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error)
+	ResolveFlag(ctx context.Context, flag *domain.ReviewFlag) error
+}
+
+// RetentionRepository applies the retention policies and keeps their audit entries.
+// PurgeReadNotifications and AnonymizeCompletedBookings return how many notifications or bookings
+// they affected; bookings already anonymized are left alone. ListRuns returns the newest runs first.
+type RetentionRepository interface {
+	PurgeReadNotifications(ctx context.Context, readBefore time.Time) (int, error)
+	AnonymizeCompletedBookings(ctx context.Context, completedBefore time.Time) (int, error)
+	CreateRun(ctx context.Context, run *domain.RetentionRun) error
+	ListRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error)
+}
+
+// ExportRepository reads the records changed in a period for the change export. ListChanges
+// returns the records of the entity updated after after and up to until, ordered by update time
+// and ID. A non-empty afterID continues a previous page: records updated exactly at after with a
+// greater ID are returned too.
+type ExportRepository interface {
+	ListChanges(ctx context.Context, entity domain.ExportEntity, after time.Time, afterID string, until time.Time, limit int) ([]*domain.ExportRecord, error)
+}
+
+// SyncRepository reads the changes of an entity for sync clients. ListChanges returns the changes
+// after the cursor ordered by change time and ID: updated records with their current state and
+// deleted records as tombstones.
+type SyncRepository interface {
+	ListChanges(ctx context.Context, entity domain.ExportEntity, after domain.SyncCursor, limit int) ([]*domain.SyncChange, error)
+}
+
+// NotificationFailureRepository keeps the notifications that could not be delivered until they are
+// retried. ClaimDue returns the pending failures due at now, oldest due first, and moves their next
+// attempt to leaseUntil so that concurrent retries skip them. List returns the failures with the
+// status, or of every status when it is empty, newest first.
+type NotificationFailureRepository interface {
+	Create(ctx context.Context, failure *domain.NotificationFailure) error
+	Update(ctx context.Context, failure *domain.NotificationFailure) error
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.NotificationFailure, error)
+	List(ctx context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error)
+	CountByStatus(ctx context.Context) (map[domain.NotificationFailureStatus]int, error)
+}
+
+// DeferredNotificationRepository keeps the notifications held back by quiet hours until they are
+// delivered. ClaimDue returns the notifications due at now, oldest due first, and moves their
+// delivery to leaseUntil so that concurrent deliveries skip them; Update stores a new delivery
+// time.
+type DeferredNotificationRepository interface {
+	Create(ctx context.Context, notification *domain.DeferredNotification) error
+	Update(ctx context.Context, notification *domain.DeferredNotification) error
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.DeferredNotification, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type RestaurantPerformanceRepository interface {
+	// GetBetween returns the performance of every live restaurant from from up to to, exclusive.
+	GetBetween(ctx context.Context, from, to time.Time) ([]*domain.RestaurantPerformance, error)
+}
+
+// ReengagementRepository finds the guests of live restaurants who have no upcoming booking and
+// could be invited back.
+type ReengagementRepository interface {
+	// ListLapsed returns the guests whose last completed visit was on the date, each with the
+	// restaurant they visited most.
+	ListLapsed(ctx context.Context, lastVisit, today time.Time) ([]*domain.ReengagementCandidate, error)
+	// ListAnniversaries returns the guests who celebrated an anniversary on the date, each with
+	// the restaurant of the celebration.
+	ListAnniversaries(ctx context.Context, visitDate, today time.Time) ([]*domain.ReengagementCandidate, error)
+}
+
+// AnalyticsRepository reads the booking history of restaurants for their analytics.
+type AnalyticsRepository interface {
+	// ListBookings returns the bookings of every status of a restaurant for a date from from up to
+	// to, exclusive, test bookings left out.
+	ListBookings(ctx context.Context, restaurantID string, from, to time.Time) ([]*domain.Booking, error)
+	// CountHourlyDemand counts the bookings of every status of a restaurant for a date from from
+	// up to to, exclusive, by weekday and hour; hours without bookings are left out.
+	CountHourlyDemand(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.HourlyDemand, error)
+	// CountSeated counts the confirmed bookings of a restaurant, test bookings left out, whose
+	// parties are seated at at, and their guests.
+	CountSeated(ctx context.Context, restaurantID string, at time.Time) (bookings, guests int, err error)
+	// GetResponseLatency sums up the responses to the bookings requested at a restaurant from
+	// from up to to, exclusive. The bookings requested on a day the restaurant was closed are left
+	// out of it and of ListSlowResponders.
+	GetResponseLatency(ctx context.Context, restaurantID string, from, to time.Time) (*domain.ResponseLatency, error)
+	// ListSlowResponders returns the live restaurants that answered at least minResponses of the
+	// bookings requested from from up to to with a median above threshold, slowest first.
+	ListSlowResponders(ctx context.Context, from, to time.Time, threshold time.Duration, minResponses int) ([]*domain.ResponseLatency, error)
+	// ListCampaignPerformance sums up by campaign the bookings requested from from up to to at a
+	// restaurant, or at every restaurant when restaurantID is empty, with the most completed
+	// bookings first. Bookings without attribution and test bookings are left out.
+	ListCampaignPerformance(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.CampaignPerformance, error)
+}
+
+type QuotaRepository interface {
+	// GetPlan returns the plan of the restaurant, the free plan without overrides when none was set.
+	GetPlan(ctx context.Context, restaurantID string) (*domain.RestaurantPlan, error)
+	SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error
+	// CountActiveSlots counts the availability slots of the restaurant on today and later.
+	CountActiveSlots(ctx context.Context, restaurantID string, today time.Time) (int, error)
+	// CountBookings counts the bookings requested at the restaurant from from up to to, exclusive,
+	// test bookings aside.
+	CountBookings(ctx context.Context, restaurantID string, from, to time.Time) (int, error)
+}
+
+type BillingRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.Subscription) error
+	GetSubscription(ctx context.Context, id string) (*domain.Subscription, error)
+	// GetActiveSubscription returns the subscription of the restaurant that is not cancelled.
+	GetActiveSubscription(ctx context.Context, restaurantID string) (*domain.Subscription, error)
+	// CancelSubscription ends the subscription at the time, unless it already ended.
+	CancelSubscription(ctx context.Context, id string, at time.Time) error
+	// ListSubscriptions returns the subscriptions of the restaurant, or of every restaurant when it
+	// is empty, newest first.
+	ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error)
+
+	// CreateInvoices bills every subscription active in the month from periodStart up to
+	// periodEnd that has no invoice for it yet, for the share of the month's days it was active,
+	// and returns the new invoices.
+	CreateInvoices(ctx context.Context, periodStart, periodEnd, issuedAt, dueAt time.Time) ([]*domain.Invoice, error)
+	GetInvoice(ctx context.Context, id string) (*domain.Invoice, error)
+	// ListInvoices returns the invoices matching the filter, newest first.
+	ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error)
+	// UpdateInvoicePayment stores the status, payment time and payment reference of the invoice.
+	UpdateInvoicePayment(ctx context.Context, invoice *domain.Invoice) error
+
+	// RecordPaymentEvent keeps the event and reports false when one with its ID was already kept.
+	RecordPaymentEvent(ctx context.Context, event *domain.PaymentEvent) (bool, error)
+}
+
+type IncentiveRepository interface {
+	// ListRules returns the rules, only the enabled ones when enabledOnly is set, oldest first.
+	ListRules(ctx context.Context, enabledOnly bool) ([]*domain.IncentiveRule, error)
+	GetRule(ctx context.Context, id string) (*domain.IncentiveRule, error)
+	CreateRule(ctx context.Context, rule *domain.IncentiveRule) error
+	UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error
+	DeleteRule(ctx context.Context, id string) error
+
+	// CountPreviousBookings counts the bookings the user made, test, rejected and cancelled ones
+	// aside.
+	CountPreviousBookings(ctx context.Context, userID string) (int, error)
+
+	CreateEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error
+	// GetEvaluation returns the latest evaluation of the booking.
+	GetEvaluation(ctx context.Context, bookingID string) (*domain.IncentiveEvaluation, error)
+	// ListEvaluations returns the evaluations for the bookings of the user, or of every user when
+	// it is empty, newest first.
+	ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error)
+}
+
+// RestaurantLocationRepository keeps the geocoded addresses of restaurants and the queue of those
+// waiting to be geocoded.
+type RestaurantLocationRepository interface {
+	// Enqueue queues the address of the restaurant for geocoding unless it was already geocoded
+	// or queued.
+	Enqueue(ctx context.Context, restaurantID, address string) error
+	GetByRestaurant(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error)
+	// ClaimPending marks up to limit queued locations, and those left processing since
+	// staleBefore, as processing and returns them.
+	ClaimPending(ctx context.Context, limit int, staleBefore time.Time) ([]*domain.RestaurantLocation, error)
+	// Save stores the outcome of geocoding a claimed location. A location whose address changed
+	// meanwhile is left queued and not found.
+	Save(ctx context.Context, location *domain.RestaurantLocation) error
+	// ListCities returns the cities with restaurants in the country, or in every country when it
+	// is empty, with their number of restaurants.
+	ListCities(ctx context.Context, countryCode string) ([]*domain.City, error)
+}
+
+// AvailabilityAlertRepository keeps the alerts of guests waiting for a table to free up.
+type AvailabilityAlertRepository interface {
+	// Create fails when the guest already has an active alert for the restaurant and date.
+	Create(ctx context.Context, alert *domain.AvailabilityAlert) error
+	// ListActive returns the active alerts for the date and later, by restaurant and date.
+	ListActive(ctx context.Context, from time.Time) ([]*domain.AvailabilityAlert, error)
+	// MarkNotified marks an active alert notified of the slot; an alert no longer active is not
+	// found.
+	MarkNotified(ctx context.Context, id, timeSlot string, notifiedAt time.Time) error
+	// ExpireBefore marks the active alerts for dates before the date expired and returns how
+	// many there were.
+	ExpireBefore(ctx context.Context, date time.Time) (int, error)
+}
+
+// BookingDraftRepository keeps the drafts of bookings made step by step.
+type BookingDraftRepository interface {
+	Create(ctx context.Context, draft *domain.BookingDraft) error
+	// GetByID locks the draft until the end of the transaction it is read in.
+	GetByID(ctx context.Context, id string) (*domain.BookingDraft, error)
+	// Update stores the status, slot, pre-order, booking and expiry of the draft.
+	Update(ctx context.Context, draft *domain.BookingDraft) error
+	Delete(ctx context.Context, id string) error
+	// ListExpired returns up to limit drafts that expired by now, oldest expiry first.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.BookingDraft, error)
+}
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *domain.Organization) error
+	// AddRestaurant moves the restaurant into the organization, out of the one it was in.
+	AddRestaurant(ctx context.Context, organizationID, restaurantID string) error
+	GetByRestaurant(ctx context.Context, restaurantID string) (*domain.Organization, error)
+}
+
+type BookingTransferRepository interface {
+	// Create fails with common.ErrBookingTransferExists when the booking has a pending transfer.
+	Create(ctx context.Context, transfer *domain.BookingTransfer) error
+	GetByID(ctx context.Context, id string) (*domain.BookingTransfer, error)
+	// ListByBooking returns the transfers of the booking and the one it was created by, newest
+	// first.
+	ListByBooking(ctx context.Context, bookingID string) ([]*domain.BookingTransfer, error)
+	// Decide stores the status, new booking and decision time of a pending transfer; a transfer
+	// that is not pending is not found.
+	Decide(ctx context.Context, transfer *domain.BookingTransfer) error
+}
+
+// SLORepository stores the request metrics and the daily reports of the service level objectives.
+type SLORepository interface {
+	// AddRequestMetrics adds the counts to the ones stored for their hours.
+	AddRequestMetrics(ctx context.Context, metrics []*domain.RequestMetrics) error
+	// SumRequestMetrics returns the counts of the hours from one time, included, to another.
+	SumRequestMetrics(ctx context.Context, from, to time.Time) (*domain.RequestMetrics, error)
+	// SaveReport stores the report of its date, replacing the one stored before.
+	SaveReport(ctx context.Context, report *domain.SLOReport) error
+	// ListReports returns the reports of the dates from one to another, both included, oldest
+	// first.
+	ListReports(ctx context.Context, from, to time.Time) ([]*domain.SLOReport, error)
+}
+
+// BookingSyncRepository keeps the results of the booking operations apps submit after an offline
+// period, by user and key.
+type BookingSyncRepository interface {
+	// Save stores the result; it fails with common.ErrBookingSyncKeyUsed when the user has a
+	// result under the key already.
+	Save(ctx context.Context, result *domain.BookingSyncResult) error
+	GetByKey(ctx context.Context, userID, key string) (*domain.BookingSyncResult, error)
+}
+
+// RestaurantClaimRepository stores the claims of restaurant profiles, the owners the approved ones
+// made and the audit records of the hand-overs.
+type RestaurantClaimRepository interface {
+	// Create fails with common.ErrRestaurantClaimExists when the user has a pending claim of the
+	// restaurant.
+	Create(ctx context.Context, claim *domain.RestaurantClaim) error
+	GetByID(ctx context.Context, id string) (*domain.RestaurantClaim, error)
+	// List returns the claims matching the filter, oldest first.
+	List(ctx context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error)
+	// Decide stores the status, reviewer, note and decision time of a pending claim; a claim that
+	// is not pending is not found.
+	Decide(ctx context.Context, claim *domain.RestaurantClaim) error
+
+	// GetOwner fails with common.ErrRestaurantOwnerNotFound for a restaurant without an owner.
+	GetOwner(ctx context.Context, restaurantID string) (*domain.RestaurantOwnership, error)
+	// SetOwner fails with common.ErrRestaurantAlreadyOwned when the restaurant has an owner.
+	SetOwner(ctx context.Context, ownership *domain.RestaurantOwnership) error
+	AddAuditRecord(ctx context.Context, record *domain.OwnershipAuditRecord) error
+	// ListAuditRecords returns the audit records of the restaurant, newest first.
+	ListAuditRecords(ctx context.Context, restaurantID string) ([]*domain.OwnershipAuditRecord, error)
+}
+
+// CustomDomainRepository stores the custom domains of restaurants; a domain is registered once
+// across all restaurants.
+type CustomDomainRepository interface {
+	// Create fails with common.ErrCustomDomainTaken when the domain is registered.
+	Create(ctx context.Context, customDomain *domain.CustomDomain) error
+	GetByID(ctx context.Context, id string) (*domain.CustomDomain, error)
+	GetByDomain(ctx context.Context, name string) (*domain.CustomDomain, error)
+	// ListByRestaurant returns the domains of the restaurant, oldest first.
+	ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.CustomDomain, error)
+	MarkVerified(ctx context.Context, id string, at time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+// RestaurantClosureRepository stores the periods restaurants are closed; the closures of a
+// restaurant never overlap.
+type RestaurantClosureRepository interface {
+	// Create fails with common.ErrRestaurantClosureOverlaps when the closure shares a day with
+	// another of the restaurant.
+	Create(ctx context.Context, closure *domain.RestaurantClosure) error
+	GetByID(ctx context.Context, id string) (*domain.RestaurantClosure, error)
+	// ListByRestaurant returns the closures of the restaurant that end after the day of from,
+	// earliest first.
+	ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.RestaurantClosure, error)
+	// ListCovering returns the closures of the restaurants that cover the day of date, at most one
+	// a restaurant.
+	ListCovering(ctx context.Context, restaurantIDs []string, date time.Time) ([]*domain.RestaurantClosure, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// SpecialDayRepository stores the special days of restaurants, at most one a restaurant a date.
+type SpecialDayRepository interface {
+	// Set stores the special day, replacing the one the restaurant has on its date.
+	Set(ctx context.Context, day *domain.SpecialDay) error
+	// ListByRestaurant returns the special days of the restaurant from the day of from on,
+	// earliest first.
+	ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.SpecialDay, error)
+	// Delete fails with common.ErrSpecialDayNotFound when the restaurant has no special day on the
+	// day of date.
+	Delete(ctx context.Context, restaurantID string, date time.Time) error
+}
+
+// TableRepository stores the tables of the floor plans of restaurants.
+type TableRepository interface {
+	Create(ctx context.Context, table *domain.Table) error
+	GetByID(ctx context.Context, id string) (*domain.Table, error)
+	// ListByRestaurant returns the tables of the restaurant ordered by zone and name.
+	ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.Table, error)
+	Update(ctx context.Context, table *domain.Table) error
+	// ListHolds returns the tables of the restaurant held by its pending and confirmed bookings of
+	// the date.
+	ListHolds(ctx context.Context, restaurantID string, date time.Time) ([]domain.TableHold, error)
+	// ListSeatedRestaurants returns the restaurants with pending or confirmed bookings seated at
+	// tables on the date.
+	ListSeatedRestaurants(ctx context.Context, date time.Time) ([]string, error)
+	// Reseat moves the pending and confirmed bookings to the tables, keyed by booking ID, and stamps
+	// them updated at at. It moves all of them or none, with common.ErrTableUnavailable when a
+	// table is no longer free or a booking is no longer active.
+	Reseat(ctx context.Context, moves map[string]string, at time.Time) error
+	// Delete deletes the table; a table held by a pending or confirmed booking from today on is
+	// kept and the error is common.ErrTableBooked.
+	Delete(ctx context.Context, id string) error
+}
+
+// WidgetSettingsRepository stores the widget settings of restaurants.
+type WidgetSettingsRepository interface {
+	// Get fails with common.ErrWidgetSettingsNotFound for a restaurant that never saved any.
+	Get(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error)
+	Save(ctx context.Context, settings *domain.WidgetSettings) error
+}
+
+// QueryStatsRepository reads the statistics the database gathers about its tables and statements.
+type QueryStatsRepository interface {
+	// ListSeqScanTables returns up to limit tables of at least minRows live rows that were scanned
+	// sequentially more often than through an index, the most rows read sequentially first.
+	ListSeqScanTables(ctx context.Context, minRows int64, limit int) ([]domain.SeqScanTable, error)
+	// ListScanHeavyQueries returns up to limit statements called at least minCalls times, the most
+	// blocks read per row returned first. It fails with common.ErrQueryStatsUnavailable when the
+	// statements are not tracked.
+	ListScanHeavyQueries(ctx context.Context, minCalls int64, limit int) ([]domain.ScanHeavyQuery, error)
+}
+
+// AccountRepository stores the accounts signing users and restaurants in and their refresh tokens.
+type AccountRepository interface {
+	// Create fails with common.ErrAccountExists when another account has the email or the user has
+	// an account.
+	Create(ctx context.Context, account *domain.Account) error
+	// GetByID and GetByEmail fail with common.ErrAccountNotFound for an unknown account; the email
+	// is the normalized one.
+	GetByID(ctx context.Context, id string) (*domain.Account, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Account, error)
+	// SetVerificationToken replaces the hash of the token that verifies the email of the account;
+	// it fails with common.ErrAccountNotFound for an unknown account.
+	SetVerificationToken(ctx context.Context, id, tokenHash string) error
+	// VerifyEmail marks the email of the account holding the token verified and forgets the token,
+	// so that it works once. It fails with common.ErrVerificationTokenNotFound for an unknown hash.
+	VerifyEmail(ctx context.Context, tokenHash string, at time.Time) (*domain.Account, error)
+
+	// CreateRefreshToken starts a family of its own for a token without one.
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	// GetRefreshToken fails with common.ErrRefreshTokenNotFound for an unknown hash.
+	GetRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	// RevokeRefreshToken revokes a token that is not revoked yet and tells whether it did, so that
+	// of two refreshes racing with one token only one wins.
+	RevokeRefreshToken(ctx context.Context, id string, at time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string, at time.Time) error
+}
+
+// Factory creates the repositories of one storage backend; the repositories and the transactor
+// of a factory share its storage.
+type Factory interface {
+	Restaurant() RestaurantRepository
+	WorkingHours() WorkingHoursRepository
+	Availability() AvailabilityRepository
+	Booking() BookingRepository
+	User() UserRepository
+	Notification() NotificationRepository
+	NotificationSettings() NotificationSettingsRepository
+	BookingLink() BookingLinkRepository
+	Menu() MenuRepository
+	Image() ImageRepository
+	Review() ReviewRepository
+	Retention() RetentionRepository
+	Export() ExportRepository
+	Sync() SyncRepository
+	NotificationFailure() NotificationFailureRepository
+	DeferredNotification() DeferredNotificationRepository
+	RestaurantPerformance() RestaurantPerformanceRepository
+	Reengagement() ReengagementRepository
+	Analytics() AnalyticsRepository
+	Quota() QuotaRepository
+	Billing() BillingRepository
+	Incentive() IncentiveRepository
+	RestaurantLocation() RestaurantLocationRepository
+	AvailabilityAlert() AvailabilityAlertRepository
+	BookingDraft() BookingDraftRepository
+	Organization() OrganizationRepository
+	BookingTransfer() BookingTransferRepository
+	SLO() SLORepository
+	BookingSync() BookingSyncRepository
+	RestaurantClaim() RestaurantClaimRepository
+	CustomDomain() CustomDomainRepository
+	RestaurantClosure() RestaurantClosureRepository
+	SpecialDay() SpecialDayRepository
+	Table() TableRepository
+	WidgetSettings() WidgetSettingsRepository
+	QueryStats() QueryStatsRepository
+	Account() AccountRepository
+	Transactor() Transactor
+}
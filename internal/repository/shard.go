@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// DefaultShard is the name of the only shard when no shards are configured.
+const DefaultShard = "default"
+
+// DefaultVirtualNodes is how many points of the ring each shard owns when not configured.
+const DefaultVirtualNodes = 128
+
+// HashRing places keys on shards by consistent hashing. Each shard owns a number of points of a
+// ring of 64-bit hashes and a key belongs to the shard of the first point at or after the hash of
+// the key. Adding a shard only moves the keys its new points take over, about 1/n of them, so a
+// cluster can grow without moving every restaurant.
+type HashRing struct {
+	points []ringPoint
+	shards []string
+}
+
+type ringPoint struct {
+	hash  uint64
+	shard string
+}
+
+// NewHashRing places virtualNodes points of each shard on the ring; duplicate and empty names
+// are ignored. A ring without shards locates every key on no shard.
+func NewHashRing(shards []string, virtualNodes int) *HashRing {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+
+	ring := &HashRing{}
+	for _, shard := range shards {
+		if shard == "" || slices.Contains(ring.shards, shard) {
+			continue
+		}
+		ring.shards = append(ring.shards, shard)
+		for i := range virtualNodes {
+			ring.points = append(ring.points, ringPoint{hash: ringHash(shard + "#" + strconv.Itoa(i)), shard: shard})
+		}
+	}
+
+	// Points of two shards with the same hash are ordered by name, so every instance agrees.
+	sort.Slice(ring.points, func(i, j int) bool {
+		if ring.points[i].hash != ring.points[j].hash {
+			return ring.points[i].hash < ring.points[j].hash
+		}
+		return ring.points[i].shard < ring.points[j].shard
+	})
+	return ring
+}
+
+// Locate returns the shard the key belongs to, or "" for a ring without shards.
+func (r *HashRing) Locate(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+
+	hash := ringHash(key)
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].shard
+}
+
+// Shards returns the shards of the ring in the order they were given.
+func (r *HashRing) Shards() []string {
+	return slices.Clone(r.shards)
+}
+
+func ringHash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	// FNV spreads similar keys such as "shard#1" and "shard#2" poorly over the high bits; the
+	// finalizer of SplitMix64 mixes them in.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type AbuseHandler struct {
+	abuseUseCase usecase.AbuseUseCase
+}
+
+func NewAbuseHandler(abuseUseCase usecase.AbuseUseCase) *AbuseHandler {
+	return &AbuseHandler{
+		abuseUseCase: abuseUseCase,
+	}
+}
+
+type AbuseEventResponse struct {
+	ID           string                `json:"id"`
+	Kind         domain.AbuseEventKind `json:"kind"`
+	ClientIP     string                `json:"client_ip,omitempty"`
+	UserID       string                `json:"user_id,omitempty"`
+	RestaurantID string                `json:"restaurant_id,omitempty"`
+	Count        int                   `json:"count"`
+	// WindowSeconds is the period Count was seen within.
+	WindowSeconds  int64      `json:"window_seconds"`
+	TightenedUntil *time.Time `json:"tightened_until,omitempty"`
+	DetectedAt     time.Time  `json:"detected_at"`
+}
+
+func newAbuseEventResponse(event *domain.AbuseEvent) AbuseEventResponse {
+	return AbuseEventResponse{
+		ID:             event.ID,
+		Kind:           event.Kind,
+		ClientIP:       event.ClientIP,
+		UserID:         event.UserID,
+		RestaurantID:   event.RestaurantID,
+		Count:          event.Count,
+		WindowSeconds:  int64(event.Window / time.Second),
+		TightenedUntil: event.TightenedUntil,
+		DetectedAt:     event.DetectedAt,
+	}
+}
+
+// ListAbuseEvents godoc
+// @Summary List abuse events
+// @Description Suspicious booking patterns of clients, such as booking bursts from one address and cancel-rebook loops holding the seats of a restaurant, newest first
+// @Tags admin
+// @Produce json
+// @Param kind query string false "Only events of this kind: booking_burst or cancel_rebook"
+// @Success 200 {array} AbuseEventResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/abuse-events [get]
+func (h *AbuseHandler) ListAbuseEvents(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	events, err := h.abuseUseCase.ListAbuseEvents(ctx, domain.AbuseEventKind(c.Query("kind")))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAbuseEventKind) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListAbuseEvents, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(events, newAbuseEventResponse))
+}
@@ -0,0 +1,556 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultForecastDays = 7
+	defaultStatsDays    = 30
+	defaultCampaignDays = 30
+)
+
+type AnalyticsHandler struct {
+	analyticsUseCase usecase.AnalyticsUseCase
+}
+
+func NewAnalyticsHandler(analyticsUseCase usecase.AnalyticsUseCase) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+	}
+}
+
+type SlotForecastResponse struct {
+	TimeSlot           string  `json:"time_slot"`
+	Capacity           int     `json:"capacity"`
+	Reserved           int     `json:"reserved"`
+	ProjectedReserved  float64 `json:"projected_reserved"`
+	ProjectedOccupancy float64 `json:"projected_occupancy"`
+	Samples            int     `json:"samples"`
+}
+
+type DayForecastResponse struct {
+	Date               string                 `json:"date"`
+	ProjectedOccupancy float64                `json:"projected_occupancy"`
+	Slots              []SlotForecastResponse `json:"slots"`
+}
+
+// ForecastResponse projects the occupancy of the upcoming days of a restaurant, as shares of the
+// seats from 0 to 1.
+type ForecastResponse struct {
+	RestaurantID string                `json:"restaurant_id"`
+	Weeks        int                   `json:"weeks"`
+	Days         []DayForecastResponse `json:"days"`
+}
+
+func newForecastResponse(forecast *domain.OccupancyForecast) ForecastResponse {
+	return ForecastResponse{
+		RestaurantID: forecast.RestaurantID,
+		Weeks:        forecast.Weeks,
+		Days: mapResponses(forecast.Days, func(day domain.DayForecast) DayForecastResponse {
+			return DayForecastResponse{
+				Date:               day.Date.Format("2006-01-02"),
+				ProjectedOccupancy: roundShare(day.ProjectedOccupancy()),
+				Slots: mapResponses(day.Slots, func(slot domain.SlotForecast) SlotForecastResponse {
+					return SlotForecastResponse{
+						TimeSlot:           slot.TimeSlot,
+						Capacity:           slot.Capacity,
+						Reserved:           slot.Reserved,
+						ProjectedReserved:  roundShare(slot.ProjectedReserved),
+						ProjectedOccupancy: roundShare(slot.ProjectedOccupancy()),
+						Samples:            slot.Samples,
+					}
+				}),
+			}
+		}),
+	}
+}
+
+// roundShare keeps two decimals, which is all a projection is worth.
+func roundShare(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// GetForecast godoc
+// @Summary Get occupancy forecast
+// @Description Project the occupancy of every slot of the upcoming days from the seats reserved now and the seats booked in the same time before the slots at the same weekday and time over the last weeks. Restaurant staff only
+// @Tags restaurants,availability
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param days query int false "Days from today, up to 28" default(7)
+// @Success 200 {object} ForecastResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/forecast [get]
+func (h *AnalyticsHandler) GetForecast(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	days, err := strconv.Atoi(c.Query("days", strconv.Itoa(defaultForecastDays)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	forecast, err := h.analyticsUseCase.GetForecast(ctx, id, time.Now(), days)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidForecastDays):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetOccupancyForecast, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newForecastResponse(forecast))
+}
+
+// WeekdayDemandResponse lists the bookings requested and their guests for every hour of a weekday.
+type WeekdayDemandResponse struct {
+	Weekday  string `json:"weekday"`
+	Bookings []int  `json:"bookings"`
+	Guests   []int  `json:"guests"`
+}
+
+type DemandHeatmapResponse struct {
+	RestaurantID string                  `json:"restaurant_id"`
+	From         string                  `json:"from"`
+	To           string                  `json:"to"`
+	Weeks        int                     `json:"weeks"`
+	Days         []WeekdayDemandResponse `json:"days"`
+	GeneratedAt  time.Time               `json:"generated_at"`
+}
+
+// heatmapWeekdays lists the days of a heatmap Monday first, as staff rosters do.
+var heatmapWeekdays = []time.Weekday{
+	time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday,
+}
+
+func newDemandHeatmapResponse(heatmap *domain.DemandHeatmap) DemandHeatmapResponse {
+	days := make([]WeekdayDemandResponse, 0, len(heatmapWeekdays))
+	for _, weekday := range heatmapWeekdays {
+		day := WeekdayDemandResponse{
+			Weekday:  strings.ToLower(weekday.String()),
+			Bookings: make([]int, 0, len(heatmap.Cells[weekday])),
+			Guests:   make([]int, 0, len(heatmap.Cells[weekday])),
+		}
+		for _, cell := range heatmap.Cells[weekday] {
+			day.Bookings = append(day.Bookings, cell.Bookings)
+			day.Guests = append(day.Guests, cell.Guests)
+		}
+		days = append(days, day)
+	}
+
+	return DemandHeatmapResponse{
+		RestaurantID: heatmap.RestaurantID,
+		From:         heatmap.From.Format("2006-01-02"),
+		To:           heatmap.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		Weeks:        heatmap.Weeks,
+		Days:         days,
+		GeneratedAt:  heatmap.GeneratedAt,
+	}
+}
+
+// GetDemandHeatmap godoc
+// @Summary Get demand heatmap
+// @Description Count the bookings requested for every weekday and hour over the last weeks, whatever became of them, to plan staffing. Days are listed Monday first with 24 hourly counts each. The heatmap is computed once a day. Restaurant staff only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param weeks query int false "Past weeks to count, up to 52; ANALYTICS_HEATMAP_WEEKS by default"
+// @Success 200 {object} DemandHeatmapResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/demand-heatmap [get]
+func (h *AnalyticsHandler) GetDemandHeatmap(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	weeks, err := strconv.Atoi(c.Query("weeks", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	heatmap, err := h.analyticsUseCase.GetDemandHeatmap(ctx, id, time.Now(), weeks)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidHeatmapWeeks):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetDemandHeatmap, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newDemandHeatmapResponse(heatmap))
+}
+
+// ResponseTimeResponse sums up how fast a restaurant answered booking requests; times are in
+// seconds and zero while no request was answered.
+type ResponseTimeResponse struct {
+	Requests   int     `json:"requests"`
+	Responses  int     `json:"responses"`
+	Unanswered int     `json:"unanswered"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P90Seconds float64 `json:"p90_seconds"`
+	P99Seconds float64 `json:"p99_seconds"`
+}
+
+func newResponseTimeResponse(latency *domain.ResponseLatency) ResponseTimeResponse {
+	return ResponseTimeResponse{
+		Requests:   latency.Requests,
+		Responses:  latency.Responses,
+		Unanswered: latency.Unanswered,
+		P50Seconds: math.Round(latency.P50.Seconds()),
+		P90Seconds: math.Round(latency.P90.Seconds()),
+		P99Seconds: math.Round(latency.P99.Seconds()),
+	}
+}
+
+type RestaurantStatsResponse struct {
+	RestaurantID string               `json:"restaurant_id"`
+	From         string               `json:"from"`
+	To           string               `json:"to"`
+	ResponseTime ResponseTimeResponse `json:"response_time"`
+}
+
+type SlowResponderResponse struct {
+	RestaurantID   string               `json:"restaurant_id"`
+	RestaurantName string               `json:"restaurant_name"`
+	From           string               `json:"from"`
+	To             string               `json:"to"`
+	ResponseTime   ResponseTimeResponse `json:"response_time"`
+}
+
+// GetRestaurantStats godoc
+// @Summary Get restaurant stats
+// @Description Sum up how fast the restaurant confirmed or rejected the bookings requested over the last days, today included: the median, 90th and 99th percentile times from the request to the answer and the requests still unanswered. Restaurant staff only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param days query int false "Past days, up to 365" default(30)
+// @Success 200 {object} RestaurantStatsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/stats [get]
+func (h *AnalyticsHandler) GetRestaurantStats(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	days, err := strconv.Atoi(c.Query("days", strconv.Itoa(defaultStatsDays)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	latency, err := h.analyticsUseCase.GetResponseLatency(ctx, id, time.Now(), days)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidLatencyDays):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetResponseLatency, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(RestaurantStatsResponse{
+		RestaurantID: id,
+		From:         latency.From.Format("2006-01-02"),
+		To:           latency.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		ResponseTime: newResponseTimeResponse(latency),
+	})
+}
+
+// OccupancyNowResponse is the live occupancy of a restaurant; Level is quiet, moderate, busy or
+// unknown when guests are seated outside of any slot.
+type OccupancyNowResponse struct {
+	RestaurantID string           `json:"restaurant_id"`
+	Bookings     int              `json:"bookings"`
+	Guests       int              `json:"guests"`
+	Capacity     int              `json:"capacity"`
+	Occupancy    float64          `json:"occupancy"`
+	Level        domain.BusyLevel `json:"level"`
+	At           time.Time        `json:"at"`
+}
+
+// GetOccupancyNow godoc
+// @Summary Get live occupancy
+// @Description Count the confirmed bookings whose parties are seated now, over their duration, against the seats of the slot under way, for a busyness badge. The occupancy is recomputed at most once a minute
+// @Tags restaurants,availability
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} OccupancyNowResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/occupancy-now [get]
+func (h *AnalyticsHandler) GetOccupancyNow(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	occupancy, err := h.analyticsUseCase.GetOccupancyNow(ctx, id, time.Now().UTC())
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetOccupancyNow, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respondCacheable(c, occupancy.At, OccupancyNowResponse{
+		RestaurantID: occupancy.RestaurantID,
+		Bookings:     occupancy.Bookings,
+		Guests:       occupancy.Guests,
+		Capacity:     occupancy.Capacity,
+		Occupancy:    roundShare(occupancy.Occupancy()),
+		Level:        occupancy.Level(),
+		At:           occupancy.At,
+	})
+}
+
+// ListSlowResponders godoc
+// @Summary List slow responding restaurants
+// @Description The restaurants whose median time to confirm or reject a booking request over the last ANALYTICS_SLOW_RESPONSE_DAYS days is above ANALYTICS_SLOW_RESPONSE_THRESHOLD, among those that answered enough requests, slowest first. Admins only
+// @Tags admin
+// @Produce json
+// @Success 200 {array} SlowResponderResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/analytics/slow-responders [get]
+func (h *AnalyticsHandler) ListSlowResponders(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	latencies, err := h.analyticsUseCase.ListSlowResponders(ctx, time.Now())
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListSlowResponders, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(latencies, func(latency *domain.ResponseLatency) SlowResponderResponse {
+		return SlowResponderResponse{
+			RestaurantID:   latency.RestaurantID,
+			RestaurantName: latency.RestaurantName,
+			From:           latency.From.Format("2006-01-02"),
+			To:             latency.To.AddDate(0, 0, -1).Format("2006-01-02"),
+			ResponseTime:   newResponseTimeResponse(latency),
+		}
+	}))
+}
+
+// CampaignPerformanceResponse sums up the bookings a campaign brought; the conversion rate is the
+// share of them that were completed, from 0 to 1.
+type CampaignPerformanceResponse struct {
+	UTMSource      string  `json:"utm_source"`
+	UTMMedium      string  `json:"utm_medium"`
+	UTMCampaign    string  `json:"utm_campaign"`
+	ReferralCode   string  `json:"referral_code"`
+	Bookings       int     `json:"bookings"`
+	Completed      int     `json:"completed"`
+	Cancelled      int     `json:"cancelled"`
+	Guests         int     `json:"guests"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+type CampaignReportResponse struct {
+	RestaurantID string                        `json:"restaurant_id,omitempty"`
+	From         string                        `json:"from"`
+	To           string                        `json:"to"`
+	Campaigns    []CampaignPerformanceResponse `json:"campaigns"`
+}
+
+// ListCampaignPerformance godoc
+// @Summary List campaign performance
+// @Description Sum up by UTM source, medium and campaign and referral code the bookings requested over the last days, today included: how many were requested, completed and cancelled or rejected, the guests of the completed ones and the conversion rate. Campaigns with the most completed bookings come first; bookings without a campaign and test bookings are left out. Admins only
+// @Tags admin
+// @Produce json
+// @Param days query int false "Past days, up to 365" default(30)
+// @Param restaurant_id query string false "Only the bookings of this restaurant"
+// @Success 200 {object} CampaignReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/analytics/campaigns [get]
+func (h *AnalyticsHandler) ListCampaignPerformance(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	days, err := strconv.Atoi(c.Query("days", strconv.Itoa(defaultCampaignDays)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+	restaurantID := c.Query("restaurant_id")
+
+	now := time.Now()
+	campaigns, err := h.analyticsUseCase.ListCampaignPerformance(ctx, restaurantID, now, days)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCampaignDays):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrListCampaignPerformance, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return c.Status(fiber.StatusOK).JSON(CampaignReportResponse{
+		RestaurantID: restaurantID,
+		From:         today.AddDate(0, 0, 1-days).Format("2006-01-02"),
+		To:           today.Format("2006-01-02"),
+		Campaigns: mapResponses(campaigns, func(performance domain.CampaignPerformance) CampaignPerformanceResponse {
+			return CampaignPerformanceResponse{
+				UTMSource:      performance.Source,
+				UTMMedium:      performance.Medium,
+				UTMCampaign:    performance.Campaign,
+				ReferralCode:   performance.ReferralCode,
+				Bookings:       performance.Bookings,
+				Completed:      performance.Completed,
+				Cancelled:      performance.Cancelled,
+				Guests:         performance.Guests,
+				ConversionRate: roundShare(performance.ConversionRate()),
+			}
+		}),
+	})
+}
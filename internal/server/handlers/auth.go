@@ -0,0 +1,441 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/auth"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type AuthHandler struct {
+	authUseCase usecase.AuthUseCase
+}
+
+func NewAuthHandler(authUseCase usecase.AuthUseCase) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+	}
+}
+
+type RegisterUserRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Password string `json:"password"`
+}
+
+type RegisterRestaurantAccountRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type AccountResponse struct {
+	ID              string             `json:"id"`
+	Kind            domain.AccountKind `json:"kind"`
+	Email           string             `json:"email"`
+	UserID          string             `json:"user_id,omitempty"`
+	RestaurantID    string             `json:"restaurant_id,omitempty"`
+	EmailVerified   bool               `json:"email_verified"`
+	EmailVerifiedAt *time.Time         `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// TokenResponse is what signing in answers: the access token to send as a bearer token, valid for
+// ExpiresIn seconds, and the refresh token to get the next pair with.
+type TokenResponse struct {
+	AccessToken  string          `json:"access_token"`
+	TokenType    string          `json:"token_type"`
+	ExpiresIn    int             `json:"expires_in"`
+	RefreshToken string          `json:"refresh_token"`
+	Account      AccountResponse `json:"account"`
+}
+
+func newAccountResponse(account *domain.Account) AccountResponse {
+	return AccountResponse{
+		ID:              account.ID,
+		Kind:            account.Kind,
+		Email:           account.Email,
+		UserID:          account.UserID,
+		RestaurantID:    account.RestaurantID,
+		EmailVerified:   account.EmailVerifiedAt != nil,
+		EmailVerifiedAt: account.EmailVerifiedAt,
+		CreatedAt:       account.CreatedAt,
+	}
+}
+
+func newTokenResponse(tokens *usecase.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    tokens.ExpiresIn,
+		RefreshToken: tokens.RefreshToken,
+		Account:      newAccountResponse(tokens.Account),
+	}
+}
+
+// RegisterUser godoc
+// @Summary Register a user
+// @Description Create a user with an account and sign it in. The access token is sent as "Authorization: Bearer <token>"; the refresh token gets the next pair from /auth/refresh. A link verifying the email is mailed to it
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param user body RegisterUserRequest true "User and password"
+// @Success 201 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "The email is taken"
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/register [post]
+func (h *AuthHandler) RegisterUser(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request RegisterUserRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	user := &domain.User{
+		Name:  request.Name,
+		Email: request.Email,
+		Phone: request.Phone,
+	}
+
+	tokens, err := h.authUseCase.RegisterUser(ctx, user, request.Password)
+	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateAccount, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newTokenResponse(tokens))
+}
+
+// RegisterRestaurantAccount godoc
+// @Summary Register a restaurant account
+// @Description Create a staff account of the restaurant. Only the staff and the owner of the restaurant and admins may create one
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param account body RegisterRestaurantAccountRequest true "Email and password"
+// @Success 201 {object} AccountResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "The email is taken"
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/restaurants/{id}/accounts [post]
+func (h *AuthHandler) RegisterRestaurantAccount(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request RegisterRestaurantAccountRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	account, err := h.authUseCase.RegisterRestaurantAccount(ctx, restaurantID, request.Email, request.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateAccount, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newAccountResponse(account))
+}
+
+// Login godoc
+// @Summary Sign in
+// @Description Sign a user or a restaurant account in with the email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body LoginRequest true "Email and password"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string "Wrong email or password"
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request LoginRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	tokens, err := h.authUseCase.Login(ctx, request.Email, request.Password)
+	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrGetAccount, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newTokenResponse(tokens))
+}
+
+// RefreshToken godoc
+// @Summary Refresh the tokens
+// @Description Exchange a refresh token for a new pair. A refresh token works once; using it again signs the session out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string "Unknown, expired or used refresh token"
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request RefreshTokenRequest
+	if err := c.Bind().Body(&request); err != nil || request.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	tokens, err := h.authUseCase.Refresh(ctx, request.RefreshToken)
+	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRefreshToken, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newTokenResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Sign out
+// @Description Sign the session of the refresh token out; the access tokens issued to it stay valid until they expire
+// @Tags auth
+// @Accept json
+// @Param token body RefreshTokenRequest true "Refresh token"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request RefreshTokenRequest
+	if err := c.Bind().Body(&request); err != nil || request.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.authUseCase.Logout(ctx, request.RefreshToken); err != nil {
+		log.Error(ctx, common.ErrRevokeRefreshToken, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// VerifyEmail godoc
+// @Summary Verify the email of an account
+// @Description Confirm the email of the account with the token of the link mailed to it. A token works once; the admin role of AUTH_ADMIN_EMAILS is only given to verified accounts, from the next sign-in or refresh on
+// @Tags auth
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} AccountResponse
+// @Failure 400 {object} map[string]string "Unknown or used token"
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/verify-email [get]
+func (h *AuthHandler) VerifyEmail(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	account, err := h.authUseCase.VerifyEmail(ctx, token)
+	if err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrVerifyEmail, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newAccountResponse(account))
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification link
+// @Description Mail a new link verifying the email to the account with the email, replacing the one sent before. The answer is the same whether such an unverified account exists or not
+// @Tags auth
+// @Accept json
+// @Param request body ResendVerificationRequest true "Email"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string "Authentication is not configured"
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request ResendVerificationRequest
+	if err := c.Bind().Body(&request); err != nil || request.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.authUseCase.ResendVerification(ctx, request.Email); err != nil {
+		if status, ok := authErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrVerifyEmail, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// authErrorStatus maps the errors a client can cause while signing in to their status.
+func authErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, usecase.ErrAuthDisabled):
+		return fiber.StatusServiceUnavailable, true
+	case errors.Is(err, usecase.ErrInvalidCredentials), errors.Is(err, usecase.ErrInvalidRefreshToken):
+		return fiber.StatusUnauthorized, true
+	case errors.Is(err, usecase.ErrAccountExists), errors.Is(err, usecase.ErrEmailExists):
+		return fiber.StatusConflict, true
+	case errors.Is(err, usecase.ErrWeakPassword), errors.Is(err, auth.ErrPasswordTooLong),
+		errors.Is(err, usecase.ErrInvalidVerificationToken),
+		errors.Is(err, contact.ErrInvalidEmail), errors.Is(err, contact.ErrInvalidPhone):
+		return fiber.StatusBadRequest, true
+	}
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type AvailabilityAlertHandler struct {
+	availabilityAlertUseCase usecase.AvailabilityAlertUseCase
+}
+
+func NewAvailabilityAlertHandler(availabilityAlertUseCase usecase.AvailabilityAlertUseCase) *AvailabilityAlertHandler {
+	return &AvailabilityAlertHandler{
+		availabilityAlertUseCase: availabilityAlertUseCase,
+	}
+}
+
+type CreateAvailabilityAlertRequest struct {
+	// UserID is the guest to alert; it defaults to the caller.
+	UserID string `json:"user_id"`
+	// Date is the day of the table, YYYY-MM-DD.
+	Date string `json:"date"`
+	// FromTime and ToTime bound the slots of the window, both included, HH:MM.
+	FromTime  string `json:"from_time"`
+	ToTime    string `json:"to_time"`
+	PartySize int    `json:"party_size"`
+}
+
+type AvailabilityAlertResponse struct {
+	ID           string                         `json:"id"`
+	RestaurantID string                         `json:"restaurant_id"`
+	UserID       string                         `json:"user_id"`
+	Date         string                         `json:"date"`
+	FromTime     string                         `json:"from_time"`
+	ToTime       string                         `json:"to_time"`
+	PartySize    int                            `json:"party_size"`
+	Status       domain.AvailabilityAlertStatus `json:"status"`
+	CreatedAt    time.Time                      `json:"created_at"`
+}
+
+func newAvailabilityAlertResponse(alert *domain.AvailabilityAlert) AvailabilityAlertResponse {
+	return AvailabilityAlertResponse{
+		ID:           alert.ID,
+		RestaurantID: alert.RestaurantID,
+		UserID:       alert.UserID,
+		Date:         alert.Date.Format("2006-01-02"),
+		FromTime:     alert.FromTime,
+		ToTime:       alert.ToTime,
+		PartySize:    alert.PartySize,
+		Status:       alert.Status,
+		CreatedAt:    alert.CreatedAt,
+	}
+}
+
+// CreateAvailabilityAlert godoc
+// @Summary Get notified when a table frees up
+// @Description Subscribe a guest to a table for the party at the restaurant in a time window on a date. The guest is notified once, as soon as a slot of the window has room again, e.g. after a cancellation; the alert expires when the date passes. A guest has one active alert per restaurant and date
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param alert body CreateAvailabilityAlertRequest true "Date, time window and party size"
+// @Success 201 {object} AvailabilityAlertResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "A table is already available, or the guest already has an alert for the date"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability-alerts [post]
+func (h *AvailabilityAlertHandler) CreateAvailabilityAlert(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request CreateAvailabilityAlertRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", request.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	alert := &domain.AvailabilityAlert{
+		RestaurantID: id,
+		UserID:       request.UserID,
+		Date:         date,
+		FromTime:     request.FromTime,
+		ToTime:       request.ToTime,
+		PartySize:    request.PartySize,
+	}
+	if err := h.availabilityAlertUseCase.CreateAlert(ctx, alert); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidAvailabilityAlert):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrTableAvailable):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrAvailabilityAlertExists:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrAvailabilityAlertExists,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateAvailabilityAlert, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newAvailabilityAlertResponse(alert))
+}
@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// HeaderBillingSignature carries the hex HMAC-SHA256 of a payment provider event.
+const HeaderBillingSignature = "X-Billing-Signature"
+
+type BillingHandler struct {
+	billingUseCase usecase.BillingUseCase
+}
+
+func NewBillingHandler(billingUseCase usecase.BillingUseCase) *BillingHandler {
+	return &BillingHandler{
+		billingUseCase: billingUseCase,
+	}
+}
+
+type SubscriptionResponse struct {
+	ID           string      `json:"id"`
+	RestaurantID string      `json:"restaurant_id"`
+	Plan         domain.Plan `json:"plan"`
+	MonthlyPrice int64       `json:"monthly_price"`
+	Currency     string      `json:"currency"`
+	StartedAt    time.Time   `json:"started_at"`
+	CancelledAt  *time.Time  `json:"cancelled_at,omitempty"`
+	Active       bool        `json:"active"`
+}
+
+func newSubscriptionResponse(subscription *domain.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		ID:           subscription.ID,
+		RestaurantID: subscription.RestaurantID,
+		Plan:         subscription.Plan,
+		MonthlyPrice: subscription.MonthlyPrice,
+		Currency:     subscription.Currency,
+		StartedAt:    subscription.StartedAt,
+		CancelledAt:  subscription.CancelledAt,
+		Active:       subscription.Active(),
+	}
+}
+
+type InvoiceResponse struct {
+	ID             string               `json:"id"`
+	Number         string               `json:"number"`
+	SubscriptionID string               `json:"subscription_id"`
+	RestaurantID   string               `json:"restaurant_id"`
+	RestaurantName string               `json:"restaurant_name"`
+	Plan           domain.Plan          `json:"plan"`
+	PeriodStart    string               `json:"period_start"`
+	PeriodEnd      string               `json:"period_end"`
+	Amount         int64                `json:"amount"`
+	Currency       string               `json:"currency"`
+	Status         domain.InvoiceStatus `json:"status"`
+	IssuedAt       time.Time            `json:"issued_at"`
+	DueAt          time.Time            `json:"due_at"`
+	PaidAt         *time.Time           `json:"paid_at,omitempty"`
+	PaymentID      string               `json:"payment_id,omitempty"`
+}
+
+func newInvoiceResponse(invoice *domain.Invoice) InvoiceResponse {
+	return InvoiceResponse{
+		ID:             invoice.ID,
+		Number:         invoice.Number,
+		SubscriptionID: invoice.SubscriptionID,
+		RestaurantID:   invoice.RestaurantID,
+		RestaurantName: invoice.RestaurantName,
+		Plan:           invoice.Plan,
+		PeriodStart:    invoice.PeriodStart.Format("2006-01-02"),
+		PeriodEnd:      invoice.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+		Amount:         invoice.Amount,
+		Currency:       invoice.Currency,
+		Status:         invoice.Status,
+		IssuedAt:       invoice.IssuedAt,
+		DueAt:          invoice.DueAt,
+		PaidAt:         invoice.PaidAt,
+		PaymentID:      invoice.PaymentID,
+	}
+}
+
+// CreateSubscriptionRequest puts a restaurant on a paid plan; the plan's price applies when
+// monthly_price is left out.
+type CreateSubscriptionRequest struct {
+	RestaurantID string      `json:"restaurant_id"`
+	Plan         domain.Plan `json:"plan"`
+	MonthlyPrice *int64      `json:"monthly_price"`
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe a restaurant
+// @Description Put a restaurant on a paid plan from now on, ending its current subscription. The quotas of the plan apply at once. Prices are in minor units of the billing currency. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param subscription body CreateSubscriptionRequest true "Restaurant, plan and optional monthly price"
+// @Success 201 {object} SubscriptionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/subscriptions [post]
+func (h *BillingHandler) CreateSubscription(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request CreateSubscriptionRequest
+	if err := c.Bind().Body(&request); err != nil || request.RestaurantID == "" {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	subscription, err := h.billingUseCase.Subscribe(ctx, request.RestaurantID, request.Plan, request.MonthlyPrice, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPlan), errors.Is(err, usecase.ErrInvalidPrice):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateSubscription, zap.String("restaurantID", request.RestaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newSubscriptionResponse(subscription))
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description End a subscription now and move the restaurant back to the free plan. The month it ends in is billed for the days it was active. Admins only
+// @Tags admin
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Subscription not found or already cancelled"
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/subscriptions/{id} [delete]
+func (h *BillingHandler) CancelSubscription(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if err := h.billingUseCase.CancelSubscription(ctx, id, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrSubscriptionNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrSubscriptionNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCancelSubscription, zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSubscriptions godoc
+// @Summary List subscriptions
+// @Description The subscriptions of a restaurant, or of every restaurant, newest first. Admins only
+// @Tags admin
+// @Produce json
+// @Param restaurant_id query string false "Restaurant ID"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} SubscriptionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/subscriptions [get]
+func (h *BillingHandler) ListSubscriptions(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	restaurantID := c.Query("restaurant_id")
+	subscriptions, err := h.billingUseCase.ListSubscriptions(ctx, restaurantID, offset, lookaheadLimit(limit))
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListSubscriptions, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	subscriptions, hasNext := trimPage(subscriptions, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(subscriptions),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(subscriptions, newSubscriptionResponse))
+}
+
+// GenerateInvoices godoc
+// @Summary Generate invoices
+// @Description Bill the subscriptions active in a past month that were not billed for it yet, for the share of its days they were active. The invoice job does this every day for the month before. Admins only
+// @Tags admin
+// @Produce json
+// @Param month query string false "Month as YYYY-MM; the month before by default"
+// @Success 201 {array} InvoiceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/invoices/generate [post]
+func (h *BillingHandler) GenerateInvoices(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	if value := c.Query("month"); value != "" {
+		if month, err = time.Parse("2006-01", value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	invoices, err := h.billingUseCase.GenerateInvoices(ctx, month, now)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidDateRange):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateInvoices, zap.Time("month", month), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(mapResponses(invoices, newInvoiceResponse))
+}
+
+// ListInvoices godoc
+// @Summary List invoices
+// @Description The invoices of a restaurant, or of every restaurant, newest first. Amounts are in minor units of their currency. Admins only
+// @Tags admin
+// @Produce json
+// @Param restaurant_id query string false "Restaurant ID"
+// @Param status query string false "open, paid or failed; all by default"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} InvoiceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/invoices [get]
+func (h *BillingHandler) ListInvoices(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	filter := domain.InvoiceFilter{
+		RestaurantID: c.Query("restaurant_id"),
+		Status:       domain.InvoiceStatus(c.Query("status")),
+	}
+	invoices, err := h.billingUseCase.ListInvoices(ctx, filter, offset, lookaheadLimit(limit))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidInvoiceStatus):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListInvoices, zap.String("restaurantID", filter.RestaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	invoices, hasNext := trimPage(invoices, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(invoices),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(invoices, newInvoiceResponse))
+}
+
+// GetInvoicePDF godoc
+// @Summary Get invoice PDF
+// @Description The invoice as a PDF document, named after its number. Admins only
+// @Tags admin
+// @Produce application/pdf
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} binary
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/billing/invoices/{id}/pdf [get]
+func (h *BillingHandler) GetInvoicePDF(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	invoice, document, err := h.billingUseCase.RenderInvoice(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrInvoiceNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrInvoiceNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrRenderInvoice, zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+invoice.Number+`.pdf"`)
+	return c.Status(fiber.StatusOK).Send(document)
+}
+
+// PaymentWebhook godoc
+// @Summary Payment provider webhook
+// @Description Receive a payment.succeeded or payment.failed event of the payment provider for an invoice, signed with the hex HMAC-SHA256 of the body under BILLING_WEBHOOK_SECRET in X-Billing-Signature. Every event is applied once; a failure reported after the invoice was paid is ignored
+// @Tags billing
+// @Accept json
+// @Produce json
+// @Param X-Billing-Signature header string true "Hex HMAC-SHA256 of the body"
+// @Param event body domain.PaymentEvent true "Payment event"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string "Invalid signature"
+// @Failure 404 {object} map[string]string "Invoice not found"
+// @Failure 500 {object} map[string]string
+// @Router /billing/webhook [post]
+func (h *BillingHandler) PaymentWebhook(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if err := h.billingUseCase.HandlePaymentEvent(ctx, c.Body(), c.Get(HeaderBillingSignature)); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidWebhookSignature):
+			log.Warn(ctx, common.ErrHandlePaymentEvent, zap.Error(err))
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrInvalidPaymentEvent):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case err.Error() == common.ErrInvoiceNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrInvoiceNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrHandlePaymentEvent, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": common.MsgSuccess,
+	})
+}
@@ -2,15 +2,19 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
@@ -24,11 +28,106 @@ var (
 
 type BookingHandler struct {
 	bookingUseCase usecase.BookingUseCase
+	receiptUseCase usecase.NotificationReceiptUseCase
+	menuUseCase    usecase.MenuUseCase
 }
 
-func NewBookingHandler(bookingUseCase usecase.BookingUseCase) *BookingHandler {
+func NewBookingHandler(
+	bookingUseCase usecase.BookingUseCase,
+	receiptUseCase usecase.NotificationReceiptUseCase,
+	menuUseCase usecase.MenuUseCase,
+) *BookingHandler {
 	return &BookingHandler{
 		bookingUseCase: bookingUseCase,
+		receiptUseCase: receiptUseCase,
+		menuUseCase:    menuUseCase,
+	}
+}
+
+type BookingResponse struct {
+	ID           string                       `json:"id"`
+	RestaurantID string                       `json:"restaurant_id"`
+	UserID       string                       `json:"user_id"`
+	Date         time.Time                    `json:"date"`
+	Time         string                       `json:"time"`
+	Duration     int                          `json:"duration"`
+	GuestsCount  int                          `json:"guests_count"`
+	Status       domain.BookingStatus         `json:"status"`
+	Comment      string                       `json:"comment"`
+	Occasion     domain.BookingOccasion       `json:"occasion,omitempty"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	UpdatedAt    time.Time                    `json:"updated_at"`
+	ConfirmedAt  *time.Time                   `json:"confirmed_at,omitempty"`
+	RejectedAt   *time.Time                   `json:"rejected_at,omitempty"`
+	CompletedAt  *time.Time                   `json:"completed_at,omitempty"`
+	Alternatives []BookingAlternativeResponse `json:"alternatives,omitempty"`
+	IsTest       bool                         `json:"is_test"`
+	AgeAttested  bool                         `json:"age_attested"`
+	// Attribution is shown with the details of a booking that came through a campaign.
+	Attribution *domain.BookingAttribution `json:"attribution,omitempty"`
+	// TableID is the table the party is seated at, in a restaurant with tables.
+	TableID string `json:"table_id,omitempty"`
+	// SeatingZone and NeedsAccessible are what the guest asked of the table.
+	SeatingZone     string `json:"seating_zone,omitempty"`
+	NeedsAccessible bool   `json:"needs_accessible,omitempty"`
+}
+
+type BookingAlternativeResponse struct {
+	ID         string     `json:"id"`
+	BookingID  string     `json:"booking_id"`
+	Date       time.Time  `json:"date"`
+	Time       string     `json:"time"`
+	Message    string     `json:"message"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	RejectedAt *time.Time `json:"rejected_at,omitempty"`
+}
+
+// BookingDetails is a booking with the delivery status of the notifications sent about it and
+// the menu items pre-ordered for it.
+type BookingDetails struct {
+	BookingResponse
+	Notifications []NotificationDeliveryResponse `json:"notifications"`
+	PreOrder      *PreOrderResponse              `json:"pre_order,omitempty"`
+}
+
+func newBookingResponse(booking *domain.Booking) BookingResponse {
+	return BookingResponse{
+		ID:              booking.ID,
+		RestaurantID:    booking.RestaurantID,
+		UserID:          booking.UserID,
+		Date:            booking.Date,
+		Time:            booking.Time,
+		Duration:        booking.Duration,
+		GuestsCount:     booking.GuestsCount,
+		Status:          booking.Status,
+		Comment:         booking.Comment,
+		Occasion:        booking.Occasion,
+		CreatedAt:       booking.CreatedAt,
+		UpdatedAt:       booking.UpdatedAt,
+		ConfirmedAt:     booking.ConfirmedAt,
+		RejectedAt:      booking.RejectedAt,
+		CompletedAt:     booking.CompletedAt,
+		Alternatives:    mapResponses(booking.Alternatives, newBookingAlternativeResponse),
+		IsTest:          booking.IsTest,
+		AgeAttested:     booking.AgeAttested,
+		Attribution:     booking.Attribution,
+		TableID:         booking.TableID,
+		SeatingZone:     booking.SeatingZone,
+		NeedsAccessible: booking.NeedsAccessible,
+	}
+}
+
+func newBookingAlternativeResponse(alternative domain.BookingAlternative) BookingAlternativeResponse {
+	return BookingAlternativeResponse{
+		ID:         alternative.ID,
+		BookingID:  alternative.BookingID,
+		Date:       alternative.Date,
+		Time:       alternative.Time,
+		Message:    alternative.Message,
+		CreatedAt:  alternative.CreatedAt,
+		AcceptedAt: alternative.AcceptedAt,
+		RejectedAt: alternative.RejectedAt,
 	}
 }
 
@@ -40,6 +139,43 @@ type CreateBookingRequest struct {
 	Duration     int       `json:"duration" validate:"required,min=30"`
 	GuestsCount  int       `json:"guests_count" validate:"required,min=1"`
 	Comment      string    `json:"comment"`
+	// Occasion is one of birthday, anniversary and business, or empty.
+	Occasion domain.BookingOccasion `json:"occasion"`
+	// IsTest makes a test booking; bookings of a test restaurant are test bookings anyway.
+	IsTest bool `json:"is_test"`
+	// AgeAttested attests that every guest is of age; an adult-only restaurant requires it.
+	AgeAttested bool `json:"age_attested"`
+	// TableID asks for a table of a restaurant with tables; left out, the party is seated at the
+	// free table that fits it best.
+	TableID string `json:"table_id"`
+	// SeatingZone is the zone the guest would like to sit in, and NeedsAccessible asks for a table
+	// a wheelchair can reach; the zone is kept when a table there fits, accessibility always.
+	SeatingZone     string `json:"seating_zone"`
+	NeedsAccessible bool   `json:"needs_accessible"`
+	// The UTM parameters and the referral code tell which campaign brought the booking; those
+	// left out are taken from the query string, where widgets pass on the ones of their page.
+	UTMSource    string `json:"utm_source"`
+	UTMMedium    string `json:"utm_medium"`
+	UTMCampaign  string `json:"utm_campaign"`
+	UTMTerm      string `json:"utm_term"`
+	UTMContent   string `json:"utm_content"`
+	ReferralCode string `json:"referral_code"`
+}
+
+// bookingAttribution is the campaign of the booking requested, nil when it names none.
+func bookingAttribution(c fiber.Ctx, request *CreateBookingRequest) *domain.BookingAttribution {
+	attribution := domain.BookingAttribution{
+		Source:       cmp.Or(request.UTMSource, c.Query("utm_source")),
+		Medium:       cmp.Or(request.UTMMedium, c.Query("utm_medium")),
+		Campaign:     cmp.Or(request.UTMCampaign, c.Query("utm_campaign")),
+		Term:         cmp.Or(request.UTMTerm, c.Query("utm_term")),
+		Content:      cmp.Or(request.UTMContent, c.Query("utm_content")),
+		ReferralCode: cmp.Or(request.ReferralCode, c.Query("referral_code"), c.Query("ref")),
+	}
+	if attribution.IsZero() {
+		return nil
+	}
+	return &attribution
 }
 
 func getContextAndLogger(c fiber.Ctx) (context.Context, ports.LoggerPort, error) {
@@ -49,6 +185,8 @@ func getContextAndLogger(c fiber.Ctx) (context.Context, ports.LoggerPort, error)
 		return nil, nil, ErrContextNotFound
 	}
 
+	ctx = logger.WithFields(ctx, routeLogFields(c)...)
+
 	log, err := logger.FromContext(ctx)
 	if err != nil {
 		return ctx, nil, fmt.Errorf("%w: %s", ErrLoggerNotFound, err.Error())
@@ -57,23 +195,58 @@ func getContextAndLogger(c fiber.Ctx) (context.Context, ports.LoggerPort, error)
 	return ctx, log, nil
 }
 
+// routeLogIDs names the log field of the ID following each collection in a route path, so that
+// /restaurants/:id and /organizations/:id/restaurants/:restaurantId both log restaurant_id.
+var routeLogIDs = map[string]string{
+	"restaurants": logger.RestaurantIDKey,
+	"bookings":    logger.BookingIDKey,
+	"users":       logger.UserIDKey,
+}
+
+// routeLogFields returns the restaurant, booking and user IDs in the path of the matched route.
+func routeLogFields(c fiber.Ctx) []zap.Field {
+	var fields []zap.Field
+	segments := strings.Split(c.Route().Path, "/")
+	for i := 1; i < len(segments); i++ {
+		param, ok := strings.CutPrefix(segments[i], ":")
+		if !ok {
+			continue
+		}
+		if key, ok := routeLogIDs[segments[i-1]]; ok {
+			if value := c.Params(param); value != "" {
+				fields = append(fields, zap.String(key, value))
+			}
+		}
+	}
+	return fields
+}
+
+// dryRunRequested reports whether the ?dry_run query parameter asks to preview
+// a mass operation instead of applying it.
+func dryRunRequested(c fiber.Ctx) (bool, error) {
+	return strconv.ParseBool(c.Query("dry_run", "false"))
+}
+
 // CreateBooking godoc
 // @Summary Create booking
-// @Description Create a new booking for a restaurant
+// @Description Create a new booking for a restaurant. The utm_source, utm_medium, utm_campaign, utm_term, utm_content and referral_code fields record the campaign that brought the booking; those missing from the body are read from the query string, ref standing for referral_code
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param booking body CreateBookingRequest true "Booking data"
-// @Success 201 {object} domain.Booking
+// @Param utm_source query string false "UTM source, when not in the body"
+// @Param referral_code query string false "Referral code, when not in the body"
+// @Success 201 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} QuotaExceededResponse "Monthly booking quota of the restaurant used up"
 // @Failure 404 {object} map[string]string "Restaurant or user not found"
-// @Failure 422 {object} map[string]string "Not enough seats at the specified time"
+// @Failure 422 {object} map[string]string "Not enough seats or no free table at the specified time, the requested table is not free for the party, age_attested missing for an adult-only restaurant, or the restaurant is closed on the date (a RestaurantClosedResponse)"
 // @Failure 500 {object} map[string]string
 // @Router /bookings [post]
 func (h *BookingHandler) CreateBooking(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
@@ -82,62 +255,101 @@ func (h *BookingHandler) CreateBooking(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
+	ctx = logger.WithFields(ctx,
+		zap.String(logger.RestaurantIDKey, request.RestaurantID),
+		zap.String(logger.UserIDKey, request.UserID))
 
 	booking := &domain.Booking{
-		RestaurantID: request.RestaurantID,
-		UserID:       request.UserID,
-		Date:         request.Date,
-		Time:         request.Time,
-		Duration:     request.Duration,
-		GuestsCount:  request.GuestsCount,
-		Comment:      request.Comment,
-		Status:       domain.BookingStatusPending,
+		RestaurantID:    request.RestaurantID,
+		UserID:          request.UserID,
+		Date:            request.Date,
+		Time:            request.Time,
+		Duration:        request.Duration,
+		GuestsCount:     request.GuestsCount,
+		Comment:         request.Comment,
+		Occasion:        request.Occasion,
+		Status:          domain.BookingStatusPending,
+		IsTest:          request.IsTest,
+		AgeAttested:     request.AgeAttested,
+		Attribution:     bookingAttribution(c, &request),
+		TableID:         request.TableID,
+		SeatingZone:     request.SeatingZone,
+		NeedsAccessible: request.NeedsAccessible,
 	}
 
 	bookingID, err := h.bookingUseCase.CreateBooking(ctx, booking)
 	if err != nil {
 		log.Error(ctx, common.ErrCreateBooking, zap.Error(err))
 
+		if errors.Is(err, usecase.ErrInvalidOccasion) || errors.Is(err, domain.ErrInvalidEntity) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		var exceeded *usecase.QuotaExceededError
+		if errors.As(err, &exceeded) {
+			return respond(c, fiber.StatusForbidden, newQuotaExceededResponse(exceeded))
+		}
+
+		var closed *usecase.RestaurantClosedError
+		if errors.As(err, &closed) {
+			return respond(c, fiber.StatusUnprocessableEntity, newRestaurantClosedResponse(closed))
+		}
+
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
+		if errors.Is(err, usecase.ErrAgeAttestationRequired) {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+				"error": common.ErrAgeAttestationRequired,
+			})
+		}
+
 		if err.Error() == common.ErrUserNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrUserNotFound,
 			})
 		}
 
-		if err.Error() == common.ErrInsufficientCapacity {
-			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+		if err.Error() == common.ErrInsufficientCapacity || errors.Is(err, domain.ErrReservedSeatsOverflow) ||
+			errors.Is(err, usecase.ErrNoAvailability) {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
 				"error": common.ErrInsufficientCapacity,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		if errors.Is(err, usecase.ErrTableUnavailable) {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+				"error": common.ErrTableUnavailable,
+			})
+		}
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return respond(c, fiber.StatusCreated, fiber.Map{
 		"id": bookingID,
 	})
 }
 
 // GetBooking godoc
 // @Summary Get booking
-// @Description Get detailed information about a booking by ID
+// @Description Get detailed information about a booking by ID, with the delivery status of its notifications and its pre-order
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingDetails
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 500 {object} map[string]string
@@ -145,34 +357,60 @@ func (h *BookingHandler) CreateBooking(c fiber.Ctx) error {
 func (h *BookingHandler) GetBooking(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	booking, err := h.bookingUseCase.GetBooking(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetBookingByID, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetBookingByID, zap.String("id", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	if booking == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return respond(c, fiber.StatusNotFound, fiber.Map{
 			"error": common.ErrBookingNotFound,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(booking)
+	// The receipts are extra detail; the booking is answered without them when they cannot be read.
+	deliveries, err := h.receiptUseCase.GetBookingDeliveries(ctx, booking)
+	if err != nil {
+		log.Warn(ctx, common.ErrGetNotificationDeliveries, zap.String("id", id.String()), zap.Error(err))
+		deliveries = []domain.NotificationDelivery{}
+	}
+
+	details := BookingDetails{
+		BookingResponse: newBookingResponse(booking),
+		Notifications:   mapResponses(deliveries, newNotificationDeliveryResponse),
+	}
+
+	preOrder, err := h.menuUseCase.GetPreOrder(ctx, booking)
+	if err != nil {
+		log.Warn(ctx, common.ErrGetPreOrder, zap.String("id", id.String()), zap.Error(err))
+	} else if preOrder != nil {
+		response := newPreOrderResponse(preOrder)
+		details.PreOrder = &response
+	}
+
+	return respond(c, fiber.StatusOK, details)
 }
 
 // ConfirmBooking godoc
@@ -180,10 +418,11 @@ func (h *BookingHandler) GetBooking(c fiber.Ctx) error {
 // @Description Confirm a booking by the restaurant
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Not the restaurant of the booking"
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 422 {object} map[string]string "Cannot confirm booking in current status"
 // @Failure 500 {object} map[string]string
@@ -201,11 +440,12 @@ type RejectBookingRequest struct {
 // @Description Reject a booking by the restaurant with a reason
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
 // @Param reason body RejectBookingRequest true "Rejection reason"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Not the restaurant of the booking"
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 422 {object} map[string]string "Cannot reject booking in current status"
 // @Failure 500 {object} map[string]string
@@ -213,15 +453,15 @@ type RejectBookingRequest struct {
 func (h *BookingHandler) RejectBooking(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -229,20 +469,26 @@ func (h *BookingHandler) RejectBooking(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
 	if err := h.bookingUseCase.RejectBooking(ctx, id, request.Reason); err != nil {
-		log.Error(ctx, common.ErrRejectBookingByID, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrRejectBookingByID, zap.String("id", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return respond(c, fiber.StatusOK, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
@@ -252,10 +498,11 @@ func (h *BookingHandler) RejectBooking(c fiber.Ctx) error {
 // @Description Cancel a booking by the user
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Neither the user nor the restaurant of the booking"
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 422 {object} map[string]string "Cannot cancel booking in current status"
 // @Failure 500 {object} map[string]string
@@ -269,10 +516,11 @@ func (h *BookingHandler) CancelBooking(c fiber.Ctx) error {
 // @Description Mark a booking as completed
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Not the restaurant of the booking"
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 422 {object} map[string]string "Cannot complete booking in current status"
 // @Failure 500 {object} map[string]string
@@ -283,44 +531,50 @@ func (h *BookingHandler) CompleteBooking(c fiber.Ctx) error {
 
 func (h *BookingHandler) handleBookingStatusChange(
 	c fiber.Ctx,
-	action func(ctx context.Context, id string) error,
+	action func(ctx context.Context, id domain.BookingID) error,
 	errMsg string,
 ) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	if err := action(ctx, id); err != nil {
-		log.Error(ctx, errMsg, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, errMsg, zap.String("id", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
 
 		if err.Error() == common.ErrBookingNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrBookingNotFound,
 			})
 		}
 
 		if err.Error() == common.ErrInvalidBookingStatus {
-			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
 				"error": common.ErrInvalidBookingStatus,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return respond(c, fiber.StatusOK, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
@@ -336,11 +590,12 @@ type SuggestAlternativeTimeRequest struct {
 // @Description Restaurant suggests an alternative time for a booking
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Booking ID"
 // @Param alternative_time body SuggestAlternativeTimeRequest true "Alternative time data"
-// @Success 201 {object} domain.Booking
+// @Success 201 {object} BookingResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Not the restaurant of the booking"
 // @Failure 404 {object} map[string]string "Booking not found"
 // @Failure 422 {object} map[string]string "Cannot suggest alternative time in current status"
 // @Failure 500 {object} map[string]string
@@ -348,15 +603,15 @@ type SuggestAlternativeTimeRequest struct {
 func (h *BookingHandler) SuggestAlternativeTime(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -364,7 +619,7 @@ func (h *BookingHandler) SuggestAlternativeTime(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
@@ -372,17 +627,23 @@ func (h *BookingHandler) SuggestAlternativeTime(c fiber.Ctx) error {
 	alternativeID, err := h.bookingUseCase.SuggestAlternativeTime(ctx, id, request.Date, request.Time, request.Message)
 	if err != nil {
 		log.Error(ctx, common.ErrSuggestAlternativeTime,
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Time("date", request.Date),
 			zap.String("time", request.Time),
 			zap.Error(err))
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return respond(c, fiber.StatusCreated, fiber.Map{
 		"id": alternativeID,
 	})
 }
@@ -392,11 +653,12 @@ func (h *BookingHandler) SuggestAlternativeTime(c fiber.Ctx) error {
 // @Description User accepts the suggested alternative time
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Alternative ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Alternative not found"
+// @Failure 422 {object} map[string]string "No table the party fits at is free at the alternative time"
 // @Failure 500 {object} map[string]string
 // @Router /bookings/alternatives/{id}/accept [post]
 func (h *BookingHandler) AcceptAlternative(c fiber.Ctx) error {
@@ -408,9 +670,9 @@ func (h *BookingHandler) AcceptAlternative(c fiber.Ctx) error {
 // @Description User rejects the suggested alternative time
 // @Tags bookings
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Alternative ID"
-// @Success 200 {object} domain.Booking
+// @Success 200 {object} BookingResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Alternative not found"
 // @Failure 500 {object} map[string]string
@@ -427,14 +689,14 @@ func (h *BookingHandler) handleAlternativeAction(
 ) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
@@ -443,17 +705,27 @@ func (h *BookingHandler) handleAlternativeAction(
 		log.Error(ctx, errMsg, zap.String("alternativeID", id), zap.Error(err))
 
 		if err.Error() == common.ErrAlternativeNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrAlternativeNotFound,
 			})
 		}
+		if errors.Is(err, usecase.ErrNoAvailability) {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+				"error": common.ErrInsufficientCapacity,
+			})
+		}
+		if errors.Is(err, usecase.ErrTableUnavailable) {
+			return respond(c, fiber.StatusUnprocessableEntity, fiber.Map{
+				"error": common.ErrTableUnavailable,
+			})
+		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return respond(c, fiber.StatusOK, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
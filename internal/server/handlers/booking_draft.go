@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BookingDraftHandler struct {
+	bookingDraftUseCase usecase.BookingDraftUseCase
+}
+
+func NewBookingDraftHandler(bookingDraftUseCase usecase.BookingDraftUseCase) *BookingDraftHandler {
+	return &BookingDraftHandler{
+		bookingDraftUseCase: bookingDraftUseCase,
+	}
+}
+
+type CreateBookingDraftRequest struct {
+	RestaurantID string                 `json:"restaurant_id"`
+	GuestsCount  int                    `json:"guests_count"`
+	Duration     int                    `json:"duration"`
+	Comment      string                 `json:"comment"`
+	Occasion     domain.BookingOccasion `json:"occasion"`
+	AgeAttested  bool                   `json:"age_attested"`
+}
+
+type HoldBookingDraftSlotRequest struct {
+	// Date is the day of the slot, YYYY-MM-DD, and Time its start, HH:MM.
+	Date string `json:"date"`
+	Time string `json:"time"`
+}
+
+type BookingDraftResponse struct {
+	ID           string                    `json:"id"`
+	UserID       string                    `json:"user_id"`
+	RestaurantID string                    `json:"restaurant_id"`
+	GuestsCount  int                       `json:"guests_count"`
+	Duration     int                       `json:"duration"`
+	Comment      string                    `json:"comment,omitempty"`
+	Occasion     domain.BookingOccasion    `json:"occasion,omitempty"`
+	AgeAttested  bool                      `json:"age_attested"`
+	Status       domain.BookingDraftStatus `json:"status"`
+	Date         string                    `json:"date,omitempty"`
+	Time         string                    `json:"time,omitempty"`
+	PreOrder     []domain.PreOrderItem     `json:"pre_order"`
+	// EstimatedTotal is the cost of the pre-order in minor units of its currency.
+	EstimatedTotal int64     `json:"estimated_total"`
+	BookingID      string    `json:"booking_id,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func newBookingDraftResponse(draft *domain.BookingDraft) BookingDraftResponse {
+	response := BookingDraftResponse{
+		ID:             draft.ID,
+		UserID:         draft.UserID,
+		RestaurantID:   draft.RestaurantID,
+		GuestsCount:    draft.GuestsCount,
+		Duration:       draft.Duration,
+		Comment:        draft.Comment,
+		Occasion:       draft.Occasion,
+		AgeAttested:    draft.AgeAttested,
+		Status:         draft.Status,
+		Time:           draft.Time,
+		PreOrder:       draft.PreOrder,
+		EstimatedTotal: (&domain.PreOrder{Items: draft.PreOrder}).Estimate(),
+		BookingID:      draft.BookingID,
+		ExpiresAt:      draft.ExpiresAt,
+		CreatedAt:      draft.CreatedAt,
+	}
+	if !draft.Date.IsZero() {
+		response.Date = draft.Date.Format("2006-01-02")
+	}
+	if response.PreOrder == nil {
+		response.PreOrder = make([]domain.PreOrderItem, 0)
+	}
+	return response
+}
+
+// CreateBookingDraft godoc
+// @Summary Start a booking in steps
+// @Description Start a booking draft for the caller's party at a restaurant. The booking is then made step by step: a slot is held for the draft, a pre-order added and the draft confirmed. Every step renews the draft for BOOKING_DRAFT_TTL; a draft left alone longer expires and the seats it held are given back
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param draft body CreateBookingDraftRequest true "Restaurant and party"
+// @Success 201 {object} BookingDraftResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts [post]
+func (h *BookingDraftHandler) CreateBookingDraft(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request CreateBookingDraftRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	draft := &domain.BookingDraft{
+		RestaurantID: request.RestaurantID,
+		GuestsCount:  request.GuestsCount,
+		Duration:     request.Duration,
+		Comment:      request.Comment,
+		Occasion:     request.Occasion,
+		AgeAttested:  request.AgeAttested,
+	}
+	if err := h.bookingDraftUseCase.CreateDraft(ctx, draft); err != nil {
+		return h.draftError(ctx, log, c, draft.ID, common.ErrCreateBookingDraft, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newBookingDraftResponse(draft))
+}
+
+// GetBookingDraft godoc
+// @Summary Get a booking draft
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking draft ID"
+// @Success 200 {object} BookingDraftResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking draft not found"
+// @Failure 410 {object} map[string]string "Booking draft expired"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts/{id} [get]
+func (h *BookingDraftHandler) GetBookingDraft(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	draft, err := h.bookingDraftUseCase.GetDraft(ctx, id)
+	if err != nil {
+		return h.draftError(ctx, log, c, id, common.ErrGetBookingDraft, err)
+	}
+
+	return c.JSON(newBookingDraftResponse(draft))
+}
+
+// HoldBookingDraftSlot godoc
+// @Summary Hold a slot for a booking draft
+// @Description Hold the seats of the restaurant's slot at the date and time for the party of the draft, giving back those of the slot held before. Nobody else can book the held seats until the draft is confirmed, deleted or expires
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking draft ID"
+// @Param slot body HoldBookingDraftSlotRequest true "Date and time of the slot"
+// @Success 200 {object} BookingDraftResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking draft not found"
+// @Failure 409 {object} map[string]string "No room in the slot, or the draft is already confirmed"
+// @Failure 410 {object} map[string]string "Booking draft expired"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts/{id}/slot [put]
+func (h *BookingDraftHandler) HoldBookingDraftSlot(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	var request HoldBookingDraftSlotRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", request.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	draft, err := h.bookingDraftUseCase.HoldSlot(ctx, id, date, request.Time)
+	if err != nil {
+		return h.draftError(ctx, log, c, id, common.ErrUpdateBookingDraft, err)
+	}
+
+	return c.JSON(newBookingDraftResponse(draft))
+}
+
+// UpdateBookingDraftPreOrder godoc
+// @Summary Pre-order dishes with a booking draft
+// @Description Replace the menu items pre-ordered with the draft; an empty list clears the pre-order. The draft must hold a slot and the pre-order cutoff before it must not have passed. The pre-order is saved with the booking once the draft is confirmed
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking draft ID"
+// @Param preOrder body UpdatePreOrderRequest true "Pre-ordered items"
+// @Success 200 {object} BookingDraftResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking draft not found"
+// @Failure 409 {object} map[string]string "No slot held yet, the draft is already confirmed, or the pre-order is closed"
+// @Failure 410 {object} map[string]string "Booking draft expired"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts/{id}/pre-order [put]
+func (h *BookingDraftHandler) UpdateBookingDraftPreOrder(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	var request UpdatePreOrderRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	draft, err := h.bookingDraftUseCase.SetPreOrder(ctx, id, request.Items)
+	if err != nil {
+		return h.draftError(ctx, log, c, id, common.ErrUpdateBookingDraft, err)
+	}
+
+	return c.JSON(newBookingDraftResponse(draft))
+}
+
+// ConfirmBookingDraft godoc
+// @Summary Confirm a booking draft
+// @Description Create the booking of the draft in the held slot, with its pre-order. The booking goes through the same checks as any other booking; when one fails the draft keeps holding its slot
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking draft ID"
+// @Success 201 {object} BookingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} QuotaExceededResponse "Monthly booking quota of the restaurant used up"
+// @Failure 404 {object} map[string]string "Booking draft not found"
+// @Failure 409 {object} map[string]string "No slot held yet, the draft is already confirmed, the pre-order is closed, or a pre-ordered dish sold out"
+// @Failure 410 {object} map[string]string "Booking draft expired"
+// @Failure 422 {object} map[string]string "age_attested missing for an adult-only restaurant, or the restaurant is closed on the date (a RestaurantClosedResponse)"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts/{id}/confirm [post]
+func (h *BookingDraftHandler) ConfirmBookingDraft(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	booking, err := h.bookingDraftUseCase.ConfirmDraft(ctx, id)
+	if err != nil {
+		var exceeded *usecase.QuotaExceededError
+		if errors.As(err, &exceeded) {
+			return c.Status(fiber.StatusForbidden).JSON(newQuotaExceededResponse(exceeded))
+		}
+		var closed *usecase.RestaurantClosedError
+		if errors.As(err, &closed) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(newRestaurantClosedResponse(closed))
+		}
+		if errors.Is(err, usecase.ErrAgeAttestationRequired) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": common.ErrAgeAttestationRequired,
+			})
+		}
+		return h.draftError(ctx, log, c, id, common.ErrCreateBooking, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newBookingResponse(booking))
+}
+
+// DeleteBookingDraft godoc
+// @Summary Abandon a booking draft
+// @Description Delete the draft and give back the seats it held
+// @Tags bookings
+// @Param id path string true "Booking draft ID"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking draft not found"
+// @Failure 500 {object} map[string]string
+// @Router /booking-drafts/{id} [delete]
+func (h *BookingDraftHandler) DeleteBookingDraft(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if err := h.bookingDraftUseCase.DeleteDraft(ctx, id); err != nil {
+		return h.draftError(ctx, log, c, id, common.ErrDeleteBookingDraft, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// draftError answers a failure of a step of a booking draft.
+func (h *BookingDraftHandler) draftError(ctx context.Context, log ports.LoggerPort, c fiber.Ctx, id, message string, err error) error {
+	var limitErr *domain.PreOrderLimitError
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBookingDraft), errors.Is(err, usecase.ErrInvalidOccasion),
+		errors.Is(err, usecase.ErrInvalidPreOrder), errors.Is(err, domain.ErrInvalidEntity):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrBookingDraftExpired):
+		return c.Status(fiber.StatusGone).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrBookingDraftStep), errors.Is(err, usecase.ErrNoAvailability),
+		errors.Is(err, usecase.ErrTableUnavailable), errors.Is(err, usecase.ErrPreOrderClosed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.As(err, &limitErr):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": limitErr.Error(),
+		})
+	case errors.Is(err, tenant.ErrAccessDenied):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": common.ErrAccessDenied,
+		})
+	case err.Error() == common.ErrBookingDraftNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrBookingDraftNotFound,
+		})
+	case err.Error() == common.ErrRestaurantNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantNotFound,
+		})
+	}
+
+	log.Error(ctx, message, zap.String("id", id), zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": common.ErrInternalServer,
+	})
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BookingLinkHandler struct {
+	bookingLinkUseCase usecase.BookingLinkUseCase
+	bookingUseCase     usecase.BookingUseCase
+}
+
+func NewBookingLinkHandler(bookingLinkUseCase usecase.BookingLinkUseCase, bookingUseCase usecase.BookingUseCase) *BookingLinkHandler {
+	return &BookingLinkHandler{
+		bookingLinkUseCase: bookingLinkUseCase,
+		bookingUseCase:     bookingUseCase,
+	}
+}
+
+type ResolvedBookingLinkResponse struct {
+	Action    domain.BookingLinkAction `json:"action"`
+	SingleUse bool                     `json:"single_use"`
+	ExpiresAt time.Time                `json:"expires_at"`
+	Booking   BookingResponse          `json:"booking"`
+}
+
+type CreateBookingLinkRequest struct {
+	Action    domain.BookingLinkAction `json:"action"`
+	ExpiresAt *time.Time               `json:"expires_at,omitempty"`
+	SingleUse bool                     `json:"single_use"`
+}
+
+// CreateBookingLink godoc
+// @Summary Create booking link
+// @Description Create a short signed link that opens the booking details or its cancellation without logging in
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param link body CreateBookingLinkRequest true "Link options; expires_at defaults to the configured lifetime"
+// @Success 201 {object} usecase.IssuedBookingLink
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/links [post]
+func (h *BookingLinkHandler) CreateBookingLink(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var request CreateBookingLinkRequest
+	if err := c.Bind().Body(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	options := usecase.BookingLinkOptions{
+		Action:    request.Action,
+		SingleUse: request.SingleUse,
+	}
+	if request.ExpiresAt != nil {
+		options.ExpiresAt = *request.ExpiresAt
+	}
+
+	link, err := h.bookingLinkUseCase.CreateBookingLink(ctx, id, options)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBookingLink):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrBookingNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateBookingLink, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(link)
+}
+
+// ResolveBookingLink godoc
+// @Summary Open booking link
+// @Description Resolve a short booking link to the booking details or its cancellation page. No login is required
+// @Tags bookings
+// @Produce json
+// @Param token path string true "Link token"
+// @Success 200 {object} ResolvedBookingLinkResponse
+// @Failure 404 {object} map[string]string "Unknown link"
+// @Failure 410 {object} map[string]string "Link expired or already used"
+// @Failure 500 {object} map[string]string
+// @Router /b/{token} [get]
+func (h *BookingLinkHandler) ResolveBookingLink(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	resolved, err := h.bookingLinkUseCase.ResolveBookingLink(ctx, c.Params("token"))
+	if err != nil {
+		if status, ok := bookingLinkErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrResolveBookingLink, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(ResolvedBookingLinkResponse{
+		Action:    resolved.Action,
+		SingleUse: resolved.SingleUse,
+		ExpiresAt: resolved.ExpiresAt,
+		Booking:   newBookingResponse(resolved.Booking),
+	})
+}
+
+// CancelBookingByLink godoc
+// @Summary Cancel booking by link
+// @Description Cancel the booking of a cancellation link. No login is required
+// @Tags bookings
+// @Produce json
+// @Param token path string true "Link token"
+// @Success 200 {object} BookingResponse
+// @Failure 404 {object} map[string]string "Unknown link"
+// @Failure 410 {object} map[string]string "Link expired or already used"
+// @Failure 422 {object} map[string]string "Cannot cancel booking in current status"
+// @Failure 500 {object} map[string]string
+// @Router /b/{token}/cancel [post]
+func (h *BookingLinkHandler) CancelBookingByLink(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	booking, err := h.bookingLinkUseCase.UseBookingLink(ctx, c.Params("token"), domain.BookingLinkActionCancel,
+		func(ctx context.Context, booking *domain.Booking) error {
+			return h.bookingUseCase.CancelBooking(ctx, domain.BookingID(booking.ID))
+		})
+	if err != nil {
+		if status, ok := bookingLinkErrorStatus(err); ok {
+			return c.Status(status).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if errors.Is(err, usecase.ErrInvalidBookingStatus) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": common.ErrInvalidBookingStatus,
+			})
+		}
+
+		log.Error(ctx, common.ErrCancelBookingByID, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newBookingResponse(booking))
+}
+
+func bookingLinkErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBookingLink):
+		return fiber.StatusNotFound, true
+	case errors.Is(err, usecase.ErrBookingLinkExpired), errors.Is(err, usecase.ErrBookingLinkUsed):
+		return fiber.StatusGone, true
+	}
+	return 0, false
+}
@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// bookingPageDuration is the duration in minutes of the bookings made on a hosted page.
+	bookingPageDuration = 120
+
+	// Hosted pages may show the principal's booking form, so only the browser keeps them.
+	bookingPageCacheControl = "private, max-age=60"
+)
+
+// bookingPageStyle and bookingPageScript are the only style and script of a hosted page; the
+// Content-Security-Policy allows them by their hashes.
+const bookingPageStyle = `
+body{margin:0 auto;max-width:640px;padding:16px;font:16px/1.5 system-ui,sans-serif;color:#222}
+h1{margin:0;font-size:24px}
+h2{margin:24px 0 8px;font-size:18px}
+.info{color:#555}
+.day{margin-bottom:12px}
+.date{font-weight:600}
+.slots{display:flex;flex-wrap:wrap;gap:6px;margin-top:4px}
+.slot input{position:absolute;opacity:0}
+.slot span{display:inline-block;padding:4px 10px;border:1px solid #2a7;border-radius:4px;color:#2a7;cursor:pointer}
+.slot.limited span{border-color:#d80;color:#d80}
+.slot input:checked+span{background:#2a7;color:#fff}
+.slot.limited input:checked+span{background:#d80}
+.none{color:#888}
+.nav{display:flex;justify-content:space-between;margin:8px 0}
+label.field{display:block;margin-bottom:8px}
+label.field input,label.field textarea{display:block;width:100%;box-sizing:border-box;padding:6px;font:inherit}
+button{padding:8px 16px;font:inherit}
+#booking-status{min-height:1.5em}
+`
+
+const bookingPageScript = `
+document.getElementById("booking-form").addEventListener("submit", async function (event) {
+  event.preventDefault();
+  var form = event.target;
+  var status = document.getElementById("booking-status");
+  var button = form.querySelector("button");
+  var post = async function (path, body) {
+    var response = await fetch("/api/v1" + path, {
+      method: "POST",
+      headers: {"Content-Type": "application/json", "Accept": "application/json", "X-Response-Envelope": "false"},
+      body: JSON.stringify(body)
+    });
+    var data = await response.json().catch(function () { return {}; });
+    return {ok: response.ok, status: response.status, data: data};
+  };
+  var slot = form.elements.slot ? form.elements.slot.value : "";
+  if (!slot) {
+    status.textContent = "Choose a time.";
+    return;
+  }
+  var parts = slot.split(" ");
+  button.disabled = true;
+  status.textContent = "Booking…";
+  try {
+    var userID = form.dataset.user;
+    if (!userID) {
+      var user = await post("/users", {
+        name: form.elements.name.value,
+        email: form.elements.email.value,
+        phone: form.elements.phone.value
+      });
+      if (!user.ok) {
+        throw new Error(user.status === 409 ? "There is an account with this email; sign in to book." : user.data.error);
+      }
+      userID = user.data.id;
+    }
+    var booking = await post("/bookings" + window.location.search, {
+      restaurant_id: form.dataset.restaurant,
+      user_id: userID,
+      date: parts[0] + "T00:00:00Z",
+      time: parts[1],
+      duration: Number(form.dataset.duration),
+      guests_count: Number(form.elements.guests.value),
+      comment: form.elements.comment.value,
+      age_attested: form.elements.age_attested ? form.elements.age_attested.checked : false
+    });
+    if (!booking.ok) {
+      throw new Error(booking.data.error || "The booking failed.");
+    }
+    form.hidden = true;
+    status.textContent = "Booked for " + parts[0] + " at " + parts[1] + ". The restaurant will confirm your booking by email.";
+  } catch (error) {
+    status.textContent = error.message || "The booking failed.";
+    button.disabled = false;
+  }
+});
+`
+
+// bookingPageTemplate is the booking page of a restaurant for guests coming without a website of
+// the restaurant; the form books through the API.
+var bookingPageTemplate = template.Must(template.New("booking-page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Restaurant.Name}}</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<h1>{{.Restaurant.Name}}</h1>
+<div class="info">{{.Restaurant.Cuisine}}{{if .Restaurant.Address}} · {{.Restaurant.Address}}{{end}}</div>
+{{if .Restaurant.Description}}<p>{{.Restaurant.Description}}</p>{{end}}
+<div class="info">{{if .Restaurant.ContactPhone}}<a href="tel:{{or .Restaurant.NormalizedContactPhone .Restaurant.ContactPhone}}">{{.Restaurant.ContactPhone}}</a>{{end}}{{if .Restaurant.ContactEmail}} · <a href="mailto:{{.Restaurant.ContactEmail}}">{{.Restaurant.ContactEmail}}</a>{{end}}</div>
+<h2>Book a table</h2>
+<form id="booking-form" data-restaurant="{{.Restaurant.ID}}" data-duration="{{.Duration}}"{{if .UserID}} data-user="{{.UserID}}"{{end}}>
+<div class="nav">{{if .Earlier}}<a href="?date={{.Earlier}}">Earlier dates</a>{{else}}<span></span>{{end}}<a href="?date={{.Later}}">Later dates</a></div>
+{{range .Days}}<div class="day"><div class="date">{{.Date}}</div>
+{{if .Slots}}<div class="slots">{{range .Slots}}<label class="slot {{.Status}}"><input type="radio" name="slot" value="{{.Date}} {{.Time}}" required><span>{{.Time}}</span></label>{{end}}</div>
+{{else}}<div class="none">No free tables</div>
+{{end}}</div>
+{{end}}<label class="field">Guests<input type="number" name="guests" min="1" max="50" value="2" required></label>
+{{if not .UserID}}<label class="field">Name<input type="text" name="name" autocomplete="name" required></label>
+<label class="field">Email<input type="email" name="email" autocomplete="email" required></label>
+<label class="field">Phone<input type="tel" name="phone" autocomplete="tel" required></label>
+{{end}}<label class="field">Comment<textarea name="comment" rows="2"></textarea></label>
+{{if .Restaurant.IsAdultOnly}}<label class="field"><input type="checkbox" name="age_attested" required> Every guest is of age</label>
+{{end}}<button type="submit">Book</button>
+</form>
+<p id="booking-status" role="status"></p>
+<script>{{.Script}}</script>
+</body>
+</html>
+`))
+
+// bookingPagePolicy lets a hosted page run its own style and script only and call the API of its
+// host.
+var bookingPagePolicy = "default-src 'self'; style-src " + sourceHash(bookingPageStyle) +
+	"; script-src " + sourceHash(bookingPageScript) + "; frame-ancestors 'self'"
+
+func sourceHash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+type bookingPageData struct {
+	Restaurant *domain.Restaurant
+	Days       []EmbedDayResponse
+	// Earlier is the first day of the previous range, empty when the page starts today.
+	Earlier  string
+	Later    string
+	UserID   string
+	Duration int
+	Style    template.CSS
+	Script   template.JS
+}
+
+type BookingPageHandler struct {
+	restaurantUseCase   usecase.RestaurantUseCase
+	availabilityUseCase usecase.AvailabilityUseCase
+	days                int
+}
+
+func NewBookingPageHandler(
+	restaurantUseCase usecase.RestaurantUseCase,
+	availabilityUseCase usecase.AvailabilityUseCase,
+	days int,
+) *BookingPageHandler {
+	switch {
+	case days < 1:
+		days = defaultEmbedDays
+	case days > maxEmbedDays:
+		days = maxEmbedDays
+	}
+
+	return &BookingPageHandler{
+		restaurantUseCase:   restaurantUseCase,
+		availabilityUseCase: availabilityUseCase,
+		days:                days,
+	}
+}
+
+// GetBookingPage godoc
+// @Summary Hosted booking page
+// @Description A booking page of the restaurant for restaurants without a website of their own: the restaurant, its free slots of the next days and a form booking through the API. A former slug redirects to the current one; restaurants without a slug are found by ID
+// @Tags embed
+// @Produce html
+// @Param slug path string true "Restaurant slug or ID"
+// @Param date query string false "First day (YYYY-MM-DD), today by default"
+// @Success 200 {string} string "HTML page"
+// @Failure 301 "Redirect to the current slug"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /r/{slug} [get]
+func (h *BookingPageHandler) GetBookingPage(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	slug := c.Params("slug")
+	if slug == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today
+	if value := c.Query("date"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+		if from.Before(today) {
+			from = today
+		}
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurantBySlug(ctx, slug)
+	if err != nil && (errors.Is(err, usecase.ErrInvalidRestaurantSlug) || err.Error() == common.ErrRestaurantNotFound) {
+		restaurant, err = nil, nil
+		if id, parseErr := domain.ParseRestaurantID(slug); parseErr == nil {
+			restaurant, err = h.restaurantUseCase.GetRestaurant(ctx, id)
+		}
+	}
+	if err != nil && err.Error() != common.ErrRestaurantNotFound {
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("slug", slug), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	if restaurant == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantNotFound,
+		})
+	}
+
+	if restaurant.Slug != "" && restaurant.Slug != slug && restaurant.ID != slug {
+		return c.Redirect().Status(fiber.StatusMovedPermanently).To("/r/" + restaurant.Slug)
+	}
+
+	days, err := freeSlotDays(ctx, h.availabilityUseCase, domain.RestaurantID(restaurant.ID), from, h.days)
+	if err != nil {
+		log.Error(ctx, common.ErrGetCurrentAvailability, zap.String("restaurantID", restaurant.ID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	data := bookingPageData{
+		Restaurant: restaurant,
+		Days:       days,
+		Later:      from.AddDate(0, 0, h.days).Format(time.DateOnly),
+		Duration:   bookingPageDuration,
+		Style:      template.CSS(bookingPageStyle),
+		Script:     template.JS(bookingPageScript),
+	}
+	if from.After(today) {
+		earlier := from.AddDate(0, 0, -h.days)
+		if earlier.Before(today) {
+			earlier = today
+		}
+		data.Earlier = earlier.Format(time.DateOnly)
+	}
+	if principal, ok := tenant.FromContext(ctx); ok {
+		data.UserID = principal.UserID
+	}
+
+	var page bytes.Buffer
+	if err := bookingPageTemplate.Execute(&page, data); err != nil {
+		log.Error(ctx, common.ErrRenderBookingPage, zap.String("restaurantID", restaurant.ID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, bookingPageCacheControl)
+	c.Set(fiber.HeaderContentSecurityPolicy, bookingPagePolicy)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(page.Bytes())
+}
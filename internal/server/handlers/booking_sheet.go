@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BookingSheetHandler struct {
+	bookingSheetUseCase usecase.BookingSheetUseCase
+}
+
+func NewBookingSheetHandler(bookingSheetUseCase usecase.BookingSheetUseCase) *BookingSheetHandler {
+	return &BookingSheetHandler{
+		bookingSheetUseCase: bookingSheetUseCase,
+	}
+}
+
+// PrintBookingSheet godoc
+// @Summary Print the bookings of a day
+// @Description Render the PDF run-sheet of the restaurant's pending, confirmed and completed bookings on a date, ordered by time, with party sizes, guests, occasions, comments and pre-orders, for restaurants without a tablet at the host stand. Restaurant staff only
+// @Tags restaurants,bookings
+// @Produce application/pdf
+// @Param id path string true "Restaurant ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {file} binary
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/bookings/print [get]
+func (h *BookingSheetHandler) PrintBookingSheet(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	document, err := h.bookingSheetUseCase.RenderBookingSheet(ctx, id, date)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrRenderBookingSheet, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	// Inline, so that a browser opens the sheet ready to print.
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="bookings-`+date.Format("2006-01-02")+`.pdf"`)
+	return c.Status(fiber.StatusOK).Send(document)
+}
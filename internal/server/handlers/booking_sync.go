@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BookingSyncHandler struct {
+	bookingSyncUseCase usecase.BookingSyncUseCase
+}
+
+func NewBookingSyncHandler(bookingSyncUseCase usecase.BookingSyncUseCase) *BookingSyncHandler {
+	return &BookingSyncHandler{
+		bookingSyncUseCase: bookingSyncUseCase,
+	}
+}
+
+// BookingSyncBookingRequest is a booking made offline; it is made for the user of the request.
+type BookingSyncBookingRequest struct {
+	RestaurantID string                 `json:"restaurant_id"`
+	Date         time.Time              `json:"date"`
+	Time         string                 `json:"time"`
+	Duration     int                    `json:"duration"`
+	GuestsCount  int                    `json:"guests_count"`
+	Comment      string                 `json:"comment"`
+	Occasion     domain.BookingOccasion `json:"occasion"`
+	AgeAttested  bool                   `json:"age_attested"`
+}
+
+type BookingSyncOperationRequest struct {
+	// Key identifies the operation among the operations of the user; submit the operation under
+	// the same key until it gets a result.
+	Key       string                      `json:"key"`
+	Operation domain.BookingSyncOperation `json:"operation"`
+	// Booking is the booking to create.
+	Booking *BookingSyncBookingRequest `json:"booking,omitempty"`
+	// BookingID, or BookingKey, the key of the operation that created it, is the booking to cancel.
+	BookingID  string `json:"booking_id,omitempty"`
+	BookingKey string `json:"booking_key,omitempty"`
+}
+
+// BookingSyncRequest carries the operations of the user of the request.
+type BookingSyncRequest struct {
+	Operations []BookingSyncOperationRequest `json:"operations"`
+}
+
+func (r BookingSyncOperationRequest) toItem() usecase.BookingSyncItem {
+	item := usecase.BookingSyncItem{
+		Key:        r.Key,
+		Operation:  r.Operation,
+		BookingID:  r.BookingID,
+		BookingKey: r.BookingKey,
+	}
+	if r.Booking != nil {
+		item.Booking = &domain.Booking{
+			RestaurantID: r.Booking.RestaurantID,
+			Date:         r.Booking.Date,
+			Time:         r.Booking.Time,
+			Duration:     r.Booking.Duration,
+			GuestsCount:  r.Booking.GuestsCount,
+			Comment:      r.Booking.Comment,
+			Occasion:     r.Booking.Occasion,
+			AgeAttested:  r.Booking.AgeAttested,
+		}
+	}
+	return item
+}
+
+type BookingSyncResultResponse struct {
+	Key       string                      `json:"key"`
+	Operation domain.BookingSyncOperation `json:"operation"`
+	Status    domain.BookingSyncStatus    `json:"status"`
+	BookingID string                      `json:"booking_id,omitempty"`
+	Error     string                      `json:"error,omitempty"`
+	Replayed  bool                        `json:"replayed"`
+}
+
+func newBookingSyncResultResponse(result *domain.BookingSyncResult) BookingSyncResultResponse {
+	return BookingSyncResultResponse{
+		Key:       result.Key,
+		Operation: result.Operation,
+		Status:    result.Status,
+		BookingID: result.BookingID,
+		Error:     result.Error,
+		Replayed:  result.Replayed,
+	}
+}
+
+type BookingSyncResponse struct {
+	Results []BookingSyncResultResponse `json:"results"`
+}
+
+// SyncBookings godoc
+// @Summary Submit bookings made offline
+// @Description Bookings and cancellations a guest made in the app while offline, carried out in order. Every operation gets a result: applied, slot_filled when its slot filled up in the meantime, already_cancelled, not_cancellable when the booking was rejected or completed in the meantime, not_found, rejected for an invalid booking or one of an adult-only restaurant without age_attested, or failed. Operations are kept by key, so an operation submitted again gets its earlier result, marked replayed, except a failed one, which is retried. A cancellation names the booking by booking_id or by booking_key, the key of the operation that created it. The operations are always the signed in user's own
+// @Tags sync,bookings
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param request body BookingSyncRequest true "Operations, at most 100"
+// @Success 200 {object} BookingSyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string "The caller is not a user"
+// @Failure 500 {object} map[string]string
+// @Router /sync/bookings [post]
+func (h *BookingSyncHandler) SyncBookings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request BookingSyncRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	items := make([]usecase.BookingSyncItem, len(request.Operations))
+	for i, operation := range request.Operations {
+		items[i] = operation.toItem()
+	}
+
+	results, err := h.bookingSyncUseCase.SyncBookings(ctx, items)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBookingSync):
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrSyncBookings, zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, BookingSyncResponse{
+		Results: mapResponses(results, newBookingSyncResultResponse),
+	})
+}
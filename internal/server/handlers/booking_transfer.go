@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BookingTransferHandler struct {
+	bookingTransferUseCase usecase.BookingTransferUseCase
+}
+
+func NewBookingTransferHandler(bookingTransferUseCase usecase.BookingTransferUseCase) *BookingTransferHandler {
+	return &BookingTransferHandler{
+		bookingTransferUseCase: bookingTransferUseCase,
+	}
+}
+
+type RequestBookingTransferRequest struct {
+	TargetRestaurantID string `json:"target_restaurant_id"`
+	// Date, YYYY-MM-DD, and Time, HH:MM, default to those of the booking.
+	Date string `json:"date"`
+	Time string `json:"time"`
+	// Message is told to the guest.
+	Message string `json:"message"`
+}
+
+type BookingTransferResponse struct {
+	ID                 string                       `json:"id"`
+	BookingID          string                       `json:"booking_id"`
+	SourceRestaurantID string                       `json:"source_restaurant_id"`
+	TargetRestaurantID string                       `json:"target_restaurant_id"`
+	Date               string                       `json:"date"`
+	Time               string                       `json:"time"`
+	Message            string                       `json:"message,omitempty"`
+	Status             domain.BookingTransferStatus `json:"status"`
+	NewBookingID       string                       `json:"new_booking_id,omitempty"`
+	CreatedAt          time.Time                    `json:"created_at"`
+	DecidedAt          *time.Time                   `json:"decided_at,omitempty"`
+}
+
+func newBookingTransferResponse(transfer *domain.BookingTransfer) BookingTransferResponse {
+	return BookingTransferResponse{
+		ID:                 transfer.ID,
+		BookingID:          transfer.BookingID,
+		SourceRestaurantID: transfer.SourceRestaurantID,
+		TargetRestaurantID: transfer.TargetRestaurantID,
+		Date:               transfer.Date.Format("2006-01-02"),
+		Time:               transfer.Time,
+		Message:            transfer.Message,
+		Status:             transfer.Status,
+		NewBookingID:       transfer.NewBookingID,
+		CreatedAt:          transfer.CreatedAt,
+		DecidedAt:          transfer.DecidedAt,
+	}
+}
+
+// RequestBookingTransfer godoc
+// @Summary Propose to transfer a booking to a sister restaurant
+// @Description Propose to move the booking to another restaurant of the same organization, by default at the same date and time. The target must have room for the party. The guest is asked to agree; until then the booking stays as it is. Staff of the booking's restaurant only
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param transfer body RequestBookingTransferRequest true "Target restaurant, date, time and message"
+// @Success 201 {object} BookingTransferResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking or restaurant not found"
+// @Failure 409 {object} map[string]string "No room at the target, or a transfer is already pending"
+// @Failure 422 {object} map[string]string "Target is not a sister restaurant, or the booking is not active"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/transfers [post]
+func (h *BookingTransferHandler) RequestBookingTransfer(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request RequestBookingTransferRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	transfer := &domain.BookingTransfer{
+		BookingID:          id,
+		TargetRestaurantID: request.TargetRestaurantID,
+		Time:               request.Time,
+		Message:            request.Message,
+	}
+	if request.Date != "" {
+		if transfer.Date, err = time.Parse("2006-01-02", request.Date); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	if err := h.bookingTransferUseCase.RequestTransfer(ctx, transfer); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBookingTransfer):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrNotSisterRestaurant), errors.Is(err, usecase.ErrTransferNotAllowed):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrNoAvailability):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrBookingTransferExists:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrBookingTransferExists,
+			})
+		case strings.HasPrefix(err.Error(), common.ErrBookingNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateBookingTransfer, zap.String("bookingID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newBookingTransferResponse(transfer))
+}
+
+// ListBookingTransfers godoc
+// @Summary List the transfers of a booking
+// @Description List the transfers proposed for the booking and the one it was created by, newest first; an accepted transfer links the original booking to its replacement
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {array} BookingTransferResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/transfers [get]
+func (h *BookingTransferHandler) ListBookingTransfers(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	transfers, err := h.bookingTransferUseCase.ListTransfers(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case strings.HasPrefix(err.Error(), common.ErrBookingNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetBookingTransfer, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(mapResponses(transfers, newBookingTransferResponse))
+}
+
+// AcceptBookingTransfer godoc
+// @Summary Accept a booking transfer
+// @Description Agree to move the booking: it is booked, confirmed, at the sister restaurant and the original booking is cancelled. The booking's guest only
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} BookingResponse "The new booking"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Transfer not found"
+// @Failure 409 {object} map[string]string "Already decided, or no room left at the target"
+// @Failure 422 {object} map[string]string "The booking is no longer active"
+// @Failure 500 {object} map[string]string
+// @Router /booking-transfers/{id}/accept [post]
+func (h *BookingTransferHandler) AcceptBookingTransfer(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	booking, err := h.bookingTransferUseCase.AcceptTransfer(ctx, id)
+	if err != nil {
+		return h.decisionError(ctx, log, c, id, err)
+	}
+
+	return c.JSON(newBookingResponse(booking))
+}
+
+// DeclineBookingTransfer godoc
+// @Summary Decline a booking transfer
+// @Description Refuse to move the booking; it stays as it is. The booking's guest only
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Transfer not found"
+// @Failure 409 {object} map[string]string "Already decided"
+// @Failure 500 {object} map[string]string
+// @Router /booking-transfers/{id}/decline [post]
+func (h *BookingTransferHandler) DeclineBookingTransfer(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.bookingTransferUseCase.DeclineTransfer(ctx, id); err != nil {
+		return h.decisionError(ctx, log, c, id, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// decisionError answers a failure to accept or decline a transfer.
+func (h *BookingTransferHandler) decisionError(ctx context.Context, log ports.LoggerPort, c fiber.Ctx, id string, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrTransferDecided), errors.Is(err, usecase.ErrNoAvailability):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrTransferNotAllowed):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, tenant.ErrAccessDenied):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": common.ErrAccessDenied,
+		})
+	case err.Error() == common.ErrBookingTransferNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrBookingTransferNotFound,
+		})
+	}
+
+	log.Error(ctx, common.ErrUpdateBookingTransfer, zap.String("id", id), zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": common.ErrInternalServer,
+	})
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type BulkCancellationHandler struct {
+	bulkCancellationUseCase usecase.BulkCancellationUseCase
+}
+
+func NewBulkCancellationHandler(bulkCancellationUseCase usecase.BulkCancellationUseCase) *BulkCancellationHandler {
+	return &BulkCancellationHandler{
+		bulkCancellationUseCase: bulkCancellationUseCase,
+	}
+}
+
+type BulkCancellationRequest struct {
+	// Date is the day to cancel, YYYY-MM-DD.
+	Date string `json:"date"`
+	// FromTime and ToTime bound the cancelled slots, both included, HH:MM; empty ones cover the
+	// whole day.
+	FromTime string `json:"from_time"`
+	ToTime   string `json:"to_time"`
+	// Reason is told to the guests.
+	Reason string `json:"reason"`
+}
+
+type RebookingAlternativeResponse struct {
+	ID   string `json:"id"`
+	Date string `json:"date"`
+	Time string `json:"time"`
+}
+
+type PartnerSuggestionResponse struct {
+	RestaurantID   string `json:"restaurant_id"`
+	RestaurantName string `json:"restaurant_name"`
+	Date           string `json:"date"`
+	TimeSlot       string `json:"time_slot"`
+}
+
+type CancelledBookingResponse struct {
+	BookingID    string                         `json:"booking_id"`
+	UserID       string                         `json:"user_id"`
+	Time         string                         `json:"time"`
+	GuestsCount  int                            `json:"guests_count"`
+	Alternatives []RebookingAlternativeResponse `json:"alternatives"`
+	Partners     []PartnerSuggestionResponse    `json:"partners"`
+}
+
+type BulkCancellationResponse struct {
+	Cancelled   []CancelledBookingResponse `json:"cancelled"`
+	ClosedSlots int                        `json:"closed_slots"`
+	// Incomplete is set when some bookings of the window could not be cancelled; the request can
+	// be repeated for them.
+	Incomplete bool `json:"incomplete"`
+	// DryRun is set when nothing was saved and no guest was notified.
+	DryRun bool `json:"dry_run"`
+}
+
+func newBulkCancellationResponse(report *usecase.BulkCancellationReport) BulkCancellationResponse {
+	response := BulkCancellationResponse{
+		Cancelled:   make([]CancelledBookingResponse, 0, len(report.Cancelled)),
+		ClosedSlots: report.ClosedSlots,
+		DryRun:      report.DryRun,
+	}
+	for _, cancelled := range report.Cancelled {
+		booking := CancelledBookingResponse{
+			BookingID:    cancelled.Booking.ID,
+			UserID:       cancelled.Booking.UserID,
+			Time:         cancelled.Booking.Time,
+			GuestsCount:  cancelled.Booking.GuestsCount,
+			Alternatives: make([]RebookingAlternativeResponse, 0, len(cancelled.Alternatives)),
+			Partners:     make([]PartnerSuggestionResponse, 0, len(cancelled.Partners)),
+		}
+		for _, alternative := range cancelled.Alternatives {
+			booking.Alternatives = append(booking.Alternatives, RebookingAlternativeResponse{
+				ID:   alternative.ID,
+				Date: alternative.Date.Format("2006-01-02"),
+				Time: alternative.Time,
+			})
+		}
+		for _, partner := range cancelled.Partners {
+			booking.Partners = append(booking.Partners, PartnerSuggestionResponse{
+				RestaurantID:   partner.RestaurantID,
+				RestaurantName: partner.RestaurantName,
+				Date:           partner.Date.Format("2006-01-02"),
+				TimeSlot:       partner.TimeSlot,
+			})
+		}
+		response.Cancelled = append(response.Cancelled, booking)
+	}
+	return response
+}
+
+// CancelBookings godoc
+// @Summary Cancel the bookings of an evening
+// @Description Close the restaurant's slots in a time window of a date and cancel its pending and confirmed bookings there. Every guest is notified with the nearest slots with room for the party at the restaurant, offered as alternatives of the booking, and with free tables at other restaurants of the same city. With dry_run=true the cancellation is made in a rolled back transaction and no guest is notified
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param cancellation body BulkCancellationRequest true "Date, time window and reason"
+// @Param dry_run query bool false "Preview the cancelled bookings and the offers without saving or notifying"
+// @Success 200 {object} BulkCancellationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/bookings/cancel [post]
+func (h *BulkCancellationHandler) CancelBookings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	dryRun, err := dryRunRequested(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request BulkCancellationRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", request.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	report, err := h.bulkCancellationUseCase.CancelBookings(ctx, usecase.BulkCancellation{
+		RestaurantID: id,
+		Date:         date,
+		FromTime:     request.FromTime,
+		ToTime:       request.ToTime,
+		Reason:       request.Reason,
+		DryRun:       dryRun,
+	})
+	if err != nil && report == nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBulkCancellation):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrBulkCancelBookings, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	response := newBulkCancellationResponse(report)
+	if err != nil {
+		log.Error(ctx, common.ErrBulkCancelBookings, zap.String("restaurantID", id), zap.Error(err))
+		response.Incomplete = true
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// publicCacheControl lets browsers and CDNs keep public read responses for a minute; after that
+// they revalidate with If-None-Match or If-Modified-Since and usually get a 304.
+const publicCacheControl = "public, max-age=60, must-revalidate"
+
+// respondCacheable writes a public read response with Cache-Control, ETag and, when lastModified
+// is known, Last-Modified headers. The ETag also catches changes that leave no modification time
+// behind, such as a deleted restaurant dropping out of a list.
+func respondCacheable(c fiber.Ctx, lastModified time.Time, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Set(fiber.HeaderCacheControl, publicCacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, lastModified) {
+		c.Vary(fiber.HeaderAccept)
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return respond(c, fiber.StatusOK, body)
+}
+
+// notModified tells whether the copy the client holds is current. If-None-Match takes precedence
+// over If-Modified-Since, as RFC 9110 requires.
+func notModified(c fiber.Ctx, etag string, lastModified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, candidate := range strings.Split(noneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	modifiedSince := c.Get(fiber.HeaderIfModifiedSince)
+	if modifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(modifiedSince)
+	if err != nil {
+		return false
+	}
+	// Last-Modified has a resolution of one second.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// latestUpdate returns the latest of the modification times of the items.
+func latestUpdate[T any](items []T, updatedAt func(T) time.Time) time.Time {
+	var latest time.Time
+	for _, item := range items {
+		if t := updatedAt(item); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
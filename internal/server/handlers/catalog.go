@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	sitemapPageSize     = 1000
+	maxSitemapPages     = 50
+	defaultFeedPageSize = 50
+	maxFeedPageSize     = 100
+
+	catalogCacheControl = "public, max-age=300"
+	sitemapNamespace    = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+type CatalogHandler struct {
+	catalogUseCase usecase.CatalogUseCase
+	publicURL      string
+}
+
+func NewCatalogHandler(catalogUseCase usecase.CatalogUseCase, publicURL string) *CatalogHandler {
+	return &CatalogHandler{
+		catalogUseCase: catalogUseCase,
+		publicURL:      strings.TrimRight(publicURL, "/"),
+	}
+}
+
+type SitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type SitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+type SitemapIndexEntry struct {
+	Loc string `xml:"loc"`
+}
+
+type SitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	XMLNS    string              `xml:"xmlns,attr"`
+	Sitemaps []SitemapIndexEntry `xml:"sitemap"`
+}
+
+// SchemaRestaurant is a restaurant in schema.org Restaurant markup.
+type SchemaRestaurant struct {
+	Type          string `json:"@type"`
+	ID            string `json:"@id"`
+	URL           string `json:"url"`
+	Name          string `json:"name"`
+	Address       string `json:"address,omitempty"`
+	ServesCuisine string `json:"servesCuisine,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Email         string `json:"email,omitempty"`
+	Telephone     string `json:"telephone,omitempty"`
+	DateModified  string `json:"dateModified,omitempty"`
+}
+
+type SchemaListItem struct {
+	Type     string           `json:"@type"`
+	Position int              `json:"position"`
+	Item     SchemaRestaurant `json:"item"`
+}
+
+// RestaurantsFeed is a page of the catalogue as a schema.org ItemList.
+type RestaurantsFeed struct {
+	Context         string           `json:"@context"`
+	Type            string           `json:"@type"`
+	ItemListElement []SchemaListItem `json:"itemListElement"`
+	Page            int              `json:"page"`
+	PageSize        int              `json:"page_size"`
+	Next            string           `json:"next,omitempty"`
+}
+
+// Sitemap godoc
+// @Summary Sitemap
+// @Description Sitemap of the public restaurant pages. Without the page parameter a sitemap index of all pages is returned
+// @Tags catalog
+// @Produce xml
+// @Param page query int false "Sitemap page"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /sitemap.xml [get]
+func (h *CatalogHandler) Sitemap(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if c.Query("page") == "" {
+		index := SitemapIndex{XMLNS: sitemapNamespace}
+		for page := 1; page <= maxSitemapPages; page++ {
+			catalogPage, err := h.catalogUseCase.GetCatalogPage(ctx, page, sitemapPageSize)
+			if err != nil {
+				log.Error(ctx, common.ErrBuildSitemap, zap.Int("page", page), zap.Error(err))
+
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": common.ErrInternalServer,
+				})
+			}
+
+			index.Sitemaps = append(index.Sitemaps, SitemapIndexEntry{
+				Loc: fmt.Sprintf("%s/sitemap.xml?page=%d", h.publicURL, page),
+			})
+
+			if !catalogPage.HasNext {
+				break
+			}
+		}
+
+		return h.sendXML(c, index)
+	}
+
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	catalogPage, err := h.catalogUseCase.GetCatalogPage(ctx, page, sitemapPageSize)
+	if err != nil {
+		log.Error(ctx, common.ErrBuildSitemap, zap.Int("page", page), zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	urlSet := SitemapURLSet{
+		XMLNS: sitemapNamespace,
+		URLs:  make([]SitemapURL, 0, len(catalogPage.Restaurants)),
+	}
+	for _, restaurant := range catalogPage.Restaurants {
+		urlSet.URLs = append(urlSet.URLs, SitemapURL{
+			Loc:     h.restaurantURL(restaurant),
+			LastMod: formatLastMod(restaurant.UpdatedAt, time.DateOnly),
+		})
+	}
+
+	return h.sendXML(c, urlSet)
+}
+
+// RestaurantsFeed godoc
+// @Summary Restaurants feed
+// @Description Machine-readable restaurant catalogue in schema.org Restaurant markup for partners
+// @Tags catalog
+// @Produce json
+// @Param page query int false "Page" default(1)
+// @Param page_size query int false "Page size" default(50)
+// @Success 200 {object} RestaurantsFeed
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feed/restaurants.json [get]
+func (h *CatalogHandler) RestaurantsFeed(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	pageSize, err := strconv.Atoi(c.Query("page_size", strconv.Itoa(defaultFeedPageSize)))
+	if err != nil || pageSize < 1 || pageSize > maxFeedPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	catalogPage, err := h.catalogUseCase.GetCatalogPage(ctx, page, pageSize)
+	if err != nil {
+		log.Error(ctx, common.ErrBuildRestaurantsFeed, zap.Int("page", page), zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	feed := RestaurantsFeed{
+		Context:         "https://schema.org",
+		Type:            "ItemList",
+		ItemListElement: make([]SchemaListItem, 0, len(catalogPage.Restaurants)),
+		Page:            catalogPage.Page,
+		PageSize:        catalogPage.PageSize,
+	}
+	for i, restaurant := range catalogPage.Restaurants {
+		feed.ItemListElement = append(feed.ItemListElement, SchemaListItem{
+			Type:     "ListItem",
+			Position: (catalogPage.Page-1)*catalogPage.PageSize + i + 1,
+			Item:     h.schemaRestaurant(restaurant),
+		})
+	}
+
+	if catalogPage.HasNext {
+		feed.Next = fmt.Sprintf("%s/feed/restaurants.json?page=%d&page_size=%d", h.publicURL, page+1, pageSize)
+		c.Set(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="next"`, feed.Next))
+	}
+
+	c.Set(fiber.HeaderCacheControl, catalogCacheControl)
+
+	return c.Status(fiber.StatusOK).JSON(feed)
+}
+
+func (h *CatalogHandler) sendXML(c fiber.Ctx, v any) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	c.Set(fiber.HeaderCacheControl, catalogCacheControl)
+
+	return c.Status(fiber.StatusOK).Send(append([]byte(xml.Header), body...))
+}
+
+func (h *CatalogHandler) restaurantURL(restaurant *domain.Restaurant) string {
+	return h.publicURL + "/restaurants/" + restaurant.ID
+}
+
+func (h *CatalogHandler) schemaRestaurant(restaurant *domain.Restaurant) SchemaRestaurant {
+	url := h.restaurantURL(restaurant)
+
+	return SchemaRestaurant{
+		Type:          "Restaurant",
+		ID:            url,
+		URL:           url,
+		Name:          restaurant.Name,
+		Address:       restaurant.Address,
+		ServesCuisine: string(restaurant.Cuisine),
+		Description:   restaurant.Description,
+		Email:         restaurant.ContactEmail,
+		Telephone:     restaurant.ContactPhone,
+		DateModified:  formatLastMod(restaurant.UpdatedAt, time.RFC3339),
+	}
+}
+
+func formatLastMod(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(layout)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type CustomDomainHandler struct {
+	customDomainUseCase usecase.CustomDomainUseCase
+	cnameTarget         string
+}
+
+// NewCustomDomainHandler creates the handler; the CNAME target is the host the custom domains
+// have to point at.
+func NewCustomDomainHandler(customDomainUseCase usecase.CustomDomainUseCase, cnameTarget string) *CustomDomainHandler {
+	return &CustomDomainHandler{
+		customDomainUseCase: customDomainUseCase,
+		cnameTarget:         cnameTarget,
+	}
+}
+
+type AddCustomDomainRequest struct {
+	// Domain is a domain or subdomain the restaurant controls, such as book.example.com.
+	Domain string `json:"domain"`
+}
+
+// DNSRecordResponse is a record the restaurant has to publish at its DNS provider.
+type DNSRecordResponse struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CustomDomainResponse struct {
+	ID           string                    `json:"id"`
+	RestaurantID string                    `json:"restaurant_id"`
+	Domain       string                    `json:"domain"`
+	Status       domain.CustomDomainStatus `json:"status"`
+	// DNSRecords are the TXT record proving control of the domain and the CNAME record pointing
+	// it at the platform.
+	DNSRecords []DNSRecordResponse `json:"dns_records"`
+	CreatedAt  time.Time           `json:"created_at"`
+	VerifiedAt *time.Time          `json:"verified_at,omitempty"`
+}
+
+func (h *CustomDomainHandler) newCustomDomainResponse(customDomain *domain.CustomDomain) CustomDomainResponse {
+	records := []DNSRecordResponse{{
+		Type:  "TXT",
+		Name:  customDomain.VerificationRecord(),
+		Value: customDomain.VerificationToken,
+	}}
+	if h.cnameTarget != "" {
+		records = append(records, DNSRecordResponse{
+			Type:  "CNAME",
+			Name:  customDomain.Domain,
+			Value: h.cnameTarget,
+		})
+	}
+
+	return CustomDomainResponse{
+		ID:           customDomain.ID,
+		RestaurantID: customDomain.RestaurantID,
+		Domain:       customDomain.Domain,
+		Status:       customDomain.Status,
+		DNSRecords:   records,
+		CreatedAt:    customDomain.CreatedAt,
+		VerifiedAt:   customDomain.VerifiedAt,
+	}
+}
+
+// AddCustomDomain godoc
+// @Summary Add a custom domain
+// @Description Register a domain of the restaurant to serve its booking page from, pending verification. The response lists the DNS records to publish: a TXT record holding the verification token and a CNAME record pointing the domain at the platform
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param domain body AddCustomDomainRequest true "Domain"
+// @Success 201 {object} CustomDomainResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "The domain is registered, or the restaurant has too many domains"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/domains [post]
+func (h *CustomDomainHandler) AddCustomDomain(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request AddCustomDomainRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	customDomain, err := h.customDomainUseCase.AddDomain(ctx, restaurantID, request.Domain)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCustomDomain):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		case errors.Is(err, usecase.ErrCustomDomainLimit), err.Error() == common.ErrCustomDomainTaken:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateCustomDomain, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.newCustomDomainResponse(customDomain))
+}
+
+// ListCustomDomains godoc
+// @Summary List custom domains
+// @Description The domains of the restaurant, oldest first, with the DNS records each needs
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {array} CustomDomainResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/domains [get]
+func (h *CustomDomainHandler) ListCustomDomains(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	customDomains, err := h.customDomainUseCase.ListDomains(ctx, restaurantID)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListCustomDomains, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(mapResponses(customDomains, h.newCustomDomainResponse))
+}
+
+// VerifyCustomDomain godoc
+// @Summary Verify a custom domain
+// @Description Check the verification TXT record of the domain. Once verified the domain serves the booking page of the restaurant and gets a certificate on its first HTTPS request, as soon as its CNAME record points at the platform
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param domainId path string true "Domain ID"
+// @Success 200 {object} CustomDomainResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Domain not found"
+// @Failure 422 {object} map[string]string "The TXT record is missing or holds another token"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/domains/{domainId}/verify [post]
+func (h *CustomDomainHandler) VerifyCustomDomain(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	domainID := c.Params("domainId")
+	if restaurantID == "" || domainID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	customDomain, err := h.customDomainUseCase.VerifyDomain(ctx, restaurantID, domainID)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrCustomDomainUnverified):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrCustomDomainNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrCustomDomainNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateCustomDomain, zap.String("domainID", domainID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(h.newCustomDomainResponse(customDomain))
+}
+
+// RemoveCustomDomain godoc
+// @Summary Remove a custom domain
+// @Description Stop serving the booking page of the restaurant from the domain
+// @Tags restaurants
+// @Param id path string true "Restaurant ID"
+// @Param domainId path string true "Domain ID"
+// @Success 204 "Removed"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Domain not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/domains/{domainId} [delete]
+func (h *CustomDomainHandler) RemoveCustomDomain(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	domainID := c.Params("domainId")
+	if restaurantID == "" || domainID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.customDomainUseCase.RemoveDomain(ctx, restaurantID, domainID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrCustomDomainNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrCustomDomainNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteCustomDomain, zap.String("domainID", domainID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// boardCacheControl keeps the board, which is private to the staff, out of shared caches and makes
+// browsers ask every time.
+const boardCacheControl = "private, no-cache"
+
+type DisplayBoardHandler struct {
+	displayBoardUseCase usecase.DisplayBoardUseCase
+}
+
+func NewDisplayBoardHandler(displayBoardUseCase usecase.DisplayBoardUseCase) *DisplayBoardHandler {
+	return &DisplayBoardHandler{
+		displayBoardUseCase: displayBoardUseCase,
+	}
+}
+
+type BoardBookingResponse struct {
+	ID          string                 `json:"id"`
+	Time        string                 `json:"time"`
+	GuestsCount int                    `json:"guests_count"`
+	GuestName   string                 `json:"guest_name,omitempty"`
+	Status      domain.BookingStatus   `json:"status"`
+	Occasion    domain.BookingOccasion `json:"occasion,omitempty"`
+	Comment     string                 `json:"comment,omitempty"`
+	TableID     string                 `json:"table_id,omitempty"`
+}
+
+func newBoardBookingResponse(booking usecase.BoardBooking) BoardBookingResponse {
+	return BoardBookingResponse{
+		ID:          booking.ID,
+		Time:        booking.Time,
+		GuestsCount: booking.GuestsCount,
+		GuestName:   booking.GuestName,
+		Status:      booking.Status,
+		Occasion:    booking.Occasion,
+		Comment:     booking.Comment,
+		TableID:     booking.TableID,
+	}
+}
+
+type DisplayBoardResponse struct {
+	Date     string                 `json:"date"`
+	Version  string                 `json:"version"`
+	Delta    bool                   `json:"delta"`
+	Bookings []BoardBookingResponse `json:"bookings"`
+}
+
+// GetDisplayBoard godoc
+// @Summary Get the host-stand board
+// @Description Today's bookings of the restaurant in a compact form for a display polling every few seconds. Without since the pending, confirmed and completed bookings are returned; with the version of the previous response as since only the bookings changed after it are, in every status, and delta is true. 304 is answered when nothing changed since the version or the If-Modified-Since time. Restaurant staff only
+// @Tags restaurants,bookings
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param since query string false "Version of the previous response"
+// @Param If-Modified-Since header string false "Last-Modified of the previous response"
+// @Success 200 {object} DisplayBoardResponse
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/board [get]
+func (h *DisplayBoardHandler) GetDisplayBoard(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	query := usecase.BoardQuery{
+		RestaurantID: id,
+		Date:         time.Now(),
+		Since:        c.Query("since"),
+	}
+	if modifiedSince := c.Get(fiber.HeaderIfModifiedSince); modifiedSince != "" {
+		// An unreadable date is ignored, as RFC 9110 requires.
+		if since, err := http.ParseTime(modifiedSince); err == nil {
+			query.ModifiedSince = since
+		}
+	}
+
+	board, err := h.displayBoardUseCase.GetBoard(ctx, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case errors.Is(err, usecase.ErrInvalidBoardVersion):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidBoardVersion,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetDisplayBoard, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, boardCacheControl)
+	c.Set(fiber.HeaderLastModified, board.UpdatedAt.UTC().Format(http.TimeFormat))
+	if board.NotModified {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(DisplayBoardResponse{
+		Date:     board.Date.Format(time.DateOnly),
+		Version:  board.Version,
+		Delta:    board.Delta,
+		Bookings: mapResponses(board.Bookings, newBoardBookingResponse),
+	})
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxEmbedDays bounds the days a widget may ask for; each day is a query.
+	maxEmbedDays     = 14
+	defaultEmbedDays = 7
+
+	embedFormatJSON = "json"
+	embedFormatHTML = "html"
+
+	embedCacheControl = "public, max-age=60"
+)
+
+// embedTemplate is the HTML widget, small enough to be iframed into any page as it is.
+var embedTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Restaurant.Name}}</title>
+<style>
+body{margin:0;padding:8px;font:14px/1.4 system-ui,sans-serif;color:#222}
+h1{margin:0 0 8px;font-size:16px}
+.day{margin-bottom:8px}
+.date{font-weight:600}
+.slots{display:flex;flex-wrap:wrap;gap:4px;margin-top:4px}
+.slot{padding:2px 8px;border:1px solid #2a7;border-radius:4px;color:#2a7;text-decoration:none}
+.slot.limited{border-color:#d80;color:#d80}
+.none{color:#888}
+</style>
+</head>
+<body>
+<h1>{{.Restaurant.Name}}</h1>
+{{range .Days}}<div class="day"><div class="date">{{.Date}}</div>
+{{if .Slots}}<div class="slots">{{range .Slots}}<a class="slot {{.Status}}" href="{{$.BookURL}}?date={{.Date}}&amp;time={{.Time}}" target="_blank" rel="noopener">{{.Time}}</a>{{end}}</div>
+{{else}}<div class="none">No free tables</div>
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
+
+type EmbedHandler struct {
+	restaurantUseCase   usecase.RestaurantUseCase
+	availabilityUseCase usecase.AvailabilityUseCase
+	publicURL           string
+	days                int
+	frameAncestors      string
+}
+
+func NewEmbedHandler(
+	restaurantUseCase usecase.RestaurantUseCase,
+	availabilityUseCase usecase.AvailabilityUseCase,
+	publicURL string,
+	days int,
+	frameAncestors []string,
+) *EmbedHandler {
+	switch {
+	case days < 1:
+		days = defaultEmbedDays
+	case days > maxEmbedDays:
+		days = maxEmbedDays
+	}
+
+	return &EmbedHandler{
+		restaurantUseCase:   restaurantUseCase,
+		availabilityUseCase: availabilityUseCase,
+		publicURL:           strings.TrimRight(publicURL, "/"),
+		days:                days,
+		frameAncestors:      strings.Join(frameAncestors, " "),
+	}
+}
+
+type EmbedRestaurantResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug,omitempty"`
+}
+
+// EmbedSlotResponse is a slot with free seats; Status is "available" or "limited".
+type EmbedSlotResponse struct {
+	Date   string `json:"-"`
+	Time   string `json:"time"`
+	Free   int    `json:"free"`
+	Status string `json:"status"`
+}
+
+type EmbedDayResponse struct {
+	Date  string              `json:"date"`
+	Slots []EmbedSlotResponse `json:"slots"`
+}
+
+type EmbedAvailabilityResponse struct {
+	Restaurant EmbedRestaurantResponse `json:"restaurant"`
+	BookURL    string                  `json:"book_url"`
+	Days       []EmbedDayResponse      `json:"days"`
+}
+
+// GetAvailabilityWidget godoc
+// @Summary Availability widget
+// @Description The free slots of a restaurant for the next days in a compact shape for widgets on restaurant websites, which may fetch it from the allowed origins. With format=html it is a ready page to put in an iframe; fully booked slots are left out
+// @Tags embed
+// @Produce json
+// @Produce html
+// @Param id path string true "Restaurant ID"
+// @Param date query string false "First day (YYYY-MM-DD), today by default"
+// @Param days query int false "Number of days, at most 14"
+// @Param format query string false "json (default) or html"
+// @Success 200 {object} EmbedAvailabilityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /embed/restaurants/{id}/availability [get]
+func (h *EmbedHandler) GetAvailabilityWidget(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	format := c.Query("format", embedFormatJSON)
+	if format != embedFormatJSON && format != embedFormatHTML {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("date"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	days := h.days
+	if value := c.Query("days"); value != "" {
+		if days, err = strconv.Atoi(value); err != nil || days < 1 || days > maxEmbedDays {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
+	if err != nil && err.Error() != common.ErrRestaurantNotFound {
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	if restaurant == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantNotFound,
+		})
+	}
+
+	path := restaurant.Slug
+	if path == "" {
+		path = restaurant.ID
+	}
+	widget := EmbedAvailabilityResponse{
+		Restaurant: EmbedRestaurantResponse{
+			ID:   restaurant.ID,
+			Name: restaurant.Name,
+			Slug: restaurant.Slug,
+		},
+		BookURL: h.publicURL + "/restaurants/" + path,
+	}
+
+	widget.Days, err = freeSlotDays(ctx, h.availabilityUseCase, id, from, days)
+	if err != nil {
+		log.Error(ctx, common.ErrGetCurrentAvailability, zap.String("restaurantID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, embedCacheControl)
+	if format == embedFormatJSON {
+		return c.Status(fiber.StatusOK).JSON(widget)
+	}
+
+	var page bytes.Buffer
+	if err := embedTemplate.Execute(&page, widget); err != nil {
+		log.Error(ctx, common.ErrRenderEmbedWidget, zap.String("restaurantID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderContentSecurityPolicy, "frame-ancestors "+h.frameAncestors)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).Send(page.Bytes())
+}
+
+// freeSlotDays returns the slots with free seats of the restaurant for the days from the given
+// one.
+func freeSlotDays(ctx context.Context, availabilityUseCase usecase.AvailabilityUseCase, restaurantID domain.RestaurantID, from time.Time, days int) ([]EmbedDayResponse, error) {
+	result := make([]EmbedDayResponse, 0, days)
+	for i := range days {
+		date := from.AddDate(0, 0, i)
+		slots, err := availabilityUseCase.GetAvailability(ctx, restaurantID, date)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, newEmbedDayResponse(date, slots))
+	}
+	return result, nil
+}
+
+func newEmbedDayResponse(date time.Time, slots []*domain.Availability) EmbedDayResponse {
+	day := EmbedDayResponse{
+		Date:  date.Format(time.DateOnly),
+		Slots: make([]EmbedSlotResponse, 0, len(slots)),
+	}
+	for _, slot := range slots {
+		status := slot.AvailabilityStatus()
+		if status == "fully_booked" {
+			continue
+		}
+		day.Slots = append(day.Slots, EmbedSlotResponse{
+			Date:   day.Date,
+			Time:   slot.TimeSlot,
+			Free:   slot.AvailableSeats(),
+			Status: status,
+		})
+	}
+	return day
+}
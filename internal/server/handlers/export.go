@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const contentTypeNDJSON = "application/x-ndjson"
+
+type ExportHandler struct {
+	exportUseCase usecase.ExportUseCase
+}
+
+func NewExportHandler(exportUseCase usecase.ExportUseCase) *ExportHandler {
+	return &ExportHandler{
+		exportUseCase: exportUseCase,
+	}
+}
+
+// ExportTrailer is the last line of an export: the checkpoint to pass as since to the next
+// export, or the error that cut the export short.
+type ExportTrailer struct {
+	Checkpoint *time.Time `json:"checkpoint,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// ExportChanges godoc
+// @Summary Export changed records
+// @Description Stream every record updated after since, or every record without it, as newline-delimited JSON, one domain.ExportRecord per line, entity by entity. The last line is an ExportTrailer with the checkpoint for the next export, or with the error that cut the export short; a stream without a checkpoint is incomplete. Admins only
+// @Tags admin
+// @Produce application/x-ndjson
+// @Param since query string false "Checkpoint of the previous export, RFC 3339"
+// @Param entities query string false "Comma-separated entities to export, all by default"
+// @Success 200 {object} domain.ExportRecord
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/export [get]
+func (h *ExportHandler) ExportChanges(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	var entities []domain.ExportEntity
+	for _, entity := range strings.Split(c.Query("entities"), ",") {
+		if entity = strings.TrimSpace(entity); entity != "" {
+			entities = append(entities, domain.ExportEntity(entity))
+		}
+	}
+
+	// The export runs while the response is sent; its status is decided by the first record, or
+	// by the result when there is none, so rejected exports still get an error status.
+	pr, pw := io.Pipe()
+	started := make(chan error, 1)
+	go func() {
+		enc := json.NewEncoder(pw)
+		emitted := false
+		checkpoint, err := h.exportUseCase.ExportChanges(ctx, since, entities, func(record *domain.ExportRecord) error {
+			if !emitted {
+				emitted = true
+				started <- nil
+			}
+			return enc.Encode(record)
+		})
+		if !emitted {
+			started <- err
+			if err != nil {
+				_ = pw.Close()
+				return
+			}
+		}
+
+		trailer := ExportTrailer{Checkpoint: &checkpoint}
+		if err != nil {
+			log.Error(ctx, common.ErrExportChanges, zap.Time("since", since), zap.Error(err))
+			trailer = ExportTrailer{Error: common.ErrExportChanges}
+		}
+		_ = enc.Encode(trailer)
+		_ = pw.Close()
+	}()
+
+	if err := <-started; err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case errors.Is(err, usecase.ErrInvalidExportEntity):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrExportChanges, zap.Time("since", since), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrExportChanges,
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, contentTypeNDJSON)
+	return c.Status(fiber.StatusOK).SendStream(pr)
+}
@@ -2,9 +2,12 @@
 package handlers
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
@@ -28,7 +31,9 @@ func NewFactsHandler(factsUseCase usecase.FactsUseCase) *FactsHandler {
 // @Accept json
 // @Produce json
 // @Param count query int false "Number of facts to return" default(3)
-// @Success 200 {array} domain.Fact
+// @Param restaurant_id query string false "Only facts of the given restaurant"
+// @Param cuisine query string false "Only facts of restaurants with the given cuisine"
+// @Success 200 {array} FactResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /facts/random [get]
@@ -45,7 +50,17 @@ func (h *FactsHandler) GetRandomFacts(c fiber.Ctx) error {
 		count = 3
 	}
 
-	facts, err := h.factsUseCase.GetRandomFacts(ctx, count)
+	filter := domain.FactFilter{
+		RestaurantID: c.Query("restaurant_id"),
+		Cuisine:      domain.Cuisine(c.Query("cuisine")),
+	}
+
+	var facts []domain.Fact
+	if filter.IsEmpty() {
+		facts, err = h.factsUseCase.GetRandomFacts(ctx, count)
+	} else {
+		facts, err = h.factsUseCase.GetFilteredRandomFacts(ctx, filter, count)
+	}
 	if err != nil {
 		log.Error(ctx, common.ErrGetRandomFacts, zap.Error(err))
 
@@ -54,5 +69,58 @@ func (h *FactsHandler) GetRandomFacts(c fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(facts)
+	return c.Status(fiber.StatusOK).JSON(mapResponses(facts, newFactResponse))
+}
+
+// GetFactOfTheDay godoc
+// @Summary Get fact of the day
+// @Description Get the featured fact of the current day. The locale is taken from the query or the Accept-Language header and, without either, from the location of the client address
+// @Tags facts
+// @Accept json
+// @Produce json
+// @Param locale query string false "Fact locale" default(en)
+// @Param Accept-Language header string false "Preferred language, used when locale is not set"
+// @Success 200 {object} FactResponse
+// @Failure 404 {object} map[string]string "No facts available"
+// @Failure 500 {object} map[string]string
+// @Router /facts/today [get]
+func (h *FactsHandler) GetFactOfTheDay(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	locale := requestLocale(ctx, c)
+
+	fact, err := h.factsUseCase.GetFactOfTheDay(ctx, locale)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNoFactsAvailable) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrNoFactsAvailable,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetFactOfTheDay, zap.String("locale", locale), zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalError,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newFactResponse(*fact))
+}
+
+// preferredLanguage returns the first language tag of an Accept-Language header value.
+func preferredLanguage(header string) string {
+	tag, _, _ := strings.Cut(header, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+
+	tag = strings.TrimSpace(tag)
+	if tag == "*" {
+		return ""
+	}
+
+	return tag
 }
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type FaultInjectionHandler struct {
+	faultInjectionUseCase usecase.FaultInjectionUseCase
+}
+
+func NewFaultInjectionHandler(faultInjectionUseCase usecase.FaultInjectionUseCase) *FaultInjectionHandler {
+	return &FaultInjectionHandler{
+		faultInjectionUseCase: faultInjectionUseCase,
+	}
+}
+
+type RouteFaultRequest struct {
+	// Method is empty for every method.
+	Method string `json:"method,omitempty"`
+	// Path is a route path as registered, e.g. "/api/v1/restaurants/:id/availability".
+	Path        string  `json:"path"`
+	LatencyMS   int64   `json:"latency_ms"`
+	ErrorRate   float64 `json:"error_rate"`
+	ErrorStatus int     `json:"error_status,omitempty"`
+}
+
+type RouteFaultResponse struct {
+	ID          string    `json:"id"`
+	Method      string    `json:"method,omitempty"`
+	Path        string    `json:"path"`
+	LatencyMS   int64     `json:"latency_ms"`
+	ErrorRate   float64   `json:"error_rate"`
+	ErrorStatus int       `json:"error_status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newRouteFaultResponse(fault domain.RouteFault) RouteFaultResponse {
+	return RouteFaultResponse{
+		ID:          fault.ID,
+		Method:      fault.Method,
+		Path:        fault.Path,
+		LatencyMS:   fault.Latency.Milliseconds(),
+		ErrorRate:   fault.ErrorRate,
+		ErrorStatus: fault.ErrorStatus,
+		CreatedAt:   fault.CreatedAt,
+	}
+}
+
+type FaultInjectionResponse struct {
+	Enabled       bool                 `json:"enabled"`
+	Routes        []RouteFaultResponse `json:"routes"`
+	DBFailureRate float64              `json:"db_failure_rate"`
+}
+
+type DBFaultRequest struct {
+	FailureRate float64 `json:"failure_rate"`
+}
+
+// GetFaults godoc
+// @Summary Get injected faults
+// @Description The faults injected into routes and the share of database calls failed, and whether fault injection is enabled at all. Admins only
+// @Tags admin
+// @Produce json
+// @Success 200 {object} FaultInjectionResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/faults [get]
+func (h *FaultInjectionHandler) GetFaults(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	faults, err := h.faultInjectionUseCase.GetFaults(ctx)
+	if err != nil {
+		return h.faultError(ctx, log, c, err)
+	}
+
+	routes := make([]RouteFaultResponse, 0, len(faults.Routes))
+	for _, fault := range faults.Routes {
+		routes = append(routes, newRouteFaultResponse(fault))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(FaultInjectionResponse{
+		Enabled:       faults.Enabled,
+		Routes:        routes,
+		DBFailureRate: faults.DBFailureRate,
+	})
+}
+
+// AddRouteFault godoc
+// @Summary Inject a fault into a route
+// @Description Delay every request of the route by latency_ms and answer a share error_rate of them with error_status, 503 by default. Only where fault injection is enabled; admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param fault body RouteFaultRequest true "Fault"
+// @Success 201 {object} RouteFaultResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Fault injection is disabled"
+// @Failure 500 {object} map[string]string
+// @Router /admin/faults/routes [post]
+func (h *FaultInjectionHandler) AddRouteFault(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request RouteFaultRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	fault := &domain.RouteFault{
+		Method:      request.Method,
+		Path:        request.Path,
+		Latency:     time.Duration(request.LatencyMS) * time.Millisecond,
+		ErrorRate:   request.ErrorRate,
+		ErrorStatus: request.ErrorStatus,
+	}
+	if err := h.faultInjectionUseCase.AddRouteFault(ctx, fault); err != nil {
+		return h.faultError(ctx, log, c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newRouteFaultResponse(*fault))
+}
+
+// DeleteRouteFault godoc
+// @Summary Remove a fault from a route
+// @Description Only where fault injection is enabled; admins only
+// @Tags admin
+// @Param id path string true "Fault ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Fault not found or fault injection is disabled"
+// @Failure 500 {object} map[string]string
+// @Router /admin/faults/routes/{id} [delete]
+func (h *FaultInjectionHandler) DeleteRouteFault(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if err := h.faultInjectionUseCase.DeleteRouteFault(ctx, c.Params("id")); err != nil {
+		return h.faultError(ctx, log, c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetDBFault godoc
+// @Summary Fail database calls
+// @Description Fail a share failure_rate of the database calls as if the database were down; 0 stops. Only where fault injection is enabled; admins only
+// @Tags admin
+// @Accept json
+// @Param fault body DBFaultRequest true "Failure rate"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Fault injection is disabled"
+// @Failure 500 {object} map[string]string
+// @Router /admin/faults/db [put]
+func (h *FaultInjectionHandler) SetDBFault(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request DBFaultRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.faultInjectionUseCase.SetDBFailureRate(ctx, request.FailureRate); err != nil {
+		return h.faultError(ctx, log, c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ClearFaults godoc
+// @Summary Remove every injected fault
+// @Description Only where fault injection is enabled; admins only
+// @Tags admin
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Fault injection is disabled"
+// @Failure 500 {object} map[string]string
+// @Router /admin/faults [delete]
+func (h *FaultInjectionHandler) ClearFaults(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if err := h.faultInjectionUseCase.ClearFaults(ctx); err != nil {
+		return h.faultError(ctx, log, c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *FaultInjectionHandler) faultError(ctx context.Context, log ports.LoggerPort, c fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, tenant.ErrAccessDenied):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": common.ErrAccessDenied,
+		})
+	case errors.Is(err, usecase.ErrFaultInjectionDisabled), errors.Is(err, usecase.ErrRouteFaultNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidRouteFault), errors.Is(err, usecase.ErrInvalidFailureRate):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	log.Error(ctx, common.ErrManageFaults, zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": common.ErrInternalServer,
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type GeoHandler struct{}
+
+func NewGeoHandler() *GeoHandler {
+	return &GeoHandler{}
+}
+
+// GeoDefaultsResponse holds what a client without stored preferences starts with: the location
+// to search restaurants around, the currency to display prices in and the locale.
+type GeoDefaultsResponse struct {
+	// Located tells whether the client address was located; without it there is no location and
+	// the currency and locale are only the stated or service defaults.
+	Located     bool     `json:"located"`
+	CountryCode string   `json:"country_code,omitempty"`
+	City        string   `json:"city,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Currency    string   `json:"currency,omitempty"`
+	Locale      string   `json:"locale"`
+}
+
+// GetGeoDefaults godoc
+// @Summary Get location-based defaults
+// @Description Defaults derived from the location of the client address: where to search restaurants, the display currency and the locale. Preferences stated in the query or the Accept-Language header win over the location
+// @Tags geo
+// @Produce json
+// @Param currency query string false "Preferred display currency, an ISO 4217 code"
+// @Param locale query string false "Preferred locale"
+// @Param Accept-Language header string false "Preferred language, used when locale is not set"
+// @Success 200 {object} GeoDefaultsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /geo/defaults [get]
+func (h *GeoHandler) GetGeoDefaults(c fiber.Ctx) error {
+	ctx, _, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	response := GeoDefaultsResponse{
+		Locale: requestLocale(ctx, c),
+	}
+	if response.Locale == "" {
+		response.Locale = domain.DefaultFactLocale
+	}
+
+	location, located := geo.FromContext(ctx)
+	if located {
+		response.Located = true
+		response.CountryCode = location.CountryCode
+		response.City = location.City
+		response.Currency = location.Currency
+		if location.HasCoordinates {
+			response.Latitude = &location.Latitude
+			response.Longitude = &location.Longitude
+		}
+	}
+
+	if value := c.Query("currency"); value != "" {
+		currency, ok := domain.NormalizeCurrency(value)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidCurrency,
+			})
+		}
+		response.Currency = currency
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// requestLocale returns the locale the client asked for with the locale query parameter or the
+// Accept-Language header and, when it asked for none, the locale of its location.
+func requestLocale(ctx context.Context, c fiber.Ctx) string {
+	if locale := c.Query("locale"); locale != "" {
+		return locale
+	}
+	if locale := preferredLanguage(c.Get(fiber.HeaderAcceptLanguage)); locale != "" {
+		return locale
+	}
+	if location, ok := geo.FromContext(ctx); ok {
+		return location.Locale
+	}
+	return ""
+}
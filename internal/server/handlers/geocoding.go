@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type GeocodingHandler struct {
+	geocodingUseCase usecase.GeocodingUseCase
+}
+
+func NewGeocodingHandler(geocodingUseCase usecase.GeocodingUseCase) *GeocodingHandler {
+	return &GeocodingHandler{
+		geocodingUseCase: geocodingUseCase,
+	}
+}
+
+type RestaurantLocationResponse struct {
+	RestaurantID     string                 `json:"restaurant_id"`
+	Address          string                 `json:"address"`
+	Status           domain.GeocodingStatus `json:"status"`
+	FormattedAddress string                 `json:"formatted_address,omitempty"`
+	Street           string                 `json:"street,omitempty"`
+	HouseNumber      string                 `json:"house_number,omitempty"`
+	City             string                 `json:"city,omitempty"`
+	CityID           string                 `json:"city_id,omitempty"`
+	Region           string                 `json:"region,omitempty"`
+	PostalCode       string                 `json:"postal_code,omitempty"`
+	CountryCode      string                 `json:"country_code,omitempty"`
+	Latitude         *float64               `json:"latitude,omitempty"`
+	Longitude        *float64               `json:"longitude,omitempty"`
+	Provider         string                 `json:"provider,omitempty"`
+	Error            string                 `json:"error,omitempty"`
+	Attempts         int                    `json:"attempts"`
+	GeocodedAt       *time.Time             `json:"geocoded_at,omitempty"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+func newRestaurantLocationResponse(location *domain.RestaurantLocation) RestaurantLocationResponse {
+	response := RestaurantLocationResponse{
+		RestaurantID:     location.RestaurantID,
+		Address:          location.Address,
+		Status:           location.Status,
+		FormattedAddress: location.Formatted,
+		Street:           location.Street,
+		HouseNumber:      location.HouseNumber,
+		City:             location.City,
+		CityID:           location.CityID,
+		Region:           location.Region,
+		PostalCode:       location.PostalCode,
+		CountryCode:      location.CountryCode,
+		Provider:         location.Provider,
+		Error:            location.Error,
+		Attempts:         location.Attempts,
+		GeocodedAt:       location.GeocodedAt,
+		UpdatedAt:        location.UpdatedAt,
+	}
+	// Coordinates are only known once the address is geocoded; 0,0 is a place too.
+	if location.Status == domain.GeocodingDone {
+		response.Latitude = &location.Latitude
+		response.Longitude = &location.Longitude
+	}
+	return response
+}
+
+// GetRestaurantLocation godoc
+// @Summary Get restaurant location
+// @Description The address of the restaurant as geocoded in the background after it was set: pending or processing while it waits, done with its components and coordinates, or failed with the reason, which the restaurant is also notified of. Restaurant staff only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} RestaurantLocationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "The address was never geocoded"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/location [get]
+func (h *GeocodingHandler) GetRestaurantLocation(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	location, err := h.geocodingUseCase.GetRestaurantLocation(ctx, id)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+		if err.Error() == common.ErrRestaurantLocationNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantLocationNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurantLocation, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newRestaurantLocationResponse(location))
+}
+
+type CityResponse struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Region          string `json:"region,omitempty"`
+	CountryCode     string `json:"country_code,omitempty"`
+	RestaurantCount int    `json:"restaurant_count"`
+}
+
+func newCityResponse(city *domain.City) CityResponse {
+	return CityResponse{
+		ID:              city.ID,
+		Name:            city.Name,
+		Region:          city.Region,
+		CountryCode:     city.CountryCode,
+		RestaurantCount: city.RestaurantCount,
+	}
+}
+
+// ListCities godoc
+// @Summary List cities
+// @Description The cities restaurants were placed in by geocoding their addresses, with the number of restaurants in each, those with the most first. The id of a city filters GET /restaurants by its city_id parameter
+// @Tags restaurants
+// @Produce json
+// @Param country query string false "ISO 3166-1 alpha-2 country code; every country by default"
+// @Success 200 {array} CityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /cities [get]
+func (h *GeocodingHandler) ListCities(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	country := c.Query("country")
+	cities, err := h.geocodingUseCase.ListCities(ctx, country)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCountryCode) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrListCities, zap.String("country", country), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(cities, newCityResponse))
+}
@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// An uploaded image never changes, so every variant of it can be kept for good.
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+type ImageHandler struct {
+	imageUseCase usecase.ImageUseCase
+	publicURL    string
+}
+
+func NewImageHandler(imageUseCase usecase.ImageUseCase, publicURL string) *ImageHandler {
+	return &ImageHandler{
+		imageUseCase: imageUseCase,
+		publicURL:    strings.TrimRight(publicURL, "/"),
+	}
+}
+
+type ImageResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	ContentType  string    `json:"content_type"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Size         int       `json:"size"`
+	URL          string    `json:"url"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	Variants []ImageVariantResponse `json:"variants"`
+}
+
+// ImageVariantResponse describes the image converted to another format in the background; the url
+// of the image serves it to clients accepting the format once it is ready.
+type ImageVariantResponse struct {
+	ContentType string `json:"content_type"`
+	Status      string `json:"status"`
+	Size        int    `json:"size,omitempty"`
+}
+
+func (h *ImageHandler) newImageResponse(image *domain.RestaurantImage) ImageResponse {
+	return ImageResponse{
+		ID:           image.ID,
+		RestaurantID: image.RestaurantID,
+		ContentType:  image.ContentType,
+		Width:        image.Width,
+		Height:       image.Height,
+		Size:         image.Size,
+		URL:          h.publicURL + "/images/" + image.ID,
+		CreatedAt:    image.CreatedAt,
+		Variants: mapResponses(image.Variants, func(variant domain.RestaurantImageVariant) ImageVariantResponse {
+			return ImageVariantResponse{
+				ContentType: variant.ContentType,
+				Status:      string(variant.Status),
+				Size:        variant.Size,
+			}
+		}),
+	}
+}
+
+// UploadImage godoc
+// @Summary Upload restaurant image
+// @Description Upload a JPEG, PNG or GIF image for a restaurant as the raw request body
+// @Tags restaurants
+// @Accept jpeg
+// @Accept png
+// @Accept gif
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Success 201 {object} ImageResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 413 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/images [post]
+func (h *ImageHandler) UploadImage(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" || len(c.Body()) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	image, err := h.imageUseCase.UploadImage(ctx, id, bytes.Clone(c.Body()))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidImage):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUploadImage, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusCreated, h.newImageResponse(image))
+}
+
+// ListImages godoc
+// @Summary List restaurant images
+// @Description Images of a restaurant, oldest first; the url of an image serves resized variants of it
+// @Tags restaurants
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Success 200 {array} ImageResponse
+// @Success 304 {string} string "Not modified since the cached copy"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/images [get]
+func (h *ImageHandler) ListImages(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	images, err := h.imageUseCase.ListImages(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrListImages, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	lastModified := latestUpdate(images, func(image *domain.RestaurantImage) time.Time { return image.CreatedAt })
+	return respondCacheable(c, lastModified, mapResponses(images, h.newImageResponse))
+}
+
+// DeleteImage godoc
+// @Summary Delete restaurant image
+// @Description Delete an image of a restaurant together with its cached variants
+// @Tags restaurants
+// @Param id path string true "Restaurant ID"
+// @Param imageId path string true "Image ID"
+// @Success 204 "Deleted"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Image not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/images/{imageId} [delete]
+func (h *ImageHandler) DeleteImage(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	imageID := c.Params("imageId")
+	if id == "" || imageID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.imageUseCase.DeleteImage(ctx, id, imageID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrImageNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrImageNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteImage, zap.String("imageID", imageID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetImage godoc
+// @Summary Get image
+// @Description An image of a restaurant resized on the fly, e.g. for the small screens of mobile clients. Without w and h the original upload is returned. Images are never scaled up. Clients naming a configured variant format such as image/webp in Accept get it once the variant has been generated; otherwise JPEG images stay JPEG and others are returned as PNG
+// @Tags images
+// @Produce jpeg
+// @Produce png
+// @Produce gif
+// @Produce webp
+// @Param id path string true "Image ID"
+// @Param w query int false "Width in pixels"
+// @Param h query int false "Height in pixels"
+// @Param fit query string false "contain (default) fits the image inside w×h, cover crops it to fill w×h, fill stretches it to w×h"
+// @Success 200 {file} binary
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Image not found"
+// @Failure 500 {object} map[string]string
+// @Router /images/{id} [get]
+func (h *ImageHandler) GetImage(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	opts, ok := imageOptions(c)
+	if !ok || id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	// The format depends on the Accept header and on which variants are ready yet, so the ETag
+	// is only known once the variant is.
+	variant, err := h.imageUseCase.GetImageVariant(ctx, id, opts, acceptedImageTypes(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidImageSize):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case err.Error() == common.ErrImageNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrImageNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetImage, zap.String("imageID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	etag := `"` + variant.Key + `"`
+	c.Set(fiber.HeaderCacheControl, imageCacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	c.Vary(fiber.HeaderAccept)
+	if notModified(c, etag, time.Time{}) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, variant.ContentType)
+	return c.Status(fiber.StatusOK).Send(variant.Data)
+}
+
+// acceptedImageTypes returns the image types the Accept header names explicitly. Wildcards are
+// left out: clients sending only */* may not be able to show newer formats such as WebP.
+func acceptedImageTypes(c fiber.Ctx) []string {
+	var accepted []string
+	for _, part := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if !strings.HasPrefix(mediaType, "image/") || strings.HasSuffix(mediaType, "/*") {
+			continue
+		}
+
+		rejected := false
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				rejected = err != nil || q <= 0
+			}
+		}
+		if !rejected {
+			accepted = append(accepted, mediaType)
+		}
+	}
+	return accepted
+}
+
+// imageOptions reads the w, h and fit query parameters; a missing size is zero.
+func imageOptions(c fiber.Ctx) (imaging.Options, bool) {
+	var opts imaging.Options
+	for name, target := range map[string]*int{"w": &opts.Width, "h": &opts.Height} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return opts, false
+		}
+		*target = n
+	}
+
+	fit, err := imaging.ParseFit(c.Query("fit"))
+	if err != nil {
+		return opts, false
+	}
+	opts.Fit = fit
+	return opts, true
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type IncentiveHandler struct {
+	incentiveUseCase usecase.IncentiveUseCase
+}
+
+func NewIncentiveHandler(incentiveUseCase usecase.IncentiveUseCase) *IncentiveHandler {
+	return &IncentiveHandler{
+		incentiveUseCase: incentiveUseCase,
+	}
+}
+
+// IncentiveRuleRequest describes a rule; rules are enabled unless enabled is false.
+type IncentiveRuleRequest struct {
+	Name       string                     `json:"name"`
+	Effect     domain.IncentiveEffect     `json:"effect"`
+	Points     int                        `json:"points"`
+	Conditions domain.IncentiveConditions `json:"conditions"`
+	Enabled    *bool                      `json:"enabled"`
+	ValidFrom  *time.Time                 `json:"valid_from"`
+	ValidUntil *time.Time                 `json:"valid_until"`
+}
+
+func (r IncentiveRuleRequest) rule(id string) *domain.IncentiveRule {
+	rule := &domain.IncentiveRule{
+		ID:         id,
+		Name:       r.Name,
+		Effect:     r.Effect,
+		Points:     r.Points,
+		Conditions: r.Conditions,
+		Enabled:    true,
+		ValidFrom:  r.ValidFrom,
+		ValidUntil: r.ValidUntil,
+	}
+	if r.Enabled != nil {
+		rule.Enabled = *r.Enabled
+	}
+	return rule
+}
+
+type IncentiveRuleResponse struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Effect     domain.IncentiveEffect     `json:"effect"`
+	Points     int                        `json:"points,omitempty"`
+	Conditions domain.IncentiveConditions `json:"conditions"`
+	Enabled    bool                       `json:"enabled"`
+	ValidFrom  *time.Time                 `json:"valid_from,omitempty"`
+	ValidUntil *time.Time                 `json:"valid_until,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
+}
+
+func newIncentiveRuleResponse(rule *domain.IncentiveRule) IncentiveRuleResponse {
+	return IncentiveRuleResponse{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Effect:     rule.Effect,
+		Points:     rule.Points,
+		Conditions: rule.Conditions,
+		Enabled:    rule.Enabled,
+		ValidFrom:  rule.ValidFrom,
+		ValidUntil: rule.ValidUntil,
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}
+}
+
+type IncentiveEvaluationResponse struct {
+	ID           string                       `json:"id"`
+	BookingID    string                       `json:"booking_id"`
+	WaiveDeposit bool                         `json:"waive_deposit"`
+	BonusPoints  int                          `json:"bonus_points"`
+	Facts        domain.IncentiveFacts        `json:"facts"`
+	Rules        []domain.IncentiveRuleResult `json:"rules"`
+	EvaluatedAt  time.Time                    `json:"evaluated_at"`
+}
+
+func newIncentiveEvaluationResponse(evaluation *domain.IncentiveEvaluation) IncentiveEvaluationResponse {
+	return IncentiveEvaluationResponse{
+		ID:           evaluation.ID,
+		BookingID:    evaluation.BookingID,
+		WaiveDeposit: evaluation.WaiveDeposit,
+		BonusPoints:  evaluation.BonusPoints,
+		Facts:        evaluation.Facts,
+		Rules:        evaluation.Results,
+		EvaluatedAt:  evaluation.EvaluatedAt,
+	}
+}
+
+// ListIncentiveRules godoc
+// @Summary List incentive rules
+// @Description Every incentive rule, disabled ones included, oldest first. Admins only
+// @Tags admin
+// @Produce json
+// @Success 200 {array} IncentiveRuleResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/incentives/rules [get]
+func (h *IncentiveHandler) ListIncentiveRules(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	rules, err := h.incentiveUseCase.ListRules(ctx)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListIncentiveRules, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(rules, newIncentiveRuleResponse))
+}
+
+// CreateIncentiveRule godoc
+// @Summary Create an incentive rule
+// @Description Add a rule granting an incentive to the bookings meeting its conditions: waive_deposit waives the deposit, bonus_points credits its points. Conditions left out match every booking. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param rule body IncentiveRuleRequest true "Rule"
+// @Success 201 {object} IncentiveRuleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/incentives/rules [post]
+func (h *IncentiveHandler) CreateIncentiveRule(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request IncentiveRuleRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	rule := request.rule("")
+	if err := h.incentiveUseCase.CreateRule(ctx, rule); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidIncentiveRule):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateIncentiveRule, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newIncentiveRuleResponse(rule))
+}
+
+// UpdateIncentiveRule godoc
+// @Summary Update an incentive rule
+// @Description Replace an incentive rule. Bookings evaluated before keep their incentives. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body IncentiveRuleRequest true "Rule"
+// @Success 200 {object} IncentiveRuleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/incentives/rules/{id} [put]
+func (h *IncentiveHandler) UpdateIncentiveRule(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request IncentiveRuleRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	rule := request.rule(c.Params("id"))
+	if err := h.incentiveUseCase.UpdateRule(ctx, rule); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidIncentiveRule):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrIncentiveRuleNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrIncentiveRuleNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateIncentiveRule, zap.String("id", rule.ID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newIncentiveRuleResponse(rule))
+}
+
+// DeleteIncentiveRule godoc
+// @Summary Delete an incentive rule
+// @Description Remove an incentive rule; disabling it keeps it for later instead. Admins only
+// @Tags admin
+// @Param id path string true "Rule ID"
+// @Success 204
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Rule not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/incentives/rules/{id} [delete]
+func (h *IncentiveHandler) DeleteIncentiveRule(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if err := h.incentiveUseCase.DeleteRule(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrIncentiveRuleNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrIncentiveRuleNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteIncentiveRule, zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListIncentiveEvaluations godoc
+// @Summary List incentive evaluations
+// @Description The audit trail of the incentive rules evaluated for bookings, with the facts they were evaluated against and the conditions each rule failed, newest first. Admins only
+// @Tags admin
+// @Produce json
+// @Param user_id query string false "User ID"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} IncentiveEvaluationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/incentives/evaluations [get]
+func (h *IncentiveHandler) ListIncentiveEvaluations(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	userID := c.Query("user_id")
+	evaluations, err := h.incentiveUseCase.ListEvaluations(ctx, userID, offset, lookaheadLimit(limit))
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListIncentiveEvaluations, zap.String("userID", userID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	evaluations, hasNext := trimPage(evaluations, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(evaluations),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(evaluations, newIncentiveEvaluationResponse))
+}
+
+// GetBookingIncentives godoc
+// @Summary Get booking incentives
+// @Description The incentives granted to a booking when it was made, with the rules evaluated for it. Bookings made before any rule existed, and test bookings, have none. For the guest, the staff of the restaurant and admins
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} IncentiveEvaluationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found or not evaluated"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/incentives [get]
+func (h *IncentiveHandler) GetBookingIncentives(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	evaluation, err := h.incentiveUseCase.GetBookingIncentives(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case strings.HasPrefix(err.Error(), common.ErrBookingNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		case err.Error() == common.ErrIncentiveEvaluationNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrIncentiveEvaluationNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetIncentiveEvaluation, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newIncentiveEvaluationResponse(evaluation))
+}
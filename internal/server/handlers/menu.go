@@ -0,0 +1,443 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type MenuHandler struct {
+	menuUseCase usecase.MenuUseCase
+}
+
+func NewMenuHandler(menuUseCase usecase.MenuUseCase) *MenuHandler {
+	return &MenuHandler{
+		menuUseCase: menuUseCase,
+	}
+}
+
+type UpdateMenuRequest struct {
+	Items []domain.MenuItem `json:"items"`
+}
+
+type UpdatePreOrderRequest struct {
+	Items []domain.PreOrderItem `json:"items"`
+}
+
+// MenuItemResponse carries the price in minor units of its currency and, for display, formatted
+// with the decimal places and code of the currency.
+type MenuItemResponse struct {
+	ID             string `json:"id"`
+	RestaurantID   string `json:"restaurant_id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Category       string `json:"category"`
+	Price          int64  `json:"price"`
+	Currency       string `json:"currency"`
+	PriceFormatted string `json:"price_formatted"`
+	IsAvailable    bool   `json:"is_available"`
+	// DailyLimit is how many of the item the bookings of a day may pre-order; zero is no limit.
+	DailyLimit int `json:"daily_limit"`
+	// Allergens and DietaryLabels are never null.
+	Allergens     []domain.Allergen     `json:"allergens"`
+	DietaryLabels []domain.DietaryLabel `json:"dietary_labels"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+// PreOrderResponse has no currency and formatted total when nothing is ordered.
+type PreOrderResponse struct {
+	BookingID               string                 `json:"booking_id"`
+	Items                   []PreOrderItemResponse `json:"items"`
+	EstimatedTotal          int64                  `json:"estimated_total"`
+	Currency                string                 `json:"currency,omitempty"`
+	EstimatedTotalFormatted string                 `json:"estimated_total_formatted,omitempty"`
+	EditableUntil           time.Time              `json:"editable_until"`
+	Editable                bool                   `json:"editable"`
+}
+
+type PreOrderItemResponse struct {
+	MenuItemID         string `json:"menu_item_id"`
+	Name               string `json:"name"`
+	UnitPrice          int64  `json:"unit_price"`
+	Currency           string `json:"currency"`
+	UnitPriceFormatted string `json:"unit_price_formatted"`
+	Quantity           int    `json:"quantity"`
+	Notes              string `json:"notes,omitempty"`
+}
+
+func newMenuItemResponse(item domain.MenuItem) MenuItemResponse {
+	response := MenuItemResponse{
+		ID:             item.ID,
+		RestaurantID:   item.RestaurantID,
+		Name:           item.Name,
+		Description:    item.Description,
+		Category:       item.Category,
+		Price:          item.Price,
+		Currency:       item.Currency,
+		PriceFormatted: domain.FormatAmount(item.Price, item.Currency),
+		IsAvailable:    item.IsAvailable,
+		DailyLimit:     item.DailyLimit,
+		Allergens:      item.Allergens,
+		DietaryLabels:  item.DietaryLabels,
+		CreatedAt:      item.CreatedAt,
+		UpdatedAt:      item.UpdatedAt,
+	}
+	if response.Allergens == nil {
+		response.Allergens = make([]domain.Allergen, 0)
+	}
+	if response.DietaryLabels == nil {
+		response.DietaryLabels = make([]domain.DietaryLabel, 0)
+	}
+	return response
+}
+
+// queryList splits a comma-separated query parameter, leaving out empty values.
+func queryList[T ~string](c fiber.Ctx, key string) []T {
+	var values []T
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, T(value))
+		}
+	}
+	return values
+}
+
+func newPreOrderResponse(preOrder *domain.PreOrder) PreOrderResponse {
+	response := PreOrderResponse{
+		BookingID: preOrder.BookingID,
+		Items: mapResponses(preOrder.Items, func(item domain.PreOrderItem) PreOrderItemResponse {
+			return PreOrderItemResponse{
+				MenuItemID:         item.MenuItemID,
+				Name:               item.Name,
+				UnitPrice:          item.UnitPrice,
+				Currency:           item.Currency,
+				UnitPriceFormatted: domain.FormatAmount(item.UnitPrice, item.Currency),
+				Quantity:           item.Quantity,
+				Notes:              item.Notes,
+			}
+		}),
+		EstimatedTotal: preOrder.EstimatedTotal,
+		Currency:       preOrder.Currency,
+		EditableUntil:  preOrder.EditableUntil,
+		Editable:       preOrder.Editable,
+	}
+	if preOrder.Currency != "" {
+		response.EstimatedTotalFormatted = domain.FormatAmount(preOrder.EstimatedTotal, preOrder.Currency)
+	}
+	return response
+}
+
+// GetMenu godoc
+// @Summary Get restaurant menu
+// @Description Get the menu items of a restaurant in menu order; prices are in minor units of the restaurant currency, with a formatted copy for display. The items can be narrowed to those free of some allergens and suiting some diets; vegan items suit vegetarians
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param exclude_allergens query string false "Comma-separated allergens the items must not contain, e.g. milk,tree_nuts"
+// @Param dietary_labels query string false "Comma-separated diets the items must suit, e.g. vegan,gluten_free"
+// @Success 200 {array} MenuItemResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/menu [get]
+func (h *MenuHandler) GetMenu(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	filter := domain.MenuFilter{
+		ExcludeAllergens: queryList[domain.Allergen](c, "exclude_allergens"),
+		DietaryLabels:    queryList[domain.DietaryLabel](c, "dietary_labels"),
+	}
+
+	items, err := h.menuUseCase.GetMenu(ctx, id, filter)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidMenuFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetMenu, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(items, newMenuItemResponse))
+}
+
+// UpdateMenu godoc
+// @Summary Update restaurant menu
+// @Description Replace the menu of a restaurant. Items keep their ID when it is given, items left out are removed. Allergens and dietary labels come from fixed vocabularies, and a label cannot contradict the allergens, such as a vegan item containing milk. A daily limit caps the units of an item the bookings of a day may pre-order
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param menu body UpdateMenuRequest true "Menu items"
+// @Success 200 {array} MenuItemResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/menu [put]
+func (h *MenuHandler) UpdateMenu(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request UpdateMenuRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	items, err := h.menuUseCase.UpdateMenu(ctx, id, request.Items)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidMenuItem) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateMenu, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(items, newMenuItemResponse))
+}
+
+// ImportMenu godoc
+// @Summary Import restaurant menu
+// @Description Add menu items from a CSV or JSON file, sent as the "file" form field or as the body. CSV needs name and price columns, prices in major units such as 450.00, and may have description, category, available, allergens and dietary_labels; the column names of common point-of-sale exports are understood too. JSON is a list of items shaped like the menu API, prices in minor units. Items named like ones on the menu update them. Nothing is saved when any item is invalid
+// @Tags restaurants
+// @Accept text/csv,json,mpfd
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param file formData file false "CSV or JSON file"
+// @Param format query string false "csv or json, taken from the content type or the file name by default"
+// @Param replace query bool false "Remove the items not in the file"
+// @Param dry_run query bool false "Validate and report without saving"
+// @Success 200 {object} usecase.MenuImportReport
+// @Failure 400 {object} map[string]string "Unreadable file"
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 422 {object} usecase.MenuImportReport "Per-item validation errors"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/menu/import [post]
+func (h *MenuHandler) ImportMenu(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	options := usecase.MenuImportOptions{Format: usecase.MenuImportCSV}
+	if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) {
+		options.Format = usecase.MenuImportJSON
+	}
+	if options.DryRun, err = dryRunRequested(c); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+	if options.Replace, err = strconv.ParseBool(c.Query("replace", "false")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var body io.Reader = bytes.NewReader(c.Body())
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Error(ctx, common.ErrReadImportFile, zap.Error(err))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrReadImportFile,
+			})
+		}
+		defer file.Close()
+		body = file
+
+		if strings.EqualFold(filepath.Ext(fileHeader.Filename), ".json") {
+			options.Format = usecase.MenuImportJSON
+		} else {
+			options.Format = usecase.MenuImportCSV
+		}
+	}
+	if format := c.Query("format"); format != "" {
+		options.Format = usecase.MenuImportFormat(strings.ToLower(format))
+	}
+
+	report, err := h.menuUseCase.ImportMenu(ctx, id, body, options)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidImportFile), errors.Is(err, usecase.ErrImportTooLarge):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrImportMenu, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if report.HasErrors() {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(report)
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// UpdatePreOrder godoc
+// @Summary Update booking pre-order
+// @Description Replace the menu items pre-ordered for a booking, with quantities and notes; an empty list clears the pre-order. The pre-order can be changed until the cutoff before the booking. Items with a daily limit are reserved for the date of the booking and released when it is cancelled or rejected
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param preOrder body UpdatePreOrderRequest true "Pre-ordered items"
+// @Success 200 {object} PreOrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found"
+// @Failure 409 {object} map[string]string "Pre-order can no longer be changed, or an item has too few units left for the day"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/pre-order [put]
+func (h *MenuHandler) UpdatePreOrder(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var request UpdatePreOrderRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	preOrder, err := h.menuUseCase.UpdatePreOrder(ctx, id, request.Items)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPreOrder) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, usecase.ErrPreOrderClosed) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		var limitErr *domain.PreOrderLimitError
+		if errors.As(err, &limitErr) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": limitErr.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		// the repository wraps the not found error with the message as prefix
+		if strings.HasPrefix(err.Error(), common.ErrBookingNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdatePreOrder, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newPreOrderResponse(preOrder))
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type NotificationHandler struct {
+	notificationUseCase usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUseCase usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+// ResendNotification godoc
+// @Summary Resend notification
+// @Description Send the email of an existing notification once more
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 500 {object} map[string]string
+// @Router /notifications/{id}/resend [post]
+func (h *NotificationHandler) ResendNotification(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.notificationUseCase.ResendNotification(ctx, id); err != nil {
+		log.Error(ctx, common.ErrResendNotification, zap.String("notificationID", id), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		// the repository wraps the not found error with the message as prefix
+		if strings.HasPrefix(err.Error(), common.ErrNotificationNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrNotificationNotFound,
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status": common.MsgSuccess,
+	})
+}
+
+type UpdateNotificationSettingsRequest struct {
+	Preferences []domain.NotificationPreference `json:"preferences"`
+	QuietHours  []domain.QuietHours             `json:"quiet_hours"`
+}
+
+type NotificationResponse struct {
+	ID            string                     `json:"id"`
+	RecipientType domain.RecipientType       `json:"recipient_type"`
+	RecipientID   string                     `json:"recipient_id"`
+	Type          domain.NotificationType    `json:"type"`
+	Channel       domain.NotificationChannel `json:"channel"`
+	Title         string                     `json:"title"`
+	Message       string                     `json:"message"`
+	IsRead        bool                       `json:"is_read"`
+	RelatedID     string                     `json:"related_id"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	DeliveredAt   *time.Time                 `json:"delivered_at,omitempty"`
+	ReadAt        *time.Time                 `json:"read_at,omitempty"`
+}
+
+type NotificationSettingsResponse struct {
+	RecipientType domain.RecipientType             `json:"recipient_type"`
+	RecipientID   string                           `json:"recipient_id"`
+	Preferences   []NotificationPreferenceResponse `json:"preferences"`
+	QuietHours    []domain.QuietHours              `json:"quiet_hours"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    domain.NotificationType    `json:"type"`
+	Channel domain.NotificationChannel `json:"channel"`
+	Enabled bool                       `json:"enabled"`
+}
+
+func newNotificationResponse(notification domain.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:            notification.ID,
+		RecipientType: notification.RecipientType,
+		RecipientID:   notification.RecipientID,
+		Type:          notification.Type,
+		Channel:       notification.Channel,
+		Title:         notification.Title,
+		Message:       notification.Message,
+		IsRead:        notification.IsRead,
+		RelatedID:     notification.RelatedID,
+		CreatedAt:     notification.CreatedAt,
+		DeliveredAt:   notification.DeliveredAt,
+		ReadAt:        notification.ReadAt,
+	}
+}
+
+func newNotificationSettingsResponse(settings *domain.NotificationSettings) NotificationSettingsResponse {
+	return NotificationSettingsResponse{
+		RecipientType: settings.RecipientType,
+		RecipientID:   settings.RecipientID,
+		Preferences: mapResponses(settings.Preferences, func(p domain.NotificationPreference) NotificationPreferenceResponse {
+			return NotificationPreferenceResponse{Type: p.Type, Channel: p.Channel, Enabled: p.Enabled}
+		}),
+		QuietHours: settings.QuietHours,
+	}
+}
+
+// GetUserNotificationSettings godoc
+// @Summary Get user notification settings
+// @Description Get the channel preferences of a user for every notification type and its quiet hours
+// @Tags users,notifications
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/notification-settings [get]
+func (h *NotificationHandler) GetUserNotificationSettings(c fiber.Ctx) error {
+	return h.getNotificationSettings(c, domain.RecipientTypeUser)
+}
+
+// UpdateUserNotificationSettings godoc
+// @Summary Update user notification settings
+// @Description Replace the channel preferences and quiet hours of a user; omitted combinations fall back to the defaults. Digests, reports and marketing wait for the quiet hours of their channel to end, booking changes do not
+// @Tags users,notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param settings body UpdateNotificationSettingsRequest true "Preferences and quiet hours"
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/notification-settings [put]
+func (h *NotificationHandler) UpdateUserNotificationSettings(c fiber.Ctx) error {
+	return h.updateNotificationSettings(c, domain.RecipientTypeUser)
+}
+
+// GetRestaurantNotificationSettings godoc
+// @Summary Get restaurant notification settings
+// @Description Get the channel preferences of a restaurant for every notification type and its quiet hours
+// @Tags restaurants,notifications
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/notification-settings [get]
+func (h *NotificationHandler) GetRestaurantNotificationSettings(c fiber.Ctx) error {
+	return h.getNotificationSettings(c, domain.RecipientTypeRestaurant)
+}
+
+// UpdateRestaurantNotificationSettings godoc
+// @Summary Update restaurant notification settings
+// @Description Replace the channel preferences and quiet hours of a restaurant; omitted combinations fall back to the defaults. Digests, reports and marketing wait for the quiet hours of their channel to end, booking changes do not
+// @Tags restaurants,notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param settings body UpdateNotificationSettingsRequest true "Preferences and quiet hours"
+// @Success 200 {object} NotificationSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/notification-settings [put]
+func (h *NotificationHandler) UpdateRestaurantNotificationSettings(c fiber.Ctx) error {
+	return h.updateNotificationSettings(c, domain.RecipientTypeRestaurant)
+}
+
+func (h *NotificationHandler) getNotificationSettings(c fiber.Ctx, recipientType domain.RecipientType) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	settings, err := h.notificationUseCase.GetNotificationSettings(ctx, recipientType, id)
+	if err != nil {
+		log.Error(ctx, common.ErrGetNotificationSettings,
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", id),
+			zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newNotificationSettingsResponse(settings))
+}
+
+func (h *NotificationHandler) updateNotificationSettings(c fiber.Ctx, recipientType domain.RecipientType) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request UpdateNotificationSettingsRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	settings, err := h.notificationUseCase.UpdateNotificationSettings(ctx, &domain.NotificationSettings{
+		RecipientType: recipientType,
+		RecipientID:   id,
+		Preferences:   request.Preferences,
+		QuietHours:    request.QuietHours,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidNotificationPreference) || errors.Is(err, usecase.ErrInvalidQuietHours) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrSaveNotificationSettings,
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", id),
+			zap.Error(err))
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newNotificationSettingsResponse(settings))
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type NotificationFailureHandler struct {
+	notificationRetryUseCase usecase.NotificationRetryUseCase
+}
+
+func NewNotificationFailureHandler(notificationRetryUseCase usecase.NotificationRetryUseCase) *NotificationFailureHandler {
+	return &NotificationFailureHandler{
+		notificationRetryUseCase: notificationRetryUseCase,
+	}
+}
+
+type NotificationFailureResponse struct {
+	ID            string                           `json:"id"`
+	RecipientType domain.RecipientType             `json:"recipient_type"`
+	RecipientID   string                           `json:"recipient_id"`
+	Type          domain.NotificationType          `json:"type"`
+	Title         string                           `json:"title"`
+	Message       string                           `json:"message"`
+	RelatedID     string                           `json:"related_id,omitempty"`
+	Cause         string                           `json:"cause"`
+	Attempts      int                              `json:"attempts"`
+	Status        domain.NotificationFailureStatus `json:"status"`
+	NextAttemptAt *time.Time                       `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time                        `json:"created_at"`
+	UpdatedAt     time.Time                        `json:"updated_at"`
+}
+
+func newNotificationFailureResponse(failure *domain.NotificationFailure) NotificationFailureResponse {
+	return NotificationFailureResponse{
+		ID:            failure.ID,
+		RecipientType: failure.RecipientType,
+		RecipientID:   failure.RecipientID,
+		Type:          failure.NotificationType,
+		Title:         failure.Title,
+		Message:       failure.Message,
+		RelatedID:     failure.RelatedID,
+		Cause:         failure.Cause,
+		Attempts:      failure.Attempts,
+		Status:        failure.Status,
+		NextAttemptAt: failure.NextAttemptAt,
+		CreatedAt:     failure.CreatedAt,
+		UpdatedAt:     failure.UpdatedAt,
+	}
+}
+
+// ListNotificationFailures godoc
+// @Summary List notification failures
+// @Description The notifications that could not be delivered, newest first: pending ones wait for a retry, delivered ones succeeded on a retry and exhausted ones were given up after the last attempt. Admins only
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, delivered or exhausted; all by default"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} NotificationFailureResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/notification-failures [get]
+func (h *NotificationFailureHandler) ListNotificationFailures(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	status := domain.NotificationFailureStatus(c.Query("status"))
+	failures, err := h.notificationRetryUseCase.ListFailures(ctx, status, offset, lookaheadLimit(limit))
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case errors.Is(err, usecase.ErrInvalidNotificationFailureStatus):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrListNotificationFailures, zap.String("status", string(status)), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	failures, hasNext := trimPage(failures, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(failures),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(failures, newNotificationFailureResponse))
+}
+
+// CountNotificationFailures godoc
+// @Summary Count notification failures
+// @Description The number of notification failures of every status. Admins only
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]int
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/notification-failures/counts [get]
+func (h *NotificationFailureHandler) CountNotificationFailures(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	counts, err := h.notificationRetryUseCase.CountFailures(ctx)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrCountNotificationFailures, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(counts)
+}
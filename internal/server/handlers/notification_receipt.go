@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// trackingPixel is a transparent 1x1 GIF.
+var trackingPixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+type NotificationReceiptHandler struct {
+	receiptUseCase usecase.NotificationReceiptUseCase
+}
+
+func NewNotificationReceiptHandler(receiptUseCase usecase.NotificationReceiptUseCase) *NotificationReceiptHandler {
+	return &NotificationReceiptHandler{
+		receiptUseCase: receiptUseCase,
+	}
+}
+
+type NotificationDeliveryResponse struct {
+	NotificationID string                     `json:"notification_id"`
+	RecipientType  domain.RecipientType       `json:"recipient_type"`
+	Type           domain.NotificationType    `json:"type"`
+	Channel        domain.NotificationChannel `json:"channel"`
+	Status         domain.DeliveryStatus      `json:"status"`
+	CreatedAt      time.Time                  `json:"created_at"`
+	DeliveredAt    *time.Time                 `json:"delivered_at,omitempty"`
+	ReadAt         *time.Time                 `json:"read_at,omitempty"`
+}
+
+func newNotificationDeliveryResponse(delivery domain.NotificationDelivery) NotificationDeliveryResponse {
+	return NotificationDeliveryResponse{
+		NotificationID: delivery.NotificationID,
+		RecipientType:  delivery.RecipientType,
+		Type:           delivery.Type,
+		Channel:        delivery.Channel,
+		Status:         delivery.Status,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		ReadAt:         delivery.ReadAt,
+	}
+}
+
+// TrackNotificationRead godoc
+// @Summary Notification tracking pixel
+// @Description Mark a notification read when the image embedded in it is loaded. The image is returned for unknown notifications too
+// @Tags notifications
+// @Produce image/gif
+// @Param id path string true "Notification ID"
+// @Success 200 {file} binary
+// @Router /notifications/{id}/pixel.gif [get]
+func (h *NotificationReceiptHandler) TrackNotificationRead(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err == nil {
+		id := c.Params("id")
+		if err := h.receiptUseCase.TrackRead(ctx, id); err != nil && !strings.HasPrefix(err.Error(), common.ErrNotificationNotFound) {
+			log.Warn(ctx, common.ErrMarkNotificationAsRead, zap.String("notificationID", id), zap.Error(err))
+		}
+	}
+
+	// Every load has to reach the server to count as a read.
+	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate")
+	c.Set(fiber.HeaderContentType, "image/gif")
+	return c.Status(fiber.StatusOK).Send(trackingPixel)
+}
+
+// ConfirmNotificationRead godoc
+// @Summary Confirm notification read
+// @Description Read callback of the recipient; the time of the first read is kept
+// @Tags notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 500 {object} map[string]string
+// @Router /notifications/{id}/read [post]
+func (h *NotificationReceiptHandler) ConfirmNotificationRead(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	notification, err := h.receiptUseCase.ConfirmRead(ctx, id)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		// the repository wraps the not found error with the message as prefix
+		if strings.HasPrefix(err.Error(), common.ErrNotificationNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrNotificationNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrConfirmNotificationRead, zap.String("notificationID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newNotificationResponse(*notification))
+}
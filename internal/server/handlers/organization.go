@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type OrganizationHandler struct {
+	organizationUseCase usecase.OrganizationUseCase
+}
+
+func NewOrganizationHandler(organizationUseCase usecase.OrganizationUseCase) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationUseCase: organizationUseCase,
+	}
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+type OrganizationResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newOrganizationResponse(organization *domain.Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+// CreateOrganization godoc
+// @Summary Create an organization
+// @Description Create an organization grouping the sister restaurants of one owner; bookings can be transferred between its restaurants. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param organization body CreateOrganizationRequest true "Organization name"
+// @Success 201 {object} OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var request CreateOrganizationRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	organization, err := h.organizationUseCase.CreateOrganization(ctx, request.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidOrganization):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateOrganization, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newOrganizationResponse(organization))
+}
+
+// AddOrganizationRestaurant godoc
+// @Summary Add a restaurant to an organization
+// @Description Move the restaurant into the organization, out of the one it was in. Admins only
+// @Tags admin
+// @Produce json
+// @Param id path string true "Organization ID"
+// @Param restaurantId path string true "Restaurant ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Organization or restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/organizations/{id}/restaurants/{restaurantId} [put]
+func (h *OrganizationHandler) AddOrganizationRestaurant(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	restaurantID := c.Params("restaurantId")
+	if id == "" || restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.organizationUseCase.AddRestaurant(ctx, id, restaurantID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrOrganizationNotFound, err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrAddOrganizationRestaurant,
+			zap.String("organizationID", id),
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSisterRestaurants godoc
+// @Summary List sister restaurants
+// @Description List the other restaurants of the organization the restaurant belongs to, the targets its bookings can be transferred to. Restaurant staff only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {array} RestaurantResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/sister-restaurants [get]
+func (h *OrganizationHandler) ListSisterRestaurants(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	restaurants, err := h.organizationUseCase.ListSisterRestaurants(ctx, id)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListSisterRestaurants, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(mapResponses(restaurants, newRestaurantResponse))
+}
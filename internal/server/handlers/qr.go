@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/qr"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	qrFormatPNG = "png"
+	qrFormatSVG = "svg"
+
+	defaultQRScale = 8
+	maxQRScale     = 32
+
+	// The check-in token of a booking never changes, but only its owner and the restaurant may see it.
+	bookingQRCacheControl = "private, max-age=86400"
+	// The link of a restaurant changes with its slug, so shared caches keep it for an hour.
+	restaurantQRCacheControl = "public, max-age=3600"
+)
+
+type QRHandler struct {
+	restaurantUseCase  usecase.RestaurantUseCase
+	bookingLinkUseCase usecase.BookingLinkUseCase
+	publicURL          string
+}
+
+func NewQRHandler(restaurantUseCase usecase.RestaurantUseCase, bookingLinkUseCase usecase.BookingLinkUseCase, publicURL string) *QRHandler {
+	return &QRHandler{
+		restaurantUseCase:  restaurantUseCase,
+		bookingLinkUseCase: bookingLinkUseCase,
+		publicURL:          strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// GetBookingQR godoc
+// @Summary Booking QR code
+// @Description QR code of the check-in token of a booking, to be shown at the restaurant
+// @Tags bookings
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "Booking ID"
+// @Param format query string false "png (default) or svg"
+// @Param scale query int false "Pixels per module, 1 to 32 (default 8)"
+// @Success 200 {file} binary
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/qr [get]
+func (h *QRHandler) GetBookingQR(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	format, scale, ok := qrOptions(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	token, err := h.bookingLinkUseCase.CheckInToken(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrBookingNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetBookingByID, zap.String("id", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if err := sendQR(c, token, format, scale, bookingQRCacheControl); err != nil {
+		log.Error(ctx, common.ErrGenerateQRCode, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	return nil
+}
+
+// GetRestaurantQR godoc
+// @Summary Restaurant QR code
+// @Description QR code of the link to the booking page of a restaurant, e.g. for table tents and flyers
+// @Tags restaurants
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "Restaurant ID"
+// @Param format query string false "png (default) or svg"
+// @Param scale query int false "Pixels per module, 1 to 32 (default 8)"
+// @Success 200 {file} binary
+// @Success 304 "Not modified"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/qr [get]
+func (h *QRHandler) GetRestaurantQR(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	format, scale, ok := qrOptions(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	if restaurant == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantNotFound,
+		})
+	}
+
+	path := restaurant.Slug
+	if path == "" {
+		path = restaurant.ID
+	}
+
+	if err := sendQR(c, h.publicURL+"/restaurants/"+path, format, scale, restaurantQRCacheControl); err != nil {
+		log.Error(ctx, common.ErrGenerateQRCode, zap.String("restaurantID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	return nil
+}
+
+func qrOptions(c fiber.Ctx) (string, int, bool) {
+	format := c.Query("format", qrFormatPNG)
+	if format != qrFormatPNG && format != qrFormatSVG {
+		return "", 0, false
+	}
+
+	scale := defaultQRScale
+	if value := c.Query("scale"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxQRScale {
+			return "", 0, false
+		}
+		scale = parsed
+	}
+
+	return format, scale, true
+}
+
+// sendQR renders content as a QR code. The ETag is derived from what is drawn, so a client
+// holding the current image gets 304 without the code being rendered again.
+func sendQR(c fiber.Ctx, content, format string, scale int, cacheControl string) error {
+	sum := sha256.Sum256([]byte(format + "\x00" + strconv.Itoa(scale) + "\x00" + content))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Set(fiber.HeaderCacheControl, cacheControl)
+	c.Set(fiber.HeaderETag, etag)
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	code, err := qr.Encode(content)
+	if err != nil {
+		return err
+	}
+
+	if format == qrFormatSVG {
+		c.Set(fiber.HeaderContentType, "image/svg+xml")
+		return c.Status(fiber.StatusOK).Send(code.SVG(scale))
+	}
+
+	image, err := code.PNG(scale)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Status(fiber.StatusOK).Send(image)
+}
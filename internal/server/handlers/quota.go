@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type QuotaHandler struct {
+	quotaUseCase usecase.QuotaUseCase
+}
+
+func NewQuotaHandler(quotaUseCase usecase.QuotaUseCase) *QuotaHandler {
+	return &QuotaHandler{
+		quotaUseCase: quotaUseCase,
+	}
+}
+
+// QuotaExceededResponse is returned when a restaurant has used up a quota of its plan.
+type QuotaExceededResponse struct {
+	Error    string               `json:"error"`
+	Resource domain.QuotaResource `json:"resource"`
+	Plan     domain.Plan          `json:"plan"`
+	Limit    int                  `json:"limit"`
+}
+
+func newQuotaExceededResponse(exceeded *usecase.QuotaExceededError) QuotaExceededResponse {
+	return QuotaExceededResponse{
+		Error:    common.ErrQuotaExceeded,
+		Resource: exceeded.Resource,
+		Plan:     exceeded.Plan,
+		Limit:    exceeded.Limit,
+	}
+}
+
+// QuotaLimitsResponse lists limits or usage by resource; zero limits are unlimited.
+type QuotaLimitsResponse struct {
+	ActiveSlots     int `json:"active_slots"`
+	MonthlyBookings int `json:"monthly_bookings"`
+}
+
+type QuotaResponse struct {
+	RestaurantID string              `json:"restaurant_id"`
+	Plan         domain.Plan         `json:"plan"`
+	Limits       QuotaLimitsResponse `json:"limits"`
+	Usage        QuotaLimitsResponse `json:"usage"`
+	// Overridden lists the resources whose limit an admin set in place of the plan's.
+	Overridden []domain.QuotaResource `json:"overridden"`
+	Note       string                 `json:"note,omitempty"`
+	UpdatedAt  *time.Time             `json:"updated_at,omitempty"`
+}
+
+func newQuotaResponse(quota *domain.Quota) QuotaResponse {
+	response := QuotaResponse{
+		RestaurantID: quota.Plan.RestaurantID,
+		Plan:         quota.Plan.Plan,
+		Limits: QuotaLimitsResponse{
+			ActiveSlots:     quota.Limits.ActiveSlots,
+			MonthlyBookings: quota.Limits.MonthlyBookings,
+		},
+		Usage: QuotaLimitsResponse{
+			ActiveSlots:     quota.Usage.ActiveSlots,
+			MonthlyBookings: quota.Usage.MonthlyBookings,
+		},
+		Overridden: make([]domain.QuotaResource, 0),
+		Note:       quota.Plan.Note,
+	}
+	if quota.Plan.ActiveSlots != nil {
+		response.Overridden = append(response.Overridden, domain.QuotaActiveSlots)
+	}
+	if quota.Plan.MonthlyBookings != nil {
+		response.Overridden = append(response.Overridden, domain.QuotaMonthlyBookings)
+	}
+	if !quota.Plan.UpdatedAt.IsZero() {
+		response.UpdatedAt = &quota.Plan.UpdatedAt
+	}
+	return response
+}
+
+// SetPlanRequest moves a restaurant to a plan. Limits left out follow the plan; 0 is unlimited.
+type SetPlanRequest struct {
+	Plan               domain.Plan `json:"plan"`
+	MaxActiveSlots     *int        `json:"max_active_slots"`
+	MaxMonthlyBookings *int        `json:"max_monthly_bookings"`
+	Note               string      `json:"note"`
+}
+
+// GetQuota godoc
+// @Summary Get restaurant quota
+// @Description The plan of the restaurant with its limits and how much of them it uses: the availability slots of today and later and the bookings requested this month. Zero limits are unlimited. Restaurant staff only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} QuotaResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/quota [get]
+func (h *QuotaHandler) GetQuota(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	quota, err := h.quotaUseCase.GetQuota(ctx, id, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurantPlan, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newQuotaResponse(quota))
+}
+
+// SetPlan godoc
+// @Summary Set restaurant plan
+// @Description Move a restaurant to the free or pro plan and optionally override its limits; limits left out follow the plan and 0 is unlimited. What the restaurant already has beyond a lowered limit is kept. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param plan body SetPlanRequest true "Plan and limit overrides"
+// @Success 200 {object} QuotaResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurants/{id}/plan [put]
+func (h *QuotaHandler) SetPlan(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request SetPlanRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	err = h.quotaUseCase.SetPlan(ctx, &domain.RestaurantPlan{
+		RestaurantID:    id,
+		Plan:            request.Plan,
+		ActiveSlots:     request.MaxActiveSlots,
+		MonthlyBookings: request.MaxMonthlyBookings,
+		Note:            request.Note,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidPlan), errors.Is(err, usecase.ErrInvalidQuotaLimit):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrSetRestaurantPlan, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	quota, err := h.quotaUseCase.GetQuota(ctx, id, time.Now())
+	if err != nil {
+		log.Error(ctx, common.ErrGetRestaurantPlan, zap.String("restaurantID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newQuotaResponse(quota))
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type RequestReplayHandler struct {
+	requestReplayUseCase usecase.RequestReplayUseCase
+}
+
+func NewRequestReplayHandler(requestReplayUseCase usecase.RequestReplayUseCase) *RequestReplayHandler {
+	return &RequestReplayHandler{
+		requestReplayUseCase: requestReplayUseCase,
+	}
+}
+
+type RecordedRequestResponse struct {
+	ID       string `json:"id"`
+	APIKeyID string `json:"api_key_id"`
+	Method   string `json:"method"`
+	// Path includes the query string.
+	Path        string            `json:"path"`
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body,omitempty"`
+	BodyOmitted bool              `json:"body_omitted,omitempty"`
+	Status      int               `json:"status"`
+	DurationMS  int64             `json:"duration_ms"`
+	RecordedAt  time.Time         `json:"recorded_at"`
+}
+
+func newRecordedRequestResponse(request *domain.RecordedRequest) RecordedRequestResponse {
+	return RecordedRequestResponse{
+		ID:          request.ID,
+		APIKeyID:    request.APIKeyID,
+		Method:      request.Method,
+		Path:        request.Path,
+		Headers:     request.Headers,
+		Body:        request.Body,
+		BodyOmitted: request.BodyOmitted,
+		Status:      request.Status,
+		DurationMS:  request.DurationMS,
+		RecordedAt:  request.RecordedAt,
+	}
+}
+
+// ListRecordedRequests godoc
+// @Summary List recorded requests
+// @Description The latest API requests made with API keys, newest first, with credentials removed
+// @Tags admin
+// @Produce json
+// @Param api_key_id query string false "Only requests of the API key with this ID"
+// @Success 200 {array} RecordedRequestResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/requests [get]
+func (h *RequestReplayHandler) ListRecordedRequests(c fiber.Ctx) error {
+	ctx, _, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	requests, err := h.requestReplayUseCase.ListRecordedRequests(ctx, c.Query("api_key_id"))
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(requests, newRecordedRequestResponse))
+}
+
+// ReplayRequest godoc
+// @Summary Replay recorded request
+// @Description Send a recorded request again to the configured staging URL and return its response
+// @Tags admin
+// @Produce json
+// @Param id path string true "Recorded request ID"
+// @Success 200 {object} usecase.ReplayResult
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Recorded request not found"
+// @Failure 502 {object} map[string]string "Staging did not answer"
+// @Failure 503 {object} map[string]string "Replay is not configured"
+// @Failure 500 {object} map[string]string
+// @Router /admin/requests/{id}/replay [post]
+func (h *RequestReplayHandler) ReplayRequest(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	result, err := h.requestReplayUseCase.ReplayRequest(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case errors.Is(err, usecase.ErrRecordedRequestNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrReplayNotConfigured):
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrReplayFailed):
+			return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+				"error": usecase.ErrReplayFailed.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrReplayRequest, zap.String("requestID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
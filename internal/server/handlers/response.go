@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// mapResponses converts a list of domain values into their responses. The result is never nil,
+// so an empty list is written as [] rather than null.
+func mapResponses[T, R any](items []T, toResponse func(T) R) []R {
+	responses := make([]R, len(items))
+	for i, item := range items {
+		responses[i] = toResponse(item)
+	}
+	return responses
+}
+
+// lookaheadLimit is the limit to list a page of limit items with: the item listed past the page
+// tells whether there is a next one, without counting the rows.
+func lookaheadLimit(limit int) int {
+	if limit < 0 {
+		return limit
+	}
+	return limit + 1
+}
+
+// trimPage drops the item listed past the page by lookaheadLimit and reports whether there was one.
+func trimPage[T any](items []T, limit int) ([]T, bool) {
+	if limit < 0 || len(items) <= limit {
+		return items, false
+	}
+	return items[:limit], true
+}
+
+// parsePageRequest reads the cursor and limit of a list paginated with a cursor. An offset is
+// refused rather than ignored, so that a client still paging by offset does not get the first
+// page over and over.
+func parsePageRequest(c fiber.Ctx) (usecase.PageRequest, error) {
+	if c.Query("offset") != "" {
+		return usecase.PageRequest{}, errors.New(common.ErrOffsetPagination)
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(usecase.DefaultPageLimit)))
+	if err != nil {
+		return usecase.PageRequest{}, errors.New(common.ErrInvalidParams)
+	}
+
+	return usecase.PageRequest{Cursor: c.Query("cursor"), Limit: limit}, nil
+}
+
+// setPage attaches the pagination of a page read with a cursor to the response.
+func setPage[T any](c fiber.Ctx, request usecase.PageRequest, page *usecase.PageResponse[T]) {
+	middleware.SetPagination(c, middleware.Pagination{
+		Limit:      request.Size(),
+		Count:      len(page.Items),
+		HasNext:    page.HasMore(),
+		NextCursor: page.NextCursor,
+	})
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/tinylib/msgp/msgp"
+)
+
+const (
+	MIMEApplicationMsgPack  = "application/msgpack"
+	MIMEApplicationXMsgPack = "application/x-msgpack"
+
+	xmlRootElement = "response"
+	xmlListItem    = "item"
+)
+
+// ResponseEncoder writes response bodies in the media types it offers. Encoders other than JSON
+// get the body in its JSON form, so every format uses the same field names.
+type ResponseEncoder interface {
+	MediaTypes() []string
+	Encode(body any) ([]byte, error)
+}
+
+var (
+	responseEncodersMu sync.RWMutex
+	responseEncoders   = []ResponseEncoder{xmlEncoder{}, msgpackEncoder{}}
+)
+
+// RegisterResponseEncoder makes the media types of the encoder available to Accept negotiation.
+// It replaces an encoder already registered for the same first media type. JSON is always
+// offered first and cannot be replaced.
+func RegisterResponseEncoder(encoder ResponseEncoder) {
+	responseEncodersMu.Lock()
+	defer responseEncodersMu.Unlock()
+
+	for i, registered := range responseEncoders {
+		if registered.MediaTypes()[0] == encoder.MediaTypes()[0] {
+			responseEncoders[i] = encoder
+			return
+		}
+	}
+	responseEncoders = append(responseEncoders, encoder)
+}
+
+// respond writes the body with the encoder the Accept header of the request asks for. Requests
+// without an Accept header, or accepting none of the offered types, get JSON.
+func respond(c fiber.Ctx, status int, body any) error {
+	c.Vary(fiber.HeaderAccept)
+
+	mediaType, encoder := negotiateEncoder(c)
+	if encoder == nil {
+		return c.Status(status).JSON(body)
+	}
+
+	generic, err := toGeneric(body)
+	if err != nil {
+		return err
+	}
+
+	data, err := encoder.Encode(generic)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, mediaType)
+	return c.Status(status).Send(data)
+}
+
+// negotiateEncoder returns the accepted media type and its encoder, or no encoder for JSON.
+func negotiateEncoder(c fiber.Ctx) (string, ResponseEncoder) {
+	responseEncodersMu.RLock()
+	defer responseEncodersMu.RUnlock()
+
+	offers := []string{fiber.MIMEApplicationJSON}
+	for _, encoder := range responseEncoders {
+		offers = append(offers, encoder.MediaTypes()...)
+	}
+
+	accepted := c.Accepts(offers...)
+	for _, encoder := range responseEncoders {
+		if slices.Contains(encoder.MediaTypes(), accepted) {
+			return accepted, encoder
+		}
+	}
+	return fiber.MIMEApplicationJSON, nil
+}
+
+// toGeneric turns the body into the maps, slices and scalars of its JSON form. Whole numbers stay
+// int64 so that encoders can tell them from fractions.
+func toGeneric(body any) (any, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return convertNumbers(generic), nil
+}
+
+func convertNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	}
+	return value
+}
+
+type msgpackEncoder struct{}
+
+func (msgpackEncoder) MediaTypes() []string {
+	return []string{MIMEApplicationMsgPack, MIMEApplicationXMsgPack}
+}
+
+func (msgpackEncoder) Encode(body any) ([]byte, error) {
+	return msgp.AppendIntf(nil, body)
+}
+
+// xmlEncoder writes the body under a <response> element. Object fields become child elements,
+// list entries <item> elements; fields whose names are not valid XML names are written as
+// <entry key="...">.
+type xmlEncoder struct{}
+
+func (xmlEncoder) MediaTypes() []string {
+	return []string{fiber.MIMEApplicationXML, fiber.MIMETextXML}
+}
+
+func (xmlEncoder) Encode(body any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(&buf)
+	if err := encodeXMLElement(encoder, xml.StartElement{Name: xml.Name{Local: xmlRootElement}}, body); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeXMLElement(encoder *xml.Encoder, start xml.StartElement, value any) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			if err := encodeXMLElement(encoder, xmlFieldElement(key), v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := encodeXMLElement(encoder, xml.StartElement{Name: xml.Name{Local: xmlListItem}}, item); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+func xmlFieldElement(name string) xml.StartElement {
+	if isXMLName(name) {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+	}
+}
+
+func isXMLName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if i == 0 && !letter {
+			return false
+		}
+		if !letter && r != '-' && r != '.' && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
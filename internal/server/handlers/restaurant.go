@@ -1,11 +1,20 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
@@ -30,50 +39,302 @@ func NewRestaurantHandler(
 	}
 }
 
+type RestaurantResponse struct {
+	ID           string         `json:"id"`
+	Name         string         `json:"name"`
+	Slug         string         `json:"slug"`
+	Address      string         `json:"address"`
+	Cuisine      domain.Cuisine `json:"cuisine"`
+	Description  string         `json:"description"`
+	Facts        []FactResponse `json:"facts"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	ContactEmail string         `json:"contact_email"`
+	ContactPhone string         `json:"contact_phone"`
+	IsTest       bool           `json:"is_test"`
+	IsAdultOnly  bool           `json:"is_adult_only"`
+	Currency     string         `json:"currency"`
+}
+
+type FactResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Content      string    `json:"content"`
+	Locale       string    `json:"locale"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WorkingHoursResponse struct {
+	ID           string         `json:"id"`
+	RestaurantID string         `json:"restaurant_id"`
+	WeekDay      domain.WeekDay `json:"week_day"`
+	OpenTime     string         `json:"open_time"`
+	CloseTime    string         `json:"close_time"`
+	IsClosed     bool           `json:"is_closed"`
+	ValidFrom    time.Time      `json:"valid_from"`
+	ValidTo      time.Time      `json:"valid_to"`
+}
+
+type AvailabilityResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Date         time.Time `json:"date"`
+	TimeSlot     string    `json:"time_slot"`
+	Capacity     int       `json:"capacity"`
+	Reserved     int       `json:"reserved"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type AvailabilityDeltaResponse struct {
+	Slots   []AvailabilityResponse `json:"slots"`
+	Version string                 `json:"version"`
+	HasMore bool                   `json:"has_more"`
+}
+
+type ReservedSeatsDriftResponse struct {
+	AvailabilityID string    `json:"availability_id"`
+	RestaurantID   string    `json:"restaurant_id"`
+	Date           time.Time `json:"date"`
+	TimeSlot       string    `json:"time_slot"`
+	Recorded       int       `json:"recorded"`
+	Actual         int       `json:"actual"`
+}
+
+func newRestaurantResponse(restaurant *domain.Restaurant) RestaurantResponse {
+	return RestaurantResponse{
+		ID:           restaurant.ID,
+		Name:         restaurant.Name,
+		Slug:         restaurant.Slug,
+		Address:      restaurant.Address,
+		Cuisine:      restaurant.Cuisine,
+		Description:  restaurant.Description,
+		Facts:        mapResponses(restaurant.Facts, newFactResponse),
+		CreatedAt:    restaurant.CreatedAt,
+		UpdatedAt:    restaurant.UpdatedAt,
+		ContactEmail: restaurant.ContactEmail,
+		ContactPhone: restaurant.ContactPhone,
+		IsTest:       restaurant.IsTest,
+		IsAdultOnly:  restaurant.IsAdultOnly,
+		Currency:     restaurant.Currency,
+	}
+}
+
+func newFactResponse(fact domain.Fact) FactResponse {
+	return FactResponse{
+		ID:           fact.ID,
+		RestaurantID: fact.RestaurantID,
+		Content:      fact.Content,
+		Locale:       fact.Locale,
+		CreatedAt:    fact.CreatedAt,
+	}
+}
+
+func newWorkingHoursResponse(hours *domain.WorkingHours) WorkingHoursResponse {
+	return WorkingHoursResponse{
+		ID:           hours.ID,
+		RestaurantID: hours.RestaurantID,
+		WeekDay:      hours.WeekDay,
+		OpenTime:     hours.OpenTime,
+		CloseTime:    hours.CloseTime,
+		IsClosed:     hours.IsClosed,
+		ValidFrom:    hours.ValidFrom,
+		ValidTo:      hours.ValidTo,
+	}
+}
+
+func newAvailabilityResponse(availability *domain.Availability) AvailabilityResponse {
+	return AvailabilityResponse{
+		ID:           availability.ID,
+		RestaurantID: availability.RestaurantID,
+		Date:         availability.Date,
+		TimeSlot:     availability.TimeSlot,
+		Capacity:     availability.Capacity,
+		Reserved:     availability.Reserved,
+		UpdatedAt:    availability.UpdatedAt,
+	}
+}
+
+func newReservedSeatsDriftResponse(drift domain.ReservedSeatsDrift) ReservedSeatsDriftResponse {
+	return ReservedSeatsDriftResponse{
+		AvailabilityID: drift.AvailabilityID,
+		RestaurantID:   drift.RestaurantID,
+		Date:           drift.Date,
+		TimeSlot:       drift.TimeSlot,
+		Recorded:       drift.Recorded,
+		Actual:         drift.Actual,
+	}
+}
+
 // ListRestaurants godoc
 // @Summary List restaurants
-// @Description Get a list of all restaurants with optional pagination
+// @Description Get a page of the restaurants ordered by name, or of those in a city of GET /cities. Pages are read after the cursor of the previous one, so restaurants added meanwhile never shift them
 // @Tags restaurants
 // @Accept json
-// @Produce json
-// @Param offset query int false "Offset" default(0)
-// @Param limit query int false "Limit" default(20)
-// @Success 200 {array} domain.Restaurant
+// @Produce json,xml,application/msgpack
+// @Param city_id query string false "City ID; every city by default"
+// @Param adult_only query bool false "Only adult-only venues when true, only the others when false; both by default"
+// @Param cursor query string false "X-Next-Cursor header of the previous page, from the first restaurant by default"
+// @Param limit query int false "Limit, up to 100" default(20)
+// @Success 200 {array} RestaurantResponse
+// @Success 304 {string} string "Not modified since the cached copy"
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /restaurants [get]
 func (h *RestaurantHandler) ListRestaurants(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	request, err := parsePageRequest(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filter := domain.RestaurantFilter{CityID: c.Query("city_id")}
+	if adultOnly := c.Query("adult_only"); adultOnly != "" {
+		value, err := strconv.ParseBool(adultOnly)
+		if err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+		filter.AdultOnly = &value
+	}
+
+	page, err := h.restaurantUseCase.ListRestaurants(ctx, filter, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPageCursor) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidPageCursor,
+			})
+		}
+
+		log.Error(ctx, common.ErrListRestaurants, zap.Error(err))
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	pagination := middleware.Pagination{
+		Limit:      request.Size(),
+		Count:      len(page.Items),
+		HasNext:    page.HasMore(),
+		NextCursor: page.NextCursor,
+	}
+	if filter.AdultOnly == nil && filter.CityID == "" {
+		// The estimate only helps clients size the list, the page is served without it.
+		total, err := h.restaurantUseCase.EstimateRestaurantCount(ctx)
+		switch {
+		case err != nil:
+			log.Warn(ctx, common.ErrEstimateRestaurants, zap.Error(err))
+		case total >= 0:
+			pagination.EstimatedTotal = &total
+		}
+	}
+	middleware.SetPagination(c, pagination)
+	lastModified := latestUpdate(page.Items, func(restaurant *domain.Restaurant) time.Time {
+		return restaurant.UpdatedAt
+	})
+	return respondCacheable(c, lastModified, mapResponses(page.Items, newRestaurantResponse))
+}
+
+// SearchRestaurants godoc
+// @Summary Search restaurants
+// @Description Search the catalogue by words of the name and description, best matches first, and narrow it down by cuisine, address, city and whether the restaurant is open now. Test restaurants are left out
+// @Tags restaurants
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param q query string false "Words of the name or description; quoted phrases, -excluded words and or are understood"
+// @Param cuisine query string false "Cuisine"
+// @Param address query string false "Words of the address"
+// @Param city_id query string false "City ID of GET /cities"
+// @Param open_now query bool false "Only restaurants open now by their schedule: working hours, special days and closures"
+// @Param adult_only query bool false "Only adult-only venues when true, only the others when false; both by default"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} RestaurantResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/search [get]
+func (h *RestaurantHandler) SearchRestaurants(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil || offset < 0 {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
 	limit, err := strconv.Atoi(c.Query("limit", "20"))
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	if err != nil || limit <= 0 {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
-	restaurants, err := h.restaurantUseCase.ListRestaurants(ctx, offset, limit)
+	filter := domain.RestaurantFilter{
+		Query:    c.Query("q"),
+		Cuisine:  domain.Cuisine(c.Query("cuisine")),
+		Address:  c.Query("address"),
+		CityID:   c.Query("city_id"),
+		LiveOnly: true,
+	}
+	if adultOnly := c.Query("adult_only"); adultOnly != "" {
+		value, err := strconv.ParseBool(adultOnly)
+		if err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+		filter.AdultOnly = &value
+	}
+	if openNow := c.Query("open_now"); openNow != "" {
+		value, err := strconv.ParseBool(openNow)
+		if err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+		if value {
+			now := time.Now().UTC()
+			filter.OpenAt = &now
+		}
+	}
+
+	restaurants, err := h.restaurantUseCase.SearchRestaurants(ctx, filter, offset, lookaheadLimit(limit))
 	if err != nil {
-		log.Error(ctx, common.ErrListRestaurants, zap.Error(err))
+		if status, ok := restaurantErrorStatus(err); ok {
+			return respond(c, status, fiber.Map{
+				"error": err.Error(),
+			})
+		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		log.Error(ctx, common.ErrSearchRestaurants, zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(restaurants)
+	restaurants, hasNext := trimPage(restaurants, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(restaurants),
+		HasNext: hasNext,
+	})
+	return respond(c, fiber.StatusOK, mapResponses(restaurants, newRestaurantResponse))
 }
 
 // GetRestaurant godoc
@@ -81,9 +342,10 @@ func (h *RestaurantHandler) ListRestaurants(c fiber.Ctx) error {
 // @Description Get detailed information about a restaurant by ID
 // @Tags restaurants
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
-// @Success 200 {object} domain.Restaurant
+// @Success 200 {object} RestaurantResponse
+// @Success 304 {string} string "Not modified since the cached copy"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
@@ -91,50 +353,111 @@ func (h *RestaurantHandler) ListRestaurants(c fiber.Ctx) error {
 func (h *RestaurantHandler) GetRestaurant(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	if restaurant == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return respond(c, fiber.StatusNotFound, fiber.Map{
 			"error": common.ErrRestaurantNotFound,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(restaurant)
+	return respondCacheable(c, restaurant.UpdatedAt, newRestaurantResponse(restaurant))
+}
+
+// GetRestaurantBySlug godoc
+// @Summary Get restaurant by slug
+// @Description Get detailed information about a restaurant by its URL slug. A slug the restaurant had before redirects to the current one
+// @Tags restaurants
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param slug path string true "Restaurant slug"
+// @Success 200 {object} RestaurantResponse
+// @Success 301 {string} string "Moved to the current slug"
+// @Success 304 {string} string "Not modified since the cached copy"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/by-slug/{slug} [get]
+func (h *RestaurantHandler) GetRestaurantBySlug(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	slug := c.Params("slug")
+	if slug == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurantBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidRestaurantSlug) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("slug", slug), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if restaurant.Slug != slug {
+		return c.Redirect().Status(fiber.StatusMovedPermanently).To(strings.TrimSuffix(c.Path(), slug) + restaurant.Slug)
+	}
+
+	return respondCacheable(c, restaurant.UpdatedAt, newRestaurantResponse(restaurant))
 }
 
 type CreateRestaurantRequest struct {
 	Name         string         `json:"name"          validate:"required"`
+	Slug         string         `json:"slug"`
 	Address      string         `json:"address"       validate:"required"`
 	Cuisine      domain.Cuisine `json:"cuisine"       validate:"required"`
 	Description  string         `json:"description"`
 	ContactEmail string         `json:"contact_email" validate:"required,email"`
 	ContactPhone string         `json:"contact_phone" validate:"required"`
 	Facts        []string       `json:"facts"`
+	IsTest       bool           `json:"is_test"`
+	// IsAdultOnly admits adults only; every booking then needs the guest to attest their age.
+	IsAdultOnly bool `json:"is_adult_only"`
+	// Currency is the ISO 4217 code of the prices of the restaurant, RUB when left out.
+	Currency string `json:"currency"`
 }
 
 // CreateRestaurant godoc
@@ -142,16 +465,17 @@ type CreateRestaurantRequest struct {
 // @Description Create a new restaurant
 // @Tags restaurants
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param restaurant body CreateRestaurantRequest true "Restaurant data"
-// @Success 201 {object} domain.Restaurant
+// @Success 201 {object} RestaurantResponse
 // @Failure 400 {object} map[string]string "Invalid data"
+// @Failure 409 {object} map[string]string "Slug is already taken"
 // @Failure 500 {object} map[string]string
 // @Router /restaurants [post]
 func (h *RestaurantHandler) CreateRestaurant(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
@@ -160,49 +484,66 @@ func (h *RestaurantHandler) CreateRestaurant(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
 	restaurant := &domain.Restaurant{
 		Name:         request.Name,
+		Slug:         request.Slug,
 		Address:      request.Address,
 		Cuisine:      request.Cuisine,
 		Description:  request.Description,
 		ContactEmail: request.ContactEmail,
 		ContactPhone: request.ContactPhone,
+		IsTest:       request.IsTest,
+		IsAdultOnly:  request.IsAdultOnly,
+		Currency:     request.Currency,
 	}
 
 	restaurantID, err := h.restaurantUseCase.CreateRestaurant(ctx, restaurant)
 	if err != nil {
+		if status, ok := restaurantErrorStatus(err); ok {
+			return respond(c, status, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
 		log.Error(ctx, common.ErrCreateRestaurant, zap.Error(err))
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	for _, factContent := range request.Facts {
-		if _, err := h.restaurantUseCase.AddFact(ctx, restaurantID, factContent); err != nil {
+		if _, err := h.restaurantUseCase.AddFact(ctx, domain.RestaurantID(restaurantID), factContent, ""); err != nil {
 			log.Warn(ctx, common.ErrAddFact,
 				zap.String("restaurantID", restaurantID),
 				zap.Error(err))
 		}
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return respond(c, fiber.StatusCreated, fiber.Map{
 		"id": restaurantID,
 	})
 }
 
 type UpdateRestaurantRequest struct {
 	Name         string         `json:"name"          validate:"required"`
+	Slug         string         `json:"slug"`
 	Address      string         `json:"address"       validate:"required"`
 	Cuisine      domain.Cuisine `json:"cuisine"       validate:"required"`
 	Description  string         `json:"description"`
 	ContactEmail string         `json:"contact_email" validate:"required,email"`
 	ContactPhone string         `json:"contact_phone" validate:"required"`
+	// IsTest switches test mode; the restaurant keeps its mode when it is left out.
+	IsTest *bool `json:"is_test,omitempty"`
+	// IsAdultOnly switches the adult-only restriction; the restaurant keeps it when it is left out.
+	IsAdultOnly *bool `json:"is_adult_only,omitempty"`
+	// Currency changes the currency of the prices; amounts already saved are not converted.
+	Currency string `json:"currency,omitempty"`
 }
 
 // UpdateRestaurant godoc
@@ -210,26 +551,27 @@ type UpdateRestaurantRequest struct {
 // @Description Update an existing restaurant
 // @Tags restaurants
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
 // @Param restaurant body UpdateRestaurantRequest true "Restaurant data"
-// @Success 200 {object} domain.Restaurant
+// @Success 200 {object} RestaurantResponse
 // @Failure 400 {object} map[string]string "Invalid data"
 // @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "Slug is already taken"
 // @Failure 500 {object} map[string]string
 // @Router /restaurants/{id} [put]
 func (h *RestaurantHandler) UpdateRestaurant(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -237,64 +579,94 @@ func (h *RestaurantHandler) UpdateRestaurant(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
 	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	if restaurant == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return respond(c, fiber.StatusNotFound, fiber.Map{
 			"error": common.ErrRestaurantNotFound,
 		})
 	}
 
 	restaurant.Name = request.Name
+	if request.Slug != "" {
+		restaurant.Slug = request.Slug
+	}
 	restaurant.Address = request.Address
 	restaurant.Cuisine = request.Cuisine
 	restaurant.Description = request.Description
 	restaurant.ContactEmail = request.ContactEmail
 	restaurant.ContactPhone = request.ContactPhone
+	if request.IsTest != nil {
+		restaurant.IsTest = *request.IsTest
+	}
+	if request.IsAdultOnly != nil {
+		restaurant.IsAdultOnly = *request.IsAdultOnly
+	}
+	if request.Currency != "" {
+		restaurant.Currency = request.Currency
+	}
 
 	if err := h.restaurantUseCase.UpdateRestaurant(ctx, restaurant); err != nil {
-		log.Error(ctx, common.ErrUpdateRestaurant, zap.String("id", id), zap.Error(err))
+		if status, ok := restaurantErrorStatus(err); ok {
+			return respond(c, status, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateRestaurant, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return respond(c, fiber.StatusOK, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
 
+func restaurantErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRestaurantSlug), errors.Is(err, usecase.ErrInvalidCurrency),
+		errors.Is(err, contact.ErrInvalidEmail), errors.Is(err, contact.ErrInvalidPhone),
+		errors.Is(err, domain.ErrInvalidEntity):
+		return fiber.StatusBadRequest, true
+	case errors.Is(err, usecase.ErrRestaurantSlugTaken):
+		return fiber.StatusConflict, true
+	}
+	return 0, false
+}
+
 // DeleteRestaurant godoc
 // @Summary Delete restaurant
 // @Description Delete restaurant by ID
 // @Tags restaurants
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
@@ -304,39 +676,40 @@ func (h *RestaurantHandler) UpdateRestaurant(c fiber.Ctx) error {
 func (h *RestaurantHandler) DeleteRestaurant(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	if err := h.restaurantUseCase.DeleteRestaurant(ctx, id); err != nil {
-		log.Error(ctx, common.ErrDeleteRestaurant, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrDeleteRestaurant, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return respond(c, fiber.StatusOK, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
 
 type AddFactRequest struct {
 	Content string `json:"content" validate:"required"`
+	Locale  string `json:"locale"`
 }
 
 // AddFact godoc
@@ -344,10 +717,10 @@ type AddFactRequest struct {
 // @Description Add an interesting fact about a restaurant
 // @Tags restaurants,facts
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
 // @Param fact body AddFactRequest true "Fact content"
-// @Success 201 {object} domain.Fact
+// @Success 201 {object} FactResponse
 // @Failure 400 {object} map[string]string "Invalid data"
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
@@ -355,15 +728,15 @@ type AddFactRequest struct {
 func (h *RestaurantHandler) AddFact(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -371,50 +744,50 @@ func (h *RestaurantHandler) AddFact(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
 	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
 	if restaurant == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+		return respond(c, fiber.StatusNotFound, fiber.Map{
 			"error": common.ErrRestaurantNotFound,
 		})
 	}
 
-	fact, err := h.restaurantUseCase.AddFact(ctx, id, request.Content)
+	fact, err := h.restaurantUseCase.AddFact(ctx, id, request.Content, request.Locale)
 	if err != nil {
 		log.Error(ctx, common.ErrAddFact,
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fact)
+	return respond(c, fiber.StatusCreated, newFactResponse(*fact))
 }
 
 // GetFacts godoc
@@ -422,9 +795,10 @@ func (h *RestaurantHandler) AddFact(c fiber.Ctx) error {
 // @Description Get all interesting facts about a restaurant
 // @Tags restaurants,facts
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
-// @Success 200 {array} domain.Fact
+// @Success 200 {array} FactResponse
+// @Success 304 {string} string "Not modified since the cached copy"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
@@ -432,28 +806,43 @@ func (h *RestaurantHandler) AddFact(c fiber.Ctx) error {
 func (h *RestaurantHandler) GetFacts(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
 		})
 	}
 
 	facts, err := h.restaurantUseCase.GetFacts(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetFacts, zap.String("restaurantID", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetFacts, zap.String("restaurantID", id.String()), zap.Error(err))
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(facts)
+	// Adding a fact moves the modification time of the restaurant forward.
+	return respondCacheable(c, restaurant.UpdatedAt, mapResponses(facts, newFactResponse))
 }
 
 type SetWorkingHoursRequest struct {
@@ -469,10 +858,10 @@ type SetWorkingHoursRequest struct {
 // @Description Set working hours for a restaurant
 // @Tags restaurants,working-hours
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
 // @Param working_hours body SetWorkingHoursRequest true "Working hours data"
-// @Success 201 {object} domain.WorkingHours
+// @Success 201 {object} WorkingHoursResponse
 // @Failure 400 {object} map[string]string "Invalid data"
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
@@ -480,15 +869,15 @@ type SetWorkingHoursRequest struct {
 func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
@@ -496,7 +885,7 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
@@ -504,7 +893,7 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 	if _, err := time.Parse("15:04", request.OpenTime); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.String("openTime", request.OpenTime), zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
@@ -512,7 +901,7 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 	if _, err := time.Parse("15:04", request.CloseTime); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.String("closeTime", request.CloseTime), zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
@@ -526,7 +915,7 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 	}
 
 	workingHours := &domain.WorkingHours{
-		RestaurantID: id,
+		RestaurantID: id.String(),
 		WeekDay:      request.WeekDay,
 		OpenTime:     request.OpenTime,
 		CloseTime:    request.CloseTime,
@@ -536,22 +925,28 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 
 	if err := h.restaurantUseCase.SetWorkingHours(ctx, id, workingHours); err != nil {
 		log.Error(ctx, common.ErrSetWorkingHours,
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Int("weekDay", int(request.WeekDay)),
 			zap.Error(err))
 
+		if errors.Is(err, domain.ErrInvalidEntity) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+	return respond(c, fiber.StatusCreated, fiber.Map{
 		"status": common.MsgSuccess,
 	})
 }
@@ -561,9 +956,10 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 // @Description Get working hours of a restaurant
 // @Tags restaurants,working-hours
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
-// @Success 200 {array} domain.WorkingHours
+// @Success 200 {array} WorkingHoursResponse
+// @Success 304 {string} string "Not modified since the cached copy"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
@@ -571,219 +967,1193 @@ func (h *RestaurantHandler) SetWorkingHours(c fiber.Ctx) error {
 func (h *RestaurantHandler) GetWorkingHours(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
 		})
 	}
 
 	workingHours, err := h.restaurantUseCase.GetWorkingHours(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetWorkingHours, zap.String("restaurantID", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetWorkingHours, zap.String("restaurantID", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(workingHours)
+	// Setting working hours moves the modification time of the restaurant forward.
+	return respondCacheable(c, restaurant.UpdatedAt, mapResponses(workingHours, newWorkingHoursResponse))
 }
 
-type SetAvailabilityRequest struct {
-	Date     time.Time `json:"date"     validate:"required"`
-	TimeSlot string    `json:"time_slot" validate:"required"`
-	Capacity int       `json:"capacity"  validate:"required,min=1"`
+type SetSpecialDayRequest struct {
+	// OpenTime and CloseTime ("15:04") are left out when the restaurant is closed on the date.
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	IsClosed  bool   `json:"is_closed"`
+	Note      string `json:"note"`
 }
 
-// SetAvailability godoc
-// @Summary Set availability
-// @Description Set availability for a specific date and time
-// @Tags restaurants,availability
+type SpecialDayResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Date         string    `json:"date"`
+	OpenTime     string    `json:"open_time,omitempty"`
+	CloseTime    string    `json:"close_time,omitempty"`
+	IsClosed     bool      `json:"is_closed"`
+	Note         string    `json:"note,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newSpecialDayResponse(day *domain.SpecialDay) SpecialDayResponse {
+	return SpecialDayResponse{
+		ID:           day.ID,
+		RestaurantID: day.RestaurantID,
+		Date:         day.Date.Format(time.DateOnly),
+		OpenTime:     day.OpenTime,
+		CloseTime:    day.CloseTime,
+		IsClosed:     day.IsClosed,
+		Note:         day.Note,
+		UpdatedAt:    day.UpdatedAt,
+	}
+}
+
+// SetSpecialDay godoc
+// @Summary Set a special day
+// @Description Replace the working hours of the restaurant on the date, such as a holiday it is closed on or a night it stays open late, replacing the special day it had on the date. A closure of the restaurant still closes the date
+// @Tags restaurants,working-hours
 // @Accept json
-// @Produce json
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
-// @Param availability body SetAvailabilityRequest true "Availability data"
-// @Success 201 {object} domain.Availability
-// @Failure 400 {object} map[string]string "Invalid data"
+// @Param date path string true "Date (YYYY-MM-DD)"
+// @Param special_day body SetSpecialDayRequest true "Special day"
+// @Success 200 {object} SpecialDayResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
-// @Router /restaurants/{id}/availability [post]
-func (h *RestaurantHandler) SetAvailability(c fiber.Ctx) error {
+// @Router /restaurants/{id}/special-days/{date} [put]
+func (h *RestaurantHandler) SetSpecialDay(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	date, err := time.Parse(time.DateOnly, c.Params("date"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
-	var request SetAvailabilityRequest
+	var request SetSpecialDayRequest
 	if err := c.Bind().Body(&request); err != nil {
 		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
 
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
 			"error": common.ErrInvalidParams,
 		})
 	}
 
-	availability := &domain.Availability{
-		RestaurantID: id,
-		Date:         request.Date,
-		TimeSlot:     request.TimeSlot,
-		Capacity:     request.Capacity,
-		Reserved:     0,
+	day := &domain.SpecialDay{
+		RestaurantID: id.String(),
+		Date:         date,
+		OpenTime:     request.OpenTime,
+		CloseTime:    request.CloseTime,
+		IsClosed:     request.IsClosed,
+		Note:         request.Note,
 	}
-
-	log.Info(ctx, common.MsgUpdateAvailability,
-		zap.String("restaurantID", id),
-		zap.Time("date", availability.Date),
-		zap.String("timeSlot", availability.TimeSlot),
-		zap.Int("capacity", availability.Capacity))
-
-	if err := h.availabilityUseCase.SetAvailability(ctx, availability); err != nil {
-		log.Error(ctx, common.ErrUpdateAvailability,
-			zap.String("restaurantID", id),
-			zap.Error(err))
-
-		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+	if err := h.restaurantUseCase.SetSpecialDay(ctx, day); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEntity):
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return respond(c, fiber.StatusNotFound, fiber.Map{
 				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		log.Error(ctx, common.ErrSetSpecialDay, zap.String("restaurantID", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"status": common.MsgSuccess,
-	})
+	return respond(c, fiber.StatusOK, newSpecialDayResponse(day))
 }
 
-// GetAvailability godoc
-// @Summary Get availability
-// @Description Get availability for a restaurant on a specific date
-// @Tags restaurants,availability
-// @Accept json
-// @Produce json
+// ListSpecialDays godoc
+// @Summary List special days
+// @Description The special days of the restaurant from today on, earliest first
+// @Tags restaurants,working-hours
+// @Produce json,xml,application/msgpack
 // @Param id path string true "Restaurant ID"
-// @Param date query string false "Date (YYYY-MM-DD)"
-// @Success 200 {array} domain.Availability
+// @Success 200 {array} SpecialDayResponse
 // @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string "Restaurant not found"
 // @Failure 500 {object} map[string]string
-// @Router /restaurants/{id}/availability [get]
-func (h *RestaurantHandler) GetAvailability(c fiber.Ctx) error {
+// @Router /restaurants/{id}/special-days [get]
+func (h *RestaurantHandler) ListSpecialDays(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
-		})
-	}
-
-	dateStr := c.Query("date")
-	if dateStr == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
-		})
-	}
-
-	date, err := time.Parse("2006-01-02", dateStr)
+	id, err := domain.ParseRestaurantID(c.Params("id"))
 	if err != nil {
-		log.Error(ctx, common.ErrParseRequestBody, zap.String("date", dateStr), zap.Error(err))
-
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
-	availability, err := h.availabilityUseCase.GetAvailability(ctx, id, date)
+	days, err := h.restaurantUseCase.ListSpecialDays(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetCurrentAvailability, zap.String("restaurantID", id), zap.Error(err))
-
-		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": common.ErrRestaurantNotFound,
-			})
-		}
-
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		log.Error(ctx, common.ErrListSpecialDays, zap.String("restaurantID", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(availability)
+	return respond(c, fiber.StatusOK, mapResponses(days, newSpecialDayResponse))
 }
 
-// GetRestaurantBookings godoc
-// @Summary Get restaurant bookings
-// @Description Get all bookings for a specific restaurant
-// @Tags restaurants,bookings
-// @Accept json
-// @Produce json
+// DeleteSpecialDay godoc
+// @Summary Delete a special day
+// @Description The working hours of the weekday apply on the date again
+// @Tags restaurants,working-hours
 // @Param id path string true "Restaurant ID"
-// @Param status query string false "Booking status (pending,confirmed,rejected,canceled,completed)"
-// @Param date query string false "Date (YYYY-MM-DD)"
-// @Success 200 {array} domain.Booking
+// @Param date path string true "Date (YYYY-MM-DD)"
+// @Success 204 "Deleted"
 // @Failure 400 {object} map[string]string
-// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Special day not found"
 // @Failure 500 {object} map[string]string
-// @Router /restaurants/{id}/bookings [get]
-func (h *RestaurantHandler) GetRestaurantBookings(c fiber.Ctx) error {
+// @Router /restaurants/{id}/special-days/{date} [delete]
+func (h *RestaurantHandler) DeleteSpecialDay(c fiber.Ctx) error {
 	ctx, log, err := getContextAndLogger(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
-	bookings, err := h.bookingUseCase.GetRestaurantBookings(ctx, id)
+	date, err := time.Parse(time.DateOnly, c.Params("date"))
 	if err != nil {
-		log.Error(ctx, common.ErrGetRestaurantBookings, zap.String("restaurantID", id), zap.Error(err))
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
 
-		if err.Error() == common.ErrRestaurantNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": common.ErrRestaurantNotFound,
+	if err := h.restaurantUseCase.DeleteSpecialDay(ctx, id, date); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrSpecialDayNotFound:
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrSpecialDayNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteSpecialDay, zap.String("restaurantID", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// defaultScheduleDays is how many days the schedule covers when to is left out.
+const defaultScheduleDays = 7
+
+type OpeningHoursResponse struct {
+	OpensAt  time.Time `json:"opens_at"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type ScheduleDayResponse struct {
+	Date   string                 `json:"date"`
+	Closed bool                   `json:"closed"`
+	Hours  []OpeningHoursResponse `json:"hours"`
+	// ReopensOn and ClosureMessage are set on the days of a closure of the restaurant.
+	ReopensOn      string `json:"reopens_on,omitempty"`
+	ClosureMessage string `json:"closure_message,omitempty"`
+	// SpecialDay is set when a special day replaces the working hours on the date, with its note.
+	SpecialDay     bool   `json:"special_day,omitempty"`
+	SpecialDayNote string `json:"special_day_note,omitempty"`
+}
+
+func newScheduleDayResponse(day domain.ScheduleDay) ScheduleDayResponse {
+	response := ScheduleDayResponse{
+		Date:   day.Date.Format(time.DateOnly),
+		Closed: day.IsClosed(),
+		Hours:  mapResponses(day.Hours, newOpeningHoursResponse),
+	}
+	if day.Closure != nil {
+		response.ReopensOn = day.Closure.ReopensOn.Format(time.DateOnly)
+		response.ClosureMessage = day.Closure.Message
+	}
+	if day.SpecialDay != nil {
+		response.SpecialDay = true
+		response.SpecialDayNote = day.SpecialDay.Note
+	}
+	return response
+}
+
+func newOpeningHoursResponse(hours domain.OpeningHours) OpeningHoursResponse {
+	return OpeningHoursResponse{
+		OpensAt:  hours.OpensAt,
+		ClosesAt: hours.ClosesAt,
+	}
+}
+
+// GetSchedule godoc
+// @Summary Get schedule
+// @Description When the restaurant is open on every date of the range, resolved from the working hours of the weekdays, their validity windows, closed days, the special days and the closures of the restaurant, so that clients need not apply the rules themselves. Hours that last past midnight close on the next date. Availability is generated from the same schedule
+// @Tags restaurants,working-hours
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param from query string false "First date (YYYY-MM-DD), today by default"
+// @Param to query string false "Last date (YYYY-MM-DD), a week from from by default; at most 92 days"
+// @Success 200 {array} ScheduleDayResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/schedule [get]
+func (h *RestaurantHandler) GetSchedule(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	from := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("from"); value != "" {
+		if from, err = time.Parse(time.DateOnly, value); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+	to := from.AddDate(0, 0, defaultScheduleDays-1)
+	if value := c.Query("to"); value != "" {
+		if to, err = time.Parse(time.DateOnly, value); err != nil {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	schedule, err := h.restaurantUseCase.GetSchedule(ctx, id, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidDateRange):
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetSchedule, zap.String("restaurantID", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, mapResponses(schedule, newScheduleDayResponse))
+}
+
+// CapacityConflictResponse is returned when a capacity change would leave fewer seats than are reserved.
+type CapacityConflictResponse struct {
+	Error            string            `json:"error"`
+	Capacity         int               `json:"capacity"`
+	Reserved         int               `json:"reserved"`
+	ImpactedBookings []BookingResponse `json:"impacted_bookings"`
+}
+
+type SetAvailabilityRequest struct {
+	Date     time.Time `json:"date"     validate:"required"`
+	TimeSlot string    `json:"time_slot" validate:"required"`
+	Capacity int       `json:"capacity"  validate:"required,min=1"`
+}
+
+// SetAvailability godoc
+// @Summary Set availability
+// @Description Set availability for a specific date and time. Lowering the capacity below the reserved seats
+// @Description is rejected with the impacted bookings unless force=true, which applies it and lists them for manual resolution.
+// @Tags restaurants,availability
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param availability body SetAvailabilityRequest true "Availability data"
+// @Param force query bool false "Apply the capacity even below the reserved seats"
+// @Success 201 {object} AvailabilityResponse
+// @Failure 400 {object} map[string]string "Invalid data"
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 403 {object} QuotaExceededResponse "Active slot quota of the plan used up"
+// @Failure 409 {object} CapacityConflictResponse "Capacity below reserved seats"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability [post]
+func (h *RestaurantHandler) SetAvailability(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	force, err := strconv.ParseBool(c.Query("force", "false"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request SetAvailabilityRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	availability := &domain.Availability{
+		RestaurantID: id.String(),
+		Date:         request.Date,
+		TimeSlot:     request.TimeSlot,
+		Capacity:     request.Capacity,
+		Reserved:     0,
+	}
+
+	log.Info(ctx, common.MsgUpdateAvailability,
+		zap.String("restaurantID", id.String()),
+		zap.Time("date", availability.Date),
+		zap.String("timeSlot", availability.TimeSlot),
+		zap.Int("capacity", availability.Capacity))
+
+	var impacted []*domain.Booking
+	if force {
+		impacted, err = h.availabilityUseCase.ForceSetAvailability(ctx, availability)
+	} else {
+		err = h.availabilityUseCase.SetAvailability(ctx, availability)
+	}
+	if err != nil {
+		var conflict *usecase.CapacityConflictError
+		if errors.As(err, &conflict) {
+			return respond(c, fiber.StatusConflict, CapacityConflictResponse{
+				Error:            common.ErrCapacityBelowReserved,
+				Capacity:         conflict.Availability.Capacity,
+				Reserved:         conflict.Availability.Reserved,
+				ImpactedBookings: mapResponses(conflict.ImpactedBookings, newBookingResponse),
+			})
+		}
+
+		var exceeded *usecase.QuotaExceededError
+		if errors.As(err, &exceeded) {
+			return respond(c, fiber.StatusForbidden, newQuotaExceededResponse(exceeded))
+		}
+
+		log.Error(ctx, common.ErrUpdateAvailability,
+			zap.String("restaurantID", id.String()),
+			zap.Error(err))
+
+		if errors.Is(err, domain.ErrInvalidEntity) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if len(impacted) > 0 {
+		return respond(c, fiber.StatusCreated, fiber.Map{
+			"status":            common.MsgSuccess,
+			"impacted_bookings": mapResponses(impacted, newBookingResponse),
+		})
+	}
+
+	return respond(c, fiber.StatusCreated, fiber.Map{
+		"status": common.MsgSuccess,
+	})
+}
+
+type GenerateAvailabilityRequest struct {
+	From        string `json:"from"         validate:"required"`
+	To          string `json:"to"           validate:"required"`
+	SlotMinutes int    `json:"slot_minutes" validate:"required,min=1"`
+	Capacity    int    `json:"capacity"     validate:"required,min=1"`
+}
+
+// GenerateAvailability godoc
+// @Summary Generate availability
+// @Description Generate availability slots for a date range from the restaurant working hours.
+// @Description With dry_run=true the slots are computed in a rolled back transaction and nothing is saved.
+// @Tags restaurants,availability
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param request body GenerateAvailabilityRequest true "Date range (YYYY-MM-DD), slot length and capacity"
+// @Param dry_run query bool false "Preview the generated slots without saving them"
+// @Success 200 {array} AvailabilityResponse "Dry run result"
+// @Success 201 {array} AvailabilityResponse
+// @Failure 400 {object} map[string]string "Invalid data"
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 403 {object} QuotaExceededResponse "Active slot quota of the plan used up"
+// @Failure 409 {object} CapacityConflictResponse "Capacity below reserved seats of an existing slot"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability/generate [post]
+func (h *RestaurantHandler) GenerateAvailability(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	dryRun, err := dryRunRequested(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request GenerateAvailabilityRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	from, fromErr := time.Parse("2006-01-02", request.From)
+	to, toErr := time.Parse("2006-01-02", request.To)
+	if fromErr != nil || toErr != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	generated, err := h.availabilityUseCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+		RestaurantID: id.String(),
+		From:         from,
+		To:           to,
+		SlotDuration: time.Duration(request.SlotMinutes) * time.Minute,
+		Capacity:     request.Capacity,
+		DryRun:       dryRun,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDateRange) ||
+			errors.Is(err, usecase.ErrInvalidSlotDuration) ||
+			errors.Is(err, usecase.ErrInvalidCapacity) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		var conflict *usecase.CapacityConflictError
+		if errors.As(err, &conflict) {
+			return respond(c, fiber.StatusConflict, CapacityConflictResponse{
+				Error:            common.ErrCapacityBelowReserved,
+				Capacity:         conflict.Availability.Capacity,
+				Reserved:         conflict.Availability.Reserved,
+				ImpactedBookings: mapResponses(conflict.ImpactedBookings, newBookingResponse),
+			})
+		}
+
+		var exceeded *usecase.QuotaExceededError
+		if errors.As(err, &exceeded) {
+			return respond(c, fiber.StatusForbidden, newQuotaExceededResponse(exceeded))
+		}
+
+		log.Error(ctx, common.ErrGenerateAvailability,
+			zap.String("restaurantID", id.String()),
+			zap.Error(err))
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	if dryRun {
+		return respond(c, fiber.StatusOK, mapResponses(generated, newAvailabilityResponse))
+	}
+
+	return respond(c, fiber.StatusCreated, mapResponses(generated, newAvailabilityResponse))
+}
+
+// ExportRestaurant godoc
+// @Summary Export restaurant
+// @Description Export the restaurant profile, facts and working hours as a portable JSON bundle
+// @Tags restaurants,admin
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} usecase.RestaurantBundle
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/export [get]
+func (h *RestaurantHandler) ExportRestaurant(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	bundle, err := h.restaurantUseCase.ExportRestaurant(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrExportRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="restaurant-`+id.String()+`.json"`)
+	return c.Status(fiber.StatusOK).JSON(bundle)
+}
+
+// ImportRestaurantBundle godoc
+// @Summary Import restaurant bundle
+// @Description Create a restaurant from a bundle produced by the export endpoint, keeping its ID
+// @Tags restaurants,admin
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param bundle body usecase.RestaurantBundle true "Exported restaurant bundle"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid or unsupported bundle"
+// @Failure 409 {object} map[string]string "Restaurant already exists"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/import [post]
+func (h *RestaurantHandler) ImportRestaurantBundle(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	var bundle usecase.RestaurantBundle
+	if err := c.Bind().Body(&bundle); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	id, err := h.restaurantUseCase.ImportRestaurantBundle(ctx, &bundle)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnsupportedBundleVersion), errors.Is(err, usecase.ErrInvalidBundle):
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, usecase.ErrRestaurantAlreadyExists):
+			return respond(c, fiber.StatusConflict, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrImportRestaurantBundle, zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusCreated, fiber.Map{
+		"id": id,
+	})
+}
+
+// ImportRestaurants godoc
+// @Summary Import restaurants from CSV
+// @Description Import restaurants with working hours from a CSV file sent as the "file" form field or as a text/csv body.
+// @Description Columns: name, address, cuisine, contact_email, contact_phone and optional description, working_hours
+// @Description ("mon 10:00-22:00; sun closed"). Nothing is inserted when any row is invalid.
+// @Tags restaurants,admin
+// @Accept text/csv,mpfd
+// @Produce json,xml,application/msgpack
+// @Param file formData file false "CSV file"
+// @Param dry_run query bool false "Validate and insert in a rolled back transaction"
+// @Success 200 {object} usecase.RestaurantImportReport "Dry run result"
+// @Success 201 {object} usecase.RestaurantImportReport
+// @Failure 400 {object} map[string]string "Unreadable file"
+// @Failure 422 {object} usecase.RestaurantImportReport "Per-row validation errors"
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurants/import [post]
+func (h *RestaurantHandler) ImportRestaurants(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	dryRun, err := dryRunRequested(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var body io.Reader = bytes.NewReader(c.Body())
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			log.Error(ctx, common.ErrReadImportFile, zap.Error(err))
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrReadImportFile,
+			})
+		}
+		defer file.Close()
+		body = file
+	}
+
+	report, err := h.restaurantUseCase.ImportRestaurants(ctx, body, dryRun)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidImportFile) || errors.Is(err, usecase.ErrImportTooLarge) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrImportRestaurants, zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	switch {
+	case report.HasErrors():
+		return respond(c, fiber.StatusUnprocessableEntity, report)
+	case dryRun:
+		return respond(c, fiber.StatusOK, report)
+	default:
+		return respond(c, fiber.StatusCreated, report)
+	}
+}
+
+// ReservedSeatsReportResponse lists the slots of a date whose reserved seats differ from their active bookings.
+type ReservedSeatsReportResponse struct {
+	Date       string                       `json:"date"`
+	Fixed      bool                         `json:"fixed"`
+	Mismatches []ReservedSeatsDriftResponse `json:"mismatches"`
+}
+
+// ReservedSeatsReport godoc
+// @Summary Reserved seats reconciliation report
+// @Description Compare the guests of pending and confirmed bookings of every slot of a date with its reserved seats. With fix=true the reserved seats are corrected.
+// @Tags admin,availability
+// @Produce json,xml,application/msgpack
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Param fix query bool false "Correct the mismatched reserved seats"
+// @Success 200 {object} ReservedSeatsReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/reconciliation [get]
+func (h *RestaurantHandler) ReservedSeatsReport(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	dateStr := c.Query("date")
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	fix, err := strconv.ParseBool(c.Query("fix", "false"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	mismatches, err := h.availabilityUseCase.ReservedSeatsReport(ctx, date, fix)
+	if err != nil {
+		log.Error(ctx, common.ErrReconcileReservedSeats, zap.String("date", dateStr), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, ReservedSeatsReportResponse{
+		Date:       dateStr,
+		Fixed:      fix,
+		Mismatches: mapResponses(mismatches, newReservedSeatsDriftResponse),
+	})
+}
+
+// GetAvailability godoc
+// @Summary Get availability
+// @Description Get availability for a restaurant on a specific date
+// @Tags restaurants,availability
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Success 200 {array} AvailabilityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability [get]
+func (h *RestaurantHandler) GetAvailability(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	dateStr := c.Query("date")
+	if dateStr == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.String("date", dateStr), zap.Error(err))
+
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	availability, err := h.availabilityUseCase.GetAvailability(ctx, id, date)
+	if err != nil {
+		log.Error(ctx, common.ErrGetCurrentAvailability, zap.String("restaurantID", id.String()), zap.Error(err))
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, mapResponses(availability, newAvailabilityResponse))
+}
+
+// GetAvailabilityDelta godoc
+// @Summary Get availability changes
+// @Description Slots of the restaurant changed after the version the client holds, oldest change first, so that mobile apps keep their copy of the calendar in sync without downloading it again. Pass version as since_version next time; has_more tells whether to ask right away. Without since_version every slot is returned
+// @Tags restaurants,availability
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param since_version query string false "Version of the previous response, from the start by default"
+// @Param limit query int false "Limit" default(100)
+// @Success 200 {object} AvailabilityDeltaResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability/delta [get]
+func (h *RestaurantHandler) GetAvailabilityDelta(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	since, err := domain.ParseSyncCursor(c.Query("since_version"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidAvailabilityVersion,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(usecase.DefaultSyncLimit)))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	delta, err := h.availabilityUseCase.GetAvailabilityDelta(ctx, id, since, limit)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrListAvailabilityChanges, zap.String("restaurantID", id.String()), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, AvailabilityDeltaResponse{
+		Slots:   mapResponses(delta.Slots, newAvailabilityResponse),
+		Version: delta.Version.String(),
+		HasMore: delta.HasMore,
+	})
+}
+
+// AvailabilityInUseResponse is returned when a slot that still holds bookings is deleted.
+type AvailabilityInUseResponse struct {
+	Error    string            `json:"error"`
+	Reserved int               `json:"reserved"`
+	Bookings []BookingResponse `json:"bookings"`
+}
+
+// GetSlotBookings godoc
+// @Summary Get bookings of an availability slot
+// @Description The pending and confirmed bookings that hold seats in the slot, so that owners see whom editing or deleting it affects
+// @Tags restaurants,availability
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param availabilityId path string true "Availability ID"
+// @Success 200 {array} BookingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Availability not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability/{availabilityId}/bookings [get]
+func (h *RestaurantHandler) GetSlotBookings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	availabilityID := c.Params("availabilityId")
+	if availabilityID == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	bookings, err := h.availabilityUseCase.GetSlotBookings(ctx, id.String(), availabilityID)
+	if err != nil {
+		if err.Error() == common.ErrAvailabilityNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrAvailabilityNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrListAvailabilityBookings,
+			zap.String("restaurantID", id.String()),
+			zap.String("availabilityID", availabilityID),
+			zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, mapResponses(bookings, newBookingResponse))
+}
+
+// DeleteAvailability godoc
+// @Summary Delete availability
+// @Description Delete an availability slot. A slot with reserved seats or pending or confirmed bookings is kept; cancel or move its bookings first
+// @Tags restaurants,availability
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param availabilityId path string true "Availability ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Availability not found"
+// @Failure 409 {object} AvailabilityInUseResponse "The slot holds bookings"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/availability/{availabilityId} [delete]
+func (h *RestaurantHandler) DeleteAvailability(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	availabilityID := c.Params("availabilityId")
+	if availabilityID == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.availabilityUseCase.DeleteAvailability(ctx, id.String(), availabilityID); err != nil {
+		var inUse *usecase.AvailabilityInUseError
+		switch {
+		case errors.As(err, &inUse):
+			return respond(c, fiber.StatusConflict, AvailabilityInUseResponse{
+				Error:    inUse.Error(),
+				Reserved: inUse.Availability.Reserved,
+				Bookings: mapResponses(inUse.Bookings, newBookingResponse),
+			})
+		case err.Error() == common.ErrAvailabilityNotFound:
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrAvailabilityNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteAvailability,
+			zap.String("restaurantID", id.String()),
+			zap.String("availabilityID", availabilityID),
+			zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return respond(c, fiber.StatusOK, fiber.Map{
+		"status": common.MsgSuccess,
+	})
+}
+
+// GetRestaurantBookings godoc
+// @Summary Get restaurant bookings
+// @Description Get a page of the bookings of a specific restaurant, the latest first, optionally filtered by status, date and occasion
+// @Tags restaurants,bookings
+// @Accept json
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param status query string false "Booking status (pending,confirmed,rejected,cancelled,completed)"
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Param occasion query string false "Booking occasion (birthday,anniversary,business)"
+// @Param cursor query string false "X-Next-Cursor header of the previous page, from the latest booking by default"
+// @Param limit query int false "Limit, up to 100" default(20)
+// @Success 200 {array} BookingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/bookings [get]
+func (h *RestaurantHandler) GetRestaurantBookings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filter, err := parseBookingFilter(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	request, err := parsePageRequest(c)
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	page, err := h.bookingUseCase.GetRestaurantBookings(ctx, id, filter, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPageCursor) {
+			return respond(c, fiber.StatusBadRequest, fiber.Map{
+				"error": common.ErrInvalidPageCursor,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurantBookings, zap.String("restaurantID", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return respond(c, fiber.StatusForbidden, fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
 			})
 		}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
 			"error": common.ErrInternalServer,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(bookings)
+	setPage(c, request, page)
+	return respond(c, fiber.StatusOK, mapResponses(page.Items, newBookingResponse))
+}
+
+var bookingStatuses = []domain.BookingStatus{
+	domain.BookingStatusPending,
+	domain.BookingStatusConfirmed,
+	domain.BookingStatusRejected,
+	domain.BookingStatusCancelled,
+	domain.BookingStatusCompleted,
+}
+
+func parseBookingFilter(c fiber.Ctx) (domain.BookingFilter, error) {
+	var filter domain.BookingFilter
+
+	if status := domain.BookingStatus(c.Query("status")); status != "" {
+		if !slices.Contains(bookingStatuses, status) {
+			return filter, fmt.Errorf("%s: unknown status %q", common.ErrInvalidParams, status)
+		}
+		filter.Status = status
+	}
+
+	if occasion := domain.BookingOccasion(c.Query("occasion")); occasion != "" {
+		if !slices.Contains(domain.BookingOccasions, occasion) {
+			return filter, fmt.Errorf("%s: unknown occasion %q", common.ErrInvalidParams, occasion)
+		}
+		filter.Occasion = occasion
+	}
+
+	if dateStr := c.Query("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return filter, fmt.Errorf("%s: invalid date %q", common.ErrInvalidParams, dateStr)
+		}
+		filter.Date = &date
+	}
+
+	return filter, nil
 }
@@ -0,0 +1,444 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type RestaurantClaimHandler struct {
+	restaurantClaimUseCase usecase.RestaurantClaimUseCase
+}
+
+func NewRestaurantClaimHandler(restaurantClaimUseCase usecase.RestaurantClaimUseCase) *RestaurantClaimHandler {
+	return &RestaurantClaimHandler{
+		restaurantClaimUseCase: restaurantClaimUseCase,
+	}
+}
+
+type SubmitRestaurantClaimRequest struct {
+	// Proof tells the reviewing admin why the user owns the restaurant, such as a registration
+	// number or a link to documents.
+	Proof string `json:"proof"`
+}
+
+type ReviewRestaurantClaimRequest struct {
+	Note string `json:"note"`
+}
+
+type RestaurantClaimResponse struct {
+	ID           string                       `json:"id"`
+	RestaurantID string                       `json:"restaurant_id"`
+	UserID       string                       `json:"user_id"`
+	Proof        string                       `json:"proof"`
+	Status       domain.RestaurantClaimStatus `json:"status"`
+	ReviewerID   string                       `json:"reviewer_id,omitempty"`
+	ReviewNote   string                       `json:"review_note,omitempty"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	DecidedAt    *time.Time                   `json:"decided_at,omitempty"`
+}
+
+func newRestaurantClaimResponse(claim *domain.RestaurantClaim) RestaurantClaimResponse {
+	return RestaurantClaimResponse{
+		ID:           claim.ID,
+		RestaurantID: claim.RestaurantID,
+		UserID:       claim.UserID,
+		Proof:        claim.Proof,
+		Status:       claim.Status,
+		ReviewerID:   claim.ReviewerID,
+		ReviewNote:   claim.ReviewNote,
+		CreatedAt:    claim.CreatedAt,
+		DecidedAt:    claim.DecidedAt,
+	}
+}
+
+type RestaurantOwnerResponse struct {
+	OwnerID string    `json:"owner_id"`
+	ClaimID string    `json:"claim_id"`
+	Since   time.Time `json:"since"`
+}
+
+type OwnershipAuditRecordResponse struct {
+	ID         string    `json:"id"`
+	ClaimID    string    `json:"claim_id"`
+	OwnerID    string    `json:"owner_id"`
+	ApprovedBy string    `json:"approved_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func newOwnershipAuditRecordResponse(record *domain.OwnershipAuditRecord) OwnershipAuditRecordResponse {
+	return OwnershipAuditRecordResponse{
+		ID:         record.ID,
+		ClaimID:    record.ClaimID,
+		OwnerID:    record.OwnerID,
+		ApprovedBy: record.ApprovedBy,
+		CreatedAt:  record.CreatedAt,
+	}
+}
+
+type RestaurantOwnershipResponse struct {
+	RestaurantID string `json:"restaurant_id"`
+	// Owner is null while the platform holds the restaurant.
+	Owner *RestaurantOwnerResponse       `json:"owner"`
+	Audit []OwnershipAuditRecordResponse `json:"audit"`
+}
+
+// SubmitRestaurantClaim godoc
+// @Summary Claim a restaurant
+// @Description Ask for a restaurant profile an admin imported to be handed over to the user of the request, who owns the restaurant. An admin reviews the proof; the user is notified of the decision
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param claim body SubmitRestaurantClaimRequest true "Proof of ownership, up to 2000 characters"
+// @Success 201 {object} RestaurantClaimResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "The restaurant has an owner, or the user's claim of it is pending"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/claims [post]
+func (h *RestaurantClaimHandler) SubmitRestaurantClaim(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request SubmitRestaurantClaimRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	claim := &domain.RestaurantClaim{
+		RestaurantID: restaurantID,
+		Proof:        request.Proof,
+	}
+	if err := h.restaurantClaimUseCase.SubmitClaim(ctx, claim); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidRestaurantClaim):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		case err.Error() == common.ErrRestaurantAlreadyOwned, err.Error() == common.ErrRestaurantClaimExists:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateRestaurantClaim, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newRestaurantClaimResponse(claim))
+}
+
+// GetRestaurantClaim godoc
+// @Summary Get a restaurant claim
+// @Description The claim with its review. The claimant and admins only
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Success 200 {object} RestaurantClaimResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Claim not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurant-claims/{id} [get]
+func (h *RestaurantClaimHandler) GetRestaurantClaim(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	claim, err := h.restaurantClaimUseCase.GetClaim(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantClaimNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantClaimNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurantClaim, zap.String("id", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newRestaurantClaimResponse(claim))
+}
+
+// ListRestaurantClaims godoc
+// @Summary List restaurant claims
+// @Description The claims of restaurant profiles, oldest first, to review the pending ones. Admins only
+// @Tags admin
+// @Produce json
+// @Param status query string false "pending, approved or rejected"
+// @Param restaurant_id query string false "Restaurant ID"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} RestaurantClaimResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurant-claims [get]
+func (h *RestaurantClaimHandler) ListRestaurantClaims(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	filter := domain.RestaurantClaimFilter{
+		RestaurantID: c.Query("restaurant_id"),
+		Status:       domain.RestaurantClaimStatus(c.Query("status")),
+	}
+	claims, err := h.restaurantClaimUseCase.ListClaims(ctx, filter, offset, lookaheadLimit(limit))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidRestaurantClaim):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListRestaurantClaims, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	claims, hasNext := trimPage(claims, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(claims),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(claims, newRestaurantClaimResponse))
+}
+
+// ApproveRestaurantClaim godoc
+// @Summary Approve a restaurant claim
+// @Description Make the claimant the owner of the restaurant and record the hand-over in the ownership audit. The other pending claims of the restaurant are rejected; every claimant is notified. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Param review body ReviewRestaurantClaimRequest false "Optional note"
+// @Success 200 {object} RestaurantClaimResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Claim not found"
+// @Failure 409 {object} map[string]string "Already decided, or the restaurant has an owner"
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurant-claims/{id}/approve [post]
+func (h *RestaurantClaimHandler) ApproveRestaurantClaim(c fiber.Ctx) error {
+	return h.review(c, h.restaurantClaimUseCase.ApproveClaim)
+}
+
+// RejectRestaurantClaim godoc
+// @Summary Reject a restaurant claim
+// @Description Turn the claim down; the claimant is notified with the note. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Param review body ReviewRestaurantClaimRequest false "Note telling the claimant why"
+// @Success 200 {object} RestaurantClaimResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Claim not found"
+// @Failure 409 {object} map[string]string "Already decided"
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurant-claims/{id}/reject [post]
+func (h *RestaurantClaimHandler) RejectRestaurantClaim(c fiber.Ctx) error {
+	return h.review(c, h.restaurantClaimUseCase.RejectClaim)
+}
+
+// review decides the claim of the request with decide.
+func (h *RestaurantClaimHandler) review(c fiber.Ctx, decide func(ctx context.Context, id, note string) (*domain.RestaurantClaim, error)) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request ReviewRestaurantClaimRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&request); err != nil {
+			log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidParams,
+			})
+		}
+	}
+
+	claim, err := decide(ctx, id, request.Note)
+	if err != nil {
+		return h.reviewError(ctx, log, c, id, err)
+	}
+
+	return c.JSON(newRestaurantClaimResponse(claim))
+}
+
+// reviewError answers a failure to approve or reject a claim.
+func (h *RestaurantClaimHandler) reviewError(ctx context.Context, log ports.LoggerPort, c fiber.Ctx, id string, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRestaurantClaim):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRestaurantClaimDecided), err.Error() == common.ErrRestaurantAlreadyOwned:
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, tenant.ErrAccessDenied):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": common.ErrAccessDenied,
+		})
+	case err.Error() == common.ErrRestaurantClaimNotFound:
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantClaimNotFound,
+		})
+	}
+
+	log.Error(ctx, common.ErrUpdateRestaurantClaim, zap.String("id", id), zap.Error(err))
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": common.ErrInternalServer,
+	})
+}
+
+// GetRestaurantOwnership godoc
+// @Summary Get the owner of a restaurant
+// @Description The owner the restaurant was handed over to, null while the platform holds it, and the audit of its hand-overs, newest first. Admins only
+// @Tags admin
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} RestaurantOwnershipResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /admin/restaurants/{id}/ownership [get]
+func (h *RestaurantClaimHandler) GetRestaurantOwnership(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	report, err := h.restaurantClaimUseCase.GetOwnership(ctx, restaurantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetRestaurantOwner, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	response := RestaurantOwnershipResponse{
+		RestaurantID: restaurantID,
+		Audit:        mapResponses(report.Audit, newOwnershipAuditRecordResponse),
+	}
+	if report.Owner != nil {
+		response.Owner = &RestaurantOwnerResponse{
+			OwnerID: report.Owner.OwnerID,
+			ClaimID: report.Owner.ClaimID,
+			Since:   report.Owner.Since,
+		}
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type RestaurantClosureHandler struct {
+	restaurantClosureUseCase usecase.RestaurantClosureUseCase
+}
+
+func NewRestaurantClosureHandler(restaurantClosureUseCase usecase.RestaurantClosureUseCase) *RestaurantClosureHandler {
+	return &RestaurantClosureHandler{
+		restaurantClosureUseCase: restaurantClosureUseCase,
+	}
+}
+
+type CreateRestaurantClosureRequest struct {
+	// StartsOn is the first day the restaurant is closed and ReopensOn the day it opens again,
+	// YYYY-MM-DD.
+	StartsOn  string `json:"starts_on"`
+	ReopensOn string `json:"reopens_on"`
+	// Message is added to the reply to the guests booking during the closure.
+	Message string `json:"message"`
+}
+
+type RestaurantClosureResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	StartsOn     string    `json:"starts_on"`
+	ReopensOn    string    `json:"reopens_on"`
+	Message      string    `json:"message,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	// AutoReply is the reply to the guests booking the first day of the closure still ahead, only
+	// set when it is created.
+	AutoReply string `json:"auto_reply,omitempty"`
+}
+
+func newRestaurantClosureResponse(closure *domain.RestaurantClosure) RestaurantClosureResponse {
+	return RestaurantClosureResponse{
+		ID:           closure.ID,
+		RestaurantID: closure.RestaurantID,
+		StartsOn:     closure.StartsOn.Format("2006-01-02"),
+		ReopensOn:    closure.ReopensOn.Format("2006-01-02"),
+		Message:      closure.Message,
+		CreatedAt:    closure.CreatedAt,
+	}
+}
+
+// SisterRestaurantResponse is an open sister restaurant a closed restaurant refers its guests to.
+type SisterRestaurantResponse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Address string `json:"address"`
+}
+
+// RestaurantClosedResponse is the reply of a restaurant to a booking for a day it is closed.
+type RestaurantClosedResponse struct {
+	Error string `json:"error"`
+	// Message is the reply to show the guest.
+	Message           string                     `json:"message"`
+	ReopensOn         string                     `json:"reopens_on"`
+	SisterRestaurants []SisterRestaurantResponse `json:"sister_restaurants"`
+}
+
+func newRestaurantClosedResponse(closed *usecase.RestaurantClosedError) RestaurantClosedResponse {
+	return RestaurantClosedResponse{
+		Error:     common.ErrRestaurantClosed,
+		Message:   closed.Reply,
+		ReopensOn: closed.Closure.ReopensOn.Format("2006-01-02"),
+		SisterRestaurants: mapResponses(closed.Sisters, func(restaurant *domain.Restaurant) SisterRestaurantResponse {
+			return SisterRestaurantResponse{
+				ID:      restaurant.ID,
+				Name:    restaurant.Name,
+				Slug:    restaurant.Slug,
+				Address: restaurant.Address,
+			}
+		}),
+	}
+}
+
+// CreateRestaurantClosure godoc
+// @Summary Close the restaurant for a period
+// @Description Close the restaurant, e.g. for a vacation, from starts_on up to reopens_on. Booking requests for these days are answered on its behalf with the day it reopens, its message and its open sister restaurants nearby, and the requests made during the closure don't count towards its response times. The response carries that reply as auto_reply; with dry_run=true the closure is made in a rolled back transaction and nothing is saved
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param closure body CreateRestaurantClosureRequest true "Period and message"
+// @Param dry_run query bool false "Preview the closure and its auto-reply without saving them"
+// @Success 200 {object} RestaurantClosureResponse "Dry run result"
+// @Success 201 {object} RestaurantClosureResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 409 {object} map[string]string "The closure overlaps another closure of the restaurant"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/closures [post]
+func (h *RestaurantClosureHandler) CreateRestaurantClosure(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	dryRun, err := dryRunRequested(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request CreateRestaurantClosureRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	startsOn, err := time.Parse("2006-01-02", request.StartsOn)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+	reopensOn, err := time.Parse("2006-01-02", request.ReopensOn)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	closure := &domain.RestaurantClosure{
+		RestaurantID: restaurantID,
+		StartsOn:     startsOn,
+		ReopensOn:    reopensOn,
+		Message:      request.Message,
+	}
+	reply, err := h.restaurantClosureUseCase.CreateClosure(ctx, closure, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEntity), errors.Is(err, usecase.ErrInvalidRestaurantClosure):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		case err.Error() == common.ErrRestaurantClosureOverlaps:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrRestaurantClosureOverlaps,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateRestaurantClosure, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	response := newRestaurantClosureResponse(closure)
+	response.AutoReply = reply
+	if dryRun {
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// ListRestaurantClosures godoc
+// @Summary List restaurant closures
+// @Description The closures of the restaurant that are not over, earliest first
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {array} RestaurantClosureResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/closures [get]
+func (h *RestaurantClosureHandler) ListRestaurantClosures(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	closures, err := h.restaurantClosureUseCase.ListClosures(ctx, restaurantID)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListRestaurantClosures, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(mapResponses(closures, newRestaurantClosureResponse))
+}
+
+// DeleteRestaurantClosure godoc
+// @Summary Delete a restaurant closure
+// @Description Open the restaurant again on the days of the closure
+// @Tags restaurants
+// @Param id path string true "Restaurant ID"
+// @Param closureId path string true "Closure ID"
+// @Success 204 "Deleted"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Closure not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/closures/{closureId} [delete]
+func (h *RestaurantClosureHandler) DeleteRestaurantClosure(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	closureID := c.Params("closureId")
+	if restaurantID == "" || closureID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.restaurantClosureUseCase.DeleteClosure(ctx, restaurantID, closureID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantClosureNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantClosureNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteRestaurantClosure, zap.String("closureID", closureID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type RetentionHandler struct {
+	retentionUseCase usecase.RetentionUseCase
+}
+
+func NewRetentionHandler(retentionUseCase usecase.RetentionUseCase) *RetentionHandler {
+	return &RetentionHandler{
+		retentionUseCase: retentionUseCase,
+	}
+}
+
+type RetentionRunResponse struct {
+	ID         string                 `json:"id"`
+	Policy     domain.RetentionPolicy `json:"policy"`
+	DryRun     bool                   `json:"dry_run"`
+	Cutoff     time.Time              `json:"cutoff"`
+	Affected   int                    `json:"affected"`
+	Error      string                 `json:"error,omitempty"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+}
+
+func newRetentionRunResponse(run *domain.RetentionRun) RetentionRunResponse {
+	return RetentionRunResponse{
+		ID:         run.ID,
+		Policy:     run.Policy,
+		DryRun:     run.DryRun,
+		Cutoff:     run.Cutoff,
+		Affected:   run.Affected,
+		Error:      run.Error,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	}
+}
+
+// ApplyRetention godoc
+// @Summary Apply retention policies
+// @Description Purge the notifications read before their retention period and anonymize the bookings completed before theirs, as the nightly job does. With dry_run=true nothing is changed and the runs report how many records would be affected. Every run is kept in the audit log. Admins only
+// @Tags admin
+// @Produce json
+// @Param dry_run query bool false "Only report what would be purged and anonymized"
+// @Success 200 {array} RetentionRunResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/retention/run [post]
+func (h *RetentionHandler) ApplyRetention(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	dryRun, err := dryRunRequested(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	runs, err := h.retentionUseCase.ApplyRetention(ctx, dryRun)
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrApplyRetention, zap.Bool("dryRun", dryRun), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrApplyRetention,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(mapResponses(runs, newRetentionRunResponse))
+}
+
+// ListRetentionRuns godoc
+// @Summary List retention runs
+// @Description The audit log of the retention policies applied, dry runs included, newest first. Admins only
+// @Tags admin
+// @Produce json
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} RetentionRunResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/retention/runs [get]
+func (h *RetentionHandler) ListRetentionRuns(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	runs, err := h.retentionUseCase.ListRetentionRuns(ctx, offset, lookaheadLimit(limit))
+	if err != nil {
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListRetentionRuns, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	runs, hasNext := trimPage(runs, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(runs),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(runs, newRetentionRunResponse))
+}
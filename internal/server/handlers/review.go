@@ -0,0 +1,546 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type ReviewHandler struct {
+	reviewUseCase usecase.ReviewUseCase
+}
+
+func NewReviewHandler(reviewUseCase usecase.ReviewUseCase) *ReviewHandler {
+	return &ReviewHandler{
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+type ReviewReplyRequest struct {
+	Text string `json:"text"`
+}
+
+type FlagReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ResolveReviewFlagRequest struct {
+	Status domain.ReviewFlagStatus `json:"status"`
+	Note   string                  `json:"note"`
+}
+
+type ReviewResponse struct {
+	ID           string               `json:"id"`
+	RestaurantID string               `json:"restaurant_id"`
+	BookingID    string               `json:"booking_id"`
+	Rating       int                  `json:"rating"`
+	Text         string               `json:"text"`
+	Status       domain.ReviewStatus  `json:"status"`
+	Reply        *ReviewReplyResponse `json:"reply,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+type ReviewReplyResponse struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReviewFlagResponse struct {
+	ID             string                  `json:"id"`
+	ReviewID       string                  `json:"review_id"`
+	RestaurantID   string                  `json:"restaurant_id"`
+	Reason         string                  `json:"reason"`
+	Status         domain.ReviewFlagStatus `json:"status"`
+	ResolutionNote string                  `json:"resolution_note,omitempty"`
+	Review         *ReviewResponse         `json:"review,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	ResolvedAt     *time.Time              `json:"resolved_at,omitempty"`
+}
+
+// newReviewResponse leaves out the author; reviews are public and only name the booking.
+func newReviewResponse(review *domain.Review) ReviewResponse {
+	response := ReviewResponse{
+		ID:           review.ID,
+		RestaurantID: review.RestaurantID,
+		BookingID:    review.BookingID,
+		Rating:       review.Rating,
+		Text:         review.Text,
+		Status:       review.Status,
+		CreatedAt:    review.CreatedAt,
+		UpdatedAt:    review.UpdatedAt,
+	}
+	if review.Reply != nil {
+		response.Reply = &ReviewReplyResponse{
+			Text:      review.Reply.Text,
+			CreatedAt: review.Reply.CreatedAt,
+			UpdatedAt: review.Reply.UpdatedAt,
+		}
+	}
+	return response
+}
+
+func newReviewFlagResponse(flag *domain.ReviewFlag) ReviewFlagResponse {
+	response := ReviewFlagResponse{
+		ID:             flag.ID,
+		ReviewID:       flag.ReviewID,
+		RestaurantID:   flag.RestaurantID,
+		Reason:         flag.Reason,
+		Status:         flag.Status,
+		ResolutionNote: flag.ResolutionNote,
+		CreatedAt:      flag.CreatedAt,
+		ResolvedAt:     flag.ResolvedAt,
+	}
+	if flag.Review != nil {
+		review := newReviewResponse(flag.Review)
+		response.Review = &review
+	}
+	return response
+}
+
+// CreateReview godoc
+// @Summary Review a booking
+// @Description Publish the review of a completed booking; only the guest who made the booking can review it, once
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param review body CreateReviewRequest true "Rating from 1 to 5 and text"
+// @Success 201 {object} ReviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Booking not found"
+// @Failure 409 {object} map[string]string "Booking is not completed or already reviewed"
+// @Failure 500 {object} map[string]string
+// @Router /bookings/{id}/review [post]
+func (h *ReviewHandler) CreateReview(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseBookingID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var request CreateReviewRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	review, err := h.reviewUseCase.CreateReview(ctx, id, request.Rating, request.Text)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReview) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, usecase.ErrReviewNotAllowed) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if err.Error() == common.ErrReviewExists {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrReviewExists,
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		// the repository wraps the not found error with the message as prefix
+		if strings.HasPrefix(err.Error(), common.ErrBookingNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrBookingNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateReview, zap.String("bookingID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newReviewResponse(review))
+}
+
+// ListReviews godoc
+// @Summary List restaurant reviews
+// @Description Get the published reviews of a restaurant, newest first, with the replies of the restaurant
+// @Tags restaurants
+// @Produce json,xml,application/msgpack
+// @Param id path string true "Restaurant ID"
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} ReviewResponse
+// @Success 304 {string} string "Not modified since the cached copy"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/reviews [get]
+func (h *ReviewHandler) ListReviews(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return respond(c, fiber.StatusBadRequest, fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	reviews, err := h.reviewUseCase.ListReviews(ctx, id, offset, lookaheadLimit(limit))
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return respond(c, fiber.StatusNotFound, fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrListReviews, zap.String("restaurantID", id), zap.Error(err))
+		return respond(c, fiber.StatusInternalServerError, fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	reviews, hasNext := trimPage(reviews, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(reviews),
+		HasNext: hasNext,
+	})
+	lastModified := latestUpdate(reviews, func(review *domain.Review) time.Time {
+		return review.UpdatedAt
+	})
+	return respondCacheable(c, lastModified, mapResponses(reviews, newReviewResponse))
+}
+
+// ReplyToReview godoc
+// @Summary Reply to a review
+// @Description Set the public reply of the restaurant to one of its reviews, replacing any earlier reply. The guest is notified of the first reply
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param reviewId path string true "Review ID"
+// @Param reply body ReviewReplyRequest true "Reply text"
+// @Success 200 {object} ReviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/reviews/{reviewId}/reply [put]
+func (h *ReviewHandler) ReplyToReview(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	reviewID := c.Params("reviewId")
+	if restaurantID == "" || reviewID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request ReviewReplyRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	review, err := h.reviewUseCase.ReplyToReview(ctx, restaurantID, reviewID, request.Text)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReview) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		if err.Error() == common.ErrReviewNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrReviewNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrReplyToReview, zap.String("reviewID", reviewID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newReviewResponse(review))
+}
+
+// FlagReview godoc
+// @Summary Flag a review
+// @Description Ask the admins to arbitrate an abusive review of the restaurant. A review can have one open flag at a time
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param reviewId path string true "Review ID"
+// @Param flag body FlagReviewRequest true "Why the review is abusive"
+// @Success 201 {object} ReviewFlagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 409 {object} map[string]string "Review already has an open flag"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/reviews/{reviewId}/flag [post]
+func (h *ReviewHandler) FlagReview(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	reviewID := c.Params("reviewId")
+	if restaurantID == "" || reviewID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request FlagReviewRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	flag, err := h.reviewUseCase.FlagReview(ctx, restaurantID, reviewID, request.Reason)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReviewFlag) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		switch err.Error() {
+		case common.ErrReviewNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrReviewNotFound,
+			})
+		case common.ErrReviewAlreadyFlagged:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrReviewAlreadyFlagged,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateReviewFlag, zap.String("reviewID", reviewID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newReviewFlagResponse(flag))
+}
+
+// ListReviewFlags godoc
+// @Summary List review flags
+// @Description Get the review flags with the status, oldest first, with the flagged reviews. Admins only
+// @Tags admin
+// @Produce json
+// @Param status query string false "Flag status: open, upheld or dismissed" default(open)
+// @Param offset query int false "Offset" default(0)
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {array} ReviewFlagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/review-flags [get]
+func (h *ReviewHandler) ListReviewFlags(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	offset, err := strconv.Atoi(c.Query("offset", "0"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	status := domain.ReviewFlagStatus(c.Query("status"))
+	flags, err := h.reviewUseCase.ListReviewFlags(ctx, status, offset, lookaheadLimit(limit))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReviewFlag) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListReviewFlags, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	flags, hasNext := trimPage(flags, limit)
+	middleware.SetPagination(c, middleware.Pagination{
+		Offset:  offset,
+		Limit:   limit,
+		Count:   len(flags),
+		HasNext: hasNext,
+	})
+	return c.Status(fiber.StatusOK).JSON(mapResponses(flags, newReviewFlagResponse))
+}
+
+// ResolveReviewFlag godoc
+// @Summary Resolve a review flag
+// @Description Settle an open review flag. Upholding it removes the review; dismissing it keeps the review published. The restaurant, and the guest when the review is removed, are notified. Admins only
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Param resolution body ResolveReviewFlagRequest true "upheld or dismissed, with an optional note"
+// @Success 200 {object} ReviewFlagResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Review flag not found"
+// @Failure 409 {object} map[string]string "Review flag already resolved"
+// @Failure 500 {object} map[string]string
+// @Router /admin/review-flags/{id}/resolve [post]
+func (h *ReviewHandler) ResolveReviewFlag(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id := c.Params("id")
+	if id == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request ResolveReviewFlagRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	flag, err := h.reviewUseCase.ResolveReviewFlag(ctx, id, request.Status, request.Note)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidReviewFlag) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		if errors.Is(err, usecase.ErrReviewFlagResolved) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrReviewFlagResolved,
+			})
+		}
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		if err.Error() == common.ErrReviewFlagNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrReviewFlagNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrResolveReviewFlag, zap.String("flagID", id), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newReviewFlagResponse(flag))
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const defaultSLODays = 30
+
+type SLOHandler struct {
+	sloUseCase usecase.SLOUseCase
+}
+
+func NewSLOHandler(sloUseCase usecase.SLOUseCase) *SLOHandler {
+	return &SLOHandler{
+		sloUseCase: sloUseCase,
+	}
+}
+
+// SLOObjectiveResponse is the compliance with one objective. BudgetRemaining is the share of the
+// error budget left, negative when the objective was missed.
+type SLOObjectiveResponse struct {
+	Target          float64 `json:"target"`
+	Achieved        float64 `json:"achieved"`
+	BurnRate        float64 `json:"burn_rate"`
+	BudgetRemaining float64 `json:"budget_remaining"`
+	Met             bool    `json:"met"`
+}
+
+type SLOReportResponse struct {
+	Date               string               `json:"date"`
+	Requests           int64                `json:"requests"`
+	Errors             int64                `json:"errors"`
+	Slow               int64                `json:"slow"`
+	LatencyThresholdMS int64                `json:"latency_threshold_ms"`
+	Availability       SLOObjectiveResponse `json:"availability"`
+	Latency            SLOObjectiveResponse `json:"latency"`
+}
+
+func newSLOReportResponse(report *domain.SLOReport) SLOReportResponse {
+	return SLOReportResponse{
+		Date:               report.Date.Format("2006-01-02"),
+		Requests:           report.Requests,
+		Errors:             report.Errors,
+		Slow:               report.Slow,
+		LatencyThresholdMS: report.Objectives.LatencyThreshold.Milliseconds(),
+		Availability: SLOObjectiveResponse{
+			Target:          report.Objectives.AvailabilityTarget,
+			Achieved:        report.Availability,
+			BurnRate:        report.AvailabilityBurnRate,
+			BudgetRemaining: 1 - report.AvailabilityBurnRate,
+			Met:             report.AvailabilityMet(),
+		},
+		Latency: SLOObjectiveResponse{
+			Target:          report.Objectives.LatencyTarget,
+			Achieved:        report.LatencyCompliance,
+			BurnRate:        report.LatencyBurnRate,
+			BudgetRemaining: 1 - report.LatencyBurnRate,
+			Met:             report.LatencyMet(),
+		},
+	}
+}
+
+type SLOPeriodResponse struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Summary SLOReportResponse   `json:"summary"`
+	Days    []SLOReportResponse `json:"days"`
+}
+
+// GetSLOReports godoc
+// @Summary Get SLO reports
+// @Description The daily reports of the availability and latency objectives over the last days, yesterday included, and their sum over the period measured against the current objectives: the share of requests answered without a server error and within the latency threshold, how many times faster than sustainable the error budget was spent, and the share of the budget left. Days without a report are left out. Admins only
+// @Tags admin
+// @Produce json
+// @Param days query int false "Past days, up to 366" default(30)
+// @Success 200 {object} SLOPeriodResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/slo [get]
+func (h *SLOHandler) GetSLOReports(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	days, err := strconv.Atoi(c.Query("days", strconv.Itoa(defaultSLODays)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	to := time.Now().AddDate(0, 0, -1)
+	period, err := h.sloUseCase.GetReports(ctx, to.AddDate(0, 0, 1-days), to)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidSLOPeriod):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
+
+		log.Error(ctx, common.ErrListSLOReports, zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SLOPeriodResponse{
+		From:    period.From.Format("2006-01-02"),
+		To:      period.To.Format("2006-01-02"),
+		Summary: newSLOReportResponse(period.Summary),
+		Days:    mapResponses(period.Reports, newSLOReportResponse),
+	})
+}
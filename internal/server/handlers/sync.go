@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type SyncHandler struct {
+	syncUseCase usecase.SyncUseCase
+}
+
+func NewSyncHandler(syncUseCase usecase.SyncUseCase) *SyncHandler {
+	return &SyncHandler{
+		syncUseCase: syncUseCase,
+	}
+}
+
+type SyncChangeResponse struct {
+	ID        string          `json:"id"`
+	ChangedAt time.Time       `json:"changed_at"`
+	Deleted   bool            `json:"deleted"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+func newSyncChangeResponse(change *domain.SyncChange) SyncChangeResponse {
+	return SyncChangeResponse{
+		ID:        change.ID,
+		ChangedAt: change.ChangedAt,
+		Deleted:   change.Deleted,
+		Data:      change.Data,
+	}
+}
+
+type SyncResponse struct {
+	Entity    domain.ExportEntity  `json:"entity"`
+	Changes   []SyncChangeResponse `json:"changes"`
+	NextSince string               `json:"next_since"`
+	HasMore   bool                 `json:"has_more"`
+}
+
+// ListSyncChanges godoc
+// @Summary List changes of an entity
+// @Description Records of the entity changed after the cursor, oldest change first, with their stored row as data, and tombstones marked deleted for records deleted since. Pass next_since as since to get the next page, and again later to get what changed in between; has_more tells whether to ask right away. since also accepts an RFC 3339 time. Admins only
+// @Tags sync
+// @Produce json
+// @Param entity path string true "Entity: restaurants, users, availability, bookings, menu_items, reviews or review_replies"
+// @Param since query string false "Cursor of the previous page or RFC 3339 time, from the start by default"
+// @Param limit query int false "Limit" default(100)
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/sync/{entity} [get]
+func (h *SyncHandler) ListSyncChanges(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	entity := domain.ExportEntity(c.Params("entity"))
+
+	since, err := domain.ParseSyncCursor(c.Query("since"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidSyncCursor,
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(usecase.DefaultSyncLimit)))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	page, err := h.syncUseCase.ListChanges(ctx, entity, since, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case errors.Is(err, usecase.ErrInvalidExportEntity):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrListSyncChanges, zap.String("entity", string(entity)), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(SyncResponse{
+		Entity:    entity,
+		Changes:   mapResponses(page.Changes, newSyncChangeResponse),
+		NextSince: page.Next.String(),
+		HasMore:   page.HasMore,
+	})
+}
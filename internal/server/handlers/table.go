@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type TableHandler struct {
+	tableUseCase usecase.TableUseCase
+}
+
+func NewTableHandler(tableUseCase usecase.TableUseCase) *TableHandler {
+	return &TableHandler{
+		tableUseCase: tableUseCase,
+	}
+}
+
+type TableRequest struct {
+	Name  string `json:"name"`
+	Seats int    `json:"seats"`
+	// Zone is the part of the floor the table is in, such as the main hall or the terrace.
+	Zone      string `json:"zone"`
+	IsSmoking bool   `json:"is_smoking"`
+	IsOutdoor bool   `json:"is_outdoor"`
+	// IsAccessible marks a table a wheelchair can reach.
+	IsAccessible bool `json:"is_accessible"`
+}
+
+type TableResponse struct {
+	ID           string    `json:"id"`
+	RestaurantID string    `json:"restaurant_id"`
+	Name         string    `json:"name"`
+	Seats        int       `json:"seats"`
+	Zone         string    `json:"zone,omitempty"`
+	IsSmoking    bool      `json:"is_smoking"`
+	IsOutdoor    bool      `json:"is_outdoor"`
+	IsAccessible bool      `json:"is_accessible"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func newTableResponse(table *domain.Table) TableResponse {
+	return TableResponse{
+		ID:           table.ID,
+		RestaurantID: table.RestaurantID,
+		Name:         table.Name,
+		Seats:        table.Seats,
+		Zone:         table.Zone,
+		IsSmoking:    table.IsSmoking,
+		IsOutdoor:    table.IsOutdoor,
+		IsAccessible: table.IsAccessible,
+		CreatedAt:    table.CreatedAt,
+		UpdatedAt:    table.UpdatedAt,
+	}
+}
+
+// CreateTable godoc
+// @Summary Add a table to the floor plan
+// @Description Add a table to the floor plan of the restaurant. Once a restaurant has tables, every new booking is seated at one of them: the table the guest asked for or the smallest free table the party fits at
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param table body TableRequest true "Table"
+// @Success 201 {object} TableResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Restaurant not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/tables [post]
+func (h *TableHandler) CreateTable(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request TableRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	table := request.table(restaurantID, "")
+	if err := h.tableUseCase.CreateTable(ctx, table); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEntity):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrCreateTable, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(newTableResponse(table))
+}
+
+// ListTables godoc
+// @Summary List restaurant tables
+// @Description The floor plan of the restaurant, ordered by zone and name, for guests to ask for a table when booking
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {array} TableResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/tables [get]
+func (h *TableHandler) ListTables(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	tables, err := h.tableUseCase.ListTables(ctx, restaurantID)
+	if err != nil {
+		log.Error(ctx, common.ErrListTables, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(mapResponses(tables, newTableResponse))
+}
+
+// GetTable godoc
+// @Summary Get a restaurant table
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param tableId path string true "Table ID"
+// @Success 200 {object} TableResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "Table not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/tables/{tableId} [get]
+func (h *TableHandler) GetTable(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	tableID := c.Params("tableId")
+	if restaurantID == "" || tableID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	table, err := h.tableUseCase.GetTable(ctx, restaurantID, tableID)
+	if err != nil {
+		if err.Error() == common.ErrTableNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrTableNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetTable, zap.String("tableID", tableID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newTableResponse(table))
+}
+
+// UpdateTable godoc
+// @Summary Update a restaurant table
+// @Description Replace the name, seats, zone and flags of the table. Bookings seated at it keep it
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param tableId path string true "Table ID"
+// @Param table body TableRequest true "Table"
+// @Success 200 {object} TableResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Table not found"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/tables/{tableId} [put]
+func (h *TableHandler) UpdateTable(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	tableID := c.Params("tableId")
+	if restaurantID == "" || tableID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request TableRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	table := request.table(restaurantID, tableID)
+	if err := h.tableUseCase.UpdateTable(ctx, table); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidEntity):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrTableNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrTableNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateTable, zap.String("tableID", tableID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(newTableResponse(table))
+}
+
+// DeleteTable godoc
+// @Summary Delete a restaurant table
+// @Description Remove the table from the floor plan. Past bookings seated at it are kept without it
+// @Tags restaurants
+// @Param id path string true "Restaurant ID"
+// @Param tableId path string true "Table ID"
+// @Success 204 "Deleted"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string "Table not found"
+// @Failure 409 {object} map[string]string "Pending or confirmed bookings from today on are seated at the table"
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/tables/{tableId} [delete]
+func (h *TableHandler) DeleteTable(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	tableID := c.Params("tableId")
+	if restaurantID == "" || tableID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	if err := h.tableUseCase.DeleteTable(ctx, restaurantID, tableID); err != nil {
+		switch {
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrTableNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrTableNotFound,
+			})
+		case err.Error() == common.ErrTableBooked:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": common.ErrTableBooked,
+			})
+		}
+
+		log.Error(ctx, common.ErrDeleteTable, zap.String("tableID", tableID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// table returns the table of the restaurant the request describes.
+func (r TableRequest) table(restaurantID, tableID string) *domain.Table {
+	return &domain.Table{
+		ID:           tableID,
+		RestaurantID: restaurantID,
+		Name:         r.Name,
+		Seats:        r.Seats,
+		Zone:         r.Zone,
+		IsSmoking:    r.IsSmoking,
+		IsOutdoor:    r.IsOutdoor,
+		IsAccessible: r.IsAccessible,
+	}
+}
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"errors"
+	"time"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
@@ -35,6 +38,26 @@ type CreateUserRequest struct {
 	Phone string `json:"phone" validate:"required"`
 }
 
+type UserResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newUserResponse(user *domain.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
 // CreateUser godoc
 // @Summary Create user
 // @Description Create a new user
@@ -42,7 +65,7 @@ type CreateUserRequest struct {
 // @Accept json
 // @Produce json
 // @Param user body CreateUserRequest true "User data"
-// @Success 201 {object} domain.User
+// @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string "Invalid data"
 // @Failure 409 {object} map[string]string "Email already exists"
 // @Failure 500 {object} map[string]string
@@ -78,6 +101,12 @@ func (h *UserHandler) CreateUser(c fiber.Ctx) error {
 			})
 		}
 
+		if errors.Is(err, contact.ErrInvalidEmail) || errors.Is(err, contact.ErrInvalidPhone) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
 		log.Error(ctx, common.ErrCreateUserHandler, zap.Error(err))
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -97,7 +126,7 @@ func (h *UserHandler) CreateUser(c fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {object} domain.User
+// @Success 200 {object} UserResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string
@@ -110,16 +139,16 @@ func (h *UserHandler) GetUser(c fiber.Ctx) error {
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
+	id, err := domain.ParseUserID(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+			"error": err.Error(),
 		})
 	}
 
 	user, err := h.userUseCase.GetUser(ctx, id)
 	if err != nil {
-		log.Error(ctx, common.ErrGetUserHandler, zap.String("id", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetUserHandler, zap.String("id", id.String()), zap.Error(err))
 
 		if err.Error() == common.ErrUserNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -138,7 +167,7 @@ func (h *UserHandler) GetUser(c fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(user)
+	return c.Status(fiber.StatusOK).JSON(newUserResponse(user))
 }
 
 type UpdateUserRequest struct {
@@ -155,7 +184,7 @@ type UpdateUserRequest struct {
 // @Produce json
 // @Param id path string true "User ID"
 // @Param user body UpdateUserRequest true "User data"
-// @Success 200 {object} domain.User
+// @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string "Invalid data"
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 409 {object} map[string]string "Email already exists"
@@ -169,10 +198,10 @@ func (h *UserHandler) UpdateUser(c fiber.Ctx) error {
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
+	id, err := domain.ParseUserID(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+			"error": err.Error(),
 		})
 	}
 
@@ -186,7 +215,7 @@ func (h *UserHandler) UpdateUser(c fiber.Ctx) error {
 	}
 
 	user := &domain.User{
-		ID:    id,
+		ID:    id.String(),
 		Name:  request.Name,
 		Email: request.Email,
 		Phone: request.Phone,
@@ -205,7 +234,13 @@ func (h *UserHandler) UpdateUser(c fiber.Ctx) error {
 			})
 		}
 
-		log.Error(ctx, common.ErrUpdateUserHandler, zap.String("id", id), zap.Error(err))
+		if errors.Is(err, contact.ErrInvalidEmail) || errors.Is(err, contact.ErrInvalidPhone) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		log.Error(ctx, common.ErrUpdateUserHandler, zap.String("id", id.String()), zap.Error(err))
 
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": common.ErrInternalServer,
@@ -219,12 +254,14 @@ func (h *UserHandler) UpdateUser(c fiber.Ctx) error {
 
 // GetUserBookings godoc
 // @Summary Get user bookings
-// @Description Get all bookings of a user
+// @Description Get a page of the bookings of a user, the latest first
 // @Tags users,bookings
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {array} domain.Booking
+// @Param cursor query string false "X-Next-Cursor header of the previous page, from the latest booking by default"
+// @Param limit query int false "Limit, up to 100" default(20)
+// @Success 200 {array} BookingResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string
@@ -237,16 +274,35 @@ func (h *UserHandler) GetUserBookings(c fiber.Ctx) error {
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
+	id, err := domain.ParseUserID(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+			"error": err.Error(),
 		})
 	}
 
-	bookings, err := h.bookingUseCase.GetUserBookings(ctx, id)
+	request, err := parsePageRequest(c)
 	if err != nil {
-		log.Error(ctx, common.ErrGetUserBookings, zap.String("userID", id), zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	page, err := h.bookingUseCase.GetUserBookings(ctx, id, request)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidPageCursor) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": common.ErrInvalidPageCursor,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetUserBookings, zap.String("userID", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
 
 		if err.Error() == common.ErrUserNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -259,7 +315,8 @@ func (h *UserHandler) GetUserBookings(c fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(bookings)
+	setPage(c, request, page)
+	return c.Status(fiber.StatusOK).JSON(mapResponses(page.Items, newBookingResponse))
 }
 
 // GetUserNotifications godoc
@@ -269,7 +326,7 @@ func (h *UserHandler) GetUserBookings(c fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Success 200 {array} domain.Notification
+// @Success 200 {array} NotificationResponse
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string "User not found"
 // @Failure 500 {object} map[string]string
@@ -282,16 +339,22 @@ func (h *UserHandler) GetUserNotifications(c fiber.Ctx) error {
 		})
 	}
 
-	id := c.Params("id")
-	if id == "" {
+	id, err := domain.ParseUserID(c.Params("id"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": common.ErrInvalidParams,
+			"error": err.Error(),
 		})
 	}
 
-	notifications, err := h.notificationUseCase.GetUserNotifications(ctx, id)
+	notifications, err := h.notificationUseCase.GetUserNotifications(ctx, id.String())
 	if err != nil {
-		log.Error(ctx, common.ErrGetUserNotifications, zap.String("userID", id), zap.Error(err))
+		log.Error(ctx, common.ErrGetUserNotifications, zap.String("userID", id.String()), zap.Error(err))
+
+		if errors.Is(err, tenant.ErrAccessDenied) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		}
 
 		if err.Error() == common.ErrUserNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -304,5 +367,5 @@ func (h *UserHandler) GetUserNotifications(c fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(notifications)
+	return c.Status(fiber.StatusOK).JSON(mapResponses(notifications, newNotificationResponse))
 }
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+type WidgetSettingsHandler struct {
+	widgetSettingsUseCase usecase.WidgetSettingsUseCase
+	restaurantUseCase     usecase.RestaurantUseCase
+	publicURL             string
+}
+
+func NewWidgetSettingsHandler(
+	widgetSettingsUseCase usecase.WidgetSettingsUseCase,
+	restaurantUseCase usecase.RestaurantUseCase,
+	publicURL string,
+) *WidgetSettingsHandler {
+	return &WidgetSettingsHandler{
+		widgetSettingsUseCase: widgetSettingsUseCase,
+		restaurantUseCase:     restaurantUseCase,
+		publicURL:             strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// UpdateWidgetSettingsRequest replaces the widget settings; the fields left out take their
+// defaults.
+type UpdateWidgetSettingsRequest struct {
+	Theme            WidgetThemeRequest `json:"theme"`
+	DefaultPartySize int                `json:"default_party_size"`
+	Locale           string             `json:"locale"`
+}
+
+// WidgetThemeRequest holds colors as #rgb or #rrggbb.
+type WidgetThemeRequest struct {
+	PrimaryColor    string `json:"primary_color"`
+	BackgroundColor string `json:"background_color"`
+	TextColor       string `json:"text_color"`
+}
+
+type WidgetSettingsResponse struct {
+	RestaurantID     string             `json:"restaurant_id"`
+	Theme            domain.WidgetTheme `json:"theme"`
+	DefaultPartySize int                `json:"default_party_size"`
+	Locale           string             `json:"locale"`
+	UpdatedAt        *time.Time         `json:"updated_at,omitempty"`
+}
+
+// WidgetConfigResponse is what the embeddable script needs to draw the widget of a restaurant.
+type WidgetConfigResponse struct {
+	Restaurant       EmbedRestaurantResponse `json:"restaurant"`
+	Theme            domain.WidgetTheme      `json:"theme"`
+	DefaultPartySize int                     `json:"default_party_size"`
+	Locale           string                  `json:"locale"`
+	AvailabilityURL  string                  `json:"availability_url"`
+	BookingURL       string                  `json:"booking_url"`
+}
+
+func newWidgetSettingsResponse(settings *domain.WidgetSettings) WidgetSettingsResponse {
+	response := WidgetSettingsResponse{
+		RestaurantID:     settings.RestaurantID,
+		Theme:            settings.Theme,
+		DefaultPartySize: settings.DefaultPartySize,
+		Locale:           settings.Locale,
+	}
+	if !settings.UpdatedAt.IsZero() {
+		response.UpdatedAt = &settings.UpdatedAt
+	}
+	return response
+}
+
+// GetWidgetSettings godoc
+// @Summary Get widget settings
+// @Description The settings of the booking widget of the restaurant; the defaults until the restaurant saves its own
+// @Tags restaurants
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} WidgetSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/widget-settings [get]
+func (h *WidgetSettingsHandler) GetWidgetSettings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	settings, err := h.widgetSettingsUseCase.GetWidgetSettings(ctx, restaurantID)
+	if err != nil {
+		if err.Error() == common.ErrRestaurantNotFound {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrGetWidgetSettings, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newWidgetSettingsResponse(settings))
+}
+
+// UpdateWidgetSettings godoc
+// @Summary Update widget settings
+// @Description Replace the settings of the booking widget of the restaurant: its theme colors (#rgb or #rrggbb), the party size it preselects (1 to 20) and its locale (a BCP 47 tag). The fields left out take their defaults
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Param settings body UpdateWidgetSettingsRequest true "Widget settings"
+// @Success 200 {object} WidgetSettingsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /restaurants/{id}/widget-settings [put]
+func (h *WidgetSettingsHandler) UpdateWidgetSettings(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	restaurantID := c.Params("id")
+	if restaurantID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	var request UpdateWidgetSettingsRequest
+	if err := c.Bind().Body(&request); err != nil {
+		log.Error(ctx, common.ErrParseRequestBody, zap.Error(err))
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": common.ErrInvalidParams,
+		})
+	}
+
+	settings := domain.DefaultWidgetSettings(restaurantID)
+	if request.Theme.PrimaryColor != "" {
+		settings.Theme.PrimaryColor = request.Theme.PrimaryColor
+	}
+	if request.Theme.BackgroundColor != "" {
+		settings.Theme.BackgroundColor = request.Theme.BackgroundColor
+	}
+	if request.Theme.TextColor != "" {
+		settings.Theme.TextColor = request.Theme.TextColor
+	}
+	if request.DefaultPartySize != 0 {
+		settings.DefaultPartySize = request.DefaultPartySize
+	}
+	if request.Locale != "" {
+		settings.Locale = request.Locale
+	}
+
+	if err := h.widgetSettingsUseCase.UpdateWidgetSettings(ctx, settings); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidWidgetSettings):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		case errors.Is(err, tenant.ErrAccessDenied):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": common.ErrAccessDenied,
+			})
+		case err.Error() == common.ErrRestaurantNotFound:
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": common.ErrRestaurantNotFound,
+			})
+		}
+
+		log.Error(ctx, common.ErrSaveWidgetSettings, zap.String("restaurantID", restaurantID), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(newWidgetSettingsResponse(settings))
+}
+
+// GetWidgetConfig godoc
+// @Summary Widget configuration
+// @Description The configuration the embeddable booking script loads for a restaurant: its theme, default party size and locale, with the URLs of its free slots and of its hosted booking page
+// @Tags embed
+// @Produce json
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} WidgetConfigResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /embed/restaurants/{id}/config [get]
+func (h *WidgetSettingsHandler) GetWidgetConfig(c fiber.Ctx) error {
+	ctx, log, err := getContextAndLogger(c)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	id, err := domain.ParseRestaurantID(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	restaurant, err := h.restaurantUseCase.GetRestaurant(ctx, id)
+	if err != nil && err.Error() != common.ErrRestaurantNotFound {
+		log.Error(ctx, common.ErrGetRestaurant, zap.String("id", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+	if restaurant == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": common.ErrRestaurantNotFound,
+		})
+	}
+
+	settings, err := h.widgetSettingsUseCase.GetWidgetSettings(ctx, id.String())
+	if err != nil {
+		log.Error(ctx, common.ErrGetWidgetSettings, zap.String("restaurantID", id.String()), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": common.ErrInternalServer,
+		})
+	}
+
+	path := restaurant.Slug
+	if path == "" {
+		path = restaurant.ID
+	}
+
+	c.Set(fiber.HeaderCacheControl, embedCacheControl)
+	return c.Status(fiber.StatusOK).JSON(WidgetConfigResponse{
+		Restaurant: EmbedRestaurantResponse{
+			ID:   restaurant.ID,
+			Name: restaurant.Name,
+			Slug: restaurant.Slug,
+		},
+		Theme:            settings.Theme,
+		DefaultPartySize: settings.DefaultPartySize,
+		Locale:           settings.Locale,
+		AvailabilityURL:  h.publicURL + "/embed/restaurants/" + restaurant.ID + "/availability",
+		BookingURL:       h.publicURL + "/r/" + path,
+	})
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"expvar"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// concurrencyMetrics are published under "concurrency" on the diagnostics port: the requests being
+// handled and waiting for a slot, and the rejected ones in total and by route.
+var (
+	concurrencyMetrics = expvar.NewMap("concurrency")
+	inFlightRequests   = new(expvar.Int)
+	queuedRequests     = new(expvar.Int)
+	rejectedRequests   = new(expvar.Int)
+	rejectedByRoute    = new(expvar.Map).Init()
+)
+
+func init() {
+	concurrencyMetrics.Set("in_flight", inFlightRequests)
+	concurrencyMetrics.Set("queued", queuedRequests)
+	concurrencyMetrics.Set("rejected", rejectedRequests)
+	concurrencyMetrics.Set("rejected_by_route", rejectedByRoute)
+}
+
+// ConcurrencyLimits bound the requests handled at once, overall and per route, so that load is
+// shed before the database pool runs out of connections. Zero limits are off.
+type ConcurrencyLimits struct {
+	MaxInFlight         int
+	MaxInFlightPerRoute int
+	// MaxQueue requests wait up to QueueTimeout for a slot; the others are rejected at once.
+	MaxQueue     int
+	QueueTimeout time.Duration
+	RetryAfter   time.Duration
+	// Exempt paths are never limited, e.g. health checks.
+	Exempt []string
+}
+
+type concurrencyLimiter struct {
+	limits ConcurrencyLimits
+	slots  chan struct{}
+	queued atomic.Int64
+
+	routesOnce sync.Once
+	routes     []*limitedRoute
+}
+
+// limitedRoute is a registered route with its own slots.
+type limitedRoute struct {
+	name     string
+	method   string
+	segments []string
+	slots    chan struct{}
+}
+
+// ConcurrencyMiddleware rejects requests with 503 and Retry-After while the limits are reached
+// and the queue is full, or once a queued request has waited QueueTimeout. Requests are counted
+// per route as registered, e.g. "GET /api/v1/restaurants/:id"; paths of no route only count
+// towards MaxInFlight.
+func ConcurrencyMiddleware(limits ConcurrencyLimits) fiber.Handler {
+	l := &concurrencyLimiter{limits: limits}
+	if limits.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, limits.MaxInFlight)
+	}
+
+	return func(c fiber.Ctx) error {
+		if slices.Contains(l.limits.Exempt, c.Path()) {
+			return c.Next()
+		}
+
+		// Routes are registered after the middleware, so they are collected on the first request.
+		l.routesOnce.Do(func() {
+			l.collectRoutes(c.App())
+		})
+
+		route := l.route(c.Method(), c.Path())
+		release, ok := l.acquire(route)
+		if !ok {
+			return l.reject(c, route)
+		}
+		defer release()
+
+		return c.Next()
+	}
+}
+
+func (l *concurrencyLimiter) collectRoutes(app *fiber.App) {
+	if l.limits.MaxInFlightPerRoute <= 0 {
+		return
+	}
+
+	for _, r := range app.GetRoutes(true) {
+		l.routes = append(l.routes, &limitedRoute{
+			name:     r.Method + " " + r.Path,
+			method:   r.Method,
+			segments: routeSegments(r.Path),
+			slots:    make(chan struct{}, l.limits.MaxInFlightPerRoute),
+		})
+	}
+}
+
+func (l *concurrencyLimiter) route(method, path string) *limitedRoute {
+	for _, r := range l.routes {
+		if r.method == method && matchRouteSegments(r.segments, path) {
+			return r
+		}
+	}
+	return nil
+}
+
+// acquire takes a slot of the route and an overall slot, waiting in the queue when there is room
+// in it, and returns the function giving them back.
+func (l *concurrencyLimiter) acquire(route *limitedRoute) (func(), bool) {
+	var routeSlots chan struct{}
+	if route != nil {
+		routeSlots = route.slots
+	}
+
+	if takeSlot(routeSlots) {
+		if takeSlot(l.slots) {
+			return l.inFlight(routeSlots), true
+		}
+		giveSlot(routeSlots)
+	}
+
+	if l.queued.Add(1) > int64(l.limits.MaxQueue) {
+		l.queued.Add(-1)
+		return nil, false
+	}
+	queuedRequests.Add(1)
+	defer func() {
+		l.queued.Add(-1)
+		queuedRequests.Add(-1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.limits.QueueTimeout)
+	defer cancel()
+
+	if !waitSlot(ctx, routeSlots) {
+		return nil, false
+	}
+	if !waitSlot(ctx, l.slots) {
+		giveSlot(routeSlots)
+		return nil, false
+	}
+	return l.inFlight(routeSlots), true
+}
+
+func (l *concurrencyLimiter) inFlight(routeSlots chan struct{}) func() {
+	inFlightRequests.Add(1)
+	return func() {
+		inFlightRequests.Add(-1)
+		giveSlot(l.slots)
+		giveSlot(routeSlots)
+	}
+}
+
+func (l *concurrencyLimiter) reject(c fiber.Ctx, route *limitedRoute) error {
+	rejectedRequests.Add(1)
+	name := c.Method() + " " + c.Path()
+	if route != nil {
+		name = route.name
+		rejectedByRoute.Add(name, 1)
+	}
+
+	if ctx, ok := c.Locals("ctx").(context.Context); ok {
+		if log, err := logger.FromContext(ctx); err == nil {
+			log.Warn(ctx, common.ErrServerOverloaded,
+				zap.String("route", name),
+				zap.Int64("queued", l.queued.Load()))
+		}
+	}
+
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(l.limits.RetryAfter.Seconds()))))
+	return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+		"error": common.ErrServerOverloaded,
+	})
+}
+
+// takeSlot takes a free slot without waiting; nil slots are unlimited.
+func takeSlot(slots chan struct{}) bool {
+	if slots == nil {
+		return true
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func waitSlot(ctx context.Context, slots chan struct{}) bool {
+	if slots == nil {
+		return true
+	}
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func giveSlot(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
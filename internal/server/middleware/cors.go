@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/cors"
+)
+
+var ErrInvalidCORSPolicy = errors.New("invalid CORS policy")
+
+// DefaultCORSAllowHeaders are the request headers browsers may send across origins when a policy
+// names none: those of the API clients, never the principal headers of the gateway.
+var DefaultCORSAllowHeaders = []string{
+	fiber.HeaderAccept,
+	fiber.HeaderAcceptLanguage,
+	fiber.HeaderAuthorization,
+	fiber.HeaderContentType,
+	fiber.HeaderIfModifiedSince,
+	fiber.HeaderIfNoneMatch,
+	"Idempotency-Key",
+	HeaderAPIKey,
+	HeaderResponseEnvelope,
+}
+
+// gatewayHeaders may only come from the gateway, so browsers must never be allowed to send them.
+var gatewayHeaders = []string{HeaderUserID, HeaderRestaurantIDs, HeaderRoles, HeaderGatewaySecret}
+
+// CORSPolicy sets which cross-origin requests browsers may make. Empty origins allow every origin,
+// empty methods the usual ones and empty allowed headers DefaultCORSAllowHeaders.
+type CORSPolicy struct {
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSRoute applies its own policy to the paths under Prefix, e.g. endpoints embedded into other
+// websites.
+type CORSRoute struct {
+	Prefix string
+	Policy CORSPolicy
+}
+
+// Validate reports a policy browsers would reject or that would leak credentials: credentials
+// with a wildcard origin, origins that are not a scheme and host, and allowed headers including
+// the principal headers of the gateway.
+func (p CORSPolicy) Validate() error {
+	if p.AllowCredentials && allowsEveryOrigin(p.AllowOrigins) {
+		return fmt.Errorf("%w: credentials cannot be allowed for every origin", ErrInvalidCORSPolicy)
+	}
+
+	for _, origin := range p.AllowOrigins {
+		if origin == "*" {
+			continue
+		}
+
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" || origin != parsed.Scheme+"://"+parsed.Host {
+			return fmt.Errorf("%w: origin %q is not a scheme and host", ErrInvalidCORSPolicy, origin)
+		}
+	}
+
+	for _, header := range p.AllowHeaders {
+		if header == "*" || slices.ContainsFunc(gatewayHeaders, func(gatewayHeader string) bool {
+			return strings.EqualFold(strings.TrimSpace(header), gatewayHeader)
+		}) {
+			return fmt.Errorf("%w: header %q would let other origins name the principal", ErrInvalidCORSPolicy, header)
+		}
+	}
+	return nil
+}
+
+func (p CORSPolicy) handler() fiber.Handler {
+	allowHeaders := p.AllowHeaders
+	if len(allowHeaders) == 0 {
+		allowHeaders = DefaultCORSAllowHeaders
+	}
+
+	return cors.New(cors.Config{
+		AllowOrigins:     p.AllowOrigins,
+		AllowMethods:     p.AllowMethods,
+		AllowHeaders:     allowHeaders,
+		ExposeHeaders:    p.ExposeHeaders,
+		AllowCredentials: p.AllowCredentials,
+		MaxAge:           int(p.MaxAge / time.Second),
+	})
+}
+
+// CORSMiddleware answers preflight requests and adds the CORS headers to responses, following the
+// route with the longest matching prefix or, outside every route, the default policy. It fails on
+// an invalid policy instead of serving headers browsers would refuse.
+func CORSMiddleware(policy CORSPolicy, routes ...CORSRoute) (fiber.Handler, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	defaultHandler := policy.handler()
+
+	type route struct {
+		prefix  string
+		handler fiber.Handler
+	}
+	compiled := make([]route, 0, len(routes))
+	for _, r := range routes {
+		if err := r.Policy.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", r.Prefix, err)
+		}
+		compiled = append(compiled, route{prefix: strings.TrimRight(r.Prefix, "/"), handler: r.Policy.handler()})
+	}
+	sort.SliceStable(compiled, func(i, j int) bool {
+		return len(compiled[i].prefix) > len(compiled[j].prefix)
+	})
+
+	return func(c fiber.Ctx) error {
+		path := c.Path()
+		for _, r := range compiled {
+			if path == r.prefix || strings.HasPrefix(path, r.prefix+"/") {
+				return r.handler(c)
+			}
+		}
+		return defaultHandler(c)
+	}, nil
+}
+
+// allowsEveryOrigin reports whether the origins of a policy include the wildcard.
+func allowsEveryOrigin(origins []string) bool {
+	return len(origins) == 0 || slices.Contains(origins, "*")
+}
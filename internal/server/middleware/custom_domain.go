@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// HostResolver returns the verified custom domain of a host.
+type HostResolver interface {
+	ResolveHost(ctx context.Context, host string) (*domain.CustomDomain, error)
+}
+
+// CustomDomainMiddleware serves the hosted booking page of a restaurant at the root of its
+// verified custom domains; every other path of the domain, the API included, is served as on the
+// platform hosts, which are never looked up. Requests for unknown hosts go on unchanged. It must
+// be registered after PrincipalMiddleware, which puts the request context in place.
+func CustomDomainMiddleware(resolver HostResolver, platformHosts []string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead:
+		default:
+			return c.Next()
+		}
+		if c.Path() != "/" {
+			return c.Next()
+		}
+
+		host := strings.ToLower(c.Hostname())
+		if host == "" || slices.Contains(platformHosts, host) {
+			return c.Next()
+		}
+
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+
+		customDomain, err := resolver.ResolveHost(ctx, host)
+		if err != nil {
+			if err.Error() != common.ErrCustomDomainNotFound {
+				if log, logErr := logger.FromContext(ctx); logErr == nil {
+					log.Warn(ctx, common.ErrGetCustomDomain, zap.String("host", host), zap.Error(err))
+				}
+			}
+			return c.Next()
+		}
+
+		c.Path("/r/" + customDomain.RestaurantID)
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// HeaderResponseEnvelope lets a client ask for ("true") or opt out of ("false") the response
+// envelope regardless of the server default.
+const HeaderResponseEnvelope = "X-Response-Envelope"
+
+// HeaderNextCursor carries the cursor of the next page of a cursor-paginated list, so that clients
+// reading bare bodies can page through it too.
+const HeaderNextCursor = "X-Next-Cursor"
+
+const localsResponseMeta = "responseMeta"
+
+// Envelope is the body of every API response when the envelope is enabled. Data is null when the
+// request failed, Error is null when it succeeded.
+type Envelope struct {
+	Data  json.RawMessage `json:"data"`
+	Meta  *ResponseMeta   `json:"meta"`
+	Error *string         `json:"error"`
+}
+
+type ResponseMeta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes the page of a list response; Count is the number of items on the page.
+// HasNext tells whether a page follows, which lists find out without counting their rows.
+// Lists paginated with a cursor give NextCursor, the cursor of the page that follows, instead of
+// an Offset. EstimatedTotal is the number of rows the database statistics give for a list that is
+// not filtered, and is only approximate.
+type Pagination struct {
+	Offset         int    `json:"offset,omitempty"`
+	Limit          int    `json:"limit"`
+	Count          int    `json:"count"`
+	HasNext        bool   `json:"has_next"`
+	NextCursor     string `json:"next_cursor,omitempty"`
+	EstimatedTotal *int64 `json:"estimated_total,omitempty"`
+}
+
+// SetPagination attaches the pagination of a list response to the envelope meta, and its next
+// cursor to the HeaderNextCursor header.
+func SetPagination(c fiber.Ctx, pagination Pagination) {
+	if pagination.NextCursor != "" {
+		c.Set(HeaderNextCursor, pagination.NextCursor)
+	}
+
+	meta, ok := c.Locals(localsResponseMeta).(*ResponseMeta)
+	if !ok {
+		meta = &ResponseMeta{}
+		c.Locals(localsResponseMeta, meta)
+	}
+	meta.Pagination = &pagination
+}
+
+// EnvelopeMiddleware wraps the JSON responses of the API into an Envelope. v1 clients written
+// against bare bodies keep them while enabledByDefault is off, unless they ask for the envelope
+// with HeaderResponseEnvelope.
+func EnvelopeMiddleware(enabledByDefault bool) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if !strings.HasPrefix(c.Path(), "/api/") || !envelopeRequested(c, enabledByDefault) {
+			return c.Next()
+		}
+
+		// Errors are rendered by the error handler here so that they are wrapped as well.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		response := c.Response()
+		contentType := string(response.Header.ContentType())
+		failed := response.StatusCode() >= fiber.StatusBadRequest
+
+		envelope := Envelope{Data: json.RawMessage("null")}
+		if meta, ok := c.Locals(localsResponseMeta).(*ResponseMeta); ok {
+			envelope.Meta = meta
+		}
+
+		body := bytes.TrimSpace(response.Body())
+		switch {
+		case !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON):
+			// Plain text errors come from fiber itself, e.g. for unknown routes.
+			if !failed || !strings.HasPrefix(contentType, fiber.MIMETextPlain) {
+				return nil
+			}
+			message := string(body)
+			envelope.Error = &message
+		case failed:
+			envelope.Error, envelope.Data = splitErrorBody(body)
+		case len(body) > 0:
+			envelope.Data = body
+		}
+
+		wrapped, err := json.Marshal(envelope)
+		if err != nil {
+			return err
+		}
+		response.Header.SetContentType(fiber.MIMEApplicationJSONCharsetUTF8)
+		response.SetBody(wrapped)
+		return nil
+	}
+}
+
+func envelopeRequested(c fiber.Ctx, enabledByDefault bool) bool {
+	requested, err := strconv.ParseBool(c.Get(HeaderResponseEnvelope))
+	if err != nil {
+		return enabledByDefault
+	}
+	return requested
+}
+
+// splitErrorBody takes the message out of an error body. The other fields of the body, such as
+// the impacted bookings of a capacity conflict, are kept as the data.
+func splitErrorBody(body []byte) (*string, json.RawMessage) {
+	if len(body) == 0 {
+		return nil, json.RawMessage("null")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, body
+	}
+
+	var message *string
+	if raw, ok := fields["error"]; ok {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			message = &text
+			delete(fields, "error")
+		}
+	}
+
+	if len(fields) == 0 {
+		return message, json.RawMessage("null")
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return message, body
+	}
+	return message, data
+}
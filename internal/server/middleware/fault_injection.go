@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// HeaderFaultInjected marks a response whose error was injected on purpose.
+	HeaderFaultInjected = "X-Fault-Injected"
+
+	faultsPathPrefix = "/api/v1/admin/faults"
+)
+
+// FaultInjector provides the faults injected into routes.
+type FaultInjector interface {
+	RouteFaults() []domain.RouteFault
+}
+
+// FaultInjectionMiddleware delays the requests of a route with a fault and fails a share of them
+// with the status of the fault; the first fault matching the request applies. The endpoints
+// managing the faults are never faulted, so the faults can always be removed. It must be
+// registered after TimeoutMiddleware, so that the injected latency runs into the request timeout
+// as a slow handler would.
+func FaultInjectionMiddleware(injector FaultInjector) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		faults := injector.RouteFaults()
+		if len(faults) == 0 || strings.HasPrefix(c.Path(), faultsPathPrefix) {
+			return c.Next()
+		}
+
+		var fault *domain.RouteFault
+		for i := range faults {
+			if (faults[i].Method == "" || faults[i].Method == c.Method()) && matchRoutePath(faults[i].Path, c.Path()) {
+				fault = &faults[i]
+				break
+			}
+		}
+		if fault == nil {
+			return c.Next()
+		}
+
+		if fault.Latency > 0 {
+			ctx, ok := c.Locals("ctx").(context.Context)
+			if !ok {
+				ctx = context.Background()
+			}
+
+			timer := time.NewTimer(fault.Latency)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		if fault.ErrorRate > 0 && rand.Float64() < fault.ErrorRate {
+			c.Set(HeaderFaultInjected, "true")
+			if fault.ErrorStatus == fiber.StatusServiceUnavailable || fault.ErrorStatus == fiber.StatusTooManyRequests {
+				c.Set(fiber.HeaderRetryAfter, "1")
+			}
+			return c.Status(fault.ErrorStatus).JSON(fiber.Map{
+				"error": common.ErrInjectedFault,
+			})
+		}
+
+		return c.Next()
+	}
+}
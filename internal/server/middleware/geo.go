@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// GeoMiddleware puts the location of the client address into the request context, where handlers
+// take their defaults from when the client states no preference. Requests whose address cannot be
+// located go on without one. It must be registered after PrincipalMiddleware, which puts the client
+// address into the request context.
+func GeoMiddleware(locator geo.Locator) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			return c.Next()
+		}
+
+		ip := tenant.ClientIPFromContext(ctx)
+		if ip == "" {
+			return c.Next()
+		}
+
+		location, err := locator.Locate(ip)
+		if err != nil {
+			if !errors.Is(err, geo.ErrNotFound) {
+				if log, logErr := logger.FromContext(ctx); logErr == nil {
+					log.Warn(ctx, common.ErrLocateClient, zap.String("ip", ip), zap.Error(err))
+				}
+			}
+			return c.Next()
+		}
+
+		c.Locals("ctx", geo.NewContext(ctx, location))
+
+		return c.Next()
+	}
+}
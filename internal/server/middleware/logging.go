@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"context"
+	"strings"
+	"sync/atomic"
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
@@ -12,13 +14,28 @@ import (
 	"go.uber.org/zap"
 )
 
-func LoggingMiddleware() fiber.Handler {
+// LogSampling thins out the logs of hot read endpoints: of the GET requests to paths starting with
+// one of Routes, only one in Rate writes its info and debug lines. A Rate of 1 or less writes the
+// lines of every request.
+type LogSampling struct {
+	Routes []string
+	Rate   int
+}
+
+func LoggingMiddleware(sampling LogSampling) fiber.Handler {
+	var reads atomic.Uint64
+
 	return func(c fiber.Ctx) error {
 		ctx := context.Background()
 
 		requestID := uuid.New().String()
 		ctx = context.WithValue(ctx, utils.RequestID, requestID)
 
+		if sampling.Rate > 1 && c.Method() == fiber.MethodGet && sampledRoute(c.Path(), sampling.Routes) &&
+			reads.Add(1)%uint64(sampling.Rate) != 1 {
+			ctx = utils.WithSampledOut(ctx)
+		}
+
 		log, err := logger.FromContext(ctx)
 		if err != nil {
 			log, err = logger.NewLogger()
@@ -39,3 +56,12 @@ func LoggingMiddleware() fiber.Handler {
 		return c.Next()
 	}
 }
+
+func sampledRoute(path string, routes []string) bool {
+	for _, route := range routes {
+		if route != "" && strings.HasPrefix(path, route) {
+			return true
+		}
+	}
+	return false
+}
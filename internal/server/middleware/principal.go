@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderUserID        = "X-User-ID"
+	HeaderRestaurantIDs = "X-Restaurant-IDs"
+	HeaderRoles         = "X-Roles"
+	// HeaderGatewaySecret proves that the principal headers were set by the gateway.
+	HeaderGatewaySecret = "X-Gateway-Secret"
+)
+
+var ErrInvalidGatewayTrust = errors.New("invalid gateway trust")
+
+// GatewayTrust names the authenticating gateway whose principal headers are trusted: requests from
+// one of Networks carrying Secret in HeaderGatewaySecret, whichever of the two are set. The zero
+// value trusts no request.
+type GatewayTrust struct {
+	Networks []netip.Prefix
+	Secret   string
+}
+
+// NewGatewayTrust trusts the gateway reaching the service from the networks in CIDR notation with
+// the secret. At least one of them must be given.
+func NewGatewayTrust(networks []string, secret string) (GatewayTrust, error) {
+	trust := GatewayTrust{Secret: secret}
+	for _, network := range networks {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(network))
+		if err != nil {
+			return GatewayTrust{}, fmt.Errorf("%w: network %q: %w", ErrInvalidGatewayTrust, network, err)
+		}
+		trust.Networks = append(trust.Networks, prefix.Masked())
+	}
+
+	if len(trust.Networks) == 0 && trust.Secret == "" {
+		return GatewayTrust{}, fmt.Errorf("%w: neither gateway networks nor a secret are set", ErrInvalidGatewayTrust)
+	}
+	return trust, nil
+}
+
+// trusts reports whether the request comes from the gateway.
+func (g GatewayTrust) trusts(c fiber.Ctx) bool {
+	if len(g.Networks) == 0 && g.Secret == "" {
+		return false
+	}
+
+	if len(g.Networks) > 0 {
+		addr, err := netip.ParseAddr(c.IP())
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		inNetwork := false
+		for _, network := range g.Networks {
+			if network.Contains(addr) {
+				inNetwork = true
+				break
+			}
+		}
+		if !inNetwork {
+			return false
+		}
+	}
+
+	return g.Secret == "" || subtle.ConstantTimeCompare([]byte(c.Get(HeaderGatewaySecret)), []byte(g.Secret)) == 1
+}
+
+// Authenticator returns the principal an access token was issued to.
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*tenant.Principal, error)
+}
+
+// PrincipalMiddleware puts the caller and its address into the request context. A request with
+// an Authorization bearer token runs as the principal of the token, and an invalid or expired
+// token gets a 401. Without a token, and only for requests the gateway trust names, the principal
+// is read from the headers set by the authenticating gateway in front of the API; other requests
+// and those without X-User-ID run without a principal. It must be registered after
+// LoggingMiddleware, which creates the request context.
+func PrincipalMiddleware(authenticator Authenticator, gateway GatewayTrust) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+		ctx = tenant.WithClientIP(ctx, strings.Clone(c.IP()))
+		c.Locals("ctx", ctx)
+
+		if token, ok := bearerToken(c.Get(fiber.HeaderAuthorization)); ok && authenticator != nil {
+			principal, err := authenticator.Authenticate(ctx, token)
+			if err != nil {
+				log, _ := logger.FromContext(ctx)
+				log.Info(ctx, common.ErrInvalidBearerToken, zap.Error(err))
+
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+			}
+
+			c.Locals("ctx", tenant.NewContext(withPrincipalLogs(ctx, principal), principal))
+			return c.Next()
+		}
+
+		if !gateway.trusts(c) {
+			return c.Next()
+		}
+
+		userID := strings.TrimSpace(c.Get(HeaderUserID))
+		if userID == "" {
+			return c.Next()
+		}
+
+		principal := &tenant.Principal{
+			UserID:        userID,
+			RestaurantIDs: splitHeaderList(c.Get(HeaderRestaurantIDs)),
+		}
+		for _, role := range splitHeaderList(c.Get(HeaderRoles)) {
+			principal.Roles = append(principal.Roles, tenant.Role(role))
+		}
+
+		c.Locals("ctx", tenant.NewContext(withPrincipalLogs(ctx, principal), principal))
+
+		return c.Next()
+	}
+}
+
+// RequirePrincipalMiddleware answers requests without a principal with 401; with writesOnly reads
+// pass without one. It must be registered after PrincipalMiddleware.
+func RequirePrincipalMiddleware(writesOnly bool) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if writesOnly {
+			switch c.Method() {
+			case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+				return c.Next()
+			}
+		}
+
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if ok {
+			if _, ok := tenant.FromContext(ctx); ok {
+				return c.Next()
+			}
+		}
+
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return fiber.NewError(fiber.StatusUnauthorized, common.ErrAuthenticationRequired)
+	}
+}
+
+// RequireRoleMiddleware lets through callers with any of the roles; include tenant.RoleGuest to let
+// requests without a principal through. Others get 401 without a principal and 403 with one. It
+// must be registered after PrincipalMiddleware.
+func RequireRoleMiddleware(roles ...tenant.Role) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+		if tenant.HasRole(ctx, roles...) {
+			return c.Next()
+		}
+
+		if _, ok := tenant.FromContext(ctx); !ok {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return fiber.NewError(fiber.StatusUnauthorized, common.ErrAuthenticationRequired)
+		}
+		return fiber.NewError(fiber.StatusForbidden, common.ErrAccessDenied)
+	}
+}
+
+// withPrincipalLogs makes the log lines of the request name the caller until a handler names the
+// user it is about; a restaurant account has no user and is named by the account.
+func withPrincipalLogs(ctx context.Context, principal *tenant.Principal) context.Context {
+	if principal.UserID != "" {
+		return logger.WithUserID(ctx, principal.UserID)
+	}
+	if principal.AccountID != "" {
+		return logger.WithUserID(ctx, principal.AccountID)
+	}
+	return ctx
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func splitHeaderList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
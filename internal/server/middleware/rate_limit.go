@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RateLimiter decides whether the client of a request may make another write request.
+type RateLimiter interface {
+	Allow(ctx context.Context) (bool, time.Duration)
+}
+
+// RateLimitMiddleware answers write requests over the limit of their client with 429 and a
+// Retry-After header; reads are never limited. It must be registered after PrincipalMiddleware,
+// which puts the client into the request context.
+func RateLimitMiddleware(limiter RateLimiter) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+
+		allowed, retryAfter := limiter.Allow(ctx)
+		if allowed {
+			return c.Next()
+		}
+
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": common.ErrTooManyRequests,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// RequestRecorder stores the requests made with an API key.
+type RequestRecorder interface {
+	RecordRequest(ctx context.Context, apiKey string, request *domain.RecordedRequest)
+}
+
+// RequestRecorderMiddleware hands every API request made with an API key to the recorder once it
+// is answered. The admin API itself is not recorded. It must be registered after
+// LoggingMiddleware, which creates the request context.
+func RequestRecorderMiddleware(recorder RequestRecorder) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		apiKey := strings.TrimSpace(c.Get(HeaderAPIKey))
+		path := c.Path()
+		if apiKey == "" || !strings.HasPrefix(path, "/api/v1/") || strings.HasPrefix(path, "/api/v1/admin/") {
+			return c.Next()
+		}
+
+		started := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// Fiber strings point into buffers reused after the request, so the recorded ones are copied.
+		headers := make(map[string]string)
+		for name, values := range c.GetReqHeaders() {
+			headers[strings.Clone(name)] = strings.Clone(strings.Join(values, ", "))
+		}
+
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			ctx = context.Background()
+		}
+
+		recorder.RecordRequest(ctx, apiKey, &domain.RecordedRequest{
+			Method:     strings.Clone(c.Method()),
+			Path:       strings.Clone(c.OriginalURL()),
+			Headers:    headers,
+			Body:       string(c.Body()),
+			Status:     status,
+			DurationMS: time.Since(started).Milliseconds(),
+			RecordedAt: started,
+		})
+
+		return err
+	}
+}
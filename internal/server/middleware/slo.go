@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RequestObserver counts the answered requests for the service level objectives.
+type RequestObserver interface {
+	ObserveRequest(at time.Time, status int, elapsed time.Duration)
+}
+
+// SLOMiddleware hands the status and duration of every request but the exempt paths to the
+// observer. It must be registered before the middleware that sheds load, so that the requests it
+// rejects count as failed.
+func SLOMiddleware(observer RequestObserver, exempt []string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if slices.Contains(exempt, c.Path()) {
+			return c.Next()
+		}
+
+		started := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		observer.ObserveRequest(started, status, time.Since(started))
+
+		return err
+	}
+}
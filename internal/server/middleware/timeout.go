@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// TimeoutRoute gives the requests of a route their own timeout; zero means none. Path is a route
+// path as registered, e.g. "/api/v1/restaurants/:id/availability/generate".
+type TimeoutRoute struct {
+	Method  string
+	Path    string
+	Timeout time.Duration
+}
+
+// TimeoutMiddleware puts a deadline on the request context, which the repositories pass to their
+// queries, so a slow request is cancelled instead of holding a connection indefinitely. A request
+// that fails after running out of time is answered with 504 whatever the handler made of the
+// error. Requests of a route get the timeout of the route, the rest timeout; zero turns it off. It
+// must be registered after EnvelopeMiddleware and RequestRecorderMiddleware, which use the request
+// context after the handler returns.
+func TimeoutMiddleware(timeout time.Duration, routes ...TimeoutRoute) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		limit := timeout
+		for _, route := range routes {
+			if route.Method == c.Method() && matchRoutePath(route.Path, c.Path()) {
+				limit = route.Timeout
+				break
+			}
+		}
+
+		parent, ok := c.Locals("ctx").(context.Context)
+		if limit <= 0 || !ok {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(parent, limit)
+		defer cancel()
+
+		c.Locals("ctx", ctx)
+		err := c.Next()
+		c.Locals("ctx", parent)
+
+		timedOut := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		if !timedOut || (err == nil && c.Response().StatusCode() < fiber.StatusInternalServerError) {
+			return err
+		}
+
+		if log, logErr := logger.FromContext(parent); logErr == nil {
+			log.Warn(parent, common.ErrRequestTimeout,
+				zap.String("method", c.Method()),
+				zap.String("path", c.Path()),
+				zap.Duration("timeout", limit),
+				zap.Error(err))
+		}
+
+		c.Response().ResetBody()
+		return c.Status(fiber.StatusGatewayTimeout).JSON(fiber.Map{
+			"error": common.ErrRequestTimeout,
+		})
+	}
+}
+
+// matchRoutePath reports whether path is one of the paths of the route pattern, whose ":name"
+// segments match any segment.
+func matchRoutePath(pattern, path string) bool {
+	return matchRouteSegments(routeSegments(pattern), path)
+}
+
+func routeSegments(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func matchRouteSegments(patternSegments []string, path string) bool {
+	pathSegments := routeSegments(path)
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,15 +2,57 @@ package server
 
 import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 
 	"github.com/gofiber/fiber/v3"
 )
 
 type Router struct {
-	restaurantHandler *handlers.RestaurantHandler
-	bookingHandler    *handlers.BookingHandler
-	userHandler       *handlers.UserHandler
-	factsHandler      *handlers.FactsHandler
+	restaurantHandler          *handlers.RestaurantHandler
+	bookingHandler             *handlers.BookingHandler
+	userHandler                *handlers.UserHandler
+	factsHandler               *handlers.FactsHandler
+	catalogHandler             *handlers.CatalogHandler
+	notificationHandler        *handlers.NotificationHandler
+	bookingLinkHandler         *handlers.BookingLinkHandler
+	qrHandler                  *handlers.QRHandler
+	requestReplayHandler       *handlers.RequestReplayHandler
+	notificationReceiptHandler *handlers.NotificationReceiptHandler
+	menuHandler                *handlers.MenuHandler
+	imageHandler               *handlers.ImageHandler
+	reviewHandler              *handlers.ReviewHandler
+	abuseHandler               *handlers.AbuseHandler
+	geoHandler                 *handlers.GeoHandler
+	retentionHandler           *handlers.RetentionHandler
+	exportHandler              *handlers.ExportHandler
+	syncHandler                *handlers.SyncHandler
+	embedHandler               *handlers.EmbedHandler
+	notificationFailureHandler *handlers.NotificationFailureHandler
+	analyticsHandler           *handlers.AnalyticsHandler
+	quotaHandler               *handlers.QuotaHandler
+	billingHandler             *handlers.BillingHandler
+	incentiveHandler           *handlers.IncentiveHandler
+	geocodingHandler           *handlers.GeocodingHandler
+	availabilityAlertHandler   *handlers.AvailabilityAlertHandler
+	bulkCancellationHandler    *handlers.BulkCancellationHandler
+	organizationHandler        *handlers.OrganizationHandler
+	bookingTransferHandler     *handlers.BookingTransferHandler
+	bookingSheetHandler        *handlers.BookingSheetHandler
+	displayBoardHandler        *handlers.DisplayBoardHandler
+	sloHandler                 *handlers.SLOHandler
+	faultInjectionHandler      *handlers.FaultInjectionHandler
+	bookingSyncHandler         *handlers.BookingSyncHandler
+	restaurantClaimHandler     *handlers.RestaurantClaimHandler
+	customDomainHandler        *handlers.CustomDomainHandler
+	restaurantClosureHandler   *handlers.RestaurantClosureHandler
+	tableHandler               *handlers.TableHandler
+	bookingPageHandler         *handlers.BookingPageHandler
+	widgetSettingsHandler      *handlers.WidgetSettingsHandler
+	bookingDraftHandler        *handlers.BookingDraftHandler
+	authHandler                *handlers.AuthHandler
+
+	authRequired bool
 }
 
 func NewRouter() *Router {
@@ -22,11 +64,93 @@ func (r *Router) SetHandlers(
 	bookingHandler *handlers.BookingHandler,
 	userHandler *handlers.UserHandler,
 	factsHandler *handlers.FactsHandler,
+	catalogHandler *handlers.CatalogHandler,
+	notificationHandler *handlers.NotificationHandler,
+	bookingLinkHandler *handlers.BookingLinkHandler,
+	qrHandler *handlers.QRHandler,
+	requestReplayHandler *handlers.RequestReplayHandler,
+	notificationReceiptHandler *handlers.NotificationReceiptHandler,
+	menuHandler *handlers.MenuHandler,
+	imageHandler *handlers.ImageHandler,
+	reviewHandler *handlers.ReviewHandler,
+	abuseHandler *handlers.AbuseHandler,
+	geoHandler *handlers.GeoHandler,
+	retentionHandler *handlers.RetentionHandler,
+	exportHandler *handlers.ExportHandler,
+	syncHandler *handlers.SyncHandler,
+	embedHandler *handlers.EmbedHandler,
+	notificationFailureHandler *handlers.NotificationFailureHandler,
+	analyticsHandler *handlers.AnalyticsHandler,
+	quotaHandler *handlers.QuotaHandler,
+	billingHandler *handlers.BillingHandler,
+	incentiveHandler *handlers.IncentiveHandler,
+	geocodingHandler *handlers.GeocodingHandler,
+	availabilityAlertHandler *handlers.AvailabilityAlertHandler,
+	bulkCancellationHandler *handlers.BulkCancellationHandler,
+	organizationHandler *handlers.OrganizationHandler,
+	bookingTransferHandler *handlers.BookingTransferHandler,
+	bookingSheetHandler *handlers.BookingSheetHandler,
+	displayBoardHandler *handlers.DisplayBoardHandler,
+	sloHandler *handlers.SLOHandler,
+	faultInjectionHandler *handlers.FaultInjectionHandler,
+	bookingSyncHandler *handlers.BookingSyncHandler,
+	restaurantClaimHandler *handlers.RestaurantClaimHandler,
+	customDomainHandler *handlers.CustomDomainHandler,
+	bookingPageHandler *handlers.BookingPageHandler,
+	widgetSettingsHandler *handlers.WidgetSettingsHandler,
+	bookingDraftHandler *handlers.BookingDraftHandler,
+	authHandler *handlers.AuthHandler,
+	restaurantClosureHandler *handlers.RestaurantClosureHandler,
+	tableHandler *handlers.TableHandler,
 ) {
 	r.restaurantHandler = restaurantHandler
 	r.bookingHandler = bookingHandler
 	r.userHandler = userHandler
 	r.factsHandler = factsHandler
+	r.catalogHandler = catalogHandler
+	r.notificationHandler = notificationHandler
+	r.bookingLinkHandler = bookingLinkHandler
+	r.qrHandler = qrHandler
+	r.requestReplayHandler = requestReplayHandler
+	r.notificationReceiptHandler = notificationReceiptHandler
+	r.menuHandler = menuHandler
+	r.imageHandler = imageHandler
+	r.reviewHandler = reviewHandler
+	r.abuseHandler = abuseHandler
+	r.geoHandler = geoHandler
+	r.retentionHandler = retentionHandler
+	r.exportHandler = exportHandler
+	r.syncHandler = syncHandler
+	r.embedHandler = embedHandler
+	r.notificationFailureHandler = notificationFailureHandler
+	r.analyticsHandler = analyticsHandler
+	r.quotaHandler = quotaHandler
+	r.billingHandler = billingHandler
+	r.incentiveHandler = incentiveHandler
+	r.geocodingHandler = geocodingHandler
+	r.availabilityAlertHandler = availabilityAlertHandler
+	r.bulkCancellationHandler = bulkCancellationHandler
+	r.organizationHandler = organizationHandler
+	r.bookingTransferHandler = bookingTransferHandler
+	r.bookingSheetHandler = bookingSheetHandler
+	r.displayBoardHandler = displayBoardHandler
+	r.sloHandler = sloHandler
+	r.faultInjectionHandler = faultInjectionHandler
+	r.bookingSyncHandler = bookingSyncHandler
+	r.restaurantClaimHandler = restaurantClaimHandler
+	r.customDomainHandler = customDomainHandler
+	r.bookingPageHandler = bookingPageHandler
+	r.widgetSettingsHandler = widgetSettingsHandler
+	r.bookingDraftHandler = bookingDraftHandler
+	r.authHandler = authHandler
+	r.restaurantClosureHandler = restaurantClosureHandler
+	r.tableHandler = tableHandler
+}
+
+// SetAuthRequired turns away requests without a principal from the users, the changes of
+// restaurants, the bookings and the admin routes.
+func (r *Router) SetAuthRequired(required bool) {
+	r.authRequired = required
 }
 
 func (r *Router) RegisterRoutes(app *fiber.App) {
@@ -51,39 +175,213 @@ func (r *Router) RegisterRoutes(app *fiber.App) {
 		return c.SendFile("./docs/static/swagger-ui.html")
 	})
 
+	app.Get("/sitemap.xml", r.catalogHandler.Sitemap)
+	app.Get("/feed/restaurants.json", r.catalogHandler.RestaurantsFeed)
+
+	// Короткие ссылки из SMS открываются без входа в систему
+	app.Get("/b/:token", r.bookingLinkHandler.ResolveBookingLink)
+	app.Post("/b/:token/cancel", r.bookingLinkHandler.CancelBookingByLink)
+
+	// Изображения отдаются без префикса API, чтобы их ссылки были короткими
+	app.Get("/images/:id", r.imageHandler.GetImage)
+
+	// Виджеты встраиваются на сайты ресторанов, их правила CORS задаются отдельно
+	embed := app.Group("/embed")
+	embed.Get("/restaurants/:id/availability", r.embedHandler.GetAvailabilityWidget)
+	embed.Get("/restaurants/:id/config", r.widgetSettingsHandler.GetWidgetConfig)
+
+	app.Get("/r/:slug", r.bookingPageHandler.GetBookingPage)
+
+	auth := api.Group("/auth")
+	auth.Post("/register", r.authHandler.RegisterUser)
+	auth.Post("/login", r.authHandler.Login)
+	auth.Post("/refresh", r.authHandler.RefreshToken)
+	auth.Post("/logout", r.authHandler.Logout)
+	auth.Get("/verify-email", r.authHandler.VerifyEmail)
+	auth.Post("/verify-email/resend", r.authHandler.ResendVerification)
+	auth.Post("/restaurants/:id/accounts", r.authHandler.RegisterRestaurantAccount)
+
 	restaurants := api.Group("/restaurants")
+	if r.authRequired {
+		restaurants.Use(middleware.RequirePrincipalMiddleware(true))
+	}
 	restaurants.Get("/", r.restaurantHandler.ListRestaurants)
 	restaurants.Post("/", r.restaurantHandler.CreateRestaurant)
+	restaurants.Post("/import", r.restaurantHandler.ImportRestaurantBundle)
+	restaurants.Get("/search", r.restaurantHandler.SearchRestaurants)
+	restaurants.Get("/by-slug/:slug", r.restaurantHandler.GetRestaurantBySlug)
 	restaurants.Get("/:id", r.restaurantHandler.GetRestaurant)
 	restaurants.Put("/:id", r.restaurantHandler.UpdateRestaurant)
 	restaurants.Delete("/:id", r.restaurantHandler.DeleteRestaurant)
+	restaurants.Get("/:id/export", r.restaurantHandler.ExportRestaurant)
+	restaurants.Get("/:id/location", r.geocodingHandler.GetRestaurantLocation)
+	restaurants.Get("/:id/qr", r.qrHandler.GetRestaurantQR)
 	restaurants.Post("/:id/facts", r.restaurantHandler.AddFact)
 	restaurants.Get("/:id/facts", r.restaurantHandler.GetFacts)
 	restaurants.Post("/:id/working-hours", r.restaurantHandler.SetWorkingHours)
 	restaurants.Get("/:id/working-hours", r.restaurantHandler.GetWorkingHours)
+	restaurants.Get("/:id/special-days", r.restaurantHandler.ListSpecialDays)
+	restaurants.Put("/:id/special-days/:date", r.restaurantHandler.SetSpecialDay)
+	restaurants.Delete("/:id/special-days/:date", r.restaurantHandler.DeleteSpecialDay)
+	restaurants.Get("/:id/schedule", r.restaurantHandler.GetSchedule)
 	restaurants.Post("/:id/availability", r.restaurantHandler.SetAvailability)
 	restaurants.Get("/:id/availability", r.restaurantHandler.GetAvailability)
+	restaurants.Get("/:id/availability/delta", r.restaurantHandler.GetAvailabilityDelta)
+	restaurants.Get("/:id/availability/:availabilityId/bookings", r.restaurantHandler.GetSlotBookings)
+	restaurants.Delete("/:id/availability/:availabilityId", r.restaurantHandler.DeleteAvailability)
+	restaurants.Post("/:id/availability/generate", r.restaurantHandler.GenerateAvailability)
+	restaurants.Post("/:id/availability-alerts", r.availabilityAlertHandler.CreateAvailabilityAlert)
+	restaurants.Post("/:id/bookings/cancel", r.bulkCancellationHandler.CancelBookings)
+	restaurants.Get("/:id/bookings/print", r.bookingSheetHandler.PrintBookingSheet)
+	restaurants.Get("/:id/board", r.displayBoardHandler.GetDisplayBoard)
+	restaurants.Get("/:id/sister-restaurants", r.organizationHandler.ListSisterRestaurants)
+	restaurants.Post("/:id/claims", r.restaurantClaimHandler.SubmitRestaurantClaim)
+	restaurants.Get("/:id/forecast", r.analyticsHandler.GetForecast)
+	restaurants.Get("/:id/demand-heatmap", r.analyticsHandler.GetDemandHeatmap)
+	restaurants.Get("/:id/stats", r.analyticsHandler.GetRestaurantStats)
+	restaurants.Get("/:id/occupancy-now", r.analyticsHandler.GetOccupancyNow)
+	restaurants.Get("/:id/quota", r.quotaHandler.GetQuota)
 	restaurants.Get("/:id/bookings", r.restaurantHandler.GetRestaurantBookings)
+	restaurants.Get("/:id/menu", r.menuHandler.GetMenu)
+	restaurants.Put("/:id/menu", r.menuHandler.UpdateMenu)
+	restaurants.Post("/:id/menu/import", r.menuHandler.ImportMenu)
+	restaurants.Post("/:id/images", r.imageHandler.UploadImage)
+	restaurants.Get("/:id/images", r.imageHandler.ListImages)
+	restaurants.Delete("/:id/images/:imageId", r.imageHandler.DeleteImage)
+	restaurants.Post("/:id/domains", r.customDomainHandler.AddCustomDomain)
+	restaurants.Get("/:id/domains", r.customDomainHandler.ListCustomDomains)
+	restaurants.Post("/:id/domains/:domainId/verify", r.customDomainHandler.VerifyCustomDomain)
+	restaurants.Delete("/:id/domains/:domainId", r.customDomainHandler.RemoveCustomDomain)
+	// Закрытие ресторана на период: запросы бронирований на эти дни получают автоответ
+	restaurants.Post("/:id/closures", r.restaurantClosureHandler.CreateRestaurantClosure)
+	restaurants.Get("/:id/closures", r.restaurantClosureHandler.ListRestaurantClosures)
+	restaurants.Delete("/:id/closures/:closureId", r.restaurantClosureHandler.DeleteRestaurantClosure)
+	// План зала: столы, за которые рассаживаются бронирования
+	restaurants.Post("/:id/tables", r.tableHandler.CreateTable)
+	restaurants.Get("/:id/tables", r.tableHandler.ListTables)
+	restaurants.Get("/:id/tables/:tableId", r.tableHandler.GetTable)
+	restaurants.Put("/:id/tables/:tableId", r.tableHandler.UpdateTable)
+	restaurants.Delete("/:id/tables/:tableId", r.tableHandler.DeleteTable)
+	restaurants.Get("/:id/widget-settings", r.widgetSettingsHandler.GetWidgetSettings)
+	restaurants.Put("/:id/widget-settings", r.widgetSettingsHandler.UpdateWidgetSettings)
+	restaurants.Get("/:id/reviews", r.reviewHandler.ListReviews)
+	restaurants.Put("/:id/reviews/:reviewId/reply", r.reviewHandler.ReplyToReview)
+	restaurants.Post("/:id/reviews/:reviewId/flag", r.reviewHandler.FlagReview)
+	restaurants.Get("/:id/notification-settings", r.notificationHandler.GetRestaurantNotificationSettings)
+	restaurants.Put("/:id/notification-settings", r.notificationHandler.UpdateRestaurantNotificationSettings)
 
 	bookings := api.Group("/bookings")
+	if r.authRequired {
+		bookings.Use(middleware.RequirePrincipalMiddleware(false))
+	}
 	bookings.Post("/", r.bookingHandler.CreateBooking)
 	bookings.Get("/:id", r.bookingHandler.GetBooking)
-	bookings.Post("/:id/confirm", r.bookingHandler.ConfirmBooking)
-	bookings.Post("/:id/reject", r.bookingHandler.RejectBooking)
-	bookings.Post("/:id/cancel", r.bookingHandler.CancelBooking)
-	bookings.Post("/:id/complete", r.bookingHandler.CompleteBooking)
-	bookings.Post("/:id/alternative", r.bookingHandler.SuggestAlternativeTime)
+	// Подтверждать и отклонять бронирования может только сам ресторан, независимо от AUTH_REQUIRED
+	restaurantSide := middleware.RequireRoleMiddleware(tenant.RoleRestaurantStaff, tenant.RoleAdmin)
+	bookings.Post("/:id/confirm", r.bookingHandler.ConfirmBooking, restaurantSide)
+	bookings.Post("/:id/reject", r.bookingHandler.RejectBooking, restaurantSide)
+	// Гости отменяют бронирования по коротким ссылкам, без входа в систему
+	bookings.Post("/:id/cancel", r.bookingHandler.CancelBooking,
+		middleware.RequireRoleMiddleware(tenant.RoleUser, tenant.RoleRestaurantStaff, tenant.RoleAdmin))
+	bookings.Post("/:id/complete", r.bookingHandler.CompleteBooking, restaurantSide)
+	bookings.Post("/:id/alternative", r.bookingHandler.SuggestAlternativeTime, restaurantSide)
+	bookings.Post("/:id/links", r.bookingLinkHandler.CreateBookingLink)
+	bookings.Get("/:id/qr", r.qrHandler.GetBookingQR)
+	bookings.Put("/:id/pre-order", r.menuHandler.UpdatePreOrder)
+	bookings.Post("/:id/review", r.reviewHandler.CreateReview)
+	bookings.Get("/:id/incentives", r.incentiveHandler.GetBookingIncentives)
 	bookings.Post("/alternatives/:id/accept", r.bookingHandler.AcceptAlternative)
 	bookings.Post("/alternatives/:id/reject", r.bookingHandler.RejectAlternative)
+	bookings.Get("/:id/transfers", r.bookingTransferHandler.ListBookingTransfers)
+	bookings.Post("/:id/transfers", r.bookingTransferHandler.RequestBookingTransfer)
+
+	// Черновики, переносы бронирований и заявки на рестораны принадлежат вошедшему пользователю
+	api.Get("/restaurant-claims/:id", r.restaurantClaimHandler.GetRestaurantClaim, middleware.RequirePrincipalMiddleware(false))
+
+	bookingDrafts := api.Group("/booking-drafts")
+	bookingDrafts.Use(middleware.RequirePrincipalMiddleware(false))
+	bookingDrafts.Post("/", r.bookingDraftHandler.CreateBookingDraft)
+	bookingDrafts.Get("/:id", r.bookingDraftHandler.GetBookingDraft)
+	bookingDrafts.Delete("/:id", r.bookingDraftHandler.DeleteBookingDraft)
+	bookingDrafts.Put("/:id/slot", r.bookingDraftHandler.HoldBookingDraftSlot)
+	bookingDrafts.Put("/:id/pre-order", r.bookingDraftHandler.UpdateBookingDraftPreOrder)
+	bookingDrafts.Post("/:id/confirm", r.bookingDraftHandler.ConfirmBookingDraft)
+
+	bookingTransfers := api.Group("/booking-transfers")
+	bookingTransfers.Use(middleware.RequireRoleMiddleware(tenant.RoleUser))
+	bookingTransfers.Post("/:id/accept", r.bookingTransferHandler.AcceptBookingTransfer)
+	bookingTransfers.Post("/:id/decline", r.bookingTransferHandler.DeclineBookingTransfer)
 
 	users := api.Group("/users")
+	if r.authRequired {
+		users.Use(middleware.RequirePrincipalMiddleware(false))
+	}
 	users.Post("/", r.userHandler.CreateUser)
 	users.Get("/:id", r.userHandler.GetUser)
 	users.Put("/:id", r.userHandler.UpdateUser)
 	users.Get("/:id/bookings", r.userHandler.GetUserBookings)
 	users.Get("/:id/notifications", r.userHandler.GetUserNotifications)
+	users.Get("/:id/notification-settings", r.notificationHandler.GetUserNotificationSettings)
+	users.Put("/:id/notification-settings", r.notificationHandler.UpdateUserNotificationSettings)
+
+	notifications := api.Group("/notifications")
+	notifications.Post("/:id/resend", r.notificationHandler.ResendNotification, middleware.RequirePrincipalMiddleware(false))
+	notifications.Post("/:id/read", r.notificationReceiptHandler.ConfirmNotificationRead)
+	notifications.Get("/:id/pixel.gif", r.notificationReceiptHandler.TrackNotificationRead)
+
+	admin := api.Group("/admin")
+	admin.Use(middleware.RequireRoleMiddleware(tenant.RoleAdmin))
+	admin.Post("/restaurants/import", r.restaurantHandler.ImportRestaurants)
+	admin.Put("/restaurants/:id/plan", r.quotaHandler.SetPlan)
+	admin.Get("/reconciliation", r.restaurantHandler.ReservedSeatsReport)
+	admin.Get("/requests", r.requestReplayHandler.ListRecordedRequests)
+	admin.Post("/requests/:id/replay", r.requestReplayHandler.ReplayRequest)
+	admin.Get("/review-flags", r.reviewHandler.ListReviewFlags)
+	admin.Post("/review-flags/:id/resolve", r.reviewHandler.ResolveReviewFlag)
+	admin.Get("/abuse-events", r.abuseHandler.ListAbuseEvents)
+	admin.Post("/retention/run", r.retentionHandler.ApplyRetention)
+	admin.Get("/retention/runs", r.retentionHandler.ListRetentionRuns)
+	admin.Get("/export", r.exportHandler.ExportChanges)
+	admin.Get("/sync/:entity", r.syncHandler.ListSyncChanges)
+	admin.Get("/notification-failures", r.notificationFailureHandler.ListNotificationFailures)
+	admin.Get("/notification-failures/counts", r.notificationFailureHandler.CountNotificationFailures)
+	admin.Get("/analytics/slow-responders", r.analyticsHandler.ListSlowResponders)
+	admin.Get("/analytics/campaigns", r.analyticsHandler.ListCampaignPerformance)
+	admin.Get("/slo", r.sloHandler.GetSLOReports)
+	admin.Get("/faults", r.faultInjectionHandler.GetFaults)
+	admin.Delete("/faults", r.faultInjectionHandler.ClearFaults)
+	admin.Post("/faults/routes", r.faultInjectionHandler.AddRouteFault)
+	admin.Delete("/faults/routes/:id", r.faultInjectionHandler.DeleteRouteFault)
+	admin.Put("/faults/db", r.faultInjectionHandler.SetDBFault)
+	admin.Post("/billing/subscriptions", r.billingHandler.CreateSubscription)
+	admin.Get("/billing/subscriptions", r.billingHandler.ListSubscriptions)
+	admin.Delete("/billing/subscriptions/:id", r.billingHandler.CancelSubscription)
+	admin.Post("/billing/invoices/generate", r.billingHandler.GenerateInvoices)
+	admin.Get("/billing/invoices", r.billingHandler.ListInvoices)
+	admin.Get("/billing/invoices/:id/pdf", r.billingHandler.GetInvoicePDF)
+	admin.Get("/incentives/rules", r.incentiveHandler.ListIncentiveRules)
+	admin.Post("/incentives/rules", r.incentiveHandler.CreateIncentiveRule)
+	admin.Put("/incentives/rules/:id", r.incentiveHandler.UpdateIncentiveRule)
+	admin.Delete("/incentives/rules/:id", r.incentiveHandler.DeleteIncentiveRule)
+	admin.Get("/incentives/evaluations", r.incentiveHandler.ListIncentiveEvaluations)
+	admin.Post("/organizations", r.organizationHandler.CreateOrganization)
+	admin.Put("/organizations/:id/restaurants/:restaurantId", r.organizationHandler.AddOrganizationRestaurant)
+	admin.Get("/restaurant-claims", r.restaurantClaimHandler.ListRestaurantClaims)
+	admin.Post("/restaurant-claims/:id/approve", r.restaurantClaimHandler.ApproveRestaurantClaim)
+	admin.Post("/restaurant-claims/:id/reject", r.restaurantClaimHandler.RejectRestaurantClaim)
+	admin.Get("/restaurants/:id/ownership", r.restaurantClaimHandler.GetRestaurantOwnership)
+
+	// Операции, накопленные приложением без сети, всегда принадлежат вошедшему пользователю
+	api.Post("/sync/bookings", r.bookingSyncHandler.SyncBookings, middleware.RequirePrincipalMiddleware(false))
 
 	facts := api.Group("/facts")
 	facts.Get("/random", r.factsHandler.GetRandomFacts)
+	facts.Get("/today", r.factsHandler.GetFactOfTheDay)
+
+	api.Get("/geo/defaults", r.geoHandler.GetGeoDefaults)
+	api.Get("/cities", r.geocodingHandler.ListCities)
+
+	// События платёжного провайдера подписываются секретом, а не ключом API
+	api.Post("/billing/webhook", r.billingHandler.PaymentWebhook)
 
 }
@@ -11,15 +11,17 @@ import (
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/configs"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
-	"github.com/gofiber/fiber/v3/middleware/cors"
+	"github.com/gofiber/fiber/v3/middleware/compress"
 	"github.com/gofiber/fiber/v3/middleware/recover"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 type ErrCustomServerShutdown struct {
@@ -50,6 +52,10 @@ type Server struct {
 	config *configs.Config
 	app    *fiber.App
 	router *Router
+
+	// certManager obtains the certificates of the public host and the custom domains; nil when
+	// the server speaks plain HTTP.
+	certManager *autocert.Manager
 }
 
 func NewServer(
@@ -61,12 +67,114 @@ func NewServer(
 	factsUseCase usecase.FactsUseCase,
 	availabilityUseCase usecase.AvailabilityUseCase,
 	notificationUseCase usecase.NotificationUseCase,
+	catalogUseCase usecase.CatalogUseCase,
+	bookingLinkUseCase usecase.BookingLinkUseCase,
+	requestReplayUseCase usecase.RequestReplayUseCase,
+	notificationReceiptUseCase usecase.NotificationReceiptUseCase,
+	menuUseCase usecase.MenuUseCase,
+	imageUseCase usecase.ImageUseCase,
+	reviewUseCase usecase.ReviewUseCase,
+	abuseUseCase usecase.AbuseUseCase,
+	geoLocator geo.Locator,
+	retentionUseCase usecase.RetentionUseCase,
+	exportUseCase usecase.ExportUseCase,
+	syncUseCase usecase.SyncUseCase,
+	notificationRetryUseCase usecase.NotificationRetryUseCase,
+	analyticsUseCase usecase.AnalyticsUseCase,
+	quotaUseCase usecase.QuotaUseCase,
+	billingUseCase usecase.BillingUseCase,
+	incentiveUseCase usecase.IncentiveUseCase,
+	geocodingUseCase usecase.GeocodingUseCase,
+	availabilityAlertUseCase usecase.AvailabilityAlertUseCase,
+	bulkCancellationUseCase usecase.BulkCancellationUseCase,
+	organizationUseCase usecase.OrganizationUseCase,
+	bookingTransferUseCase usecase.BookingTransferUseCase,
+	bookingSheetUseCase usecase.BookingSheetUseCase,
+	displayBoardUseCase usecase.DisplayBoardUseCase,
+	sloUseCase usecase.SLOUseCase,
+	faultInjectionUseCase usecase.FaultInjectionUseCase,
+	bookingSyncUseCase usecase.BookingSyncUseCase,
+	restaurantClaimUseCase usecase.RestaurantClaimUseCase,
+	customDomainUseCase usecase.CustomDomainUseCase,
+	widgetSettingsUseCase usecase.WidgetSettingsUseCase,
+	bookingDraftUseCase usecase.BookingDraftUseCase,
+	authUseCase usecase.AuthUseCase,
+	restaurantClosureUseCase usecase.RestaurantClosureUseCase,
+	tableUseCase usecase.TableUseCase,
 ) (*Server, error) {
+	// Widgets are fetched by restaurant websites, which are not among the API frontends.
+	corsMiddleware, err := middleware.CORSMiddleware(middleware.CORSPolicy{
+		AllowOrigins:     config.CORS.AllowedOrigins,
+		AllowMethods:     config.CORS.AllowedMethods,
+		AllowHeaders:     config.CORS.AllowedHeaders,
+		ExposeHeaders:    config.CORS.ExposedHeaders,
+		AllowCredentials: config.CORS.AllowCredentials,
+		MaxAge:           config.CORS.MaxAge,
+	}, middleware.CORSRoute{
+		Prefix: "/embed",
+		Policy: middleware.CORSPolicy{
+			AllowOrigins: config.Embed.AllowedOrigins,
+			AllowMethods: []string{fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions},
+			MaxAge:       config.CORS.MaxAge,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrConfigureCORS, err)
+	}
+
+	// These routes work through many rows at a time; the export streams after its handler has
+	// returned, so only the client bounds it.
+	longTimeout := config.Server.LongRequestTimeout
+	timeoutRoutes := []middleware.TimeoutRoute{
+		{Method: fiber.MethodPost, Path: "/api/v1/restaurants/import", Timeout: longTimeout},
+		{Method: fiber.MethodPost, Path: "/api/v1/restaurants/:id/availability/generate", Timeout: longTimeout},
+		{Method: fiber.MethodPost, Path: "/api/v1/restaurants/:id/menu/import", Timeout: longTimeout},
+		{Method: fiber.MethodPost, Path: "/api/v1/admin/restaurants/import", Timeout: longTimeout},
+		{Method: fiber.MethodGet, Path: "/api/v1/admin/reconciliation", Timeout: longTimeout},
+		{Method: fiber.MethodPost, Path: "/api/v1/admin/retention/run", Timeout: longTimeout},
+		{Method: fiber.MethodGet, Path: "/api/v1/admin/export"},
+	}
+
+	// Custom domains are looked up for the other hosts only.
+	publicHost := config.Server.PublicHost()
+	platformHosts := []string{publicHost}
+	cnameTarget := config.Domains.CNAMETarget
+	if cnameTarget == "" {
+		cnameTarget = publicHost
+	}
+
+	var gatewayTrust middleware.GatewayTrust
+	if config.Auth.TrustGatewayHeaders {
+		gatewayTrust, err = middleware.NewGatewayTrust(config.Auth.GatewayNetworks, config.Auth.GatewaySecret)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", common.ErrConfigureGatewayTrust, err)
+		}
+	}
+
+	bodyLimit := config.Server.BodyLimit
+	if bodyLimit <= 0 {
+		bodyLimit = fiber.DefaultBodyLimit
+	}
+
 	app := fiber.New(fiber.Config{
-		AppName: "Restaurant Booking API",
+		AppName:   "Restaurant Booking API",
+		BodyLimit: bodyLimit,
 		ErrorHandler: func(c fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				code = fiberErr.Code
+			}
+
+			// Oversized bodies are rejected before any middleware runs, so there is no request
+			// context to log with yet.
+			if code == fiber.StatusRequestEntityTooLarge {
+				return wrapFiberError(c.Status(code).JSON(fiber.Map{
+					"error": fmt.Sprintf("%s of %d bytes", common.ErrRequestBodyTooLarge, bodyLimit),
+				}))
+			}
+
 			ctxValue, ok := c.Locals("ctx").(context.Context)
 			if !ok {
 				return wrapFiberError(c.Status(code).JSON(fiber.Map{
@@ -85,11 +193,6 @@ func NewServer(
 				log = defaultLog
 			}
 
-			var fiberErr *fiber.Error
-			if errors.As(err, &fiberErr) {
-				code = fiberErr.Code
-			}
-
 			log.Error(ctx, common.MsgHTTPError,
 				zap.Error(err),
 				zap.Int("status", code),
@@ -103,16 +206,78 @@ func NewServer(
 	})
 
 	app.Use(recover.New())
-	app.Use(cors.New())
-	app.Use(middleware.LoggingMiddleware())
+	app.Use(corsMiddleware)
+	app.Use(compress.New(compress.Config{
+		Level: compress.Level(config.Server.CompressionLevel),
+	}))
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{
+		Routes: config.Logging.SampledReadRoutes,
+		Rate:   config.Logging.ReadSampleRate,
+	}))
+	app.Use(middleware.PrincipalMiddleware(authUseCase, gatewayTrust))
+	app.Use(middleware.CustomDomainMiddleware(customDomainUseCase, platformHosts))
+	app.Use(middleware.GeoMiddleware(geoLocator))
+	app.Use(middleware.SLOMiddleware(sloUseCase, []string{"/health"}))
+	app.Use(middleware.ConcurrencyMiddleware(middleware.ConcurrencyLimits{
+		MaxInFlight:         config.Concurrency.MaxInFlight,
+		MaxInFlightPerRoute: config.Concurrency.MaxInFlightPerRoute,
+		MaxQueue:            config.Concurrency.MaxQueue,
+		QueueTimeout:        config.Concurrency.QueueTimeout,
+		RetryAfter:          config.Concurrency.RetryAfter,
+		Exempt:              []string{"/health"},
+	}))
+	app.Use(middleware.RateLimitMiddleware(abuseUseCase))
+	app.Use(middleware.RequestRecorderMiddleware(requestReplayUseCase))
+	app.Use(middleware.EnvelopeMiddleware(config.Server.ResponseEnvelope))
+	app.Use(middleware.TimeoutMiddleware(config.Server.RequestTimeout, timeoutRoutes...))
+	app.Use(middleware.FaultInjectionMiddleware(faultInjectionUseCase))
 
 	restaurantHandler := handlers.NewRestaurantHandler(restaurantUseCase, bookingUseCase, availabilityUseCase)
-	bookingHandler := handlers.NewBookingHandler(bookingUseCase)
+	bookingHandler := handlers.NewBookingHandler(bookingUseCase, notificationReceiptUseCase, menuUseCase)
 	userHandler := handlers.NewUserHandler(userUseCase, bookingUseCase, notificationUseCase)
 	factsHandler := handlers.NewFactsHandler(factsUseCase)
+	catalogHandler := handlers.NewCatalogHandler(catalogUseCase, config.Server.PublicURL)
+	notificationHandler := handlers.NewNotificationHandler(notificationUseCase)
+	bookingLinkHandler := handlers.NewBookingLinkHandler(bookingLinkUseCase, bookingUseCase)
+	qrHandler := handlers.NewQRHandler(restaurantUseCase, bookingLinkUseCase, config.Server.PublicURL)
+	requestReplayHandler := handlers.NewRequestReplayHandler(requestReplayUseCase)
+	notificationReceiptHandler := handlers.NewNotificationReceiptHandler(notificationReceiptUseCase)
+	menuHandler := handlers.NewMenuHandler(menuUseCase)
+	imageHandler := handlers.NewImageHandler(imageUseCase, config.Server.PublicURL)
+	reviewHandler := handlers.NewReviewHandler(reviewUseCase)
+	abuseHandler := handlers.NewAbuseHandler(abuseUseCase)
+	geoHandler := handlers.NewGeoHandler()
+	retentionHandler := handlers.NewRetentionHandler(retentionUseCase)
+	exportHandler := handlers.NewExportHandler(exportUseCase)
+	syncHandler := handlers.NewSyncHandler(syncUseCase)
+	notificationFailureHandler := handlers.NewNotificationFailureHandler(notificationRetryUseCase)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsUseCase)
+	quotaHandler := handlers.NewQuotaHandler(quotaUseCase)
+	billingHandler := handlers.NewBillingHandler(billingUseCase)
+	incentiveHandler := handlers.NewIncentiveHandler(incentiveUseCase)
+	geocodingHandler := handlers.NewGeocodingHandler(geocodingUseCase)
+	availabilityAlertHandler := handlers.NewAvailabilityAlertHandler(availabilityAlertUseCase)
+	bulkCancellationHandler := handlers.NewBulkCancellationHandler(bulkCancellationUseCase)
+	organizationHandler := handlers.NewOrganizationHandler(organizationUseCase)
+	bookingTransferHandler := handlers.NewBookingTransferHandler(bookingTransferUseCase)
+	bookingSheetHandler := handlers.NewBookingSheetHandler(bookingSheetUseCase)
+	displayBoardHandler := handlers.NewDisplayBoardHandler(displayBoardUseCase)
+	sloHandler := handlers.NewSLOHandler(sloUseCase)
+	faultInjectionHandler := handlers.NewFaultInjectionHandler(faultInjectionUseCase)
+	bookingSyncHandler := handlers.NewBookingSyncHandler(bookingSyncUseCase)
+	restaurantClaimHandler := handlers.NewRestaurantClaimHandler(restaurantClaimUseCase)
+	customDomainHandler := handlers.NewCustomDomainHandler(customDomainUseCase, cnameTarget)
+	bookingPageHandler := handlers.NewBookingPageHandler(restaurantUseCase, availabilityUseCase, config.Embed.Days)
+	widgetSettingsHandler := handlers.NewWidgetSettingsHandler(widgetSettingsUseCase, restaurantUseCase, config.Server.PublicURL)
+	bookingDraftHandler := handlers.NewBookingDraftHandler(bookingDraftUseCase)
+	authHandler := handlers.NewAuthHandler(authUseCase)
+	restaurantClosureHandler := handlers.NewRestaurantClosureHandler(restaurantClosureUseCase)
+	tableHandler := handlers.NewTableHandler(tableUseCase)
+	embedHandler := handlers.NewEmbedHandler(restaurantUseCase, availabilityUseCase, config.Server.PublicURL, config.Embed.Days, config.Embed.FrameAncestors)
 
 	router := NewRouter()
-	router.SetHandlers(restaurantHandler, bookingHandler, userHandler, factsHandler)
+	router.SetHandlers(restaurantHandler, bookingHandler, userHandler, factsHandler, catalogHandler, notificationHandler, bookingLinkHandler, qrHandler, requestReplayHandler, notificationReceiptHandler, menuHandler, imageHandler, reviewHandler, abuseHandler, geoHandler, retentionHandler, exportHandler, syncHandler, embedHandler, notificationFailureHandler, analyticsHandler, quotaHandler, billingHandler, incentiveHandler, geocodingHandler, availabilityAlertHandler, bulkCancellationHandler, organizationHandler, bookingTransferHandler, bookingSheetHandler, displayBoardHandler, sloHandler, faultInjectionHandler, bookingSyncHandler, restaurantClaimHandler, customDomainHandler, bookingPageHandler, widgetSettingsHandler, bookingDraftHandler, authHandler, restaurantClosureHandler, tableHandler)
+	router.SetAuthRequired(config.Auth.Required)
 
 	s := &Server{
 		config: config,
@@ -120,6 +285,23 @@ func NewServer(
 		router: router,
 	}
 
+	if config.Domains.TLS {
+		s.certManager = &autocert.Manager{
+			Prompt: autocert.AcceptTOS,
+			Cache:  autocert.DirCache(config.Domains.CertCacheDir),
+			Email:  config.Domains.ACMEEmail,
+			// Certificates are only requested for the public host and verified custom domains, so
+			// that requests naming other hosts can't spend the rate limits of the authority.
+			HostPolicy: func(ctx context.Context, host string) error {
+				if host == publicHost {
+					return nil
+				}
+				_, err := customDomainUseCase.ResolveHost(ctx, host)
+				return err
+			},
+		}
+	}
+
 	return s, nil
 }
 
@@ -147,7 +329,7 @@ func (s *Server) Start(ctx context.Context) error {
 		log.Info(ctx, common.MsgServerStarting,
 			zap.String("address", serverAddr))
 
-		if err := s.app.Listen(serverAddr); err != nil {
+		if err := s.app.Listen(serverAddr, fiber.ListenConfig{AutoCertManager: s.certManager}); err != nil {
 			log.Error(ctx, common.MsgServerStartError, zap.Error(err))
 			os.Exit(1)
 		}
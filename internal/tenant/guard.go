@@ -0,0 +1,68 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrAccessDenied = errors.New("access denied")
+
+// Strict makes every guard violation panic instead of returning an error, so that a query missing
+// its ownership filter fails loudly in development and tests.
+var Strict = false
+
+// IsolationError reports a record of another tenant that reached the principal of the request.
+type IsolationError struct {
+	PrincipalUserID string
+	Resource        string
+	ResourceID      string
+}
+
+func (e *IsolationError) Error() string {
+	return fmt.Sprintf("%s: %s %s is not accessible to user %q", ErrAccessDenied, e.Resource, e.ResourceID, e.PrincipalUserID)
+}
+
+func (e *IsolationError) Unwrap() error {
+	return ErrAccessDenied
+}
+
+// GuardUser passes when ctx has no principal or the principal is the user.
+func GuardUser(ctx context.Context, resource, resourceID, userID string) error {
+	principal, ok := FromContext(ctx)
+	if !ok || principal.CanAccessUser(userID) {
+		return nil
+	}
+	return violation(principal, resource, resourceID)
+}
+
+// GuardRestaurant passes when ctx has no principal or the principal works for the restaurant.
+func GuardRestaurant(ctx context.Context, resource, resourceID, restaurantID string) error {
+	principal, ok := FromContext(ctx)
+	if !ok || principal.CanAccessRestaurant(restaurantID) {
+		return nil
+	}
+	return violation(principal, resource, resourceID)
+}
+
+// GuardOwner passes for records shared by a user and a restaurant, such as bookings,
+// when the principal is either of them.
+func GuardOwner(ctx context.Context, resource, resourceID, userID, restaurantID string) error {
+	principal, ok := FromContext(ctx)
+	if !ok || principal.CanAccessUser(userID) || principal.CanAccessRestaurant(restaurantID) {
+		return nil
+	}
+	return violation(principal, resource, resourceID)
+}
+
+func violation(principal *Principal, resource, resourceID string) error {
+	err := &IsolationError{
+		PrincipalUserID: principal.UserID,
+		Resource:        resource,
+		ResourceID:      resourceID,
+	}
+	if Strict {
+		panic(err)
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+// Package tenant carries the principal and client address of a request in its context and provides guards that
+// repositories use to make sure data of other users and restaurants never reaches it.
+package tenant
+
+import (
+	"context"
+	"slices"
+)
+
+type Role string
+
+const (
+	// RoleGuest is the role of a request without a principal; no principal carries it.
+	RoleGuest Role = "guest"
+
+	RoleUser Role = "user"
+
+	RoleRestaurantStaff Role = "restaurant_staff"
+
+	RoleAdmin Role = "admin"
+)
+
+// Principal is the caller of a request: the user, the restaurants the user works for and the roles.
+type Principal struct {
+	UserID        string
+	RestaurantIDs []string
+	Roles         []Role
+	// AccountID is the account the principal signed in with; empty for a principal named by the
+	// gateway.
+	AccountID string
+}
+
+func (p *Principal) HasRole(role Role) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (p *Principal) IsAdmin() bool {
+	return p.HasRole(RoleAdmin)
+}
+
+// IsRestaurantStaff reports whether the principal works for a restaurant: a restaurant account,
+// or a user the gateway names restaurants for.
+func (p *Principal) IsRestaurantStaff() bool {
+	return p.HasRole(RoleRestaurantStaff) || len(p.RestaurantIDs) > 0
+}
+
+func (p *Principal) CanAccessUser(userID string) bool {
+	return p.IsAdmin() || (p.UserID != "" && p.UserID == userID)
+}
+
+func (p *Principal) CanAccessRestaurant(restaurantID string) bool {
+	return p.IsAdmin() || slices.Contains(p.RestaurantIDs, restaurantID)
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// SystemContext returns ctx carrying the principal of background jobs and operator tools: an admin
+// acting on behalf of no user.
+func SystemContext(ctx context.Context) context.Context {
+	return NewContext(ctx, &Principal{Roles: []Role{RoleAdmin}})
+}
+
+// FromContext returns the principal of the request; background jobs and tools run without one
+// unless they act as admins through SystemContext.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// HasRole reports whether the caller of ctx has any of the roles. A request without a principal
+// has RoleGuest only.
+func HasRole(ctx context.Context, roles ...Role) bool {
+	principal, ok := FromContext(ctx)
+	for _, role := range roles {
+		switch {
+		case !ok:
+			if role == RoleGuest {
+				return true
+			}
+		case role == RoleRestaurantStaff:
+			if principal.IsRestaurantStaff() {
+				return true
+			}
+		case principal.HasRole(role):
+			return true
+		}
+	}
+	return false
+}
+
+type clientIPKey struct{}
+
+// WithClientIP stores the network address the request came from.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the address the request came from, or an empty string outside of
+// requests.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
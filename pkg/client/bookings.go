@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+func (c *Client) GetBooking(ctx context.Context, id string) (*domain.Booking, error) {
+	var booking domain.Booking
+	if err := c.do(ctx, http.MethodGet, "/bookings/"+url.PathEscape(id), nil, nil, &booking); err != nil {
+		return nil, err
+	}
+
+	return &booking, nil
+}
+
+func (c *Client) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	body := struct {
+		RestaurantID string                 `json:"restaurant_id"`
+		UserID       string                 `json:"user_id"`
+		Date         time.Time              `json:"date"`
+		Time         string                 `json:"time"`
+		Duration     int                    `json:"duration"`
+		GuestsCount  int                    `json:"guests_count"`
+		Comment      string                 `json:"comment"`
+		IsTest       bool                   `json:"is_test"`
+		Occasion     domain.BookingOccasion `json:"occasion,omitempty"`
+		AgeAttested  bool                   `json:"age_attested"`
+	}{
+		RestaurantID: booking.RestaurantID,
+		UserID:       booking.UserID,
+		Date:         booking.Date,
+		Time:         booking.Time,
+		Duration:     booking.Duration,
+		GuestsCount:  booking.GuestsCount,
+		Comment:      booking.Comment,
+		IsTest:       booking.IsTest,
+		Occasion:     booking.Occasion,
+		AgeAttested:  booking.AgeAttested,
+	}
+
+	var resp idResponse
+	if err := c.do(ctx, http.MethodPost, "/bookings", nil, body, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) ConfirmBooking(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/bookings/"+url.PathEscape(id)+"/confirm", nil, nil, nil)
+}
+
+func (c *Client) RejectBooking(ctx context.Context, id string, reason string) error {
+	body := struct {
+		Reason string `json:"reason"`
+	}{Reason: reason}
+
+	return c.do(ctx, http.MethodPost, "/bookings/"+url.PathEscape(id)+"/reject", nil, body, nil)
+}
+
+func (c *Client) CancelBooking(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/bookings/"+url.PathEscape(id)+"/cancel", nil, nil, nil)
+}
+
+func (c *Client) CompleteBooking(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/bookings/"+url.PathEscape(id)+"/complete", nil, nil, nil)
+}
+
+func (c *Client) SuggestAlternativeTime(ctx context.Context, bookingID string, date time.Time, timeSlot string, message string) (string, error) {
+	body := struct {
+		Date    time.Time `json:"date"`
+		Time    string    `json:"time"`
+		Message string    `json:"message"`
+	}{Date: date, Time: timeSlot, Message: message}
+
+	var resp idResponse
+	if err := c.do(ctx, http.MethodPost, "/bookings/"+url.PathEscape(bookingID)+"/alternative", nil, body, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) AcceptAlternative(ctx context.Context, alternativeID string) error {
+	return c.do(ctx, http.MethodPost, "/bookings/alternatives/"+url.PathEscape(alternativeID)+"/accept", nil, nil, nil)
+}
+
+func (c *Client) RejectAlternative(ctx context.Context, alternativeID string) error {
+	return c.do(ctx, http.MethodPost, "/bookings/alternatives/"+url.PathEscape(alternativeID)+"/reject", nil, nil, nil)
+}
@@ -0,0 +1,256 @@
+// Package client is a typed Go client of the Restaurant Booking REST API. Methods mirror
+// the use case contracts of pkg/usecase and work with the internal/domain entities, so
+// other services consume the API without duplicating DTOs.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// HeaderIdempotencyKey is sent with every mutating request; retries of the same call reuse the key.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderNextCursor carries the cursor of the next page of a list; it is absent on the last page.
+	HeaderNextCursor = "X-Next-Cursor"
+
+	defaultTimeout    = 10 * time.Second
+	defaultMaxRetries = 3
+	defaultBackoff    = 200 * time.Millisecond
+	maxBackoff        = 5 * time.Second
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	backoff    time.Duration
+	newKey     func() string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default HTTP client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the initial backoff between attempts.
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithIdempotencyKeyGenerator replaces the generator of idempotency keys.
+func WithIdempotencyKeyGenerator(newKey func() string) Option {
+	return func(c *Client) {
+		c.newKey = newKey
+	}
+}
+
+// New creates a client of the API served at baseURL, e.g. "http://localhost:8080/api/v1".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+		newKey:     uuid.NewString,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey makes the next mutating call made with ctx use the given key
+// instead of a generated one, so callers can safely repeat a call across restarts.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+type idResponse struct {
+	ID string `json:"id"`
+}
+
+// do sends the request, retrying transport errors and retryable statuses with exponential
+// backoff, and decodes a successful response into out when it is not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	_, err := c.send(ctx, method, path, query, in, out)
+	return err
+}
+
+// send is do returning the headers of the successful response as well.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, in, out any) (http.Header, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var idempotencyKey string
+	if method != http.MethodGet {
+		idempotencyKey, _ = ctx.Value(idempotencyKeyCtxKey{}).(string)
+		if idempotencyKey == "" {
+			idempotencyKey = c.newKey()
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := c.wait(ctx, attempt); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(HeaderIdempotencyKey, idempotencyKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		lastErr = handleResponse(resp, out)
+		if lastErr == nil {
+			return resp.Header, nil
+		}
+		if !isRetryable(lastErr) {
+			return nil, lastErr
+		}
+	}
+
+	return nil, lastErr
+}
+
+// getPage reads a page of a list paginated with a cursor; the cursor of the next page comes in
+// the HeaderNextCursor header.
+func getPage[T any](ctx context.Context, c *Client, path string, query url.Values, page usecase.PageRequest) (*usecase.PageResponse[T], error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	if page.Cursor != "" {
+		query.Set("cursor", page.Cursor)
+	}
+	if page.Limit > 0 {
+		query.Set("limit", strconv.Itoa(page.Limit))
+	}
+
+	result := &usecase.PageResponse[T]{}
+	header, err := c.send(ctx, http.MethodGet, path, query, nil, &result.Items)
+	if err != nil {
+		return nil, err
+	}
+	result.NextCursor = header.Get(HeaderNextCursor)
+
+	return result, nil
+}
+
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoff << (attempt - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	delay += time.Duration(rand.Int64N(int64(delay)/2 + 1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func handleResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		var errBody struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &errBody) == nil {
+			apiErr.Message = errBody.Error
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
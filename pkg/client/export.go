@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+// ErrIncompleteExport is returned when an export stream ends without its checkpoint.
+var ErrIncompleteExport = errors.New("incomplete export")
+
+// exportLine is a line of the export stream: a record or, last, the trailer.
+type exportLine struct {
+	domain.ExportRecord
+	Checkpoint *time.Time `json:"checkpoint"`
+	Error      string     `json:"error"`
+}
+
+// ExportChanges streams the records updated after since, of the given entities or of every entity,
+// passing each to fn, and returns the checkpoint for the next export. The stream is not retried;
+// on any error the export is repeated from the same since.
+func (c *Client) ExportChanges(ctx context.Context, since time.Time, entities []domain.ExportEntity, fn func(*domain.ExportRecord) error) (time.Time, error) {
+	query := url.Values{}
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339Nano))
+	}
+	if len(entities) > 0 {
+		names := make([]string, len(entities))
+		for i, entity := range entities {
+			names[i] = string(entity)
+		}
+		query.Set("entities", strings.Join(names, ","))
+	}
+
+	endpoint := c.baseURL + "/admin/export"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	// The export takes as long as there are changes; only ctx bounds it.
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return time.Time{}, handleResponse(resp, nil)
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var line exportLine
+		if err := dec.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				return time.Time{}, ErrIncompleteExport
+			}
+			return time.Time{}, fmt.Errorf("decode export: %w", err)
+		}
+
+		switch {
+		case line.Error != "":
+			return time.Time{}, fmt.Errorf("%w: %s", ErrIncompleteExport, line.Error)
+		case line.Checkpoint != nil:
+			return *line.Checkpoint, nil
+		}
+
+		if err := fn(&line.ExportRecord); err != nil {
+			return time.Time{}, err
+		}
+	}
+}
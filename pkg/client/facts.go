@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+)
+
+func (c *Client) GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error) {
+	return c.GetFilteredRandomFacts(ctx, domain.FactFilter{}, count)
+}
+
+func (c *Client) GetFilteredRandomFacts(ctx context.Context, filter domain.FactFilter, count int) ([]domain.Fact, error) {
+	query := url.Values{}
+	query.Set("count", strconv.Itoa(count))
+	if filter.RestaurantID != "" {
+		query.Set("restaurant_id", filter.RestaurantID)
+	}
+	if filter.Cuisine != "" {
+		query.Set("cuisine", string(filter.Cuisine))
+	}
+
+	var facts []domain.Fact
+	if err := c.do(ctx, http.MethodGet, "/facts/random", query, nil, &facts); err != nil {
+		return nil, err
+	}
+
+	return facts, nil
+}
+
+func (c *Client) GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error) {
+	query := url.Values{}
+	if locale != "" {
+		query.Set("locale", locale)
+	}
+
+	var fact domain.Fact
+	if err := c.do(ctx, http.MethodGet, "/facts/today", query, nil, &fact); err != nil {
+		return nil, err
+	}
+
+	return &fact, nil
+}
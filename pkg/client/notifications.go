@@ -0,0 +1,11 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+func (c *Client) ResendNotification(ctx context.Context, notificationID string) error {
+	return c.do(ctx, http.MethodPost, "/notifications/"+url.PathEscape(notificationID)+"/resend", nil, nil, nil)
+}
@@ -0,0 +1,279 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+type restaurantBody struct {
+	Name         string         `json:"name"`
+	Slug         string         `json:"slug,omitempty"`
+	Address      string         `json:"address"`
+	Cuisine      domain.Cuisine `json:"cuisine"`
+	Description  string         `json:"description"`
+	ContactEmail string         `json:"contact_email"`
+	ContactPhone string         `json:"contact_phone"`
+	Facts        []string       `json:"facts,omitempty"`
+	IsTest       bool           `json:"is_test"`
+	IsAdultOnly  bool           `json:"is_adult_only"`
+	Currency     string         `json:"currency,omitempty"`
+}
+
+func newRestaurantBody(restaurant *domain.Restaurant) restaurantBody {
+	body := restaurantBody{
+		Name:         restaurant.Name,
+		Slug:         restaurant.Slug,
+		Address:      restaurant.Address,
+		Cuisine:      restaurant.Cuisine,
+		Description:  restaurant.Description,
+		ContactEmail: restaurant.ContactEmail,
+		ContactPhone: restaurant.ContactPhone,
+		IsTest:       restaurant.IsTest,
+		IsAdultOnly:  restaurant.IsAdultOnly,
+		Currency:     restaurant.Currency,
+	}
+	for _, fact := range restaurant.Facts {
+		body.Facts = append(body.Facts, fact.Content)
+	}
+
+	return body
+}
+
+func (c *Client) GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error) {
+	var restaurant domain.Restaurant
+	if err := c.do(ctx, http.MethodGet, "/restaurants/"+url.PathEscape(id), nil, nil, &restaurant); err != nil {
+		return nil, err
+	}
+
+	return &restaurant, nil
+}
+
+// GetRestaurantBySlug follows the redirect of a previous slug, so the returned restaurant carries
+// the current one.
+func (c *Client) GetRestaurantBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	var restaurant domain.Restaurant
+	if err := c.do(ctx, http.MethodGet, "/restaurants/by-slug/"+url.PathEscape(slug), nil, nil, &restaurant); err != nil {
+		return nil, err
+	}
+
+	return &restaurant, nil
+}
+
+// ListRestaurants returns a page of the restaurants ordered by name, narrowed to the city and the
+// adult-only venues of the filter.
+func (c *Client) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page usecase.PageRequest) (*usecase.PageResponse[*domain.Restaurant], error) {
+	query := url.Values{}
+	if filter.CityID != "" {
+		query.Set("city_id", filter.CityID)
+	}
+	if filter.AdultOnly != nil {
+		query.Set("adult_only", strconv.FormatBool(*filter.AdultOnly))
+	}
+
+	return getPage[*domain.Restaurant](ctx, c, "/restaurants", query, page)
+}
+
+// CreateRestaurant creates the restaurant; the content of restaurant.Facts is added as its facts.
+func (c *Client) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
+	var resp idResponse
+	if err := c.do(ctx, http.MethodPost, "/restaurants", nil, newRestaurantBody(restaurant), &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+// ExportRestaurant returns the portable bundle of a restaurant, see ImportRestaurantBundle.
+func (c *Client) ExportRestaurant(ctx context.Context, id string) (*usecase.RestaurantBundle, error) {
+	var bundle usecase.RestaurantBundle
+	if err := c.do(ctx, http.MethodGet, "/restaurants/"+url.PathEscape(id)+"/export", nil, nil, &bundle); err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+func (c *Client) ImportRestaurantBundle(ctx context.Context, bundle *usecase.RestaurantBundle) (string, error) {
+	var resp idResponse
+	if err := c.do(ctx, http.MethodPost, "/restaurants/import", nil, bundle, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) UpdateRestaurant(ctx context.Context, restaurant *domain.Restaurant) error {
+	body := newRestaurantBody(restaurant)
+	body.Facts = nil
+
+	return c.do(ctx, http.MethodPut, "/restaurants/"+url.PathEscape(restaurant.ID), nil, body, nil)
+}
+
+func (c *Client) DeleteRestaurant(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/restaurants/"+url.PathEscape(id), nil, nil, nil)
+}
+
+func (c *Client) AddFact(ctx context.Context, restaurantID, content, locale string) (*domain.Fact, error) {
+	body := struct {
+		Content string `json:"content"`
+		Locale  string `json:"locale,omitempty"`
+	}{Content: content, Locale: locale}
+
+	var fact domain.Fact
+	if err := c.do(ctx, http.MethodPost, "/restaurants/"+url.PathEscape(restaurantID)+"/facts", nil, body, &fact); err != nil {
+		return nil, err
+	}
+
+	return &fact, nil
+}
+
+func (c *Client) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+	var facts []domain.Fact
+	if err := c.do(ctx, http.MethodGet, "/restaurants/"+url.PathEscape(restaurantID)+"/facts", nil, nil, &facts); err != nil {
+		return nil, err
+	}
+
+	return facts, nil
+}
+
+func (c *Client) SetWorkingHours(ctx context.Context, restaurantID string, workingHours *domain.WorkingHours) error {
+	body := struct {
+		WeekDay   domain.WeekDay `json:"week_day"`
+		OpenTime  string         `json:"open_time"`
+		CloseTime string         `json:"close_time"`
+		ValidFrom time.Time      `json:"valid_from"`
+		ValidTo   time.Time      `json:"valid_to"`
+	}{
+		WeekDay:   workingHours.WeekDay,
+		OpenTime:  workingHours.OpenTime,
+		CloseTime: workingHours.CloseTime,
+		ValidFrom: workingHours.ValidFrom,
+		ValidTo:   workingHours.ValidTo,
+	}
+
+	return c.do(ctx, http.MethodPost, "/restaurants/"+url.PathEscape(restaurantID)+"/working-hours", nil, body, nil)
+}
+
+func (c *Client) GetWorkingHours(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+	var workingHours []*domain.WorkingHours
+	if err := c.do(ctx, http.MethodGet, "/restaurants/"+url.PathEscape(restaurantID)+"/working-hours", nil, nil, &workingHours); err != nil {
+		return nil, err
+	}
+
+	return workingHours, nil
+}
+
+// SetAvailability fails with ErrConflict when the capacity is below the reserved seats of the slot.
+func (c *Client) SetAvailability(ctx context.Context, availability *domain.Availability) error {
+	return c.do(ctx, http.MethodPost, "/restaurants/"+url.PathEscape(availability.RestaurantID)+"/availability", nil, newAvailabilityBody(availability), nil)
+}
+
+// ForceSetAvailability sets the capacity even below the reserved seats and returns the impacted bookings.
+func (c *Client) ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	var resp struct {
+		ImpactedBookings []*domain.Booking `json:"impacted_bookings"`
+	}
+	query := url.Values{"force": {"true"}}
+	if err := c.do(ctx, http.MethodPost, "/restaurants/"+url.PathEscape(availability.RestaurantID)+"/availability", query, newAvailabilityBody(availability), &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.ImpactedBookings, nil
+}
+
+type availabilityBody struct {
+	Date     time.Time `json:"date"`
+	TimeSlot string    `json:"time_slot"`
+	Capacity int       `json:"capacity"`
+}
+
+func newAvailabilityBody(availability *domain.Availability) availabilityBody {
+	return availabilityBody{
+		Date:     availability.Date,
+		TimeSlot: availability.TimeSlot,
+		Capacity: availability.Capacity,
+	}
+}
+
+func (c *Client) GetAvailability(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+	query := url.Values{}
+	query.Set("date", date.Format(time.DateOnly))
+
+	var availability []*domain.Availability
+	if err := c.do(ctx, http.MethodGet, "/restaurants/"+url.PathEscape(restaurantID)+"/availability", query, nil, &availability); err != nil {
+		return nil, err
+	}
+
+	return availability, nil
+}
+
+func (c *Client) GetRestaurantBookings(
+	ctx context.Context,
+	restaurantID string,
+	filter domain.BookingFilter,
+	page usecase.PageRequest,
+) (*usecase.PageResponse[*domain.Booking], error) {
+	query := url.Values{}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	if filter.Date != nil {
+		query.Set("date", filter.Date.Format(time.DateOnly))
+	}
+	if filter.Occasion != "" {
+		query.Set("occasion", string(filter.Occasion))
+	}
+
+	return getPage[*domain.Booking](ctx, c, "/restaurants/"+url.PathEscape(restaurantID)+"/bookings", query, page)
+}
+
+func (c *Client) GenerateAvailability(ctx context.Context, params usecase.GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	body := struct {
+		From        string `json:"from"`
+		To          string `json:"to"`
+		SlotMinutes int    `json:"slot_minutes"`
+		Capacity    int    `json:"capacity"`
+	}{
+		From:        params.From.Format(time.DateOnly),
+		To:          params.To.Format(time.DateOnly),
+		SlotMinutes: int(params.SlotDuration / time.Minute),
+		Capacity:    params.Capacity,
+	}
+
+	var query url.Values
+	if params.DryRun {
+		query = url.Values{"dry_run": {"true"}}
+	}
+
+	var generated []*domain.Availability
+	if err := c.do(ctx, http.MethodPost, "/restaurants/"+url.PathEscape(params.RestaurantID)+"/availability/generate", query, body, &generated); err != nil {
+		return nil, err
+	}
+
+	return generated, nil
+}
+
+// ReservedSeatsReport lists the slots of the date whose reserved seats differ from their active
+// bookings; with fix the server corrects them.
+func (c *Client) ReservedSeatsReport(ctx context.Context, date time.Time, fix bool) ([]domain.ReservedSeatsDrift, error) {
+	query := url.Values{}
+	query.Set("date", date.Format(time.DateOnly))
+	if fix {
+		query.Set("fix", "true")
+	}
+
+	var resp struct {
+		Mismatches []domain.ReservedSeatsDrift `json:"mismatches"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/admin/reconciliation", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Mismatches, nil
+}
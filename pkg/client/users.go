@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+)
+
+type userBody struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	var user domain.User
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(id), nil, nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (c *Client) CreateUser(ctx context.Context, user *domain.User) (string, error) {
+	body := userBody{Name: user.Name, Email: user.Email, Phone: user.Phone}
+
+	var resp idResponse
+	if err := c.do(ctx, http.MethodPost, "/users", nil, body, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.ID, nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, user *domain.User) error {
+	body := userBody{Name: user.Name, Email: user.Email, Phone: user.Phone}
+
+	return c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(user.ID), nil, body, nil)
+}
+
+func (c *Client) GetUserBookings(ctx context.Context, userID string, page usecase.PageRequest) (*usecase.PageResponse[*domain.Booking], error) {
+	return getPage[*domain.Booking](ctx, c, "/users/"+url.PathEscape(userID)+"/bookings", nil, page)
+}
+
+func (c *Client) GetUserNotifications(ctx context.Context, userID string) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(userID)+"/notifications", nil, nil, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidAbuseEventKind = errors.New("invalid abuse event kind")
+
+const (
+	// maxAbuseClients bounds the memory taken by the tracked clients; the client seen least
+	// recently is forgotten first.
+	maxAbuseClients = 10000
+
+	// maxAbuseEvents is how many of the latest events are kept for admins.
+	maxAbuseEvents = 1000
+
+	rateLimitPeriod = time.Minute
+)
+
+// AbuseRules sets when a client counts as suspicious and how it is slowed down. Zero thresholds
+// and limits turn the matching check off.
+type AbuseRules struct {
+	Window             time.Duration
+	BookingsPerClient  int
+	CancelRebookCycles int
+
+	// RateLimit is how many write requests a client may make per minute.
+	RateLimit int
+
+	// AutoTighten lowers the rate limit of a flagged client to TightenedRateLimit for TightenFor.
+	AutoTighten        bool
+	TightenedRateLimit int
+	TightenFor         time.Duration
+}
+
+// AbuseUseCase watches the bookings of every client, known by its address or, without one, by
+// its user, for patterns no guest would make, and keeps the suspicious ones in memory for admins.
+// It also rate-limits the write requests of clients, more tightly for flagged ones when enabled.
+type AbuseUseCase interface {
+	// RecordBooking notes a booking made by the client of the request.
+	RecordBooking(ctx context.Context, booking *domain.Booking)
+
+	// RecordCancellation notes a booking cancelled by the client of the request.
+	RecordCancellation(ctx context.Context, booking *domain.Booking)
+
+	// Allow counts a write request of the client of the request against its limit. When the
+	// request is over the limit it reports how long until the client may try again.
+	Allow(ctx context.Context) (bool, time.Duration)
+
+	// ListAbuseEvents returns the events of the kind, or of every kind when it is empty, newest
+	// first; admins only.
+	ListAbuseEvents(ctx context.Context, kind domain.AbuseEventKind) ([]*domain.AbuseEvent, error)
+}
+
+type abuseClient struct {
+	lastSeen time.Time
+
+	bookings []time.Time
+	// cancelledAt holds the last cancellation at each restaurant not yet followed by a booking.
+	cancelledAt map[string]time.Time
+	// cycles holds when the client booked a restaurant again after cancelling there.
+	cycles map[string][]time.Time
+	// flaggedAt keeps every pattern from being reported more than once per window.
+	flaggedAt map[string]time.Time
+
+	tightenedUntil time.Time
+	periodStart    time.Time
+	requests       int
+}
+
+type abuseUseCase struct {
+	rules AbuseRules
+	ids   idgen.Generator
+
+	mu      sync.Mutex
+	clients map[string]*abuseClient
+	events  []*domain.AbuseEvent
+	clock   clock.Clock
+}
+
+func NewAbuseUseCase(rules AbuseRules, ids idgen.Generator, clock clock.Clock) AbuseUseCase {
+	return &abuseUseCase{
+		rules:   rules,
+		ids:     ids,
+		clients: make(map[string]*abuseClient),
+		clock:   clock,
+	}
+}
+
+// abuseClientKey identifies the client of the request; background jobs and tools have none.
+func abuseClientKey(ctx context.Context) string {
+	if ip := tenant.ClientIPFromContext(ctx); ip != "" {
+		return "ip:" + ip
+	}
+	if principal, ok := tenant.FromContext(ctx); ok && principal.UserID != "" {
+		return "user:" + principal.UserID
+	}
+	return ""
+}
+
+func (u *abuseUseCase) RecordBooking(ctx context.Context, booking *domain.Booking) {
+	key := abuseClientKey(ctx)
+	if key == "" {
+		return
+	}
+
+	now := u.clock.Now()
+	var detected []*domain.AbuseEvent
+
+	u.mu.Lock()
+	client := u.client(key, now)
+	cutoff := now.Add(-u.rules.Window)
+
+	client.bookings = append(pruneBefore(client.bookings, cutoff), now)
+	if u.rules.BookingsPerClient > 0 && len(client.bookings) > u.rules.BookingsPerClient {
+		if event := u.flag(ctx, client, domain.AbuseEventBookingBurst, "", len(client.bookings), now); event != nil {
+			detected = append(detected, event)
+		}
+	}
+
+	if cancelledAt, ok := client.cancelledAt[booking.RestaurantID]; ok {
+		delete(client.cancelledAt, booking.RestaurantID)
+		if cancelledAt.After(cutoff) {
+			cycles := append(pruneBefore(client.cycles[booking.RestaurantID], cutoff), now)
+			client.cycles[booking.RestaurantID] = cycles
+			if u.rules.CancelRebookCycles > 0 && len(cycles) >= u.rules.CancelRebookCycles {
+				if event := u.flag(ctx, client, domain.AbuseEventCancelRebook, booking.RestaurantID, len(cycles), now); event != nil {
+					detected = append(detected, event)
+				}
+			}
+		}
+	}
+	u.mu.Unlock()
+
+	log, _ := logger.FromContext(ctx)
+	for _, event := range detected {
+		log.Warn(ctx, "suspicious booking pattern detected",
+			zap.String("kind", string(event.Kind)),
+			zap.String("clientIP", event.ClientIP),
+			zap.String("userID", event.UserID),
+			zap.String("restaurantID", event.RestaurantID),
+			zap.Int("count", event.Count),
+			zap.Bool("tightened", event.TightenedUntil != nil))
+	}
+}
+
+func (u *abuseUseCase) RecordCancellation(ctx context.Context, booking *domain.Booking) {
+	key := abuseClientKey(ctx)
+	if key == "" {
+		return
+	}
+
+	now := u.clock.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.client(key, now).cancelledAt[booking.RestaurantID] = now
+}
+
+func (u *abuseUseCase) Allow(ctx context.Context) (bool, time.Duration) {
+	key := abuseClientKey(ctx)
+	if key == "" {
+		return true, 0
+	}
+
+	now := u.clock.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	limit := u.rules.RateLimit
+	client, tracked := u.clients[key]
+	if tracked && now.Before(client.tightenedUntil) && (limit <= 0 || u.rules.TightenedRateLimit < limit) {
+		limit = u.rules.TightenedRateLimit
+	}
+	if limit <= 0 {
+		return true, 0
+	}
+
+	if !tracked {
+		client = u.client(key, now)
+	}
+	client.lastSeen = now
+	if now.Sub(client.periodStart) >= rateLimitPeriod {
+		client.periodStart = now
+		client.requests = 0
+	}
+	client.requests++
+	if client.requests > limit {
+		return false, client.periodStart.Add(rateLimitPeriod).Sub(now)
+	}
+	return true, 0
+}
+
+func (u *abuseUseCase) ListAbuseEvents(ctx context.Context, kind domain.AbuseEventKind) ([]*domain.AbuseEvent, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "", domain.AbuseEventBookingBurst, domain.AbuseEventCancelRebook:
+	default:
+		return nil, ErrInvalidAbuseEventKind
+	}
+
+	u.mu.Lock()
+	events := make([]*domain.AbuseEvent, 0, len(u.events))
+	for _, event := range u.events {
+		if kind == "" || event.Kind == kind {
+			events = append(events, event)
+		}
+	}
+	u.mu.Unlock()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].DetectedAt.After(events[j].DetectedAt)
+	})
+	return events, nil
+}
+
+// client returns the tracked client, starting to track it when it is new. It must be called with
+// the mutex held.
+func (u *abuseUseCase) client(key string, now time.Time) *abuseClient {
+	client, ok := u.clients[key]
+	if !ok {
+		if len(u.clients) >= maxAbuseClients {
+			u.forgetLeastRecentClient()
+		}
+		client = &abuseClient{
+			cancelledAt: make(map[string]time.Time),
+			cycles:      make(map[string][]time.Time),
+			flaggedAt:   make(map[string]time.Time),
+		}
+		u.clients[key] = client
+	}
+	client.lastSeen = now
+	return client
+}
+
+func (u *abuseUseCase) forgetLeastRecentClient() {
+	var oldestKey string
+	var oldest time.Time
+	for key, client := range u.clients {
+		if oldestKey == "" || client.lastSeen.Before(oldest) {
+			oldestKey, oldest = key, client.lastSeen
+		}
+	}
+	delete(u.clients, oldestKey)
+}
+
+// flag records an event unless the same pattern of the client was reported within the window,
+// and tightens the rate limit of the client when enabled. It must be called with the mutex held.
+func (u *abuseUseCase) flag(ctx context.Context, client *abuseClient, kind domain.AbuseEventKind, restaurantID string, count int, now time.Time) *domain.AbuseEvent {
+	pattern := string(kind) + ":" + restaurantID
+	if flaggedAt, ok := client.flaggedAt[pattern]; ok && now.Sub(flaggedAt) < u.rules.Window {
+		return nil
+	}
+	client.flaggedAt[pattern] = now
+
+	event := &domain.AbuseEvent{
+		ID:           u.ids.NewID(),
+		Kind:         kind,
+		ClientIP:     tenant.ClientIPFromContext(ctx),
+		RestaurantID: restaurantID,
+		Count:        count,
+		Window:       u.rules.Window,
+		DetectedAt:   now,
+	}
+	if principal, ok := tenant.FromContext(ctx); ok {
+		event.UserID = principal.UserID
+	}
+
+	if u.rules.AutoTighten && u.rules.TightenedRateLimit > 0 {
+		client.tightenedUntil = now.Add(u.rules.TightenFor)
+		tightenedUntil := client.tightenedUntil
+		event.TightenedUntil = &tightenedUntil
+	}
+
+	u.events = append(u.events, event)
+	if len(u.events) > maxAbuseEvents {
+		u.events = u.events[len(u.events)-maxAbuseEvents:]
+	}
+	return event
+}
+
+// pruneBefore drops the times before cutoff from a list kept in ascending order.
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
+
+type abuseMonitoredBookingUseCase struct {
+	BookingUseCase
+	abuse AbuseUseCase
+}
+
+// NewAbuseMonitoredBookingUseCase reports the bookings made and cancelled through bookings to
+// abuse. Bookings cancelled with a booking link are not reported.
+func NewAbuseMonitoredBookingUseCase(bookings BookingUseCase, abuse AbuseUseCase) BookingUseCase {
+	return &abuseMonitoredBookingUseCase{
+		BookingUseCase: bookings,
+		abuse:          abuse,
+	}
+}
+
+func (u *abuseMonitoredBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	id, err := u.BookingUseCase.CreateBooking(ctx, booking)
+	if err != nil {
+		return "", err
+	}
+
+	u.abuse.RecordBooking(ctx, booking)
+	return id, nil
+}
+
+func (u *abuseMonitoredBookingUseCase) CancelBooking(ctx context.Context, id domain.BookingID) error {
+	if err := u.BookingUseCase.CancelBooking(ctx, id); err != nil {
+		return err
+	}
+
+	booking, err := u.BookingUseCase.GetBooking(ctx, id)
+	if err != nil {
+		log, _ := logger.FromContext(ctx)
+		log.Warn(ctx, "failed to get cancelled booking for abuse detection",
+			zap.String("bookingID", id.String()),
+			zap.Error(err))
+		return nil
+	}
+
+	u.abuse.RecordCancellation(ctx, booking)
+	return nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+)
+
+// ErrAgeAttestationRequired is returned for a booking of an adult-only restaurant whose guest did
+// not attest their age.
+var ErrAgeAttestationRequired = errors.New(common.ErrAgeAttestationRequired)
+
+type ageRestrictedBookingUseCase struct {
+	BookingUseCase
+	restaurantRepo repository.RestaurantRepository
+}
+
+// NewAgeRestrictedBookingUseCase rejects the bookings made through bookings of an adult-only
+// restaurant unless the guest attested their age.
+func NewAgeRestrictedBookingUseCase(bookings BookingUseCase, restaurantRepo repository.RestaurantRepository) BookingUseCase {
+	return &ageRestrictedBookingUseCase{
+		BookingUseCase: bookings,
+		restaurantRepo: restaurantRepo,
+	}
+}
+
+func (u *ageRestrictedBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	if !booking.AgeAttested {
+		restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(booking.RestaurantID))
+		if err != nil {
+			return "", err
+		}
+		if restaurant.IsAdultOnly {
+			return "", ErrAgeAttestationRequired
+		}
+	}
+	return u.BookingUseCase.CreateBooking(ctx, booking)
+}
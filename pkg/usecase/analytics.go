@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+)
+
+const (
+	// maxForecastDays bounds the days a forecast may cover; each day is a query.
+	maxForecastDays = 28
+
+	maxHeatmapWeeks = 52
+
+	maxResponseLatencyDays = 365
+
+	maxCampaignDays = 365
+
+	// occupancyCacheTTL is how long the live occupancy of a restaurant is reused; listings ask for
+	// it far more often than it changes.
+	occupancyCacheTTL = time.Minute
+)
+
+var (
+	ErrInvalidForecastDays = errors.New("invalid forecast days")
+	ErrInvalidHeatmapWeeks = errors.New("invalid heatmap weeks")
+	ErrInvalidLatencyDays  = errors.New("invalid response latency days")
+	ErrInvalidCampaignDays = errors.New("invalid campaign days")
+)
+
+// SlowResponderPolicy flags the restaurants answering at least MinResponses of the bookings
+// requested over the last Days days with a median response time above Threshold.
+type SlowResponderPolicy struct {
+	Days         int
+	Threshold    time.Duration
+	MinResponses int
+}
+
+type AnalyticsUseCase interface {
+	// GetForecast projects the occupancy of the slots of the restaurant for days days from today,
+	// for its staff. The seats of a slot still to be booked are the average of those booked in the
+	// same time before the slots at the same weekday and time over the last weeks.
+	GetForecast(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.OccupancyForecast, error)
+
+	// GetDemandHeatmap counts the bookings requested at the restaurant by weekday and hour over
+	// the weeks before today, or the default number of weeks when it is zero, for its staff. The
+	// heatmap is computed once a day per restaurant and number of weeks.
+	GetDemandHeatmap(ctx context.Context, restaurantID string, today time.Time, weeks int) (*domain.DemandHeatmap, error)
+
+	// GetResponseLatency sums up how fast the restaurant answered the bookings requested over the
+	// last days days up to today, for its staff.
+	GetResponseLatency(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.ResponseLatency, error)
+
+	// ListSlowResponders returns the restaurants flagged by the slow responder policy up to today,
+	// slowest first; admins only.
+	ListSlowResponders(ctx context.Context, today time.Time) ([]*domain.ResponseLatency, error)
+
+	// ListCampaignPerformance sums up by campaign the bookings requested over the last days days up
+	// to today at the restaurant, or at every restaurant when restaurantID is empty; admins only.
+	ListCampaignPerformance(ctx context.Context, restaurantID string, today time.Time, days int) ([]domain.CampaignPerformance, error)
+
+	// GetOccupancyNow counts the confirmed bookings of the restaurant whose parties are seated now,
+	// against the seats of the slot under way, for the busyness badge of listings; anyone may ask.
+	// The occupancy of a restaurant is computed at most once a minute.
+	GetOccupancyNow(ctx context.Context, restaurantID string, now time.Time) (*domain.LiveOccupancy, error)
+}
+
+type heatmapKey struct {
+	restaurantID string
+	weeks        int
+}
+
+type analyticsUseCase struct {
+	analyticsRepo    repository.AnalyticsRepository
+	availabilityRepo repository.AvailabilityRepository
+	restaurantRepo   repository.RestaurantRepository
+	forecastWeeks    int
+	heatmapWeeks     int
+	slowResponders   SlowResponderPolicy
+
+	heatmapsMu  sync.Mutex
+	heatmapsDay string
+	heatmaps    map[heatmapKey]*domain.DemandHeatmap
+
+	occupancyMu sync.Mutex
+	occupancy   map[string]*domain.LiveOccupancy
+	clock       clock.Clock
+}
+
+// NewAnalyticsUseCase creates the use case; forecastWeeks is how many past weeks forecasts average
+// over and heatmapWeeks how many heatmaps count by default.
+func NewAnalyticsUseCase(
+	analyticsRepo repository.AnalyticsRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	restaurantRepo repository.RestaurantRepository,
+	forecastWeeks int,
+	heatmapWeeks int,
+	slowResponders SlowResponderPolicy,
+	clock clock.Clock,
+) AnalyticsUseCase {
+	return &analyticsUseCase{
+		analyticsRepo:    analyticsRepo,
+		availabilityRepo: availabilityRepo,
+		restaurantRepo:   restaurantRepo,
+		forecastWeeks:    max(forecastWeeks, 1),
+		heatmapWeeks:     min(max(heatmapWeeks, 1), maxHeatmapWeeks),
+		slowResponders:   slowResponders,
+		heatmaps:         make(map[heatmapKey]*domain.DemandHeatmap),
+		occupancy:        make(map[string]*domain.LiveOccupancy),
+		clock:            clock,
+	}
+}
+
+func (u *analyticsUseCase) GetForecast(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.OccupancyForecast, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	if days < 1 || days > maxForecastDays {
+		return nil, fmt.Errorf("%w: from 1 to %d days can be forecast", ErrInvalidForecastDays, maxForecastDays)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	historyFrom := today.AddDate(0, 0, -7*u.forecastWeeks)
+	history, err := u.analyticsRepo.ListBookings(ctx, restaurantID, historyFrom, today)
+	if err != nil {
+		return nil, err
+	}
+
+	bySlot := make(map[string][]*domain.Booking)
+	for _, booking := range history {
+		if booking.Status == domain.BookingStatusConfirmed || booking.Status == domain.BookingStatusCompleted {
+			key := slotKey(booking.Date, booking.Time)
+			bySlot[key] = append(bySlot[key], booking)
+		}
+	}
+
+	forecast := &domain.OccupancyForecast{
+		RestaurantID: restaurantID,
+		Weeks:        u.forecastWeeks,
+		Days:         make([]domain.DayForecast, 0, days),
+	}
+	for lead := range days {
+		date := today.AddDate(0, 0, lead)
+		slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurantID), date)
+		if err != nil {
+			return nil, err
+		}
+
+		day := domain.DayForecast{Date: date, Slots: make([]domain.SlotForecast, 0, len(slots))}
+		for _, slot := range slots {
+			pickup, samples := averagePickup(bySlot, date, slot.TimeSlot, lead, historyFrom, today)
+			day.Slots = append(day.Slots, domain.SlotForecast{
+				TimeSlot:          slot.TimeSlot,
+				Capacity:          slot.Capacity,
+				Reserved:          slot.Reserved,
+				ProjectedReserved: min(float64(slot.Reserved)+pickup, float64(slot.Capacity)),
+				Samples:           samples,
+			})
+		}
+		forecast.Days = append(forecast.Days, day)
+	}
+
+	return forecast, nil
+}
+
+// averagePickup averages the seats booked in the last lead days before each past slot at the same
+// weekday and time as the one on date, between historyFrom and today.
+func averagePickup(bySlot map[string][]*domain.Booking, date time.Time, timeSlot string, lead int, historyFrom, today time.Time) (float64, int) {
+	var pickup, samples int
+	for past := date.AddDate(0, 0, -7); !past.Before(historyFrom); past = past.AddDate(0, 0, -7) {
+		if !past.Before(today) {
+			continue
+		}
+
+		bookedBy := past.AddDate(0, 0, -lead)
+		for _, booking := range bySlot[slotKey(past, timeSlot)] {
+			if !booking.CreatedAt.Before(bookedBy) {
+				pickup += booking.GuestsCount
+			}
+		}
+		samples++
+	}
+
+	if samples == 0 {
+		return 0, 0
+	}
+	return float64(pickup) / float64(samples), samples
+}
+
+func (u *analyticsUseCase) GetDemandHeatmap(ctx context.Context, restaurantID string, today time.Time, weeks int) (*domain.DemandHeatmap, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	if weeks == 0 {
+		weeks = u.heatmapWeeks
+	}
+	if weeks < 1 || weeks > maxHeatmapWeeks {
+		return nil, fmt.Errorf("%w: from 1 to %d weeks can be counted", ErrInvalidHeatmapWeeks, maxHeatmapWeeks)
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	day := today.Format("2006-01-02")
+	key := heatmapKey{restaurantID: restaurantID, weeks: weeks}
+
+	// Heatmaps of the day before are dropped all at once, so the cache only holds today's.
+	u.heatmapsMu.Lock()
+	if u.heatmapsDay != day {
+		u.heatmapsDay = day
+		clear(u.heatmaps)
+	}
+	cached, ok := u.heatmaps[key]
+	u.heatmapsMu.Unlock()
+
+	if ok {
+		return cached, nil
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	heatmap := &domain.DemandHeatmap{
+		RestaurantID: restaurantID,
+		From:         today.AddDate(0, 0, -7*weeks),
+		To:           today,
+		Weeks:        weeks,
+		GeneratedAt:  u.clock.Now(),
+	}
+	demand, err := u.analyticsRepo.CountHourlyDemand(ctx, restaurantID, heatmap.From, heatmap.To)
+	if err != nil {
+		return nil, err
+	}
+	for _, hourly := range demand {
+		if hourly.Weekday < time.Sunday || hourly.Weekday > time.Saturday || hourly.Hour < 0 || hourly.Hour > 23 {
+			continue
+		}
+		heatmap.Cells[hourly.Weekday][hourly.Hour] = domain.DemandCell{
+			Bookings: hourly.Bookings,
+			Guests:   hourly.Guests,
+		}
+	}
+
+	u.heatmapsMu.Lock()
+	if u.heatmapsDay == day {
+		u.heatmaps[key] = heatmap
+	}
+	u.heatmapsMu.Unlock()
+
+	return heatmap, nil
+}
+
+func (u *analyticsUseCase) GetResponseLatency(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.ResponseLatency, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	if days < 1 || days > maxResponseLatencyDays {
+		return nil, fmt.Errorf("%w: from 1 to %d days can be summed up", ErrInvalidLatencyDays, maxResponseLatencyDays)
+	}
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := latencyPeriod(today, days)
+	latency, err := u.analyticsRepo.GetResponseLatency(ctx, restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	latency.RestaurantName = restaurant.Name
+	return latency, nil
+}
+
+func (u *analyticsUseCase) ListSlowResponders(ctx context.Context, today time.Time) ([]*domain.ResponseLatency, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	from, to := latencyPeriod(today, max(u.slowResponders.Days, 1))
+	return u.analyticsRepo.ListSlowResponders(ctx, from, to, u.slowResponders.Threshold, max(u.slowResponders.MinResponses, 1))
+}
+
+func (u *analyticsUseCase) ListCampaignPerformance(ctx context.Context, restaurantID string, today time.Time, days int) ([]domain.CampaignPerformance, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if days < 1 || days > maxCampaignDays {
+		return nil, fmt.Errorf("%w: from 1 to %d days can be summed up", ErrInvalidCampaignDays, maxCampaignDays)
+	}
+
+	if restaurantID != "" {
+		if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+			return nil, err
+		}
+	}
+
+	from, to := latencyPeriod(today, days)
+	return u.analyticsRepo.ListCampaignPerformance(ctx, restaurantID, from, to)
+}
+
+// latencyPeriod covers the days days up to today, today included.
+func latencyPeriod(today time.Time, days int) (time.Time, time.Time) {
+	to := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location()).AddDate(0, 0, 1)
+	return to.AddDate(0, 0, -days), to
+}
+
+func slotKey(date time.Time, timeSlot string) string {
+	return date.Format("2006-01-02") + " " + timeSlot
+}
+
+func (u *analyticsUseCase) GetOccupancyNow(ctx context.Context, restaurantID string, now time.Time) (*domain.LiveOccupancy, error) {
+	// Expired entries are dropped on every call, so restaurants no longer asked about do not pile up.
+	u.occupancyMu.Lock()
+	for id, occupancy := range u.occupancy {
+		if now.Sub(occupancy.At) >= occupancyCacheTTL || now.Before(occupancy.At) {
+			delete(u.occupancy, id)
+		}
+	}
+	cached, ok := u.occupancy[restaurantID]
+	u.occupancyMu.Unlock()
+
+	if ok {
+		return cached, nil
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	bookings, guests, err := u.analyticsRepo.CountSeated(ctx, restaurantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	capacity, err := u.currentSlotCapacity(ctx, restaurantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancy := &domain.LiveOccupancy{
+		RestaurantID: restaurantID,
+		Bookings:     bookings,
+		Guests:       guests,
+		Capacity:     capacity,
+		At:           now,
+	}
+
+	u.occupancyMu.Lock()
+	u.occupancy[restaurantID] = occupancy
+	u.occupancyMu.Unlock()
+
+	return occupancy, nil
+}
+
+// currentSlotCapacity returns the capacity of the latest slot of the day started at or before now
+// and less than a default booking duration ago, 0 when there is none.
+func (u *analyticsUseCase) currentSlotCapacity(ctx context.Context, restaurantID string, now time.Time) (int, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurantID), today)
+	if err != nil {
+		return 0, err
+	}
+
+	var capacity int
+	var latest time.Time
+	for _, slot := range slots {
+		clock, err := time.Parse("15:04", slot.TimeSlot)
+		if err != nil {
+			continue
+		}
+
+		start := today.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+		if start.After(now) || now.Sub(start) >= domain.DefaultBookingDuration || start.Before(latest) {
+			continue
+		}
+		latest, capacity = start, slot.Capacity
+	}
+
+	return capacity, nil
+}
@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/auth"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAuthDisabled        = errors.New("authentication is not configured")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrAccountExists       = errors.New("an account with the email already exists")
+	ErrWeakPassword        = errors.New("password is too short")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrInvalidAccessToken  = errors.New("invalid access token")
+
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+)
+
+const (
+	DefaultAccessTokenTTL    = 15 * time.Minute
+	DefaultRefreshTokenTTL   = 30 * 24 * time.Hour
+	DefaultMinPasswordLength = 8
+
+	// EmailVerificationPath is the route the links verifying the email of an account open.
+	EmailVerificationPath = "/api/v1/auth/verify-email"
+)
+
+// AuthSettings configure the tokens. Without a Secret nothing can sign in. Durations and lengths
+// that are not positive are replaced with the defaults.
+type AuthSettings struct {
+	Secret            string
+	Issuer            string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	MinPasswordLength int
+	// AdminEmails are the accounts given the admin role, once they verified their email.
+	AdminEmails []string
+	// BaseURL is the public URL of the service the verification links point at.
+	BaseURL string
+}
+
+// TokenPair is what signing in returns: the access token to send as a bearer token, valid for
+// ExpiresIn seconds, and the refresh token to get the next pair with.
+type TokenPair struct {
+	AccessToken  string
+	TokenType    string
+	ExpiresIn    int
+	RefreshToken string
+	Account      *domain.Account
+}
+
+// AuthUseCase signs users and the staff of restaurants in with an email and a password.
+type AuthUseCase interface {
+	// RegisterUser creates the user with an account and signs it in. Like every new account it is
+	// mailed a link verifying its email.
+	RegisterUser(ctx context.Context, user *domain.User, password string) (*TokenPair, error)
+
+	// RegisterRestaurantAccount creates a staff account of the restaurant. Only the staff and the
+	// owner of the restaurant and admins may create one.
+	RegisterRestaurantAccount(ctx context.Context, restaurantID, email, password string) (*domain.Account, error)
+
+	Login(ctx context.Context, email, password string) (*TokenPair, error)
+
+	// Refresh exchanges a refresh token for a new pair; the token can't be used again. A token
+	// that was already exchanged signs the whole session out.
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+
+	// Logout signs the session of the refresh token out; unknown tokens are ignored.
+	Logout(ctx context.Context, refreshToken string) error
+
+	// VerifyEmail marks the email of the account the token was mailed to verified. A token works
+	// once.
+	VerifyEmail(ctx context.Context, token string) (*domain.Account, error)
+
+	// ResendVerification mails a new verification link to the account with the email, replacing
+	// the one sent before. Unknown and verified emails are ignored, so that the answer tells
+	// nothing about which accounts exist.
+	ResendVerification(ctx context.Context, email string) error
+
+	// Authenticate verifies an access token and returns the principal it was issued to.
+	Authenticate(ctx context.Context, accessToken string) (*tenant.Principal, error)
+}
+
+type authUseCase struct {
+	accountRepo    repository.AccountRepository
+	restaurantRepo repository.RestaurantRepository
+	claimRepo      repository.RestaurantClaimRepository
+	userUseCase    UserUseCase
+	emailService   EmailService
+	transactor     repository.Transactor
+	clock          clock.Clock
+	signer         *auth.Signer
+	settings       AuthSettings
+	adminEmails    []string
+}
+
+func NewAuthUseCase(
+	accountRepo repository.AccountRepository,
+	restaurantRepo repository.RestaurantRepository,
+	claimRepo repository.RestaurantClaimRepository,
+	userUseCase UserUseCase,
+	emailService EmailService,
+	transactor repository.Transactor,
+	clock clock.Clock,
+	settings AuthSettings,
+) AuthUseCase {
+	if settings.AccessTokenTTL <= 0 {
+		settings.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if settings.RefreshTokenTTL <= 0 {
+		settings.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	if settings.MinPasswordLength <= 0 {
+		settings.MinPasswordLength = DefaultMinPasswordLength
+	}
+	settings.BaseURL = strings.TrimRight(settings.BaseURL, "/")
+
+	adminEmails := make([]string, 0, len(settings.AdminEmails))
+	for _, email := range settings.AdminEmails {
+		if normalized, err := contact.NormalizeEmail(email); err == nil {
+			adminEmails = append(adminEmails, normalized)
+		}
+	}
+
+	return &authUseCase{
+		accountRepo:    accountRepo,
+		restaurantRepo: restaurantRepo,
+		claimRepo:      claimRepo,
+		userUseCase:    userUseCase,
+		emailService:   emailService,
+		transactor:     transactor,
+		clock:          clock,
+		signer:         auth.NewSigner(settings.Secret, settings.Issuer),
+		settings:       settings,
+		adminEmails:    adminEmails,
+	}
+}
+
+func (u *authUseCase) RegisterUser(ctx context.Context, user *domain.User, password string) (*TokenPair, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	hash, err := u.hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	verificationToken, verificationHash, err := auth.NewVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var account *domain.Account
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.userUseCase.CreateUser(ctx, user); err != nil {
+			return err
+		}
+
+		account = &domain.Account{
+			Kind:                  domain.AccountUser,
+			Email:                 user.Email,
+			NormalizedEmail:       user.NormalizedEmail,
+			PasswordHash:          hash,
+			UserID:                user.ID,
+			EmailVerificationHash: verificationHash,
+		}
+		return u.createAccount(ctx, account)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "user account registered",
+		zap.String("accountID", account.ID),
+		zap.String("userID", account.UserID))
+	u.sendVerification(ctx, account, verificationToken)
+	return u.issueTokens(ctx, account, "")
+}
+
+func (u *authUseCase) RegisterRestaurantAccount(ctx context.Context, restaurantID, email, password string) (*domain.Account, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	if err := u.authorizeRestaurant(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+
+	normalized, err := contact.NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := u.hashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	verificationToken, verificationHash, err := auth.NewVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	account := &domain.Account{
+		Kind:                  domain.AccountRestaurant,
+		Email:                 email,
+		NormalizedEmail:       normalized,
+		PasswordHash:          hash,
+		RestaurantID:          restaurantID,
+		EmailVerificationHash: verificationHash,
+	}
+	if err := u.createAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "restaurant account registered",
+		zap.String("accountID", account.ID),
+		zap.String("restaurantID", restaurantID))
+	u.sendVerification(ctx, account, verificationToken)
+	return account, nil
+}
+
+// authorizeRestaurant passes the staff of the restaurant, admins and the owner of the restaurant.
+// Unlike most checks it also turns away requests without a principal, or anyone could create a
+// login for any restaurant.
+func (u *authUseCase) authorizeRestaurant(ctx context.Context, restaurantID string) error {
+	principal, ok := tenant.FromContext(ctx)
+	if !ok {
+		return tenant.ErrAccessDenied
+	}
+	if principal.CanAccessRestaurant(restaurantID) {
+		return nil
+	}
+
+	if principal.UserID != "" {
+		owner, err := u.claimRepo.GetOwner(ctx, restaurantID)
+		switch {
+		case err == nil && owner.OwnerID == principal.UserID:
+			return nil
+		case err != nil && err.Error() != common.ErrRestaurantOwnerNotFound:
+			return err
+		}
+	}
+
+	return tenant.ErrAccessDenied
+}
+
+func (u *authUseCase) Login(ctx context.Context, email, password string) (*TokenPair, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	normalized, err := contact.NormalizeEmail(email)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	account, err := u.accountRepo.GetByEmail(ctx, normalized)
+	if err != nil {
+		if err.Error() != common.ErrAccountNotFound {
+			return nil, err
+		}
+		// Checked all the same, so that an unknown email takes as long as a wrong password.
+		auth.CheckPassword("", password)
+		log.Info(ctx, "login with an unknown email")
+		return nil, ErrInvalidCredentials
+	}
+
+	if !auth.CheckPassword(account.PasswordHash, password) {
+		log.Info(ctx, "login with a wrong password", zap.String("accountID", account.ID))
+		return nil, ErrInvalidCredentials
+	}
+
+	return u.issueTokens(ctx, account, "")
+}
+
+func (u *authUseCase) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	token, err := u.accountRepo.GetRefreshToken(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if err.Error() == common.ErrRefreshTokenNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	now := u.clock.Now()
+	if !now.Before(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	revoked := false
+	if token.RevokedAt == nil {
+		if revoked, err = u.accountRepo.RevokeRefreshToken(ctx, token.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	if !revoked {
+		// The token was exchanged before, by its holder or by whoever stole it; neither can be told
+		// apart from the other, so the session ends for both.
+		log.Warn(ctx, "refresh token used again, signing the session out",
+			zap.String("accountID", token.AccountID),
+			zap.String("familyID", token.FamilyID))
+		if err := u.accountRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	account, err := u.accountRepo.GetByID(ctx, token.AccountID)
+	if err != nil {
+		if err.Error() == common.ErrAccountNotFound {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	return u.issueTokens(ctx, account, token.FamilyID)
+}
+
+func (u *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+	token, err := u.accountRepo.GetRefreshToken(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if err.Error() == common.ErrRefreshTokenNotFound {
+			return nil
+		}
+		return err
+	}
+
+	return u.accountRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID, u.clock.Now())
+}
+
+func (u *authUseCase) VerifyEmail(ctx context.Context, token string) (*domain.Account, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	account, err := u.accountRepo.VerifyEmail(ctx, auth.HashVerificationToken(token), u.clock.Now())
+	if err != nil {
+		if err.Error() == common.ErrVerificationTokenNotFound {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	log.Info(ctx, "account email verified", zap.String("accountID", account.ID))
+	return account, nil
+}
+
+func (u *authUseCase) ResendVerification(ctx context.Context, email string) error {
+	if u.settings.Secret == "" {
+		return ErrAuthDisabled
+	}
+
+	normalized, err := contact.NormalizeEmail(email)
+	if err != nil {
+		return err
+	}
+
+	account, err := u.accountRepo.GetByEmail(ctx, normalized)
+	if err != nil {
+		if err.Error() == common.ErrAccountNotFound {
+			return nil
+		}
+		return err
+	}
+	if account.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	token, hash, err := auth.NewVerificationToken()
+	if err != nil {
+		return err
+	}
+	if err := u.accountRepo.SetVerificationToken(ctx, account.ID, hash); err != nil {
+		return err
+	}
+
+	u.sendVerification(ctx, account, token)
+	return nil
+}
+
+func (u *authUseCase) Authenticate(_ context.Context, accessToken string) (*tenant.Principal, error) {
+	if u.settings.Secret == "" {
+		return nil, ErrAuthDisabled
+	}
+
+	claims, err := u.signer.Verify(accessToken, u.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAccessToken, err)
+	}
+
+	principal := &tenant.Principal{
+		UserID:        claims.UserID,
+		RestaurantIDs: claims.RestaurantIDs,
+		AccountID:     claims.Subject,
+	}
+	for _, role := range claims.Roles {
+		principal.Roles = append(principal.Roles, tenant.Role(role))
+	}
+	return principal, nil
+}
+
+func (u *authUseCase) hashPassword(password string) (string, error) {
+	if len(password) < u.settings.MinPasswordLength {
+		return "", fmt.Errorf("%w: at least %d characters", ErrWeakPassword, u.settings.MinPasswordLength)
+	}
+	return auth.HashPassword(password)
+}
+
+func (u *authUseCase) createAccount(ctx context.Context, account *domain.Account) error {
+	if err := u.accountRepo.Create(ctx, account); err != nil {
+		if err.Error() == common.ErrAccountExists {
+			return ErrAccountExists
+		}
+		return err
+	}
+	return nil
+}
+
+// sendVerification mails the link verifying the email of the account. A failure is only logged:
+// the account works without a verified email, and a new link can be asked for.
+func (u *authUseCase) sendVerification(ctx context.Context, account *domain.Account, token string) {
+	log, _ := logger.FromContext(ctx)
+
+	link := u.settings.BaseURL + EmailVerificationPath + "?token=" + url.QueryEscape(token)
+	body := "Open the link to confirm the email of your account:\n\n" + link +
+		"\n\nIf you did not sign up, ignore this email."
+	if err := u.emailService.SendEmail(account.Email, "Confirm your email", body); err != nil {
+		log.Warn(ctx, common.ErrSendVerificationEmail, zap.String("accountID", account.ID), zap.Error(err))
+	}
+}
+
+// principalOf is the principal the tokens of the account name. The admin role goes only to
+// accounts that verified their email, or anyone could claim it by signing up with the address of
+// an admin before them.
+func (u *authUseCase) principalOf(account *domain.Account) *tenant.Principal {
+	principal := &tenant.Principal{AccountID: account.ID}
+	switch account.Kind {
+	case domain.AccountUser:
+		principal.UserID = account.UserID
+		principal.Roles = append(principal.Roles, tenant.RoleUser)
+	case domain.AccountRestaurant:
+		principal.RestaurantIDs = []string{account.RestaurantID}
+		principal.Roles = append(principal.Roles, tenant.RoleRestaurantStaff)
+	}
+	if account.EmailVerifiedAt != nil && slices.Contains(u.adminEmails, account.NormalizedEmail) {
+		principal.Roles = append(principal.Roles, tenant.RoleAdmin)
+	}
+	return principal
+}
+
+// issueTokens signs an access token for the account and stores a new refresh token of the family,
+// or of a family of its own when familyID is empty.
+func (u *authUseCase) issueTokens(ctx context.Context, account *domain.Account, familyID string) (*TokenPair, error) {
+	now := u.clock.Now()
+	principal := u.principalOf(account)
+
+	claims := auth.Claims{
+		Subject:       account.ID,
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(u.settings.AccessTokenTTL).Unix(),
+		Kind:          string(account.Kind),
+		UserID:        principal.UserID,
+		RestaurantIDs: principal.RestaurantIDs,
+	}
+	for _, role := range principal.Roles {
+		claims.Roles = append(claims.Roles, string(role))
+	}
+
+	accessToken, err := u.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.accountRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		AccountID: account.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: now.Add(u.settings.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(u.settings.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+		Account:      account,
+	}, nil
+}
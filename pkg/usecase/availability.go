@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
@@ -11,62 +15,266 @@ import (
 	"go.uber.org/zap"
 )
 
+const maxGenerateAvailabilityDays = 92
+
+var (
+	ErrInvalidDateRange      = errors.New("invalid date range")
+	ErrInvalidSlotDuration   = errors.New("invalid slot duration")
+	ErrInvalidCapacity       = errors.New("invalid capacity")
+	ErrCapacityBelowReserved = errors.New("capacity is below the number of reserved seats")
+	ErrAvailabilityInUse     = errors.New("availability is in use")
+)
+
+// CapacityConflictError is returned when a slot would get fewer seats than are already reserved.
+// ImpactedBookings are the active bookings of the slot that need manual resolution.
+type CapacityConflictError struct {
+	Availability     *domain.Availability
+	ImpactedBookings []*domain.Booking
+}
+
+func (e *CapacityConflictError) Error() string {
+	return fmt.Sprintf("%s: slot %s %s has %d reserved seats, capacity %d",
+		ErrCapacityBelowReserved, e.Availability.Date.Format(time.DateOnly), e.Availability.TimeSlot,
+		e.Availability.Reserved, e.Availability.Capacity)
+}
+
+func (e *CapacityConflictError) Unwrap() error {
+	return ErrCapacityBelowReserved
+}
+
+// AvailabilityInUseError is returned when a slot that still holds bookings is deleted. Bookings are
+// its pending and confirmed bookings; they may be empty when the slot has reserved seats nothing
+// accounts for, which the reconciliation of reserved seats clears.
+type AvailabilityInUseError struct {
+	Availability *domain.Availability
+	Bookings     []*domain.Booking
+}
+
+func (e *AvailabilityInUseError) Error() string {
+	return fmt.Sprintf("%s: slot %s %s has %d reserved seats and %d active bookings",
+		ErrAvailabilityInUse, e.Availability.Date.Format(time.DateOnly), e.Availability.TimeSlot,
+		e.Availability.Reserved, len(e.Bookings))
+}
+
+func (e *AvailabilityInUseError) Unwrap() error {
+	return ErrAvailabilityInUse
+}
+
+// GenerateAvailabilityParams describes slots to create from the working hours of a restaurant.
+type GenerateAvailabilityParams struct {
+	RestaurantID string
+	From         time.Time
+	To           time.Time
+	SlotDuration time.Duration
+	Capacity     int
+	// DryRun returns the slots that would be generated without persisting them.
+	DryRun bool
+}
+
+// AvailabilityDelta is what changed in the slots of a restaurant after a version a client holds:
+// the changed slots, oldest change first, and the version to ask from next, the one asked from
+// when nothing changed.
+type AvailabilityDelta struct {
+	Slots   []*domain.Availability
+	Version domain.SyncCursor
+	HasMore bool
+}
+
 type AvailabilityUseCase interface {
-	GetAvailability(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error)
+	GetAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error)
+
+	// GetAvailabilityDelta returns up to limit slots of the restaurant changed after since, so that
+	// mobile apps keep a copy of the calendar without downloading it again; a limit out of range is
+	// replaced with DefaultSyncLimit or capped at MaxSyncLimit.
+	GetAvailabilityDelta(ctx context.Context, restaurantID domain.RestaurantID, since domain.SyncCursor, limit int) (*AvailabilityDelta, error)
 
 	SetAvailability(ctx context.Context, availability *domain.Availability) error
 
+	// ForceSetAvailability sets the capacity even below the reserved seats and returns
+	// the bookings of the slot that no longer fit.
+	ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error)
+
 	UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error
 
-	CheckAvailability(ctx context.Context, restaurantID string, date time.Time, timeSlot string, guestsCount int) (bool, error)
+	// GetSlotBookings returns the pending and confirmed bookings that hold seats in the slot of
+	// the restaurant, so that the owner sees whom a change of the slot affects.
+	GetSlotBookings(ctx context.Context, restaurantID, availabilityID string) ([]*domain.Booking, error)
+
+	// DeleteAvailability deletes the slot of the restaurant. A slot with reserved seats or active
+	// bookings is kept and *AvailabilityInUseError is returned.
+	DeleteAvailability(ctx context.Context, restaurantID, availabilityID string) error
+
+	// ReconcileReservedSeats recomputes reserved seats of today's and future slots from their
+	// active bookings not seated at a table and held booking drafts and returns the slots whose
+	// recorded count had drifted.
+	ReconcileReservedSeats(ctx context.Context) ([]domain.ReservedSeatsDrift, error)
+
+	// ReservedSeatsReport lists the slots of the date whose reserved seats differ from their
+	// active bookings. With fix the reserved seats are corrected, otherwise nothing is changed.
+	ReservedSeatsReport(ctx context.Context, date time.Time, fix bool) ([]domain.ReservedSeatsDrift, error)
+
+	CheckAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time, timeSlot string, guestsCount int) (bool, error)
+
+	GenerateAvailability(ctx context.Context, params GenerateAvailabilityParams) ([]*domain.Availability, error)
 }
 
 type availabilityUseCase struct {
 	availabilityRepo repository.AvailabilityRepository
 	restaurantRepo   repository.RestaurantRepository
 	workingHoursRepo repository.WorkingHoursRepository
+	closureRepo      repository.RestaurantClosureRepository
+	specialDayRepo   repository.SpecialDayRepository
+	bookingRepo      repository.BookingRepository
+	transactor       repository.Transactor
+	clock            clock.Clock
 }
 
 func NewAvailabilityUseCase(
 	availabilityRepo repository.AvailabilityRepository,
 	restaurantRepo repository.RestaurantRepository,
 	workingHoursRepo repository.WorkingHoursRepository,
+	closureRepo repository.RestaurantClosureRepository,
+	specialDayRepo repository.SpecialDayRepository,
+	bookingRepo repository.BookingRepository,
+	transactor repository.Transactor,
+	clock clock.Clock,
 ) AvailabilityUseCase {
 	return &availabilityUseCase{
 		availabilityRepo: availabilityRepo,
 		restaurantRepo:   restaurantRepo,
 		workingHoursRepo: workingHoursRepo,
+		closureRepo:      closureRepo,
+		specialDayRepo:   specialDayRepo,
+		bookingRepo:      bookingRepo,
+		transactor:       transactor,
+		clock:            clock,
 	}
 }
 
-func (u *availabilityUseCase) GetAvailability(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (u *availabilityUseCase) GetAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	return u.availabilityRepo.GetByRestaurantAndDate(ctx, restaurantID, date)
 }
 
+func (u *availabilityUseCase) GetAvailabilityDelta(ctx context.Context, restaurantID domain.RestaurantID, since domain.SyncCursor, limit int) (*AvailabilityDelta, error) {
+	if _, err := u.restaurantRepo.GetByID(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultSyncLimit
+	case limit > MaxSyncLimit:
+		limit = MaxSyncLimit
+	}
+
+	// One slot more than asked tells whether there are more.
+	slots, err := u.availabilityRepo.ListChanges(ctx, restaurantID, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &AvailabilityDelta{Slots: slots, Version: since}
+	if len(slots) > limit {
+		delta.Slots = slots[:limit]
+		delta.HasMore = true
+	}
+	if len(delta.Slots) > 0 {
+		last := delta.Slots[len(delta.Slots)-1]
+		delta.Version = domain.SyncCursor{ChangedAt: last.UpdatedAt, ID: last.ID}
+	}
+	return delta, nil
+}
+
+// SetAvailability fails with *CapacityConflictError when the capacity is lowered below the reserved seats.
 func (u *availabilityUseCase) SetAvailability(ctx context.Context, availability *domain.Availability) error {
+	_, err := u.setAvailability(ctx, availability, false)
+	return err
+}
+
+func (u *availabilityUseCase) ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	return u.setAvailability(ctx, availability, true)
+}
+
+func (u *availabilityUseCase) setAvailability(ctx context.Context, availability *domain.Availability, force bool) ([]*domain.Booking, error) {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "setting restaurant availability",
 		zap.String("restaurantID", availability.RestaurantID),
 		zap.Time("date", availability.Date),
 		zap.String("timeSlot", availability.TimeSlot),
 		zap.Int("capacity", availability.Capacity),
-		zap.Int("reserved", availability.Reserved))
+		zap.Bool("force", force))
+
+	if err := availability.Validate(); err != nil {
+		return nil, err
+	}
 
-	availability.UpdatedAt = time.Now()
+	availability.UpdatedAt = u.clock.Now()
 
-	if err := u.availabilityRepo.SetAvailability(ctx, availability); err != nil {
+	if err := u.availabilityRepo.SetAvailability(ctx, availability, force); err != nil {
+		if err.Error() == common.ErrCapacityBelowReserved {
+			return nil, u.capacityConflict(ctx, availability)
+		}
 		log.Error(ctx, "failed to set restaurant availability",
 			zap.String("restaurantID", availability.RestaurantID),
 			zap.Time("date", availability.Date),
 			zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	log.Info(ctx, "restaurant availability successfully set",
 		zap.String("availabilityID", availability.ID),
 		zap.String("restaurantID", availability.RestaurantID),
 		zap.Time("date", availability.Date))
-	return nil
+
+	if availability.Capacity >= availability.Reserved {
+		return []*domain.Booking{}, nil
+	}
+
+	impacted, err := u.impactedBookings(ctx, availability)
+	if err != nil {
+		return nil, err
+	}
+	log.Warn(ctx, "availability capacity forced below reserved seats",
+		zap.String("availabilityID", availability.ID),
+		zap.Int("capacity", availability.Capacity),
+		zap.Int("reserved", availability.Reserved),
+		zap.Int("impactedBookings", len(impacted)))
+	return impacted, nil
+}
+
+// capacityConflict builds the error for a rejected capacity change, listing the impacted bookings when they can be loaded.
+func (u *availabilityUseCase) capacityConflict(ctx context.Context, availability *domain.Availability) error {
+	log, _ := logger.FromContext(ctx)
+	log.Warn(ctx, "availability capacity below reserved seats rejected",
+		zap.String("availabilityID", availability.ID),
+		zap.Int("capacity", availability.Capacity),
+		zap.Int("reserved", availability.Reserved))
+
+	impacted, err := u.impactedBookings(ctx, availability)
+	if err != nil {
+		return err
+	}
+	return &CapacityConflictError{Availability: availability, ImpactedBookings: impacted}
+}
+
+// impactedBookings returns the pending and confirmed bookings that hold seats in the slot.
+func (u *availabilityUseCase) impactedBookings(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	bookings, err := u.bookingRepo.GetByRestaurantID(ctx, domain.RestaurantID(availability.RestaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	date := availability.Date.Format(time.DateOnly)
+	impacted := make([]*domain.Booking, 0)
+	for _, booking := range bookings {
+		if booking.Date.Format(time.DateOnly) != date || booking.Time != availability.TimeSlot {
+			continue
+		}
+		if booking.Status == domain.BookingStatusPending || booking.Status == domain.BookingStatusConfirmed {
+			impacted = append(impacted, booking)
+		}
+	}
+	return impacted, nil
 }
 
 func (u *availabilityUseCase) UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error {
@@ -89,10 +297,124 @@ func (u *availabilityUseCase) UpdateReservedSeats(ctx context.Context, availabil
 	return nil
 }
 
-func (u *availabilityUseCase) CheckAvailability(ctx context.Context, restaurantID string, date time.Time, timeSlot string, guestsCount int) (bool, error) {
+func (u *availabilityUseCase) GetSlotBookings(ctx context.Context, restaurantID, availabilityID string) ([]*domain.Booking, error) {
+	slot, err := u.restaurantSlot(ctx, restaurantID, availabilityID)
+	if err != nil {
+		return nil, err
+	}
+	return u.impactedBookings(ctx, slot)
+}
+
+func (u *availabilityUseCase) DeleteAvailability(ctx context.Context, restaurantID, availabilityID string) error {
 	log, _ := logger.FromContext(ctx)
-	log.Info(ctx, "checking restaurant availability",
+
+	err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		slot, err := u.restaurantSlot(ctx, restaurantID, availabilityID)
+		if err != nil {
+			return err
+		}
+
+		// Bookings cancelled in bulk and seats that failed to be released give their seats back only
+		// with the reconciliation, which is run for the date first so that an emptied slot can be
+		// deleted.
+		if _, err := u.availabilityRepo.ReconcileReservedSeats(ctx, slot.Date, slot.Date); err != nil {
+			return err
+		}
+		if slot, err = u.availabilityRepo.GetByID(ctx, slot.ID); err != nil {
+			return err
+		}
+
+		bookings, err := u.impactedBookings(ctx, slot)
+		if err != nil {
+			return err
+		}
+		if len(bookings) > 0 {
+			return &AvailabilityInUseError{Availability: slot, Bookings: bookings}
+		}
+
+		// The repository checks the reserved seats again under a lock, against a booking made
+		// since the slot was read.
+		if err := u.availabilityRepo.Delete(ctx, slot.ID); err != nil {
+			if err.Error() == common.ErrAvailabilityReserved {
+				return &AvailabilityInUseError{Availability: slot, Bookings: bookings}
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info(ctx, "availability deleted",
 		zap.String("restaurantID", restaurantID),
+		zap.String("availabilityID", availabilityID))
+	return nil
+}
+
+// restaurantSlot returns the slot, which must be one of the restaurant.
+func (u *availabilityUseCase) restaurantSlot(ctx context.Context, restaurantID, availabilityID string) (*domain.Availability, error) {
+	slot, err := u.availabilityRepo.GetByID(ctx, availabilityID)
+	if err != nil {
+		return nil, err
+	}
+	if slot.RestaurantID != restaurantID {
+		return nil, errors.New(common.ErrAvailabilityNotFound)
+	}
+	return slot, nil
+}
+
+func (u *availabilityUseCase) ReconcileReservedSeats(ctx context.Context) ([]domain.ReservedSeatsDrift, error) {
+	log, _ := logger.FromContext(ctx)
+
+	now := u.clock.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	drifts, err := u.availabilityRepo.ReconcileReservedSeats(ctx, from, time.Time{})
+	if err != nil {
+		log.Error(ctx, "failed to reconcile reserved seats", zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "reserved seats reconciled",
+		zap.Time("from", from),
+		zap.Int("drifted", len(drifts)))
+	return drifts, nil
+}
+
+func (u *availabilityUseCase) ReservedSeatsReport(ctx context.Context, date time.Time, fix bool) ([]domain.ReservedSeatsDrift, error) {
+	log, _ := logger.FromContext(ctx)
+
+	// The report reuses the reconciliation and rolls it back unless a fix was asked for.
+	run := u.transactor.DryRun
+	if fix {
+		run = u.transactor.InTransaction
+	}
+
+	var drifts []domain.ReservedSeatsDrift
+	err := run(ctx, func(ctx context.Context) error {
+		var err error
+		drifts, err = u.availabilityRepo.ReconcileReservedSeats(ctx, date, date)
+		return err
+	})
+	if err != nil {
+		log.Error(ctx, "failed to build reserved seats report",
+			zap.Time("date", date),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "reserved seats report built",
+		zap.Time("date", date),
+		zap.Bool("fix", fix),
+		zap.Int("mismatches", len(drifts)))
+	return drifts, nil
+}
+
+func (u *availabilityUseCase) CheckAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time, timeSlot string, guestsCount int) (bool, error) {
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "checking restaurant availability",
+		zap.String("restaurantID", restaurantID.String()),
 		zap.Time("date", date),
 		zap.String("timeSlot", timeSlot),
 		zap.Int("guestsCount", guestsCount))
@@ -100,7 +422,7 @@ func (u *availabilityUseCase) CheckAvailability(ctx context.Context, restaurantI
 	availabilities, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, restaurantID, date)
 	if err != nil {
 		log.Error(ctx, "failed to get restaurant availability",
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Time("date", date),
 			zap.Error(err))
 		return false, err
@@ -110,7 +432,7 @@ func (u *availabilityUseCase) CheckAvailability(ctx context.Context, restaurantI
 		if avail.TimeSlot == timeSlot {
 			isAvailable := avail.AvailableSeats() >= guestsCount
 			log.Info(ctx, "availability check result",
-				zap.String("restaurantID", restaurantID),
+				zap.String("restaurantID", restaurantID.String()),
 				zap.Time("date", date),
 				zap.String("timeSlot", timeSlot),
 				zap.Int("guestsCount", guestsCount),
@@ -121,8 +443,157 @@ func (u *availabilityUseCase) CheckAvailability(ctx context.Context, restaurantI
 	}
 
 	log.Warn(ctx, "time slot not found",
-		zap.String("restaurantID", restaurantID),
+		zap.String("restaurantID", restaurantID.String()),
 		zap.Time("date", date),
 		zap.String("timeSlot", timeSlot))
 	return false, nil
 }
+
+// GenerateAvailability creates availability slots for every day of [From, To] the restaurant is not
+// closed on according to the working hours valid on that day. Existing slots keep their reserved seats and get the new capacity;
+// the new ones are inserted together in bulk. All slots are written in one transaction, which is
+// rolled back when params.DryRun is set.
+func (u *availabilityUseCase) GenerateAvailability(ctx context.Context, params GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "generating restaurant availability",
+		zap.String("restaurantID", params.RestaurantID),
+		zap.Time("from", params.From),
+		zap.Time("to", params.To),
+		zap.Duration("slotDuration", params.SlotDuration),
+		zap.Int("capacity", params.Capacity),
+		zap.Bool("dryRun", params.DryRun))
+
+	from := truncateToDate(params.From)
+	to := truncateToDate(params.To)
+	if to.Before(from) || to.Sub(from) >= maxGenerateAvailabilityDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+	if params.SlotDuration < time.Minute {
+		return nil, ErrInvalidSlotDuration
+	}
+	if params.Capacity < 1 {
+		return nil, ErrInvalidCapacity
+	}
+
+	workingHours, err := u.workingHoursRepo.GetByRestaurantID(ctx, domain.RestaurantID(params.RestaurantID))
+	if err != nil {
+		log.Error(ctx, "failed to get working hours for availability generation",
+			zap.String("restaurantID", params.RestaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+	specialDays, err := u.specialDayRepo.ListByRestaurant(ctx, params.RestaurantID, from)
+	if err != nil {
+		log.Error(ctx, "failed to get special days for availability generation",
+			zap.String("restaurantID", params.RestaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+	closures, err := u.closureRepo.ListByRestaurant(ctx, params.RestaurantID, from)
+	if err != nil {
+		log.Error(ctx, "failed to get closures for availability generation",
+			zap.String("restaurantID", params.RestaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	run := u.transactor.InTransaction
+	if params.DryRun {
+		run = u.transactor.DryRun
+	}
+
+	var generated []*domain.Availability
+	err = run(ctx, func(ctx context.Context) error {
+		generated = make([]*domain.Availability, 0)
+		created := make([]*domain.Availability, 0)
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			day := domain.ScheduleOn(workingHours, specialDays, closures, date)
+			slots := availabilitySlots(day.Hours, date, params.SlotDuration)
+			if len(slots) == 0 {
+				continue
+			}
+
+			existing, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(params.RestaurantID), date)
+			if err != nil {
+				return err
+			}
+			existingSlots := make(map[string]*domain.Availability, len(existing))
+			for _, availability := range existing {
+				existingSlots[availability.TimeSlot] = availability
+			}
+
+			for _, slot := range slots {
+				availability := &domain.Availability{
+					RestaurantID: params.RestaurantID,
+					Date:         date,
+					TimeSlot:     slot,
+					Capacity:     params.Capacity,
+					UpdatedAt:    u.clock.Now(),
+				}
+
+				current, ok := existingSlots[slot]
+				if !ok {
+					created = append(created, availability)
+					generated = append(generated, availability)
+					continue
+				}
+				if current.Capacity == params.Capacity {
+					// Rewriting the slot unchanged would only make it look changed to syncing clients.
+					generated = append(generated, current)
+					continue
+				}
+
+				if err := u.availabilityRepo.SetAvailability(ctx, availability, false); err != nil {
+					if err.Error() == common.ErrCapacityBelowReserved {
+						return u.capacityConflict(ctx, availability)
+					}
+					log.Error(ctx, "failed to set generated availability",
+						zap.String("restaurantID", params.RestaurantID),
+						zap.Time("date", date),
+						zap.String("timeSlot", slot),
+						zap.Error(err))
+					return err
+				}
+
+				generated = append(generated, availability)
+			}
+		}
+
+		if err := u.availabilityRepo.CreateBatch(ctx, created); err != nil {
+			log.Error(ctx, "failed to insert generated availability",
+				zap.String("restaurantID", params.RestaurantID),
+				zap.Int("count", len(created)),
+				zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "restaurant availability successfully generated",
+		zap.String("restaurantID", params.RestaurantID),
+		zap.Int("count", len(generated)),
+		zap.Bool("dryRun", params.DryRun))
+	return generated, nil
+}
+
+// availabilitySlots returns the start times ("15:04") of the slots that fit entirely
+// into the opening hours of the date.
+func availabilitySlots(opening []domain.OpeningHours, date time.Time, slotDuration time.Duration) []string {
+	endOfDay := date.Add(24 * time.Hour)
+
+	slots := make([]string, 0)
+	for _, hours := range opening {
+		for start := hours.OpensAt; !start.Add(slotDuration).After(hours.ClosesAt) && start.Before(endOfDay); start = start.Add(slotDuration) {
+			slots = append(slots, start.Format("15:04"))
+		}
+	}
+
+	return slots
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidAvailabilityAlert = errors.New("invalid availability alert")
+
+	// ErrTableAvailable is returned for an alert a table is already free for; the guest can
+	// book it right away.
+	ErrTableAvailable = errors.New("a table is already available for the alert, book it instead")
+)
+
+// AvailabilityAlertUseCase tells guests when a table they asked about frees up, e.g. after a
+// cancellation.
+type AvailabilityAlertUseCase interface {
+	// CreateAlert subscribes the user of the alert to a table for its party at the restaurant in
+	// its window on its date. A guest may only subscribe themselves.
+	CreateAlert(ctx context.Context, alert *domain.AvailabilityAlert) error
+
+	// CheckAlerts expires the alerts whose date is before now, tells the guests of the others a
+	// slot has room for, once per alert, and returns how many were told. An alert that fails to
+	// be checked does not stop the others.
+	CheckAlerts(ctx context.Context, now time.Time) (int, error)
+}
+
+type availabilityAlertUseCase struct {
+	alertRepo        repository.AvailabilityAlertRepository
+	availabilityRepo repository.AvailabilityRepository
+	restaurantRepo   repository.RestaurantRepository
+	notifier         domain.NotificationService
+	clock            clock.Clock
+}
+
+func NewAvailabilityAlertUseCase(
+	alertRepo repository.AvailabilityAlertRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	restaurantRepo repository.RestaurantRepository,
+	notifier domain.NotificationService,
+	clock clock.Clock,
+) AvailabilityAlertUseCase {
+	return &availabilityAlertUseCase{
+		alertRepo:        alertRepo,
+		availabilityRepo: availabilityRepo,
+		restaurantRepo:   restaurantRepo,
+		notifier:         notifier,
+		clock:            clock,
+	}
+}
+
+func (u *availabilityAlertUseCase) CreateAlert(ctx context.Context, alert *domain.AvailabilityAlert) error {
+	if principal, ok := tenant.FromContext(ctx); ok {
+		if alert.UserID == "" {
+			alert.UserID = principal.UserID
+		}
+		if !principal.CanAccessUser(alert.UserID) {
+			return tenant.ErrAccessDenied
+		}
+	}
+
+	now := u.clock.Now()
+	if err := validateAvailabilityAlert(alert, now); err != nil {
+		return err
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(alert.RestaurantID)); err != nil {
+		return err
+	}
+
+	slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(alert.RestaurantID), alert.Date)
+	if err != nil {
+		return err
+	}
+	if slot := freedSlot(alert, slots, now); slot != nil {
+		return fmt.Errorf("%w: %s", ErrTableAvailable, slot.TimeSlot)
+	}
+
+	return u.alertRepo.Create(ctx, alert)
+}
+
+func validateAvailabilityAlert(alert *domain.AvailabilityAlert, now time.Time) error {
+	if alert.UserID == "" {
+		return fmt.Errorf("%w: user is required", ErrInvalidAvailabilityAlert)
+	}
+	if alert.PartySize < 1 {
+		return fmt.Errorf("%w: party size must be at least 1", ErrInvalidAvailabilityAlert)
+	}
+
+	from, err := time.Parse("15:04", alert.FromTime)
+	if err != nil {
+		return fmt.Errorf("%w: from_time must be HH:MM", ErrInvalidAvailabilityAlert)
+	}
+	to, err := time.Parse("15:04", alert.ToTime)
+	if err != nil {
+		return fmt.Errorf("%w: to_time must be HH:MM", ErrInvalidAvailabilityAlert)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("%w: to_time is before from_time", ErrInvalidAvailabilityAlert)
+	}
+	// Slots are compared as text, so the times are kept as "15:04".
+	alert.FromTime = from.Format("15:04")
+	alert.ToTime = to.Format("15:04")
+
+	alert.Date = time.Date(alert.Date.Year(), alert.Date.Month(), alert.Date.Day(), 0, 0, 0, 0, alert.Date.Location())
+	if alert.Date.Format(time.DateOnly) < now.Format(time.DateOnly) {
+		return fmt.Errorf("%w: date is in the past", ErrInvalidAvailabilityAlert)
+	}
+	return nil
+}
+
+func (u *availabilityAlertUseCase) CheckAlerts(ctx context.Context, now time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	today := startOfDay(now)
+	expired, err := u.alertRepo.ExpireBefore(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	alerts, err := u.alertRepo.ListActive(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		notified    int
+		errs        []error
+		slots       = make(map[string][]*domain.Availability)
+		restaurants = make(map[string]*domain.Restaurant)
+	)
+	for _, alert := range alerts {
+		if ctx.Err() != nil {
+			break
+		}
+
+		key := alert.RestaurantID + "/" + alert.Date.Format(time.DateOnly)
+		daySlots, ok := slots[key]
+		if !ok {
+			daySlots, err = u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(alert.RestaurantID), alert.Date)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			slots[key] = daySlots
+		}
+
+		slot := freedSlot(alert, daySlots, now)
+		if slot == nil {
+			continue
+		}
+
+		restaurant, ok := restaurants[alert.RestaurantID]
+		if !ok {
+			restaurant, err = u.restaurantRepo.GetByID(ctx, domain.RestaurantID(alert.RestaurantID))
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			restaurants[alert.RestaurantID] = restaurant
+		}
+
+		// The alert is marked first, so that a guest is told once even by concurrent runs.
+		if err := u.alertRepo.MarkNotified(ctx, alert.ID, slot.TimeSlot, now); err != nil {
+			if err.Error() != common.ErrAvailabilityAlertNotFound {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		notified++
+
+		title, message := FormatAvailabilityAlert(alert, restaurant, slot.TimeSlot)
+		err := u.notifier.NotifyUser(ctx, alert.UserID, domain.NotificationTypeAvailabilityAlert, title, message, alert.RestaurantID)
+		if err != nil {
+			log.Error(ctx, "failed to send notification to user",
+				zap.String("userID", alert.UserID),
+				zap.String("alertID", alert.ID),
+				zap.Error(err))
+		}
+	}
+
+	log.Info(ctx, "availability alerts checked",
+		zap.Int("active", len(alerts)),
+		zap.Int("notified", notified),
+		zap.Int("expired", expired))
+	return notified, errors.Join(errs...)
+}
+
+// freedSlot returns the earliest slot of the day with room for the alert that has not started
+// by now, or nil.
+func freedSlot(alert *domain.AvailabilityAlert, slots []*domain.Availability, now time.Time) *domain.Availability {
+	var started string
+	if alert.Date.Format(time.DateOnly) == now.Format(time.DateOnly) {
+		started = now.Format("15:04")
+	}
+
+	var freed *domain.Availability
+	for _, slot := range slots {
+		if slot.TimeSlot <= started || !alert.Matches(slot) {
+			continue
+		}
+		if freed == nil || slot.TimeSlot < freed.TimeSlot {
+			freed = slot
+		}
+	}
+	return freed
+}
+
+// FormatAvailabilityAlert renders the title and message telling the guest of the alert about the
+// slot.
+func FormatAvailabilityAlert(alert *domain.AvailabilityAlert, restaurant *domain.Restaurant, timeSlot string) (string, string) {
+	return "A table freed up at " + restaurant.Name,
+		fmt.Sprintf("A table for %d is available at %s on %s at %s. Book it soon, before someone else does.",
+			alert.PartySize, restaurant.Name, alert.Date.Format("02.01.2006"), timeSlot)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
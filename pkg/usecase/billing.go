@@ -0,0 +1,372 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/pdf"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidPrice            = errors.New("invalid price")
+	ErrInvalidInvoiceStatus    = errors.New("invalid invoice status")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidPaymentEvent     = errors.New("invalid payment event")
+)
+
+// invoiceTemplate is the text of an invoice PDF; lines starting with "# " are headings.
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"date":   func(t time.Time) string { return t.Format("2006-01-02") },
+	"amount": domain.FormatAmount,
+	"row":    func(label, value string) string { return fmt.Sprintf("%-48s %20s", label, value) },
+}).Parse(`# Invoice {{.Number}}
+
+{{.Issuer}}
+
+Billed to:  {{.RestaurantName}}
+Issued:     {{date .IssuedAt}}
+Due:        {{date .DueAt}}
+Status:     {{.Status}}
+
+{{row "Description" "Amount"}}
+{{row "--------------------------------------------" "--------------------"}}
+{{row (printf "%s plan, %s to %s" .Plan (date .PeriodStart) (date .LastDay)) (amount .Amount .Currency)}}
+{{row "" "--------------------"}}
+{{row "Total" (amount .Amount .Currency)}}
+{{if .PaidAt}}
+Paid on {{date .PaidAt}}{{with .PaymentID}}, payment {{.}}{{end}}. Thank you.
+{{else}}
+Please pay by {{date .DueAt}}, quoting {{.Number}}.
+{{end}}`))
+
+// invoiceDocument is what the invoice template renders.
+type invoiceDocument struct {
+	*domain.Invoice
+	Issuer string
+}
+
+func (d invoiceDocument) LastDay() time.Time {
+	return d.PeriodEnd.AddDate(0, 0, -1)
+}
+
+// BillingSettings set what subscriptions cost and how they are billed.
+type BillingSettings struct {
+	// Prices are the monthly prices of the paid plans in minor units of Currency.
+	Prices          map[domain.Plan]int64
+	Currency        string
+	PaymentTermDays int
+	// WebhookSecret signs the events of the payment provider; without it every event is rejected.
+	WebhookSecret string
+	// Issuer is the name invoices are issued by.
+	Issuer string
+}
+
+// BillingUseCase keeps the subscriptions of restaurants to paid plans, bills them monthly and
+// follows the payment of the invoices.
+type BillingUseCase interface {
+	// Subscribe puts the restaurant on a paid plan from now on at price, or at the plan's price when
+	// it is nil, ending its current subscription; admins only. The quotas of the plan apply at once.
+	Subscribe(ctx context.Context, restaurantID string, plan domain.Plan, price *int64, now time.Time) (*domain.Subscription, error)
+
+	// CancelSubscription ends the subscription now and moves the restaurant back to the free plan;
+	// admins only.
+	CancelSubscription(ctx context.Context, id string, now time.Time) error
+
+	// ListSubscriptions returns the subscriptions of the restaurant, or of every restaurant when it
+	// is empty, newest first; admins only.
+	ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error)
+
+	// GenerateInvoices bills the subscriptions active in the month of month that were not billed
+	// for it yet and returns the new invoices; admins only.
+	GenerateInvoices(ctx context.Context, month, now time.Time) ([]*domain.Invoice, error)
+
+	// ListInvoices returns the invoices matching the filter, newest first; admins only.
+	ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error)
+
+	// RenderInvoice returns the invoice with its PDF; admins only.
+	RenderInvoice(ctx context.Context, id string) (*domain.Invoice, []byte, error)
+
+	// HandlePaymentEvent applies an event of the payment provider to its invoice once, after
+	// checking that signature is the hex HMAC-SHA256 of payload with the webhook secret. A
+	// failure reported after the invoice was paid is ignored.
+	HandlePaymentEvent(ctx context.Context, payload []byte, signature string) error
+}
+
+type billingUseCase struct {
+	billingRepo    repository.BillingRepository
+	quotaRepo      repository.QuotaRepository
+	restaurantRepo repository.RestaurantRepository
+	transactor     repository.Transactor
+	settings       BillingSettings
+	clock          clock.Clock
+}
+
+func NewBillingUseCase(
+	billingRepo repository.BillingRepository,
+	quotaRepo repository.QuotaRepository,
+	restaurantRepo repository.RestaurantRepository,
+	transactor repository.Transactor,
+	clock clock.Clock,
+	settings BillingSettings,
+) BillingUseCase {
+	return &billingUseCase{
+		billingRepo:    billingRepo,
+		quotaRepo:      quotaRepo,
+		restaurantRepo: restaurantRepo,
+		transactor:     transactor,
+		settings:       settings,
+		clock:          clock,
+	}
+}
+
+func (u *billingUseCase) Subscribe(
+	ctx context.Context,
+	restaurantID string,
+	plan domain.Plan,
+	price *int64,
+	now time.Time,
+) (*domain.Subscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if plan == domain.PlanFree || !slices.Contains(domain.Plans, plan) {
+		return nil, fmt.Errorf("%w: %q is not a paid plan", ErrInvalidPlan, plan)
+	}
+
+	subscription := &domain.Subscription{
+		RestaurantID: restaurantID,
+		Plan:         plan,
+		MonthlyPrice: u.settings.Prices[plan],
+		Currency:     u.settings.Currency,
+		StartedAt:    now,
+	}
+	if price != nil {
+		if *price < 0 {
+			return nil, fmt.Errorf("%w: the price cannot be negative", ErrInvalidPrice)
+		}
+		subscription.MonthlyPrice = *price
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		current, err := u.billingRepo.GetActiveSubscription(ctx, restaurantID)
+		switch {
+		case err == nil:
+			if err := u.billingRepo.CancelSubscription(ctx, current.ID, now); err != nil {
+				return err
+			}
+		case err.Error() != common.ErrSubscriptionNotFound:
+			return err
+		}
+
+		if err := u.billingRepo.CreateSubscription(ctx, subscription); err != nil {
+			return err
+		}
+		return u.movePlan(ctx, restaurantID, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "restaurant subscribed",
+		zap.String("restaurantID", restaurantID),
+		zap.String("plan", string(plan)),
+		zap.Int64("monthlyPrice", subscription.MonthlyPrice))
+	return subscription, nil
+}
+
+func (u *billingUseCase) CancelSubscription(ctx context.Context, id string, now time.Time) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	return u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		subscription, err := u.billingRepo.GetSubscription(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := u.billingRepo.CancelSubscription(ctx, id, now); err != nil {
+			return err
+		}
+		return u.movePlan(ctx, subscription.RestaurantID, domain.PlanFree)
+	})
+}
+
+// movePlan changes the plan of the restaurant, keeping the limits an admin overrode.
+func (u *billingUseCase) movePlan(ctx context.Context, restaurantID string, plan domain.Plan) error {
+	current, err := u.quotaRepo.GetPlan(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	current.Plan = plan
+	return u.quotaRepo.SetPlan(ctx, current)
+}
+
+func (u *billingUseCase) ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return u.billingRepo.ListSubscriptions(ctx, restaurantID, offset, limit)
+}
+
+func (u *billingUseCase) GenerateInvoices(ctx context.Context, month, now time.Time) ([]*domain.Invoice, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	periodStart := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 1, 0)
+	if periodEnd.After(now) {
+		return nil, fmt.Errorf("%w: %s is not over yet", ErrInvalidDateRange, periodStart.Format("2006-01"))
+	}
+
+	invoices, err := u.billingRepo.CreateInvoices(ctx, periodStart, periodEnd, now, now.AddDate(0, 0, u.settings.PaymentTermDays))
+	if err != nil {
+		return nil, err
+	}
+
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "invoices generated",
+		zap.Time("periodStart", periodStart),
+		zap.Int("count", len(invoices)))
+	return invoices, nil
+}
+
+func (u *billingUseCase) ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if filter.Status != "" && !slices.Contains(domain.InvoiceStatuses, filter.Status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidInvoiceStatus, filter.Status)
+	}
+
+	return u.billingRepo.ListInvoices(ctx, filter, offset, limit)
+}
+
+func (u *billingUseCase) RenderInvoice(ctx context.Context, id string) (*domain.Invoice, []byte, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	invoice, err := u.billingRepo.GetInvoice(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	text, err := FormatInvoice(invoice, u.settings.Issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return invoice, pdf.FromText(text), nil
+}
+
+// FormatInvoice writes the text of the invoice issued by issuer, laid out for a monospaced font.
+func FormatInvoice(invoice *domain.Invoice, issuer string) (string, error) {
+	var b bytes.Buffer
+	if err := invoiceTemplate.Execute(&b, invoiceDocument{Invoice: invoice, Issuer: issuer}); err != nil {
+		return "", fmt.Errorf("%s: %w", common.ErrRenderInvoice, err)
+	}
+	return b.String(), nil
+}
+
+func (u *billingUseCase) HandlePaymentEvent(ctx context.Context, payload []byte, signature string) error {
+	if !u.validSignature(payload, signature) {
+		return ErrInvalidWebhookSignature
+	}
+
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPaymentEvent, err)
+	}
+	if event.ID == "" || event.InvoiceID == "" {
+		return fmt.Errorf("%w: id and invoice_id are required", ErrInvalidPaymentEvent)
+	}
+	if event.Type != domain.PaymentEventSucceeded && event.Type != domain.PaymentEventFailed {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidPaymentEvent, event.Type)
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = u.clock.Now()
+	}
+
+	log, _ := logger.FromContext(ctx)
+	return u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		invoice, err := u.billingRepo.GetInvoice(ctx, event.InvoiceID)
+		if err != nil {
+			return err
+		}
+
+		recorded, err := u.billingRepo.RecordPaymentEvent(ctx, &event)
+		if err != nil {
+			return err
+		}
+		if !recorded {
+			log.Info(ctx, "payment event already handled", zap.String("eventID", event.ID))
+			return nil
+		}
+
+		if invoice.Status == domain.InvoiceStatusPaid {
+			return nil
+		}
+
+		invoice.PaymentID = event.PaymentID
+		if event.Type == domain.PaymentEventSucceeded {
+			invoice.Status = domain.InvoiceStatusPaid
+			invoice.PaidAt = &event.OccurredAt
+		} else {
+			invoice.Status = domain.InvoiceStatusFailed
+		}
+
+		if err := u.billingRepo.UpdateInvoicePayment(ctx, invoice); err != nil {
+			return err
+		}
+
+		log.Info(ctx, "invoice payment updated",
+			zap.String("invoiceID", invoice.ID),
+			zap.String("number", invoice.Number),
+			zap.String("status", string(invoice.Status)),
+			zap.String("eventID", event.ID))
+		return nil
+	})
+}
+
+func (u *billingUseCase) validSignature(payload []byte, signature string) bool {
+	if u.settings.WebhookSecret == "" {
+		return false
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(u.settings.WebhookSecret))
+	mac.Write(payload)
+	return hmac.Equal(decoded, mac.Sum(nil))
+}
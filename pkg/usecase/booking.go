@@ -4,11 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 
 	"go.uber.org/zap"
 )
@@ -16,27 +21,39 @@ import (
 var (
 	ErrNoAvailability       = errors.New("no availability for this time")
 	ErrInvalidBookingStatus = errors.New("invalid booking status")
+	ErrInvalidOccasion      = errors.New("invalid booking occasion")
 )
 
 type BookingUseCase interface {
-	GetBooking(ctx context.Context, id string) (*domain.Booking, error)
+	GetBooking(ctx context.Context, id domain.BookingID) (*domain.Booking, error)
 
-	GetRestaurantBookings(ctx context.Context, restaurantID string) ([]*domain.Booking, error)
+	// GetRestaurantBookings returns a page of the bookings of a restaurant that pass the filter, the
+	// latest first.
+	GetRestaurantBookings(ctx context.Context, restaurantID domain.RestaurantID, filter domain.BookingFilter, page PageRequest) (*PageResponse[*domain.Booking], error)
 
-	GetUserBookings(ctx context.Context, userID string) ([]*domain.Booking, error)
+	// GetUserBookings returns a page of the bookings of a user, the latest first.
+	GetUserBookings(ctx context.Context, userID domain.UserID, page PageRequest) (*PageResponse[*domain.Booking], error)
 
+	// CreateBooking books a slot for the party. In a restaurant with tables the party is seated at
+	// the table it asked for, or else at the smallest free table it fits at; it fails with
+	// ErrTableUnavailable when the table asked for is not free or too small, and with
+	// ErrNoAvailability when no table fits.
 	CreateBooking(ctx context.Context, booking *domain.Booking) (string, error)
 
-	ConfirmBooking(ctx context.Context, id string) error
+	ConfirmBooking(ctx context.Context, id domain.BookingID) error
 
-	RejectBooking(ctx context.Context, id string, reason string) error
+	RejectBooking(ctx context.Context, id domain.BookingID, reason string) error
 
-	CancelBooking(ctx context.Context, id string) error
+	CancelBooking(ctx context.Context, id domain.BookingID) error
 
-	CompleteBooking(ctx context.Context, id string) error
+	CompleteBooking(ctx context.Context, id domain.BookingID) error
 
-	SuggestAlternativeTime(ctx context.Context, bookingID string, date time.Time, time string, message string) (string, error)
+	SuggestAlternativeTime(ctx context.Context, bookingID domain.BookingID, date time.Time, time string, message string) (string, error)
 
+	// AcceptAlternative moves the booking to the date and time of the alternative and confirms it.
+	// In a restaurant with tables the party keeps its table when it is free then, or else is
+	// seated at the smallest free table it fits at; it fails with ErrNoAvailability when no table
+	// fits and with ErrTableUnavailable when the table is taken meanwhile.
 	AcceptAlternative(ctx context.Context, alternativeID string) error
 
 	RejectAlternative(ctx context.Context, alternativeID string) error
@@ -45,31 +62,67 @@ type BookingUseCase interface {
 type bookingUseCase struct {
 	bookingRepo      repository.BookingRepository
 	availabilityRepo repository.AvailabilityRepository
+	tableRepo        repository.TableRepository
 	notificationSvc  domain.NotificationService
+	transactor       repository.Transactor
+	clock            clock.Clock
 }
 
 func NewBookingUseCase(
 	bookingRepo repository.BookingRepository,
 	availabilityRepo repository.AvailabilityRepository,
+	tableRepo repository.TableRepository,
 	notificationSvc domain.NotificationService,
+	transactor repository.Transactor,
+	clock clock.Clock,
 ) BookingUseCase {
 	return &bookingUseCase{
 		bookingRepo:      bookingRepo,
 		availabilityRepo: availabilityRepo,
+		tableRepo:        tableRepo,
 		notificationSvc:  notificationSvc,
+		transactor:       transactor,
+		clock:            clock,
 	}
 }
 
-func (u *bookingUseCase) GetBooking(ctx context.Context, id string) (*domain.Booking, error) {
+func (u *bookingUseCase) GetBooking(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
 	return u.bookingRepo.GetByID(ctx, id)
 }
 
-func (u *bookingUseCase) GetRestaurantBookings(ctx context.Context, restaurantID string) ([]*domain.Booking, error) {
-	return u.bookingRepo.GetByRestaurantID(ctx, restaurantID)
+func (u *bookingUseCase) GetRestaurantBookings(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	page PageRequest,
+) (*PageResponse[*domain.Booking], error) {
+	after, err := decodeCursor[domain.BookingCursor](page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.Size()
+	bookings, err := u.bookingRepo.ListByRestaurant(ctx, restaurantID, filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(bookings, limit, (*domain.Booking).CursorAfter)
 }
 
-func (u *bookingUseCase) GetUserBookings(ctx context.Context, userID string) ([]*domain.Booking, error) {
-	return u.bookingRepo.GetByUserID(ctx, userID)
+func (u *bookingUseCase) GetUserBookings(ctx context.Context, userID domain.UserID, page PageRequest) (*PageResponse[*domain.Booking], error) {
+	after, err := decodeCursor[domain.BookingCursor](page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.Size()
+	bookings, err := u.bookingRepo.ListByUser(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(bookings, limit, (*domain.Booking).CursorAfter)
 }
 
 func (u *bookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
@@ -80,7 +133,24 @@ func (u *bookingUseCase) CreateBooking(ctx context.Context, booking *domain.Book
 		zap.String("time", booking.Time),
 		zap.Int("guests", booking.GuestsCount))
 
-	availabilities, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, booking.RestaurantID, booking.Date)
+	if booking.Occasion != "" && !slices.Contains(domain.BookingOccasions, booking.Occasion) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidOccasion, booking.Occasion)
+	}
+	booking.SeatingZone = strings.TrimSpace(booking.SeatingZone)
+	if err := booking.Validate(); err != nil {
+		return "", err
+	}
+	booking.TableRequested = booking.TableID != ""
+	if booking.Attribution != nil {
+		if err := booking.Attribution.Normalize(); err != nil {
+			return "", err
+		}
+		if booking.Attribution.IsZero() {
+			booking.Attribution = nil
+		}
+	}
+
+	availabilities, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(booking.RestaurantID), booking.Date)
 	if err != nil {
 		log.Error(ctx, "failed to get availability",
 			zap.String("restaurantID", booking.RestaurantID),
@@ -89,51 +159,63 @@ func (u *bookingUseCase) CreateBooking(ctx context.Context, booking *domain.Book
 		return "", err
 	}
 
-	var availabilityID string
-	var availableSeats int
-
-	for _, avail := range availabilities {
-		if avail.TimeSlot == booking.Time {
-			availabilityID = avail.ID
-			availableSeats = avail.AvailableSeats()
-			break
-		}
+	slot := timeSlot(availabilities, booking.Time)
+	if slot == nil {
+		log.Warn(ctx, "no slot for booking",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.Time("date", booking.Date),
+			zap.String("time", booking.Time))
+		return "", ErrNoAvailability
 	}
 
-	if availabilityID == "" || availableSeats < booking.GuestsCount {
+	// In a restaurant with tables the party is seated at a table instead of being counted against
+	// the seats of the slot.
+	table, err := seatingTable(ctx, u.tableRepo, booking)
+	if err != nil {
+		return "", err
+	}
+	if table != nil {
+		booking.TableID = table.ID
+	} else if slot.AvailableSeats() < booking.GuestsCount {
 		log.Warn(ctx, "no availability for booking",
 			zap.String("restaurantID", booking.RestaurantID),
 			zap.Time("date", booking.Date),
 			zap.String("time", booking.Time),
 			zap.Int("requestedSeats", booking.GuestsCount),
-			zap.Int("availableSeats", availableSeats))
+			zap.Int("availableSeats", slot.AvailableSeats()))
 		return "", ErrNoAvailability
 	}
 
-	now := time.Now()
+	now := u.clock.Now()
 	booking.Status = domain.BookingStatusPending
 	booking.CreatedAt = now
 	booking.UpdatedAt = now
 
 	if err := u.bookingRepo.Create(ctx, booking); err != nil {
+		if err.Error() == common.ErrTableUnavailable {
+			return "", ErrTableUnavailable
+		}
 		log.Error(ctx, "failed to create booking", zap.Error(err))
 		return "", err
 	}
 
-	if err := u.availabilityRepo.UpdateReservedSeats(ctx, availabilityID, booking.GuestsCount); err != nil {
-		deleteErr := u.bookingRepo.UpdateStatus(ctx, booking.ID, domain.BookingStatusCancelled)
-		if deleteErr != nil {
-			log.Error(ctx, "failed to cancel booking after unsuccessful availability update",
-				zap.String("bookingID", booking.ID),
-				zap.Error(deleteErr))
-			fmt.Printf("failed to cancel booking %s after unsuccessful availability update: %v\n",
-				booking.ID, deleteErr)
+	// A booking seated at a table holds the table rather than the seats of the slot.
+	if table == nil {
+		if err := u.availabilityRepo.UpdateReservedSeats(ctx, slot.ID, booking.GuestsCount); err != nil {
+			deleteErr := u.bookingRepo.UpdateStatus(ctx, domain.BookingID(booking.ID), domain.BookingStatusCancelled)
+			if deleteErr != nil {
+				log.Error(ctx, "failed to cancel booking after unsuccessful availability update",
+					zap.String("bookingID", booking.ID),
+					zap.Error(deleteErr))
+				fmt.Printf("failed to cancel booking %s after unsuccessful availability update: %v\n",
+					booking.ID, deleteErr)
+			}
+			log.Error(ctx, "failed to update seats availability",
+				zap.String("availabilityID", slot.ID),
+				zap.Int("guestsCount", booking.GuestsCount),
+				zap.Error(err))
+			return "", fmt.Errorf("failed to update seats availability: %w", err)
 		}
-		log.Error(ctx, "failed to update seats availability",
-			zap.String("availabilityID", availabilityID),
-			zap.Int("guestsCount", booking.GuestsCount),
-			zap.Error(err))
-		return "", fmt.Errorf("failed to update seats availability: %w", err)
 	}
 
 	err = u.notificationSvc.NotifyRestaurant(
@@ -155,36 +237,106 @@ func (u *bookingUseCase) CreateBooking(ctx context.Context, booking *domain.Book
 		zap.String("bookingID", booking.ID),
 		zap.String("restaurantID", booking.RestaurantID),
 		zap.Time("date", booking.Date),
-		zap.String("time", booking.Time))
+		zap.String("time", booking.Time),
+		zap.String("tableID", booking.TableID))
 
 	return booking.ID, nil
 }
 
-func (u *bookingUseCase) ConfirmBooking(ctx context.Context, id string) error {
+// timeSlot returns the slot at the time of day, nil when there is none.
+func timeSlot(slots []*domain.Availability, clock string) *domain.Availability {
+	for _, slot := range slots {
+		if slot.TimeSlot == clock {
+			return slot
+		}
+	}
+	return nil
+}
+
+// seatingTable returns the table to seat the party of the booking at, nil for a restaurant without
+// tables.
+func seatingTable(ctx context.Context, tableRepo repository.TableRepository, booking *domain.Booking) (*domain.Table, error) {
+	log, _ := logger.FromContext(ctx)
+
+	tables, err := tableRepo.ListByRestaurant(ctx, booking.RestaurantID)
+	if err != nil {
+		log.Error(ctx, "failed to get tables",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+	if len(tables) == 0 {
+		if booking.TableID != "" {
+			return nil, ErrTableUnavailable
+		}
+		return nil, nil
+	}
+
+	holds, err := tableRepo.ListHolds(ctx, booking.RestaurantID, booking.Date)
+	if err != nil {
+		log.Error(ctx, "failed to get held tables",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.Time("date", booking.Date),
+			zap.Error(err))
+		return nil, err
+	}
+	free := domain.FreeTables(tables, holds, booking)
+
+	if booking.TableID != "" {
+		for _, table := range free {
+			if table.ID == booking.TableID && table.Fits(booking.GuestsCount) {
+				return table, nil
+			}
+		}
+		log.Warn(ctx, "requested table unavailable for booking",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.String("tableID", booking.TableID),
+			zap.Int("guests", booking.GuestsCount))
+		return nil, ErrTableUnavailable
+	}
+
+	table := domain.PickTable(free, booking)
+	if table == nil {
+		log.Warn(ctx, "no free table for booking",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.Time("date", booking.Date),
+			zap.String("time", booking.Time),
+			zap.Int("guests", booking.GuestsCount),
+			zap.Int("freeTables", len(free)))
+		return nil, ErrNoAvailability
+	}
+	return table, nil
+}
+
+func (u *bookingUseCase) ConfirmBooking(ctx context.Context, id domain.BookingID) error {
 	log, _ := logger.FromContext(ctx)
-	log.Info(ctx, "confirming booking", zap.String("bookingID", id))
+	log.Info(ctx, "confirming booking", zap.String("bookingID", id.String()))
 
 	booking, err := u.bookingRepo.GetByID(ctx, id)
 	if err != nil {
-		log.Error(ctx, "failed to get booking", zap.String("bookingID", id), zap.Error(err))
+		log.Error(ctx, "failed to get booking", zap.String("bookingID", id.String()), zap.Error(err))
 		return err
 	}
 
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
 	if booking.Status != domain.BookingStatusPending {
 		log.Warn(ctx, "invalid booking status for confirmation",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.String("currentStatus", string(booking.Status)))
 		return ErrInvalidBookingStatus
 	}
 
-	now := time.Now()
+	now := u.clock.Now()
 	booking.Status = domain.BookingStatusConfirmed
 	booking.UpdatedAt = now
 	booking.ConfirmedAt = &now
 
 	if err := u.bookingRepo.UpdateStatus(ctx, id, domain.BookingStatusConfirmed); err != nil {
 		log.Error(ctx, "failed to update booking status",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
@@ -200,50 +352,52 @@ func (u *bookingUseCase) ConfirmBooking(ctx context.Context, id string) error {
 	if err != nil {
 		log.Error(ctx, "failed to send notification to user",
 			zap.String("userID", booking.UserID),
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 	}
 
 	log.Info(ctx, "booking successfully confirmed",
-		zap.String("bookingID", id),
+		zap.String("bookingID", id.String()),
 		zap.String("restaurantID", booking.RestaurantID),
 		zap.String("userID", booking.UserID))
 
 	return nil
 }
 
-func (u *bookingUseCase) RejectBooking(ctx context.Context, id string, reason string) error {
+func (u *bookingUseCase) RejectBooking(ctx context.Context, id domain.BookingID, reason string) error {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "rejecting booking",
-		zap.String("bookingID", id),
+		zap.String("bookingID", id.String()),
 		zap.String("reason", reason))
 
 	booking, err := u.bookingRepo.GetByID(ctx, id)
 	if err != nil {
 		log.Error(ctx, "failed to get booking",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
 
-	if booking.Status != domain.BookingStatusPending {
-		log.Warn(ctx, "invalid booking status for rejection",
-			zap.String("bookingID", id),
-			zap.String("currentStatus", string(booking.Status)))
-		return ErrInvalidBookingStatus
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return tenant.ErrAccessDenied
 	}
 
-	now := time.Now()
-	booking.Status = domain.BookingStatusRejected
-	booking.UpdatedAt = now
-	booking.RejectedAt = &now
-
-	if err := u.bookingRepo.UpdateStatus(ctx, id, domain.BookingStatusRejected); err != nil {
+	now := u.clock.Now()
+	if err := u.deactivate(ctx, booking, domain.BookingStatusRejected, now, domain.BookingStatusPending); err != nil {
+		if errors.Is(err, ErrInvalidBookingStatus) {
+			log.Warn(ctx, "invalid booking status for rejection",
+				zap.String("bookingID", id.String()),
+				zap.String("currentStatus", string(booking.Status)))
+			return err
+		}
 		log.Error(ctx, "failed to update booking status",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
+	booking.Status = domain.BookingStatusRejected
+	booking.UpdatedAt = now
+	booking.RejectedAt = &now
 
 	message := "Your booking on " + booking.Date.Format("02.01.2006") + " at " + booking.Time + " has been rejected by the restaurant."
 	if reason != "" {
@@ -261,12 +415,12 @@ func (u *bookingUseCase) RejectBooking(ctx context.Context, id string, reason st
 	if err != nil {
 		log.Error(ctx, "failed to send notification to user",
 			zap.String("userID", booking.UserID),
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 	}
 
 	log.Info(ctx, "booking successfully rejected",
-		zap.String("bookingID", id),
+		zap.String("bookingID", id.String()),
 		zap.String("restaurantID", booking.RestaurantID),
 		zap.String("userID", booking.UserID),
 		zap.String("reason", reason))
@@ -274,34 +428,34 @@ func (u *bookingUseCase) RejectBooking(ctx context.Context, id string, reason st
 	return nil
 }
 
-func (u *bookingUseCase) CancelBooking(ctx context.Context, id string) error {
+func (u *bookingUseCase) CancelBooking(ctx context.Context, id domain.BookingID) error {
 	log, _ := logger.FromContext(ctx)
-	log.Info(ctx, "canceling booking", zap.String("bookingID", id))
+	log.Info(ctx, "canceling booking", zap.String("bookingID", id.String()))
 
 	booking, err := u.bookingRepo.GetByID(ctx, id)
 	if err != nil {
 		log.Error(ctx, "failed to get booking",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
 
-	if booking.Status == domain.BookingStatusCompleted || booking.Status == domain.BookingStatusRejected {
-		log.Warn(ctx, "invalid booking status for cancellation",
-			zap.String("bookingID", id),
-			zap.String("currentStatus", string(booking.Status)))
-		return ErrInvalidBookingStatus
-	}
-
-	booking.Status = domain.BookingStatusCancelled
-	booking.UpdatedAt = time.Now()
-
-	if err := u.bookingRepo.UpdateStatus(ctx, id, domain.BookingStatusCancelled); err != nil {
+	now := u.clock.Now()
+	err = u.deactivate(ctx, booking, domain.BookingStatusCancelled, now, domain.BookingStatusPending, domain.BookingStatusConfirmed)
+	if err != nil {
+		if errors.Is(err, ErrInvalidBookingStatus) {
+			log.Warn(ctx, "invalid booking status for cancellation",
+				zap.String("bookingID", id.String()),
+				zap.String("currentStatus", string(booking.Status)))
+			return err
+		}
 		log.Error(ctx, "failed to update booking status",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
+	booking.Status = domain.BookingStatusCancelled
+	booking.UpdatedAt = now
 
 	err = u.notificationSvc.NotifyRestaurant(
 		ctx,
@@ -314,88 +468,151 @@ func (u *bookingUseCase) CancelBooking(ctx context.Context, id string) error {
 	if err != nil {
 		log.Error(ctx, "failed to send notification to restaurant",
 			zap.String("restaurantID", booking.RestaurantID),
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 	}
 
 	log.Info(ctx, "booking successfully cancelled",
-		zap.String("bookingID", id),
+		zap.String("bookingID", id.String()),
 		zap.String("restaurantID", booking.RestaurantID),
 		zap.String("userID", booking.UserID))
 
 	return nil
 }
 
-func (u *bookingUseCase) CompleteBooking(ctx context.Context, id string) error {
+// deactivate moves the booking from one of the from statuses to the status and gives its seats
+// back to its slot in the same transaction, so that the guests waiting for the slot hear of them at
+// once. The status is checked under the lock of the booking: of a cancellation and a rejection
+// racing each other only one takes the booking and releases its seats, and the other one fails
+// with ErrInvalidBookingStatus.
+func (u *bookingUseCase) deactivate(
+	ctx context.Context,
+	booking *domain.Booking,
+	status domain.BookingStatus,
+	at time.Time,
+	from ...domain.BookingStatus,
+) error {
+	return u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		changed, err := u.bookingRepo.ChangeStatus(ctx, domain.BookingID(booking.ID), from, status, at)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			return ErrInvalidBookingStatus
+		}
+
+		// A slot already short of the seats is left to the reconciliation of reserved seats,
+		// which recounts it, rather than keeping the booking active.
+		err = releaseSeats(ctx, u.availabilityRepo, booking)
+		if errors.Is(err, domain.ErrReservedSeatsNegative) {
+			log, _ := logger.FromContext(ctx)
+			log.Error(ctx, "failed to release seats of booking",
+				zap.String("bookingID", booking.ID),
+				zap.Int("guestsCount", booking.GuestsCount),
+				zap.Error(err))
+			return nil
+		}
+		return err
+	})
+}
+
+// releaseSeats takes the guests of the booking off the reserved seats of its slot. A booking
+// seated at a table holds no seats of the slot, and its table is free as soon as it is no longer
+// active.
+func releaseSeats(ctx context.Context, availabilityRepo repository.AvailabilityRepository, booking *domain.Booking) error {
+	if booking.TableID != "" {
+		return nil
+	}
+
+	slots, err := availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(booking.RestaurantID), booking.Date)
+	if err != nil {
+		return err
+	}
+	slot := timeSlot(slots, booking.Time)
+	if slot == nil {
+		return nil
+	}
+	return availabilityRepo.UpdateReservedSeats(ctx, slot.ID, -booking.GuestsCount)
+}
+
+func (u *bookingUseCase) CompleteBooking(ctx context.Context, id domain.BookingID) error {
 	log, _ := logger.FromContext(ctx)
-	log.Info(ctx, "completing booking", zap.String("bookingID", id))
+	log.Info(ctx, "completing booking", zap.String("bookingID", id.String()))
 
 	booking, err := u.bookingRepo.GetByID(ctx, id)
 	if err != nil {
-		log.Error(ctx, "failed to get booking", zap.String("bookingID", id), zap.Error(err))
+		log.Error(ctx, "failed to get booking", zap.String("bookingID", id.String()), zap.Error(err))
 		return err
 	}
 
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
 	if booking.Status != domain.BookingStatusConfirmed {
 		log.Warn(ctx, "invalid booking status for completion",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.String("currentStatus", string(booking.Status)))
 		return ErrInvalidBookingStatus
 	}
 
-	now := time.Now()
+	now := u.clock.Now()
 	booking.Status = domain.BookingStatusCompleted
 	booking.UpdatedAt = now
 	booking.CompletedAt = &now
 
 	if err := u.bookingRepo.UpdateStatus(ctx, id, domain.BookingStatusCompleted); err != nil {
 		log.Error(ctx, "failed to update booking status",
-			zap.String("bookingID", id),
+			zap.String("bookingID", id.String()),
 			zap.Error(err))
 		return err
 	}
 
 	log.Info(ctx, "booking successfully completed",
-		zap.String("bookingID", id),
+		zap.String("bookingID", id.String()),
 		zap.String("restaurantID", booking.RestaurantID),
 		zap.String("userID", booking.UserID))
 
 	return nil
 }
 
-func (u *bookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID string, date time.Time, timeSlot string, message string) (string, error) {
+func (u *bookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID domain.BookingID, date time.Time, timeSlot string, message string) (string, error) {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "suggesting alternative booking time",
-		zap.String("bookingID", bookingID),
+		zap.String("bookingID", bookingID.String()),
 		zap.Time("alternativeDate", date),
 		zap.String("alternativeTime", timeSlot))
 
 	booking, err := u.bookingRepo.GetByID(ctx, bookingID)
 	if err != nil {
 		log.Error(ctx, "failed to get booking",
-			zap.String("bookingID", bookingID),
+			zap.String("bookingID", bookingID.String()),
 			zap.Error(err))
 		return "", err
 	}
 
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return "", tenant.ErrAccessDenied
+	}
+
 	if booking.Status != domain.BookingStatusPending {
 		log.Warn(ctx, "invalid booking status for suggesting alternative time",
-			zap.String("bookingID", bookingID),
+			zap.String("bookingID", bookingID.String()),
 			zap.String("currentStatus", string(booking.Status)))
 		return "", ErrInvalidBookingStatus
 	}
 
 	alternative := &domain.BookingAlternative{
-		BookingID: bookingID,
+		BookingID: bookingID.String(),
 		Date:      date,
 		Time:      timeSlot,
 		Message:   message,
-		CreatedAt: time.Now(),
+		CreatedAt: u.clock.Now(),
 	}
 
 	if err := u.bookingRepo.AddAlternative(ctx, alternative); err != nil {
 		log.Error(ctx, "failed to add booking alternative",
-			zap.String("bookingID", bookingID),
+			zap.String("bookingID", bookingID.String()),
 			zap.Error(err))
 		return "", err
 	}
@@ -406,18 +623,18 @@ func (u *bookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID s
 		domain.NotificationTypeAlternativeOffer,
 		"Alternative time offered",
 		"Restaurant offers alternative time for your booking: "+date.Format("02.01.2006")+" at "+timeSlot,
-		bookingID,
+		bookingID.String(),
 	)
 	if err != nil {
 		log.Error(ctx, "failed to send notification to user",
 			zap.String("userID", booking.UserID),
-			zap.String("bookingID", bookingID),
+			zap.String("bookingID", bookingID.String()),
 			zap.Error(err))
 	}
 
 	log.Info(ctx, "alternative booking time successfully suggested",
 		zap.String("alternativeID", alternative.ID),
-		zap.String("bookingID", bookingID),
+		zap.String("bookingID", bookingID.String()),
 		zap.String("userID", booking.UserID))
 
 	return alternative.ID, nil
@@ -435,7 +652,7 @@ func (u *bookingUseCase) AcceptAlternative(ctx context.Context, alternativeID st
 		return err
 	}
 
-	booking, err := u.bookingRepo.GetByID(ctx, alternative.BookingID)
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(alternative.BookingID))
 	if err != nil {
 		log.Error(ctx, "failed to get booking for alternative offer",
 			zap.String("bookingID", alternative.BookingID),
@@ -444,7 +661,19 @@ func (u *bookingUseCase) AcceptAlternative(ctx context.Context, alternativeID st
 		return err
 	}
 
-	if err := u.bookingRepo.AcceptAlternative(ctx, alternativeID); err != nil {
+	table, err := u.reseatingTable(ctx, booking, alternative)
+	if err != nil {
+		return err
+	}
+	var tableID string
+	if table != nil {
+		tableID = table.ID
+	}
+
+	if err := u.bookingRepo.AcceptAlternative(ctx, alternativeID, tableID); err != nil {
+		if err.Error() == common.ErrTableUnavailable {
+			return ErrTableUnavailable
+		}
 		log.Error(ctx, "failed to accept alternative offer",
 			zap.String("alternativeID", alternativeID),
 			zap.Error(err))
@@ -475,6 +704,23 @@ func (u *bookingUseCase) AcceptAlternative(ctx context.Context, alternativeID st
 	return nil
 }
 
+// reseatingTable returns the table to seat the party of the booking at on the date and time of the
+// alternative: its own table when that is still free then, or else the smallest free table it fits
+// at; nil for a restaurant without tables.
+func (u *bookingUseCase) reseatingTable(ctx context.Context, booking *domain.Booking, alternative *domain.BookingAlternative) (*domain.Table, error) {
+	moved := *booking
+	moved.Date = alternative.Date
+	moved.Time = alternative.Time
+
+	table, err := seatingTable(ctx, u.tableRepo, &moved)
+	if errors.Is(err, ErrTableUnavailable) && moved.TableID != "" {
+		moved.TableID = ""
+		moved.TableRequested = false
+		table, err = seatingTable(ctx, u.tableRepo, &moved)
+	}
+	return table, err
+}
+
 func (u *bookingUseCase) RejectAlternative(ctx context.Context, alternativeID string) error {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "rejecting alternative booking offer", zap.String("alternativeID", alternativeID))
@@ -487,7 +733,7 @@ func (u *bookingUseCase) RejectAlternative(ctx context.Context, alternativeID st
 		return err
 	}
 
-	booking, err := u.bookingRepo.GetByID(ctx, alternative.BookingID)
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(alternative.BookingID))
 	if err != nil {
 		log.Error(ctx, "failed to get booking for alternative offer",
 			zap.String("bookingID", alternative.BookingID),
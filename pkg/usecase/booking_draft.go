@@ -0,0 +1,412 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidBookingDraft = errors.New("invalid booking draft")
+
+	// ErrBookingDraftExpired is returned for a draft left alone for longer than its lifetime; its
+	// seats are no longer held and the booking has to be started over.
+	ErrBookingDraftExpired = errors.New("booking draft has expired")
+
+	// ErrBookingDraftStep is returned for a step the draft is not ready for, such as a pre-order
+	// before a slot is held, or for any step of a confirmed draft.
+	ErrBookingDraftStep = errors.New("booking draft is not at this step")
+)
+
+// draftExpiryBatchSize is how many expired drafts one run of ExpireDrafts deletes at most.
+const draftExpiryBatchSize = 100
+
+// BookingDraftUseCase books a table in steps: a draft for the party is created, a slot is
+// picked and its seats held for the draft, a pre-order is added and the draft is confirmed into
+// a booking. Every step is checked on its own and refreshes the lifetime of the draft.
+type BookingDraftUseCase interface {
+	// CreateDraft starts a draft for the party at the restaurant. A guest may only start drafts
+	// for themselves.
+	CreateDraft(ctx context.Context, draft *domain.BookingDraft) error
+
+	GetDraft(ctx context.Context, id string) (*domain.BookingDraft, error)
+
+	// HoldSlot holds the seats of the restaurant's slot on the date at the time for the party,
+	// giving back those of the slot held before. It fails with ErrNoAvailability when the slot has
+	// no room for the party.
+	HoldSlot(ctx context.Context, id string, date time.Time, timeSlot string) (*domain.BookingDraft, error)
+
+	// SetPreOrder replaces the items pre-ordered with the draft; an empty list clears the
+	// pre-order. It fails with ErrPreOrderClosed once the cutoff before the held slot has passed.
+	SetPreOrder(ctx context.Context, id string, items []domain.PreOrderItem) (*domain.BookingDraft, error)
+
+	// ConfirmDraft creates the booking of the draft in the held slot, with its pre-order, and
+	// returns it. The booking goes through the checks of any other booking, so it fails like
+	// BookingUseCase.CreateBooking does and with a *domain.PreOrderLimitError when a pre-ordered
+	// menu item sold out meanwhile; the draft keeps its hold then.
+	ConfirmDraft(ctx context.Context, id string) (*domain.Booking, error)
+
+	// DeleteDraft abandons the draft and gives back the seats it held.
+	DeleteDraft(ctx context.Context, id string) error
+
+	// ExpireDrafts deletes the drafts that expired by now, giving back the seats they held, and
+	// returns how many were deleted. A draft that fails to be deleted does not stop the others.
+	ExpireDrafts(ctx context.Context, now time.Time) (int, error)
+}
+
+type bookingDraftUseCase struct {
+	draftRepo        repository.BookingDraftRepository
+	availabilityRepo repository.AvailabilityRepository
+	restaurantRepo   repository.RestaurantRepository
+	menuRepo         repository.MenuRepository
+	bookings         BookingUseCase
+	transactor       repository.Transactor
+	ttl              time.Duration
+	preOrderCutoff   time.Duration
+	clock            clock.Clock
+}
+
+// NewBookingDraftUseCase creates the use case; bookings creates the booking of a confirmed draft,
+// ttl is how long a draft lives after its last step and preOrderCutoff is how long before the
+// held slot its pre-order stops being editable.
+func NewBookingDraftUseCase(
+	draftRepo repository.BookingDraftRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	restaurantRepo repository.RestaurantRepository,
+	menuRepo repository.MenuRepository,
+	bookings BookingUseCase,
+	transactor repository.Transactor,
+	ttl time.Duration,
+	preOrderCutoff time.Duration,
+	clock clock.Clock,
+) BookingDraftUseCase {
+	return &bookingDraftUseCase{
+		draftRepo:        draftRepo,
+		availabilityRepo: availabilityRepo,
+		restaurantRepo:   restaurantRepo,
+		menuRepo:         menuRepo,
+		bookings:         bookings,
+		transactor:       transactor,
+		ttl:              ttl,
+		preOrderCutoff:   preOrderCutoff,
+		clock:            clock,
+	}
+}
+
+func (u *bookingDraftUseCase) CreateDraft(ctx context.Context, draft *domain.BookingDraft) error {
+	log, _ := logger.FromContext(ctx)
+
+	principal, ok := tenant.FromContext(ctx)
+	if !ok {
+		return tenant.ErrAccessDenied
+	}
+	if draft.UserID == "" {
+		draft.UserID = principal.UserID
+	}
+	if !principal.CanAccessUser(draft.UserID) {
+		return tenant.ErrAccessDenied
+	}
+
+	switch {
+	case draft.UserID == "":
+		return fmt.Errorf("%w: user is required", ErrInvalidBookingDraft)
+	case draft.GuestsCount < 1:
+		return fmt.Errorf("%w: guests count must be at least 1", ErrInvalidBookingDraft)
+	case draft.Duration < 0:
+		return fmt.Errorf("%w: duration cannot be negative", ErrInvalidBookingDraft)
+	case draft.Occasion != "" && !slices.Contains(domain.BookingOccasions, draft.Occasion):
+		return fmt.Errorf("%w: %q", ErrInvalidOccasion, draft.Occasion)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(draft.RestaurantID)); err != nil {
+		return err
+	}
+
+	draft.Comment = strings.TrimSpace(draft.Comment)
+	draft.Status = domain.BookingDraftOpen
+	draft.Date = time.Time{}
+	draft.Time = ""
+	draft.AvailabilityID = ""
+	draft.PreOrder = make([]domain.PreOrderItem, 0)
+	draft.BookingID = ""
+	draft.ExpiresAt = u.clock.Now().Add(u.ttl)
+
+	if err := u.draftRepo.Create(ctx, draft); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "booking draft created",
+		zap.String("draftID", draft.ID),
+		zap.String("restaurantID", draft.RestaurantID),
+		zap.Int("guests", draft.GuestsCount))
+	return nil
+}
+
+func (u *bookingDraftUseCase) GetDraft(ctx context.Context, id string) (*domain.BookingDraft, error) {
+	draft, err := u.draftRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if draft.Expired(u.clock.Now()) {
+		return nil, ErrBookingDraftExpired
+	}
+	return draft, nil
+}
+
+func (u *bookingDraftUseCase) HoldSlot(ctx context.Context, id string, date time.Time, timeSlot string) (*domain.BookingDraft, error) {
+	log, _ := logger.FromContext(ctx)
+
+	draft, err := u.step(ctx, id, func(ctx context.Context, draft *domain.BookingDraft, now time.Time) error {
+		slotTime, err := time.Parse("15:04", timeSlot)
+		if err != nil {
+			return fmt.Errorf("%w: time must be HH:MM", ErrInvalidBookingDraft)
+		}
+		if date.IsZero() {
+			return fmt.Errorf("%w: date is required", ErrInvalidBookingDraft)
+		}
+		year, month, day := date.Date()
+		start := time.Date(year, month, day, slotTime.Hour(), slotTime.Minute(), 0, 0, date.Location())
+		if !start.After(now) {
+			return fmt.Errorf("%w: the slot has already started", ErrInvalidBookingDraft)
+		}
+
+		if err := u.releaseHold(ctx, draft); err != nil {
+			return err
+		}
+
+		slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(draft.RestaurantID), date)
+		if err != nil {
+			return err
+		}
+		var slot *domain.Availability
+		for _, candidate := range slots {
+			if candidate.TimeSlot == slotTime.Format("15:04") && candidate.AvailableSeats() >= draft.GuestsCount {
+				slot = candidate
+				break
+			}
+		}
+		if slot == nil {
+			return ErrNoAvailability
+		}
+
+		if err := u.availabilityRepo.UpdateReservedSeats(ctx, slot.ID, draft.GuestsCount); err != nil {
+			var seatsErr *domain.ReservedSeatsError
+			if errors.As(err, &seatsErr) {
+				return ErrNoAvailability
+			}
+			return err
+		}
+
+		draft.Status = domain.BookingDraftHeld
+		draft.Date = time.Date(year, month, day, 0, 0, 0, 0, date.Location())
+		draft.Time = slot.TimeSlot
+		draft.AvailabilityID = slot.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "booking draft slot held",
+		zap.String("draftID", draft.ID),
+		zap.String("availabilityID", draft.AvailabilityID),
+		zap.Int("guests", draft.GuestsCount))
+	return draft, nil
+}
+
+func (u *bookingDraftUseCase) SetPreOrder(ctx context.Context, id string, items []domain.PreOrderItem) (*domain.BookingDraft, error) {
+	return u.step(ctx, id, func(ctx context.Context, draft *domain.BookingDraft, now time.Time) error {
+		if draft.Status != domain.BookingDraftHeld {
+			return fmt.Errorf("%w: pick a slot before the pre-order", ErrBookingDraftStep)
+		}
+		if !u.preOrderOpen(draft, now) {
+			return ErrPreOrderClosed
+		}
+
+		menu, err := u.menuRepo.GetMenu(ctx, draft.RestaurantID)
+		if err != nil {
+			return err
+		}
+		ordered, err := priceMenuItems(menu, items)
+		if err != nil {
+			return err
+		}
+
+		draft.PreOrder = ordered
+		return nil
+	})
+}
+
+func (u *bookingDraftUseCase) ConfirmDraft(ctx context.Context, id string) (*domain.Booking, error) {
+	log, _ := logger.FromContext(ctx)
+
+	var booking *domain.Booking
+	draft, err := u.step(ctx, id, func(ctx context.Context, draft *domain.BookingDraft, now time.Time) error {
+		if draft.Status != domain.BookingDraftHeld {
+			return fmt.Errorf("%w: pick a slot before confirming", ErrBookingDraftStep)
+		}
+		if len(draft.PreOrder) > 0 && !u.preOrderOpen(draft, now) {
+			return ErrPreOrderClosed
+		}
+
+		// The held seats are given back first, so that the booking takes them like any other.
+		if err := u.releaseHold(ctx, draft); err != nil {
+			return err
+		}
+
+		booking = draft.Booking()
+		if _, err := u.bookings.CreateBooking(ctx, booking); err != nil {
+			return err
+		}
+		if len(draft.PreOrder) > 0 {
+			if err := u.menuRepo.SavePreOrder(ctx, booking.ID, draft.PreOrder); err != nil {
+				return err
+			}
+		}
+
+		draft.Status = domain.BookingDraftConfirmed
+		draft.BookingID = booking.ID
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, "failed to confirm booking draft",
+			zap.String("draftID", id),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "booking draft confirmed",
+		zap.String("draftID", draft.ID),
+		zap.String("bookingID", booking.ID))
+	return booking, nil
+}
+
+func (u *bookingDraftUseCase) DeleteDraft(ctx context.Context, id string) error {
+	return u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		draft, err := u.draftRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.releaseHold(ctx, draft); err != nil {
+			return err
+		}
+		return u.draftRepo.Delete(ctx, id)
+	})
+}
+
+func (u *bookingDraftUseCase) ExpireDrafts(ctx context.Context, now time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	drafts, err := u.draftRepo.ListExpired(ctx, now, draftExpiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		expired int
+		errs    []error
+	)
+	for _, draft := range drafts {
+		if ctx.Err() != nil {
+			break
+		}
+
+		var deleted bool
+		err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+			// The draft is read again under lock, as a step may have renewed it meanwhile.
+			draft, err := u.draftRepo.GetByID(ctx, draft.ID)
+			if err != nil {
+				return err
+			}
+			if !draft.Expired(now) {
+				return nil
+			}
+			if err := u.releaseHold(ctx, draft); err != nil {
+				return err
+			}
+			deleted = true
+			return u.draftRepo.Delete(ctx, draft.ID)
+		})
+		switch {
+		case err == nil && deleted:
+			expired++
+		case err != nil && err.Error() != common.ErrBookingDraftNotFound:
+			log.Error(ctx, common.ErrExpireBookingDrafts,
+				zap.String("draftID", draft.ID),
+				zap.Error(err))
+			errs = append(errs, err)
+		}
+	}
+
+	log.Info(ctx, "booking drafts expired",
+		zap.Int("due", len(drafts)),
+		zap.Int("expired", expired))
+	return expired, errors.Join(errs...)
+}
+
+// step runs fn on the draft in a transaction, with the draft locked, and stores the draft with a
+// renewed lifetime. Expired and confirmed drafts take no more steps.
+func (u *bookingDraftUseCase) step(ctx context.Context, id string, fn func(ctx context.Context, draft *domain.BookingDraft, now time.Time) error) (*domain.BookingDraft, error) {
+	var draft *domain.BookingDraft
+	err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		draft, err = u.draftRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		now := u.clock.Now()
+		if draft.Expired(now) {
+			return ErrBookingDraftExpired
+		}
+		if draft.Status == domain.BookingDraftConfirmed {
+			return fmt.Errorf("%w: the draft is already confirmed", ErrBookingDraftStep)
+		}
+
+		if err := fn(ctx, draft, now); err != nil {
+			return err
+		}
+
+		draft.ExpiresAt = now.Add(u.ttl)
+		return u.draftRepo.Update(ctx, draft)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return draft, nil
+}
+
+// releaseHold gives back the seats held by the draft, if any, and leaves it open.
+func (u *bookingDraftUseCase) releaseHold(ctx context.Context, draft *domain.BookingDraft) error {
+	if draft.Status != domain.BookingDraftHeld {
+		return nil
+	}
+	if draft.AvailabilityID != "" {
+		if err := u.availabilityRepo.UpdateReservedSeats(ctx, draft.AvailabilityID, -draft.GuestsCount); err != nil {
+			return err
+		}
+	}
+	draft.Status = domain.BookingDraftOpen
+	return nil
+}
+
+// preOrderOpen reports whether the pre-order of a draft holding a slot can still be changed.
+func (u *bookingDraftUseCase) preOrderOpen(draft *domain.BookingDraft, now time.Time) bool {
+	start, err := bookingStart(draft.Booking())
+	if err != nil {
+		return false
+	}
+	return now.Before(start.Add(-u.preOrderCutoff))
+}
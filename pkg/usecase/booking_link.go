@@ -0,0 +1,357 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidBookingLink = errors.New("invalid booking link")
+	ErrBookingLinkExpired = errors.New("booking link has expired")
+	ErrBookingLinkUsed    = errors.New("booking link has already been used")
+)
+
+const (
+	// BookingLinkPath is the path the tokens are served under, e.g. /b/{token}.
+	BookingLinkPath = "/b/"
+
+	// CheckInTokenPrefix starts every check-in token, telling it apart from a link token when scanned.
+	CheckInTokenPrefix = "ci."
+
+	bookingLinkIDBytes        = 9
+	bookingLinkSignatureBytes = 12
+
+	// confirmationViewLinkGrace keeps the details link of a confirmed booking working for
+	// a day after the visit, e.g. to look up the address or the receipt.
+	confirmationViewLinkGrace = 24 * time.Hour
+)
+
+type BookingLinkOptions struct {
+	Action domain.BookingLinkAction
+	// ExpiresAt defaults to the configured lifetime of a link from now.
+	ExpiresAt time.Time
+	SingleUse bool
+}
+
+type IssuedBookingLink struct {
+	Token     string                   `json:"token"`
+	URL       string                   `json:"url"`
+	Action    domain.BookingLinkAction `json:"action"`
+	SingleUse bool                     `json:"single_use"`
+	ExpiresAt time.Time                `json:"expires_at"`
+}
+
+type ResolvedBookingLink struct {
+	Action    domain.BookingLinkAction `json:"action"`
+	SingleUse bool                     `json:"single_use"`
+	ExpiresAt time.Time                `json:"expires_at"`
+	Booking   *domain.Booking          `json:"booking"`
+}
+
+// BookingLinkUseCase hands out short signed links that open a booking without logging in.
+type BookingLinkUseCase interface {
+	CreateBookingLink(ctx context.Context, bookingID domain.BookingID, options BookingLinkOptions) (*IssuedBookingLink, error)
+
+	// IssueConfirmationLinks creates the links sent with a booking confirmation: one to the details,
+	// valid until a day after the visit, and a single-use one to cancel, valid until the visit starts.
+	IssueConfirmationLinks(ctx context.Context, bookingID domain.BookingID) (view, cancel *IssuedBookingLink, err error)
+
+	// ResolveBookingLink returns the booking the link points to. A single-use link to the details
+	// is used up by resolving it; a link to cancel is only used up by UseBookingLink.
+	ResolveBookingLink(ctx context.Context, token string) (*ResolvedBookingLink, error)
+
+	// UseBookingLink runs fn on the booking of a link granting the action, in one transaction with
+	// using up a single-use link, and returns the booking as fn left it.
+	UseBookingLink(ctx context.Context, token string, action domain.BookingLinkAction, fn func(ctx context.Context, booking *domain.Booking) error) (*domain.Booking, error)
+
+	// CheckInToken returns the token the guest shows at the restaurant, e.g. as a QR code. It is
+	// derived from the booking and the secret, so it is the same every time it is asked for.
+	CheckInToken(ctx context.Context, bookingID domain.BookingID) (string, error)
+}
+
+type bookingLinkUseCase struct {
+	linkRepo    repository.BookingLinkRepository
+	bookingRepo repository.BookingRepository
+	transactor  repository.Transactor
+	secret      []byte
+	baseURL     string
+	ttl         time.Duration
+	clock       clock.Clock
+}
+
+// NewBookingLinkUseCase creates the use case; secret signs the tokens and baseURL is the public URL
+// the links are built on.
+func NewBookingLinkUseCase(
+	linkRepo repository.BookingLinkRepository,
+	bookingRepo repository.BookingRepository,
+	transactor repository.Transactor,
+	secret string,
+	baseURL string,
+	ttl time.Duration,
+	clock clock.Clock,
+) BookingLinkUseCase {
+	return &bookingLinkUseCase{
+		linkRepo:    linkRepo,
+		bookingRepo: bookingRepo,
+		transactor:  transactor,
+		secret:      []byte(secret),
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		ttl:         ttl,
+		clock:       clock,
+	}
+}
+
+func (u *bookingLinkUseCase) CreateBookingLink(ctx context.Context, bookingID domain.BookingID, options BookingLinkOptions) (*IssuedBookingLink, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if options.Action != domain.BookingLinkActionView && options.Action != domain.BookingLinkActionCancel {
+		return nil, ErrInvalidBookingLink
+	}
+
+	now := u.clock.Now()
+	if options.ExpiresAt.IsZero() {
+		options.ExpiresAt = now.Add(u.ttl)
+	}
+	if !options.ExpiresAt.After(now) {
+		return nil, ErrInvalidBookingLink
+	}
+
+	if _, err := u.bookingRepo.GetByID(ctx, bookingID); err != nil {
+		return nil, err
+	}
+
+	id, err := newBookingLinkID()
+	if err != nil {
+		return nil, err
+	}
+
+	link := &domain.BookingLink{
+		ID:        id,
+		BookingID: bookingID.String(),
+		Action:    options.Action,
+		SingleUse: options.SingleUse,
+		ExpiresAt: options.ExpiresAt,
+		CreatedAt: now,
+	}
+	if err := u.linkRepo.Create(ctx, link); err != nil {
+		log.Error(ctx, "failed to create booking link",
+			zap.String("bookingID", bookingID.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "booking link created",
+		zap.String("bookingID", bookingID.String()),
+		zap.String("action", string(link.Action)),
+		zap.Bool("singleUse", link.SingleUse),
+		zap.Time("expiresAt", link.ExpiresAt))
+
+	token := u.sign(link.ID)
+	return &IssuedBookingLink{
+		Token:     token,
+		URL:       u.baseURL + BookingLinkPath + token,
+		Action:    link.Action,
+		SingleUse: link.SingleUse,
+		ExpiresAt: link.ExpiresAt,
+	}, nil
+}
+
+func (u *bookingLinkUseCase) IssueConfirmationLinks(ctx context.Context, bookingID domain.BookingID) (*IssuedBookingLink, *IssuedBookingLink, error) {
+	booking, err := u.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var viewExpiresAt, cancelExpiresAt time.Time
+	if start, err := bookingStart(booking); err == nil && start.After(u.clock.Now()) {
+		viewExpiresAt = start.Add(confirmationViewLinkGrace)
+		cancelExpiresAt = start
+	}
+
+	view, err := u.CreateBookingLink(ctx, bookingID, BookingLinkOptions{
+		Action:    domain.BookingLinkActionView,
+		ExpiresAt: viewExpiresAt,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cancel, err := u.CreateBookingLink(ctx, bookingID, BookingLinkOptions{
+		Action:    domain.BookingLinkActionCancel,
+		ExpiresAt: cancelExpiresAt,
+		SingleUse: true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return view, cancel, nil
+}
+
+func (u *bookingLinkUseCase) ResolveBookingLink(ctx context.Context, token string) (*ResolvedBookingLink, error) {
+	link, err := u.usableLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(link.BookingID))
+	if err != nil {
+		return nil, err
+	}
+
+	if link.SingleUse && link.Action == domain.BookingLinkActionView {
+		if err := u.markUsed(ctx, link); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ResolvedBookingLink{
+		Action:    link.Action,
+		SingleUse: link.SingleUse,
+		ExpiresAt: link.ExpiresAt,
+		Booking:   booking,
+	}, nil
+}
+
+func (u *bookingLinkUseCase) UseBookingLink(ctx context.Context, token string, action domain.BookingLinkAction, fn func(ctx context.Context, booking *domain.Booking) error) (*domain.Booking, error) {
+	log, _ := logger.FromContext(ctx)
+
+	link, err := u.usableLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if link.Action != action {
+		return nil, ErrInvalidBookingLink
+	}
+
+	var booking *domain.Booking
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if link.SingleUse {
+			if err := u.markUsed(ctx, link); err != nil {
+				return err
+			}
+		}
+
+		current, err := u.bookingRepo.GetByID(ctx, domain.BookingID(link.BookingID))
+		if err != nil {
+			return err
+		}
+		if err := fn(ctx, current); err != nil {
+			return err
+		}
+
+		booking, err = u.bookingRepo.GetByID(ctx, domain.BookingID(link.BookingID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "booking link used",
+		zap.String("bookingID", link.BookingID),
+		zap.String("linkID", link.ID),
+		zap.String("action", string(action)))
+	return booking, nil
+}
+
+func (u *bookingLinkUseCase) CheckInToken(ctx context.Context, bookingID domain.BookingID) (string, error) {
+	if _, err := u.bookingRepo.GetByID(ctx, bookingID); err != nil {
+		return "", err
+	}
+
+	return CheckInTokenPrefix + bookingID.String() + "." + base64.RawURLEncoding.EncodeToString(u.signature(CheckInTokenPrefix+bookingID.String())), nil
+}
+
+// usableLink checks the signature of the token and returns its link when it is neither expired
+// nor used up.
+func (u *bookingLinkUseCase) usableLink(ctx context.Context, token string) (*domain.BookingLink, error) {
+	id, ok := u.verify(token)
+	if !ok {
+		return nil, ErrInvalidBookingLink
+	}
+
+	link, err := u.linkRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == common.ErrBookingLinkNotFound {
+			return nil, ErrInvalidBookingLink
+		}
+		return nil, err
+	}
+
+	if link.Expired(u.clock.Now()) {
+		return nil, ErrBookingLinkExpired
+	}
+	if link.Consumed() {
+		return nil, ErrBookingLinkUsed
+	}
+
+	return link, nil
+}
+
+func (u *bookingLinkUseCase) markUsed(ctx context.Context, link *domain.BookingLink) error {
+	if err := u.linkRepo.MarkUsed(ctx, link.ID, u.clock.Now()); err != nil {
+		if err.Error() == common.ErrBookingLinkAlreadyUsed {
+			return ErrBookingLinkUsed
+		}
+		return err
+	}
+	return nil
+}
+
+// sign appends a truncated HMAC of the link ID, so tokens cannot be guessed from IDs.
+func (u *bookingLinkUseCase) sign(id string) string {
+	return id + base64.RawURLEncoding.EncodeToString(u.signature(id))
+}
+
+func (u *bookingLinkUseCase) verify(token string) (string, bool) {
+	idLength := base64.RawURLEncoding.EncodedLen(bookingLinkIDBytes)
+	if len(token) != idLength+base64.RawURLEncoding.EncodedLen(bookingLinkSignatureBytes) {
+		return "", false
+	}
+
+	id := token[:idLength]
+	signature, err := base64.RawURLEncoding.DecodeString(token[idLength:])
+	if err != nil {
+		return "", false
+	}
+
+	return id, hmac.Equal(signature, u.signature(id))
+}
+
+func (u *bookingLinkUseCase) signature(id string) []byte {
+	mac := hmac.New(sha256.New, u.secret)
+	mac.Write([]byte(id))
+	return mac.Sum(nil)[:bookingLinkSignatureBytes]
+}
+
+func newBookingLinkID() (string, error) {
+	b := make([]byte, bookingLinkIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func bookingStart(booking *domain.Booking) (time.Time, error) {
+	slot, err := time.Parse("15:04", booking.Time)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	year, month, day := booking.Date.Date()
+	return time.Date(year, month, day, slot.Hour(), slot.Minute(), 0, 0, booking.Date.Location()), nil
+}
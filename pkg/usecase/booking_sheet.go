@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/pdf"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// sheetWidth is how many Courier characters fit on a line of an A4 page.
+	sheetWidth = 80
+
+	// sheetDetailIndent lines the details of a booking up under its guest.
+	sheetDetailIndent = 15
+)
+
+// BookingSheetUseCase prints the bookings of a day for restaurants without a tablet at the host
+// stand.
+type BookingSheetUseCase interface {
+	// RenderBookingSheet returns the PDF run-sheet of the restaurant's pending, confirmed and
+	// completed bookings on the date, ordered by time. Restaurant staff only.
+	RenderBookingSheet(ctx context.Context, restaurantID string, date time.Time) ([]byte, error)
+}
+
+type bookingSheetUseCase struct {
+	restaurantRepo repository.RestaurantRepository
+	bookingRepo    repository.BookingRepository
+	userRepo       repository.UserRepository
+	menuRepo       repository.MenuRepository
+}
+
+func NewBookingSheetUseCase(
+	restaurantRepo repository.RestaurantRepository,
+	bookingRepo repository.BookingRepository,
+	userRepo repository.UserRepository,
+	menuRepo repository.MenuRepository,
+) BookingSheetUseCase {
+	return &bookingSheetUseCase{
+		restaurantRepo: restaurantRepo,
+		bookingRepo:    bookingRepo,
+		userRepo:       userRepo,
+		menuRepo:       menuRepo,
+	}
+}
+
+// SheetBooking is a booking on a run-sheet with its guest and pre-order; Guest is nil when the
+// guest could not be read.
+type SheetBooking struct {
+	*domain.Booking
+	Guest    *domain.User
+	PreOrder []domain.PreOrderItem
+}
+
+func (u *bookingSheetUseCase) RenderBookingSheet(ctx context.Context, restaurantID string, date time.Time) ([]byte, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := u.bookingRepo.GetByRestaurantID(ctx, domain.RestaurantID(restaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	day := date.Format(time.DateOnly)
+	var sheet []SheetBooking
+	guests := make(map[string]*domain.User)
+	for _, booking := range bookings {
+		if booking.Date.Format(time.DateOnly) != day || !onSheet(booking.Status) {
+			continue
+		}
+
+		// The guest and the pre-order are extra detail; a booking is printed without them when they
+		// cannot be read.
+		guest, ok := guests[booking.UserID]
+		if !ok {
+			if guest, err = u.userRepo.GetByID(ctx, domain.UserID(booking.UserID)); err != nil {
+				log.Warn(ctx, "failed to get guest for booking sheet",
+					zap.String("bookingID", booking.ID),
+					zap.Error(err))
+			}
+			guests[booking.UserID] = guest
+		}
+		preOrder, err := u.menuRepo.GetPreOrder(ctx, booking.ID)
+		if err != nil {
+			log.Warn(ctx, "failed to get pre-order for booking sheet",
+				zap.String("bookingID", booking.ID),
+				zap.Error(err))
+		}
+
+		sheet = append(sheet, SheetBooking{Booking: booking, Guest: guest, PreOrder: preOrder})
+	}
+
+	slices.SortStableFunc(sheet, func(a, b SheetBooking) int {
+		if a.Time != b.Time {
+			return strings.Compare(a.Time, b.Time)
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return pdf.FromText(FormatBookingSheet(restaurant, date, sheet)), nil
+}
+
+func onSheet(status domain.BookingStatus) bool {
+	return status == domain.BookingStatusPending ||
+		status == domain.BookingStatusConfirmed ||
+		status == domain.BookingStatusCompleted
+}
+
+// FormatBookingSheet renders the text of the run-sheet of the bookings, which are in the order
+// they are printed in.
+func FormatBookingSheet(restaurant *domain.Restaurant, date time.Time, bookings []SheetBooking) string {
+	var b strings.Builder
+
+	total := 0
+	for _, booking := range bookings {
+		total += booking.GuestsCount
+	}
+
+	fmt.Fprintf(&b, "%s%s\n\n", pdf.HeadingPrefix, restaurant.Name)
+	fmt.Fprintf(&b, "Bookings of %s, %s: %d bookings, %d guests.\n\n", date.Weekday(), date.Format("02.01.2006"), len(bookings), total)
+	if len(bookings) == 0 {
+		b.WriteString("No bookings.\n")
+		return b.String()
+	}
+
+	row := func(timeSlot, guests, name, phone, status string) {
+		fmt.Fprintf(&b, "%-5s  %6s  %-24.24s  %-16.16s  %s\n", timeSlot, guests, name, phone, status)
+	}
+	row("Time", "Guests", "Guest", "Phone", "Status")
+	row("-----", "------", strings.Repeat("-", 24), strings.Repeat("-", 16), strings.Repeat("-", 9))
+
+	for _, booking := range bookings {
+		var name, phone string
+		if booking.Guest != nil {
+			name, phone = booking.Guest.Name, booking.Guest.Phone
+		}
+		row(booking.Time, fmt.Sprint(booking.GuestsCount), name, phone, string(booking.Status))
+
+		if booking.Occasion != "" {
+			writeSheetDetail(&b, "Occasion: "+string(booking.Occasion))
+		}
+		if comment := strings.TrimSpace(booking.Comment); comment != "" {
+			writeSheetDetail(&b, "Comment: "+comment)
+		}
+		if len(booking.PreOrder) > 0 {
+			items := make([]string, 0, len(booking.PreOrder))
+			for _, item := range booking.PreOrder {
+				entry := fmt.Sprintf("%d x %s", item.Quantity, item.Name)
+				if item.Notes != "" {
+					entry += " (" + item.Notes + ")"
+				}
+				items = append(items, entry)
+			}
+			writeSheetDetail(&b, "Pre-order: "+strings.Join(items, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// writeSheetDetail writes the text under the guest column, wrapped at word boundaries to the
+// width of the page.
+func writeSheetDetail(b *strings.Builder, text string) {
+	indent := strings.Repeat(" ", sheetDetailIndent)
+	width := sheetWidth - sheetDetailIndent
+
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			b.WriteString(indent + string(line) + "\n")
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, runes...)
+	}
+	if len(line) > 0 {
+		b.WriteString(indent + string(line) + "\n")
+	}
+}
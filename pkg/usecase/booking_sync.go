@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxBookingSyncItems is how many operations an app can submit at once.
+	MaxBookingSyncItems = 100
+
+	maxBookingSyncKeyLength = 100
+)
+
+var ErrInvalidBookingSync = errors.New("invalid booking sync")
+
+// BookingSyncItem is an operation an app queued while offline. Key identifies it among the
+// operations of the user; the app picks it and submits the operation under the same key until it
+// gets a result. A cancellation names the booking by BookingID or, for a booking made offline
+// too, by BookingKey, the key of the operation that made it.
+type BookingSyncItem struct {
+	Key        string
+	Operation  domain.BookingSyncOperation
+	Booking    *domain.Booking
+	BookingID  string
+	BookingKey string
+}
+
+// BookingSyncUseCase accepts the bookings and cancellations that guests made in the app while
+// their phone was offline.
+type BookingSyncUseCase interface {
+	// SyncBookings carries out the operations of the user in order and returns the result of each.
+	// An operation that conflicts with what happened in the meantime, such as a booking of a slot
+	// that filled up, does not stop the others; its result tells what happened. An operation
+	// submitted before gets the result it got then. The operations are the principal's own; a
+	// request without a user is refused.
+	SyncBookings(ctx context.Context, items []BookingSyncItem) ([]*domain.BookingSyncResult, error)
+}
+
+type bookingSyncUseCase struct {
+	syncRepo    repository.BookingSyncRepository
+	bookingRepo repository.BookingRepository
+	bookings    BookingUseCase
+	transactor  repository.Transactor
+	clock       clock.Clock
+}
+
+// NewBookingSyncUseCase makes and cancels bookings through bookings, so that they are checked and
+// reported as any other.
+func NewBookingSyncUseCase(
+	syncRepo repository.BookingSyncRepository,
+	bookingRepo repository.BookingRepository,
+	bookings BookingUseCase,
+	transactor repository.Transactor,
+	clock clock.Clock,
+) BookingSyncUseCase {
+	return &bookingSyncUseCase{
+		syncRepo:    syncRepo,
+		bookingRepo: bookingRepo,
+		bookings:    bookings,
+		transactor:  transactor,
+		clock:       clock,
+	}
+}
+
+func (u *bookingSyncUseCase) SyncBookings(ctx context.Context, items []BookingSyncItem) ([]*domain.BookingSyncResult, error) {
+	principal, ok := tenant.FromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return nil, tenant.ErrAccessDenied
+	}
+	userID := principal.UserID
+	if err := validateBookingSync(items); err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.BookingSyncResult, 0, len(items))
+	for _, item := range items {
+		results = append(results, u.syncItem(ctx, userID, item))
+	}
+	return results, nil
+}
+
+func validateBookingSync(items []BookingSyncItem) error {
+	if len(items) == 0 || len(items) > MaxBookingSyncItems {
+		return fmt.Errorf("%w: from 1 to %d operations are accepted at once", ErrInvalidBookingSync, MaxBookingSyncItems)
+	}
+
+	keys := make(map[string]bool, len(items))
+	for i, item := range items {
+		switch {
+		case item.Key == "" || len(item.Key) > maxBookingSyncKeyLength:
+			return fmt.Errorf("%w: operation %d needs a key of up to %d characters", ErrInvalidBookingSync, i, maxBookingSyncKeyLength)
+		case keys[item.Key]:
+			return fmt.Errorf("%w: key %q is repeated", ErrInvalidBookingSync, item.Key)
+		case !item.Operation.IsValid():
+			return fmt.Errorf("%w: operation %q of key %q", ErrInvalidBookingSync, item.Operation, item.Key)
+		case item.Operation == domain.BookingSyncCreate && item.Booking == nil:
+			return fmt.Errorf("%w: booking of key %q is missing", ErrInvalidBookingSync, item.Key)
+		case item.Operation == domain.BookingSyncCancel && (item.BookingID == "") == (item.BookingKey == ""):
+			return fmt.Errorf("%w: cancellation of key %q needs either a booking ID or a booking key", ErrInvalidBookingSync, item.Key)
+		}
+		keys[item.Key] = true
+	}
+	return nil
+}
+
+// syncItem carries out the operation and keeps its result in one transaction, unless a result is
+// kept under its key already. An operation that fails unexpectedly is rolled back and its result
+// is not kept, so that the app retries it.
+func (u *bookingSyncUseCase) syncItem(ctx context.Context, userID string, item BookingSyncItem) *domain.BookingSyncResult {
+	log, _ := logger.FromContext(ctx)
+
+	var result *domain.BookingSyncResult
+	err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		kept, err := u.syncRepo.GetByKey(ctx, userID, item.Key)
+		if err == nil {
+			kept.Replayed = true
+			result = kept
+			return nil
+		}
+		if err.Error() != common.ErrBookingSyncResultNotFound {
+			return err
+		}
+
+		result = &domain.BookingSyncResult{
+			UserID:    userID,
+			Key:       item.Key,
+			Operation: item.Operation,
+			CreatedAt: u.clock.Now(),
+		}
+		if item.Operation == domain.BookingSyncCreate {
+			err = u.create(ctx, userID, item.Booking, result)
+		} else {
+			err = u.cancel(ctx, userID, item, result)
+		}
+		if err != nil {
+			return err
+		}
+
+		return u.syncRepo.Save(ctx, result)
+	})
+	if err != nil {
+		log.Error(ctx, common.ErrSyncBookings,
+			zap.String("userID", userID),
+			zap.String("key", item.Key),
+			zap.Error(err))
+		return &domain.BookingSyncResult{
+			UserID:    userID,
+			Key:       item.Key,
+			Operation: item.Operation,
+			Status:    domain.BookingSyncFailed,
+			CreatedAt: u.clock.Now(),
+		}
+	}
+
+	return result
+}
+
+// create makes the booking and records the outcome in result. Errors other than the ones a guest
+// can cause are returned.
+func (u *bookingSyncUseCase) create(ctx context.Context, userID string, booking *domain.Booking, result *domain.BookingSyncResult) error {
+	booking.ID = ""
+	booking.UserID = userID
+
+	id, err := u.bookings.CreateBooking(ctx, booking)
+	var exceeded *QuotaExceededError
+	switch {
+	case err == nil:
+		result.BookingID = id
+		result.Status = domain.BookingSyncApplied
+	case errors.Is(err, ErrNoAvailability), errors.Is(err, ErrTableUnavailable), errors.Is(err, domain.ErrReservedSeatsOverflow),
+		err.Error() == common.ErrInsufficientCapacity:
+		result.Status = domain.BookingSyncSlotFilled
+	case err.Error() == common.ErrRestaurantNotFound, err.Error() == common.ErrUserNotFound:
+		result.Status = domain.BookingSyncNotFound
+		result.Error = err.Error()
+	case errors.Is(err, ErrInvalidOccasion), errors.Is(err, domain.ErrInvalidEntity), errors.Is(err, ErrAgeAttestationRequired),
+		errors.Is(err, ErrRestaurantClosed), errors.As(err, &exceeded):
+		result.Status = domain.BookingSyncRejected
+		result.Error = err.Error()
+	default:
+		return err
+	}
+	return nil
+}
+
+// cancel cancels the booking and records the outcome in result. Errors other than the ones a
+// guest can cause are returned.
+func (u *bookingSyncUseCase) cancel(ctx context.Context, userID string, item BookingSyncItem, result *domain.BookingSyncResult) error {
+	bookingID := item.BookingID
+	if item.BookingKey != "" {
+		created, err := u.syncRepo.GetByKey(ctx, userID, item.BookingKey)
+		switch {
+		case err != nil && err.Error() == common.ErrBookingSyncResultNotFound:
+			result.Status = domain.BookingSyncNotFound
+			return nil
+		case err != nil:
+			return err
+		case created.BookingID == "":
+			// The booking was never made, for example because its slot filled up.
+			result.Status = domain.BookingSyncNotFound
+			return nil
+		}
+		bookingID = created.BookingID
+	}
+
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(bookingID))
+	switch {
+	case err != nil && (strings.HasPrefix(err.Error(), common.ErrBookingNotFound) || errors.Is(err, tenant.ErrAccessDenied)),
+		err == nil && booking.UserID != userID:
+		result.Status = domain.BookingSyncNotFound
+		return nil
+	case err != nil:
+		return err
+	}
+	result.BookingID = booking.ID
+
+	switch booking.Status {
+	case domain.BookingStatusCancelled:
+		result.Status = domain.BookingSyncAlreadyCancelled
+		return nil
+	case domain.BookingStatusRejected, domain.BookingStatusCompleted:
+		result.Status = domain.BookingSyncNotCancellable
+		return nil
+	}
+
+	if err := u.bookings.CancelBooking(ctx, domain.BookingID(booking.ID)); err != nil {
+		if errors.Is(err, ErrInvalidBookingStatus) {
+			result.Status = domain.BookingSyncNotCancellable
+			return nil
+		}
+		return err
+	}
+	result.Status = domain.BookingSyncApplied
+	return nil
+}
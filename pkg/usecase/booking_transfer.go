@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidBookingTransfer = errors.New("invalid booking transfer")
+
+	// ErrNotSisterRestaurant is returned for a transfer to a restaurant outside the organization
+	// of the booking's restaurant.
+	ErrNotSisterRestaurant = errors.New("target restaurant is not in the same organization")
+
+	// ErrTransferNotAllowed is returned for a transfer of a booking that is no longer pending or
+	// confirmed.
+	ErrTransferNotAllowed = errors.New("booking cannot be transferred")
+
+	// ErrTransferDecided is returned when the guest already accepted or declined the transfer.
+	ErrTransferDecided = errors.New("booking transfer is already decided")
+)
+
+// BookingTransferUseCase moves bookings between sister restaurants of an organization with the
+// consent of their guests.
+type BookingTransferUseCase interface {
+	// RequestTransfer proposes to move the booking to the target restaurant on the date and time
+	// of the transfer, those of the booking when empty, and asks the guest to agree. The target
+	// must be in the organization of the booking's restaurant and have room for the party.
+	// Staff of the booking's restaurant only.
+	RequestTransfer(ctx context.Context, transfer *domain.BookingTransfer) error
+
+	// ListTransfers returns the transfers of the booking and the one it was created by.
+	ListTransfers(ctx context.Context, bookingID domain.BookingID) ([]*domain.BookingTransfer, error)
+
+	// AcceptTransfer books the party at the target restaurant, confirmed, at the smallest free
+	// table it fits at when the target has tables, cancels the original booking and returns the
+	// new one. Fails with ErrNoAvailability when the target filled up meanwhile. The booking's
+	// guest only.
+	AcceptTransfer(ctx context.Context, transferID string) (*domain.Booking, error)
+
+	// DeclineTransfer keeps the original booking. The booking's guest only.
+	DeclineTransfer(ctx context.Context, transferID string) error
+}
+
+type bookingTransferUseCase struct {
+	transferRepo     repository.BookingTransferRepository
+	organizationRepo repository.OrganizationRepository
+	bookingRepo      repository.BookingRepository
+	availabilityRepo repository.AvailabilityRepository
+	tableRepo        repository.TableRepository
+	restaurantRepo   repository.RestaurantRepository
+	notifier         domain.NotificationService
+	transactor       repository.Transactor
+	clock            clock.Clock
+}
+
+func NewBookingTransferUseCase(
+	transferRepo repository.BookingTransferRepository,
+	organizationRepo repository.OrganizationRepository,
+	bookingRepo repository.BookingRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	tableRepo repository.TableRepository,
+	restaurantRepo repository.RestaurantRepository,
+	notifier domain.NotificationService,
+	transactor repository.Transactor,
+	clock clock.Clock,
+) BookingTransferUseCase {
+	return &bookingTransferUseCase{
+		transferRepo:     transferRepo,
+		organizationRepo: organizationRepo,
+		bookingRepo:      bookingRepo,
+		availabilityRepo: availabilityRepo,
+		tableRepo:        tableRepo,
+		restaurantRepo:   restaurantRepo,
+		notifier:         notifier,
+		transactor:       transactor,
+		clock:            clock,
+	}
+}
+
+func (u *bookingTransferUseCase) RequestTransfer(ctx context.Context, transfer *domain.BookingTransfer) error {
+	log, _ := logger.FromContext(ctx)
+
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(transfer.BookingID))
+	if err != nil {
+		return err
+	}
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+	if !transferable(booking) {
+		return fmt.Errorf("%w: booking is %s", ErrTransferNotAllowed, booking.Status)
+	}
+
+	transfer.SourceRestaurantID = booking.RestaurantID
+	if transfer.Date.IsZero() {
+		transfer.Date = booking.Date
+	}
+	if transfer.Time == "" {
+		transfer.Time = booking.Time
+	}
+	if _, err := time.Parse("15:04", transfer.Time); err != nil {
+		return fmt.Errorf("%w: time must be HH:MM", ErrInvalidBookingTransfer)
+	}
+	transfer.Message = strings.TrimSpace(transfer.Message)
+
+	if err := u.checkSisters(ctx, transfer.SourceRestaurantID, transfer.TargetRestaurantID); err != nil {
+		return err
+	}
+	if _, _, err := u.seating(ctx, transfer, booking); err != nil {
+		return err
+	}
+
+	source, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(transfer.SourceRestaurantID))
+	if err != nil {
+		return err
+	}
+	target, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(transfer.TargetRestaurantID))
+	if err != nil {
+		return err
+	}
+
+	if err := u.transferRepo.Create(ctx, transfer); err != nil {
+		return err
+	}
+
+	title, message := FormatTransferRequest(booking, transfer, source, target)
+	err = u.notifier.NotifyUser(ctx, booking.UserID, domain.NotificationTypeBookingTransfer, title, message, transfer.ID)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to user",
+			zap.String("userID", booking.UserID),
+			zap.String("transferID", transfer.ID),
+			zap.Error(err))
+	}
+
+	log.Info(ctx, "booking transfer requested",
+		zap.String("bookingID", booking.ID),
+		zap.String("transferID", transfer.ID),
+		zap.String("targetRestaurantID", transfer.TargetRestaurantID))
+	return nil
+}
+
+func transferable(booking *domain.Booking) bool {
+	return booking.Status == domain.BookingStatusPending || booking.Status == domain.BookingStatusConfirmed
+}
+
+// checkSisters fails with ErrNotSisterRestaurant unless both restaurants are distinct members of
+// one organization.
+func (u *bookingTransferUseCase) checkSisters(ctx context.Context, sourceID, targetID string) error {
+	if targetID == "" || targetID == sourceID {
+		return fmt.Errorf("%w: target must be another restaurant", ErrNotSisterRestaurant)
+	}
+
+	source, err := u.organizationRepo.GetByRestaurant(ctx, sourceID)
+	if err != nil {
+		if err.Error() == common.ErrOrganizationNotFound {
+			return fmt.Errorf("%w: the restaurant belongs to no organization", ErrNotSisterRestaurant)
+		}
+		return err
+	}
+	target, err := u.organizationRepo.GetByRestaurant(ctx, targetID)
+	if err != nil {
+		if err.Error() == common.ErrOrganizationNotFound {
+			return ErrNotSisterRestaurant
+		}
+		return err
+	}
+	if source.ID != target.ID {
+		return ErrNotSisterRestaurant
+	}
+	return nil
+}
+
+// seating returns the slot of the target restaurant at the date and time of the transfer and, in
+// a restaurant with tables, the table to seat the party of the booking at, or ErrNoAvailability
+// when the target has no room for the party.
+func (u *bookingTransferUseCase) seating(ctx context.Context, transfer *domain.BookingTransfer, booking *domain.Booking) (*domain.Availability, *domain.Table, error) {
+	slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(transfer.TargetRestaurantID), transfer.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+	slot := timeSlot(slots, transfer.Time)
+	if slot == nil {
+		return nil, nil, ErrNoAvailability
+	}
+
+	table, err := seatingTable(ctx, u.tableRepo, &domain.Booking{
+		RestaurantID:    transfer.TargetRestaurantID,
+		Date:            transfer.Date,
+		Time:            transfer.Time,
+		Duration:        booking.Duration,
+		GuestsCount:     booking.GuestsCount,
+		SeatingZone:     booking.SeatingZone,
+		NeedsAccessible: booking.NeedsAccessible,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if table == nil && slot.AvailableSeats() < booking.GuestsCount {
+		return nil, nil, ErrNoAvailability
+	}
+	return slot, table, nil
+}
+
+func (u *bookingTransferUseCase) ListTransfers(ctx context.Context, bookingID domain.BookingID) ([]*domain.BookingTransfer, error) {
+	// GetByID checks that the booking is accessible to the caller.
+	if _, err := u.bookingRepo.GetByID(ctx, bookingID); err != nil {
+		return nil, err
+	}
+	return u.transferRepo.ListByBooking(ctx, bookingID.String())
+}
+
+func (u *bookingTransferUseCase) AcceptTransfer(ctx context.Context, transferID string) (*domain.Booking, error) {
+	log, _ := logger.FromContext(ctx)
+
+	transfer, booking, err := u.pendingTransfer(ctx, transferID)
+	if err != nil {
+		return nil, err
+	}
+	if !transferable(booking) {
+		return nil, fmt.Errorf("%w: booking is %s", ErrTransferNotAllowed, booking.Status)
+	}
+
+	now := u.clock.Now()
+	moved := &domain.Booking{
+		RestaurantID:    transfer.TargetRestaurantID,
+		UserID:          booking.UserID,
+		Date:            transfer.Date,
+		Time:            transfer.Time,
+		Duration:        booking.Duration,
+		GuestsCount:     booking.GuestsCount,
+		Status:          domain.BookingStatusConfirmed,
+		Comment:         booking.Comment,
+		Occasion:        booking.Occasion,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		ConfirmedAt:     &now,
+		IsTest:          booking.IsTest,
+		AgeAttested:     booking.AgeAttested,
+		SeatingZone:     booking.SeatingZone,
+		NeedsAccessible: booking.NeedsAccessible,
+	}
+
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		slot, table, err := u.seating(ctx, transfer, booking)
+		if err != nil {
+			return err
+		}
+		if table != nil {
+			moved.TableID = table.ID
+		}
+		if err := u.bookingRepo.Create(ctx, moved); err != nil {
+			if err.Error() == common.ErrTableUnavailable {
+				return ErrNoAvailability
+			}
+			return err
+		}
+		// A booking seated at a table holds the table rather than the seats of the slot.
+		if table == nil {
+			if err := u.availabilityRepo.UpdateReservedSeats(ctx, slot.ID, moved.GuestsCount); err != nil {
+				return err
+			}
+		}
+		if err := u.bookingRepo.UpdateStatus(ctx, domain.BookingID(booking.ID), domain.BookingStatusCancelled); err != nil {
+			return err
+		}
+
+		transfer.Status = domain.BookingTransferAccepted
+		transfer.NewBookingID = moved.ID
+		transfer.DecidedAt = &now
+		return u.transferRepo.Decide(ctx, transfer)
+	})
+	if err != nil {
+		log.Error(ctx, "failed to accept booking transfer",
+			zap.String("transferID", transferID),
+			zap.Error(err))
+		if err.Error() == common.ErrBookingTransferNotFound {
+			return nil, ErrTransferDecided
+		}
+		var seatsErr *domain.ReservedSeatsError
+		if errors.As(err, &seatsErr) {
+			return nil, ErrNoAvailability
+		}
+		return nil, err
+	}
+
+	// The original booking gives its seats back once the move is committed, so that a failure to
+	// release them does not undo the transfer; the reconciliation of reserved seats recounts the slot.
+	if err := releaseSeats(ctx, u.availabilityRepo, booking); err != nil {
+		log.Error(ctx, "failed to release seats of transferred booking",
+			zap.String("bookingID", booking.ID),
+			zap.Error(err))
+	}
+
+	message := "The guest agreed to move the booking on " + booking.Date.Format("02.01.2006") + " at " + booking.Time +
+		" to " + transfer.Date.Format("02.01.2006") + " at " + transfer.Time
+	u.notifyRestaurant(ctx, transfer.SourceRestaurantID, "Booking transfer accepted", message, booking.ID)
+	u.notifyRestaurant(ctx, transfer.TargetRestaurantID, "Transferred booking",
+		"A booking for "+fmt.Sprint(moved.GuestsCount)+" was transferred to you on "+transfer.Date.Format("02.01.2006")+" at "+transfer.Time,
+		moved.ID)
+
+	log.Info(ctx, "booking transfer accepted",
+		zap.String("transferID", transfer.ID),
+		zap.String("bookingID", booking.ID),
+		zap.String("newBookingID", moved.ID))
+	return moved, nil
+}
+
+func (u *bookingTransferUseCase) DeclineTransfer(ctx context.Context, transferID string) error {
+	transfer, booking, err := u.pendingTransfer(ctx, transferID)
+	if err != nil {
+		return err
+	}
+
+	now := u.clock.Now()
+	transfer.Status = domain.BookingTransferDeclined
+	transfer.DecidedAt = &now
+	if err := u.transferRepo.Decide(ctx, transfer); err != nil {
+		if err.Error() == common.ErrBookingTransferNotFound {
+			return ErrTransferDecided
+		}
+		return err
+	}
+
+	u.notifyRestaurant(ctx, transfer.SourceRestaurantID, "Booking transfer declined",
+		"The guest declined to move the booking on "+booking.Date.Format("02.01.2006")+" at "+booking.Time+"; it stays as it is",
+		booking.ID)
+	return nil
+}
+
+// pendingTransfer returns the transfer and its booking when the transfer is still pending and
+// the caller is the guest of the booking.
+func (u *bookingTransferUseCase) pendingTransfer(ctx context.Context, transferID string) (*domain.BookingTransfer, *domain.Booking, error) {
+	transfer, err := u.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// GetByID checks that the booking is accessible to the caller; only its guest may decide,
+	// not the staff of the restaurant.
+	booking, err := u.bookingRepo.GetByID(ctx, domain.BookingID(transfer.BookingID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if principal, ok := tenant.FromContext(ctx); !ok || principal.UserID != booking.UserID {
+		return nil, nil, tenant.ErrAccessDenied
+	}
+
+	if transfer.Status != domain.BookingTransferPending {
+		return nil, nil, ErrTransferDecided
+	}
+	return transfer, booking, nil
+}
+
+func (u *bookingTransferUseCase) notifyRestaurant(ctx context.Context, restaurantID, title, message, relatedID string) {
+	log, _ := logger.FromContext(ctx)
+
+	err := u.notifier.NotifyRestaurant(ctx, restaurantID, domain.NotificationTypeBookingTransfer, title, message, relatedID)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to restaurant",
+			zap.String("restaurantID", restaurantID),
+			zap.String("relatedID", relatedID),
+			zap.Error(err))
+	}
+}
+
+// FormatTransferRequest renders the title and message asking the guest of the booking to agree
+// to the transfer.
+func FormatTransferRequest(booking *domain.Booking, transfer *domain.BookingTransfer, source, target *domain.Restaurant) (string, string) {
+	var message strings.Builder
+	fmt.Fprintf(&message, "%s asks to move your booking for %d on %s at %s to %s on %s at %s",
+		source.Name, booking.GuestsCount, booking.Date.Format("02.01.2006"), booking.Time,
+		target.Name, transfer.Date.Format("02.01.2006"), transfer.Time)
+	if transfer.Message != "" {
+		fmt.Fprintf(&message, ": %s", transfer.Message)
+	}
+	message.WriteString(". Accept or decline the transfer; until then your booking stays as it is.")
+
+	return "Move your booking to " + target.Name + "?", message.String()
+}
@@ -0,0 +1,487 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// rebookingDays is how many days after the cancelled one are searched for other slots at the
+	// restaurant.
+	rebookingDays = 3
+
+	// maxRebookingAlternatives is how many slots at the restaurant a guest is offered at most.
+	maxRebookingAlternatives = 3
+
+	// maxPartnerSuggestions is how many other restaurants of the city a guest is pointed to at
+	// most, and partnerSlotWindow how far from the cancelled time their slot may be.
+	maxPartnerSuggestions = 2
+	partnerSlotWindow     = time.Hour
+
+	// partnerRestaurantsScanned bounds the restaurants of the city looked at for suggestions.
+	partnerRestaurantsScanned = 50
+)
+
+var ErrInvalidBulkCancellation = errors.New("invalid bulk cancellation")
+
+// BulkCancellation cancels the bookings of a restaurant on Date from FromTime to ToTime, both
+// included; empty times cover the whole day.
+type BulkCancellation struct {
+	RestaurantID string
+	Date         time.Time
+	FromTime     string
+	ToTime       string
+	Reason       string
+	// DryRun reports what would be cancelled and offered in a rolled back transaction, without
+	// notifying the guests.
+	DryRun bool
+}
+
+// PartnerSuggestion is a slot at another restaurant of the same city with room for the party of
+// a cancelled booking.
+type PartnerSuggestion struct {
+	RestaurantID   string
+	RestaurantName string
+	Date           time.Time
+	TimeSlot       string
+}
+
+// CancelledBooking is a booking cancelled in bulk with what its guest was offered instead:
+// Alternatives at the restaurant, which the guest accepts like any alternative offer, and slots
+// at partner restaurants.
+type CancelledBooking struct {
+	Booking      *domain.Booking
+	Alternatives []*domain.BookingAlternative
+	Partners     []PartnerSuggestion
+}
+
+// BulkCancellationReport sums up a bulk cancellation.
+type BulkCancellationReport struct {
+	Cancelled   []CancelledBooking
+	ClosedSlots int
+	DryRun      bool
+}
+
+// BulkCancellationUseCase cancels the bookings of a restaurant that must close for an evening and
+// helps their guests rebook.
+type BulkCancellationUseCase interface {
+	// CancelBookings closes the slots of the restaurant in the window, so they cannot be booked
+	// again, and cancels its pending and confirmed bookings there. Every guest is notified with
+	// the nearest slots with room for the party at the restaurant, offered as alternatives of the
+	// booking, and at other restaurants of the same city. A dry run rolls all of it back and
+	// notifies no one. Restaurant staff only.
+	CancelBookings(ctx context.Context, cancellation BulkCancellation) (*BulkCancellationReport, error)
+}
+
+type bulkCancellationUseCase struct {
+	bookingRepo      repository.BookingRepository
+	availabilityRepo repository.AvailabilityRepository
+	restaurantRepo   repository.RestaurantRepository
+	locationRepo     repository.RestaurantLocationRepository
+	notifier         domain.NotificationService
+	transactor       repository.Transactor
+	clock            clock.Clock
+}
+
+func NewBulkCancellationUseCase(
+	bookingRepo repository.BookingRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	restaurantRepo repository.RestaurantRepository,
+	locationRepo repository.RestaurantLocationRepository,
+	notifier domain.NotificationService,
+	transactor repository.Transactor,
+	clock clock.Clock,
+) BulkCancellationUseCase {
+	return &bulkCancellationUseCase{
+		bookingRepo:      bookingRepo,
+		availabilityRepo: availabilityRepo,
+		restaurantRepo:   restaurantRepo,
+		locationRepo:     locationRepo,
+		notifier:         notifier,
+		transactor:       transactor,
+		clock:            clock,
+	}
+}
+
+func (u *bulkCancellationUseCase) CancelBookings(ctx context.Context, cancellation BulkCancellation) (*BulkCancellationReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(cancellation.RestaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	if err := normalizeBulkCancellation(&cancellation); err != nil {
+		return nil, err
+	}
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(cancellation.RestaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	// A bulk cancellation keeps what it managed to cancel, so only the dry run is transactional.
+	run := func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}
+	if cancellation.DryRun {
+		run = u.transactor.DryRun
+	}
+
+	report := &BulkCancellationReport{DryRun: cancellation.DryRun}
+	var errs []error
+	err = run(ctx, func(ctx context.Context) error {
+		var err error
+		if report.ClosedSlots, err = u.closeSlots(ctx, cancellation); err != nil {
+			return err
+		}
+
+		bookings, err := u.bookingRepo.GetByRestaurantID(ctx, domain.RestaurantID(cancellation.RestaurantID))
+		if err != nil {
+			return err
+		}
+
+		finder := &rebookingFinder{
+			availabilityRepo: u.availabilityRepo,
+			slots:            make(map[string][]*domain.Availability),
+		}
+		for _, booking := range bookings {
+			if !cancellation.covers(booking) {
+				continue
+			}
+
+			if err := u.bookingRepo.UpdateStatus(ctx, domain.BookingID(booking.ID), domain.BookingStatusCancelled); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			booking.Status = domain.BookingStatusCancelled
+
+			cancelled := CancelledBooking{Booking: booking}
+			cancelled.Alternatives = u.offerAlternatives(ctx, finder, cancellation, booking)
+			cancelled.Partners = u.suggestPartners(ctx, finder, booking)
+			report.Cancelled = append(report.Cancelled, cancelled)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !cancellation.DryRun {
+		for _, cancelled := range report.Cancelled {
+			title, message := FormatBulkCancellation(restaurant, cancellation.Reason, cancelled)
+			err := u.notifier.NotifyUser(ctx, cancelled.Booking.UserID, domain.NotificationTypeBookingCancelled, title, message, cancelled.Booking.ID)
+			if err != nil {
+				log.Error(ctx, "failed to send notification to user",
+					zap.String("userID", cancelled.Booking.UserID),
+					zap.String("bookingID", cancelled.Booking.ID),
+					zap.Error(err))
+			}
+		}
+	}
+
+	log.Info(ctx, "bookings cancelled in bulk",
+		zap.String("restaurantID", cancellation.RestaurantID),
+		zap.Time("date", cancellation.Date),
+		zap.String("from", cancellation.FromTime),
+		zap.String("to", cancellation.ToTime),
+		zap.Int("cancelled", len(report.Cancelled)),
+		zap.Int("closedSlots", report.ClosedSlots),
+		zap.Bool("dryRun", cancellation.DryRun))
+	return report, errors.Join(errs...)
+}
+
+func normalizeBulkCancellation(cancellation *BulkCancellation) error {
+	if cancellation.Date.IsZero() {
+		return fmt.Errorf("%w: date is required", ErrInvalidBulkCancellation)
+	}
+	if cancellation.FromTime == "" {
+		cancellation.FromTime = "00:00"
+	}
+	if cancellation.ToTime == "" {
+		cancellation.ToTime = "23:59"
+	}
+
+	from, err := time.Parse("15:04", cancellation.FromTime)
+	if err != nil {
+		return fmt.Errorf("%w: from_time must be HH:MM", ErrInvalidBulkCancellation)
+	}
+	to, err := time.Parse("15:04", cancellation.ToTime)
+	if err != nil {
+		return fmt.Errorf("%w: to_time must be HH:MM", ErrInvalidBulkCancellation)
+	}
+	if to.Before(from) {
+		return fmt.Errorf("%w: to_time is before from_time", ErrInvalidBulkCancellation)
+	}
+	cancellation.FromTime = from.Format("15:04")
+	cancellation.ToTime = to.Format("15:04")
+	cancellation.Reason = strings.TrimSpace(cancellation.Reason)
+	return nil
+}
+
+// covers reports whether the booking is an active one in the window of the cancellation.
+func (c *BulkCancellation) covers(booking *domain.Booking) bool {
+	if booking.Status != domain.BookingStatusPending && booking.Status != domain.BookingStatusConfirmed {
+		return false
+	}
+	return booking.Date.Format(time.DateOnly) == c.Date.Format(time.DateOnly) && c.coversTime(booking.Time)
+}
+
+func (c *BulkCancellation) coversTime(timeSlot string) bool {
+	return timeSlot >= c.FromTime && timeSlot <= c.ToTime
+}
+
+// closeSlots takes the capacity of the slots in the window away, keeping their reserved seats,
+// and returns how many were closed.
+func (u *bulkCancellationUseCase) closeSlots(ctx context.Context, cancellation BulkCancellation) (int, error) {
+	slots, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(cancellation.RestaurantID), cancellation.Date)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, slot := range slots {
+		if !cancellation.coversTime(slot.TimeSlot) || slot.Capacity == 0 {
+			continue
+		}
+		slot.Capacity = 0
+		if err := u.availabilityRepo.SetAvailability(ctx, slot, true); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// offerAlternatives adds the nearest slots at the restaurant with room for the party as
+// alternatives of the booking. A failure only costs the guest the offer; it is logged.
+func (u *bulkCancellationUseCase) offerAlternatives(
+	ctx context.Context,
+	finder *rebookingFinder,
+	cancellation BulkCancellation,
+	booking *domain.Booking,
+) []*domain.BookingAlternative {
+	log, _ := logger.FromContext(ctx)
+
+	slots, err := finder.nearestSlots(ctx, booking, cancellation)
+	if err != nil {
+		log.Warn(ctx, "failed to find alternatives for cancelled booking",
+			zap.String("bookingID", booking.ID),
+			zap.Error(err))
+		return nil
+	}
+
+	alternatives := make([]*domain.BookingAlternative, 0, len(slots))
+	for _, slot := range slots {
+		alternative := &domain.BookingAlternative{
+			BookingID: booking.ID,
+			Date:      slot.Date,
+			Time:      slot.TimeSlot,
+			Message:   "Offered instead of a booking the restaurant cancelled",
+			CreatedAt: u.clock.Now(),
+		}
+		if err := u.bookingRepo.AddAlternative(ctx, alternative); err != nil {
+			log.Warn(ctx, "failed to offer alternative for cancelled booking",
+				zap.String("bookingID", booking.ID),
+				zap.Error(err))
+			continue
+		}
+		alternatives = append(alternatives, alternative)
+	}
+	return alternatives
+}
+
+// suggestPartners returns slots with room for the party near the time of the booking at other
+// restaurants of its city; a restaurant whose city is not known has none.
+func (u *bulkCancellationUseCase) suggestPartners(ctx context.Context, finder *rebookingFinder, booking *domain.Booking) []PartnerSuggestion {
+	log, _ := logger.FromContext(ctx)
+
+	if finder.partners == nil {
+		finder.partners = []*domain.Restaurant{}
+		location, err := u.locationRepo.GetByRestaurant(ctx, booking.RestaurantID)
+		if err != nil || location.CityID == "" {
+			return nil
+		}
+		restaurants, err := u.restaurantRepo.ListByCity(ctx, location.CityID, 0, partnerRestaurantsScanned)
+		if err != nil {
+			log.Warn(ctx, "failed to list partner restaurants",
+				zap.String("restaurantID", booking.RestaurantID),
+				zap.Error(err))
+			return nil
+		}
+		for _, restaurant := range restaurants {
+			if restaurant.ID != booking.RestaurantID && !restaurant.IsTest {
+				finder.partners = append(finder.partners, restaurant)
+			}
+		}
+	}
+
+	var suggestions []PartnerSuggestion
+	for _, partner := range finder.partners {
+		if len(suggestions) == maxPartnerSuggestions {
+			break
+		}
+		slot, err := finder.closestSlot(ctx, partner.ID, booking)
+		if err != nil || slot == nil {
+			continue
+		}
+		suggestions = append(suggestions, PartnerSuggestion{
+			RestaurantID:   partner.ID,
+			RestaurantName: partner.Name,
+			Date:           slot.Date,
+			TimeSlot:       slot.TimeSlot,
+		})
+	}
+	return suggestions
+}
+
+// rebookingFinder looks for free slots, reading the slots of a restaurant and day once.
+type rebookingFinder struct {
+	availabilityRepo repository.AvailabilityRepository
+	slots            map[string][]*domain.Availability
+	// partners are the other restaurants of the city, nil until they are listed.
+	partners []*domain.Restaurant
+}
+
+func (f *rebookingFinder) daySlots(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+	key := restaurantID + "/" + date.Format(time.DateOnly)
+	if slots, ok := f.slots[key]; ok {
+		return slots, nil
+	}
+
+	slots, err := f.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurantID), date)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		// The date of a slot is the day it was asked for, whatever the repository returns.
+		slot.Date = date
+	}
+	f.slots[key] = slots
+	return slots, nil
+}
+
+// nearestSlots returns the slots of the restaurant with room for the party on the day of the
+// booking outside the cancelled window and on the following days, nearest to the booking first.
+func (f *rebookingFinder) nearestSlots(ctx context.Context, booking *domain.Booking, cancellation BulkCancellation) ([]*domain.Availability, error) {
+	type candidate struct {
+		slot     *domain.Availability
+		distance time.Duration
+	}
+
+	var candidates []candidate
+	for day := 0; day <= rebookingDays; day++ {
+		date := cancellation.Date.AddDate(0, 0, day)
+		slots, err := f.daySlots(ctx, booking.RestaurantID, date)
+		if err != nil {
+			return nil, err
+		}
+		for _, slot := range slots {
+			if slot.AvailableSeats() < booking.GuestsCount || (day == 0 && cancellation.coversTime(slot.TimeSlot)) {
+				continue
+			}
+			distance, ok := slotDistance(booking.Time, slot.TimeSlot)
+			if !ok {
+				continue
+			}
+			candidates = append(candidates, candidate{slot: slot, distance: time.Duration(day)*24*time.Hour + distance})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return int(a.distance - b.distance)
+	})
+
+	slots := make([]*domain.Availability, 0, maxRebookingAlternatives)
+	for _, c := range candidates {
+		if len(slots) == maxRebookingAlternatives {
+			break
+		}
+		slots = append(slots, c.slot)
+	}
+	return slots, nil
+}
+
+// closestSlot returns the slot of the restaurant on the day of the booking with room for the
+// party closest to its time within partnerSlotWindow, or nil.
+func (f *rebookingFinder) closestSlot(ctx context.Context, restaurantID string, booking *domain.Booking) (*domain.Availability, error) {
+	slots, err := f.daySlots(ctx, restaurantID, booking.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		closest  *domain.Availability
+		shortest time.Duration
+	)
+	for _, slot := range slots {
+		distance, ok := slotDistance(booking.Time, slot.TimeSlot)
+		if !ok || distance > partnerSlotWindow || slot.AvailableSeats() < booking.GuestsCount {
+			continue
+		}
+		if closest == nil || distance < shortest {
+			closest, shortest = slot, distance
+		}
+	}
+	return closest, nil
+}
+
+// slotDistance returns how far apart two "15:04" times of a day are.
+func slotDistance(a, b string) (time.Duration, bool) {
+	first, err := time.Parse("15:04", a)
+	if err != nil {
+		return 0, false
+	}
+	second, err := time.Parse("15:04", b)
+	if err != nil {
+		return 0, false
+	}
+	if second.Before(first) {
+		return first.Sub(second), true
+	}
+	return second.Sub(first), true
+}
+
+// FormatBulkCancellation renders the title and message telling the guest of a booking cancelled
+// in bulk about the cancellation and the offers to rebook.
+func FormatBulkCancellation(restaurant *domain.Restaurant, reason string, cancelled CancelledBooking) (string, string) {
+	booking := cancelled.Booking
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "%s had to cancel your booking on %s at %s",
+		restaurant.Name, booking.Date.Format("02.01.2006"), booking.Time)
+	if reason != "" {
+		fmt.Fprintf(&message, ": %s", reason)
+	}
+	message.WriteString(".")
+
+	if len(cancelled.Alternatives) > 0 {
+		times := make([]string, 0, len(cancelled.Alternatives))
+		for _, alternative := range cancelled.Alternatives {
+			times = append(times, alternative.Date.Format("02.01.2006")+" at "+alternative.Time)
+		}
+		fmt.Fprintf(&message, " You can rebook there on %s by accepting one of the alternatives offered.",
+			strings.Join(times, " or "))
+	}
+
+	if len(cancelled.Partners) > 0 {
+		places := make([]string, 0, len(cancelled.Partners))
+		for _, partner := range cancelled.Partners {
+			places = append(places, partner.RestaurantName+" at "+partner.TimeSlot)
+		}
+		fmt.Fprintf(&message, " Tables for %d are also free nearby the same day: %s.",
+			booking.GuestsCount, strings.Join(places, ", "))
+	}
+
+	return "Your booking at " + restaurant.Name + " was cancelled", message.String()
+}
@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultCacheWarmDays           = 7
+	DefaultCacheTTL                = 3 * time.Minute
+	DefaultCacheWarmMaxRestaurants = 500
+)
+
+// ReadCache keeps serialized read results for a while; cache.Redis and cache.Memory implement it.
+type ReadCache interface {
+	// Get reports whether a value is stored under the key; a missing key is not an error.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheWarmerSettings tell what to keep in the read cache: the restaurants of Cities, at most
+// MaxRestaurants of each, and their availability for Days days from today, each stored for TTL.
+// Values that are not positive are replaced with the defaults.
+type CacheWarmerSettings struct {
+	Cities         []string
+	Days           int
+	TTL            time.Duration
+	MaxRestaurants int
+}
+
+// CacheWarmReport counts what a run stored. Failed lists the cities that could not be read.
+type CacheWarmReport struct {
+	Cities      int
+	Restaurants int
+	Slots       int
+	Failed      []string
+}
+
+// CacheWarmerUseCase fills the read cache before the requests for it arrive, so that the popular
+// cities of a region are served from the cache of that region.
+type CacheWarmerUseCase interface {
+	WarmCache(ctx context.Context, now time.Time) (*CacheWarmReport, error)
+}
+
+// cachedCity is what is stored for a city. Complete tells whether every restaurant of the city is
+// in the list, or only the first MaxRestaurants.
+type cachedCity struct {
+	Restaurants []*domain.Restaurant `json:"restaurants"`
+	Complete    bool                 `json:"complete"`
+}
+
+type cacheWarmerUseCase struct {
+	restaurantRepo   repository.RestaurantRepository
+	availabilityRepo repository.AvailabilityRepository
+	cache            ReadCache
+	settings         CacheWarmerSettings
+}
+
+func NewCacheWarmerUseCase(
+	restaurantRepo repository.RestaurantRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	cache ReadCache,
+	settings CacheWarmerSettings,
+) CacheWarmerUseCase {
+	if settings.Days <= 0 {
+		settings.Days = DefaultCacheWarmDays
+	}
+	if settings.TTL <= 0 {
+		settings.TTL = DefaultCacheTTL
+	}
+	if settings.MaxRestaurants <= 0 {
+		settings.MaxRestaurants = DefaultCacheWarmMaxRestaurants
+	}
+
+	return &cacheWarmerUseCase{
+		restaurantRepo:   restaurantRepo,
+		availabilityRepo: availabilityRepo,
+		cache:            cache,
+		settings:         settings,
+	}
+}
+
+func (u *cacheWarmerUseCase) WarmCache(ctx context.Context, now time.Time) (*CacheWarmReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	report := &CacheWarmReport{Failed: make([]string, 0)}
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	for _, cityID := range u.settings.Cities {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		restaurants, slots, err := u.warmCity(ctx, cityID, today)
+		report.Restaurants += restaurants
+		report.Slots += slots
+		if err != nil {
+			var cacheErr *cacheWriteError
+			if errors.As(err, &cacheErr) {
+				// The cache is down; the other cities would fail the same way.
+				return report, cacheErr.err
+			}
+			log.Error(ctx, "failed to warm the cache of a city",
+				zap.String("cityID", cityID),
+				zap.Error(err))
+			report.Failed = append(report.Failed, cityID)
+			continue
+		}
+		report.Cities++
+	}
+
+	return report, nil
+}
+
+// cacheWriteError tells a failed write to the cache from a failed read of the database.
+type cacheWriteError struct {
+	err error
+}
+
+func (e *cacheWriteError) Error() string {
+	return e.err.Error()
+}
+
+// warmCity stores the restaurants of the city and then their slots; the list goes first, as it is
+// what the slots are found through.
+func (u *cacheWarmerUseCase) warmCity(ctx context.Context, cityID string, today time.Time) (int, int, error) {
+	// One restaurant more than kept tells whether the list is complete.
+	restaurants, err := u.restaurantRepo.ListAfter(ctx, domain.RestaurantFilter{CityID: cityID}, domain.RestaurantCursor{}, u.settings.MaxRestaurants+1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	city := cachedCity{Restaurants: restaurants, Complete: len(restaurants) <= u.settings.MaxRestaurants}
+	if !city.Complete {
+		city.Restaurants = restaurants[:u.settings.MaxRestaurants]
+	}
+	if err := u.store(ctx, cityCacheKey(cityID), city); err != nil {
+		return 0, 0, err
+	}
+
+	slots := 0
+	for _, restaurant := range city.Restaurants {
+		for day := range u.settings.Days {
+			date := today.AddDate(0, 0, day)
+			availability, err := u.availabilityRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), date)
+			if err != nil {
+				return len(city.Restaurants), slots, err
+			}
+			if err := u.store(ctx, availabilityCacheKey(restaurant.ID, date), availability); err != nil {
+				return len(city.Restaurants), slots, err
+			}
+			slots += len(availability)
+		}
+	}
+
+	return len(city.Restaurants), slots, nil
+}
+
+func (u *cacheWarmerUseCase) store(ctx context.Context, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := u.cache.Set(ctx, key, data, u.settings.TTL); err != nil {
+		return &cacheWriteError{err: err}
+	}
+	return nil
+}
+
+func cityCacheKey(cityID string) string {
+	return "restaurants:city:" + cityID
+}
+
+func availabilityCacheKey(restaurantID string, date time.Time) string {
+	return "availability:" + restaurantID + ":" + date.Format(time.DateOnly)
+}
+
+// loadCached decodes the value stored under the key into value. A missing key, a failing cache
+// and a value that no longer decodes all report false: the caller then reads the database.
+func loadCached(ctx context.Context, cache ReadCache, key string, value any) bool {
+	data, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		log, _ := logger.FromContext(ctx)
+		log.Warn(ctx, "failed to read the cache", zap.String("key", key), zap.Error(err))
+		return false
+	}
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(data, value) == nil
+}
+
+type cachedRestaurantUseCase struct {
+	RestaurantUseCase
+	cache ReadCache
+}
+
+// NewCachedRestaurantUseCase serves the restaurants of the cities warmed by the cache warmer from
+// the cache. The list may lag the database by up to the TTL of the cache.
+func NewCachedRestaurantUseCase(restaurants RestaurantUseCase, cache ReadCache) RestaurantUseCase {
+	return &cachedRestaurantUseCase{
+		RestaurantUseCase: restaurants,
+		cache:             cache,
+	}
+}
+
+func (u *cachedRestaurantUseCase) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page PageRequest) (*PageResponse[*domain.Restaurant], error) {
+	var city cachedCity
+	if filter.CityID != "" && filter.AdultOnly == nil && loadCached(ctx, u.cache, cityCacheKey(filter.CityID), &city) {
+		if restaurants, more, ok := city.after(page.Cursor, page.Size()); ok {
+			response := &PageResponse[*domain.Restaurant]{Items: restaurants}
+			if more {
+				cursor, err := encodeCursor(restaurants[len(restaurants)-1].CursorAfter())
+				if err != nil {
+					return nil, err
+				}
+				response.NextCursor = cursor
+			}
+			return response, nil
+		}
+	}
+
+	return u.RestaurantUseCase.ListRestaurants(ctx, filter, page)
+}
+
+// after returns up to limit cached restaurants after the cursor and whether more follow them. The
+// cursor is found by the ID of its restaurant, so the names are never compared outside of the
+// database collation; a restaurant no longer cached and a page past the end of an incomplete list
+// are only in the database.
+func (c *cachedCity) after(cursor string, limit int) ([]*domain.Restaurant, bool, bool) {
+	after, err := decodeCursor[domain.RestaurantCursor](cursor)
+	if err != nil {
+		return nil, false, false
+	}
+
+	start := 0
+	if !after.IsZero() {
+		index := slices.IndexFunc(c.Restaurants, func(restaurant *domain.Restaurant) bool {
+			return restaurant.ID == after.ID
+		})
+		if index < 0 {
+			return nil, false, false
+		}
+		start = index + 1
+	}
+
+	end := start + limit
+	if end > len(c.Restaurants) {
+		if !c.Complete {
+			return nil, false, false
+		}
+		end = len(c.Restaurants)
+	}
+	return c.Restaurants[start:end], end < len(c.Restaurants) || !c.Complete, true
+}
+
+type cachedAvailabilityUseCase struct {
+	AvailabilityUseCase
+	cache ReadCache
+}
+
+// NewCachedAvailabilityUseCase serves the slots warmed by the cache warmer from the cache. The
+// seats shown may lag the database by up to the TTL of the cache; bookings are always checked
+// against the database.
+func NewCachedAvailabilityUseCase(availability AvailabilityUseCase, cache ReadCache) AvailabilityUseCase {
+	return &cachedAvailabilityUseCase{
+		AvailabilityUseCase: availability,
+		cache:               cache,
+	}
+}
+
+func (u *cachedAvailabilityUseCase) GetAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
+	var slots []*domain.Availability
+	if loadCached(ctx, u.cache, availabilityCacheKey(restaurantID.String(), date), &slots) {
+		return slots, nil
+	}
+
+	return u.AvailabilityUseCase.GetAvailability(ctx, restaurantID, date)
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCatalogPageSize = 100
+	maxCatalogPageSize     = 1000
+
+	catalogPageTTL = 5 * time.Minute
+)
+
+// CatalogPage is a page of the public restaurant catalogue used by crawlers and partner feeds.
+// Test restaurants are never listed.
+type CatalogPage struct {
+	Restaurants []*domain.Restaurant
+	Page        int
+	PageSize    int
+	HasNext     bool
+	GeneratedAt time.Time
+}
+
+type CatalogUseCase interface {
+	GetCatalogPage(ctx context.Context, page, pageSize int) (*CatalogPage, error)
+}
+
+type catalogPageKey struct {
+	page     int
+	pageSize int
+}
+
+type catalogUseCase struct {
+	restaurantRepo repository.RestaurantRepository
+
+	pagesMu sync.Mutex
+	pages   map[catalogPageKey]*CatalogPage
+	clock   clock.Clock
+}
+
+func NewCatalogUseCase(restaurantRepo repository.RestaurantRepository, clock clock.Clock) CatalogUseCase {
+	return &catalogUseCase{
+		restaurantRepo: restaurantRepo,
+		pages:          make(map[catalogPageKey]*CatalogPage),
+		clock:          clock,
+	}
+}
+
+// GetCatalogPage returns the 1-based page of the catalogue. Pages are cached for catalogPageTTL
+// since crawlers tend to request the whole catalogue in a short burst.
+func (u *catalogUseCase) GetCatalogPage(ctx context.Context, page, pageSize int) (*CatalogPage, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultCatalogPageSize
+	}
+	if pageSize > maxCatalogPageSize {
+		pageSize = maxCatalogPageSize
+	}
+
+	key := catalogPageKey{page: page, pageSize: pageSize}
+	now := u.clock.Now()
+
+	u.pagesMu.Lock()
+	cached, ok := u.pages[key]
+	u.pagesMu.Unlock()
+
+	if ok && now.Before(cached.GeneratedAt.Add(catalogPageTTL)) {
+		return cached, nil
+	}
+
+	restaurants, err := u.restaurantRepo.ListLive(ctx, (page-1)*pageSize, pageSize+1)
+	if err != nil {
+		log.Error(ctx, "failed to list restaurants for catalog",
+			zap.Int("page", page),
+			zap.Int("pageSize", pageSize),
+			zap.Error(err))
+		return nil, err
+	}
+
+	hasNext := len(restaurants) > pageSize
+	if hasNext {
+		restaurants = restaurants[:pageSize]
+	}
+
+	catalogPage := &CatalogPage{
+		Restaurants: restaurants,
+		Page:        page,
+		PageSize:    pageSize,
+		HasNext:     hasNext,
+		GeneratedAt: now,
+	}
+
+	u.pagesMu.Lock()
+	u.pages[key] = catalogPage
+	u.pagesMu.Unlock()
+
+	log.Info(ctx, "catalog page generated",
+		zap.Int("page", page),
+		zap.Int("pageSize", pageSize),
+		zap.Int("count", len(restaurants)))
+	return catalogPage, nil
+}
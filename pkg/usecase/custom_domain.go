@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const customDomainTokenBytes = 16
+
+var (
+	ErrInvalidCustomDomain = errors.New("invalid custom domain")
+
+	// ErrCustomDomainLimit is returned when the restaurant has as many domains as it may have.
+	ErrCustomDomainLimit = errors.New("the restaurant has too many custom domains")
+
+	// ErrCustomDomainUnverified is returned when the verification TXT record of the domain is
+	// missing or holds another token.
+	ErrCustomDomainUnverified = errors.New("custom domain is not verified")
+)
+
+// TXTResolver looks up the TXT records of a name; net.DefaultResolver implements it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// CustomDomainUseCase lets restaurants serve their booking page from their own domain. The staff
+// of a restaurant register a domain, publish its verification token in DNS and point the domain
+// at the platform; the server then routes the domain to the restaurant and gets it a certificate.
+type CustomDomainUseCase interface {
+	// AddDomain registers the domain for the restaurant, pending verification. Fails with
+	// common.ErrCustomDomainTaken when the domain is registered.
+	AddDomain(ctx context.Context, restaurantID, name string) (*domain.CustomDomain, error)
+
+	ListDomains(ctx context.Context, restaurantID string) ([]*domain.CustomDomain, error)
+
+	// VerifyDomain checks the verification TXT record of the domain and marks it verified when
+	// the record holds its token.
+	VerifyDomain(ctx context.Context, restaurantID, domainID string) (*domain.CustomDomain, error)
+
+	RemoveDomain(ctx context.Context, restaurantID, domainID string) error
+
+	// ResolveHost returns the verified domain of the host, failing with
+	// common.ErrCustomDomainNotFound for other hosts. It serves unauthenticated requests.
+	ResolveHost(ctx context.Context, host string) (*domain.CustomDomain, error)
+}
+
+type customDomainUseCase struct {
+	domainRepo       repository.CustomDomainRepository
+	restaurantRepo   repository.RestaurantRepository
+	resolver         TXTResolver
+	maxPerRestaurant int
+	reservedHosts    []string
+	clock            clock.Clock
+}
+
+// NewCustomDomainUseCase creates the use case; the reserved hosts, usually the platform's own,
+// and their subdomains can't be registered.
+func NewCustomDomainUseCase(
+	domainRepo repository.CustomDomainRepository,
+	restaurantRepo repository.RestaurantRepository,
+	resolver TXTResolver,
+	maxPerRestaurant int,
+	reservedHosts []string,
+	clock clock.Clock,
+) CustomDomainUseCase {
+	hosts := make([]string, 0, len(reservedHosts))
+	for _, host := range reservedHosts {
+		if host, ok := domain.NormalizeHostname(host); ok {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return &customDomainUseCase{
+		domainRepo:       domainRepo,
+		restaurantRepo:   restaurantRepo,
+		resolver:         resolver,
+		maxPerRestaurant: maxPerRestaurant,
+		reservedHosts:    hosts,
+		clock:            clock,
+	}
+}
+
+func (u *customDomainUseCase) AddDomain(ctx context.Context, restaurantID, name string) (*domain.CustomDomain, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	host, ok := domain.NormalizeHostname(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a domain name", ErrInvalidCustomDomain, name)
+	}
+	if u.reserved(host) {
+		return nil, fmt.Errorf("%w: %s belongs to the platform", ErrInvalidCustomDomain, host)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	existing, err := u.domainRepo.ListByRestaurant(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if u.maxPerRestaurant > 0 && len(existing) >= u.maxPerRestaurant {
+		return nil, fmt.Errorf("%w: at most %d", ErrCustomDomainLimit, u.maxPerRestaurant)
+	}
+
+	token := make([]byte, customDomainTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("%s: %w", common.ErrCreateCustomDomain, err)
+	}
+
+	customDomain := &domain.CustomDomain{
+		RestaurantID:      restaurantID,
+		Domain:            host,
+		VerificationToken: hex.EncodeToString(token),
+	}
+	if err := u.domainRepo.Create(ctx, customDomain); err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "custom domain added",
+		zap.String("restaurantID", restaurantID),
+		zap.String("domain", host))
+	return customDomain, nil
+}
+
+func (u *customDomainUseCase) reserved(host string) bool {
+	return slices.ContainsFunc(u.reservedHosts, func(reserved string) bool {
+		return host == reserved || strings.HasSuffix(host, "."+reserved)
+	})
+}
+
+func (u *customDomainUseCase) ListDomains(ctx context.Context, restaurantID string) ([]*domain.CustomDomain, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	return u.domainRepo.ListByRestaurant(ctx, restaurantID)
+}
+
+func (u *customDomainUseCase) VerifyDomain(ctx context.Context, restaurantID, domainID string) (*domain.CustomDomain, error) {
+	log, _ := logger.FromContext(ctx)
+
+	customDomain, err := u.restaurantDomain(ctx, restaurantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if customDomain.Status == domain.CustomDomainVerified {
+		return customDomain, nil
+	}
+
+	records, err := u.resolver.LookupTXT(ctx, customDomain.VerificationRecord())
+	if err != nil {
+		log.Warn(ctx, "custom domain verification lookup failed",
+			zap.String("domain", customDomain.Domain),
+			zap.Error(err))
+		return nil, fmt.Errorf("%w: no TXT record %s", ErrCustomDomainUnverified, customDomain.VerificationRecord())
+	}
+	if !slices.ContainsFunc(records, func(record string) bool {
+		return strings.TrimSpace(record) == customDomain.VerificationToken
+	}) {
+		return nil, fmt.Errorf("%w: TXT record %s does not hold the verification token",
+			ErrCustomDomainUnverified, customDomain.VerificationRecord())
+	}
+
+	now := u.clock.Now()
+	if err := u.domainRepo.MarkVerified(ctx, customDomain.ID, now); err != nil {
+		return nil, err
+	}
+	customDomain.Status = domain.CustomDomainVerified
+	customDomain.VerifiedAt = &now
+
+	log.Info(ctx, "custom domain verified",
+		zap.String("restaurantID", restaurantID),
+		zap.String("domain", customDomain.Domain))
+	return customDomain, nil
+}
+
+func (u *customDomainUseCase) RemoveDomain(ctx context.Context, restaurantID, domainID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	customDomain, err := u.restaurantDomain(ctx, restaurantID, domainID)
+	if err != nil {
+		return err
+	}
+	if err := u.domainRepo.Delete(ctx, customDomain.ID); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "custom domain removed",
+		zap.String("restaurantID", restaurantID),
+		zap.String("domain", customDomain.Domain))
+	return nil
+}
+
+// restaurantDomain returns the domain of the restaurant; a domain of another restaurant is not
+// found.
+func (u *customDomainUseCase) restaurantDomain(ctx context.Context, restaurantID, domainID string) (*domain.CustomDomain, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	customDomain, err := u.domainRepo.GetByID(ctx, domainID)
+	if err != nil {
+		return nil, err
+	}
+	if customDomain.RestaurantID != restaurantID {
+		return nil, errors.New(common.ErrCustomDomainNotFound)
+	}
+	return customDomain, nil
+}
+
+func (u *customDomainUseCase) ResolveHost(ctx context.Context, host string) (*domain.CustomDomain, error) {
+	name, ok := domain.NormalizeHostname(host)
+	if !ok {
+		return nil, errors.New(common.ErrCustomDomainNotFound)
+	}
+
+	customDomain, err := u.domainRepo.GetByDomain(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if customDomain.Status != domain.CustomDomainVerified {
+		return nil, errors.New(common.ErrCustomDomainNotFound)
+	}
+	return customDomain, nil
+}
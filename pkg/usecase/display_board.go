@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidBoardVersion = errors.New("invalid board version")
+
+// BoardQuery asks for the display board of a restaurant on Date. Since is the version of the board
+// the display already shows, to get only the bookings changed after it; ModifiedSince is the time
+// of that board, to learn whether it changed at all.
+type BoardQuery struct {
+	RestaurantID  string
+	Date          time.Time
+	Since         string
+	ModifiedSince time.Time
+}
+
+// DisplayBoard is the board of the bookings of a day. A full board lists the pending, confirmed
+// and completed bookings; a delta lists the bookings changed after the version the display holds
+// in every status, so that the display drops the cancelled and rejected ones. NotModified is set,
+// without bookings, when nothing changed.
+type DisplayBoard struct {
+	Date        time.Time
+	Version     string
+	UpdatedAt   time.Time
+	Delta       bool
+	NotModified bool
+	Bookings    []BoardBooking
+}
+
+// BoardBooking is a booking on the board with the name of its guest, empty when the guest could
+// not be read.
+type BoardBooking struct {
+	*domain.Booking
+	GuestName string
+}
+
+// DisplayBoardUseCase serves the board of a host stand, which polls it every few seconds.
+type DisplayBoardUseCase interface {
+	// GetBoard returns the board of the query; a version of another day is ignored and a full
+	// board is returned. Restaurant staff only.
+	GetBoard(ctx context.Context, query BoardQuery) (*DisplayBoard, error)
+}
+
+type displayBoardUseCase struct {
+	bookingRepo repository.BookingRepository
+	userRepo    repository.UserRepository
+}
+
+func NewDisplayBoardUseCase(bookingRepo repository.BookingRepository, userRepo repository.UserRepository) DisplayBoardUseCase {
+	return &displayBoardUseCase{
+		bookingRepo: bookingRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (u *displayBoardUseCase) GetBoard(ctx context.Context, query BoardQuery) (*DisplayBoard, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(query.RestaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	sinceDay, since, err := parseBoardVersion(query.Since)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, err := u.bookingRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(query.RestaurantID), query.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// The board never dates from before its day, so a display still showing yesterday's board
+	// always gets today's.
+	year, month, day := query.Date.Date()
+	board := &DisplayBoard{
+		Date:      query.Date,
+		UpdatedAt: time.Date(year, month, day, 0, 0, 0, 0, query.Date.Location()),
+	}
+	for _, booking := range bookings {
+		if booking.UpdatedAt.After(board.UpdatedAt) {
+			board.UpdatedAt = booking.UpdatedAt
+		}
+	}
+	board.Version = formatBoardVersion(query.Date, board.UpdatedAt)
+
+	// Last-Modified has a resolution of one second.
+	if !query.ModifiedSince.IsZero() && !board.UpdatedAt.Truncate(time.Second).After(query.ModifiedSince) {
+		board.NotModified = true
+		return board, nil
+	}
+
+	board.Delta = sinceDay == query.Date.Format(time.DateOnly)
+	var shown []*domain.Booking
+	for _, booking := range bookings {
+		if (board.Delta && booking.UpdatedAt.After(since)) || (!board.Delta && onSheet(booking.Status)) {
+			shown = append(shown, booking)
+		}
+	}
+	if board.Delta && len(shown) == 0 {
+		board.NotModified = true
+		return board, nil
+	}
+
+	guests := make(map[string]string)
+	board.Bookings = make([]BoardBooking, 0, len(shown))
+	for _, booking := range shown {
+		name, ok := guests[booking.UserID]
+		if !ok {
+			if guest, err := u.userRepo.GetByID(ctx, domain.UserID(booking.UserID)); err != nil {
+				log.Warn(ctx, "failed to get guest for display board",
+					zap.String("bookingID", booking.ID),
+					zap.Error(err))
+			} else {
+				name = guest.Name
+			}
+			guests[booking.UserID] = name
+		}
+		board.Bookings = append(board.Bookings, BoardBooking{Booking: booking, GuestName: name})
+	}
+
+	return board, nil
+}
+
+// formatBoardVersion encodes the day of a board and the time of its latest change as
+// "2026-05-10.1778405400000000000".
+func formatBoardVersion(date, updatedAt time.Time) string {
+	return date.Format(time.DateOnly) + "." + strconv.FormatInt(updatedAt.UnixNano(), 10)
+}
+
+// parseBoardVersion decodes a version of formatBoardVersion; the empty version has no day.
+func parseBoardVersion(version string) (string, time.Time, error) {
+	if version == "" {
+		return "", time.Time{}, nil
+	}
+
+	day, nanos, ok := strings.Cut(version, ".")
+	if !ok {
+		return "", time.Time{}, ErrInvalidBoardVersion
+	}
+	if _, err := time.Parse(time.DateOnly, day); err != nil {
+		return "", time.Time{}, ErrInvalidBoardVersion
+	}
+	updatedAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return "", time.Time{}, ErrInvalidBoardVersion
+	}
+	return day, time.Unix(0, updatedAt), nil
+}
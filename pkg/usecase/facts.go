@@ -2,7 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
@@ -10,19 +16,55 @@ import (
 	"go.uber.org/zap"
 )
 
+var ErrNoFactsAvailable = errors.New("no facts available")
+
+const (
+	defaultRandomFactsCount = 3
+	maxRandomFactsCount     = 10
+
+	factsPoolSize = 200
+	factsPoolTTL  = 30 * time.Second
+	// maxFactsPools bounds the cached pools, which are keyed by filters taken from requests.
+	maxFactsPools = 256
+)
+
 type FactsUseCase interface {
 	GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error)
 
-	GetRestaurantFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error)
+	GetFilteredRandomFacts(ctx context.Context, filter domain.FactFilter, count int) ([]domain.Fact, error)
+
+	GetRestaurantFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error)
+
+	GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error)
+}
+
+type factsPool struct {
+	facts     []domain.Fact
+	expiresAt time.Time
+}
+
+type featuredFact struct {
+	fact domain.Fact
+	day  string
 }
 
 type factsUseCase struct {
 	restaurantRepo repository.RestaurantRepository
+
+	poolsMu sync.Mutex
+	pools   map[domain.FactFilter]factsPool
+
+	featuredMu sync.Mutex
+	featured   map[string]featuredFact
+	clock      clock.Clock
 }
 
-func NewFactsUseCase(restaurantRepo repository.RestaurantRepository) FactsUseCase {
+func NewFactsUseCase(restaurantRepo repository.RestaurantRepository, clock clock.Clock) FactsUseCase {
 	return &factsUseCase{
 		restaurantRepo: restaurantRepo,
+		pools:          make(map[domain.FactFilter]factsPool),
+		featured:       make(map[string]featuredFact),
+		clock:          clock,
 	}
 }
 
@@ -30,12 +72,7 @@ func (u *factsUseCase) GetRandomFacts(ctx context.Context, count int) ([]domain.
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "getting random facts", zap.Int("count", count))
 
-	if count <= 0 {
-		count = 3
-	}
-	if count > 10 {
-		count = 10
-	}
+	count = normalizeFactsCount(count)
 
 	facts, err := u.restaurantRepo.GetRandomFacts(ctx, count)
 	if err != nil {
@@ -47,6 +84,196 @@ func (u *factsUseCase) GetRandomFacts(ctx context.Context, count int) ([]domain.
 	return facts, nil
 }
 
-func (u *factsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (u *factsUseCase) GetFilteredRandomFacts(ctx context.Context, filter domain.FactFilter, count int) ([]domain.Fact, error) {
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "getting filtered random facts",
+		zap.String("restaurantID", filter.RestaurantID),
+		zap.String("cuisine", string(filter.Cuisine)),
+		zap.Int("count", count))
+
+	count = normalizeFactsCount(count)
+
+	pool, err := u.getFactsPool(ctx, filter)
+	if err != nil {
+		log.Error(ctx, "failed to get facts pool",
+			zap.String("restaurantID", filter.RestaurantID),
+			zap.String("cuisine", string(filter.Cuisine)),
+			zap.Error(err))
+		return nil, err
+	}
+
+	facts := pickWeightedFacts(pool, count)
+
+	log.Info(ctx, "retrieved filtered random facts", zap.Int("count", len(facts)))
+	return facts, nil
+}
+
+func (u *factsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	return u.restaurantRepo.GetFacts(ctx, restaurantID)
 }
+
+// GetFactOfTheDay returns the featured fact of the current UTC day for the locale.
+// The fact is selected on the first call of the day and cached until the day changes;
+// the previous day's fact is not repeated when the pool allows it.
+func (u *factsUseCase) GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error) {
+	log, _ := logger.FromContext(ctx)
+
+	locale = NormalizeFactLocale(locale)
+	today := u.clock.Now().UTC().Format("2006-01-02")
+
+	u.featuredMu.Lock()
+	defer u.featuredMu.Unlock()
+
+	previous, ok := u.featured[locale]
+	if ok && previous.day == today {
+		return &previous.fact, nil
+	}
+
+	pool, err := u.getFactsPool(ctx, domain.FactFilter{Locale: locale})
+	if err != nil {
+		log.Error(ctx, "failed to get facts pool for fact of the day",
+			zap.String("locale", locale),
+			zap.Error(err))
+		return nil, err
+	}
+
+	candidates := make([]domain.Fact, 0, len(pool))
+	for _, fact := range pool {
+		if ok && fact.ID == previous.fact.ID {
+			continue
+		}
+		candidates = append(candidates, fact)
+	}
+	if len(candidates) == 0 {
+		candidates = pool
+	}
+	if len(candidates) == 0 {
+		log.Warn(ctx, "no facts available for fact of the day", zap.String("locale", locale))
+		return nil, ErrNoFactsAvailable
+	}
+
+	fact := candidates[rand.IntN(len(candidates))]
+	u.featured[locale] = featuredFact{fact: fact, day: today}
+
+	log.Info(ctx, "fact of the day selected",
+		zap.String("locale", locale),
+		zap.String("factID", fact.ID),
+		zap.String("day", today))
+	return &fact, nil
+}
+
+// NormalizeFactLocale reduces a locale or language tag (e.g. "ru-RU") to its lowercase
+// primary language subtag, falling back to the default fact locale.
+func NormalizeFactLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if idx := strings.IndexAny(locale, "-_"); idx >= 0 {
+		locale = locale[:idx]
+	}
+	if locale == "" {
+		return domain.DefaultFactLocale
+	}
+
+	return strings.ToLower(locale)
+}
+
+// getFactsPool returns the cached candidate facts for the filter, reloading them
+// from the repository once the cached pool has expired.
+func (u *factsUseCase) getFactsPool(ctx context.Context, filter domain.FactFilter) ([]domain.Fact, error) {
+	now := u.clock.Now()
+
+	u.poolsMu.Lock()
+	pool, ok := u.pools[filter]
+	u.poolsMu.Unlock()
+
+	if ok && now.Before(pool.expiresAt) {
+		return pool.facts, nil
+	}
+
+	facts, err := u.restaurantRepo.GetFactsPool(ctx, filter, factsPoolSize)
+	if err != nil {
+		return nil, err
+	}
+
+	u.poolsMu.Lock()
+	u.storePool(filter, factsPool{facts: facts, expiresAt: now.Add(factsPoolTTL)}, now)
+	u.poolsMu.Unlock()
+
+	return facts, nil
+}
+
+// storePool caches the pool of the filter. Once maxFactsPools pools are cached, the expired ones
+// are dropped and, if none has expired, the one loaded first makes room. The caller holds poolsMu.
+func (u *factsUseCase) storePool(filter domain.FactFilter, pool factsPool, now time.Time) {
+	if _, ok := u.pools[filter]; !ok && len(u.pools) >= maxFactsPools {
+		var oldest domain.FactFilter
+		var oldestExpiresAt time.Time
+		for cachedFilter, cached := range u.pools {
+			if !now.Before(cached.expiresAt) {
+				delete(u.pools, cachedFilter)
+				continue
+			}
+			if oldestExpiresAt.IsZero() || cached.expiresAt.Before(oldestExpiresAt) {
+				oldest, oldestExpiresAt = cachedFilter, cached.expiresAt
+			}
+		}
+		if len(u.pools) >= maxFactsPools {
+			delete(u.pools, oldest)
+		}
+	}
+
+	u.pools[filter] = pool
+}
+
+func normalizeFactsCount(count int) int {
+	if count <= 0 {
+		return defaultRandomFactsCount
+	}
+	if count > maxRandomFactsCount {
+		return maxRandomFactsCount
+	}
+
+	return count
+}
+
+// pickWeightedFacts draws facts so that every restaurant has the same chance to appear,
+// regardless of how many facts it has: restaurants are visited in random order and
+// contribute one random fact per round until the requested count is reached.
+func pickWeightedFacts(pool []domain.Fact, count int) []domain.Fact {
+	byRestaurant := make(map[string][]domain.Fact)
+	restaurantIDs := make([]string, 0)
+	for _, fact := range pool {
+		if _, ok := byRestaurant[fact.RestaurantID]; !ok {
+			restaurantIDs = append(restaurantIDs, fact.RestaurantID)
+		}
+		byRestaurant[fact.RestaurantID] = append(byRestaurant[fact.RestaurantID], fact)
+	}
+
+	rand.Shuffle(len(restaurantIDs), func(i, j int) {
+		restaurantIDs[i], restaurantIDs[j] = restaurantIDs[j], restaurantIDs[i]
+	})
+
+	picked := make([]domain.Fact, 0, count)
+	for len(picked) < count && len(restaurantIDs) > 0 {
+		remaining := restaurantIDs[:0]
+		for _, restaurantID := range restaurantIDs {
+			if len(picked) == count {
+				break
+			}
+
+			facts := byRestaurant[restaurantID]
+			idx := rand.IntN(len(facts))
+			picked = append(picked, facts[idx])
+
+			facts[idx] = facts[len(facts)-1]
+			facts = facts[:len(facts)-1]
+			byRestaurant[restaurantID] = facts
+
+			if len(facts) > 0 {
+				remaining = append(remaining, restaurantID)
+			}
+		}
+		restaurantIDs = remaining
+	}
+
+	return picked
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxInjectedLatency bounds the latency injected into a route, so a forgotten fault cannot hold
+	// requests indefinitely.
+	MaxInjectedLatency = time.Minute
+
+	defaultFaultStatus = http.StatusServiceUnavailable
+)
+
+var (
+	ErrFaultInjectionDisabled = errors.New("fault injection is disabled")
+	ErrRouteFaultNotFound     = errors.New("route fault not found")
+	ErrInvalidRouteFault      = errors.New("invalid route fault")
+	ErrInvalidFailureRate     = errors.New("failure rate must be between 0 and 1")
+	// ErrInjectedDBFault is the error of a database call failed on purpose.
+	ErrInjectedDBFault = errors.New("injected database fault")
+)
+
+// FaultInjectionUseCase keeps the faults injected for resilience testing of clients and their
+// retry logic in memory. Nothing is injected unless it is enabled, which it must never be in
+// production; managing the faults is for admins.
+type FaultInjectionUseCase interface {
+	// RouteFaults returns the faults of routes in the order they were added; none when disabled.
+	RouteFaults() []domain.RouteFault
+	// DBFault returns ErrInjectedDBFault for the share of calls set by SetDBFailureRate.
+	DBFault() error
+
+	GetFaults(ctx context.Context) (*domain.FaultInjection, error)
+	// AddRouteFault injects the fault into the requests of its route. A fault without an error
+	// status answers 503.
+	AddRouteFault(ctx context.Context, fault *domain.RouteFault) error
+	DeleteRouteFault(ctx context.Context, id string) error
+	SetDBFailureRate(ctx context.Context, rate float64) error
+	// ClearFaults removes every fault.
+	ClearFaults(ctx context.Context) error
+}
+
+type faultInjectionUseCase struct {
+	enabled bool
+	ids     idgen.Generator
+
+	mu            sync.RWMutex
+	routes        []domain.RouteFault
+	dbFailureRate float64
+	clock         clock.Clock
+}
+
+func NewFaultInjectionUseCase(enabled bool, ids idgen.Generator, clock clock.Clock) FaultInjectionUseCase {
+	return &faultInjectionUseCase{
+		enabled: enabled,
+		ids:     ids,
+		clock:   clock,
+	}
+}
+
+func (u *faultInjectionUseCase) RouteFaults() []domain.RouteFault {
+	if !u.enabled {
+		return nil
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return u.routes
+}
+
+func (u *faultInjectionUseCase) DBFault() error {
+	if !u.enabled {
+		return nil
+	}
+
+	u.mu.RLock()
+	rate := u.dbFailureRate
+	u.mu.RUnlock()
+
+	if rate > 0 && rand.Float64() < rate {
+		return ErrInjectedDBFault
+	}
+	return nil
+}
+
+func (u *faultInjectionUseCase) GetFaults(ctx context.Context) (*domain.FaultInjection, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return &domain.FaultInjection{
+		Enabled:       u.enabled,
+		Routes:        slices.Clone(u.routes),
+		DBFailureRate: u.dbFailureRate,
+	}, nil
+}
+
+func (u *faultInjectionUseCase) AddRouteFault(ctx context.Context, fault *domain.RouteFault) error {
+	if err := u.checkManage(ctx); err != nil {
+		return err
+	}
+
+	fault.Method = strings.ToUpper(strings.TrimSpace(fault.Method))
+	fault.Path = strings.TrimSpace(fault.Path)
+	if fault.ErrorStatus == 0 {
+		fault.ErrorStatus = defaultFaultStatus
+	}
+	if err := validateRouteFault(fault); err != nil {
+		return err
+	}
+
+	fault.ID = u.ids.NewID()
+	fault.CreatedAt = u.clock.Now()
+
+	u.mu.Lock()
+	// The middleware reads the slice without the lock, so it is replaced instead of appended to.
+	u.routes = append(slices.Clone(u.routes), *fault)
+	u.mu.Unlock()
+
+	u.logChange(ctx, "route fault added", zap.String("faultID", fault.ID), zap.String("method", fault.Method),
+		zap.String("path", fault.Path), zap.Duration("latency", fault.Latency), zap.Float64("errorRate", fault.ErrorRate))
+	return nil
+}
+
+func validateRouteFault(fault *domain.RouteFault) error {
+	switch {
+	case !strings.HasPrefix(fault.Path, "/"):
+		return fmt.Errorf("%w: path must start with /", ErrInvalidRouteFault)
+	case fault.Latency < 0 || fault.Latency > MaxInjectedLatency:
+		return fmt.Errorf("%w: latency must be between 0 and %s", ErrInvalidRouteFault, MaxInjectedLatency)
+	case fault.ErrorRate < 0 || fault.ErrorRate > 1:
+		return fmt.Errorf("%w: error rate must be between 0 and 1", ErrInvalidRouteFault)
+	case fault.ErrorStatus < 400 || fault.ErrorStatus > 599:
+		return fmt.Errorf("%w: error status must be a 4xx or 5xx status", ErrInvalidRouteFault)
+	case fault.Latency == 0 && fault.ErrorRate == 0:
+		return fmt.Errorf("%w: latency or error rate is required", ErrInvalidRouteFault)
+	}
+	return nil
+}
+
+func (u *faultInjectionUseCase) DeleteRouteFault(ctx context.Context, id string) error {
+	if err := u.checkManage(ctx); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	index := slices.IndexFunc(u.routes, func(fault domain.RouteFault) bool {
+		return fault.ID == id
+	})
+	if index >= 0 {
+		u.routes = slices.Delete(slices.Clone(u.routes), index, index+1)
+	}
+	u.mu.Unlock()
+
+	if index < 0 {
+		return ErrRouteFaultNotFound
+	}
+
+	u.logChange(ctx, "route fault deleted", zap.String("faultID", id))
+	return nil
+}
+
+func (u *faultInjectionUseCase) SetDBFailureRate(ctx context.Context, rate float64) error {
+	if err := u.checkManage(ctx); err != nil {
+		return err
+	}
+	if rate < 0 || rate > 1 {
+		return ErrInvalidFailureRate
+	}
+
+	u.mu.Lock()
+	u.dbFailureRate = rate
+	u.mu.Unlock()
+
+	u.logChange(ctx, "database failure rate set", zap.Float64("rate", rate))
+	return nil
+}
+
+func (u *faultInjectionUseCase) ClearFaults(ctx context.Context) error {
+	if err := u.checkManage(ctx); err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.routes = nil
+	u.dbFailureRate = 0
+	u.mu.Unlock()
+
+	u.logChange(ctx, "faults cleared")
+	return nil
+}
+
+func (u *faultInjectionUseCase) checkManage(ctx context.Context) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	if !u.enabled {
+		return ErrFaultInjectionDisabled
+	}
+	return nil
+}
+
+// logChange warns about every change, so that failures seen while faults are injected can be told
+// apart from real ones in the logs.
+func (u *faultInjectionUseCase) logChange(ctx context.Context, msg string, fields ...zap.Field) {
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Warn(ctx, msg, fields...)
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// geocodingMaxAttempts is how often an address is tried while the provider cannot be asked
+	// before it is given up; an address the provider does not know is given up at once.
+	geocodingMaxAttempts = 3
+
+	// geocodingClaimTimeout is how long a location may stay processing before another run takes
+	// it over.
+	geocodingClaimTimeout = 5 * time.Minute
+)
+
+// GeocodingUseCase places the addresses of restaurants in the background: an address set on a
+// restaurant is queued, and GeocodePending resolves the queue through a geocoding provider.
+type GeocodingUseCase interface {
+	// RequestGeocoding queues the address of the restaurant unless it is already geocoded.
+	RequestGeocoding(ctx context.Context, restaurant *domain.Restaurant) error
+
+	// GetRestaurantLocation returns the geocoded address of the restaurant, or the state of its
+	// geocoding.
+	GetRestaurantLocation(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error)
+
+	// GeocodePending geocodes up to limit queued addresses and returns how many it handled. The
+	// restaurants whose address could not be placed are notified.
+	GeocodePending(ctx context.Context, limit int) (int, error)
+
+	// ListCities returns the cities restaurants were placed in, in the country given by its ISO
+	// 3166-1 alpha-2 code or in every country when it is empty, with their number of restaurants.
+	ListCities(ctx context.Context, countryCode string) ([]*domain.City, error)
+}
+
+var ErrInvalidCountryCode = errors.New("invalid country code")
+
+type geocodingUseCase struct {
+	locationRepo repository.RestaurantLocationRepository
+	geocoder     geo.Geocoder
+	notifier     domain.NotificationService
+	clock        clock.Clock
+}
+
+func NewGeocodingUseCase(
+	locationRepo repository.RestaurantLocationRepository,
+	geocoder geo.Geocoder,
+	notifier domain.NotificationService,
+	clock clock.Clock,
+) GeocodingUseCase {
+	return &geocodingUseCase{
+		locationRepo: locationRepo,
+		geocoder:     geocoder,
+		notifier:     notifier,
+		clock:        clock,
+	}
+}
+
+func (u *geocodingUseCase) RequestGeocoding(ctx context.Context, restaurant *domain.Restaurant) error {
+	if restaurant.Address == "" {
+		return nil
+	}
+	return u.locationRepo.Enqueue(ctx, restaurant.ID, restaurant.Address)
+}
+
+func (u *geocodingUseCase) GetRestaurantLocation(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	return u.locationRepo.GetByRestaurant(ctx, restaurantID)
+}
+
+func (u *geocodingUseCase) GeocodePending(ctx context.Context, limit int) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	locations, err := u.locationRepo.ClaimPending(ctx, limit, u.clock.Now().Add(-geocodingClaimTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for _, location := range locations {
+		if ctx.Err() != nil {
+			break
+		}
+
+		u.geocode(ctx, location)
+
+		if err := u.locationRepo.Save(ctx, location); err != nil {
+			// The address changed while it was geocoded; the new one is queued.
+			if err.Error() == common.ErrRestaurantLocationNotFound {
+				continue
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		if location.Status == domain.GeocodingFailed {
+			log.Warn(ctx, common.ErrGeocodeRestaurant,
+				zap.String("restaurantID", location.RestaurantID),
+				zap.String("address", location.Address),
+				zap.String("error", location.Error))
+			u.notifyFailure(ctx, location)
+		}
+	}
+
+	return len(locations), errors.Join(errs...)
+}
+
+func (u *geocodingUseCase) ListCities(ctx context.Context, countryCode string) ([]*domain.City, error) {
+	countryCode = strings.ToUpper(strings.TrimSpace(countryCode))
+	if countryCode != "" && !isCountryCode(countryCode) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCountryCode, countryCode)
+	}
+	return u.locationRepo.ListCities(ctx, countryCode)
+}
+
+func isCountryCode(code string) bool {
+	return len(code) == 2 && code[0] >= 'A' && code[0] <= 'Z' && code[1] >= 'A' && code[1] <= 'Z'
+}
+
+// geocode sets the outcome of geocoding the location: its components once the provider placed
+// it, failed once the provider does not know it or could not be asked for the last time, and
+// pending again otherwise.
+func (u *geocodingUseCase) geocode(ctx context.Context, location *domain.RestaurantLocation) {
+	location.Attempts++
+	location.Provider = u.geocoder.Provider()
+
+	address, err := u.geocoder.Geocode(ctx, location.Address)
+	if err != nil {
+		location.Error = err.Error()
+		location.Status = domain.GeocodingPending
+		if errors.Is(err, geo.ErrAddressNotFound) || location.Attempts >= geocodingMaxAttempts {
+			location.Status = domain.GeocodingFailed
+		}
+		return
+	}
+
+	now := u.clock.Now()
+	location.Status = domain.GeocodingDone
+	location.Formatted = address.Formatted
+	location.Street = address.Street
+	location.HouseNumber = address.HouseNumber
+	location.City = address.City
+	location.Region = address.Region
+	location.PostalCode = address.PostalCode
+	location.CountryCode = address.CountryCode
+	location.Latitude = address.Latitude
+	location.Longitude = address.Longitude
+	location.Error = ""
+	location.GeocodedAt = &now
+}
+
+func (u *geocodingUseCase) notifyFailure(ctx context.Context, location *domain.RestaurantLocation) {
+	log, _ := logger.FromContext(ctx)
+
+	message := fmt.Sprintf("The address %q could not be placed on the map: %s. Saving the restaurant again retries it.",
+		location.Address, location.Error)
+	if location.Error == geo.ErrAddressNotFound.Error() {
+		message = fmt.Sprintf("The address %q was not found on the map, so guests searching nearby do not see the restaurant. Please check the address.",
+			location.Address)
+	}
+
+	err := u.notifier.NotifyRestaurant(
+		ctx,
+		location.RestaurantID,
+		domain.NotificationTypeGeocodingFailed,
+		"Address not found on the map",
+		message,
+		location.RestaurantID,
+	)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to restaurant",
+			zap.String("restaurantID", location.RestaurantID),
+			zap.Error(err))
+	}
+}
+
+type geocodedRestaurantUseCase struct {
+	RestaurantUseCase
+	geocoding GeocodingUseCase
+}
+
+// NewGeocodedRestaurantUseCase queues the addresses of the restaurants created, updated and
+// imported through restaurants for geocoding. A restaurant is saved even when its address cannot
+// be queued; the failure is logged.
+func NewGeocodedRestaurantUseCase(restaurants RestaurantUseCase, geocoding GeocodingUseCase) RestaurantUseCase {
+	return &geocodedRestaurantUseCase{
+		RestaurantUseCase: restaurants,
+		geocoding:         geocoding,
+	}
+}
+
+func (u *geocodedRestaurantUseCase) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
+	id, err := u.RestaurantUseCase.CreateRestaurant(ctx, restaurant)
+	if err != nil {
+		return id, err
+	}
+
+	u.requestGeocoding(ctx, restaurant)
+	return id, nil
+}
+
+func (u *geocodedRestaurantUseCase) UpdateRestaurant(ctx context.Context, restaurant *domain.Restaurant) error {
+	if err := u.RestaurantUseCase.UpdateRestaurant(ctx, restaurant); err != nil {
+		return err
+	}
+
+	u.requestGeocoding(ctx, restaurant)
+	return nil
+}
+
+func (u *geocodedRestaurantUseCase) ImportRestaurants(ctx context.Context, r io.Reader, dryRun bool) (*RestaurantImportReport, error) {
+	report, err := u.RestaurantUseCase.ImportRestaurants(ctx, r, dryRun)
+	if err != nil || report.DryRun {
+		return report, err
+	}
+
+	for _, id := range report.RestaurantIDs {
+		restaurant, err := u.RestaurantUseCase.GetRestaurant(ctx, domain.RestaurantID(id))
+		if err != nil {
+			log, _ := logger.FromContext(ctx)
+			log.Warn(ctx, common.ErrEnqueueGeocoding, zap.String("restaurantID", id), zap.Error(err))
+			continue
+		}
+		u.requestGeocoding(ctx, restaurant)
+	}
+	return report, nil
+}
+
+func (u *geocodedRestaurantUseCase) ImportRestaurantBundle(ctx context.Context, bundle *RestaurantBundle) (string, error) {
+	id, err := u.RestaurantUseCase.ImportRestaurantBundle(ctx, bundle)
+	if err != nil {
+		return id, err
+	}
+
+	u.requestGeocoding(ctx, &domain.Restaurant{ID: id, Address: bundle.Restaurant.Address})
+	return id, nil
+}
+
+func (u *geocodedRestaurantUseCase) requestGeocoding(ctx context.Context, restaurant *domain.Restaurant) {
+	if err := u.geocoding.RequestGeocoding(ctx, restaurant); err != nil {
+		log, _ := logger.FromContext(ctx)
+		log.Warn(ctx, common.ErrEnqueueGeocoding, zap.String("restaurantID", restaurant.ID), zap.Error(err))
+	}
+}
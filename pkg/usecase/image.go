@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidImage     = errors.New("invalid image")
+	ErrInvalidImageSize = errors.New("invalid image size")
+)
+
+const (
+	// maxImagePixels keeps uploads small enough to be decoded in memory when a variant is made.
+	maxImagePixels = 40_000_000
+
+	// imageVariantClaimTimeout is how long a variant may stay processing before another run takes
+	// it over, e.g. after the instance generating it stopped.
+	imageVariantClaimTimeout = 10 * time.Minute
+)
+
+// ImageVariantCache keeps resized images by key; imaging.DiskCache implements it.
+type ImageVariantCache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte) error
+	RemovePrefix(prefix string)
+}
+
+// ImageVariant is an encoded image ready to be sent. Key names it among all the variants of all
+// images, e.g. for an ETag.
+type ImageVariant struct {
+	Key         string
+	Data        []byte
+	ContentType string
+}
+
+type ImageUseCase interface {
+	// UploadImage stores a JPEG, PNG or GIF image for the restaurant and queues its variants in
+	// the configured formats for GenerateVariants.
+	UploadImage(ctx context.Context, restaurantID string, data []byte) (*domain.RestaurantImage, error)
+
+	ListImages(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error)
+
+	DeleteImage(ctx context.Context, restaurantID, imageID string) error
+
+	// GetImageVariant returns the image resized as the options ask, from the cache when it was
+	// made before. Options without a size return the original upload. The image is written in the
+	// first configured format among the accepted content types whose variant is ready, and in the
+	// format of the original otherwise.
+	GetImageVariant(ctx context.Context, imageID string, opts imaging.Options, accepted []string) (*ImageVariant, error)
+
+	// GenerateVariants converts up to limit queued variants and returns how many it handled.
+	GenerateVariants(ctx context.Context, limit int) (int, error)
+}
+
+type imageUseCase struct {
+	imageRepo      repository.ImageRepository
+	restaurantRepo repository.RestaurantRepository
+	cache          ImageVariantCache
+	maxDimension   int
+	variantFormats []string
+	clock          clock.Clock
+}
+
+// NewImageUseCase creates the use case; maxDimension is the largest width or height a variant may
+// be asked for and variantFormats are the content types, most preferred first, every upload is
+// converted to. Formats imaging cannot encode are ignored.
+func NewImageUseCase(
+	imageRepo repository.ImageRepository,
+	restaurantRepo repository.RestaurantRepository,
+	cache ImageVariantCache,
+	maxDimension int,
+	variantFormats []string,
+	clock clock.Clock,
+) ImageUseCase {
+	formats := make([]string, 0, len(variantFormats))
+	for _, format := range variantFormats {
+		if imaging.CanEncode(format) && !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+
+	return &imageUseCase{
+		imageRepo:      imageRepo,
+		restaurantRepo: restaurantRepo,
+		cache:          cache,
+		maxDimension:   maxDimension,
+		variantFormats: formats,
+		clock:          clock,
+	}
+}
+
+func (u *imageUseCase) UploadImage(ctx context.Context, restaurantID string, data []byte) (*domain.RestaurantImage, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a JPEG, PNG or GIF image", ErrInvalidImage)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d pixels is not supported", ErrInvalidImage, config.Width, config.Height)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	img := &domain.RestaurantImage{
+		RestaurantID: restaurantID,
+		ContentType:  "image/" + format,
+		Width:        config.Width,
+		Height:       config.Height,
+		Data:         data,
+		Variants:     make([]domain.RestaurantImageVariant, 0, len(u.variantFormats)),
+	}
+	for _, format := range u.variantFormats {
+		if format != img.ContentType {
+			img.Variants = append(img.Variants, domain.RestaurantImageVariant{
+				ContentType: format,
+				Status:      domain.ImageVariantPending,
+			})
+		}
+	}
+	if err := u.imageRepo.Create(ctx, img); err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "image uploaded",
+		zap.String("restaurantID", restaurantID),
+		zap.String("imageID", img.ID),
+		zap.Int("size", len(data)))
+	return img, nil
+}
+
+func (u *imageUseCase) ListImages(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	return u.imageRepo.ListByRestaurant(ctx, restaurantID)
+}
+
+func (u *imageUseCase) DeleteImage(ctx context.Context, restaurantID, imageID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	img, err := u.imageRepo.GetByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if img.RestaurantID != restaurantID {
+		return errors.New(common.ErrImageNotFound)
+	}
+
+	if err := u.imageRepo.Delete(ctx, imageID); err != nil {
+		return err
+	}
+	u.cache.RemovePrefix(imageID + "_")
+
+	log.Info(ctx, "image deleted",
+		zap.String("restaurantID", restaurantID),
+		zap.String("imageID", imageID))
+	return nil
+}
+
+// GetImageVariant serves cached variants after looking up which formats are ready, without loading
+// the image. Deleting an image drops its variants; variants of images removed together with their
+// restaurant stay until the cache evicts them.
+func (u *imageUseCase) GetImageVariant(ctx context.Context, imageID string, opts imaging.Options, accepted []string) (*ImageVariant, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if opts.Width < 0 || opts.Height < 0 || opts.Width > u.maxDimension || opts.Height > u.maxDimension {
+		return nil, fmt.Errorf("%w: width and height must be between 1 and %d", ErrInvalidImageSize, u.maxDimension)
+	}
+
+	opts.Format = ""
+	if len(u.variantFormats) > 0 && len(accepted) > 0 {
+		variants, err := u.imageRepo.GetVariants(ctx, imageID)
+		if err != nil {
+			return nil, err
+		}
+		opts.Format = preferredFormat(u.variantFormats, variants, accepted)
+	}
+
+	key := opts.Key(imageID)
+	if data, ok := u.cache.Get(key); ok {
+		contentType := opts.Format
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		return &ImageVariant{Key: key, Data: data, ContentType: contentType}, nil
+	}
+
+	var variant *ImageVariant
+	switch {
+	case opts.Original() && opts.Format != "":
+		data, err := u.imageRepo.GetVariantData(ctx, imageID, opts.Format)
+		if err != nil {
+			return nil, err
+		}
+		variant = &ImageVariant{Key: key, Data: data, ContentType: opts.Format}
+	default:
+		img, err := u.imageRepo.GetByID(ctx, imageID)
+		if err != nil {
+			return nil, err
+		}
+
+		variant = &ImageVariant{Key: key, Data: img.Data, ContentType: img.ContentType}
+		if !opts.Original() {
+			data, contentType, err := imaging.Resize(img.Data, opts)
+			if err != nil {
+				log.Error(ctx, common.ErrResizeImage, zap.String("imageID", imageID), zap.Error(err))
+				return nil, err
+			}
+			variant = &ImageVariant{Key: key, Data: data, ContentType: contentType}
+		}
+	}
+
+	if err := u.cache.Put(key, variant.Data); err != nil {
+		log.Warn(ctx, "failed to cache image variant", zap.String("key", key), zap.Error(err))
+	}
+	return variant, nil
+}
+
+// preferredFormat returns the first of the formats that the client accepts and whose variant is
+// ready, or no format.
+func preferredFormat(formats []string, variants []domain.RestaurantImageVariant, accepted []string) string {
+	for _, format := range formats {
+		if !slices.Contains(accepted, format) {
+			continue
+		}
+		for _, variant := range variants {
+			if variant.ContentType == format && variant.Status == domain.ImageVariantReady {
+				return format
+			}
+		}
+	}
+	return ""
+}
+
+func (u *imageUseCase) GenerateVariants(ctx context.Context, limit int) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	variants, err := u.imageRepo.ClaimPendingVariants(ctx, limit, u.clock.Now().Add(-imageVariantClaimTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	var errs []error
+	for i := range variants {
+		variant := &variants[i]
+		if err := u.generateVariant(ctx, variant); err != nil {
+			log.Warn(ctx, common.ErrGenerateImageVariant,
+				zap.String("imageID", variant.ImageID),
+				zap.String("contentType", variant.ContentType),
+				zap.Error(err))
+			variant.Status = domain.ImageVariantFailed
+			variant.Error = err.Error()
+		}
+
+		if err := u.imageRepo.SaveVariant(ctx, variant); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(variants), errors.Join(errs...)
+}
+
+// generateVariant encodes the full-size image in the format of the variant. A variant that is not
+// smaller than the upload is discarded, e.g. lossless WebP of a JPEG photo.
+func (u *imageUseCase) generateVariant(ctx context.Context, variant *domain.RestaurantImageVariant) error {
+	img, err := u.imageRepo.GetByID(ctx, variant.ImageID)
+	if err != nil {
+		return err
+	}
+
+	data, _, err := imaging.Resize(img.Data, imaging.Options{Format: variant.ContentType})
+	if err != nil {
+		return err
+	}
+
+	variant.Width, variant.Height, variant.Size = img.Width, img.Height, len(data)
+	variant.Error = ""
+	if len(data) >= len(img.Data) {
+		variant.Status = domain.ImageVariantDiscarded
+		variant.Data = nil
+		return nil
+	}
+
+	variant.Status = domain.ImageVariantReady
+	variant.Data = data
+	return nil
+}
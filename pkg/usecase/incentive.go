@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidIncentiveRule = errors.New("invalid incentive rule")
+
+// IncentiveUseCase keeps the rules of platform incentives and evaluates them for bookings,
+// keeping every evaluation for audit.
+type IncentiveUseCase interface {
+	// ListRules returns every rule, disabled ones included, oldest first; admins only.
+	ListRules(ctx context.Context) ([]*domain.IncentiveRule, error)
+
+	// CreateRule adds a rule; admins only.
+	CreateRule(ctx context.Context, rule *domain.IncentiveRule) error
+
+	// UpdateRule replaces a rule; admins only. Evaluations made before keep their outcome.
+	UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error
+
+	// DeleteRule removes a rule; admins only.
+	DeleteRule(ctx context.Context, id string) error
+
+	// Evaluate evaluates the enabled rules for a booking about to be made at now.
+	Evaluate(ctx context.Context, booking *domain.Booking, now time.Time) (*domain.IncentiveEvaluation, error)
+
+	// RecordEvaluation keeps the evaluation of a booking once it was made.
+	RecordEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error
+
+	// GetBookingIncentives returns the latest evaluation of a booking; its guest, the staff of its
+	// restaurant and admins only.
+	GetBookingIncentives(ctx context.Context, bookingID domain.BookingID) (*domain.IncentiveEvaluation, error)
+
+	// ListEvaluations returns the evaluations for the bookings of the user, or of every user when
+	// it is empty, newest first; admins only.
+	ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error)
+}
+
+type incentiveUseCase struct {
+	incentiveRepo repository.IncentiveRepository
+	bookingRepo   repository.BookingRepository
+}
+
+func NewIncentiveUseCase(incentiveRepo repository.IncentiveRepository, bookingRepo repository.BookingRepository) IncentiveUseCase {
+	return &incentiveUseCase{
+		incentiveRepo: incentiveRepo,
+		bookingRepo:   bookingRepo,
+	}
+}
+
+func (u *incentiveUseCase) ListRules(ctx context.Context) ([]*domain.IncentiveRule, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return u.incentiveRepo.ListRules(ctx, false)
+}
+
+func (u *incentiveUseCase) CreateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := validateIncentiveRule(rule); err != nil {
+		return err
+	}
+
+	return u.incentiveRepo.CreateRule(ctx, rule)
+}
+
+func (u *incentiveUseCase) UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := validateIncentiveRule(rule); err != nil {
+		return err
+	}
+
+	return u.incentiveRepo.UpdateRule(ctx, rule)
+}
+
+func validateIncentiveRule(rule *domain.IncentiveRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return fmt.Errorf("%w: a name is required", ErrInvalidIncentiveRule)
+	}
+	if !slices.Contains(domain.IncentiveEffects, rule.Effect) {
+		return fmt.Errorf("%w: unknown effect %q", ErrInvalidIncentiveRule, rule.Effect)
+	}
+	if rule.Effect == domain.IncentiveBonusPoints && rule.Points <= 0 {
+		return fmt.Errorf("%w: a bonus_points rule needs positive points", ErrInvalidIncentiveRule)
+	}
+	if rule.Effect != domain.IncentiveBonusPoints && rule.Points != 0 {
+		return fmt.Errorf("%w: only bonus_points rules have points", ErrInvalidIncentiveRule)
+	}
+	if rule.ValidFrom != nil && rule.ValidUntil != nil && !rule.ValidUntil.After(*rule.ValidFrom) {
+		return fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidIncentiveRule)
+	}
+	if rule.Conditions.MinGuests < 0 {
+		return fmt.Errorf("%w: min_guests cannot be negative", ErrInvalidIncentiveRule)
+	}
+	for _, weekday := range rule.Conditions.Weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return fmt.Errorf("%w: weekdays run from 0 (Sunday) to 6 (Saturday)", ErrInvalidIncentiveRule)
+		}
+	}
+	return nil
+}
+
+func (u *incentiveUseCase) DeleteRule(ctx context.Context, id string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	return u.incentiveRepo.DeleteRule(ctx, id)
+}
+
+func (u *incentiveUseCase) Evaluate(ctx context.Context, booking *domain.Booking, now time.Time) (*domain.IncentiveEvaluation, error) {
+	rules, err := u.incentiveRepo.ListRules(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := u.incentiveRepo.CountPreviousBookings(ctx, booking.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := domain.EvaluateIncentives(rules, domain.NewIncentiveFacts(booking, previous, now))
+	evaluation.EvaluatedAt = now
+	return evaluation, nil
+}
+
+func (u *incentiveUseCase) RecordEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error {
+	return u.incentiveRepo.CreateEvaluation(ctx, evaluation)
+}
+
+func (u *incentiveUseCase) GetBookingIncentives(ctx context.Context, bookingID domain.BookingID) (*domain.IncentiveEvaluation, error) {
+	booking, err := u.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if principal, ok := tenant.FromContext(ctx); ok &&
+		!principal.CanAccessUser(booking.UserID) && !principal.CanAccessRestaurant(booking.RestaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	return u.incentiveRepo.GetEvaluation(ctx, bookingID.String())
+}
+
+func (u *incentiveUseCase) ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return u.incentiveRepo.ListEvaluations(ctx, userID, offset, limit)
+}
+
+type incentiveBookingUseCase struct {
+	BookingUseCase
+	incentives IncentiveUseCase
+	clock      clock.Clock
+}
+
+// NewIncentiveBookingUseCase evaluates the incentive rules for the bookings made through bookings
+// and keeps the evaluations. Incentives never stand in the way of a booking: a failed evaluation
+// is logged and the booking goes ahead without incentives. Test bookings are not evaluated.
+func NewIncentiveBookingUseCase(bookings BookingUseCase, incentives IncentiveUseCase, clock clock.Clock) BookingUseCase {
+	return &incentiveBookingUseCase{
+		BookingUseCase: bookings,
+		incentives:     incentives,
+		clock:          clock,
+	}
+}
+
+func (u *incentiveBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	if booking.IsTest {
+		return u.BookingUseCase.CreateBooking(ctx, booking)
+	}
+
+	log, _ := logger.FromContext(ctx)
+
+	// The facts are gathered first, so that the new booking is not among the previous ones.
+	evaluation, evaluateErr := u.incentives.Evaluate(ctx, booking, u.clock.Now())
+	if evaluateErr != nil {
+		log.Warn(ctx, common.ErrEvaluateIncentives, zap.String("userID", booking.UserID), zap.Error(evaluateErr))
+	}
+
+	id, err := u.BookingUseCase.CreateBooking(ctx, booking)
+	if err != nil || evaluation == nil {
+		return id, err
+	}
+
+	evaluation.BookingID = id
+	if err := u.incentives.RecordEvaluation(ctx, evaluation); err != nil {
+		log.Warn(ctx, common.ErrCreateIncentiveEvaluation, zap.String("bookingID", id), zap.Error(err))
+		return id, nil
+	}
+
+	if evaluation.WaiveDeposit || evaluation.BonusPoints > 0 {
+		log.Info(ctx, "booking incentives granted",
+			zap.String("bookingID", id),
+			zap.Bool("waiveDeposit", evaluation.WaiveDeposit),
+			zap.Int("bonusPoints", evaluation.BonusPoints))
+	}
+	return id, nil
+}
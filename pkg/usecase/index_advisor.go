@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+)
+
+const (
+	DefaultIndexAdvisorMinRows  = 10000
+	DefaultIndexAdvisorMinCalls = 100
+	DefaultIndexAdvisorLimit    = 10
+)
+
+// IndexAdvisorSettings tell which tables and statements are worth reporting. Tables with fewer
+// than MinRows live rows are cheap to scan and statements called fewer than MinCalls times rarely
+// matter; at most Limit of each are reported. Values that are not positive are replaced with the
+// defaults.
+type IndexAdvisorSettings struct {
+	MinRows  int64
+	MinCalls int64
+	Limit    int
+}
+
+// IndexAdvisorUseCase looks through the database statistics for the queries an index is missing
+// for.
+type IndexAdvisorUseCase interface {
+	// AdviseIndexes lists the tables scanned sequentially more often than through an index and the
+	// statements reading the most blocks per row they return. Without pg_stat_statements only the
+	// tables are listed.
+	AdviseIndexes(ctx context.Context, now time.Time) (*domain.IndexAdvice, error)
+}
+
+type indexAdvisorUseCase struct {
+	queryStatsRepo repository.QueryStatsRepository
+	settings       IndexAdvisorSettings
+}
+
+func NewIndexAdvisorUseCase(queryStatsRepo repository.QueryStatsRepository, settings IndexAdvisorSettings) IndexAdvisorUseCase {
+	if settings.MinRows <= 0 {
+		settings.MinRows = DefaultIndexAdvisorMinRows
+	}
+	if settings.MinCalls <= 0 {
+		settings.MinCalls = DefaultIndexAdvisorMinCalls
+	}
+	if settings.Limit <= 0 {
+		settings.Limit = DefaultIndexAdvisorLimit
+	}
+
+	return &indexAdvisorUseCase{
+		queryStatsRepo: queryStatsRepo,
+		settings:       settings,
+	}
+}
+
+func (u *indexAdvisorUseCase) AdviseIndexes(ctx context.Context, now time.Time) (*domain.IndexAdvice, error) {
+	tables, err := u.queryStatsRepo.ListSeqScanTables(ctx, u.settings.MinRows, u.settings.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	advice := &domain.IndexAdvice{
+		Tables:              tables,
+		Queries:             make([]domain.ScanHeavyQuery, 0),
+		StatementsAvailable: true,
+		CheckedAt:           now,
+	}
+
+	queries, err := u.queryStatsRepo.ListScanHeavyQueries(ctx, u.settings.MinCalls, u.settings.Limit)
+	switch {
+	case err == nil:
+		advice.Queries = queries
+	case err.Error() == common.ErrQueryStatsUnavailable:
+		advice.StatementsAvailable = false
+	default:
+		return nil, err
+	}
+
+	return advice, nil
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidMenuItem   = errors.New("invalid menu item")
+	ErrInvalidMenuFilter = errors.New("invalid menu filter")
+	ErrInvalidPreOrder   = errors.New("invalid pre-order")
+	ErrPreOrderClosed    = errors.New("pre-order can no longer be changed")
+)
+
+const maxPreOrderQuantity = 50
+
+type MenuUseCase interface {
+	// GetMenu returns the menu items of a restaurant passing the filter, in menu order. It fails
+	// with ErrInvalidMenuFilter for allergens or dietary labels outside the vocabularies.
+	GetMenu(ctx context.Context, restaurantID string, filter domain.MenuFilter) ([]domain.MenuItem, error)
+
+	// UpdateMenu replaces the menu of a restaurant with the items in the given order. Items keep
+	// their ID when it is given; items left out are removed from the menu but stay in pre-orders.
+	UpdateMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) ([]domain.MenuItem, error)
+
+	// ImportMenu reads menu items from a CSV or JSON file into the menu of a restaurant; items
+	// named like ones on the menu update them. When any item is invalid the report lists the
+	// errors and nothing is saved. It fails with ErrInvalidImportFile for unreadable files.
+	ImportMenu(ctx context.Context, restaurantID string, r io.Reader, options MenuImportOptions) (*MenuImportReport, error)
+
+	// GetPreOrder returns the pre-order of a booking already loaded for the caller.
+	GetPreOrder(ctx context.Context, booking *domain.Booking) (*domain.PreOrder, error)
+
+	// UpdatePreOrder replaces the items pre-ordered for a booking; an empty list clears the
+	// pre-order. It fails with ErrPreOrderClosed once the cutoff before the booking has passed.
+	UpdatePreOrder(ctx context.Context, bookingID domain.BookingID, items []domain.PreOrderItem) (*domain.PreOrder, error)
+}
+
+type menuUseCase struct {
+	menuRepo       repository.MenuRepository
+	restaurantRepo repository.RestaurantRepository
+	bookingRepo    repository.BookingRepository
+	preOrderCutoff time.Duration
+	clock          clock.Clock
+}
+
+// NewMenuUseCase creates the use case; preOrderCutoff is how long before a booking its pre-order
+// stops being editable.
+func NewMenuUseCase(
+	menuRepo repository.MenuRepository,
+	restaurantRepo repository.RestaurantRepository,
+	bookingRepo repository.BookingRepository,
+	preOrderCutoff time.Duration,
+	clock clock.Clock,
+) MenuUseCase {
+	return &menuUseCase{
+		menuRepo:       menuRepo,
+		restaurantRepo: restaurantRepo,
+		bookingRepo:    bookingRepo,
+		preOrderCutoff: preOrderCutoff,
+		clock:          clock,
+	}
+}
+
+func (u *menuUseCase) GetMenu(ctx context.Context, restaurantID string, filter domain.MenuFilter) ([]domain.MenuItem, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMenuFilter, err)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	items, err := u.menuRepo.GetMenu(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]domain.MenuItem, 0, len(items))
+	for _, item := range items {
+		if filter.Matches(&item) {
+			matching = append(matching, item)
+		}
+	}
+	return matching, nil
+}
+
+func (u *menuUseCase) UpdateMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) ([]domain.MenuItem, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	current, err := u.GetMenu(ctx, restaurantID, domain.MenuFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(current))
+	for _, item := range current {
+		known[item.ID] = true
+	}
+
+	seen := make(map[string]bool, len(items))
+	for i := range items {
+		items[i].Name = strings.TrimSpace(items[i].Name)
+		if items[i].Name == "" {
+			return nil, fmt.Errorf("%w: item %d has no name", ErrInvalidMenuItem, i+1)
+		}
+		if items[i].Price < 0 {
+			return nil, fmt.Errorf("%w: %q has a negative price", ErrInvalidMenuItem, items[i].Name)
+		}
+		if items[i].DailyLimit < 0 {
+			return nil, fmt.Errorf("%w: %q has a negative daily limit", ErrInvalidMenuItem, items[i].Name)
+		}
+		if err := items[i].NormalizeTags(); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidMenuItem, items[i].Name, err)
+		}
+		if id := items[i].ID; id != "" {
+			if !known[id] {
+				return nil, fmt.Errorf("%w: %q is not on the menu", ErrInvalidMenuItem, id)
+			}
+			if seen[id] {
+				return nil, fmt.Errorf("%w: %q is listed twice", ErrInvalidMenuItem, id)
+			}
+			seen[id] = true
+		}
+	}
+
+	if err := u.menuRepo.SaveMenu(ctx, restaurantID, items); err != nil {
+		log.Error(ctx, "failed to update menu",
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "menu successfully updated",
+		zap.String("restaurantID", restaurantID),
+		zap.Int("items", len(items)))
+	return u.menuRepo.GetMenu(ctx, restaurantID)
+}
+
+func (u *menuUseCase) GetPreOrder(ctx context.Context, booking *domain.Booking) (*domain.PreOrder, error) {
+	items, err := u.menuRepo.GetPreOrder(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.newPreOrder(booking, items, u.clock.Now()), nil
+}
+
+func (u *menuUseCase) UpdatePreOrder(ctx context.Context, bookingID domain.BookingID, items []domain.PreOrderItem) (*domain.PreOrder, error) {
+	log, _ := logger.FromContext(ctx)
+
+	// GetByID checks that the booking belongs to the caller.
+	booking, err := u.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.newPreOrder(booking, nil, u.clock.Now()).Editable {
+		return nil, ErrPreOrderClosed
+	}
+
+	menu, err := u.menuRepo.GetMenu(ctx, booking.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := priceMenuItems(menu, items)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.menuRepo.SavePreOrder(ctx, booking.ID, ordered); err != nil {
+		log.Error(ctx, "failed to update pre-order",
+			zap.String("bookingID", booking.ID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	preOrder := u.newPreOrder(booking, ordered, u.clock.Now())
+	log.Info(ctx, "pre-order successfully updated",
+		zap.String("bookingID", booking.ID),
+		zap.Int("items", len(ordered)),
+		zap.Int64("estimatedTotal", preOrder.EstimatedTotal))
+	return preOrder, nil
+}
+
+// priceMenuItems checks the items against the menu and returns them with the names and prices of
+// their menu items.
+func priceMenuItems(menu []domain.MenuItem, items []domain.PreOrderItem) ([]domain.PreOrderItem, error) {
+	byID := make(map[string]domain.MenuItem, len(menu))
+	for _, item := range menu {
+		byID[item.ID] = item
+	}
+
+	ordered := make([]domain.PreOrderItem, 0, len(items))
+	for _, item := range items {
+		menuItem, ok := byID[item.MenuItemID]
+		if !ok || !menuItem.IsAvailable {
+			return nil, fmt.Errorf("%w: menu item %q is not available", ErrInvalidPreOrder, item.MenuItemID)
+		}
+		if item.Quantity < 1 || item.Quantity > maxPreOrderQuantity {
+			return nil, fmt.Errorf("%w: quantity of %q must be between 1 and %d", ErrInvalidPreOrder, menuItem.Name, maxPreOrderQuantity)
+		}
+
+		ordered = append(ordered, domain.PreOrderItem{
+			MenuItemID: menuItem.ID,
+			Name:       menuItem.Name,
+			UnitPrice:  menuItem.Price,
+			Currency:   menuItem.Currency,
+			Quantity:   item.Quantity,
+			Notes:      strings.TrimSpace(item.Notes),
+		})
+	}
+	return ordered, nil
+}
+
+// newPreOrder puts together the pre-order of a booking. Pre-orders of bookings that are no longer
+// active, or whose start cannot be told, are never editable.
+func (u *menuUseCase) newPreOrder(booking *domain.Booking, items []domain.PreOrderItem, now time.Time) *domain.PreOrder {
+	if items == nil {
+		items = make([]domain.PreOrderItem, 0)
+	}
+	preOrder := &domain.PreOrder{
+		BookingID: booking.ID,
+		Items:     items,
+	}
+	preOrder.EstimatedTotal = preOrder.Estimate()
+	if len(items) > 0 {
+		preOrder.Currency = items[0].Currency
+	}
+
+	start, err := bookingStart(booking)
+	if err != nil {
+		return preOrder
+	}
+	preOrder.EditableUntil = start.Add(-u.preOrderCutoff)
+
+	active := booking.Status == domain.BookingStatusPending || booking.Status == domain.BookingStatusConfirmed
+	preOrder.Editable = active && now.Before(preOrder.EditableUntil)
+	return preOrder
+}
@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const maxMenuImportRows = 2000
+
+// MenuImportFormat is the format of a menu import file.
+type MenuImportFormat string
+
+const (
+	MenuImportCSV  MenuImportFormat = "csv"
+	MenuImportJSON MenuImportFormat = "json"
+)
+
+// menuImportColumns maps the column names of menu exports to the fields they fill: the names of
+// this API and the ones point-of-sale systems commonly export.
+var menuImportColumns = map[string]string{
+	"name":               "name",
+	"item":               "name",
+	"item name":          "name",
+	"menu item":          "name",
+	"product":            "name",
+	"product name":       "name",
+	"title":              "name",
+	"variation name":     "variation",
+	"variation":          "variation",
+	"description":        "description",
+	"item description":   "description",
+	"details":            "description",
+	"category":           "category",
+	"categories":         "category",
+	"menu group":         "category",
+	"group":              "category",
+	"reporting category": "category",
+	"section":            "category",
+	"price":              "price",
+	"unit price":         "price",
+	"base price":         "price",
+	"amount":             "price",
+	"is_available":       "available",
+	"available":          "available",
+	"active":             "available",
+	"enabled":            "available",
+	"allergens":          "allergens",
+	"dietary_labels":     "dietary_labels",
+	"dietary labels":     "dietary_labels",
+	"diet":               "dietary_labels",
+	"dietary":            "dietary_labels",
+}
+
+// MenuImportOptions say how to read and apply a menu import. With Replace the menu becomes the
+// imported items only, otherwise the items not in the file stay on the menu.
+type MenuImportOptions struct {
+	Format  MenuImportFormat
+	Replace bool
+	DryRun  bool
+}
+
+// MenuImportRowError lists the problems of one item; Row is its line in a CSV file or its position
+// in a JSON list.
+type MenuImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+type MenuImportReport struct {
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Updated int                  `json:"updated"`
+	Removed int                  `json:"removed"`
+	DryRun  bool                 `json:"dry_run"`
+	Errors  []MenuImportRowError `json:"errors,omitempty"`
+}
+
+func (r *MenuImportReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+type menuImportRow struct {
+	row  int
+	item domain.MenuItem
+}
+
+// menuImportJSONItem is an item of a JSON import, shaped like the items of the menu API; prices
+// are in minor units and items are available unless is_available is false.
+type menuImportJSONItem struct {
+	Name          string                `json:"name"`
+	Description   string                `json:"description"`
+	Category      string                `json:"category"`
+	Price         *int64                `json:"price"`
+	IsAvailable   *bool                 `json:"is_available"`
+	Allergens     []domain.Allergen     `json:"allergens"`
+	DietaryLabels []domain.DietaryLabel `json:"dietary_labels"`
+}
+
+// ImportMenu reads menu items from CSV or JSON and adds them to the menu of the restaurant. An
+// item named like one on the menu updates it, keeping its ID. The import is all or nothing: when
+// any item is invalid the report lists the errors and the menu is left as it is.
+func (u *menuUseCase) ImportMenu(ctx context.Context, restaurantID string, r io.Reader, options MenuImportOptions) (*MenuImportReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID))
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []menuImportRow
+	var report *MenuImportReport
+	switch options.Format {
+	case MenuImportCSV:
+		rows, report, err = parseMenuImportCSV(r, restaurant.Currency)
+	case MenuImportJSON:
+		rows, report, err = parseMenuImportJSON(r)
+	default:
+		err = fmt.Errorf("%w: unknown format %q", ErrInvalidImportFile, options.Format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = options.DryRun
+
+	current, err := u.menuRepo.GetMenu(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]int, len(current))
+	for i, item := range current {
+		byName[strings.ToLower(item.Name)] = i
+	}
+	listed := make(map[string]int, len(rows))
+	imported := make(map[int]bool, len(rows))
+	items := make([]domain.MenuItem, 0, len(current)+len(rows))
+	for _, row := range rows {
+		key := strings.ToLower(row.item.Name)
+		if first, ok := listed[key]; ok {
+			report.Errors = append(report.Errors, MenuImportRowError{
+				Row:    row.row,
+				Errors: []string{fmt.Sprintf("name %q is also on row %d", row.item.Name, first)},
+			})
+			continue
+		}
+		listed[key] = row.row
+
+		if i, ok := byName[key]; ok {
+			// Exports carry no daily limits, so the limit set on the menu stays.
+			row.item.ID = current[i].ID
+			row.item.DailyLimit = current[i].DailyLimit
+			imported[i] = true
+			report.Updated++
+		} else {
+			report.Created++
+		}
+		items = append(items, row.item)
+	}
+
+	log.Info(ctx, "importing menu",
+		zap.String("restaurantID", restaurantID),
+		zap.Int("total", report.Total),
+		zap.Int("invalidRows", len(report.Errors)),
+		zap.Bool("dryRun", options.DryRun))
+
+	if report.HasErrors() {
+		report.Created, report.Updated = 0, 0
+		return report, nil
+	}
+
+	if options.Replace {
+		report.Removed = len(current) - len(imported)
+	} else {
+		// The items staying on the menu keep their place ahead of the new ones.
+		kept := make([]domain.MenuItem, 0, len(current)+len(items))
+		for i, item := range current {
+			if !imported[i] {
+				kept = append(kept, item)
+			}
+		}
+		items = append(kept, items...)
+	}
+
+	if options.DryRun {
+		return report, nil
+	}
+
+	if err := u.menuRepo.SaveMenu(ctx, restaurantID, items); err != nil {
+		log.Error(ctx, "failed to import menu",
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "menu successfully imported",
+		zap.String("restaurantID", restaurantID),
+		zap.Int("created", report.Created),
+		zap.Int("updated", report.Updated),
+		zap.Int("removed", report.Removed))
+	return report, nil
+}
+
+// parseMenuImportCSV reads items with their prices in major units of the currency, such as
+// "450.00". The header needs a name and a price column under any of the names of
+// menuImportColumns; allergens and dietary labels are separated by commas, semicolons or bars.
+func parseMenuImportCSV(r io.Reader, currency string) ([]menuImportRow, *MenuImportReport, error) {
+	buffered := bufio.NewReader(r)
+	reader := csv.NewReader(buffered)
+	reader.TrimLeadingSpace = true
+	// Some point-of-sale systems export with semicolons, as spreadsheets do in many locales.
+	start, _ := buffered.Peek(buffered.Size())
+	if header, _, _ := bytes.Cut(start, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := menuImportColumns[name]; ok {
+			if _, taken := columns[field]; !taken {
+				columns[field] = i
+			}
+		}
+	}
+	for _, field := range []string{"name", "price"} {
+		if _, ok := columns[field]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, field)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	report := &MenuImportReport{}
+	rows := make([]menuImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		report.Total++
+		if report.Total > maxMenuImportRows {
+			return nil, nil, ErrImportTooLarge
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+			}
+			report.Errors = append(report.Errors, MenuImportRowError{Row: parseErr.StartLine, Errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		var rowErrors []string
+		item := domain.MenuItem{
+			Name:          field("name"),
+			Description:   field("description"),
+			Category:      field("category"),
+			IsAvailable:   true,
+			Allergens:     splitMenuImportTags[domain.Allergen](field("allergens")),
+			DietaryLabels: splitMenuImportTags[domain.DietaryLabel](field("dietary_labels")),
+		}
+		if variation := field("variation"); variation != "" && !strings.EqualFold(variation, "regular") {
+			item.Name += " (" + variation + ")"
+		}
+
+		if value := field("price"); value == "" {
+			rowErrors = append(rowErrors, "price is required")
+		} else if item.Price, err = domain.ParseAmount(value, currency); err != nil {
+			rowErrors = append(rowErrors, "price "+err.Error())
+		}
+
+		switch strings.ToLower(field("available")) {
+		case "", "true", "yes", "y", "1":
+		case "false", "no", "n", "0":
+			item.IsAvailable = false
+		default:
+			rowErrors = append(rowErrors, "available is not true or false")
+		}
+
+		rowErrors = append(rowErrors, validateMenuImportItem(&item)...)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, MenuImportRowError{Row: line, Errors: rowErrors})
+			continue
+		}
+		rows = append(rows, menuImportRow{row: line, item: item})
+	}
+
+	return rows, report, nil
+}
+
+// parseMenuImportJSON reads a list of items, alone or under "items" as the menu API takes it.
+func parseMenuImportJSON(r io.Reader) ([]menuImportRow, *MenuImportReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	var items []menuImportJSONItem
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var wrapped struct {
+			Items []menuImportJSONItem `json:"items"`
+		}
+		err = json.Unmarshal(trimmed, &wrapped)
+		items = wrapped.Items
+	} else {
+		err = json.Unmarshal(trimmed, &items)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	if len(items) > maxMenuImportRows {
+		return nil, nil, ErrImportTooLarge
+	}
+
+	report := &MenuImportReport{Total: len(items)}
+	rows := make([]menuImportRow, 0, len(items))
+	for i, imported := range items {
+		var rowErrors []string
+		item := domain.MenuItem{
+			Name:          strings.TrimSpace(imported.Name),
+			Description:   strings.TrimSpace(imported.Description),
+			Category:      strings.TrimSpace(imported.Category),
+			IsAvailable:   imported.IsAvailable == nil || *imported.IsAvailable,
+			Allergens:     imported.Allergens,
+			DietaryLabels: imported.DietaryLabels,
+		}
+		if imported.Price == nil {
+			rowErrors = append(rowErrors, "price is required")
+		} else {
+			item.Price = *imported.Price
+		}
+
+		rowErrors = append(rowErrors, validateMenuImportItem(&item)...)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, MenuImportRowError{Row: i + 1, Errors: rowErrors})
+			continue
+		}
+		rows = append(rows, menuImportRow{row: i + 1, item: item})
+	}
+
+	return rows, report, nil
+}
+
+func validateMenuImportItem(item *domain.MenuItem) []string {
+	var rowErrors []string
+	if item.Name == "" {
+		rowErrors = append(rowErrors, "name is required")
+	}
+	if item.Price < 0 {
+		rowErrors = append(rowErrors, "price is negative")
+	}
+	if err := item.NormalizeTags(); err != nil {
+		rowErrors = append(rowErrors, err.Error())
+	}
+	return rowErrors
+}
+
+// splitMenuImportTags splits a list such as "Milk; tree nuts" into vocabulary values like
+// tree_nuts.
+func splitMenuImportTags[T ~string](value string) []T {
+	tags := make([]T, 0)
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+		tag = strings.Join(strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+			return r == ' ' || r == '-' || r == '_'
+		}), "_")
+		if tag != "" {
+			tags = append(tags, T(tag))
+		}
+	}
+	return tags
+}
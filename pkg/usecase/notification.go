@@ -2,9 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
 
 	"go.uber.org/zap"
 )
@@ -17,24 +21,52 @@ type NotificationUseCase interface {
 	GetUserNotifications(ctx context.Context, userID string) ([]domain.Notification, error)
 
 	MarkAsRead(ctx context.Context, notificationID string) error
+
+	ResendNotification(ctx context.Context, notificationID string) error
+
+	// GetNotificationSettings returns the preference of every event type and channel of the recipient,
+	// including the defaults of the ones never changed.
+	GetNotificationSettings(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error)
+
+	// UpdateNotificationSettings replaces the preferences and quiet hours of the recipient and returns the
+	// resulting settings.
+	UpdateNotificationSettings(ctx context.Context, settings *domain.NotificationSettings) (*domain.NotificationSettings, error)
 }
 
+var (
+	ErrInvalidNotificationPreference = errors.New("invalid notification preference")
+	ErrInvalidQuietHours             = errors.New("invalid quiet hours")
+)
+
 type notificationUseCase struct {
 	emailService EmailService
 	notifier     domain.NotificationService
+	settingsRepo repository.NotificationSettingsRepository
+	facts        FactOfTheDayProvider
 }
 
 type EmailService interface {
 	SendEmail(to, subject, body string) error
 }
 
+// FactOfTheDayProvider supplies the fact appended as a footer to notification emails.
+type FactOfTheDayProvider interface {
+	GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error)
+}
+
+// NewNotificationUseCase creates the notification use case; facts may be nil to send emails without the fact footer.
+// Emails are sent regardless of the recipient preferences when settingsRepo is nil.
 func NewNotificationUseCase(
 	emailService EmailService,
 	notifier domain.NotificationService,
+	settingsRepo repository.NotificationSettingsRepository,
+	facts FactOfTheDayProvider,
 ) NotificationUseCase {
 	return &notificationUseCase{
 		emailService: emailService,
 		notifier:     notifier,
+		settingsRepo: settingsRepo,
+		facts:        facts,
 	}
 }
 
@@ -54,13 +86,14 @@ func (u *notificationUseCase) NotifyRestaurant(ctx context.Context, restaurantID
 		return err
 	}
 
-	restaurantEmail := u.getRestaurantEmail(restaurantID)
-
-	if err := u.emailService.SendEmail(restaurantEmail, title, message); err != nil {
-		log.Error(ctx, "failed to send email to restaurant",
-			zap.String("restaurantID", restaurantID),
-			zap.Error(err))
+	if u.emailAllowed(ctx, domain.RecipientTypeRestaurant, restaurantID, notificationType) {
+		restaurantEmail := u.getRestaurantEmail(restaurantID)
 
+		if err := u.emailService.SendEmail(restaurantEmail, title, u.withFactFooter(ctx, message)); err != nil {
+			log.Error(ctx, "failed to send email to restaurant",
+				zap.String("restaurantID", restaurantID),
+				zap.Error(err))
+		}
 	}
 
 	log.Info(ctx, "notification to restaurant successfully sent",
@@ -86,12 +119,14 @@ func (u *notificationUseCase) NotifyUser(ctx context.Context, userID string, not
 		return err
 	}
 
-	userEmail := u.getUserEmail(userID)
+	if u.emailAllowed(ctx, domain.RecipientTypeUser, userID, notificationType) {
+		userEmail := u.getUserEmail(userID)
 
-	if err := u.emailService.SendEmail(userEmail, title, message); err != nil {
-		log.Error(ctx, "failed to send email to user",
-			zap.String("userID", userID),
-			zap.Error(err))
+		if err := u.emailService.SendEmail(userEmail, title, u.withFactFooter(ctx, message)); err != nil {
+			log.Error(ctx, "failed to send email to user",
+				zap.String("userID", userID),
+				zap.Error(err))
+		}
 	}
 
 	log.Info(ctx, "notification to user successfully sent",
@@ -101,6 +136,40 @@ func (u *notificationUseCase) NotifyUser(ctx context.Context, userID string, not
 	return nil
 }
 
+// emailAllowed consults the preferences of the recipient; when they cannot be read the email is sent.
+func (u *notificationUseCase) emailAllowed(ctx context.Context, recipientType domain.RecipientType, recipientID string, notificationType domain.NotificationType) bool {
+	if u.settingsRepo == nil {
+		return true
+	}
+
+	settings, err := u.settingsRepo.Get(ctx, recipientType, recipientID)
+	if err != nil {
+		log, _ := logger.FromContext(ctx)
+		log.Warn(ctx, "failed to get notification settings, sending email anyway",
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", recipientID),
+			zap.Error(err))
+		return true
+	}
+
+	return settings.Allows(notificationType, domain.NotificationChannelEmail)
+}
+
+// withFactFooter appends the fact of the day to the email body. The footer is optional,
+// so any failure to get the fact leaves the body unchanged.
+func (u *notificationUseCase) withFactFooter(ctx context.Context, body string) string {
+	if u.facts == nil {
+		return body
+	}
+
+	fact, err := u.facts.GetFactOfTheDay(ctx, domain.DefaultFactLocale)
+	if err != nil || fact == nil {
+		return body
+	}
+
+	return body + "\n\n---\nFact of the day: " + fact.Content
+}
+
 func (u *notificationUseCase) getUserEmail(userID string) string {
 
 	return userID + "@example.com"
@@ -146,3 +215,147 @@ func (u *notificationUseCase) MarkAsRead(ctx context.Context, notificationID str
 		zap.String("notificationID", notificationID))
 	return nil
 }
+
+// ResendNotification sends the email of an already stored notification once more,
+// e.g. after the recipient reported it missing.
+func (u *notificationUseCase) ResendNotification(ctx context.Context, notificationID string) error {
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "resending notification",
+		zap.String("notificationID", notificationID))
+
+	notification, err := u.notifier.GetNotification(ctx, notificationID)
+	if err != nil {
+		log.Error(ctx, "failed to get notification for resend",
+			zap.String("notificationID", notificationID),
+			zap.Error(err))
+		return err
+	}
+
+	email := u.getUserEmail(notification.RecipientID)
+	if notification.RecipientType == domain.RecipientTypeRestaurant {
+		email = u.getRestaurantEmail(notification.RecipientID)
+	}
+
+	if err := u.emailService.SendEmail(email, notification.Title, u.withFactFooter(ctx, notification.Message)); err != nil {
+		log.Error(ctx, "failed to resend notification email",
+			zap.String("notificationID", notificationID),
+			zap.Error(err))
+		return err
+	}
+
+	log.Info(ctx, "notification successfully resent",
+		zap.String("notificationID", notificationID),
+		zap.String("recipientType", string(notification.RecipientType)),
+		zap.String("recipientID", notification.RecipientID))
+	return nil
+}
+
+func (u *notificationUseCase) GetNotificationSettings(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	log, _ := logger.FromContext(ctx)
+
+	stored, err := u.settingsRepo.Get(ctx, recipientType, recipientID)
+	if err != nil {
+		log.Error(ctx, "failed to get notification settings",
+			zap.String("recipientType", string(recipientType)),
+			zap.String("recipientID", recipientID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return completeNotificationSettings(stored), nil
+}
+
+func (u *notificationUseCase) UpdateNotificationSettings(ctx context.Context, settings *domain.NotificationSettings) (*domain.NotificationSettings, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if err := validateNotificationPreferences(settings.Preferences); err != nil {
+		return nil, err
+	}
+	if err := validateQuietHours(settings.QuietHours); err != nil {
+		return nil, err
+	}
+
+	if err := u.settingsRepo.Save(ctx, settings); err != nil {
+		log.Error(ctx, "failed to update notification settings",
+			zap.String("recipientType", string(settings.RecipientType)),
+			zap.String("recipientID", settings.RecipientID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	log.Info(ctx, "notification settings successfully updated",
+		zap.String("recipientType", string(settings.RecipientType)),
+		zap.String("recipientID", settings.RecipientID),
+		zap.Int("preferences", len(settings.Preferences)),
+		zap.Int("quietHours", len(settings.QuietHours)))
+	return completeNotificationSettings(settings), nil
+}
+
+func validateNotificationPreferences(preferences []domain.NotificationPreference) error {
+	type key struct {
+		notificationType domain.NotificationType
+		channel          domain.NotificationChannel
+	}
+	seen := make(map[key]bool, len(preferences))
+
+	for _, p := range preferences {
+		if !slices.Contains(domain.NotificationTypes, p.Type) {
+			return fmt.Errorf("%w: unknown type %q", ErrInvalidNotificationPreference, p.Type)
+		}
+		if !slices.Contains(domain.NotificationChannels, p.Channel) {
+			return fmt.Errorf("%w: unknown channel %q", ErrInvalidNotificationPreference, p.Channel)
+		}
+
+		k := key{p.Type, p.Channel}
+		if seen[k] {
+			return fmt.Errorf("%w: %s over %s is listed twice", ErrInvalidNotificationPreference, p.Type, p.Channel)
+		}
+		seen[k] = true
+	}
+
+	return nil
+}
+
+// validateQuietHours allows one set of quiet hours per channel.
+func validateQuietHours(quietHours []domain.QuietHours) error {
+	seen := make(map[domain.NotificationChannel]bool, len(quietHours))
+
+	for i := range quietHours {
+		if err := quietHours[i].Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidQuietHours, err)
+		}
+
+		if seen[quietHours[i].Channel] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidQuietHours, quietHours[i].Channel)
+		}
+		seen[quietHours[i].Channel] = true
+	}
+
+	return nil
+}
+
+// completeNotificationSettings lists every event type and channel, so clients can render
+// the whole preference center without knowing the defaults.
+func completeNotificationSettings(settings *domain.NotificationSettings) *domain.NotificationSettings {
+	complete := &domain.NotificationSettings{
+		RecipientType: settings.RecipientType,
+		RecipientID:   settings.RecipientID,
+		Preferences:   make([]domain.NotificationPreference, 0, len(domain.NotificationTypes)*len(domain.NotificationChannels)),
+		QuietHours:    slices.Clone(settings.QuietHours),
+	}
+	if complete.QuietHours == nil {
+		complete.QuietHours = make([]domain.QuietHours, 0)
+	}
+
+	for _, notificationType := range domain.NotificationTypes {
+		for _, channel := range domain.NotificationChannels {
+			complete.Preferences = append(complete.Preferences, domain.NotificationPreference{
+				Type:    notificationType,
+				Channel: channel,
+				Enabled: settings.Allows(notificationType, channel),
+			})
+		}
+	}
+
+	return complete
+}
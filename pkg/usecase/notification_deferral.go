@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// deferredNotificationLease is how long a claimed deferred notification is left to its delivery
+// before another run may claim it again; a delivery that fails is tried again after it.
+const deferredNotificationLease = 5 * time.Minute
+
+// DeferredDeliveryReport sums up a delivery run: the notifications claimed, those delivered, those
+// deferred again by quiet hours still holding, and those dropped because the recipient turned the
+// channel off or has no address for it.
+type DeferredDeliveryReport struct {
+	Claimed   int
+	Delivered int
+	Deferred  int
+	Dropped   int
+}
+
+// NotificationDeferralUseCase holds back the notifications that can wait during the quiet hours of
+// their recipient and delivers them once the quiet hours end.
+type NotificationDeferralUseCase interface {
+	// Defer keeps the notification for later and reports true when the quiet hours in settings hold
+	// back its type over its channel now; it reports false for a notification to deliver right away.
+	Defer(ctx context.Context, settings *domain.NotificationSettings, notification *domain.DeferredNotification) (bool, error)
+
+	// DeliverDue delivers up to limit deferred notifications that are due, checking the settings of
+	// the recipient again first.
+	DeliverDue(ctx context.Context, limit int) (DeferredDeliveryReport, error)
+}
+
+type notificationDeferralUseCase struct {
+	deferredRepo   repository.DeferredNotificationRepository
+	settingsRepo   repository.NotificationSettingsRepository
+	userRepo       repository.UserRepository
+	restaurantRepo repository.RestaurantRepository
+	notifier       domain.NotificationService
+	emailService   EmailService
+	sms            domain.SMSSender
+	clock          clock.Clock
+}
+
+// NewNotificationDeferralUseCase creates the deferral use case; notifier delivers the in-app
+// notifications and must not defer them again.
+func NewNotificationDeferralUseCase(
+	deferredRepo repository.DeferredNotificationRepository,
+	settingsRepo repository.NotificationSettingsRepository,
+	userRepo repository.UserRepository,
+	restaurantRepo repository.RestaurantRepository,
+	notifier domain.NotificationService,
+	emailService EmailService,
+	sms domain.SMSSender,
+	clock clock.Clock,
+) NotificationDeferralUseCase {
+	return &notificationDeferralUseCase{
+		deferredRepo:   deferredRepo,
+		settingsRepo:   settingsRepo,
+		userRepo:       userRepo,
+		restaurantRepo: restaurantRepo,
+		notifier:       notifier,
+		emailService:   emailService,
+		sms:            sms,
+		clock:          clock,
+	}
+}
+
+func (u *notificationDeferralUseCase) Defer(
+	ctx context.Context,
+	settings *domain.NotificationSettings,
+	notification *domain.DeferredNotification,
+) (bool, error) {
+	until, ok := settings.DeferUntil(notification.NotificationType, notification.Channel, u.clock.Now())
+	if !ok {
+		return false, nil
+	}
+
+	notification.RecipientType = settings.RecipientType
+	notification.RecipientID = settings.RecipientID
+	notification.DeliverAt = until
+	if err := u.deferredRepo.Create(ctx, notification); err != nil {
+		return false, err
+	}
+
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Debug(ctx, "notification deferred by quiet hours",
+			zap.String("recipientType", string(notification.RecipientType)),
+			zap.String("recipientID", notification.RecipientID),
+			zap.String("type", string(notification.NotificationType)),
+			zap.String("channel", string(notification.Channel)),
+			zap.Time("deliverAt", until))
+	}
+	return true, nil
+}
+
+func (u *notificationDeferralUseCase) DeliverDue(ctx context.Context, limit int) (DeferredDeliveryReport, error) {
+	now := u.clock.Now()
+	notifications, err := u.deferredRepo.ClaimDue(ctx, now, now.Add(deferredNotificationLease), limit)
+	if err != nil {
+		return DeferredDeliveryReport{}, err
+	}
+
+	var (
+		report DeferredDeliveryReport
+		errs   []error
+	)
+	for _, notification := range notifications {
+		report.Claimed++
+
+		settings, err := u.settingsRepo.Get(ctx, notification.RecipientType, notification.RecipientID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if !settings.Allows(notification.NotificationType, notification.Channel) {
+			report.Dropped++
+			if err := u.deferredRepo.Delete(ctx, notification.ID); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if until, ok := settings.DeferUntil(notification.NotificationType, notification.Channel, now); ok {
+			report.Deferred++
+			notification.DeliverAt = until
+			if err := u.deferredRepo.Update(ctx, notification); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		delivered, err := u.deliver(ctx, notification)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if delivered {
+			report.Delivered++
+		} else {
+			report.Dropped++
+		}
+		if err := u.deferredRepo.Delete(ctx, notification.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// deliver sends the notification over its channel and reports false when the recipient has no
+// address for the channel.
+func (u *notificationDeferralUseCase) deliver(ctx context.Context, notification *domain.DeferredNotification) (bool, error) {
+	if notification.Channel == domain.NotificationChannelInApp {
+		var err error
+		if notification.RecipientType == domain.RecipientTypeRestaurant {
+			err = u.notifier.NotifyRestaurant(ctx, notification.RecipientID, notification.NotificationType,
+				notification.Title, notification.Message, notification.RelatedID)
+		} else {
+			err = u.notifier.NotifyUser(ctx, notification.RecipientID, notification.NotificationType,
+				notification.Title, notification.Message, notification.RelatedID)
+		}
+		return err == nil, err
+	}
+
+	email, phone, err := u.contact(ctx, notification.RecipientType, notification.RecipientID)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case notification.Channel == domain.NotificationChannelEmail && email != "":
+		err = u.emailService.SendEmail(email, notification.Title, notification.Message)
+	case notification.Channel == domain.NotificationChannelSMS && phone != "":
+		err = u.sms.SendSMS(phone, notification.Message)
+	default:
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// contact returns the current email and phone of the recipient, so that a deferred notification
+// follows a change of address.
+func (u *notificationDeferralUseCase) contact(ctx context.Context, recipientType domain.RecipientType, recipientID string) (string, string, error) {
+	if recipientType == domain.RecipientTypeRestaurant {
+		restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(recipientID))
+		if err != nil {
+			return "", "", err
+		}
+		return restaurant.ContactEmail, restaurant.ContactPhone, nil
+	}
+
+	user, err := u.userRepo.GetByID(ctx, domain.UserID(recipientID))
+	if err != nil {
+		return "", "", err
+	}
+	return user.Email, user.Phone, nil
+}
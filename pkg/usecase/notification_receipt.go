@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+// NotificationReceiptUseCase records when notifications are read and reports their delivery, so a
+// restaurant can verify its guest saw the confirmation of a booking.
+type NotificationReceiptUseCase interface {
+	// TrackRead marks a notification read when its tracking pixel is loaded. The pixel is fetched
+	// without credentials, so the random notification ID is all it takes.
+	TrackRead(ctx context.Context, notificationID string) error
+
+	// ConfirmRead is the read callback of the recipient; it returns the notification as read.
+	ConfirmRead(ctx context.Context, notificationID string) (*domain.Notification, error)
+
+	// GetBookingDeliveries returns the receipts of the notifications about a booking already loaded
+	// for the caller, oldest first. Staff of the restaurant see the receipts of both the guest and
+	// the restaurant, while the guest only sees their own.
+	GetBookingDeliveries(ctx context.Context, booking *domain.Booking) ([]domain.NotificationDelivery, error)
+}
+
+type notificationReceiptUseCase struct {
+	receiptRepo repository.NotificationReceiptRepository
+	clock       clock.Clock
+}
+
+func NewNotificationReceiptUseCase(receiptRepo repository.NotificationReceiptRepository, clock clock.Clock) NotificationReceiptUseCase {
+	return &notificationReceiptUseCase{
+		receiptRepo: receiptRepo,
+		clock:       clock,
+	}
+}
+
+func (u *notificationReceiptUseCase) TrackRead(ctx context.Context, notificationID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	if err := u.receiptRepo.MarkRead(ctx, notificationID, u.clock.Now()); err != nil {
+		return err
+	}
+
+	log.Debug(ctx, "notification read tracked", zap.String("notificationID", notificationID))
+	return nil
+}
+
+func (u *notificationReceiptUseCase) ConfirmRead(ctx context.Context, notificationID string) (*domain.Notification, error) {
+	log, _ := logger.FromContext(ctx)
+
+	// GetByID checks that the notification is addressed to the caller.
+	notification, err := u.receiptRepo.GetByID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+
+	readAt := u.clock.Now()
+	if err := u.receiptRepo.MarkRead(ctx, notificationID, readAt); err != nil {
+		log.Error(ctx, "failed to confirm notification read",
+			zap.String("notificationID", notificationID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	notification.IsRead = true
+	if notification.ReadAt == nil {
+		notification.ReadAt = &readAt
+	}
+	return notification, nil
+}
+
+func (u *notificationReceiptUseCase) GetBookingDeliveries(ctx context.Context, booking *domain.Booking) ([]domain.NotificationDelivery, error) {
+	log, _ := logger.FromContext(ctx)
+
+	notifications, err := u.receiptRepo.GetByRelatedID(ctx, booking.ID)
+	if err != nil {
+		log.Error(ctx, "failed to get booking notifications",
+			zap.String("bookingID", booking.ID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	principal, ok := tenant.FromContext(ctx)
+	guestOnly := ok && !principal.CanAccessRestaurant(booking.RestaurantID)
+
+	deliveries := make([]domain.NotificationDelivery, 0, len(notifications))
+	for i := range notifications {
+		if guestOnly && notifications[i].RecipientType != domain.RecipientTypeUser {
+			continue
+		}
+		deliveries = append(deliveries, notifications[i].Delivery())
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidNotificationFailureStatus = errors.New("invalid notification failure status")
+
+// notificationFailureLease is how long a claimed failure is left to its retry before another run
+// may claim it again.
+const notificationFailureLease = 5 * time.Minute
+
+// notificationFailureMetrics are published under "notification_failures" on the diagnostics port:
+// the failures recorded, the retries made, and the notifications delivered by a retry and given up.
+var notificationFailureMetrics = expvar.NewMap("notification_failures")
+
+// NotificationRetryPolicy sets how failed notifications are retried: up to MaxAttempts deliveries
+// in all, the first one included, waiting Backoff after the first failure and twice as long after
+// each further one, at most MaxBackoff.
+type NotificationRetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// NotificationRetryReport sums up a retry run.
+type NotificationRetryReport struct {
+	Retried   int
+	Delivered int
+	Exhausted int
+}
+
+// NotificationRetryUseCase keeps the notifications that could not be delivered and retries them.
+type NotificationRetryUseCase interface {
+	// RecordFailure keeps a notification whose delivery failed with cause for a retry.
+	RecordFailure(ctx context.Context, failure *domain.NotificationFailure, cause error) error
+
+	// RetryDue delivers up to limit failed notifications whose next attempt is due. A notification
+	// failing its last attempt is given up.
+	RetryDue(ctx context.Context, limit int) (NotificationRetryReport, error)
+
+	// ListFailures returns the failures with the status, or of every status when it is empty,
+	// newest first; admins only.
+	ListFailures(ctx context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error)
+
+	// CountFailures returns the number of failures of every status; admins only.
+	CountFailures(ctx context.Context) (map[domain.NotificationFailureStatus]int, error)
+}
+
+type notificationRetryUseCase struct {
+	failureRepo repository.NotificationFailureRepository
+	notifier    domain.NotificationService
+	policy      NotificationRetryPolicy
+	clock       clock.Clock
+}
+
+// NewNotificationRetryUseCase creates the retry use case; notifier delivers the retries and must
+// not record their failures again.
+func NewNotificationRetryUseCase(
+	failureRepo repository.NotificationFailureRepository,
+	notifier domain.NotificationService,
+	policy NotificationRetryPolicy,
+	clock clock.Clock,
+) NotificationRetryUseCase {
+	return &notificationRetryUseCase{
+		failureRepo: failureRepo,
+		notifier:    notifier,
+		policy:      policy,
+		clock:       clock,
+	}
+}
+
+func (u *notificationRetryUseCase) RecordFailure(ctx context.Context, failure *domain.NotificationFailure, cause error) error {
+	failure.Cause = cause.Error()
+	failure.Attempts = 0
+	u.scheduleRetry(failure, u.clock.Now())
+
+	if err := u.failureRepo.Create(ctx, failure); err != nil {
+		return err
+	}
+
+	notificationFailureMetrics.Add("recorded", 1)
+	if failure.Status == domain.NotificationFailureStatusExhausted {
+		notificationFailureMetrics.Add("exhausted", 1)
+	}
+	return nil
+}
+
+func (u *notificationRetryUseCase) RetryDue(ctx context.Context, limit int) (NotificationRetryReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	now := u.clock.Now()
+	failures, err := u.failureRepo.ClaimDue(ctx, now, now.Add(notificationFailureLease), limit)
+	if err != nil {
+		return NotificationRetryReport{}, err
+	}
+
+	var (
+		report NotificationRetryReport
+		errs   []error
+	)
+	for _, failure := range failures {
+		report.Retried++
+		notificationFailureMetrics.Add("retried", 1)
+
+		if err := u.deliver(ctx, failure); err != nil {
+			failure.Cause = err.Error()
+			u.scheduleRetry(failure, u.clock.Now())
+		} else {
+			failure.Attempts++
+			failure.Status = domain.NotificationFailureStatusDelivered
+			failure.NextAttemptAt = nil
+		}
+
+		switch failure.Status {
+		case domain.NotificationFailureStatusDelivered:
+			report.Delivered++
+			notificationFailureMetrics.Add("delivered", 1)
+		case domain.NotificationFailureStatusExhausted:
+			report.Exhausted++
+			notificationFailureMetrics.Add("exhausted", 1)
+			log.Warn(ctx, "notification given up after failed retries",
+				zap.String("failureID", failure.ID),
+				zap.String("recipientType", string(failure.RecipientType)),
+				zap.String("recipientID", failure.RecipientID),
+				zap.Int("attempts", failure.Attempts),
+				zap.String("cause", failure.Cause))
+		}
+
+		if err := u.failureRepo.Update(ctx, failure); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return report, errors.Join(errs...)
+}
+
+func (u *notificationRetryUseCase) deliver(ctx context.Context, failure *domain.NotificationFailure) error {
+	if failure.RecipientType == domain.RecipientTypeRestaurant {
+		return u.notifier.NotifyRestaurant(ctx, failure.RecipientID, failure.NotificationType,
+			failure.Title, failure.Message, failure.RelatedID)
+	}
+	return u.notifier.NotifyUser(ctx, failure.RecipientID, failure.NotificationType,
+		failure.Title, failure.Message, failure.RelatedID)
+}
+
+// scheduleRetry counts a failed attempt and schedules the next one, or gives the notification up
+// after the last attempt.
+func (u *notificationRetryUseCase) scheduleRetry(failure *domain.NotificationFailure, now time.Time) {
+	failure.Attempts++
+	if failure.Attempts >= u.policy.MaxAttempts {
+		failure.Status = domain.NotificationFailureStatusExhausted
+		failure.NextAttemptAt = nil
+		return
+	}
+
+	backoff := u.policy.Backoff
+	for i := 1; i < failure.Attempts; i++ {
+		backoff *= 2
+		if u.policy.MaxBackoff > 0 && backoff >= u.policy.MaxBackoff {
+			backoff = u.policy.MaxBackoff
+			break
+		}
+	}
+
+	next := now.Add(backoff)
+	failure.Status = domain.NotificationFailureStatusPending
+	failure.NextAttemptAt = &next
+}
+
+func (u *notificationRetryUseCase) ListFailures(
+	ctx context.Context,
+	status domain.NotificationFailureStatus,
+	offset, limit int,
+) ([]*domain.NotificationFailure, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if status != "" && !slices.Contains(domain.NotificationFailureStatuses, status) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNotificationFailureStatus, status)
+	}
+
+	return u.failureRepo.List(ctx, status, offset, limit)
+}
+
+func (u *notificationRetryUseCase) CountFailures(ctx context.Context) (map[domain.NotificationFailureStatus]int, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	counts, err := u.failureRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range domain.NotificationFailureStatuses {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+)
+
+// maxSisterRestaurants bounds the sister restaurants listed for a restaurant.
+const maxSisterRestaurants = 100
+
+var ErrInvalidOrganization = errors.New("invalid organization")
+
+// OrganizationUseCase groups sister restaurants of one owner into organizations, within which
+// bookings can be transferred.
+type OrganizationUseCase interface {
+	// CreateOrganization creates an organization without restaurants. Admins only.
+	CreateOrganization(ctx context.Context, name string) (*domain.Organization, error)
+
+	// AddRestaurant moves the restaurant into the organization. Admins only.
+	AddRestaurant(ctx context.Context, organizationID, restaurantID string) error
+
+	// ListSisterRestaurants returns the other restaurants of the restaurant's organization, none
+	// when it belongs to none. Restaurant staff only.
+	ListSisterRestaurants(ctx context.Context, restaurantID string) ([]*domain.Restaurant, error)
+}
+
+type organizationUseCase struct {
+	organizationRepo repository.OrganizationRepository
+	restaurantRepo   repository.RestaurantRepository
+}
+
+func NewOrganizationUseCase(organizationRepo repository.OrganizationRepository, restaurantRepo repository.RestaurantRepository) OrganizationUseCase {
+	return &organizationUseCase{
+		organizationRepo: organizationRepo,
+		restaurantRepo:   restaurantRepo,
+	}
+}
+
+func (u *organizationUseCase) CreateOrganization(ctx context.Context, name string) (*domain.Organization, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidOrganization)
+	}
+
+	organization := &domain.Organization{Name: name}
+	if err := u.organizationRepo.Create(ctx, organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+func (u *organizationUseCase) AddRestaurant(ctx context.Context, organizationID, restaurantID string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+	return u.organizationRepo.AddRestaurant(ctx, organizationID, restaurantID)
+}
+
+func (u *organizationUseCase) ListSisterRestaurants(ctx context.Context, restaurantID string) ([]*domain.Restaurant, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	return u.restaurantRepo.ListSisters(ctx, domain.RestaurantID(restaurantID), 0, maxSisterRestaurants)
+}
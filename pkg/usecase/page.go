@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var ErrInvalidPageCursor = errors.New("invalid page cursor")
+
+// PageRequest asks for up to Limit items of a list after Cursor, the NextCursor of the previous
+// page; an empty cursor starts from the first item. A limit out of range is replaced with
+// DefaultPageLimit or capped at MaxPageLimit.
+//
+// Pages are read after the position of the cursor instead of skipping rows, so deep pages cost as
+// much as the first one and items added or removed meanwhile neither repeat nor go missing.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// PageResponse is a page of a list. NextCursor continues after its last item; it is empty on the
+// last page.
+type PageResponse[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// HasMore reports whether another page follows.
+func (p *PageResponse[T]) HasMore() bool {
+	return p.NextCursor != ""
+}
+
+// Size is the most items the page holds: Limit brought into range.
+func (p PageRequest) Size() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageLimit
+	case p.Limit > MaxPageLimit:
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+// decodeCursor returns the position an opaque cursor of newPage stands for, the zero position for
+// an empty cursor.
+func decodeCursor[C interface{ IsZero() bool }](cursor string) (C, error) {
+	var position C
+	if cursor == "" {
+		return position, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return position, ErrInvalidPageCursor
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&position); err != nil || position.IsZero() {
+		return position, ErrInvalidPageCursor
+	}
+	return position, nil
+}
+
+// newPage makes a page of the items read with one more than limit, which tells whether another
+// page follows; its cursor is the position after the last item of the page.
+func newPage[T any, C any](items []T, limit int, cursorAfter func(T) C) (*PageResponse[T], error) {
+	page := &PageResponse[T]{Items: items}
+	if len(items) <= limit {
+		return page, nil
+	}
+
+	page.Items = items[:limit]
+	cursor, err := encodeCursor(cursorAfter(page.Items[limit-1]))
+	if err != nil {
+		return nil, err
+	}
+	page.NextCursor = cursor
+	return page, nil
+}
+
+// encodeCursor returns the opaque cursor decodeCursor reads the position back from.
+func encodeCursor(position any) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
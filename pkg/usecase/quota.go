@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQuotaExceeded     = errors.New("quota exceeded")
+	ErrInvalidPlan       = errors.New("invalid plan")
+	ErrInvalidQuotaLimit = errors.New("invalid quota limit")
+)
+
+// QuotaExceededError is returned when a restaurant has used up a quota of its plan.
+type QuotaExceededError struct {
+	Resource domain.QuotaResource
+	Plan     domain.Plan
+	Limit    int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %d reached on the %s plan", ErrQuotaExceeded, e.Resource, e.Limit, e.Plan)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// QuotaUseCase keeps the plans of restaurants and the quotas that come with them. Quotas are soft:
+// they are checked before a change, so concurrent changes may overshoot them a little, and what a
+// restaurant has beyond a lowered limit is kept.
+type QuotaUseCase interface {
+	// GetQuota returns the plan of the restaurant with its limits and usage at now, for its staff.
+	GetQuota(ctx context.Context, restaurantID string, now time.Time) (*domain.Quota, error)
+
+	// SetPlan moves the restaurant to a plan, with the limits overriding those of the plan; admins
+	// only.
+	SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error
+
+	// CheckBooking fails with *QuotaExceededError when the restaurant has used up the bookings of
+	// the month of now.
+	CheckBooking(ctx context.Context, restaurantID string, now time.Time) error
+
+	// LimitActiveSlots runs fn in a transaction and rolls it back with *QuotaExceededError when it
+	// leaves the restaurant with more active slots than its limit and than it had before.
+	LimitActiveSlots(ctx context.Context, restaurantID string, now time.Time, fn func(ctx context.Context) error) error
+}
+
+type quotaUseCase struct {
+	quotaRepo      repository.QuotaRepository
+	restaurantRepo repository.RestaurantRepository
+	transactor     repository.Transactor
+	plans          map[domain.Plan]domain.QuotaLimits
+}
+
+// NewQuotaUseCase creates the use case with the limits of every plan; plans missing from plans
+// are unlimited.
+func NewQuotaUseCase(
+	quotaRepo repository.QuotaRepository,
+	restaurantRepo repository.RestaurantRepository,
+	transactor repository.Transactor,
+	plans map[domain.Plan]domain.QuotaLimits,
+) QuotaUseCase {
+	return &quotaUseCase{
+		quotaRepo:      quotaRepo,
+		restaurantRepo: restaurantRepo,
+		transactor:     transactor,
+		plans:          plans,
+	}
+}
+
+func (u *quotaUseCase) GetQuota(ctx context.Context, restaurantID string, now time.Time) (*domain.Quota, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	plan, err := u.quotaRepo.GetPlan(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	activeSlots, err := u.quotaRepo.CountActiveSlots(ctx, restaurantID, truncateToDate(now))
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := bookingMonth(now)
+	monthlyBookings, err := u.quotaRepo.CountBookings(ctx, restaurantID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Quota{
+		Plan:   plan,
+		Limits: plan.Limits(u.plans[plan.Plan]),
+		Usage: domain.QuotaUsage{
+			ActiveSlots:     activeSlots,
+			MonthlyBookings: monthlyBookings,
+		},
+	}, nil
+}
+
+func (u *quotaUseCase) SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if !slices.Contains(domain.Plans, plan.Plan) {
+		return fmt.Errorf("%w: %q", ErrInvalidPlan, plan.Plan)
+	}
+	if (plan.ActiveSlots != nil && *plan.ActiveSlots < 0) || (plan.MonthlyBookings != nil && *plan.MonthlyBookings < 0) {
+		return fmt.Errorf("%w: limits cannot be negative", ErrInvalidQuotaLimit)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(plan.RestaurantID)); err != nil {
+		return err
+	}
+
+	if err := u.quotaRepo.SetPlan(ctx, plan); err != nil {
+		return err
+	}
+
+	log, _ := logger.FromContext(ctx)
+	log.Info(ctx, "restaurant plan set",
+		zap.String("restaurantID", plan.RestaurantID),
+		zap.String("plan", string(plan.Plan)),
+		zap.String("note", plan.Note))
+	return nil
+}
+
+func (u *quotaUseCase) CheckBooking(ctx context.Context, restaurantID string, now time.Time) error {
+	plan, limits, err := u.limits(ctx, restaurantID)
+	if err != nil || limits.MonthlyBookings == 0 {
+		return err
+	}
+
+	from, to := bookingMonth(now)
+	count, err := u.quotaRepo.CountBookings(ctx, restaurantID, from, to)
+	if err != nil {
+		return err
+	}
+
+	if count >= limits.MonthlyBookings {
+		return &QuotaExceededError{
+			Resource: domain.QuotaMonthlyBookings,
+			Plan:     plan.Plan,
+			Limit:    limits.MonthlyBookings,
+		}
+	}
+	return nil
+}
+
+func (u *quotaUseCase) LimitActiveSlots(ctx context.Context, restaurantID string, now time.Time, fn func(ctx context.Context) error) error {
+	plan, limits, err := u.limits(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+	if limits.ActiveSlots == 0 {
+		return fn(ctx)
+	}
+
+	today := truncateToDate(now)
+	return u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		before, err := u.quotaRepo.CountActiveSlots(ctx, restaurantID, today)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(ctx); err != nil {
+			return err
+		}
+
+		after, err := u.quotaRepo.CountActiveSlots(ctx, restaurantID, today)
+		if err != nil {
+			return err
+		}
+
+		if after > limits.ActiveSlots && after > before {
+			return &QuotaExceededError{
+				Resource: domain.QuotaActiveSlots,
+				Plan:     plan.Plan,
+				Limit:    limits.ActiveSlots,
+			}
+		}
+		return nil
+	})
+}
+
+func (u *quotaUseCase) limits(ctx context.Context, restaurantID string) (*domain.RestaurantPlan, domain.QuotaLimits, error) {
+	plan, err := u.quotaRepo.GetPlan(ctx, restaurantID)
+	if err != nil {
+		return nil, domain.QuotaLimits{}, err
+	}
+	return plan, plan.Limits(u.plans[plan.Plan]), nil
+}
+
+// bookingMonth is the calendar month of now that monthly booking quotas count.
+func bookingMonth(now time.Time) (time.Time, time.Time) {
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+type quotaLimitedAvailabilityUseCase struct {
+	AvailabilityUseCase
+	quotas QuotaUseCase
+	clock  clock.Clock
+}
+
+// NewQuotaLimitedAvailabilityUseCase keeps the slots set and generated through availability within
+// the active slot quota of the restaurant.
+func NewQuotaLimitedAvailabilityUseCase(availability AvailabilityUseCase, quotas QuotaUseCase, clock clock.Clock) AvailabilityUseCase {
+	return &quotaLimitedAvailabilityUseCase{
+		AvailabilityUseCase: availability,
+		quotas:              quotas,
+		clock:               clock,
+	}
+}
+
+func (u *quotaLimitedAvailabilityUseCase) SetAvailability(ctx context.Context, availability *domain.Availability) error {
+	return u.quotas.LimitActiveSlots(ctx, availability.RestaurantID, u.clock.Now(), func(ctx context.Context) error {
+		return u.AvailabilityUseCase.SetAvailability(ctx, availability)
+	})
+}
+
+func (u *quotaLimitedAvailabilityUseCase) ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	var impacted []*domain.Booking
+	err := u.quotas.LimitActiveSlots(ctx, availability.RestaurantID, u.clock.Now(), func(ctx context.Context) error {
+		var err error
+		impacted, err = u.AvailabilityUseCase.ForceSetAvailability(ctx, availability)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return impacted, nil
+}
+
+func (u *quotaLimitedAvailabilityUseCase) GenerateAvailability(ctx context.Context, params GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	if params.DryRun {
+		return u.AvailabilityUseCase.GenerateAvailability(ctx, params)
+	}
+
+	var generated []*domain.Availability
+	err := u.quotas.LimitActiveSlots(ctx, params.RestaurantID, u.clock.Now(), func(ctx context.Context) error {
+		var err error
+		generated, err = u.AvailabilityUseCase.GenerateAvailability(ctx, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return generated, nil
+}
+
+type quotaLimitedBookingUseCase struct {
+	BookingUseCase
+	quotas QuotaUseCase
+	clock  clock.Clock
+}
+
+// NewQuotaLimitedBookingUseCase rejects the bookings made through bookings once the restaurant has
+// used up its monthly booking quota. Test bookings are not limited.
+func NewQuotaLimitedBookingUseCase(bookings BookingUseCase, quotas QuotaUseCase, clock clock.Clock) BookingUseCase {
+	return &quotaLimitedBookingUseCase{
+		BookingUseCase: bookings,
+		quotas:         quotas,
+		clock:          clock,
+	}
+}
+
+func (u *quotaLimitedBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	if !booking.IsTest {
+		if err := u.quotas.CheckBooking(ctx, booking.RestaurantID, u.clock.Now()); err != nil {
+			return "", err
+		}
+	}
+	return u.BookingUseCase.CreateBooking(ctx, booking)
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// ReengagementPolicy sets when guests are invited back: RebookAfterWeeks after their last completed
+// visit, and AnniversaryLeadDays before the first anniversary of an anniversary celebrated at a
+// restaurant. Zero turns the invitation off.
+type ReengagementPolicy struct {
+	RebookAfterWeeks    int
+	AnniversaryLeadDays int
+}
+
+type ReengagementUseCase interface {
+	// SendInvitations invites back the guests due on the date over their preferred marketing
+	// channel, at most once per guest and run, and returns how many were sent. Guests who did not
+	// opt in to marketing are skipped, invitations held back by the quiet hours of the guest are
+	// sent once they end, and an invitation that fails to be sent does not stop the others.
+	SendInvitations(ctx context.Context, date time.Time) (int, error)
+}
+
+type reengagementUseCase struct {
+	reengagementRepo repository.ReengagementRepository
+	settingsRepo     repository.NotificationSettingsRepository
+	userRepo         repository.UserRepository
+	notifier         domain.NotificationService
+	emailService     EmailService
+	sms              domain.SMSSender
+	deferrals        NotificationDeferralUseCase
+	policy           ReengagementPolicy
+}
+
+// NewReengagementUseCase creates the reengagement use case; emails and texts are sent regardless
+// of quiet hours when deferrals is nil.
+func NewReengagementUseCase(
+	reengagementRepo repository.ReengagementRepository,
+	settingsRepo repository.NotificationSettingsRepository,
+	userRepo repository.UserRepository,
+	notifier domain.NotificationService,
+	emailService EmailService,
+	sms domain.SMSSender,
+	deferrals NotificationDeferralUseCase,
+	policy ReengagementPolicy,
+) ReengagementUseCase {
+	return &reengagementUseCase{
+		reengagementRepo: reengagementRepo,
+		settingsRepo:     settingsRepo,
+		userRepo:         userRepo,
+		notifier:         notifier,
+		emailService:     emailService,
+		sms:              sms,
+		deferrals:        deferrals,
+		policy:           policy,
+	}
+}
+
+func (u *reengagementUseCase) SendInvitations(ctx context.Context, date time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	// Anniversaries come first, as they are the more personal invitation of a guest due for both.
+	var candidates []*domain.ReengagementCandidate
+	if u.policy.AnniversaryLeadDays > 0 {
+		anniversaries, err := u.reengagementRepo.ListAnniversaries(ctx, day.AddDate(-1, 0, u.policy.AnniversaryLeadDays), day)
+		if err != nil {
+			return 0, err
+		}
+		candidates = append(candidates, anniversaries...)
+	}
+	if u.policy.RebookAfterWeeks > 0 {
+		lapsed, err := u.reengagementRepo.ListLapsed(ctx, day.AddDate(0, 0, -7*u.policy.RebookAfterWeeks), day)
+		if err != nil {
+			return 0, err
+		}
+		candidates = append(candidates, lapsed...)
+	}
+
+	sent := 0
+	invited := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if invited[candidate.UserID] {
+			continue
+		}
+		invited[candidate.UserID] = true
+
+		ok, err := u.invite(ctx, candidate)
+		if err != nil {
+			log.Error(ctx, "failed to invite guest back",
+				zap.String("userID", candidate.UserID),
+				zap.String("restaurantID", candidate.RestaurantID),
+				zap.String("reason", string(candidate.Reason)),
+				zap.Error(err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	log.Info(ctx, "guests invited back",
+		zap.Time("date", day),
+		zap.Int("sent", sent),
+		zap.Int("candidates", len(candidates)))
+	return sent, nil
+}
+
+// invite sends the invitation over the preferred marketing channel of the guest and reports
+// whether it was sent; without the marketing preferences of the guest nothing is sent.
+func (u *reengagementUseCase) invite(ctx context.Context, candidate *domain.ReengagementCandidate) (bool, error) {
+	settings, err := u.settingsRepo.Get(ctx, domain.RecipientTypeUser, candidate.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	channel, ok := settings.PreferredMarketingChannel()
+	if !ok {
+		return false, nil
+	}
+
+	title, message := FormatReengagementInvitation(candidate)
+	if channel == domain.NotificationChannelInApp {
+		err := u.notifier.NotifyUser(ctx, candidate.UserID, domain.NotificationTypeMarketing, title, message, candidate.RestaurantID)
+		return err == nil, err
+	}
+
+	if u.deferrals != nil {
+		deferred, err := u.deferrals.Defer(ctx, settings, &domain.DeferredNotification{
+			NotificationType: domain.NotificationTypeMarketing,
+			Channel:          channel,
+			Title:            title,
+			Message:          message,
+			RelatedID:        candidate.RestaurantID,
+		})
+		if err != nil || deferred {
+			return deferred, err
+		}
+	}
+
+	user, err := u.userRepo.GetByID(ctx, domain.UserID(candidate.UserID))
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case channel == domain.NotificationChannelEmail && user.Email != "":
+		err = u.emailService.SendEmail(user.Email, title, message)
+	case channel == domain.NotificationChannelSMS && user.Phone != "":
+		err = u.sms.SendSMS(user.Phone, message)
+	default:
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FormatReengagementInvitation renders the title and message of the invitation.
+func FormatReengagementInvitation(candidate *domain.ReengagementCandidate) (string, string) {
+	if candidate.Reason == domain.ReengagementReasonAnniversary {
+		return "Your anniversary is coming up",
+			fmt.Sprintf("A year ago you celebrated your anniversary at %s on %s. Book a table to celebrate there again.",
+				candidate.RestaurantName, candidate.VisitDate.Format("January 2"))
+	}
+	return "We miss you at " + candidate.RestaurantName,
+		fmt.Sprintf("It has been a while since your last visit on %s. Book a table at %s again.",
+			candidate.VisitDate.Format("January 2"), candidate.RestaurantName)
+}
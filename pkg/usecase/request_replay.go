@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRecordedRequestNotFound = errors.New("recorded request not found")
+	ErrReplayNotConfigured     = errors.New("replay staging URL is not configured")
+	ErrReplayFailed            = errors.New("failed to replay request")
+)
+
+const (
+	// ReplayedRequestHeader carries the ID of the recorded request on its replay.
+	ReplayedRequestHeader = "X-Replayed-Request-ID"
+
+	redactedValue = "[REDACTED]"
+
+	maxRecordedBodyBytes = 64 << 10
+	maxReplayBodyBytes   = 64 << 10
+
+	// maxRecordedAPIKeys bounds the memory taken by the log; the key used least recently is
+	// forgotten first.
+	maxRecordedAPIKeys = 1000
+
+	replayTimeout = 10 * time.Second
+)
+
+// recordedHeaders are the only request headers kept; everything else, cookies and credentials
+// included, is dropped.
+var recordedHeaders = []string{
+	"Accept",
+	"Accept-Language",
+	"Content-Type",
+	"If-None-Match",
+	"User-Agent",
+	"X-Restaurant-IDs",
+	"X-Roles",
+	"X-User-ID",
+}
+
+// sensitiveFields are redacted from JSON bodies and query strings wherever they are nested.
+var sensitiveFields = []string{
+	"password",
+	"secret",
+	"token",
+	"api_key",
+	"apikey",
+	"authorization",
+	"card_number",
+	"cvv",
+	"cvc",
+}
+
+type ReplayResult struct {
+	RequestID  string            `json:"request_id"`
+	URL        string            `json:"url"`
+	Status     int               `json:"status"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body,omitempty"`
+	DurationMS int64             `json:"duration_ms"`
+}
+
+// RequestReplayUseCase keeps the latest API requests of every API key in memory, so admins can
+// look at what a partner sent and replay it against staging. Listing and replaying are for admins.
+type RequestReplayUseCase interface {
+	// RecordRequest sanitizes the request and stores it under the API key it was made with,
+	// forgetting the oldest request of the key beyond the limit.
+	RecordRequest(ctx context.Context, apiKey string, request *domain.RecordedRequest)
+
+	// ListRecordedRequests returns the requests of an API key ID, or of every key when it is
+	// empty, newest first.
+	ListRecordedRequests(ctx context.Context, apiKeyID string) ([]*domain.RecordedRequest, error)
+
+	ReplayRequest(ctx context.Context, id string) (*ReplayResult, error)
+}
+
+type requestReplayUseCase struct {
+	perKey        int
+	stagingURL    string
+	stagingAPIKey string
+	client        *http.Client
+	ids           idgen.Generator
+
+	mu       sync.Mutex
+	byKey    map[string][]*domain.RecordedRequest
+	lastSeen map[string]time.Time
+	byID     map[string]*domain.RecordedRequest
+	clock    clock.Clock
+}
+
+// NewRequestReplayUseCase keeps perKey requests of every API key; zero turns recording off.
+// An empty stagingURL turns replaying off.
+func NewRequestReplayUseCase(perKey int, stagingURL, stagingAPIKey string, ids idgen.Generator, clock clock.Clock) RequestReplayUseCase {
+	return &requestReplayUseCase{
+		perKey:        perKey,
+		stagingURL:    strings.TrimRight(stagingURL, "/"),
+		stagingAPIKey: stagingAPIKey,
+		client:        &http.Client{Timeout: replayTimeout},
+		ids:           ids,
+		byKey:         make(map[string][]*domain.RecordedRequest),
+		lastSeen:      make(map[string]time.Time),
+		byID:          make(map[string]*domain.RecordedRequest),
+		clock:         clock,
+	}
+}
+
+// APIKeyID identifies an API key in the log without storing the key itself.
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (u *requestReplayUseCase) RecordRequest(_ context.Context, apiKey string, request *domain.RecordedRequest) {
+	if u.perKey <= 0 || apiKey == "" {
+		return
+	}
+
+	recorded := sanitizeRequest(request)
+	recorded.ID = u.ids.NewID()
+	recorded.APIKeyID = APIKeyID(apiKey)
+	if recorded.RecordedAt.IsZero() {
+		recorded.RecordedAt = u.clock.Now()
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.byKey[recorded.APIKeyID]; !ok && len(u.byKey) >= maxRecordedAPIKeys {
+		u.forgetLeastRecentKey()
+	}
+
+	requests := append(u.byKey[recorded.APIKeyID], recorded)
+	for len(requests) > u.perKey {
+		delete(u.byID, requests[0].ID)
+		requests = requests[1:]
+	}
+	u.byKey[recorded.APIKeyID] = requests
+	u.lastSeen[recorded.APIKeyID] = recorded.RecordedAt
+	u.byID[recorded.ID] = recorded
+}
+
+func (u *requestReplayUseCase) forgetLeastRecentKey() {
+	var oldestKey string
+	var oldest time.Time
+	for key, seen := range u.lastSeen {
+		if oldestKey == "" || seen.Before(oldest) {
+			oldestKey, oldest = key, seen
+		}
+	}
+
+	for _, request := range u.byKey[oldestKey] {
+		delete(u.byID, request.ID)
+	}
+	delete(u.byKey, oldestKey)
+	delete(u.lastSeen, oldestKey)
+}
+
+func (u *requestReplayUseCase) ListRecordedRequests(ctx context.Context, apiKeyID string) ([]*domain.RecordedRequest, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	var requests []*domain.RecordedRequest
+	if apiKeyID != "" {
+		requests = slices.Clone(u.byKey[apiKeyID])
+	} else {
+		for _, keyRequests := range u.byKey {
+			requests = append(requests, keyRequests...)
+		}
+	}
+	u.mu.Unlock()
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].RecordedAt.After(requests[j].RecordedAt)
+	})
+	return requests, nil
+}
+
+func (u *requestReplayUseCase) ReplayRequest(ctx context.Context, id string) (*ReplayResult, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	recorded, ok := u.byID[id]
+	u.mu.Unlock()
+	if !ok {
+		return nil, ErrRecordedRequestNotFound
+	}
+	if u.stagingURL == "" {
+		return nil, ErrReplayNotConfigured
+	}
+
+	target := u.stagingURL + recorded.Path
+	req, err := http.NewRequestWithContext(ctx, recorded.Method, target, strings.NewReader(recorded.Body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range recorded.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(ReplayedRequestHeader, recorded.ID)
+	if u.stagingAPIKey != "" {
+		req.Header.Set("X-API-Key", u.stagingAPIKey)
+	}
+
+	started := u.clock.Now()
+	resp, err := u.client.Do(req)
+	if err != nil {
+		log.Warn(ctx, "failed to replay request",
+			zap.String("requestID", recorded.ID),
+			zap.String("url", target),
+			zap.Error(err))
+		return nil, errors.Join(ErrReplayFailed, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxReplayBodyBytes))
+	if err != nil {
+		return nil, errors.Join(ErrReplayFailed, err)
+	}
+
+	result := &ReplayResult{
+		RequestID:  recorded.ID,
+		URL:        target,
+		Status:     resp.StatusCode,
+		Headers:    make(map[string]string, len(resp.Header)),
+		Body:       string(body),
+		DurationMS: u.clock.Now().Sub(started).Milliseconds(),
+	}
+	for name := range resp.Header {
+		result.Headers[name] = resp.Header.Get(name)
+	}
+
+	log.Info(ctx, "request replayed",
+		zap.String("requestID", recorded.ID),
+		zap.String("apiKeyID", recorded.APIKeyID),
+		zap.String("url", target),
+		zap.Int("status", resp.StatusCode))
+	return result, nil
+}
+
+// requireAdmin lets admins through only; jobs and tools act as admins through
+// tenant.SystemContext.
+func requireAdmin(ctx context.Context) error {
+	if principal, ok := tenant.FromContext(ctx); !ok || !principal.IsAdmin() {
+		return tenant.ErrAccessDenied
+	}
+	return nil
+}
+
+func sanitizeRequest(request *domain.RecordedRequest) *domain.RecordedRequest {
+	sanitized := *request
+	sanitized.Path = sanitizePath(request.Path)
+
+	sanitized.Headers = make(map[string]string)
+	for name, value := range request.Headers {
+		for _, allowed := range recordedHeaders {
+			if strings.EqualFold(name, allowed) {
+				sanitized.Headers[allowed] = value
+			}
+		}
+	}
+
+	sanitized.Body, sanitized.BodyOmitted = sanitizeBody(sanitized.Headers["Content-Type"], request.Body)
+	return &sanitized
+}
+
+func sanitizePath(path string) string {
+	base, rawQuery, ok := strings.Cut(path, "?")
+	if !ok {
+		return path
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return base
+	}
+	for name := range query {
+		if isSensitiveField(name) {
+			query.Set(name, redactedValue)
+		}
+	}
+	return base + "?" + query.Encode()
+}
+
+// sanitizeBody keeps JSON bodies with their sensitive fields redacted. Other bodies, such as CSV
+// imports, cannot be cleaned reliably and are left out, as are bodies above the size limit.
+func sanitizeBody(contentType, body string) (string, bool) {
+	if body == "" {
+		return "", false
+	}
+	if len(body) > maxRecordedBodyBytes || !strings.HasPrefix(strings.ToLower(contentType), "application/json") {
+		return "", true
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", true
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactJSON(value)); err != nil {
+		return "", true
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), false
+}
+
+func redactJSON(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSensitiveField(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range sensitiveFields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
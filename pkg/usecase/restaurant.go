@@ -2,58 +2,156 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 
 	"go.uber.org/zap"
 )
 
 type RestaurantUseCase interface {
-	GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error)
+	GetRestaurant(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error)
 
-	ListRestaurants(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error)
+	GetRestaurantBySlug(ctx context.Context, slug string) (*domain.Restaurant, error)
+
+	// ListRestaurants returns a page of the catalogue ordered by name, narrowed to the city and the
+	// adult-only venues of the filter; its other fields are ignored.
+	ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page PageRequest) (*PageResponse[*domain.Restaurant], error)
+
+	// SearchRestaurants returns up to limit restaurants matching the filter from offset on, the
+	// best matches of its query first.
+	SearchRestaurants(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error)
+
+	// EstimateRestaurantCount returns the number of restaurants the database statistics give, which
+	// is approximate but does not count the rows; -1 when the statistics are not gathered yet.
+	EstimateRestaurantCount(ctx context.Context) (int64, error)
 
 	CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error)
 
 	UpdateRestaurant(ctx context.Context, restaurant *domain.Restaurant) error
 
-	DeleteRestaurant(ctx context.Context, id string) error
+	DeleteRestaurant(ctx context.Context, id domain.RestaurantID) error
 
-	AddFact(ctx context.Context, restaurantID string, content string) (*domain.Fact, error)
+	AddFact(ctx context.Context, restaurantID domain.RestaurantID, content, locale string) (*domain.Fact, error)
 
-	GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error)
+	GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error)
 
 	GetRandomFacts(ctx context.Context, count int) ([]domain.Fact, error)
 
-	SetWorkingHours(ctx context.Context, restaurantID string, workingHours *domain.WorkingHours) error
+	SetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID, workingHours *domain.WorkingHours) error
+
+	GetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error)
+
+	// SetSpecialDay replaces the working hours of the restaurant on the date of the special day,
+	// replacing the special day it had on the date.
+	SetSpecialDay(ctx context.Context, day *domain.SpecialDay) error
+
+	// ListSpecialDays returns the special days of the restaurant from today on, earliest first.
+	ListSpecialDays(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.SpecialDay, error)
+
+	DeleteSpecialDay(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) error
+
+	// GetSchedule resolves the working hours, special days and closures of the restaurant into when
+	// it is open on every date from from to to, at most 92 of them; availability is generated from
+	// the same schedule.
+	GetSchedule(ctx context.Context, restaurantID domain.RestaurantID, from, to time.Time) ([]domain.ScheduleDay, error)
 
-	GetWorkingHours(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error)
+	ImportRestaurants(ctx context.Context, r io.Reader, dryRun bool) (*RestaurantImportReport, error)
+
+	ExportRestaurant(ctx context.Context, id domain.RestaurantID) (*RestaurantBundle, error)
+
+	ImportRestaurantBundle(ctx context.Context, bundle *RestaurantBundle) (string, error)
 }
 
+var (
+	ErrInvalidRestaurantSlug = errors.New("invalid restaurant slug")
+	ErrRestaurantSlugTaken   = errors.New("restaurant slug is already taken")
+	ErrInvalidCurrency       = errors.New("invalid currency code")
+)
+
+// maxSlugAttempts limits the numbered variants tried for a generated slug.
+const maxSlugAttempts = 100
+
 type restaurantUseCase struct {
 	restaurantRepo   repository.RestaurantRepository
 	workingHoursRepo repository.WorkingHoursRepository
+	closureRepo      repository.RestaurantClosureRepository
+	specialDayRepo   repository.SpecialDayRepository
+	transactor       repository.Transactor
+	phoneRegion      string
+	clock            clock.Clock
 }
 
+// NewRestaurantUseCase creates the restaurant use case; contact phones written without a country
+// code are read as numbers of phoneRegion.
 func NewRestaurantUseCase(
 	restaurantRepo repository.RestaurantRepository,
 	workingHoursRepo repository.WorkingHoursRepository,
+	closureRepo repository.RestaurantClosureRepository,
+	specialDayRepo repository.SpecialDayRepository,
+	transactor repository.Transactor,
+	phoneRegion string,
+	clock clock.Clock,
 ) RestaurantUseCase {
 	return &restaurantUseCase{
 		restaurantRepo:   restaurantRepo,
 		workingHoursRepo: workingHoursRepo,
+		closureRepo:      closureRepo,
+		specialDayRepo:   specialDayRepo,
+		transactor:       transactor,
+		phoneRegion:      phoneRegion,
+		clock:            clock,
 	}
 }
 
-func (u *restaurantUseCase) GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (u *restaurantUseCase) GetRestaurant(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	return u.restaurantRepo.GetByID(ctx, id)
 }
 
-func (u *restaurantUseCase) ListRestaurants(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
-	return u.restaurantRepo.List(ctx, offset, limit)
+// GetRestaurantBySlug also accepts a slug the restaurant had before; the caller can tell by
+// comparing it with the slug of the returned restaurant.
+func (u *restaurantUseCase) GetRestaurantBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	if !domain.IsValidSlug(slug) {
+		return nil, ErrInvalidRestaurantSlug
+	}
+	return u.restaurantRepo.GetBySlug(ctx, slug)
+}
+
+func (u *restaurantUseCase) ListRestaurants(ctx context.Context, filter domain.RestaurantFilter, page PageRequest) (*PageResponse[*domain.Restaurant], error) {
+	after, err := decodeCursor[domain.RestaurantCursor](page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := page.Size()
+	restaurants, err := u.restaurantRepo.ListAfter(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPage(restaurants, limit, (*domain.Restaurant).CursorAfter)
+}
+
+func (u *restaurantUseCase) SearchRestaurants(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	if filter.Cuisine != "" && !filter.Cuisine.IsKnown() {
+		return nil, &domain.ValidationError{Entity: "restaurant search", Field: "cuisine", Reason: "is not a known cuisine"}
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Address = strings.TrimSpace(filter.Address)
+	return u.restaurantRepo.Search(ctx, filter, offset, limit)
+}
+
+func (u *restaurantUseCase) EstimateRestaurantCount(ctx context.Context) (int64, error) {
+	return u.restaurantRepo.EstimateCount(ctx)
 }
 
 func (u *restaurantUseCase) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
@@ -63,7 +161,20 @@ func (u *restaurantUseCase) CreateRestaurant(ctx context.Context, restaurant *do
 		zap.String("address", restaurant.Address),
 		zap.String("cuisine", string(restaurant.Cuisine)))
 
-	now := time.Now()
+	if err := normalizeRestaurantCurrency(restaurant); err != nil {
+		return "", err
+	}
+	if err := restaurant.Validate(); err != nil {
+		return "", err
+	}
+	if err := u.normalizeContacts(restaurant); err != nil {
+		return "", err
+	}
+	if err := u.assignSlug(ctx, restaurant, nil); err != nil {
+		return "", err
+	}
+
+	now := u.clock.Now()
 	restaurant.CreatedAt = now
 	restaurant.UpdatedAt = now
 	if err := u.restaurantRepo.Create(ctx, restaurant); err != nil {
@@ -83,7 +194,22 @@ func (u *restaurantUseCase) UpdateRestaurant(ctx context.Context, restaurant *do
 		zap.String("restaurantID", restaurant.ID),
 		zap.String("name", restaurant.Name))
 
-	restaurant.UpdatedAt = time.Now()
+	if err := normalizeRestaurantCurrency(restaurant); err != nil {
+		return err
+	}
+	if err := restaurant.Validate(); err != nil {
+		return err
+	}
+	if err := u.normalizeContacts(restaurant); err != nil {
+		return err
+	}
+	if restaurant.Slug != "" {
+		if err := u.checkSlug(ctx, restaurant.Slug, restaurant.ID); err != nil {
+			return err
+		}
+	}
+
+	restaurant.UpdatedAt = u.clock.Now()
 
 	if err := u.restaurantRepo.Update(ctx, restaurant); err != nil {
 		log.Error(ctx, "failed to update restaurant",
@@ -96,47 +222,93 @@ func (u *restaurantUseCase) UpdateRestaurant(ctx context.Context, restaurant *do
 	return nil
 }
 
-func (u *restaurantUseCase) DeleteRestaurant(ctx context.Context, id string) error {
+// normalizeRestaurantCurrency gives a restaurant without a currency the default one and brings the
+// code of the others to upper case.
+func normalizeRestaurantCurrency(restaurant *domain.Restaurant) error {
+	if restaurant.Currency == "" {
+		restaurant.Currency = domain.DefaultCurrency
+		return nil
+	}
+
+	currency, ok := domain.NormalizeCurrency(restaurant.Currency)
+	if !ok {
+		return ErrInvalidCurrency
+	}
+	restaurant.Currency = currency
+	return nil
+}
+
+// normalizeContacts validates the contact email and phone of the restaurant and sets their
+// normalized forms; a contact left empty stays empty.
+func (u *restaurantUseCase) normalizeContacts(restaurant *domain.Restaurant) error {
+	restaurant.NormalizedContactEmail = ""
+	if restaurant.ContactEmail != "" {
+		email, err := contact.NormalizeEmail(restaurant.ContactEmail)
+		if err != nil {
+			return err
+		}
+		restaurant.NormalizedContactEmail = email
+	}
+
+	restaurant.NormalizedContactPhone = ""
+	if restaurant.ContactPhone != "" {
+		phone, err := contact.NormalizePhone(restaurant.ContactPhone, u.phoneRegion)
+		if err != nil {
+			return err
+		}
+		restaurant.NormalizedContactPhone = phone
+	}
+
+	return nil
+}
+
+func (u *restaurantUseCase) DeleteRestaurant(ctx context.Context, id domain.RestaurantID) error {
 	log, _ := logger.FromContext(ctx)
-	log.Info(ctx, "deleting restaurant", zap.String("restaurantID", id))
+	log.Info(ctx, "deleting restaurant", zap.String("restaurantID", id.String()))
 
 	if err := u.restaurantRepo.Delete(ctx, id); err != nil {
 		log.Error(ctx, "failed to delete restaurant",
-			zap.String("restaurantID", id),
+			zap.String("restaurantID", id.String()),
 			zap.Error(err))
 		return err
 	}
 
-	log.Info(ctx, "restaurant successfully deleted", zap.String("restaurantID", id))
+	log.Info(ctx, "restaurant successfully deleted", zap.String("restaurantID", id.String()))
 	return nil
 }
 
-func (u *restaurantUseCase) AddFact(ctx context.Context, restaurantID string, content string) (*domain.Fact, error) {
+func (u *restaurantUseCase) AddFact(ctx context.Context, restaurantID domain.RestaurantID, content, locale string) (*domain.Fact, error) {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "adding restaurant fact",
-		zap.String("restaurantID", restaurantID))
+		zap.String("restaurantID", restaurantID.String()),
+		zap.String("locale", locale))
+
+	if locale == "" {
+		locale = domain.DefaultFactLocale
+	}
 
 	fact := domain.Fact{
-		RestaurantID: restaurantID,
+		RestaurantID: restaurantID.String(),
 		Content:      content,
-		CreatedAt:    time.Now(),
+		Locale:       locale,
+		CreatedAt:    u.clock.Now(),
 	}
 
 	createdFact, err := u.restaurantRepo.AddFact(ctx, restaurantID, fact)
 	if err != nil {
 		log.Error(ctx, "failed to add restaurant fact",
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 		return nil, err
 	}
 
 	log.Info(ctx, "restaurant fact successfully added",
 		zap.String("factID", createdFact.ID),
-		zap.String("restaurantID", restaurantID))
+		zap.String("restaurantID", restaurantID.String()))
 	return createdFact, nil
 }
 
-func (u *restaurantUseCase) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (u *restaurantUseCase) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	return u.restaurantRepo.GetFacts(ctx, restaurantID)
 }
 
@@ -144,28 +316,162 @@ func (u *restaurantUseCase) GetRandomFacts(ctx context.Context, count int) ([]do
 	return u.restaurantRepo.GetRandomFacts(ctx, count)
 }
 
-func (u *restaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID string, workingHours *domain.WorkingHours) error {
+func (u *restaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID, workingHours *domain.WorkingHours) error {
 	log, _ := logger.FromContext(ctx)
 	log.Info(ctx, "setting restaurant working hours",
-		zap.String("restaurantID", restaurantID),
+		zap.String("restaurantID", restaurantID.String()),
 		zap.Int("weekDay", int(workingHours.WeekDay)),
 		zap.String("openTime", workingHours.OpenTime),
 		zap.String("closeTime", workingHours.CloseTime))
 
-	workingHours.RestaurantID = restaurantID
+	workingHours.RestaurantID = restaurantID.String()
+	if err := workingHours.Validate(); err != nil {
+		return err
+	}
 	if err := u.workingHoursRepo.SetWorkingHours(ctx, workingHours); err != nil {
 		log.Error(ctx, "failed to set restaurant working hours",
-			zap.String("restaurantID", restaurantID),
+			zap.String("restaurantID", restaurantID.String()),
 			zap.Error(err))
 		return err
 	}
 
 	log.Info(ctx, "restaurant working hours successfully set",
-		zap.String("restaurantID", restaurantID),
+		zap.String("restaurantID", restaurantID.String()),
 		zap.Int("weekDay", int(workingHours.WeekDay)))
 	return nil
 }
 
-func (u *restaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (u *restaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	return u.workingHoursRepo.GetByRestaurantID(ctx, restaurantID)
 }
+
+func (u *restaurantUseCase) SetSpecialDay(ctx context.Context, day *domain.SpecialDay) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(day.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	day.Date = truncateToDate(day.Date)
+	day.Note = strings.TrimSpace(day.Note)
+	if day.IsClosed {
+		day.OpenTime, day.CloseTime = "", ""
+	}
+	if err := day.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(day.RestaurantID)); err != nil {
+		return err
+	}
+
+	if err := u.specialDayRepo.Set(ctx, day); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "restaurant special day set",
+		zap.String("restaurantID", day.RestaurantID),
+		zap.Time("date", day.Date),
+		zap.Bool("isClosed", day.IsClosed))
+	return nil
+}
+
+func (u *restaurantUseCase) ListSpecialDays(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.SpecialDay, error) {
+	return u.specialDayRepo.ListByRestaurant(ctx, restaurantID.String(), u.clock.Now())
+}
+
+func (u *restaurantUseCase) DeleteSpecialDay(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID.String()) {
+		return tenant.ErrAccessDenied
+	}
+
+	if err := u.specialDayRepo.Delete(ctx, restaurantID.String(), truncateToDate(date)); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "restaurant special day deleted",
+		zap.String("restaurantID", restaurantID.String()),
+		zap.Time("date", date))
+	return nil
+}
+
+func (u *restaurantUseCase) GetSchedule(ctx context.Context, restaurantID domain.RestaurantID, from, to time.Time) ([]domain.ScheduleDay, error) {
+	from, to = truncateToDate(from), truncateToDate(to)
+	if to.Before(from) || to.Sub(from) >= maxGenerateAvailabilityDays*24*time.Hour {
+		return nil, ErrInvalidDateRange
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, restaurantID); err != nil {
+		return nil, err
+	}
+	workingHours, err := u.workingHoursRepo.GetByRestaurantID(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	specialDays, err := u.specialDayRepo.ListByRestaurant(ctx, restaurantID.String(), from)
+	if err != nil {
+		return nil, err
+	}
+	closures, err := u.closureRepo.ListByRestaurant(ctx, restaurantID.String(), from)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := make([]domain.ScheduleDay, 0, int(to.Sub(from)/(24*time.Hour))+1)
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		schedule = append(schedule, domain.ScheduleOn(workingHours, specialDays, closures, date))
+	}
+	return schedule, nil
+}
+
+// assignSlug validates the slug chosen for a new restaurant or, when there is none, generates one
+// from its name, numbering it when it is taken. Slugs in reserved are treated as taken, which lets
+// a batch of restaurants get distinct slugs before any of them is stored.
+func (u *restaurantUseCase) assignSlug(ctx context.Context, restaurant *domain.Restaurant, reserved map[string]bool) error {
+	if restaurant.Slug != "" {
+		if reserved[restaurant.Slug] {
+			return ErrRestaurantSlugTaken
+		}
+		return u.checkSlug(ctx, restaurant.Slug, restaurant.ID)
+	}
+
+	base := domain.Slugify(restaurant.Name)
+	for attempt := 1; attempt <= maxSlugAttempts; attempt++ {
+		slug := base
+		if attempt > 1 {
+			suffix := "-" + strconv.Itoa(attempt)
+			slug = strings.TrimRight(base[:min(len(base), domain.MaxSlugLength-len(suffix))], "-") + suffix
+		}
+		if reserved[slug] {
+			continue
+		}
+
+		taken, err := u.restaurantRepo.IsSlugTaken(ctx, slug, restaurant.ID)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			restaurant.Slug = slug
+			return nil
+		}
+	}
+
+	return ErrRestaurantSlugTaken
+}
+
+func (u *restaurantUseCase) checkSlug(ctx context.Context, slug, restaurantID string) error {
+	if !domain.IsValidSlug(slug) {
+		return ErrInvalidRestaurantSlug
+	}
+
+	taken, err := u.restaurantRepo.IsSlugTaken(ctx, slug, restaurantID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrRestaurantSlugTaken
+	}
+
+	return nil
+}
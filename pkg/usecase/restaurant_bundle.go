@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// RestaurantBundleVersion is the format version written by ExportRestaurant.
+const RestaurantBundleVersion = 1
+
+var (
+	ErrUnsupportedBundleVersion = errors.New("unsupported restaurant bundle version")
+	ErrInvalidBundle            = errors.New("invalid restaurant bundle")
+	ErrRestaurantAlreadyExists  = errors.New("restaurant already exists")
+)
+
+// RestaurantBundle is a portable copy of a restaurant profile with its facts and working hours,
+// used to move a restaurant between environments.
+type RestaurantBundle struct {
+	Version      int                    `json:"version"`
+	ExportedAt   time.Time              `json:"exported_at"`
+	Restaurant   *domain.Restaurant     `json:"restaurant"`
+	WorkingHours []*domain.WorkingHours `json:"working_hours"`
+}
+
+func (u *restaurantUseCase) ExportRestaurant(ctx context.Context, id domain.RestaurantID) (*RestaurantBundle, error) {
+	log, _ := logger.FromContext(ctx)
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	facts, err := u.restaurantRepo.GetFacts(ctx, id)
+	if err != nil {
+		log.Error(ctx, "failed to get facts for restaurant export",
+			zap.String("restaurantID", id.String()),
+			zap.Error(err))
+		return nil, err
+	}
+	restaurant.Facts = facts
+
+	workingHours, err := u.workingHoursRepo.GetByRestaurantID(ctx, id)
+	if err != nil {
+		log.Error(ctx, "failed to get working hours for restaurant export",
+			zap.String("restaurantID", id.String()),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &RestaurantBundle{
+		Version:      RestaurantBundleVersion,
+		ExportedAt:   u.clock.Now().UTC(),
+		Restaurant:   restaurant,
+		WorkingHours: workingHours,
+	}, nil
+}
+
+// ImportRestaurantBundle creates the restaurant of an exported bundle under the same ID, so links
+// to it keep working in the target environment. Facts and working hours get new IDs.
+func (u *restaurantUseCase) ImportRestaurantBundle(ctx context.Context, bundle *RestaurantBundle) (string, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if bundle.Version != RestaurantBundleVersion {
+		return "", ErrUnsupportedBundleVersion
+	}
+	if bundle.Restaurant == nil || bundle.Restaurant.ID == "" || bundle.Restaurant.Name == "" || bundle.Restaurant.Address == "" {
+		return "", ErrInvalidBundle
+	}
+
+	restaurant := *bundle.Restaurant
+	facts := restaurant.Facts
+	restaurant.Facts = nil
+
+	// Bundles exported before restaurants had a currency get the default one.
+	if err := normalizeRestaurantCurrency(&restaurant); err != nil {
+		return "", ErrInvalidBundle
+	}
+	if err := u.normalizeContacts(&restaurant); err != nil {
+		return "", ErrInvalidBundle
+	}
+
+	log.Info(ctx, "importing restaurant bundle",
+		zap.String("restaurantID", restaurant.ID),
+		zap.Int("facts", len(facts)),
+		zap.Int("workingHours", len(bundle.WorkingHours)))
+
+	err := u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurant.ID)); err == nil {
+			return ErrRestaurantAlreadyExists
+		} else if err.Error() != common.ErrRestaurantNotFound {
+			return err
+		}
+
+		// The slug of the bundle is kept when it is free in the target environment.
+		if err := u.checkSlug(ctx, restaurant.Slug, restaurant.ID); err != nil {
+			if !errors.Is(err, ErrRestaurantSlugTaken) && !errors.Is(err, ErrInvalidRestaurantSlug) {
+				return err
+			}
+			restaurant.Slug = ""
+			if err := u.assignSlug(ctx, &restaurant, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := u.restaurantRepo.Create(ctx, &restaurant); err != nil {
+			return err
+		}
+
+		hours := make([]*domain.WorkingHours, 0, len(bundle.WorkingHours))
+		for _, h := range bundle.WorkingHours {
+			wh := *h
+			wh.ID = ""
+			wh.RestaurantID = restaurant.ID
+			hours = append(hours, &wh)
+		}
+		if err := u.workingHoursRepo.CreateBatch(ctx, hours); err != nil {
+			return err
+		}
+
+		for _, fact := range facts {
+			fact.ID = ""
+			fact.RestaurantID = restaurant.ID
+			if _, err := u.restaurantRepo.AddFact(ctx, domain.RestaurantID(restaurant.ID), fact); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrRestaurantAlreadyExists) {
+			log.Error(ctx, "failed to import restaurant bundle",
+				zap.String("restaurantID", restaurant.ID),
+				zap.Error(err))
+		}
+		return "", err
+	}
+
+	log.Info(ctx, "restaurant bundle successfully imported", zap.String("restaurantID", restaurant.ID))
+	return restaurant.ID, nil
+}
@@ -0,0 +1,350 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxRestaurantClaimProofLength = 2000
+	maxRestaurantClaimNoteLength  = 1000
+
+	// maxSupersededClaims bounds the other pending claims of a restaurant rejected when one of
+	// them is approved.
+	maxSupersededClaims = 1000
+
+	// supersededClaimNote is the review note of the pending claims of a restaurant rejected
+	// because another claim of it was approved.
+	supersededClaimNote = "another claim of the restaurant was approved"
+)
+
+var (
+	ErrInvalidRestaurantClaim = errors.New("invalid restaurant claim")
+
+	// ErrRestaurantClaimDecided is returned when an admin already approved or rejected the claim.
+	ErrRestaurantClaimDecided = errors.New("restaurant claim is already decided")
+)
+
+// RestaurantOwnershipReport is the owner of a restaurant, nil while the platform holds it, and
+// the audit records of its hand-overs, newest first.
+type RestaurantOwnershipReport struct {
+	Owner *domain.RestaurantOwnership    `json:"owner"`
+	Audit []*domain.OwnershipAuditRecord `json:"audit"`
+}
+
+// RestaurantClaimUseCase hands the restaurant profiles admins imported over to their owners: an
+// owner claims a profile with proof and an admin reviews the claim.
+type RestaurantClaimUseCase interface {
+	// SubmitClaim asks for the restaurant to be handed over to the user of the claim, the
+	// principal when empty. Fails with common.ErrRestaurantAlreadyOwned for a restaurant that has
+	// an owner and with common.ErrRestaurantClaimExists when the user's claim of it is pending.
+	SubmitClaim(ctx context.Context, claim *domain.RestaurantClaim) error
+
+	// GetClaim returns the claim to its user and to admins.
+	GetClaim(ctx context.Context, id string) (*domain.RestaurantClaim, error)
+
+	// ListClaims returns the claims matching the filter, oldest first. Admins only.
+	ListClaims(ctx context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error)
+
+	// ApproveClaim makes the claimant the owner of the restaurant, records the hand-over in the
+	// audit and rejects the other pending claims of the restaurant. Admins only.
+	ApproveClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error)
+
+	// RejectClaim turns the claim down; the note tells the claimant why. Admins only.
+	RejectClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error)
+
+	// GetOwnership returns the owner of the restaurant with the audit of its hand-overs. Admins
+	// only.
+	GetOwnership(ctx context.Context, restaurantID string) (*RestaurantOwnershipReport, error)
+}
+
+type restaurantClaimUseCase struct {
+	claimRepo      repository.RestaurantClaimRepository
+	restaurantRepo repository.RestaurantRepository
+	notifier       domain.NotificationService
+	transactor     repository.Transactor
+	clock          clock.Clock
+}
+
+func NewRestaurantClaimUseCase(
+	claimRepo repository.RestaurantClaimRepository,
+	restaurantRepo repository.RestaurantRepository,
+	notifier domain.NotificationService,
+	transactor repository.Transactor,
+	clock clock.Clock,
+) RestaurantClaimUseCase {
+	return &restaurantClaimUseCase{
+		claimRepo:      claimRepo,
+		restaurantRepo: restaurantRepo,
+		notifier:       notifier,
+		transactor:     transactor,
+		clock:          clock,
+	}
+}
+
+func (u *restaurantClaimUseCase) SubmitClaim(ctx context.Context, claim *domain.RestaurantClaim) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok {
+		if claim.UserID == "" {
+			claim.UserID = principal.UserID
+		}
+		if !principal.CanAccessUser(claim.UserID) {
+			return tenant.ErrAccessDenied
+		}
+	}
+
+	claim.Proof = strings.TrimSpace(claim.Proof)
+	switch {
+	case claim.UserID == "":
+		return fmt.Errorf("%w: user is required", ErrInvalidRestaurantClaim)
+	case claim.Proof == "":
+		return fmt.Errorf("%w: proof is required", ErrInvalidRestaurantClaim)
+	case utf8.RuneCountInString(claim.Proof) > maxRestaurantClaimProofLength:
+		return fmt.Errorf("%w: proof is longer than %d characters", ErrInvalidRestaurantClaim, maxRestaurantClaimProofLength)
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(claim.RestaurantID)); err != nil {
+		return err
+	}
+	if err := u.checkUnowned(ctx, claim.RestaurantID); err != nil {
+		return err
+	}
+
+	if err := u.claimRepo.Create(ctx, claim); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "restaurant claimed",
+		zap.String("claimID", claim.ID),
+		zap.String("restaurantID", claim.RestaurantID),
+		zap.String("userID", claim.UserID))
+	return nil
+}
+
+// checkUnowned fails with common.ErrRestaurantAlreadyOwned when the restaurant has an owner.
+func (u *restaurantClaimUseCase) checkUnowned(ctx context.Context, restaurantID string) error {
+	_, err := u.claimRepo.GetOwner(ctx, restaurantID)
+	switch {
+	case err == nil:
+		return errors.New(common.ErrRestaurantAlreadyOwned)
+	case err.Error() == common.ErrRestaurantOwnerNotFound:
+		return nil
+	default:
+		return err
+	}
+}
+
+func (u *restaurantClaimUseCase) GetClaim(ctx context.Context, id string) (*domain.RestaurantClaim, error) {
+	claim, err := u.claimRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if principal, ok := tenant.FromContext(ctx); !ok || !principal.CanAccessUser(claim.UserID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	return claim, nil
+}
+
+func (u *restaurantClaimUseCase) ListClaims(ctx context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, fmt.Errorf("%w: status %q", ErrInvalidRestaurantClaim, filter.Status)
+	}
+	return u.claimRepo.List(ctx, filter, offset, limit)
+}
+
+func (u *restaurantClaimUseCase) ApproveClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error) {
+	log, _ := logger.FromContext(ctx)
+
+	claim, err := u.pendingClaim(ctx, id, note)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.clock.Now()
+	claim.Status = domain.RestaurantClaimApproved
+	claim.ReviewerID = reviewerID(ctx)
+	claim.ReviewNote = strings.TrimSpace(note)
+	claim.DecidedAt = &now
+
+	var superseded []*domain.RestaurantClaim
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := u.claimRepo.Decide(ctx, claim); err != nil {
+			return err
+		}
+		// SetOwner fails when the restaurant got an owner since the claim was made, which rolls
+		// the approval back.
+		err := u.claimRepo.SetOwner(ctx, &domain.RestaurantOwnership{
+			RestaurantID: claim.RestaurantID,
+			OwnerID:      claim.UserID,
+			ClaimID:      claim.ID,
+			Since:        now,
+		})
+		if err != nil {
+			return err
+		}
+		err = u.claimRepo.AddAuditRecord(ctx, &domain.OwnershipAuditRecord{
+			RestaurantID: claim.RestaurantID,
+			ClaimID:      claim.ID,
+			OwnerID:      claim.UserID,
+			ApprovedBy:   claim.ReviewerID,
+			CreatedAt:    now,
+		})
+		if err != nil {
+			return err
+		}
+
+		superseded, err = u.claimRepo.List(ctx, domain.RestaurantClaimFilter{
+			RestaurantID: claim.RestaurantID,
+			Status:       domain.RestaurantClaimPending,
+		}, 0, maxSupersededClaims)
+		if err != nil {
+			return err
+		}
+		for _, other := range superseded {
+			other.Status = domain.RestaurantClaimRejected
+			other.ReviewerID = claim.ReviewerID
+			other.ReviewNote = supersededClaimNote
+			other.DecidedAt = &now
+			if err := u.claimRepo.Decide(ctx, other); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, "failed to approve restaurant claim",
+			zap.String("claimID", id),
+			zap.Error(err))
+		if err.Error() == common.ErrRestaurantClaimNotFound {
+			return nil, ErrRestaurantClaimDecided
+		}
+		return nil, err
+	}
+
+	log.Info(ctx, "restaurant claim approved",
+		zap.String("claimID", claim.ID),
+		zap.String("restaurantID", claim.RestaurantID),
+		zap.String("ownerID", claim.UserID),
+		zap.Int("superseded", len(superseded)))
+
+	name := u.restaurantName(ctx, claim.RestaurantID)
+	u.notifyClaimant(ctx, claim, "Restaurant claim approved", "You are now the owner of "+name+" on the platform")
+	for _, other := range superseded {
+		u.notifyClaimant(ctx, other, "Restaurant claim rejected", "Your claim of "+name+" was rejected: "+other.ReviewNote)
+	}
+	return claim, nil
+}
+
+func (u *restaurantClaimUseCase) RejectClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error) {
+	claim, err := u.pendingClaim(ctx, id, note)
+	if err != nil {
+		return nil, err
+	}
+
+	now := u.clock.Now()
+	claim.Status = domain.RestaurantClaimRejected
+	claim.ReviewerID = reviewerID(ctx)
+	claim.ReviewNote = strings.TrimSpace(note)
+	claim.DecidedAt = &now
+	if err := u.claimRepo.Decide(ctx, claim); err != nil {
+		if err.Error() == common.ErrRestaurantClaimNotFound {
+			return nil, ErrRestaurantClaimDecided
+		}
+		return nil, err
+	}
+
+	message := "Your claim of " + u.restaurantName(ctx, claim.RestaurantID) + " was rejected"
+	if claim.ReviewNote != "" {
+		message += ": " + claim.ReviewNote
+	}
+	u.notifyClaimant(ctx, claim, "Restaurant claim rejected", message)
+	return claim, nil
+}
+
+// pendingClaim returns the claim when the caller is an admin, the claim is still pending and
+// the review note is not too long.
+func (u *restaurantClaimUseCase) pendingClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(note)) > maxRestaurantClaimNoteLength {
+		return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidRestaurantClaim, maxRestaurantClaimNoteLength)
+	}
+
+	claim, err := u.claimRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if claim.Status != domain.RestaurantClaimPending {
+		return nil, ErrRestaurantClaimDecided
+	}
+	return claim, nil
+}
+
+func (u *restaurantClaimUseCase) GetOwnership(ctx context.Context, restaurantID string) (*RestaurantOwnershipReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	report := &RestaurantOwnershipReport{}
+	owner, err := u.claimRepo.GetOwner(ctx, restaurantID)
+	switch {
+	case err == nil:
+		report.Owner = owner
+	case err.Error() != common.ErrRestaurantOwnerNotFound:
+		return nil, err
+	}
+
+	report.Audit, err = u.claimRepo.ListAuditRecords(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// reviewerID is the admin deciding a claim; tools run without a principal and leave it empty.
+func reviewerID(ctx context.Context) string {
+	if principal, ok := tenant.FromContext(ctx); ok {
+		return principal.UserID
+	}
+	return ""
+}
+
+func (u *restaurantClaimUseCase) restaurantName(ctx context.Context, restaurantID string) string {
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID))
+	if err != nil {
+		return "the restaurant"
+	}
+	return restaurant.Name
+}
+
+func (u *restaurantClaimUseCase) notifyClaimant(ctx context.Context, claim *domain.RestaurantClaim, title, message string) {
+	log, _ := logger.FromContext(ctx)
+
+	err := u.notifier.NotifyUser(ctx, claim.UserID, domain.NotificationTypeRestaurantClaim, title, message, claim.ID)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to user",
+			zap.String("userID", claim.UserID),
+			zap.String("claimID", claim.ID),
+			zap.Error(err))
+	}
+}
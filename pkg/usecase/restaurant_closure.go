@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+// maxClosureSisters bounds the sister restaurants a closed restaurant refers its guests to.
+const maxClosureSisters = 3
+
+// earthRadiusKm is the mean radius of the Earth, for the distances between restaurants.
+const earthRadiusKm = 6371.0
+
+var (
+	// ErrInvalidRestaurantClosure is returned for a closure that is over already.
+	ErrInvalidRestaurantClosure = errors.New("invalid restaurant closure")
+
+	// ErrRestaurantClosed is wrapped by every *RestaurantClosedError.
+	ErrRestaurantClosed = errors.New(common.ErrRestaurantClosed)
+)
+
+// closureReplyTemplate is the reply to a booking requested for a day the restaurant is closed.
+var closureReplyTemplate = template.Must(template.New("closure_reply").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("Mon 02.01.2006") },
+}).Parse(`{{.Restaurant.Name}} is closed on {{date .Date}} and reopens on {{date .Closure.ReopensOn}}. We can't take your booking for that day, but we'd be glad to see you once we're back.{{with .Closure.Message}}
+{{.}}{{end}}{{if .Sisters}}
+Our sister restaurants nearby are open:{{range .Sisters}}
+- {{.Name}}{{with .Address}}, {{.}}{{end}}{{end}}{{end}}`))
+
+// ClosureReply is what a closed restaurant answers to a booking requested for the day of Date.
+type ClosureReply struct {
+	Restaurant *domain.Restaurant
+	Closure    *domain.RestaurantClosure
+	Date       time.Time
+	// Sisters are the open sister restaurants of the organization, nearest first.
+	Sisters []*domain.Restaurant
+}
+
+// FormatClosureReply renders the reply to the guest.
+func FormatClosureReply(reply *ClosureReply) (string, error) {
+	var b strings.Builder
+	if err := closureReplyTemplate.Execute(&b, reply); err != nil {
+		return "", fmt.Errorf("%s: %w", common.ErrRenderClosureReply, err)
+	}
+	return b.String(), nil
+}
+
+// RestaurantClosedError answers a booking requested for a day its restaurant is closed, on behalf
+// of the restaurant.
+type RestaurantClosedError struct {
+	Closure *domain.RestaurantClosure
+	// Reply is the message to the guest, with the day the restaurant reopens and its sisters.
+	Reply   string
+	Sisters []*domain.Restaurant
+}
+
+func (e *RestaurantClosedError) Error() string {
+	return fmt.Sprintf("%s: reopens on %s", ErrRestaurantClosed, e.Closure.ReopensOn.Format(time.DateOnly))
+}
+
+func (e *RestaurantClosedError) Unwrap() error {
+	return ErrRestaurantClosed
+}
+
+// RestaurantClosureUseCase keeps the periods restaurants are closed, such as vacations, and
+// answers the booking requests for their days on the restaurants' behalf.
+type RestaurantClosureUseCase interface {
+	// CreateClosure adds a closure to the restaurant, for its staff, and returns the reply to the
+	// guests booking its first day still ahead. A dry run rolls the closure back. Fails with
+	// common.ErrRestaurantClosureOverlaps when it shares a day with another closure.
+	CreateClosure(ctx context.Context, closure *domain.RestaurantClosure, dryRun bool) (string, error)
+
+	// ListClosures returns the closures of the restaurant that are not over, earliest first.
+	ListClosures(ctx context.Context, restaurantID string) ([]*domain.RestaurantClosure, error)
+
+	DeleteClosure(ctx context.Context, restaurantID, closureID string) error
+
+	// CheckOpen fails with *RestaurantClosedError when the restaurant of the booking is closed on
+	// its date.
+	CheckOpen(ctx context.Context, booking *domain.Booking) error
+}
+
+type restaurantClosureUseCase struct {
+	closureRepo    repository.RestaurantClosureRepository
+	restaurantRepo repository.RestaurantRepository
+	locationRepo   repository.RestaurantLocationRepository
+	transactor     repository.Transactor
+	clock          clock.Clock
+}
+
+func NewRestaurantClosureUseCase(
+	closureRepo repository.RestaurantClosureRepository,
+	restaurantRepo repository.RestaurantRepository,
+	locationRepo repository.RestaurantLocationRepository,
+	transactor repository.Transactor,
+	clock clock.Clock,
+) RestaurantClosureUseCase {
+	return &restaurantClosureUseCase{
+		closureRepo:    closureRepo,
+		restaurantRepo: restaurantRepo,
+		locationRepo:   locationRepo,
+		transactor:     transactor,
+		clock:          clock,
+	}
+}
+
+func (u *restaurantClosureUseCase) CreateClosure(ctx context.Context, closure *domain.RestaurantClosure, dryRun bool) (string, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(closure.RestaurantID) {
+		return "", tenant.ErrAccessDenied
+	}
+
+	closure.StartsOn = truncateToDate(closure.StartsOn)
+	closure.ReopensOn = truncateToDate(closure.ReopensOn)
+	closure.Message = strings.TrimSpace(closure.Message)
+	if err := closure.Validate(); err != nil {
+		return "", err
+	}
+	today := truncateToDate(u.clock.Now())
+	if !closure.ReopensOn.After(today) {
+		return "", fmt.Errorf("%w: it is over already", ErrInvalidRestaurantClosure)
+	}
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(closure.RestaurantID))
+	if err != nil {
+		return "", err
+	}
+
+	run := u.transactor.InTransaction
+	if dryRun {
+		run = u.transactor.DryRun
+	}
+
+	var reply string
+	err = run(ctx, func(ctx context.Context) error {
+		if err := u.closureRepo.Create(ctx, closure); err != nil {
+			return err
+		}
+
+		date := closure.StartsOn
+		if date.Before(today) {
+			date = today
+		}
+		var err error
+		reply, err = FormatClosureReply(&ClosureReply{
+			Restaurant: restaurant,
+			Closure:    closure,
+			Date:       date,
+			Sisters:    u.openSisters(ctx, closure.RestaurantID, date),
+		})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	log.Info(ctx, "restaurant closure created",
+		zap.String("restaurantID", closure.RestaurantID),
+		zap.Time("startsOn", closure.StartsOn),
+		zap.Time("reopensOn", closure.ReopensOn),
+		zap.Bool("dryRun", dryRun))
+	return reply, nil
+}
+
+func (u *restaurantClosureUseCase) ListClosures(ctx context.Context, restaurantID string) ([]*domain.RestaurantClosure, error) {
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+	return u.closureRepo.ListByRestaurant(ctx, restaurantID, u.clock.Now())
+}
+
+func (u *restaurantClosureUseCase) DeleteClosure(ctx context.Context, restaurantID, closureID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	closure, err := u.closureRepo.GetByID(ctx, closureID)
+	if err != nil {
+		return err
+	}
+	if closure.RestaurantID != restaurantID {
+		return errors.New(common.ErrRestaurantClosureNotFound)
+	}
+	if err := u.closureRepo.Delete(ctx, closure.ID); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "restaurant closure deleted",
+		zap.String("restaurantID", restaurantID),
+		zap.String("closureID", closureID))
+	return nil
+}
+
+func (u *restaurantClosureUseCase) CheckOpen(ctx context.Context, booking *domain.Booking) error {
+	closure, err := u.closureOn(ctx, booking.RestaurantID, booking.Date)
+	if err != nil || closure == nil {
+		return err
+	}
+
+	restaurant, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(booking.RestaurantID))
+	if err != nil {
+		return err
+	}
+
+	reply := &ClosureReply{
+		Restaurant: restaurant,
+		Closure:    closure,
+		Date:       booking.Date,
+		Sisters:    u.openSisters(ctx, booking.RestaurantID, booking.Date),
+	}
+	message, err := FormatClosureReply(reply)
+	if err != nil {
+		return err
+	}
+
+	if log, err := logger.FromContext(ctx); err == nil {
+		log.Info(ctx, "booking answered by closure auto-reply",
+			zap.String("restaurantID", booking.RestaurantID),
+			zap.String("closureID", closure.ID),
+			zap.Int("sisters", len(reply.Sisters)))
+	}
+	return &RestaurantClosedError{Closure: closure, Reply: message, Sisters: reply.Sisters}
+}
+
+// closureOn returns the closure of the restaurant covering the day of date, nil when it is open.
+func (u *restaurantClosureUseCase) closureOn(ctx context.Context, restaurantID string, date time.Time) (*domain.RestaurantClosure, error) {
+	closures, err := u.closureRepo.ListByRestaurant(ctx, restaurantID, date)
+	if err != nil {
+		return nil, err
+	}
+	return domain.ClosureOn(closures, date), nil
+}
+
+// openSisters returns the sister restaurants open on the day of date, nearest to the restaurant
+// first, those not placed on the map last. A failure leaves the reply without sisters.
+func (u *restaurantClosureUseCase) openSisters(ctx context.Context, restaurantID string, date time.Time) []*domain.Restaurant {
+	log, _ := logger.FromContext(ctx)
+
+	sisters, err := u.restaurantRepo.ListSisters(ctx, domain.RestaurantID(restaurantID), 0, maxSisterRestaurants)
+	if err != nil {
+		log.Warn(ctx, "failed to list sister restaurants for closure reply",
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return nil
+	}
+	if len(sisters) == 0 {
+		return nil
+	}
+
+	sisterIDs := make([]string, 0, len(sisters))
+	for _, sister := range sisters {
+		sisterIDs = append(sisterIDs, sister.ID)
+	}
+	closures, err := u.closureRepo.ListCovering(ctx, sisterIDs, date)
+	if err != nil {
+		log.Warn(ctx, "failed to list closures of sister restaurants for closure reply",
+			zap.String("restaurantID", restaurantID),
+			zap.Error(err))
+		return nil
+	}
+	closed := make(map[string]bool, len(closures))
+	for _, closure := range closures {
+		closed[closure.RestaurantID] = true
+	}
+
+	type candidate struct {
+		restaurant *domain.Restaurant
+		distance   float64
+	}
+
+	origin, _ := u.locationRepo.GetByRestaurant(ctx, restaurantID)
+	candidates := make([]candidate, 0, len(sisters))
+	for _, sister := range sisters {
+		if sister.IsTest || closed[sister.ID] {
+			continue
+		}
+
+		distance := math.Inf(1)
+		if location, err := u.locationRepo.GetByRestaurant(ctx, sister.ID); err == nil && placed(origin) && placed(location) {
+			distance = distanceKm(origin, location)
+		}
+		candidates = append(candidates, candidate{restaurant: sister, distance: distance})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.distance, b.distance)
+	})
+
+	open := make([]*domain.Restaurant, 0, maxClosureSisters)
+	for _, c := range candidates[:min(len(candidates), maxClosureSisters)] {
+		open = append(open, c.restaurant)
+	}
+	return open
+}
+
+// placed reports whether the address of the restaurant was geocoded.
+func placed(location *domain.RestaurantLocation) bool {
+	return location != nil && location.Status == domain.GeocodingDone
+}
+
+// distanceKm is the great-circle distance between the restaurants.
+func distanceKm(a, b *domain.RestaurantLocation) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+type closedRestaurantBookingUseCase struct {
+	BookingUseCase
+	closures RestaurantClosureUseCase
+}
+
+// NewClosedRestaurantBookingUseCase answers the bookings made through bookings for a day their
+// restaurant is closed with *RestaurantClosedError instead of booking them.
+func NewClosedRestaurantBookingUseCase(bookings BookingUseCase, closures RestaurantClosureUseCase) BookingUseCase {
+	return &closedRestaurantBookingUseCase{
+		BookingUseCase: bookings,
+		closures:       closures,
+	}
+}
+
+func (u *closedRestaurantBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	if err := u.closures.CheckOpen(ctx, booking); err != nil {
+		return "", err
+	}
+	return u.BookingUseCase.CreateBooking(ctx, booking)
+}
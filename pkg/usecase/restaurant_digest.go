@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// RestaurantDigest sums up the bookings of a restaurant for one day, with notes about the
+// occasions coming up in the next days.
+type RestaurantDigest struct {
+	RestaurantID string             `json:"restaurant_id"`
+	Date         time.Time          `json:"date"`
+	Bookings     int                `json:"bookings"`
+	Guests       int                `json:"guests"`
+	Pending      int                `json:"pending"`
+	Occasions    []UpcomingOccasion `json:"occasions,omitempty"`
+}
+
+// UpcomingOccasion is a booking celebrating an occasion on the day of the digest or shortly after.
+type UpcomingOccasion struct {
+	BookingID   string                 `json:"booking_id"`
+	Occasion    domain.BookingOccasion `json:"occasion"`
+	Date        time.Time              `json:"date"`
+	Time        string                 `json:"time"`
+	GuestsCount int                    `json:"guests_count"`
+}
+
+type RestaurantDigestUseCase interface {
+	// BuildDailyDigests returns the digest of every restaurant with active bookings on the date or
+	// an occasion coming up within the lookahead.
+	BuildDailyDigests(ctx context.Context, date time.Time) ([]*RestaurantDigest, error)
+
+	// SendDailyDigests notifies every restaurant of its digest and returns how many were sent.
+	// A digest that fails to be sent does not stop the others.
+	SendDailyDigests(ctx context.Context, date time.Time) (int, error)
+}
+
+type restaurantDigestUseCase struct {
+	bookingRepo       repository.BookingRepository
+	notifier          domain.NotificationService
+	occasionLookahead int
+}
+
+// NewRestaurantDigestUseCase creates the use case; occasionLookahead is the number of days after the
+// date of a digest whose occasions are noted in it.
+func NewRestaurantDigestUseCase(
+	bookingRepo repository.BookingRepository,
+	notifier domain.NotificationService,
+	occasionLookahead int,
+) RestaurantDigestUseCase {
+	return &restaurantDigestUseCase{
+		bookingRepo:       bookingRepo,
+		notifier:          notifier,
+		occasionLookahead: max(occasionLookahead, 0),
+	}
+}
+
+func (u *restaurantDigestUseCase) BuildDailyDigests(ctx context.Context, date time.Time) ([]*RestaurantDigest, error) {
+	log, _ := logger.FromContext(ctx)
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	bookings, err := u.bookingRepo.GetActiveBetween(ctx, day, day.AddDate(0, 0, u.occasionLookahead))
+	if err != nil {
+		log.Error(ctx, "failed to get bookings for restaurant digests",
+			zap.Time("date", day),
+			zap.Error(err))
+		return nil, err
+	}
+
+	digests := make([]*RestaurantDigest, 0)
+	byRestaurant := make(map[string]*RestaurantDigest)
+	for _, booking := range bookings {
+		onDay := sameDay(booking.Date, day)
+		if !onDay && booking.Occasion == "" {
+			continue
+		}
+
+		digest, ok := byRestaurant[booking.RestaurantID]
+		if !ok {
+			digest = &RestaurantDigest{RestaurantID: booking.RestaurantID, Date: day}
+			byRestaurant[booking.RestaurantID] = digest
+			digests = append(digests, digest)
+		}
+
+		if onDay {
+			digest.Bookings++
+			digest.Guests += booking.GuestsCount
+			if booking.Status == domain.BookingStatusPending {
+				digest.Pending++
+			}
+		}
+		if booking.Occasion != "" {
+			digest.Occasions = append(digest.Occasions, UpcomingOccasion{
+				BookingID:   booking.ID,
+				Occasion:    booking.Occasion,
+				Date:        booking.Date,
+				Time:        booking.Time,
+				GuestsCount: booking.GuestsCount,
+			})
+		}
+	}
+
+	return digests, nil
+}
+
+func (u *restaurantDigestUseCase) SendDailyDigests(ctx context.Context, date time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	digests, err := u.BuildDailyDigests(ctx, date)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, digest := range digests {
+		title := "Your day ahead: " + digest.Date.Format("2006-01-02")
+		err := u.notifier.NotifyRestaurant(ctx, digest.RestaurantID, domain.NotificationTypeDailyDigest,
+			title, FormatRestaurantDigest(digest), digest.RestaurantID)
+		if err != nil {
+			log.Error(ctx, "failed to send restaurant digest",
+				zap.String("restaurantID", digest.RestaurantID),
+				zap.Error(err))
+			continue
+		}
+		sent++
+	}
+
+	log.Info(ctx, "restaurant digests sent",
+		zap.Time("date", date),
+		zap.Int("sent", sent),
+		zap.Int("digests", len(digests)))
+	return sent, nil
+}
+
+var occasionLabels = map[domain.BookingOccasion]string{
+	domain.BookingOccasionBirthday:    "Birthday",
+	domain.BookingOccasionAnniversary: "Anniversary",
+	domain.BookingOccasionBusiness:    "Business meal",
+}
+
+// FormatRestaurantDigest renders the digest as the message of its notification.
+func FormatRestaurantDigest(digest *RestaurantDigest) string {
+	var b strings.Builder
+
+	switch digest.Bookings {
+	case 0:
+		b.WriteString("No bookings today.")
+	case 1:
+		fmt.Fprintf(&b, "1 booking today, %d guests.", digest.Guests)
+	default:
+		fmt.Fprintf(&b, "%d bookings today, %d guests.", digest.Bookings, digest.Guests)
+	}
+	if digest.Pending > 0 {
+		fmt.Fprintf(&b, " %d awaiting confirmation.", digest.Pending)
+	}
+
+	if len(digest.Occasions) > 0 {
+		b.WriteString("\nOccasions upcoming:")
+		for _, occasion := range digest.Occasions {
+			when := "today"
+			if !sameDay(occasion.Date, digest.Date) {
+				when = occasion.Date.Format("Mon 2006-01-02")
+			}
+			fmt.Fprintf(&b, "\n- %s %s at %s, %d guests", occasionLabels[occasion.Occasion], when, occasion.Time, occasion.GuestsCount)
+		}
+	}
+
+	return b.String()
+}
+
+func sameDay(a, b time.Time) bool {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
@@ -0,0 +1,303 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	restaurantImportBatchSize = 100
+	maxRestaurantImportRows   = 10000
+)
+
+var (
+	ErrInvalidImportFile = errors.New("invalid import file")
+	ErrImportTooLarge    = errors.New("import file has too many rows")
+)
+
+var requiredImportColumns = []string{"name", "address", "cuisine", "contact_email", "contact_phone"}
+
+var importWeekDays = map[string]domain.WeekDay{
+	"mon": domain.Monday,
+	"tue": domain.Tuesday,
+	"wed": domain.Wednesday,
+	"thu": domain.Thursday,
+	"fri": domain.Friday,
+	"sat": domain.Saturday,
+	"sun": domain.Sunday,
+}
+
+// RestaurantImportRowError lists the problems of one CSV line; Row is the line number in the file.
+type RestaurantImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+type RestaurantImportReport struct {
+	Total         int                        `json:"total"`
+	Imported      int                        `json:"imported"`
+	DryRun        bool                       `json:"dry_run"`
+	RestaurantIDs []string                   `json:"restaurant_ids,omitempty"`
+	Errors        []RestaurantImportRowError `json:"errors,omitempty"`
+}
+
+func (r *RestaurantImportReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+type restaurantImportRow struct {
+	restaurant   *domain.Restaurant
+	workingHours []*domain.WorkingHours
+}
+
+// ImportRestaurants reads restaurants with their working hours from CSV. The import is all or
+// nothing: when any row is invalid the report lists the errors and nothing is inserted, otherwise
+// rows are inserted in batches within one transaction, which is rolled back for a dry run.
+//
+// The header must contain name, address, cuisine, contact_email and contact_phone, and may contain
+// description and working_hours, e.g. "mon 10:00-22:00; tue 10:00-22:00; sun closed".
+func (u *restaurantUseCase) ImportRestaurants(ctx context.Context, r io.Reader, dryRun bool) (*RestaurantImportReport, error) {
+	log, _ := logger.FromContext(ctx)
+
+	rows, report, err := parseRestaurantImport(r, u.clock.Now(), u.phoneRegion)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = dryRun
+
+	log.Info(ctx, "importing restaurants",
+		zap.Int("total", report.Total),
+		zap.Int("invalidRows", len(report.Errors)),
+		zap.Bool("dryRun", dryRun))
+
+	if report.HasErrors() {
+		return report, nil
+	}
+
+	run := u.transactor.InTransaction
+	if dryRun {
+		run = u.transactor.DryRun
+	}
+
+	err = run(ctx, func(ctx context.Context) error {
+		slugs := make(map[string]bool, len(rows))
+		for start := 0; start < len(rows); start += restaurantImportBatchSize {
+			batch := rows[start:min(start+restaurantImportBatchSize, len(rows))]
+
+			restaurants := make([]*domain.Restaurant, 0, len(batch))
+			for _, row := range batch {
+				if err := u.assignSlug(ctx, row.restaurant, slugs); err != nil {
+					return err
+				}
+				slugs[row.restaurant.Slug] = true
+				restaurants = append(restaurants, row.restaurant)
+			}
+			if err := u.restaurantRepo.CreateBatch(ctx, restaurants); err != nil {
+				return err
+			}
+
+			hours := make([]*domain.WorkingHours, 0)
+			for _, row := range batch {
+				for _, h := range row.workingHours {
+					h.RestaurantID = row.restaurant.ID
+					hours = append(hours, h)
+				}
+			}
+			if err := u.workingHoursRepo.CreateBatch(ctx, hours); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, "failed to import restaurants", zap.Error(err))
+		return nil, err
+	}
+
+	report.Imported = len(rows)
+	report.RestaurantIDs = make([]string, 0, len(rows))
+	for _, row := range rows {
+		report.RestaurantIDs = append(report.RestaurantIDs, row.restaurant.ID)
+	}
+
+	log.Info(ctx, "restaurants successfully imported",
+		zap.Int("imported", report.Imported),
+		zap.Bool("dryRun", dryRun))
+	return report, nil
+}
+
+func parseRestaurantImport(r io.Reader, validFrom time.Time, phoneRegion string) ([]restaurantImportRow, *RestaurantImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidImportFile, name)
+		}
+	}
+	reader.FieldsPerRecord = len(header)
+
+	report := &RestaurantImportReport{}
+	rows := make([]restaurantImportRow, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		report.Total++
+		if report.Total > maxRestaurantImportRows {
+			return nil, nil, ErrImportTooLarge
+		}
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+			}
+			report.Errors = append(report.Errors, RestaurantImportRowError{Row: parseErr.StartLine, Errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row, rowErrors := parseRestaurantImportRow(field, validFrom, phoneRegion)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, RestaurantImportRowError{Row: line, Errors: rowErrors})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, report, nil
+}
+
+func parseRestaurantImportRow(field func(name string) string, validFrom time.Time, phoneRegion string) (restaurantImportRow, []string) {
+	var rowErrors []string
+	for _, name := range requiredImportColumns {
+		if field(name) == "" {
+			rowErrors = append(rowErrors, name+" is required")
+		}
+	}
+
+	email, phone := field("contact_email"), field("contact_phone")
+	var normalizedEmail, normalizedPhone string
+	if email != "" {
+		var err error
+		if normalizedEmail, err = contact.NormalizeEmail(email); err != nil {
+			rowErrors = append(rowErrors, "contact_email is not a valid email address")
+		}
+	}
+	if phone != "" {
+		var err error
+		if normalizedPhone, err = contact.NormalizePhone(phone, phoneRegion); err != nil {
+			rowErrors = append(rowErrors, "contact_phone is not a valid phone number")
+		}
+	}
+
+	currency := domain.DefaultCurrency
+	if value := field("currency"); value != "" {
+		var ok bool
+		if currency, ok = domain.NormalizeCurrency(value); !ok {
+			rowErrors = append(rowErrors, "currency is not an ISO 4217 code")
+		}
+	}
+
+	workingHours, err := parseImportWorkingHours(field("working_hours"), validFrom)
+	if err != nil {
+		rowErrors = append(rowErrors, "working_hours: "+err.Error())
+	}
+
+	if len(rowErrors) > 0 {
+		return restaurantImportRow{}, rowErrors
+	}
+
+	restaurant := &domain.Restaurant{
+		Name:                   field("name"),
+		Address:                field("address"),
+		Cuisine:                domain.Cuisine(field("cuisine")),
+		Description:            field("description"),
+		ContactEmail:           email,
+		ContactPhone:           phone,
+		Currency:               currency,
+		NormalizedContactEmail: normalizedEmail,
+		NormalizedContactPhone: normalizedPhone,
+	}
+	var invalid *domain.ValidationError
+	if err := restaurant.Validate(); errors.As(err, &invalid) {
+		return restaurantImportRow{}, []string{invalid.Field + " " + invalid.Reason}
+	}
+
+	return restaurantImportRow{
+		restaurant:   restaurant,
+		workingHours: workingHours,
+	}, nil
+}
+
+// parseImportWorkingHours parses "mon 10:00-22:00; sun closed" into working hours starting at validFrom.
+func parseImportWorkingHours(value string, validFrom time.Time) ([]*domain.WorkingHours, error) {
+	hours := make([]*domain.WorkingHours, 0)
+	seen := make(map[domain.WeekDay]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("expected \"<day> <HH:MM-HH:MM|closed>\", got %q", strings.TrimSpace(part))
+		}
+
+		weekDay, ok := importWeekDays[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", fields[0])
+		}
+		if seen[weekDay] {
+			return nil, fmt.Errorf("day %q is listed twice", fields[0])
+		}
+		seen[weekDay] = true
+
+		wh := &domain.WorkingHours{WeekDay: weekDay, ValidFrom: validFrom}
+		if strings.EqualFold(fields[1], "closed") {
+			wh.OpenTime, wh.CloseTime, wh.IsClosed = "00:00", "00:00", true
+			hours = append(hours, wh)
+			continue
+		}
+
+		openTime, closeTime, found := strings.Cut(fields[1], "-")
+		open, openErr := time.Parse("15:04", openTime)
+		closing, closeErr := time.Parse("15:04", closeTime)
+		if !found || openErr != nil || closeErr != nil || !open.Before(closing) {
+			return nil, fmt.Errorf("invalid time range %q", fields[1])
+		}
+		wh.OpenTime, wh.CloseTime = openTime, closeTime
+		hours = append(hours, wh)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// RetentionPolicies sets how long data is kept; a zero period turns the policy off.
+type RetentionPolicies struct {
+	// ReadNotifications is how long notifications are kept after they were read.
+	ReadNotifications time.Duration
+
+	// CompletedBookings is how long completed bookings keep their guest.
+	CompletedBookings time.Duration
+}
+
+// RetentionUseCase purges and anonymizes data past its retention period. Every application of a
+// policy, dry runs included, leaves an audit entry.
+type RetentionUseCase interface {
+	// ApplyRetention applies every enabled policy and returns its runs. A dry run reports how many
+	// records would be affected and changes nothing. A failing policy does not stop the others;
+	// its error is returned along with the runs. Admins only.
+	ApplyRetention(ctx context.Context, dryRun bool) ([]*domain.RetentionRun, error)
+
+	// ListRetentionRuns returns the audit entries, newest first; admins only.
+	ListRetentionRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error)
+}
+
+type retentionUseCase struct {
+	retentionRepo repository.RetentionRepository
+	transactor    repository.Transactor
+	policies      RetentionPolicies
+	clock         clock.Clock
+}
+
+func NewRetentionUseCase(
+	retentionRepo repository.RetentionRepository,
+	transactor repository.Transactor,
+	policies RetentionPolicies,
+	clock clock.Clock,
+) RetentionUseCase {
+	return &retentionUseCase{
+		retentionRepo: retentionRepo,
+		transactor:    transactor,
+		policies:      policies,
+		clock:         clock,
+	}
+}
+
+func (u *retentionUseCase) ApplyRetention(ctx context.Context, dryRun bool) ([]*domain.RetentionRun, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	type policy struct {
+		name   domain.RetentionPolicy
+		period time.Duration
+		apply  func(ctx context.Context, cutoff time.Time) (int, error)
+	}
+	policies := []policy{
+		{domain.RetentionPurgeReadNotifications, u.policies.ReadNotifications, u.retentionRepo.PurgeReadNotifications},
+		{domain.RetentionAnonymizeCompletedBookings, u.policies.CompletedBookings, u.retentionRepo.AnonymizeCompletedBookings},
+	}
+
+	runs := make([]*domain.RetentionRun, 0, len(policies))
+	var errs []error
+	for _, p := range policies {
+		if p.period <= 0 {
+			continue
+		}
+
+		run, err := u.applyPolicy(ctx, p.name, p.period, p.apply, dryRun)
+		if run != nil {
+			runs = append(runs, run)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return runs, errors.Join(errs...)
+}
+
+func (u *retentionUseCase) applyPolicy(
+	ctx context.Context,
+	policy domain.RetentionPolicy,
+	period time.Duration,
+	apply func(ctx context.Context, cutoff time.Time) (int, error),
+	dryRun bool,
+) (*domain.RetentionRun, error) {
+	log, _ := logger.FromContext(ctx)
+
+	started := u.clock.Now()
+	run := &domain.RetentionRun{
+		Policy:    policy,
+		DryRun:    dryRun,
+		Cutoff:    started.Add(-period),
+		StartedAt: started,
+	}
+
+	// A dry run applies the policy the same way and rolls it back.
+	execute := u.transactor.InTransaction
+	if dryRun {
+		execute = u.transactor.DryRun
+	}
+	applyErr := execute(ctx, func(ctx context.Context) error {
+		var err error
+		run.Affected, err = apply(ctx, run.Cutoff)
+		return err
+	})
+	if applyErr != nil {
+		run.Affected = 0
+		run.Error = applyErr.Error()
+	}
+	run.FinishedAt = u.clock.Now()
+
+	if err := u.retentionRepo.CreateRun(ctx, run); err != nil {
+		log.Error(ctx, "failed to record retention run",
+			zap.String("policy", string(policy)),
+			zap.Bool("dryRun", dryRun),
+			zap.Int("affected", run.Affected),
+			zap.Error(err))
+		return run, errors.Join(applyErr, err)
+	}
+
+	if applyErr != nil {
+		log.Error(ctx, "failed to apply retention policy",
+			zap.String("policy", string(policy)),
+			zap.Bool("dryRun", dryRun),
+			zap.Error(applyErr))
+		return run, applyErr
+	}
+
+	log.Info(ctx, "retention policy applied",
+		zap.String("policy", string(policy)),
+		zap.Bool("dryRun", dryRun),
+		zap.Time("cutoff", run.Cutoff),
+		zap.Int("affected", run.Affected))
+	return run, nil
+}
+
+func (u *retentionUseCase) ListRetentionRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return u.retentionRepo.ListRuns(ctx, offset, limit)
+}
@@ -0,0 +1,384 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidReview      = errors.New("invalid review")
+	ErrReviewNotAllowed   = errors.New("booking cannot be reviewed")
+	ErrInvalidReviewFlag  = errors.New("invalid review flag")
+	ErrReviewFlagResolved = errors.New(common.ErrReviewFlagResolved)
+)
+
+const (
+	maxReviewTextLength     = 2000
+	maxReviewReplyLength    = 2000
+	maxReviewFlagReasonSize = 1000
+)
+
+type ReviewUseCase interface {
+	// CreateReview publishes the review of a completed booking by the guest who made it.
+	CreateReview(ctx context.Context, bookingID domain.BookingID, rating int, text string) (*domain.Review, error)
+
+	// ListReviews returns the published reviews of a restaurant, newest first, with their replies.
+	ListReviews(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error)
+
+	// ReplyToReview sets the public reply of the restaurant to one of its reviews, replacing any
+	// earlier reply.
+	ReplyToReview(ctx context.Context, restaurantID, reviewID, text string) (*domain.Review, error)
+
+	// FlagReview asks the admins to arbitrate an abusive review of the restaurant. It fails with
+	// ErrReviewAlreadyFlagged while an earlier flag on the review is still open.
+	FlagReview(ctx context.Context, restaurantID, reviewID, reason string) (*domain.ReviewFlag, error)
+
+	// ListReviewFlags returns the flags with the status, oldest first; admins only.
+	ListReviewFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error)
+
+	// ResolveReviewFlag settles an open flag as upheld, which removes the review, or dismissed;
+	// admins only.
+	ResolveReviewFlag(ctx context.Context, flagID string, status domain.ReviewFlagStatus, note string) (*domain.ReviewFlag, error)
+}
+
+type reviewUseCase struct {
+	reviewRepo      repository.ReviewRepository
+	restaurantRepo  repository.RestaurantRepository
+	bookingRepo     repository.BookingRepository
+	notificationSvc domain.NotificationService
+	transactor      repository.Transactor
+}
+
+func NewReviewUseCase(
+	reviewRepo repository.ReviewRepository,
+	restaurantRepo repository.RestaurantRepository,
+	bookingRepo repository.BookingRepository,
+	notificationSvc domain.NotificationService,
+	transactor repository.Transactor,
+) ReviewUseCase {
+	return &reviewUseCase{
+		reviewRepo:      reviewRepo,
+		restaurantRepo:  restaurantRepo,
+		bookingRepo:     bookingRepo,
+		notificationSvc: notificationSvc,
+		transactor:      transactor,
+	}
+}
+
+func (u *reviewUseCase) CreateReview(ctx context.Context, bookingID domain.BookingID, rating int, text string) (*domain.Review, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if rating < 1 || rating > 5 {
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidReview)
+	}
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > maxReviewTextLength {
+		return nil, fmt.Errorf("%w: text is longer than %d characters", ErrInvalidReview, maxReviewTextLength)
+	}
+
+	// GetByID checks that the booking is accessible to the caller; only its guest may review it,
+	// not the staff of the restaurant.
+	booking, err := u.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if principal, ok := tenant.FromContext(ctx); ok && principal.UserID != booking.UserID {
+		return nil, tenant.ErrAccessDenied
+	}
+	if booking.Status != domain.BookingStatusCompleted {
+		return nil, fmt.Errorf("%w: booking is %s", ErrReviewNotAllowed, booking.Status)
+	}
+
+	review := &domain.Review{
+		RestaurantID: booking.RestaurantID,
+		UserID:       booking.UserID,
+		BookingID:    booking.ID,
+		Rating:       rating,
+		Text:         text,
+		Status:       domain.ReviewStatusPublished,
+	}
+	if err := u.reviewRepo.Create(ctx, review); err != nil {
+		log.Error(ctx, "failed to create review",
+			zap.String("bookingID", booking.ID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	err = u.notificationSvc.NotifyRestaurant(
+		ctx,
+		review.RestaurantID,
+		domain.NotificationTypeNewReview,
+		"New review",
+		fmt.Sprintf("A guest rated their visit %d out of 5", review.Rating),
+		review.ID,
+	)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to restaurant",
+			zap.String("restaurantID", review.RestaurantID),
+			zap.String("reviewID", review.ID),
+			zap.Error(err))
+	}
+
+	log.Info(ctx, "review successfully created",
+		zap.String("reviewID", review.ID),
+		zap.String("bookingID", booking.ID),
+		zap.Int("rating", review.Rating))
+	return review, nil
+}
+
+func (u *reviewUseCase) ListReviews(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+
+	return u.reviewRepo.ListByRestaurant(ctx, restaurantID, offset, limit)
+}
+
+func (u *reviewUseCase) ReplyToReview(ctx context.Context, restaurantID, reviewID, text string) (*domain.Review, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: reply has no text", ErrInvalidReview)
+	}
+	if utf8.RuneCountInString(text) > maxReviewReplyLength {
+		return nil, fmt.Errorf("%w: reply is longer than %d characters", ErrInvalidReview, maxReviewReplyLength)
+	}
+
+	review, err := u.getRestaurantReview(ctx, restaurantID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != domain.ReviewStatusPublished {
+		return nil, fmt.Errorf("%w: review is %s", ErrInvalidReview, review.Status)
+	}
+
+	firstReply := review.Reply == nil
+	reply := &domain.ReviewReply{
+		ReviewID: review.ID,
+		Text:     text,
+	}
+	if err := u.reviewRepo.SaveReply(ctx, reply); err != nil {
+		log.Error(ctx, "failed to save review reply",
+			zap.String("reviewID", review.ID),
+			zap.Error(err))
+		return nil, err
+	}
+	review.Reply = reply
+	review.UpdatedAt = reply.UpdatedAt
+
+	// Guests hear about the reply once; later edits are not announced.
+	if firstReply {
+		err = u.notificationSvc.NotifyUser(
+			ctx,
+			review.UserID,
+			domain.NotificationTypeReviewReply,
+			"The restaurant replied to your review",
+			text,
+			review.ID,
+		)
+		if err != nil {
+			log.Error(ctx, "failed to send notification to user",
+				zap.String("userID", review.UserID),
+				zap.String("reviewID", review.ID),
+				zap.Error(err))
+		}
+	}
+
+	log.Info(ctx, "review reply successfully saved",
+		zap.String("reviewID", review.ID),
+		zap.Bool("firstReply", firstReply))
+	return review, nil
+}
+
+func (u *reviewUseCase) FlagReview(ctx context.Context, restaurantID, reviewID, reason string) (*domain.ReviewFlag, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return nil, tenant.ErrAccessDenied
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidReviewFlag)
+	}
+	if utf8.RuneCountInString(reason) > maxReviewFlagReasonSize {
+		return nil, fmt.Errorf("%w: reason is longer than %d characters", ErrInvalidReviewFlag, maxReviewFlagReasonSize)
+	}
+
+	review, err := u.getRestaurantReview(ctx, restaurantID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != domain.ReviewStatusPublished {
+		return nil, fmt.Errorf("%w: review is %s", ErrInvalidReviewFlag, review.Status)
+	}
+
+	flag := &domain.ReviewFlag{
+		ReviewID:     review.ID,
+		RestaurantID: restaurantID,
+		Reason:       reason,
+	}
+	if err := u.reviewRepo.CreateFlag(ctx, flag); err != nil {
+		log.Error(ctx, "failed to flag review",
+			zap.String("reviewID", review.ID),
+			zap.Error(err))
+		return nil, err
+	}
+	flag.Review = review
+
+	log.Info(ctx, "review flagged for arbitration",
+		zap.String("flagID", flag.ID),
+		zap.String("reviewID", review.ID),
+		zap.String("restaurantID", restaurantID))
+	return flag, nil
+}
+
+func (u *reviewUseCase) ListReviewFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = domain.ReviewFlagStatusOpen
+	}
+	switch status {
+	case domain.ReviewFlagStatusOpen, domain.ReviewFlagStatusUpheld, domain.ReviewFlagStatusDismissed:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidReviewFlag, status)
+	}
+
+	return u.reviewRepo.ListFlags(ctx, status, offset, limit)
+}
+
+func (u *reviewUseCase) ResolveReviewFlag(ctx context.Context, flagID string, status domain.ReviewFlagStatus, note string) (*domain.ReviewFlag, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if status != domain.ReviewFlagStatusUpheld && status != domain.ReviewFlagStatusDismissed {
+		return nil, fmt.Errorf("%w: resolution must be %s or %s", ErrInvalidReviewFlag, domain.ReviewFlagStatusUpheld, domain.ReviewFlagStatusDismissed)
+	}
+
+	flag, err := u.reviewRepo.GetFlag(ctx, flagID)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Status != domain.ReviewFlagStatusOpen {
+		return nil, ErrReviewFlagResolved
+	}
+
+	flag.Status = status
+	flag.ResolutionNote = strings.TrimSpace(note)
+	err = u.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := u.reviewRepo.ResolveFlag(ctx, flag); err != nil {
+			return err
+		}
+		if status == domain.ReviewFlagStatusUpheld {
+			return u.reviewRepo.UpdateStatus(ctx, flag.ReviewID, domain.ReviewStatusRemoved)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error(ctx, "failed to resolve review flag",
+			zap.String("flagID", flag.ID),
+			zap.Error(err))
+		if err.Error() == common.ErrReviewFlagResolved {
+			return nil, ErrReviewFlagResolved
+		}
+		return nil, err
+	}
+
+	review, err := u.reviewRepo.GetByID(ctx, flag.ReviewID)
+	if err != nil {
+		log.Warn(ctx, "failed to reload flagged review",
+			zap.String("reviewID", flag.ReviewID),
+			zap.Error(err))
+	}
+	flag.Review = review
+
+	u.notifyFlagResolved(ctx, flag)
+
+	log.Info(ctx, "review flag resolved",
+		zap.String("flagID", flag.ID),
+		zap.String("reviewID", flag.ReviewID),
+		zap.String("status", string(flag.Status)))
+	return flag, nil
+}
+
+// getRestaurantReview loads a review and hides reviews of other restaurants behind not found.
+func (u *reviewUseCase) getRestaurantReview(ctx context.Context, restaurantID, reviewID string) (*domain.Review, error) {
+	review, err := u.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.RestaurantID != restaurantID {
+		return nil, errors.New(common.ErrReviewNotFound)
+	}
+	return review, nil
+}
+
+// notifyFlagResolved tells the restaurant how its flag was settled and, when the review was taken
+// down, the guest who wrote it.
+func (u *reviewUseCase) notifyFlagResolved(ctx context.Context, flag *domain.ReviewFlag) {
+	log, _ := logger.FromContext(ctx)
+
+	message := "Your flag was dismissed and the review stays published"
+	if flag.Status == domain.ReviewFlagStatusUpheld {
+		message = "Your flag was upheld and the review was removed"
+	}
+	if flag.ResolutionNote != "" {
+		message += ": " + flag.ResolutionNote
+	}
+
+	err := u.notificationSvc.NotifyRestaurant(
+		ctx,
+		flag.RestaurantID,
+		domain.NotificationTypeReviewFlagResolved,
+		"Review flag resolved",
+		message,
+		flag.ReviewID,
+	)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to restaurant",
+			zap.String("restaurantID", flag.RestaurantID),
+			zap.String("flagID", flag.ID),
+			zap.Error(err))
+	}
+
+	if flag.Status != domain.ReviewFlagStatusUpheld || flag.Review == nil {
+		return
+	}
+
+	err = u.notificationSvc.NotifyUser(
+		ctx,
+		flag.Review.UserID,
+		domain.NotificationTypeReviewFlagResolved,
+		"Your review was removed",
+		"An administrator removed your review after a complaint from the restaurant",
+		flag.ReviewID,
+	)
+	if err != nil {
+		log.Error(ctx, "failed to send notification to user",
+			zap.String("userID", flag.Review.UserID),
+			zap.String("flagID", flag.ID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultAvailabilityTarget = 0.999
+	DefaultLatencyTarget      = 0.99
+	DefaultLatencyThreshold   = 500 * time.Millisecond
+
+	// MaxSLOPeriodDays is the longest period of reports listed at once.
+	MaxSLOPeriodDays = 366
+)
+
+var ErrInvalidSLOPeriod = errors.New("invalid SLO period")
+
+// SLOSettings are the service level objectives and who is alerted about them. Targets outside
+// (0, 1) and a threshold that is not positive are replaced with the defaults.
+type SLOSettings struct {
+	Objectives domain.SLOObjectives
+
+	// FastBurnRate is the burn rate of the current and the previous hour at which the users of
+	// AlertUserIDs are alerted; at 14.4 an hour spends 2% of a 30-day budget. Zero turns the
+	// alerts off; hours with fewer than MinRequests requests never raise one.
+	FastBurnRate float64
+	MinRequests  int64
+	AlertUserIDs []string
+}
+
+// SLOPeriod is the daily reports of a period and their sum.
+type SLOPeriod struct {
+	From    time.Time
+	To      time.Time
+	Summary *domain.SLOReport
+	Reports []*domain.SLOReport
+}
+
+// SLOUseCase measures the API against its service level objectives: it counts the answered
+// requests, stores a report of every day and alerts when an error budget burns too fast.
+type SLOUseCase interface {
+	// ObserveRequest counts a request answered with the status after it took elapsed. It only
+	// updates counters in memory, so it is called for every request.
+	ObserveRequest(at time.Time, status int, elapsed time.Duration)
+	// FlushMetrics adds the requests counted since the last flush to the stored metrics; when that
+	// fails they are kept for the next flush.
+	FlushMetrics(ctx context.Context) error
+	// CheckBurnRate measures the current and the previous hour and alerts when they burn an error
+	// budget FastBurnRate times faster than sustainable, once per objective and hour.
+	CheckBurnRate(ctx context.Context, now time.Time) (*domain.SLOReport, error)
+	// GenerateDailyReport stores the report of the day and alerts when an objective was missed.
+	GenerateDailyReport(ctx context.Context, day time.Time) (*domain.SLOReport, error)
+	// GetReports returns the reports of the dates from one to another, both included, with their
+	// sum measured against the current objectives. Admins only.
+	GetReports(ctx context.Context, from, to time.Time) (*SLOPeriod, error)
+}
+
+type sloUseCase struct {
+	sloRepo  repository.SLORepository
+	notifier domain.NotificationService
+	settings SLOSettings
+
+	mu      sync.Mutex
+	pending map[time.Time]*domain.RequestMetrics
+	// alerted is the hour each objective was last alerted about.
+	alerted map[string]time.Time
+	clock   clock.Clock
+}
+
+func NewSLOUseCase(sloRepo repository.SLORepository, notifier domain.NotificationService, clock clock.Clock, settings SLOSettings) SLOUseCase {
+	objectives := &settings.Objectives
+	if objectives.AvailabilityTarget <= 0 || objectives.AvailabilityTarget >= 1 {
+		objectives.AvailabilityTarget = DefaultAvailabilityTarget
+	}
+	if objectives.LatencyTarget <= 0 || objectives.LatencyTarget >= 1 {
+		objectives.LatencyTarget = DefaultLatencyTarget
+	}
+	if objectives.LatencyThreshold <= 0 {
+		objectives.LatencyThreshold = DefaultLatencyThreshold
+	}
+
+	return &sloUseCase{
+		sloRepo:  sloRepo,
+		notifier: notifier,
+		settings: settings,
+		pending:  make(map[time.Time]*domain.RequestMetrics),
+		alerted:  make(map[string]time.Time),
+		clock:    clock,
+	}
+}
+
+func (u *sloUseCase) ObserveRequest(at time.Time, status int, elapsed time.Duration) {
+	hour := at.Truncate(time.Hour)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	metrics, ok := u.pending[hour]
+	if !ok {
+		metrics = &domain.RequestMetrics{Hour: hour}
+		u.pending[hour] = metrics
+	}
+	metrics.Requests++
+	if status >= 500 {
+		metrics.Errors++
+	}
+	if elapsed > u.settings.Objectives.LatencyThreshold {
+		metrics.Slow++
+	}
+}
+
+func (u *sloUseCase) FlushMetrics(ctx context.Context) error {
+	u.mu.Lock()
+	flushed := make([]*domain.RequestMetrics, 0, len(u.pending))
+	for _, metrics := range u.pending {
+		flushed = append(flushed, metrics)
+	}
+	u.pending = make(map[time.Time]*domain.RequestMetrics)
+	u.mu.Unlock()
+
+	if len(flushed) == 0 {
+		return nil
+	}
+
+	if err := u.sloRepo.AddRequestMetrics(ctx, flushed); err != nil {
+		u.mu.Lock()
+		for _, metrics := range flushed {
+			kept, ok := u.pending[metrics.Hour]
+			if !ok {
+				u.pending[metrics.Hour] = metrics
+				continue
+			}
+			kept.Requests += metrics.Requests
+			kept.Errors += metrics.Errors
+			kept.Slow += metrics.Slow
+		}
+		u.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (u *sloUseCase) CheckBurnRate(ctx context.Context, now time.Time) (*domain.SLOReport, error) {
+	hour := now.Truncate(time.Hour)
+
+	metrics, err := u.sloRepo.SumRequestMetrics(ctx, hour.Add(-time.Hour), hour.Add(time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	report := domain.NewSLOReport(hour, *metrics, u.settings.Objectives)
+	if u.settings.FastBurnRate <= 0 || report.Requests < u.settings.MinRequests {
+		return report, nil
+	}
+
+	if report.AvailabilityBurnRate >= u.settings.FastBurnRate && u.firstAlert("availability", hour) {
+		u.alert(ctx, "Availability error budget is burning fast", fmt.Sprintf(
+			"In the last two hours %.2f%% of %d requests failed with a server error, spending the error budget of the %.2f%% availability objective %.1f times faster than sustainable.",
+			percent(1-report.Availability), report.Requests, percent(report.Objectives.AvailabilityTarget), report.AvailabilityBurnRate))
+	}
+	if report.LatencyBurnRate >= u.settings.FastBurnRate && u.firstAlert("latency", hour) {
+		u.alert(ctx, "Latency error budget is burning fast", fmt.Sprintf(
+			"In the last two hours %.2f%% of %d requests took longer than %s, spending the error budget of the %.2f%% latency objective %.1f times faster than sustainable.",
+			percent(1-report.LatencyCompliance), report.Requests, report.Objectives.LatencyThreshold, percent(report.Objectives.LatencyTarget), report.LatencyBurnRate))
+	}
+
+	return report, nil
+}
+
+// firstAlert records that the objective is alerted about in the hour, and reports whether it was
+// not before.
+func (u *sloUseCase) firstAlert(objective string, hour time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if last, ok := u.alerted[objective]; ok && !hour.After(last) {
+		return false
+	}
+	u.alerted[objective] = hour
+	return true
+}
+
+func (u *sloUseCase) GenerateDailyReport(ctx context.Context, day time.Time) (*domain.SLOReport, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+
+	metrics, err := u.sloRepo.SumRequestMetrics(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := domain.NewSLOReport(from, *metrics, u.settings.Objectives)
+	report.CreatedAt = u.clock.Now()
+	if err := u.sloRepo.SaveReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	date := from.Format("02.01.2006")
+	if !report.AvailabilityMet() {
+		u.alert(ctx, "Availability objective missed on "+date, fmt.Sprintf(
+			"On %s %.2f%% of %d requests were answered without a server error against the %.2f%% availability objective, spending %.1f times the error budget of the day.",
+			date, percent(report.Availability), report.Requests, percent(report.Objectives.AvailabilityTarget), report.AvailabilityBurnRate))
+	}
+	if !report.LatencyMet() {
+		u.alert(ctx, "Latency objective missed on "+date, fmt.Sprintf(
+			"On %s %.2f%% of %d requests were answered within %s against the %.2f%% latency objective, spending %.1f times the error budget of the day.",
+			date, percent(report.LatencyCompliance), report.Requests, report.Objectives.LatencyThreshold, percent(report.Objectives.LatencyTarget), report.LatencyBurnRate))
+	}
+
+	return report, nil
+}
+
+// alert notifies every user of AlertUserIDs; a failed notification is logged and does not stop
+// the others.
+func (u *sloUseCase) alert(ctx context.Context, title, message string) {
+	log, _ := logger.FromContext(ctx)
+
+	log.Warn(ctx, title, zap.String("message", message))
+	for _, userID := range u.settings.AlertUserIDs {
+		if err := u.notifier.NotifyUser(ctx, userID, domain.NotificationTypeSLOAlert, title, message, ""); err != nil {
+			log.Error(ctx, common.ErrSendSLOAlert, zap.String("userID", userID), zap.Error(err))
+		}
+	}
+}
+
+func (u *sloUseCase) GetReports(ctx context.Context, from, to time.Time) (*SLOPeriod, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if to.Before(from) || to.Sub(from) >= MaxSLOPeriodDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: from %s to %s, at most %d days", ErrInvalidSLOPeriod,
+			from.Format(time.DateOnly), to.Format(time.DateOnly), MaxSLOPeriodDays)
+	}
+
+	reports, err := u.sloRepo.ListReports(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var total domain.RequestMetrics
+	for _, report := range reports {
+		total.Requests += report.Requests
+		total.Errors += report.Errors
+		total.Slow += report.Slow
+	}
+
+	return &SLOPeriod{
+		From:    from,
+		To:      to,
+		Summary: domain.NewSLOReport(from, total, u.settings.Objectives),
+		Reports: reports,
+	}, nil
+}
+
+func percent(share float64) float64 {
+	return share * 100
+}
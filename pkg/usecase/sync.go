@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+)
+
+const (
+	DefaultSyncLimit = 100
+	MaxSyncLimit     = 1000
+)
+
+// SyncPage is a page of the changes of an entity. Next is the cursor to continue from, the one the
+// page was read after when it is empty.
+type SyncPage struct {
+	Changes []*domain.SyncChange
+	Next    domain.SyncCursor
+	HasMore bool
+}
+
+// SyncUseCase lets clients mirror entities incrementally: they read the changes after the cursor
+// of their previous page until there are no more, and later continue from the last cursor. The
+// database stamps every change, so a record changed again always comes after the cursor.
+type SyncUseCase interface {
+	// ListChanges returns up to limit changes of the entity after since; a limit out of range is
+	// replaced with DefaultSyncLimit or capped at MaxSyncLimit. Admins only.
+	ListChanges(ctx context.Context, entity domain.ExportEntity, since domain.SyncCursor, limit int) (*SyncPage, error)
+}
+
+type syncUseCase struct {
+	syncRepo repository.SyncRepository
+}
+
+func NewSyncUseCase(syncRepo repository.SyncRepository) SyncUseCase {
+	return &syncUseCase{
+		syncRepo: syncRepo,
+	}
+}
+
+func (u *syncUseCase) ListChanges(ctx context.Context, entity domain.ExportEntity, since domain.SyncCursor, limit int) (*SyncPage, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if !entity.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExportEntity, entity)
+	}
+
+	switch {
+	case limit <= 0:
+		limit = DefaultSyncLimit
+	case limit > MaxSyncLimit:
+		limit = MaxSyncLimit
+	}
+
+	// One change more than asked tells whether there are more.
+	changes, err := u.syncRepo.ListChanges(ctx, entity, since, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SyncPage{Changes: changes, Next: since}
+	if len(changes) > limit {
+		page.Changes = changes[:limit]
+		page.HasMore = true
+	}
+	if len(page.Changes) > 0 {
+		page.Next = page.Changes[len(page.Changes)-1].CursorAfter()
+	}
+	return page, nil
+}
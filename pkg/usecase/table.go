@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+// ErrTableUnavailable is returned for a booking asking for a table that is not a free table of its
+// restaurant fitting its party.
+var ErrTableUnavailable = errors.New(common.ErrTableUnavailable)
+
+// TableUseCase keeps the floor plans of restaurants, the tables their bookings are seated at.
+type TableUseCase interface {
+	// CreateTable adds a table to the floor plan of its restaurant, for its staff.
+	CreateTable(ctx context.Context, table *domain.Table) error
+
+	// ListTables returns the tables of the restaurant ordered by zone and name; guests see them to
+	// ask for one.
+	ListTables(ctx context.Context, restaurantID string) ([]*domain.Table, error)
+
+	GetTable(ctx context.Context, restaurantID, tableID string) (*domain.Table, error)
+
+	// UpdateTable replaces the name, seats, zone and flags of a table of the restaurant. Bookings
+	// seated at it keep it, even when it seats fewer guests now.
+	UpdateTable(ctx context.Context, table *domain.Table) error
+
+	// DeleteTable removes a table of the restaurant. Fails with common.ErrTableBooked while a
+	// pending or confirmed booking from today on is seated at it.
+	DeleteTable(ctx context.Context, restaurantID, tableID string) error
+
+	// OptimizeSeating seats the pending and confirmed bookings of the day of now that are yet to
+	// start again, restaurant by restaurant, at the tables that fit their parties and preferences
+	// best. Bookings that started and bookings at the table their guest asked for stay where they
+	// are. It returns how many bookings moved; it is for admins and the pass before each service.
+	OptimizeSeating(ctx context.Context, now time.Time) (int, error)
+}
+
+type tableUseCase struct {
+	tableRepo      repository.TableRepository
+	restaurantRepo repository.RestaurantRepository
+	bookingRepo    repository.BookingRepository
+}
+
+func NewTableUseCase(tableRepo repository.TableRepository, restaurantRepo repository.RestaurantRepository, bookingRepo repository.BookingRepository) TableUseCase {
+	return &tableUseCase{
+		tableRepo:      tableRepo,
+		restaurantRepo: restaurantRepo,
+		bookingRepo:    bookingRepo,
+	}
+}
+
+func (u *tableUseCase) CreateTable(ctx context.Context, table *domain.Table) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(table.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	normalizeTable(table)
+	if err := table.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(table.RestaurantID)); err != nil {
+		return err
+	}
+
+	if err := u.tableRepo.Create(ctx, table); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "table created",
+		zap.String("restaurantID", table.RestaurantID),
+		zap.String("tableID", table.ID),
+		zap.Int("seats", table.Seats))
+	return nil
+}
+
+func (u *tableUseCase) ListTables(ctx context.Context, restaurantID string) ([]*domain.Table, error) {
+	return u.tableRepo.ListByRestaurant(ctx, restaurantID)
+}
+
+func (u *tableUseCase) GetTable(ctx context.Context, restaurantID, tableID string) (*domain.Table, error) {
+	table, err := u.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if table.RestaurantID != restaurantID {
+		return nil, errors.New(common.ErrTableNotFound)
+	}
+	return table, nil
+}
+
+func (u *tableUseCase) UpdateTable(ctx context.Context, table *domain.Table) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(table.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	normalizeTable(table)
+	if err := table.Validate(); err != nil {
+		return err
+	}
+	if _, err := u.GetTable(ctx, table.RestaurantID, table.ID); err != nil {
+		return err
+	}
+
+	if err := u.tableRepo.Update(ctx, table); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "table updated",
+		zap.String("restaurantID", table.RestaurantID),
+		zap.String("tableID", table.ID),
+		zap.Int("seats", table.Seats))
+	return nil
+}
+
+func (u *tableUseCase) DeleteTable(ctx context.Context, restaurantID, tableID string) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(restaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	if _, err := u.GetTable(ctx, restaurantID, tableID); err != nil {
+		return err
+	}
+	if err := u.tableRepo.Delete(ctx, tableID); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "table deleted",
+		zap.String("restaurantID", restaurantID),
+		zap.String("tableID", tableID))
+	return nil
+}
+
+func (u *tableUseCase) OptimizeSeating(ctx context.Context, now time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	if err := requireAdmin(ctx); err != nil {
+		return 0, err
+	}
+
+	// Bookings are seated by the wall clock of their restaurant.
+	wall := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	restaurantIDs, err := u.tableRepo.ListSeatedRestaurants(ctx, date)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, restaurantID := range restaurantIDs {
+		n, err := u.reseat(ctx, restaurantID, date, wall, now)
+		if err != nil {
+			// The seating of a restaurant whose bookings changed meanwhile is left for the next pass.
+			log.Warn(ctx, "failed to optimize seating",
+				zap.String("restaurantID", restaurantID),
+				zap.Error(err))
+			continue
+		}
+		moved += n
+	}
+
+	log.Info(ctx, "seating optimized",
+		zap.Time("date", date),
+		zap.Int("restaurants", len(restaurantIDs)),
+		zap.Int("moved", moved))
+	return moved, nil
+}
+
+// reseat seats the bookings of the restaurant on the date that start after wall again, around the
+// bookings that stay, and returns how many moved.
+func (u *tableUseCase) reseat(ctx context.Context, restaurantID string, date, wall, now time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	tables, err := u.tableRepo.ListByRestaurant(ctx, restaurantID)
+	if err != nil || len(tables) == 0 {
+		return 0, err
+	}
+	bookings, err := u.bookingRepo.GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurantID), date)
+	if err != nil {
+		return 0, err
+	}
+	holds, err := u.tableRepo.ListHolds(ctx, restaurantID, date)
+	if err != nil {
+		return 0, err
+	}
+
+	movable := make([]*domain.Booking, 0, len(bookings))
+	moving := make(map[string]bool)
+	for _, booking := range bookings {
+		if booking.TableID == "" || booking.TableRequested ||
+			booking.Status != domain.BookingStatusPending && booking.Status != domain.BookingStatusConfirmed {
+			continue
+		}
+		if start, _, ok := booking.Seating(); !ok || !start.After(wall) {
+			continue
+		}
+		movable = append(movable, booking)
+		moving[booking.ID] = true
+	}
+	staying := make([]domain.TableHold, 0, len(holds))
+	for _, hold := range holds {
+		if !moving[hold.BookingID] {
+			staying = append(staying, hold)
+		}
+	}
+
+	moves := domain.OptimizeSeating(tables, staying, movable)
+	if len(moves) == 0 {
+		return 0, nil
+	}
+	if err := u.tableRepo.Reseat(ctx, moves, now); err != nil {
+		return 0, err
+	}
+
+	log.Info(ctx, "bookings reseated",
+		zap.String("restaurantID", restaurantID),
+		zap.Time("date", date),
+		zap.Int("moved", len(moves)))
+	return len(moves), nil
+}
+
+// normalizeTable trims the name and the zone of the table.
+func normalizeTable(table *domain.Table) {
+	table.Name = strings.TrimSpace(table.Name)
+	table.Zone = strings.TrimSpace(table.Zone)
+}
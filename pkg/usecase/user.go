@@ -3,8 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
-	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
@@ -18,7 +19,7 @@ var (
 )
 
 type UserUseCase interface {
-	GetUser(ctx context.Context, id string) (*domain.User, error)
+	GetUser(ctx context.Context, id domain.UserID) (*domain.User, error)
 
 	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
 
@@ -28,21 +29,48 @@ type UserUseCase interface {
 }
 
 type userUseCase struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	phoneRegion string
+	clock       clock.Clock
 }
 
-func NewUserUseCase(userRepo repository.UserRepository) UserUseCase {
+// NewUserUseCase creates the user use case; phones written without a country code are read as
+// numbers of phoneRegion.
+func NewUserUseCase(userRepo repository.UserRepository, phoneRegion string, clock clock.Clock) UserUseCase {
 	return &userUseCase{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		phoneRegion: phoneRegion,
+		clock:       clock,
 	}
 }
 
-func (u *userUseCase) GetUser(ctx context.Context, id string) (*domain.User, error) {
+func (u *userUseCase) GetUser(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	return u.userRepo.GetByID(ctx, id)
 }
 
 func (u *userUseCase) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
-	return u.userRepo.GetByEmail(ctx, email)
+	normalized, err := contact.NormalizeEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	return u.userRepo.GetByEmail(ctx, normalized)
+}
+
+// normalizeContacts validates the email and phone of the user and sets their normalized forms.
+func (u *userUseCase) normalizeContacts(user *domain.User) error {
+	email, err := contact.NormalizeEmail(user.Email)
+	if err != nil {
+		return err
+	}
+
+	phone, err := contact.NormalizePhone(user.Phone, u.phoneRegion)
+	if err != nil {
+		return err
+	}
+
+	user.NormalizedEmail = email
+	user.NormalizedPhone = phone
+	return nil
 }
 
 func (u *userUseCase) CreateUser(ctx context.Context, user *domain.User) (string, error) {
@@ -51,14 +79,18 @@ func (u *userUseCase) CreateUser(ctx context.Context, user *domain.User) (string
 		zap.String("email", user.Email),
 		zap.String("name", user.Name))
 
-	existingUser, err := u.userRepo.GetByEmail(ctx, user.Email)
+	if err := u.normalizeContacts(user); err != nil {
+		return "", err
+	}
+
+	existingUser, err := u.userRepo.GetByEmail(ctx, user.NormalizedEmail)
 	if err == nil && existingUser != nil {
 		log.Warn(ctx, "attempt to create user with existing email",
 			zap.String("email", user.Email))
 		return "", ErrEmailExists
 	}
 
-	now := time.Now()
+	now := u.clock.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
@@ -81,7 +113,11 @@ func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) error {
 		zap.String("userID", user.ID),
 		zap.String("email", user.Email))
 
-	existingUser, err := u.userRepo.GetByID(ctx, user.ID)
+	if err := u.normalizeContacts(user); err != nil {
+		return err
+	}
+
+	existingUser, err := u.userRepo.GetByID(ctx, domain.UserID(user.ID))
 	if err != nil {
 		log.Error(ctx, "failed to get user by ID",
 			zap.String("userID", user.ID),
@@ -94,8 +130,8 @@ func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) error {
 		return ErrUserNotFound
 	}
 
-	if existingUser.Email != user.Email {
-		userWithSameEmail, err := u.userRepo.GetByEmail(ctx, user.Email)
+	if existingUser.NormalizedEmail != user.NormalizedEmail {
+		userWithSameEmail, err := u.userRepo.GetByEmail(ctx, user.NormalizedEmail)
 		if err == nil && userWithSameEmail != nil && userWithSameEmail.ID != user.ID {
 			log.Warn(ctx, "attempt to update user with email that already exists",
 				zap.String("email", user.Email),
@@ -105,7 +141,7 @@ func (u *userUseCase) UpdateUser(ctx context.Context, user *domain.User) error {
 		}
 	}
 
-	user.UpdatedAt = time.Now()
+	user.UpdatedAt = u.clock.Now()
 	user.CreatedAt = existingUser.CreatedAt
 
 	if err := u.userRepo.Update(ctx, user); err != nil {
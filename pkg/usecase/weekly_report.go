@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// weeklyReportTemplate is the body of the weekly report email.
+var weeklyReportTemplate = template.Must(template.New("weekly_report").Funcs(template.FuncMap{
+	"date":     func(t time.Time) string { return t.Format("Mon 2006-01-02") },
+	"signed":   func(n int) string { return fmt.Sprintf("%+d", n) },
+	"percent":  func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	"rating":   func(f float64) string { return fmt.Sprintf("%.1f", f) },
+	"duration": formatResponseTime,
+}).Parse(`Weekly report for {{.Current.RestaurantName}}, {{date .Current.From}} to {{date .LastDay}}
+
+Bookings: {{.Current.Bookings}} ({{signed .BookingsChange}} on the week before), {{.Current.Guests}} guests
+Occupancy: {{percent .Current.Occupancy}} of the seats offered{{if .Previous.SeatsOffered}} ({{percent .Previous.Occupancy}} the week before){{end}}
+Cancellations: {{.Current.Cancellations}}{{if .Current.Bookings}} of {{.Current.Bookings}} bookings{{end}}
+{{if .Current.Reviews}}Rating: {{rating .Current.AverageRating}} from {{.Current.Reviews}} new reviews{{if .Previous.Reviews}} ({{rating .Previous.AverageRating}} the week before){{end}}
+{{else}}Rating: no new reviews
+{{end}}{{if .Current.Responses}}Response time: {{duration .Current.AverageResponseTime}} on average to answer {{.Current.Responses}} booking requests
+{{else}}Response time: no booking requests answered
+{{end}}
+You get this report every week. To stop it, turn off the email channel of "weekly_report" in your notification settings.
+`))
+
+// WeeklyReport is the performance of a restaurant over a week, next to the week before.
+type WeeklyReport struct {
+	Current  *domain.RestaurantPerformance
+	Previous *domain.RestaurantPerformance
+}
+
+// LastDay is the last day of the week of the report.
+func (r *WeeklyReport) LastDay() time.Time {
+	return r.Current.To.AddDate(0, 0, -1)
+}
+
+func (r *WeeklyReport) BookingsChange() int {
+	return r.Current.Bookings - r.Previous.Bookings
+}
+
+type WeeklyReportUseCase interface {
+	// BuildWeeklyReports returns the report of every live restaurant for the seven days before the
+	// date.
+	BuildWeeklyReports(ctx context.Context, date time.Time) ([]*WeeklyReport, error)
+
+	// SendWeeklyReports emails every restaurant its report, unless it turned the email of
+	// NotificationTypeWeeklyReport off, and returns how many were sent. A report held back by the
+	// quiet hours of the restaurant is sent once they end and counts as sent. A report that fails
+	// to be sent does not stop the others.
+	SendWeeklyReports(ctx context.Context, date time.Time) (int, error)
+}
+
+type weeklyReportUseCase struct {
+	performanceRepo repository.RestaurantPerformanceRepository
+	settingsRepo    repository.NotificationSettingsRepository
+	emailService    EmailService
+	deferrals       NotificationDeferralUseCase
+}
+
+// NewWeeklyReportUseCase creates the weekly report use case; reports are sent regardless of quiet
+// hours when deferrals is nil.
+func NewWeeklyReportUseCase(
+	performanceRepo repository.RestaurantPerformanceRepository,
+	settingsRepo repository.NotificationSettingsRepository,
+	emailService EmailService,
+	deferrals NotificationDeferralUseCase,
+) WeeklyReportUseCase {
+	return &weeklyReportUseCase{
+		performanceRepo: performanceRepo,
+		settingsRepo:    settingsRepo,
+		emailService:    emailService,
+		deferrals:       deferrals,
+	}
+}
+
+func (u *weeklyReportUseCase) BuildWeeklyReports(ctx context.Context, date time.Time) ([]*WeeklyReport, error) {
+	to := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	from := to.AddDate(0, 0, -7)
+
+	current, err := u.performanceRepo.GetBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := u.performanceRepo.GetBetween(ctx, from.AddDate(0, 0, -7), from)
+	if err != nil {
+		return nil, err
+	}
+
+	previousByRestaurant := make(map[string]*domain.RestaurantPerformance, len(previous))
+	for _, performance := range previous {
+		previousByRestaurant[performance.RestaurantID] = performance
+	}
+
+	reports := make([]*WeeklyReport, 0, len(current))
+	for _, performance := range current {
+		before, ok := previousByRestaurant[performance.RestaurantID]
+		if !ok {
+			before = &domain.RestaurantPerformance{
+				RestaurantID: performance.RestaurantID,
+				From:         from.AddDate(0, 0, -7),
+				To:           from,
+			}
+		}
+		reports = append(reports, &WeeklyReport{Current: performance, Previous: before})
+	}
+
+	return reports, nil
+}
+
+func (u *weeklyReportUseCase) SendWeeklyReports(ctx context.Context, date time.Time) (int, error) {
+	log, _ := logger.FromContext(ctx)
+
+	reports, err := u.BuildWeeklyReports(ctx, date)
+	if err != nil {
+		log.Error(ctx, "failed to build weekly reports", zap.Time("date", date), zap.Error(err))
+		return 0, err
+	}
+
+	sent := 0
+	for _, report := range reports {
+		restaurantID := report.Current.RestaurantID
+		if report.Current.ContactEmail == "" {
+			continue
+		}
+		settings, ok := u.emailSettings(ctx, restaurantID)
+		if !ok {
+			continue
+		}
+
+		body, err := FormatWeeklyReport(report)
+		if err != nil {
+			log.Error(ctx, common.ErrRenderWeeklyReport, zap.String("restaurantID", restaurantID), zap.Error(err))
+			continue
+		}
+
+		subject := "Your week in review: " + report.LastDay().Format("2006-01-02")
+		if u.deferred(ctx, settings, restaurantID, subject, body) {
+			sent++
+			continue
+		}
+		if err := u.emailService.SendEmail(report.Current.ContactEmail, subject, body); err != nil {
+			log.Error(ctx, "failed to send weekly report",
+				zap.String("restaurantID", restaurantID),
+				zap.Error(err))
+			continue
+		}
+		sent++
+	}
+
+	log.Info(ctx, "weekly reports sent",
+		zap.Time("date", date),
+		zap.Int("sent", sent),
+		zap.Int("reports", len(reports)))
+	return sent, nil
+}
+
+// emailSettings returns the notification settings of the restaurant and whether it allows the
+// report email. The report is sent without settings when they cannot be read, like the other
+// notification emails.
+func (u *weeklyReportUseCase) emailSettings(ctx context.Context, restaurantID string) (*domain.NotificationSettings, bool) {
+	settings, err := u.settingsRepo.Get(ctx, domain.RecipientTypeRestaurant, restaurantID)
+	if err != nil {
+		if log, logErr := logger.FromContext(ctx); logErr == nil {
+			log.Warn(ctx, "failed to get notification settings, sending weekly report anyway",
+				zap.String("restaurantID", restaurantID),
+				zap.Error(err))
+		}
+		return nil, true
+	}
+	return settings, settings.Allows(domain.NotificationTypeWeeklyReport, domain.NotificationChannelEmail)
+}
+
+// deferred holds the report back until the quiet hours of the restaurant end and reports whether
+// it did; a report that cannot be deferred is sent right away.
+func (u *weeklyReportUseCase) deferred(ctx context.Context, settings *domain.NotificationSettings, restaurantID, subject, body string) bool {
+	if settings == nil || u.deferrals == nil {
+		return false
+	}
+
+	deferred, err := u.deferrals.Defer(ctx, settings, &domain.DeferredNotification{
+		NotificationType: domain.NotificationTypeWeeklyReport,
+		Channel:          domain.NotificationChannelEmail,
+		Title:            subject,
+		Message:          body,
+		RelatedID:        restaurantID,
+	})
+	if err != nil {
+		if log, logErr := logger.FromContext(ctx); logErr == nil {
+			log.Warn(ctx, "failed to defer weekly report, sending it now",
+				zap.String("restaurantID", restaurantID),
+				zap.Error(err))
+		}
+		return false
+	}
+	return deferred
+}
+
+// FormatWeeklyReport renders the report as the body of its email.
+func FormatWeeklyReport(report *WeeklyReport) (string, error) {
+	var b strings.Builder
+	if err := weeklyReportTemplate.Execute(&b, report); err != nil {
+		return "", fmt.Errorf("%s: %w", common.ErrRenderWeeklyReport, err)
+	}
+	return b.String(), nil
+}
+
+// formatResponseTime rounds to minutes, as the seconds a restaurant takes to answer do not matter.
+func formatResponseTime(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
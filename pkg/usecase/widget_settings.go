@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"go.uber.org/zap"
+)
+
+// WidgetSettingsUseCase keeps the settings of the booking widget restaurants embed into their
+// websites: its theme colors, the party size it preselects and its locale.
+type WidgetSettingsUseCase interface {
+	// GetWidgetSettings returns the settings of the restaurant, the defaults when it never saved
+	// any. Anyone may read them, as the widget does.
+	GetWidgetSettings(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error)
+
+	// UpdateWidgetSettings replaces the settings of the restaurant, failing with an error
+	// wrapping domain.ErrInvalidWidgetSettings when they don't validate. Restaurant staff only.
+	UpdateWidgetSettings(ctx context.Context, settings *domain.WidgetSettings) error
+}
+
+type widgetSettingsUseCase struct {
+	settingsRepo   repository.WidgetSettingsRepository
+	restaurantRepo repository.RestaurantRepository
+}
+
+func NewWidgetSettingsUseCase(
+	settingsRepo repository.WidgetSettingsRepository,
+	restaurantRepo repository.RestaurantRepository,
+) WidgetSettingsUseCase {
+	return &widgetSettingsUseCase{
+		settingsRepo:   settingsRepo,
+		restaurantRepo: restaurantRepo,
+	}
+}
+
+func (u *widgetSettingsUseCase) GetWidgetSettings(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error) {
+	settings, err := u.settingsRepo.Get(ctx, restaurantID)
+	if err == nil {
+		return settings, nil
+	}
+	if err.Error() != common.ErrWidgetSettingsNotFound {
+		return nil, err
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(restaurantID)); err != nil {
+		return nil, err
+	}
+	return domain.DefaultWidgetSettings(restaurantID), nil
+}
+
+func (u *widgetSettingsUseCase) UpdateWidgetSettings(ctx context.Context, settings *domain.WidgetSettings) error {
+	log, _ := logger.FromContext(ctx)
+
+	if principal, ok := tenant.FromContext(ctx); ok && !principal.CanAccessRestaurant(settings.RestaurantID) {
+		return tenant.ErrAccessDenied
+	}
+
+	if err := settings.Normalize(); err != nil {
+		return err
+	}
+
+	if _, err := u.restaurantRepo.GetByID(ctx, domain.RestaurantID(settings.RestaurantID)); err != nil {
+		return err
+	}
+
+	if err := u.settingsRepo.Save(ctx, settings); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "widget settings updated", zap.String("restaurantID", settings.RestaurantID))
+	return nil
+}
@@ -0,0 +1,81 @@
+package auth_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswords(t *testing.T) {
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse", hash)
+
+	assert.True(t, auth.CheckPassword(hash, "correct horse"))
+	assert.False(t, auth.CheckPassword(hash, "correct horse "))
+	assert.False(t, auth.CheckPassword("", "correct horse"), "an account without a password never signs in")
+
+	_, err = auth.HashPassword(strings.Repeat("a", auth.MaxPasswordLength+1))
+	assert.ErrorIs(t, err, auth.ErrPasswordTooLong)
+}
+
+func TestSigner(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	signer := auth.NewSigner("secret", "restaurant-booking")
+
+	token, err := signer.Sign(auth.Claims{
+		Subject:       "account1",
+		IssuedAt:      now.Unix(),
+		ExpiresAt:     now.Add(15 * time.Minute).Unix(),
+		Kind:          "restaurant",
+		RestaurantIDs: []string{"rest1"},
+		Roles:         []string{"restaurant_staff"},
+	})
+	require.NoError(t, err)
+
+	claims, err := signer.Verify(token, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "account1", claims.Subject)
+	assert.Equal(t, "restaurant-booking", claims.Issuer)
+	assert.Equal(t, []string{"rest1"}, claims.RestaurantIDs)
+
+	_, err = signer.Verify(token, now.Add(15*time.Minute))
+	assert.ErrorIs(t, err, auth.ErrTokenExpired)
+
+	_, err = auth.NewSigner("other secret", "restaurant-booking").Verify(token, now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = auth.NewSigner("secret", "someone-else").Verify(token, now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	parts := strings.Split(token, ".")
+	forged, err := auth.NewSigner("secret", "restaurant-booking").Sign(auth.Claims{
+		Subject:   "account1",
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Roles:     []string{"admin"},
+	})
+	require.NoError(t, err)
+	tampered := parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2]
+	_, err = signer.Verify(tampered, now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken, "the payload of another token doesn't match the signature")
+
+	_, err = signer.Verify("not a token", now)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestRefreshTokens(t *testing.T) {
+	token, hash, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+
+	other, _, err := auth.NewRefreshToken()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, token, hash, "only the hash is stored")
+	assert.Equal(t, hash, auth.HashRefreshToken(token))
+}
@@ -0,0 +1,125 @@
+// Package bench_test holds the benchmarks of the hot paths. Run them with allocations reported:
+//
+//	make bench
+//
+// The Postgres variants run only with BENCH_POSTGRES=true, against the migrated database the
+// POSTGRES_* variables point at.
+package bench_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/adapters/zap_adapter"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// benchSlot is booked by every benchmark; its capacity is never run out of.
+const benchSlot = "19:00"
+
+// newBenchContext returns a context with the production logger writing to nowhere, so that the
+// cost of the log lines of a request is measured without flooding the output.
+func newBenchContext(b *testing.B) context.Context {
+	config := zap.NewProductionConfig()
+	config.OutputPaths = []string{os.DevNull}
+	config.ErrorOutputPaths = []string{os.DevNull}
+
+	log, err := zap_adapter.NewZapLoggerFactoryWithConfig(config).NewLogger()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return logger.NewContext(context.Background(), log)
+}
+
+// newBookingUseCase composes the booking use cases as cmd/server does, the in-memory abuse
+// monitoring aside.
+func newBookingUseCase(
+	bookingRepo repository.BookingRepository,
+	availabilityRepo repository.AvailabilityRepository,
+	tableRepo repository.TableRepository,
+	quotaRepo repository.QuotaRepository,
+	incentiveRepo repository.IncentiveRepository,
+	restaurantRepo repository.RestaurantRepository,
+	transactor repository.Transactor,
+	notifier domain.NotificationService,
+) usecase.BookingUseCase {
+	quotas := usecase.NewQuotaUseCase(quotaRepo, restaurantRepo, transactor, map[domain.Plan]domain.QuotaLimits{
+		domain.PlanFree: {ActiveSlots: 50, MonthlyBookings: 300},
+		domain.PlanPro:  {},
+	})
+	incentives := usecase.NewIncentiveUseCase(incentiveRepo, bookingRepo)
+
+	return usecase.NewQuotaLimitedBookingUseCase(
+		usecase.NewIncentiveBookingUseCase(usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notifier, transactor, clock.System{}), incentives, clock.System{}), quotas, clock.System{})
+}
+
+// benchIncentiveRules are evaluated for every booking; the first matches none of them, so only
+// the evaluation is paid for.
+func benchIncentiveRules() []*domain.IncentiveRule {
+	return []*domain.IncentiveRule{
+		{ID: uuid.New().String(), Name: "First booking", Effect: domain.IncentiveWaiveDeposit, Conditions: domain.IncentiveConditions{FirstBooking: true}, Enabled: true},
+		{ID: uuid.New().String(), Name: "Large party", Effect: domain.IncentiveBonusPoints, Points: 100, Conditions: domain.IncentiveConditions{MinGuests: 8}, Enabled: true},
+	}
+}
+
+// runCreateBooking books the slot of the restaurant on the date b.N times, each time for another
+// of 1000 guests, and reports the allocations of a booking.
+func runCreateBooking(b *testing.B, ctx context.Context, bookings usecase.BookingUseCase, restaurantID string, date time.Time) {
+	userIDs := make([]string, 1000)
+	for i := range userIDs {
+		userIDs[i] = uuid.New().String()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	i := 0
+	for b.Loop() {
+		booking := &domain.Booking{
+			RestaurantID: restaurantID,
+			UserID:       userIDs[i%len(userIDs)],
+			Date:         date,
+			Time:         benchSlot,
+			GuestsCount:  2,
+			Comment:      "Table by the window, booking " + strconv.Itoa(i),
+		}
+		if _, err := bookings.CreateBooking(ctx, booking); err != nil {
+			b.Fatal(err)
+		}
+		i++
+	}
+}
+
+func BenchmarkCreateBooking(b *testing.B) {
+	b.Run("memory", func(b *testing.B) {
+		ctx := newBenchContext(b)
+
+		restaurantID := uuid.New().String()
+		date := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+		availabilityRepo := &memoryAvailability{slots: []*domain.Availability{
+			{ID: uuid.New().String(), RestaurantID: restaurantID, Date: date, TimeSlot: "18:00", Capacity: 40},
+			{ID: uuid.New().String(), RestaurantID: restaurantID, Date: date, TimeSlot: benchSlot, Capacity: 1 << 30},
+			{ID: uuid.New().String(), RestaurantID: restaurantID, Date: date, TimeSlot: "20:00", Capacity: 40},
+		}}
+		bookingRepo := newMemoryBookings()
+
+		bookings := newBookingUseCase(bookingRepo, availabilityRepo, &memoryTables{}, &memoryQuotas{},
+			&memoryIncentives{rules: benchIncentiveRules()}, nil, nil, &memoryNotifications{})
+
+		runCreateBooking(b, ctx, bookings, restaurantID, date)
+	})
+
+	b.Run("postgres", func(b *testing.B) {
+		runPostgresCreateBooking(b)
+	})
+}
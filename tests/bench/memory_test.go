@@ -0,0 +1,157 @@
+package bench_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// The in-memory repositories below implement only what creating a booking calls; the embedded
+// interfaces are nil, so anything else panics.
+
+type memoryBookings struct {
+	repository.BookingRepository
+
+	mu       sync.Mutex
+	bookings map[string]*domain.Booking
+}
+
+func newMemoryBookings() *memoryBookings {
+	return &memoryBookings{bookings: make(map[string]*domain.Booking)}
+}
+
+func (r *memoryBookings) Create(_ context.Context, booking *domain.Booking) error {
+	if booking.ID == "" {
+		booking.ID = uuid.New().String()
+	}
+
+	stored := *booking
+	r.mu.Lock()
+	r.bookings[booking.ID] = &stored
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *memoryBookings) UpdateStatus(_ context.Context, id domain.BookingID, status domain.BookingStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, ok := r.bookings[id.String()]
+	if !ok {
+		return errors.New(common.ErrBookingNotFound)
+	}
+	booking.Status = status
+	booking.UpdatedAt = time.Now()
+	return nil
+}
+
+type memoryAvailability struct {
+	repository.AvailabilityRepository
+
+	mu    sync.Mutex
+	slots []*domain.Availability
+}
+
+func (r *memoryAvailability) GetByRestaurantAndDate(_ context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var slots []*domain.Availability
+	for _, slot := range r.slots {
+		if domain.RestaurantID(slot.RestaurantID) == restaurantID && slot.Date.Equal(date) {
+			copied := *slot
+			slots = append(slots, &copied)
+		}
+	}
+	return slots, nil
+}
+
+func (r *memoryAvailability) UpdateReservedSeats(_ context.Context, availabilityID string, delta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, slot := range r.slots {
+		if slot.ID == availabilityID {
+			slot.Reserved += delta
+			return nil
+		}
+	}
+	return errors.New(common.ErrAvailabilityNotFound)
+}
+
+// memoryTables has no tables, as the benchmarked restaurant books by seats.
+type memoryTables struct {
+	repository.TableRepository
+}
+
+func (r *memoryTables) ListByRestaurant(context.Context, string) ([]*domain.Table, error) {
+	return nil, nil
+}
+
+type memoryQuotas struct {
+	repository.QuotaRepository
+}
+
+func (r *memoryQuotas) GetPlan(_ context.Context, restaurantID string) (*domain.RestaurantPlan, error) {
+	return &domain.RestaurantPlan{RestaurantID: restaurantID, Plan: domain.PlanPro}, nil
+}
+
+// CountBookings answers a constant, as counting the growing number of bookings would make the
+// benchmark slower the longer it runs.
+func (r *memoryQuotas) CountBookings(context.Context, string, time.Time, time.Time) (int, error) {
+	return 0, nil
+}
+
+type memoryIncentives struct {
+	repository.IncentiveRepository
+
+	rules []*domain.IncentiveRule
+
+	mu          sync.Mutex
+	evaluations []*domain.IncentiveEvaluation
+}
+
+func (r *memoryIncentives) ListRules(context.Context, bool) ([]*domain.IncentiveRule, error) {
+	return r.rules, nil
+}
+
+func (r *memoryIncentives) CountPreviousBookings(context.Context, string) (int, error) {
+	return 0, nil
+}
+
+func (r *memoryIncentives) CreateEvaluation(_ context.Context, evaluation *domain.IncentiveEvaluation) error {
+	r.mu.Lock()
+	r.evaluations = append(r.evaluations, evaluation)
+	r.mu.Unlock()
+	return nil
+}
+
+type memoryNotifications struct {
+	domain.NotificationService
+
+	mu            sync.Mutex
+	notifications []domain.Notification
+}
+
+func (s *memoryNotifications) NotifyRestaurant(_ context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	s.mu.Lock()
+	s.notifications = append(s.notifications, domain.Notification{
+		ID:            uuid.New().String(),
+		RecipientID:   restaurantID,
+		RecipientType: domain.RecipientTypeRestaurant,
+		Type:          notificationType,
+		Title:         title,
+		Message:       message,
+		RelatedID:     relatedID,
+		CreatedAt:     time.Now(),
+	})
+	s.mu.Unlock()
+	return nil
+}
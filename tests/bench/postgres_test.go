@@ -0,0 +1,93 @@
+package bench_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	pgdb "github.com/flexer2006/case-back-restaurant-go/db/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
+
+	"github.com/google/uuid"
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+// runPostgresCreateBooking benchmarks creating bookings stored in Postgres. The restaurant it
+// books at is deleted afterwards with its slots, bookings and notifications.
+func runPostgresCreateBooking(b *testing.B) {
+	if os.Getenv("BENCH_POSTGRES") != "true" {
+		b.Skip("BENCH_POSTGRES is not true")
+	}
+
+	ctx := newBenchContext(b)
+
+	var cfg configs.PostgresConfig
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		b.Fatal(err)
+	}
+
+	db, err := pgdb.New(ctx, &cfg)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = db.Close(context.Background()) })
+
+	repoFactory := postgres.NewRepositoryFactory(db, idgen.UUID{})
+	restaurantRepo := repoFactory.Restaurant()
+	availabilityRepo := repoFactory.Availability()
+	incentiveRepo := repoFactory.Incentive()
+
+	restaurant := &domain.Restaurant{
+		Name:     "Benchmark Bistro",
+		Slug:     "benchmark-" + uuid.New().String(),
+		Address:  "1 Benchmark Street",
+		Cuisine:  "European",
+		Currency: domain.DefaultCurrency,
+	}
+	if err := restaurantRepo.Create(ctx, restaurant); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { cleanUpRestaurant(b, ctx, db, restaurant.ID) })
+
+	date := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	for _, slot := range []*domain.Availability{
+		{RestaurantID: restaurant.ID, Date: date, TimeSlot: "18:00", Capacity: 40},
+		{RestaurantID: restaurant.ID, Date: date, TimeSlot: benchSlot, Capacity: 1 << 30},
+		{RestaurantID: restaurant.ID, Date: date, TimeSlot: "20:00", Capacity: 40},
+	} {
+		if err := availabilityRepo.SetAvailability(ctx, slot, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, rule := range benchIncentiveRules() {
+		if err := incentiveRepo.CreateRule(ctx, rule); err != nil {
+			b.Fatal(err)
+		}
+		b.Cleanup(func() { _ = incentiveRepo.DeleteRule(ctx, rule.ID) })
+	}
+
+	bookings := newBookingUseCase(repoFactory.Booking(), availabilityRepo, repoFactory.Table(), repoFactory.Quota(), incentiveRepo,
+		restaurantRepo, repoFactory.Transactor(), postgres.NewNotificationService(repoFactory.Notification()))
+
+	runCreateBooking(b, ctx, bookings, restaurant.ID, date)
+}
+
+func cleanUpRestaurant(b *testing.B, ctx context.Context, db pgdb.Database, restaurantID string) {
+	adapter, ok := db.GetPool().(*pgdb.PgxPoolAdapter)
+	if !ok {
+		b.Fatal("unexpected pool type")
+	}
+
+	pool := adapter.GetInternalPool()
+	if _, err := pool.Exec(ctx, "DELETE FROM notifications WHERE recipient_id = $1", restaurantID); err != nil {
+		b.Error(err)
+	}
+	if _, err := pool.Exec(ctx, "DELETE FROM restaurants WHERE id = $1", restaurantID); err != nil {
+		b.Error(err)
+	}
+}
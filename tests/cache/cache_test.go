@@ -0,0 +1,196 @@
+package cache_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/cache"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Expires(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC))
+	store := cache.NewMemory(fake)
+
+	_, ok, err := store.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.Set(ctx, "key", []byte("value"), time.Minute))
+	value, ok, err := store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "value", string(value))
+
+	fake.Advance(time.Minute)
+	_, ok, err = store.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// fakeRedis answers the commands of cache.Redis the way a Redis server does and records them.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+}
+
+func startFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeRedis{listener: listener, password: password, values: make(map[string]string)}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			if args[1] == s.password {
+				authenticated = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "PING":
+			reply = "+PONG\r\n"
+		case args[0] == "SELECT":
+			reply = "+OK\r\n"
+		case args[0] == "SET":
+			s.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if value, ok := s.values[args[1]]; ok {
+				reply = "$" + strconv.Itoa(len(value)) + "\r\n" + value + "\r\n"
+			} else {
+				reply = "$-1\r\n"
+			}
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for range count {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+func TestRedis_SetAndGet(t *testing.T) {
+	ctx := context.Background()
+	server := startFakeRedis(t, "secret")
+
+	store := cache.NewRedis(cache.RedisOptions{Addr: server.listener.Addr().String(), Password: "secret", DB: 2, PoolSize: 2})
+	defer store.Close()
+
+	require.NoError(t, store.Ping(ctx))
+
+	value := "{\"name\":\"Line\r\nbreak\"}"
+	require.NoError(t, store.Set(ctx, "restaurants:city:moscow", []byte(value), 90*time.Second))
+
+	got, ok, err := store.Get(ctx, "restaurants:city:moscow")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, value, string(got), "values are binary safe")
+
+	_, ok, err = store.Get(ctx, "restaurants:city:kazan")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	commands := server.recorded()
+	assert.Equal(t, []string{"AUTH secret", "SELECT 2", "PING"}, commands[:3], "a new connection signs in and selects the database")
+	assert.Contains(t, commands, "SET restaurants:city:moscow "+value+" PX 90000")
+	assert.Len(t, commands, 6, "the connection is reused")
+}
+
+func TestRedis_Errors(t *testing.T) {
+	ctx := context.Background()
+	server := startFakeRedis(t, "secret")
+
+	store := cache.NewRedis(cache.RedisOptions{Addr: server.listener.Addr().String(), Password: "wrong"})
+	err := store.Ping(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "WRONGPASS")
+
+	unreachable := cache.NewRedis(cache.RedisOptions{Addr: "127.0.0.1:1", Timeout: time.Second})
+	_, _, err = unreachable.Get(ctx, "key")
+	assert.Error(t, err)
+}
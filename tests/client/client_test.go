@@ -0,0 +1,191 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/client"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(serverURL string) *client.Client {
+	return client.New(serverURL+"/api/v1/", client.WithRetries(3, time.Millisecond))
+}
+
+func TestCreateRestaurant(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/restaurants", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get(client.HeaderIdempotencyKey))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Test Restaurant", body["name"])
+		assert.Equal(t, []any{"Fact 1"}, body["facts"])
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"restaurant1"}`))
+	}))
+	defer srv.Close()
+
+	id, err := newTestClient(srv.URL).CreateRestaurant(context.Background(), &domain.Restaurant{
+		Name:  "Test Restaurant",
+		Facts: []domain.Fact{{Content: "Fact 1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "restaurant1", id)
+}
+
+func TestListRestaurants_FollowsNextCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/restaurants", r.URL.Path)
+		assert.Equal(t, "city1", r.URL.Query().Get("city_id"))
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set(client.HeaderNextCursor, "after-restaurant2")
+			_, _ = w.Write([]byte(`[{"id":"restaurant1"},{"id":"restaurant2"}]`))
+			return
+		}
+		assert.Equal(t, "after-restaurant2", r.URL.Query().Get("cursor"))
+		_, _ = w.Write([]byte(`[{"id":"restaurant3"}]`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(srv.URL)
+	filter := domain.RestaurantFilter{CityID: "city1"}
+
+	page, err := c.ListRestaurants(context.Background(), filter, usecase.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2)
+	require.True(t, page.HasMore())
+
+	page, err = c.ListRestaurants(context.Background(), filter, usecase.PageRequest{Cursor: page.NextCursor, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "restaurant3", page.Items[0].ID)
+	assert.False(t, page.HasMore())
+}
+
+func TestRetriesReuseIdempotencyKey(t *testing.T) {
+	var attempts atomic.Int32
+	keys := make(chan string, 3)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys <- r.Header.Get(client.HeaderIdempotencyKey)
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer srv.Close()
+
+	ctx := client.WithIdempotencyKey(context.Background(), "key-1")
+	err := newTestClient(srv.URL).CancelBooking(ctx, "booking1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), attempts.Load())
+
+	close(keys)
+	for key := range keys {
+		assert.Equal(t, "key-1", key)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	var attempts atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":"restaurant not found"}`))
+	}))
+	defer srv.Close()
+
+	restaurant, err := newTestClient(srv.URL).GetRestaurant(context.Background(), "missing")
+	assert.Nil(t, restaurant)
+	assert.ErrorIs(t, err, client.ErrNotFound)
+	assert.NotErrorIs(t, err, client.ErrServer)
+
+	var apiErr *client.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "restaurant not found", apiErr.Message)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var attempts atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		assert.Empty(t, r.Header.Get(client.HeaderIdempotencyKey))
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).GetAvailability(context.Background(), "restaurant1", time.Now())
+	assert.ErrorIs(t, err, client.ErrServer)
+	assert.Equal(t, int32(4), attempts.Load())
+}
+
+func TestExportChanges(t *testing.T) {
+	since := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/admin/export", r.URL.Path)
+		assert.Equal(t, since.Format(time.RFC3339Nano), r.URL.Query().Get("since"))
+		assert.Equal(t, "restaurants,bookings", r.URL.Query().Get("entities"))
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"entity":"restaurants","id":"restaurant1","updated_at":"2025-05-01T11:00:00Z","data":{"id":"restaurant1"}}
+{"entity":"bookings","id":"booking1","updated_at":"2025-05-01T12:00:00Z","data":{"id":"booking1"}}
+{"checkpoint":"2025-05-02T00:00:00Z"}
+`))
+	}))
+	defer srv.Close()
+
+	var records []*domain.ExportRecord
+	checkpoint, err := newTestClient(srv.URL).ExportChanges(context.Background(), since,
+		[]domain.ExportEntity{domain.ExportRestaurants, domain.ExportBookings},
+		func(record *domain.ExportRecord) error {
+			records = append(records, record)
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC), checkpoint)
+	require.Len(t, records, 2)
+	assert.Equal(t, domain.ExportRestaurants, records[0].Entity)
+	assert.Equal(t, "booking1", records[1].ID)
+	assert.JSONEq(t, `{"id":"booking1"}`, string(records[1].Data))
+}
+
+func TestExportChanges_Incomplete(t *testing.T) {
+	for name, body := range map[string]string{
+		"cut off": `{"entity":"restaurants","id":"restaurant1","updated_at":"2025-05-01T11:00:00Z","data":{}}` + "\n",
+		"failed":  `{"error":"failed to export changed records"}` + "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			_, err := newTestClient(srv.URL).ExportChanges(context.Background(), time.Time{}, nil,
+				func(*domain.ExportRecord) error { return nil })
+			assert.ErrorIs(t, err, client.ErrIncompleteExport)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	assert.Equal(t, start, fake.Now())
+	assert.Equal(t, start, fake.Now(), "a fake clock stands still")
+
+	fake.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), fake.Now())
+
+	fake.Set(start.AddDate(0, 0, -1))
+	assert.Equal(t, start.AddDate(0, 0, -1), fake.Now())
+}
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	now := clock.System{}.Now()
+
+	assert.False(t, now.Before(before))
+	assert.WithinDuration(t, time.Now(), now, time.Second)
+}
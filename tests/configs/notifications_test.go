@@ -0,0 +1,46 @@
+package configs_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckBookingLinkSecret(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		development bool
+		wantErr     bool
+	}{
+		{name: "empty", secret: "", wantErr: true},
+		{name: "empty in development", secret: "", development: true, wantErr: true},
+		{name: "default", secret: configs.DevelopmentBookingLinkSecret, wantErr: true},
+		{name: "default in development", secret: configs.DevelopmentBookingLinkSecret, development: true},
+		{name: "own secret", secret: "s3cr3t-of-its-own"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := configs.NotificationsConfig{BookingLinkSecret: tt.secret}
+			err := cfg.CheckBookingLinkSecret(tt.development)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Equal(t, common.ErrBookingLinkSecretRequired, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestIsDevelopment(t *testing.T) {
+	assert.True(t, (&configs.Config{Environment: "development"}).IsDevelopment())
+	assert.True(t, (&configs.Config{Environment: " Development "}).IsDevelopment())
+	assert.False(t, (&configs.Config{Environment: "production"}).IsDevelopment())
+	assert.False(t, (&configs.Config{}).IsDevelopment())
+}
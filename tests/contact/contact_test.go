@@ -0,0 +1,71 @@
+package contact_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		raw    string
+		region string
+		want   string
+	}{
+		{"+7 (916) 123-45-67", "RU", "+79161234567"},
+		{"8 916 123 45 67", "RU", "+79161234567"},
+		{"9161234567", "RU", "+79161234567"},
+		{"79161234567", "RU", "+79161234567"},
+		{"8 10 49 30 1234567", "RU", "+49301234567"},
+		{"0049 30 1234567", "FR", "+49301234567"},
+		{"(212) 555-1234", "US", "+12125551234"},
+		{"1-212-555-1234", "us", "+12125551234"},
+		{"020 7946 0958", "GB", "+442079460958"},
+		{"06 1234 5678", "IT", "+390612345678"},
+		{"+852 2123 4567", "RU", "+85221234567"},
+	}
+	for _, tt := range tests {
+		got, err := contact.NormalizePhone(tt.raw, tt.region)
+		require.NoError(t, err, tt.raw)
+		assert.Equal(t, tt.want, got, tt.raw)
+	}
+
+	for _, raw := range []string{"", "+", "12345", "8 916 123 45", "+7 916 123 45 678", "+0 123 456 789", "916-CALL-NOW", "+1234567890123456"} {
+		_, err := contact.NormalizePhone(raw, "RU")
+		assert.ErrorIs(t, err, contact.ErrInvalidPhone, raw)
+	}
+
+	_, err := contact.NormalizePhone("916 123 45 67", "XX")
+	assert.ErrorIs(t, err, contact.ErrInvalidPhone, "national numbers need a known region")
+	assert.True(t, contact.IsRegion("ru"))
+	assert.False(t, contact.IsRegion("XX"))
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	got, err := contact.NormalizeEmail("  Anna.Petrova@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "anna.petrova@example.com", got)
+
+	got, err = contact.NormalizeEmail("гость@пример.рф")
+	require.NoError(t, err)
+	assert.Equal(t, "гость@пример.рф", got)
+
+	invalid := []string{
+		"",
+		"anna",
+		"anna@localhost",
+		"Anna <anna@example.com>",
+		"anna@example..com",
+		"anna@-example.com",
+		"anna@example.c0m",
+		"anna@example.c",
+		"anna@exa_mple.com",
+	}
+	for _, raw := range invalid {
+		_, err := contact.NormalizeEmail(raw)
+		assert.ErrorIs(t, err, contact.ErrInvalidEmail, raw)
+	}
+}
@@ -0,0 +1,43 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQueryExecMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected pgx.QueryExecMode
+	}{
+		{"", pgx.QueryExecModeCacheStatement},
+		{"cache_statement", pgx.QueryExecModeCacheStatement},
+		{"cache_describe", pgx.QueryExecModeCacheDescribe},
+		{"describe_exec", pgx.QueryExecModeDescribeExec},
+		{" EXEC ", pgx.QueryExecModeExec},
+		{"simple_protocol", pgx.QueryExecModeSimpleProtocol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := postgres.ParseQueryExecMode(tt.name)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, mode)
+		})
+	}
+
+	_, err := postgres.ParseQueryExecMode("prepared")
+	assert.Error(t, err)
+}
+
+func TestPreparesStatements(t *testing.T) {
+	assert.True(t, postgres.PreparesStatements(pgx.QueryExecModeCacheStatement))
+	assert.False(t, postgres.PreparesStatements(pgx.QueryExecModeCacheDescribe))
+	assert.False(t, postgres.PreparesStatements(pgx.QueryExecModeSimpleProtocol))
+}
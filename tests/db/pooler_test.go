@@ -0,0 +1,64 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	"github.com/flexer2006/case-back-restaurant-go/db/postgres"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPoolerProfile(t *testing.T) {
+	cfg := configs.PostgresConfig{
+		MaxConnections:       100,
+		MinConnections:       3,
+		QueryExecMode:        "cache_statement",
+		PoolerMaxConnections: 20,
+	}
+
+	t.Run("none", func(t *testing.T) {
+		cfg := cfg
+		cfg.Pooler = postgres.PoolerNone
+
+		profiled, err := postgres.ApplyPoolerProfile(cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, cfg, profiled)
+	})
+
+	t.Run("pgbouncer", func(t *testing.T) {
+		cfg := cfg
+		cfg.Pooler = "PgBouncer"
+
+		profiled, err := postgres.ApplyPoolerProfile(cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, "describe_exec", profiled.QueryExecMode)
+		assert.Equal(t, 20, profiled.MaxConnections)
+		assert.Equal(t, 0, profiled.MinConnections)
+	})
+
+	t.Run("pgbouncer keeps a mode that prepares nothing and a smaller pool", func(t *testing.T) {
+		cfg := cfg
+		cfg.Pooler = postgres.PoolerPgBouncer
+		cfg.QueryExecMode = "simple_protocol"
+		cfg.MaxConnections = 10
+
+		profiled, err := postgres.ApplyPoolerProfile(cfg)
+
+		require.NoError(t, err)
+		assert.Equal(t, "simple_protocol", profiled.QueryExecMode)
+		assert.Equal(t, 10, profiled.MaxConnections)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		cfg := cfg
+		cfg.Pooler = "pgpool"
+
+		_, err := postgres.ApplyPoolerProfile(cfg)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,86 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/configs"
+	migratepkg "github.com/flexer2006/case-back-restaurant-go/db/postgres/migrate"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardDatabase(t *testing.T) {
+	base := configs.PostgresConfig{Host: "localhost", Port: 5432, Username: "postgres", Password: "root", Database: "postgres"}
+	t.Setenv("POSTGRES_SHARD_EU_2_HOST", "eu-2.db")
+	t.Setenv("POSTGRES_SHARD_EU_2_PORT", "6432")
+	t.Setenv("POSTGRES_SHARD_EU_2_DB", "booking")
+
+	cfg, err := configs.ShardDatabase(base, "eu-2")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-2.db", cfg.Host)
+	assert.Equal(t, 6432, cfg.Port)
+	assert.Equal(t, "booking", cfg.Database)
+	assert.Equal(t, "postgres", cfg.Username, "what is not overridden is shared")
+
+	cfg, err = configs.ShardDatabase(base, "eu-1")
+	require.NoError(t, err)
+	assert.Equal(t, base, cfg)
+
+	t.Setenv("POSTGRES_SHARD_EU_3_PORT", "port")
+	_, err = configs.ShardDatabase(base, "eu-3")
+	assert.Error(t, err)
+}
+
+func TestMigrateShards_StopsAtTheFailedShard(t *testing.T) {
+	zapLogger, err := logger.NewLogger()
+	require.NoError(t, err)
+	ctx := logger.NewContext(context.Background(), zapLogger)
+
+	shard := func(name string) migratepkg.Shard {
+		return migratepkg.Shard{Name: name, Config: &configs.PostgresConfig{Host: name, Port: 5432, Username: "u", Password: "p", Database: "db", SSLMode: "disable"}}
+	}
+	shards := []migratepkg.Shard{shard("eu-1"), shard("eu-2"), shard("us-1")}
+
+	migrated := new(MockMigrator)
+	migrated.On("Up").Return(nil)
+	migrated.On("Version").Return(uint(44), false, nil)
+	migrated.On("Close").Return(nil, nil)
+
+	failing := new(MockMigrator)
+	failing.On("Up").Return(errors.New("lock timeout"))
+	failing.On("Version").Return(uint(43), true, nil)
+	failing.On("Close").Return(nil, nil)
+
+	untouched := new(MockMigrator)
+	untouched.On("Version").Return(uint(43), false, nil)
+	untouched.On("Close").Return(nil, nil)
+
+	handler := new(MockMigrationHandler)
+	handler.On("Migrate", migratepkg.DefaultMigrationsPath, createDSN(shards[0].Config)).Return(migrated, nil)
+	handler.On("Migrate", migratepkg.DefaultMigrationsPath, createDSN(shards[1].Config)).Return(failing, nil)
+	handler.On("Migrate", migratepkg.DefaultMigrationsPath, createDSN(shards[2].Config)).Return(untouched, nil)
+
+	originalNewHandlerFunc := migratepkg.NewHandlerFunc
+	migratepkg.NewHandlerFunc = func() migratepkg.MigrationHandler {
+		return handler
+	}
+	defer func() {
+		migratepkg.NewHandlerFunc = originalNewHandlerFunc
+	}()
+
+	versions, err := migratepkg.MigrateShards(ctx, shards, 0, "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), common.ErrMigrateShard+" eu-2")
+	assert.Equal(t, []migratepkg.ShardVersion{
+		{Shard: "eu-1", Version: 44},
+		{Shard: "eu-2", Version: 43, Dirty: true},
+		{Shard: "us-1", Version: 43},
+	}, versions)
+	untouched.AssertNotCalled(t, "Up")
+}
@@ -0,0 +1,54 @@
+package diagnostics_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/diagnostics"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerVars(t *testing.T) {
+	rec := httptest.NewRecorder()
+	diagnostics.NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var vars map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	for _, name := range []string{"goroutines", "gc", "memstats", "postgres_pool"} {
+		assert.Contains(t, vars, name)
+	}
+
+	var gc map[string]any
+	require.NoError(t, json.Unmarshal(vars["gc"], &gc))
+	assert.Contains(t, gc, "num_gc")
+	assert.Equal(t, "null", string(vars["postgres_pool"]), "no pool is published before SetPool")
+}
+
+func TestHandlerProfiles(t *testing.T) {
+	rec := httptest.NewRecorder()
+	diagnostics.NewHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=1", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine profile")
+}
+
+func TestStart(t *testing.T) {
+	log, err := logger.NewLogger()
+	require.NoError(t, err)
+	ctx := logger.NewContext(context.Background(), log)
+
+	_, err = diagnostics.Start(ctx, "256.0.0.1:0")
+	require.Error(t, err)
+
+	stop, err := diagnostics.Start(ctx, "127.0.0.1:0")
+	require.NoError(t, err)
+	stop()
+}
@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	currency, ok := domain.NormalizeCurrency("eur")
+	assert.True(t, ok)
+	assert.Equal(t, "EUR", currency)
+
+	for _, code := range []string{"", "EU", "EURO", "E1R", "ЕВР"} {
+		_, ok := domain.NormalizeCurrency(code)
+		assert.False(t, ok, code)
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{125000, "RUB", "1250.00 RUB"},
+		{5, "EUR", "0.05 EUR"},
+		{0, "USD", "0.00 USD"},
+		{-1999, "USD", "-19.99 USD"},
+		{1500, "JPY", "1500 JPY"},
+		{12345, "KWD", "12.345 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.FormatAmount(tt.amount, tt.currency))
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+	}{
+		{"450", "RUB", 45000},
+		{"450.00", "RUB", 45000},
+		{"1 250,50 ₽", "RUB", 125050},
+		{"$1,250.50", "USD", 125050},
+		{"1,250", "USD", 125000},
+		{"1.234.567,89", "EUR", 123456789},
+		{"12,5", "EUR", 1250},
+		{"1,500", "JPY", 1500},
+		{"12.345", "KWD", 12345},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			amount, err := domain.ParseAmount(tt.value, tt.currency)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, amount)
+		})
+	}
+
+	for _, value := range []string{"", "free", "1.2.3", "12.5.000", "1.5 JPY"} {
+		_, err := domain.ParseAmount(value, "JPY")
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIDs(t *testing.T) {
+	const id = "5b0f7e0c-8d1a-4c49-9f43-6a2e3c1d7b95"
+
+	restaurantID, err := domain.ParseRestaurantID("5B0F7E0C-8D1A-4C49-9F43-6A2E3C1D7B95")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RestaurantID(id), restaurantID)
+
+	bookingID, err := domain.ParseBookingID("urn:uuid:" + id)
+	require.NoError(t, err)
+	assert.Equal(t, id, bookingID.String())
+
+	userID, err := domain.ParseUserID(id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserID(id), userID)
+
+	const v7 = "01970a3c-5f2e-7b1d-9c4a-2e6f8d0b3a71"
+	_, err = domain.ParseBookingID(v7)
+	assert.NoError(t, err)
+
+	const v1 = "c232ab00-9414-11ec-b3c8-9f6bdeced846"
+	for _, value := range []string{"", "user123", id[:35], id + "0", v1} {
+		_, err := domain.ParseUserID(value)
+		assert.ErrorIs(t, err, domain.ErrInvalidID, value)
+	}
+}
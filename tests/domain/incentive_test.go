@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateIncentives(t *testing.T) {
+	// 2025-06-04 is a Wednesday.
+	booking := &domain.Booking{UserID: "u1", RestaurantID: "r1", Date: time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), GuestsCount: 2}
+	bookedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	launch := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rules := []*domain.IncentiveRule{
+		{ID: "first", Effect: domain.IncentiveWaiveDeposit, Conditions: domain.IncentiveConditions{FirstBooking: true}},
+		{ID: "weekday", Effect: domain.IncentiveBonusPoints, Points: 50, Conditions: domain.IncentiveConditions{
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		}},
+		{ID: "group", Effect: domain.IncentiveBonusPoints, Points: 100, Conditions: domain.IncentiveConditions{MinGuests: 6}},
+		{ID: "later", Effect: domain.IncentiveBonusPoints, Points: 10, ValidFrom: &launch},
+	}
+
+	evaluation := domain.EvaluateIncentives(rules, domain.NewIncentiveFacts(booking, 0, bookedAt))
+	assert.True(t, evaluation.WaiveDeposit)
+	assert.Equal(t, 50, evaluation.BonusPoints)
+	assert.Equal(t, time.Wednesday, evaluation.Facts.Weekday)
+
+	assert.Len(t, evaluation.Results, 4, "every rule is traced")
+	assert.True(t, evaluation.Results[0].Matched)
+	assert.Equal(t, 50, evaluation.Results[1].Points)
+	assert.Equal(t, []string{"min_guests"}, evaluation.Results[2].Failed)
+	assert.Zero(t, evaluation.Results[2].Points)
+	assert.Equal(t, []string{"valid_from"}, evaluation.Results[3].Failed)
+
+	returning := domain.EvaluateIncentives(rules[:1], domain.NewIncentiveFacts(booking, 3, bookedAt))
+	assert.False(t, returning.WaiveDeposit)
+	assert.Equal(t, []string{"first_booking"}, returning.Results[0].Failed)
+}
@@ -0,0 +1,139 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationDeliveryStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		notification domain.Notification
+		want         domain.DeliveryStatus
+	}{
+		{"not delivered yet", domain.Notification{Channel: domain.NotificationChannelSMS}, domain.DeliveryStatusPending},
+		{"delivered", domain.Notification{DeliveredAt: &now}, domain.DeliveryStatusDelivered},
+		{"read", domain.Notification{DeliveredAt: &now, ReadAt: &now}, domain.DeliveryStatusRead},
+		{"marked read before read times were kept", domain.Notification{DeliveredAt: &now, IsRead: true}, domain.DeliveryStatusRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.notification.DeliveryStatus())
+		})
+	}
+}
+
+func TestNotificationDelivery_LeavesContentOut(t *testing.T) {
+	now := time.Now()
+	notification := domain.Notification{
+		ID:            "n1",
+		RecipientType: domain.RecipientTypeUser,
+		RecipientID:   "user1",
+		Type:          domain.NotificationTypeBookingConfirmed,
+		Channel:       domain.NotificationChannelInApp,
+		Title:         "Booking confirmed",
+		Message:       "See you at 19:00",
+		CreatedAt:     now,
+		DeliveredAt:   &now,
+	}
+
+	assert.Equal(t, domain.NotificationDelivery{
+		NotificationID: "n1",
+		RecipientType:  domain.RecipientTypeUser,
+		Type:           domain.NotificationTypeBookingConfirmed,
+		Channel:        domain.NotificationChannelInApp,
+		Status:         domain.DeliveryStatusDelivered,
+		CreatedAt:      now,
+		DeliveredAt:    &now,
+	}, notification.Delivery())
+}
+
+func TestNotificationSettings_MarketingIsOptIn(t *testing.T) {
+	settings := &domain.NotificationSettings{}
+	for _, channel := range domain.NotificationChannels {
+		assert.False(t, settings.Allows(domain.NotificationTypeMarketing, channel), channel)
+	}
+	_, ok := settings.PreferredMarketingChannel()
+	assert.False(t, ok)
+
+	settings.Preferences = []domain.NotificationPreference{
+		{Type: domain.NotificationTypeMarketing, Channel: domain.NotificationChannelInApp, Enabled: true},
+		{Type: domain.NotificationTypeMarketing, Channel: domain.NotificationChannelSMS, Enabled: true},
+	}
+	channel, ok := settings.PreferredMarketingChannel()
+	assert.True(t, ok)
+	assert.Equal(t, domain.NotificationChannelSMS, channel, "SMS is preferred over in-app")
+}
+
+func TestQuietHours_Until(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.UTC)
+	}
+	snooze := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name   string
+		quiet  domain.QuietHours
+		now    time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{"within a daytime window", domain.QuietHours{Start: "13:00", End: "15:00"}, at(16, 14, 0), at(16, 15, 0), true},
+		{"before a daytime window", domain.QuietHours{Start: "13:00", End: "15:00"}, at(16, 12, 59), time.Time{}, false},
+		{"at the end of a window", domain.QuietHours{Start: "13:00", End: "15:00"}, at(16, 15, 0), time.Time{}, false},
+		{"late in an overnight window", domain.QuietHours{Start: "22:00", End: "08:00"}, at(16, 23, 0), at(17, 8, 0), true},
+		{"early in an overnight window", domain.QuietHours{Start: "22:00", End: "08:00"}, at(17, 6, 30), at(17, 8, 0), true},
+		{"outside an overnight window", domain.QuietHours{Start: "22:00", End: "08:00"}, at(16, 12, 0), time.Time{}, false},
+		{"snoozed", domain.QuietHours{SnoozedUntil: snooze(at(16, 18, 0))}, at(16, 12, 0), at(16, 18, 0), true},
+		{"snooze over", domain.QuietHours{SnoozedUntil: snooze(at(16, 18, 0))}, at(16, 18, 0), time.Time{}, false},
+		{"snooze ending within the window", domain.QuietHours{Start: "22:00", End: "08:00", SnoozedUntil: snooze(at(16, 23, 0))}, at(16, 12, 0), at(17, 8, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.quiet.Until(tt.now)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuietHours_Validate(t *testing.T) {
+	snoozedUntil := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&domain.QuietHours{Channel: domain.NotificationChannelEmail, Start: "22:00", End: "07:30"}).Validate())
+	assert.NoError(t, (&domain.QuietHours{Channel: domain.NotificationChannelSMS, SnoozedUntil: &snoozedUntil}).Validate())
+
+	assert.Error(t, (&domain.QuietHours{Channel: "pager", Start: "22:00", End: "07:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Channel: domain.NotificationChannelEmail}).Validate())
+	assert.Error(t, (&domain.QuietHours{Channel: domain.NotificationChannelEmail, Start: "22:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Channel: domain.NotificationChannelEmail, Start: "25:00", End: "07:00"}).Validate())
+	assert.Error(t, (&domain.QuietHours{Channel: domain.NotificationChannelEmail, Start: "22:00", End: "22:00"}).Validate())
+}
+
+func TestNotificationSettings_DeferUntilSparesUrgentTypes(t *testing.T) {
+	now := time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)
+	settings := &domain.NotificationSettings{QuietHours: []domain.QuietHours{
+		{Channel: domain.NotificationChannelEmail, Start: "22:00", End: "08:00"},
+	}}
+
+	until, ok := settings.DeferUntil(domain.NotificationTypeWeeklyReport, domain.NotificationChannelEmail, now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), until)
+
+	_, ok = settings.DeferUntil(domain.NotificationTypeBookingConfirmed, domain.NotificationChannelEmail, now)
+	assert.False(t, ok, "booking changes are urgent")
+
+	_, ok = settings.DeferUntil(domain.NotificationTypeMarketing, domain.NotificationChannelSMS, now)
+	assert.False(t, ok, "the quiet hours of another channel")
+}
@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestaurantClosure(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, time.August, d, 0, 0, 0, 0, time.UTC) }
+	vacation := &domain.RestaurantClosure{StartsOn: day(10), ReopensOn: day(20)}
+
+	assert.NoError(t, vacation.Validate())
+	assert.False(t, vacation.Covers(day(9)))
+	assert.True(t, vacation.Covers(day(10)))
+	assert.True(t, vacation.Covers(day(19).Add(23*time.Hour)))
+	assert.False(t, vacation.Covers(day(20)), "the restaurant is open on the day it reopens")
+
+	assert.True(t, vacation.Overlaps(&domain.RestaurantClosure{StartsOn: day(19), ReopensOn: day(25)}))
+	assert.False(t, vacation.Overlaps(&domain.RestaurantClosure{StartsOn: day(20), ReopensOn: day(25)}))
+	assert.False(t, vacation.Overlaps(&domain.RestaurantClosure{StartsOn: day(1), ReopensOn: day(10)}))
+
+	for _, invalid := range []*domain.RestaurantClosure{
+		{ReopensOn: day(20)},
+		{StartsOn: day(10)},
+		{StartsOn: day(10), ReopensOn: day(10)},
+		{StartsOn: day(10), ReopensOn: day(11), Message: strings.Repeat("a", domain.MaxClosureMessageLength+1)},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), domain.ErrInvalidEntity)
+	}
+}
@@ -0,0 +1,35 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSLOReport(t *testing.T) {
+	objectives := domain.SLOObjectives{AvailabilityTarget: 0.999, LatencyTarget: 0.99, LatencyThreshold: 500 * time.Millisecond}
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	report := domain.NewSLOReport(date, domain.RequestMetrics{Requests: 10000, Errors: 5, Slow: 200}, objectives)
+	assert.InDelta(t, 0.9995, report.Availability, 1e-9)
+	assert.InDelta(t, 0.5, report.AvailabilityBurnRate, 1e-6)
+	assert.True(t, report.AvailabilityMet())
+	assert.InDelta(t, 0.98, report.LatencyCompliance, 1e-9)
+	assert.InDelta(t, 2, report.LatencyBurnRate, 1e-6)
+	assert.False(t, report.LatencyMet())
+
+	empty := domain.NewSLOReport(date, domain.RequestMetrics{}, objectives)
+	assert.Equal(t, 1.0, empty.Availability)
+	assert.Zero(t, empty.AvailabilityBurnRate)
+	assert.True(t, empty.AvailabilityMet() && empty.LatencyMet())
+}
+
+func TestBurnRate(t *testing.T) {
+	assert.Zero(t, domain.BurnRate(1, 0.99))
+	assert.InDelta(t, 1, domain.BurnRate(0.99, 0.99), 1e-9)
+	assert.Equal(t, math.MaxFloat64, domain.BurnRate(0.99, 1))
+}
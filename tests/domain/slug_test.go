@@ -0,0 +1,42 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Pasta Place", "pasta-place"},
+		{"  Joe's   Diner!! ", "joes-diner"},
+		{"Café Crème 24/7", "cafe-creme-24-7"},
+		{"Пельменная №1", "pelmennaya-1"},
+		{"Щи да каша", "shchi-da-kasha"},
+		{"!!!", domain.DefaultSlugPrefix},
+		{strings.Repeat("a", 70), strings.Repeat("a", domain.MaxSlugLength)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug := domain.Slugify(tt.name)
+			assert.Equal(t, tt.want, slug)
+			assert.True(t, domain.IsValidSlug(slug))
+		})
+	}
+}
+
+func TestIsValidSlug(t *testing.T) {
+	assert.True(t, domain.IsValidSlug("pasta-place-2"))
+	assert.False(t, domain.IsValidSlug(""))
+	assert.False(t, domain.IsValidSlug("Pasta"))
+	assert.False(t, domain.IsValidSlug("pasta--place"))
+	assert.False(t, domain.IsValidSlug("-pasta"))
+	assert.False(t, domain.IsValidSlug(strings.Repeat("a", domain.MaxSlugLength+1)))
+}
@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecialDay_Validate(t *testing.T) {
+	date := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&domain.SpecialDay{Date: date, OpenTime: "18:00", CloseTime: "03:00"}).Validate())
+	assert.NoError(t, (&domain.SpecialDay{Date: date, IsClosed: true}).Validate(), "a closed day has no hours")
+
+	for _, invalid := range []*domain.SpecialDay{
+		{OpenTime: "18:00", CloseTime: "23:00"},
+		{Date: date, CloseTime: "23:00"},
+		{Date: date, OpenTime: "18:00", CloseTime: "25:00"},
+		{Date: date, IsClosed: true, Note: strings.Repeat("a", domain.MaxSpecialDayNoteLength+1)},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), domain.ErrInvalidEntity)
+	}
+}
+
+func TestScheduleOn(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2026, time.December, d, hour, 0, 0, 0, time.UTC) }
+	// December 31, 2026 is a Thursday.
+	workingHours := []*domain.WorkingHours{{WeekDay: domain.Thursday, OpenTime: "12:00", CloseTime: "22:00"}}
+	newYearsEve := []*domain.SpecialDay{{Date: day(31, 0), OpenTime: "18:00", CloseTime: "03:00"}}
+
+	schedule := domain.ScheduleOn(workingHours, nil, nil, day(31, 0))
+	assert.Equal(t, []domain.OpeningHours{{OpensAt: day(31, 12), ClosesAt: day(31, 22)}}, schedule.Hours)
+
+	schedule = domain.ScheduleOn(workingHours, newYearsEve, nil, day(31, 0))
+	assert.Equal(t, []domain.OpeningHours{{OpensAt: day(31, 18), ClosesAt: day(31, 27)}}, schedule.Hours)
+	assert.Equal(t, newYearsEve[0], schedule.SpecialDay)
+
+	closures := []*domain.RestaurantClosure{{StartsOn: day(31, 0), ReopensOn: day(31, 0).AddDate(0, 0, 10)}}
+	schedule = domain.ScheduleOn(workingHours, newYearsEve, closures, day(31, 0))
+	assert.True(t, schedule.IsClosed(), "a closure takes precedence over a special day")
+	assert.Nil(t, schedule.SpecialDay)
+}
@@ -0,0 +1,38 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncCursorRoundTrip(t *testing.T) {
+	cursors := []domain.SyncCursor{
+		{},
+		{ChangedAt: time.Date(2025, 5, 1, 10, 0, 0, 123456000, time.UTC), ID: "booking1"},
+		{ChangedAt: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, cursor := range cursors {
+		parsed, err := domain.ParseSyncCursor(cursor.String())
+		require.NoError(t, err)
+		assert.True(t, cursor.ChangedAt.Equal(parsed.ChangedAt))
+		assert.Equal(t, cursor.ID, parsed.ID)
+	}
+}
+
+func TestParseSyncCursor(t *testing.T) {
+	cursor, err := domain.ParseSyncCursor("2025-05-01T10:00:00+03:00")
+	require.NoError(t, err)
+	assert.True(t, cursor.ChangedAt.Equal(time.Date(2025, 5, 1, 7, 0, 0, 0, time.UTC)))
+	assert.Empty(t, cursor.ID)
+
+	for _, value := range []string{"yesterday", "bm8tc2VwYXJhdG9y", "MjAyNS0wNS0wMVQxMDowMDowMFp8"} {
+		_, err := domain.ParseSyncCursor(value)
+		assert.ErrorIs(t, err, domain.ErrInvalidSyncCursor, value)
+	}
+}
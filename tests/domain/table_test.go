@@ -0,0 +1,138 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable_Validate(t *testing.T) {
+	window := &domain.Table{RestaurantID: "bistro", Name: "Window 1", Seats: 2, Zone: "Main hall"}
+	assert.NoError(t, window.Validate())
+	assert.True(t, window.Fits(2))
+	assert.False(t, window.Fits(3))
+
+	for _, invalid := range []*domain.Table{
+		{Name: "1", Seats: 2},
+		{RestaurantID: "bistro", Name: "  ", Seats: 2},
+		{RestaurantID: "bistro", Name: strings.Repeat("a", domain.MaxTableNameLength+1), Seats: 2},
+		{RestaurantID: "bistro", Name: "1", Seats: 2, Zone: strings.Repeat("a", domain.MaxTableZoneLength+1)},
+		{RestaurantID: "bistro", Name: "1"},
+		{RestaurantID: "bistro", Name: "1", Seats: domain.MaxTableSeats + 1},
+	} {
+		assert.ErrorIs(t, invalid.Validate(), domain.ErrInvalidEntity)
+	}
+}
+
+func TestFreeTables(t *testing.T) {
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+	tables := []*domain.Table{
+		{ID: "t1", Name: "1", Seats: 2},
+		{ID: "t2", Name: "2", Seats: 4},
+		{ID: "t3", Name: "3", Seats: 4},
+	}
+	holds := []domain.TableHold{
+		{TableID: "t1", BookingID: "early", Date: date, Time: "17:00", Duration: 60},
+		{TableID: "t2", BookingID: "dinner", Date: date, Time: "19:00", Duration: 120},
+		{TableID: "t3", BookingID: "late", Date: date, Time: "21:00", Duration: 60},
+	}
+
+	free := domain.FreeTables(tables, holds, &domain.Booking{Date: date, Time: "18:00", Duration: 180})
+	assert.Equal(t, []*domain.Table{tables[0], tables[2]}, free, "the hold at 19:00 overlaps, the holds ending at 18:00 and starting at 21:00 don't")
+
+	free = domain.FreeTables(tables, holds, &domain.Booking{ID: "dinner", Date: date, Time: "19:30", Duration: 60})
+	assert.Equal(t, tables, free, "a booking does not keep its own table from itself")
+
+	assert.Empty(t, domain.FreeTables(tables, nil, &domain.Booking{Date: date, Time: "late"}))
+}
+
+func TestPickTable(t *testing.T) {
+	tables := []*domain.Table{
+		{ID: "t8", Name: "8", Seats: 8},
+		{ID: "t4b", Name: "4b", Seats: 4},
+		{ID: "t2", Name: "2", Seats: 2},
+		{ID: "t4a", Name: "4a", Seats: 4},
+	}
+
+	assert.Equal(t, "t2", domain.PickTable(tables, &domain.Booking{GuestsCount: 1}).ID)
+	assert.Equal(t, "t4a", domain.PickTable(tables, &domain.Booking{GuestsCount: 3}).ID, "the smallest fitting table, by name among tables of a size")
+	assert.Equal(t, "t8", domain.PickTable(tables, &domain.Booking{GuestsCount: 8}).ID)
+	assert.Nil(t, domain.PickTable(tables, &domain.Booking{GuestsCount: 9}))
+	assert.Nil(t, domain.PickTable(nil, &domain.Booking{GuestsCount: 2}))
+}
+
+func TestPickTable_Preferences(t *testing.T) {
+	tables := []*domain.Table{
+		{ID: "hall2", Name: "1", Seats: 2, Zone: "Main hall"},
+		{ID: "hall4", Name: "2", Seats: 4, Zone: "Main hall", IsAccessible: true},
+		{ID: "terrace6", Name: "3", Seats: 6, Zone: "Terrace"},
+		{ID: "hall4b", Name: "4", Seats: 4, Zone: "Main hall"},
+	}
+
+	assert.Equal(t, "terrace6", domain.PickTable(tables, &domain.Booking{GuestsCount: 2, SeatingZone: "terrace"}).ID,
+		"the zone asked for, even at a larger table")
+	assert.Equal(t, "hall2", domain.PickTable(tables, &domain.Booking{GuestsCount: 2, SeatingZone: "Garden"}).ID,
+		"the smallest table when no table is in the zone asked for")
+	assert.Equal(t, "hall4", domain.PickTable(tables, &domain.Booking{GuestsCount: 2, SeatingZone: "Terrace", NeedsAccessible: true}).ID,
+		"accessibility before the zone")
+	assert.Nil(t, domain.PickTable(tables, &domain.Booking{GuestsCount: 5, NeedsAccessible: true}))
+	assert.Equal(t, "hall4b", domain.PickTable(tables, &domain.Booking{GuestsCount: 4}).ID,
+		"the accessible table is kept for the parties that need it")
+}
+
+func TestOptimizeSeating(t *testing.T) {
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+	tables := []*domain.Table{
+		{ID: "t2", Name: "2", Seats: 2},
+		{ID: "t4", Name: "4", Seats: 4, Zone: "Terrace"},
+		{ID: "t6", Name: "6", Seats: 6, IsAccessible: true},
+	}
+	pair := &domain.Booking{ID: "pair", Date: date, Time: "19:00", GuestsCount: 2, TableID: "t6"}
+	wheelchair := &domain.Booking{ID: "wheelchair", Date: date, Time: "20:00", GuestsCount: 3, NeedsAccessible: true, TableID: "t4"}
+
+	moves := domain.OptimizeSeating(tables, nil, []*domain.Booking{pair, wheelchair})
+	assert.Equal(t, map[string]string{"pair": "t2", "wheelchair": "t6"}, moves,
+		"the pair leaves the six-top for the two-top, the party in a wheelchair gets the accessible table")
+
+	terrace := &domain.Booking{ID: "terrace", Date: date, Time: "19:00", GuestsCount: 2, SeatingZone: "Terrace", TableID: "t2"}
+	assert.Equal(t, map[string]string{"terrace": "t4"}, domain.OptimizeSeating(tables, nil, []*domain.Booking{terrace}),
+		"a party asking for the terrace moves there")
+
+	seated := &domain.Booking{ID: "seated", Date: date, Time: "19:00", GuestsCount: 2, TableID: "t2"}
+	assert.Nil(t, domain.OptimizeSeating(tables, nil, []*domain.Booking{seated}), "nothing to gain")
+
+	holds := []domain.TableHold{
+		{TableID: "t2", BookingID: "early", Date: date, Time: "18:00", Duration: 90},
+		{TableID: "t4", BookingID: "late", Date: date, Time: "20:00", Duration: 60},
+	}
+	assert.Nil(t, domain.OptimizeSeating(tables, holds, []*domain.Booking{pair}), "the smaller tables are held by bookings that stay")
+
+	crowd := &domain.Booking{ID: "crowd", Date: date, Time: "19:00", GuestsCount: 5, TableID: "t6"}
+	large := &domain.Booking{ID: "large", Date: date, Time: "19:30", GuestsCount: 6, TableID: "t4"}
+	assert.Nil(t, domain.OptimizeSeating(tables, nil, []*domain.Booking{crowd, large}), "not every party fits, so nobody moves")
+}
+
+func TestAvailability_ReserveHeldTables(t *testing.T) {
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+	tables := []*domain.Table{
+		{ID: "t2", Name: "2", Seats: 2},
+		{ID: "t4", Name: "4", Seats: 4},
+		{ID: "t6", Name: "6", Seats: 6},
+	}
+	holds := []domain.TableHold{
+		{TableID: "t6", BookingID: "dinner", Date: date, Time: "19:00", Duration: 120},
+	}
+
+	slot := &domain.Availability{Date: date, TimeSlot: "18:00", Capacity: 20, Reserved: 1}
+	slot.ReserveHeldTables(tables, holds)
+	assert.Equal(t, 15, slot.Reserved, "the held draft and the seats that the two free tables cannot offer")
+	assert.Equal(t, 5, slot.AvailableSeats())
+
+	slot = &domain.Availability{Date: date, TimeSlot: "21:00", Capacity: 10}
+	slot.ReserveHeldTables(tables, holds)
+	assert.Zero(t, slot.Reserved, "the tables offer more seats than the slot")
+}
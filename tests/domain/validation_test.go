@@ -0,0 +1,169 @@
+package domain_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertInvalid checks that err is a validation error of the field.
+func assertInvalid(t *testing.T, err error, field string) {
+	t.Helper()
+
+	if field == "" {
+		assert.NoError(t, err)
+		return
+	}
+
+	require.ErrorIs(t, err, domain.ErrInvalidEntity)
+	var invalid *domain.ValidationError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, field, invalid.Field)
+}
+
+func TestRestaurantValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		restaurant domain.Restaurant
+		field      string
+	}{
+		{"name only", domain.Restaurant{Name: "Bistro"}, ""},
+		{"known cuisine in any case", domain.Restaurant{Name: "Bistro", Cuisine: "Italian", Currency: "eur"}, ""},
+		{"blank name", domain.Restaurant{Name: "  "}, "name"},
+		{"unknown cuisine", domain.Restaurant{Name: "Bistro", Cuisine: "martian"}, "cuisine"},
+		{"bad currency", domain.Restaurant{Name: "Bistro", Currency: "EURO"}, "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalid(t, tt.restaurant.Validate(), tt.field)
+		})
+	}
+}
+
+func TestBookingValidate(t *testing.T) {
+	valid := domain.Booking{
+		RestaurantID: "restaurant-1",
+		UserID:       "user-1",
+		Date:         time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Time:         "19:30",
+		GuestsCount:  2,
+	}
+
+	tests := []struct {
+		name   string
+		change func(b *domain.Booking)
+		field  string
+	}{
+		{"valid", func(*domain.Booking) {}, ""},
+		{"no restaurant", func(b *domain.Booking) { b.RestaurantID = "" }, "restaurant_id"},
+		{"no user", func(b *domain.Booking) { b.UserID = "" }, "user_id"},
+		{"no date", func(b *domain.Booking) { b.Date = time.Time{} }, "date"},
+		{"bad time", func(b *domain.Booking) { b.Time = "25:00" }, "time"},
+		{"no guests", func(b *domain.Booking) { b.GuestsCount = 0 }, "guests_count"},
+		{"negative duration", func(b *domain.Booking) { b.Duration = -30 }, "duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := valid
+			tt.change(&booking)
+			assertInvalid(t, booking.Validate(), tt.field)
+		})
+	}
+}
+
+func TestAvailabilityValidate(t *testing.T) {
+	valid := domain.Availability{
+		RestaurantID: "restaurant-1",
+		Date:         time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		TimeSlot:     "19:00",
+		Capacity:     10,
+		Reserved:     10,
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *domain.Availability)
+		field  string
+	}{
+		{"valid", func(*domain.Availability) {}, ""},
+		{"no restaurant", func(a *domain.Availability) { a.RestaurantID = "" }, "restaurant_id"},
+		{"no date", func(a *domain.Availability) { a.Date = time.Time{} }, "date"},
+		{"bad time slot", func(a *domain.Availability) { a.TimeSlot = "7pm" }, "time_slot"},
+		{"negative capacity", func(a *domain.Availability) { a.Capacity, a.Reserved = -1, 0 }, "capacity"},
+		{"negative reserved", func(a *domain.Availability) { a.Reserved = -1 }, "reserved"},
+		{"overbooked", func(a *domain.Availability) { a.Reserved = 11 }, "reserved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			availability := valid
+			tt.change(&availability)
+			assertInvalid(t, availability.Validate(), tt.field)
+		})
+	}
+}
+
+func TestWorkingHoursValidate(t *testing.T) {
+	validFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		hours domain.WorkingHours
+		field string
+	}{
+		{"day", domain.WorkingHours{WeekDay: domain.Monday, OpenTime: "09:00", CloseTime: "22:00"}, ""},
+		{"past midnight", domain.WorkingHours{WeekDay: domain.Friday, OpenTime: "23:00", CloseTime: "01:00"}, ""},
+		{"around the clock", domain.WorkingHours{WeekDay: domain.Sunday, OpenTime: "00:00", CloseTime: "00:00"}, ""},
+		{"closed without times", domain.WorkingHours{WeekDay: domain.Tuesday, IsClosed: true}, ""},
+		{"no week day", domain.WorkingHours{OpenTime: "09:00", CloseTime: "22:00"}, "week_day"},
+		{"week day out of range", domain.WorkingHours{WeekDay: 8, OpenTime: "09:00", CloseTime: "22:00"}, "week_day"},
+		{"bad open time", domain.WorkingHours{WeekDay: domain.Monday, OpenTime: "9am", CloseTime: "22:00"}, "open_time"},
+		{"bad close time", domain.WorkingHours{WeekDay: domain.Monday, OpenTime: "09:00"}, "close_time"},
+		{"valid to before valid from", domain.WorkingHours{
+			WeekDay: domain.Monday, OpenTime: "09:00", CloseTime: "22:00",
+			ValidFrom: validFrom, ValidTo: validFrom.AddDate(0, 0, -1),
+		}, "valid_to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertInvalid(t, tt.hours.Validate(), tt.field)
+		})
+	}
+}
+
+func TestBookingAttributionNormalize(t *testing.T) {
+	attribution := domain.BookingAttribution{
+		Source:       " Instagram ",
+		Medium:       "Social",
+		Campaign:     "Summer-Menu",
+		Content:      "Story A",
+		ReferralCode: "friend10",
+	}
+	require.NoError(t, attribution.Normalize())
+	assert.Equal(t, domain.BookingAttribution{
+		Source:       "instagram",
+		Medium:       "social",
+		Campaign:     "summer-menu",
+		Content:      "Story A",
+		ReferralCode: "FRIEND10",
+	}, attribution)
+
+	blank := domain.BookingAttribution{Source: "  "}
+	require.NoError(t, blank.Normalize())
+	assert.True(t, blank.IsZero())
+
+	long := domain.BookingAttribution{Campaign: strings.Repeat("a", 101)}
+	assertInvalid(t, long.Normalize(), "utm_campaign")
+
+	control := domain.BookingAttribution{Term: "brunch\x00"}
+	assertInvalid(t, control.Normalize(), "utm_term")
+}
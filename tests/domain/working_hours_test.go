@@ -0,0 +1,73 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOpenAt(t *testing.T) {
+	hours := []*domain.WorkingHours{
+		{WeekDay: domain.Monday, OpenTime: "12:00", CloseTime: "15:00"},
+		{WeekDay: domain.Friday, OpenTime: "18:00", CloseTime: "02:00"},
+		{WeekDay: domain.Sunday, OpenTime: "10:00", CloseTime: "22:00", IsClosed: true},
+		{WeekDay: domain.Tuesday, OpenTime: "09:00", CloseTime: "17:00", ValidFrom: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"during lunch on monday", time.Date(2026, 5, 4, 13, 30, 0, 0, time.UTC), true},
+		{"at closing time", time.Date(2026, 5, 4, 15, 0, 0, 0, time.UTC), false},
+		{"friday evening", time.Date(2026, 5, 8, 23, 0, 0, 0, time.UTC), true},
+		{"after midnight of friday", time.Date(2026, 5, 9, 1, 30, 0, 0, time.UTC), true},
+		{"saturday morning", time.Date(2026, 5, 9, 2, 0, 0, 0, time.UTC), false},
+		{"closed sunday", time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC), false},
+		{"before the hours are valid", time.Date(2026, 5, 26, 10, 0, 0, 0, time.UTC), false},
+		{"once the hours are valid", time.Date(2026, 6, 2, 10, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.IsOpenAt(hours, nil, nil, tt.at))
+		})
+	}
+}
+
+func TestIsOpenAt_SpecialDaysAndClosures(t *testing.T) {
+	hours := []*domain.WorkingHours{
+		{WeekDay: domain.Friday, OpenTime: "18:00", CloseTime: "02:00"},
+	}
+	specialDays := []*domain.SpecialDay{
+		{Date: time.Date(2026, 5, 8, 0, 0, 0, 0, time.UTC), OpenTime: "12:00", CloseTime: "16:00"},
+		{Date: time.Date(2026, 5, 14, 0, 0, 0, 0, time.UTC), OpenTime: "20:00", CloseTime: "01:00"},
+	}
+	closures := []*domain.RestaurantClosure{
+		{StartsOn: time.Date(2026, 5, 22, 0, 0, 0, 0, time.UTC), ReopensOn: time.Date(2026, 5, 23, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"special hours replace the friday hours", time.Date(2026, 5, 8, 13, 0, 0, 0, time.UTC), true},
+		{"friday evening of a special day", time.Date(2026, 5, 8, 19, 0, 0, 0, time.UTC), false},
+		{"special day on a closed weekday", time.Date(2026, 5, 14, 21, 0, 0, 0, time.UTC), true},
+		{"after midnight of a special day", time.Date(2026, 5, 15, 0, 30, 0, 0, time.UTC), true},
+		{"friday of a closure", time.Date(2026, 5, 22, 19, 0, 0, 0, time.UTC), false},
+		{"after midnight of a closed friday", time.Date(2026, 5, 23, 1, 0, 0, 0, time.UTC), false},
+		{"friday after the closure", time.Date(2026, 5, 29, 19, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.IsOpenAt(hours, specialDays, closures, tt.at))
+		})
+	}
+}
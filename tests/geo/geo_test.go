@@ -0,0 +1,46 @@
+package geo_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountryDefaults(t *testing.T) {
+	currency, locale := geo.CountryDefaults("de")
+	assert.Equal(t, "EUR", currency)
+	assert.Equal(t, "de", locale)
+
+	currency, locale = geo.CountryDefaults("RU")
+	assert.Equal(t, "RUB", currency)
+	assert.Equal(t, "ru", locale)
+
+	currency, locale = geo.CountryDefaults("AQ")
+	assert.Empty(t, currency)
+	assert.Empty(t, locale)
+}
+
+func TestLocationContext(t *testing.T) {
+	_, ok := geo.FromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := geo.NewContext(context.Background(), &geo.Location{CountryCode: "FR"})
+	location, ok := geo.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "FR", location.CountryCode)
+}
+
+func TestNopLocator(t *testing.T) {
+	_, err := geo.NopLocator{}.Locate("203.0.113.7")
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+}
+
+func TestOpenMaxMindMissingDatabase(t *testing.T) {
+	_, err := geo.OpenMaxMind(filepath.Join(t.TempDir(), "missing.mmdb"))
+	assert.Error(t, err)
+}
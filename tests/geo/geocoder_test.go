@@ -0,0 +1,98 @@
+package geo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNominatimGeocoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		assert.Equal(t, "test-agent", r.UserAgent())
+		assert.Equal(t, "en", r.URL.Query().Get("accept-language"))
+
+		if r.URL.Query().Get("q") != "Tverskaya 1, Moscow" {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"lat": "55.7575", "lon": "37.6137", "display_name": "1, Tverskaya Street, Moscow",
+			"address": {"road": "Tverskaya Street", "house_number": "1", "city": "Moscow", "state": "Moscow",
+			"postcode": "125009", "country_code": "ru"}}]`))
+	}))
+	defer server.Close()
+
+	geocoder := geo.NewNominatimGeocoder(server.Client(), server.URL+"/", "test-agent", "en")
+
+	address, err := geocoder.Geocode(context.Background(), "Tverskaya 1, Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "Tverskaya Street", address.Street)
+	assert.Equal(t, "1", address.HouseNumber)
+	assert.Equal(t, "Moscow", address.City)
+	assert.Equal(t, "125009", address.PostalCode)
+	assert.Equal(t, "RU", address.CountryCode)
+	assert.InDelta(t, 55.7575, address.Latitude, 1e-9)
+	assert.InDelta(t, 37.6137, address.Longitude, 1e-9)
+
+	_, err = geocoder.Geocode(context.Background(), "Nowhere 0")
+	assert.ErrorIs(t, err, geo.ErrAddressNotFound)
+}
+
+func TestGoogleGeocoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("key"))
+
+		switch r.URL.Query().Get("address") {
+		case "Unter den Linden 77, Berlin":
+			_, _ = w.Write([]byte(`{"status": "OK", "results": [{"formatted_address": "Unter den Linden 77, 10117 Berlin, Germany",
+				"address_components": [
+					{"long_name": "77", "short_name": "77", "types": ["street_number"]},
+					{"long_name": "Unter den Linden", "short_name": "Unter den Linden", "types": ["route"]},
+					{"long_name": "Berlin", "short_name": "Berlin", "types": ["locality", "political"]},
+					{"long_name": "Berlin", "short_name": "BE", "types": ["administrative_area_level_1", "political"]},
+					{"long_name": "Germany", "short_name": "DE", "types": ["country", "political"]},
+					{"long_name": "10117", "short_name": "10117", "types": ["postal_code"]}],
+				"geometry": {"location": {"lat": 52.5163, "lng": 13.3806}}}]}`))
+		case "Nowhere 0":
+			_, _ = w.Write([]byte(`{"status": "ZERO_RESULTS", "results": []}`))
+		default:
+			_, _ = w.Write([]byte(`{"status": "OVER_QUERY_LIMIT", "error_message": "quota exceeded", "results": []}`))
+		}
+	}))
+	defer server.Close()
+
+	geocoder := geo.NewGoogleGeocoder(server.Client(), server.URL, "secret", "en")
+
+	address, err := geocoder.Geocode(context.Background(), "Unter den Linden 77, Berlin")
+	require.NoError(t, err)
+	assert.Equal(t, "Unter den Linden", address.Street)
+	assert.Equal(t, "77", address.HouseNumber)
+	assert.Equal(t, "Berlin", address.City)
+	assert.Equal(t, "Berlin", address.Region)
+	assert.Equal(t, "10117", address.PostalCode)
+	assert.Equal(t, "DE", address.CountryCode)
+	assert.InDelta(t, 52.5163, address.Latitude, 1e-9)
+
+	_, err = geocoder.Geocode(context.Background(), "Nowhere 0")
+	assert.ErrorIs(t, err, geo.ErrAddressNotFound)
+
+	_, err = geocoder.Geocode(context.Background(), "Elsewhere 1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, geo.ErrAddressNotFound)
+	assert.Contains(t, err.Error(), "OVER_QUERY_LIMIT")
+}
+
+func TestNewGeocoder(t *testing.T) {
+	geocoder, err := geo.NewGeocoder(geo.ProviderNominatim, geo.GeocoderSettings{NominatimURL: "https://nominatim.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, geo.ProviderNominatim, geocoder.Provider())
+
+	_, err = geo.NewGeocoder("bing", geo.GeocoderSettings{})
+	assert.ErrorIs(t, err, geo.ErrUnknownGeocoder)
+}
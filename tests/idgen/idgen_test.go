@@ -0,0 +1,63 @@
+package idgen_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUID(t *testing.T) {
+	var ids idgen.UUID
+
+	previous := ids.NewID()
+	for range 1000 {
+		id := ids.NewID()
+		parsed, err := uuid.Parse(id)
+		require.NoError(t, err)
+		assert.Equal(t, uuid.Version(7), parsed.Version())
+		assert.Greater(t, id, previous)
+		previous = id
+	}
+}
+
+func TestSequence(t *testing.T) {
+	ids := idgen.NewSequence()
+	assert.Equal(t, "00000000-0000-7000-8000-000000000001", ids.NewID())
+	assert.Equal(t, "00000000-0000-7000-8000-000000000002", ids.NewID())
+
+	id := ids.NewID()
+	_, err := domain.ParseBookingID(id)
+	require.NoError(t, err)
+	assert.Equal(t, "00000000-0000-7000-8000-000000000003", id, "the sequence IDs are canonical UUIDs")
+
+	assert.Equal(t, "00000000-0000-7000-8000-000000000001", idgen.NewSequence().NewID(), "every sequence starts over")
+}
+
+func TestSequence_Concurrent(t *testing.T) {
+	ids := idgen.NewSequence()
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				id := ids.NewID()
+				mu.Lock()
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 800)
+}
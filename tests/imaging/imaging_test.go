@@ -0,0 +1,210 @@
+package imaging_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG is a w×h image whose left half is red and right half blue.
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, data []byte) image.Image {
+	t.Helper()
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	return img
+}
+
+func TestResize_Fits(t *testing.T) {
+	source := testPNG(t, 400, 200)
+
+	for name, test := range map[string]struct {
+		opts          imaging.Options
+		width, height int
+	}{
+		"contain keeps the aspect ratio": {imaging.Options{Width: 100, Height: 100, Fit: imaging.FitContain}, 100, 50},
+		"width only":                     {imaging.Options{Width: 100}, 100, 50},
+		"height only":                    {imaging.Options{Height: 20}, 40, 20},
+		"cover crops to the box":         {imaging.Options{Width: 100, Height: 100, Fit: imaging.FitCover}, 100, 100},
+		"fill stretches":                 {imaging.Options{Width: 100, Height: 100, Fit: imaging.FitFill}, 100, 100},
+		"never scaled up":                {imaging.Options{Width: 800, Height: 800}, 400, 200},
+		"cover never scaled up":          {imaging.Options{Width: 1000, Height: 500, Fit: imaging.FitCover}, 400, 200},
+	} {
+		data, contentType, err := imaging.Resize(source, test.opts)
+		require.NoError(t, err, name)
+		assert.Equal(t, "image/png", contentType, name)
+
+		bounds := decode(t, data).Bounds()
+		assert.Equal(t, test.width, bounds.Dx(), name)
+		assert.Equal(t, test.height, bounds.Dy(), name)
+	}
+}
+
+func TestResize_AveragesAndCrops(t *testing.T) {
+	source := testPNG(t, 400, 200)
+
+	data, _, err := imaging.Resize(source, imaging.Options{Width: 2, Height: 1, Fit: imaging.FitFill})
+	require.NoError(t, err)
+	img := decode(t, data)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(img.At(0, 0)))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, color.RGBAModel.Convert(img.At(1, 0)))
+
+	// The centred square of a half red, half blue image is still half red and half blue.
+	data, _, err = imaging.Resize(source, imaging.Options{Width: 2, Height: 2, Fit: imaging.FitCover})
+	require.NoError(t, err)
+	img = decode(t, data)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, color.RGBAModel.Convert(img.At(0, 1)))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, color.RGBAModel.Convert(img.At(1, 1)))
+}
+
+func TestResize_KeepsJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, decode(t, testPNG(t, 64, 64)), nil))
+
+	data, contentType, err := imaging.Resize(buf.Bytes(), imaging.Options{Width: 16})
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, 16, decode(t, data).Bounds().Dx())
+
+	_, _, err = imaging.Resize([]byte("not an image"), imaging.Options{Width: 16})
+	assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+}
+
+func TestParseFit(t *testing.T) {
+	fit, err := imaging.ParseFit("")
+	require.NoError(t, err)
+	assert.Equal(t, imaging.FitContain, fit)
+
+	fit, err = imaging.ParseFit("cover")
+	require.NoError(t, err)
+	assert.Equal(t, imaging.FitCover, fit)
+
+	_, err = imaging.ParseFit("zoom")
+	assert.ErrorIs(t, err, imaging.ErrInvalidFit)
+}
+
+func TestDiskCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := imaging.NewDiskCache(dir, 10)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Put("a", []byte("aaaa")))
+	require.NoError(t, cache.Put("b", []byte("bbbb")))
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+	require.NoError(t, cache.Put("c", []byte("cccc")))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "b was used least recently")
+	data, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "aaaa", string(data))
+	assert.Equal(t, int64(8), cache.Size())
+	assert.NoFileExists(t, filepath.Join(dir, "b"))
+
+	require.NoError(t, cache.Put("huge", bytes.Repeat([]byte("x"), 11)))
+	_, ok = cache.Get("huge")
+	assert.False(t, ok, "larger than the whole cache")
+
+	assert.Error(t, cache.Put("../escape", []byte("x")))
+}
+
+func TestDiskCache_RemovePrefixAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := imaging.NewDiskCache(dir, 100)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Put("image1_100x0_contain", []byte("one")))
+	require.NoError(t, cache.Put("image1_0x0_contain", []byte("two")))
+	require.NoError(t, cache.Put("image2_100x0_contain", []byte("three")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("partial"), 0o644))
+
+	cache.RemovePrefix("image1_")
+	_, ok := cache.Get("image1_100x0_contain")
+	assert.False(t, ok)
+
+	reopened, err := imaging.NewDiskCache(dir, 100)
+	require.NoError(t, err)
+	data, ok := reopened.Get("image2_100x0_contain")
+	assert.True(t, ok)
+	assert.Equal(t, "three", string(data))
+	assert.Equal(t, int64(5), reopened.Size())
+	assert.NoFileExists(t, filepath.Join(dir, ".tmp-123"))
+}
+
+func TestDiskCache_Disabled(t *testing.T) {
+	cache, err := imaging.NewDiskCache(filepath.Join(t.TempDir(), "unused"), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Put("a", []byte("a")))
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := imaging.NewMemoryCache(10)
+
+	require.NoError(t, cache.Put("a", []byte("aaaa")))
+	require.NoError(t, cache.Put("b", []byte("bbbb")))
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+	require.NoError(t, cache.Put("c", []byte("cccc")))
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "b was used least recently")
+	data, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "aaaa", string(data))
+	assert.Equal(t, int64(8), cache.Size())
+
+	require.NoError(t, cache.Put("huge", bytes.Repeat([]byte("x"), 11)))
+	_, ok = cache.Get("huge")
+	assert.False(t, ok, "larger than the whole cache")
+
+	require.NoError(t, cache.Put("a", []byte("aa")))
+	assert.Equal(t, int64(6), cache.Size())
+}
+
+func TestMemoryCache_RemovePrefix(t *testing.T) {
+	cache := imaging.NewMemoryCache(100)
+
+	require.NoError(t, cache.Put("image1_100x0_contain", []byte("one")))
+	require.NoError(t, cache.Put("image1_0x0_contain", []byte("two")))
+	require.NoError(t, cache.Put("image2_100x0_contain", []byte("three")))
+
+	cache.RemovePrefix("image1_")
+	_, ok := cache.Get("image1_0x0_contain")
+	assert.False(t, ok)
+	_, ok = cache.Get("image2_100x0_contain")
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), cache.Size())
+}
@@ -0,0 +1,385 @@
+package imaging_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The decoder below reads the VP8L features the encoder uses (subtract green and predictor
+// transforms, simple and normal prefix codes, literal pixels) following RFC 9649, so that encoded
+// images can be compared with their source pixel by pixel.
+
+type vp8lReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *vp8lReader) bits(n int) (uint32, error) {
+	var value uint32
+	for i := 0; i < n; i++ {
+		if r.pos >= len(r.data)*8 {
+			return 0, errors.New("unexpected end of data")
+		}
+		bit := uint32(r.data[r.pos/8]>>(r.pos%8)) & 1
+		value |= bit << i
+		r.pos++
+	}
+	return value, nil
+}
+
+type vp8lCode struct {
+	single  int
+	symbols map[[2]int]int // (length, code) → symbol
+}
+
+func (r *vp8lReader) symbol(code *vp8lCode) (int, error) {
+	if code.single >= 0 {
+		return code.single, nil
+	}
+	value := 0
+	for length := 1; length <= 15; length++ {
+		bit, err := r.bits(1)
+		if err != nil {
+			return 0, err
+		}
+		value = value<<1 | int(bit)
+		if symbol, ok := code.symbols[[2]int{length, value}]; ok {
+			return symbol, nil
+		}
+	}
+	return 0, errors.New("invalid prefix code")
+}
+
+func buildVP8LCode(lengths []int) (*vp8lCode, error) {
+	used, last := 0, 0
+	kraft := 0.0
+	var count [16]int
+	for symbol, n := range lengths {
+		if n > 0 {
+			used++
+			last = symbol
+			count[n]++
+			kraft += 1 / float64(int(1)<<n)
+		}
+	}
+	if used == 0 {
+		return nil, errors.New("empty prefix code")
+	}
+	if used == 1 {
+		return &vp8lCode{single: last}, nil
+	}
+	if kraft != 1 {
+		return nil, fmt.Errorf("incomplete prefix code: %v", kraft)
+	}
+
+	var next [16]int
+	code := 0
+	for n := 1; n < 16; n++ {
+		code = (code + count[n-1]) << 1
+		next[n] = code
+	}
+	result := &vp8lCode{single: -1, symbols: make(map[[2]int]int)}
+	for symbol, n := range lengths {
+		if n > 0 {
+			result.symbols[[2]int{n, next[n]}] = symbol
+			next[n]++
+		}
+	}
+	return result, nil
+}
+
+func (r *vp8lReader) prefixCode(alphabet int) (*vp8lCode, error) {
+	lengths := make([]int, alphabet)
+
+	simple, err := r.bits(1)
+	if err != nil {
+		return nil, err
+	}
+	if simple == 1 {
+		num, _ := r.bits(1)
+		first8, _ := r.bits(1)
+		width := 1
+		if first8 == 1 {
+			width = 8
+		}
+		s0, err := r.bits(width)
+		if err != nil {
+			return nil, err
+		}
+		lengths[s0] = 1
+		if num == 1 {
+			s1, err := r.bits(8)
+			if err != nil {
+				return nil, err
+			}
+			lengths[s1] = 1
+		}
+		return buildVP8LCode(lengths)
+	}
+
+	order := []int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	numCodes, _ := r.bits(4)
+	codeLengthLengths := make([]int, 19)
+	for i := 0; i < int(numCodes)+4; i++ {
+		n, err := r.bits(3)
+		if err != nil {
+			return nil, err
+		}
+		codeLengthLengths[order[i]] = int(n)
+	}
+	codeLengthCode, err := buildVP8LCode(codeLengthLengths)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSymbol := alphabet
+	if useMax, _ := r.bits(1); useMax == 1 {
+		nbits, _ := r.bits(3)
+		n, _ := r.bits(2 + 2*int(nbits))
+		maxSymbol = 2 + int(n)
+	}
+
+	previous := 8
+	for symbol := 0; symbol < alphabet && maxSymbol > 0; maxSymbol-- {
+		n, err := r.symbol(codeLengthCode)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case n < 16:
+			lengths[symbol] = n
+			symbol++
+			if n != 0 {
+				previous = n
+			}
+		default:
+			// 16 repeats the previous length 3 to 6 times, 17 and 18 write 3 to 10 and 11 to 138 zeros.
+			extraBits, offset, value := 2, 3, previous
+			switch n {
+			case 17:
+				extraBits, value = 3, 0
+			case 18:
+				extraBits, offset, value = 7, 11, 0
+			}
+			extra, _ := r.bits(extraBits)
+			for k := 0; k < int(extra)+offset && symbol < alphabet; k++ {
+				lengths[symbol] = value
+				symbol++
+			}
+		}
+	}
+	return buildVP8LCode(lengths)
+}
+
+// imageStream decodes an entropy-coded image of w×h pixels into A, R, G, B quadruples.
+func (r *vp8lReader) imageStream(w, h int, level0 bool) ([][4]byte, error) {
+	if cache, _ := r.bits(1); cache != 0 {
+		return nil, errors.New("colour cache is not expected")
+	}
+	if level0 {
+		if meta, _ := r.bits(1); meta != 0 {
+			return nil, errors.New("meta prefix codes are not expected")
+		}
+	}
+
+	var codes [5]*vp8lCode
+	for i, alphabet := range []int{280, 256, 256, 256, 40} {
+		code, err := r.prefixCode(alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("code %d: %w", i, err)
+		}
+		codes[i] = code
+	}
+
+	pixels := make([][4]byte, w*h)
+	for i := range pixels {
+		green, err := r.symbol(codes[0])
+		if err != nil {
+			return nil, err
+		}
+		if green >= 256 {
+			return nil, errors.New("backward references are not expected")
+		}
+		red, _ := r.symbol(codes[1])
+		blue, _ := r.symbol(codes[2])
+		alpha, err := r.symbol(codes[3])
+		if err != nil {
+			return nil, err
+		}
+		pixels[i] = [4]byte{byte(alpha), byte(red), byte(green), byte(blue)}
+	}
+	return pixels, nil
+}
+
+func decodeWebP(t *testing.T, data []byte) *image.NRGBA {
+	t.Helper()
+
+	require.Greater(t, len(data), 20)
+	require.Equal(t, "RIFF", string(data[:4]))
+	require.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]))
+	require.Equal(t, "WEBPVP8L", string(data[8:16]))
+	size := binary.LittleEndian.Uint32(data[16:20])
+	require.LessOrEqual(t, int(size), len(data)-20)
+
+	r := &vp8lReader{data: data[20 : 20+size]}
+	signature, _ := r.bits(8)
+	require.Equal(t, uint32(0x2f), signature)
+	w1, _ := r.bits(14)
+	h1, _ := r.bits(14)
+	_, _ = r.bits(1)
+	version, _ := r.bits(3)
+	require.Zero(t, version)
+	w, h := int(w1)+1, int(h1)+1
+
+	type transform struct {
+		kind  uint32
+		bits  int
+		modes [][4]byte
+	}
+	var transforms []transform
+	for {
+		present, err := r.bits(1)
+		require.NoError(t, err)
+		if present == 0 {
+			break
+		}
+		kind, _ := r.bits(2)
+		switch kind {
+		case 2:
+			transforms = append(transforms, transform{kind: kind})
+		case 0:
+			bits, _ := r.bits(3)
+			blockBits := int(bits) + 2
+			blocksW, blocksH := (w+1<<blockBits-1)>>blockBits, (h+1<<blockBits-1)>>blockBits
+			modes, err := r.imageStream(blocksW, blocksH, false)
+			require.NoError(t, err)
+			transforms = append(transforms, transform{kind: kind, bits: blockBits, modes: modes})
+		default:
+			t.Fatalf("unexpected transform %d", kind)
+		}
+	}
+
+	pixels, err := r.imageStream(w, h, true)
+	require.NoError(t, err)
+
+	for i := len(transforms) - 1; i >= 0; i-- {
+		tr := transforms[i]
+		switch tr.kind {
+		case 2:
+			for p := range pixels {
+				pixels[p][1] += pixels[p][2]
+				pixels[p][3] += pixels[p][2]
+			}
+		case 0:
+			blocksW := (w + 1<<tr.bits - 1) >> tr.bits
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					var predicted [4]byte
+					switch {
+					case x == 0 && y == 0:
+						predicted = [4]byte{0xff, 0, 0, 0}
+					case y == 0:
+						predicted = pixels[y*w+x-1]
+					case x == 0:
+						predicted = pixels[(y-1)*w+x]
+					default:
+						mode := tr.modes[(y>>tr.bits)*blocksW+(x>>tr.bits)][2]
+						require.Equal(t, byte(12), mode, "only the gradient predictor is expected")
+						left, top, topLeft := pixels[y*w+x-1], pixels[(y-1)*w+x], pixels[(y-1)*w+x-1]
+						for c := range predicted {
+							predicted[c] = byte(min(255, max(0, int(left[c])+int(top[c])-int(topLeft[c]))))
+						}
+					}
+					for c := range predicted {
+						pixels[y*w+x][c] += predicted[c]
+					}
+				}
+			}
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i, p := range pixels {
+		copy(img.Pix[i*4:], []byte{p[1], p[2], p[3], p[0]})
+	}
+	return img
+}
+
+func TestEncodeWebP_RoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	noise := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	random.Read(noise.Pix)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 600, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 600; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y * 6), B: uint8(x + y), A: 255})
+		}
+	}
+
+	flat := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := range flat.Pix {
+		flat.Pix[i] = 0x80
+	}
+
+	twoColours := image.NewNRGBA(image.Rect(0, 0, 5, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 5; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if (x+y)%2 == 0 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			twoColours.SetNRGBA(x, y, c)
+		}
+	}
+
+	single := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	single.SetNRGBA(0, 0, color.NRGBA{R: 1, G: 2, B: 3, A: 4})
+
+	for name, img := range map[string]*image.NRGBA{
+		"noise":       noise,
+		"gradient":    gradient,
+		"flat":        flat,
+		"two colours": twoColours,
+		"one pixel":   single,
+	} {
+		data, err := imaging.EncodeWebP(img)
+		require.NoError(t, err, name)
+
+		decoded := decodeWebP(t, data)
+		assert.Equal(t, img.Bounds(), decoded.Bounds(), name)
+		assert.Equal(t, img.Pix, decoded.Pix, name)
+	}
+}
+
+func TestEncodeWebP_Limits(t *testing.T) {
+	_, err := imaging.EncodeWebP(image.NewNRGBA(image.Rect(0, 0, 16385, 1)))
+	assert.ErrorIs(t, err, imaging.ErrImageTooLarge)
+}
+
+func TestResize_ToWebP(t *testing.T) {
+	data, contentType, err := imaging.Resize(testPNG(t, 400, 200), imaging.Options{Width: 100, Format: imaging.ContentTypeWebP})
+	require.NoError(t, err)
+	assert.Equal(t, imaging.ContentTypeWebP, contentType)
+
+	decoded := decodeWebP(t, data)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, decoded.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{B: 255, A: 255}, decoded.NRGBAAt(99, 49))
+
+	_, _, err = imaging.Resize(testPNG(t, 4, 4), imaging.Options{Format: imaging.ContentTypeAVIF})
+	assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+	assert.False(t, imaging.CanEncode(imaging.ContentTypeAVIF))
+	assert.Equal(t, "image1_100x0_contain_webp", imaging.Options{Width: 100, Format: imaging.ContentTypeWebP}.Key("image1"))
+}
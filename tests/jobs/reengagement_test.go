@@ -0,0 +1,35 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/jobs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReengagementUseCase struct {
+	dates []time.Time
+}
+
+func (u *recordingReengagementUseCase) SendInvitations(_ context.Context, date time.Time) (int, error) {
+	u.dates = append(u.dates, date)
+	return 0, nil
+}
+
+func TestReengagementJob_InvitesAsOfClock(t *testing.T) {
+	now := time.Date(2025, time.June, 2, 10, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+	reengagement := new(recordingReengagementUseCase)
+	job := jobs.NewReengagementJob(reengagement, fake)
+
+	require.NoError(t, job.Run(context.Background()))
+	fake.Advance(24 * time.Hour)
+	require.NoError(t, job.Run(context.Background()))
+
+	assert.Equal(t, []time.Time{now, now.Add(24 * time.Hour)}, reengagement.dates)
+}
@@ -0,0 +1,107 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/jobs"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingJob struct {
+	runs atomic.Int32
+	err  error
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Run(_ context.Context) error {
+	j.runs.Add(1)
+	return j.err
+}
+
+func newTestContext(t *testing.T) context.Context {
+	log, err := logger.NewLogger()
+	require.NoError(t, err)
+
+	return logger.NewContext(context.Background(), log)
+}
+
+func TestScheduler_RunsJobsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(newTestContext(t))
+
+	job := &countingJob{}
+	failingJob := &countingJob{err: errors.New("job error")}
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Every(10*time.Millisecond, job)
+	scheduler.Every(10*time.Millisecond, failingJob)
+	scheduler.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return job.runs.Load() >= 3 && failingJob.runs.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	scheduler.Wait()
+
+	runs := job.runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, runs, job.runs.Load())
+}
+
+func TestNextDailyRun(t *testing.T) {
+	loc := time.FixedZone("test", 3*60*60)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "later today",
+			now:  time.Date(2024, 3, 10, 1, 30, 0, 0, loc),
+			want: time.Date(2024, 3, 10, 3, 0, 0, 0, loc),
+		},
+		{
+			name: "exactly at run time moves to tomorrow",
+			now:  time.Date(2024, 3, 10, 3, 0, 0, 0, loc),
+			want: time.Date(2024, 3, 11, 3, 0, 0, 0, loc),
+		},
+		{
+			name: "after run time across month end",
+			now:  time.Date(2024, 3, 31, 22, 0, 0, 0, loc),
+			want: time.Date(2024, 4, 1, 3, 0, 0, 0, loc),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, jobs.NextDailyRun(tt.now, 3*time.Hour))
+		})
+	}
+}
+
+func TestScheduler_DailyDoesNotRunOnStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(newTestContext(t))
+
+	job := &countingJob{}
+
+	scheduler := jobs.NewScheduler()
+	scheduler.Daily(12*time.Hour, job)
+	scheduler.Start(ctx)
+
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	scheduler.Wait()
+
+	assert.Equal(t, int32(0), job.runs.Load())
+}
@@ -163,6 +163,32 @@ func TestRequestID(t *testing.T) {
 	require.Equal(t, requestID, fieldsWithRequestID[1].String)
 }
 
+func TestContextFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), utils.RequestID, "test-request-id")
+	ctx = logger.WithUserID(ctx, "caller")
+	ctx = logger.WithRestaurantID(ctx, "r1")
+	ctx = logger.WithFields(ctx, zap.String(logger.UserIDKey, "u1"), zap.String(logger.BookingIDKey, "b1"))
+
+	fields := utils.AddContextFields(ctx, []zap.Field{zap.String("test", "value")})
+
+	keys := make([]string, 0, len(fields))
+	values := make(map[string]string)
+	for _, field := range fields {
+		keys = append(keys, field.Key)
+		values[field.Key] = field.String
+	}
+	require.Equal(t, []string{"test", "request_id", "restaurant_id", "user_id", "booking_id"}, keys)
+	require.Equal(t, "u1", values["user_id"], "a later field replaces the one with the same key")
+
+	require.Len(t, utils.AddContextFields(context.Background(), nil), 0)
+}
+
+func TestSampledOut(t *testing.T) {
+	ctx := context.Background()
+	require.False(t, utils.IsSampledOut(ctx))
+	require.True(t, utils.IsSampledOut(utils.WithSampledOut(ctx)))
+}
+
 func TestZapLoggerAdapter(t *testing.T) {
 	encoderConfig := zapcore.EncoderConfig{
 		MessageKey:     "message",
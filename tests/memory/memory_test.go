@@ -0,0 +1,1519 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/memory"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestContext() context.Context {
+	loggerInstance, _ := logger.NewLogger()
+	return logger.NewContext(context.Background(), loggerInstance)
+}
+
+// seedRestaurant creates a restaurant with one slot of the capacity a week from now.
+// testNow is the instant the clocks of the use cases under test stand at.
+var testNow = time.Date(2026, time.March, 2, 12, 0, 0, 0, time.UTC)
+
+func newTestClock() *clock.Fake {
+	return clock.NewFake(testNow)
+}
+
+func seedRestaurant(t *testing.T, ctx context.Context, factory *memory.RepositoryFactory, capacity int) (*domain.Restaurant, *domain.Availability) {
+	t.Helper()
+
+	restaurant := &domain.Restaurant{Name: "Memory Bistro", Slug: "memory-bistro", Currency: domain.DefaultCurrency}
+	require.NoError(t, factory.Restaurant().Create(ctx, restaurant))
+
+	slot := &domain.Availability{
+		RestaurantID: restaurant.ID,
+		Date:         testNow.AddDate(0, 0, 7).Truncate(24 * time.Hour),
+		TimeSlot:     "19:00",
+		Capacity:     capacity,
+	}
+	require.NoError(t, factory.Availability().SetAvailability(ctx, slot, false))
+
+	return restaurant, slot
+}
+
+func seedUser(t *testing.T, ctx context.Context, factory *memory.RepositoryFactory, email string) string {
+	t.Helper()
+
+	user := &domain.User{Name: "Guest", Email: email, NormalizedEmail: email}
+	require.NoError(t, factory.User().Create(ctx, user))
+
+	return user.ID
+}
+
+func TestRepositories_NotFound(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+
+	_, err := factory.Restaurant().GetByID(ctx, "missing")
+	assert.EqualError(t, err, common.ErrRestaurantNotFound)
+
+	_, err = factory.Booking().GetByID(ctx, "missing")
+	assert.EqualError(t, err, common.ErrBookingNotFound)
+
+	err = factory.Availability().UpdateReservedSeats(ctx, "missing", 1)
+	assert.EqualError(t, err, common.ErrAvailabilityNotFound)
+
+	_, err = factory.Incentive().GetRule(ctx, "missing")
+	assert.EqualError(t, err, common.ErrIncentiveRuleNotFound)
+}
+
+func TestRepositories_GenerateIDsFromTheStoreGenerator(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 2)
+
+	assert.Equal(t, "00000000-0000-7000-8000-000000000001", restaurant.ID)
+	assert.Equal(t, "00000000-0000-7000-8000-000000000002", slot.ID)
+}
+
+func TestRepositories_ReturnCopies(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 10)
+
+	restaurant.Name = "Changed behind the store's back"
+
+	stored, err := factory.Restaurant().GetByID(ctx, domain.RestaurantID(restaurant.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Memory Bistro", stored.Name)
+}
+
+func TestRestaurantRepository_UpdateKeepsSlugRedirect(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 10)
+
+	restaurant.Slug = "memory-brasserie"
+	require.NoError(t, factory.Restaurant().Update(ctx, restaurant))
+
+	found, err := factory.Restaurant().GetBySlug(ctx, "memory-bistro")
+	require.NoError(t, err)
+	assert.Equal(t, restaurant.ID, found.ID)
+	assert.Equal(t, "memory-brasserie", found.Slug)
+}
+
+func TestRestaurantRepository_SearchByAdultOnly(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	cafe, _ := seedRestaurant(t, ctx, factory, 10)
+	bar := &domain.Restaurant{Name: "Memory Bar", Slug: "memory-bar", Currency: domain.DefaultCurrency, IsAdultOnly: true}
+	require.NoError(t, factory.Restaurant().Create(ctx, bar))
+
+	adultOnly, notAdultOnly := true, false
+	found, err := factory.Restaurant().Search(ctx, domain.RestaurantFilter{AdultOnly: &adultOnly}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, bar.ID, found[0].ID)
+
+	found, err = factory.Restaurant().Search(ctx, domain.RestaurantFilter{AdultOnly: &notAdultOnly}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, cafe.ID, found[0].ID)
+
+	found, err = factory.Restaurant().Search(ctx, domain.RestaurantFilter{}, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, found, 2)
+}
+
+func TestRestaurantRepository_SearchByQueryAndFacets(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	repo := factory.Restaurant()
+
+	trattoria := &domain.Restaurant{Name: "Trattoria Roma", Slug: "trattoria-roma", Address: "Main st. 1", Cuisine: "Italian", Description: "Fresh pasta every day", Currency: domain.DefaultCurrency}
+	sushi := &domain.Restaurant{Name: "Sushi Bar", Slug: "sushi-bar", Address: "Harbour rd. 5", Cuisine: "japanese", Description: "Fresh fish", Currency: domain.DefaultCurrency}
+	sandbox := &domain.Restaurant{Name: "Pasta Sandbox", Slug: "pasta-sandbox", Address: "Main st. 2", Cuisine: "italian", Description: "Fresh pasta", Currency: domain.DefaultCurrency, IsTest: true}
+	for _, restaurant := range []*domain.Restaurant{trattoria, sushi, sandbox} {
+		require.NoError(t, repo.Create(ctx, restaurant))
+	}
+	require.NoError(t, factory.WorkingHours().SetWorkingHours(ctx, &domain.WorkingHours{RestaurantID: sushi.ID, WeekDay: domain.Friday, OpenTime: "18:00", CloseTime: "02:00"}))
+
+	names := func(filter domain.RestaurantFilter) []string {
+		found, err := repo.Search(ctx, filter, 0, 10)
+		require.NoError(t, err)
+		names := make([]string, 0, len(found))
+		for _, restaurant := range found {
+			names = append(names, restaurant.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Pasta Sandbox", "Trattoria Roma"}, names(domain.RestaurantFilter{Query: "FRESH pasta"}))
+	assert.Equal(t, []string{"Trattoria Roma"}, names(domain.RestaurantFilter{Query: "fresh pasta", LiveOnly: true}))
+	assert.Equal(t, []string{"Sushi Bar"}, names(domain.RestaurantFilter{Query: "fresh -pasta"}))
+	assert.Equal(t, []string{"Pasta Sandbox", "Trattoria Roma"}, names(domain.RestaurantFilter{Cuisine: "italian"}))
+	assert.Equal(t, []string{"Trattoria Roma"}, names(domain.RestaurantFilter{Address: "main 1"}))
+
+	saturdayNight := time.Date(2026, 5, 9, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"Sushi Bar"}, names(domain.RestaurantFilter{OpenAt: &saturdayNight}))
+
+	// Special days and closures decide over the working hours, as in the schedule.
+	require.NoError(t, factory.SpecialDay().Set(ctx, &domain.SpecialDay{RestaurantID: sushi.ID, Date: time.Date(2026, 5, 15, 0, 0, 0, 0, time.UTC), IsClosed: true}))
+	require.NoError(t, factory.SpecialDay().Set(ctx, &domain.SpecialDay{RestaurantID: trattoria.ID, Date: time.Date(2026, 5, 14, 0, 0, 0, 0, time.UTC), OpenTime: "20:00", CloseTime: "23:00"}))
+	require.NoError(t, factory.RestaurantClosure().Create(ctx, &domain.RestaurantClosure{RestaurantID: sushi.ID,
+		StartsOn: time.Date(2026, 5, 22, 0, 0, 0, 0, time.UTC), ReopensOn: time.Date(2026, 5, 23, 0, 0, 0, 0, time.UTC)}))
+
+	closedFriday := time.Date(2026, 5, 16, 1, 0, 0, 0, time.UTC)
+	assert.Empty(t, names(domain.RestaurantFilter{OpenAt: &closedFriday}), "a closed special day replaces the friday hours")
+	specialThursday := time.Date(2026, 5, 14, 21, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"Trattoria Roma"}, names(domain.RestaurantFilter{OpenAt: &specialThursday}))
+	fridayOfClosure := time.Date(2026, 5, 22, 23, 0, 0, 0, time.UTC)
+	assert.Empty(t, names(domain.RestaurantFilter{OpenAt: &fridayOfClosure}), "a closure closes the day whatever its hours")
+	nextFriday := time.Date(2026, 5, 29, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"Sushi Bar"}, names(domain.RestaurantFilter{OpenAt: &nextFriday}))
+}
+
+func TestRestaurantRepository_EstimateCount(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+
+	count, err := factory.Restaurant().EstimateCount(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	seedRestaurant(t, ctx, factory, 10)
+	count, err = factory.Restaurant().EstimateCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestAvailabilityRepository_UpdateReservedSeatsBeyondCapacity(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+
+	err := factory.Availability().UpdateReservedSeats(ctx, slot.ID, 5)
+	assert.Error(t, err)
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, 0, slots[0].Reserved)
+}
+
+func TestAvailabilityRepository_CreateBatch(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+
+	created := []*domain.Availability{
+		{RestaurantID: restaurant.ID, Date: slot.Date, TimeSlot: "21:00", Capacity: 6},
+		{RestaurantID: restaurant.ID, Date: slot.Date, TimeSlot: "22:00", Capacity: 6},
+	}
+	require.NoError(t, factory.Availability().CreateBatch(ctx, created))
+	assert.NotEmpty(t, created[0].ID)
+
+	// A batch with a slot that exists already is rejected as a whole.
+	err := factory.Availability().CreateBatch(ctx, []*domain.Availability{
+		{RestaurantID: restaurant.ID, Date: slot.Date, TimeSlot: "23:00", Capacity: 6},
+		{RestaurantID: restaurant.ID, Date: slot.Date, TimeSlot: slot.TimeSlot, Capacity: 6},
+	})
+	assert.Error(t, err)
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	assert.Len(t, slots, 3)
+}
+
+func TestAvailabilityRepository_ConcurrentReservations(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 50)
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = factory.Availability().UpdateReservedSeats(ctx, slot.ID, 1)
+		}()
+	}
+	wg.Wait()
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	assert.Equal(t, 50, slots[0].Reserved)
+}
+
+func TestAvailabilityRepository_ListChanges(t *testing.T) {
+	ctx := setupTestContext()
+	clk := newTestClock()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), clk))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+	other := &domain.Availability{RestaurantID: restaurant.ID, Date: slot.Date, TimeSlot: "20:00", Capacity: 4}
+	require.NoError(t, factory.Availability().SetAvailability(ctx, other, false))
+
+	changes, err := factory.Availability().ListChanges(ctx, domain.RestaurantID(restaurant.ID), domain.SyncCursor{}, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, slot.ID, changes[0].ID)
+	version := domain.SyncCursor{ChangedAt: changes[1].UpdatedAt, ID: changes[1].ID}
+
+	changes, err = factory.Availability().ListChanges(ctx, domain.RestaurantID(restaurant.ID), version, 10)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	clk.Advance(time.Second)
+	require.NoError(t, factory.Availability().UpdateReservedSeats(ctx, slot.ID, 2))
+	changes, err = factory.Availability().ListChanges(ctx, domain.RestaurantID(restaurant.ID), version, 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, slot.ID, changes[0].ID)
+	assert.Equal(t, 2, changes[0].Reserved)
+}
+
+func TestTransactor_RollsBackOnError(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	errStop := errors.New("stop")
+
+	err := factory.Transactor().InTransaction(ctx, func(ctx context.Context) error {
+		if err := factory.Availability().UpdateReservedSeats(ctx, slot.ID, 3); err != nil {
+			return err
+		}
+		if err := factory.Restaurant().Delete(ctx, domain.RestaurantID(restaurant.ID)); err != nil {
+			return err
+		}
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	_, err = factory.Restaurant().GetByID(ctx, domain.RestaurantID(restaurant.ID))
+	require.NoError(t, err)
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, 0, slots[0].Reserved)
+}
+
+func TestTransactor_NestedRollbackKeepsOuterWrites(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	transactor := factory.Transactor()
+
+	err := transactor.InTransaction(ctx, func(ctx context.Context) error {
+		if err := factory.Availability().UpdateReservedSeats(ctx, slot.ID, 2); err != nil {
+			return err
+		}
+		return transactor.DryRun(ctx, func(ctx context.Context) error {
+			return factory.Availability().UpdateReservedSeats(ctx, slot.ID, 5)
+		})
+	})
+	require.NoError(t, err)
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	assert.Equal(t, 2, slots[0].Reserved)
+}
+
+func TestRestaurantRepository_DeleteCascades(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	booking := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2}
+	require.NoError(t, factory.Booking().Create(ctx, booking))
+
+	require.NoError(t, factory.Restaurant().Delete(ctx, domain.RestaurantID(restaurant.ID)))
+
+	_, err := factory.Booking().GetByID(ctx, domain.BookingID(booking.ID))
+	assert.EqualError(t, err, common.ErrBookingNotFound)
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	assert.Empty(t, slots)
+}
+
+func TestExportRepository_LeavesOutTestRecords(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	sandbox := &domain.Restaurant{Name: "Sandbox", Slug: "sandbox", Currency: domain.DefaultCurrency, IsTest: true}
+	require.NoError(t, factory.Restaurant().Create(ctx, sandbox))
+
+	live := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, GuestsCount: 2}
+	testBooking := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, GuestsCount: 2, IsTest: true}
+	sandboxBooking := &domain.Booking{RestaurantID: sandbox.ID, UserID: userID, GuestsCount: 2}
+	for _, booking := range []*domain.Booking{live, testBooking, sandboxBooking} {
+		booking.Date, booking.Time, booking.UpdatedAt = slot.Date, slot.TimeSlot, testNow
+		require.NoError(t, factory.Booking().Create(ctx, booking))
+	}
+
+	until := testNow.Add(time.Hour)
+	exported := func(entity domain.ExportEntity) []string {
+		records, err := factory.Export().ListChanges(ctx, entity, time.Time{}, "", until, 100)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(records))
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{restaurant.ID}, exported(domain.ExportRestaurants))
+	assert.Equal(t, []string{live.ID}, exported(domain.ExportBookings))
+	assert.Equal(t, []string{userID}, exported(domain.ExportUsers), "entities without test records are exported whole")
+}
+
+func TestBookingRepository_ListsBookingsOfASlotInCreationOrder(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	var created []string
+	for range 5 {
+		booking := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2}
+		require.NoError(t, factory.Booking().Create(ctx, booking))
+		created = append(created, booking.ID)
+	}
+
+	bookings, err := factory.Booking().ListByUser(ctx, domain.UserID(userID), domain.BookingCursor{}, 100)
+	require.NoError(t, err)
+	require.Len(t, bookings, len(created))
+	for i, booking := range bookings {
+		assert.Equal(t, created[len(created)-1-i], booking.ID)
+	}
+}
+
+func TestNotificationDeferral_DeliversAfterQuietHoursInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 10)
+	fakeClock := clock.NewFake(time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC))
+
+	settingsRepo := factory.NotificationSettings()
+	require.NoError(t, settingsRepo.Save(ctx, &domain.NotificationSettings{
+		RecipientType: domain.RecipientTypeRestaurant,
+		RecipientID:   restaurant.ID,
+		QuietHours:    []domain.QuietHours{{Channel: domain.NotificationChannelInApp, Start: "22:00", End: "08:00"}},
+	}))
+	settings, err := settingsRepo.Get(ctx, domain.RecipientTypeRestaurant, restaurant.ID)
+	require.NoError(t, err)
+	require.Len(t, settings.QuietHours, 1)
+
+	notifier := postgres.NewNotificationService(factory.Notification())
+	deferrals := usecase.NewNotificationDeferralUseCase(factory.DeferredNotification(), settingsRepo, factory.User(),
+		factory.Restaurant(), notifier, nil, nil, fakeClock)
+	router := notification.NewRouter(notifier, settingsRepo, deferrals)
+
+	require.NoError(t, router.NotifyRestaurant(ctx, restaurant.ID, domain.NotificationTypeDailyDigest, "Your day ahead", "digest", restaurant.ID))
+	require.NoError(t, router.NotifyRestaurant(ctx, restaurant.ID, domain.NotificationTypeNewBooking, "New booking", "booking", "b1"))
+
+	delivered, err := factory.Notification().GetByRestaurantID(ctx, restaurant.ID)
+	require.NoError(t, err)
+	require.Len(t, delivered, 1, "the digest waits for the quiet hours to end")
+	assert.Equal(t, "booking", delivered[0].Message)
+
+	report, err := deferrals.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, report.Claimed)
+
+	fakeClock.Set(time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC))
+	report, err = deferrals.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.DeferredDeliveryReport{Claimed: 1, Delivered: 1}, report)
+
+	delivered, err = factory.Notification().GetByRestaurantID(ctx, restaurant.ID)
+	require.NoError(t, err)
+	assert.Len(t, delivered, 2)
+
+	report, err = deferrals.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, report.Claimed, "a delivered notification is not kept")
+}
+
+func TestRestaurantUseCase_PagesThroughCursorsInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+
+	for _, name := range []string{"Bistro", "Dacha", "Aragvi", "Cafe Pushkin", "Erwin"} {
+		require.NoError(t, factory.Restaurant().Create(ctx, &domain.Restaurant{Name: name, Slug: strings.ToLower(name), Currency: domain.DefaultCurrency}))
+	}
+	restaurants := usecase.NewRestaurantUseCase(factory.Restaurant(), factory.WorkingHours(), factory.RestaurantClosure(), factory.SpecialDay(), factory.Transactor(), "RU", newTestClock())
+
+	first, err := restaurants.ListRestaurants(ctx, domain.RestaurantFilter{}, usecase.PageRequest{Limit: 2})
+	require.NoError(t, err)
+	require.True(t, first.HasMore())
+
+	// A restaurant added before the cursor neither repeats nor shifts the next page.
+	require.NoError(t, factory.Restaurant().Create(ctx, &domain.Restaurant{Name: "Aist", Slug: "aist", Currency: domain.DefaultCurrency}))
+
+	var names []string
+	for page := first; ; {
+		for _, restaurant := range page.Items {
+			names = append(names, restaurant.Name)
+		}
+		if !page.HasMore() {
+			break
+		}
+		page, err = restaurants.ListRestaurants(ctx, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: page.NextCursor, Limit: 2})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"Aragvi", "Bistro", "Cafe Pushkin", "Dacha", "Erwin"}, names)
+}
+
+func TestRestaurantUseCase_SpecialDaysInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant := &domain.Restaurant{Name: "Bistro", Slug: "bistro", Currency: domain.DefaultCurrency}
+	require.NoError(t, factory.Restaurant().Create(ctx, restaurant))
+	restaurants := usecase.NewRestaurantUseCase(factory.Restaurant(), factory.WorkingHours(), factory.RestaurantClosure(), factory.SpecialDay(), factory.Transactor(), "RU", newTestClock())
+
+	date := testNow.AddDate(0, 0, 3)
+	holiday := &domain.SpecialDay{RestaurantID: restaurant.ID, Date: date, OpenTime: "10:00", CloseTime: "14:00"}
+	require.NoError(t, restaurants.SetSpecialDay(ctx, holiday))
+	require.NoError(t, restaurants.SetSpecialDay(ctx, &domain.SpecialDay{RestaurantID: restaurant.ID, Date: date, IsClosed: true}))
+
+	days, err := restaurants.ListSpecialDays(ctx, domain.RestaurantID(restaurant.ID))
+	require.NoError(t, err)
+	require.Len(t, days, 1, "a special day replaces the one of its date")
+	assert.Equal(t, holiday.ID, days[0].ID)
+	assert.True(t, days[0].IsClosed)
+
+	schedule, err := restaurants.GetSchedule(ctx, domain.RestaurantID(restaurant.ID), date, date)
+	require.NoError(t, err)
+	assert.True(t, schedule[0].IsClosed())
+
+	require.NoError(t, restaurants.DeleteSpecialDay(ctx, domain.RestaurantID(restaurant.ID), date))
+	err = restaurants.DeleteSpecialDay(ctx, domain.RestaurantID(restaurant.ID), date)
+	assert.EqualError(t, err, common.ErrSpecialDayNotFound)
+}
+
+func TestBookingUseCase_PagesThroughCursorsInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	var created []string
+	for _, at := range []string{"18:00", "21:00", "19:00", "19:00", "20:00"} {
+		booking := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: at, GuestsCount: 2, Status: domain.BookingStatusConfirmed}
+		require.NoError(t, factory.Booking().Create(ctx, booking))
+		created = append(created, booking.ID)
+	}
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+
+	var listed []string
+	page := &usecase.PageResponse[*domain.Booking]{}
+	for {
+		var err error
+		page, err = bookings.GetRestaurantBookings(ctx, domain.RestaurantID(restaurant.ID), domain.BookingFilter{}, usecase.PageRequest{Cursor: page.NextCursor, Limit: 2})
+		require.NoError(t, err)
+		for _, booking := range page.Items {
+			listed = append(listed, booking.ID)
+		}
+		if !page.HasMore() {
+			break
+		}
+	}
+	assert.Equal(t, []string{created[1], created[4], created[3], created[2], created[0]}, listed, "the latest first, ties broken by ID")
+
+	page, err := bookings.GetUserBookings(ctx, domain.UserID(userID), usecase.PageRequest{Limit: 5})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 5)
+	assert.False(t, page.HasMore(), "a page holding the rest of the list is the last")
+}
+
+func TestMenuRepository_PreOrderDailyLimits(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	menu := []domain.MenuItem{{Name: "Tasting menu", Price: 900000, IsAvailable: true, DailyLimit: 3}}
+	require.NoError(t, factory.Menu().SaveMenu(ctx, restaurant.ID, menu))
+	tasting := menu[0].ID
+
+	var bookingIDs []string
+	for range 2 {
+		booking := &domain.Booking{
+			RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2,
+			Status: domain.BookingStatusPending,
+		}
+		require.NoError(t, factory.Booking().Create(ctx, booking))
+		bookingIDs = append(bookingIDs, booking.ID)
+	}
+	order := func(quantity int) []domain.PreOrderItem {
+		return []domain.PreOrderItem{{MenuItemID: tasting, Name: "Tasting menu", UnitPrice: 900000, Quantity: quantity}}
+	}
+
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingIDs[0], order(2)))
+
+	err := factory.Menu().SavePreOrder(ctx, bookingIDs[1], order(2))
+	var limitErr *domain.PreOrderLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, domain.ErrPreOrderLimitReached)
+	assert.Equal(t, 2, limitErr.Reserved)
+	assert.Equal(t, 3, limitErr.Limit)
+	preOrder, err := factory.Menu().GetPreOrder(ctx, bookingIDs[1])
+	require.NoError(t, err)
+	assert.Empty(t, preOrder)
+
+	// Ordering less gives units back, ordering the same again takes none.
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingIDs[0], order(1)))
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingIDs[0], order(1)))
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingIDs[1], order(2)))
+	assert.ErrorIs(t, factory.Menu().SavePreOrder(ctx, bookingIDs[0], order(2)), domain.ErrPreOrderLimitReached)
+
+	require.NoError(t, factory.Booking().UpdateStatus(ctx, domain.BookingID(bookingIDs[1]), domain.BookingStatusCancelled))
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingIDs[0], order(3)))
+}
+
+func TestBookingUseCase_CreateBookingInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+
+	firstUserID := seedUser(t, ctx, factory, "first@example.com")
+	secondUserID := seedUser(t, ctx, factory, "second@example.com")
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+
+	bookingID, err := bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID,
+		UserID:       firstUserID,
+		Date:         slot.Date,
+		Time:         slot.TimeSlot,
+		GuestsCount:  3,
+	})
+	require.NoError(t, err)
+
+	_, err = bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID,
+		UserID:       secondUserID,
+		Date:         slot.Date,
+		Time:         slot.TimeSlot,
+		GuestsCount:  2,
+	})
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability)
+
+	notifications, err := factory.Notification().GetByRestaurantID(ctx, restaurant.ID)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, bookingID, notifications[0].RelatedID)
+}
+
+func TestBookingUseCase_CancelRacesRejectionInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), clock.NewFake(now))
+
+	bookingID, err := bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID,
+		UserID:       userID,
+		Date:         slot.Date,
+		Time:         slot.TimeSlot,
+		GuestsCount:  3,
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		errs[0] = bookings.CancelBooking(ctx, domain.BookingID(bookingID))
+	}()
+	go func() {
+		defer wg.Done()
+		errs[1] = bookings.RejectBooking(ctx, domain.BookingID(bookingID), "")
+	}()
+	wg.Wait()
+
+	// Only one of them takes the booking and gives its seats back.
+	if errs[0] == nil {
+		assert.ErrorIs(t, errs[1], usecase.ErrInvalidBookingStatus)
+	} else {
+		assert.ErrorIs(t, errs[0], usecase.ErrInvalidBookingStatus)
+		assert.NoError(t, errs[1])
+	}
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	assert.Equal(t, 0, slots[0].Reserved)
+
+	booking, err := factory.Booking().GetByID(ctx, domain.BookingID(bookingID))
+	require.NoError(t, err)
+	assert.True(t, booking.UpdatedAt.Equal(now))
+}
+
+func TestBookingUseCase_SeatsAtTablesInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 20)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	tables := usecase.NewTableUseCase(factory.Table(), factory.Restaurant(), factory.Booking())
+	small := &domain.Table{RestaurantID: restaurant.ID, Name: "1", Seats: 2}
+	large := &domain.Table{RestaurantID: restaurant.ID, Name: "2", Seats: 4}
+	require.NoError(t, tables.CreateTable(ctx, small))
+	require.NoError(t, tables.CreateTable(ctx, large))
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	book := func(guests int) (*domain.Booking, error) {
+		booking := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: guests}
+		_, err := bookings.CreateBooking(ctx, booking)
+		return booking, err
+	}
+
+	first, err := book(2)
+	require.NoError(t, err)
+	assert.Equal(t, small.ID, first.TableID)
+
+	second, err := book(2)
+	require.NoError(t, err)
+	assert.Equal(t, large.ID, second.TableID, "the small table is held")
+
+	_, err = book(1)
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability, "every table is held")
+
+	slots, err := factory.Availability().GetByRestaurantAndDate(ctx, domain.RestaurantID(restaurant.ID), slot.Date)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Zero(t, slots[0].AvailableSeats(), "the seats of the slot are those of its free tables")
+
+	clock, err := time.Parse("15:04", slot.TimeSlot)
+	require.NoError(t, err)
+	overlapping := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: clock.Add(time.Hour).Format("15:04"),
+		GuestsCount: 2, Status: domain.BookingStatusPending, TableID: small.ID}
+	assert.EqualError(t, factory.Booking().Create(ctx, overlapping), common.ErrTableUnavailable, "the table is held for the whole seating")
+
+	assert.EqualError(t, tables.DeleteTable(ctx, restaurant.ID, small.ID), common.ErrTableBooked)
+
+	require.NoError(t, factory.Booking().UpdateStatus(ctx, domain.BookingID(first.ID), domain.BookingStatusCancelled))
+	third, err := book(1)
+	require.NoError(t, err)
+	assert.Equal(t, small.ID, third.TableID, "a cancelled booking frees its table")
+
+	drifts, err := factory.Availability().ReconcileReservedSeats(ctx, slot.Date, slot.Date)
+	require.NoError(t, err)
+	assert.Empty(t, drifts, "bookings seated at tables are not counted against the slot")
+
+	stored, err := factory.Booking().GetByID(ctx, domain.BookingID(third.ID))
+	require.NoError(t, err)
+	assert.Equal(t, small.ID, stored.TableID)
+}
+
+func TestTableUseCase_OptimizeSeatingInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 20)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	tables := usecase.NewTableUseCase(factory.Table(), factory.Restaurant(), factory.Booking())
+	large := &domain.Table{RestaurantID: restaurant.ID, Name: "6", Seats: 6}
+	require.NoError(t, tables.CreateTable(ctx, large))
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	pair := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2}
+	_, err := bookings.CreateBooking(ctx, pair)
+	require.NoError(t, err)
+	require.Equal(t, large.ID, pair.TableID)
+
+	small := &domain.Table{RestaurantID: restaurant.ID, Name: "2", Seats: 2}
+	require.NoError(t, tables.CreateTable(ctx, small))
+	asked := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 1, TableID: small.ID}
+	_, err = bookings.CreateBooking(ctx, asked)
+	require.NoError(t, err)
+
+	_, err = tables.OptimizeSeating(ctx, slot.Date.Add(10*time.Hour))
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied, "only admins and jobs reseat every restaurant")
+
+	moved, err := tables.OptimizeSeating(tenant.SystemContext(ctx), slot.Date.Add(10*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, moved, "the two-top is the table the other guest asked for")
+
+	require.NoError(t, factory.Booking().UpdateStatus(ctx, domain.BookingID(asked.ID), domain.BookingStatusCancelled))
+	moved, err = tables.OptimizeSeating(tenant.SystemContext(ctx), slot.Date.Add(10*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+
+	stored, err := factory.Booking().GetByID(ctx, domain.BookingID(pair.ID))
+	require.NoError(t, err)
+	assert.Equal(t, small.ID, stored.TableID, "the pair leaves the six-top for the two-top")
+
+	moved, err = tables.OptimizeSeating(tenant.SystemContext(ctx), slot.Date.Add(20*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, moved, "a booking that started keeps its table")
+}
+
+func TestAnalyticsRepository_CampaignPerformanceInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 20)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	book := func(attribution *domain.BookingAttribution) string {
+		id, err := bookings.CreateBooking(ctx, &domain.Booking{
+			RestaurantID: restaurant.ID,
+			UserID:       userID,
+			Date:         slot.Date,
+			Time:         slot.TimeSlot,
+			GuestsCount:  2,
+			Attribution:  attribution,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	completed := book(&domain.BookingAttribution{Source: "Instagram", Medium: "social", Campaign: "summer"})
+	cancelled := book(&domain.BookingAttribution{Source: "instagram", Medium: "social", Campaign: "summer"})
+	book(&domain.BookingAttribution{ReferralCode: "friend10"})
+	direct := book(&domain.BookingAttribution{Source: " "})
+	require.NoError(t, factory.Booking().UpdateStatus(ctx, domain.BookingID(completed), domain.BookingStatusCompleted))
+	require.NoError(t, factory.Booking().UpdateStatus(ctx, domain.BookingID(cancelled), domain.BookingStatusCancelled))
+
+	booking, err := factory.Booking().GetByID(ctx, domain.BookingID(completed))
+	require.NoError(t, err)
+	require.NotNil(t, booking.Attribution)
+	assert.Equal(t, "instagram", booking.Attribution.Source)
+	booking, err = factory.Booking().GetByID(ctx, domain.BookingID(direct))
+	require.NoError(t, err)
+	assert.Nil(t, booking.Attribution)
+
+	now := testNow
+	campaigns, err := factory.Analytics().ListCampaignPerformance(ctx, restaurant.ID, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CampaignPerformance{
+		{Source: "instagram", Medium: "social", Campaign: "summer", Bookings: 2, Completed: 1, Cancelled: 1, Guests: 2},
+		{ReferralCode: "FRIEND10", Bookings: 1},
+	}, campaigns)
+
+	campaigns, err = factory.Analytics().ListCampaignPerformance(ctx, "other", now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, campaigns)
+}
+
+func TestRestaurantClosureUseCase_AutoReplyInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 20)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	now := testNow
+	pending := &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2,
+		Status: domain.BookingStatusPending, CreatedAt: now}
+	require.NoError(t, factory.Booking().Create(ctx, pending))
+
+	latency, err := factory.Analytics().GetResponseLatency(ctx, restaurant.ID, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, latency.Unanswered)
+
+	closures := usecase.NewRestaurantClosureUseCase(factory.RestaurantClosure(), factory.Restaurant(), factory.RestaurantLocation(), factory.Transactor(), newTestClock())
+	vacation := &domain.RestaurantClosure{RestaurantID: restaurant.ID, StartsOn: now.UTC(), ReopensOn: slot.Date.AddDate(0, 0, 1)}
+	preview := &domain.RestaurantClosure{RestaurantID: restaurant.ID, StartsOn: slot.Date, ReopensOn: slot.Date.AddDate(0, 0, 3)}
+	reply, err := closures.CreateClosure(ctx, preview, true)
+	require.NoError(t, err)
+	assert.Contains(t, reply, "Memory Bistro is closed")
+	previewed, err := closures.ListClosures(ctx, restaurant.ID)
+	require.NoError(t, err)
+	assert.Empty(t, previewed, "a dry run leaves no closure behind")
+
+	_, err = closures.CreateClosure(ctx, vacation, false)
+	require.NoError(t, err)
+	_, err = closures.CreateClosure(ctx, &domain.RestaurantClosure{RestaurantID: restaurant.ID, StartsOn: slot.Date, ReopensOn: slot.Date.AddDate(0, 0, 3)}, false)
+	assert.EqualError(t, err, common.ErrRestaurantClosureOverlaps)
+
+	latency, err = factory.Analytics().GetResponseLatency(ctx, restaurant.ID, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, latency.Requests, "requests made during a closure don't count towards the response times")
+
+	bookings := usecase.NewClosedRestaurantBookingUseCase(usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock()), closures)
+	_, err = bookings.CreateBooking(ctx, &domain.Booking{RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2})
+	var closed *usecase.RestaurantClosedError
+	require.ErrorAs(t, err, &closed)
+	assert.Contains(t, closed.Reply, "Memory Bistro is closed")
+
+	listed, err := closures.ListClosures(ctx, restaurant.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.NoError(t, closures.DeleteClosure(ctx, restaurant.ID, listed[0].ID))
+	assert.EqualError(t, closures.DeleteClosure(ctx, restaurant.ID, listed[0].ID), common.ErrRestaurantClosureNotFound)
+}
+
+func TestAnalyticsUseCase_GetOccupancyNowInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 10)
+	userID := seedUser(t, ctx, factory, "guest@example.com")
+
+	book := func(clock string, duration, guests int, status domain.BookingStatus, isTest bool) {
+		require.NoError(t, factory.Booking().Create(ctx, &domain.Booking{
+			RestaurantID: restaurant.ID,
+			UserID:       userID,
+			Date:         slot.Date,
+			Time:         clock,
+			Duration:     duration,
+			GuestsCount:  guests,
+			Status:       status,
+			IsTest:       isTest,
+		}))
+	}
+	book("19:00", 0, 4, domain.BookingStatusConfirmed, false)
+	book("19:30", 150, 3, domain.BookingStatusConfirmed, false)
+	book("18:00", 60, 2, domain.BookingStatusConfirmed, false)
+	book("19:00", 0, 5, domain.BookingStatusPending, false)
+	book("19:00", 0, 6, domain.BookingStatusConfirmed, true)
+
+	analytics := usecase.NewAnalyticsUseCase(factory.Analytics(), factory.Availability(), factory.Restaurant(), 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+	now := slot.Date.Add(20 * time.Hour)
+
+	occupancy, err := analytics.GetOccupancyNow(ctx, restaurant.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, occupancy.Bookings, "only confirmed bookings seated now are counted")
+	assert.Equal(t, 7, occupancy.Guests)
+	assert.Equal(t, 10, occupancy.Capacity)
+	assert.Equal(t, domain.BusyLevelModerate, occupancy.Level())
+
+	later, err := analytics.GetOccupancyNow(ctx, restaurant.ID, slot.Date.Add(21*time.Hour+30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, later.Bookings, "the 19:30 party is seated for 150 minutes")
+	assert.Zero(t, later.Capacity, "the 19:00 slot is over")
+	assert.Equal(t, domain.BusyLevelUnknown, later.Level())
+
+	_, err = analytics.GetOccupancyNow(ctx, "missing", now)
+	assert.Error(t, err)
+}
+
+func TestAvailabilityUseCase_DeleteAvailabilityInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+	userID := seedUser(t, ctx, factory, "slot@example.com")
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	availability := usecase.NewAvailabilityUseCase(factory.Availability(), factory.Restaurant(),
+		factory.WorkingHours(), factory.RestaurantClosure(), factory.SpecialDay(), factory.Booking(), factory.Transactor(), newTestClock())
+
+	bookingID, err := bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID,
+		UserID:       userID,
+		Date:         slot.Date,
+		Time:         slot.TimeSlot,
+		GuestsCount:  2,
+	})
+	require.NoError(t, err)
+
+	occupying, err := availability.GetSlotBookings(ctx, restaurant.ID, slot.ID)
+	require.NoError(t, err)
+	require.Len(t, occupying, 1)
+	assert.Equal(t, bookingID, occupying[0].ID)
+
+	_, err = availability.GetSlotBookings(ctx, "another-restaurant", slot.ID)
+	require.Error(t, err)
+	assert.Equal(t, common.ErrAvailabilityNotFound, err.Error())
+
+	err = availability.DeleteAvailability(ctx, restaurant.ID, slot.ID)
+	var inUse *usecase.AvailabilityInUseError
+	require.ErrorAs(t, err, &inUse)
+	require.Len(t, inUse.Bookings, 1)
+	assert.Equal(t, 2, inUse.Availability.Reserved)
+
+	require.NoError(t, bookings.CancelBooking(ctx, domain.BookingID(bookingID)))
+	require.NoError(t, availability.DeleteAvailability(ctx, restaurant.ID, slot.ID), "the seats of a cancelled booking are given back")
+
+	_, err = factory.Availability().GetByID(ctx, slot.ID)
+	require.Error(t, err)
+	assert.Equal(t, common.ErrAvailabilityNotFound, err.Error())
+}
+
+func TestBookingSyncUseCase_SyncBookingsInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+	userID := seedUser(t, ctx, factory, "offline@example.com")
+	otherUserID := seedUser(t, ctx, factory, "other@example.com")
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	syncUseCase := usecase.NewBookingSyncUseCase(factory.BookingSync(), factory.Booking(), bookings,
+		factory.Transactor(), clock.NewFake(time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC)))
+
+	otherBookingID, err := bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID, UserID: otherUserID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 1,
+	})
+	require.NoError(t, err)
+
+	userCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}})
+	offlineBooking := func(guests int) *domain.Booking {
+		return &domain.Booking{RestaurantID: restaurant.ID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: guests}
+	}
+	results, err := syncUseCase.SyncBookings(userCtx, []usecase.BookingSyncItem{
+		{Key: "create-1", Operation: domain.BookingSyncCreate, Booking: offlineBooking(2)},
+		{Key: "create-2", Operation: domain.BookingSyncCreate, Booking: offlineBooking(2)},
+		{Key: "cancel-2", Operation: domain.BookingSyncCancel, BookingKey: "create-2"},
+		{Key: "cancel-other", Operation: domain.BookingSyncCancel, BookingID: otherBookingID},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.Equal(t, domain.BookingSyncApplied, results[0].Status)
+	assert.NotEmpty(t, results[0].BookingID)
+	assert.Equal(t, domain.BookingSyncSlotFilled, results[1].Status, "one seat was left for the second booking")
+	assert.Equal(t, domain.BookingSyncNotFound, results[2].Status, "the second booking was never made")
+	assert.Equal(t, domain.BookingSyncNotFound, results[3].Status, "the booking is not the user's")
+
+	// The response was lost, so the app submits the queue again along with a cancellation.
+	replayed, err := syncUseCase.SyncBookings(userCtx, []usecase.BookingSyncItem{
+		{Key: "create-1", Operation: domain.BookingSyncCreate, Booking: offlineBooking(2)},
+		{Key: "cancel-1", Operation: domain.BookingSyncCancel, BookingKey: "create-1"},
+		{Key: "cancel-1-again", Operation: domain.BookingSyncCancel, BookingID: results[0].BookingID},
+	})
+	require.NoError(t, err)
+	require.Len(t, replayed, 3)
+	assert.True(t, replayed[0].Replayed)
+	assert.Equal(t, results[0].BookingID, replayed[0].BookingID)
+	assert.Equal(t, domain.BookingSyncApplied, replayed[1].Status)
+	assert.Equal(t, domain.BookingSyncAlreadyCancelled, replayed[2].Status)
+
+	userBookings, err := factory.Booking().ListByUser(ctx, domain.UserID(userID), domain.BookingCursor{}, 100)
+	require.NoError(t, err)
+	require.Len(t, userBookings, 1, "the replayed booking is not made twice")
+	assert.Equal(t, domain.BookingStatusCancelled, userBookings[0].Status)
+
+	_, err = syncUseCase.SyncBookings(userCtx, []usecase.BookingSyncItem{
+		{Key: "dup", Operation: domain.BookingSyncCancel, BookingID: otherBookingID},
+		{Key: "dup", Operation: domain.BookingSyncCancel, BookingID: otherBookingID},
+	})
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingSync)
+
+	_, err = syncUseCase.SyncBookings(ctx, []usecase.BookingSyncItem{
+		{Key: "anonymous", Operation: domain.BookingSyncCancel, BookingID: otherBookingID},
+	})
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied, "a request without a user has no bookings of its own")
+}
+
+func TestRestaurantClaimUseCase_ClaimFlowInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 4)
+	ownerID := seedUser(t, ctx, factory, "owner@example.com")
+	rivalID := seedUser(t, ctx, factory, "rival@example.com")
+
+	claims := usecase.NewRestaurantClaimUseCase(factory.RestaurantClaim(), factory.Restaurant(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	asUser := func(userID string) context.Context {
+		return tenant.NewContext(ctx, &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}})
+	}
+	asAdmin := tenant.NewContext(ctx, &tenant.Principal{UserID: "admin", Roles: []tenant.Role{tenant.RoleAdmin}})
+
+	err := claims.SubmitClaim(asUser(ownerID), &domain.RestaurantClaim{RestaurantID: restaurant.ID, Proof: "  "})
+	require.ErrorIs(t, err, usecase.ErrInvalidRestaurantClaim)
+
+	claim := &domain.RestaurantClaim{RestaurantID: restaurant.ID, Proof: "Registration number 7701234567"}
+	require.NoError(t, claims.SubmitClaim(asUser(ownerID), claim))
+	assert.Equal(t, ownerID, claim.UserID)
+	assert.Equal(t, domain.RestaurantClaimPending, claim.Status)
+
+	err = claims.SubmitClaim(asUser(ownerID), &domain.RestaurantClaim{RestaurantID: restaurant.ID, Proof: "Again"})
+	require.Error(t, err)
+	assert.Equal(t, common.ErrRestaurantClaimExists, err.Error())
+
+	rival := &domain.RestaurantClaim{RestaurantID: restaurant.ID, Proof: "I own it too"}
+	require.NoError(t, claims.SubmitClaim(asUser(rivalID), rival))
+
+	_, err = claims.GetClaim(asUser(rivalID), claim.ID)
+	require.ErrorIs(t, err, tenant.ErrAccessDenied)
+	_, err = claims.GetClaim(ctx, claim.ID)
+	require.ErrorIs(t, err, tenant.ErrAccessDenied, "a claim is shown to its user only")
+	_, err = claims.ApproveClaim(asUser(ownerID), claim.ID, "")
+	require.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	pending, err := claims.ListClaims(asAdmin, domain.RestaurantClaimFilter{Status: domain.RestaurantClaimPending}, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, claim.ID, pending[0].ID, "the oldest claim is reviewed first")
+
+	approved, err := claims.ApproveClaim(asAdmin, claim.ID, "Checked the registry")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RestaurantClaimApproved, approved.Status)
+	assert.Equal(t, "admin", approved.ReviewerID)
+
+	superseded, err := claims.GetClaim(asUser(rivalID), rival.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RestaurantClaimRejected, superseded.Status, "the other pending claims are rejected")
+
+	_, err = claims.RejectClaim(asAdmin, claim.ID, "")
+	require.ErrorIs(t, err, usecase.ErrRestaurantClaimDecided)
+
+	ownership, err := claims.GetOwnership(asAdmin, restaurant.ID)
+	require.NoError(t, err)
+	require.NotNil(t, ownership.Owner)
+	assert.Equal(t, ownerID, ownership.Owner.OwnerID)
+	require.Len(t, ownership.Audit, 1)
+	assert.Equal(t, claim.ID, ownership.Audit[0].ClaimID)
+	assert.Equal(t, "admin", ownership.Audit[0].ApprovedBy)
+
+	err = claims.SubmitClaim(asUser(rivalID), &domain.RestaurantClaim{RestaurantID: restaurant.ID, Proof: "Once more"})
+	require.Error(t, err)
+	assert.Equal(t, common.ErrRestaurantAlreadyOwned, err.Error())
+}
+
+type stubTXTResolver map[string][]string
+
+func (r stubTXTResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestCustomDomainUseCase_VerificationInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 4)
+	other, _ := seedRestaurant(t, ctx, factory, 4)
+
+	resolver := stubTXTResolver{}
+	domains := usecase.NewCustomDomainUseCase(factory.CustomDomain(), factory.Restaurant(), resolver, 2, []string{"booking.example"}, newTestClock())
+	staff := tenant.NewContext(ctx, &tenant.Principal{UserID: "staff", RestaurantIDs: []string{restaurant.ID}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+
+	_, err := domains.AddDomain(staff, other.ID, "book.other.example")
+	require.ErrorIs(t, err, tenant.ErrAccessDenied)
+	for _, name := range []string{"localhost", "10.0.0.1", "book_.example", "api.booking.example"} {
+		_, err = domains.AddDomain(staff, restaurant.ID, name)
+		require.ErrorIs(t, err, usecase.ErrInvalidCustomDomain, name)
+	}
+
+	customDomain, err := domains.AddDomain(staff, restaurant.ID, " Book.Bistro.Example. ")
+	require.NoError(t, err)
+	assert.Equal(t, "book.bistro.example", customDomain.Domain)
+	assert.Equal(t, domain.CustomDomainPending, customDomain.Status)
+	assert.Len(t, customDomain.VerificationToken, 32)
+
+	_, err = domains.AddDomain(ctx, other.ID, "book.bistro.example")
+	require.Error(t, err)
+	assert.Equal(t, common.ErrCustomDomainTaken, err.Error())
+
+	_, err = domains.ResolveHost(ctx, "book.bistro.example")
+	require.Error(t, err, "pending domains are not served")
+	assert.Equal(t, common.ErrCustomDomainNotFound, err.Error())
+
+	_, err = domains.VerifyDomain(staff, restaurant.ID, customDomain.ID)
+	require.ErrorIs(t, err, usecase.ErrCustomDomainUnverified)
+	resolver["_booking-verification.book.bistro.example"] = []string{"stale-token"}
+	_, err = domains.VerifyDomain(staff, restaurant.ID, customDomain.ID)
+	require.ErrorIs(t, err, usecase.ErrCustomDomainUnverified)
+
+	resolver["_booking-verification.book.bistro.example"] = []string{"stale-token", customDomain.VerificationToken}
+	verified, err := domains.VerifyDomain(staff, restaurant.ID, customDomain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CustomDomainVerified, verified.Status)
+	require.NotNil(t, verified.VerifiedAt)
+
+	resolved, err := domains.ResolveHost(ctx, "BOOK.bistro.example")
+	require.NoError(t, err)
+	assert.Equal(t, restaurant.ID, resolved.RestaurantID)
+
+	_, err = domains.AddDomain(staff, restaurant.ID, "www.bistro.example")
+	require.NoError(t, err)
+	_, err = domains.AddDomain(staff, restaurant.ID, "bistro.example")
+	require.ErrorIs(t, err, usecase.ErrCustomDomainLimit)
+
+	err = domains.RemoveDomain(ctx, other.ID, customDomain.ID)
+	require.Error(t, err, "a domain of another restaurant is not found")
+	assert.Equal(t, common.ErrCustomDomainNotFound, err.Error())
+	require.NoError(t, domains.RemoveDomain(staff, restaurant.ID, customDomain.ID))
+	listed, err := domains.ListDomains(staff, restaurant.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "www.bistro.example", listed[0].Domain)
+}
+
+func TestWidgetSettingsUseCase_InMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 4)
+	other, _ := seedRestaurant(t, ctx, factory, 4)
+
+	widgets := usecase.NewWidgetSettingsUseCase(factory.WidgetSettings(), factory.Restaurant())
+	staff := tenant.NewContext(ctx, &tenant.Principal{UserID: "staff", RestaurantIDs: []string{restaurant.ID}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+
+	settings, err := widgets.GetWidgetSettings(ctx, restaurant.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DefaultWidgetSettings(restaurant.ID), settings)
+	_, err = widgets.GetWidgetSettings(ctx, "missing")
+	require.Error(t, err)
+	assert.Equal(t, common.ErrRestaurantNotFound, err.Error())
+
+	require.ErrorIs(t, widgets.UpdateWidgetSettings(staff, domain.DefaultWidgetSettings(other.ID)), tenant.ErrAccessDenied)
+
+	for _, invalid := range []func(*domain.WidgetSettings){
+		func(s *domain.WidgetSettings) { s.Theme.PrimaryColor = "red" },
+		func(s *domain.WidgetSettings) { s.Theme.TextColor = "#12345" },
+		func(s *domain.WidgetSettings) { s.DefaultPartySize = domain.MaxWidgetPartySize + 1 },
+		func(s *domain.WidgetSettings) { s.Locale = "not a locale" },
+	} {
+		settings := domain.DefaultWidgetSettings(restaurant.ID)
+		invalid(settings)
+		require.ErrorIs(t, widgets.UpdateWidgetSettings(staff, settings), domain.ErrInvalidWidgetSettings)
+	}
+
+	settings = domain.DefaultWidgetSettings(restaurant.ID)
+	settings.Theme.PrimaryColor = "#AB0"
+	settings.DefaultPartySize = 4
+	settings.Locale = "ru-ru"
+	require.NoError(t, widgets.UpdateWidgetSettings(staff, settings))
+
+	saved, err := widgets.GetWidgetSettings(ctx, restaurant.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "#ab0", saved.Theme.PrimaryColor)
+	assert.Equal(t, 4, saved.DefaultPartySize)
+	assert.Equal(t, "ru-RU", saved.Locale)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	require.NoError(t, factory.Restaurant().Delete(ctx, domain.RestaurantID(restaurant.ID)))
+	_, err = factory.WidgetSettings().Get(ctx, restaurant.ID)
+	require.Error(t, err, "the settings go with the restaurant")
+	assert.Equal(t, common.ErrWidgetSettingsNotFound, err.Error())
+}
+
+func TestBookingDraftUseCase_FlowInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 4)
+	userID := seedUser(t, ctx, factory, "draft@example.com")
+	otherUserID := seedUser(t, ctx, factory, "walk-in@example.com")
+
+	menu := []domain.MenuItem{{Name: "Oysters", Price: 120000, Currency: domain.DefaultCurrency, IsAvailable: true, DailyLimit: 2}}
+	require.NoError(t, factory.Menu().SaveMenu(ctx, restaurant.ID, menu))
+
+	fakeClock := newTestClock()
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	drafts := usecase.NewBookingDraftUseCase(factory.BookingDraft(), factory.Availability(), factory.Restaurant(),
+		factory.Menu(), bookings, factory.Transactor(), 15*time.Minute, 3*time.Hour, fakeClock)
+
+	reserved := func() int {
+		t.Helper()
+		stored, err := factory.Availability().GetByID(ctx, slot.ID)
+		require.NoError(t, err)
+		return stored.Reserved
+	}
+
+	asGuest := func(userID string) context.Context {
+		return tenant.NewContext(ctx, &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}})
+	}
+
+	draft := &domain.BookingDraft{RestaurantID: restaurant.ID, UserID: userID, GuestsCount: 3}
+	assert.ErrorIs(t, drafts.CreateDraft(ctx, draft), tenant.ErrAccessDenied, "a draft belongs to a signed-in user")
+	require.NoError(t, drafts.CreateDraft(asGuest(userID), draft))
+	assert.Equal(t, domain.BookingDraftOpen, draft.Status)
+
+	_, err := drafts.SetPreOrder(ctx, draft.ID, nil)
+	assert.ErrorIs(t, err, usecase.ErrBookingDraftStep, "the pre-order needs a slot")
+
+	held, err := drafts.HoldSlot(ctx, draft.ID, slot.Date, slot.TimeSlot)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BookingDraftHeld, held.Status)
+	assert.Equal(t, 3, reserved())
+
+	// The held seats are not free for anyone else, nor counted away by the reconciliation.
+	_, err = bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID, UserID: otherUserID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 2,
+	})
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability)
+	drifts, err := factory.Availability().ReconcileReservedSeats(ctx, slot.Date, slot.Date)
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	// Holding the slot again swaps the hold instead of adding to it.
+	_, err = drafts.HoldSlot(ctx, draft.ID, slot.Date, slot.TimeSlot)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reserved())
+
+	_, err = drafts.SetPreOrder(ctx, draft.ID, []domain.PreOrderItem{{MenuItemID: "unknown", Quantity: 1}})
+	assert.ErrorIs(t, err, usecase.ErrInvalidPreOrder)
+	withPreOrder, err := drafts.SetPreOrder(ctx, draft.ID, []domain.PreOrderItem{{MenuItemID: menu[0].ID, Quantity: 2}})
+	require.NoError(t, err)
+	require.Len(t, withPreOrder.PreOrder, 1)
+	assert.Equal(t, "Oysters", withPreOrder.PreOrder[0].Name)
+
+	booking, err := drafts.ConfirmDraft(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BookingStatusPending, booking.Status)
+	assert.Equal(t, 3, reserved(), "the booking takes over the held seats")
+	preOrder, err := factory.Menu().GetPreOrder(ctx, booking.ID)
+	require.NoError(t, err)
+	assert.Len(t, preOrder, 1)
+
+	confirmed, err := drafts.GetDraft(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BookingDraftConfirmed, confirmed.Status)
+	assert.Equal(t, booking.ID, confirmed.BookingID)
+	_, err = drafts.ConfirmDraft(ctx, draft.ID)
+	assert.ErrorIs(t, err, usecase.ErrBookingDraftStep)
+
+	// A draft left alone expires and gives its seats back.
+	abandoned := &domain.BookingDraft{RestaurantID: restaurant.ID, UserID: otherUserID, GuestsCount: 1}
+	require.NoError(t, drafts.CreateDraft(asGuest(otherUserID), abandoned))
+	_, err = drafts.HoldSlot(ctx, abandoned.ID, slot.Date, slot.TimeSlot)
+	require.NoError(t, err)
+	assert.Equal(t, 4, reserved())
+
+	fakeClock.Advance(16 * time.Minute)
+	_, err = drafts.GetDraft(ctx, abandoned.ID)
+	assert.ErrorIs(t, err, usecase.ErrBookingDraftExpired)
+	_, err = drafts.ConfirmDraft(ctx, abandoned.ID)
+	assert.ErrorIs(t, err, usecase.ErrBookingDraftExpired)
+
+	expired, err := drafts.ExpireDrafts(ctx, fakeClock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, expired, "the confirmed draft is cleaned up as well")
+	assert.Equal(t, 3, reserved())
+	_, err = drafts.GetDraft(ctx, abandoned.ID)
+	assert.EqualError(t, err, common.ErrBookingDraftNotFound)
+}
+
+func TestBookingDraftUseCase_ConfirmKeepsHoldOnFailureInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, slot := seedRestaurant(t, ctx, factory, 6)
+	userID := seedUser(t, ctx, factory, "sold-out@example.com")
+
+	menu := []domain.MenuItem{{Name: "Truffle risotto", Price: 250000, Currency: domain.DefaultCurrency, IsAvailable: true, DailyLimit: 1}}
+	require.NoError(t, factory.Menu().SaveMenu(ctx, restaurant.ID, menu))
+
+	bookings := usecase.NewBookingUseCase(factory.Booking(), factory.Availability(), factory.Table(),
+		postgres.NewNotificationService(factory.Notification()), factory.Transactor(), newTestClock())
+	drafts := usecase.NewBookingDraftUseCase(factory.BookingDraft(), factory.Availability(), factory.Restaurant(),
+		factory.Menu(), bookings, factory.Transactor(), 15*time.Minute, 3*time.Hour, newTestClock())
+
+	draft := &domain.BookingDraft{RestaurantID: restaurant.ID, UserID: userID, GuestsCount: 2}
+	require.NoError(t, drafts.CreateDraft(tenant.NewContext(ctx, &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}}), draft))
+	_, err := drafts.HoldSlot(ctx, draft.ID, slot.Date, slot.TimeSlot)
+	require.NoError(t, err)
+	_, err = drafts.SetPreOrder(ctx, draft.ID, []domain.PreOrderItem{{MenuItemID: menu[0].ID, Quantity: 1}})
+	require.NoError(t, err)
+
+	// Another booking takes the last risotto of the day before the draft is confirmed.
+	bookingID, err := bookings.CreateBooking(ctx, &domain.Booking{
+		RestaurantID: restaurant.ID, UserID: userID, Date: slot.Date, Time: slot.TimeSlot, GuestsCount: 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, factory.Menu().SavePreOrder(ctx, bookingID, []domain.PreOrderItem{
+		{MenuItemID: menu[0].ID, Name: "Truffle risotto", UnitPrice: 250000, Quantity: 1},
+	}))
+
+	_, err = drafts.ConfirmDraft(ctx, draft.ID)
+	var limitErr *domain.PreOrderLimitError
+	require.ErrorAs(t, err, &limitErr)
+
+	stillHeld, err := drafts.GetDraft(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BookingDraftHeld, stillHeld.Status)
+	stored, err := factory.Availability().GetByID(ctx, slot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stored.Reserved, "the draft keeps its seats and no booking is left behind")
+	userBookings, err := factory.Booking().ListByUser(ctx, domain.UserID(userID), domain.BookingCursor{}, 100)
+	require.NoError(t, err)
+	assert.Len(t, userBookings, 1)
+}
+
+func TestAuthUseCase_SignInFlowInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	fake := clock.NewFake(time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC))
+	auth := usecase.NewAuthUseCase(factory.Account(), factory.Restaurant(), factory.RestaurantClaim(),
+		usecase.NewUserUseCase(factory.User(), "RU", newTestClock()), new(recordingEmailSender), factory.Transactor(), fake,
+		usecase.AuthSettings{Secret: "secret", Issuer: "restaurant-booking"})
+
+	_, err := auth.RegisterUser(ctx, &domain.User{Name: "Guest", Email: "guest@example.com", Phone: "+7 912 345-67-89"}, "short")
+	require.ErrorIs(t, err, usecase.ErrWeakPassword)
+
+	user := &domain.User{Name: "Guest", Email: "Guest@Example.com", Phone: "+7 912 345-67-89"}
+	registered, err := auth.RegisterUser(ctx, user, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", registered.TokenType)
+	assert.Equal(t, user.ID, registered.Account.UserID)
+
+	principal, err := auth.Authenticate(ctx, registered.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, principal.UserID)
+	assert.Equal(t, registered.Account.ID, principal.AccountID)
+	assert.Equal(t, []tenant.Role{tenant.RoleUser}, principal.Roles)
+
+	_, err = auth.RegisterUser(ctx, &domain.User{Name: "Again", Email: "guest@example.com", Phone: "+7 912 345-67-80"}, "correct horse")
+	require.ErrorIs(t, err, usecase.ErrEmailExists)
+
+	_, err = auth.Login(ctx, "guest@example.com", "wrong horse")
+	require.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+	_, err = auth.Login(ctx, "nobody@example.com", "correct horse")
+	require.ErrorIs(t, err, usecase.ErrInvalidCredentials)
+
+	session, err := auth.Login(ctx, "GUEST@example.com", "correct horse")
+	require.NoError(t, err)
+
+	fake.Advance(20 * time.Minute)
+	_, err = auth.Authenticate(ctx, session.AccessToken)
+	require.ErrorIs(t, err, usecase.ErrInvalidAccessToken, "the access token has expired")
+
+	rotated, err := auth.Refresh(ctx, session.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, session.RefreshToken, rotated.RefreshToken)
+
+	_, err = auth.Refresh(ctx, session.RefreshToken)
+	require.ErrorIs(t, err, usecase.ErrInvalidRefreshToken, "a refresh token works once")
+	_, err = auth.Refresh(ctx, rotated.RefreshToken)
+	require.ErrorIs(t, err, usecase.ErrInvalidRefreshToken, "reusing a token signs the whole session out")
+
+	other, err := auth.Refresh(ctx, registered.RefreshToken)
+	require.NoError(t, err, "the other sessions stay signed in")
+	require.NoError(t, auth.Logout(ctx, other.RefreshToken))
+	_, err = auth.Refresh(ctx, other.RefreshToken)
+	require.ErrorIs(t, err, usecase.ErrInvalidRefreshToken)
+	require.NoError(t, auth.Logout(ctx, "unknown"))
+}
+
+// recordingEmailSender keeps the emails instead of sending them.
+type recordingEmailSender struct {
+	to, bodies []string
+}
+
+func (s *recordingEmailSender) SendEmail(to, _, body string) error {
+	s.to = append(s.to, to)
+	s.bodies = append(s.bodies, body)
+	return nil
+}
+
+// verificationToken returns the token of the last verification link sent.
+func (s *recordingEmailSender) verificationToken(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, s.bodies)
+	body := s.bodies[len(s.bodies)-1]
+	start := strings.Index(body, "http")
+	require.GreaterOrEqual(t, start, 0)
+	link, err := url.Parse(strings.Fields(body[start:])[0])
+	require.NoError(t, err)
+	assert.Equal(t, usecase.EmailVerificationPath, link.Path)
+	return link.Query().Get("token")
+}
+
+func TestAuthUseCase_AdminEmailNeedsVerificationInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	emails := new(recordingEmailSender)
+	auth := usecase.NewAuthUseCase(factory.Account(), factory.Restaurant(), factory.RestaurantClaim(),
+		usecase.NewUserUseCase(factory.User(), "RU", newTestClock()), emails, factory.Transactor(),
+		clock.NewFake(time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)),
+		usecase.AuthSettings{Secret: "secret", AdminEmails: []string{"Admin@Example.com"}, BaseURL: "https://book.example.com/"})
+
+	registered, err := auth.RegisterUser(ctx, &domain.User{Name: "Admin", Email: "admin@example.com", Phone: "+7 912 345-67-89"}, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin@example.com"}, emails.to)
+	assert.Contains(t, emails.bodies[0], "https://book.example.com"+usecase.EmailVerificationPath+"?token=")
+
+	principal, err := auth.Authenticate(ctx, registered.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []tenant.Role{tenant.RoleUser}, principal.Roles, "an unverified admin email is not an admin")
+
+	_, err = auth.VerifyEmail(ctx, "forged")
+	require.ErrorIs(t, err, usecase.ErrInvalidVerificationToken)
+
+	// A new link replaces the first one.
+	first := emails.verificationToken(t)
+	require.NoError(t, auth.ResendVerification(ctx, "ADMIN@example.com"))
+	require.Len(t, emails.bodies, 2)
+	_, err = auth.VerifyEmail(ctx, first)
+	require.ErrorIs(t, err, usecase.ErrInvalidVerificationToken)
+
+	token := emails.verificationToken(t)
+	account, err := auth.VerifyEmail(ctx, token)
+	require.NoError(t, err)
+	require.NotNil(t, account.EmailVerifiedAt)
+	_, err = auth.VerifyEmail(ctx, token)
+	require.ErrorIs(t, err, usecase.ErrInvalidVerificationToken, "a verification token works once")
+
+	refreshed, err := auth.Refresh(ctx, registered.RefreshToken)
+	require.NoError(t, err)
+	principal, err = auth.Authenticate(ctx, refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []tenant.Role{tenant.RoleUser, tenant.RoleAdmin}, principal.Roles)
+
+	require.NoError(t, auth.ResendVerification(ctx, "admin@example.com"))
+	require.NoError(t, auth.ResendVerification(ctx, "nobody@example.com"))
+	assert.Len(t, emails.bodies, 2, "verified and unknown emails get no link")
+}
+
+func TestAuthUseCase_RegisterRestaurantAccountInMemory(t *testing.T) {
+	ctx := setupTestContext()
+	factory := memory.NewRepositoryFactory(memory.NewStore(idgen.NewSequence(), newTestClock()))
+	restaurant, _ := seedRestaurant(t, ctx, factory, 4)
+	ownerID := seedUser(t, ctx, factory, "owner@example.com")
+	require.NoError(t, factory.RestaurantClaim().SetOwner(ctx, &domain.RestaurantOwnership{RestaurantID: restaurant.ID, OwnerID: ownerID}))
+
+	auth := usecase.NewAuthUseCase(factory.Account(), factory.Restaurant(), factory.RestaurantClaim(),
+		usecase.NewUserUseCase(factory.User(), "RU", newTestClock()), new(recordingEmailSender), factory.Transactor(), newTestClock(),
+		usecase.AuthSettings{Secret: "secret"})
+	asUser := func(userID string) context.Context {
+		return tenant.NewContext(ctx, &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}})
+	}
+
+	_, err := auth.RegisterRestaurantAccount(ctx, restaurant.ID, "host@example.com", "correct horse")
+	require.ErrorIs(t, err, tenant.ErrAccessDenied, "anonymous callers can't create logins")
+	_, err = auth.RegisterRestaurantAccount(asUser("stranger"), restaurant.ID, "host@example.com", "correct horse")
+	require.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	account, err := auth.RegisterRestaurantAccount(asUser(ownerID), restaurant.ID, "host@example.com", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, domain.AccountRestaurant, account.Kind)
+
+	_, err = auth.RegisterRestaurantAccount(asUser(ownerID), restaurant.ID, "HOST@example.com", "correct horse")
+	require.ErrorIs(t, err, usecase.ErrAccountExists)
+
+	session, err := auth.Login(ctx, "host@example.com", "correct horse")
+	require.NoError(t, err)
+	principal, err := auth.Authenticate(ctx, session.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, principal.CanAccessRestaurant(restaurant.ID))
+	assert.Equal(t, []tenant.Role{tenant.RoleRestaurantStaff}, principal.Roles)
+
+	_, err = usecase.NewAuthUseCase(factory.Account(), factory.Restaurant(), factory.RestaurantClaim(),
+		usecase.NewUserUseCase(factory.User(), "RU", newTestClock()), new(recordingEmailSender), factory.Transactor(), newTestClock(),
+		usecase.AuthSettings{}).Login(ctx, "host@example.com", "correct horse")
+	require.ErrorIs(t, err, usecase.ErrAuthDisabled)
+}
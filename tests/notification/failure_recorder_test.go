@@ -0,0 +1,78 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingNotificationService struct {
+	domain.NotificationService
+
+	err error
+}
+
+func (s *failingNotificationService) NotifyRestaurant(_ context.Context, _ string, _ domain.NotificationType, _, _ string, _ string) error {
+	return s.err
+}
+
+func (s *failingNotificationService) NotifyUser(_ context.Context, _ string, _ domain.NotificationType, _, _ string, _ string) error {
+	return s.err
+}
+
+type recordedFailure struct {
+	failure *domain.NotificationFailure
+	cause   error
+	ctxErr  error
+}
+
+type stubFailureStore struct {
+	recorded []recordedFailure
+}
+
+func (s *stubFailureStore) RecordFailure(ctx context.Context, failure *domain.NotificationFailure, cause error) error {
+	s.recorded = append(s.recorded, recordedFailure{failure, cause, ctx.Err()})
+	return nil
+}
+
+func TestFailureRecorder_RecordsFailedNotifications(t *testing.T) {
+	ctx, cancel := context.WithCancel(newDebouncerTestContext(t))
+	deliveryErr := errors.New("database error")
+	store := &stubFailureStore{}
+	recorder := notification.NewFailureRecorder(&failingNotificationService{err: deliveryErr}, store)
+
+	cancel()
+	err := recorder.NotifyUser(ctx, "user1", domain.NotificationTypeBookingConfirmed, "Booking confirmed", "See you", "b1")
+	assert.ErrorIs(t, err, deliveryErr, "the caller still gets the error")
+
+	require.Len(t, store.recorded, 1)
+	recorded := store.recorded[0]
+	assert.Equal(t, &domain.NotificationFailure{
+		RecipientType:    domain.RecipientTypeUser,
+		RecipientID:      "user1",
+		NotificationType: domain.NotificationTypeBookingConfirmed,
+		Title:            "Booking confirmed",
+		Message:          "See you",
+		RelatedID:        "b1",
+	}, recorded.failure)
+	assert.ErrorIs(t, recorded.cause, deliveryErr)
+	assert.NoError(t, recorded.ctxErr, "a cancelled request does not stop the failure from being kept")
+}
+
+func TestFailureRecorder_PassesDeliveredNotifications(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	store := &stubFailureStore{}
+	recorder := notification.NewFailureRecorder(next, store)
+
+	require.NoError(t, recorder.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "delivered", "b1"))
+
+	assert.Len(t, next.Sent(), 1)
+	assert.Empty(t, store.recorded)
+}
@@ -0,0 +1,119 @@
+package notification_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sentNotification struct {
+	restaurantID     string
+	notificationType domain.NotificationType
+	title            string
+	message          string
+	relatedID        string
+}
+
+type recordingNotificationService struct {
+	domain.NotificationService
+
+	mu   sync.Mutex
+	sent []sentNotification
+}
+
+func (s *recordingNotificationService) NotifyRestaurant(_ context.Context, restaurantID string, notificationType domain.NotificationType, title, message string, relatedID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, sentNotification{restaurantID, notificationType, title, message, relatedID})
+	return nil
+}
+
+func (s *recordingNotificationService) Sent() []sentNotification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sentNotification(nil), s.sent...)
+}
+
+func newDebouncerTestContext(t *testing.T) context.Context {
+	log, err := logger.NewLogger()
+	require.NoError(t, err)
+
+	return logger.NewContext(context.Background(), log)
+}
+
+func TestDebouncedNotificationService_CoalescesWithinWindow(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	service := notification.NewDebouncedNotificationService(next, 50*time.Millisecond)
+
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "first", "b1"))
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeBookingCancelled, "Booking cancelled", "second", "b2"))
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "third", "b3"))
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest2", domain.NotificationTypeNewBooking, "New booking", "other", "b4"))
+
+	sent := next.Sent()
+	require.Len(t, sent, 2, "first notification of each restaurant is sent right away")
+	assert.Equal(t, "first", sent[0].message)
+	assert.Equal(t, "rest2", sent[1].restaurantID)
+
+	require.Eventually(t, func() bool { return len(next.Sent()) == 3 }, time.Second, 5*time.Millisecond)
+
+	summary := next.Sent()[2]
+	assert.Equal(t, "rest1", summary.restaurantID)
+	assert.Equal(t, domain.NotificationTypeSummary, summary.notificationType)
+	assert.Equal(t, "2 booking updates", summary.title)
+	assert.Contains(t, summary.message, "Booking cancelled: second")
+	assert.Contains(t, summary.message, "New booking: third")
+	assert.Equal(t, "b3", summary.relatedID)
+
+	time.Sleep(120 * time.Millisecond)
+	assert.Len(t, next.Sent(), 3)
+}
+
+func TestDebouncedNotificationService_SingleHeldBackNotificationIsSentAsIs(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	service := notification.NewDebouncedNotificationService(next, 20*time.Millisecond)
+
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "first", "b1"))
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeBookingCancelled, "Booking cancelled", "second", "b2"))
+
+	require.Eventually(t, func() bool { return len(next.Sent()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, domain.NotificationTypeBookingCancelled, next.Sent()[1].notificationType)
+	assert.Equal(t, "second", next.Sent()[1].message)
+}
+
+func TestDebouncedNotificationService_Flush(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	service := notification.NewDebouncedNotificationService(next, time.Hour)
+
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "first", "b1"))
+	require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "second", "b2"))
+	require.Len(t, next.Sent(), 1)
+
+	service.Flush()
+
+	require.Len(t, next.Sent(), 2)
+	assert.Equal(t, "second", next.Sent()[1].message)
+}
+
+func TestDebouncedNotificationService_ZeroWindowPassesThrough(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	service := notification.NewDebouncedNotificationService(next, 0)
+
+	for range 3 {
+		require.NoError(t, service.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "msg", "b1"))
+	}
+
+	assert.Len(t, next.Sent(), 3)
+}
@@ -0,0 +1,111 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSettingsRepository struct {
+	settings map[string]*domain.NotificationSettings
+	err      error
+}
+
+func (r *stubSettingsRepository) Get(_ context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if s, ok := r.settings[string(recipientType)+"/"+recipientID]; ok {
+		return s, nil
+	}
+	return &domain.NotificationSettings{RecipientType: recipientType, RecipientID: recipientID}, nil
+}
+
+func (r *stubSettingsRepository) Save(_ context.Context, _ *domain.NotificationSettings) error {
+	return nil
+}
+
+func TestRouter_DropsTurnedOffInAppNotifications(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	settings := &stubSettingsRepository{settings: map[string]*domain.NotificationSettings{
+		"restaurant/rest1": {Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeBookingCancelled, Channel: domain.NotificationChannelInApp, Enabled: false},
+		}},
+	}}
+	router := notification.NewRouter(next, settings, nil)
+
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeBookingCancelled, "Booking cancelled", "dropped", "b1"))
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "delivered", "b2"))
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest2", domain.NotificationTypeBookingCancelled, "Booking cancelled", "other restaurant", "b3"))
+
+	sent := next.Sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "delivered", sent[0].message)
+	assert.Equal(t, "other restaurant", sent[1].message)
+}
+
+func TestRouter_DeliversWhenSettingsUnavailable(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	router := notification.NewRouter(next, &stubSettingsRepository{err: errors.New("database error")}, nil)
+
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeNewBooking, "New booking", "delivered", "b1"))
+
+	assert.Len(t, next.Sent(), 1)
+}
+
+type recordingDeferrer struct {
+	now      time.Time
+	err      error
+	deferred []*domain.DeferredNotification
+}
+
+func (d *recordingDeferrer) Defer(_ context.Context, settings *domain.NotificationSettings, notification *domain.DeferredNotification) (bool, error) {
+	if d.err != nil {
+		return false, d.err
+	}
+	until, ok := settings.DeferUntil(notification.NotificationType, notification.Channel, d.now)
+	if !ok {
+		return false, nil
+	}
+	notification.DeliverAt = until
+	d.deferred = append(d.deferred, notification)
+	return true, nil
+}
+
+func TestRouter_DefersNotificationsThatCanWaitDuringQuietHours(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &recordingNotificationService{}
+	now := time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC)
+	settings := &stubSettingsRepository{settings: map[string]*domain.NotificationSettings{
+		"restaurant/rest1": {RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest1", QuietHours: []domain.QuietHours{
+			{Channel: domain.NotificationChannelInApp, Start: "22:00", End: "08:00"},
+		}},
+	}}
+	deferrals := &recordingDeferrer{now: now}
+	router := notification.NewRouter(next, settings, deferrals)
+
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeDailyDigest, "Your day ahead", "deferred", "rest1"))
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeBookingCancelled, "Booking cancelled", "urgent", "b1"))
+
+	sent := next.Sent()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "urgent", sent[0].message)
+
+	require.Len(t, deferrals.deferred, 1)
+	assert.Equal(t, "deferred", deferrals.deferred[0].Message)
+	assert.Equal(t, domain.NotificationChannelInApp, deferrals.deferred[0].Channel)
+	assert.Equal(t, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), deferrals.deferred[0].DeliverAt)
+
+	deferrals.err = errors.New("database error")
+	require.NoError(t, router.NotifyRestaurant(ctx, "rest1", domain.NotificationTypeDailyDigest, "Your day ahead", "delivered anyway", "rest1"))
+	assert.Len(t, next.Sent(), 2, "a notification that cannot be deferred is delivered")
+}
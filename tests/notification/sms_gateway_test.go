@@ -0,0 +1,39 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSGateway_SendSMS(t *testing.T) {
+	var received map[string]string
+	var authorization string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		received = nil
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	gateway := notification.NewSMSGateway(server.Client(), server.URL, "secret", "Bistro")
+
+	require.NoError(t, gateway.SendSMS("+79001234567", "Your table is booked"))
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, map[string]string{"to": "+79001234567", "from": "Bistro", "text": "Your table is booked"}, received)
+
+	status = http.StatusBadGateway
+	err := gateway.SendSMS("+79001234567", "Your table is booked")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), common.ErrSendSMS)
+	assert.Contains(t, err.Error(), "502")
+}
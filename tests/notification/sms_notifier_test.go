@@ -0,0 +1,105 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type userNotificationService struct {
+	domain.NotificationService
+
+	users []string
+}
+
+func (s *userNotificationService) NotifyUser(_ context.Context, userID string, _ domain.NotificationType, _, _ string, _ string) error {
+	s.users = append(s.users, userID)
+	return nil
+}
+
+type stubUserRepository struct {
+	users map[string]*domain.User
+}
+
+func (r *stubUserRepository) GetByID(_ context.Context, id domain.UserID) (*domain.User, error) {
+	return r.users[id.String()], nil
+}
+
+func (r *stubUserRepository) GetByEmail(_ context.Context, _ string) (*domain.User, error) {
+	return nil, nil
+}
+
+func (r *stubUserRepository) Create(_ context.Context, _ *domain.User) error { return nil }
+
+func (r *stubUserRepository) Update(_ context.Context, _ *domain.User) error { return nil }
+
+type recordingSMSSender struct {
+	to   []string
+	body []string
+}
+
+func (s *recordingSMSSender) SendSMS(to, body string) error {
+	s.to = append(s.to, to)
+	s.body = append(s.body, body)
+	return nil
+}
+
+type recordingDeliveryLog struct {
+	notifications []*domain.Notification
+}
+
+func (l *recordingDeliveryLog) Create(_ context.Context, notification *domain.Notification) error {
+	l.notifications = append(l.notifications, notification)
+	return nil
+}
+
+type stubBookingLinkIssuer struct{}
+
+func (stubBookingLinkIssuer) IssueConfirmationLinks(_ context.Context, bookingID domain.BookingID) (view, cancel *usecase.IssuedBookingLink, err error) {
+	expiresAt := time.Now().Add(time.Hour)
+	view = &usecase.IssuedBookingLink{URL: "https://example.com/b/view-" + bookingID.String(), Action: domain.BookingLinkActionView, ExpiresAt: expiresAt}
+	cancel = &usecase.IssuedBookingLink{URL: "https://example.com/b/cancel-" + bookingID.String(), Action: domain.BookingLinkActionCancel, SingleUse: true, ExpiresAt: expiresAt}
+	return view, cancel, nil
+}
+
+func TestSMSNotifier_TextsConfirmedBookingWhenSMSEnabled(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	next := &userNotificationService{}
+	sms := &recordingSMSSender{}
+	users := &stubUserRepository{users: map[string]*domain.User{
+		"user1": {ID: "user1", Phone: "+79990000001"},
+		"user2": {ID: "user2", Phone: "+79990000002"},
+	}}
+	settings := &stubSettingsRepository{settings: map[string]*domain.NotificationSettings{
+		"user/user1": {Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelSMS, Enabled: true},
+		}},
+	}}
+	deliveries := &recordingDeliveryLog{}
+	notifier := notification.NewSMSNotifier(next, sms, users, settings, stubBookingLinkIssuer{}, deliveries)
+
+	require.NoError(t, notifier.NotifyUser(ctx, "user1", domain.NotificationTypeBookingConfirmed, "Booking confirmed", "See you", "b1"))
+	require.NoError(t, notifier.NotifyUser(ctx, "user1", domain.NotificationTypeBookingRejected, "Booking rejected", "Sorry", "b2"))
+	require.NoError(t, notifier.NotifyUser(ctx, "user2", domain.NotificationTypeBookingConfirmed, "Booking confirmed", "See you", "b3"))
+
+	assert.Equal(t, []string{"user1", "user1", "user2"}, next.users, "every notification is passed through")
+	require.Equal(t, []string{"+79990000001"}, sms.to, "SMS is sent only for confirmations with SMS turned on")
+	assert.Contains(t, sms.body[0], "https://example.com/b/view-b1")
+	assert.Contains(t, sms.body[0], "https://example.com/b/cancel-b1")
+
+	require.Len(t, deliveries.notifications, 1, "every SMS sent is kept in the delivery log")
+	delivery := deliveries.notifications[0]
+	assert.Equal(t, domain.NotificationChannelSMS, delivery.Channel)
+	assert.Equal(t, "user1", delivery.RecipientID)
+	assert.Equal(t, "b1", delivery.RelatedID)
+	assert.Equal(t, sms.body[0], delivery.Message)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Equal(t, domain.DeliveryStatusDelivered, delivery.DeliveryStatus())
+}
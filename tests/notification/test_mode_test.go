@@ -0,0 +1,103 @@
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/notification"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRestaurantRepository struct {
+	repository.RestaurantRepository
+
+	restaurants map[string]*domain.Restaurant
+}
+
+func (r *stubRestaurantRepository) GetByID(_ context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
+	if restaurant, ok := r.restaurants[id.String()]; ok {
+		return restaurant, nil
+	}
+	return nil, errors.New(common.ErrRestaurantNotFound)
+}
+
+type stubBookingRepository struct {
+	repository.BookingRepository
+
+	bookings map[string]*domain.Booking
+}
+
+func (r *stubBookingRepository) GetByID(_ context.Context, id domain.BookingID) (*domain.Booking, error) {
+	if booking, ok := r.bookings[id.String()]; ok {
+		return booking, nil
+	}
+	return nil, errors.New(common.ErrBookingNotFound)
+}
+
+type recordingSink struct {
+	restaurants []string
+	users       []string
+}
+
+func (s *recordingSink) NotifyRestaurant(_ context.Context, restaurantID string, _ domain.NotificationType, _, _ string, _ string) error {
+	s.restaurants = append(s.restaurants, restaurantID)
+	return nil
+}
+
+func (s *recordingSink) NotifyUser(_ context.Context, userID string, _ domain.NotificationType, _, _ string, _ string) error {
+	s.users = append(s.users, userID)
+	return nil
+}
+
+func setupTestModeRouter() (*notification.TestModeRouter, *recordingNotificationService, *userNotificationService, *recordingSink) {
+	restaurants := &stubRestaurantRepository{restaurants: map[string]*domain.Restaurant{
+		"live": {ID: "live"},
+		"test": {ID: "test", IsTest: true},
+	}}
+	bookings := &stubBookingRepository{bookings: map[string]*domain.Booking{
+		"live-booking": {ID: "live-booking", RestaurantID: "live"},
+		"test-booking": {ID: "test-booking", RestaurantID: "live", IsTest: true},
+	}}
+
+	restaurantNotifications := &recordingNotificationService{}
+	userNotifications := &userNotificationService{NotificationService: restaurantNotifications}
+	sink := &recordingSink{}
+
+	return notification.NewTestModeRouter(userNotifications, sink, restaurants, bookings), restaurantNotifications, userNotifications, sink
+}
+
+func TestTestModeRouter_SendsTestNotificationsToSink(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	router, live, liveUsers, sink := setupTestModeRouter()
+
+	require.NoError(t, router.NotifyRestaurant(ctx, "live", domain.NotificationTypeNewBooking, "New booking", "live", "live-booking"))
+	require.NoError(t, router.NotifyRestaurant(ctx, "live", domain.NotificationTypeNewBooking, "New booking", "test booking", "test-booking"))
+	require.NoError(t, router.NotifyRestaurant(ctx, "test", domain.NotificationTypeNewBooking, "New booking", "test restaurant", ""))
+	require.NoError(t, router.NotifyUser(ctx, "user1", domain.NotificationTypeBookingConfirmed, "Booking confirmed", "live", "live-booking"))
+	require.NoError(t, router.NotifyUser(ctx, "user2", domain.NotificationTypeBookingConfirmed, "Booking confirmed", "test", "test-booking"))
+
+	require.Len(t, live.Sent(), 1)
+	assert.Equal(t, "live", live.Sent()[0].message)
+	assert.Equal(t, []string{"user1"}, liveUsers.users)
+	assert.Equal(t, []string{"live", "test"}, sink.restaurants)
+	assert.Equal(t, []string{"user2"}, sink.users)
+}
+
+func TestTestModeRouter_DeliversWhenRelatedIDIsUnknown(t *testing.T) {
+	ctx := newDebouncerTestContext(t)
+	router, live, liveUsers, sink := setupTestModeRouter()
+
+	require.NoError(t, router.NotifyRestaurant(ctx, "live", domain.NotificationTypeBookingCancelled, "Booking cancelled", "unknown", "missing"))
+	require.NoError(t, router.NotifyUser(ctx, "user1", domain.NotificationTypeBookingRejected, "Booking rejected", "unknown", "missing"))
+
+	assert.Len(t, live.Sent(), 1)
+	assert.Equal(t, []string{"user1"}, liveUsers.users)
+	assert.Empty(t, sink.restaurants)
+	assert.Empty(t, sink.users)
+}
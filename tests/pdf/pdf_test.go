@@ -0,0 +1,54 @@
+package pdf_test
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/pdf"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromText_Structure(t *testing.T) {
+	doc := pdf.FromText("# Invoice INV-1\nTotal (EUR)   49.00\n")
+
+	assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+	assert.Contains(t, string(doc), "/Count 1")
+	assert.Contains(t, string(doc), "/F2 14 Tf\n(Invoice INV-1) Tj")
+	assert.Contains(t, string(doc), `(Total \(EUR\)   49.00) Tj`)
+
+	// Every cross-reference entry points at its object.
+	xref := bytes.Index(doc, []byte("\nxref\n")) + 1
+	require.Positive(t, xref)
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	require.NotNil(t, startxref)
+	assert.Equal(t, strconv.Itoa(xref), string(startxref[1]))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(doc[xref:], -1)
+	require.Len(t, entries, 6)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(doc[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), "object %d", i+1)
+	}
+}
+
+func TestFromText_Pages(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = "line " + strconv.Itoa(i)
+	}
+	assert.Contains(t, string(pdf.FromText(strings.Join(lines, "\n"))), "/Count 2")
+
+	assert.Contains(t, string(pdf.FromText("first\n"+pdf.PageBreak+"\nsecond")), "/Count 2")
+}
+
+func TestFromText_Encoding(t *testing.T) {
+	doc := pdf.FromText("Café € Москва")
+	assert.Contains(t, string(doc), "(Caf\xe9 \x80 ??????) Tj")
+}
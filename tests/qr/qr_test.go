@@ -0,0 +1,150 @@
+package qr_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/qr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formatBitsM are the format information strings of level M for masks 0 to 7 (ISO/IEC 18004, table C.1).
+var formatBitsM = []int{
+	0b101010000010010,
+	0b101000100100101,
+	0b101111001111100,
+	0b101101101001011,
+	0b100010111111001,
+	0b100000011001110,
+	0b100111110010111,
+	0b100101010100000,
+}
+
+func TestReedSolomon_MatchesStandardExample(t *testing.T) {
+	// The 1-M "HELLO WORLD" example of the standard.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	ec := qr.ReedSolomonRemainder(data, qr.ReedSolomonDivisor(10))
+
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, ec)
+}
+
+func TestEncode_PicksSmallestVersion(t *testing.T) {
+	tests := []struct {
+		length int
+		size   int
+	}{
+		{1, 21},
+		{14, 21},
+		{15, 25},
+		{62, 33},
+		{63, 37},
+		{120, 45},
+		{213, 57},
+	}
+
+	for _, tt := range tests {
+		code, err := qr.Encode(strings.Repeat("a", tt.length))
+		require.NoError(t, err)
+		assert.Equal(t, tt.size, code.Size, "length %d", tt.length)
+	}
+
+	_, err := qr.Encode(strings.Repeat("a", 214))
+	assert.ErrorIs(t, err, qr.ErrContentTooLong)
+}
+
+func TestEncode_DrawsFunctionPatterns(t *testing.T) {
+	code, err := qr.Encode("https://example.com/restaurants/pushkin")
+	require.NoError(t, err)
+
+	for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+		for dy := range 7 {
+			for dx := range 7 {
+				ring := max(abs(dx-3), abs(dy-3))
+				assert.Equal(t, ring != 2, code.Dark(corner[0]+dx, corner[1]+dy), "finder at %v", corner)
+			}
+		}
+	}
+
+	for i := 8; i < code.Size-8; i++ {
+		assert.Equal(t, i%2 == 0, code.Dark(i, 6))
+		assert.Equal(t, i%2 == 0, code.Dark(6, i))
+	}
+	assert.True(t, code.Dark(8, code.Size-8), "dark module")
+
+	first, second := 0, 0
+	for i := 0; i <= 5; i++ {
+		first |= boolBit(code.Dark(8, i)) << i
+	}
+	first |= boolBit(code.Dark(8, 7))<<6 | boolBit(code.Dark(8, 8))<<7 | boolBit(code.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		first |= boolBit(code.Dark(14-i, 8)) << i
+	}
+	for i := range 8 {
+		second |= boolBit(code.Dark(code.Size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= boolBit(code.Dark(8, code.Size-15+i)) << i
+	}
+	assert.Contains(t, formatBitsM, first)
+	assert.Equal(t, first, second)
+}
+
+func TestEncode_DrawsVersionInformation(t *testing.T) {
+	code, err := qr.Encode(strings.Repeat("a", 120))
+	require.NoError(t, err)
+	require.Equal(t, 45, code.Size, "version 7")
+
+	bits := 0
+	for i := range 18 {
+		bits |= boolBit(code.Dark(code.Size-11+i%3, i/3)) << i
+		assert.Equal(t, code.Dark(code.Size-11+i%3, i/3), code.Dark(i/3, code.Size-11+i%3))
+	}
+	assert.Equal(t, 0b000111110010010100, bits)
+}
+
+func TestCode_PNG(t *testing.T) {
+	code, err := qr.Encode("hello")
+	require.NoError(t, err)
+
+	data, err := code.PNG(4)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	side := (code.Size + 2*qr.QuietZone) * 4
+	assert.Equal(t, side, img.Bounds().Dx())
+	assert.Equal(t, side, img.Bounds().Dy())
+
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.NotZero(t, r, "quiet zone is light")
+	r, _, _, _ = img.At(qr.QuietZone*4, qr.QuietZone*4).RGBA()
+	assert.Zero(t, r, "finder corner is dark")
+}
+
+func TestCode_SVG(t *testing.T) {
+	code, err := qr.Encode("hello")
+	require.NoError(t, err)
+
+	svg := string(code.SVG(2))
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="58" height="58" viewBox="0 0 29 29"`))
+	assert.Contains(t, svg, "M4 4h1v1h-1z")
+	assert.True(t, strings.HasSuffix(svg, "</svg>"))
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
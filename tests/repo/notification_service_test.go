@@ -0,0 +1,49 @@
+package repo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository/postgres"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingLogger keeps the fields of the entries logged at info level.
+type recordingLogger struct {
+	entries []map[string]any
+}
+
+func (l *recordingLogger) Info(_ context.Context, _ string, fields ...zap.Field) {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	l.entries = append(l.entries, encoder.Fields)
+}
+
+func (l *recordingLogger) Warn(context.Context, string, ...zap.Field)  {}
+func (l *recordingLogger) Error(context.Context, string, ...zap.Field) {}
+func (l *recordingLogger) Debug(context.Context, string, ...zap.Field) {}
+func (l *recordingLogger) Fatal(context.Context, string, ...zap.Field) {}
+func (l *recordingLogger) SetLevel(ports.LogLevel)                     {}
+func (l *recordingLogger) GetLevel() ports.LogLevel                    { return ports.InfoLevel }
+func (l *recordingLogger) With(...zap.Field) ports.LoggerPort          { return l }
+func (l *recordingLogger) Sync() error                                 { return nil }
+
+func TestMockSMSService_RedactsLinks(t *testing.T) {
+	log := &recordingLogger{}
+	sms := postgres.NewMockSMSService(log)
+
+	body := "Your booking is confirmed\nDetails: https://book.example.com/b/v1.signed-token\nCancel: http://localhost:8080/b/v1.other-token"
+	require.NoError(t, sms.SendSMS("+79991234567", body))
+
+	require.Len(t, log.entries, 1)
+	assert.Equal(t, "+79991234567", log.entries[0]["to"])
+	assert.Equal(t, "Your booking is confirmed\nDetails: https://book.example.com/[REDACTED]\nCancel: http://localhost:8080/[REDACTED]",
+		log.entries[0]["body"])
+}
@@ -190,7 +190,7 @@ type MockRestaurantRepository struct {
 	mock.Mock
 }
 
-func (m *MockRestaurantRepository) GetByID(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (m *MockRestaurantRepository) GetByID(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -207,17 +207,28 @@ func (m *MockBookingRepository) Create(ctx context.Context, booking *domain.Book
 	return args.Error(0)
 }
 
-func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id string, status domain.BookingStatus) error {
+func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id domain.BookingID, status domain.BookingStatus) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
 }
 
+func (m *MockBookingRepository) ChangeStatus(
+	ctx context.Context,
+	id domain.BookingID,
+	from []domain.BookingStatus,
+	status domain.BookingStatus,
+	at time.Time,
+) (bool, error) {
+	args := m.Called(ctx, id, from, status, at)
+	return args.Bool(0), args.Error(1)
+}
+
 type MockAvailabilityRepository struct {
 	mock.Mock
 }
 
-func (m *MockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability) error {
-	args := m.Called(ctx, availability)
+func (m *MockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error {
+	args := m.Called(ctx, availability, force)
 	return args.Error(0)
 }
 
@@ -243,7 +254,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -268,9 +279,9 @@ func TestRestaurantRepository_GetByID(t *testing.T) {
 	}
 
 	mockRepo := new(MockRestaurantRepository)
-	mockRepo.On("GetByID", mock.Anything, restaurantID).Return(expectedRestaurant, nil)
+	mockRepo.On("GetByID", mock.Anything, domain.RestaurantID(restaurantID)).Return(expectedRestaurant, nil)
 
-	result, err := mockRepo.GetByID(context.Background(), restaurantID)
+	result, err := mockRepo.GetByID(context.Background(), domain.RestaurantID(restaurantID))
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -326,9 +337,9 @@ func TestAvailabilityRepository_SetAvailability(t *testing.T) {
 	}
 
 	mockRepo := new(MockAvailabilityRepository)
-	mockRepo.On("SetAvailability", mock.Anything, availability).Return(nil)
+	mockRepo.On("SetAvailability", mock.Anything, availability, false).Return(nil)
 
-	err := mockRepo.SetAvailability(context.Background(), availability)
+	err := mockRepo.SetAvailability(context.Background(), availability, false)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
@@ -397,9 +408,9 @@ func TestUserRepository_GetByID(t *testing.T) {
 	}
 
 	mockRepo := new(MockUserRepository)
-	mockRepo.On("GetByID", mock.Anything, userID).Return(expectedUser, nil)
+	mockRepo.On("GetByID", mock.Anything, domain.UserID(userID)).Return(expectedUser, nil)
 
-	result, err := mockRepo.GetByID(context.Background(), userID)
+	result, err := mockRepo.GetByID(context.Background(), domain.UserID(userID))
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -411,7 +422,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 }
 
 func TestBookingRepository_UpdateStatus(t *testing.T) {
-	bookingID := uuid.New().String()
+	bookingID := domain.BookingID(uuid.New().String())
 	newStatus := domain.BookingStatusConfirmed
 
 	mockRepo := new(MockBookingRepository)
@@ -427,9 +438,9 @@ func TestRestaurantRepository_GetByID_NotFound(t *testing.T) {
 	restaurantID := uuid.New().String()
 
 	mockRepo := new(MockRestaurantRepository)
-	mockRepo.On("GetByID", mock.Anything, restaurantID).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	mockRepo.On("GetByID", mock.Anything, domain.RestaurantID(restaurantID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
 
-	result, err := mockRepo.GetByID(context.Background(), restaurantID)
+	result, err := mockRepo.GetByID(context.Background(), domain.RestaurantID(restaurantID))
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
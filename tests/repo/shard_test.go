@@ -0,0 +1,62 @@
+package repo_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func restaurantIDs(n int) []string {
+	ids := make([]string, 0, n)
+	for i := range n {
+		ids = append(ids, uuid.NewSHA1(uuid.NameSpaceOID, []byte(strconv.Itoa(i))).String())
+	}
+	return ids
+}
+
+func TestHashRing_SpreadsKeysEvenly(t *testing.T) {
+	ring := repository.NewHashRing([]string{"eu-1", "eu-2", "us-1", "ap-1"}, repository.DefaultVirtualNodes)
+
+	counts := make(map[string]int)
+	for _, id := range restaurantIDs(20000) {
+		counts[ring.Locate(id)]++
+	}
+
+	require.Len(t, counts, 4)
+	for shard, count := range counts {
+		assert.InDelta(t, 5000, count, 1000, "shard %s holds %d of 20000", shard, count)
+	}
+}
+
+func TestHashRing_AddingAShardMovesOnlyItsShare(t *testing.T) {
+	before := repository.NewHashRing([]string{"eu-1", "eu-2", "us-1"}, repository.DefaultVirtualNodes)
+	after := repository.NewHashRing([]string{"eu-1", "eu-2", "us-1", "ap-1"}, repository.DefaultVirtualNodes)
+
+	ids := restaurantIDs(10000)
+	moved := 0
+	for _, id := range ids {
+		from, to := before.Locate(id), after.Locate(id)
+		if from != to {
+			moved++
+			assert.Equal(t, "ap-1", to, "keys only move to the new shard")
+		}
+	}
+
+	assert.InDelta(t, 2500, moved, 700, "about a quarter of the keys move")
+}
+
+func TestHashRing_IsStable(t *testing.T) {
+	ring := repository.NewHashRing([]string{"b", "a", "c"}, 16)
+	reordered := repository.NewHashRing([]string{"c", "b", "a", "a", ""}, 16)
+
+	for _, id := range restaurantIDs(1000) {
+		assert.Equal(t, ring.Locate(id), reordered.Locate(id))
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, reordered.Shards())
+	assert.Equal(t, "", repository.NewHashRing(nil, 16).Locate("rest1"))
+}
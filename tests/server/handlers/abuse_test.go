@@ -0,0 +1,86 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAbuseUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAbuseUseCase) RecordBooking(ctx context.Context, booking *domain.Booking) {
+	m.Called(ctx, booking)
+}
+
+func (m *MockAbuseUseCase) RecordCancellation(ctx context.Context, booking *domain.Booking) {
+	m.Called(ctx, booking)
+}
+
+func (m *MockAbuseUseCase) Allow(ctx context.Context) (bool, time.Duration) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Get(1).(time.Duration)
+}
+
+func (m *MockAbuseUseCase) ListAbuseEvents(ctx context.Context, kind domain.AbuseEventKind) ([]*domain.AbuseEvent, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AbuseEvent), args.Error(1)
+}
+
+func TestListAbuseEvents(t *testing.T) {
+	app := fiber.New()
+	abuseUseCase := new(MockAbuseUseCase)
+	handler := handlers.NewAbuseHandler(abuseUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/admin/abuse-events", handler.ListAbuseEvents)
+
+	abuseUseCase.On("ListAbuseEvents", mock.Anything, domain.AbuseEventCancelRebook).Return([]*domain.AbuseEvent{{
+		ID:           "event1",
+		Kind:         domain.AbuseEventCancelRebook,
+		ClientIP:     "203.0.113.7",
+		RestaurantID: "restaurant1",
+		Count:        3,
+		Window:       time.Hour,
+	}}, nil)
+	abuseUseCase.On("ListAbuseEvents", mock.Anything, domain.AbuseEventKind("spam")).Return(nil, usecase.ErrInvalidAbuseEventKind)
+	abuseUseCase.On("ListAbuseEvents", mock.Anything, domain.AbuseEventKind("")).Return(nil, tenant.ErrAccessDenied)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/abuse-events?kind=cancel_rebook", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var events []handlers.AbuseEventResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(3600), events[0].WindowSeconds)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/admin/abuse-events?kind=spam", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/admin/abuse-events", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,188 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUseCase) RegisterUser(ctx context.Context, user *domain.User, password string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, user, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) RegisterRestaurantAccount(ctx context.Context, restaurantID, email, password string) (*domain.Account, error) {
+	args := m.Called(ctx, restaurantID, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Login(ctx context.Context, email, password string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Refresh(ctx context.Context, refreshToken string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Logout(ctx context.Context, refreshToken string) error {
+	args := m.Called(ctx, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) VerifyEmail(ctx context.Context, token string) (*domain.Account, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAuthUseCase) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) Authenticate(ctx context.Context, accessToken string) (*tenant.Principal, error) {
+	args := m.Called(ctx, accessToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tenant.Principal), args.Error(1)
+}
+
+func setupAuthTestApp(_ *testing.T) (*fiber.App, *MockAuthUseCase) {
+	app := fiber.New()
+	authUseCase := new(MockAuthUseCase)
+	handler := handlers.NewAuthHandler(authUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	app.Post("/auth/login", handler.Login)
+	app.Get("/auth/verify-email", handler.VerifyEmail)
+	app.Post("/auth/verify-email/resend", handler.ResendVerification)
+
+	return app, authUseCase
+}
+
+func TestLogin_AnswersTokenResponse(t *testing.T) {
+	app, authUseCase := setupAuthTestApp(t)
+	authUseCase.On("Login", mock.Anything, "guest@example.com", "correct horse").Return(&usecase.TokenPair{
+		AccessToken:  "access",
+		TokenType:    "Bearer",
+		ExpiresIn:    900,
+		RefreshToken: "refresh",
+		Account: &domain.Account{
+			ID:                    "account1",
+			Kind:                  domain.AccountUser,
+			Email:                 "guest@example.com",
+			NormalizedEmail:       "guest@example.com",
+			PasswordHash:          "$2a$10$hash",
+			UserID:                userPathID,
+			EmailVerificationHash: "verification-hash",
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"guest@example.com","password":"correct horse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	var tokens handlers.TokenResponse
+	require.NoError(t, json.Unmarshal(body, &tokens))
+	assert.Equal(t, "access", tokens.AccessToken)
+	assert.Equal(t, "refresh", tokens.RefreshToken)
+	assert.Equal(t, userPathID, tokens.Account.UserID)
+	assert.False(t, tokens.Account.EmailVerified)
+	assert.NotContains(t, string(body), "hash", "neither the password nor the verification token leaks")
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Run("verified", func(t *testing.T) {
+		app, authUseCase := setupAuthTestApp(t)
+		verifiedAt := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+		authUseCase.On("VerifyEmail", mock.Anything, "token1").Return(&domain.Account{
+			ID: "account1", Kind: domain.AccountUser, Email: "guest@example.com", EmailVerifiedAt: &verifiedAt,
+		}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/verify-email?token=token1", nil))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var account handlers.AccountResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&account))
+		assert.True(t, account.EmailVerified)
+		assert.True(t, verifiedAt.Equal(*account.EmailVerifiedAt))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		app, authUseCase := setupAuthTestApp(t)
+		authUseCase.On("VerifyEmail", mock.Anything, "used").Return(nil, usecase.ErrInvalidVerificationToken)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/verify-email?token=used", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		app, authUseCase := setupAuthTestApp(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/auth/verify-email", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		authUseCase.AssertNotCalled(t, "VerifyEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestResendVerification(t *testing.T) {
+	app, authUseCase := setupAuthTestApp(t)
+	authUseCase.On("ResendVerification", mock.Anything, "guest@example.com").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/verify-email/resend", strings.NewReader(`{"email":"guest@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	authUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,124 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBookingPageApp(restaurantUseCase *MockRestaurantUseCase, availabilityUseCase *MockAvailabilityUseCase, principal *tenant.Principal) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewBookingPageHandler(restaurantUseCase, availabilityUseCase, 2)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	if principal != nil {
+		ctx = tenant.NewContext(ctx, principal)
+	}
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/r/:slug", handler.GetBookingPage)
+	return app
+}
+
+func TestGetBookingPage(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	availabilityUseCase := new(MockAvailabilityUseCase)
+	app := setupBookingPageApp(restaurantUseCase, availabilityUseCase, nil)
+
+	first := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 7)
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "pasta-place").Return(&domain.Restaurant{
+		ID: restaurantPathID, Name: "Pasta <Place>", Slug: "pasta-place", Cuisine: domain.Cuisine("italian"), IsAdultOnly: true,
+	}, nil)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), first).Return([]*domain.Availability{
+		{TimeSlot: "18:00", Capacity: 10, Reserved: 2},
+		{TimeSlot: "19:00", Capacity: 10, Reserved: 10},
+	}, nil)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), first.AddDate(0, 0, 1)).
+		Return([]*domain.Availability{}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/r/pasta-place?date="+first.Format(time.DateOnly), nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "private, max-age=60", resp.Header.Get("Cache-Control"))
+	assert.Contains(t, resp.Header.Get("Content-Security-Policy"), "script-src 'sha256-")
+
+	page, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Pasta &lt;Place&gt;")
+	assert.Contains(t, string(page), `data-restaurant="`+restaurantPathID+`"`)
+	assert.Contains(t, string(page), `value="`+first.Format(time.DateOnly)+` 18:00"`)
+	assert.NotContains(t, string(page), ">19:00<", "fully booked slots are left out")
+	assert.Contains(t, string(page), `name="email"`)
+	assert.Contains(t, string(page), `name="age_attested"`)
+	assert.Contains(t, string(page), `href="?date=`+first.AddDate(0, 0, 2).Format(time.DateOnly)+`"`)
+	assert.Contains(t, string(page), "Earlier dates")
+}
+
+func TestGetBookingPage_SignedIn(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	availabilityUseCase := new(MockAvailabilityUseCase)
+	app := setupBookingPageApp(restaurantUseCase, availabilityUseCase, &tenant.Principal{UserID: "user1"})
+
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "pasta-place").
+		Return(&domain.Restaurant{ID: restaurantPathID, Name: "Pasta Place", Slug: "pasta-place"}, nil)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), mock.Anything).Return([]*domain.Availability{}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/r/pasta-place", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	page, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), `data-user="user1"`)
+	assert.NotContains(t, string(page), `name="email"`)
+	assert.NotContains(t, string(page), "Earlier dates", "the page starts today")
+	assert.NotContains(t, string(page), `name="age_attested"`)
+}
+
+func TestGetBookingPage_Lookup(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	availabilityUseCase := new(MockAvailabilityUseCase)
+	app := setupBookingPageApp(restaurantUseCase, availabilityUseCase, nil)
+
+	restaurant := &domain.Restaurant{ID: restaurantPathID, Name: "Pasta Place", Slug: "pasta-place"}
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "old-pasta").Return(restaurant, nil)
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, restaurantPathID).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(restaurant, nil)
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "Missing").Return(nil, usecase.ErrInvalidRestaurantSlug)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), mock.Anything).Return([]*domain.Availability{}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/r/old-pasta", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/r/pasta-place", resp.Header.Get("Location"))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/r/"+restaurantPathID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "restaurants are found by ID too, as custom domains address them")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/r/Missing", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/r/pasta-place?date=soon", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +16,8 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/assert"
@@ -21,10 +25,21 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// bookingPathID and missingBookingPathID are the bookings of the request paths, which the handlers
+// parse as UUIDs.
+const (
+	bookingPathID        = "0190f3b2-7c1e-7a4d-9b2f-5e6a7b8c9d0e"
+	missingBookingPathID = "0190f3b2-7c1e-7a4d-9b2f-000000000000"
+)
+
 func setupBookingTestApp(_ *testing.T) (*fiber.App, *MockBookingUseCase, context.Context) {
 	app := fiber.New()
 	bookingUseCase := new(MockBookingUseCase)
-	handler := handlers.NewBookingHandler(bookingUseCase)
+	receiptUseCase := new(MockNotificationReceiptUseCase)
+	receiptUseCase.On("GetBookingDeliveries", mock.Anything, mock.Anything).Return([]domain.NotificationDelivery{}, nil).Maybe()
+	menuUseCase := new(MockMenuUseCase)
+	menuUseCase.On("GetPreOrder", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	handler := handlers.NewBookingHandler(bookingUseCase, receiptUseCase, menuUseCase)
 
 	testLogger := CreateTestLogger()
 
@@ -87,6 +102,39 @@ func TestCreateBooking_Success(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
+func TestCreateBooking_Attribution(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	bookingUseCase.On("CreateBooking", mock.Anything, mock.MatchedBy(func(booking *domain.Booking) bool {
+		return booking.Attribution != nil && *booking.Attribution == domain.BookingAttribution{
+			Source:       "newsletter",
+			Medium:       "email",
+			Campaign:     "spring",
+			ReferralCode: "FRIEND10",
+		}
+	})).Return("booking123", nil).Once()
+	bookingUseCase.On("CreateBooking", mock.Anything, mock.MatchedBy(func(booking *domain.Booking) bool {
+		return booking.Attribution == nil
+	})).Return("booking456", nil).Once()
+
+	body := `{"restaurant_id":"restaurant1","user_id":"user1","date":"2030-01-01T00:00:00Z","time":"19:00",` +
+		`"duration":90,"guests_count":2,"utm_source":"newsletter","utm_medium":"email"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings?utm_source=ignored&utm_campaign=spring&ref=FRIEND10", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	body = `{"restaurant_id":"restaurant1","user_id":"user1","date":"2030-01-01T00:00:00Z","time":"19:00","duration":90,"guests_count":2}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/bookings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	bookingUseCase.AssertExpectations(t)
+}
+
 func TestCreateBooking_InvalidParams(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
@@ -138,6 +186,105 @@ func TestCreateBooking_InternalError(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
+func TestCreateBooking_InvalidOccasion(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	bookingUseCase.On("CreateBooking", mock.Anything, mock.MatchedBy(func(booking *domain.Booking) bool {
+		return booking.Occasion == "graduation"
+	})).Return("", fmt.Errorf("%w: %q", usecase.ErrInvalidOccasion, "graduation"))
+
+	reqJSON, _ := json.Marshal(handlers.CreateBookingRequest{
+		RestaurantID: "restaurant1",
+		UserID:       "user1",
+		Date:         time.Now().Add(24 * time.Hour),
+		Time:         "19:00",
+		Duration:     90,
+		GuestsCount:  2,
+		Occasion:     "graduation",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	bookingUseCase.AssertExpectations(t)
+}
+
+func TestCreateBooking_AgeAttestationRequired(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	bookingUseCase.On("CreateBooking", mock.Anything, mock.MatchedBy(func(booking *domain.Booking) bool {
+		return booking.RestaurantID == "bar" && !booking.AgeAttested
+	})).Return("", usecase.ErrAgeAttestationRequired)
+
+	reqJSON, _ := json.Marshal(handlers.CreateBookingRequest{
+		RestaurantID: "bar",
+		UserID:       "user1",
+		Date:         time.Now().Add(24 * time.Hour),
+		Time:         "22:00",
+		Duration:     90,
+		GuestsCount:  2,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var respBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, common.ErrAgeAttestationRequired, respBody["error"])
+
+	bookingUseCase.AssertExpectations(t)
+}
+
+func TestCreateBooking_RestaurantClosed(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	closure := &domain.RestaurantClosure{
+		ID:           "closure1",
+		RestaurantID: "restaurant1",
+		StartsOn:     time.Now().Truncate(24 * time.Hour),
+		ReopensOn:    time.Date(2030, time.June, 20, 0, 0, 0, 0, time.UTC),
+	}
+	bookingUseCase.On("CreateBooking", mock.Anything, mock.Anything).Return("", &usecase.RestaurantClosedError{
+		Closure: closure,
+		Reply:   "Bistro is closed",
+		Sisters: []*domain.Restaurant{{ID: "sister1", Name: "Round The Corner", Slug: "round-the-corner"}},
+	})
+
+	reqJSON, _ := json.Marshal(handlers.CreateBookingRequest{
+		RestaurantID: "restaurant1",
+		UserID:       "user1",
+		Date:         time.Now().Add(24 * time.Hour),
+		Time:         "19:00",
+		Duration:     90,
+		GuestsCount:  2,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var respBody handlers.RestaurantClosedResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, common.ErrRestaurantClosed, respBody.Error)
+	assert.Equal(t, "Bistro is closed", respBody.Message)
+	assert.Equal(t, "2030-06-20", respBody.ReopensOn)
+	require.Len(t, respBody.SisterRestaurants, 1)
+	assert.Equal(t, "round-the-corner", respBody.SisterRestaurants[0].Slug)
+
+	bookingUseCase.AssertExpectations(t)
+}
+
 func TestGetBooking_Success(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
@@ -156,9 +303,9 @@ func TestGetBooking_Success(t *testing.T) {
 		ConfirmedAt:  &currentTime,
 	}
 
-	bookingUseCase.On("GetBooking", mock.Anything, "booking123").Return(expectedBooking, nil)
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(expectedBooking, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/booking123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -174,12 +321,27 @@ func TestGetBooking_Success(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
+func TestGetBooking_InvalidID(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/bookings/booking123", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/cancel", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	bookingUseCase.AssertNotCalled(t, "GetBooking", mock.Anything, mock.Anything)
+	bookingUseCase.AssertNotCalled(t, "CancelBooking", mock.Anything, mock.Anything)
+}
+
 func TestGetBooking_NotFound(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("GetBooking", mock.Anything, "nonexistent").Return(nil, nil)
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(missingBookingPathID)).Return(nil, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/nonexistent", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+missingBookingPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
@@ -195,9 +357,9 @@ func TestGetBooking_NotFound(t *testing.T) {
 func TestGetBooking_InternalError(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("GetBooking", mock.Anything, "booking123").Return(nil, errors.New("database error"))
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(nil, errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/booking123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -210,12 +372,29 @@ func TestGetBooking_InternalError(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
+func TestGetBooking_AccessDenied(t *testing.T) {
+	app, bookingUseCase, _ := setupBookingTestApp(t)
+
+	isolationErr := &tenant.IsolationError{PrincipalUserID: "user2", Resource: "booking", ResourceID: "booking123"}
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(nil, isolationErr)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	var respBody map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, common.ErrAccessDenied, respBody["error"])
+}
+
 func TestConfirmBooking_Success(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("ConfirmBooking", mock.Anything, "booking123").Return(nil)
+	bookingUseCase.On("ConfirmBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(nil)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/confirm", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/confirm", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -231,9 +410,9 @@ func TestConfirmBooking_Success(t *testing.T) {
 func TestConfirmBooking_InternalError(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("ConfirmBooking", mock.Anything, "booking123").Return(errors.New("database error"))
+	bookingUseCase.On("ConfirmBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/confirm", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/confirm", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -249,14 +428,14 @@ func TestConfirmBooking_InternalError(t *testing.T) {
 func TestRejectBooking_Success(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("RejectBooking", mock.Anything, "booking123", "Fully booked").Return(nil)
+	bookingUseCase.On("RejectBooking", mock.Anything, domain.BookingID(bookingPathID), "Fully booked").Return(nil)
 
 	reqBody := handlers.RejectBookingRequest{
 		Reason: "Fully booked",
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/reject", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/reject", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -278,7 +457,7 @@ func TestRejectBooking_InvalidParams(t *testing.T) {
 
 	reqJSON := []byte(`{"reason": invalid-json}`)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/reject", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/reject", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -294,14 +473,14 @@ func TestRejectBooking_InvalidParams(t *testing.T) {
 func TestRejectBooking_InternalError(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("RejectBooking", mock.Anything, "booking123", "Fully booked").Return(errors.New("database error"))
+	bookingUseCase.On("RejectBooking", mock.Anything, domain.BookingID(bookingPathID), "Fully booked").Return(errors.New("database error"))
 
 	reqBody := handlers.RejectBookingRequest{
 		Reason: "Fully booked",
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/reject", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/reject", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -319,9 +498,9 @@ func TestRejectBooking_InternalError(t *testing.T) {
 func TestCancelBooking_Success(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("CancelBooking", mock.Anything, "booking123").Return(nil)
+	bookingUseCase.On("CancelBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(nil)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/cancel", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/cancel", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -337,9 +516,9 @@ func TestCancelBooking_Success(t *testing.T) {
 func TestCancelBooking_InternalError(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("CancelBooking", mock.Anything, "booking123").Return(errors.New("database error"))
+	bookingUseCase.On("CancelBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/cancel", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/cancel", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -355,9 +534,9 @@ func TestCancelBooking_InternalError(t *testing.T) {
 func TestCompleteBooking_Success(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("CompleteBooking", mock.Anything, "booking123").Return(nil)
+	bookingUseCase.On("CompleteBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(nil)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/complete", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/complete", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -373,9 +552,9 @@ func TestCompleteBooking_Success(t *testing.T) {
 func TestCompleteBooking_InternalError(t *testing.T) {
 	app, bookingUseCase, _ := setupBookingTestApp(t)
 
-	bookingUseCase.On("CompleteBooking", mock.Anything, "booking123").Return(errors.New("database error"))
+	bookingUseCase.On("CompleteBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/complete", nil)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/complete", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -397,7 +576,7 @@ func TestSuggestAlternativeTime_Success(t *testing.T) {
 	bookingUseCase.On(
 		"SuggestAlternativeTime",
 		mock.Anything,
-		"booking123",
+		domain.BookingID(bookingPathID),
 		mock.MatchedBy(func(date time.Time) bool {
 			return date.Equal(alternativeDate)
 		}),
@@ -412,7 +591,7 @@ func TestSuggestAlternativeTime_Success(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/alternative", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/alternative", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -434,7 +613,7 @@ func TestSuggestAlternativeTime_InvalidParams(t *testing.T) {
 
 	reqJSON := []byte(`{"date": invalid-json, "time": "20:00"}`)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/alternative", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/alternative", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -455,7 +634,7 @@ func TestSuggestAlternativeTime_InternalError(t *testing.T) {
 	bookingUseCase.On(
 		"SuggestAlternativeTime",
 		mock.Anything,
-		"booking123",
+		domain.BookingID(bookingPathID),
 		mock.Anything,
 		"20:00",
 		"Alternative time suggestion",
@@ -468,7 +647,7 @@ func TestSuggestAlternativeTime_InternalError(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/booking123/alternative", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/alternative", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -0,0 +1,88 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func conditionalGet(t *testing.T, app *fiber.App, path string, headers map[string]string) *http.Response {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func TestGetRestaurant_CachingHeaders(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	updatedAt := time.Date(2025, 3, 1, 12, 30, 15, 500, time.UTC)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{
+		ID:        restaurantPathID,
+		Name:      "Pelmennaya",
+		UpdatedAt: updatedAt,
+	}, nil)
+
+	resp := conditionalGet(t, app, "/api/v1/restaurants/"+restaurantPathID, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=60, must-revalidate", resp.Header.Get(fiber.HeaderCacheControl))
+	assert.Equal(t, "Sat, 01 Mar 2025 12:30:15 GMT", resp.Header.Get(fiber.HeaderLastModified))
+	etag := resp.Header.Get(fiber.HeaderETag)
+	require.NotEmpty(t, etag)
+
+	for name, test := range map[string]struct {
+		headers map[string]string
+		status  int
+	}{
+		"matching etag":           {map[string]string{fiber.HeaderIfNoneMatch: etag}, http.StatusNotModified},
+		"other etag":              {map[string]string{fiber.HeaderIfNoneMatch: `W/"stale"`}, http.StatusOK},
+		"not modified since":      {map[string]string{fiber.HeaderIfModifiedSince: "Sat, 01 Mar 2025 12:30:15 GMT"}, http.StatusNotModified},
+		"modified since":          {map[string]string{fiber.HeaderIfModifiedSince: "Sat, 01 Mar 2025 12:00:00 GMT"}, http.StatusOK},
+		"etag wins over the date": {map[string]string{fiber.HeaderIfNoneMatch: `W/"stale"`, fiber.HeaderIfModifiedSince: "Sun, 02 Mar 2025 00:00:00 GMT"}, http.StatusOK},
+	} {
+		resp := conditionalGet(t, app, "/api/v1/restaurants/"+restaurantPathID, test.headers)
+		assert.Equal(t, test.status, resp.StatusCode, name)
+	}
+}
+
+func TestGetFacts_UnknownRestaurant(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	resp := conditionalGet(t, app, "/api/v1/restaurants/"+missingRestaurantPathID+"/facts", nil)
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderCacheControl))
+	restaurantUseCase.AssertNotCalled(t, "GetFacts", mock.Anything, mock.Anything)
+}
+
+func TestGetWorkingHours_LastModifiedOfRestaurant(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{
+		ID:        restaurantPathID,
+		UpdatedAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+	}, nil)
+	restaurantUseCase.On("GetWorkingHours", mock.Anything, domain.RestaurantID(restaurantPathID)).Return([]*domain.WorkingHours{}, nil)
+
+	resp := conditionalGet(t, app, "/api/v1/restaurants/"+restaurantPathID+"/working-hours", nil)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Sat, 01 Mar 2025 09:00:00 GMT", resp.Header.Get(fiber.HeaderLastModified))
+}
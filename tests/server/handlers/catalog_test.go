@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCatalogUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCatalogUseCase) GetCatalogPage(ctx context.Context, page, pageSize int) (*usecase.CatalogPage, error) {
+	args := m.Called(ctx, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.CatalogPage), args.Error(1)
+}
+
+func setupCatalogTestApp(_ *testing.T) (*fiber.App, *MockCatalogUseCase) {
+	app := fiber.New()
+	catalogUseCase := new(MockCatalogUseCase)
+	handler := handlers.NewCatalogHandler(catalogUseCase, "https://example.com/")
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	app.Get("/sitemap.xml", handler.Sitemap)
+	app.Get("/feed/restaurants.json", handler.RestaurantsFeed)
+
+	return app, catalogUseCase
+}
+
+func TestSitemap(t *testing.T) {
+	updatedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	restaurants := []*domain.Restaurant{
+		{ID: "restaurant1", Name: "Alpha", UpdatedAt: updatedAt},
+		{ID: "restaurant2", Name: "Bravo", UpdatedAt: updatedAt},
+	}
+
+	t.Run("index", func(t *testing.T) {
+		app, catalogUseCase := setupCatalogTestApp(t)
+		catalogUseCase.On("GetCatalogPage", mock.Anything, 1, mock.Anything).Return(&usecase.CatalogPage{Page: 1, HasNext: true}, nil)
+		catalogUseCase.On("GetCatalogPage", mock.Anything, 2, mock.Anything).Return(&usecase.CatalogPage{Page: 2}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "<sitemapindex")
+		assert.Contains(t, string(body), "https://example.com/sitemap.xml?page=1")
+		assert.Contains(t, string(body), "https://example.com/sitemap.xml?page=2")
+		assert.NotContains(t, string(body), "page=3")
+
+		catalogUseCase.AssertExpectations(t)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		app, catalogUseCase := setupCatalogTestApp(t)
+		catalogUseCase.On("GetCatalogPage", mock.Anything, 1, mock.Anything).Return(&usecase.CatalogPage{Page: 1, Restaurants: restaurants}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sitemap.xml?page=1", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "application/xml")
+		assert.NotEmpty(t, resp.Header.Get("Cache-Control"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "<loc>https://example.com/restaurants/restaurant1</loc>")
+		assert.Contains(t, string(body), "<lastmod>2025-03-01</lastmod>")
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		app, _ := setupCatalogTestApp(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sitemap.xml?page=abc", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestRestaurantsFeed(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		app, catalogUseCase := setupCatalogTestApp(t)
+		catalogUseCase.On("GetCatalogPage", mock.Anything, 2, 1).Return(&usecase.CatalogPage{
+			Page:        2,
+			PageSize:    1,
+			HasNext:     true,
+			Restaurants: []*domain.Restaurant{{ID: "restaurant1", Name: "Alpha", Cuisine: "italian"}},
+		}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed/restaurants.json?page=2&page_size=1", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Link"), `rel="next"`)
+
+		var feed handlers.RestaurantsFeed
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&feed))
+		assert.Equal(t, "https://schema.org", feed.Context)
+		require.Len(t, feed.ItemListElement, 1)
+		assert.Equal(t, 2, feed.ItemListElement[0].Position)
+		assert.Equal(t, "Restaurant", feed.ItemListElement[0].Item.Type)
+		assert.Equal(t, "italian", feed.ItemListElement[0].Item.ServesCuisine)
+		assert.Equal(t, "https://example.com/feed/restaurants.json?page=3&page_size=1", feed.Next)
+	})
+
+	t.Run("invalid page size", func(t *testing.T) {
+		app, _ := setupCatalogTestApp(t)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed/restaurants.json?page_size=1000", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("use case error", func(t *testing.T) {
+		app, catalogUseCase := setupCatalogTestApp(t)
+		catalogUseCase.On("GetCatalogPage", mock.Anything, 1, 50).Return(nil, errors.New("database error"))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/feed/restaurants.json", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
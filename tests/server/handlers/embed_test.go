@@ -0,0 +1,98 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEmbedApp(restaurantUseCase *MockRestaurantUseCase, availabilityUseCase *MockAvailabilityUseCase) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewEmbedHandler(restaurantUseCase, availabilityUseCase, "https://book.example/", 2,
+		[]string{"https://pasta.example"})
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/embed/restaurants/:id/availability", handler.GetAvailabilityWidget)
+	return app
+}
+
+func TestGetAvailabilityWidget(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	availabilityUseCase := new(MockAvailabilityUseCase)
+	app := setupEmbedApp(restaurantUseCase, availabilityUseCase)
+
+	first := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).
+		Return(&domain.Restaurant{ID: restaurantPathID, Name: "Pasta <Place>", Slug: "pasta-place"}, nil)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), first).Return([]*domain.Availability{
+		{TimeSlot: "18:00", Capacity: 10, Reserved: 2},
+		{TimeSlot: "19:00", Capacity: 10, Reserved: 10},
+		{TimeSlot: "20:00", Capacity: 10, Reserved: 9},
+	}, nil)
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), first.AddDate(0, 0, 1)).
+		Return([]*domain.Availability{}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/embed/restaurants/"+restaurantPathID+"/availability?date=2025-05-01", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+
+	var widget handlers.EmbedAvailabilityResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&widget))
+	assert.Equal(t, "https://book.example/restaurants/pasta-place", widget.BookURL)
+	require.Len(t, widget.Days, 2)
+	require.Len(t, widget.Days[0].Slots, 2, "fully booked slots are left out")
+	assert.Equal(t, handlers.EmbedSlotResponse{Time: "18:00", Free: 8, Status: "available"}, widget.Days[0].Slots[0])
+	assert.Equal(t, "limited", widget.Days[0].Slots[1].Status)
+	assert.Empty(t, widget.Days[1].Slots)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/embed/restaurants/"+restaurantPathID+"/availability?date=2025-05-01&format=html", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "frame-ancestors https://pasta.example", resp.Header.Get("Content-Security-Policy"))
+	page, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "Pasta &lt;Place&gt;")
+	assert.Contains(t, string(page), `href="https://book.example/restaurants/pasta-place?date=2025-05-01&amp;time=18`)
+	assert.NotContains(t, string(page), ">19:00<")
+}
+
+func TestGetAvailabilityWidget_Errors(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	availabilityUseCase := new(MockAvailabilityUseCase)
+	app := setupEmbedApp(restaurantUseCase, availabilityUseCase)
+
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	for path, status := range map[string]int{
+		"/embed/restaurants/" + missingRestaurantPathID + "/availability":     http.StatusNotFound,
+		"/embed/restaurants/" + restaurantPathID + "/availability?days=15":    http.StatusBadRequest,
+		"/embed/restaurants/" + restaurantPathID + "/availability?date=today": http.StatusBadRequest,
+		"/embed/restaurants/" + restaurantPathID + "/availability?format=xml": http.StatusBadRequest,
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, path)
+	}
+	availabilityUseCase.AssertNotCalled(t, "GetAvailability", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExportUseCase struct {
+	mock.Mock
+}
+
+func (m *MockExportUseCase) ExportChanges(ctx context.Context, since time.Time, entities []domain.ExportEntity, emit func(*domain.ExportRecord) error) (time.Time, error) {
+	args := m.Called(ctx, since, entities, emit)
+	if records, ok := args.Get(0).([]*domain.ExportRecord); ok {
+		for _, record := range records {
+			if err := emit(record); err != nil {
+				return time.Time{}, err
+			}
+		}
+	}
+	return args.Get(1).(time.Time), args.Error(2)
+}
+
+func setupExportApp(exportUseCase usecase.ExportUseCase) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewExportHandler(exportUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/admin/export", handler.ExportChanges)
+	return app
+}
+
+func TestExportChanges(t *testing.T) {
+	exportUseCase := new(MockExportUseCase)
+	app := setupExportApp(exportUseCase)
+
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	checkpoint := time.Date(2025, 5, 2, 0, 0, 0, 0, time.UTC)
+	exportUseCase.On("ExportChanges", mock.Anything, since, []domain.ExportEntity{domain.ExportBookings}, mock.Anything).
+		Return([]*domain.ExportRecord{
+			{Entity: domain.ExportBookings, ID: "booking1", UpdatedAt: since.Add(time.Hour), Data: json.RawMessage(`{"id":"booking1"}`)},
+			{Entity: domain.ExportBookings, ID: "booking2", UpdatedAt: since.Add(2 * time.Hour), Data: json.RawMessage(`{"id":"booking2"}`)},
+		}, checkpoint, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/export?since=2025-05-01T00:00:00Z&entities=bookings", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 3)
+
+	var record domain.ExportRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "booking2", record.ID)
+
+	var trailer handlers.ExportTrailer
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &trailer))
+	require.NotNil(t, trailer.Checkpoint)
+	assert.True(t, checkpoint.Equal(*trailer.Checkpoint))
+}
+
+func TestExportChanges_FailureAfterRecords(t *testing.T) {
+	exportUseCase := new(MockExportUseCase)
+	app := setupExportApp(exportUseCase)
+
+	exportUseCase.On("ExportChanges", mock.Anything, time.Time{}, []domain.ExportEntity(nil), mock.Anything).
+		Return([]*domain.ExportRecord{{Entity: domain.ExportUsers, ID: "user1", Data: json.RawMessage(`{}`)}},
+			time.Time{}, errors.New("connection lost"))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/export", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	require.Len(t, lines, 2)
+
+	var trailer handlers.ExportTrailer
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &trailer))
+	assert.Nil(t, trailer.Checkpoint)
+	assert.NotEmpty(t, trailer.Error)
+}
+
+func TestExportChanges_Rejected(t *testing.T) {
+	exportUseCase := new(MockExportUseCase)
+	app := setupExportApp(exportUseCase)
+
+	exportUseCase.On("ExportChanges", mock.Anything, time.Time{}, []domain.ExportEntity{"passwords"}, mock.Anything).
+		Return(nil, time.Time{}, usecase.ErrInvalidExportEntity)
+	exportUseCase.On("ExportChanges", mock.Anything, time.Time{}, []domain.ExportEntity(nil), mock.Anything).
+		Return(nil, time.Time{}, tenant.ErrAccessDenied)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/export?entities=passwords", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/admin/export", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/admin/export?since=yesterday", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -11,8 +11,10 @@ import (
 
 	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/assert"
@@ -29,11 +31,24 @@ func (m *MockFactsUseCase) GetRandomFacts(ctx context.Context, count int) ([]dom
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
-func (m *MockFactsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *MockFactsUseCase) GetFilteredRandomFacts(ctx context.Context, filter domain.FactFilter, count int) ([]domain.Fact, error) {
+	args := m.Called(ctx, filter, count)
+	return args.Get(0).([]domain.Fact), args.Error(1)
+}
+
+func (m *MockFactsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
+func (m *MockFactsUseCase) GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error) {
+	args := m.Called(ctx, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Fact), args.Error(1)
+}
+
 func setupFactsTestApp(_ *testing.T) (*fiber.App, *MockFactsUseCase, context.Context) {
 	app := fiber.New()
 	factsUseCase := new(MockFactsUseCase)
@@ -50,6 +65,7 @@ func setupFactsTestApp(_ *testing.T) (*fiber.App, *MockFactsUseCase, context.Con
 
 	api := app.Group("/api/v1")
 	api.Get("/facts/random", handler.GetRandomFacts)
+	api.Get("/facts/today", handler.GetFactOfTheDay)
 
 	return app, factsUseCase, ctx
 }
@@ -227,3 +243,114 @@ func TestGetRandomFacts_InternalError(t *testing.T) {
 
 	factsUseCase.AssertExpectations(t)
 }
+
+func TestGetRandomFacts_WithFilters(t *testing.T) {
+	app, factsUseCase, _ := setupFactsTestApp(t)
+
+	facts := []domain.Fact{
+		{
+			ID:           "fact1",
+			RestaurantID: "restaurant1",
+			Content:      "Interesting fact 1",
+			CreatedAt:    time.Now(),
+		},
+	}
+	filter := domain.FactFilter{RestaurantID: "restaurant1", Cuisine: "italian"}
+
+	factsUseCase.On("GetFilteredRandomFacts", mock.Anything, filter, 2).Return(facts, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/facts/random?count=2&restaurant_id=restaurant1&cuisine=italian", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respFacts []domain.Fact
+	err = json.NewDecoder(resp.Body).Decode(&respFacts)
+	require.NoError(t, err)
+	assert.Len(t, respFacts, 1)
+	assert.Equal(t, "fact1", respFacts[0].ID)
+
+	factsUseCase.AssertExpectations(t)
+}
+
+func TestGetFactOfTheDay(t *testing.T) {
+	app, factsUseCase, _ := setupFactsTestApp(t)
+
+	fact := &domain.Fact{
+		ID:           "fact1",
+		RestaurantID: "restaurant1",
+		Content:      "Факт дня",
+		Locale:       "ru",
+		CreatedAt:    time.Now(),
+	}
+
+	factsUseCase.On("GetFactOfTheDay", mock.Anything, "ru").Return(fact, nil).Once()
+	factsUseCase.On("GetFactOfTheDay", mock.Anything, "ru-RU").Return(fact, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/facts/today?locale=ru", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respFact domain.Fact
+	err = json.NewDecoder(resp.Body).Decode(&respFact)
+	require.NoError(t, err)
+	assert.Equal(t, "fact1", respFact.ID)
+	assert.Equal(t, "ru", respFact.Locale)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/facts/today", nil)
+	req.Header.Set("Accept-Language", "ru-RU,ru;q=0.9,en;q=0.8")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	factsUseCase.AssertExpectations(t)
+}
+
+func TestGetFactOfTheDay_LocatedClient(t *testing.T) {
+	app := fiber.New()
+	factsUseCase := new(MockFactsUseCase)
+	handler := handlers.NewFactsHandler(factsUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	ctx = geo.NewContext(ctx, &geo.Location{CountryCode: "DE", Locale: "de"})
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/api/v1/facts/today", handler.GetFactOfTheDay)
+
+	fact := &domain.Fact{ID: "fact1", RestaurantID: "restaurant1", Content: "Tatsache", Locale: "de"}
+	factsUseCase.On("GetFactOfTheDay", mock.Anything, "de").Return(fact, nil).Once()
+	factsUseCase.On("GetFactOfTheDay", mock.Anything, "en").Return(fact, nil).Once()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/facts/today", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/facts/today", nil)
+	req.Header.Set("Accept-Language", "en")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	factsUseCase.AssertExpectations(t)
+}
+
+func TestGetFactOfTheDay_NoFacts(t *testing.T) {
+	app, factsUseCase, _ := setupFactsTestApp(t)
+
+	factsUseCase.On("GetFactOfTheDay", mock.Anything, "").Return(nil, usecase.ErrNoFactsAvailable)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/facts/today", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var respBody map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, common.ErrNoFactsAvailable, respBody["error"])
+
+	factsUseCase.AssertExpectations(t)
+}
@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGeoDefaultsApp(location *geo.Location) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewGeoHandler()
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	if location != nil {
+		ctx = geo.NewContext(ctx, location)
+	}
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/geo/defaults", handler.GetGeoDefaults)
+	return app
+}
+
+func getGeoDefaults(t *testing.T, app *fiber.App, req *http.Request) handlers.GeoDefaultsResponse {
+	t.Helper()
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var defaults handlers.GeoDefaultsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&defaults))
+	return defaults
+}
+
+func TestGetGeoDefaults(t *testing.T) {
+	app := newGeoDefaultsApp(&geo.Location{
+		CountryCode:    "DE",
+		City:           "Berlin",
+		Latitude:       52.52,
+		Longitude:      13.40,
+		HasCoordinates: true,
+		Currency:       "EUR",
+		Locale:         "de",
+	})
+
+	defaults := getGeoDefaults(t, app, httptest.NewRequest(http.MethodGet, "/geo/defaults", nil))
+	assert.True(t, defaults.Located)
+	assert.Equal(t, "Berlin", defaults.City)
+	require.NotNil(t, defaults.Latitude)
+	assert.InDelta(t, 52.52, *defaults.Latitude, 0.001)
+	assert.Equal(t, "EUR", defaults.Currency)
+	assert.Equal(t, "de", defaults.Locale)
+
+	req := httptest.NewRequest(http.MethodGet, "/geo/defaults?currency=usd", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "fr-FR,fr;q=0.9")
+	defaults = getGeoDefaults(t, app, req)
+	assert.Equal(t, "USD", defaults.Currency)
+	assert.Equal(t, "fr-FR", defaults.Locale)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/geo/defaults?currency=euro", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGetGeoDefaultsUnlocated(t *testing.T) {
+	defaults := getGeoDefaults(t, newGeoDefaultsApp(nil), httptest.NewRequest(http.MethodGet, "/geo/defaults", nil))
+	assert.False(t, defaults.Located)
+	assert.Nil(t, defaults.Latitude)
+	assert.Empty(t, defaults.Currency)
+	assert.Equal(t, "en", defaults.Locale)
+}
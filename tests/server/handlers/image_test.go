@@ -0,0 +1,198 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImageUseCase) UploadImage(ctx context.Context, restaurantID string, data []byte) (*domain.RestaurantImage, error) {
+	args := m.Called(ctx, restaurantID, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageUseCase) ListImages(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageUseCase) DeleteImage(ctx context.Context, restaurantID, imageID string) error {
+	args := m.Called(ctx, restaurantID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockImageUseCase) GetImageVariant(ctx context.Context, imageID string, opts imaging.Options, accepted []string) (*usecase.ImageVariant, error) {
+	args := m.Called(ctx, imageID, opts, accepted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImageVariant), args.Error(1)
+}
+
+func (m *MockImageUseCase) GenerateVariants(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func setupImageTestApp(_ *testing.T) (*fiber.App, *MockImageUseCase) {
+	app := fiber.New()
+	imageUseCase := new(MockImageUseCase)
+	handler := handlers.NewImageHandler(imageUseCase, "https://example.com/")
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	app.Get("/images/:id", handler.GetImage)
+	api := app.Group("/api/v1")
+	api.Post("/restaurants/:id/images", handler.UploadImage)
+	api.Get("/restaurants/:id/images", handler.ListImages)
+	api.Delete("/restaurants/:id/images/:imageId", handler.DeleteImage)
+
+	return app, imageUseCase
+}
+
+func TestGetImage(t *testing.T) {
+	app, imageUseCase := setupImageTestApp(t)
+
+	opts := imaging.Options{Width: 320, Height: 240, Fit: imaging.FitCover}
+	imageUseCase.On("GetImageVariant", mock.Anything, "image1", opts, []string(nil)).Return(&usecase.ImageVariant{
+		Key:         "image1_320x240_cover",
+		Data:        []byte("jpeg bytes"),
+		ContentType: "image/jpeg",
+	}, nil)
+	imageUseCase.On("GetImageVariant", mock.Anything, "missing", mock.Anything, mock.Anything).Return(nil, errors.New(common.ErrImageNotFound))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/images/image1?w=320&h=240&fit=cover", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/jpeg", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, "public, max-age=31536000, immutable", resp.Header.Get(fiber.HeaderCacheControl))
+	assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg bytes", string(body))
+
+	etag := resp.Header.Get(fiber.HeaderETag)
+	assert.Equal(t, `"image1_320x240_cover"`, etag)
+	req := httptest.NewRequest(http.MethodGet, "/images/image1?w=320&h=240&fit=cover", nil)
+	req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/images/missing?w=10", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderCacheControl))
+
+	for _, query := range []string{"w=abc", "w=-5", "h=0", "fit=zoom"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/images/image1?"+query, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+func TestGetImage_AcceptedFormats(t *testing.T) {
+	app, imageUseCase := setupImageTestApp(t)
+
+	imageUseCase.On("GetImageVariant", mock.Anything, "image1", imaging.Options{Fit: imaging.FitContain}, []string{"image/avif", "image/webp"}).Return(&usecase.ImageVariant{
+		Key:         "image1_0x0_contain_webp",
+		Data:        []byte("webp bytes"),
+		ContentType: "image/webp",
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/images/image1", nil)
+	req.Header.Set(fiber.HeaderAccept, "image/AVIF,image/webp;q=0.9,image/png;q=0,image/*;q=0.8,*/*;q=0.5")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/webp", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, `"image1_0x0_contain_webp"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+func TestUploadImage(t *testing.T) {
+	app, imageUseCase := setupImageTestApp(t)
+
+	imageUseCase.On("UploadImage", mock.Anything, "restaurant1", []byte("png bytes")).Return(&domain.RestaurantImage{
+		ID:           "image1",
+		RestaurantID: "restaurant1",
+		ContentType:  "image/png",
+		Width:        640,
+		Height:       480,
+		Size:         9,
+	}, nil)
+	imageUseCase.On("UploadImage", mock.Anything, "restaurant1", []byte("text")).Return(nil, usecase.ErrInvalidImage)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/images", strings.NewReader("png bytes"))
+	req.Header.Set(fiber.HeaderContentType, "image/png")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var image handlers.ImageResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&image))
+	assert.Equal(t, "https://example.com/images/image1", image.URL)
+	assert.Equal(t, 640, image.Width)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/images", strings.NewReader("text")))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/images", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestListAndDeleteImages(t *testing.T) {
+	app, imageUseCase := setupImageTestApp(t)
+
+	imageUseCase.On("ListImages", mock.Anything, "restaurant1").Return([]*domain.RestaurantImage{}, nil)
+	imageUseCase.On("DeleteImage", mock.Anything, "restaurant1", "image1").Return(nil)
+	imageUseCase.On("DeleteImage", mock.Anything, "restaurant1", "other").Return(errors.New(common.ErrImageNotFound))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/images", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/restaurant1/images/image1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/restaurant1/images/other", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,309 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMenuUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMenuUseCase) GetMenu(ctx context.Context, restaurantID string, filter domain.MenuFilter) ([]domain.MenuItem, error) {
+	args := m.Called(ctx, restaurantID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MenuItem), args.Error(1)
+}
+
+func (m *MockMenuUseCase) UpdateMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) ([]domain.MenuItem, error) {
+	args := m.Called(ctx, restaurantID, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MenuItem), args.Error(1)
+}
+
+func (m *MockMenuUseCase) ImportMenu(ctx context.Context, restaurantID string, r io.Reader, options usecase.MenuImportOptions) (*usecase.MenuImportReport, error) {
+	args := m.Called(ctx, restaurantID, r, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.MenuImportReport), args.Error(1)
+}
+
+func (m *MockMenuUseCase) GetPreOrder(ctx context.Context, booking *domain.Booking) (*domain.PreOrder, error) {
+	args := m.Called(ctx, booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PreOrder), args.Error(1)
+}
+
+func (m *MockMenuUseCase) UpdatePreOrder(ctx context.Context, bookingID domain.BookingID, items []domain.PreOrderItem) (*domain.PreOrder, error) {
+	args := m.Called(ctx, bookingID, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PreOrder), args.Error(1)
+}
+
+func setupMenuTestApp(_ *testing.T) (*fiber.App, *MockMenuUseCase) {
+	app := fiber.New()
+	menuUseCase := new(MockMenuUseCase)
+	handler := handlers.NewMenuHandler(menuUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	api := app.Group("/api/v1")
+	api.Get("/restaurants/:id/menu", handler.GetMenu)
+	api.Put("/restaurants/:id/menu", handler.UpdateMenu)
+	api.Post("/restaurants/:id/menu/import", handler.ImportMenu)
+	api.Put("/bookings/:id/pre-order", handler.UpdatePreOrder)
+
+	return app, menuUseCase
+}
+
+func TestGetMenu(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	menuUseCase.On("GetMenu", mock.Anything, "restaurant1", domain.MenuFilter{}).Return([]domain.MenuItem{
+		{ID: "item1", RestaurantID: "restaurant1", Name: "Borscht", Price: 45000, Currency: "RUB", IsAvailable: true},
+	}, nil)
+	menuUseCase.On("GetMenu", mock.Anything, "missing", mock.Anything).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/menu", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var items []handlers.MenuItemResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+	require.Len(t, items, 1)
+	assert.Equal(t, int64(45000), items[0].Price)
+	assert.Equal(t, "RUB", items[0].Currency)
+	assert.Equal(t, "450.00 RUB", items[0].PriceFormatted)
+	assert.Equal(t, []domain.Allergen{}, items[0].Allergens)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/missing/menu", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	menuUseCase.AssertExpectations(t)
+}
+
+func TestGetMenu_Filters(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	menuUseCase.On("GetMenu", mock.Anything, "restaurant1", domain.MenuFilter{
+		ExcludeAllergens: []domain.Allergen{domain.AllergenMilk, domain.AllergenTreeNuts},
+		DietaryLabels:    []domain.DietaryLabel{domain.DietaryVegan},
+	}).Return([]domain.MenuItem{
+		{ID: "item1", Name: "Salad", DietaryLabels: []domain.DietaryLabel{domain.DietaryVegan}},
+	}, nil)
+	menuUseCase.On("GetMenu", mock.Anything, "restaurant1", domain.MenuFilter{
+		DietaryLabels: []domain.DietaryLabel{"paleo"},
+	}).Return(nil, fmt.Errorf("%w: unknown dietary label \"paleo\"", usecase.ErrInvalidMenuFilter))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/menu?exclude_allergens=milk,+tree_nuts&dietary_labels=vegan", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var items []handlers.MenuItemResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+	require.Len(t, items, 1)
+	assert.Equal(t, []domain.DietaryLabel{domain.DietaryVegan}, items[0].DietaryLabels)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/menu?dietary_labels=paleo", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	menuUseCase.AssertExpectations(t)
+}
+
+func TestUpdateMenu_Errors(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	menuUseCase.On("UpdateMenu", mock.Anything, "restaurant1", mock.Anything).
+		Return(nil, fmt.Errorf("%w: item 1 has no name", usecase.ErrInvalidMenuItem)).Once()
+	menuUseCase.On("UpdateMenu", mock.Anything, "restaurant2", mock.Anything).
+		Return(nil, tenant.ErrAccessDenied).Once()
+
+	body := `{"items":[{"name":"","price":100}]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/restaurant1/menu", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/restaurant2/menu", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	menuUseCase.AssertExpectations(t)
+}
+
+func TestUpdatePreOrder(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	late, soldOut := "0190f3b2-7c1e-7a4d-9b2f-00000000000a", "0190f3b2-7c1e-7a4d-9b2f-00000000000b"
+	items := []domain.PreOrderItem{{MenuItemID: "item1", Quantity: 2, Notes: "no dill"}}
+	menuUseCase.On("UpdatePreOrder", mock.Anything, domain.BookingID(bookingPathID), items).Return(&domain.PreOrder{
+		BookingID:      "booking1",
+		Items:          []domain.PreOrderItem{{MenuItemID: "item1", Name: "Borscht", UnitPrice: 45000, Currency: "RUB", Quantity: 2, Notes: "no dill"}},
+		EstimatedTotal: 90000,
+		Currency:       "RUB",
+		EditableUntil:  time.Now().Add(time.Hour),
+		Editable:       true,
+	}, nil)
+	menuUseCase.On("UpdatePreOrder", mock.Anything, domain.BookingID(late), mock.Anything).Return(nil, usecase.ErrPreOrderClosed)
+	menuUseCase.On("UpdatePreOrder", mock.Anything, domain.BookingID(missingBookingPathID), mock.Anything).
+		Return(nil, fmt.Errorf("%s: %w", common.ErrBookingNotFound, errors.New("no rows")))
+	menuUseCase.On("UpdatePreOrder", mock.Anything, domain.BookingID(soldOut), mock.Anything).
+		Return(nil, fmt.Errorf("%s: %w", common.ErrSavePreOrder, &domain.PreOrderLimitError{
+			MenuItemID: "item1", Name: "Borscht", Date: time.Now(), Limit: 10, Reserved: 9, Delta: 2,
+		}))
+
+	body := `{"items":[{"menu_item_id":"item1","quantity":2,"notes":"no dill"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/bookings/"+bookingPathID+"/pre-order", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var preOrder handlers.PreOrderResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&preOrder))
+	assert.Equal(t, int64(90000), preOrder.EstimatedTotal)
+	assert.Equal(t, "900.00 RUB", preOrder.EstimatedTotalFormatted)
+	assert.Equal(t, "450.00 RUB", preOrder.Items[0].UnitPriceFormatted)
+	assert.True(t, preOrder.Editable)
+
+	for id, status := range map[string]int{late: http.StatusConflict, soldOut: http.StatusConflict, missingBookingPathID: http.StatusNotFound} {
+		req = httptest.NewRequest(http.MethodPut, "/api/v1/bookings/"+id+"/pre-order", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, id)
+	}
+
+	menuUseCase.AssertExpectations(t)
+}
+
+func TestGetBooking_IncludesPreOrder(t *testing.T) {
+	app := fiber.New()
+	bookingUseCase := new(MockBookingUseCase)
+	receiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	handler := handlers.NewBookingHandler(bookingUseCase, receiptUseCase, menuUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/api/v1/bookings/:id", handler.GetBooking)
+
+	booking := &domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1", Status: domain.BookingStatusConfirmed}
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(booking, nil)
+	receiptUseCase.On("GetBookingDeliveries", mock.Anything, booking).Return([]domain.NotificationDelivery{}, nil)
+	menuUseCase.On("GetPreOrder", mock.Anything, booking).Return(&domain.PreOrder{
+		BookingID:      "booking1",
+		Items:          []domain.PreOrderItem{{MenuItemID: "item1", Name: "Borscht", UnitPrice: 45000, Quantity: 2}},
+		EstimatedTotal: 90000,
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var details handlers.BookingDetails
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+	require.NotNil(t, details.PreOrder)
+	assert.Equal(t, int64(90000), details.PreOrder.EstimatedTotal)
+	require.Len(t, details.PreOrder.Items, 1)
+	assert.Equal(t, "Borscht", details.PreOrder.Items[0].Name)
+
+	menuUseCase.AssertExpectations(t)
+}
+
+func TestGetMenu_EmptyMenuIsEmptyList(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	menuUseCase.On("GetMenu", mock.Anything, "restaurant1", mock.Anything).Return([]domain.MenuItem(nil), nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/menu", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(body))
+}
+
+func TestImportMenu(t *testing.T) {
+	app, menuUseCase := setupMenuTestApp(t)
+
+	menuUseCase.On("ImportMenu", mock.Anything, "restaurant1", mock.Anything,
+		usecase.MenuImportOptions{Format: usecase.MenuImportJSON, Replace: true}).
+		Return(&usecase.MenuImportReport{Total: 1, Created: 1}, nil).Once()
+	menuUseCase.On("ImportMenu", mock.Anything, "restaurant1", mock.Anything,
+		usecase.MenuImportOptions{Format: usecase.MenuImportCSV, DryRun: true}).
+		Return(&usecase.MenuImportReport{Total: 1, DryRun: true, Errors: []usecase.MenuImportRowError{
+			{Row: 2, Errors: []string{"price is required"}},
+		}}, nil).Once()
+	menuUseCase.On("ImportMenu", mock.Anything, "restaurant1", mock.Anything,
+		usecase.MenuImportOptions{Format: "xml"}).
+		Return(nil, fmt.Errorf("%w: unknown format \"xml\"", usecase.ErrInvalidImportFile)).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/menu/import?replace=true",
+		strings.NewReader(`[{"name":"Tea","price":100}]`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/menu/import?dry_run=true",
+		strings.NewReader("name,price\nTea,\n"))
+	req.Header.Set("Content-Type", "text/csv")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	var report usecase.MenuImportReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, 2, report.Errors[0].Row)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/menu/import?format=XML", strings.NewReader("<menu/>")))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	menuUseCase.AssertExpectations(t)
+}
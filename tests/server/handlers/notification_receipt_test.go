@@ -0,0 +1,186 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationReceiptUseCase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationReceiptUseCase) TrackRead(ctx context.Context, notificationID string) error {
+	args := m.Called(ctx, notificationID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationReceiptUseCase) ConfirmRead(ctx context.Context, notificationID string) (*domain.Notification, error) {
+	args := m.Called(ctx, notificationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationReceiptUseCase) GetBookingDeliveries(ctx context.Context, booking *domain.Booking) ([]domain.NotificationDelivery, error) {
+	args := m.Called(ctx, booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.NotificationDelivery), args.Error(1)
+}
+
+func setupNotificationReceiptTestApp(_ *testing.T) (*fiber.App, *MockNotificationReceiptUseCase) {
+	app := fiber.New()
+	receiptUseCase := new(MockNotificationReceiptUseCase)
+	handler := handlers.NewNotificationReceiptHandler(receiptUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	api := app.Group("/api/v1")
+	api.Post("/notifications/:id/read", handler.ConfirmNotificationRead)
+	api.Get("/notifications/:id/pixel.gif", handler.TrackNotificationRead)
+
+	return app, receiptUseCase
+}
+
+func TestTrackNotificationRead_ReturnsPixel(t *testing.T) {
+	app, receiptUseCase := setupNotificationReceiptTestApp(t)
+
+	receiptUseCase.On("TrackRead", mock.Anything, "notification1").Return(nil)
+	receiptUseCase.On("TrackRead", mock.Anything, "unknown").Return(fmt.Errorf("%s: %w", common.ErrNotificationNotFound, errors.New("no rows")))
+
+	for _, id := range []string{"notification1", "unknown"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/notifications/"+id+"/pixel.gif", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "unknown notifications are not revealed")
+		assert.Equal(t, "image/gif", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Cache-Control"), "no-store")
+
+		img, err := gif.Decode(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, 1, img.Bounds().Dx())
+		assert.Equal(t, 1, img.Bounds().Dy())
+	}
+
+	receiptUseCase.AssertExpectations(t)
+}
+
+func TestConfirmNotificationRead(t *testing.T) {
+	app, receiptUseCase := setupNotificationReceiptTestApp(t)
+
+	readAt := time.Now()
+	receiptUseCase.On("ConfirmRead", mock.Anything, "notification1").Return(&domain.Notification{ID: "notification1", IsRead: true, ReadAt: &readAt}, nil)
+	receiptUseCase.On("ConfirmRead", mock.Anything, "missing").Return(nil, fmt.Errorf("%s: %w", common.ErrNotificationNotFound, errors.New("no rows")))
+	receiptUseCase.On("ConfirmRead", mock.Anything, "foreign").Return(nil, &tenant.IsolationError{Resource: "notification", ResourceID: "foreign"})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/notifications/notification1/read", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var notification domain.Notification
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&notification))
+	assert.True(t, notification.IsRead)
+	assert.NotNil(t, notification.ReadAt)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/notifications/missing/read", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/notifications/foreign/read", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	receiptUseCase.AssertExpectations(t)
+}
+
+func TestGetBooking_IncludesNotificationDeliveries(t *testing.T) {
+	app := fiber.New()
+	bookingUseCase := new(MockBookingUseCase)
+	receiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	menuUseCase.On("GetPreOrder", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	handler := handlers.NewBookingHandler(bookingUseCase, receiptUseCase, menuUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/api/v1/bookings/:id", handler.GetBooking)
+
+	booking := &domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1", Status: domain.BookingStatusConfirmed}
+	deliveredAt := time.Now()
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(booking, nil)
+	receiptUseCase.On("GetBookingDeliveries", mock.Anything, booking).Return([]domain.NotificationDelivery{
+		{NotificationID: "n1", RecipientType: domain.RecipientTypeUser, Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelInApp, Status: domain.DeliveryStatusRead, DeliveredAt: &deliveredAt, ReadAt: &deliveredAt},
+		{NotificationID: "n2", RecipientType: domain.RecipientTypeUser, Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelSMS, Status: domain.DeliveryStatusDelivered, DeliveredAt: &deliveredAt},
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var details handlers.BookingDetails
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+	assert.Equal(t, "booking1", details.ID)
+	assert.Equal(t, domain.BookingStatusConfirmed, details.Status)
+	require.Len(t, details.Notifications, 2)
+	assert.Equal(t, domain.DeliveryStatusRead, details.Notifications[0].Status)
+	assert.Equal(t, domain.NotificationChannelSMS, details.Notifications[1].Channel)
+
+	bookingUseCase.AssertExpectations(t)
+	receiptUseCase.AssertExpectations(t)
+}
+
+func TestGetBooking_AnswersWithoutUnreadableDeliveries(t *testing.T) {
+	app := fiber.New()
+	bookingUseCase := new(MockBookingUseCase)
+	receiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	menuUseCase.On("GetPreOrder", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	handler := handlers.NewBookingHandler(bookingUseCase, receiptUseCase, menuUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/api/v1/bookings/:id", handler.GetBooking)
+
+	booking := &domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1"}
+	bookingUseCase.On("GetBooking", mock.Anything, domain.BookingID(bookingPathID)).Return(booking, nil)
+	receiptUseCase.On("GetBookingDeliveries", mock.Anything, booking).Return(nil, errors.New("database error"))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/bookings/"+bookingPathID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var details handlers.BookingDetails
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+	assert.Equal(t, "booking1", details.ID)
+	assert.Empty(t, details.Notifications)
+}
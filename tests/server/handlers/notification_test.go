@@ -0,0 +1,160 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupNotificationTestApp(_ *testing.T) (*fiber.App, *MockNotificationUseCase) {
+	app := fiber.New()
+	notificationUseCase := new(MockNotificationUseCase)
+	handler := handlers.NewNotificationHandler(notificationUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	api := app.Group("/api/v1")
+	api.Post("/notifications/:id/resend", handler.ResendNotification)
+	api.Get("/users/:id/notification-settings", handler.GetUserNotificationSettings)
+	api.Put("/users/:id/notification-settings", handler.UpdateUserNotificationSettings)
+	api.Get("/restaurants/:id/notification-settings", handler.GetRestaurantNotificationSettings)
+	api.Put("/restaurants/:id/notification-settings", handler.UpdateRestaurantNotificationSettings)
+
+	return app, notificationUseCase
+}
+
+func TestResendNotification_Success(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	notificationUseCase.On("ResendNotification", mock.Anything, "notification1").Return(nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/notifications/notification1/resend", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	notificationUseCase.AssertExpectations(t)
+}
+
+func TestResendNotification_NotFound(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	notFoundErr := errors.New(common.ErrNotificationNotFound + ": notification not found")
+	notificationUseCase.On("ResendNotification", mock.Anything, "missing").Return(notFoundErr)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/notifications/missing/resend", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var respBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, common.ErrNotificationNotFound, respBody["error"])
+}
+
+func TestGetUserNotificationSettings_Success(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	settings := &domain.NotificationSettings{
+		RecipientType: domain.RecipientTypeUser,
+		RecipientID:   "user1",
+		Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelEmail, Enabled: false},
+		},
+	}
+	notificationUseCase.On("GetNotificationSettings", mock.Anything, domain.RecipientTypeUser, "user1").Return(settings, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/users/user1/notification-settings", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result domain.NotificationSettings
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, *settings, result)
+
+	notificationUseCase.AssertExpectations(t)
+}
+
+func TestUpdateRestaurantNotificationSettings_Success(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	notificationUseCase.On("UpdateNotificationSettings", mock.Anything, mock.MatchedBy(func(s *domain.NotificationSettings) bool {
+		return s.RecipientType == domain.RecipientTypeRestaurant && s.RecipientID == "rest1" &&
+			len(s.Preferences) == 1 && s.Preferences[0].Channel == domain.NotificationChannelSMS && s.Preferences[0].Enabled
+	})).Return(&domain.NotificationSettings{RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest1"}, nil)
+
+	body := `{"preferences":[{"type":"new_booking","channel":"sms","enabled":true}]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/rest1/notification-settings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	notificationUseCase.AssertExpectations(t)
+}
+
+func TestUpdateUserNotificationSettings_InvalidPreference(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	notificationUseCase.On("UpdateNotificationSettings", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: unknown channel %q", usecase.ErrInvalidNotificationPreference, "fax"))
+
+	body := `{"preferences":[{"type":"new_booking","channel":"fax","enabled":true}]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user1/notification-settings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestUpdateUserNotificationSettings_QuietHours(t *testing.T) {
+	app, notificationUseCase := setupNotificationTestApp(t)
+
+	quietHours := []domain.QuietHours{{Channel: domain.NotificationChannelEmail, Start: "22:00", End: "08:00"}}
+	notificationUseCase.On("UpdateNotificationSettings", mock.Anything, mock.MatchedBy(func(s *domain.NotificationSettings) bool {
+		return s.RecipientID == "user1" && assert.ObjectsAreEqual(quietHours, s.QuietHours)
+	})).Return(&domain.NotificationSettings{RecipientType: domain.RecipientTypeUser, RecipientID: "user1", QuietHours: quietHours}, nil)
+	notificationUseCase.On("UpdateNotificationSettings", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: %s is listed twice", usecase.ErrInvalidQuietHours, domain.NotificationChannelEmail))
+
+	body := `{"preferences":[],"quiet_hours":[{"channel":"email","start":"22:00","end":"08:00"}]}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user1/notification-settings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result domain.NotificationSettings
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, quietHours, result.QuietHours)
+
+	body = `{"quiet_hours":[{"channel":"email","start":"22:00","end":"08:00"},{"channel":"email","start":"23:00","end":"07:00"}]}`
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/users/user1/notification-settings", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBookingLinkUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingLinkUseCase) CreateBookingLink(ctx context.Context, bookingID domain.BookingID, options usecase.BookingLinkOptions) (*usecase.IssuedBookingLink, error) {
+	args := m.Called(ctx, bookingID, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.IssuedBookingLink), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) IssueConfirmationLinks(ctx context.Context, bookingID domain.BookingID) (*usecase.IssuedBookingLink, *usecase.IssuedBookingLink, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*usecase.IssuedBookingLink), args.Get(1).(*usecase.IssuedBookingLink), args.Error(2)
+}
+
+func (m *MockBookingLinkUseCase) ResolveBookingLink(ctx context.Context, token string) (*usecase.ResolvedBookingLink, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ResolvedBookingLink), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) UseBookingLink(ctx context.Context, token string, action domain.BookingLinkAction, fn func(ctx context.Context, booking *domain.Booking) error) (*domain.Booking, error) {
+	args := m.Called(ctx, token, action, fn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) CheckInToken(ctx context.Context, bookingID domain.BookingID) (string, error) {
+	args := m.Called(ctx, bookingID)
+	return args.String(0), args.Error(1)
+}
+
+func setupQRTestApp(_ *testing.T) (*fiber.App, *MockRestaurantUseCase, *MockBookingLinkUseCase) {
+	app := fiber.New()
+	restaurantUseCase := new(MockRestaurantUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	handler := handlers.NewQRHandler(restaurantUseCase, bookingLinkUseCase, "https://example.com/")
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	app.Get("/restaurants/:id/qr", handler.GetRestaurantQR)
+	app.Get("/bookings/:id/qr", handler.GetBookingQR)
+
+	return app, restaurantUseCase, bookingLinkUseCase
+}
+
+func TestGetBookingQR(t *testing.T) {
+	t.Run("png", func(t *testing.T) {
+		app, _, bookingLinkUseCase := setupQRTestApp(t)
+		bookingLinkUseCase.On("CheckInToken", mock.Anything, domain.BookingID(bookingPathID)).Return("ci.booking1.signature", nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/bookings/"+bookingPathID+"/qr", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "private, max-age=86400", resp.Header.Get("Cache-Control"))
+		assert.NotEmpty(t, resp.Header.Get("ETag"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(body), "\x89PNG"))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		app, _, bookingLinkUseCase := setupQRTestApp(t)
+		bookingLinkUseCase.On("CheckInToken", mock.Anything, domain.BookingID(bookingPathID)).Return("ci.booking1.signature", nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/bookings/"+bookingPathID+"/qr?format=svg", nil))
+		require.NoError(t, err)
+		etag := resp.Header.Get("ETag")
+
+		req := httptest.NewRequest(http.MethodGet, "/bookings/"+bookingPathID+"/qr?format=svg", nil)
+		req.Header.Set("If-None-Match", etag)
+		resp, err = app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/bookings/"+bookingPathID+"/qr?format=png", nil))
+		require.NoError(t, err)
+		assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		app, _, _ := setupQRTestApp(t)
+
+		for _, query := range []string{"format=gif", "scale=0", "scale=33", "scale=big"} {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/bookings/"+bookingPathID+"/qr?"+query, nil))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		app, _, bookingLinkUseCase := setupQRTestApp(t)
+		bookingLinkUseCase.On("CheckInToken", mock.Anything, domain.BookingID(missingBookingPathID)).Return("", errors.New(common.ErrBookingNotFound))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/bookings/"+missingBookingPathID+"/qr", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestGetRestaurantQR(t *testing.T) {
+	t.Run("svg", func(t *testing.T) {
+		app, restaurantUseCase, _ := setupQRTestApp(t)
+		restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{ID: restaurantPathID, Slug: "pushkin"}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/restaurants/"+restaurantPathID+"/qr?format=svg&scale=4", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+		assert.Equal(t, "public, max-age=3600", resp.Header.Get("Cache-Control"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(body), "<svg"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		app, restaurantUseCase, _ := setupQRTestApp(t)
+		restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/restaurants/"+missingRestaurantPathID+"/qr", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
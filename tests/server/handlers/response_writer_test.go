@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+func getRestaurantAs(t *testing.T, app *fiber.App, path, accept string) (*http.Response, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set(fiber.HeaderAccept, accept)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func TestGetRestaurant_ContentNegotiation(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{
+		ID:     restaurantPathID,
+		Name:   "Pelmennaya",
+		IsTest: true,
+		Facts:  []domain.Fact{{ID: "fact1", Content: "Opened in 1912"}},
+	}, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	t.Run("json by default", func(t *testing.T) {
+		resp, body := getRestaurantAs(t, app, "/api/v1/restaurants/"+restaurantPathID, "")
+
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+		assert.Contains(t, string(body), `"name":"Pelmennaya"`)
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		resp, body := getRestaurantAs(t, app, "/api/v1/restaurants/"+restaurantPathID, "application/xml")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationXML, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, fiber.HeaderAccept, resp.Header.Get(fiber.HeaderVary))
+
+		var restaurant struct {
+			XMLName xml.Name `xml:"response"`
+			ID      string   `xml:"id"`
+			Name    string   `xml:"name"`
+			IsTest  bool     `xml:"is_test"`
+			Facts   []string `xml:"facts>item>content"`
+		}
+		require.NoError(t, xml.Unmarshal(body, &restaurant), string(body))
+		assert.Equal(t, restaurantPathID, restaurant.ID)
+		assert.Equal(t, "Pelmennaya", restaurant.Name)
+		assert.True(t, restaurant.IsTest)
+		assert.Equal(t, []string{"Opened in 1912"}, restaurant.Facts)
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		resp, body := getRestaurantAs(t, app, "/api/v1/restaurants/"+restaurantPathID, "application/x-msgpack")
+
+		assert.Equal(t, handlers.MIMEApplicationXMsgPack, resp.Header.Get(fiber.HeaderContentType))
+
+		decoded, _, err := msgp.ReadIntfBytes(body)
+		require.NoError(t, err)
+		restaurant, ok := decoded.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Pelmennaya", restaurant["name"])
+		assert.Equal(t, true, restaurant["is_test"])
+		assert.Len(t, restaurant["facts"], 1)
+	})
+
+	t.Run("errors are negotiated too", func(t *testing.T) {
+		resp, body := getRestaurantAs(t, app, "/api/v1/restaurants/"+missingRestaurantPathID, "application/xml")
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, string(body), "<error>"+common.ErrRestaurantNotFound+"</error>")
+	})
+
+	t.Run("unsupported types fall back to json", func(t *testing.T) {
+		resp, _ := getRestaurantAs(t, app, "/api/v1/restaurants/"+restaurantPathID, "text/csv")
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	})
+}
+
+type keyValueEncoder struct{}
+
+func (keyValueEncoder) MediaTypes() []string {
+	return []string{"text/x-key-value"}
+}
+
+func (keyValueEncoder) Encode(body any) ([]byte, error) {
+	fields, ok := body.(map[string]any)
+	if !ok {
+		return nil, errors.New("not an object")
+	}
+	return []byte(fmt.Sprintf("id=%v", fields["id"])), nil
+}
+
+func TestRegisterResponseEncoder(t *testing.T) {
+	handlers.RegisterResponseEncoder(keyValueEncoder{})
+
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{ID: restaurantPathID}, nil)
+
+	resp, body := getRestaurantAs(t, app, "/api/v1/restaurants/"+restaurantPathID, "text/x-key-value")
+
+	assert.Equal(t, "text/x-key-value", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, "id="+restaurantPathID, string(body))
+}
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +16,8 @@ import (
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/assert"
@@ -21,11 +25,18 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// restaurantPathID and missingRestaurantPathID are the restaurants of the request paths, which the
+// handlers parse as UUIDs.
+const (
+	restaurantPathID        = "0190f3b2-5a4c-7d1e-8f2a-3b4c5d6e7f80"
+	missingRestaurantPathID = "0190f3b2-5a4c-7d1e-8f2a-000000000000"
+)
+
 type MockRestaurantUseCase struct {
 	mock.Mock
 }
 
-func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -33,11 +44,44 @@ func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id string) (*
 	return args.Get(0).(*domain.Restaurant), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) ListRestaurants(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
-	args := m.Called(ctx, offset, limit)
+func (m *MockRestaurantUseCase) GetRestaurantBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ListRestaurants(
+	ctx context.Context,
+	filter domain.RestaurantFilter,
+	page usecase.PageRequest,
+) (*usecase.PageResponse[*domain.Restaurant], error) {
+	args := m.Called(ctx, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Restaurant]), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) SearchRestaurants(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, offset, limit)
 	return args.Get(0).([]*domain.Restaurant), args.Error(1)
 }
 
+func (m *MockRestaurantUseCase) EstimateRestaurantCount(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) GetSchedule(ctx context.Context, restaurantID domain.RestaurantID, from, to time.Time) ([]domain.ScheduleDay, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ScheduleDay), args.Error(1)
+}
+
 func (m *MockRestaurantUseCase) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
 	args := m.Called(ctx, restaurant)
 	return args.String(0), args.Error(1)
@@ -48,20 +92,20 @@ func (m *MockRestaurantUseCase) UpdateRestaurant(ctx context.Context, restaurant
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) DeleteRestaurant(ctx context.Context, id string) error {
+func (m *MockRestaurantUseCase) DeleteRestaurant(ctx context.Context, id domain.RestaurantID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) AddFact(ctx context.Context, restaurantID string, content string) (*domain.Fact, error) {
-	args := m.Called(ctx, restaurantID, content)
+func (m *MockRestaurantUseCase) AddFact(ctx context.Context, restaurantID domain.RestaurantID, content, locale string) (*domain.Fact, error) {
+	args := m.Called(ctx, restaurantID, content, locale)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Fact), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *MockRestaurantUseCase) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
@@ -71,40 +115,132 @@ func (m *MockRestaurantUseCase) GetRandomFacts(ctx context.Context, count int) (
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID string, workingHours *domain.WorkingHours) error {
+func (m *MockRestaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID, workingHours *domain.WorkingHours) error {
 	args := m.Called(ctx, restaurantID, workingHours)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (m *MockRestaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]*domain.WorkingHours), args.Error(1)
 }
 
+func (m *MockRestaurantUseCase) SetSpecialDay(ctx context.Context, day *domain.SpecialDay) error {
+	args := m.Called(ctx, day)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantUseCase) ListSpecialDays(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.SpecialDay, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SpecialDay), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) DeleteSpecialDay(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) error {
+	args := m.Called(ctx, restaurantID, date)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantUseCase) ImportRestaurants(ctx context.Context, r io.Reader, dryRun bool) (*usecase.RestaurantImportReport, error) {
+	args := m.Called(ctx, r, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RestaurantImportReport), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ExportRestaurant(ctx context.Context, id domain.RestaurantID) (*usecase.RestaurantBundle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RestaurantBundle), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ImportRestaurantBundle(ctx context.Context, bundle *usecase.RestaurantBundle) (string, error) {
+	args := m.Called(ctx, bundle)
+	return args.String(0), args.Error(1)
+}
+
 type MockAvailabilityUseCase struct {
 	mock.Mock
 }
 
-func (m *MockAvailabilityUseCase) GetAvailability(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (m *MockAvailabilityUseCase) GetAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	args := m.Called(ctx, restaurantID, date)
 	return args.Get(0).([]*domain.Availability), args.Error(1)
 }
 
+func (m *MockAvailabilityUseCase) GetAvailabilityDelta(ctx context.Context, restaurantID domain.RestaurantID, since domain.SyncCursor, limit int) (*usecase.AvailabilityDelta, error) {
+	args := m.Called(ctx, restaurantID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AvailabilityDelta), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) GetSlotBookings(ctx context.Context, restaurantID, availabilityID string) ([]*domain.Booking, error) {
+	args := m.Called(ctx, restaurantID, availabilityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) DeleteAvailability(ctx context.Context, restaurantID, availabilityID string) error {
+	args := m.Called(ctx, restaurantID, availabilityID)
+	return args.Error(0)
+}
+
 func (m *MockAvailabilityUseCase) SetAvailability(ctx context.Context, availability *domain.Availability) error {
 	args := m.Called(ctx, availability)
 	return args.Error(0)
 }
 
+func (m *MockAvailabilityUseCase) ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	args := m.Called(ctx, availability)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
 func (m *MockAvailabilityUseCase) UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error {
 	args := m.Called(ctx, availabilityID, delta)
 	return args.Error(0)
 }
 
-func (m *MockAvailabilityUseCase) CheckAvailability(ctx context.Context, restaurantID string, date time.Time, timeSlot string, guestsCount int) (bool, error) {
+func (m *MockAvailabilityUseCase) ReconcileReservedSeats(ctx context.Context) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) ReservedSeatsReport(ctx context.Context, date time.Time, fix bool) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx, date, fix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) CheckAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time, timeSlot string, guestsCount int) (bool, error) {
 	args := m.Called(ctx, restaurantID, date, timeSlot, guestsCount)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockAvailabilityUseCase) GenerateAvailability(ctx context.Context, params usecase.GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Availability), args.Error(1)
+}
+
 func setupRestaurantTestApp(_ *testing.T) (*fiber.App, *MockRestaurantUseCase, *MockBookingUseCase, *MockAvailabilityUseCase, context.Context) {
 	app := fiber.New()
 	restaurantUseCase := new(MockRestaurantUseCase)
@@ -124,16 +260,29 @@ func setupRestaurantTestApp(_ *testing.T) (*fiber.App, *MockRestaurantUseCase, *
 	api := app.Group("/api/v1")
 	api.Get("/restaurants", handler.ListRestaurants)
 	api.Post("/restaurants", handler.CreateRestaurant)
+	api.Get("/restaurants/search", handler.SearchRestaurants)
+	api.Get("/restaurants/by-slug/:slug", handler.GetRestaurantBySlug)
 	api.Get("/restaurants/:id", handler.GetRestaurant)
 	api.Put("/restaurants/:id", handler.UpdateRestaurant)
 	api.Delete("/restaurants/:id", handler.DeleteRestaurant)
 	api.Get("/restaurants/:id/facts", handler.GetFacts)
 	api.Post("/restaurants/:id/facts", handler.AddFact)
 	api.Get("/restaurants/:id/working-hours", handler.GetWorkingHours)
+	api.Get("/restaurants/:id/schedule", handler.GetSchedule)
+	api.Put("/restaurants/:id/special-days/:date", handler.SetSpecialDay)
+	api.Delete("/restaurants/:id/special-days/:date", handler.DeleteSpecialDay)
 	api.Post("/restaurants/:id/working-hours", handler.SetWorkingHours)
 	api.Get("/restaurants/:id/availability", handler.GetAvailability)
+	api.Get("/restaurants/:id/availability/delta", handler.GetAvailabilityDelta)
+	api.Get("/restaurants/:id/availability/:availabilityId/bookings", handler.GetSlotBookings)
+	api.Delete("/restaurants/:id/availability/:availabilityId", handler.DeleteAvailability)
 	api.Post("/restaurants/:id/availability", handler.SetAvailability)
+	api.Post("/restaurants/:id/availability/generate", handler.GenerateAvailability)
 	api.Get("/restaurants/:id/bookings", handler.GetRestaurantBookings)
+	api.Post("/admin/restaurants/import", handler.ImportRestaurants)
+	api.Get("/admin/reconciliation", handler.ReservedSeatsReport)
+	api.Get("/restaurants/:id/export", handler.ExportRestaurant)
+	api.Post("/restaurants/import", handler.ImportRestaurantBundle)
 
 	return app, restaurantUseCase, bookingUseCase, availabilityUseCase, ctx
 }
@@ -144,7 +293,7 @@ func TestListRestaurants_Success(t *testing.T) {
 	currentTime := time.Now()
 	restaurants := []*domain.Restaurant{
 		{
-			ID:           "restaurant1",
+			ID:           restaurantPathID,
 			Name:         "Restaurant 1",
 			Address:      "123 Main St",
 			Cuisine:      "Italian",
@@ -167,7 +316,9 @@ func TestListRestaurants_Success(t *testing.T) {
 		},
 	}
 
-	restaurantUseCase.On("ListRestaurants", mock.Anything, 0, 20).Return(restaurants, nil)
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Limit: 20}).
+		Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants}, nil)
+	restaurantUseCase.On("EstimateRestaurantCount", mock.Anything).Return(int64(2), nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants", nil)
 	resp, err := app.Test(req)
@@ -201,12 +352,15 @@ func TestListRestaurants_WithPagination(t *testing.T) {
 		},
 	}
 
-	restaurantUseCase.On("ListRestaurants", mock.Anything, 10, 5).Return(restaurants, nil)
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: "after-restaurant2", Limit: 1}).
+		Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants, NextCursor: "after-restaurant3"}, nil)
+	restaurantUseCase.On("EstimateRestaurantCount", mock.Anything).Return(int64(11), nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?offset=10&limit=5", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?cursor=after-restaurant2&limit=1", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "after-restaurant3", resp.Header.Get(middleware.HeaderNextCursor))
 
 	var respRestaurants []*domain.Restaurant
 	err = json.NewDecoder(resp.Body).Decode(&respRestaurants)
@@ -217,10 +371,139 @@ func TestListRestaurants_WithPagination(t *testing.T) {
 	restaurantUseCase.AssertExpectations(t)
 }
 
+func TestListRestaurants_RejectsOffsetAndInvalidCursor(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: "10", Limit: 20}).
+		Return(nil, usecase.ErrInvalidPageCursor)
+
+	for query, expected := range map[string]string{
+		"?offset=10": common.ErrOffsetPagination,
+		"?cursor=10": common.ErrInvalidPageCursor,
+		"?limit=ten": common.ErrInvalidParams,
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants"+query, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+
+		var respBody map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Equal(t, expected, respBody["error"], query)
+	}
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestListRestaurants_HasNextAndEstimatedTotal(t *testing.T) {
+	restaurantUseCase := new(MockRestaurantUseCase)
+	handler := handlers.NewRestaurantHandler(restaurantUseCase, new(MockBookingUseCase), new(MockAvailabilityUseCase))
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Use(middleware.EnvelopeMiddleware(true))
+	app.Get("/api/v1/restaurants", handler.ListRestaurants)
+
+	restaurants := []*domain.Restaurant{{ID: restaurantPathID}, {ID: "restaurant2"}, {ID: "restaurant3"}}
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Limit: 2}).
+		Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants[:2], NextCursor: "after-restaurant2"}, nil)
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: "after-restaurant2", Limit: 2}).
+		Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants[2:]}, nil)
+	restaurantUseCase.On("EstimateRestaurantCount", mock.Anything).Return(int64(-1), nil).Once()
+	restaurantUseCase.On("EstimateRestaurantCount", mock.Anything).Return(int64(3), nil).Once()
+
+	var body struct {
+		Data []handlers.RestaurantResponse `json:"data"`
+		Meta struct {
+			Pagination middleware.Pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?limit=2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Data, 2)
+	assert.True(t, body.Meta.Pagination.HasNext)
+	assert.Equal(t, 2, body.Meta.Pagination.Count)
+	assert.Equal(t, "after-restaurant2", body.Meta.Pagination.NextCursor)
+	assert.Nil(t, body.Meta.Pagination.EstimatedTotal, "no estimate before the table is analyzed")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?cursor="+body.Meta.Pagination.NextCursor+"&limit=2", nil))
+	require.NoError(t, err)
+	body.Meta.Pagination = middleware.Pagination{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Data, 1)
+	assert.False(t, body.Meta.Pagination.HasNext)
+	assert.Empty(t, body.Meta.Pagination.NextCursor)
+	assert.Empty(t, resp.Header.Get(middleware.HeaderNextCursor))
+	require.NotNil(t, body.Meta.Pagination.EstimatedTotal)
+	assert.Equal(t, int64(3), *body.Meta.Pagination.EstimatedTotal)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestListRestaurants_AdultOnly(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurants := []*domain.Restaurant{{ID: "bar", Name: "Bar", IsAdultOnly: true}}
+	restaurantUseCase.On("ListRestaurants", mock.Anything, mock.MatchedBy(func(filter domain.RestaurantFilter) bool {
+		return filter.CityID == "city1" && filter.AdultOnly != nil && *filter.AdultOnly
+	}), usecase.PageRequest{Limit: 20}).Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?city_id=city1&adult_only=true", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respRestaurants []handlers.RestaurantResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respRestaurants))
+	require.Len(t, respRestaurants, 1)
+	assert.True(t, respRestaurants[0].IsAdultOnly)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants?adult_only=maybe", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestSearchRestaurants(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurants := []*domain.Restaurant{{ID: "pasta", Name: "Pasta", Cuisine: domain.Cuisine("italian")}}
+	restaurantUseCase.On("SearchRestaurants", mock.Anything, mock.MatchedBy(func(filter domain.RestaurantFilter) bool {
+		return filter.Query == "fresh pasta" && filter.Cuisine == domain.Cuisine("italian") && filter.Address == "main" &&
+			filter.OpenAt != nil && filter.LiveOnly
+	}), 0, 21).Return(restaurants, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/search?q=fresh+pasta&cuisine=italian&address=main&open_now=true", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respRestaurants []handlers.RestaurantResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respRestaurants))
+	require.Len(t, respRestaurants, 1)
+	assert.Equal(t, "pasta", respRestaurants[0].ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/search?open_now=soon", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
 func TestListRestaurants_InternalError(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("ListRestaurants", mock.Anything, 0, 20).Return([]*domain.Restaurant{}, errors.New("database error"))
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Limit: 20}).
+		Return(nil, errors.New("database error"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants", nil)
 	resp, err := app.Test(req)
@@ -240,7 +523,7 @@ func TestGetRestaurant_Success(t *testing.T) {
 
 	currentTime := time.Now()
 	expectedRestaurant := &domain.Restaurant{
-		ID:           "restaurant1",
+		ID:           restaurantPathID,
 		Name:         "Restaurant 1",
 		Address:      "123 Main St",
 		Cuisine:      "Italian",
@@ -251,9 +534,9 @@ func TestGetRestaurant_Success(t *testing.T) {
 		UpdatedAt:    currentTime,
 	}
 
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "restaurant1").Return(expectedRestaurant, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(expectedRestaurant, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -271,9 +554,9 @@ func TestGetRestaurant_Success(t *testing.T) {
 func TestGetRestaurant_NotFound(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "nonexistent").Return(nil, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/nonexistent", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+missingRestaurantPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
@@ -289,9 +572,9 @@ func TestGetRestaurant_NotFound(t *testing.T) {
 func TestGetRestaurant_InternalError(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "restaurant1").Return(nil, errors.New("database error"))
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(nil, errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -304,6 +587,72 @@ func TestGetRestaurant_InternalError(t *testing.T) {
 	restaurantUseCase.AssertExpectations(t)
 }
 
+func TestGetRestaurant_InvalidID(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	for _, path := range []string{"/api/v1/restaurants/restaurant1", "/api/v1/restaurants/restaurant1/schedule", "/api/v1/restaurants/restaurant1/export"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+
+		var respBody map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+		assert.Contains(t, respBody["error"], domain.ErrInvalidID.Error(), path)
+	}
+
+	restaurantUseCase.AssertNotCalled(t, "GetRestaurant", mock.Anything, mock.Anything)
+	restaurantUseCase.AssertNotCalled(t, "GetSchedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	restaurantUseCase.AssertNotCalled(t, "ExportRestaurant", mock.Anything, mock.Anything)
+}
+
+func TestGetRestaurantBySlug_Success(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurant := &domain.Restaurant{ID: restaurantPathID, Name: "Pasta Place", Slug: "pasta-place"}
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "pasta-place").Return(restaurant, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/by-slug/pasta-place", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respRestaurant domain.Restaurant
+	err = json.NewDecoder(resp.Body).Decode(&respRestaurant)
+	require.NoError(t, err)
+	assert.Equal(t, restaurantPathID, respRestaurant.ID)
+	assert.Equal(t, "pasta-place", respRestaurant.Slug)
+}
+
+func TestGetRestaurantBySlug_RedirectsPreviousSlug(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurant := &domain.Restaurant{ID: restaurantPathID, Name: "Pasta Place", Slug: "pasta-place"}
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "pasta").Return(restaurant, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/by-slug/pasta", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode)
+	assert.Equal(t, "/api/v1/restaurants/by-slug/pasta-place", resp.Header.Get("Location"))
+}
+
+func TestGetRestaurantBySlug_Errors(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "unknown").Return(nil, errors.New(common.ErrRestaurantNotFound))
+	restaurantUseCase.On("GetRestaurantBySlug", mock.Anything, "Bad_Slug").Return(nil, usecase.ErrInvalidRestaurantSlug)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/by-slug/unknown", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/by-slug/Bad_Slug", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
 func TestCreateRestaurant_Success(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
@@ -329,8 +678,8 @@ func TestCreateRestaurant_Success(t *testing.T) {
 		CreatedAt:    time.Now(),
 	}
 
-	restaurantUseCase.On("AddFact", mock.Anything, "restaurant123", "Amazing pizza").Return(fact1, nil)
-	restaurantUseCase.On("AddFact", mock.Anything, "restaurant123", "Fresh ingredients").Return(fact2, nil)
+	restaurantUseCase.On("AddFact", mock.Anything, domain.RestaurantID("restaurant123"), "Amazing pizza", "").Return(fact1, nil)
+	restaurantUseCase.On("AddFact", mock.Anything, domain.RestaurantID("restaurant123"), "Fresh ingredients", "").Return(fact2, nil)
 
 	reqBody := handlers.CreateRestaurantRequest{
 		Name:         "Test Restaurant",
@@ -413,7 +762,7 @@ func TestUpdateRestaurant_Success(t *testing.T) {
 
 	currentTime := time.Now()
 	existingRestaurant := &domain.Restaurant{
-		ID:           "restaurant1",
+		ID:           restaurantPathID,
 		Name:         "Old Restaurant Name",
 		Address:      "Old Address",
 		Cuisine:      "Italian",
@@ -424,9 +773,9 @@ func TestUpdateRestaurant_Success(t *testing.T) {
 		UpdatedAt:    currentTime,
 	}
 
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "restaurant1").Return(existingRestaurant, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(existingRestaurant, nil)
 	restaurantUseCase.On("UpdateRestaurant", mock.Anything, mock.MatchedBy(func(restaurant *domain.Restaurant) bool {
-		return restaurant.ID == "restaurant1" &&
+		return restaurant.ID == restaurantPathID &&
 			restaurant.Name == "Updated Restaurant" &&
 			restaurant.Address == "456 New St" &&
 			string(restaurant.Cuisine) == "Mexican" &&
@@ -443,7 +792,7 @@ func TestUpdateRestaurant_Success(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/restaurant1", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+restaurantPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -458,10 +807,63 @@ func TestUpdateRestaurant_Success(t *testing.T) {
 	restaurantUseCase.AssertExpectations(t)
 }
 
+func TestUpdateRestaurant_KeepsTestModeWhenOmitted(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurant := &domain.Restaurant{ID: restaurantPathID, Name: "Sandbox", Slug: "sandbox", IsTest: true}
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(restaurant, nil)
+	restaurantUseCase.On("UpdateRestaurant", mock.Anything, mock.MatchedBy(func(r *domain.Restaurant) bool {
+		return r.IsTest
+	})).Return(nil)
+
+	reqJSON := []byte(`{"name":"Sandbox","address":"456 New St","cuisine":"Mexican","contact_email":"sandbox@example.com","contact_phone":"+70987654321"}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+restaurantPathID, bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestUpdateRestaurant_SlugTaken(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurant := &domain.Restaurant{ID: restaurantPathID, Name: "Restaurant 1", Slug: "restaurant-1"}
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(restaurant, nil)
+	restaurantUseCase.On("UpdateRestaurant", mock.Anything, mock.MatchedBy(func(r *domain.Restaurant) bool {
+		return r.Slug == "pasta"
+	})).Return(usecase.ErrRestaurantSlugTaken)
+
+	reqBody := handlers.UpdateRestaurantRequest{
+		Name:         "Restaurant 1",
+		Slug:         "pasta",
+		Address:      "456 New St",
+		Cuisine:      "Mexican",
+		ContactEmail: "updated@example.com",
+		ContactPhone: "+70987654321",
+	}
+	reqJSON, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+restaurantPathID, bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var respBody map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.ErrRestaurantSlugTaken.Error(), respBody["error"])
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
 func TestUpdateRestaurant_NotFound(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "nonexistent").Return(nil, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, nil)
 
 	reqBody := handlers.UpdateRestaurantRequest{
 		Name:         "Updated Restaurant",
@@ -473,7 +875,7 @@ func TestUpdateRestaurant_NotFound(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/nonexistent", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+missingRestaurantPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -491,9 +893,9 @@ func TestUpdateRestaurant_NotFound(t *testing.T) {
 func TestDeleteRestaurant_Success(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("DeleteRestaurant", mock.Anything, "restaurant1").Return(nil)
+	restaurantUseCase.On("DeleteRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(nil)
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/restaurant1", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -509,9 +911,9 @@ func TestDeleteRestaurant_Success(t *testing.T) {
 func TestDeleteRestaurant_InternalError(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("DeleteRestaurant", mock.Anything, "restaurant1").Return(errors.New("database error"))
+	restaurantUseCase.On("DeleteRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/restaurant1", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -528,26 +930,26 @@ func TestAddFact_Success(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
 	// Проверяем существование ресторана
-	restaurantUseCase.On("GetRestaurant", mock.Anything, "restaurant1").Return(&domain.Restaurant{
-		ID: "restaurant1",
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{
+		ID: restaurantPathID,
 	}, nil)
 
 	// Создаем объект факта, который будет возвращен
 	createdFact := &domain.Fact{
 		ID:           "fact123",
-		RestaurantID: "restaurant1",
+		RestaurantID: restaurantPathID,
 		Content:      "New interesting fact",
 		CreatedAt:    time.Now(),
 	}
 
-	restaurantUseCase.On("AddFact", mock.Anything, "restaurant1", "New interesting fact").Return(createdFact, nil)
+	restaurantUseCase.On("AddFact", mock.Anything, domain.RestaurantID(restaurantPathID), "New interesting fact", "").Return(createdFact, nil)
 
 	reqBody := handlers.AddFactRequest{
 		Content: "New interesting fact",
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/facts", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/facts", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -558,7 +960,7 @@ func TestAddFact_Success(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&respBody)
 	require.NoError(t, err)
 	assert.Equal(t, "fact123", respBody.ID)
-	assert.Equal(t, "restaurant1", respBody.RestaurantID)
+	assert.Equal(t, restaurantPathID, respBody.RestaurantID)
 	assert.Equal(t, "New interesting fact", respBody.Content)
 
 	restaurantUseCase.AssertExpectations(t)
@@ -571,21 +973,22 @@ func TestGetFacts_Success(t *testing.T) {
 	facts := []domain.Fact{
 		{
 			ID:           "fact1",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			Content:      "Interesting fact 1",
 			CreatedAt:    currentTime,
 		},
 		{
 			ID:           "fact2",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			Content:      "Interesting fact 2",
 			CreatedAt:    currentTime,
 		},
 	}
 
-	restaurantUseCase.On("GetFacts", mock.Anything, "restaurant1").Return(facts, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{ID: restaurantPathID}, nil)
+	restaurantUseCase.On("GetFacts", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(facts, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/facts", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/facts", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -603,8 +1006,8 @@ func TestGetFacts_Success(t *testing.T) {
 func TestSetWorkingHours_Success(t *testing.T) {
 	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
 
-	restaurantUseCase.On("SetWorkingHours", mock.Anything, "restaurant1", mock.MatchedBy(func(wh *domain.WorkingHours) bool {
-		return wh.RestaurantID == "restaurant1" &&
+	restaurantUseCase.On("SetWorkingHours", mock.Anything, domain.RestaurantID(restaurantPathID), mock.MatchedBy(func(wh *domain.WorkingHours) bool {
+		return wh.RestaurantID == restaurantPathID &&
 			wh.WeekDay == domain.Monday &&
 			wh.OpenTime == "09:00" &&
 			wh.CloseTime == "22:00"
@@ -622,7 +1025,7 @@ func TestSetWorkingHours_Success(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/working-hours", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/working-hours", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -646,7 +1049,7 @@ func TestGetWorkingHours_Success(t *testing.T) {
 	workingHours := []*domain.WorkingHours{
 		{
 			ID:           "wh1",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			WeekDay:      domain.Monday,
 			OpenTime:     "09:00",
 			CloseTime:    "22:00",
@@ -655,7 +1058,7 @@ func TestGetWorkingHours_Success(t *testing.T) {
 		},
 		{
 			ID:           "wh2",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			WeekDay:      domain.Tuesday,
 			OpenTime:     "09:00",
 			CloseTime:    "22:00",
@@ -664,9 +1067,10 @@ func TestGetWorkingHours_Success(t *testing.T) {
 		},
 	}
 
-	restaurantUseCase.On("GetWorkingHours", mock.Anything, "restaurant1").Return(workingHours, nil)
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(&domain.Restaurant{ID: restaurantPathID}, nil)
+	restaurantUseCase.On("GetWorkingHours", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(workingHours, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/working-hours", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/working-hours", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -681,11 +1085,99 @@ func TestGetWorkingHours_Success(t *testing.T) {
 	restaurantUseCase.AssertExpectations(t)
 }
 
+func TestGetSchedule(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	from := time.Date(2025, time.June, 9, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.June, 10, 0, 0, 0, 0, time.UTC)
+	restaurantUseCase.On("GetSchedule", mock.Anything, domain.RestaurantID(restaurantPathID), from, to).Return([]domain.ScheduleDay{
+		{Date: from, Hours: []domain.OpeningHours{{OpensAt: from.Add(18 * time.Hour), ClosesAt: to.Add(2 * time.Hour)}}},
+		{Date: to, Closure: &domain.RestaurantClosure{StartsOn: to, ReopensOn: to.AddDate(0, 0, 7), Message: "On vacation"}},
+		{Date: to.AddDate(0, 0, 1), Hours: []domain.OpeningHours{}, SpecialDay: &domain.SpecialDay{Date: to.AddDate(0, 0, 1), IsClosed: true, Note: "Inventory"}},
+	}, nil)
+	restaurantUseCase.On("GetSchedule", mock.Anything, domain.RestaurantID(restaurantPathID), to, from).Return(nil, usecase.ErrInvalidDateRange)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/schedule?from=2025-06-09&to=2025-06-10", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var schedule []handlers.ScheduleDayResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&schedule))
+	require.Len(t, schedule, 3)
+	assert.Equal(t, "2025-06-09", schedule[0].Date)
+	assert.False(t, schedule[0].Closed)
+	require.Len(t, schedule[0].Hours, 1)
+	assert.True(t, schedule[0].Hours[0].ClosesAt.Equal(to.Add(2*time.Hour)))
+	assert.Empty(t, schedule[0].ReopensOn)
+	assert.True(t, schedule[1].Closed)
+	assert.Equal(t, "2025-06-17", schedule[1].ReopensOn)
+	assert.Equal(t, "On vacation", schedule[1].ClosureMessage)
+	assert.False(t, schedule[1].SpecialDay)
+	assert.True(t, schedule[2].Closed)
+	assert.True(t, schedule[2].SpecialDay)
+	assert.Equal(t, "Inventory", schedule[2].SpecialDayNote)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/schedule?from=2025-06-10&to=2025-06-09", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/schedule?from=June", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestSpecialDays(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	date := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	restaurantUseCase.On("SetSpecialDay", mock.Anything, mock.MatchedBy(func(day *domain.SpecialDay) bool {
+		return day.RestaurantID == restaurantPathID && day.Date.Equal(date) && day.OpenTime == "18:00" && day.CloseTime == "03:00"
+	})).Return(nil).Once()
+	restaurantUseCase.On("DeleteSpecialDay", mock.Anything, domain.RestaurantID(restaurantPathID), date).Return(nil).Once()
+	restaurantUseCase.On("DeleteSpecialDay", mock.Anything, domain.RestaurantID(restaurantPathID), date.AddDate(0, 0, 1)).Return(errors.New(common.ErrSpecialDayNotFound)).Once()
+
+	body, err := json.Marshal(handlers.SetSpecialDayRequest{OpenTime: "18:00", CloseTime: "03:00", Note: "New Year's Eve"})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+restaurantPathID+"/special-days/2025-12-31", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var day handlers.SpecialDayResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&day))
+	assert.Equal(t, "2025-12-31", day.Date)
+	assert.Equal(t, "New Year's Eve", day.Note)
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v1/restaurants/"+restaurantPathID+"/special-days/December", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID+"/special-days/2025-12-31", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID+"/special-days/2026-01-01", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
 func TestSetAvailability_Success(t *testing.T) {
 	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
 
 	availabilityUseCase.On("SetAvailability", mock.Anything, mock.MatchedBy(func(a *domain.Availability) bool {
-		return a.RestaurantID == "restaurant1" &&
+		return a.RestaurantID == restaurantPathID &&
 			a.TimeSlot == "19:00" &&
 			a.Capacity == 20
 	})).Return(nil)
@@ -699,7 +1191,7 @@ func TestSetAvailability_Success(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/availability", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability", bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -714,6 +1206,372 @@ func TestSetAvailability_Success(t *testing.T) {
 	availabilityUseCase.AssertExpectations(t)
 }
 
+func TestSetAvailability_CapacityConflict(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	impacted := []*domain.Booking{{ID: "booking1", Time: "19:00", GuestsCount: 6, Status: domain.BookingStatusConfirmed}}
+	availabilityUseCase.On("SetAvailability", mock.Anything, mock.Anything).Return(&usecase.CapacityConflictError{
+		Availability:     &domain.Availability{TimeSlot: "19:00", Capacity: 4, Reserved: 6},
+		ImpactedBookings: impacted,
+	})
+
+	reqJSON, _ := json.Marshal(handlers.SetAvailabilityRequest{Date: time.Now(), TimeSlot: "19:00", Capacity: 4})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var respBody handlers.CapacityConflictResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, common.ErrCapacityBelowReserved, respBody.Error)
+	assert.Equal(t, 6, respBody.Reserved)
+	require.Len(t, respBody.ImpactedBookings, 1)
+	assert.Equal(t, "booking1", respBody.ImpactedBookings[0].ID)
+}
+
+func TestGetSlotBookings(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	occupying := []*domain.Booking{{ID: "booking1", Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed}}
+	availabilityUseCase.On("GetSlotBookings", mock.Anything, restaurantPathID, "slot1").Return(occupying, nil)
+	availabilityUseCase.On("GetSlotBookings", mock.Anything, restaurantPathID, "slot2").Return(nil, errors.New(common.ErrAvailabilityNotFound))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/availability/slot1/bookings", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respBody []handlers.BookingResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	require.Len(t, respBody, 1)
+	assert.Equal(t, "booking1", respBody[0].ID)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/availability/slot2/bookings", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDeleteAvailability(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	availabilityUseCase.On("DeleteAvailability", mock.Anything, restaurantPathID, "empty").Return(nil)
+	availabilityUseCase.On("DeleteAvailability", mock.Anything, restaurantPathID, "busy").Return(&usecase.AvailabilityInUseError{
+		Availability: &domain.Availability{TimeSlot: "19:00", Capacity: 4, Reserved: 2},
+		Bookings:     []*domain.Booking{{ID: "booking1", Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusPending}},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID+"/availability/empty", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/restaurants/"+restaurantPathID+"/availability/busy", nil)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var respBody handlers.AvailabilityInUseResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, 2, respBody.Reserved)
+	require.Len(t, respBody.Bookings, 1)
+	assert.Equal(t, "booking1", respBody.Bookings[0].ID)
+
+	availabilityUseCase.AssertExpectations(t)
+}
+
+func TestSetAvailability_Force(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	impacted := []*domain.Booking{{ID: "booking1", Time: "19:00", GuestsCount: 6, Status: domain.BookingStatusConfirmed}}
+	availabilityUseCase.On("ForceSetAvailability", mock.Anything, mock.MatchedBy(func(a *domain.Availability) bool {
+		return a.Capacity == 4
+	})).Return(impacted, nil)
+
+	reqJSON, _ := json.Marshal(handlers.SetAvailabilityRequest{Date: time.Now(), TimeSlot: "19:00", Capacity: 4})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability?force=true", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var respBody struct {
+		Status           string            `json:"status"`
+		ImpactedBookings []*domain.Booking `json:"impacted_bookings"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Len(t, respBody.ImpactedBookings, 1)
+
+	availabilityUseCase.AssertNotCalled(t, "SetAvailability", mock.Anything, mock.Anything)
+}
+
+func TestGenerateAvailability_Success(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	from := time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC)
+	generated := []*domain.Availability{
+		{ID: "a1", RestaurantID: restaurantPathID, Date: from, TimeSlot: "18:00", Capacity: 20},
+		{ID: "a2", RestaurantID: restaurantPathID, Date: from, TimeSlot: "19:30", Capacity: 20},
+	}
+
+	availabilityUseCase.On("GenerateAvailability", mock.Anything, usecase.GenerateAvailabilityParams{
+		RestaurantID: restaurantPathID,
+		From:         from,
+		To:           from.AddDate(0, 0, 6),
+		SlotDuration: 90 * time.Minute,
+		Capacity:     20,
+	}).Return(generated, nil)
+
+	reqJSON, _ := json.Marshal(handlers.GenerateAvailabilityRequest{
+		From:        "2025-04-14",
+		To:          "2025-04-20",
+		SlotMinutes: 90,
+		Capacity:    20,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability/generate", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var respBody []domain.Availability
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Len(t, respBody, 2)
+
+	availabilityUseCase.AssertExpectations(t)
+}
+
+func TestGenerateAvailability_DryRun(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	from := time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC)
+	generated := []*domain.Availability{
+		{RestaurantID: restaurantPathID, Date: from, TimeSlot: "18:00", Capacity: 20},
+	}
+
+	availabilityUseCase.On("GenerateAvailability", mock.Anything, usecase.GenerateAvailabilityParams{
+		RestaurantID: restaurantPathID,
+		From:         from,
+		To:           from,
+		SlotDuration: time.Hour,
+		Capacity:     20,
+		DryRun:       true,
+	}).Return(generated, nil)
+
+	reqJSON, _ := json.Marshal(handlers.GenerateAvailabilityRequest{
+		From:        "2025-04-14",
+		To:          "2025-04-14",
+		SlotMinutes: 60,
+		Capacity:    20,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability/generate?dry_run=true", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	availabilityUseCase.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability/generate?dry_run=maybe", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestGenerateAvailability_InvalidRange(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	availabilityUseCase.On("GenerateAvailability", mock.Anything, mock.Anything).Return(nil, usecase.ErrInvalidDateRange)
+
+	reqJSON, _ := json.Marshal(handlers.GenerateAvailabilityRequest{
+		From:        "2025-04-20",
+		To:          "2025-04-14",
+		SlotMinutes: 60,
+		Capacity:    20,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/"+restaurantPathID+"/availability/generate", bytes.NewBuffer(reqJSON))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestExportRestaurant_Success(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	bundle := &usecase.RestaurantBundle{
+		Version:      usecase.RestaurantBundleVersion,
+		Restaurant:   &domain.Restaurant{ID: restaurantPathID, Name: "Pasta"},
+		WorkingHours: []*domain.WorkingHours{{WeekDay: domain.Monday, OpenTime: "10:00", CloseTime: "22:00"}},
+	}
+	restaurantUseCase.On("ExportRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).Return(bundle, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/export", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "restaurant-"+restaurantPathID+".json")
+
+	var respBody usecase.RestaurantBundle
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Equal(t, "Pasta", respBody.Restaurant.Name)
+	assert.Len(t, respBody.WorkingHours, 1)
+}
+
+func TestExportRestaurant_NotFound(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("ExportRestaurant", mock.Anything, domain.RestaurantID(missingRestaurantPathID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+missingRestaurantPathID+"/export", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestImportRestaurantBundle(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{name: "created", expectedStatus: http.StatusCreated},
+		{name: "already exists", err: usecase.ErrRestaurantAlreadyExists, expectedStatus: http.StatusConflict},
+		{name: "unsupported version", err: usecase.ErrUnsupportedBundleVersion, expectedStatus: http.StatusBadRequest},
+		{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+			restaurantUseCase.On("ImportRestaurantBundle", mock.Anything, mock.MatchedBy(func(b *usecase.RestaurantBundle) bool {
+				return b.Restaurant != nil && b.Restaurant.ID == restaurantPathID
+			})).Return(restaurantPathID, tc.err)
+
+			reqJSON, _ := json.Marshal(usecase.RestaurantBundle{
+				Version:    usecase.RestaurantBundleVersion,
+				Restaurant: &domain.Restaurant{ID: restaurantPathID, Name: "Pasta", Address: "Main st. 1"},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/restaurants/import", bytes.NewBuffer(reqJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestImportRestaurants_Success(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	csvBody := "name,address,cuisine,contact_email,contact_phone\nPasta,Main st. 1,italian,pasta@example.com,+100\n"
+	restaurantUseCase.On("ImportRestaurants", mock.Anything, mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == csvBody
+	}), false).Return(&usecase.RestaurantImportReport{Total: 1, Imported: 1, RestaurantIDs: []string{"r1"}}, nil)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "restaurants.csv")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(csvBody))
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/restaurants/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var report usecase.RestaurantImportReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, []string{"r1"}, report.RestaurantIDs)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestImportRestaurants_RowErrors(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("ImportRestaurants", mock.Anything, mock.Anything, true).Return(&usecase.RestaurantImportReport{
+		Total:  1,
+		DryRun: true,
+		Errors: []usecase.RestaurantImportRowError{{Row: 2, Errors: []string{"name is required"}}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/restaurants/import?dry_run=true", bytes.NewBufferString("name,address\n,x\n"))
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	restaurantUseCase.AssertExpectations(t)
+}
+
+func TestImportRestaurants_InvalidFile(t *testing.T) {
+	app, restaurantUseCase, _, _, _ := setupRestaurantTestApp(t)
+
+	restaurantUseCase.On("ImportRestaurants", mock.Anything, mock.Anything, false).Return(nil, usecase.ErrInvalidImportFile)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/restaurants/import", bytes.NewBufferString(""))
+	req.Header.Set("Content-Type", "text/csv")
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReservedSeatsReport_Success(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	date := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	mismatches := []domain.ReservedSeatsDrift{{AvailabilityID: "avail1", RestaurantID: "rest1", Date: date, TimeSlot: "19:00", Recorded: 6, Actual: 2}}
+	availabilityUseCase.On("ReservedSeatsReport", mock.Anything, date, true).Return(mismatches, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/reconciliation?date=2025-05-10&fix=true", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result handlers.ReservedSeatsReportResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.True(t, result.Fixed)
+	require.Len(t, result.Mismatches, 1)
+	assert.Equal(t, 6, result.Mismatches[0].Recorded)
+	assert.Equal(t, 2, result.Mismatches[0].Actual)
+
+	availabilityUseCase.AssertExpectations(t)
+}
+
+func TestReservedSeatsReport_InvalidDate(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/reconciliation?date=10.05.2025", nil)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	availabilityUseCase.AssertNotCalled(t, "ReservedSeatsReport", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetAvailability_Success(t *testing.T) {
 	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
 
@@ -723,7 +1581,7 @@ func TestGetAvailability_Success(t *testing.T) {
 	availabilities := []*domain.Availability{
 		{
 			ID:           "a1",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			Date:         date,
 			TimeSlot:     "18:00",
 			Capacity:     20,
@@ -731,7 +1589,7 @@ func TestGetAvailability_Success(t *testing.T) {
 		},
 		{
 			ID:           "a2",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			Date:         date,
 			TimeSlot:     "19:00",
 			Capacity:     20,
@@ -739,11 +1597,11 @@ func TestGetAvailability_Success(t *testing.T) {
 		},
 	}
 
-	availabilityUseCase.On("GetAvailability", mock.Anything, "restaurant1", mock.MatchedBy(func(d time.Time) bool {
+	availabilityUseCase.On("GetAvailability", mock.Anything, domain.RestaurantID(restaurantPathID), mock.MatchedBy(func(d time.Time) bool {
 		return d.Format("2006-01-02") == dateStr
 	})).Return(availabilities, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/availability?date="+dateStr, nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/availability?date="+dateStr, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -758,6 +1616,52 @@ func TestGetAvailability_Success(t *testing.T) {
 	availabilityUseCase.AssertExpectations(t)
 }
 
+func TestGetAvailabilityDelta(t *testing.T) {
+	app, _, _, availabilityUseCase, _ := setupRestaurantTestApp(t)
+
+	since := domain.SyncCursor{ChangedAt: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC), ID: "a1"}
+	changedAt := time.Date(2025, 5, 1, 11, 0, 0, 0, time.UTC)
+	delta := &usecase.AvailabilityDelta{
+		Slots:   []*domain.Availability{{ID: "a2", RestaurantID: restaurantPathID, TimeSlot: "19:00", Capacity: 20, Reserved: 12, UpdatedAt: changedAt}},
+		Version: domain.SyncCursor{ChangedAt: changedAt, ID: "a2"},
+		HasMore: true,
+	}
+	availabilityUseCase.On("GetAvailabilityDelta", mock.Anything, domain.RestaurantID(restaurantPathID), since, 50).Return(delta, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/availability/delta?since_version="+since.String()+"&limit=50", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body handlers.AvailabilityDeltaResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Slots, 1)
+	assert.Equal(t, 12, body.Slots[0].Reserved)
+	assert.Equal(t, delta.Version.String(), body.Version)
+	assert.True(t, body.HasMore)
+
+	t.Run("invalid version", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+restaurantPathID+"/availability/delta?since_version=not*a*version", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("restaurant not found", func(t *testing.T) {
+		availabilityUseCase.On("GetAvailabilityDelta", mock.Anything, domain.RestaurantID(missingRestaurantPathID), domain.SyncCursor{}, usecase.DefaultSyncLimit).
+			Return(nil, errors.New(common.ErrRestaurantNotFound)).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+missingRestaurantPathID+"/availability/delta", nil)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	availabilityUseCase.AssertExpectations(t)
+}
+
+const bookingsRestaurantID = "9c4d2b1e-3f6a-4e8b-a7d5-0e1f2a3b4c5d"
+
 func TestGetRestaurantBookings_Success(t *testing.T) {
 	app, _, bookingUseCase, _, _ := setupRestaurantTestApp(t)
 
@@ -765,7 +1669,7 @@ func TestGetRestaurantBookings_Success(t *testing.T) {
 	bookings := []*domain.Booking{
 		{
 			ID:           "booking1",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			UserID:       "user1",
 			Date:         currentTime,
 			Time:         "18:00",
@@ -778,7 +1682,7 @@ func TestGetRestaurantBookings_Success(t *testing.T) {
 		},
 		{
 			ID:           "booking2",
-			RestaurantID: "restaurant1",
+			RestaurantID: restaurantPathID,
 			UserID:       "user2",
 			Date:         currentTime.Add(24 * time.Hour),
 			Time:         "19:00",
@@ -790,12 +1694,14 @@ func TestGetRestaurantBookings_Success(t *testing.T) {
 		},
 	}
 
-	bookingUseCase.On("GetRestaurantBookings", mock.Anything, "restaurant1").Return(bookings, nil)
+	bookingUseCase.On("GetRestaurantBookings", mock.Anything, domain.RestaurantID(bookingsRestaurantID), domain.BookingFilter{}, usecase.PageRequest{Limit: 2}).
+		Return(&usecase.PageResponse[*domain.Booking]{Items: bookings, NextCursor: "after-booking2"}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/bookings", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+bookingsRestaurantID+"/bookings?limit=2", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "after-booking2", resp.Header.Get(middleware.HeaderNextCursor))
 
 	var respBookings []*domain.Booking
 	err = json.NewDecoder(resp.Body).Decode(&respBookings)
@@ -807,14 +1713,57 @@ func TestGetRestaurantBookings_Success(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
-func TestGetRestaurantBookings_InternalError(t *testing.T) {
+func TestGetRestaurantBookings_Filters(t *testing.T) {
 	app, _, bookingUseCase, _, _ := setupRestaurantTestApp(t)
 
-	bookingUseCase.On("GetRestaurantBookings", mock.Anything, "restaurant1").Return([]*domain.Booking{}, errors.New("database error"))
+	date := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	bookingUseCase.On("GetRestaurantBookings", mock.Anything, domain.RestaurantID(bookingsRestaurantID), domain.BookingFilter{
+		Status:   domain.BookingStatusConfirmed,
+		Date:     &date,
+		Occasion: domain.BookingOccasionBirthday,
+	}, usecase.PageRequest{Cursor: "after-booking0", Limit: 20}).Return(&usecase.PageResponse[*domain.Booking]{
+		Items: []*domain.Booking{{ID: "booking1", Occasion: domain.BookingOccasionBirthday}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+bookingsRestaurantID+"/bookings?status=confirmed&date=2025-05-01&occasion=birthday&cursor=after-booking0", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var respBookings []*domain.Booking
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBookings))
+	require.Len(t, respBookings, 1)
+	assert.Equal(t, domain.BookingOccasionBirthday, respBookings[0].Occasion)
+
+	for _, query := range []string{"status=archived", "occasion=graduation", "date=01.05.2025", "offset=20"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+bookingsRestaurantID+"/bookings?"+query, nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	bookingUseCase.AssertExpectations(t)
+}
+
+func TestGetRestaurantBookings_InvalidID(t *testing.T) {
+	app, _, bookingUseCase, _, _ := setupRestaurantTestApp(t)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/bookings", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	bookingUseCase.AssertNotCalled(t, "GetRestaurantBookings", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetRestaurantBookings_InternalError(t *testing.T) {
+	app, _, bookingUseCase, _, _ := setupRestaurantTestApp(t)
+
+	bookingUseCase.On("GetRestaurantBookings", mock.Anything, domain.RestaurantID(bookingsRestaurantID), domain.BookingFilter{}, usecase.PageRequest{Limit: 20}).
+		Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/"+bookingsRestaurantID+"/bookings", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
 
 	var respBody map[string]string
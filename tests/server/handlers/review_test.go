@@ -0,0 +1,267 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReviewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReviewUseCase) CreateReview(ctx context.Context, bookingID domain.BookingID, rating int, text string) (*domain.Review, error) {
+	args := m.Called(ctx, bookingID, rating, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ListReviews(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ReplyToReview(ctx context.Context, restaurantID, reviewID, text string) (*domain.Review, error) {
+	args := m.Called(ctx, restaurantID, reviewID, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) FlagReview(ctx context.Context, restaurantID, reviewID, reason string) (*domain.ReviewFlag, error) {
+	args := m.Called(ctx, restaurantID, reviewID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ListReviewFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	args := m.Called(ctx, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ResolveReviewFlag(ctx context.Context, flagID string, status domain.ReviewFlagStatus, note string) (*domain.ReviewFlag, error) {
+	args := m.Called(ctx, flagID, status, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewFlag), args.Error(1)
+}
+
+func setupReviewTestApp(_ *testing.T) (*fiber.App, *MockReviewUseCase) {
+	app := fiber.New()
+	reviewUseCase := new(MockReviewUseCase)
+	handler := handlers.NewReviewHandler(reviewUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+
+	api := app.Group("/api/v1")
+	api.Post("/bookings/:id/review", handler.CreateReview)
+	api.Get("/restaurants/:id/reviews", handler.ListReviews)
+	api.Put("/restaurants/:id/reviews/:reviewId/reply", handler.ReplyToReview)
+	api.Post("/restaurants/:id/reviews/:reviewId/flag", handler.FlagReview)
+	api.Get("/admin/review-flags", handler.ListReviewFlags)
+	api.Post("/admin/review-flags/:id/resolve", handler.ResolveReviewFlag)
+
+	return app, reviewUseCase
+}
+
+func jsonRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return req
+}
+
+func TestCreateReviewHandler(t *testing.T) {
+	app, reviewUseCase := setupReviewTestApp(t)
+
+	reviewed, confirmed := "0190f3b2-7c1e-7a4d-9b2f-00000000000c", "0190f3b2-7c1e-7a4d-9b2f-00000000000d"
+	reviewUseCase.On("CreateReview", mock.Anything, domain.BookingID(bookingPathID), 5, "Great").Return(&domain.Review{
+		ID:           "review1",
+		RestaurantID: "restaurant1",
+		UserID:       "user1",
+		BookingID:    "booking1",
+		Rating:       5,
+		Text:         "Great",
+		Status:       domain.ReviewStatusPublished,
+	}, nil)
+	reviewUseCase.On("CreateReview", mock.Anything, domain.BookingID(reviewed), 5, "Great").Return(nil, errors.New(common.ErrReviewExists))
+	reviewUseCase.On("CreateReview", mock.Anything, domain.BookingID(confirmed), 5, "Great").Return(nil, fmt.Errorf("%w: booking is confirmed", usecase.ErrReviewNotAllowed))
+	reviewUseCase.On("CreateReview", mock.Anything, domain.BookingID(missingBookingPathID), 5, "Great").Return(nil, fmt.Errorf("%s: %w", common.ErrBookingNotFound, errors.New("no rows")))
+	reviewUseCase.On("CreateReview", mock.Anything, domain.BookingID(bookingPathID), 9, "Great").Return(nil, fmt.Errorf("%w: rating must be between 1 and 5", usecase.ErrInvalidReview))
+
+	resp, err := app.Test(jsonRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/review", `{"rating":5,"text":"Great"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var review map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	assert.Equal(t, "review1", review["id"])
+	assert.NotContains(t, review, "user_id")
+
+	for booking, status := range map[string]int{
+		reviewed:             http.StatusConflict,
+		confirmed:            http.StatusConflict,
+		missingBookingPathID: http.StatusNotFound,
+	} {
+		resp, err := app.Test(jsonRequest(http.MethodPost, "/api/v1/bookings/"+booking+"/review", `{"rating":5,"text":"Great"}`))
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, booking)
+	}
+
+	resp, err = app.Test(jsonRequest(http.MethodPost, "/api/v1/bookings/"+bookingPathID+"/review", `{"rating":9,"text":"Great"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestListReviewsHandler(t *testing.T) {
+	app, reviewUseCase := setupReviewTestApp(t)
+
+	updatedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	reviewUseCase.On("ListReviews", mock.Anything, "restaurant1", 0, 21).Return([]*domain.Review{{
+		ID:           "review1",
+		RestaurantID: "restaurant1",
+		Rating:       3,
+		Status:       domain.ReviewStatusPublished,
+		Reply:        &domain.ReviewReply{ReviewID: "review1", Text: "Thank you", CreatedAt: updatedAt, UpdatedAt: updatedAt},
+		UpdatedAt:    updatedAt,
+	}}, nil)
+	reviewUseCase.On("ListReviews", mock.Anything, "missing", 0, 21).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/reviews", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderETag))
+	var reviews []handlers.ReviewResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reviews))
+	require.Len(t, reviews, 1)
+	require.NotNil(t, reviews[0].Reply)
+	assert.Equal(t, "Thank you", reviews[0].Reply.Text)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/missing/reviews", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/restaurants/restaurant1/reviews?limit=abc", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestReplyAndFlagReviewHandlers(t *testing.T) {
+	app, reviewUseCase := setupReviewTestApp(t)
+
+	reviewUseCase.On("ReplyToReview", mock.Anything, "restaurant1", "review1", "Thank you").Return(&domain.Review{
+		ID:     "review1",
+		Status: domain.ReviewStatusPublished,
+		Reply:  &domain.ReviewReply{ReviewID: "review1", Text: "Thank you"},
+	}, nil)
+	reviewUseCase.On("ReplyToReview", mock.Anything, "restaurant2", "review1", "Thank you").Return(nil, tenant.ErrAccessDenied)
+	reviewUseCase.On("FlagReview", mock.Anything, "restaurant1", "review1", "Insults").Return(&domain.ReviewFlag{
+		ID:           "flag1",
+		ReviewID:     "review1",
+		RestaurantID: "restaurant1",
+		Reason:       "Insults",
+		Status:       domain.ReviewFlagStatusOpen,
+	}, nil)
+	reviewUseCase.On("FlagReview", mock.Anything, "restaurant1", "review2", "Insults").Return(nil, errors.New(common.ErrReviewAlreadyFlagged))
+	reviewUseCase.On("FlagReview", mock.Anything, "restaurant1", "review3", "Insults").Return(nil, errors.New(common.ErrReviewNotFound))
+
+	resp, err := app.Test(jsonRequest(http.MethodPut, "/api/v1/restaurants/restaurant1/reviews/review1/reply", `{"text":"Thank you"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(jsonRequest(http.MethodPut, "/api/v1/restaurants/restaurant2/reviews/review1/reply", `{"text":"Thank you"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = app.Test(jsonRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/reviews/review1/flag", `{"reason":"Insults"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	var flag handlers.ReviewFlagResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&flag))
+	assert.Equal(t, domain.ReviewFlagStatusOpen, flag.Status)
+
+	resp, err = app.Test(jsonRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/reviews/review2/flag", `{"reason":"Insults"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, err = app.Test(jsonRequest(http.MethodPost, "/api/v1/restaurants/restaurant1/reviews/review3/flag", `{"reason":"Insults"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestReviewFlagAdminHandlers(t *testing.T) {
+	app, reviewUseCase := setupReviewTestApp(t)
+
+	reviewUseCase.On("ListReviewFlags", mock.Anything, domain.ReviewFlagStatus(""), 0, 21).Return([]*domain.ReviewFlag{}, nil)
+	reviewUseCase.On("ListReviewFlags", mock.Anything, domain.ReviewFlagStatus("closed"), 0, 21).Return(nil, fmt.Errorf("%w: unknown status", usecase.ErrInvalidReviewFlag))
+	reviewUseCase.On("ResolveReviewFlag", mock.Anything, "flag1", domain.ReviewFlagStatusUpheld, "Abusive").Return(&domain.ReviewFlag{
+		ID:       "flag1",
+		ReviewID: "review1",
+		Status:   domain.ReviewFlagStatusUpheld,
+		Review:   &domain.Review{ID: "review1", Status: domain.ReviewStatusRemoved},
+	}, nil)
+	reviewUseCase.On("ResolveReviewFlag", mock.Anything, "flag2", domain.ReviewFlagStatusUpheld, "Abusive").Return(nil, usecase.ErrReviewFlagResolved)
+	reviewUseCase.On("ResolveReviewFlag", mock.Anything, "flag3", domain.ReviewFlagStatusUpheld, "Abusive").Return(nil, errors.New(common.ErrReviewFlagNotFound))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/admin/review-flags", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(body))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/admin/review-flags?status=closed", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = app.Test(jsonRequest(http.MethodPost, "/api/v1/admin/review-flags/flag1/resolve", `{"status":"upheld","note":"Abusive"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var flag handlers.ReviewFlagResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&flag))
+	require.NotNil(t, flag.Review)
+	assert.Equal(t, domain.ReviewStatusRemoved, flag.Review.Status)
+
+	for id, status := range map[string]int{
+		"flag2": http.StatusConflict,
+		"flag3": http.StatusNotFound,
+	} {
+		resp, err := app.Test(jsonRequest(http.MethodPost, "/api/v1/admin/review-flags/"+id+"/resolve", `{"status":"upheld","note":"Abusive"}`))
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, id)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSyncUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSyncUseCase) ListChanges(ctx context.Context, entity domain.ExportEntity, since domain.SyncCursor, limit int) (*usecase.SyncPage, error) {
+	args := m.Called(ctx, entity, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.SyncPage), args.Error(1)
+}
+
+// setupSyncApp mounts the change feed behind the admin guard of the router, called by principal.
+func setupSyncApp(syncUseCase *MockSyncUseCase, principal *tenant.Principal) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewSyncHandler(syncUseCase)
+
+	ctx := logger.NewContext(context.Background(), CreateTestLogger())
+	if principal != nil {
+		ctx = tenant.NewContext(ctx, principal)
+	}
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", ctx)
+		return c.Next()
+	})
+	app.Get("/sync/:entity", handler.ListSyncChanges, middleware.RequireRoleMiddleware(tenant.RoleAdmin))
+	return app
+}
+
+func TestListSyncChanges(t *testing.T) {
+	syncUseCase := new(MockSyncUseCase)
+	app := setupSyncApp(syncUseCase, &tenant.Principal{UserID: "admin1", Roles: []tenant.Role{tenant.RoleAdmin}})
+
+	changedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	next := domain.SyncCursor{ChangedAt: changedAt, ID: "booking2"}
+	syncUseCase.On("ListChanges", mock.Anything, domain.ExportBookings, domain.SyncCursor{}, 2).Return(&usecase.SyncPage{
+		Changes: []*domain.SyncChange{
+			{ID: "booking1", ChangedAt: changedAt, Data: json.RawMessage(`{"id":"booking1","status":"confirmed"}`)},
+			{ID: "booking2", ChangedAt: changedAt, Deleted: true},
+		},
+		Next:    next,
+		HasMore: true,
+	}, nil)
+	syncUseCase.On("ListChanges", mock.Anything, domain.ExportBookings, next, usecase.DefaultSyncLimit).
+		Return(&usecase.SyncPage{Changes: []*domain.SyncChange{}, Next: next}, nil)
+	syncUseCase.On("ListChanges", mock.Anything, domain.ExportEntity("notifications"), domain.SyncCursor{}, usecase.DefaultSyncLimit).
+		Return(nil, usecase.ErrInvalidExportEntity)
+	syncUseCase.On("ListChanges", mock.Anything, domain.ExportUsers, domain.SyncCursor{}, usecase.DefaultSyncLimit).
+		Return(nil, tenant.ErrAccessDenied)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/sync/bookings?limit=2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var page handlers.SyncResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	require.Len(t, page.Changes, 2)
+	assert.JSONEq(t, `{"id":"booking1","status":"confirmed"}`, string(page.Changes[0].Data))
+	assert.True(t, page.Changes[1].Deleted)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, next.String(), page.NextSince)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/sync/bookings?since="+page.NextSince, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for path, status := range map[string]int{
+		"/sync/notifications":          http.StatusBadRequest,
+		"/sync/users":                  http.StatusForbidden,
+		"/sync/bookings?since=garbage": http.StatusBadRequest,
+		"/sync/bookings?limit=many":    http.StatusBadRequest,
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, path)
+	}
+}
+
+func TestListSyncChanges_AdminsOnly(t *testing.T) {
+	syncUseCase := new(MockSyncUseCase)
+
+	for _, caller := range []struct {
+		principal *tenant.Principal
+		status    int
+	}{
+		{nil, http.StatusUnauthorized},
+		{&tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}}, http.StatusForbidden},
+		{&tenant.Principal{RestaurantIDs: []string{"r1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}}, http.StatusForbidden},
+	} {
+		resp, err := setupSyncApp(syncUseCase, caller.principal).Test(httptest.NewRequest(http.MethodGet, "/sync/users", nil))
+		require.NoError(t, err)
+		assert.Equal(t, caller.status, resp.StatusCode)
+	}
+	syncUseCase.AssertNotCalled(t, "ListChanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
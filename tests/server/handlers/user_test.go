@@ -24,6 +24,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// userPathID and missingUserPathID are the users of the request paths, which the handlers parse as
+// UUIDs.
+const (
+	userPathID        = "0190f3b2-6b5d-7e2f-9a3b-4c5d6e7f8091"
+	missingUserPathID = "0190f3b2-6b5d-7e2f-9a3b-000000000000"
+)
+
 type MockLogger struct {
 	mock.Mock
 }
@@ -86,7 +93,7 @@ type MockUserUseCase struct {
 	mock.Mock
 }
 
-func (m *MockUserUseCase) GetUser(ctx context.Context, id string) (*domain.User, error) {
+func (m *MockUserUseCase) GetUser(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -116,7 +123,7 @@ type MockBookingUseCase struct {
 	mock.Mock
 }
 
-func (m *MockBookingUseCase) GetBooking(ctx context.Context, id string) (*domain.Booking, error) {
+func (m *MockBookingUseCase) GetBooking(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -124,14 +131,25 @@ func (m *MockBookingUseCase) GetBooking(ctx context.Context, id string) (*domain
 	return args.Get(0).(*domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingUseCase) GetRestaurantBookings(ctx context.Context, restaurantID string) ([]*domain.Booking, error) {
-	args := m.Called(ctx, restaurantID)
-	return args.Get(0).([]*domain.Booking), args.Error(1)
+func (m *MockBookingUseCase) GetRestaurantBookings(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	page usecase.PageRequest,
+) (*usecase.PageResponse[*domain.Booking], error) {
+	args := m.Called(ctx, restaurantID, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Booking]), args.Error(1)
 }
 
-func (m *MockBookingUseCase) GetUserBookings(ctx context.Context, userID string) ([]*domain.Booking, error) {
-	args := m.Called(ctx, userID)
-	return args.Get(0).([]*domain.Booking), args.Error(1)
+func (m *MockBookingUseCase) GetUserBookings(ctx context.Context, userID domain.UserID, page usecase.PageRequest) (*usecase.PageResponse[*domain.Booking], error) {
+	args := m.Called(ctx, userID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Booking]), args.Error(1)
 }
 
 func (m *MockBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
@@ -139,27 +157,27 @@ func (m *MockBookingUseCase) CreateBooking(ctx context.Context, booking *domain.
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockBookingUseCase) ConfirmBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) ConfirmBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) RejectBooking(ctx context.Context, id string, reason string) error {
+func (m *MockBookingUseCase) RejectBooking(ctx context.Context, id domain.BookingID, reason string) error {
 	args := m.Called(ctx, id, reason)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) CompleteBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) CompleteBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID string, date time.Time, time string, message string) (string, error) {
+func (m *MockBookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID domain.BookingID, date time.Time, time string, message string) (string, error) {
 	args := m.Called(ctx, bookingID, date, time, message)
 	return args.String(0), args.Error(1)
 }
@@ -198,6 +216,27 @@ func (m *MockNotificationUseCase) MarkAsRead(ctx context.Context, notificationID
 	return args.Error(0)
 }
 
+func (m *MockNotificationUseCase) ResendNotification(ctx context.Context, notificationID string) error {
+	args := m.Called(ctx, notificationID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationUseCase) GetNotificationSettings(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	args := m.Called(ctx, recipientType, recipientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) UpdateNotificationSettings(ctx context.Context, settings *domain.NotificationSettings) (*domain.NotificationSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationSettings), args.Error(1)
+}
+
 func setupTestApp(_ *testing.T) (*fiber.App, *MockUserUseCase, *MockBookingUseCase, *MockNotificationUseCase, context.Context) {
 	app := fiber.New()
 	userUseCase := new(MockUserUseCase)
@@ -229,7 +268,7 @@ func TestCreateUser_Success(t *testing.T) {
 
 	userUseCase.On("CreateUser", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
 		return user.Name == "Test User" && user.Email == "test@example.com" && user.Phone == "+71234567890"
-	})).Return("user123", nil)
+	})).Return(userPathID, nil)
 
 	reqBody := handlers.CreateUserRequest{
 		Name:  "Test User",
@@ -248,7 +287,7 @@ func TestCreateUser_Success(t *testing.T) {
 	var respBody map[string]string
 	err = json.NewDecoder(resp.Body).Decode(&respBody)
 	require.NoError(t, err)
-	assert.Equal(t, "user123", respBody["id"])
+	assert.Equal(t, userPathID, respBody["id"])
 
 	userUseCase.AssertExpectations(t)
 }
@@ -333,7 +372,7 @@ func TestGetUser_Success(t *testing.T) {
 	app, userUseCase, _, _, _ := setupTestApp(t)
 
 	expectedUser := &domain.User{
-		ID:        "user123",
+		ID:        userPathID,
 		Name:      "Test User",
 		Email:     "test@example.com",
 		Phone:     "+71234567890",
@@ -341,9 +380,9 @@ func TestGetUser_Success(t *testing.T) {
 		UpdatedAt: time.Now(),
 	}
 
-	userUseCase.On("GetUser", mock.Anything, "user123").Return(expectedUser, nil)
+	userUseCase.On("GetUser", mock.Anything, domain.UserID(userPathID)).Return(expectedUser, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -358,12 +397,38 @@ func TestGetUser_Success(t *testing.T) {
 	userUseCase.AssertExpectations(t)
 }
 
+func TestGetUser_WireFormat(t *testing.T) {
+	app, userUseCase, _, _, _ := setupTestApp(t)
+
+	userUseCase.On("GetUser", mock.Anything, domain.UserID(userPathID)).Return(&domain.User{
+		ID:    userPathID,
+		Name:  "Test User",
+		Email: "test@example.com",
+		Phone: "+71234567890",
+	}, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userPathID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"id", "name", "email", "phone", "created_at", "updated_at"}, keys)
+
+	userUseCase.AssertExpectations(t)
+}
+
 func TestGetUser_NotFound(t *testing.T) {
 	app, userUseCase, _, _, _ := setupTestApp(t)
 
-	userUseCase.On("GetUser", mock.Anything, "nonexistent").Return(nil, nil)
+	userUseCase.On("GetUser", mock.Anything, domain.UserID(missingUserPathID)).Return(nil, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/nonexistent", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+missingUserPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
@@ -379,9 +444,9 @@ func TestGetUser_NotFound(t *testing.T) {
 func TestGetUser_InternalError(t *testing.T) {
 	app, userUseCase, _, _, _ := setupTestApp(t)
 
-	userUseCase.On("GetUser", mock.Anything, "user123").Return(nil, errors.New("database error"))
+	userUseCase.On("GetUser", mock.Anything, domain.UserID(userPathID)).Return(nil, errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userPathID, nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -394,11 +459,25 @@ func TestGetUser_InternalError(t *testing.T) {
 	userUseCase.AssertExpectations(t)
 }
 
+func TestGetUser_InvalidID(t *testing.T) {
+	app, userUseCase, _, _, _ := setupTestApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/v1/users/user123", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var respBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Contains(t, respBody["error"], domain.ErrInvalidID.Error())
+
+	userUseCase.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+}
+
 func TestUpdateUser_Success(t *testing.T) {
 	app, userUseCase, _, _, _ := setupTestApp(t)
 
 	userUseCase.On("UpdateUser", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
-		return user.ID == "user123" &&
+		return user.ID == userPathID &&
 			user.Name == "Updated User" &&
 			user.Email == "updated@example.com" &&
 			user.Phone == "+71234567891"
@@ -411,7 +490,7 @@ func TestUpdateUser_Success(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user123", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+userPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -438,7 +517,7 @@ func TestUpdateUser_NotFound(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/nonexistent", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+missingUserPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -465,7 +544,7 @@ func TestUpdateUser_EmailExists(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user123", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+userPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -492,7 +571,7 @@ func TestUpdateUser_InternalError(t *testing.T) {
 	}
 	reqJSON, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/user123", bytes.NewBuffer(reqJSON))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/"+userPathID, bytes.NewBuffer(reqJSON))
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := app.Test(req)
@@ -507,6 +586,8 @@ func TestUpdateUser_InternalError(t *testing.T) {
 	userUseCase.AssertExpectations(t)
 }
 
+const bookingsUserID = "5b0f7e0c-8d1a-4c49-9f43-6a2e3c1d7b95"
+
 func TestGetUserBookings_Success(t *testing.T) {
 	app, _, bookingUseCase, _, _ := setupTestApp(t)
 
@@ -514,8 +595,8 @@ func TestGetUserBookings_Success(t *testing.T) {
 	bookings := []*domain.Booking{
 		{
 			ID:           "booking1",
-			RestaurantID: "restaurant1",
-			UserID:       "user123",
+			RestaurantID: restaurantPathID,
+			UserID:       userPathID,
 			Date:         currentTime,
 			Time:         "18:00",
 			Duration:     90,
@@ -528,7 +609,7 @@ func TestGetUserBookings_Success(t *testing.T) {
 		{
 			ID:           "booking2",
 			RestaurantID: "restaurant2",
-			UserID:       "user123",
+			UserID:       userPathID,
 			Date:         currentTime.Add(24 * time.Hour),
 			Time:         "19:00",
 			Duration:     120,
@@ -539,9 +620,10 @@ func TestGetUserBookings_Success(t *testing.T) {
 		},
 	}
 
-	bookingUseCase.On("GetUserBookings", mock.Anything, "user123").Return(bookings, nil)
+	bookingUseCase.On("GetUserBookings", mock.Anything, domain.UserID(bookingsUserID), usecase.PageRequest{Cursor: "after-booking0", Limit: 2}).
+		Return(&usecase.PageResponse[*domain.Booking]{Items: bookings}, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123/bookings", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+bookingsUserID+"/bookings?cursor=after-booking0&limit=2", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -559,9 +641,10 @@ func TestGetUserBookings_Success(t *testing.T) {
 func TestGetUserBookings_InternalError(t *testing.T) {
 	app, _, bookingUseCase, _, _ := setupTestApp(t)
 
-	bookingUseCase.On("GetUserBookings", mock.Anything, "user123").Return([]*domain.Booking{}, errors.New("database error"))
+	bookingUseCase.On("GetUserBookings", mock.Anything, domain.UserID(bookingsUserID), usecase.PageRequest{Limit: 20}).
+		Return(nil, errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123/bookings", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+bookingsUserID+"/bookings", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
@@ -574,6 +657,40 @@ func TestGetUserBookings_InternalError(t *testing.T) {
 	bookingUseCase.AssertExpectations(t)
 }
 
+func TestGetUserBookings_InvalidCursor(t *testing.T) {
+	app, _, bookingUseCase, _, _ := setupTestApp(t)
+
+	bookingUseCase.On("GetUserBookings", mock.Anything, domain.UserID(bookingsUserID), usecase.PageRequest{Cursor: "stale", Limit: 20}).
+		Return(nil, usecase.ErrInvalidPageCursor)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+bookingsUserID+"/bookings?cursor=stale", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var respBody map[string]string
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, common.ErrInvalidPageCursor, respBody["error"])
+
+	bookingUseCase.AssertExpectations(t)
+}
+
+func TestGetUserBookings_InvalidID(t *testing.T) {
+	app, _, bookingUseCase, _, _ := setupTestApp(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123/bookings", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var respBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&respBody))
+	assert.Contains(t, respBody["error"], domain.ErrInvalidID.Error())
+
+	bookingUseCase.AssertNotCalled(t, "GetUserBookings", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestGetUserNotifications_Success(t *testing.T) {
 	app, _, _, notificationUseCase, _ := setupTestApp(t)
 
@@ -582,7 +699,7 @@ func TestGetUserNotifications_Success(t *testing.T) {
 		{
 			ID:            "notification1",
 			RecipientType: domain.RecipientTypeUser,
-			RecipientID:   "user123",
+			RecipientID:   userPathID,
 			Type:          domain.NotificationTypeBookingConfirmed,
 			Title:         "Booking confirmed",
 			Message:       "Your booking has been confirmed",
@@ -593,7 +710,7 @@ func TestGetUserNotifications_Success(t *testing.T) {
 		{
 			ID:            "notification2",
 			RecipientType: domain.RecipientTypeUser,
-			RecipientID:   "user123",
+			RecipientID:   userPathID,
 			Type:          domain.NotificationTypeBookingRejected,
 			Title:         "Booking rejected",
 			Message:       "Your booking has been rejected",
@@ -603,9 +720,9 @@ func TestGetUserNotifications_Success(t *testing.T) {
 		},
 	}
 
-	notificationUseCase.On("GetUserNotifications", mock.Anything, "user123").Return(notifications, nil)
+	notificationUseCase.On("GetUserNotifications", mock.Anything, userPathID).Return(notifications, nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123/notifications", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userPathID+"/notifications", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -623,9 +740,9 @@ func TestGetUserNotifications_Success(t *testing.T) {
 func TestGetUserNotifications_InternalError(t *testing.T) {
 	app, _, _, notificationUseCase, _ := setupTestApp(t)
 
-	notificationUseCase.On("GetUserNotifications", mock.Anything, "user123").Return([]domain.Notification{}, errors.New("database error"))
+	notificationUseCase.On("GetUserNotifications", mock.Anything, userPathID).Return([]domain.Notification{}, errors.New("database error"))
 
-	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/user123/notifications", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/"+userPathID+"/notifications", nil)
 	resp, err := app.Test(req)
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
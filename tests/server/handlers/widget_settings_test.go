@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/handlers"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWidgetSettingsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWidgetSettingsUseCase) GetWidgetSettings(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WidgetSettings), args.Error(1)
+}
+
+func (m *MockWidgetSettingsUseCase) UpdateWidgetSettings(ctx context.Context, settings *domain.WidgetSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func setupWidgetSettingsApp(widgetSettingsUseCase *MockWidgetSettingsUseCase, restaurantUseCase *MockRestaurantUseCase) *fiber.App {
+	app := fiber.New()
+	handler := handlers.NewWidgetSettingsHandler(widgetSettingsUseCase, restaurantUseCase, "https://booking.example/")
+
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", logger.NewContext(context.Background(), CreateTestLogger()))
+		return c.Next()
+	})
+	app.Put("/restaurants/:id/widget-settings", handler.UpdateWidgetSettings)
+	app.Get("/embed/restaurants/:id/config", handler.GetWidgetConfig)
+	return app
+}
+
+func TestUpdateWidgetSettings(t *testing.T) {
+	widgetSettingsUseCase := new(MockWidgetSettingsUseCase)
+	app := setupWidgetSettingsApp(widgetSettingsUseCase, new(MockRestaurantUseCase))
+
+	widgetSettingsUseCase.On("UpdateWidgetSettings", mock.Anything, mock.MatchedBy(func(settings *domain.WidgetSettings) bool {
+		return settings.RestaurantID == restaurantPathID && settings.Theme.PrimaryColor == "#ab0" &&
+			settings.Theme.TextColor == domain.DefaultWidgetTextColor && settings.DefaultPartySize == domain.DefaultWidgetPartySize
+	})).Return(nil)
+	widgetSettingsUseCase.On("UpdateWidgetSettings", mock.Anything, mock.MatchedBy(func(settings *domain.WidgetSettings) bool {
+		return settings.RestaurantID == "restaurant2"
+	})).Return(tenant.ErrAccessDenied)
+	widgetSettingsUseCase.On("UpdateWidgetSettings", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("%w: locale", domain.ErrInvalidWidgetSettings))
+
+	tests := []struct {
+		name         string
+		restaurantID string
+		body         string
+		expected     int
+	}{
+		{"fields left out take the defaults", restaurantPathID, `{"theme":{"primary_color":"#ab0"}}`, http.StatusOK},
+		{"another restaurant", "restaurant2", `{}`, http.StatusForbidden},
+		{"invalid settings", restaurantPathID, `{"locale":"??"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/restaurants/"+tt.restaurantID+"/widget-settings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetWidgetConfig(t *testing.T) {
+	widgetSettingsUseCase := new(MockWidgetSettingsUseCase)
+	restaurantUseCase := new(MockRestaurantUseCase)
+	app := setupWidgetSettingsApp(widgetSettingsUseCase, restaurantUseCase)
+
+	restaurantUseCase.On("GetRestaurant", mock.Anything, domain.RestaurantID(restaurantPathID)).
+		Return(&domain.Restaurant{ID: restaurantPathID, Name: "Pasta Place", Slug: "pasta-place"}, nil)
+	settings := domain.DefaultWidgetSettings(restaurantPathID)
+	settings.Locale = "ru-RU"
+	widgetSettingsUseCase.On("GetWidgetSettings", mock.Anything, restaurantPathID).Return(settings, nil)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/embed/restaurants/"+restaurantPathID+"/config", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+
+	var config handlers.WidgetConfigResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&config))
+	assert.Equal(t, "Pasta Place", config.Restaurant.Name)
+	assert.Equal(t, domain.DefaultWidgetPrimaryColor, config.Theme.PrimaryColor)
+	assert.Equal(t, "ru-RU", config.Locale)
+	assert.Equal(t, "https://booking.example/embed/restaurants/"+restaurantPathID+"/availability", config.AvailabilityURL)
+	assert.Equal(t, "https://booking.example/r/pasta-place", config.BookingURL)
+}
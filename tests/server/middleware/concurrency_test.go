@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingApp serves /slow/:id until release is closed, signalling entered for every request
+// handled, and /fast at once.
+func blockingApp(limits middleware.ConcurrencyLimits) (app *fiber.App, entered chan struct{}, release chan struct{}) {
+	entered = make(chan struct{}, 10)
+	release = make(chan struct{})
+
+	app = fiber.New()
+	app.Use(middleware.ConcurrencyMiddleware(limits))
+	app.Get("/slow/:id", func(c fiber.Ctx) error {
+		entered <- struct{}{}
+		<-release
+		return c.SendString("slow")
+	})
+	app.Get("/fast", func(c fiber.Ctx) error {
+		return c.SendString("fast")
+	})
+	app.Get("/health", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	return app, entered, release
+}
+
+func sendInBackground(t *testing.T, app *fiber.App, path string) <-chan int {
+	t.Helper()
+	status := make(chan int, 1)
+	go func() {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), fiber.TestConfig{Timeout: 0})
+		if err != nil {
+			status <- 0
+			return
+		}
+		status <- resp.StatusCode
+	}()
+	return status
+}
+
+func TestConcurrencyMiddlewareShedsLoad(t *testing.T) {
+	app, entered, release := blockingApp(middleware.ConcurrencyLimits{
+		MaxInFlight: 1,
+		RetryAfter:  2 * time.Second,
+		Exempt:      []string{"/health"},
+	})
+
+	first := sendInBackground(t, app, "/slow/1")
+	<-entered
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "exempt paths are never limited")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	metrics := expvar.Get("concurrency")
+	require.NotNil(t, metrics)
+	assert.Contains(t, metrics.String(), `"rejected":`)
+}
+
+func TestConcurrencyMiddlewarePerRoute(t *testing.T) {
+	app, entered, release := blockingApp(middleware.ConcurrencyLimits{
+		MaxInFlight:         10,
+		MaxInFlightPerRoute: 1,
+	})
+
+	first := sendInBackground(t, app, "/slow/1")
+	<-entered
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/slow/2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "the route is busy")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "other routes are served")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+}
+
+func TestConcurrencyMiddlewareQueue(t *testing.T) {
+	app, entered, release := blockingApp(middleware.ConcurrencyLimits{
+		MaxInFlight:  1,
+		MaxQueue:     1,
+		QueueTimeout: 5 * time.Second,
+	})
+
+	first := sendInBackground(t, app, "/slow/1")
+	<-entered
+
+	queued := sendInBackground(t, app, "/slow/2")
+	require.Eventually(t, func() bool {
+		metrics, ok := expvar.Get("concurrency").(*expvar.Map)
+		return ok && metrics.Get("queued").(*expvar.Int).Value() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "the queue is full")
+
+	close(release)
+	assert.Equal(t, http.StatusOK, <-first)
+	assert.Equal(t, http.StatusOK, <-queued, "the queued request is served once a slot is free")
+}
+
+func TestConcurrencyMiddlewareQueueTimeout(t *testing.T) {
+	app, entered, release := blockingApp(middleware.ConcurrencyLimits{
+		MaxInFlight:  1,
+		MaxQueue:     1,
+		QueueTimeout: 20 * time.Millisecond,
+	})
+	defer close(release)
+
+	_ = sendInBackground(t, app, "/slow/1")
+	<-entered
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/fast", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+}
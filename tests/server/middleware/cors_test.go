@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func preflightRequest(path, origin string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	return req
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	cors, err := middleware.CORSMiddleware(middleware.CORSPolicy{
+		AllowOrigins:     []string{"https://app.example"},
+		AllowMethods:     []string{http.MethodGet, http.MethodPost},
+		ExposeHeaders:    []string{"ETag"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}, middleware.CORSRoute{
+		Prefix: "/embed",
+		Policy: middleware.CORSPolicy{AllowOrigins: []string{"*"}, AllowMethods: []string{http.MethodGet}},
+	})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(cors)
+	app.Get("/api/v1/restaurants", func(c fiber.Ctx) error {
+		return c.SendString("restaurants")
+	})
+	app.Get("/embed/widget", func(c fiber.Ctx) error {
+		return c.SendString("widget")
+	})
+	app.Get("/embedded", func(c fiber.Ctx) error {
+		return c.SendString("not a widget")
+	})
+
+	resp, err := app.Test(preflightRequest("/api/v1/restaurants", "https://app.example"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://app.example", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Methods"), http.MethodPost)
+
+	resp, err = app.Test(preflightRequest("/api/v1/restaurants", "https://other.example"))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	req := preflightRequest("/api/v1/restaurants", "https://app.example")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-Roles")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Content-Type")
+	assert.NotContains(t, resp.Header.Get("Access-Control-Allow-Headers"), middleware.HeaderRoles,
+		"the principal headers are not allowed across origins")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/restaurants", nil)
+	req.Header.Set("Origin", "https://app.example")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ETag", resp.Header.Get("Access-Control-Expose-Headers"))
+
+	resp, err = app.Test(preflightRequest("/embed/widget", "https://other.example"))
+	require.NoError(t, err)
+	assert.Equal(t, "*", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Credentials"))
+
+	resp, err = app.Test(preflightRequest("/embedded", "https://other.example"))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"), "a route covers its own path segment only")
+}
+
+func TestCORSPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  middleware.CORSPolicy
+		wantErr bool
+	}{
+		{"default", middleware.CORSPolicy{}, false},
+		{"origins", middleware.CORSPolicy{AllowOrigins: []string{"https://app.example", "http://localhost:3000"}, AllowCredentials: true}, false},
+		{"credentials for every origin", middleware.CORSPolicy{AllowOrigins: []string{"*"}, AllowCredentials: true}, true},
+		{"credentials without origins", middleware.CORSPolicy{AllowCredentials: true}, true},
+		{"origin without scheme", middleware.CORSPolicy{AllowOrigins: []string{"app.example"}}, true},
+		{"origin with path", middleware.CORSPolicy{AllowOrigins: []string{"https://app.example/"}}, true},
+		{"headers", middleware.CORSPolicy{AllowHeaders: []string{"Content-Type", "Authorization"}}, false},
+		{"every header", middleware.CORSPolicy{AllowHeaders: []string{"*"}}, true},
+		{"principal header", middleware.CORSPolicy{AllowHeaders: []string{"Content-Type", "x-roles"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, middleware.ErrInvalidCORSPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err := middleware.CORSMiddleware(middleware.CORSPolicy{}, middleware.CORSRoute{
+		Prefix: "/embed",
+		Policy: middleware.CORSPolicy{AllowCredentials: true},
+	})
+	assert.ErrorIs(t, err, middleware.ErrInvalidCORSPolicy)
+}
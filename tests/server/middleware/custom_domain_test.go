@@ -0,0 +1,84 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubHostResolver struct {
+	domains map[string]*domain.CustomDomain
+	hosts   []string
+}
+
+func (r *stubHostResolver) ResolveHost(_ context.Context, host string) (*domain.CustomDomain, error) {
+	r.hosts = append(r.hosts, host)
+	if customDomain, ok := r.domains[host]; ok {
+		return customDomain, nil
+	}
+	return nil, errors.New(common.ErrCustomDomainNotFound)
+}
+
+func newCustomDomainApp(resolver middleware.HostResolver) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.PrincipalMiddleware(nil, anyGateway))
+	app.Use(middleware.CustomDomainMiddleware(resolver, []string{"booking.example"}))
+	app.Get("/", func(c fiber.Ctx) error {
+		return c.SendString("platform")
+	})
+	app.Get("/r/:slug", func(c fiber.Ctx) error {
+		return c.SendString(c.Params("slug") + " " + c.Query("date"))
+	})
+	app.Get("/api/v1/test", func(c fiber.Ctx) error {
+		return c.SendString("api")
+	})
+	return app
+}
+
+func requestHost(t *testing.T, app *fiber.App, host, path string) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestCustomDomainMiddleware(t *testing.T) {
+	resolver := &stubHostResolver{domains: map[string]*domain.CustomDomain{
+		"book.bistro.example": {RestaurantID: "rest-1", Domain: "book.bistro.example", Status: domain.CustomDomainVerified},
+	}}
+	app := newCustomDomainApp(resolver)
+
+	assert.Equal(t, "rest-1 ", requestHost(t, app, "book.bistro.example", "/"))
+	assert.Equal(t, "rest-1 2026-05-10", requestHost(t, app, "Book.Bistro.Example:443", "/?date=2026-05-10"))
+	assert.Equal(t, "api", requestHost(t, app, "book.bistro.example", "/api/v1/test"))
+	assert.Equal(t, []string{"book.bistro.example", "book.bistro.example"}, resolver.hosts)
+}
+
+func TestCustomDomainMiddlewareOtherHosts(t *testing.T) {
+	resolver := &stubHostResolver{}
+	app := newCustomDomainApp(resolver)
+
+	assert.Equal(t, "platform", requestHost(t, app, "booking.example", "/"))
+	assert.Empty(t, resolver.hosts, "platform hosts are not looked up")
+
+	assert.Equal(t, "platform", requestHost(t, app, "unknown.example", "/"))
+	assert.Equal(t, []string{"unknown.example"}, resolver.hosts)
+}
@@ -0,0 +1,106 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEnvelopeApp(enabledByDefault bool) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.EnvelopeMiddleware(enabledByDefault))
+
+	app.Get("/api/v1/restaurants", func(c fiber.Ctx) error {
+		middleware.SetPagination(c, middleware.Pagination{Offset: 20, Limit: 10, Count: 2})
+		return c.JSON([]fiber.Map{{"id": "restaurant1"}, {"id": "restaurant2"}})
+	})
+	app.Get("/api/v1/restaurants/missing", func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "restaurant not found"})
+	})
+	app.Put("/api/v1/availability", func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "capacity below reserved seats", "reserved": 8})
+	})
+	app.Get("/api/v1/teapot", func(fiber.Ctx) error {
+		return fiber.ErrTeapot
+	})
+	app.Get("/sitemap.xml", func(c fiber.Ctx) error {
+		return c.JSON(fiber.Map{"plain": true})
+	})
+
+	return app
+}
+
+func doEnvelopeRequest(t *testing.T, app *fiber.App, method, path, envelopeHeader string) (int, map[string]json.RawMessage) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	if envelopeHeader != "" {
+		req.Header.Set(middleware.HeaderResponseEnvelope, envelopeHeader)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &fields), string(body))
+	return resp.StatusCode, fields
+}
+
+func TestEnvelopeMiddleware_WrapsListWithPagination(t *testing.T) {
+	app := setupEnvelopeApp(true)
+
+	status, body := doEnvelopeRequest(t, app, http.MethodGet, "/api/v1/restaurants", "")
+
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, `[{"id":"restaurant1"},{"id":"restaurant2"}]`, string(body["data"]))
+	assert.JSONEq(t, `{"pagination":{"offset":20,"limit":10,"count":2,"has_next":false}}`, string(body["meta"]))
+	assert.Equal(t, "null", string(body["error"]))
+}
+
+func TestEnvelopeMiddleware_WrapsErrors(t *testing.T) {
+	app := setupEnvelopeApp(true)
+
+	status, body := doEnvelopeRequest(t, app, http.MethodGet, "/api/v1/restaurants/missing", "")
+	assert.Equal(t, fiber.StatusNotFound, status)
+	assert.Equal(t, "null", string(body["data"]))
+	assert.Equal(t, "null", string(body["meta"]))
+	assert.JSONEq(t, `"restaurant not found"`, string(body["error"]))
+
+	status, body = doEnvelopeRequest(t, app, http.MethodPut, "/api/v1/availability", "")
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.JSONEq(t, `{"reserved":8}`, string(body["data"]))
+	assert.JSONEq(t, `"capacity below reserved seats"`, string(body["error"]))
+
+	status, body = doEnvelopeRequest(t, app, http.MethodGet, "/api/v1/teapot", "")
+	assert.Equal(t, fiber.StatusTeapot, status)
+	assert.JSONEq(t, `"I'm a teapot"`, string(body["error"]))
+}
+
+func TestEnvelopeMiddleware_CompatibilityFlag(t *testing.T) {
+	legacy := setupEnvelopeApp(false)
+
+	_, body := doEnvelopeRequest(t, legacy, http.MethodGet, "/api/v1/restaurants/missing", "")
+	assert.JSONEq(t, `"restaurant not found"`, string(body["error"]))
+	assert.NotContains(t, body, "data")
+
+	_, body = doEnvelopeRequest(t, legacy, http.MethodGet, "/api/v1/restaurants/missing", "true")
+	assert.Contains(t, body, "data")
+
+	enveloped := setupEnvelopeApp(true)
+
+	_, body = doEnvelopeRequest(t, enveloped, http.MethodGet, "/api/v1/restaurants/missing", "false")
+	assert.NotContains(t, body, "data")
+
+	_, body = doEnvelopeRequest(t, enveloped, http.MethodGet, "/sitemap.xml", "")
+	assert.JSONEq(t, `true`, string(body["plain"]))
+}
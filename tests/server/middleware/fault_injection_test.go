@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticFaults []domain.RouteFault
+
+func (f staticFaults) RouteFaults() []domain.RouteFault {
+	return f
+}
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(middleware.FaultInjectionMiddleware(staticFaults{
+		{Method: http.MethodGet, Path: "/api/v1/restaurants/:id", ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable},
+		{Path: "/api/v1/bookings/:id", Latency: 50 * time.Millisecond},
+		{Path: "/api/v1/admin/faults", ErrorRate: 1, ErrorStatus: http.StatusInternalServerError},
+	}))
+
+	ok := func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	}
+	app.Get("/api/v1/restaurants/:id", ok)
+	app.Put("/api/v1/restaurants/:id", ok)
+	app.Get("/api/v1/bookings/:id", ok)
+	app.Get("/api/v1/admin/faults", ok)
+
+	call := func(method, route string) *http.Response {
+		req, err := http.NewRequest(method, route, nil)
+		require.NoError(t, err)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := call(http.MethodGet, "/api/v1/restaurants/r1")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(middleware.HeaderFaultInjected))
+	assert.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	assert.Equal(t, http.StatusOK, call(http.MethodPut, "/api/v1/restaurants/r1").StatusCode, "other methods are not faulted")
+
+	started := time.Now()
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/bookings/b1").StatusCode)
+	assert.GreaterOrEqual(t, time.Since(started), 50*time.Millisecond)
+
+	assert.Equal(t, http.StatusOK, call(http.MethodGet, "/api/v1/admin/faults").StatusCode, "managing faults is never faulted")
+}
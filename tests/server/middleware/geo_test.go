@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubLocator struct {
+	location *geo.Location
+	err      error
+	ip       string
+}
+
+func (l *stubLocator) Locate(ip string) (*geo.Location, error) {
+	l.ip = ip
+	return l.location, l.err
+}
+
+func newGeoApp(locator geo.Locator) *fiber.App {
+	app := fiber.New()
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.PrincipalMiddleware(nil, anyGateway))
+	app.Use(middleware.GeoMiddleware(locator))
+	app.Get("/test", func(c fiber.Ctx) error {
+		location, ok := geo.FromContext(c.Locals("ctx").(context.Context))
+		if !ok {
+			return c.SendString("unknown")
+		}
+		return c.SendString(location.CountryCode)
+	})
+	return app
+}
+
+func TestGeoMiddleware(t *testing.T) {
+	locator := &stubLocator{location: &geo.Location{CountryCode: "DE"}}
+
+	resp, err := newGeoApp(locator).Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "DE", string(body))
+	assert.Equal(t, "0.0.0.0", locator.ip)
+}
+
+func TestGeoMiddlewareUnlocated(t *testing.T) {
+	for _, locateErr := range []error{geo.ErrNotFound, errors.New("corrupt database")} {
+		resp, err := newGeoApp(&stubLocator{err: locateErr}).Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "unknown", string(body))
+	}
+}
@@ -18,7 +18,7 @@ import (
 func TestLoggingMiddleware(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.LoggingMiddleware())
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
 
 	app.Get("/test", func(c fiber.Ctx) error {
 		ctx := c.Locals("ctx")
@@ -80,7 +80,7 @@ func TestLoggingMiddleware(t *testing.T) {
 func TestLoggingMiddlewareContextPropagation(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.LoggingMiddleware())
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
 
 	app.Get("/check-logger", func(c fiber.Ctx) error {
 		ctx := c.Locals("ctx")
@@ -118,7 +118,7 @@ func TestLoggingMiddlewareContextPropagation(t *testing.T) {
 func TestLoggingMiddlewareWithDifferentMethods(t *testing.T) {
 	app := fiber.New()
 
-	app.Use(middleware.LoggingMiddleware())
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
 
 	methods := []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
 
@@ -176,3 +176,44 @@ func TestLoggingMiddlewareWithDifferentMethods(t *testing.T) {
 		})
 	}
 }
+
+func TestLoggingMiddlewareReadSampling(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{Routes: []string{"/hot"}, Rate: 3}))
+
+	handler := func(c fiber.Ctx) error {
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			return c.Status(500).SendString("context not found")
+		}
+		if utils.IsSampledOut(ctx) {
+			return c.SendString("sampled out")
+		}
+		return c.SendString("logged")
+	}
+	app.Get("/hot", handler)
+	app.Post("/hot", handler)
+	app.Get("/cold", handler)
+
+	call := func(method, route string) string {
+		req, err := http.NewRequest(method, route, nil)
+		require.NoError(t, err)
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	var hot []string
+	for range 4 {
+		hot = append(hot, call(http.MethodGet, "/hot"))
+	}
+	assert.Equal(t, []string{"logged", "sampled out", "sampled out", "logged"}, hot)
+
+	assert.Equal(t, "logged", call(http.MethodPost, "/hot"), "writes are never sampled")
+	assert.Equal(t, "logged", call(http.MethodGet, "/cold"))
+}
@@ -0,0 +1,284 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// anyGateway trusts the principal headers of every request.
+var anyGateway = middleware.GatewayTrust{Networks: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0"), netip.MustParsePrefix("::/0")}}
+
+func TestPrincipalMiddleware(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.PrincipalMiddleware(nil, anyGateway))
+
+	app.Get("/test", func(c fiber.Ctx) error {
+		ctx, ok := c.Locals("ctx").(context.Context)
+		if !ok {
+			return c.Status(500).SendString("context not found")
+		}
+
+		principal, ok := tenant.FromContext(ctx)
+		if !ok {
+			return c.SendString("anonymous")
+		}
+
+		roles := make([]string, 0, len(principal.Roles))
+		for _, role := range principal.Roles {
+			roles = append(roles, string(role))
+		}
+		return c.SendString(principal.UserID + "|" + strings.Join(principal.RestaurantIDs, ",") + "|" + strings.Join(roles, ","))
+	})
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		expectedBody string
+	}{
+		{
+			name:         "without user header",
+			expectedBody: "anonymous",
+		},
+		{
+			name: "with principal headers",
+			headers: map[string]string{
+				middleware.HeaderUserID:        "user1",
+				middleware.HeaderRestaurantIDs: "rest1, rest2,",
+				middleware.HeaderRoles:         "restaurant_staff",
+			},
+			expectedBody: "user1|rest1,rest2|restaurant_staff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+type stubAuthenticator struct{}
+
+func (stubAuthenticator) Authenticate(_ context.Context, accessToken string) (*tenant.Principal, error) {
+	if accessToken != "valid" {
+		return nil, errors.New("invalid access token")
+	}
+	return &tenant.Principal{UserID: "token-user", Roles: []tenant.Role{tenant.RoleUser}}, nil
+}
+
+func TestPrincipalMiddleware_BearerToken(t *testing.T) {
+	newApp := func(trustGatewayHeaders, requirePrincipal bool) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+		var gateway middleware.GatewayTrust
+		if trustGatewayHeaders {
+			gateway = anyGateway
+		}
+		app.Use(middleware.PrincipalMiddleware(stubAuthenticator{}, gateway))
+		if requirePrincipal {
+			app.Use(middleware.RequirePrincipalMiddleware(true))
+		}
+		handler := func(c fiber.Ctx) error {
+			ctx, _ := c.Locals("ctx").(context.Context)
+			if principal, ok := tenant.FromContext(ctx); ok {
+				return c.SendString(principal.UserID)
+			}
+			return c.SendString("anonymous")
+		}
+		app.Get("/test", handler)
+		app.Post("/test", handler)
+		return app
+	}
+
+	tests := []struct {
+		name                string
+		trustGatewayHeaders bool
+		requirePrincipal    bool
+		method              string
+		headers             map[string]string
+		expectedStatus      int
+		expectedBody        string
+	}{
+		{
+			name:           "valid token",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Authorization": "Bearer valid", middleware.HeaderUserID: "spoofed"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "token-user",
+		},
+		{
+			name:           "invalid token",
+			method:         http.MethodGet,
+			headers:        map[string]string{"Authorization": "Bearer forged"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "untrusted gateway headers",
+			method:         http.MethodGet,
+			headers:        map[string]string{middleware.HeaderUserID: "spoofed"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "anonymous",
+		},
+		{
+			name:                "trusted gateway headers",
+			trustGatewayHeaders: true,
+			method:              http.MethodGet,
+			headers:             map[string]string{middleware.HeaderUserID: "gateway-user"},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "gateway-user",
+		},
+		{
+			name:             "anonymous read when required for writes",
+			requirePrincipal: true,
+			method:           http.MethodGet,
+			expectedStatus:   http.StatusOK,
+			expectedBody:     "anonymous",
+		},
+		{
+			name:             "anonymous write when required for writes",
+			requirePrincipal: true,
+			method:           http.MethodPost,
+			expectedStatus:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/test", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := newApp(tt.trustGatewayHeaders, tt.requirePrincipal).Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"))
+				return
+			}
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestPrincipalMiddleware_GatewayTrust(t *testing.T) {
+	_, err := middleware.NewGatewayTrust(nil, "")
+	assert.ErrorIs(t, err, middleware.ErrInvalidGatewayTrust, "trusting every request")
+	_, err = middleware.NewGatewayTrust([]string{"10.0.0.1"}, "")
+	assert.ErrorIs(t, err, middleware.ErrInvalidGatewayTrust)
+
+	otherNetwork, err := middleware.NewGatewayTrust([]string{"10.0.0.0/8"}, "")
+	require.NoError(t, err)
+	withSecret, err := middleware.NewGatewayTrust(nil, "gateway-secret")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		gateway      middleware.GatewayTrust
+		secret       string
+		expectedBody string
+	}{
+		{name: "no gateway", expectedBody: "anonymous"},
+		{name: "request from another network", gateway: otherNetwork, expectedBody: "anonymous"},
+		{name: "request without the secret", gateway: withSecret, expectedBody: "anonymous"},
+		{name: "request with a wrong secret", gateway: withSecret, secret: "guess", expectedBody: "anonymous"},
+		{name: "request with the secret", gateway: withSecret, secret: "gateway-secret", expectedBody: "admin1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+			app.Use(middleware.PrincipalMiddleware(nil, tt.gateway))
+			app.Get("/test", func(c fiber.Ctx) error {
+				ctx, _ := c.Locals("ctx").(context.Context)
+				if principal, ok := tenant.FromContext(ctx); ok {
+					return c.SendString(principal.UserID)
+				}
+				return c.SendString("anonymous")
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(middleware.HeaderUserID, "admin1")
+			req.Header.Set(middleware.HeaderRoles, "admin")
+			if tt.secret != "" {
+				req.Header.Set(middleware.HeaderGatewaySecret, tt.secret)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(body))
+		})
+	}
+}
+
+func TestRequireRoleMiddleware(t *testing.T) {
+	app := fiber.New()
+
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.PrincipalMiddleware(nil, anyGateway))
+	app.Post("/admin", func(c fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	}, middleware.RequireRoleMiddleware(tenant.RoleAdmin))
+	app.Post("/confirm", func(c fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	}, middleware.RequireRoleMiddleware(tenant.RoleRestaurantStaff, tenant.RoleAdmin, tenant.RoleGuest))
+
+	tests := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		expected int
+	}{
+		{name: "guest on admin route", path: "/admin", expected: http.StatusUnauthorized},
+		{name: "user on admin route", path: "/admin", headers: map[string]string{middleware.HeaderUserID: "user1"}, expected: http.StatusForbidden},
+		{name: "admin on admin route", path: "/admin", headers: map[string]string{middleware.HeaderUserID: "user1", middleware.HeaderRoles: "admin"}, expected: http.StatusNoContent},
+		{name: "guest allowed", path: "/confirm", expected: http.StatusNoContent},
+		{name: "user on restaurant route", path: "/confirm", headers: map[string]string{middleware.HeaderUserID: "user1", middleware.HeaderRoles: "user"}, expected: http.StatusForbidden},
+		{name: "staff named by restaurants", path: "/confirm", headers: map[string]string{middleware.HeaderUserID: "user1", middleware.HeaderRestaurantIDs: "rest1"}, expected: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.StatusCode)
+		})
+	}
+}
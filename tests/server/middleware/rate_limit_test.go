@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLimiter allows the first limit requests and remembers the client of the last one.
+type countingLimiter struct {
+	limit    int
+	requests int
+	clientIP string
+}
+
+func (l *countingLimiter) Allow(ctx context.Context) (bool, time.Duration) {
+	l.requests++
+	l.clientIP = tenant.ClientIPFromContext(ctx)
+	return l.requests <= l.limit, 1500 * time.Millisecond
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := &countingLimiter{limit: 1}
+
+	app := fiber.New()
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.PrincipalMiddleware(nil, anyGateway))
+	app.Use(middleware.RateLimitMiddleware(limiter))
+	app.Get("/test", func(c fiber.Ctx) error {
+		return c.SendString("read")
+	})
+	app.Post("/test", func(c fiber.Ctx) error {
+		return c.SendString("written")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "0.0.0.0", limiter.clientIP)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, limiter.requests)
+}
@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingRecorder struct {
+	apiKeys  []string
+	requests []*domain.RecordedRequest
+}
+
+func (r *recordingRecorder) RecordRequest(_ context.Context, apiKey string, request *domain.RecordedRequest) {
+	r.apiKeys = append(r.apiKeys, apiKey)
+	r.requests = append(r.requests, request)
+}
+
+func TestRequestRecorderMiddleware(t *testing.T) {
+	recorder := &recordingRecorder{}
+
+	app := fiber.New()
+	app.Use(middleware.LoggingMiddleware(middleware.LogSampling{}))
+	app.Use(middleware.RequestRecorderMiddleware(recorder))
+
+	app.Post("/api/v1/bookings", func(c fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).SendString("created")
+	})
+	app.Get("/api/v1/missing", func(fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+	app.Get("/api/v1/admin/requests", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/bookings?date=2025-01-01", strings.NewReader(`{"user_id":"user1"}`))
+	req.Header.Set(middleware.HeaderAPIKey, "partner-key")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/missing", nil)
+	req.Header.Set(middleware.HeaderAPIKey, "partner-key")
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	// Neither requests without an API key nor the admin API are recorded.
+	resp, err = app.Test(httptest.NewRequest(http.MethodPost, "/api/v1/bookings", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/requests", nil)
+	req.Header.Set(middleware.HeaderAPIKey, "partner-key")
+	_, err = app.Test(req)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.requests, 2)
+	assert.Equal(t, []string{"partner-key", "partner-key"}, recorder.apiKeys)
+
+	created := recorder.requests[0]
+	assert.Equal(t, http.MethodPost, created.Method)
+	assert.Equal(t, "/api/v1/bookings?date=2025-01-01", created.Path)
+	assert.Equal(t, `{"user_id":"user1"}`, created.Body)
+	assert.Equal(t, "application/json", created.Headers["Content-Type"])
+	assert.Equal(t, fiber.StatusCreated, created.Status)
+	assert.False(t, created.RecordedAt.IsZero())
+
+	assert.Equal(t, fiber.StatusNotFound, recorder.requests[1].Status)
+}
@@ -0,0 +1,48 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	statuses []int
+}
+
+func (o *recordingObserver) ObserveRequest(_ time.Time, status int, _ time.Duration) {
+	o.statuses = append(o.statuses, status)
+}
+
+func TestSLOMiddleware(t *testing.T) {
+	observer := &recordingObserver{}
+	app := fiber.New()
+
+	app.Use(middleware.SLOMiddleware(observer, []string{"/health"}))
+
+	app.Get("/ok", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+	app.Get("/broken", func(c fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "busy")
+	})
+	app.Get("/health", func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for _, route := range []string{"/ok", "/broken", "/health", "/missing"} {
+		req, err := http.NewRequest(http.MethodGet, route, nil)
+		require.NoError(t, err)
+
+		_, err = app.Test(req)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusNotFound}, observer.statuses)
+}
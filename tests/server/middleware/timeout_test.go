@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/server/middleware"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	var parentErr error
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", context.Background())
+		err := c.Next()
+		parentErr = c.Locals("ctx").(context.Context).Err()
+		return err
+	})
+	app.Use(middleware.TimeoutMiddleware(20*time.Millisecond,
+		middleware.TimeoutRoute{Method: fiber.MethodGet, Path: "/reports/:id", Timeout: time.Second},
+		middleware.TimeoutRoute{Method: fiber.MethodGet, Path: "/stream"},
+	))
+
+	slow := func(c fiber.Ctx) error {
+		ctx := c.Locals("ctx").(context.Context)
+		select {
+		case <-ctx.Done():
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": ctx.Err().Error()})
+		case <-time.After(100 * time.Millisecond):
+			return c.SendString("done")
+		}
+	}
+	app.Get("/slow", slow)
+	app.Get("/reports/:id", slow)
+	app.Get("/fast", func(c fiber.Ctx) error {
+		return c.SendString("fast")
+	})
+	app.Get("/stream", func(c fiber.Ctx) error {
+		if _, ok := c.Locals("ctx").(context.Context).Deadline(); ok {
+			return c.SendString("deadline")
+		}
+		return c.SendString("no deadline")
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"slow request", "/slow", http.StatusGatewayTimeout, common.ErrRequestTimeout},
+		{"fast request", "/fast", http.StatusOK, "fast"},
+		{"route timeout", "/reports/42", http.StatusOK, "done"},
+		{"route without timeout", "/stream", http.StatusOK, "no deadline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Contains(t, string(body), tt.wantBody)
+			assert.NoError(t, parentErr, "the request context is restored after the handler")
+		})
+	}
+}
+
+func TestTimeoutMiddlewareKeepsSuccessfulResponse(t *testing.T) {
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals("ctx", context.Background())
+		return c.Next()
+	})
+	app.Use(middleware.TimeoutMiddleware(10 * time.Millisecond))
+	app.Get("/late", func(c fiber.Ctx) error {
+		<-c.Locals("ctx").(context.Context).Done()
+		return c.Status(fiber.StatusCreated).SendString("created")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/late", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+}
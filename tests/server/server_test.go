@@ -2,16 +2,26 @@ package server_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/configs"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
 	"github.com/flexer2006/case-back-restaurant-go/internal/server"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -108,6 +118,14 @@ func TestNewServer(t *testing.T) {
 	factsUseCase := new(MockFactsUseCase)
 	availabilityUseCase := new(MockAvailabilityUseCase)
 	notificationUseCase := new(MockNotificationUseCase)
+	catalogUseCase := new(MockCatalogUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	requestReplayUseCase := new(MockRequestReplayUseCase)
+	notificationReceiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	imageUseCase := new(MockImageUseCase)
+	reviewUseCase := new(MockReviewUseCase)
+	abuseUseCase := new(MockAbuseUseCase)
 
 	s, err := server.NewServer(
 		ctx,
@@ -118,6 +136,30 @@ func TestNewServer(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		new(MockSLOUseCase), new(MockFaultInjectionUseCase), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), new(MockCustomDomainUseCase), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 
 	require.NoError(t, err)
@@ -134,6 +176,14 @@ func TestRegisterRoutes(t *testing.T) {
 	factsUseCase := new(MockFactsUseCase)
 	availabilityUseCase := new(MockAvailabilityUseCase)
 	notificationUseCase := new(MockNotificationUseCase)
+	catalogUseCase := new(MockCatalogUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	requestReplayUseCase := new(MockRequestReplayUseCase)
+	notificationReceiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	imageUseCase := new(MockImageUseCase)
+	reviewUseCase := new(MockReviewUseCase)
+	abuseUseCase := new(MockAbuseUseCase)
 
 	s, err := server.NewServer(
 		ctx,
@@ -144,6 +194,30 @@ func TestRegisterRoutes(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		new(MockSLOUseCase), new(MockFaultInjectionUseCase), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), new(MockCustomDomainUseCase), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 	require.NoError(t, err)
 
@@ -159,7 +233,7 @@ func TestStartAndStopServer(t *testing.T) {
 
 	ctx := context.Background()
 	config := createTestConfig()
-	config.Server.Port = 9090 + int(time.Now().Unix()%100)
+	config.Server.Port = freePort(t)
 
 	config.Shutdown.Timeout = 500 * time.Millisecond
 
@@ -169,6 +243,14 @@ func TestStartAndStopServer(t *testing.T) {
 	factsUseCase := new(MockFactsUseCase)
 	availabilityUseCase := new(MockAvailabilityUseCase)
 	notificationUseCase := new(MockNotificationUseCase)
+	catalogUseCase := new(MockCatalogUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	requestReplayUseCase := new(MockRequestReplayUseCase)
+	notificationReceiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	imageUseCase := new(MockImageUseCase)
+	reviewUseCase := new(MockReviewUseCase)
+	abuseUseCase := new(MockAbuseUseCase)
 
 	mockLogger := new(MockLogger)
 	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
@@ -187,6 +269,30 @@ func TestStartAndStopServer(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		newObservingSLOUseCase(), newFaultlessInjectionUseCase(), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), newUnknownHostsUseCase(), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 	require.NoError(t, err)
 
@@ -198,7 +304,7 @@ func TestStartAndStopServer(t *testing.T) {
 		t.Logf("server shutdown with result: %v", err)
 	}()
 
-	time.Sleep(1 * time.Second)
+	waitForServer(t, config.Server.Port)
 
 	t.Logf("checking server availability")
 	initialResp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/", config.Server.Port))
@@ -233,6 +339,295 @@ func TestStartAndStopServer(t *testing.T) {
 	mockLogger.AssertCalled(t, "Info", mock.Anything, mock.Anything)
 }
 
+func TestServerCompressionAndBodyLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping server test in short mode")
+	}
+
+	ctx := context.Background()
+	config := createTestConfig()
+	config.Server.Port = freePort(t)
+	config.Server.BodyLimit = 1024
+	config.Shutdown.Timeout = 500 * time.Millisecond
+
+	restaurantUseCase := new(MockRestaurantUseCase)
+	restaurants := make([]*domain.Restaurant, 0, 20)
+	for i := range 20 {
+		restaurants = append(restaurants, &domain.Restaurant{
+			ID:          fmt.Sprintf("restaurant%d", i),
+			Name:        "Pelmennaya",
+			Description: "Dumplings made by hand every morning",
+		})
+	}
+	restaurantUseCase.On("ListRestaurants", mock.Anything, domain.RestaurantFilter{}, usecase.PageRequest{Limit: 20}).
+		Return(&usecase.PageResponse[*domain.Restaurant]{Items: restaurants, NextCursor: "next"}, nil)
+	restaurantUseCase.On("EstimateRestaurantCount", mock.Anything).Return(int64(50), nil)
+
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+	mockLogger.On("With", mock.Anything).Return(mockLogger).Maybe()
+	mockLogger.On("Sync").Return(nil).Maybe()
+	ctx = logger.NewContext(ctx, mockLogger)
+
+	s, err := server.NewServer(
+		ctx,
+		config,
+		restaurantUseCase,
+		new(MockBookingUseCase),
+		new(MockUserUseCase),
+		new(MockFactsUseCase),
+		new(MockAvailabilityUseCase),
+		new(MockNotificationUseCase),
+		new(MockCatalogUseCase),
+		new(MockBookingLinkUseCase),
+		new(MockRequestReplayUseCase),
+		new(MockNotificationReceiptUseCase),
+		new(MockMenuUseCase),
+		new(MockImageUseCase),
+		new(MockReviewUseCase),
+		new(MockAbuseUseCase),
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		newObservingSLOUseCase(), newFaultlessInjectionUseCase(), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), newUnknownHostsUseCase(), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
+	)
+	require.NoError(t, err)
+	s.RegisterRoutes()
+
+	go func() {
+		_ = s.Start(ctx)
+	}()
+	defer func() {
+		stopCtx, stopCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer stopCancel()
+		_ = s.Stop(stopCtx)
+	}()
+
+	waitForServer(t, config.Server.Port)
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", config.Server.Port)
+
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/api/v1/restaurants", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+	require.NoError(t, resp.Body.Close())
+
+	body := strings.NewReader(`{"name":"` + strings.Repeat("a", 2048) + `"}`)
+	resp, err = http.Post(baseURL+"/api/v1/users", "application/json", body)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	var errorBody map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errorBody))
+	assert.Contains(t, errorBody["error"], "1024 bytes")
+}
+
+func TestServerHidesDiagnostics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping server test in short mode")
+	}
+
+	ctx := context.Background()
+	config := createTestConfig()
+	config.Server.Port = freePort(t)
+	config.Shutdown.Timeout = 500 * time.Millisecond
+
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Warn", mock.Anything, mock.Anything).Return().Maybe()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+	mockLogger.On("With", mock.Anything).Return(mockLogger).Maybe()
+	mockLogger.On("Sync").Return(nil).Maybe()
+	ctx = logger.NewContext(ctx, mockLogger)
+
+	s, err := server.NewServer(
+		ctx,
+		config,
+		new(MockRestaurantUseCase),
+		new(MockBookingUseCase),
+		new(MockUserUseCase),
+		new(MockFactsUseCase),
+		new(MockAvailabilityUseCase),
+		new(MockNotificationUseCase),
+		new(MockCatalogUseCase),
+		new(MockBookingLinkUseCase),
+		new(MockRequestReplayUseCase),
+		new(MockNotificationReceiptUseCase),
+		new(MockMenuUseCase),
+		new(MockImageUseCase),
+		new(MockReviewUseCase),
+		new(MockAbuseUseCase),
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		newObservingSLOUseCase(), newFaultlessInjectionUseCase(), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), newUnknownHostsUseCase(), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
+	)
+	require.NoError(t, err)
+	s.RegisterRoutes()
+
+	go func() {
+		_ = s.Start(ctx)
+	}()
+	defer func() {
+		stopCtx, stopCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer stopCancel()
+		_ = s.Stop(stopCtx)
+	}()
+
+	waitForServer(t, config.Server.Port)
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", config.Server.Port)
+
+	// The metrics and profiles are served on the diagnostics port only.
+	for _, path := range []string{"/debug/vars", "/debug/pprof/"} {
+		resp, err := http.Get(baseURL + path)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+		require.NoError(t, resp.Body.Close())
+	}
+}
+
+func TestServerEmbedCORS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping server test in short mode")
+	}
+
+	ctx := context.Background()
+	config := createTestConfig()
+	config.Server.Port = freePort(t)
+	config.Shutdown.Timeout = 500 * time.Millisecond
+	config.Embed.AllowedOrigins = []string{"https://pasta.example"}
+
+	// Start needs a logger in the context and fails right away without one.
+	mockLogger := new(MockLogger)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
+	mockLogger.On("Error", mock.Anything, mock.Anything).Return().Maybe()
+	mockLogger.On("With", mock.Anything).Return(mockLogger).Maybe()
+	mockLogger.On("Sync").Return(nil).Maybe()
+	ctx = logger.NewContext(ctx, mockLogger)
+
+	s, err := server.NewServer(
+		ctx,
+		config,
+		new(MockRestaurantUseCase),
+		new(MockBookingUseCase),
+		new(MockUserUseCase),
+		new(MockFactsUseCase),
+		new(MockAvailabilityUseCase),
+		new(MockNotificationUseCase),
+		new(MockCatalogUseCase),
+		new(MockBookingLinkUseCase),
+		new(MockRequestReplayUseCase),
+		new(MockNotificationReceiptUseCase),
+		new(MockMenuUseCase),
+		new(MockImageUseCase),
+		new(MockReviewUseCase),
+		new(MockAbuseUseCase),
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		newObservingSLOUseCase(), newFaultlessInjectionUseCase(), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), newUnknownHostsUseCase(), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
+	)
+	require.NoError(t, err)
+	s.RegisterRoutes()
+
+	go func() {
+		_ = s.Start(ctx)
+	}()
+	defer func() {
+		stopCtx, stopCancel := context.WithTimeout(ctx, 2*time.Second)
+		defer stopCancel()
+		_ = s.Stop(stopCtx)
+	}()
+
+	waitForServer(t, config.Server.Port)
+	baseURL := fmt.Sprintf("http://127.0.0.1:%d", config.Server.Port)
+
+	preflight := func(path, origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, baseURL+path, nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp
+	}
+
+	widget := "/embed/restaurants/restaurant1/availability"
+	assert.Equal(t, "https://pasta.example", preflight(widget, "https://pasta.example").Header.Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, preflight(widget, "https://other.example").Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "*", preflight("/api/v1/restaurants", "https://other.example").Header.Get("Access-Control-Allow-Origin"))
+}
+
+// freePort returns a port of the ephemeral range that nothing listens on.
+func freePort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+	return port
+}
+
+// waitForServer waits until the server started in the background accepts connections on the port.
+func waitForServer(t *testing.T, port int) {
+	t.Helper()
+
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	require.Eventually(t, func() bool {
+		conn, err := net.DialTimeout("tcp", address, 100*time.Millisecond)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 5*time.Second, 20*time.Millisecond, "server is not listening on %s", address)
+}
+
 func TestServerWithConfig(t *testing.T) {
 	ctx := context.Background()
 	config := createTestConfig()
@@ -243,6 +638,14 @@ func TestServerWithConfig(t *testing.T) {
 	factsUseCase := new(MockFactsUseCase)
 	availabilityUseCase := new(MockAvailabilityUseCase)
 	notificationUseCase := new(MockNotificationUseCase)
+	catalogUseCase := new(MockCatalogUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	requestReplayUseCase := new(MockRequestReplayUseCase)
+	notificationReceiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	imageUseCase := new(MockImageUseCase)
+	reviewUseCase := new(MockReviewUseCase)
+	abuseUseCase := new(MockAbuseUseCase)
 
 	s1, err := server.NewServer(
 		ctx,
@@ -253,6 +656,30 @@ func TestServerWithConfig(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		new(MockSLOUseCase), new(MockFaultInjectionUseCase), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), new(MockCustomDomainUseCase), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 	require.NoError(t, err)
 	assert.NotNil(t, s1)
@@ -267,6 +694,30 @@ func TestServerWithConfig(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		new(MockSLOUseCase), new(MockFaultInjectionUseCase), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), new(MockCustomDomainUseCase), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 	require.NoError(t, err)
 	assert.NotNil(t, s2)
@@ -282,6 +733,14 @@ func TestStopServerWithContext(t *testing.T) {
 	factsUseCase := new(MockFactsUseCase)
 	availabilityUseCase := new(MockAvailabilityUseCase)
 	notificationUseCase := new(MockNotificationUseCase)
+	catalogUseCase := new(MockCatalogUseCase)
+	bookingLinkUseCase := new(MockBookingLinkUseCase)
+	requestReplayUseCase := new(MockRequestReplayUseCase)
+	notificationReceiptUseCase := new(MockNotificationReceiptUseCase)
+	menuUseCase := new(MockMenuUseCase)
+	imageUseCase := new(MockImageUseCase)
+	reviewUseCase := new(MockReviewUseCase)
+	abuseUseCase := new(MockAbuseUseCase)
 
 	mockLogger := new(MockLogger)
 	mockLogger.On("Info", mock.Anything, mock.Anything).Return()
@@ -298,6 +757,30 @@ func TestStopServerWithContext(t *testing.T) {
 		factsUseCase,
 		availabilityUseCase,
 		notificationUseCase,
+		catalogUseCase,
+		bookingLinkUseCase,
+		requestReplayUseCase,
+		notificationReceiptUseCase,
+		menuUseCase,
+		imageUseCase,
+		reviewUseCase,
+		abuseUseCase,
+		geo.NopLocator{},
+		new(MockRetentionUseCase),
+		new(MockExportUseCase),
+		new(MockSyncUseCase),
+		new(MockNotificationRetryUseCase),
+		new(MockAnalyticsUseCase),
+		new(MockQuotaUseCase),
+		new(MockBillingUseCase),
+		new(MockIncentiveUseCase),
+		new(MockGeocodingUseCase), new(MockAvailabilityAlertUseCase),
+		new(MockBulkCancellationUseCase), new(MockOrganizationUseCase), new(MockBookingTransferUseCase),
+		new(MockBookingSheetUseCase),
+		new(MockDisplayBoardUseCase),
+		new(MockSLOUseCase), new(MockFaultInjectionUseCase), new(MockBookingSyncUseCase), new(MockRestaurantClaimUseCase), new(MockCustomDomainUseCase), new(MockWidgetSettingsUseCase), new(MockBookingDraftUseCase), new(MockAuthUseCase),
+		new(MockRestaurantClosureUseCase),
+		new(MockTableUseCase),
 	)
 	require.NoError(t, err)
 
@@ -309,7 +792,7 @@ func TestStopServerWithContext(t *testing.T) {
 	_ = s.Stop(timeoutCtx)
 }
 
-func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -317,11 +800,44 @@ func (m *MockRestaurantUseCase) GetRestaurant(ctx context.Context, id string) (*
 	return args.Get(0).(*domain.Restaurant), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) ListRestaurants(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
-	args := m.Called(ctx, offset, limit)
+func (m *MockRestaurantUseCase) GetRestaurantBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ListRestaurants(
+	ctx context.Context,
+	filter domain.RestaurantFilter,
+	page usecase.PageRequest,
+) (*usecase.PageResponse[*domain.Restaurant], error) {
+	args := m.Called(ctx, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Restaurant]), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) SearchRestaurants(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, offset, limit)
 	return args.Get(0).([]*domain.Restaurant), args.Error(1)
 }
 
+func (m *MockRestaurantUseCase) EstimateRestaurantCount(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) GetSchedule(ctx context.Context, restaurantID domain.RestaurantID, from, to time.Time) ([]domain.ScheduleDay, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ScheduleDay), args.Error(1)
+}
+
 func (m *MockRestaurantUseCase) CreateRestaurant(ctx context.Context, restaurant *domain.Restaurant) (string, error) {
 	args := m.Called(ctx, restaurant)
 	return args.String(0), args.Error(1)
@@ -332,20 +848,20 @@ func (m *MockRestaurantUseCase) UpdateRestaurant(ctx context.Context, restaurant
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) DeleteRestaurant(ctx context.Context, id string) error {
+func (m *MockRestaurantUseCase) DeleteRestaurant(ctx context.Context, id domain.RestaurantID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) AddFact(ctx context.Context, restaurantID string, content string) (*domain.Fact, error) {
-	args := m.Called(ctx, restaurantID, content)
+func (m *MockRestaurantUseCase) AddFact(ctx context.Context, restaurantID domain.RestaurantID, content, locale string) (*domain.Fact, error) {
+	args := m.Called(ctx, restaurantID, content, locale)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*domain.Fact), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *MockRestaurantUseCase) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
@@ -355,17 +871,56 @@ func (m *MockRestaurantUseCase) GetRandomFacts(ctx context.Context, count int) (
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
-func (m *MockRestaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID string, workingHours *domain.WorkingHours) error {
+func (m *MockRestaurantUseCase) SetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID, workingHours *domain.WorkingHours) error {
 	args := m.Called(ctx, restaurantID, workingHours)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (m *MockRestaurantUseCase) GetWorkingHours(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]*domain.WorkingHours), args.Error(1)
 }
 
-func (m *MockBookingUseCase) GetBooking(ctx context.Context, id string) (*domain.Booking, error) {
+func (m *MockRestaurantUseCase) SetSpecialDay(ctx context.Context, day *domain.SpecialDay) error {
+	args := m.Called(ctx, day)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantUseCase) ListSpecialDays(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.SpecialDay, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SpecialDay), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) DeleteSpecialDay(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) error {
+	args := m.Called(ctx, restaurantID, date)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantUseCase) ImportRestaurants(ctx context.Context, r io.Reader, dryRun bool) (*usecase.RestaurantImportReport, error) {
+	args := m.Called(ctx, r, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RestaurantImportReport), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ExportRestaurant(ctx context.Context, id domain.RestaurantID) (*usecase.RestaurantBundle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RestaurantBundle), args.Error(1)
+}
+
+func (m *MockRestaurantUseCase) ImportRestaurantBundle(ctx context.Context, bundle *usecase.RestaurantBundle) (string, error) {
+	args := m.Called(ctx, bundle)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetBooking(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -373,14 +928,25 @@ func (m *MockBookingUseCase) GetBooking(ctx context.Context, id string) (*domain
 	return args.Get(0).(*domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingUseCase) GetRestaurantBookings(ctx context.Context, restaurantID string) ([]*domain.Booking, error) {
-	args := m.Called(ctx, restaurantID)
-	return args.Get(0).([]*domain.Booking), args.Error(1)
+func (m *MockBookingUseCase) GetRestaurantBookings(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	page usecase.PageRequest,
+) (*usecase.PageResponse[*domain.Booking], error) {
+	args := m.Called(ctx, restaurantID, filter, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Booking]), args.Error(1)
 }
 
-func (m *MockBookingUseCase) GetUserBookings(ctx context.Context, userID string) ([]*domain.Booking, error) {
-	args := m.Called(ctx, userID)
-	return args.Get(0).([]*domain.Booking), args.Error(1)
+func (m *MockBookingUseCase) GetUserBookings(ctx context.Context, userID domain.UserID, page usecase.PageRequest) (*usecase.PageResponse[*domain.Booking], error) {
+	args := m.Called(ctx, userID, page)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.PageResponse[*domain.Booking]), args.Error(1)
 }
 
 func (m *MockBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
@@ -388,27 +954,27 @@ func (m *MockBookingUseCase) CreateBooking(ctx context.Context, booking *domain.
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockBookingUseCase) ConfirmBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) ConfirmBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) RejectBooking(ctx context.Context, id string, reason string) error {
+func (m *MockBookingUseCase) RejectBooking(ctx context.Context, id domain.BookingID, reason string) error {
 	args := m.Called(ctx, id, reason)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) CompleteBooking(ctx context.Context, id string) error {
+func (m *MockBookingUseCase) CompleteBooking(ctx context.Context, id domain.BookingID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockBookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID string, date time.Time, time string, message string) (string, error) {
+func (m *MockBookingUseCase) SuggestAlternativeTime(ctx context.Context, bookingID domain.BookingID, date time.Time, time string, message string) (string, error) {
 	args := m.Called(ctx, bookingID, date, time, message)
 	return args.String(0), args.Error(1)
 }
@@ -423,7 +989,7 @@ func (m *MockBookingUseCase) RejectAlternative(ctx context.Context, alternativeI
 	return args.Error(0)
 }
 
-func (m *MockUserUseCase) GetUser(ctx context.Context, id string) (*domain.User, error) {
+func (m *MockUserUseCase) GetUser(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -454,31 +1020,97 @@ func (m *MockFactsUseCase) GetRandomFacts(ctx context.Context, count int) ([]dom
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
-func (m *MockFactsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *MockFactsUseCase) GetFilteredRandomFacts(ctx context.Context, filter domain.FactFilter, count int) ([]domain.Fact, error) {
+	args := m.Called(ctx, filter, count)
+	return args.Get(0).([]domain.Fact), args.Error(1)
+}
+
+func (m *MockFactsUseCase) GetRestaurantFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
-func (m *MockAvailabilityUseCase) GetAvailability(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (m *MockFactsUseCase) GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error) {
+	args := m.Called(ctx, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Fact), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) GetAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	args := m.Called(ctx, restaurantID, date)
 	return args.Get(0).([]*domain.Availability), args.Error(1)
 }
 
+func (m *MockAvailabilityUseCase) GetAvailabilityDelta(ctx context.Context, restaurantID domain.RestaurantID, since domain.SyncCursor, limit int) (*usecase.AvailabilityDelta, error) {
+	args := m.Called(ctx, restaurantID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.AvailabilityDelta), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) GetSlotBookings(ctx context.Context, restaurantID, availabilityID string) ([]*domain.Booking, error) {
+	args := m.Called(ctx, restaurantID, availabilityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) DeleteAvailability(ctx context.Context, restaurantID, availabilityID string) error {
+	args := m.Called(ctx, restaurantID, availabilityID)
+	return args.Error(0)
+}
+
 func (m *MockAvailabilityUseCase) SetAvailability(ctx context.Context, availability *domain.Availability) error {
 	args := m.Called(ctx, availability)
 	return args.Error(0)
 }
 
+func (m *MockAvailabilityUseCase) ForceSetAvailability(ctx context.Context, availability *domain.Availability) ([]*domain.Booking, error) {
+	args := m.Called(ctx, availability)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
 func (m *MockAvailabilityUseCase) UpdateReservedSeats(ctx context.Context, availabilityID string, delta int) error {
 	args := m.Called(ctx, availabilityID, delta)
 	return args.Error(0)
 }
 
-func (m *MockAvailabilityUseCase) CheckAvailability(ctx context.Context, restaurantID string, date time.Time, timeSlot string, guestsCount int) (bool, error) {
+func (m *MockAvailabilityUseCase) ReconcileReservedSeats(ctx context.Context) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) ReservedSeatsReport(ctx context.Context, date time.Time, fix bool) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx, date, fix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
+func (m *MockAvailabilityUseCase) CheckAvailability(ctx context.Context, restaurantID domain.RestaurantID, date time.Time, timeSlot string, guestsCount int) (bool, error) {
 	args := m.Called(ctx, restaurantID, date, timeSlot, guestsCount)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockAvailabilityUseCase) GenerateAvailability(ctx context.Context, params usecase.GenerateAvailabilityParams) ([]*domain.Availability, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Availability), args.Error(1)
+}
+
 func (m *MockNotificationUseCase) NotifyRestaurant(ctx context.Context, restaurantID string, notificationType domain.NotificationType, title, message, relatedID string) error {
 	args := m.Called(ctx, restaurantID, notificationType, title, message, relatedID)
 	return args.Error(0)
@@ -498,3 +1130,1082 @@ func (m *MockNotificationUseCase) MarkAsRead(ctx context.Context, notificationID
 	args := m.Called(ctx, notificationID)
 	return args.Error(0)
 }
+
+func (m *MockNotificationUseCase) ResendNotification(ctx context.Context, notificationID string) error {
+	args := m.Called(ctx, notificationID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationUseCase) GetNotificationSettings(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	args := m.Called(ctx, recipientType, recipientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationUseCase) UpdateNotificationSettings(ctx context.Context, settings *domain.NotificationSettings) (*domain.NotificationSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationSettings), args.Error(1)
+}
+
+type MockCatalogUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCatalogUseCase) GetCatalogPage(ctx context.Context, page, pageSize int) (*usecase.CatalogPage, error) {
+	args := m.Called(ctx, page, pageSize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.CatalogPage), args.Error(1)
+}
+
+type MockBookingLinkUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingLinkUseCase) CreateBookingLink(ctx context.Context, bookingID domain.BookingID, options usecase.BookingLinkOptions) (*usecase.IssuedBookingLink, error) {
+	args := m.Called(ctx, bookingID, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.IssuedBookingLink), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) IssueConfirmationLinks(ctx context.Context, bookingID domain.BookingID) (*usecase.IssuedBookingLink, *usecase.IssuedBookingLink, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*usecase.IssuedBookingLink), args.Get(1).(*usecase.IssuedBookingLink), args.Error(2)
+}
+
+func (m *MockBookingLinkUseCase) ResolveBookingLink(ctx context.Context, token string) (*usecase.ResolvedBookingLink, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ResolvedBookingLink), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) UseBookingLink(ctx context.Context, token string, action domain.BookingLinkAction, fn func(ctx context.Context, booking *domain.Booking) error) (*domain.Booking, error) {
+	args := m.Called(ctx, token, action, fn)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingLinkUseCase) CheckInToken(ctx context.Context, bookingID domain.BookingID) (string, error) {
+	args := m.Called(ctx, bookingID)
+	return args.String(0), args.Error(1)
+}
+
+type MockRequestReplayUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRequestReplayUseCase) RecordRequest(ctx context.Context, apiKey string, request *domain.RecordedRequest) {
+	m.Called(ctx, apiKey, request)
+}
+
+func (m *MockRequestReplayUseCase) ListRecordedRequests(ctx context.Context, apiKeyID string) ([]*domain.RecordedRequest, error) {
+	args := m.Called(ctx, apiKeyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecordedRequest), args.Error(1)
+}
+
+func (m *MockRequestReplayUseCase) ReplayRequest(ctx context.Context, id string) (*usecase.ReplayResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ReplayResult), args.Error(1)
+}
+
+type MockNotificationReceiptUseCase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationReceiptUseCase) TrackRead(ctx context.Context, notificationID string) error {
+	args := m.Called(ctx, notificationID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationReceiptUseCase) ConfirmRead(ctx context.Context, notificationID string) (*domain.Notification, error) {
+	args := m.Called(ctx, notificationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationReceiptUseCase) GetBookingDeliveries(ctx context.Context, booking *domain.Booking) ([]domain.NotificationDelivery, error) {
+	args := m.Called(ctx, booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.NotificationDelivery), args.Error(1)
+}
+
+type MockMenuUseCase struct {
+	mock.Mock
+}
+
+func (m *MockMenuUseCase) GetMenu(ctx context.Context, restaurantID string, filter domain.MenuFilter) ([]domain.MenuItem, error) {
+	args := m.Called(ctx, restaurantID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MenuItem), args.Error(1)
+}
+
+func (m *MockMenuUseCase) UpdateMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) ([]domain.MenuItem, error) {
+	args := m.Called(ctx, restaurantID, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MenuItem), args.Error(1)
+}
+
+func (m *MockMenuUseCase) ImportMenu(ctx context.Context, restaurantID string, r io.Reader, options usecase.MenuImportOptions) (*usecase.MenuImportReport, error) {
+	args := m.Called(ctx, restaurantID, r, options)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.MenuImportReport), args.Error(1)
+}
+
+func (m *MockMenuUseCase) GetPreOrder(ctx context.Context, booking *domain.Booking) (*domain.PreOrder, error) {
+	args := m.Called(ctx, booking)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PreOrder), args.Error(1)
+}
+
+func (m *MockMenuUseCase) UpdatePreOrder(ctx context.Context, bookingID domain.BookingID, items []domain.PreOrderItem) (*domain.PreOrder, error) {
+	args := m.Called(ctx, bookingID, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PreOrder), args.Error(1)
+}
+
+type MockImageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImageUseCase) UploadImage(ctx context.Context, restaurantID string, data []byte) (*domain.RestaurantImage, error) {
+	args := m.Called(ctx, restaurantID, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageUseCase) ListImages(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageUseCase) DeleteImage(ctx context.Context, restaurantID, imageID string) error {
+	args := m.Called(ctx, restaurantID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockImageUseCase) GetImageVariant(ctx context.Context, imageID string, opts imaging.Options, accepted []string) (*usecase.ImageVariant, error) {
+	args := m.Called(ctx, imageID, opts, accepted)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.ImageVariant), args.Error(1)
+}
+
+func (m *MockImageUseCase) GenerateVariants(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+type MockReviewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReviewUseCase) CreateReview(ctx context.Context, bookingID domain.BookingID, rating int, text string) (*domain.Review, error) {
+	args := m.Called(ctx, bookingID, rating, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ListReviews(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ReplyToReview(ctx context.Context, restaurantID, reviewID, text string) (*domain.Review, error) {
+	args := m.Called(ctx, restaurantID, reviewID, text)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewUseCase) FlagReview(ctx context.Context, restaurantID, reviewID, reason string) (*domain.ReviewFlag, error) {
+	args := m.Called(ctx, restaurantID, reviewID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ListReviewFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	args := m.Called(ctx, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewUseCase) ResolveReviewFlag(ctx context.Context, flagID string, status domain.ReviewFlagStatus, note string) (*domain.ReviewFlag, error) {
+	args := m.Called(ctx, flagID, status, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewFlag), args.Error(1)
+}
+
+type MockAbuseUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAbuseUseCase) RecordBooking(ctx context.Context, booking *domain.Booking) {
+	m.Called(ctx, booking)
+}
+
+func (m *MockAbuseUseCase) RecordCancellation(ctx context.Context, booking *domain.Booking) {
+	m.Called(ctx, booking)
+}
+
+func (m *MockAbuseUseCase) Allow(ctx context.Context) (bool, time.Duration) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Get(1).(time.Duration)
+}
+
+func (m *MockAbuseUseCase) ListAbuseEvents(ctx context.Context, kind domain.AbuseEventKind) ([]*domain.AbuseEvent, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AbuseEvent), args.Error(1)
+}
+
+type MockRetentionUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRetentionUseCase) ApplyRetention(ctx context.Context, dryRun bool) ([]*domain.RetentionRun, error) {
+	args := m.Called(ctx, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RetentionRun), args.Error(1)
+}
+
+func (m *MockRetentionUseCase) ListRetentionRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error) {
+	args := m.Called(ctx, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RetentionRun), args.Error(1)
+}
+
+type MockExportUseCase struct {
+	mock.Mock
+}
+
+func (m *MockExportUseCase) ExportChanges(ctx context.Context, since time.Time, entities []domain.ExportEntity, emit func(*domain.ExportRecord) error) (time.Time, error) {
+	args := m.Called(ctx, since, entities, emit)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+type MockSyncUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSyncUseCase) ListChanges(ctx context.Context, entity domain.ExportEntity, since domain.SyncCursor, limit int) (*usecase.SyncPage, error) {
+	args := m.Called(ctx, entity, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.SyncPage), args.Error(1)
+}
+
+type MockNotificationRetryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRetryUseCase) RecordFailure(ctx context.Context, failure *domain.NotificationFailure, cause error) error {
+	args := m.Called(ctx, failure, cause)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRetryUseCase) RetryDue(ctx context.Context, limit int) (usecase.NotificationRetryReport, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).(usecase.NotificationRetryReport), args.Error(1)
+}
+
+func (m *MockNotificationRetryUseCase) ListFailures(ctx context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error) {
+	args := m.Called(ctx, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationFailure), args.Error(1)
+}
+
+func (m *MockNotificationRetryUseCase) CountFailures(ctx context.Context) (map[domain.NotificationFailureStatus]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[domain.NotificationFailureStatus]int), args.Error(1)
+}
+
+type MockAnalyticsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsUseCase) GetForecast(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.OccupancyForecast, error) {
+	args := m.Called(ctx, restaurantID, today, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OccupancyForecast), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) GetDemandHeatmap(ctx context.Context, restaurantID string, today time.Time, weeks int) (*domain.DemandHeatmap, error) {
+	args := m.Called(ctx, restaurantID, today, weeks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DemandHeatmap), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) GetResponseLatency(ctx context.Context, restaurantID string, today time.Time, days int) (*domain.ResponseLatency, error) {
+	args := m.Called(ctx, restaurantID, today, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResponseLatency), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) ListSlowResponders(ctx context.Context, today time.Time) ([]*domain.ResponseLatency, error) {
+	args := m.Called(ctx, today)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ResponseLatency), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) ListCampaignPerformance(ctx context.Context, restaurantID string, today time.Time, days int) ([]domain.CampaignPerformance, error) {
+	args := m.Called(ctx, restaurantID, today, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CampaignPerformance), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) GetOccupancyNow(ctx context.Context, restaurantID string, now time.Time) (*domain.LiveOccupancy, error) {
+	args := m.Called(ctx, restaurantID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LiveOccupancy), args.Error(1)
+}
+
+type MockQuotaUseCase struct {
+	mock.Mock
+}
+
+func (m *MockQuotaUseCase) GetQuota(ctx context.Context, restaurantID string, now time.Time) (*domain.Quota, error) {
+	args := m.Called(ctx, restaurantID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Quota), args.Error(1)
+}
+
+func (m *MockQuotaUseCase) SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockQuotaUseCase) CheckBooking(ctx context.Context, restaurantID string, now time.Time) error {
+	args := m.Called(ctx, restaurantID, now)
+	return args.Error(0)
+}
+
+func (m *MockQuotaUseCase) LimitActiveSlots(ctx context.Context, restaurantID string, now time.Time, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx, restaurantID, now, fn)
+	return args.Error(0)
+}
+
+type MockBillingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBillingUseCase) Subscribe(ctx context.Context, restaurantID string, plan domain.Plan, price *int64, now time.Time) (*domain.Subscription, error) {
+	args := m.Called(ctx, restaurantID, plan, price, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Subscription), args.Error(1)
+}
+
+func (m *MockBillingUseCase) CancelSubscription(ctx context.Context, id string, now time.Time) error {
+	args := m.Called(ctx, id, now)
+	return args.Error(0)
+}
+
+func (m *MockBillingUseCase) ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Subscription), args.Error(1)
+}
+
+func (m *MockBillingUseCase) GenerateInvoices(ctx context.Context, month, now time.Time) ([]*domain.Invoice, error) {
+	args := m.Called(ctx, month, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingUseCase) ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingUseCase) RenderInvoice(ctx context.Context, id string) (*domain.Invoice, []byte, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.Invoice), args.Get(1).([]byte), args.Error(2)
+}
+
+func (m *MockBillingUseCase) HandlePaymentEvent(ctx context.Context, payload []byte, signature string) error {
+	args := m.Called(ctx, payload, signature)
+	return args.Error(0)
+}
+
+type MockIncentiveUseCase struct {
+	mock.Mock
+}
+
+func (m *MockIncentiveUseCase) ListRules(ctx context.Context) ([]*domain.IncentiveRule, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IncentiveRule), args.Error(1)
+}
+
+func (m *MockIncentiveUseCase) CreateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveUseCase) UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveUseCase) DeleteRule(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveUseCase) Evaluate(ctx context.Context, booking *domain.Booking, now time.Time) (*domain.IncentiveEvaluation, error) {
+	args := m.Called(ctx, booking, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncentiveEvaluation), args.Error(1)
+}
+
+func (m *MockIncentiveUseCase) RecordEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error {
+	args := m.Called(ctx, evaluation)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveUseCase) GetBookingIncentives(ctx context.Context, bookingID domain.BookingID) (*domain.IncentiveEvaluation, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncentiveEvaluation), args.Error(1)
+}
+
+func (m *MockIncentiveUseCase) ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error) {
+	args := m.Called(ctx, userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IncentiveEvaluation), args.Error(1)
+}
+
+type MockGeocodingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockGeocodingUseCase) RequestGeocoding(ctx context.Context, restaurant *domain.Restaurant) error {
+	args := m.Called(ctx, restaurant)
+	return args.Error(0)
+}
+
+func (m *MockGeocodingUseCase) GetRestaurantLocation(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantLocation), args.Error(1)
+}
+
+func (m *MockGeocodingUseCase) GeocodePending(ctx context.Context, limit int) (int, error) {
+	args := m.Called(ctx, limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockGeocodingUseCase) ListCities(ctx context.Context, countryCode string) ([]*domain.City, error) {
+	args := m.Called(ctx, countryCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.City), args.Error(1)
+}
+
+type MockAvailabilityAlertUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityAlertUseCase) CreateAlert(ctx context.Context, alert *domain.AvailabilityAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityAlertUseCase) CheckAlerts(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+type MockBulkCancellationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBulkCancellationUseCase) CancelBookings(ctx context.Context, cancellation usecase.BulkCancellation) (*usecase.BulkCancellationReport, error) {
+	args := m.Called(ctx, cancellation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.BulkCancellationReport), args.Error(1)
+}
+
+type MockOrganizationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationUseCase) CreateOrganization(ctx context.Context, name string) (*domain.Organization, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationUseCase) AddRestaurant(ctx context.Context, organizationID, restaurantID string) error {
+	args := m.Called(ctx, organizationID, restaurantID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationUseCase) ListSisterRestaurants(ctx context.Context, restaurantID string) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+type MockBookingTransferUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingTransferUseCase) RequestTransfer(ctx context.Context, transfer *domain.BookingTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockBookingTransferUseCase) ListTransfers(ctx context.Context, bookingID domain.BookingID) ([]*domain.BookingTransfer, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BookingTransfer), args.Error(1)
+}
+
+func (m *MockBookingTransferUseCase) AcceptTransfer(ctx context.Context, transferID string) (*domain.Booking, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingTransferUseCase) DeclineTransfer(ctx context.Context, transferID string) error {
+	args := m.Called(ctx, transferID)
+	return args.Error(0)
+}
+
+type MockBookingSheetUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingSheetUseCase) RenderBookingSheet(ctx context.Context, restaurantID string, date time.Time) ([]byte, error) {
+	args := m.Called(ctx, restaurantID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type MockDisplayBoardUseCase struct {
+	mock.Mock
+}
+
+func (m *MockDisplayBoardUseCase) GetBoard(ctx context.Context, query usecase.BoardQuery) (*usecase.DisplayBoard, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.DisplayBoard), args.Error(1)
+}
+
+type MockSLOUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSLOUseCase) ObserveRequest(at time.Time, status int, elapsed time.Duration) {
+	m.Called(at, status, elapsed)
+}
+
+func (m *MockSLOUseCase) FlushMetrics(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockSLOUseCase) CheckBurnRate(ctx context.Context, now time.Time) (*domain.SLOReport, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SLOReport), args.Error(1)
+}
+
+func (m *MockSLOUseCase) GenerateDailyReport(ctx context.Context, day time.Time) (*domain.SLOReport, error) {
+	args := m.Called(ctx, day)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.SLOReport), args.Error(1)
+}
+
+func (m *MockSLOUseCase) GetReports(ctx context.Context, from, to time.Time) (*usecase.SLOPeriod, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.SLOPeriod), args.Error(1)
+}
+
+// newObservingSLOUseCase returns an SLO use case that takes the observation of every request.
+func newObservingSLOUseCase() *MockSLOUseCase {
+	sloUseCase := new(MockSLOUseCase)
+	sloUseCase.On("ObserveRequest", mock.Anything, mock.Anything, mock.Anything).Return()
+	return sloUseCase
+}
+
+type MockFaultInjectionUseCase struct {
+	mock.Mock
+}
+
+func (m *MockFaultInjectionUseCase) RouteFaults() []domain.RouteFault {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]domain.RouteFault)
+}
+
+// newFaultlessInjectionUseCase returns a fault injection use case that injects no faults.
+func newFaultlessInjectionUseCase() *MockFaultInjectionUseCase {
+	faultInjectionUseCase := new(MockFaultInjectionUseCase)
+	faultInjectionUseCase.On("RouteFaults").Return(nil)
+	return faultInjectionUseCase
+}
+
+func (m *MockFaultInjectionUseCase) DBFault() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockFaultInjectionUseCase) GetFaults(ctx context.Context) (*domain.FaultInjection, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FaultInjection), args.Error(1)
+}
+
+func (m *MockFaultInjectionUseCase) AddRouteFault(ctx context.Context, fault *domain.RouteFault) error {
+	args := m.Called(ctx, fault)
+	return args.Error(0)
+}
+
+func (m *MockFaultInjectionUseCase) DeleteRouteFault(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFaultInjectionUseCase) SetDBFailureRate(ctx context.Context, rate float64) error {
+	args := m.Called(ctx, rate)
+	return args.Error(0)
+}
+
+func (m *MockFaultInjectionUseCase) ClearFaults(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+type MockBookingSyncUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingSyncUseCase) SyncBookings(ctx context.Context, items []usecase.BookingSyncItem) ([]*domain.BookingSyncResult, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BookingSyncResult), args.Error(1)
+}
+
+type MockRestaurantClaimUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRestaurantClaimUseCase) SubmitClaim(ctx context.Context, claim *domain.RestaurantClaim) error {
+	args := m.Called(ctx, claim)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantClaimUseCase) GetClaim(ctx context.Context, id string) (*domain.RestaurantClaim, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantClaim), args.Error(1)
+}
+
+func (m *MockRestaurantClaimUseCase) ListClaims(ctx context.Context, filter domain.RestaurantClaimFilter, offset, limit int) ([]*domain.RestaurantClaim, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantClaim), args.Error(1)
+}
+
+func (m *MockRestaurantClaimUseCase) ApproveClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error) {
+	args := m.Called(ctx, id, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantClaim), args.Error(1)
+}
+
+func (m *MockRestaurantClaimUseCase) RejectClaim(ctx context.Context, id, note string) (*domain.RestaurantClaim, error) {
+	args := m.Called(ctx, id, note)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantClaim), args.Error(1)
+}
+
+func (m *MockRestaurantClaimUseCase) GetOwnership(ctx context.Context, restaurantID string) (*usecase.RestaurantOwnershipReport, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.RestaurantOwnershipReport), args.Error(1)
+}
+
+type MockCustomDomainUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCustomDomainUseCase) AddDomain(ctx context.Context, restaurantID, name string) (*domain.CustomDomain, error) {
+	args := m.Called(ctx, restaurantID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomDomain), args.Error(1)
+}
+
+func (m *MockCustomDomainUseCase) ListDomains(ctx context.Context, restaurantID string) ([]*domain.CustomDomain, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CustomDomain), args.Error(1)
+}
+
+func (m *MockCustomDomainUseCase) VerifyDomain(ctx context.Context, restaurantID, domainID string) (*domain.CustomDomain, error) {
+	args := m.Called(ctx, restaurantID, domainID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomDomain), args.Error(1)
+}
+
+func (m *MockCustomDomainUseCase) RemoveDomain(ctx context.Context, restaurantID, domainID string) error {
+	args := m.Called(ctx, restaurantID, domainID)
+	return args.Error(0)
+}
+
+func (m *MockCustomDomainUseCase) ResolveHost(ctx context.Context, host string) (*domain.CustomDomain, error) {
+	args := m.Called(ctx, host)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CustomDomain), args.Error(1)
+}
+
+// newUnknownHostsUseCase returns a custom domain use case that knows no host.
+func newUnknownHostsUseCase() *MockCustomDomainUseCase {
+	customDomainUseCase := new(MockCustomDomainUseCase)
+	customDomainUseCase.On("ResolveHost", mock.Anything, mock.Anything).Return(nil, errors.New(common.ErrCustomDomainNotFound)).Maybe()
+	return customDomainUseCase
+}
+
+type MockWidgetSettingsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWidgetSettingsUseCase) GetWidgetSettings(ctx context.Context, restaurantID string) (*domain.WidgetSettings, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WidgetSettings), args.Error(1)
+}
+
+func (m *MockWidgetSettingsUseCase) UpdateWidgetSettings(ctx context.Context, settings *domain.WidgetSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+type MockBookingDraftUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBookingDraftUseCase) CreateDraft(ctx context.Context, draft *domain.BookingDraft) error {
+	args := m.Called(ctx, draft)
+	return args.Error(0)
+}
+
+func (m *MockBookingDraftUseCase) GetDraft(ctx context.Context, id string) (*domain.BookingDraft, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BookingDraft), args.Error(1)
+}
+
+func (m *MockBookingDraftUseCase) HoldSlot(ctx context.Context, id string, date time.Time, timeSlot string) (*domain.BookingDraft, error) {
+	args := m.Called(ctx, id, date, timeSlot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BookingDraft), args.Error(1)
+}
+
+func (m *MockBookingDraftUseCase) SetPreOrder(ctx context.Context, id string, items []domain.PreOrderItem) (*domain.BookingDraft, error) {
+	args := m.Called(ctx, id, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BookingDraft), args.Error(1)
+}
+
+func (m *MockBookingDraftUseCase) ConfirmDraft(ctx context.Context, id string) (*domain.Booking, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingDraftUseCase) DeleteDraft(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBookingDraftUseCase) ExpireDrafts(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+type MockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUseCase) RegisterUser(ctx context.Context, user *domain.User, password string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, user, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) RegisterRestaurantAccount(ctx context.Context, restaurantID, email, password string) (*domain.Account, error) {
+	args := m.Called(ctx, restaurantID, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Login(ctx context.Context, email, password string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Refresh(ctx context.Context, refreshToken string) (*usecase.TokenPair, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*usecase.TokenPair), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Logout(ctx context.Context, refreshToken string) error {
+	args := m.Called(ctx, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) VerifyEmail(ctx context.Context, token string) (*domain.Account, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Account), args.Error(1)
+}
+
+func (m *MockAuthUseCase) ResendVerification(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockAuthUseCase) Authenticate(ctx context.Context, accessToken string) (*tenant.Principal, error) {
+	args := m.Called(ctx, accessToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tenant.Principal), args.Error(1)
+}
+
+type MockRestaurantClosureUseCase struct {
+	mock.Mock
+}
+
+func (m *MockRestaurantClosureUseCase) CreateClosure(ctx context.Context, closure *domain.RestaurantClosure, dryRun bool) (string, error) {
+	args := m.Called(ctx, closure, dryRun)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRestaurantClosureUseCase) ListClosures(ctx context.Context, restaurantID string) ([]*domain.RestaurantClosure, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantClosure), args.Error(1)
+}
+
+func (m *MockRestaurantClosureUseCase) DeleteClosure(ctx context.Context, restaurantID, closureID string) error {
+	args := m.Called(ctx, restaurantID, closureID)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantClosureUseCase) CheckOpen(ctx context.Context, booking *domain.Booking) error {
+	args := m.Called(ctx, booking)
+	return args.Error(0)
+}
+
+type MockTableUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTableUseCase) CreateTable(ctx context.Context, table *domain.Table) error {
+	args := m.Called(ctx, table)
+	return args.Error(0)
+}
+
+func (m *MockTableUseCase) ListTables(ctx context.Context, restaurantID string) ([]*domain.Table, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Table), args.Error(1)
+}
+
+func (m *MockTableUseCase) GetTable(ctx context.Context, restaurantID, tableID string) (*domain.Table, error) {
+	args := m.Called(ctx, restaurantID, tableID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Table), args.Error(1)
+}
+
+func (m *MockTableUseCase) UpdateTable(ctx context.Context, table *domain.Table) error {
+	args := m.Called(ctx, table)
+	return args.Error(0)
+}
+
+func (m *MockTableUseCase) DeleteTable(ctx context.Context, restaurantID, tableID string) error {
+	args := m.Called(ctx, restaurantID, tableID)
+	return args.Error(0)
+}
+
+func (m *MockTableUseCase) OptimizeSeating(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,68 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuards(t *testing.T) {
+	user := tenant.NewContext(context.Background(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+	staff := tenant.NewContext(context.Background(), &tenant.Principal{
+		UserID:        "staff1",
+		RestaurantIDs: []string{"rest1"},
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+	})
+	admin := tenant.NewContext(context.Background(), &tenant.Principal{UserID: "admin1", Roles: []tenant.Role{tenant.RoleAdmin}})
+
+	tests := []struct {
+		name    string
+		err     error
+		allowed bool
+	}{
+		{"no principal", tenant.GuardOwner(context.Background(), "booking", "b1", "user2", "rest2"), true},
+		{"own booking", tenant.GuardOwner(user, "booking", "b1", "user1", "rest2"), true},
+		{"booking of another user", tenant.GuardOwner(user, "booking", "b1", "user2", "rest2"), false},
+		{"booking at own restaurant", tenant.GuardOwner(staff, "booking", "b1", "user2", "rest1"), true},
+		{"booking at another restaurant", tenant.GuardOwner(staff, "booking", "b1", "user2", "rest2"), false},
+		{"admin", tenant.GuardOwner(admin, "booking", "b1", "user2", "rest2"), true},
+		{"own user data", tenant.GuardUser(user, "notification", "n1", "user1"), true},
+		{"staff reading a user", tenant.GuardUser(staff, "notification", "n1", "user1"), false},
+		{"own restaurant data", tenant.GuardRestaurant(staff, "notification", "n1", "rest1"), true},
+		{"user reading a restaurant", tenant.GuardRestaurant(user, "notification", "n1", "rest1"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.allowed {
+				assert.NoError(t, tt.err)
+				return
+			}
+			assert.ErrorIs(t, tt.err, tenant.ErrAccessDenied)
+
+			var isolationErr *tenant.IsolationError
+			require.True(t, errors.As(tt.err, &isolationErr))
+			assert.NotEmpty(t, isolationErr.Resource)
+			assert.NotEmpty(t, isolationErr.ResourceID)
+		})
+	}
+}
+
+func TestGuards_StrictPanics(t *testing.T) {
+	tenant.Strict = true
+	t.Cleanup(func() { tenant.Strict = false })
+
+	ctx := tenant.NewContext(context.Background(), &tenant.Principal{UserID: "user1"})
+
+	assert.Panics(t, func() {
+		_ = tenant.GuardUser(ctx, "notification", "n1", "user2")
+	})
+	assert.NotPanics(t, func() {
+		_ = tenant.GuardUser(ctx, "notification", "n1", "user1")
+	})
+}
@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBookingUseCase mocks the booking methods the abuse monitor wraps; the others are not called.
+type stubBookingUseCase struct {
+	usecase.BookingUseCase
+	mock.Mock
+}
+
+func (m *stubBookingUseCase) GetBooking(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *stubBookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) (string, error) {
+	args := m.Called(ctx, booking)
+	return args.String(0), args.Error(1)
+}
+
+func (m *stubBookingUseCase) CancelBooking(ctx context.Context, id domain.BookingID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func clientContext(ip string) context.Context {
+	return tenant.WithClientIP(setupTestContext(), ip)
+}
+
+func TestAbuse_BookingBurst(t *testing.T) {
+	abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{Window: time.Hour, BookingsPerClient: 3}, idgen.UUID{}, newTestClock())
+	ctx := clientContext("203.0.113.7")
+
+	for range 5 {
+		abuse.RecordBooking(ctx, &domain.Booking{RestaurantID: "restaurant1"})
+	}
+	abuse.RecordBooking(clientContext("198.51.100.1"), &domain.Booking{RestaurantID: "restaurant1"})
+
+	events, err := abuse.ListAbuseEvents(adminContext(), "")
+	require.NoError(t, err)
+	require.Len(t, events, 1, "a pattern is reported once per window")
+	assert.Equal(t, domain.AbuseEventBookingBurst, events[0].Kind)
+	assert.Equal(t, "203.0.113.7", events[0].ClientIP)
+	assert.Equal(t, 4, events[0].Count)
+	assert.Nil(t, events[0].TightenedUntil)
+}
+
+func TestAbuse_CancelRebookLoop(t *testing.T) {
+	abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{Window: time.Hour, CancelRebookCycles: 2}, idgen.UUID{}, newTestClock())
+	ctx := clientContext("203.0.113.7")
+	booking := &domain.Booking{RestaurantID: "restaurant1"}
+
+	abuse.RecordBooking(ctx, booking)
+	abuse.RecordCancellation(ctx, booking)
+	abuse.RecordBooking(ctx, &domain.Booking{RestaurantID: "restaurant2"})
+	abuse.RecordBooking(ctx, booking)
+
+	events, err := abuse.ListAbuseEvents(adminContext(), domain.AbuseEventCancelRebook)
+	require.NoError(t, err)
+	assert.Empty(t, events, "one cycle is below the threshold")
+
+	abuse.RecordCancellation(ctx, booking)
+	abuse.RecordBooking(ctx, booking)
+
+	events, err = abuse.ListAbuseEvents(adminContext(), domain.AbuseEventCancelRebook)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "restaurant1", events[0].RestaurantID)
+	assert.Equal(t, 2, events[0].Count)
+}
+
+func TestAbuse_RateLimit(t *testing.T) {
+	t.Run("base limit", func(t *testing.T) {
+		abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{Window: time.Hour, RateLimit: 2}, idgen.UUID{}, newTestClock())
+		ctx := clientContext("203.0.113.7")
+
+		for range 2 {
+			allowed, _ := abuse.Allow(ctx)
+			assert.True(t, allowed)
+		}
+		allowed, retryAfter := abuse.Allow(ctx)
+		assert.False(t, allowed)
+		assert.InDelta(t, time.Minute.Seconds(), retryAfter.Seconds(), 1)
+
+		allowed, _ = abuse.Allow(clientContext("198.51.100.1"))
+		assert.True(t, allowed, "limits are per client")
+	})
+
+	t.Run("tightened after a flag", func(t *testing.T) {
+		abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{
+			Window:             time.Hour,
+			BookingsPerClient:  1,
+			AutoTighten:        true,
+			TightenedRateLimit: 1,
+			TightenFor:         time.Hour,
+		}, idgen.UUID{}, newTestClock())
+		ctx := clientContext("203.0.113.7")
+
+		for range 3 {
+			allowed, _ := abuse.Allow(ctx)
+			assert.True(t, allowed, "no limit before the client is flagged")
+		}
+
+		abuse.RecordBooking(ctx, &domain.Booking{RestaurantID: "restaurant1"})
+		abuse.RecordBooking(ctx, &domain.Booking{RestaurantID: "restaurant1"})
+
+		events, err := abuse.ListAbuseEvents(adminContext(), "")
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.NotNil(t, events[0].TightenedUntil)
+
+		allowed, _ := abuse.Allow(ctx)
+		assert.True(t, allowed)
+		allowed, _ = abuse.Allow(ctx)
+		assert.False(t, allowed)
+	})
+}
+
+func TestAbuse_ListEvents(t *testing.T) {
+	abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{Window: time.Hour}, idgen.UUID{}, newTestClock())
+
+	_, err := abuse.ListAbuseEvents(staffContext(), "")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	_, err = abuse.ListAbuseEvents(adminContext(), "spam")
+	assert.ErrorIs(t, err, usecase.ErrInvalidAbuseEventKind)
+}
+
+func TestAbuseMonitoredBookingUseCase(t *testing.T) {
+	ctx := clientContext("203.0.113.7")
+	booking := &domain.Booking{ID: "booking1", RestaurantID: "restaurant1"}
+
+	bookings := new(stubBookingUseCase)
+	bookings.On("CreateBooking", ctx, booking).Return("booking1", nil)
+	bookings.On("CancelBooking", ctx, domain.BookingID("booking1")).Return(nil)
+	bookings.On("CancelBooking", ctx, domain.BookingID("booking2")).Return(errors.New("booking not found"))
+	bookings.On("GetBooking", ctx, domain.BookingID("booking1")).Return(booking, nil)
+
+	abuse := usecase.NewAbuseUseCase(usecase.AbuseRules{Window: time.Hour, CancelRebookCycles: 1}, idgen.UUID{}, newTestClock())
+	monitored := usecase.NewAbuseMonitoredBookingUseCase(bookings, abuse)
+
+	_, err := monitored.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+	require.NoError(t, monitored.CancelBooking(ctx, "booking1"))
+	require.Error(t, monitored.CancelBooking(ctx, "booking2"))
+	_, err = monitored.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+
+	events, err := abuse.ListAbuseEvents(adminContext(), domain.AbuseEventCancelRebook)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	bookings.AssertNotCalled(t, "GetBooking", mock.Anything, domain.BookingID("booking2"))
+}
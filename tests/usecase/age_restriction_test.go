@@ -0,0 +1,47 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeRestrictedBookingUseCase(t *testing.T) {
+	ctx := newTestContext()
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("bar")).Return(&domain.Restaurant{ID: "bar", IsAdultOnly: true}, nil)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("cafe")).Return(&domain.Restaurant{ID: "cafe"}, nil)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("missing")).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	bookings := new(stubBookingUseCase)
+	restricted := usecase.NewAgeRestrictedBookingUseCase(bookings, restaurantRepo)
+
+	_, err := restricted.CreateBooking(ctx, &domain.Booking{RestaurantID: "bar"})
+	require.ErrorIs(t, err, usecase.ErrAgeAttestationRequired)
+	assert.Equal(t, common.ErrAgeAttestationRequired, err.Error())
+
+	_, err = restricted.CreateBooking(ctx, &domain.Booking{RestaurantID: "missing"})
+	require.Error(t, err)
+	assert.Equal(t, common.ErrRestaurantNotFound, err.Error())
+
+	attested := &domain.Booking{RestaurantID: "bar", AgeAttested: true}
+	bookings.On("CreateBooking", ctx, attested).Return("booking1", nil)
+	id, err := restricted.CreateBooking(ctx, attested)
+	require.NoError(t, err)
+	assert.Equal(t, "booking1", id)
+
+	unrestricted := &domain.Booking{RestaurantID: "cafe"}
+	bookings.On("CreateBooking", ctx, unrestricted).Return("booking2", nil)
+	id, err = restricted.CreateBooking(ctx, unrestricted)
+	require.NoError(t, err)
+	assert.Equal(t, "booking2", id)
+
+	restaurantRepo.AssertNumberOfCalls(t, "GetByID", 3)
+}
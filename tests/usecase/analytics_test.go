@@ -0,0 +1,313 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) ListBookings(ctx context.Context, restaurantID string, from, to time.Time) ([]*domain.Booking, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func TestGetForecast(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 5, day, hour, 0, 0, 0, time.UTC)
+	}
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+	analyticsRepo.On("ListBookings", mock.Anything, "r1", today.AddDate(0, 0, -14), today).Return([]*domain.Booking{
+		// Wednesdays at 19:00: 4 seats booked on the day on May 28 and 2 on May 21.
+		{Date: at(28, 0), Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusCompleted, CreatedAt: at(28, 10)},
+		{Date: at(28, 0), Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusCompleted, CreatedAt: at(20, 10)},
+		{Date: at(28, 0), Time: "19:00", GuestsCount: 6, Status: domain.BookingStatusCancelled, CreatedAt: at(28, 11)},
+		{Date: at(21, 0), Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusCompleted, CreatedAt: at(21, 12)},
+		// Thursdays at 12:00: 4 seats booked the day before on May 29.
+		{Date: at(29, 0), Time: "12:00", GuestsCount: 4, Status: domain.BookingStatusCompleted, CreatedAt: at(28, 18)},
+	}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("r1"), today).Return([]*domain.Availability{
+		{TimeSlot: "19:00", Capacity: 10, Reserved: 2},
+	}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("r1"), today.AddDate(0, 0, 1)).Return([]*domain.Availability{
+		{TimeSlot: "12:00", Capacity: 4, Reserved: 3},
+	}, nil)
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, availabilityRepo, restaurantRepo, 2, 12, usecase.SlowResponderPolicy{}, newTestClock())
+	forecast, err := uc.GetForecast(newTestContext(), "r1", today.Add(15*time.Hour), 2)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 2)
+	assert.Equal(t, 2, forecast.Weeks)
+
+	wednesday := forecast.Days[0].Slots[0]
+	assert.Equal(t, 2, wednesday.Samples)
+	assert.InDelta(t, 5, wednesday.ProjectedReserved, 0.001, "2 reserved and 3 seats booked on the day on average")
+	assert.InDelta(t, 0.5, forecast.Days[0].ProjectedOccupancy(), 0.001)
+
+	thursday := forecast.Days[1].Slots[0]
+	assert.InDelta(t, 4, thursday.ProjectedReserved, 0.001, "the projection never exceeds the capacity")
+	assert.InDelta(t, 1, thursday.ProjectedOccupancy(), 0.001)
+}
+
+func TestGetForecast_Validation(t *testing.T) {
+	uc := usecase.NewAnalyticsUseCase(new(MockAnalyticsRepository), new(MockAvailabilityRepository), new(MockRestaurantRepository), 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+
+	_, err := uc.GetForecast(newTestContext(), "r1", testNow, 29)
+	assert.ErrorIs(t, err, usecase.ErrInvalidForecastDays)
+
+	_, err = uc.GetForecast(newTestContext(), "r1", testNow, 0)
+	assert.ErrorIs(t, err, usecase.ErrInvalidForecastDays)
+
+	staffCtx := tenant.NewContext(newTestContext(), &tenant.Principal{
+		UserID:        "user1",
+		RestaurantIDs: []string{"r2"},
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+	})
+	_, err = uc.GetForecast(staffCtx, "r1", testNow, 7)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func (m *MockAnalyticsRepository) CountHourlyDemand(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.HourlyDemand, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.HourlyDemand), args.Error(1)
+}
+
+func TestGetDemandHeatmap(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+	analyticsRepo.On("CountHourlyDemand", mock.Anything, "r1", today.AddDate(0, 0, -84), today).Return([]domain.HourlyDemand{
+		{Weekday: time.Friday, Hour: 19, Bookings: 12, Guests: 40},
+		{Weekday: time.Sunday, Hour: 13, Bookings: 5, Guests: 14},
+	}, nil).Once()
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, new(MockAvailabilityRepository), restaurantRepo, 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+	heatmap, err := uc.GetDemandHeatmap(newTestContext(), "r1", today.Add(9*time.Hour), 0)
+	require.NoError(t, err)
+	assert.Equal(t, 12, heatmap.Weeks, "the default weeks are counted")
+	assert.Equal(t, domain.DemandCell{Bookings: 12, Guests: 40}, heatmap.Cells[time.Friday][19])
+	assert.Equal(t, domain.DemandCell{Bookings: 5, Guests: 14}, heatmap.Cells[time.Sunday][13])
+	assert.Zero(t, heatmap.Cells[time.Monday][19])
+
+	cached, err := uc.GetDemandHeatmap(newTestContext(), "r1", today.Add(20*time.Hour), 12)
+	require.NoError(t, err)
+	assert.Same(t, heatmap, cached, "the heatmap is computed once a day")
+
+	tomorrow := today.AddDate(0, 0, 1)
+	analyticsRepo.On("CountHourlyDemand", mock.Anything, "r1", tomorrow.AddDate(0, 0, -84), tomorrow).Return([]domain.HourlyDemand{}, nil).Once()
+	fresh, err := uc.GetDemandHeatmap(newTestContext(), "r1", tomorrow, 12)
+	require.NoError(t, err)
+	assert.NotSame(t, heatmap, fresh)
+
+	analyticsRepo.AssertExpectations(t)
+}
+
+func TestGetDemandHeatmap_Validation(t *testing.T) {
+	uc := usecase.NewAnalyticsUseCase(new(MockAnalyticsRepository), new(MockAvailabilityRepository), new(MockRestaurantRepository), 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+
+	_, err := uc.GetDemandHeatmap(newTestContext(), "r1", testNow, 53)
+	assert.ErrorIs(t, err, usecase.ErrInvalidHeatmapWeeks)
+
+	_, err = uc.GetDemandHeatmap(newTestContext(), "r1", testNow, -1)
+	assert.ErrorIs(t, err, usecase.ErrInvalidHeatmapWeeks)
+
+	guestCtx := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+	_, err = uc.GetDemandHeatmap(guestCtx, "r1", testNow, 4)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func (m *MockAnalyticsRepository) GetResponseLatency(ctx context.Context, restaurantID string, from, to time.Time) (*domain.ResponseLatency, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ResponseLatency), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) ListSlowResponders(
+	ctx context.Context,
+	from, to time.Time,
+	threshold time.Duration,
+	minResponses int,
+) ([]*domain.ResponseLatency, error) {
+	args := m.Called(ctx, from, to, threshold, minResponses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ResponseLatency), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) ListCampaignPerformance(ctx context.Context, restaurantID string, from, to time.Time) ([]domain.CampaignPerformance, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CampaignPerformance), args.Error(1)
+}
+
+func (m *MockAnalyticsRepository) CountSeated(ctx context.Context, restaurantID string, at time.Time) (int, int, error) {
+	args := m.Called(ctx, restaurantID, at)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func TestGetResponseLatency(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	from, to := today.AddDate(0, 0, -29), today.AddDate(0, 0, 1)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Bistro"}, nil)
+	analyticsRepo.On("GetResponseLatency", mock.Anything, "r1", from, to).Return(&domain.ResponseLatency{
+		RestaurantID: "r1",
+		From:         from,
+		To:           to,
+		Requests:     12,
+		Responses:    10,
+		Unanswered:   2,
+		P50:          20 * time.Minute,
+		P90:          2 * time.Hour,
+		P99:          5 * time.Hour,
+	}, nil)
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, new(MockAvailabilityRepository), restaurantRepo, 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+	latency, err := uc.GetResponseLatency(newTestContext(), "r1", today.Add(15*time.Hour), 30)
+	require.NoError(t, err)
+	assert.Equal(t, "Bistro", latency.RestaurantName)
+	assert.Equal(t, 20*time.Minute, latency.P50)
+
+	_, err = uc.GetResponseLatency(newTestContext(), "r1", today, 0)
+	assert.ErrorIs(t, err, usecase.ErrInvalidLatencyDays)
+
+	guestCtx := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+	_, err = uc.GetResponseLatency(guestCtx, "r1", today, 30)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func TestListSlowResponders(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	slow := []*domain.ResponseLatency{{RestaurantID: "r2", RestaurantName: "Slow Diner", Responses: 15, P50: 3 * time.Hour}}
+	analyticsRepo.On("ListSlowResponders", mock.Anything, today.AddDate(0, 0, -27), today.AddDate(0, 0, 1), 2*time.Hour, 10).Return(slow, nil)
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, new(MockAvailabilityRepository), new(MockRestaurantRepository), 4, 12, usecase.SlowResponderPolicy{
+		Days:         28,
+		Threshold:    2 * time.Hour,
+		MinResponses: 10,
+	}, newTestClock())
+
+	adminCtx := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "admin", Roles: []tenant.Role{tenant.RoleAdmin}})
+	responders, err := uc.ListSlowResponders(adminCtx, today.Add(8*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, slow, responders)
+
+	staffCtx := tenant.NewContext(newTestContext(), &tenant.Principal{
+		UserID:        "staff",
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+		RestaurantIDs: []string{"r2"},
+	})
+	_, err = uc.ListSlowResponders(staffCtx, today)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func TestListCampaignPerformance(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	from, to := today.AddDate(0, 0, -6), today.AddDate(0, 0, 1)
+	campaigns := []domain.CampaignPerformance{{Source: "instagram", Medium: "social", Campaign: "summer", Bookings: 4, Completed: 3, Guests: 7}}
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+	analyticsRepo.On("ListCampaignPerformance", mock.Anything, "r1", from, to).Return(campaigns, nil)
+	analyticsRepo.On("ListCampaignPerformance", mock.Anything, "", from, to).Return(campaigns, nil)
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, new(MockAvailabilityRepository), restaurantRepo, 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+
+	adminCtx := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "admin", Roles: []tenant.Role{tenant.RoleAdmin}})
+	result, err := uc.ListCampaignPerformance(adminCtx, "r1", today.Add(8*time.Hour), 7)
+	require.NoError(t, err)
+	assert.Equal(t, campaigns, result)
+	assert.InDelta(t, 0.75, result[0].ConversionRate(), 1e-9)
+
+	_, err = uc.ListCampaignPerformance(adminCtx, "", today, 7)
+	require.NoError(t, err)
+
+	_, err = uc.ListCampaignPerformance(adminCtx, "", today, 366)
+	assert.ErrorIs(t, err, usecase.ErrInvalidCampaignDays)
+
+	staffCtx := tenant.NewContext(newTestContext(), &tenant.Principal{
+		UserID:        "staff",
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+		RestaurantIDs: []string{"r1"},
+	})
+	_, err = uc.ListCampaignPerformance(staffCtx, "r1", today, 7)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	analyticsRepo.AssertExpectations(t)
+}
+
+func TestGetOccupancyNow(t *testing.T) {
+	analyticsRepo := new(MockAnalyticsRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	now := today.Add(20*time.Hour + 15*time.Minute)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("missing")).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("r1"), today).Return([]*domain.Availability{
+		{TimeSlot: "18:00", Capacity: 40},
+		{TimeSlot: "19:30", Capacity: 20},
+		{TimeSlot: "21:00", Capacity: 30},
+	}, nil)
+	analyticsRepo.On("CountSeated", mock.Anything, "r1", now).Return(4, 16, nil).Once()
+
+	uc := usecase.NewAnalyticsUseCase(analyticsRepo, availabilityRepo, restaurantRepo, 4, 12, usecase.SlowResponderPolicy{}, newTestClock())
+	occupancy, err := uc.GetOccupancyNow(newTestContext(), "r1", now)
+	require.NoError(t, err)
+	assert.Equal(t, 16, occupancy.Guests)
+	assert.Equal(t, 20, occupancy.Capacity, "the latest slot under way is compared with")
+	assert.InDelta(t, 0.8, occupancy.Occupancy(), 0.001)
+	assert.Equal(t, domain.BusyLevelBusy, occupancy.Level())
+
+	cached, err := uc.GetOccupancyNow(newTestContext(), "r1", now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Same(t, occupancy, cached, "the occupancy is reused for a minute")
+
+	analyticsRepo.On("CountSeated", mock.Anything, "r1", now.Add(time.Minute)).Return(0, 0, nil).Once()
+	fresh, err := uc.GetOccupancyNow(newTestContext(), "r1", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, domain.BusyLevelQuiet, fresh.Level())
+
+	_, err = uc.GetOccupancyNow(newTestContext(), "missing", now)
+	assert.EqualError(t, err, common.ErrRestaurantNotFound)
+
+	analyticsRepo.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAvailabilityAlertRepository struct {
+	mock.Mock
+}
+
+func (m *MockAvailabilityAlertRepository) Create(ctx context.Context, alert *domain.AvailabilityAlert) error {
+	args := m.Called(ctx, alert)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityAlertRepository) ListActive(ctx context.Context, from time.Time) ([]*domain.AvailabilityAlert, error) {
+	args := m.Called(ctx, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.AvailabilityAlert), args.Error(1)
+}
+
+func (m *MockAvailabilityAlertRepository) MarkNotified(ctx context.Context, id, timeSlot string, notifiedAt time.Time) error {
+	args := m.Called(ctx, id, timeSlot, notifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityAlertRepository) ExpireBefore(ctx context.Context, date time.Time) (int, error) {
+	args := m.Called(ctx, date)
+	return args.Int(0), args.Error(1)
+}
+
+func TestAvailabilityAlertUseCase_CreateAlert(t *testing.T) {
+	ctx := newTestContext()
+	alertRepo := new(MockAvailabilityAlertRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	useCase := usecase.NewAvailabilityAlertUseCase(alertRepo, availabilityRepo, restaurantRepo, new(MockNotificationService), newTestClock())
+
+	date := testNow.AddDate(0, 0, 1)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(createTestRestaurant(), nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), day).Return([]*domain.Availability{
+		{ID: "a1", TimeSlot: "19:00", Capacity: 10, Reserved: 10},
+		{ID: "a2", TimeSlot: "22:00", Capacity: 10, Reserved: 0},
+	}, nil)
+	alertRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+	alert := &domain.AvailabilityAlert{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "18:00", ToTime: "21:00", PartySize: 2}
+	require.NoError(t, useCase.CreateAlert(ctx, alert))
+	assert.Equal(t, day, alert.Date)
+
+	// The 22:00 slot is free, so an alert for it is not needed.
+	alert = &domain.AvailabilityAlert{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "21:00", ToTime: "22:00", PartySize: 2}
+	assert.ErrorIs(t, useCase.CreateAlert(ctx, alert), usecase.ErrTableAvailable)
+
+	for _, invalid := range []*domain.AvailabilityAlert{
+		{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "18:00", ToTime: "21:00", PartySize: 0},
+		{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "21:00", ToTime: "18:00", PartySize: 2},
+		{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "evening", ToTime: "21:00", PartySize: 2},
+		{RestaurantID: "r1", UserID: "u1", Date: date.AddDate(0, 0, -2), FromTime: "18:00", ToTime: "21:00", PartySize: 2},
+	} {
+		assert.ErrorIs(t, useCase.CreateAlert(ctx, invalid), usecase.ErrInvalidAvailabilityAlert)
+	}
+
+	guestCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "u2", Roles: []tenant.Role{tenant.RoleUser}})
+	alert = &domain.AvailabilityAlert{RestaurantID: "r1", UserID: "u1", Date: date, FromTime: "18:00", ToTime: "21:00", PartySize: 2}
+	assert.ErrorIs(t, useCase.CreateAlert(guestCtx, alert), tenant.ErrAccessDenied)
+
+	alertRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestAvailabilityAlertUseCase_CheckAlerts(t *testing.T) {
+	ctx := newTestContext()
+	alertRepo := new(MockAvailabilityAlertRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	notifier := new(MockNotificationService)
+	useCase := usecase.NewAvailabilityAlertUseCase(alertRepo, availabilityRepo, restaurantRepo, notifier, newTestClock())
+
+	now := time.Date(2026, 5, 10, 18, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	tomorrow := today.AddDate(0, 0, 1)
+
+	alerts := []*domain.AvailabilityAlert{
+		// Today's 18:00 slot has already started, so 19:00 is the earliest one left.
+		{ID: "freed", RestaurantID: "r1", UserID: "u1", Date: today, FromTime: "18:00", ToTime: "20:00", PartySize: 2},
+		{ID: "too-big", RestaurantID: "r1", UserID: "u2", Date: today, FromTime: "18:00", ToTime: "20:00", PartySize: 6},
+		{ID: "raced", RestaurantID: "r1", UserID: "u3", Date: today, FromTime: "19:00", ToTime: "19:00", PartySize: 1},
+		{ID: "broken", RestaurantID: "r2", UserID: "u4", Date: tomorrow, FromTime: "19:00", ToTime: "19:00", PartySize: 1},
+	}
+	alertRepo.On("ExpireBefore", ctx, today).Return(3, nil)
+	alertRepo.On("ListActive", ctx, today).Return(alerts, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), today).Return([]*domain.Availability{
+		{ID: "a1", TimeSlot: "18:00", Capacity: 10, Reserved: 0},
+		{ID: "a2", TimeSlot: "19:00", Capacity: 10, Reserved: 7},
+	}, nil).Once()
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r2"), tomorrow).Return(nil, errors.New("connection refused"))
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Pushkin"}, nil).Once()
+	alertRepo.On("MarkNotified", ctx, "freed", "19:00", now).Return(nil)
+	alertRepo.On("MarkNotified", ctx, "raced", "19:00", now).Return(errors.New(common.ErrAvailabilityAlertNotFound))
+	notifier.On("NotifyUser", ctx, "u1", domain.NotificationTypeAvailabilityAlert, mock.Anything, mock.Anything, "r1").Return(nil)
+
+	notified, err := useCase.CheckAlerts(ctx, now)
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, notified)
+	notifier.AssertNumberOfCalls(t, "NotifyUser", 1)
+	alertRepo.AssertNotCalled(t, "MarkNotified", ctx, "too-big", mock.Anything, mock.Anything)
+	availabilityRepo.AssertExpectations(t)
+	restaurantRepo.AssertExpectations(t)
+}
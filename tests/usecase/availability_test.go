@@ -6,19 +6,21 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/common"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockAvailabilityRepository struct {
 	mock.Mock
 }
 
-func (m *mockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (m *mockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	args := m.Called(ctx, restaurantID, date)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -26,8 +28,34 @@ func (m *mockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context,
 	return args.Get(0).([]*domain.Availability), args.Error(1)
 }
 
-func (m *mockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability) error {
-	args := m.Called(ctx, availability)
+func (m *mockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error {
+	args := m.Called(ctx, availability, force)
+	return args.Error(0)
+}
+
+func (m *mockAvailabilityRepository) CreateBatch(ctx context.Context, slots []*domain.Availability) error {
+	args := m.Called(ctx, slots)
+	return args.Error(0)
+}
+
+func (m *mockAvailabilityRepository) ListChanges(ctx context.Context, restaurantID domain.RestaurantID, after domain.SyncCursor, limit int) ([]*domain.Availability, error) {
+	args := m.Called(ctx, restaurantID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Availability), args.Error(1)
+}
+
+func (m *mockAvailabilityRepository) GetByID(ctx context.Context, id string) (*domain.Availability, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Availability), args.Error(1)
+}
+
+func (m *mockAvailabilityRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -36,11 +64,19 @@ func (m *mockAvailabilityRepository) UpdateReservedSeats(ctx context.Context, av
 	return args.Error(0)
 }
 
+func (m *mockAvailabilityRepository) ReconcileReservedSeats(ctx context.Context, from, to time.Time) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
 type mockRestaurantRepository struct {
 	mock.Mock
 }
 
-func (m *mockRestaurantRepository) GetByID(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (m *mockRestaurantRepository) GetByID(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -48,27 +84,70 @@ func (m *mockRestaurantRepository) GetByID(ctx context.Context, id string) (*dom
 	return args.Get(0).(*domain.Restaurant), args.Error(1)
 }
 
-func (m *mockRestaurantRepository) List(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
+func (m *mockRestaurantRepository) GetBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Restaurant), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) IsSlugTaken(ctx context.Context, slug, restaurantID string) (bool, error) {
+	args := m.Called(ctx, slug, restaurantID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) ListAfter(ctx context.Context, filter domain.RestaurantFilter, after domain.RestaurantCursor, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, after, limit)
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) EstimateCount(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) ListLive(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
 	args := m.Called(ctx, offset, limit)
 	return args.Get(0).([]*domain.Restaurant), args.Error(1)
 }
 
+func (m *mockRestaurantRepository) ListByCity(ctx context.Context, cityID string, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, cityID, offset, limit)
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) Search(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *mockRestaurantRepository) ListSisters(ctx context.Context, restaurantID domain.RestaurantID, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
 func (m *mockRestaurantRepository) Create(ctx context.Context, restaurant *domain.Restaurant) error {
 	args := m.Called(ctx, restaurant)
 	return args.Error(0)
 }
 
+func (m *mockRestaurantRepository) CreateBatch(ctx context.Context, restaurants []*domain.Restaurant) error {
+	args := m.Called(ctx, restaurants)
+	return args.Error(0)
+}
+
 func (m *mockRestaurantRepository) Update(ctx context.Context, restaurant *domain.Restaurant) error {
 	args := m.Called(ctx, restaurant)
 	return args.Error(0)
 }
 
-func (m *mockRestaurantRepository) Delete(ctx context.Context, id string) error {
+func (m *mockRestaurantRepository) Delete(ctx context.Context, id domain.RestaurantID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *mockRestaurantRepository) AddFact(ctx context.Context, restaurantID string, fact domain.Fact) (*domain.Fact, error) {
+func (m *mockRestaurantRepository) AddFact(ctx context.Context, restaurantID domain.RestaurantID, fact domain.Fact) (*domain.Fact, error) {
 	args := m.Called(ctx, restaurantID, fact)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -76,7 +155,7 @@ func (m *mockRestaurantRepository) AddFact(ctx context.Context, restaurantID str
 	return args.Get(0).(*domain.Fact), args.Error(1)
 }
 
-func (m *mockRestaurantRepository) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *mockRestaurantRepository) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
@@ -86,11 +165,16 @@ func (m *mockRestaurantRepository) GetRandomFacts(ctx context.Context, count int
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
+func (m *mockRestaurantRepository) GetFactsPool(ctx context.Context, filter domain.FactFilter, limit int) ([]domain.Fact, error) {
+	args := m.Called(ctx, filter, limit)
+	return args.Get(0).([]domain.Fact), args.Error(1)
+}
+
 type mockWorkingHoursRepository struct {
 	mock.Mock
 }
 
-func (m *mockWorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (m *mockWorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]*domain.WorkingHours), args.Error(1)
 }
@@ -100,11 +184,36 @@ func (m *mockWorkingHoursRepository) SetWorkingHours(ctx context.Context, hours
 	return args.Error(0)
 }
 
+func (m *mockWorkingHoursRepository) CreateBatch(ctx context.Context, hours []*domain.WorkingHours) error {
+	args := m.Called(ctx, hours)
+	return args.Error(0)
+}
+
 func (m *mockWorkingHoursRepository) DeleteWorkingHours(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+// stubTransactor runs fn with the caller context and records whether it was a dry run.
+type stubTransactor struct {
+	committed  int
+	rolledBack int
+}
+
+func (s *stubTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		s.rolledBack++
+		return err
+	}
+	s.committed++
+	return nil
+}
+
+func (s *stubTransactor) DryRun(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.rolledBack++
+	return fn(ctx)
+}
+
 func setupTestContext() context.Context {
 	loggerInstance, _ := logger.NewLogger()
 	ctx := context.Background()
@@ -117,7 +226,7 @@ func TestGetAvailability(t *testing.T) {
 	workingHoursRepo := new(mockWorkingHoursRepository)
 	ctx := setupTestContext()
 
-	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo)
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
 
 	restaurantID := "rest123"
 	date := time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)
@@ -142,9 +251,9 @@ func TestGetAvailability(t *testing.T) {
 			},
 		}
 
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(expected, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(expected, nil).Once()
 
-		result, err := useCase.GetAvailability(ctx, restaurantID, date)
+		result, err := useCase.GetAvailability(ctx, domain.RestaurantID(restaurantID), date)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expected, result)
@@ -153,9 +262,9 @@ func TestGetAvailability(t *testing.T) {
 
 	t.Run("error when retrieving availability", func(t *testing.T) {
 		expectedErr := errors.New("database error")
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(nil, expectedErr).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(nil, expectedErr).Once()
 
-		result, err := useCase.GetAvailability(ctx, restaurantID, date)
+		result, err := useCase.GetAvailability(ctx, domain.RestaurantID(restaurantID), date)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -170,7 +279,7 @@ func TestSetAvailability(t *testing.T) {
 	workingHoursRepo := new(mockWorkingHoursRepository)
 	ctx := setupTestContext()
 
-	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo)
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
 
 	t.Run("successful availability setting", func(t *testing.T) {
 		availability := &domain.Availability{
@@ -184,7 +293,7 @@ func TestSetAvailability(t *testing.T) {
 
 		availabilityRepo.On("SetAvailability", mock.Anything, mock.MatchedBy(func(a *domain.Availability) bool {
 			return a.ID == availability.ID && !a.UpdatedAt.IsZero()
-		})).Return(nil).Once()
+		}), false).Return(nil).Once()
 
 		err := useCase.SetAvailability(ctx, availability)
 
@@ -206,7 +315,7 @@ func TestSetAvailability(t *testing.T) {
 		expectedErr := errors.New("database error")
 		availabilityRepo.On("SetAvailability", mock.Anything, mock.MatchedBy(func(a *domain.Availability) bool {
 			return a.ID == availability.ID && !a.UpdatedAt.IsZero()
-		})).Return(expectedErr).Once()
+		}), false).Return(expectedErr).Once()
 
 		err := useCase.SetAvailability(ctx, availability)
 
@@ -216,13 +325,75 @@ func TestSetAvailability(t *testing.T) {
 	})
 }
 
+func TestSetAvailabilityCapacityConflict(t *testing.T) {
+	ctx := setupTestContext()
+	date := time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)
+	bookings := []*domain.Booking{
+		{ID: "b1", RestaurantID: "rest123", Date: date, Time: "18:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed},
+		{ID: "b2", RestaurantID: "rest123", Date: date, Time: "18:00", GuestsCount: 2, Status: domain.BookingStatusCancelled},
+		{ID: "b3", RestaurantID: "rest123", Date: date, Time: "20:00", GuestsCount: 2, Status: domain.BookingStatusPending},
+		{ID: "b4", RestaurantID: "rest123", Date: date, Time: "18:00", GuestsCount: 3, Status: domain.BookingStatusPending},
+	}
+	reserve := func(args mock.Arguments) {
+		args.Get(1).(*domain.Availability).Reserved = 7
+	}
+
+	t.Run("rejected without force", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		bookingRepo := new(MockBookingRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), bookingRepo, new(stubTransactor), newTestClock())
+
+		availability := &domain.Availability{RestaurantID: "rest123", Date: date, TimeSlot: "18:00", Capacity: 5}
+		availabilityRepo.On("SetAvailability", ctx, availability, false).Run(reserve).Return(errors.New(common.ErrCapacityBelowReserved)).Once()
+		bookingRepo.On("GetByRestaurantID", ctx, domain.RestaurantID("rest123")).Return(bookings, nil).Once()
+
+		err := useCase.SetAvailability(ctx, availability)
+
+		assert.ErrorIs(t, err, usecase.ErrCapacityBelowReserved)
+		var conflict *usecase.CapacityConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, 7, conflict.Availability.Reserved)
+		assert.Equal(t, []*domain.Booking{bookings[0], bookings[3]}, conflict.ImpactedBookings)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		bookingRepo := new(MockBookingRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), bookingRepo, new(stubTransactor), newTestClock())
+
+		availability := &domain.Availability{RestaurantID: "rest123", Date: date, TimeSlot: "18:00", Capacity: 5}
+		availabilityRepo.On("SetAvailability", ctx, availability, true).Run(reserve).Return(nil).Once()
+		bookingRepo.On("GetByRestaurantID", ctx, domain.RestaurantID("rest123")).Return(bookings, nil).Once()
+
+		impacted, err := useCase.ForceSetAvailability(ctx, availability)
+
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.Booking{bookings[0], bookings[3]}, impacted)
+	})
+
+	t.Run("forced without conflict", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		bookingRepo := new(MockBookingRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), bookingRepo, new(stubTransactor), newTestClock())
+
+		availability := &domain.Availability{RestaurantID: "rest123", Date: date, TimeSlot: "18:00", Capacity: 10}
+		availabilityRepo.On("SetAvailability", ctx, availability, true).Run(reserve).Return(nil).Once()
+
+		impacted, err := useCase.ForceSetAvailability(ctx, availability)
+
+		require.NoError(t, err)
+		assert.Empty(t, impacted)
+		bookingRepo.AssertNotCalled(t, "GetByRestaurantID", mock.Anything, mock.Anything)
+	})
+}
+
 func TestUpdateReservedSeats(t *testing.T) {
 	availabilityRepo := new(mockAvailabilityRepository)
 	restaurantRepo := new(mockRestaurantRepository)
 	workingHoursRepo := new(mockWorkingHoursRepository)
 	ctx := setupTestContext()
 
-	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo)
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
 	availabilityID := "avail1"
 
 	t.Run("successful reserved seats update (increase)", func(t *testing.T) {
@@ -256,6 +427,133 @@ func TestUpdateReservedSeats(t *testing.T) {
 		assert.Equal(t, expectedErr, err)
 		availabilityRepo.AssertExpectations(t)
 	})
+	t.Run("overflow error is passed through", func(t *testing.T) {
+		delta := 5
+		overflowErr := &domain.ReservedSeatsError{AvailabilityID: availabilityID, Capacity: 10, Reserved: 8, Delta: delta, Err: domain.ErrReservedSeatsOverflow}
+		availabilityRepo.On("UpdateReservedSeats", ctx, availabilityID, delta).Return(overflowErr).Once()
+
+		err := useCase.UpdateReservedSeats(ctx, availabilityID, delta)
+
+		assert.ErrorIs(t, err, domain.ErrReservedSeatsOverflow)
+		var seatsErr *domain.ReservedSeatsError
+		require.ErrorAs(t, err, &seatsErr)
+		assert.Equal(t, 8, seatsErr.Reserved)
+		availabilityRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetAvailabilityDelta(t *testing.T) {
+	ctx := setupTestContext()
+	availabilityRepo := new(mockAvailabilityRepository)
+	restaurantRepo := new(mockRestaurantRepository)
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
+
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+	since := domain.SyncCursor{ChangedAt: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC), ID: "a1"}
+	changedAt := since.ChangedAt.Add(time.Minute)
+
+	t.Run("returns the changed slots and the version of the last", func(t *testing.T) {
+		slots := []*domain.Availability{
+			{ID: "a3", UpdatedAt: changedAt},
+			{ID: "a2", UpdatedAt: changedAt.Add(time.Minute)},
+			{ID: "a1", UpdatedAt: changedAt.Add(2 * time.Minute)},
+		}
+		availabilityRepo.On("ListChanges", ctx, domain.RestaurantID("restaurant1"), since, 3).Return(slots, nil).Once()
+
+		delta, err := useCase.GetAvailabilityDelta(ctx, "restaurant1", since, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, slots[:2], delta.Slots)
+		assert.Equal(t, domain.SyncCursor{ChangedAt: changedAt.Add(time.Minute), ID: "a2"}, delta.Version)
+		assert.True(t, delta.HasMore)
+	})
+
+	t.Run("keeps the version when nothing changed", func(t *testing.T) {
+		availabilityRepo.On("ListChanges", ctx, domain.RestaurantID("restaurant1"), since, usecase.DefaultSyncLimit+1).
+			Return([]*domain.Availability{}, nil).Once()
+
+		delta, err := useCase.GetAvailabilityDelta(ctx, "restaurant1", since, 0)
+
+		require.NoError(t, err)
+		assert.Empty(t, delta.Slots)
+		assert.Equal(t, since, delta.Version)
+		assert.False(t, delta.HasMore)
+	})
+
+	t.Run("restaurant not found", func(t *testing.T) {
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("missing")).Return(nil, errors.New(common.ErrRestaurantNotFound)).Once()
+
+		_, err := useCase.GetAvailabilityDelta(ctx, "missing", domain.SyncCursor{}, 10)
+
+		assert.EqualError(t, err, common.ErrRestaurantNotFound)
+	})
+
+	availabilityRepo.AssertExpectations(t)
+}
+
+func TestReconcileReservedSeats(t *testing.T) {
+	availabilityRepo := new(mockAvailabilityRepository)
+	ctx := setupTestContext()
+
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
+
+	today := time.Date(testNow.Year(), testNow.Month(), testNow.Day(), 0, 0, 0, 0, time.UTC)
+
+	t.Run("returns corrected slots", func(t *testing.T) {
+		drifts := []domain.ReservedSeatsDrift{{AvailabilityID: "avail1", Recorded: 6, Actual: 4}}
+		availabilityRepo.On("ReconcileReservedSeats", ctx, today, time.Time{}).Return(drifts, nil).Once()
+
+		result, err := useCase.ReconcileReservedSeats(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, drifts, result)
+		availabilityRepo.AssertExpectations(t)
+	})
+
+	t.Run("error from repository", func(t *testing.T) {
+		expectedErr := errors.New("database error")
+		availabilityRepo.On("ReconcileReservedSeats", ctx, today, time.Time{}).Return(nil, expectedErr).Once()
+
+		result, err := useCase.ReconcileReservedSeats(ctx)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestReservedSeatsReport(t *testing.T) {
+	ctx := setupTestContext()
+	date := time.Date(2025, 5, 10, 0, 0, 0, 0, time.UTC)
+	drifts := []domain.ReservedSeatsDrift{{AvailabilityID: "avail1", Date: date, TimeSlot: "19:00", Recorded: 6, Actual: 2}}
+
+	t.Run("report only rolls the correction back", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		transactor := new(stubTransactor)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), transactor, newTestClock())
+
+		availabilityRepo.On("ReconcileReservedSeats", ctx, date, date).Return(drifts, nil).Once()
+
+		result, err := useCase.ReservedSeatsReport(ctx, date, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, drifts, result)
+		assert.Equal(t, 1, transactor.rolledBack)
+		assert.Equal(t, 0, transactor.committed)
+	})
+
+	t.Run("fix commits the correction", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		transactor := new(stubTransactor)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), transactor, newTestClock())
+
+		availabilityRepo.On("ReconcileReservedSeats", ctx, date, date).Return(drifts, nil).Once()
+
+		result, err := useCase.ReservedSeatsReport(ctx, date, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, drifts, result)
+		assert.Equal(t, 1, transactor.committed)
+	})
 }
 
 func TestCheckAvailability(t *testing.T) {
@@ -264,7 +562,7 @@ func TestCheckAvailability(t *testing.T) {
 	workingHoursRepo := new(mockWorkingHoursRepository)
 	ctx := setupTestContext()
 
-	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo)
+	useCase := usecase.NewAvailabilityUseCase(availabilityRepo, restaurantRepo, workingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
 
 	restaurantID := "rest123"
 	date := time.Date(2023, 10, 15, 0, 0, 0, 0, time.UTC)
@@ -283,9 +581,9 @@ func TestCheckAvailability(t *testing.T) {
 			},
 		}
 
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(availabilities, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(availabilities, nil).Once()
 
-		isAvailable, err := useCase.CheckAvailability(ctx, restaurantID, date, timeSlot, guestsCount)
+		isAvailable, err := useCase.CheckAvailability(ctx, domain.RestaurantID(restaurantID), date, timeSlot, guestsCount)
 
 		assert.NoError(t, err)
 		assert.True(t, isAvailable)
@@ -305,9 +603,9 @@ func TestCheckAvailability(t *testing.T) {
 			},
 		}
 
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(availabilities, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(availabilities, nil).Once()
 
-		isAvailable, err := useCase.CheckAvailability(ctx, restaurantID, date, timeSlot, guestsCount)
+		isAvailable, err := useCase.CheckAvailability(ctx, domain.RestaurantID(restaurantID), date, timeSlot, guestsCount)
 
 		assert.NoError(t, err)
 		assert.False(t, isAvailable)
@@ -328,9 +626,9 @@ func TestCheckAvailability(t *testing.T) {
 			},
 		}
 
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(availabilities, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(availabilities, nil).Once()
 
-		isAvailable, err := useCase.CheckAvailability(ctx, restaurantID, date, nonExistentTimeSlot, guestsCount)
+		isAvailable, err := useCase.CheckAvailability(ctx, domain.RestaurantID(restaurantID), date, nonExistentTimeSlot, guestsCount)
 
 		assert.NoError(t, err)
 		assert.False(t, isAvailable)
@@ -340,9 +638,9 @@ func TestCheckAvailability(t *testing.T) {
 	t.Run("error when getting availability data", func(t *testing.T) {
 		guestsCount := 5
 		expectedErr := errors.New("database error")
-		availabilityRepo.On("GetByRestaurantAndDate", ctx, restaurantID, date).Return(nil, expectedErr).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), date).Return(nil, expectedErr).Once()
 
-		isAvailable, err := useCase.CheckAvailability(ctx, restaurantID, date, timeSlot, guestsCount)
+		isAvailable, err := useCase.CheckAvailability(ctx, domain.RestaurantID(restaurantID), date, timeSlot, guestsCount)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -350,3 +648,184 @@ func TestCheckAvailability(t *testing.T) {
 		availabilityRepo.AssertExpectations(t)
 	})
 }
+
+func TestGenerateAvailability(t *testing.T) {
+	ctx := setupTestContext()
+	restaurantID := "rest123"
+
+	// 2023-10-16 is a Monday.
+	monday := time.Date(2023, 10, 16, 0, 0, 0, 0, time.UTC)
+	workingHours := []*domain.WorkingHours{
+		{RestaurantID: restaurantID, WeekDay: domain.Monday, OpenTime: "18:00", CloseTime: "21:30"},
+		{RestaurantID: restaurantID, WeekDay: domain.Tuesday, OpenTime: "12:00", CloseTime: "13:00", ValidFrom: monday.AddDate(0, 0, 7)},
+	}
+
+	t.Run("slots follow working hours", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		workingHoursRepo := new(mockWorkingHoursRepository)
+		transactor := new(stubTransactor)
+		closureRepo := new(MockRestaurantClosureRepository)
+		specialDayRepo := new(MockSpecialDayRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), workingHoursRepo, closureRepo, specialDayRepo, new(MockBookingRepository), transactor, newTestClock())
+
+		workingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(workingHours, nil).Once()
+		specialDayRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.SpecialDay{}, nil).Once()
+		closureRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.RestaurantClosure{}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), monday).Return([]*domain.Availability{
+			{ID: "kept", RestaurantID: restaurantID, Date: monday, TimeSlot: "18:00", Capacity: 20, Reserved: 6},
+			{ID: "resized", RestaurantID: restaurantID, Date: monday, TimeSlot: "19:00", Capacity: 10, Reserved: 4},
+		}, nil).Once()
+		availabilityRepo.On("SetAvailability", ctx, mock.MatchedBy(func(a *domain.Availability) bool {
+			return a.TimeSlot == "19:00" && a.Capacity == 20
+		}), false).Return(nil).Once()
+		availabilityRepo.On("CreateBatch", ctx, mock.MatchedBy(func(slots []*domain.Availability) bool {
+			return len(slots) == 1 && slots[0].TimeSlot == "20:00"
+		})).Return(nil).Once()
+
+		generated, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID,
+			From:         monday,
+			To:           monday.AddDate(0, 0, 1),
+			SlotDuration: time.Hour,
+			Capacity:     20,
+		})
+
+		assert.NoError(t, err)
+		slots := make([]string, 0, len(generated))
+		for _, a := range generated {
+			assert.Equal(t, monday, a.Date)
+			assert.Equal(t, 20, a.Capacity)
+			slots = append(slots, a.TimeSlot)
+		}
+		assert.Equal(t, []string{"18:00", "19:00", "20:00"}, slots)
+		assert.Equal(t, "kept", generated[0].ID, "an unchanged slot is not written again")
+		availabilityRepo.AssertExpectations(t)
+		assert.Equal(t, 1, transactor.committed)
+	})
+
+	t.Run("closed days get no slots", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		workingHoursRepo := new(mockWorkingHoursRepository)
+		closureRepo := new(MockRestaurantClosureRepository)
+		specialDayRepo := new(MockSpecialDayRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), workingHoursRepo, closureRepo, specialDayRepo, new(MockBookingRepository), new(stubTransactor), newTestClock())
+
+		nextMonday := monday.AddDate(0, 0, 7)
+		workingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(workingHours, nil).Once()
+		specialDayRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.SpecialDay{}, nil).Once()
+		closureRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.RestaurantClosure{
+			{RestaurantID: restaurantID, StartsOn: monday, ReopensOn: nextMonday},
+		}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), nextMonday).Return([]*domain.Availability{}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), nextMonday.AddDate(0, 0, 1)).Return([]*domain.Availability{}, nil).Once()
+		availabilityRepo.On("CreateBatch", ctx, mock.MatchedBy(func(slots []*domain.Availability) bool {
+			return len(slots) == 4
+		})).Return(nil).Once()
+
+		generated, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: nextMonday.AddDate(0, 0, 1), SlotDuration: time.Hour, Capacity: 20,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, generated, 4)
+		assert.Equal(t, nextMonday, generated[0].Date, "the restaurant reopens on the next Monday")
+		availabilityRepo.AssertExpectations(t)
+	})
+
+	t.Run("special days replace working hours", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		workingHoursRepo := new(mockWorkingHoursRepository)
+		closureRepo := new(MockRestaurantClosureRepository)
+		specialDayRepo := new(MockSpecialDayRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), workingHoursRepo, closureRepo, specialDayRepo, new(MockBookingRepository), new(stubTransactor), newTestClock())
+
+		tuesday := monday.AddDate(0, 0, 1)
+		workingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(workingHours, nil).Once()
+		specialDayRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.SpecialDay{
+			{RestaurantID: restaurantID, Date: monday, IsClosed: true},
+			{RestaurantID: restaurantID, Date: tuesday, OpenTime: "22:00", CloseTime: "01:00"},
+		}, nil).Once()
+		closureRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.RestaurantClosure{}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), tuesday).Return([]*domain.Availability{}, nil).Once()
+		availabilityRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]*domain.Availability")).Return(nil).Once()
+
+		generated, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: tuesday, SlotDuration: time.Hour, Capacity: 20,
+		})
+
+		assert.NoError(t, err)
+		slots := make([]string, 0, len(generated))
+		for _, a := range generated {
+			assert.Equal(t, tuesday, a.Date, "the restaurant is closed on the special Monday")
+			slots = append(slots, a.TimeSlot)
+		}
+		assert.Equal(t, []string{"22:00", "23:00"}, slots, "the Tuesday without working hours opens late")
+		availabilityRepo.AssertExpectations(t)
+	})
+
+	t.Run("dry run rolls back", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		workingHoursRepo := new(mockWorkingHoursRepository)
+		transactor := new(stubTransactor)
+		closureRepo := new(MockRestaurantClosureRepository)
+		specialDayRepo := new(MockSpecialDayRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), workingHoursRepo, closureRepo, specialDayRepo, new(MockBookingRepository), transactor, newTestClock())
+
+		workingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(workingHours, nil).Once()
+		specialDayRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.SpecialDay{}, nil).Once()
+		closureRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.RestaurantClosure{}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), monday).Return([]*domain.Availability{}, nil).Once()
+		availabilityRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]*domain.Availability")).Return(nil).Once()
+
+		generated, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: monday, SlotDuration: time.Hour, Capacity: 20, DryRun: true,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, generated, 3)
+		assert.Equal(t, 0, transactor.committed)
+		assert.Equal(t, 1, transactor.rolledBack)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		useCase := usecase.NewAvailabilityUseCase(new(mockAvailabilityRepository), new(mockRestaurantRepository), new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock())
+
+		_, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: monday.AddDate(0, 0, -1), SlotDuration: time.Hour, Capacity: 20,
+		})
+		assert.ErrorIs(t, err, usecase.ErrInvalidDateRange)
+
+		_, err = useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: monday, SlotDuration: 0, Capacity: 20,
+		})
+		assert.ErrorIs(t, err, usecase.ErrInvalidSlotDuration)
+
+		_, err = useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: monday, SlotDuration: time.Hour,
+		})
+		assert.ErrorIs(t, err, usecase.ErrInvalidCapacity)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		availabilityRepo := new(mockAvailabilityRepository)
+		workingHoursRepo := new(mockWorkingHoursRepository)
+		transactor := new(stubTransactor)
+		closureRepo := new(MockRestaurantClosureRepository)
+		specialDayRepo := new(MockSpecialDayRepository)
+		useCase := usecase.NewAvailabilityUseCase(availabilityRepo, new(mockRestaurantRepository), workingHoursRepo, closureRepo, specialDayRepo, new(MockBookingRepository), transactor, newTestClock())
+
+		expectedErr := errors.New("database error")
+		workingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(workingHours, nil).Once()
+		specialDayRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.SpecialDay{}, nil).Once()
+		closureRepo.On("ListByRestaurant", ctx, restaurantID, monday).Return([]*domain.RestaurantClosure{}, nil).Once()
+		availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID(restaurantID), monday).Return([]*domain.Availability{}, nil).Once()
+		availabilityRepo.On("CreateBatch", ctx, mock.AnythingOfType("[]*domain.Availability")).Return(expectedErr).Once()
+
+		generated, err := useCase.GenerateAvailability(ctx, usecase.GenerateAvailabilityParams{
+			RestaurantID: restaurantID, From: monday, To: monday, SlotDuration: time.Hour, Capacity: 20,
+		})
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, generated)
+		assert.Equal(t, 1, transactor.rolledBack)
+	})
+}
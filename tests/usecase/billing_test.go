@@ -0,0 +1,225 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBillingRepository struct {
+	mock.Mock
+}
+
+func (m *MockBillingRepository) CreateSubscription(ctx context.Context, subscription *domain.Subscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockBillingRepository) GetSubscription(ctx context.Context, id string) (*domain.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Subscription), args.Error(1)
+}
+
+func (m *MockBillingRepository) GetActiveSubscription(ctx context.Context, restaurantID string) (*domain.Subscription, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Subscription), args.Error(1)
+}
+
+func (m *MockBillingRepository) CancelSubscription(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockBillingRepository) ListSubscriptions(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Subscription, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Subscription), args.Error(1)
+}
+
+func (m *MockBillingRepository) CreateInvoices(ctx context.Context, periodStart, periodEnd, issuedAt, dueAt time.Time) ([]*domain.Invoice, error) {
+	args := m.Called(ctx, periodStart, periodEnd, issuedAt, dueAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingRepository) GetInvoice(ctx context.Context, id string) (*domain.Invoice, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingRepository) ListInvoices(ctx context.Context, filter domain.InvoiceFilter, offset, limit int) ([]*domain.Invoice, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Invoice), args.Error(1)
+}
+
+func (m *MockBillingRepository) UpdateInvoicePayment(ctx context.Context, invoice *domain.Invoice) error {
+	args := m.Called(ctx, invoice)
+	return args.Error(0)
+}
+
+func (m *MockBillingRepository) RecordPaymentEvent(ctx context.Context, event *domain.PaymentEvent) (bool, error) {
+	args := m.Called(ctx, event)
+	return args.Bool(0), args.Error(1)
+}
+
+var testBillingSettings = usecase.BillingSettings{
+	Prices:          map[domain.Plan]int64{domain.PlanPro: 4900},
+	Currency:        "EUR",
+	PaymentTermDays: 14,
+	WebhookSecret:   "secret",
+	Issuer:          "Restaurant Booking Platform",
+}
+
+func signPayload(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(testBillingSettings.WebhookSecret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestBillingSubscribe(t *testing.T) {
+	ctx := adminContext()
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+
+	billingRepo := new(MockBillingRepository)
+	billingRepo.On("GetActiveSubscription", mock.Anything, "r1").Return(&domain.Subscription{ID: "s1", RestaurantID: "r1"}, nil)
+	billingRepo.On("CancelSubscription", mock.Anything, "s1", now).Return(nil)
+	billingRepo.On("CreateSubscription", mock.Anything, mock.Anything).Return(nil)
+
+	slots := 1000
+	quotaRepo := new(MockQuotaRepository)
+	quotaRepo.On("GetPlan", mock.Anything, "r1").Return(&domain.RestaurantPlan{RestaurantID: "r1", Plan: domain.PlanFree, ActiveSlots: &slots}, nil)
+	quotaRepo.On("SetPlan", mock.Anything, mock.Anything).Return(nil)
+
+	billing := usecase.NewBillingUseCase(billingRepo, quotaRepo, restaurantRepo, new(stubTransactor), newTestClock(), testBillingSettings)
+
+	_, err := billing.Subscribe(ctx, "r1", domain.PlanFree, nil, now)
+	require.ErrorIs(t, err, usecase.ErrInvalidPlan)
+
+	negative := int64(-1)
+	_, err = billing.Subscribe(ctx, "r1", domain.PlanPro, &negative, now)
+	require.ErrorIs(t, err, usecase.ErrInvalidPrice)
+
+	subscription, err := billing.Subscribe(ctx, "r1", domain.PlanPro, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4900), subscription.MonthlyPrice)
+	assert.Equal(t, "EUR", subscription.Currency)
+	assert.Equal(t, now, subscription.StartedAt)
+
+	billingRepo.AssertCalled(t, "CancelSubscription", mock.Anything, "s1", now)
+	quotaRepo.AssertCalled(t, "SetPlan", mock.Anything, mock.MatchedBy(func(plan *domain.RestaurantPlan) bool {
+		return plan.Plan == domain.PlanPro && plan.ActiveSlots != nil && *plan.ActiveSlots == slots
+	}))
+}
+
+func TestBillingGenerateInvoices(t *testing.T) {
+	ctx := adminContext()
+	billingRepo := new(MockBillingRepository)
+	billing := usecase.NewBillingUseCase(billingRepo, new(MockQuotaRepository), new(MockRestaurantRepository), new(stubTransactor), newTestClock(), testBillingSettings)
+
+	now := time.Date(2025, 6, 1, 4, 0, 0, 0, time.UTC)
+	_, err := billing.GenerateInvoices(ctx, now, now)
+	require.ErrorIs(t, err, usecase.ErrInvalidDateRange, "the current month is not billed")
+
+	periodStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	invoices := []*domain.Invoice{{ID: "i1"}}
+	billingRepo.On("CreateInvoices", mock.Anything, periodStart, periodEnd, now, now.AddDate(0, 0, 14)).Return(invoices, nil)
+
+	generated, err := billing.GenerateInvoices(ctx, time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC), now)
+	require.NoError(t, err)
+	assert.Equal(t, invoices, generated)
+}
+
+func TestBillingHandlePaymentEvent(t *testing.T) {
+	ctx := newTestContext()
+	billingRepo := new(MockBillingRepository)
+	billing := usecase.NewBillingUseCase(billingRepo, new(MockQuotaRepository), new(MockRestaurantRepository), new(stubTransactor), newTestClock(), testBillingSettings)
+
+	payload := []byte(`{"id":"evt1","type":"payment.succeeded","invoice_id":"i1","payment_id":"pay1","occurred_at":"2025-06-03T10:00:00Z"}`)
+
+	err := billing.HandlePaymentEvent(ctx, payload, "deadbeef")
+	require.ErrorIs(t, err, usecase.ErrInvalidWebhookSignature)
+
+	invalid := []byte(`{"id":"evt0","type":"payment.refunded","invoice_id":"i1"}`)
+	err = billing.HandlePaymentEvent(ctx, invalid, signPayload(invalid))
+	require.ErrorIs(t, err, usecase.ErrInvalidPaymentEvent)
+
+	invoice := &domain.Invoice{ID: "i1", Number: "INV-202505-000001", Status: domain.InvoiceStatusOpen}
+	billingRepo.On("GetInvoice", mock.Anything, "i1").Return(invoice, nil)
+	billingRepo.On("RecordPaymentEvent", mock.Anything, mock.MatchedBy(func(event *domain.PaymentEvent) bool {
+		return event.ID == "evt1"
+	})).Return(true, nil).Once()
+	billingRepo.On("UpdateInvoicePayment", mock.Anything, invoice).Return(nil).Once()
+
+	require.NoError(t, billing.HandlePaymentEvent(ctx, payload, "sha256="+signPayload(payload)))
+	assert.Equal(t, domain.InvoiceStatusPaid, invoice.Status)
+	assert.Equal(t, "pay1", invoice.PaymentID)
+	require.NotNil(t, invoice.PaidAt)
+
+	billingRepo.On("RecordPaymentEvent", mock.Anything, mock.Anything).Return(false, nil).Once()
+	require.NoError(t, billing.HandlePaymentEvent(ctx, payload, signPayload(payload)), "a redelivered event is a no-op")
+
+	failed := []byte(`{"id":"evt2","type":"payment.failed","invoice_id":"i1"}`)
+	billingRepo.On("RecordPaymentEvent", mock.Anything, mock.Anything).Return(true, nil).Once()
+	require.NoError(t, billing.HandlePaymentEvent(ctx, failed, signPayload(failed)))
+	assert.Equal(t, domain.InvoiceStatusPaid, invoice.Status, "a paid invoice stays paid")
+	billingRepo.AssertNumberOfCalls(t, "UpdateInvoicePayment", 1)
+
+	billingRepo.On("GetInvoice", mock.Anything, "missing").Return(nil, errors.New(common.ErrInvoiceNotFound))
+	unknown := []byte(`{"id":"evt3","type":"payment.succeeded","invoice_id":"missing"}`)
+	err = billing.HandlePaymentEvent(ctx, unknown, signPayload(unknown))
+	require.EqualError(t, err, common.ErrInvoiceNotFound)
+}
+
+func TestFormatInvoice(t *testing.T) {
+	invoice := &domain.Invoice{
+		Number:         "INV-202505-000001",
+		RestaurantName: "Chez Marie",
+		Plan:           domain.PlanPro,
+		PeriodStart:    time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		Amount:         4900,
+		Currency:       "EUR",
+		Status:         domain.InvoiceStatusOpen,
+		IssuedAt:       time.Date(2025, 6, 1, 4, 0, 0, 0, time.UTC),
+		DueAt:          time.Date(2025, 6, 15, 4, 0, 0, 0, time.UTC),
+	}
+
+	text, err := usecase.FormatInvoice(invoice, "Restaurant Booking Platform")
+	require.NoError(t, err)
+	assert.Contains(t, text, "# Invoice INV-202505-000001")
+	assert.Contains(t, text, "Chez Marie")
+	assert.Contains(t, text, "pro plan, 2025-05-01 to 2025-05-31")
+	assert.Contains(t, text, "Please pay by 2025-06-15")
+}
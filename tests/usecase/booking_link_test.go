@@ -0,0 +1,217 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBookingLinkRepository keeps links in memory, so tokens can be issued and resolved again.
+type memoryBookingLinkRepository struct {
+	links map[string]*domain.BookingLink
+}
+
+func newMemoryBookingLinkRepository() *memoryBookingLinkRepository {
+	return &memoryBookingLinkRepository{links: make(map[string]*domain.BookingLink)}
+}
+
+func (r *memoryBookingLinkRepository) Create(_ context.Context, link *domain.BookingLink) error {
+	stored := *link
+	r.links[link.ID] = &stored
+	return nil
+}
+
+func (r *memoryBookingLinkRepository) GetByID(_ context.Context, id string) (*domain.BookingLink, error) {
+	link, ok := r.links[id]
+	if !ok {
+		return nil, errors.New(common.ErrBookingLinkNotFound)
+	}
+	found := *link
+	return &found, nil
+}
+
+func (r *memoryBookingLinkRepository) MarkUsed(_ context.Context, id string, usedAt time.Time) error {
+	link, ok := r.links[id]
+	if !ok || link.UsedAt != nil {
+		return errors.New(common.ErrBookingLinkAlreadyUsed)
+	}
+	link.UsedAt = &usedAt
+	return nil
+}
+
+func setupBookingLinkUseCase() (usecase.BookingLinkUseCase, *memoryBookingLinkRepository, *MockBookingRepository, *stubTransactor, *clock.Fake) {
+	linkRepo := newMemoryBookingLinkRepository()
+	bookingRepo := new(MockBookingRepository)
+	transactor := new(stubTransactor)
+	testClock := newTestClock()
+
+	useCase := usecase.NewBookingLinkUseCase(linkRepo, bookingRepo, transactor, "secret", "https://example.com/", time.Hour, testClock)
+	return useCase, linkRepo, bookingRepo, transactor, testClock
+}
+
+func TestBookingLinkUseCase_CreateAndResolve(t *testing.T) {
+	ctx := newTestContext()
+	useCase, _, bookingRepo, _, _ := setupBookingLinkUseCase()
+
+	booking := &domain.Booking{ID: "booking1", Status: domain.BookingStatusConfirmed}
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(booking, nil)
+
+	link, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{Action: domain.BookingLinkActionView})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/b/"+link.Token, link.URL)
+	assert.Len(t, link.Token, 28)
+	assert.Equal(t, testNow.Add(time.Hour), link.ExpiresAt)
+
+	for range 2 {
+		resolved, err := useCase.ResolveBookingLink(ctx, link.Token)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BookingLinkActionView, resolved.Action)
+		assert.Equal(t, booking, resolved.Booking)
+	}
+}
+
+func TestBookingLinkUseCase_RejectsTamperedAndUnknownTokens(t *testing.T) {
+	ctx := newTestContext()
+	useCase, linkRepo, bookingRepo, _, _ := setupBookingLinkUseCase()
+
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1"}, nil)
+
+	link, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{Action: domain.BookingLinkActionView})
+	require.NoError(t, err)
+
+	tampered := link.Token[:len(link.Token)-1] + map[bool]string{true: "A", false: "B"}[!strings.HasSuffix(link.Token, "A")]
+	_, err = useCase.ResolveBookingLink(ctx, tampered)
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingLink)
+
+	_, err = useCase.ResolveBookingLink(ctx, "short")
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingLink)
+
+	// A correctly signed token of a deleted link.
+	linkRepo.links = map[string]*domain.BookingLink{}
+	_, err = useCase.ResolveBookingLink(ctx, link.Token)
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingLink)
+}
+
+func TestBookingLinkUseCase_ExpiredAndSingleUse(t *testing.T) {
+	ctx := newTestContext()
+	useCase, _, bookingRepo, _, testClock := setupBookingLinkUseCase()
+
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1"}, nil)
+
+	_, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{
+		Action:    domain.BookingLinkActionView,
+		ExpiresAt: testNow.Add(-time.Minute),
+	})
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingLink)
+
+	link, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{
+		Action:    domain.BookingLinkActionView,
+		SingleUse: true,
+	})
+	require.NoError(t, err)
+
+	_, err = useCase.ResolveBookingLink(ctx, link.Token)
+	require.NoError(t, err)
+	_, err = useCase.ResolveBookingLink(ctx, link.Token)
+	assert.ErrorIs(t, err, usecase.ErrBookingLinkUsed)
+
+	other, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{Action: domain.BookingLinkActionView})
+	require.NoError(t, err)
+	testClock.Advance(time.Hour + time.Second)
+	_, err = useCase.ResolveBookingLink(ctx, other.Token)
+	assert.ErrorIs(t, err, usecase.ErrBookingLinkExpired)
+}
+
+func TestBookingLinkUseCase_UseBookingLink(t *testing.T) {
+	ctx := newTestContext()
+	useCase, _, bookingRepo, transactor, _ := setupBookingLinkUseCase()
+
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1"}, nil)
+
+	view, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{Action: domain.BookingLinkActionView})
+	require.NoError(t, err)
+	cancel, err := useCase.CreateBookingLink(ctx, "booking1", usecase.BookingLinkOptions{
+		Action:    domain.BookingLinkActionCancel,
+		SingleUse: true,
+	})
+	require.NoError(t, err)
+
+	calls := 0
+	cancelBooking := func(_ context.Context, booking *domain.Booking) error {
+		calls++
+		assert.Equal(t, "booking1", booking.ID)
+		return nil
+	}
+
+	_, err = useCase.UseBookingLink(ctx, view.Token, domain.BookingLinkActionCancel, cancelBooking)
+	assert.ErrorIs(t, err, usecase.ErrInvalidBookingLink, "a link to the details cannot cancel")
+
+	resolved, err := useCase.ResolveBookingLink(ctx, cancel.Token)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BookingLinkActionCancel, resolved.Action, "opening the cancellation page does not use the link up")
+
+	booking, err := useCase.UseBookingLink(ctx, cancel.Token, domain.BookingLinkActionCancel, cancelBooking)
+	require.NoError(t, err)
+	assert.Equal(t, "booking1", booking.ID)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, transactor.committed)
+
+	_, err = useCase.UseBookingLink(ctx, cancel.Token, domain.BookingLinkActionCancel, cancelBooking)
+	assert.ErrorIs(t, err, usecase.ErrBookingLinkUsed)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBookingLinkUseCase_IssueConfirmationLinks(t *testing.T) {
+	ctx := newTestContext()
+	useCase, _, bookingRepo, _, _ := setupBookingLinkUseCase()
+
+	date := testNow.AddDate(0, 0, 3).Truncate(24 * time.Hour)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1", Date: date, Time: "19:30"}, nil)
+
+	view, cancel, err := useCase.IssueConfirmationLinks(ctx, "booking1")
+	require.NoError(t, err)
+
+	start := date.Add(19*time.Hour + 30*time.Minute)
+	assert.Equal(t, domain.BookingLinkActionView, view.Action)
+	assert.False(t, view.SingleUse)
+	assert.Equal(t, start.Add(24*time.Hour), view.ExpiresAt)
+	assert.Equal(t, domain.BookingLinkActionCancel, cancel.Action)
+	assert.True(t, cancel.SingleUse)
+	assert.Equal(t, start, cancel.ExpiresAt)
+	assert.NotEqual(t, view.Token, cancel.Token)
+}
+
+func TestBookingLinkUseCase_CheckInToken(t *testing.T) {
+	ctx := newTestContext()
+	useCase, _, bookingRepo, _, _ := setupBookingLinkUseCase()
+
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1"}, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking2")).Return(&domain.Booking{ID: "booking2"}, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("missing")).Return(nil, errors.New(common.ErrBookingNotFound))
+
+	token, err := useCase.CheckInToken(ctx, "booking1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(token, usecase.CheckInTokenPrefix+"booking1."))
+
+	again, err := useCase.CheckInToken(ctx, "booking1")
+	require.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	other, err := useCase.CheckInToken(ctx, "booking2")
+	require.NoError(t, err)
+	assert.NotEqual(t, strings.TrimPrefix(token, usecase.CheckInTokenPrefix+"booking1."), strings.TrimPrefix(other, usecase.CheckInTokenPrefix+"booking2."))
+
+	_, err = useCase.CheckInToken(ctx, "missing")
+	assert.EqualError(t, err, common.ErrBookingNotFound)
+}
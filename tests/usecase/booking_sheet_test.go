@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookingSheetUseCase_RenderBookingSheet(t *testing.T) {
+	ctx := newTestContext()
+	restaurantRepo := new(MockRestaurantRepository)
+	bookingRepo := new(MockBookingRepository)
+	userRepo := new(MockUserRepository)
+	menuRepo := new(MockMenuRepository)
+	useCase := usecase.NewBookingSheetUseCase(restaurantRepo, bookingRepo, userRepo, menuRepo)
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Vogue"}, nil)
+	bookingRepo.On("GetByRestaurantID", ctx, domain.RestaurantID("r1")).Return([]*domain.Booking{
+		{ID: "late", UserID: "u1", Date: date, Time: "21:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed},
+		{ID: "early", UserID: "u2", Date: date, Time: "18:30", GuestsCount: 6, Status: domain.BookingStatusPending},
+		{ID: "cancelled", UserID: "u3", Date: date, Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusCancelled},
+		{ID: "tomorrow", UserID: "u3", Date: date.AddDate(0, 0, 1), Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed},
+	}, nil)
+	userRepo.On("GetByID", ctx, domain.UserID("u1")).Return(&domain.User{ID: "u1", Name: "Anna Petrova", Phone: "+79161234567"}, nil).Once()
+	userRepo.On("GetByID", ctx, domain.UserID("u2")).Return(nil, errors.New("connection refused")).Once()
+	menuRepo.On("GetPreOrder", ctx, mock.Anything).Return([]domain.PreOrderItem{}, nil)
+
+	document, err := useCase.RenderBookingSheet(ctx, "r1", date)
+
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4")))
+	early, late := bytes.Index(document, []byte("18:30")), bytes.Index(document, []byte("Anna Petrova"))
+	assert.True(t, early > 0 && late > early, "bookings are ordered by time")
+	assert.Contains(t, string(document), "2 bookings, 8 guests")
+	assert.NotContains(t, string(document), "cancelled")
+	menuRepo.AssertNumberOfCalls(t, "GetPreOrder", 2)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r2"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err = useCase.RenderBookingSheet(staffCtx, "r1", date)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func TestFormatBookingSheet(t *testing.T) {
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	text := usecase.FormatBookingSheet(&domain.Restaurant{Name: "Vogue"}, date, []usecase.SheetBooking{
+		{
+			Booking: &domain.Booking{
+				Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed, Occasion: domain.BookingOccasion("birthday"),
+				Comment: "Table by the window please, one of the guests uses a wheelchair and needs step-free access",
+			},
+			Guest:    &domain.User{Name: "Anna Petrova", Phone: "+79161234567"},
+			PreOrder: []domain.PreOrderItem{{Name: "Borscht", Quantity: 2, Notes: "no sour cream"}, {Name: "Pelmeni", Quantity: 1}},
+		},
+		{Booking: &domain.Booking{Time: "20:30", GuestsCount: 2, Status: domain.BookingStatusPending}},
+	})
+
+	assert.Equal(t, `# Vogue
+
+Bookings of Sunday, 10.05.2026: 2 bookings, 6 guests.
+
+Time   Guests  Guest                     Phone             Status
+-----  ------  ------------------------  ----------------  ---------
+19:00       4  Anna Petrova              +79161234567      confirmed
+               Occasion: birthday
+               Comment: Table by the window please, one of the guests uses a
+               wheelchair and needs step-free access
+               Pre-order: 2 x Borscht (no sour cream), 1 x Pelmeni
+20:30       2                                              pending
+`, text)
+
+	empty := usecase.FormatBookingSheet(&domain.Restaurant{Name: "Vogue"}, date, nil)
+	assert.Contains(t, empty, "No bookings.")
+}
@@ -6,13 +6,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger"
 	"github.com/flexer2006/case-back-restaurant-go/internal/logger/ports"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -59,6 +62,13 @@ func (m *MockLogger) Sync() error {
 	return args.Error(0)
 }
 
+// testNow is the time use cases under test take for now, unless the test moves their clock.
+var testNow = time.Date(2025, time.June, 2, 12, 0, 0, 0, time.UTC)
+
+func newTestClock() *clock.Fake {
+	return clock.NewFake(testNow)
+}
+
 func newTestContext() context.Context {
 	mockLogger := new(MockLogger)
 	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything).Return()
@@ -78,7 +88,7 @@ type MockBookingRepository struct {
 	mock.Mock
 }
 
-func (m *MockBookingRepository) GetByID(ctx context.Context, id string) (*domain.Booking, error) {
+func (m *MockBookingRepository) GetByID(ctx context.Context, id domain.BookingID) (*domain.Booking, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -86,7 +96,15 @@ func (m *MockBookingRepository) GetByID(ctx context.Context, id string) (*domain
 	return args.Get(0).(*domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.Booking, error) {
+func (m *MockBookingRepository) GetActiveBetween(ctx context.Context, from, to time.Time) ([]*domain.Booking, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.Booking, error) {
 	args := m.Called(ctx, restaurantID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -94,8 +112,30 @@ func (m *MockBookingRepository) GetByRestaurantID(ctx context.Context, restauran
 	return args.Get(0).([]*domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Booking, error) {
-	args := m.Called(ctx, userID)
+func (m *MockBookingRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Booking, error) {
+	args := m.Called(ctx, restaurantID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) ListByRestaurant(
+	ctx context.Context,
+	restaurantID domain.RestaurantID,
+	filter domain.BookingFilter,
+	after domain.BookingCursor,
+	limit int,
+) ([]*domain.Booking, error) {
+	args := m.Called(ctx, restaurantID, filter, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) ListByUser(ctx context.Context, userID domain.UserID, after domain.BookingCursor, limit int) ([]*domain.Booking, error) {
+	args := m.Called(ctx, userID, after, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -107,18 +147,29 @@ func (m *MockBookingRepository) Create(ctx context.Context, booking *domain.Book
 	return args.Error(0)
 }
 
-func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id string, status domain.BookingStatus) error {
+func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id domain.BookingID, status domain.BookingStatus) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
 }
 
+func (m *MockBookingRepository) ChangeStatus(
+	ctx context.Context,
+	id domain.BookingID,
+	from []domain.BookingStatus,
+	status domain.BookingStatus,
+	at time.Time,
+) (bool, error) {
+	args := m.Called(ctx, id, from, status, at)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockBookingRepository) AddAlternative(ctx context.Context, alternative *domain.BookingAlternative) error {
 	args := m.Called(ctx, alternative)
 	return args.Error(0)
 }
 
-func (m *MockBookingRepository) AcceptAlternative(ctx context.Context, alternativeID string) error {
-	args := m.Called(ctx, alternativeID)
+func (m *MockBookingRepository) AcceptAlternative(ctx context.Context, alternativeID, tableID string) error {
+	args := m.Called(ctx, alternativeID, tableID)
 	return args.Error(0)
 }
 
@@ -139,7 +190,7 @@ type MockAvailabilityRepository struct {
 	mock.Mock
 }
 
-func (m *MockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID string, date time.Time) ([]*domain.Availability, error) {
+func (m *MockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context, restaurantID domain.RestaurantID, date time.Time) ([]*domain.Availability, error) {
 	args := m.Called(ctx, restaurantID, date)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -147,8 +198,34 @@ func (m *MockAvailabilityRepository) GetByRestaurantAndDate(ctx context.Context,
 	return args.Get(0).([]*domain.Availability), args.Error(1)
 }
 
-func (m *MockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability) error {
-	args := m.Called(ctx, availability)
+func (m *MockAvailabilityRepository) SetAvailability(ctx context.Context, availability *domain.Availability, force bool) error {
+	args := m.Called(ctx, availability, force)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) CreateBatch(ctx context.Context, slots []*domain.Availability) error {
+	args := m.Called(ctx, slots)
+	return args.Error(0)
+}
+
+func (m *MockAvailabilityRepository) ListChanges(ctx context.Context, restaurantID domain.RestaurantID, after domain.SyncCursor, limit int) ([]*domain.Availability, error) {
+	args := m.Called(ctx, restaurantID, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Availability), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) GetByID(ctx context.Context, id string) (*domain.Availability, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Availability), args.Error(1)
+}
+
+func (m *MockAvailabilityRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
@@ -157,6 +234,14 @@ func (m *MockAvailabilityRepository) UpdateReservedSeats(ctx context.Context, av
 	return args.Error(0)
 }
 
+func (m *MockAvailabilityRepository) ReconcileReservedSeats(ctx context.Context, from, to time.Time) ([]domain.ReservedSeatsDrift, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ReservedSeatsDrift), args.Error(1)
+}
+
 type MockNotificationService struct {
 	mock.Mock
 }
@@ -181,25 +266,34 @@ func (m *MockNotificationService) MarkAsRead(ctx context.Context, notificationID
 	return args.Error(0)
 }
 
+func (m *MockNotificationService) GetNotification(ctx context.Context, notificationID string) (*domain.Notification, error) {
+	args := m.Called(ctx, notificationID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Notification), args.Error(1)
+}
+
 func TestGetBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	booking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusPending,
 	}
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(booking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(booking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking retrieval", func(t *testing.T) {
 		ctx := newTestContext()
@@ -221,45 +315,50 @@ func TestGetBooking(t *testing.T) {
 func TestGetRestaurantBookings(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
+	date := time.Date(2026, 6, 12, 0, 0, 0, 0, time.UTC)
 	bookings := []*domain.Booking{
-		{
-			ID:           "booking-123",
-			RestaurantID: "restaurant-456",
-			UserID:       "user-789",
-			Date:         time.Now().Add(24 * time.Hour),
-			Time:         "19:00",
-			GuestsCount:  4,
-			Status:       domain.BookingStatusPending,
-		},
-		{
-			ID:           "booking-124",
-			RestaurantID: "restaurant-456",
-			UserID:       "user-790",
-			Date:         time.Now().Add(48 * time.Hour),
-			Time:         "20:00",
-			GuestsCount:  2,
-			Status:       domain.BookingStatusConfirmed,
-		},
+		{ID: "booking-125", RestaurantID: "restaurant-456", Date: date, Time: "21:00", Status: domain.BookingStatusConfirmed},
+		{ID: "booking-124", RestaurantID: "restaurant-456", Date: date, Time: "20:00", Status: domain.BookingStatusConfirmed},
+		{ID: "booking-123", RestaurantID: "restaurant-456", Date: date, Time: "19:00", Status: domain.BookingStatusConfirmed},
 	}
+	filter := domain.BookingFilter{Status: domain.BookingStatusConfirmed}
 
-	bookingRepo.On("GetByRestaurantID", mock.Anything, "restaurant-456").Return(bookings, nil)
-	bookingRepo.On("GetByRestaurantID", mock.Anything, "non-existent").Return(nil, errors.New("restaurant not found"))
+	bookingRepo.On("ListByRestaurant", mock.Anything, domain.RestaurantID("restaurant-456"), filter, domain.BookingCursor{}, 3).Return(bookings, nil)
+	bookingRepo.On("ListByRestaurant", mock.Anything, domain.RestaurantID("restaurant-456"), filter, bookings[1].CursorAfter(), 3).Return(bookings[2:], nil)
+	bookingRepo.On("ListByRestaurant", mock.Anything, domain.RestaurantID("non-existent"), domain.BookingFilter{}, domain.BookingCursor{}, usecase.DefaultPageLimit+1).
+		Return(nil, errors.New("restaurant not found"))
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
-	t.Run("successful restaurant bookings retrieval", func(t *testing.T) {
+	t.Run("pages through the bookings", func(t *testing.T) {
 		ctx := newTestContext()
-		result, err := uc.GetRestaurantBookings(ctx, "restaurant-456")
 
-		assert.NoError(t, err)
-		assert.Equal(t, bookings, result)
+		first, err := uc.GetRestaurantBookings(ctx, domain.RestaurantID("restaurant-456"), filter, usecase.PageRequest{Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, bookings[:2], first.Items)
+		require.True(t, first.HasMore())
+
+		second, err := uc.GetRestaurantBookings(ctx, domain.RestaurantID("restaurant-456"), filter, usecase.PageRequest{Cursor: first.NextCursor, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, bookings[2:], second.Items)
+		assert.False(t, second.HasMore())
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctx := newTestContext()
+
+		for _, cursor := range []string{"not base64!", "e30", "eyJpZCI6MX0"} {
+			_, err := uc.GetRestaurantBookings(ctx, domain.RestaurantID("restaurant-456"), filter, usecase.PageRequest{Cursor: cursor})
+			assert.ErrorIs(t, err, usecase.ErrInvalidPageCursor, cursor)
+		}
 	})
 
 	t.Run("restaurant not found", func(t *testing.T) {
 		ctx := newTestContext()
-		result, err := uc.GetRestaurantBookings(ctx, "non-existent")
+		result, err := uc.GetRestaurantBookings(ctx, domain.RestaurantID("non-existent"), domain.BookingFilter{}, usecase.PageRequest{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -269,57 +368,102 @@ func TestGetRestaurantBookings(t *testing.T) {
 func TestGetUserBookings(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	bookings := []*domain.Booking{
-		{
-			ID:           "booking-123",
-			RestaurantID: "restaurant-456",
-			UserID:       "user-789",
-			Date:         time.Now().Add(24 * time.Hour),
-			Time:         "19:00",
-			GuestsCount:  4,
-			Status:       domain.BookingStatusPending,
-		},
 		{
 			ID:           "booking-124",
 			RestaurantID: "restaurant-457",
 			UserID:       "user-789",
-			Date:         time.Now().Add(48 * time.Hour),
+			Date:         time.Date(2026, 6, 13, 0, 0, 0, 0, time.UTC),
 			Time:         "20:00",
 			GuestsCount:  2,
 			Status:       domain.BookingStatusConfirmed,
 		},
+		{
+			ID:           "booking-123",
+			RestaurantID: "restaurant-456",
+			UserID:       "user-789",
+			Date:         time.Date(2026, 6, 12, 0, 0, 0, 0, time.UTC),
+			Time:         "19:00",
+			GuestsCount:  4,
+			Status:       domain.BookingStatusPending,
+		},
 	}
 
-	bookingRepo.On("GetByUserID", mock.Anything, "user-789").Return(bookings, nil)
-	bookingRepo.On("GetByUserID", mock.Anything, "non-existent").Return(nil, errors.New("user not found"))
+	bookingRepo.On("ListByUser", mock.Anything, domain.UserID("user-789"), domain.BookingCursor{}, usecase.MaxPageLimit+1).Return(bookings, nil)
+	bookingRepo.On("ListByUser", mock.Anything, domain.UserID("non-existent"), domain.BookingCursor{}, usecase.DefaultPageLimit+1).
+		Return(nil, errors.New("user not found"))
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful user bookings retrieval", func(t *testing.T) {
 		ctx := newTestContext()
-		result, err := uc.GetUserBookings(ctx, "user-789")
+		result, err := uc.GetUserBookings(ctx, domain.UserID("user-789"), usecase.PageRequest{Limit: 1000})
 
-		assert.NoError(t, err)
-		assert.Equal(t, bookings, result)
+		require.NoError(t, err)
+		assert.Equal(t, bookings, result.Items)
+		assert.False(t, result.HasMore())
 	})
 
 	t.Run("user not found", func(t *testing.T) {
 		ctx := newTestContext()
-		result, err := uc.GetUserBookings(ctx, "non-existent")
+		result, err := uc.GetUserBookings(ctx, domain.UserID("non-existent"), usecase.PageRequest{})
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
 	})
 }
 
+func TestCreateBooking_InvalidOccasion(t *testing.T) {
+	bookingRepo := new(MockBookingRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
+	notificationSvc := new(MockNotificationService)
+
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
+	_, err := uc.CreateBooking(newTestContext(), &domain.Booking{
+		RestaurantID: "restaurant-456",
+		UserID:       "user-789",
+		Date:         testNow.Add(24 * time.Hour),
+		Time:         "19:00",
+		GuestsCount:  2,
+		Occasion:     "graduation",
+	})
+
+	assert.ErrorIs(t, err, usecase.ErrInvalidOccasion)
+	bookingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	availabilityRepo.AssertNotCalled(t, "GetByRestaurantAndDate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateBooking_InvalidBooking(t *testing.T) {
+	bookingRepo := new(MockBookingRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
+	notificationSvc := new(MockNotificationService)
+
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
+	_, err := uc.CreateBooking(newTestContext(), &domain.Booking{
+		RestaurantID: "restaurant-456",
+		UserID:       "user-789",
+		Date:         testNow.Add(24 * time.Hour),
+		Time:         "7pm",
+		GuestsCount:  2,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidEntity)
+	bookingRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	availabilityRepo.AssertNotCalled(t, "GetByRestaurantAndDate", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCreateBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
-	bookingDate := time.Now().Add(24 * time.Hour)
+	bookingDate := testNow.Add(24 * time.Hour)
 
 	booking := &domain.Booking{
 		RestaurantID: "restaurant-456",
@@ -354,12 +498,13 @@ func TestCreateBooking(t *testing.T) {
 		return b.RestaurantID == booking.RestaurantID && b.Time == booking.Time
 	})).Return(nil)
 
-	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, "restaurant-456", bookingDate).Return(availabilities, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("restaurant-456"), bookingDate).Return(availabilities, nil)
 	availabilityRepo.On("UpdateReservedSeats", mock.Anything, "avail-123", 4).Return(nil)
+	tableRepo.On("ListByRestaurant", mock.Anything, "restaurant-456").Return([]*domain.Table{}, nil)
 
 	notificationSvc.On("NotifyRestaurant", mock.Anything, "restaurant-456", domain.NotificationTypeNewBooking, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking creation", func(t *testing.T) {
 		ctx := newTestContext()
@@ -407,13 +552,14 @@ func TestCreateBooking(t *testing.T) {
 func TestConfirmBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	pendingBooking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusPending,
@@ -423,21 +569,21 @@ func TestConfirmBooking(t *testing.T) {
 		ID:           "booking-124",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "20:00",
 		GuestsCount:  2,
 		Status:       domain.BookingStatusConfirmed,
 	}
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(pendingBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "booking-124").Return(confirmedBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(pendingBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-124")).Return(confirmedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
 
-	bookingRepo.On("UpdateStatus", mock.Anything, "booking-123", domain.BookingStatusConfirmed).Return(nil)
+	bookingRepo.On("UpdateStatus", mock.Anything, domain.BookingID("booking-123"), domain.BookingStatusConfirmed).Return(nil)
 
 	notificationSvc.On("NotifyUser", mock.Anything, "user-789", domain.NotificationTypeBookingConfirmed, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking confirmation", func(t *testing.T) {
 		ctx := newTestContext()
@@ -460,18 +606,38 @@ func TestConfirmBooking(t *testing.T) {
 
 		assert.Error(t, err)
 	})
+
+	t.Run("only the restaurant of the booking confirms it", func(t *testing.T) {
+		bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-125")).Return(&domain.Booking{
+			ID:           "booking-125",
+			RestaurantID: "restaurant-456",
+			UserID:       "user-789",
+			Status:       domain.BookingStatusPending,
+		}, nil)
+		bookingRepo.On("UpdateStatus", mock.Anything, domain.BookingID("booking-125"), domain.BookingStatusConfirmed).Return(nil)
+
+		guest := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "user-789", Roles: []tenant.Role{tenant.RoleUser}})
+		assert.ErrorIs(t, uc.ConfirmBooking(guest, "booking-125"), tenant.ErrAccessDenied)
+
+		otherRestaurant := tenant.NewContext(newTestContext(), &tenant.Principal{RestaurantIDs: []string{"restaurant-1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+		assert.ErrorIs(t, uc.ConfirmBooking(otherRestaurant, "booking-125"), tenant.ErrAccessDenied)
+
+		staff := tenant.NewContext(newTestContext(), &tenant.Principal{RestaurantIDs: []string{"restaurant-456"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+		assert.NoError(t, uc.ConfirmBooking(staff, "booking-125"))
+	})
 }
 
 func TestRejectBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	pendingBooking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusPending,
@@ -481,27 +647,35 @@ func TestRejectBooking(t *testing.T) {
 		ID:           "booking-124",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "20:00",
 		GuestsCount:  2,
 		Status:       domain.BookingStatusConfirmed,
 	}
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(pendingBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "booking-124").Return(confirmedBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(pendingBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-124")).Return(confirmedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
 
-	bookingRepo.On("UpdateStatus", mock.Anything, "booking-123", domain.BookingStatusRejected).Return(nil)
+	pending := []domain.BookingStatus{domain.BookingStatusPending}
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-123"), pending, domain.BookingStatusRejected, testNow).Return(true, nil)
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-124"), pending, domain.BookingStatusRejected, testNow).Return(false, nil)
+
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("restaurant-456"), pendingBooking.Date).Return([]*domain.Availability{
+		{ID: "avail-19", RestaurantID: "restaurant-456", Date: pendingBooking.Date, TimeSlot: "19:00", Capacity: 20, Reserved: 4},
+	}, nil)
+	availabilityRepo.On("UpdateReservedSeats", mock.Anything, "avail-19", -4).Return(nil)
 
 	notificationSvc.On("NotifyUser", mock.Anything, "user-789", domain.NotificationTypeBookingRejected, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking rejection", func(t *testing.T) {
 		ctx := newTestContext()
 		err := uc.RejectBooking(ctx, "booking-123", "no available tables")
 
 		assert.NoError(t, err)
+		availabilityRepo.AssertCalled(t, "UpdateReservedSeats", mock.Anything, "avail-19", -4)
 	})
 
 	t.Run("booking already confirmed", func(t *testing.T) {
@@ -510,6 +684,7 @@ func TestRejectBooking(t *testing.T) {
 
 		assert.Error(t, err)
 		assert.Equal(t, usecase.ErrInvalidBookingStatus, err)
+		availabilityRepo.AssertNumberOfCalls(t, "UpdateReservedSeats", 1)
 	})
 
 	t.Run("booking not found", func(t *testing.T) {
@@ -523,13 +698,14 @@ func TestRejectBooking(t *testing.T) {
 func TestCancelBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	pendingBooking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusPending,
@@ -539,27 +715,74 @@ func TestCancelBooking(t *testing.T) {
 		ID:           "booking-124",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "20:00",
 		GuestsCount:  2,
 		Status:       domain.BookingStatusCompleted,
 	}
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(pendingBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "booking-124").Return(completedBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	cancelledBooking := &domain.Booking{
+		ID:           "booking-125",
+		RestaurantID: "restaurant-456",
+		UserID:       "user-789",
+		Date:         testNow.Add(24 * time.Hour),
+		Time:         "19:00",
+		GuestsCount:  4,
+		Status:       domain.BookingStatusCancelled,
+	}
+
+	seatedBooking := &domain.Booking{
+		ID:           "booking-126",
+		RestaurantID: "restaurant-456",
+		UserID:       "user-789",
+		Date:         testNow.Add(24 * time.Hour),
+		Time:         "19:00",
+		GuestsCount:  2,
+		TableID:      "table-1",
+		Status:       domain.BookingStatusConfirmed,
+	}
+
+	// Read as pending, but rejected by the restaurant before the cancellation takes the lock.
+	rejectedMeanwhile := &domain.Booking{
+		ID:           "booking-127",
+		RestaurantID: "restaurant-456",
+		UserID:       "user-789",
+		Date:         testNow.Add(24 * time.Hour),
+		Time:         "19:00",
+		GuestsCount:  4,
+		Status:       domain.BookingStatusPending,
+	}
 
-	bookingRepo.On("UpdateStatus", mock.Anything, "booking-123", domain.BookingStatusCancelled).Return(nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(pendingBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-124")).Return(completedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-125")).Return(cancelledBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-126")).Return(seatedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-127")).Return(rejectedMeanwhile, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
+
+	active := []domain.BookingStatus{domain.BookingStatusPending, domain.BookingStatusConfirmed}
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-123"), active, domain.BookingStatusCancelled, testNow).Return(true, nil)
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-124"), active, domain.BookingStatusCancelled, testNow).Return(false, nil)
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-125"), active, domain.BookingStatusCancelled, testNow).Return(false, nil)
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-126"), active, domain.BookingStatusCancelled, testNow).Return(true, nil)
+	bookingRepo.On("ChangeStatus", mock.Anything, domain.BookingID("booking-127"), active, domain.BookingStatusCancelled, testNow).Return(false, nil)
+
+	availabilityRepo.On("GetByRestaurantAndDate", mock.Anything, domain.RestaurantID("restaurant-456"), pendingBooking.Date).Return([]*domain.Availability{
+		{ID: "avail-19", RestaurantID: "restaurant-456", Date: pendingBooking.Date, TimeSlot: "19:00", Capacity: 20, Reserved: 4},
+		{ID: "avail-20", RestaurantID: "restaurant-456", Date: pendingBooking.Date, TimeSlot: "20:00", Capacity: 20},
+	}, nil)
+	availabilityRepo.On("UpdateReservedSeats", mock.Anything, "avail-19", -4).Return(nil)
 
 	notificationSvc.On("NotifyRestaurant", mock.Anything, "restaurant-456", domain.NotificationTypeBookingCancelled, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking cancellation", func(t *testing.T) {
 		ctx := newTestContext()
 		err := uc.CancelBooking(ctx, "booking-123")
 
 		assert.NoError(t, err)
+		availabilityRepo.AssertCalled(t, "UpdateReservedSeats", mock.Anything, "avail-19", -4)
 	})
 
 	t.Run("booking already completed", func(t *testing.T) {
@@ -570,6 +793,31 @@ func TestCancelBooking(t *testing.T) {
 		assert.Equal(t, usecase.ErrInvalidBookingStatus, err)
 	})
 
+	t.Run("booking already cancelled", func(t *testing.T) {
+		ctx := newTestContext()
+		err := uc.CancelBooking(ctx, "booking-125")
+
+		assert.Equal(t, usecase.ErrInvalidBookingStatus, err)
+		availabilityRepo.AssertNumberOfCalls(t, "UpdateReservedSeats", 1)
+	})
+
+	t.Run("booking seated at a table", func(t *testing.T) {
+		ctx := newTestContext()
+		err := uc.CancelBooking(ctx, "booking-126")
+
+		assert.NoError(t, err)
+		availabilityRepo.AssertNumberOfCalls(t, "UpdateReservedSeats", 1)
+	})
+
+	t.Run("booking rejected meanwhile", func(t *testing.T) {
+		ctx := newTestContext()
+		err := uc.CancelBooking(ctx, "booking-127")
+
+		assert.Equal(t, usecase.ErrInvalidBookingStatus, err)
+		availabilityRepo.AssertNumberOfCalls(t, "UpdateReservedSeats", 1)
+		notificationSvc.AssertNumberOfCalls(t, "NotifyRestaurant", 2)
+	})
+
 	t.Run("booking not found", func(t *testing.T) {
 		ctx := newTestContext()
 		err := uc.CancelBooking(ctx, "non-existent")
@@ -581,13 +829,14 @@ func TestCancelBooking(t *testing.T) {
 func TestCompleteBooking(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	confirmedBooking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusConfirmed,
@@ -597,19 +846,19 @@ func TestCompleteBooking(t *testing.T) {
 		ID:           "booking-124",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "20:00",
 		GuestsCount:  2,
 		Status:       domain.BookingStatusPending,
 	}
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(confirmedBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "booking-124").Return(pendingBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(confirmedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-124")).Return(pendingBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
 
-	bookingRepo.On("UpdateStatus", mock.Anything, "booking-123", domain.BookingStatusCompleted).Return(nil)
+	bookingRepo.On("UpdateStatus", mock.Anything, domain.BookingID("booking-123"), domain.BookingStatusCompleted).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful booking completion", func(t *testing.T) {
 		ctx := newTestContext()
@@ -637,13 +886,14 @@ func TestCompleteBooking(t *testing.T) {
 func TestSuggestAlternativeTime(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	pendingBooking := &domain.Booking{
 		ID:           "booking-123",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "19:00",
 		GuestsCount:  4,
 		Status:       domain.BookingStatusPending,
@@ -653,19 +903,19 @@ func TestSuggestAlternativeTime(t *testing.T) {
 		ID:           "booking-124",
 		RestaurantID: "restaurant-456",
 		UserID:       "user-789",
-		Date:         time.Now().Add(24 * time.Hour),
+		Date:         testNow.Add(24 * time.Hour),
 		Time:         "20:00",
 		GuestsCount:  2,
 		Status:       domain.BookingStatusConfirmed,
 	}
 
-	alternativeDate := time.Now().Add(25 * time.Hour)
+	alternativeDate := testNow.Add(25 * time.Hour)
 	alternativeTime := "20:00"
 	message := "unfortunately, all tables at 19:00 are occupied, but we can offer a time at 20:00"
 
-	bookingRepo.On("GetByID", mock.Anything, "booking-123").Return(pendingBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "booking-124").Return(confirmedBooking, nil)
-	bookingRepo.On("GetByID", mock.Anything, "non-existent").Return(nil, errors.New("booking not found"))
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-123")).Return(pendingBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("booking-124")).Return(confirmedBooking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID("non-existent")).Return(nil, errors.New("booking not found"))
 
 	bookingRepo.On("AddAlternative", mock.Anything, mock.MatchedBy(func(alt *domain.BookingAlternative) bool {
 		alt.ID = "alt-new-123"
@@ -674,7 +924,7 @@ func TestSuggestAlternativeTime(t *testing.T) {
 
 	notificationSvc.On("NotifyUser", mock.Anything, "user-789", domain.NotificationTypeAlternativeOffer, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful alternative time suggestion", func(t *testing.T) {
 		ctx := newTestContext()
@@ -705,13 +955,14 @@ func TestSuggestAlternativeTime(t *testing.T) {
 func TestAcceptAlternative(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	// Создаем тестовые данные
 	alternativeID := "alt-123"
 	bookingID := "booking-123"
 	restaurantID := "rest-123"
-	alternativeDate := time.Now().AddDate(0, 0, 1)
+	alternativeDate := testNow.AddDate(0, 0, 1)
 	alternativeTime := "18:00"
 
 	alternative := &domain.BookingAlternative{
@@ -720,7 +971,7 @@ func TestAcceptAlternative(t *testing.T) {
 		Date:      alternativeDate,
 		Time:      alternativeTime,
 		Message:   "New proposed time",
-		CreatedAt: time.Now(),
+		CreatedAt: testNow,
 	}
 
 	booking := &domain.Booking{
@@ -733,12 +984,13 @@ func TestAcceptAlternative(t *testing.T) {
 	// Настраиваем моки
 	bookingRepo.On("GetAlternativeByID", mock.Anything, alternativeID).Return(alternative, nil)
 	bookingRepo.On("GetAlternativeByID", mock.Anything, "non-existent").Return(nil, errors.New("alternative not found"))
-	bookingRepo.On("GetByID", mock.Anything, bookingID).Return(booking, nil)
-	bookingRepo.On("AcceptAlternative", mock.Anything, alternativeID).Return(nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID(bookingID)).Return(booking, nil)
+	bookingRepo.On("AcceptAlternative", mock.Anything, alternativeID, "").Return(nil)
+	tableRepo.On("ListByRestaurant", mock.Anything, restaurantID).Return([]*domain.Table{}, nil)
 
 	notificationSvc.On("NotifyRestaurant", mock.Anything, restaurantID, domain.NotificationTypeAlternativeAccepted, mock.Anything, mock.Anything, bookingID).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful alternative time acceptance", func(t *testing.T) {
 		ctx := newTestContext()
@@ -746,8 +998,8 @@ func TestAcceptAlternative(t *testing.T) {
 
 		assert.NoError(t, err)
 		bookingRepo.AssertCalled(t, "GetAlternativeByID", mock.Anything, alternativeID)
-		bookingRepo.AssertCalled(t, "GetByID", mock.Anything, bookingID)
-		bookingRepo.AssertCalled(t, "AcceptAlternative", mock.Anything, alternativeID)
+		bookingRepo.AssertCalled(t, "GetByID", mock.Anything, domain.BookingID(bookingID))
+		bookingRepo.AssertCalled(t, "AcceptAlternative", mock.Anything, alternativeID, "")
 		notificationSvc.AssertCalled(t, "NotifyRestaurant", mock.Anything, restaurantID, domain.NotificationTypeAlternativeAccepted, mock.Anything, mock.Anything, bookingID)
 	})
 
@@ -757,20 +1009,21 @@ func TestAcceptAlternative(t *testing.T) {
 
 		assert.Error(t, err)
 		bookingRepo.AssertCalled(t, "GetAlternativeByID", mock.Anything, "non-existent")
-		bookingRepo.AssertNotCalled(t, "AcceptAlternative", mock.Anything, "non-existent")
+		bookingRepo.AssertNotCalled(t, "AcceptAlternative", mock.Anything, "non-existent", mock.Anything)
 	})
 }
 
 func TestRejectAlternative(t *testing.T) {
 	bookingRepo := new(MockBookingRepository)
 	availabilityRepo := new(MockAvailabilityRepository)
+	tableRepo := new(MockTableRepository)
 	notificationSvc := new(MockNotificationService)
 
 	// Создаем тестовые данные
 	alternativeID := "alt-123"
 	bookingID := "booking-123"
 	restaurantID := "rest-123"
-	alternativeDate := time.Now().AddDate(0, 0, 1)
+	alternativeDate := testNow.AddDate(0, 0, 1)
 	alternativeTime := "18:00"
 
 	alternative := &domain.BookingAlternative{
@@ -779,7 +1032,7 @@ func TestRejectAlternative(t *testing.T) {
 		Date:      alternativeDate,
 		Time:      alternativeTime,
 		Message:   "New proposed time",
-		CreatedAt: time.Now(),
+		CreatedAt: testNow,
 	}
 
 	booking := &domain.Booking{
@@ -792,12 +1045,12 @@ func TestRejectAlternative(t *testing.T) {
 	// Настраиваем моки
 	bookingRepo.On("GetAlternativeByID", mock.Anything, alternativeID).Return(alternative, nil)
 	bookingRepo.On("GetAlternativeByID", mock.Anything, "non-existent").Return(nil, errors.New("alternative not found"))
-	bookingRepo.On("GetByID", mock.Anything, bookingID).Return(booking, nil)
+	bookingRepo.On("GetByID", mock.Anything, domain.BookingID(bookingID)).Return(booking, nil)
 	bookingRepo.On("RejectAlternative", mock.Anything, alternativeID).Return(nil)
 
 	notificationSvc.On("NotifyRestaurant", mock.Anything, restaurantID, domain.NotificationTypeAlternativeRejected, mock.Anything, mock.Anything, bookingID).Return(nil)
 
-	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, notificationSvc)
+	uc := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
 
 	t.Run("successful alternative time rejection", func(t *testing.T) {
 		ctx := newTestContext()
@@ -805,7 +1058,7 @@ func TestRejectAlternative(t *testing.T) {
 
 		assert.NoError(t, err)
 		bookingRepo.AssertCalled(t, "GetAlternativeByID", mock.Anything, alternativeID)
-		bookingRepo.AssertCalled(t, "GetByID", mock.Anything, bookingID)
+		bookingRepo.AssertCalled(t, "GetByID", mock.Anything, domain.BookingID(bookingID))
 		bookingRepo.AssertCalled(t, "RejectAlternative", mock.Anything, alternativeID)
 		notificationSvc.AssertCalled(t, "NotifyRestaurant", mock.Anything, restaurantID, domain.NotificationTypeAlternativeRejected, mock.Anything, mock.Anything, bookingID)
 	})
@@ -0,0 +1,290 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, organization *domain.Organization) error {
+	args := m.Called(ctx, organization)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) AddRestaurant(ctx context.Context, organizationID, restaurantID string) error {
+	args := m.Called(ctx, organizationID, restaurantID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetByRestaurant(ctx context.Context, restaurantID string) (*domain.Organization, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+type MockBookingTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookingTransferRepository) Create(ctx context.Context, transfer *domain.BookingTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+func (m *MockBookingTransferRepository) GetByID(ctx context.Context, id string) (*domain.BookingTransfer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BookingTransfer), args.Error(1)
+}
+
+func (m *MockBookingTransferRepository) ListByBooking(ctx context.Context, bookingID string) ([]*domain.BookingTransfer, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BookingTransfer), args.Error(1)
+}
+
+func (m *MockBookingTransferRepository) Decide(ctx context.Context, transfer *domain.BookingTransfer) error {
+	args := m.Called(ctx, transfer)
+	return args.Error(0)
+}
+
+type bookingTransferMocks struct {
+	transferRepo     *MockBookingTransferRepository
+	organizationRepo *MockOrganizationRepository
+	bookingRepo      *MockBookingRepository
+	availabilityRepo *MockAvailabilityRepository
+	tableRepo        *MockTableRepository
+	restaurantRepo   *MockRestaurantRepository
+	notifier         *MockNotificationService
+}
+
+func guestContext(userID string) context.Context {
+	return tenant.NewContext(newTestContext(), &tenant.Principal{UserID: userID, Roles: []tenant.Role{tenant.RoleUser}})
+}
+
+func newBookingTransferUseCase() (usecase.BookingTransferUseCase, bookingTransferMocks) {
+	mocks := bookingTransferMocks{
+		transferRepo:     new(MockBookingTransferRepository),
+		organizationRepo: new(MockOrganizationRepository),
+		bookingRepo:      new(MockBookingRepository),
+		availabilityRepo: new(MockAvailabilityRepository),
+		tableRepo:        new(MockTableRepository),
+		restaurantRepo:   new(MockRestaurantRepository),
+		notifier:         new(MockNotificationService),
+	}
+	useCase := usecase.NewBookingTransferUseCase(mocks.transferRepo, mocks.organizationRepo, mocks.bookingRepo,
+		mocks.availabilityRepo, mocks.tableRepo, mocks.restaurantRepo, mocks.notifier, &stubTransactor{}, newTestClock())
+	return useCase, mocks
+}
+
+func TestBookingTransferUseCase_RequestTransfer(t *testing.T) {
+	ctx := newTestContext()
+	useCase, mocks := newBookingTransferUseCase()
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	mocks.bookingRepo.On("GetByID", mock.Anything, domain.BookingID("b1")).Return(&domain.Booking{
+		ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed,
+	}, nil)
+	mocks.organizationRepo.On("GetByRestaurant", ctx, "r1").Return(&domain.Organization{ID: "o1"}, nil)
+	mocks.organizationRepo.On("GetByRestaurant", ctx, "r2").Return(&domain.Organization{ID: "o1"}, nil)
+	mocks.organizationRepo.On("GetByRestaurant", ctx, "other").Return(&domain.Organization{ID: "o2"}, nil)
+	mocks.availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r2"), date).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r2", TimeSlot: "19:00", Capacity: 10, Reserved: 4},
+		{ID: "a2", RestaurantID: "r2", TimeSlot: "21:00", Capacity: 10, Reserved: 8},
+	}, nil)
+	mocks.tableRepo.On("ListByRestaurant", ctx, "r2").Return([]*domain.Table{}, nil)
+	mocks.restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Vogue"}, nil)
+	mocks.restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r2")).Return(&domain.Restaurant{ID: "r2", Name: "Vogue Riverside"}, nil)
+	mocks.transferRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+	mocks.notifier.On("NotifyUser", ctx, "u1", domain.NotificationTypeBookingTransfer, "Move your booking to Vogue Riverside?",
+		"Vogue asks to move your booking for 4 on 10.05.2026 at 19:00 to Vogue Riverside on 10.05.2026 at 19:00: "+
+			"our terrace is closed. Accept or decline the transfer; until then your booking stays as it is.",
+		mock.Anything).Return(nil)
+
+	transfer := &domain.BookingTransfer{BookingID: "b1", TargetRestaurantID: "r2", Message: " our terrace is closed "}
+	require.NoError(t, useCase.RequestTransfer(ctx, transfer))
+	assert.Equal(t, "r1", transfer.SourceRestaurantID)
+	assert.Equal(t, date, transfer.Date)
+	assert.Equal(t, "19:00", transfer.Time)
+
+	// The 21:00 slot has two seats left, too few for the party.
+	err := useCase.RequestTransfer(ctx, &domain.BookingTransfer{BookingID: "b1", TargetRestaurantID: "r2", Time: "21:00"})
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability)
+
+	for _, target := range []string{"other", "r1", ""} {
+		err := useCase.RequestTransfer(ctx, &domain.BookingTransfer{BookingID: "b1", TargetRestaurantID: target})
+		assert.ErrorIs(t, err, usecase.ErrNotSisterRestaurant)
+	}
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r2"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	err = useCase.RequestTransfer(staffCtx, &domain.BookingTransfer{BookingID: "b1", TargetRestaurantID: "r2"})
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	mocks.transferRepo.AssertNumberOfCalls(t, "Create", 1)
+	mocks.notifier.AssertExpectations(t)
+}
+
+func TestBookingTransferUseCase_AcceptTransfer(t *testing.T) {
+	ctx := guestContext("u1")
+	useCase, mocks := newBookingTransferUseCase()
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	mocks.transferRepo.On("GetByID", mock.Anything, "t1").Return(&domain.BookingTransfer{
+		ID: "t1", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Date: date, Time: "20:00",
+		Status: domain.BookingTransferPending,
+	}, nil)
+	mocks.bookingRepo.On("GetByID", mock.Anything, domain.BookingID("b1")).Return(&domain.Booking{
+		ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 4, Comment: "window seat",
+		Status: domain.BookingStatusConfirmed,
+	}, nil)
+	mocks.availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r2"), date).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r2", TimeSlot: "20:00", Capacity: 10, Reserved: 0},
+	}, nil)
+	mocks.tableRepo.On("ListByRestaurant", ctx, "r2").Return([]*domain.Table{}, nil)
+	mocks.bookingRepo.On("Create", ctx, mock.MatchedBy(func(b *domain.Booking) bool {
+		return b.RestaurantID == "r2" && b.UserID == "u1" && b.Time == "20:00" && b.Comment == "window seat" &&
+			b.Status == domain.BookingStatusConfirmed
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Booking).ID = "b2"
+	}).Return(nil)
+	mocks.availabilityRepo.On("UpdateReservedSeats", ctx, "a1", 4).Return(nil)
+	mocks.bookingRepo.On("UpdateStatus", ctx, domain.BookingID("b1"), domain.BookingStatusCancelled).Return(nil)
+	mocks.availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date).Return([]*domain.Availability{
+		{ID: "a0", RestaurantID: "r1", TimeSlot: "19:00", Capacity: 10, Reserved: 4},
+	}, nil)
+	mocks.availabilityRepo.On("UpdateReservedSeats", ctx, "a0", -4).Return(nil)
+	mocks.transferRepo.On("Decide", ctx, mock.MatchedBy(func(transfer *domain.BookingTransfer) bool {
+		return transfer.Status == domain.BookingTransferAccepted && transfer.NewBookingID == "b2" && transfer.DecidedAt != nil
+	})).Return(nil)
+	mocks.notifier.On("NotifyRestaurant", ctx, "r1", domain.NotificationTypeBookingTransfer, mock.Anything, mock.Anything, "b1").Return(nil)
+	mocks.notifier.On("NotifyRestaurant", ctx, "r2", domain.NotificationTypeBookingTransfer, mock.Anything, mock.Anything, "b2").Return(nil)
+
+	guestCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "u2", Roles: []tenant.Role{tenant.RoleUser}})
+	_, err := useCase.AcceptTransfer(guestCtx, "t1")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	booking, err := useCase.AcceptTransfer(ctx, "t1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "b2", booking.ID)
+	assert.Equal(t, 4, booking.GuestsCount)
+	mocks.transferRepo.AssertExpectations(t)
+	mocks.bookingRepo.AssertExpectations(t)
+	mocks.notifier.AssertExpectations(t)
+	mocks.availabilityRepo.AssertExpectations(t)
+}
+
+func TestBookingTransferUseCase_AcceptTransferFullTarget(t *testing.T) {
+	ctx := guestContext("u1")
+	useCase, mocks := newBookingTransferUseCase()
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	mocks.transferRepo.On("GetByID", mock.Anything, "t1").Return(&domain.BookingTransfer{
+		ID: "t1", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Date: date, Time: "20:00",
+		Status: domain.BookingTransferPending,
+	}, nil)
+	mocks.bookingRepo.On("GetByID", mock.Anything, domain.BookingID("b1")).Return(&domain.Booking{
+		ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusPending,
+	}, nil)
+	mocks.availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r2"), date).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r2", TimeSlot: "20:00", Capacity: 10, Reserved: 8},
+	}, nil)
+	mocks.tableRepo.On("ListByRestaurant", ctx, "r2").Return([]*domain.Table{}, nil)
+
+	_, err := useCase.AcceptTransfer(ctx, "t1")
+
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability)
+	mocks.bookingRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	mocks.transferRepo.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything)
+}
+
+func TestBookingTransferUseCase_AcceptTransferSeatsAtTable(t *testing.T) {
+	ctx := guestContext("u1")
+	useCase, mocks := newBookingTransferUseCase()
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	mocks.transferRepo.On("GetByID", mock.Anything, "t1").Return(&domain.BookingTransfer{
+		ID: "t1", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Date: date, Time: "20:00",
+		Status: domain.BookingTransferPending,
+	}, nil)
+	mocks.bookingRepo.On("GetByID", mock.Anything, domain.BookingID("b1")).Return(&domain.Booking{
+		ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 4, TableID: "s4",
+		Status: domain.BookingStatusConfirmed,
+	}, nil)
+	mocks.availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r2"), date).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r2", TimeSlot: "20:00", Capacity: 10, Reserved: 10},
+	}, nil)
+	mocks.tableRepo.On("ListByRestaurant", ctx, "r2").Return([]*domain.Table{
+		{ID: "t6", RestaurantID: "r2", Name: "6", Seats: 6},
+		{ID: "t4", RestaurantID: "r2", Name: "4", Seats: 4},
+	}, nil)
+	mocks.tableRepo.On("ListHolds", ctx, "r2", date).Return([]domain.TableHold{}, nil)
+	mocks.bookingRepo.On("Create", ctx, mock.MatchedBy(func(b *domain.Booking) bool {
+		return b.RestaurantID == "r2" && b.TableID == "t4"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Booking).ID = "b2"
+	}).Return(nil)
+	mocks.bookingRepo.On("UpdateStatus", ctx, domain.BookingID("b1"), domain.BookingStatusCancelled).Return(nil)
+	mocks.transferRepo.On("Decide", ctx, mock.Anything).Return(nil)
+	mocks.notifier.On("NotifyRestaurant", ctx, mock.Anything, domain.NotificationTypeBookingTransfer, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	booking, err := useCase.AcceptTransfer(ctx, "t1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "t4", booking.TableID, "the smallest free table of the target the party fits at")
+	mocks.availabilityRepo.AssertNotCalled(t, "UpdateReservedSeats", mock.Anything, mock.Anything, mock.Anything)
+	mocks.availabilityRepo.AssertNotCalled(t, "GetByRestaurantAndDate", mock.Anything, "r1", mock.Anything)
+}
+
+func TestBookingTransferUseCase_DeclineTransfer(t *testing.T) {
+	ctx := guestContext("u1")
+	useCase, mocks := newBookingTransferUseCase()
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	mocks.transferRepo.On("GetByID", mock.Anything, "t1").Return(&domain.BookingTransfer{
+		ID: "t1", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Status: domain.BookingTransferPending,
+	}, nil)
+	mocks.transferRepo.On("GetByID", ctx, "t2").Return(&domain.BookingTransfer{
+		ID: "t2", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Status: domain.BookingTransferAccepted,
+	}, nil)
+	mocks.transferRepo.On("GetByID", ctx, "t3").Return(&domain.BookingTransfer{
+		ID: "t3", BookingID: "b1", SourceRestaurantID: "r1", TargetRestaurantID: "r2", Status: domain.BookingTransferPending,
+	}, nil)
+	mocks.bookingRepo.On("GetByID", mock.Anything, domain.BookingID("b1")).Return(&domain.Booking{
+		ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", Status: domain.BookingStatusConfirmed,
+	}, nil)
+	mocks.transferRepo.On("Decide", ctx, mock.MatchedBy(func(transfer *domain.BookingTransfer) bool {
+		return transfer.ID == "t1" && transfer.Status == domain.BookingTransferDeclined
+	})).Return(nil)
+	// t3 was decided by a concurrent request after it was read.
+	mocks.transferRepo.On("Decide", ctx, mock.MatchedBy(func(transfer *domain.BookingTransfer) bool {
+		return transfer.ID == "t3"
+	})).Return(errors.New(common.ErrBookingTransferNotFound))
+	mocks.notifier.On("NotifyRestaurant", ctx, "r1", domain.NotificationTypeBookingTransfer, mock.Anything, mock.Anything, "b1").Return(nil).Once()
+
+	assert.ErrorIs(t, useCase.DeclineTransfer(newTestContext(), "t1"), tenant.ErrAccessDenied, "only the guest of the booking decides")
+	require.NoError(t, useCase.DeclineTransfer(ctx, "t1"))
+	assert.ErrorIs(t, useCase.DeclineTransfer(ctx, "t2"), usecase.ErrTransferDecided)
+	assert.ErrorIs(t, useCase.DeclineTransfer(ctx, "t3"), usecase.ErrTransferDecided)
+	mocks.notifier.AssertExpectations(t)
+}
@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkCancellationUseCase_CancelBookings(t *testing.T) {
+	ctx := newTestContext()
+	bookingRepo := new(MockBookingRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	locationRepo := new(MockRestaurantLocationRepository)
+	notifier := new(MockNotificationService)
+	useCase := usecase.NewBulkCancellationUseCase(bookingRepo, availabilityRepo, restaurantRepo, locationRepo, notifier, new(stubTransactor), newTestClock())
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Vogue"}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r1", TimeSlot: "17:00", Capacity: 10, Reserved: 0},
+		{ID: "a2", RestaurantID: "r1", TimeSlot: "19:00", Capacity: 10, Reserved: 4},
+		{ID: "a3", RestaurantID: "r1", TimeSlot: "20:00", Capacity: 10, Reserved: 2},
+	}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date.AddDate(0, 0, 1)).Return([]*domain.Availability{
+		{ID: "a4", RestaurantID: "r1", TimeSlot: "19:00", Capacity: 10, Reserved: 0},
+	}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date.AddDate(0, 0, 2)).Return([]*domain.Availability{}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date.AddDate(0, 0, 3)).Return([]*domain.Availability{
+		{ID: "a5", RestaurantID: "r1", TimeSlot: "19:00", Capacity: 10, Reserved: 0},
+	}, nil)
+	availabilityRepo.On("SetAvailability", ctx, mock.MatchedBy(func(a *domain.Availability) bool {
+		return a.Capacity == 0
+	}), true).Return(nil)
+
+	bookingRepo.On("GetByRestaurantID", ctx, domain.RestaurantID("r1")).Return([]*domain.Booking{
+		{ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed},
+		{ID: "b2", RestaurantID: "r1", UserID: "u2", Date: date, Time: "20:00", GuestsCount: 4, Status: domain.BookingStatusPending},
+		{ID: "cancelled", RestaurantID: "r1", UserID: "u3", Date: date, Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusCancelled},
+		{ID: "next-day", RestaurantID: "r1", UserID: "u4", Date: date.AddDate(0, 0, 1), Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed},
+		{ID: "late", RestaurantID: "r1", UserID: "u5", Date: date, Time: "23:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed},
+	}, nil)
+	bookingRepo.On("UpdateStatus", ctx, domain.BookingID("b1"), domain.BookingStatusCancelled).Return(nil)
+	bookingRepo.On("UpdateStatus", ctx, domain.BookingID("b2"), domain.BookingStatusCancelled).Return(nil)
+	bookingRepo.On("AddAlternative", ctx, mock.Anything).Return(nil)
+
+	locationRepo.On("GetByRestaurant", ctx, "r1").Return(&domain.RestaurantLocation{RestaurantID: "r1", CityID: "c1"}, nil).Once()
+	restaurantRepo.On("ListByCity", ctx, "c1", 0, 50).Return([]*domain.Restaurant{
+		{ID: "r1", Name: "Vogue"},
+		{ID: "p1", Name: "Pushkin"},
+		{ID: "sandbox", Name: "Sandbox", IsTest: true},
+		{ID: "p2", Name: "Cafe"},
+	}, nil).Once()
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("p1"), date).Return([]*domain.Availability{
+		{ID: "p1-a", RestaurantID: "p1", TimeSlot: "19:30", Capacity: 10, Reserved: 0},
+		{ID: "p1-b", RestaurantID: "p1", TimeSlot: "21:30", Capacity: 10, Reserved: 0},
+	}, nil).Once()
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("p2"), date).Return([]*domain.Availability{
+		{ID: "p2-a", RestaurantID: "p2", TimeSlot: "19:00", Capacity: 4, Reserved: 4},
+		{ID: "p2-b", RestaurantID: "p2", TimeSlot: "20:00", Capacity: 6, Reserved: 0},
+	}, nil).Once()
+
+	notifier.On("NotifyUser", ctx, "u1", domain.NotificationTypeBookingCancelled, "Your booking at Vogue was cancelled",
+		"Vogue had to cancel your booking on 10.05.2026 at 19:00: the kitchen is flooded. "+
+			"You can rebook there on 10.05.2026 at 17:00 or 11.05.2026 at 19:00 or 13.05.2026 at 19:00 by accepting one of the alternatives offered. "+
+			"Tables for 2 are also free nearby the same day: Pushkin at 19:30, Cafe at 20:00.",
+		"b1").Return(nil)
+	notifier.On("NotifyUser", ctx, "u2", domain.NotificationTypeBookingCancelled, mock.Anything, mock.Anything, "b2").Return(errors.New("smtp down"))
+
+	report, err := useCase.CancelBookings(ctx, usecase.BulkCancellation{
+		RestaurantID: "r1",
+		Date:         date,
+		FromTime:     "18:00",
+		ToTime:       "22:00",
+		Reason:       " the kitchen is flooded ",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.ClosedSlots)
+	require.Len(t, report.Cancelled, 2)
+	assert.Equal(t, domain.BookingStatusCancelled, report.Cancelled[0].Booking.Status)
+
+	// The 17:00 slot before the window is the nearest for the 20:00 booking as well.
+	alternatives := report.Cancelled[1].Alternatives
+	require.Len(t, alternatives, 3)
+	assert.Equal(t, "17:00", alternatives[0].Time)
+	assert.Equal(t, "2026-05-11", alternatives[1].Date.Format(time.DateOnly))
+	assert.Equal(t, "b2", alternatives[0].BookingID)
+
+	partners := report.Cancelled[1].Partners
+	require.Len(t, partners, 2)
+	assert.Equal(t, "Pushkin", partners[0].RestaurantName)
+	assert.Equal(t, "19:30", partners[0].TimeSlot)
+	assert.Equal(t, "20:00", partners[1].TimeSlot)
+
+	bookingRepo.AssertNumberOfCalls(t, "UpdateStatus", 2)
+	bookingRepo.AssertNumberOfCalls(t, "AddAlternative", 6)
+	availabilityRepo.AssertNumberOfCalls(t, "SetAvailability", 2)
+	notifier.AssertExpectations(t)
+	locationRepo.AssertExpectations(t)
+}
+
+func TestBulkCancellationUseCase_CancelBookingsDryRun(t *testing.T) {
+	ctx := newTestContext()
+	bookingRepo := new(MockBookingRepository)
+	availabilityRepo := new(MockAvailabilityRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	locationRepo := new(MockRestaurantLocationRepository)
+	notifier := new(MockNotificationService)
+	transactor := new(stubTransactor)
+	useCase := usecase.NewBulkCancellationUseCase(bookingRepo, availabilityRepo, restaurantRepo, locationRepo, notifier, transactor, newTestClock())
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1", Name: "Vogue"}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), mock.Anything).Return([]*domain.Availability{
+		{ID: "a1", RestaurantID: "r1", Date: date, TimeSlot: "19:00", Capacity: 10, Reserved: 2},
+	}, nil)
+	availabilityRepo.On("SetAvailability", ctx, mock.Anything, true).Return(nil)
+	bookingRepo.On("GetByRestaurantID", ctx, domain.RestaurantID("r1")).Return([]*domain.Booking{
+		{ID: "b1", RestaurantID: "r1", UserID: "u1", Date: date, Time: "19:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed},
+	}, nil)
+	bookingRepo.On("UpdateStatus", ctx, domain.BookingID("b1"), domain.BookingStatusCancelled).Return(nil)
+	bookingRepo.On("AddAlternative", ctx, mock.Anything).Return(nil)
+	locationRepo.On("GetByRestaurant", ctx, "r1").Return(nil, errors.New("not placed"))
+
+	report, err := useCase.CancelBookings(ctx, usecase.BulkCancellation{RestaurantID: "r1", Date: date, DryRun: true})
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.ClosedSlots)
+	require.Len(t, report.Cancelled, 1)
+	assert.Equal(t, "b1", report.Cancelled[0].Booking.ID)
+	assert.Equal(t, 1, transactor.rolledBack, "the cancellation is previewed in a rolled back transaction")
+	assert.Zero(t, transactor.committed)
+	notifier.AssertNotCalled(t, "NotifyUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkCancellationUseCase_CancelBookingsRejected(t *testing.T) {
+	ctx := newTestContext()
+	bookingRepo := new(MockBookingRepository)
+	useCase := usecase.NewBulkCancellationUseCase(bookingRepo, new(MockAvailabilityRepository), new(MockRestaurantRepository),
+		new(MockRestaurantLocationRepository), new(MockNotificationService), new(stubTransactor), newTestClock())
+
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	for _, invalid := range []usecase.BulkCancellation{
+		{RestaurantID: "r1"},
+		{RestaurantID: "r1", Date: date, FromTime: "evening"},
+		{RestaurantID: "r1", Date: date, FromTime: "22:00", ToTime: "18:00"},
+	} {
+		_, err := useCase.CancelBookings(ctx, invalid)
+		assert.ErrorIs(t, err, usecase.ErrInvalidBulkCancellation)
+	}
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r2"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err := useCase.CancelBookings(staffCtx, usecase.BulkCancellation{RestaurantID: "r1", Date: date})
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	bookingRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/cache"
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// failingCache fails every write, as a cache that is down does.
+type failingCache struct {
+	sets int
+}
+
+func (c *failingCache) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (c *failingCache) Set(context.Context, string, []byte, time.Duration) error {
+	c.sets++
+	return errors.New("connection refused")
+}
+
+func TestCacheWarmerUseCase_WarmCache(t *testing.T) {
+	ctx := setupTestContext()
+	now := time.Date(2026, 6, 1, 18, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	store := cache.NewMemory(clock.NewFake(now))
+
+	restaurants := []*domain.Restaurant{{ID: "rest1", Name: "First"}, {ID: "rest2", Name: "Second"}, {ID: "rest3", Name: "Third"}}
+	slot := &domain.Availability{ID: "slot1", RestaurantID: "rest1", Date: today, TimeSlot: "19:00", Capacity: 10, Reserved: 4}
+
+	restaurantRepo := new(mockRestaurantRepository)
+	restaurantRepo.On("ListAfter", ctx, domain.RestaurantFilter{CityID: "moscow"}, domain.RestaurantCursor{}, 3).Return(restaurants, nil)
+	restaurantRepo.On("ListAfter", ctx, domain.RestaurantFilter{CityID: "kazan"}, domain.RestaurantCursor{}, 3).Return([]*domain.Restaurant(nil), errors.New("timeout"))
+	availabilityRepo := new(mockAvailabilityRepository)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("rest1"), today).Return([]*domain.Availability{slot}, nil)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, mock.Anything, mock.Anything).Return([]*domain.Availability{}, nil)
+
+	warmer := usecase.NewCacheWarmerUseCase(restaurantRepo, availabilityRepo, store, usecase.CacheWarmerSettings{
+		Cities:         []string{"moscow", "kazan"},
+		Days:           2,
+		MaxRestaurants: 2,
+	})
+
+	report, err := warmer.WarmCache(ctx, now)
+	require.NoError(t, err, "a city that can't be read doesn't stop the others")
+	assert.Equal(t, 1, report.Cities)
+	assert.Equal(t, 2, report.Restaurants, "only MaxRestaurants restaurants of a city are warmed")
+	assert.Equal(t, 1, report.Slots)
+	assert.Equal(t, []string{"kazan"}, report.Failed)
+	availabilityRepo.AssertNumberOfCalls(t, "GetByRestaurantAndDate", 4)
+
+	restaurantUseCase := usecase.NewCachedRestaurantUseCase(
+		usecase.NewRestaurantUseCase(restaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock()), store)
+
+	moscow := domain.RestaurantFilter{CityID: "moscow"}
+	page, err := restaurantUseCase.ListRestaurants(ctx, moscow, usecase.PageRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "rest1", page.Items[0].ID)
+	require.True(t, page.HasMore())
+	afterFirst := page.NextCursor
+
+	page, err = restaurantUseCase.ListRestaurants(ctx, moscow, usecase.PageRequest{Cursor: afterFirst, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "rest2", page.Items[0].ID)
+
+	restaurantRepo.On("ListAfter", ctx, moscow, restaurants[0].CursorAfter(), 6).Return(restaurants[1:], nil).Once()
+	page, err = restaurantUseCase.ListRestaurants(ctx, moscow, usecase.PageRequest{Cursor: afterFirst, Limit: 5})
+	require.NoError(t, err)
+	assert.Len(t, page.Items, 2, "a page past the warmed part of the city is read from the database")
+	assert.False(t, page.HasMore())
+
+	availabilityUseCase := usecase.NewCachedAvailabilityUseCase(usecase.NewAvailabilityUseCase(
+		availabilityRepo, restaurantRepo, new(mockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(MockBookingRepository), new(stubTransactor), newTestClock()), store)
+
+	slots, err := availabilityUseCase.GetAvailability(ctx, "rest1", today)
+	require.NoError(t, err)
+	require.Len(t, slots, 1)
+	assert.Equal(t, 6, slots[0].Capacity-slots[0].Reserved)
+	availabilityRepo.AssertNumberOfCalls(t, "GetByRestaurantAndDate", 4)
+
+	_, err = availabilityUseCase.GetAvailability(ctx, "rest3", today)
+	require.NoError(t, err)
+	availabilityRepo.AssertNumberOfCalls(t, "GetByRestaurantAndDate", 5)
+}
+
+func TestCacheWarmerUseCase_StopsWhenTheCacheIsDown(t *testing.T) {
+	ctx := setupTestContext()
+	store := &failingCache{}
+
+	restaurantRepo := new(mockRestaurantRepository)
+	restaurantRepo.On("ListAfter", ctx, mock.Anything, domain.RestaurantCursor{}, usecase.DefaultCacheWarmMaxRestaurants+1).
+		Return([]*domain.Restaurant{{ID: "rest1"}}, nil)
+
+	warmer := usecase.NewCacheWarmerUseCase(restaurantRepo, new(mockAvailabilityRepository), store, usecase.CacheWarmerSettings{
+		Cities: []string{"moscow", "kazan"},
+	})
+
+	_, err := warmer.WarmCache(ctx, testNow)
+	assert.Error(t, err)
+	assert.Equal(t, 1, store.sets)
+
+	moscow := domain.RestaurantFilter{CityID: "moscow"}
+	restaurantRepo.On("ListAfter", ctx, moscow, domain.RestaurantCursor{}, 11).Return([]*domain.Restaurant{{ID: "rest1"}}, nil).Once()
+	page, err := usecase.NewCachedRestaurantUseCase(
+		usecase.NewRestaurantUseCase(restaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock()), store).
+		ListRestaurants(ctx, moscow, usecase.PageRequest{Limit: 10})
+	require.NoError(t, err, "reads fall back to the database")
+	assert.Len(t, page.Items, 1)
+}
@@ -0,0 +1,65 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCatalogPage(t *testing.T) {
+	restaurants := []*domain.Restaurant{
+		{ID: "1", Name: "Alpha"},
+		{ID: "2", Name: "Bravo"},
+		{ID: "3", Name: "Charlie"},
+	}
+
+	t.Run("page with next", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		mockRepo.On("ListLive", ctx, 2, 3).Return(restaurants, nil).Once()
+
+		catalogUC := usecase.NewCatalogUseCase(mockRepo, newTestClock())
+
+		page, err := catalogUC.GetCatalogPage(ctx, 2, 2)
+		assert.NoError(t, err)
+		assert.Len(t, page.Restaurants, 2)
+		assert.True(t, page.HasNext)
+		assert.Equal(t, 2, page.Page)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("last page is cached", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		mockRepo.On("ListLive", ctx, 0, 11).Return(restaurants, nil).Once()
+
+		catalogUC := usecase.NewCatalogUseCase(mockRepo, newTestClock())
+
+		for i := 0; i < 3; i++ {
+			page, err := catalogUC.GetCatalogPage(ctx, 1, 10)
+			assert.NoError(t, err)
+			assert.Len(t, page.Restaurants, 3)
+			assert.False(t, page.HasNext)
+		}
+
+		mockRepo.AssertNumberOfCalls(t, "ListLive", 1)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		repoErr := errors.New("database error")
+		mockRepo.On("ListLive", ctx, 0, 101).Return(nil, repoErr)
+
+		catalogUC := usecase.NewCatalogUseCase(mockRepo, newTestClock())
+
+		page, err := catalogUC.GetCatalogPage(ctx, 0, 0)
+		assert.Equal(t, repoErr, err)
+		assert.Nil(t, page)
+	})
+}
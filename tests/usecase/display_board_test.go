@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisplayBoardUseCase_GetBoard(t *testing.T) {
+	ctx := newTestContext()
+	bookingRepo := new(MockBookingRepository)
+	userRepo := new(MockUserRepository)
+	useCase := usecase.NewDisplayBoardUseCase(bookingRepo, userRepo)
+
+	date := time.Date(2026, 5, 10, 17, 0, 0, 0, time.UTC)
+	seated := time.Date(2026, 5, 10, 16, 0, 0, 0, time.UTC)
+	cancelled := time.Date(2026, 5, 10, 16, 30, 0, 0, time.UTC)
+	bookingRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("r1"), date).Return([]*domain.Booking{
+		{ID: "b1", UserID: "u1", Time: "18:00", GuestsCount: 2, Status: domain.BookingStatusConfirmed, UpdatedAt: time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC)},
+		{ID: "b2", UserID: "u2", Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusCompleted, UpdatedAt: seated},
+		{ID: "b3", UserID: "u1", Time: "20:00", GuestsCount: 2, Status: domain.BookingStatusCancelled, UpdatedAt: cancelled},
+	}, nil)
+	userRepo.On("GetByID", ctx, domain.UserID("u1")).Return(&domain.User{ID: "u1", Name: "Anna Petrova"}, nil)
+	userRepo.On("GetByID", ctx, domain.UserID("u2")).Return(&domain.User{ID: "u2", Name: "Ivan Sidorov"}, nil)
+
+	board, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date})
+
+	require.NoError(t, err)
+	assert.False(t, board.Delta)
+	assert.Equal(t, cancelled, board.UpdatedAt)
+	require.Len(t, board.Bookings, 2, "the full board leaves cancelled bookings out")
+	assert.Equal(t, "Anna Petrova", board.Bookings[0].GuestName)
+	assert.Equal(t, "b2", board.Bookings[1].ID)
+
+	// The display polls with the version it shows and gets only the bookings changed after it.
+	delta, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date, Since: "2026-05-10." + strconv.FormatInt(seated.UnixNano(), 10)})
+	require.NoError(t, err)
+	assert.True(t, delta.Delta)
+	require.Len(t, delta.Bookings, 1)
+	assert.Equal(t, domain.BookingStatusCancelled, delta.Bookings[0].Status)
+
+	unchanged, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date, Since: board.Version})
+	require.NoError(t, err)
+	assert.True(t, unchanged.NotModified)
+	assert.Empty(t, unchanged.Bookings)
+
+	yesterday, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date, Since: "2026-05-09." + strconv.FormatInt(cancelled.UnixNano(), 10)})
+	require.NoError(t, err)
+	assert.False(t, yesterday.Delta, "a version of another day gets the full board")
+	assert.Len(t, yesterday.Bookings, 2)
+
+	notModified, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date, ModifiedSince: cancelled})
+	require.NoError(t, err)
+	assert.True(t, notModified.NotModified)
+
+	userRepo.AssertNumberOfCalls(t, "GetByID", 5)
+}
+
+func TestDisplayBoardUseCase_GetBoardRejected(t *testing.T) {
+	ctx := newTestContext()
+	bookingRepo := new(MockBookingRepository)
+	useCase := usecase.NewDisplayBoardUseCase(bookingRepo, new(MockUserRepository))
+
+	date := time.Date(2026, 5, 10, 17, 0, 0, 0, time.UTC)
+	for _, version := range []string{"latest", "2026-05-10", "yesterday.1", "2026-05-10.soon"} {
+		_, err := useCase.GetBoard(ctx, usecase.BoardQuery{RestaurantID: "r1", Date: date, Since: version})
+		assert.ErrorIs(t, err, usecase.ErrInvalidBoardVersion, version)
+	}
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r2"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err := useCase.GetBoard(staffCtx, usecase.BoardQuery{RestaurantID: "r1", Date: date})
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	bookingRepo.AssertNotCalled(t, "GetByRestaurantAndDate", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,110 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportRepository) ListChanges(ctx context.Context, entity domain.ExportEntity, after time.Time, afterID string, until time.Time, limit int) ([]*domain.ExportRecord, error) {
+	args := m.Called(ctx, entity, after, afterID, until, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ExportRecord), args.Error(1)
+}
+
+func exportRecords(entity domain.ExportEntity, n int, updatedAt time.Time) []*domain.ExportRecord {
+	records := make([]*domain.ExportRecord, n)
+	for i := range records {
+		id := fmt.Sprintf("%s-%04d", entity, i)
+		records[i] = &domain.ExportRecord{
+			Entity:    entity,
+			ID:        id,
+			UpdatedAt: updatedAt,
+			Data:      json.RawMessage(`{"id":"` + id + `"}`),
+		}
+	}
+	return records
+}
+
+func TestExportChanges_PagesThroughEntities(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockExportRepository)
+	export := usecase.NewExportUseCase(repo, newTestClock())
+
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := since.Add(time.Hour)
+	firstPage := exportRecords(domain.ExportBookings, 500, updatedAt)
+
+	repo.On("ListChanges", mock.Anything, domain.ExportRestaurants, since, "", mock.Anything, 500).
+		Return(exportRecords(domain.ExportRestaurants, 2, updatedAt), nil)
+	repo.On("ListChanges", mock.Anything, domain.ExportBookings, since, "", mock.Anything, 500).
+		Return(firstPage, nil)
+	repo.On("ListChanges", mock.Anything, domain.ExportBookings, updatedAt, firstPage[499].ID, mock.Anything, 500).
+		Return(exportRecords(domain.ExportBookings, 1, updatedAt.Add(time.Minute)), nil)
+
+	var exported []*domain.ExportRecord
+	checkpoint, err := export.ExportChanges(ctx, since, []domain.ExportEntity{domain.ExportBookings, domain.ExportRestaurants},
+		func(record *domain.ExportRecord) error {
+			exported = append(exported, record)
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.Len(t, exported, 503)
+	assert.Equal(t, domain.ExportRestaurants, exported[0].Entity, "referenced entities go first")
+	assert.Equal(t, domain.ExportBookings, exported[502].Entity)
+	assert.False(t, checkpoint.Before(testNow.Truncate(time.Microsecond)))
+	repo.AssertExpectations(t)
+}
+
+func TestExportChanges_StopsWhenEmitFails(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockExportRepository)
+	export := usecase.NewExportUseCase(repo, newTestClock())
+
+	repo.On("ListChanges", mock.Anything, domain.ExportRestaurants, time.Time{}, "", mock.Anything, 500).
+		Return(exportRecords(domain.ExportRestaurants, 2, testNow), nil)
+
+	emitErr := errors.New("broken pipe")
+	calls := 0
+	_, err := export.ExportChanges(ctx, time.Time{}, nil, func(*domain.ExportRecord) error {
+		calls++
+		return emitErr
+	})
+
+	assert.ErrorIs(t, err, emitErr)
+	assert.Equal(t, 1, calls)
+	repo.AssertNumberOfCalls(t, "ListChanges", 1)
+}
+
+func TestExportChanges_Rejected(t *testing.T) {
+	repo := new(MockExportRepository)
+	export := usecase.NewExportUseCase(repo, newTestClock())
+	emit := func(*domain.ExportRecord) error { return nil }
+
+	_, err := export.ExportChanges(adminContext(), time.Time{}, []domain.ExportEntity{"passwords"}, emit)
+	assert.ErrorIs(t, err, usecase.ErrInvalidExportEntity)
+
+	ctx := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1"})
+	_, err = export.ExportChanges(ctx, time.Time{}, nil, emit)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	repo.AssertNotCalled(t, "ListChanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -3,9 +3,11 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/clock"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
@@ -19,7 +21,7 @@ type MockRestaurantRepository struct {
 	mock.Mock
 }
 
-func (m *MockRestaurantRepository) GetByID(ctx context.Context, id string) (*domain.Restaurant, error) {
+func (m *MockRestaurantRepository) GetByID(ctx context.Context, id domain.RestaurantID) (*domain.Restaurant, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -27,7 +29,33 @@ func (m *MockRestaurantRepository) GetByID(ctx context.Context, id string) (*dom
 	return args.Get(0).(*domain.Restaurant), args.Error(1)
 }
 
-func (m *MockRestaurantRepository) List(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
+func (m *MockRestaurantRepository) GetBySlug(ctx context.Context, slug string) (*domain.Restaurant, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) IsSlugTaken(ctx context.Context, slug, restaurantID string) (bool, error) {
+	args := m.Called(ctx, slug, restaurantID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) ListAfter(ctx context.Context, filter domain.RestaurantFilter, after domain.RestaurantCursor, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) EstimateCount(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) ListLive(ctx context.Context, offset, limit int) ([]*domain.Restaurant, error) {
 	args := m.Called(ctx, offset, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -35,22 +63,51 @@ func (m *MockRestaurantRepository) List(ctx context.Context, offset, limit int)
 	return args.Get(0).([]*domain.Restaurant), args.Error(1)
 }
 
+func (m *MockRestaurantRepository) ListByCity(ctx context.Context, cityID string, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, cityID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) Search(ctx context.Context, filter domain.RestaurantFilter, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
+func (m *MockRestaurantRepository) ListSisters(ctx context.Context, restaurantID domain.RestaurantID, offset, limit int) ([]*domain.Restaurant, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Restaurant), args.Error(1)
+}
+
 func (m *MockRestaurantRepository) Create(ctx context.Context, restaurant *domain.Restaurant) error {
 	args := m.Called(ctx, restaurant)
 	return args.Error(0)
 }
 
+func (m *MockRestaurantRepository) CreateBatch(ctx context.Context, restaurants []*domain.Restaurant) error {
+	args := m.Called(ctx, restaurants)
+	return args.Error(0)
+}
+
 func (m *MockRestaurantRepository) Update(ctx context.Context, restaurant *domain.Restaurant) error {
 	args := m.Called(ctx, restaurant)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantRepository) Delete(ctx context.Context, id string) error {
+func (m *MockRestaurantRepository) Delete(ctx context.Context, id domain.RestaurantID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
-func (m *MockRestaurantRepository) AddFact(ctx context.Context, restaurantID string, fact domain.Fact) (*domain.Fact, error) {
+func (m *MockRestaurantRepository) AddFact(ctx context.Context, restaurantID domain.RestaurantID, fact domain.Fact) (*domain.Fact, error) {
 	args := m.Called(ctx, restaurantID, fact)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -58,7 +115,7 @@ func (m *MockRestaurantRepository) AddFact(ctx context.Context, restaurantID str
 	return args.Get(0).(*domain.Fact), args.Error(1)
 }
 
-func (m *MockRestaurantRepository) GetFacts(ctx context.Context, restaurantID string) ([]domain.Fact, error) {
+func (m *MockRestaurantRepository) GetFacts(ctx context.Context, restaurantID domain.RestaurantID) ([]domain.Fact, error) {
 	args := m.Called(ctx, restaurantID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -74,6 +131,14 @@ func (m *MockRestaurantRepository) GetRandomFacts(ctx context.Context, count int
 	return args.Get(0).([]domain.Fact), args.Error(1)
 }
 
+func (m *MockRestaurantRepository) GetFactsPool(ctx context.Context, filter domain.FactFilter, limit int) ([]domain.Fact, error) {
+	args := m.Called(ctx, filter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Fact), args.Error(1)
+}
+
 func TestGetRandomFacts(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -87,9 +152,9 @@ func TestGetRandomFacts(t *testing.T) {
 			count:         3,
 			expectedCount: 3,
 			mockFacts: []domain.Fact{
-				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: time.Now()},
-				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: time.Now()},
-				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: time.Now()},
+				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: testNow},
+				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: testNow},
+				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: testNow},
 			},
 			mockError: nil,
 		},
@@ -98,9 +163,9 @@ func TestGetRandomFacts(t *testing.T) {
 			count:         -5,
 			expectedCount: 3,
 			mockFacts: []domain.Fact{
-				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: time.Now()},
-				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: time.Now()},
-				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: time.Now()},
+				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: testNow},
+				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: testNow},
+				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: testNow},
 			},
 			mockError: nil,
 		},
@@ -109,16 +174,16 @@ func TestGetRandomFacts(t *testing.T) {
 			count:         15,
 			expectedCount: 10,
 			mockFacts: []domain.Fact{
-				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: time.Now()},
-				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: time.Now()},
-				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: time.Now()},
-				{ID: "4", RestaurantID: "r4", Content: "Fact 4", CreatedAt: time.Now()},
-				{ID: "5", RestaurantID: "r5", Content: "Fact 5", CreatedAt: time.Now()},
-				{ID: "6", RestaurantID: "r6", Content: "Fact 6", CreatedAt: time.Now()},
-				{ID: "7", RestaurantID: "r7", Content: "Fact 7", CreatedAt: time.Now()},
-				{ID: "8", RestaurantID: "r8", Content: "Fact 8", CreatedAt: time.Now()},
-				{ID: "9", RestaurantID: "r9", Content: "Fact 9", CreatedAt: time.Now()},
-				{ID: "10", RestaurantID: "r10", Content: "Fact 10", CreatedAt: time.Now()},
+				{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: testNow},
+				{ID: "2", RestaurantID: "r2", Content: "Fact 2", CreatedAt: testNow},
+				{ID: "3", RestaurantID: "r3", Content: "Fact 3", CreatedAt: testNow},
+				{ID: "4", RestaurantID: "r4", Content: "Fact 4", CreatedAt: testNow},
+				{ID: "5", RestaurantID: "r5", Content: "Fact 5", CreatedAt: testNow},
+				{ID: "6", RestaurantID: "r6", Content: "Fact 6", CreatedAt: testNow},
+				{ID: "7", RestaurantID: "r7", Content: "Fact 7", CreatedAt: testNow},
+				{ID: "8", RestaurantID: "r8", Content: "Fact 8", CreatedAt: testNow},
+				{ID: "9", RestaurantID: "r9", Content: "Fact 9", CreatedAt: testNow},
+				{ID: "10", RestaurantID: "r10", Content: "Fact 10", CreatedAt: testNow},
 			},
 			mockError: nil,
 		},
@@ -139,7 +204,7 @@ func TestGetRandomFacts(t *testing.T) {
 			expectedRepoCount := tc.expectedCount
 			mockRepo.On("GetRandomFacts", ctx, expectedRepoCount).Return(tc.mockFacts, tc.mockError)
 
-			factsUC := usecase.NewFactsUseCase(mockRepo)
+			factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
 
 			facts, err := factsUC.GetRandomFacts(ctx, tc.count)
 
@@ -161,7 +226,7 @@ func TestGetRandomFacts(t *testing.T) {
 func TestGetRestaurantFacts(t *testing.T) {
 	testCases := []struct {
 		name         string
-		restaurantID string
+		restaurantID domain.RestaurantID
 		mockFacts    []domain.Fact
 		mockError    error
 	}{
@@ -169,9 +234,9 @@ func TestGetRestaurantFacts(t *testing.T) {
 			name:         "successful restaurant facts retrieval",
 			restaurantID: "resto123",
 			mockFacts: []domain.Fact{
-				{ID: "1", RestaurantID: "resto123", Content: "Fact 1", CreatedAt: time.Now()},
-				{ID: "2", RestaurantID: "resto123", Content: "Fact 2", CreatedAt: time.Now()},
-				{ID: "3", RestaurantID: "resto123", Content: "Fact 3", CreatedAt: time.Now()},
+				{ID: "1", RestaurantID: "resto123", Content: "Fact 1", CreatedAt: testNow},
+				{ID: "2", RestaurantID: "resto123", Content: "Fact 2", CreatedAt: testNow},
+				{ID: "3", RestaurantID: "resto123", Content: "Fact 3", CreatedAt: testNow},
 			},
 			mockError: nil,
 		},
@@ -196,7 +261,7 @@ func TestGetRestaurantFacts(t *testing.T) {
 
 			mockRepo.On("GetFacts", ctx, tc.restaurantID).Return(tc.mockFacts, tc.mockError)
 
-			factsUC := usecase.NewFactsUseCase(mockRepo)
+			factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
 
 			facts, err := factsUC.GetRestaurantFacts(ctx, tc.restaurantID)
 
@@ -214,3 +279,180 @@ func TestGetRestaurantFacts(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFilteredRandomFacts(t *testing.T) {
+	pool := []domain.Fact{
+		{ID: "1", RestaurantID: "r1", Content: "Fact 1", CreatedAt: testNow},
+		{ID: "2", RestaurantID: "r1", Content: "Fact 2", CreatedAt: testNow},
+		{ID: "3", RestaurantID: "r1", Content: "Fact 3", CreatedAt: testNow},
+		{ID: "4", RestaurantID: "r1", Content: "Fact 4", CreatedAt: testNow},
+		{ID: "5", RestaurantID: "r2", Content: "Fact 5", CreatedAt: testNow},
+		{ID: "6", RestaurantID: "r3", Content: "Fact 6", CreatedAt: testNow},
+	}
+
+	t.Run("every restaurant is represented before repeats", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{Cuisine: "italian"}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(pool, nil).Once()
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		facts, err := factsUC.GetFilteredRandomFacts(ctx, filter, 3)
+		assert.NoError(t, err)
+		assert.Len(t, facts, 3)
+
+		restaurants := make(map[string]bool)
+		for _, fact := range facts {
+			restaurants[fact.RestaurantID] = true
+		}
+		assert.Len(t, restaurants, 3)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("pool is cached between calls", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{RestaurantID: "r1"}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(pool[:4], nil).Once()
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		for i := 0; i < 3; i++ {
+			facts, err := factsUC.GetFilteredRandomFacts(ctx, filter, 10)
+			assert.NoError(t, err)
+			assert.Len(t, facts, 4)
+		}
+
+		mockRepo.AssertNumberOfCalls(t, "GetFactsPool", 1)
+	})
+
+	t.Run("the pool loaded first makes room for a new filter", func(t *testing.T) {
+		// The use case keeps up to 256 pools.
+		const cachedPools = 256
+
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		mockRepo.On("GetFactsPool", ctx, mock.Anything, mock.AnythingOfType("int")).Return(pool[:1], nil)
+
+		now := clock.NewFake(time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC))
+		factsUC := usecase.NewFactsUseCase(mockRepo, now)
+
+		for i := 0; i <= cachedPools; i++ {
+			_, err := factsUC.GetFilteredRandomFacts(ctx, domain.FactFilter{Cuisine: domain.Cuisine(fmt.Sprint(i))}, 1)
+			assert.NoError(t, err)
+			now.Advance(time.Millisecond)
+		}
+		mockRepo.AssertNumberOfCalls(t, "GetFactsPool", cachedPools+1)
+
+		_, err := factsUC.GetFilteredRandomFacts(ctx, domain.FactFilter{Cuisine: "1"}, 1)
+		assert.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "GetFactsPool", cachedPools+1)
+
+		_, err = factsUC.GetFilteredRandomFacts(ctx, domain.FactFilter{Cuisine: "0"}, 1)
+		assert.NoError(t, err)
+		mockRepo.AssertNumberOfCalls(t, "GetFactsPool", cachedPools+2)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{RestaurantID: "r1"}
+		repoErr := errors.New("database error")
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(nil, repoErr)
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		facts, err := factsUC.GetFilteredRandomFacts(ctx, filter, 3)
+		assert.Equal(t, repoErr, err)
+		assert.Nil(t, facts)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetFactOfTheDay(t *testing.T) {
+	pool := []domain.Fact{
+		{ID: "1", RestaurantID: "r1", Content: "Факт 1", Locale: "ru", CreatedAt: testNow},
+		{ID: "2", RestaurantID: "r2", Content: "Факт 2", Locale: "ru", CreatedAt: testNow},
+	}
+
+	t.Run("fact is stable during the day", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{Locale: "ru"}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(pool, nil).Once()
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		first, err := factsUC.GetFactOfTheDay(ctx, "ru-RU")
+		assert.NoError(t, err)
+		assert.NotNil(t, first)
+
+		for i := 0; i < 3; i++ {
+			fact, err := factsUC.GetFactOfTheDay(ctx, "RU")
+			assert.NoError(t, err)
+			assert.Equal(t, first.ID, fact.ID)
+		}
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("next day features another fact", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{Locale: "ru"}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(pool, nil).Twice()
+
+		now := clock.NewFake(time.Date(2026, time.March, 1, 23, 0, 0, 0, time.UTC))
+		factsUC := usecase.NewFactsUseCase(mockRepo, now)
+
+		first, err := factsUC.GetFactOfTheDay(ctx, "ru")
+		assert.NoError(t, err)
+
+		now.Advance(2 * time.Hour)
+		next, err := factsUC.GetFactOfTheDay(ctx, "ru")
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.ID, next.ID, "the previous day's fact is not repeated")
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("default locale", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{Locale: domain.DefaultFactLocale}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return(pool[:1], nil).Once()
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		fact, err := factsUC.GetFactOfTheDay(ctx, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", fact.ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no facts", func(t *testing.T) {
+		ctx := newTestContext()
+		mockRepo := new(MockRestaurantRepository)
+		filter := domain.FactFilter{Locale: "de"}
+
+		mockRepo.On("GetFactsPool", ctx, filter, mock.AnythingOfType("int")).Return([]domain.Fact{}, nil).Once()
+
+		factsUC := usecase.NewFactsUseCase(mockRepo, newTestClock())
+
+		fact, err := factsUC.GetFactOfTheDay(ctx, "de")
+		assert.ErrorIs(t, err, usecase.ErrNoFactsAvailable)
+		assert.Nil(t, fact)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
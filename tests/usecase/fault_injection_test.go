@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectionUseCase_RouteFaults(t *testing.T) {
+	ctx := adminContext()
+	useCase := usecase.NewFaultInjectionUseCase(true, idgen.NewSequence(), newTestClock())
+
+	fault := &domain.RouteFault{Method: "get", Path: "/api/v1/restaurants/:id", Latency: 200 * time.Millisecond, ErrorRate: 0.5}
+	require.NoError(t, useCase.AddRouteFault(ctx, fault))
+	assert.Equal(t, "00000000-0000-7000-8000-000000000001", fault.ID)
+	assert.Equal(t, http.MethodGet, fault.Method)
+	assert.Equal(t, http.StatusServiceUnavailable, fault.ErrorStatus, "503 by default")
+
+	require.NoError(t, useCase.AddRouteFault(ctx, &domain.RouteFault{Path: "/api/v1/bookings", ErrorRate: 1, ErrorStatus: http.StatusTooManyRequests}))
+	faults := useCase.RouteFaults()
+	require.Len(t, faults, 2)
+	assert.Equal(t, fault.ID, faults[0].ID)
+
+	require.NoError(t, useCase.DeleteRouteFault(ctx, fault.ID))
+	assert.Len(t, useCase.RouteFaults(), 1)
+	assert.Len(t, faults, 2, "faults handed out are not changed by later changes")
+	assert.ErrorIs(t, useCase.DeleteRouteFault(ctx, fault.ID), usecase.ErrRouteFaultNotFound)
+
+	require.NoError(t, useCase.ClearFaults(ctx))
+	assert.Empty(t, useCase.RouteFaults())
+}
+
+func TestFaultInjectionUseCase_InvalidRouteFault(t *testing.T) {
+	ctx := adminContext()
+	useCase := usecase.NewFaultInjectionUseCase(true, idgen.NewSequence(), newTestClock())
+
+	faults := []domain.RouteFault{
+		{Path: "api/v1/bookings", ErrorRate: 1},
+		{Path: "/api/v1/bookings"},
+		{Path: "/api/v1/bookings", ErrorRate: 1.5},
+		{Path: "/api/v1/bookings", Latency: 2 * time.Hour},
+		{Path: "/api/v1/bookings", ErrorRate: 1, ErrorStatus: http.StatusOK},
+	}
+	for _, fault := range faults {
+		assert.ErrorIs(t, useCase.AddRouteFault(ctx, &fault), usecase.ErrInvalidRouteFault, fault)
+	}
+	assert.Empty(t, useCase.RouteFaults())
+}
+
+func TestFaultInjectionUseCase_DBFault(t *testing.T) {
+	ctx := adminContext()
+	useCase := usecase.NewFaultInjectionUseCase(true, idgen.NewSequence(), newTestClock())
+
+	require.NoError(t, useCase.DBFault())
+
+	require.NoError(t, useCase.SetDBFailureRate(ctx, 1))
+	assert.ErrorIs(t, useCase.DBFault(), usecase.ErrInjectedDBFault)
+	assert.ErrorIs(t, useCase.SetDBFailureRate(ctx, -0.1), usecase.ErrInvalidFailureRate)
+
+	faults, err := useCase.GetFaults(ctx)
+	require.NoError(t, err)
+	assert.True(t, faults.Enabled)
+	assert.Equal(t, 1.0, faults.DBFailureRate)
+
+	require.NoError(t, useCase.SetDBFailureRate(ctx, 0))
+	assert.NoError(t, useCase.DBFault())
+}
+
+func TestFaultInjectionUseCase_Disabled(t *testing.T) {
+	ctx := adminContext()
+	useCase := usecase.NewFaultInjectionUseCase(false, idgen.NewSequence(), newTestClock())
+
+	assert.ErrorIs(t, useCase.AddRouteFault(ctx, &domain.RouteFault{Path: "/api/v1/bookings", ErrorRate: 1}), usecase.ErrFaultInjectionDisabled)
+	assert.ErrorIs(t, useCase.SetDBFailureRate(ctx, 1), usecase.ErrFaultInjectionDisabled)
+	assert.Empty(t, useCase.RouteFaults())
+	assert.NoError(t, useCase.DBFault())
+
+	faults, err := useCase.GetFaults(ctx)
+	require.NoError(t, err)
+	assert.False(t, faults.Enabled)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err = useCase.GetFaults(staffCtx)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	assert.ErrorIs(t, usecase.NewFaultInjectionUseCase(true, idgen.NewSequence(), newTestClock()).ClearFaults(staffCtx), tenant.ErrAccessDenied)
+}
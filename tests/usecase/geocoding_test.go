@@ -0,0 +1,198 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/geo"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRestaurantLocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockRestaurantLocationRepository) Enqueue(ctx context.Context, restaurantID, address string) error {
+	args := m.Called(ctx, restaurantID, address)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantLocationRepository) GetByRestaurant(ctx context.Context, restaurantID string) (*domain.RestaurantLocation, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantLocation), args.Error(1)
+}
+
+func (m *MockRestaurantLocationRepository) ClaimPending(ctx context.Context, limit int, staleBefore time.Time) ([]*domain.RestaurantLocation, error) {
+	args := m.Called(ctx, limit, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantLocation), args.Error(1)
+}
+
+func (m *MockRestaurantLocationRepository) Save(ctx context.Context, location *domain.RestaurantLocation) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantLocationRepository) ListCities(ctx context.Context, countryCode string) ([]*domain.City, error) {
+	args := m.Called(ctx, countryCode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.City), args.Error(1)
+}
+
+// stubGeocoder places the addresses it knows and fails the others with their error.
+type stubGeocoder struct {
+	addresses map[string]*geo.Address
+	errs      map[string]error
+}
+
+func (g *stubGeocoder) Geocode(_ context.Context, address string) (*geo.Address, error) {
+	if geocoded, ok := g.addresses[address]; ok {
+		return geocoded, nil
+	}
+	if err, ok := g.errs[address]; ok {
+		return nil, err
+	}
+	return nil, geo.ErrAddressNotFound
+}
+
+func (g *stubGeocoder) Provider() string {
+	return "stub"
+}
+
+func TestGeocodingUseCase_GeocodePending(t *testing.T) {
+	ctx := newTestContext()
+	locationRepo := new(MockRestaurantLocationRepository)
+	notifier := new(MockNotificationService)
+	geocoder := &stubGeocoder{
+		addresses: map[string]*geo.Address{
+			"Tverskaya 1": {Street: "Tverskaya Street", HouseNumber: "1", City: "Moscow", CountryCode: "RU", Latitude: 55.75, Longitude: 37.61},
+		},
+		errs: map[string]error{
+			"Arbat 10":  errors.New("provider unavailable"),
+			"Nevsky 20": errors.New("provider unavailable"),
+		},
+	}
+	useCase := usecase.NewGeocodingUseCase(locationRepo, geocoder, notifier, newTestClock())
+
+	found := &domain.RestaurantLocation{RestaurantID: "r1", Address: "Tverskaya 1", Status: domain.GeocodingProcessing}
+	unknown := &domain.RestaurantLocation{RestaurantID: "r2", Address: "Nowhere 0", Status: domain.GeocodingProcessing}
+	retried := &domain.RestaurantLocation{RestaurantID: "r3", Address: "Arbat 10", Status: domain.GeocodingProcessing}
+	exhausted := &domain.RestaurantLocation{RestaurantID: "r4", Address: "Nevsky 20", Status: domain.GeocodingProcessing, Attempts: 2}
+	superseded := &domain.RestaurantLocation{RestaurantID: "r5", Address: "Old street 5", Status: domain.GeocodingProcessing}
+
+	locationRepo.On("ClaimPending", ctx, 10, mock.Anything).
+		Return([]*domain.RestaurantLocation{found, unknown, retried, exhausted, superseded}, nil)
+	locationRepo.On("Save", ctx, superseded).Return(errors.New(common.ErrRestaurantLocationNotFound))
+	locationRepo.On("Save", ctx, mock.Anything).Return(nil)
+	notifier.On("NotifyRestaurant", ctx, mock.Anything, domain.NotificationTypeGeocodingFailed, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	handled, err := useCase.GeocodePending(ctx, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, handled)
+
+	assert.Equal(t, domain.GeocodingDone, found.Status)
+	assert.Equal(t, "Moscow", found.City)
+	assert.Equal(t, "Tverskaya Street", found.Street)
+	assert.InDelta(t, 55.75, found.Latitude, 1e-9)
+	assert.Equal(t, "stub", found.Provider)
+	assert.NotNil(t, found.GeocodedAt)
+
+	assert.Equal(t, domain.GeocodingFailed, unknown.Status)
+	assert.Equal(t, geo.ErrAddressNotFound.Error(), unknown.Error)
+
+	assert.Equal(t, domain.GeocodingPending, retried.Status)
+	assert.Equal(t, 1, retried.Attempts)
+
+	assert.Equal(t, domain.GeocodingFailed, exhausted.Status)
+	assert.Equal(t, 3, exhausted.Attempts)
+
+	notifier.AssertNumberOfCalls(t, "NotifyRestaurant", 2)
+	notifier.AssertCalled(t, "NotifyRestaurant", ctx, "r2", domain.NotificationTypeGeocodingFailed, mock.Anything, mock.Anything, "r2")
+	notifier.AssertCalled(t, "NotifyRestaurant", ctx, "r4", domain.NotificationTypeGeocodingFailed, mock.Anything, mock.Anything, "r4")
+}
+
+func TestGeocodingUseCase_GetRestaurantLocationAccess(t *testing.T) {
+	locationRepo := new(MockRestaurantLocationRepository)
+	useCase := usecase.NewGeocodingUseCase(locationRepo, &stubGeocoder{}, new(MockNotificationService), newTestClock())
+
+	guestCtx := tenant.NewContext(newTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+
+	_, err := useCase.GetRestaurantLocation(guestCtx, "r1")
+
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	locationRepo.AssertNotCalled(t, "GetByRestaurant", mock.Anything, mock.Anything)
+}
+
+func TestGeocodingUseCase_ListCities(t *testing.T) {
+	ctx := newTestContext()
+	locationRepo := new(MockRestaurantLocationRepository)
+	useCase := usecase.NewGeocodingUseCase(locationRepo, &stubGeocoder{}, new(MockNotificationService), newTestClock())
+
+	cities := []*domain.City{{ID: "city-1", Name: "Moscow", CountryCode: "RU", RestaurantCount: 2}}
+	locationRepo.On("ListCities", ctx, "RU").Return(cities, nil).Once()
+	locationRepo.On("ListCities", ctx, "").Return(cities, nil).Once()
+
+	result, err := useCase.ListCities(ctx, " ru ")
+	require.NoError(t, err)
+	assert.Equal(t, cities, result)
+
+	_, err = useCase.ListCities(ctx, "")
+	require.NoError(t, err)
+
+	for _, code := range []string{"RUS", "R", "R1"} {
+		_, err = useCase.ListCities(ctx, code)
+		assert.ErrorIs(t, err, usecase.ErrInvalidCountryCode, code)
+	}
+	locationRepo.AssertExpectations(t)
+}
+
+func TestGeocodedRestaurantUseCase(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	locationRepo := new(MockRestaurantLocationRepository)
+
+	geocoding := usecase.NewGeocodingUseCase(locationRepo, &stubGeocoder{}, new(MockNotificationService), newTestClock())
+	useCase := usecase.NewGeocodedRestaurantUseCase(
+		usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock()), geocoding)
+
+	restaurant := createTestRestaurant()
+
+	t.Run("update queues the address", func(t *testing.T) {
+		mockRestaurantRepo.On("Update", ctx, restaurant).Return(nil).Once()
+		locationRepo.On("Enqueue", ctx, restaurant.ID, restaurant.Address).Return(nil).Once()
+
+		require.NoError(t, useCase.UpdateRestaurant(ctx, restaurant))
+		locationRepo.AssertExpectations(t)
+	})
+
+	t.Run("failed queueing does not fail the update", func(t *testing.T) {
+		mockRestaurantRepo.On("Update", ctx, restaurant).Return(nil).Once()
+		locationRepo.On("Enqueue", ctx, restaurant.ID, restaurant.Address).Return(errors.New("connection refused")).Once()
+
+		assert.NoError(t, useCase.UpdateRestaurant(ctx, restaurant))
+	})
+
+	t.Run("failed update queues nothing", func(t *testing.T) {
+		mockRestaurantRepo.On("Update", ctx, restaurant).Return(errors.New(common.ErrRestaurantNotFound)).Once()
+
+		assert.Error(t, useCase.UpdateRestaurant(ctx, restaurant))
+		locationRepo.AssertNumberOfCalls(t, "Enqueue", 2)
+	})
+}
@@ -0,0 +1,335 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/imaging"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) Create(ctx context.Context, image *domain.RestaurantImage) error {
+	args := m.Called(ctx, image)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) GetByID(ctx context.Context, id string) (*domain.RestaurantImage, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageRepository) ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.RestaurantImage, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantImage), args.Error(1)
+}
+
+func (m *MockImageRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) GetVariants(ctx context.Context, imageID string) ([]domain.RestaurantImageVariant, error) {
+	args := m.Called(ctx, imageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RestaurantImageVariant), args.Error(1)
+}
+
+func (m *MockImageRepository) GetVariantData(ctx context.Context, imageID, contentType string) ([]byte, error) {
+	args := m.Called(ctx, imageID, contentType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockImageRepository) ClaimPendingVariants(ctx context.Context, limit int, staleBefore time.Time) ([]domain.RestaurantImageVariant, error) {
+	args := m.Called(ctx, limit, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RestaurantImageVariant), args.Error(1)
+}
+
+func (m *MockImageRepository) SaveVariant(ctx context.Context, variant *domain.RestaurantImageVariant) error {
+	args := m.Called(ctx, variant)
+	return args.Error(0)
+}
+
+func encodedPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func encodedNoiseJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	rand.New(rand.NewSource(1)).Read(img.Pix)
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}))
+	return buf.Bytes()
+}
+
+func newImageUseCase(t *testing.T, variantFormats ...string) (usecase.ImageUseCase, *MockImageRepository, *MockRestaurantRepository, *imaging.DiskCache) {
+	t.Helper()
+
+	cache, err := imaging.NewDiskCache(t.TempDir(), 1<<20)
+	require.NoError(t, err)
+
+	imageRepo := new(MockImageRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	return usecase.NewImageUseCase(imageRepo, restaurantRepo, cache, 1000, variantFormats, newTestClock()), imageRepo, restaurantRepo, cache
+}
+
+func TestUploadImage(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("stores the dimensions", func(t *testing.T) {
+		images, imageRepo, restaurantRepo, _ := newImageUseCase(t)
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+		imageRepo.On("Create", ctx, mock.MatchedBy(func(image *domain.RestaurantImage) bool {
+			return image.RestaurantID == "restaurant1" && image.ContentType == "image/png" && image.Width == 30 && image.Height == 20
+		})).Return(nil)
+
+		_, err := images.UploadImage(ctx, "restaurant1", encodedPNG(t, 30, 20))
+
+		require.NoError(t, err)
+		imageRepo.AssertExpectations(t)
+	})
+
+	t.Run("queues the variant formats it can encode", func(t *testing.T) {
+		images, imageRepo, restaurantRepo, _ := newImageUseCase(t, imaging.ContentTypeWebP, imaging.ContentTypeAVIF, imaging.ContentTypePNG)
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+		imageRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+		img, err := images.UploadImage(ctx, "restaurant1", encodedPNG(t, 30, 20))
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.RestaurantImageVariant{
+			{ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantPending},
+		}, img.Variants)
+	})
+
+	t.Run("rejects what is not an image", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t)
+
+		_, err := images.UploadImage(ctx, "restaurant1", []byte("<svg/>"))
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidImage)
+		imageRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("only for staff of the restaurant", func(t *testing.T) {
+		images, _, _, _ := newImageUseCase(t)
+		guestCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+
+		_, err := images.UploadImage(guestCtx, "restaurant1", encodedPNG(t, 30, 20))
+
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	})
+}
+
+func TestGetImageVariant(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("resizes once and then serves from the cache", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t)
+		imageRepo.On("GetByID", ctx, "image1").Return(&domain.RestaurantImage{
+			ID:          "image1",
+			ContentType: "image/png",
+			Data:        encodedPNG(t, 300, 200),
+		}, nil).Once()
+
+		opts := imaging.Options{Width: 30, Fit: imaging.FitContain}
+		first, err := images.GetImageVariant(ctx, "image1", opts, nil)
+		require.NoError(t, err)
+		second, err := images.GetImageVariant(ctx, "image1", opts, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "image/png", second.ContentType)
+		assert.Equal(t, first.Data, second.Data)
+		config, err := png.DecodeConfig(bytes.NewReader(second.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 30, config.Width)
+		assert.Equal(t, 20, config.Height)
+		imageRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects sizes above the limit", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t)
+
+		_, err := images.GetImageVariant(ctx, "image1", imaging.Options{Width: 1001}, nil)
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidImageSize)
+		imageRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t)
+		imageRepo.On("GetByID", ctx, "missing").Return(nil, errors.New(common.ErrImageNotFound))
+
+		_, err := images.GetImageVariant(ctx, "missing", imaging.Options{Width: 10}, nil)
+
+		assert.EqualError(t, err, common.ErrImageNotFound)
+	})
+}
+
+func TestGetImageVariant_AcceptedFormat(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("serves the ready variant the client accepts", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t, imaging.ContentTypeWebP)
+		imageRepo.On("GetVariants", ctx, "image1").Return([]domain.RestaurantImageVariant{
+			{ImageID: "image1", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantReady},
+		}, nil)
+		imageRepo.On("GetVariantData", ctx, "image1", imaging.ContentTypeWebP).Return([]byte("webp bytes"), nil).Once()
+
+		opts := imaging.Options{Fit: imaging.FitContain}
+		accepted := []string{imaging.ContentTypeAVIF, imaging.ContentTypeWebP}
+		first, err := images.GetImageVariant(ctx, "image1", opts, accepted)
+		require.NoError(t, err)
+		second, err := images.GetImageVariant(ctx, "image1", opts, accepted)
+		require.NoError(t, err)
+
+		assert.Equal(t, "image1_0x0_contain_webp", first.Key)
+		assert.Equal(t, imaging.ContentTypeWebP, second.ContentType)
+		assert.Equal(t, []byte("webp bytes"), second.Data)
+		imageRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+		imageRepo.AssertExpectations(t)
+	})
+
+	t.Run("resizes into the accepted format", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t, imaging.ContentTypeWebP)
+		imageRepo.On("GetVariants", ctx, "image1").Return([]domain.RestaurantImageVariant{
+			{ImageID: "image1", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantReady},
+		}, nil)
+		imageRepo.On("GetByID", ctx, "image1").Return(&domain.RestaurantImage{
+			ID:          "image1",
+			ContentType: "image/png",
+			Data:        encodedPNG(t, 300, 200),
+		}, nil)
+
+		variant, err := images.GetImageVariant(ctx, "image1", imaging.Options{Width: 30}, []string{imaging.ContentTypeWebP})
+
+		require.NoError(t, err)
+		assert.Equal(t, imaging.ContentTypeWebP, variant.ContentType)
+		assert.Equal(t, "RIFF", string(variant.Data[:4]))
+	})
+
+	t.Run("falls back to the original format until the variant is ready", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t, imaging.ContentTypeWebP)
+		imageRepo.On("GetVariants", ctx, "image1").Return([]domain.RestaurantImageVariant{
+			{ImageID: "image1", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantPending},
+		}, nil)
+		imageRepo.On("GetByID", ctx, "image1").Return(&domain.RestaurantImage{
+			ID:          "image1",
+			ContentType: "image/png",
+			Data:        []byte("png bytes"),
+		}, nil)
+
+		variant, err := images.GetImageVariant(ctx, "image1", imaging.Options{}, []string{imaging.ContentTypeWebP})
+
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", variant.ContentType)
+		assert.Equal(t, "image1_0x0_contain", variant.Key)
+		imageRepo.AssertNotCalled(t, "GetVariantData", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGenerateVariants(t *testing.T) {
+	ctx := newTestContext()
+
+	images, imageRepo, _, _ := newImageUseCase(t, imaging.ContentTypeWebP)
+	imageRepo.On("ClaimPendingVariants", ctx, 10, mock.Anything).Return([]domain.RestaurantImageVariant{
+		{ImageID: "flat", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantProcessing},
+		{ImageID: "noisy", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantProcessing},
+		{ImageID: "broken", ContentType: imaging.ContentTypeWebP, Status: domain.ImageVariantProcessing},
+	}, nil)
+
+	// Lossless WebP easily beats PNG on a flat image, but not JPEG on a noisy photo.
+	imageRepo.On("GetByID", ctx, "flat").Return(&domain.RestaurantImage{
+		ID: "flat", Width: 300, Height: 200, Data: encodedPNG(t, 300, 200),
+	}, nil)
+	imageRepo.On("GetByID", ctx, "noisy").Return(&domain.RestaurantImage{
+		ID: "noisy", Width: 64, Height: 64, Data: encodedNoiseJPEG(t, 64, 64),
+	}, nil)
+	imageRepo.On("GetByID", ctx, "broken").Return(&domain.RestaurantImage{ID: "broken", Data: []byte("not an image")}, nil)
+
+	saved := make(map[string]domain.RestaurantImageVariant)
+	imageRepo.On("SaveVariant", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		variant := args.Get(1).(*domain.RestaurantImageVariant)
+		saved[variant.ImageID] = *variant
+	}).Return(nil)
+
+	generated, err := images.GenerateVariants(ctx, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, generated)
+	assert.Equal(t, domain.ImageVariantReady, saved["flat"].Status)
+	assert.Equal(t, 300, saved["flat"].Width)
+	assert.Equal(t, "RIFF", string(saved["flat"].Data[:4]))
+	assert.Equal(t, domain.ImageVariantDiscarded, saved["noisy"].Status)
+	assert.Nil(t, saved["noisy"].Data)
+	assert.Equal(t, domain.ImageVariantFailed, saved["broken"].Status)
+	assert.NotEmpty(t, saved["broken"].Error)
+	imageRepo.AssertNumberOfCalls(t, "SaveVariant", 3)
+}
+
+func TestDeleteImage(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("drops the cached variants", func(t *testing.T) {
+		images, imageRepo, _, cache := newImageUseCase(t)
+		require.NoError(t, cache.Put("image1_30x0_contain", []byte("variant")))
+		imageRepo.On("GetByID", ctx, "image1").Return(&domain.RestaurantImage{ID: "image1", RestaurantID: "restaurant1"}, nil)
+		imageRepo.On("Delete", ctx, "image1").Return(nil)
+
+		require.NoError(t, images.DeleteImage(ctx, "restaurant1", "image1"))
+
+		_, ok := cache.Get("image1_30x0_contain")
+		assert.False(t, ok)
+	})
+
+	t.Run("image of another restaurant", func(t *testing.T) {
+		images, imageRepo, _, _ := newImageUseCase(t)
+		imageRepo.On("GetByID", ctx, "image1").Return(&domain.RestaurantImage{ID: "image1", RestaurantID: "restaurant2"}, nil)
+
+		err := images.DeleteImage(ctx, "restaurant1", "image1")
+
+		assert.EqualError(t, err, common.ErrImageNotFound)
+		imageRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
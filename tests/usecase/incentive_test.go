@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockIncentiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockIncentiveRepository) ListRules(ctx context.Context, enabledOnly bool) ([]*domain.IncentiveRule, error) {
+	args := m.Called(ctx, enabledOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IncentiveRule), args.Error(1)
+}
+
+func (m *MockIncentiveRepository) GetRule(ctx context.Context, id string) (*domain.IncentiveRule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncentiveRule), args.Error(1)
+}
+
+func (m *MockIncentiveRepository) CreateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveRepository) UpdateRule(ctx context.Context, rule *domain.IncentiveRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveRepository) DeleteRule(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveRepository) CountPreviousBookings(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockIncentiveRepository) CreateEvaluation(ctx context.Context, evaluation *domain.IncentiveEvaluation) error {
+	args := m.Called(ctx, evaluation)
+	return args.Error(0)
+}
+
+func (m *MockIncentiveRepository) GetEvaluation(ctx context.Context, bookingID string) (*domain.IncentiveEvaluation, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.IncentiveEvaluation), args.Error(1)
+}
+
+func (m *MockIncentiveRepository) ListEvaluations(ctx context.Context, userID string, offset, limit int) ([]*domain.IncentiveEvaluation, error) {
+	args := m.Called(ctx, userID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.IncentiveEvaluation), args.Error(1)
+}
+
+func TestCreateIncentiveRule_Validation(t *testing.T) {
+	ctx := adminContext()
+	incentiveRepo := new(MockIncentiveRepository)
+	incentiveRepo.On("CreateRule", mock.Anything, mock.Anything).Return(nil)
+	incentives := usecase.NewIncentiveUseCase(incentiveRepo, new(MockBookingRepository))
+
+	invalid := []*domain.IncentiveRule{
+		{Name: " ", Effect: domain.IncentiveWaiveDeposit},
+		{Name: "double points", Effect: "double_points"},
+		{Name: "weekday points", Effect: domain.IncentiveBonusPoints},
+		{Name: "free deposit", Effect: domain.IncentiveWaiveDeposit, Points: 10},
+		{Name: "weekend", Effect: domain.IncentiveWaiveDeposit, Conditions: domain.IncentiveConditions{Weekdays: []time.Weekday{7}}},
+	}
+	for _, rule := range invalid {
+		assert.ErrorIs(t, incentives.CreateRule(ctx, rule), usecase.ErrInvalidIncentiveRule, rule.Name)
+	}
+
+	rule := &domain.IncentiveRule{Name: " First booking ", Effect: domain.IncentiveWaiveDeposit, Conditions: domain.IncentiveConditions{FirstBooking: true}}
+	require.NoError(t, incentives.CreateRule(ctx, rule))
+	assert.Equal(t, "First booking", rule.Name)
+	incentiveRepo.AssertNumberOfCalls(t, "CreateRule", 1)
+}
+
+func TestIncentiveBookingUseCase(t *testing.T) {
+	ctx := newTestContext()
+	incentiveRepo := new(MockIncentiveRepository)
+	incentiveRepo.On("ListRules", mock.Anything, true).Return([]*domain.IncentiveRule{
+		{ID: "first", Name: "First booking", Effect: domain.IncentiveWaiveDeposit, Conditions: domain.IncentiveConditions{FirstBooking: true}},
+	}, nil)
+	incentiveRepo.On("CountPreviousBookings", mock.Anything, "u1").Return(0, nil)
+	incentiveRepo.On("CreateEvaluation", mock.Anything, mock.Anything).Return(nil)
+
+	bookings := new(stubBookingUseCase)
+	booking := &domain.Booking{UserID: "u1", RestaurantID: "r1", Date: testNow.AddDate(0, 0, 3), GuestsCount: 2}
+	bookings.On("CreateBooking", ctx, booking).Return("booking1", nil)
+
+	incentives := usecase.NewIncentiveUseCase(incentiveRepo, new(MockBookingRepository))
+	evaluated := usecase.NewIncentiveBookingUseCase(bookings, incentives, newTestClock())
+
+	id, err := evaluated.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+	assert.Equal(t, "booking1", id)
+	incentiveRepo.AssertCalled(t, "CreateEvaluation", mock.Anything, mock.MatchedBy(func(evaluation *domain.IncentiveEvaluation) bool {
+		return evaluation.BookingID == "booking1" && evaluation.WaiveDeposit && len(evaluation.Results) == 1
+	}))
+
+	incentiveRepo.On("CountPreviousBookings", mock.Anything, "u2").Return(0, errors.New("connection reset"))
+	unevaluated := &domain.Booking{UserID: "u2", RestaurantID: "r1"}
+	bookings.On("CreateBooking", ctx, unevaluated).Return("booking2", nil)
+	id, err = evaluated.CreateBooking(ctx, unevaluated)
+	require.NoError(t, err, "a failed evaluation does not fail the booking")
+	assert.Equal(t, "booking2", id)
+	incentiveRepo.AssertNumberOfCalls(t, "CreateEvaluation", 1)
+
+	testBooking := &domain.Booking{UserID: "u1", RestaurantID: "r1", IsTest: true}
+	bookings.On("CreateBooking", ctx, testBooking).Return("test1", nil)
+	_, err = evaluated.CreateBooking(ctx, testBooking)
+	require.NoError(t, err)
+	incentiveRepo.AssertNumberOfCalls(t, "CountPreviousBookings", 2)
+}
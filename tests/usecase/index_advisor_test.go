@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockQueryStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockQueryStatsRepository) ListSeqScanTables(ctx context.Context, minRows int64, limit int) ([]domain.SeqScanTable, error) {
+	args := m.Called(ctx, minRows, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SeqScanTable), args.Error(1)
+}
+
+func (m *MockQueryStatsRepository) ListScanHeavyQueries(ctx context.Context, minCalls int64, limit int) ([]domain.ScanHeavyQuery, error) {
+	args := m.Called(ctx, minCalls, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ScanHeavyQuery), args.Error(1)
+}
+
+func TestIndexAdvisorUseCase_AdviseIndexes(t *testing.T) {
+	ctx := newTestContext()
+	now := time.Date(2026, 5, 10, 4, 0, 0, 0, time.UTC)
+	tables := []domain.SeqScanTable{{Name: "bookings", SeqScans: 900, SeqRowsRead: 4_500_000, IndexScans: 12, LiveRows: 50_000}}
+	queries := []domain.ScanHeavyQuery{{QueryID: 42, Query: "SELECT * FROM bookings WHERE comment = $1", Calls: 300, Rows: 3, Blocks: 90_000, BlocksPerRow: 30_000}}
+
+	repo := new(MockQueryStatsRepository)
+	repo.On("ListSeqScanTables", ctx, int64(usecase.DefaultIndexAdvisorMinRows), 5).Return(tables, nil)
+	repo.On("ListScanHeavyQueries", ctx, int64(usecase.DefaultIndexAdvisorMinCalls), 5).Return(queries, nil)
+	useCase := usecase.NewIndexAdvisorUseCase(repo, usecase.IndexAdvisorSettings{Limit: 5})
+
+	advice, err := useCase.AdviseIndexes(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, tables, advice.Tables)
+	assert.Equal(t, queries, advice.Queries)
+	assert.True(t, advice.StatementsAvailable)
+	assert.Equal(t, now, advice.CheckedAt)
+	repo.AssertExpectations(t)
+}
+
+func TestIndexAdvisorUseCase_AdviseIndexesWithoutStatements(t *testing.T) {
+	ctx := newTestContext()
+	settings := usecase.IndexAdvisorSettings{MinRows: 100, MinCalls: 10, Limit: 3}
+
+	repo := new(MockQueryStatsRepository)
+	repo.On("ListSeqScanTables", ctx, int64(100), 3).Return([]domain.SeqScanTable{{Name: "notifications"}}, nil)
+	repo.On("ListScanHeavyQueries", ctx, int64(10), 3).Return(nil, errors.New(common.ErrQueryStatsUnavailable))
+	useCase := usecase.NewIndexAdvisorUseCase(repo, settings)
+
+	advice, err := useCase.AdviseIndexes(ctx, testNow)
+
+	require.NoError(t, err, "the tables are still reported without pg_stat_statements")
+	assert.Len(t, advice.Tables, 1)
+	assert.Empty(t, advice.Queries)
+	assert.False(t, advice.StatementsAvailable)
+
+	failing := new(MockQueryStatsRepository)
+	failing.On("ListSeqScanTables", ctx, int64(100), 3).Return([]domain.SeqScanTable{}, nil)
+	failing.On("ListScanHeavyQueries", ctx, int64(10), 3).Return(nil, errors.New("connection reset"))
+
+	_, err = usecase.NewIndexAdvisorUseCase(failing, settings).AdviseIndexes(ctx, testNow)
+	assert.Error(t, err)
+}
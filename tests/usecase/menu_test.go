@@ -0,0 +1,375 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMenuRepository struct {
+	mock.Mock
+}
+
+func (m *MockMenuRepository) GetMenu(ctx context.Context, restaurantID string) ([]domain.MenuItem, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.MenuItem), args.Error(1)
+}
+
+func (m *MockMenuRepository) SaveMenu(ctx context.Context, restaurantID string, items []domain.MenuItem) error {
+	args := m.Called(ctx, restaurantID, items)
+	return args.Error(0)
+}
+
+func (m *MockMenuRepository) GetPreOrder(ctx context.Context, bookingID string) ([]domain.PreOrderItem, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.PreOrderItem), args.Error(1)
+}
+
+func (m *MockMenuRepository) SavePreOrder(ctx context.Context, bookingID string, items []domain.PreOrderItem) error {
+	args := m.Called(ctx, bookingID, items)
+	return args.Error(0)
+}
+
+var testMenu = []domain.MenuItem{
+	{ID: "item1", RestaurantID: "restaurant1", Name: "Borscht", Price: 45000, Currency: "RUB", IsAvailable: true},
+	{ID: "item2", RestaurantID: "restaurant1", Name: "Pelmeni", Price: 52000, Currency: "RUB", IsAvailable: true},
+	{ID: "item3", RestaurantID: "restaurant1", Name: "Kulebyaka", Price: 90000, Currency: "RUB", IsAvailable: false},
+}
+
+func bookingAt(start time.Time, status domain.BookingStatus) *domain.Booking {
+	year, month, day := start.Date()
+	return &domain.Booking{
+		ID:           "booking1",
+		RestaurantID: "restaurant1",
+		UserID:       "user1",
+		Date:         time.Date(year, month, day, 0, 0, 0, 0, start.Location()),
+		Time:         start.Format("15:04"),
+		Status:       status,
+	}
+}
+
+func TestUpdatePreOrder_SnapshotsMenuPrices(t *testing.T) {
+	ctx := newTestContext()
+	menuRepo := new(MockMenuRepository)
+	bookingRepo := new(MockBookingRepository)
+	menu := usecase.NewMenuUseCase(menuRepo, new(MockRestaurantRepository), bookingRepo, 3*time.Hour, newTestClock())
+
+	booking := bookingAt(testNow.Add(48*time.Hour), domain.BookingStatusConfirmed)
+	bookingRepo.On("GetByID", ctx, domain.BookingID("booking1")).Return(booking, nil)
+	menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+
+	expected := []domain.PreOrderItem{
+		{MenuItemID: "item1", Name: "Borscht", UnitPrice: 45000, Currency: "RUB", Quantity: 2, Notes: "no dill"},
+		{MenuItemID: "item2", Name: "Pelmeni", UnitPrice: 52000, Currency: "RUB", Quantity: 1},
+	}
+	menuRepo.On("SavePreOrder", ctx, "booking1", expected).Return(nil)
+
+	preOrder, err := menu.UpdatePreOrder(ctx, "booking1", []domain.PreOrderItem{
+		{MenuItemID: "item1", Quantity: 2, Notes: " no dill ", Name: "ignored", UnitPrice: 1},
+		{MenuItemID: "item2", Quantity: 1},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, expected, preOrder.Items)
+	assert.Equal(t, int64(142000), preOrder.EstimatedTotal)
+	assert.Equal(t, "RUB", preOrder.Currency)
+	assert.True(t, preOrder.Editable)
+	assert.Equal(t, testNow.Add(45*time.Hour), preOrder.EditableUntil)
+	menuRepo.AssertExpectations(t)
+}
+
+func TestUpdatePreOrder_RejectsInvalidItems(t *testing.T) {
+	ctx := newTestContext()
+
+	for name, item := range map[string]domain.PreOrderItem{
+		"unknown item":     {MenuItemID: "other", Quantity: 1},
+		"unavailable item": {MenuItemID: "item3", Quantity: 1},
+		"zero quantity":    {MenuItemID: "item1", Quantity: 0},
+		"huge quantity":    {MenuItemID: "item1", Quantity: 500},
+	} {
+		t.Run(name, func(t *testing.T) {
+			menuRepo := new(MockMenuRepository)
+			bookingRepo := new(MockBookingRepository)
+			menu := usecase.NewMenuUseCase(menuRepo, new(MockRestaurantRepository), bookingRepo, 3*time.Hour, newTestClock())
+
+			bookingRepo.On("GetByID", ctx, domain.BookingID("booking1")).Return(bookingAt(testNow.Add(48*time.Hour), domain.BookingStatusPending), nil)
+			menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+
+			_, err := menu.UpdatePreOrder(ctx, "booking1", []domain.PreOrderItem{item})
+
+			assert.ErrorIs(t, err, usecase.ErrInvalidPreOrder)
+			menuRepo.AssertNotCalled(t, "SavePreOrder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdatePreOrder_ClosedAfterCutoff(t *testing.T) {
+	ctx := newTestContext()
+
+	for name, booking := range map[string]*domain.Booking{
+		"within cutoff":     bookingAt(testNow.Add(2*time.Hour), domain.BookingStatusConfirmed),
+		"cancelled booking": bookingAt(testNow.Add(48*time.Hour), domain.BookingStatusCancelled),
+	} {
+		t.Run(name, func(t *testing.T) {
+			menuRepo := new(MockMenuRepository)
+			bookingRepo := new(MockBookingRepository)
+			menu := usecase.NewMenuUseCase(menuRepo, new(MockRestaurantRepository), bookingRepo, 3*time.Hour, newTestClock())
+
+			bookingRepo.On("GetByID", ctx, domain.BookingID("booking1")).Return(booking, nil)
+
+			_, err := menu.UpdatePreOrder(ctx, "booking1", nil)
+
+			assert.ErrorIs(t, err, usecase.ErrPreOrderClosed)
+			menuRepo.AssertNotCalled(t, "SavePreOrder", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetPreOrder(t *testing.T) {
+	ctx := newTestContext()
+	menuRepo := new(MockMenuRepository)
+	menu := usecase.NewMenuUseCase(menuRepo, new(MockRestaurantRepository), new(MockBookingRepository), 3*time.Hour, newTestClock())
+
+	booking := bookingAt(testNow.Add(time.Hour), domain.BookingStatusConfirmed)
+	menuRepo.On("GetPreOrder", ctx, "booking1").Return([]domain.PreOrderItem{
+		{MenuItemID: "item1", Name: "Borscht", UnitPrice: 45000, Quantity: 3},
+	}, nil)
+
+	preOrder, err := menu.GetPreOrder(ctx, booking)
+
+	require.NoError(t, err)
+	assert.Equal(t, "booking1", preOrder.BookingID)
+	assert.Equal(t, int64(135000), preOrder.EstimatedTotal)
+	assert.False(t, preOrder.Editable)
+}
+
+func TestGetMenu_Filter(t *testing.T) {
+	ctx := newTestContext()
+	menuRepo := new(MockMenuRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	menu := usecase.NewMenuUseCase(menuRepo, restaurantRepo, new(MockBookingRepository), 3*time.Hour, newTestClock())
+
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+	menuRepo.On("GetMenu", ctx, "restaurant1").Return([]domain.MenuItem{
+		{ID: "item1", Name: "Borscht", Allergens: []domain.Allergen{domain.AllergenCelery}, DietaryLabels: []domain.DietaryLabel{domain.DietaryVegan}},
+		{ID: "item2", Name: "Syrniki", Allergens: []domain.Allergen{domain.AllergenMilk, domain.AllergenEggs}, DietaryLabels: []domain.DietaryLabel{domain.DietaryVegetarian}},
+		{ID: "item3", Name: "Herring", Allergens: []domain.Allergen{domain.AllergenFish}},
+	}, nil)
+
+	items, err := menu.GetMenu(ctx, "restaurant1", domain.MenuFilter{DietaryLabels: []domain.DietaryLabel{domain.DietaryVegetarian}})
+	require.NoError(t, err)
+	require.Len(t, items, 2, "vegan items suit vegetarians")
+
+	items, err = menu.GetMenu(ctx, "restaurant1", domain.MenuFilter{ExcludeAllergens: []domain.Allergen{domain.AllergenMilk, domain.AllergenFish}})
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "item1", items[0].ID)
+
+	_, err = menu.GetMenu(ctx, "restaurant1", domain.MenuFilter{ExcludeAllergens: []domain.Allergen{"caffeine"}})
+	assert.ErrorIs(t, err, usecase.ErrInvalidMenuFilter)
+}
+
+func TestUpdateMenu(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("keeps known items and adds new ones", func(t *testing.T) {
+		menuRepo := new(MockMenuRepository)
+		restaurantRepo := new(MockRestaurantRepository)
+		menu := usecase.NewMenuUseCase(menuRepo, restaurantRepo, new(MockBookingRepository), 3*time.Hour, newTestClock())
+
+		items := []domain.MenuItem{
+			{ID: "item2", Name: "Pelmeni", Price: 55000, IsAvailable: true},
+			{Name: " Syrniki ", Price: 30000, IsAvailable: true},
+		}
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+		menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+		menuRepo.On("SaveMenu", ctx, "restaurant1", mock.MatchedBy(func(saved []domain.MenuItem) bool {
+			return len(saved) == 2 && saved[0].ID == "item2" && saved[1].Name == "Syrniki"
+		})).Return(nil)
+
+		_, err := menu.UpdateMenu(ctx, "restaurant1", items)
+
+		require.NoError(t, err)
+		menuRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid items", func(t *testing.T) {
+		for name, items := range map[string][]domain.MenuItem{
+			"no name":          {{Name: " ", Price: 100}},
+			"negative price":   {{Name: "Tea", Price: -1}},
+			"negative limit":   {{Name: "Tea", DailyLimit: -1}},
+			"foreign id":       {{ID: "other", Name: "Tea"}},
+			"duplicate id":     {{ID: "item1", Name: "Tea"}, {ID: "item1", Name: "Coffee"}},
+			"unknown allergen": {{Name: "Tea", Allergens: []domain.Allergen{"caffeine"}}},
+			"unknown label":    {{Name: "Tea", DietaryLabels: []domain.DietaryLabel{"paleo"}}},
+			"vegan with milk": {{Name: "Latte", Allergens: []domain.Allergen{domain.AllergenMilk},
+				DietaryLabels: []domain.DietaryLabel{domain.DietaryVegan}}},
+		} {
+			menuRepo := new(MockMenuRepository)
+			restaurantRepo := new(MockRestaurantRepository)
+			menu := usecase.NewMenuUseCase(menuRepo, restaurantRepo, new(MockBookingRepository), 3*time.Hour, newTestClock())
+
+			restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+			menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+
+			_, err := menu.UpdateMenu(ctx, "restaurant1", items)
+
+			assert.ErrorIs(t, err, usecase.ErrInvalidMenuItem, name)
+			menuRepo.AssertNotCalled(t, "SaveMenu", mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("orders tags by vocabulary", func(t *testing.T) {
+		menuRepo := new(MockMenuRepository)
+		restaurantRepo := new(MockRestaurantRepository)
+		menu := usecase.NewMenuUseCase(menuRepo, restaurantRepo, new(MockBookingRepository), 3*time.Hour, newTestClock())
+
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(&domain.Restaurant{ID: "restaurant1"}, nil)
+		menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+		menuRepo.On("SaveMenu", ctx, "restaurant1", []domain.MenuItem{{
+			Name:          "Pelmeni",
+			Allergens:     []domain.Allergen{domain.AllergenGluten, domain.AllergenEggs},
+			DietaryLabels: []domain.DietaryLabel{domain.DietaryHalal},
+		}}).Return(nil)
+
+		_, err := menu.UpdateMenu(ctx, "restaurant1", []domain.MenuItem{{
+			Name:          "Pelmeni",
+			Allergens:     []domain.Allergen{domain.AllergenEggs, domain.AllergenGluten, domain.AllergenEggs},
+			DietaryLabels: []domain.DietaryLabel{domain.DietaryHalal},
+		}})
+
+		require.NoError(t, err)
+		menuRepo.AssertExpectations(t)
+	})
+
+	t.Run("requires restaurant staff", func(t *testing.T) {
+		menu := usecase.NewMenuUseCase(new(MockMenuRepository), new(MockRestaurantRepository), new(MockBookingRepository), 3*time.Hour, newTestClock())
+		guestCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+
+		_, err := menu.UpdateMenu(guestCtx, "restaurant1", nil)
+
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	})
+}
+
+func TestImportMenu(t *testing.T) {
+	ctx := newTestContext()
+	restaurant := &domain.Restaurant{ID: "restaurant1", Currency: "RUB"}
+
+	newMenu := func() (usecase.MenuUseCase, *MockMenuRepository) {
+		menuRepo := new(MockMenuRepository)
+		restaurantRepo := new(MockRestaurantRepository)
+		restaurantRepo.On("GetByID", ctx, domain.RestaurantID("restaurant1")).Return(restaurant, nil)
+		menuRepo.On("GetMenu", ctx, "restaurant1").Return(testMenu, nil)
+		return usecase.NewMenuUseCase(menuRepo, restaurantRepo, new(MockBookingRepository), 3*time.Hour, newTestClock()), menuRepo
+	}
+
+	t.Run("reads point-of-sale CSV and merges by name", func(t *testing.T) {
+		menu, menuRepo := newMenu()
+		file := "Item Name;Menu Group;Price;Allergens;Active\n" +
+			"pelmeni;Mains;\"1 250,50 ₽\";Gluten, eggs;yes\n" +
+			"Vegan bowl;Mains;390;;no\n"
+
+		var saved []domain.MenuItem
+		menuRepo.On("SaveMenu", ctx, "restaurant1", mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(2).([]domain.MenuItem)
+		}).Return(nil)
+
+		report, err := menu.ImportMenu(ctx, "restaurant1", strings.NewReader(file), usecase.MenuImportOptions{Format: usecase.MenuImportCSV})
+
+		require.NoError(t, err)
+		assert.False(t, report.HasErrors())
+		assert.Equal(t, 2, report.Total)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		require.Len(t, saved, 4, "the items not in the file stay")
+		assert.Equal(t, "item1", saved[0].ID)
+		assert.Equal(t, "item2", saved[2].ID)
+		assert.Equal(t, int64(125050), saved[2].Price)
+		assert.Equal(t, "Mains", saved[2].Category)
+		assert.Equal(t, []domain.Allergen{domain.AllergenGluten, domain.AllergenEggs}, saved[2].Allergens)
+		assert.Equal(t, "Vegan bowl", saved[3].Name)
+		assert.False(t, saved[3].IsAvailable)
+	})
+
+	t.Run("replaces the menu with JSON items", func(t *testing.T) {
+		menu, menuRepo := newMenu()
+		file := `{"items":[{"name":"Borscht","price":47000,"dietary_labels":["vegetarian"]},{"name":"Kvass","price":15000,"is_available":false}]}`
+		menuRepo.On("SaveMenu", ctx, "restaurant1", mock.MatchedBy(func(saved []domain.MenuItem) bool {
+			return len(saved) == 2 && saved[0].ID == "item1" && saved[0].Price == 47000 && saved[1].ID == "" && !saved[1].IsAvailable
+		})).Return(nil)
+
+		report, err := menu.ImportMenu(ctx, "restaurant1", strings.NewReader(file),
+			usecase.MenuImportOptions{Format: usecase.MenuImportJSON, Replace: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 2, report.Removed)
+		menuRepo.AssertExpectations(t)
+	})
+
+	t.Run("reports every invalid row and saves nothing", func(t *testing.T) {
+		menu, menuRepo := newMenu()
+		file := "name,price,dietary_labels\n" +
+			"Tea,100,\n" +
+			",abc,\n" +
+			"Coffee,1.999,paleo\n" +
+			"tea,120,\n"
+
+		report, err := menu.ImportMenu(ctx, "restaurant1", strings.NewReader(file), usecase.MenuImportOptions{Format: usecase.MenuImportCSV})
+
+		require.NoError(t, err)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, 3, report.Errors[0].Row)
+		assert.Equal(t, []string{"price is not an amount", "name is required"}, report.Errors[0].Errors)
+		assert.Equal(t, 4, report.Errors[1].Row)
+		assert.Equal(t, 5, report.Errors[2].Row)
+		assert.Zero(t, report.Created)
+		menuRepo.AssertNotCalled(t, "SaveMenu", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run saves nothing", func(t *testing.T) {
+		menu, menuRepo := newMenu()
+
+		report, err := menu.ImportMenu(ctx, "restaurant1", strings.NewReader("name,price\nTea,100\n"),
+			usecase.MenuImportOptions{Format: usecase.MenuImportCSV, DryRun: true})
+
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 1, report.Created)
+		menuRepo.AssertNotCalled(t, "SaveMenu", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects unreadable files", func(t *testing.T) {
+		for name, file := range map[string]struct {
+			format usecase.MenuImportFormat
+			body   string
+		}{
+			"no price column": {usecase.MenuImportCSV, "name,category\nTea,Drinks\n"},
+			"broken JSON":     {usecase.MenuImportJSON, `[{"name":`},
+			"unknown format":  {"xml", "<menu/>"},
+		} {
+			menu, _ := newMenu()
+
+			_, err := menu.ImportMenu(ctx, "restaurant1", strings.NewReader(file.body), usecase.MenuImportOptions{Format: file.format})
+
+			assert.ErrorIs(t, err, usecase.ErrInvalidImportFile, name)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDeferredNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeferredNotificationRepository) Create(ctx context.Context, notification *domain.DeferredNotification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockDeferredNotificationRepository) Update(ctx context.Context, notification *domain.DeferredNotification) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockDeferredNotificationRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.DeferredNotification, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.DeferredNotification), args.Error(1)
+}
+
+func (m *MockDeferredNotificationRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// lunchQuietHours keep a channel quiet around testNow, until 13:00.
+func lunchQuietHours(channel domain.NotificationChannel) []domain.QuietHours {
+	return []domain.QuietHours{{Channel: channel, Start: "11:00", End: "13:00"}}
+}
+
+func newTestNotificationDeferral(
+	deferredRepo *MockDeferredNotificationRepository,
+	settingsRepo *MockNotificationSettingsRepository,
+	userRepo *MockUserRepository,
+	restaurantRepo *MockRestaurantRepository,
+	notifier *MockNotificationService,
+	emailService *MockEmailService,
+) usecase.NotificationDeferralUseCase {
+	return usecase.NewNotificationDeferralUseCase(deferredRepo, settingsRepo, userRepo, restaurantRepo,
+		notifier, emailService, new(MockSMSSender), newTestClock())
+}
+
+func TestDeferNotification(t *testing.T) {
+	ctx := newTestContext()
+	deferredRepo := new(MockDeferredNotificationRepository)
+	deferrals := newTestNotificationDeferral(deferredRepo, nil, nil, nil, nil, nil)
+
+	settings := &domain.NotificationSettings{
+		RecipientType: domain.RecipientTypeRestaurant,
+		RecipientID:   "rest-1",
+		QuietHours:    lunchQuietHours(domain.NotificationChannelEmail),
+	}
+	deferredRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	report := &domain.DeferredNotification{
+		NotificationType: domain.NotificationTypeWeeklyReport,
+		Channel:          domain.NotificationChannelEmail,
+		Title:            "Your week in review",
+	}
+	deferred, err := deferrals.Defer(ctx, settings, report)
+	require.NoError(t, err)
+	assert.True(t, deferred)
+	assert.Equal(t, domain.RecipientTypeRestaurant, report.RecipientType)
+	assert.Equal(t, "rest-1", report.RecipientID)
+	assert.Equal(t, time.Date(2025, time.June, 2, 13, 0, 0, 0, time.UTC), report.DeliverAt)
+
+	deferred, err = deferrals.Defer(ctx, settings, &domain.DeferredNotification{
+		NotificationType: domain.NotificationTypeBookingCancelled,
+		Channel:          domain.NotificationChannelEmail,
+	})
+	require.NoError(t, err)
+	assert.False(t, deferred, "booking changes bypass quiet hours")
+
+	deferredRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestDeliverDueDeferredNotifications(t *testing.T) {
+	ctx := newTestContext()
+	deferredRepo := new(MockDeferredNotificationRepository)
+	settingsRepo := new(MockNotificationSettingsRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	notifier := new(MockNotificationService)
+	emailService := new(MockEmailService)
+	deferrals := newTestNotificationDeferral(deferredRepo, settingsRepo, new(MockUserRepository), restaurantRepo, notifier, emailService)
+
+	inApp := &domain.DeferredNotification{
+		ID: "d1", RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest-1",
+		NotificationType: domain.NotificationTypeDailyDigest, Channel: domain.NotificationChannelInApp,
+		Title: "Your day ahead", Message: "m1", RelatedID: "rest-1",
+	}
+	email := &domain.DeferredNotification{
+		ID: "d2", RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest-2",
+		NotificationType: domain.NotificationTypeWeeklyReport, Channel: domain.NotificationChannelEmail,
+		Title: "Your week in review", Message: "m2",
+	}
+	turnedOff := &domain.DeferredNotification{
+		ID: "d3", RecipientType: domain.RecipientTypeUser, RecipientID: "user-1",
+		NotificationType: domain.NotificationTypeMarketing, Channel: domain.NotificationChannelEmail,
+		Title: "We miss you", Message: "m3",
+	}
+	stillQuiet := &domain.DeferredNotification{
+		ID: "d4", RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest-3",
+		NotificationType: domain.NotificationTypeDailyDigest, Channel: domain.NotificationChannelInApp,
+		Title: "Your day ahead", Message: "m4",
+	}
+
+	deferredRepo.On("ClaimDue", ctx, testNow, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		return leaseUntil.After(testNow)
+	}), 10).Return([]*domain.DeferredNotification{inApp, email, turnedOff, stillQuiet}, nil)
+	deferredRepo.On("Delete", ctx, mock.Anything).Return(nil)
+	deferredRepo.On("Update", ctx, stillQuiet).Return(nil)
+
+	settingsRepo.On("Get", ctx, domain.RecipientTypeRestaurant, "rest-1").Return(&domain.NotificationSettings{}, nil)
+	settingsRepo.On("Get", ctx, domain.RecipientTypeRestaurant, "rest-2").Return(&domain.NotificationSettings{}, nil)
+	settingsRepo.On("Get", ctx, domain.RecipientTypeUser, "user-1").Return(&domain.NotificationSettings{}, nil)
+	settingsRepo.On("Get", ctx, domain.RecipientTypeRestaurant, "rest-3").Return(&domain.NotificationSettings{
+		QuietHours: lunchQuietHours(domain.NotificationChannelInApp),
+	}, nil)
+
+	notifier.On("NotifyRestaurant", ctx, "rest-1", domain.NotificationTypeDailyDigest, "Your day ahead", "m1", "rest-1").Return(nil)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("rest-2")).Return(&domain.Restaurant{ID: "rest-2", ContactEmail: "bistro@example.com"}, nil)
+	emailService.On("SendEmail", "bistro@example.com", "Your week in review", "m2").Return(nil)
+
+	report, err := deferrals.DeliverDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.DeferredDeliveryReport{Claimed: 4, Delivered: 2, Deferred: 1, Dropped: 1}, report)
+	assert.Equal(t, time.Date(2025, time.June, 2, 13, 0, 0, 0, time.UTC), stillQuiet.DeliverAt)
+
+	notifier.AssertExpectations(t)
+	emailService.AssertExpectations(t)
+	deferredRepo.AssertCalled(t, "Delete", ctx, "d1")
+	deferredRepo.AssertCalled(t, "Delete", ctx, "d2")
+	deferredRepo.AssertCalled(t, "Delete", ctx, "d3")
+	deferredRepo.AssertNotCalled(t, "Delete", ctx, "d4")
+}
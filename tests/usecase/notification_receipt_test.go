@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryNotificationReceiptRepository keeps notifications in memory and guards GetByID like the
+// postgres repository does.
+type memoryNotificationReceiptRepository struct {
+	notifications map[string]*domain.Notification
+}
+
+func (r *memoryNotificationReceiptRepository) GetByID(ctx context.Context, id string) (*domain.Notification, error) {
+	notification, ok := r.notifications[id]
+	if !ok {
+		return nil, errors.New("notification not found")
+	}
+	if err := tenant.GuardUser(ctx, "notification", id, notification.RecipientID); err != nil {
+		return nil, err
+	}
+	stored := *notification
+	return &stored, nil
+}
+
+func (r *memoryNotificationReceiptRepository) GetByRelatedID(_ context.Context, relatedID string) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	for _, id := range []string{"n1", "n2", "n3"} {
+		if notification, ok := r.notifications[id]; ok && notification.RelatedID == relatedID {
+			notifications = append(notifications, *notification)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memoryNotificationReceiptRepository) MarkRead(_ context.Context, id string, readAt time.Time) error {
+	notification, ok := r.notifications[id]
+	if !ok {
+		return errors.New("notification not found")
+	}
+	notification.IsRead = true
+	if notification.ReadAt == nil {
+		notification.ReadAt = &readAt
+	}
+	return nil
+}
+
+func newMemoryNotificationReceiptRepository() *memoryNotificationReceiptRepository {
+	deliveredAt := testNow.Add(-time.Hour)
+	return &memoryNotificationReceiptRepository{notifications: map[string]*domain.Notification{
+		"n1": {ID: "n1", RecipientType: domain.RecipientTypeRestaurant, RecipientID: "restaurant1", Type: domain.NotificationTypeNewBooking, Channel: domain.NotificationChannelInApp, RelatedID: "booking1", DeliveredAt: &deliveredAt},
+		"n2": {ID: "n2", RecipientType: domain.RecipientTypeUser, RecipientID: "user1", Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelInApp, RelatedID: "booking1", DeliveredAt: &deliveredAt},
+		"n3": {ID: "n3", RecipientType: domain.RecipientTypeUser, RecipientID: "user1", Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelSMS, RelatedID: "booking1"},
+	}}
+}
+
+func TestGetBookingDeliveries(t *testing.T) {
+	repo := newMemoryNotificationReceiptRepository()
+	receipts := usecase.NewNotificationReceiptUseCase(repo, newTestClock())
+	booking := &domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1"}
+
+	staff := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "staff1", RestaurantIDs: []string{"restaurant1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	deliveries, err := receipts.GetBookingDeliveries(staff, booking)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3, "staff see the receipts of the guest and the restaurant")
+	assert.Equal(t, domain.DeliveryStatusDelivered, deliveries[1].Status)
+	assert.Equal(t, domain.DeliveryStatusPending, deliveries[2].Status)
+
+	guest := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+	deliveries, err = receipts.GetBookingDeliveries(guest, booking)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2, "the guest only sees their own receipts")
+	for _, delivery := range deliveries {
+		assert.Equal(t, domain.RecipientTypeUser, delivery.RecipientType)
+	}
+}
+
+func TestConfirmRead(t *testing.T) {
+	repo := newMemoryNotificationReceiptRepository()
+	receipts := usecase.NewNotificationReceiptUseCase(repo, newTestClock())
+
+	guest := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+	notification, err := receipts.ConfirmRead(guest, "n2")
+	require.NoError(t, err)
+	assert.True(t, notification.IsRead)
+	require.NotNil(t, notification.ReadAt)
+	firstRead := *notification.ReadAt
+
+	notification, err = receipts.ConfirmRead(guest, "n2")
+	require.NoError(t, err)
+	assert.Equal(t, firstRead, *notification.ReadAt, "the first read is kept")
+	assert.Equal(t, domain.DeliveryStatusRead, notification.DeliveryStatus())
+
+	other := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user2", Roles: []tenant.Role{tenant.RoleUser}})
+	_, err = receipts.ConfirmRead(other, "n3")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	assert.Nil(t, repo.notifications["n3"].ReadAt, "a foreign notification is not marked read")
+}
+
+func TestTrackRead(t *testing.T) {
+	repo := newMemoryNotificationReceiptRepository()
+	receipts := usecase.NewNotificationReceiptUseCase(repo, newTestClock())
+
+	require.NoError(t, receipts.TrackRead(setupTestContext(), "n2"))
+	assert.NotNil(t, repo.notifications["n2"].ReadAt)
+
+	assert.Error(t, receipts.TrackRead(setupTestContext(), "missing"))
+}
@@ -0,0 +1,169 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationFailureRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationFailureRepository) Create(ctx context.Context, failure *domain.NotificationFailure) error {
+	args := m.Called(ctx, failure)
+	return args.Error(0)
+}
+
+func (m *MockNotificationFailureRepository) Update(ctx context.Context, failure *domain.NotificationFailure) error {
+	args := m.Called(ctx, failure)
+	return args.Error(0)
+}
+
+func (m *MockNotificationFailureRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*domain.NotificationFailure, error) {
+	args := m.Called(ctx, now, leaseUntil, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationFailure), args.Error(1)
+}
+
+func (m *MockNotificationFailureRepository) List(ctx context.Context, status domain.NotificationFailureStatus, offset, limit int) ([]*domain.NotificationFailure, error) {
+	args := m.Called(ctx, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NotificationFailure), args.Error(1)
+}
+
+func (m *MockNotificationFailureRepository) CountByStatus(ctx context.Context) (map[domain.NotificationFailureStatus]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[domain.NotificationFailureStatus]int), args.Error(1)
+}
+
+var testNotificationRetryPolicy = usecase.NotificationRetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     time.Minute,
+	MaxBackoff:  90 * time.Second,
+}
+
+func TestRecordNotificationFailure(t *testing.T) {
+	ctx := setupTestContext()
+	repo := new(MockNotificationFailureRepository)
+	retry := usecase.NewNotificationRetryUseCase(repo, new(MockNotificationService), testNotificationRetryPolicy, newTestClock())
+
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	failure := &domain.NotificationFailure{
+		RecipientType:    domain.RecipientTypeUser,
+		RecipientID:      "user-1",
+		NotificationType: domain.NotificationTypeBookingConfirmed,
+		Title:            "Booking confirmed",
+	}
+	require.NoError(t, retry.RecordFailure(ctx, failure, errors.New("database error")))
+
+	assert.Equal(t, "database error", failure.Cause)
+	assert.Equal(t, 1, failure.Attempts)
+	assert.Equal(t, domain.NotificationFailureStatusPending, failure.Status)
+	require.NotNil(t, failure.NextAttemptAt)
+	assert.Equal(t, testNow.Add(time.Minute), *failure.NextAttemptAt)
+	repo.AssertExpectations(t)
+}
+
+func TestRetryDueNotifications(t *testing.T) {
+	ctx := setupTestContext()
+	repo := new(MockNotificationFailureRepository)
+	notifier := new(MockNotificationService)
+	retry := usecase.NewNotificationRetryUseCase(repo, notifier, testNotificationRetryPolicy, newTestClock())
+
+	delivered := &domain.NotificationFailure{
+		ID: "f1", RecipientType: domain.RecipientTypeRestaurant, RecipientID: "rest-1",
+		NotificationType: domain.NotificationTypeNewBooking, Title: "New booking", Message: "m1", RelatedID: "b1",
+		Attempts: 1, Status: domain.NotificationFailureStatusPending,
+	}
+	rescheduled := &domain.NotificationFailure{
+		ID: "f2", RecipientType: domain.RecipientTypeUser, RecipientID: "user-2",
+		NotificationType: domain.NotificationTypeBookingConfirmed, Title: "Booking confirmed", Message: "m2",
+		Attempts: 1, Status: domain.NotificationFailureStatusPending,
+	}
+	exhausted := &domain.NotificationFailure{
+		ID: "f3", RecipientType: domain.RecipientTypeUser, RecipientID: "user-3",
+		NotificationType: domain.NotificationTypeBookingRejected, Title: "Booking rejected", Message: "m3",
+		Attempts: 2, Status: domain.NotificationFailureStatusPending,
+	}
+
+	repo.On("ClaimDue", mock.Anything, testNow, mock.MatchedBy(func(leaseUntil time.Time) bool {
+		return leaseUntil.After(testNow)
+	}), 10).Return([]*domain.NotificationFailure{delivered, rescheduled, exhausted}, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	notifier.On("NotifyRestaurant", mock.Anything, "rest-1", domain.NotificationTypeNewBooking, "New booking", "m1", "b1").Return(nil)
+	notifier.On("NotifyUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("still failing"))
+
+	report, err := retry.RetryDue(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, usecase.NotificationRetryReport{Retried: 3, Delivered: 1, Exhausted: 1}, report)
+
+	assert.Equal(t, domain.NotificationFailureStatusDelivered, delivered.Status)
+	assert.Equal(t, 2, delivered.Attempts)
+	assert.Nil(t, delivered.NextAttemptAt)
+
+	assert.Equal(t, domain.NotificationFailureStatusPending, rescheduled.Status)
+	assert.Equal(t, 2, rescheduled.Attempts)
+	assert.Equal(t, "still failing", rescheduled.Cause)
+	require.NotNil(t, rescheduled.NextAttemptAt)
+	assert.Equal(t, testNow.Add(90*time.Second), *rescheduled.NextAttemptAt, "the doubled backoff is capped")
+
+	assert.Equal(t, domain.NotificationFailureStatusExhausted, exhausted.Status)
+	assert.Equal(t, 3, exhausted.Attempts)
+	assert.Nil(t, exhausted.NextAttemptAt)
+
+	repo.AssertNumberOfCalls(t, "Update", 3)
+}
+
+func TestListNotificationFailures(t *testing.T) {
+	repo := new(MockNotificationFailureRepository)
+	retry := usecase.NewNotificationRetryUseCase(repo, new(MockNotificationService), testNotificationRetryPolicy, newTestClock())
+
+	admin := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "admin", Roles: []tenant.Role{tenant.RoleAdmin}})
+	repo.On("List", mock.Anything, domain.NotificationFailureStatusExhausted, 0, 20).Return([]*domain.NotificationFailure{{ID: "f1"}}, nil)
+
+	failures, err := retry.ListFailures(admin, domain.NotificationFailureStatusExhausted, 0, 20)
+	require.NoError(t, err)
+	assert.Len(t, failures, 1)
+
+	_, err = retry.ListFailures(admin, "lost", 0, 20)
+	assert.ErrorIs(t, err, usecase.ErrInvalidNotificationFailureStatus)
+
+	user := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user-1"})
+	_, err = retry.ListFailures(user, "", 0, 20)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func TestCountNotificationFailures(t *testing.T) {
+	repo := new(MockNotificationFailureRepository)
+	retry := usecase.NewNotificationRetryUseCase(repo, new(MockNotificationService), testNotificationRetryPolicy, newTestClock())
+
+	repo.On("CountByStatus", mock.Anything).Return(map[domain.NotificationFailureStatus]int{
+		domain.NotificationFailureStatusPending: 4,
+	}, nil)
+
+	counts, err := retry.CountFailures(adminContext())
+	require.NoError(t, err)
+	assert.Equal(t, map[domain.NotificationFailureStatus]int{
+		domain.NotificationFailureStatusPending:   4,
+		domain.NotificationFailureStatusDelivered: 0,
+		domain.NotificationFailureStatusExhausted: 0,
+	}, counts)
+}
@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationSettingsRepository) Get(ctx context.Context, recipientType domain.RecipientType, recipientID string) (*domain.NotificationSettings, error) {
+	args := m.Called(ctx, recipientType, recipientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationSettingsRepository) Save(ctx context.Context, settings *domain.NotificationSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func TestGetNotificationSettings_FillsDefaults(t *testing.T) {
+	ctx := newTestContext()
+	settingsRepo := new(MockNotificationSettingsRepository)
+
+	notificationUseCase := usecase.NewNotificationUseCase(new(MockEmailService), new(MockNotificationService), settingsRepo, nil)
+
+	settingsRepo.On("Get", ctx, domain.RecipientTypeUser, "user1").Return(&domain.NotificationSettings{
+		RecipientType: domain.RecipientTypeUser,
+		RecipientID:   "user1",
+		Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelEmail, Enabled: false},
+		},
+	}, nil)
+
+	settings, err := notificationUseCase.GetNotificationSettings(ctx, domain.RecipientTypeUser, "user1")
+
+	require.NoError(t, err)
+	assert.Len(t, settings.Preferences, len(domain.NotificationTypes)*len(domain.NotificationChannels))
+	assert.False(t, settings.Allows(domain.NotificationTypeBookingConfirmed, domain.NotificationChannelEmail))
+	assert.True(t, settings.Allows(domain.NotificationTypeBookingRejected, domain.NotificationChannelEmail))
+	assert.True(t, settings.Allows(domain.NotificationTypeBookingConfirmed, domain.NotificationChannelInApp))
+	assert.False(t, settings.Allows(domain.NotificationTypeBookingConfirmed, domain.NotificationChannelSMS))
+}
+
+func TestUpdateNotificationSettings(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("saves valid preferences", func(t *testing.T) {
+		settingsRepo := new(MockNotificationSettingsRepository)
+		notificationUseCase := usecase.NewNotificationUseCase(new(MockEmailService), new(MockNotificationService), settingsRepo, nil)
+
+		settings := &domain.NotificationSettings{
+			RecipientType: domain.RecipientTypeRestaurant,
+			RecipientID:   "rest1",
+			Preferences: []domain.NotificationPreference{
+				{Type: domain.NotificationTypeNewBooking, Channel: domain.NotificationChannelPush, Enabled: true},
+			},
+		}
+		settingsRepo.On("Save", ctx, settings).Return(nil).Once()
+
+		result, err := notificationUseCase.UpdateNotificationSettings(ctx, settings)
+
+		require.NoError(t, err)
+		assert.True(t, result.Allows(domain.NotificationTypeNewBooking, domain.NotificationChannelPush))
+		settingsRepo.AssertExpectations(t)
+	})
+
+	invalid := map[string][]domain.NotificationPreference{
+		"unknown type":    {{Type: "birthday", Channel: domain.NotificationChannelEmail}},
+		"unknown channel": {{Type: domain.NotificationTypeNewBooking, Channel: "fax"}},
+		"duplicate": {
+			{Type: domain.NotificationTypeNewBooking, Channel: domain.NotificationChannelEmail},
+			{Type: domain.NotificationTypeNewBooking, Channel: domain.NotificationChannelEmail, Enabled: true},
+		},
+	}
+	for name, preferences := range invalid {
+		t.Run(name, func(t *testing.T) {
+			settingsRepo := new(MockNotificationSettingsRepository)
+			notificationUseCase := usecase.NewNotificationUseCase(new(MockEmailService), new(MockNotificationService), settingsRepo, nil)
+
+			_, err := notificationUseCase.UpdateNotificationSettings(ctx, &domain.NotificationSettings{
+				RecipientType: domain.RecipientTypeUser,
+				RecipientID:   "user1",
+				Preferences:   preferences,
+			})
+
+			assert.ErrorIs(t, err, usecase.ErrInvalidNotificationPreference)
+			settingsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateNotificationSettings_InvalidQuietHours(t *testing.T) {
+	ctx := newTestContext()
+
+	invalid := map[string][]domain.QuietHours{
+		"unknown channel": {{Channel: "fax", Start: "22:00", End: "08:00"}},
+		"no window":       {{Channel: domain.NotificationChannelEmail}},
+		"empty window":    {{Channel: domain.NotificationChannelEmail, Start: "22:00", End: "22:00"}},
+		"duplicate": {
+			{Channel: domain.NotificationChannelSMS, Start: "22:00", End: "08:00"},
+			{Channel: domain.NotificationChannelSMS, Start: "13:00", End: "15:00"},
+		},
+	}
+	for name, quietHours := range invalid {
+		t.Run(name, func(t *testing.T) {
+			settingsRepo := new(MockNotificationSettingsRepository)
+			notificationUseCase := usecase.NewNotificationUseCase(new(MockEmailService), new(MockNotificationService), settingsRepo, nil)
+
+			_, err := notificationUseCase.UpdateNotificationSettings(ctx, &domain.NotificationSettings{
+				RecipientType: domain.RecipientTypeUser,
+				RecipientID:   "user1",
+				QuietHours:    quietHours,
+			})
+
+			assert.ErrorIs(t, err, usecase.ErrInvalidQuietHours)
+			settingsRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNotifyUser_EmailTurnedOff(t *testing.T) {
+	ctx := newTestContext()
+	mockEmailService := new(MockEmailService)
+	mockNotifier := new(MockNotificationService)
+	settingsRepo := new(MockNotificationSettingsRepository)
+
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, settingsRepo, nil)
+
+	settingsRepo.On("Get", ctx, domain.RecipientTypeUser, "user1").Return(&domain.NotificationSettings{
+		Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeBookingConfirmed, Channel: domain.NotificationChannelEmail, Enabled: false},
+		},
+	}, nil)
+	mockNotifier.On("NotifyUser", ctx, "user1", domain.NotificationTypeBookingConfirmed, "title", "message", "booking1").Return(nil)
+
+	err := notificationUseCase.NotifyUser(ctx, "user1", domain.NotificationTypeBookingConfirmed, "title", "message", "booking1")
+
+	require.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+	mockEmailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,6 +1,7 @@
 package usecase_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -25,7 +26,7 @@ func TestNotifyRestaurant_Success(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	restaurantID := "rest123"
@@ -48,7 +49,7 @@ func TestNotifyRestaurant_Error(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	restaurantID := "rest123"
@@ -72,7 +73,7 @@ func TestNotifyUser_Success(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	userID := "user123"
@@ -96,7 +97,7 @@ func TestNotifyUser_Error(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	userID := "user123"
@@ -120,7 +121,7 @@ func TestGetUserNotifications_Success(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	userID := "user123"
@@ -162,7 +163,7 @@ func TestGetUserNotifications_Error(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	userID := "user123"
@@ -183,7 +184,7 @@ func TestMarkAsRead_Success(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	notificationID := "notif123"
@@ -200,7 +201,7 @@ func TestMarkAsRead_Error(t *testing.T) {
 	mockEmailService := new(MockEmailService)
 	mockNotifier := new(MockNotificationService)
 
-	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier)
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
 
 	ctx := newTestContext()
 	notificationID := "notif123"
@@ -214,3 +215,129 @@ func TestMarkAsRead_Error(t *testing.T) {
 	assert.Equal(t, expectedErr, err)
 	mockNotifier.AssertExpectations(t)
 }
+
+type MockFactOfTheDayProvider struct {
+	mock.Mock
+}
+
+func (m *MockFactOfTheDayProvider) GetFactOfTheDay(ctx context.Context, locale string) (*domain.Fact, error) {
+	args := m.Called(ctx, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Fact), args.Error(1)
+}
+
+func TestNotifyUser_WithFactOfTheDayFooter(t *testing.T) {
+	mockEmailService := new(MockEmailService)
+	mockNotifier := new(MockNotificationService)
+	mockFacts := new(MockFactOfTheDayProvider)
+
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, mockFacts)
+
+	ctx := newTestContext()
+	userID := "user123"
+	notificationType := domain.NotificationTypeBookingConfirmed
+	title := "booking confirmed"
+	message := "your booking is confirmed"
+	relatedID := "booking123"
+
+	mockFacts.On("GetFactOfTheDay", ctx, domain.DefaultFactLocale).Return(&domain.Fact{ID: "fact1", Content: "Pizza was invented in Naples"}, nil)
+	mockNotifier.On("NotifyUser", ctx, userID, notificationType, title, message, relatedID).Return(nil)
+	mockEmailService.On("SendEmail", userID+"@example.com", title,
+		message+"\n\n---\nFact of the day: Pizza was invented in Naples").Return(nil)
+
+	err := notificationUseCase.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+
+	assert.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+	mockEmailService.AssertExpectations(t)
+	mockFacts.AssertExpectations(t)
+}
+
+func TestNotifyUser_FactOfTheDayUnavailable(t *testing.T) {
+	mockEmailService := new(MockEmailService)
+	mockNotifier := new(MockNotificationService)
+	mockFacts := new(MockFactOfTheDayProvider)
+
+	notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, mockFacts)
+
+	ctx := newTestContext()
+	userID := "user123"
+	notificationType := domain.NotificationTypeBookingConfirmed
+	title := "booking confirmed"
+	message := "your booking is confirmed"
+	relatedID := "booking123"
+
+	mockFacts.On("GetFactOfTheDay", ctx, domain.DefaultFactLocale).Return(nil, usecase.ErrNoFactsAvailable)
+	mockNotifier.On("NotifyUser", ctx, userID, notificationType, title, message, relatedID).Return(nil)
+	mockEmailService.On("SendEmail", userID+"@example.com", title, message).Return(nil)
+
+	err := notificationUseCase.NotifyUser(ctx, userID, notificationType, title, message, relatedID)
+
+	assert.NoError(t, err)
+	mockEmailService.AssertExpectations(t)
+}
+
+func TestResendNotification(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("restaurant notification", func(t *testing.T) {
+		mockEmailService := new(MockEmailService)
+		mockNotifier := new(MockNotificationService)
+		notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
+
+		notification := &domain.Notification{
+			ID:            "notif123",
+			RecipientType: domain.RecipientTypeRestaurant,
+			RecipientID:   "rest123",
+			Title:         "new booking",
+			Message:       "you have a new booking",
+		}
+
+		mockNotifier.On("GetNotification", ctx, "notif123").Return(notification, nil)
+		mockEmailService.On("SendEmail", "rest123@example.com", notification.Title, notification.Message).Return(nil)
+
+		err := notificationUseCase.ResendNotification(ctx, "notif123")
+
+		assert.NoError(t, err)
+		mockNotifier.AssertExpectations(t)
+		mockEmailService.AssertExpectations(t)
+	})
+
+	t.Run("email error", func(t *testing.T) {
+		mockEmailService := new(MockEmailService)
+		mockNotifier := new(MockNotificationService)
+		notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
+
+		notification := &domain.Notification{
+			ID:            "notif123",
+			RecipientType: domain.RecipientTypeUser,
+			RecipientID:   "user123",
+			Title:         "booking confirmed",
+			Message:       "your booking is confirmed",
+		}
+		expectedErr := errors.New("smtp error")
+
+		mockNotifier.On("GetNotification", ctx, "notif123").Return(notification, nil)
+		mockEmailService.On("SendEmail", "user123@example.com", notification.Title, notification.Message).Return(expectedErr)
+
+		err := notificationUseCase.ResendNotification(ctx, "notif123")
+
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("notification not found", func(t *testing.T) {
+		mockEmailService := new(MockEmailService)
+		mockNotifier := new(MockNotificationService)
+		notificationUseCase := usecase.NewNotificationUseCase(mockEmailService, mockNotifier, nil, nil)
+
+		expectedErr := errors.New("notification not found")
+		mockNotifier.On("GetNotification", ctx, "missing").Return(nil, expectedErr)
+
+		err := notificationUseCase.ResendNotification(ctx, "missing")
+
+		assert.Equal(t, expectedErr, err)
+		mockEmailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
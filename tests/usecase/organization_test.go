@@ -0,0 +1,52 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationUseCase_CreateOrganization(t *testing.T) {
+	ctx := adminContext()
+	organizationRepo := new(MockOrganizationRepository)
+	useCase := usecase.NewOrganizationUseCase(organizationRepo, new(MockRestaurantRepository))
+
+	organizationRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+	organization, err := useCase.CreateOrganization(ctx, " Vogue Group ")
+	require.NoError(t, err)
+	assert.Equal(t, "Vogue Group", organization.Name)
+
+	_, err = useCase.CreateOrganization(ctx, " ")
+	assert.ErrorIs(t, err, usecase.ErrInvalidOrganization)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err = useCase.CreateOrganization(staffCtx, "Vogue Group")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	assert.ErrorIs(t, useCase.AddRestaurant(staffCtx, "o1", "r1"), tenant.ErrAccessDenied)
+
+	organizationRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestOrganizationUseCase_ListSisterRestaurants(t *testing.T) {
+	ctx := newTestContext()
+	restaurantRepo := new(MockRestaurantRepository)
+	useCase := usecase.NewOrganizationUseCase(new(MockOrganizationRepository), restaurantRepo)
+
+	sisters := []*domain.Restaurant{{ID: "r2", Name: "Vogue Riverside"}}
+	restaurantRepo.On("ListSisters", ctx, domain.RestaurantID("r1"), 0, 100).Return(sisters, nil)
+
+	restaurants, err := useCase.ListSisterRestaurants(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, sisters, restaurants)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r2"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err = useCase.ListSisterRestaurants(staffCtx, "r1")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
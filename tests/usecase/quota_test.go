@@ -0,0 +1,136 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuotaRepository) GetPlan(ctx context.Context, restaurantID string) (*domain.RestaurantPlan, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantPlan), args.Error(1)
+}
+
+func (m *MockQuotaRepository) SetPlan(ctx context.Context, plan *domain.RestaurantPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockQuotaRepository) CountActiveSlots(ctx context.Context, restaurantID string, today time.Time) (int, error) {
+	args := m.Called(ctx, restaurantID, today)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuotaRepository) CountBookings(ctx context.Context, restaurantID string, from, to time.Time) (int, error) {
+	args := m.Called(ctx, restaurantID, from, to)
+	return args.Int(0), args.Error(1)
+}
+
+var testPlans = map[domain.Plan]domain.QuotaLimits{
+	domain.PlanFree: {ActiveSlots: 500, MonthlyBookings: 300},
+	domain.PlanPro:  {},
+}
+
+func TestQuotaLimitedBookingUseCase(t *testing.T) {
+	ctx := newTestContext()
+	quotaRepo := new(MockQuotaRepository)
+	quotaRepo.On("GetPlan", mock.Anything, "r1").Return(&domain.RestaurantPlan{RestaurantID: "r1", Plan: domain.PlanFree}, nil)
+	quotaRepo.On("CountBookings", mock.Anything, "r1", mock.Anything, mock.Anything).Return(300, nil)
+
+	unlimited := 0
+	quotaRepo.On("GetPlan", mock.Anything, "r2").Return(&domain.RestaurantPlan{RestaurantID: "r2", Plan: domain.PlanFree, MonthlyBookings: &unlimited}, nil)
+
+	bookings := new(stubBookingUseCase)
+	quotas := usecase.NewQuotaUseCase(quotaRepo, new(MockRestaurantRepository), new(stubTransactor), testPlans)
+	limited := usecase.NewQuotaLimitedBookingUseCase(bookings, quotas, newTestClock())
+
+	_, err := limited.CreateBooking(ctx, &domain.Booking{RestaurantID: "r1"})
+	var exceeded *usecase.QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+	assert.Equal(t, domain.QuotaMonthlyBookings, exceeded.Resource)
+	assert.Equal(t, 300, exceeded.Limit)
+
+	testBooking := &domain.Booking{RestaurantID: "r1", IsTest: true}
+	bookings.On("CreateBooking", ctx, testBooking).Return("test1", nil)
+	id, err := limited.CreateBooking(ctx, testBooking)
+	require.NoError(t, err, "test bookings are not limited")
+	assert.Equal(t, "test1", id)
+
+	overridden := &domain.Booking{RestaurantID: "r2"}
+	bookings.On("CreateBooking", ctx, overridden).Return("booking2", nil)
+	_, err = limited.CreateBooking(ctx, overridden)
+	require.NoError(t, err, "an override of 0 lifts the limit")
+	quotaRepo.AssertNotCalled(t, "CountBookings", mock.Anything, "r2", mock.Anything, mock.Anything)
+}
+
+func TestLimitActiveSlots(t *testing.T) {
+	ctx := newTestContext()
+	now := time.Date(2025, 6, 4, 15, 0, 0, 0, time.UTC)
+	today := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+
+	quotaRepo := new(MockQuotaRepository)
+	quotaRepo.On("GetPlan", mock.Anything, "r1").Return(&domain.RestaurantPlan{RestaurantID: "r1", Plan: domain.PlanFree}, nil)
+
+	transactor := new(stubTransactor)
+	quotas := usecase.NewQuotaUseCase(quotaRepo, new(MockRestaurantRepository), transactor, testPlans)
+
+	quotaRepo.On("CountActiveSlots", mock.Anything, "r1", today).Return(499, nil).Once()
+	quotaRepo.On("CountActiveSlots", mock.Anything, "r1", today).Return(501, nil).Once()
+	err := quotas.LimitActiveSlots(ctx, "r1", now, func(context.Context) error { return nil })
+	var exceeded *usecase.QuotaExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, domain.QuotaActiveSlots, exceeded.Resource)
+	assert.Equal(t, 1, transactor.rolledBack)
+
+	// A restaurant beyond a lowered limit may still change the slots it has.
+	quotaRepo.On("CountActiveSlots", mock.Anything, "r1", today).Return(520, nil).Twice()
+	err = quotas.LimitActiveSlots(ctx, "r1", now, func(context.Context) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, transactor.committed)
+}
+
+func TestSetPlan(t *testing.T) {
+	quotaRepo := new(MockQuotaRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", mock.Anything, domain.RestaurantID("r1")).Return(&domain.Restaurant{ID: "r1"}, nil)
+
+	limit := 1000
+	plan := &domain.RestaurantPlan{RestaurantID: "r1", Plan: domain.PlanPro, ActiveSlots: &limit, Note: "trial"}
+	quotaRepo.On("SetPlan", mock.Anything, plan).Return(nil)
+
+	quotas := usecase.NewQuotaUseCase(quotaRepo, restaurantRepo, new(stubTransactor), testPlans)
+	require.NoError(t, quotas.SetPlan(adminContext(), plan))
+	assert.Equal(t, domain.QuotaLimits{ActiveSlots: 1000}, plan.Limits(testPlans[domain.PlanPro]))
+
+	err := quotas.SetPlan(adminContext(), &domain.RestaurantPlan{RestaurantID: "r1", Plan: "enterprise"})
+	assert.ErrorIs(t, err, usecase.ErrInvalidPlan)
+
+	negative := -1
+	err = quotas.SetPlan(adminContext(), &domain.RestaurantPlan{RestaurantID: "r1", Plan: domain.PlanFree, MonthlyBookings: &negative})
+	assert.ErrorIs(t, err, usecase.ErrInvalidQuotaLimit)
+
+	staffCtx := tenant.NewContext(newTestContext(), &tenant.Principal{
+		UserID:        "staff",
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+		RestaurantIDs: []string{"r1"},
+	})
+	err = quotas.SetPlan(staffCtx, plan)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	quotaRepo.AssertNumberOfCalls(t, "SetPlan", 1)
+}
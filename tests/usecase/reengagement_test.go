@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReengagementRepository struct {
+	mock.Mock
+}
+
+func (m *MockReengagementRepository) ListLapsed(ctx context.Context, lastVisit, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	args := m.Called(ctx, lastVisit, today)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReengagementCandidate), args.Error(1)
+}
+
+func (m *MockReengagementRepository) ListAnniversaries(ctx context.Context, visitDate, today time.Time) ([]*domain.ReengagementCandidate, error) {
+	args := m.Called(ctx, visitDate, today)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReengagementCandidate), args.Error(1)
+}
+
+type MockSMSSender struct {
+	mock.Mock
+}
+
+func (m *MockSMSSender) SendSMS(to, body string) error {
+	args := m.Called(to, body)
+	return args.Error(0)
+}
+
+func optedIn(channels ...domain.NotificationChannel) *domain.NotificationSettings {
+	settings := &domain.NotificationSettings{}
+	for _, channel := range channels {
+		settings.Preferences = append(settings.Preferences, domain.NotificationPreference{
+			Type: domain.NotificationTypeMarketing, Channel: channel, Enabled: true,
+		})
+	}
+	return settings
+}
+
+func TestSendInvitations(t *testing.T) {
+	reengagementRepo := new(MockReengagementRepository)
+	settingsRepo := new(MockNotificationSettingsRepository)
+	userRepo := new(MockUserRepository)
+	notificationSvc := new(MockNotificationService)
+	emailService := new(MockEmailService)
+	smsSender := new(MockSMSSender)
+
+	day := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	lastVisit := day.AddDate(0, 0, -42)
+	anniversary := time.Date(2024, 6, 29, 0, 0, 0, 0, time.UTC)
+
+	reengagementRepo.On("ListAnniversaries", mock.Anything, anniversary, day).Return([]*domain.ReengagementCandidate{
+		{UserID: "u1", RestaurantID: "r1", RestaurantName: "Bistro", Reason: domain.ReengagementReasonAnniversary, VisitDate: anniversary},
+	}, nil)
+	reengagementRepo.On("ListLapsed", mock.Anything, lastVisit, day).Return([]*domain.ReengagementCandidate{
+		{UserID: "u1", RestaurantID: "r2", RestaurantName: "Cafe", Reason: domain.ReengagementReasonLapsed, VisitDate: lastVisit},
+		{UserID: "u2", RestaurantID: "r2", RestaurantName: "Cafe", Reason: domain.ReengagementReasonLapsed, VisitDate: lastVisit},
+		{UserID: "u3", RestaurantID: "r2", RestaurantName: "Cafe", Reason: domain.ReengagementReasonLapsed, VisitDate: lastVisit},
+		{UserID: "u4", RestaurantID: "r2", RestaurantName: "Cafe", Reason: domain.ReengagementReasonLapsed, VisitDate: lastVisit},
+		{UserID: "u5", RestaurantID: "r2", RestaurantName: "Cafe", Reason: domain.ReengagementReasonLapsed, VisitDate: lastVisit},
+	}, nil)
+
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeUser, "u1").
+		Return(optedIn(domain.NotificationChannelEmail, domain.NotificationChannelSMS), nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeUser, "u2").Return(optedIn(domain.NotificationChannelSMS), nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeUser, "u3").Return(optedIn(domain.NotificationChannelInApp), nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeUser, "u4").Return(&domain.NotificationSettings{}, nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeUser, "u5").Return(nil, errors.New("db down"))
+
+	userRepo.On("GetByID", mock.Anything, domain.UserID("u1")).Return(&domain.User{ID: "u1", Email: "u1@example.com"}, nil)
+	userRepo.On("GetByID", mock.Anything, domain.UserID("u2")).Return(&domain.User{ID: "u2", Phone: "+70000000002"}, nil)
+
+	emailService.On("SendEmail", "u1@example.com", "Your anniversary is coming up", mock.Anything).Return(nil)
+	smsSender.On("SendSMS", "+70000000002", mock.Anything).Return(nil)
+	notificationSvc.On("NotifyUser", mock.Anything, "u3", domain.NotificationTypeMarketing, "We miss you at Cafe", mock.Anything, "r2").Return(nil)
+
+	uc := usecase.NewReengagementUseCase(reengagementRepo, settingsRepo, userRepo, notificationSvc, emailService, smsSender, nil,
+		usecase.ReengagementPolicy{RebookAfterWeeks: 6, AnniversaryLeadDays: 14})
+	sent, err := uc.SendInvitations(newTestContext(), day.Add(11*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent, "guests who did not opt in, or whose preferences cannot be read, get nothing")
+
+	emailService.AssertExpectations(t)
+	smsSender.AssertExpectations(t)
+	notificationSvc.AssertExpectations(t)
+	smsSender.AssertNotCalled(t, "SendSMS", "+70000000001", mock.Anything)
+	emailService.AssertNumberOfCalls(t, "SendEmail", 1)
+}
+
+func TestSendInvitations_Off(t *testing.T) {
+	reengagementRepo := new(MockReengagementRepository)
+
+	uc := usecase.NewReengagementUseCase(reengagementRepo, new(MockNotificationSettingsRepository), new(MockUserRepository),
+		new(MockNotificationService), new(MockEmailService), new(MockSMSSender), nil, usecase.ReengagementPolicy{})
+	sent, err := uc.SendInvitations(newTestContext(), testNow)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	reengagementRepo.AssertNotCalled(t, "ListLapsed", mock.Anything, mock.Anything, mock.Anything)
+	reengagementRepo.AssertNotCalled(t, "ListAnniversaries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFormatReengagementInvitation(t *testing.T) {
+	title, message := usecase.FormatReengagementInvitation(&domain.ReengagementCandidate{
+		RestaurantName: "Bistro",
+		Reason:         domain.ReengagementReasonLapsed,
+		VisitDate:      time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, "We miss you at Bistro", title)
+	assert.Equal(t, "It has been a while since your last visit on May 4. Book a table at Bistro again.", message)
+}
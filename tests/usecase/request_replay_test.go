@@ -0,0 +1,186 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/idgen"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func adminContext() context.Context {
+	return tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "admin1", Roles: []tenant.Role{tenant.RoleAdmin}})
+}
+
+func TestRecordRequest_RemovesCredentials(t *testing.T) {
+	replay := usecase.NewRequestReplayUseCase(10, "", "", idgen.UUID{}, newTestClock())
+	ctx := adminContext()
+
+	replay.RecordRequest(ctx, "partner-key", &domain.RecordedRequest{
+		Method: http.MethodPost,
+		Path:   "/api/v1/bookings?token=abc&date=2025-01-01",
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer secret",
+			"X-Api-Key":     "partner-key",
+			"Cookie":        "session=1",
+			"X-User-Id":     "user1",
+		},
+		Body:   `{"user_id":"user1","guest":{"password":"hunter2"},"cards":[{"card_number":"4111"}]}`,
+		Status: http.StatusCreated,
+	})
+
+	requests, err := replay.ListRecordedRequests(ctx, usecase.APIKeyID("partner-key"))
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+
+	recorded := requests[0]
+	assert.Equal(t, usecase.APIKeyID("partner-key"), recorded.APIKeyID)
+	assert.NotEqual(t, "partner-key", recorded.APIKeyID)
+	assert.Equal(t, "/api/v1/bookings?date=2025-01-01&token=%5BREDACTED%5D", recorded.Path)
+	assert.Equal(t, map[string]string{"Content-Type": "application/json", "X-User-ID": "user1"}, recorded.Headers)
+	assert.JSONEq(t, `{"user_id":"user1","guest":{"password":"[REDACTED]"},"cards":[{"card_number":"[REDACTED]"}]}`, recorded.Body)
+	assert.False(t, recorded.BodyOmitted)
+}
+
+func TestRecordRequest_OmitsNonJSONBody(t *testing.T) {
+	replay := usecase.NewRequestReplayUseCase(10, "", "", idgen.UUID{}, newTestClock())
+	ctx := adminContext()
+
+	replay.RecordRequest(ctx, "partner-key", &domain.RecordedRequest{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/restaurants/import",
+		Headers: map[string]string{"Content-Type": "text/csv"},
+		Body:    "name,phone\nCafe,123",
+	})
+
+	requests, err := replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Empty(t, requests[0].Body)
+	assert.True(t, requests[0].BodyOmitted)
+}
+
+func TestRecordRequest_KeepsLatestPerKey(t *testing.T) {
+	replay := usecase.NewRequestReplayUseCase(2, "", "", idgen.UUID{}, newTestClock())
+	ctx := adminContext()
+	started := testNow
+
+	for i, path := range []string{"/api/v1/a", "/api/v1/b", "/api/v1/c"} {
+		replay.RecordRequest(ctx, "key1", &domain.RecordedRequest{
+			Method:     http.MethodGet,
+			Path:       path,
+			RecordedAt: started.Add(time.Duration(i) * time.Second),
+		})
+	}
+	replay.RecordRequest(ctx, "key2", &domain.RecordedRequest{Method: http.MethodGet, Path: "/api/v1/d", RecordedAt: started})
+
+	requests, err := replay.ListRecordedRequests(ctx, usecase.APIKeyID("key1"))
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/api/v1/c", requests[0].Path)
+	assert.Equal(t, "/api/v1/b", requests[1].Path)
+
+	all, err := replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestRecordRequest_DisabledWithoutLimit(t *testing.T) {
+	replay := usecase.NewRequestReplayUseCase(0, "", "", idgen.UUID{}, newTestClock())
+	ctx := adminContext()
+
+	replay.RecordRequest(ctx, "key1", &domain.RecordedRequest{Method: http.MethodGet, Path: "/api/v1/a"})
+
+	requests, err := replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestRequestReplay_RequiresAdmin(t *testing.T) {
+	replay := usecase.NewRequestReplayUseCase(10, "http://staging", "", idgen.UUID{}, newTestClock())
+	ctx := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+
+	_, err := replay.ListRecordedRequests(ctx, "")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	_, err = replay.ReplayRequest(ctx, "any")
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
+
+func TestReplayRequest(t *testing.T) {
+	var received *http.Request
+	var receivedBody string
+	staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"booking1"}`))
+	}))
+	defer staging.Close()
+
+	replay := usecase.NewRequestReplayUseCase(10, staging.URL+"/", "staging-key", idgen.UUID{}, newTestClock())
+	ctx := adminContext()
+
+	replay.RecordRequest(ctx, "partner-key", &domain.RecordedRequest{
+		Method:  http.MethodPost,
+		Path:    "/api/v1/bookings?date=2025-01-01",
+		Headers: map[string]string{"Content-Type": "application/json", "X-User-ID": "user1"},
+		Body:    `{"user_id":"user1"}`,
+	})
+	requests, err := replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+
+	result, err := replay.ReplayRequest(ctx, requests[0].ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, result.Status)
+	assert.Equal(t, `{"id":"booking1"}`, result.Body)
+	assert.Equal(t, staging.URL+"/api/v1/bookings?date=2025-01-01", result.URL)
+	assert.Equal(t, "application/json", result.Headers["Content-Type"])
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/api/v1/bookings", received.URL.Path)
+	assert.Equal(t, "user1", received.Header.Get("X-User-ID"))
+	assert.Equal(t, "staging-key", received.Header.Get("X-API-Key"))
+	assert.Equal(t, requests[0].ID, received.Header.Get(usecase.ReplayedRequestHeader))
+	assert.Equal(t, `{"user_id":"user1"}`, receivedBody)
+}
+
+func TestReplayRequest_Errors(t *testing.T) {
+	ctx := adminContext()
+
+	replay := usecase.NewRequestReplayUseCase(10, "", "", idgen.UUID{}, newTestClock())
+	_, err := replay.ReplayRequest(ctx, "missing")
+	assert.ErrorIs(t, err, usecase.ErrRecordedRequestNotFound)
+
+	replay.RecordRequest(ctx, "key1", &domain.RecordedRequest{Method: http.MethodGet, Path: "/api/v1/a"})
+	requests, err := replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	_, err = replay.ReplayRequest(ctx, requests[0].ID)
+	assert.ErrorIs(t, err, usecase.ErrReplayNotConfigured)
+
+	staging := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	stagingURL := staging.URL
+	staging.Close()
+
+	replay = usecase.NewRequestReplayUseCase(10, stagingURL, "", idgen.UUID{}, newTestClock())
+	replay.RecordRequest(ctx, "key1", &domain.RecordedRequest{Method: http.MethodGet, Path: "/api/v1/a"})
+	requests, err = replay.ListRecordedRequests(ctx, "")
+	require.NoError(t, err)
+	_, err = replay.ReplayRequest(ctx, requests[0].ID)
+	assert.True(t, errors.Is(err, usecase.ErrReplayFailed))
+}
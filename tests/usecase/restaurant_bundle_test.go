@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestaurantUseCase_ExportRestaurant(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	facts := []domain.Fact{{ID: "f1", RestaurantID: restaurant.ID, Content: "fact", Locale: "en"}}
+	hours := []*domain.WorkingHours{{ID: "h1", RestaurantID: restaurant.ID, WeekDay: domain.Monday, OpenTime: "10:00", CloseTime: "22:00"}}
+
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurant.ID)).Return(restaurant, nil)
+	mockRestaurantRepo.On("GetFacts", ctx, domain.RestaurantID(restaurant.ID)).Return(facts, nil)
+	mockWorkingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurant.ID)).Return(hours, nil)
+
+	bundle, err := useCase.ExportRestaurant(ctx, domain.RestaurantID(restaurant.ID))
+
+	require.NoError(t, err)
+	assert.Equal(t, usecase.RestaurantBundleVersion, bundle.Version)
+	assert.Equal(t, restaurant.ID, bundle.Restaurant.ID)
+	assert.Equal(t, facts, bundle.Restaurant.Facts)
+	assert.Equal(t, hours, bundle.WorkingHours)
+}
+
+func TestRestaurantUseCase_ExportRestaurantNotFound(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID("missing")).Return(nil, errors.New(common.ErrRestaurantNotFound))
+
+	bundle, err := useCase.ExportRestaurant(ctx, "missing")
+
+	assert.EqualError(t, err, common.ErrRestaurantNotFound)
+	assert.Nil(t, bundle)
+}
+
+func TestRestaurantUseCase_ImportRestaurantBundle(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+	transactor := new(stubTransactor)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), transactor, "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	restaurant.Facts = []domain.Fact{{ID: "f1", RestaurantID: restaurant.ID, Content: "fact", Locale: "de"}}
+	bundle := &usecase.RestaurantBundle{
+		Version:    usecase.RestaurantBundleVersion,
+		Restaurant: restaurant,
+		WorkingHours: []*domain.WorkingHours{
+			{ID: "h1", RestaurantID: "other", WeekDay: domain.Sunday, IsClosed: true, OpenTime: "00:00", CloseTime: "00:00"},
+		},
+	}
+
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurant.ID)).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "test-restaurant", restaurant.ID).Return(false, nil)
+	mockRestaurantRepo.On("Create", ctx, mock.MatchedBy(func(r *domain.Restaurant) bool {
+		return r.ID == restaurant.ID && r.Slug == "test-restaurant" && r.Facts == nil
+	})).Return(nil)
+	mockWorkingHoursRepo.On("CreateBatch", ctx, mock.MatchedBy(func(hours []*domain.WorkingHours) bool {
+		return len(hours) == 1 && hours[0].ID == "" && hours[0].RestaurantID == restaurant.ID && hours[0].IsClosed
+	})).Return(nil)
+	mockRestaurantRepo.On("AddFact", ctx, domain.RestaurantID(restaurant.ID), mock.MatchedBy(func(f domain.Fact) bool {
+		return f.ID == "" && f.Content == "fact" && f.Locale == "de"
+	})).Return(&domain.Fact{}, nil)
+
+	id, err := useCase.ImportRestaurantBundle(ctx, bundle)
+
+	require.NoError(t, err)
+	assert.Equal(t, restaurant.ID, id)
+	assert.Equal(t, 1, transactor.committed)
+	mockRestaurantRepo.AssertExpectations(t)
+	mockWorkingHoursRepo.AssertExpectations(t)
+}
+
+func TestRestaurantUseCase_ImportRestaurantBundleErrors(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	transactor := new(stubTransactor)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), transactor, "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+
+	_, err := useCase.ImportRestaurantBundle(ctx, &usecase.RestaurantBundle{Version: 99, Restaurant: restaurant})
+	assert.ErrorIs(t, err, usecase.ErrUnsupportedBundleVersion)
+
+	_, err = useCase.ImportRestaurantBundle(ctx, &usecase.RestaurantBundle{Version: usecase.RestaurantBundleVersion})
+	assert.ErrorIs(t, err, usecase.ErrInvalidBundle)
+
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurant.ID)).Return(restaurant, nil)
+
+	_, err = useCase.ImportRestaurantBundle(ctx, &usecase.RestaurantBundle{Version: usecase.RestaurantBundleVersion, Restaurant: restaurant})
+	assert.ErrorIs(t, err, usecase.ErrRestaurantAlreadyExists)
+	assert.Equal(t, 1, transactor.rolledBack)
+	mockRestaurantRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,197 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRestaurantClosureRepository struct {
+	mock.Mock
+}
+
+func (m *MockRestaurantClosureRepository) Create(ctx context.Context, closure *domain.RestaurantClosure) error {
+	args := m.Called(ctx, closure)
+	return args.Error(0)
+}
+
+func (m *MockRestaurantClosureRepository) GetByID(ctx context.Context, id string) (*domain.RestaurantClosure, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RestaurantClosure), args.Error(1)
+}
+
+func (m *MockRestaurantClosureRepository) ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.RestaurantClosure, error) {
+	args := m.Called(ctx, restaurantID, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantClosure), args.Error(1)
+}
+
+func (m *MockRestaurantClosureRepository) ListCovering(ctx context.Context, restaurantIDs []string, date time.Time) ([]*domain.RestaurantClosure, error) {
+	args := m.Called(ctx, restaurantIDs, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantClosure), args.Error(1)
+}
+
+func (m *MockRestaurantClosureRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// summerVacation closes the restaurant from June 10 up to June 20, 2025.
+func summerVacation(restaurantID string) *domain.RestaurantClosure {
+	return &domain.RestaurantClosure{
+		ID:           "closure-" + restaurantID,
+		RestaurantID: restaurantID,
+		StartsOn:     time.Date(2025, time.June, 10, 0, 0, 0, 0, time.UTC),
+		ReopensOn:    time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC),
+		Message:      "We are on vacation.",
+	}
+}
+
+func TestRestaurantClosureUseCase_CheckOpen(t *testing.T) {
+	ctx := newTestContext()
+
+	closureRepo := new(MockRestaurantClosureRepository)
+	closureRepo.On("ListByRestaurant", ctx, "bistro", mock.Anything).Return([]*domain.RestaurantClosure{summerVacation("bistro")}, nil)
+	closureRepo.On("ListCovering", ctx, []string{"far", "unplaced", "shut", "sandbox", "near"}, mock.Anything).
+		Return([]*domain.RestaurantClosure{summerVacation("shut")}, nil).Once()
+
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("bistro")).Return(&domain.Restaurant{ID: "bistro", Name: "Bistro"}, nil)
+	restaurantRepo.On("ListSisters", ctx, domain.RestaurantID("bistro"), 0, mock.Anything).Return([]*domain.Restaurant{
+		{ID: "far", Name: "Far Away", Address: "Far st. 1"},
+		{ID: "unplaced", Name: "Unplaced"},
+		{ID: "shut", Name: "Also Shut"},
+		{ID: "sandbox", Name: "Sandbox", IsTest: true},
+		{ID: "near", Name: "Round The Corner", Address: "Main st. 3"},
+	}, nil)
+
+	placed := func(restaurantID string, latitude, longitude float64) *domain.RestaurantLocation {
+		return &domain.RestaurantLocation{RestaurantID: restaurantID, Status: domain.GeocodingDone, Latitude: latitude, Longitude: longitude}
+	}
+	locationRepo := new(MockRestaurantLocationRepository)
+	locationRepo.On("GetByRestaurant", ctx, "bistro").Return(placed("bistro", 55.75, 37.61), nil)
+	locationRepo.On("GetByRestaurant", ctx, "far").Return(placed("far", 55.95, 37.90), nil)
+	locationRepo.On("GetByRestaurant", ctx, "near").Return(placed("near", 55.76, 37.62), nil)
+	locationRepo.On("GetByRestaurant", ctx, "unplaced").Return(nil, errors.New(common.ErrRestaurantLocationNotFound))
+
+	closures := usecase.NewRestaurantClosureUseCase(closureRepo, restaurantRepo, locationRepo, new(stubTransactor), newTestClock())
+
+	err := closures.CheckOpen(ctx, &domain.Booking{RestaurantID: "bistro", Date: time.Date(2025, time.June, 14, 0, 0, 0, 0, time.UTC)})
+
+	var closed *usecase.RestaurantClosedError
+	require.ErrorAs(t, err, &closed)
+	assert.ErrorIs(t, err, usecase.ErrRestaurantClosed)
+	assert.Equal(t, "closure-bistro", closed.Closure.ID)
+
+	names := make([]string, 0, len(closed.Sisters))
+	for _, sister := range closed.Sisters {
+		names = append(names, sister.Name)
+	}
+	assert.Equal(t, []string{"Round The Corner", "Far Away", "Unplaced"}, names,
+		"open sisters nearest first, unplaced last, closed and test ones left out")
+
+	assert.Contains(t, closed.Reply, "Bistro is closed on Sat 14.06.2025 and reopens on Fri 20.06.2025")
+	assert.Contains(t, closed.Reply, "We are on vacation.")
+	assert.Contains(t, closed.Reply, "- Round The Corner, Main st. 3")
+
+	err = closures.CheckOpen(ctx, &domain.Booking{RestaurantID: "bistro", Date: time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)})
+	assert.NoError(t, err, "the restaurant is open again on the day it reopens")
+	closureRepo.AssertNumberOfCalls(t, "ListCovering", 1)
+}
+
+func TestRestaurantClosureUseCase_CreateClosure(t *testing.T) {
+	ctx := newTestContext()
+	closureRepo := new(MockRestaurantClosureRepository)
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("bistro")).Return(&domain.Restaurant{ID: "bistro", Name: "Bistro"}, nil)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("gone")).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	restaurantRepo.On("ListSisters", ctx, domain.RestaurantID("bistro"), 0, mock.Anything).Return([]*domain.Restaurant{}, nil)
+	transactor := new(stubTransactor)
+	closures := usecase.NewRestaurantClosureUseCase(closureRepo, restaurantRepo, new(MockRestaurantLocationRepository), transactor, newTestClock())
+
+	backwards := &domain.RestaurantClosure{
+		RestaurantID: "bistro",
+		StartsOn:     time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC),
+		ReopensOn:    time.Date(2025, time.June, 10, 0, 0, 0, 0, time.UTC),
+	}
+	_, err := closures.CreateClosure(ctx, backwards, false)
+	assert.ErrorIs(t, err, domain.ErrInvalidEntity)
+
+	over := &domain.RestaurantClosure{
+		RestaurantID: "bistro",
+		StartsOn:     time.Date(2025, time.May, 20, 0, 0, 0, 0, time.UTC),
+		ReopensOn:    time.Date(2025, time.June, 2, 0, 0, 0, 0, time.UTC),
+	}
+	_, err = closures.CreateClosure(ctx, over, false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidRestaurantClosure)
+
+	vacation := &domain.RestaurantClosure{
+		RestaurantID: "bistro",
+		StartsOn:     time.Date(2025, time.June, 10, 18, 30, 0, 0, time.UTC),
+		ReopensOn:    time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC),
+		Message:      "  Back soon!  ",
+	}
+	closureRepo.On("Create", ctx, vacation).Return(nil).Once()
+
+	gone := &domain.RestaurantClosure{RestaurantID: "gone", StartsOn: vacation.StartsOn, ReopensOn: vacation.ReopensOn}
+	_, err = closures.CreateClosure(ctx, gone, false)
+	assert.EqualError(t, err, common.ErrRestaurantNotFound)
+
+	reply, err := closures.CreateClosure(ctx, vacation, false)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, time.June, 10, 0, 0, 0, 0, time.UTC), vacation.StartsOn)
+	assert.Equal(t, "Back soon!", vacation.Message)
+	assert.Contains(t, reply, "Bistro is closed on Tue 10.06.2025 and reopens on Fri 20.06.2025")
+	assert.Contains(t, reply, "Back soon!")
+	assert.Equal(t, 1, transactor.committed)
+
+	preview := &domain.RestaurantClosure{RestaurantID: "bistro", StartsOn: vacation.StartsOn, ReopensOn: vacation.ReopensOn}
+	closureRepo.On("Create", ctx, preview).Return(nil).Once()
+	_, err = closures.CreateClosure(ctx, preview, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, transactor.rolledBack, "a dry run rolls the closure back")
+	closureRepo.AssertExpectations(t)
+}
+
+func TestClosedRestaurantBookingUseCase(t *testing.T) {
+	ctx := newTestContext()
+
+	closureRepo := new(MockRestaurantClosureRepository)
+	closureRepo.On("ListByRestaurant", ctx, "bistro", mock.Anything).Return([]*domain.RestaurantClosure{summerVacation("bistro")}, nil)
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("bistro")).Return(&domain.Restaurant{ID: "bistro", Name: "Bistro"}, nil)
+	restaurantRepo.On("ListSisters", ctx, domain.RestaurantID("bistro"), 0, mock.Anything).Return([]*domain.Restaurant{}, nil)
+	locationRepo := new(MockRestaurantLocationRepository)
+	locationRepo.On("GetByRestaurant", ctx, "bistro").Return(nil, errors.New(common.ErrRestaurantLocationNotFound))
+
+	bookings := new(stubBookingUseCase)
+	closed := usecase.NewClosedRestaurantBookingUseCase(bookings,
+		usecase.NewRestaurantClosureUseCase(closureRepo, restaurantRepo, locationRepo, new(stubTransactor), newTestClock()))
+
+	_, err := closed.CreateBooking(ctx, &domain.Booking{RestaurantID: "bistro", Date: time.Date(2025, time.June, 12, 0, 0, 0, 0, time.UTC)})
+	require.ErrorIs(t, err, usecase.ErrRestaurantClosed)
+	bookings.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+
+	open := &domain.Booking{RestaurantID: "bistro", Date: time.Date(2025, time.June, 9, 0, 0, 0, 0, time.UTC)}
+	bookings.On("CreateBooking", ctx, open).Return("booking1", nil)
+	id, err := closed.CreateBooking(ctx, open)
+	require.NoError(t, err)
+	assert.Equal(t, "booking1", id)
+}
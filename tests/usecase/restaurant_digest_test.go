@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDailyDigests(t *testing.T) {
+	bookingRepo := new(MockBookingRepository)
+	notificationSvc := new(MockNotificationService)
+
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	bookingRepo.On("GetActiveBetween", mock.Anything, day, day.AddDate(0, 0, 3)).Return([]*domain.Booking{
+		{ID: "b1", RestaurantID: "r1", Date: day, Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed, Occasion: domain.BookingOccasionBirthday},
+		{ID: "b2", RestaurantID: "r1", Date: day, Time: "20:00", GuestsCount: 2, Status: domain.BookingStatusPending},
+		{ID: "b3", RestaurantID: "r1", Date: nextDay, Time: "18:30", GuestsCount: 2, Status: domain.BookingStatusConfirmed, Occasion: domain.BookingOccasionAnniversary},
+		{ID: "b4", RestaurantID: "r2", Date: nextDay, Time: "13:00", GuestsCount: 6, Status: domain.BookingStatusConfirmed},
+		{ID: "b5", RestaurantID: "r3", Date: nextDay, Time: "12:00", GuestsCount: 3, Status: domain.BookingStatusPending, Occasion: domain.BookingOccasionBusiness},
+	}, nil)
+
+	uc := usecase.NewRestaurantDigestUseCase(bookingRepo, notificationSvc, 3)
+	digests, err := uc.BuildDailyDigests(newTestContext(), day.Add(8*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, digests, 2, "a restaurant without bookings today or occasions coming up gets no digest")
+
+	assert.Equal(t, "r1", digests[0].RestaurantID)
+	assert.Equal(t, day, digests[0].Date)
+	assert.Equal(t, 2, digests[0].Bookings)
+	assert.Equal(t, 6, digests[0].Guests)
+	assert.Equal(t, 1, digests[0].Pending)
+	require.Len(t, digests[0].Occasions, 2)
+	assert.Equal(t, domain.BookingOccasionBirthday, digests[0].Occasions[0].Occasion)
+	assert.Equal(t, "b3", digests[0].Occasions[1].BookingID)
+
+	assert.Equal(t, "r3", digests[1].RestaurantID)
+	assert.Zero(t, digests[1].Bookings)
+	require.Len(t, digests[1].Occasions, 1)
+}
+
+func TestSendDailyDigests(t *testing.T) {
+	bookingRepo := new(MockBookingRepository)
+	notificationSvc := new(MockNotificationService)
+
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	bookingRepo.On("GetActiveBetween", mock.Anything, day, day).Return([]*domain.Booking{
+		{ID: "b1", RestaurantID: "r1", Date: day, Time: "19:00", GuestsCount: 4, Status: domain.BookingStatusConfirmed, Occasion: domain.BookingOccasionBirthday},
+		{ID: "b2", RestaurantID: "r2", Date: day, Time: "20:00", GuestsCount: 2, Status: domain.BookingStatusPending},
+	}, nil)
+
+	notificationSvc.On("NotifyRestaurant", mock.Anything, "r1", domain.NotificationTypeDailyDigest, "Your day ahead: 2025-05-01",
+		"1 booking today, 4 guests.\nOccasions upcoming:\n- Birthday today at 19:00, 4 guests", "r1").Return(nil)
+	notificationSvc.On("NotifyRestaurant", mock.Anything, "r2", domain.NotificationTypeDailyDigest, mock.Anything, mock.Anything, "r2").
+		Return(errors.New("notification failed"))
+
+	uc := usecase.NewRestaurantDigestUseCase(bookingRepo, notificationSvc, 0)
+	sent, err := uc.SendDailyDigests(newTestContext(), day)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "a failed digest does not stop the others")
+
+	notificationSvc.AssertExpectations(t)
+}
+
+func TestFormatRestaurantDigest(t *testing.T) {
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	message := usecase.FormatRestaurantDigest(&usecase.RestaurantDigest{
+		Date:     day,
+		Bookings: 3,
+		Guests:   9,
+		Pending:  2,
+		Occasions: []usecase.UpcomingOccasion{
+			{Occasion: domain.BookingOccasionAnniversary, Date: day.AddDate(0, 0, 2), Time: "20:00", GuestsCount: 2},
+			{Occasion: domain.BookingOccasionBusiness, Date: day.AddDate(0, 0, 3), Time: "13:00", GuestsCount: 5},
+		},
+	})
+
+	assert.Equal(t, "3 bookings today, 9 guests. 2 awaiting confirmation.\n"+
+		"Occasions upcoming:\n"+
+		"- Anniversary Sat 2025-05-03 at 20:00, 2 guests\n"+
+		"- Business meal Sun 2025-05-04 at 13:00, 5 guests", message)
+
+	assert.Equal(t, "No bookings today.", usecase.FormatRestaurantDigest(&usecase.RestaurantDigest{Date: day}))
+}
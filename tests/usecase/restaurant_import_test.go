@@ -0,0 +1,131 @@
+package usecase_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importHeader = "name,address,cuisine,description,contact_email,contact_phone,working_hours\n"
+
+func TestRestaurantUseCase_ImportRestaurants(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+	transactor := new(stubTransactor)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), transactor, "RU", newTestClock())
+
+	csv := importHeader +
+		`Pasta,Main st. 1,italian,"Fresh, handmade",pasta@example.com,+7 495 100-00-00,"mon 10:00-22:00; sun closed"` + "\n" +
+		"Sushi,Second st. 2,japanese,,sushi@example.com,+7 495 200-00-00,\n"
+
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta", "").Return(true, nil).Once()
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta-2", "").Return(false, nil).Once()
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "sushi", "").Return(false, nil).Once()
+	mockRestaurantRepo.On("CreateBatch", ctx, mock.MatchedBy(func(restaurants []*domain.Restaurant) bool {
+		return len(restaurants) == 2 && restaurants[0].Slug == "pasta-2" && restaurants[1].Slug == "sushi"
+	})).
+		Run(func(args mock.Arguments) {
+			for i, r := range args.Get(1).([]*domain.Restaurant) {
+				r.ID = []string{"r1", "r2"}[i]
+			}
+		}).Return(nil).Once()
+	mockWorkingHoursRepo.On("CreateBatch", ctx, mock.MatchedBy(func(hours []*domain.WorkingHours) bool {
+		return len(hours) == 2 &&
+			hours[0].RestaurantID == "r1" && hours[0].WeekDay == domain.Monday && hours[0].OpenTime == "10:00" &&
+			hours[1].WeekDay == domain.Sunday && hours[1].IsClosed
+	})).Return(nil).Once()
+
+	report, err := useCase.ImportRestaurants(ctx, strings.NewReader(csv), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, []string{"r1", "r2"}, report.RestaurantIDs)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, 1, transactor.committed)
+	mockRestaurantRepo.AssertExpectations(t)
+	mockWorkingHoursRepo.AssertExpectations(t)
+}
+
+func TestRestaurantUseCase_ImportRestaurantsRowErrors(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	csv := importHeader +
+		"Pasta,Main st. 1,italian,,pasta@example.com,+7 495 100-00-00,mon 10:00-22:00\n" +
+		",Second st. 2,japanese,,not-an-email,+7 495 200-00,\n" +
+		"Grill,Third st. 3,steak,,grill@example.com,+7 495 300-00-00,fri 23:00-01:00\n"
+
+	report, err := useCase.ImportRestaurants(ctx, strings.NewReader(csv), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 0, report.Imported)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Row)
+	assert.ElementsMatch(t, []string{"name is required", "contact_email is not a valid email address", "contact_phone is not a valid phone number"}, report.Errors[0].Errors)
+	assert.Equal(t, 4, report.Errors[1].Row)
+	mockRestaurantRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestRestaurantUseCase_ImportRestaurantsDryRun(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+	transactor := new(stubTransactor)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), transactor, "RU", newTestClock())
+
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta", "").Return(false, nil)
+	mockRestaurantRepo.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+	mockWorkingHoursRepo.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+	report, err := useCase.ImportRestaurants(ctx, strings.NewReader(importHeader+"Pasta,Main st. 1,italian,,pasta@example.com,+7 495 100-00-00,\n"), true)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, 0, transactor.committed)
+	assert.Equal(t, 1, transactor.rolledBack)
+}
+
+func TestRestaurantUseCase_ImportRestaurantsInvalidFile(t *testing.T) {
+	ctx := newTestContext()
+	useCase := usecase.NewRestaurantUseCase(new(MockRestaurantRepository), new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	_, err := useCase.ImportRestaurants(ctx, strings.NewReader("name,address\nPasta,Main st. 1\n"), false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImportFile)
+
+	_, err = useCase.ImportRestaurants(ctx, strings.NewReader(""), false)
+	assert.ErrorIs(t, err, usecase.ErrInvalidImportFile)
+}
+
+func TestRestaurantUseCase_ImportRestaurantsRepositoryError(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	transactor := new(stubTransactor)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), transactor, "RU", newTestClock())
+
+	expectedErr := errors.New("database error")
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta", "").Return(false, nil)
+	mockRestaurantRepo.On("CreateBatch", ctx, mock.Anything).Return(expectedErr).Once()
+
+	report, err := useCase.ImportRestaurants(ctx, strings.NewReader(importHeader+"Pasta,Main st. 1,italian,,pasta@example.com,+7 495 100-00-00,\n"), false)
+
+	assert.Equal(t, expectedErr, err)
+	assert.Nil(t, report)
+	assert.Equal(t, 1, transactor.rolledBack)
+}
@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func createTestRestaurant() *domain.Restaurant {
@@ -23,8 +25,8 @@ func createTestRestaurant() *domain.Restaurant {
 		Description:  "test restaurant description",
 		ContactEmail: "test@restaurant.com",
 		ContactPhone: "+7 (123) 456-78-90",
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CreatedAt:    testNow,
+		UpdatedAt:    testNow,
 	}
 }
 
@@ -32,7 +34,7 @@ type MockWorkingHoursRepository struct {
 	mock.Mock
 }
 
-func (m *MockWorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID string) ([]*domain.WorkingHours, error) {
+func (m *MockWorkingHoursRepository) GetByRestaurantID(ctx context.Context, restaurantID domain.RestaurantID) ([]*domain.WorkingHours, error) {
 	args := m.Called(ctx, restaurantID)
 	return args.Get(0).([]*domain.WorkingHours), args.Error(1)
 }
@@ -45,26 +47,53 @@ func (m *MockWorkingHoursRepository) SetWorkingHours(ctx context.Context, hours
 	return args.Error(0)
 }
 
+func (m *MockWorkingHoursRepository) CreateBatch(ctx context.Context, hours []*domain.WorkingHours) error {
+	args := m.Called(ctx, hours)
+	return args.Error(0)
+}
+
 func (m *MockWorkingHoursRepository) DeleteWorkingHours(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+type MockSpecialDayRepository struct {
+	mock.Mock
+}
+
+func (m *MockSpecialDayRepository) Set(ctx context.Context, day *domain.SpecialDay) error {
+	args := m.Called(ctx, day)
+	return args.Error(0)
+}
+
+func (m *MockSpecialDayRepository) ListByRestaurant(ctx context.Context, restaurantID string, from time.Time) ([]*domain.SpecialDay, error) {
+	args := m.Called(ctx, restaurantID, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SpecialDay), args.Error(1)
+}
+
+func (m *MockSpecialDayRepository) Delete(ctx context.Context, restaurantID string, date time.Time) error {
+	args := m.Called(ctx, restaurantID, date)
+	return args.Error(0)
+}
+
 func TestRestaurantUseCase_GetRestaurant(t *testing.T) {
 
 	ctx := newTestContext()
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	expectedRestaurant := createTestRestaurant()
 	expectedRestaurant.ID = restaurantID
 
-	mockRestaurantRepo.On("GetByID", ctx, restaurantID).Return(expectedRestaurant, nil)
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurantID)).Return(expectedRestaurant, nil)
 
-	result, err := useCase.GetRestaurant(ctx, restaurantID)
+	result, err := useCase.GetRestaurant(ctx, domain.RestaurantID(restaurantID))
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRestaurant, result)
@@ -77,14 +106,14 @@ func TestRestaurantUseCase_GetRestaurantNotFound(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	expectedError := errors.New("restaurant not found")
 
-	mockRestaurantRepo.On("GetByID", ctx, restaurantID).Return(nil, expectedError)
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurantID)).Return(nil, expectedError)
 
-	result, err := useCase.GetRestaurant(ctx, restaurantID)
+	result, err := useCase.GetRestaurant(ctx, domain.RestaurantID(restaurantID))
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -98,31 +127,134 @@ func TestRestaurantUseCase_ListRestaurants(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
-	offset, limit := 0, 10
-	expectedRestaurants := []*domain.Restaurant{
-		createTestRestaurant(),
-		createTestRestaurant(),
+	restaurants := []*domain.Restaurant{
+		{ID: "rest1", Name: "Aragvi"},
+		{ID: "rest2", Name: "Bistro"},
+		{ID: "rest3", Name: "Cafe Pushkin"},
 	}
+	filter := domain.RestaurantFilter{CityID: "city-1"}
+
+	mockRestaurantRepo.On("ListAfter", ctx, filter, domain.RestaurantCursor{}, 3).Return(restaurants, nil)
+	mockRestaurantRepo.On("ListAfter", ctx, filter, domain.RestaurantCursor{Name: "Bistro", ID: "rest2"}, 3).Return(restaurants[2:], nil)
+
+	first, err := useCase.ListRestaurants(ctx, filter, usecase.PageRequest{Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, restaurants[:2], first.Items)
+	require.True(t, first.HasMore())
+
+	second, err := useCase.ListRestaurants(ctx, filter, usecase.PageRequest{Cursor: first.NextCursor, Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, restaurants[2:], second.Items)
+	assert.False(t, second.HasMore())
+	mockRestaurantRepo.AssertExpectations(t)
+}
+
+func TestRestaurantUseCase_ListRestaurantsInvalidCursor(t *testing.T) {
+
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	mockRestaurantRepo.On("List", ctx, offset, limit).Return(expectedRestaurants, nil)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
-	result, err := useCase.ListRestaurants(ctx, offset, limit)
+	result, err := useCase.ListRestaurants(ctx, domain.RestaurantFilter{}, usecase.PageRequest{Cursor: "MTA"})
+
+	assert.ErrorIs(t, err, usecase.ErrInvalidPageCursor, "an offset is no cursor")
+	assert.Nil(t, result)
+	mockRestaurantRepo.AssertNotCalled(t, "ListAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRestaurantUseCase_SearchRestaurants(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	expectedRestaurants := []*domain.Restaurant{createTestRestaurant()}
+	mockRestaurantRepo.On("Search", ctx, domain.RestaurantFilter{Query: "fresh pasta", Cuisine: "Italian"}, 0, 10).Return(expectedRestaurants, nil)
+
+	result, err := useCase.SearchRestaurants(ctx, domain.RestaurantFilter{Query: "  fresh pasta ", Cuisine: "Italian"}, 0, 10)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRestaurants, result)
-	assert.Len(t, result, 2)
+
+	_, err = useCase.SearchRestaurants(ctx, domain.RestaurantFilter{Cuisine: "martian"}, 0, 10)
+	assert.ErrorIs(t, err, domain.ErrInvalidEntity)
 	mockRestaurantRepo.AssertExpectations(t)
 }
 
+func TestRestaurantUseCase_GetSchedule(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
+	mockClosureRepo := new(MockRestaurantClosureRepository)
+	mockSpecialDayRepo := new(MockSpecialDayRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, mockClosureRepo, mockSpecialDayRepo, new(stubTransactor), "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	mockRestaurantRepo.On("GetByID", ctx, domain.RestaurantID(restaurant.ID)).Return(restaurant, nil)
+	mockWorkingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurant.ID)).Return([]*domain.WorkingHours{
+		{WeekDay: domain.Monday, OpenTime: "10:00", CloseTime: "22:00"},
+		{WeekDay: domain.Monday, OpenTime: "18:00", CloseTime: "02:00",
+			ValidFrom: time.Date(2025, time.June, 9, 0, 0, 0, 0, time.UTC), ValidTo: time.Date(2025, time.June, 15, 0, 0, 0, 0, time.UTC)},
+		{WeekDay: domain.Tuesday, IsClosed: true},
+		{WeekDay: domain.Friday, OpenTime: "18:00", CloseTime: "23:00"},
+		{WeekDay: domain.Friday, OpenTime: "12:00", CloseTime: "15:00"},
+	}, nil)
+
+	day := func(d, hour int) time.Time { return time.Date(2025, time.June, d, hour, 0, 0, 0, time.UTC) }
+	mockSpecialDayRepo.On("ListByRestaurant", ctx, restaurant.ID, day(2, 0)).Return([]*domain.SpecialDay{}, nil)
+	mockClosureRepo.On("ListByRestaurant", ctx, restaurant.ID, day(2, 0)).Return([]*domain.RestaurantClosure{}, nil)
+	schedule, err := useCase.GetSchedule(ctx, domain.RestaurantID(restaurant.ID), day(2, 0), day(9, 0))
+
+	assert.NoError(t, err)
+	assert.Len(t, schedule, 8)
+	assert.Equal(t, []domain.OpeningHours{{OpensAt: day(2, 10), ClosesAt: day(2, 22)}}, schedule[0].Hours)
+	assert.True(t, schedule[1].IsClosed(), "Tuesday is closed")
+	assert.True(t, schedule[6].IsClosed(), "a day without hours is closed")
+	assert.Equal(t, []domain.OpeningHours{
+		{OpensAt: day(6, 12), ClosesAt: day(6, 15)},
+		{OpensAt: day(6, 18), ClosesAt: day(6, 23)},
+	}, schedule[4].Hours, "earliest opening first")
+	assert.Equal(t, []domain.OpeningHours{
+		{OpensAt: day(9, 10), ClosesAt: day(9, 22)},
+		{OpensAt: day(9, 18), ClosesAt: day(10, 2)},
+	}, schedule[7].Hours, "hours valid from the date apply and close after midnight")
+
+	closure := &domain.RestaurantClosure{ID: "closure1", RestaurantID: restaurant.ID, StartsOn: day(6, 0), ReopensOn: day(7, 0)}
+	holiday := &domain.SpecialDay{RestaurantID: restaurant.ID, Date: day(6, 0), OpenTime: "09:00", CloseTime: "12:00"}
+	lateNight := &domain.SpecialDay{RestaurantID: restaurant.ID, Date: day(9, 0), OpenTime: "20:00", CloseTime: "04:00"}
+	dayOff := &domain.SpecialDay{RestaurantID: restaurant.ID, Date: day(13, 0), IsClosed: true, Note: "Staff party"}
+	mockSpecialDayRepo.On("ListByRestaurant", ctx, restaurant.ID, day(6, 0)).Return([]*domain.SpecialDay{holiday, lateNight, dayOff}, nil)
+	mockClosureRepo.On("ListByRestaurant", ctx, restaurant.ID, day(6, 0)).Return([]*domain.RestaurantClosure{closure}, nil)
+	schedule, err = useCase.GetSchedule(ctx, domain.RestaurantID(restaurant.ID), day(6, 0), day(13, 0))
+	assert.NoError(t, err)
+	assert.True(t, schedule[0].IsClosed(), "a closure closes the day whatever its working hours and special day")
+	assert.Equal(t, closure, schedule[0].Closure)
+	assert.Nil(t, schedule[0].SpecialDay)
+	assert.Nil(t, schedule[1].Closure, "the restaurant reopens on the day after")
+	assert.Equal(t, []domain.OpeningHours{{OpensAt: day(9, 20), ClosesAt: day(10, 4)}}, schedule[3].Hours,
+		"a special day replaces the working hours of the weekday")
+	assert.Equal(t, lateNight, schedule[3].SpecialDay)
+	assert.True(t, schedule[7].IsClosed(), "the restaurant is closed on a closed special day")
+	assert.Equal(t, dayOff, schedule[7].SpecialDay)
+
+	_, err = useCase.GetSchedule(ctx, domain.RestaurantID(restaurant.ID), day(9, 0), day(2, 0))
+	assert.ErrorIs(t, err, usecase.ErrInvalidDateRange)
+}
+
 func TestRestaurantUseCase_CreateRestaurant(t *testing.T) {
 
 	ctx := newTestContext()
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	newRestaurant := &domain.Restaurant{
 		Name:         "new restaurant",
@@ -135,6 +267,7 @@ func TestRestaurantUseCase_CreateRestaurant(t *testing.T) {
 
 	expectedID := "test-restaurant-id"
 
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "new-restaurant", "").Return(false, nil)
 	mockRestaurantRepo.On("Create", ctx, mock.AnythingOfType("*domain.Restaurant")).Run(func(args mock.Arguments) {
 		restaurant := args.Get(1).(*domain.Restaurant)
 		restaurant.ID = expectedID
@@ -146,6 +279,10 @@ func TestRestaurantUseCase_CreateRestaurant(t *testing.T) {
 	assert.NotEmpty(t, id)
 	assert.Equal(t, expectedID, id)
 	assert.Equal(t, expectedID, newRestaurant.ID)
+	assert.Equal(t, "new-restaurant", newRestaurant.Slug)
+	assert.Equal(t, domain.DefaultCurrency, newRestaurant.Currency)
+	assert.Equal(t, "new@restaurant.com", newRestaurant.NormalizedContactEmail)
+	assert.Equal(t, "+79876543210", newRestaurant.NormalizedContactPhone)
 	assert.False(t, newRestaurant.CreatedAt.IsZero())
 	assert.False(t, newRestaurant.UpdatedAt.IsZero())
 	mockRestaurantRepo.AssertExpectations(t)
@@ -157,12 +294,13 @@ func TestRestaurantUseCase_UpdateRestaurant(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	clk := newTestClock()
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", clk)
 
 	restaurant := createTestRestaurant()
 	oldUpdateTime := restaurant.UpdatedAt
 
-	time.Sleep(1 * time.Millisecond)
+	clk.Advance(time.Minute)
 
 	mockRestaurantRepo.On("Update", ctx, mock.AnythingOfType("*domain.Restaurant")).Return(nil)
 
@@ -170,22 +308,143 @@ func TestRestaurantUseCase_UpdateRestaurant(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.True(t, restaurant.UpdatedAt.After(oldUpdateTime))
+	assert.Equal(t, clk.Now(), restaurant.UpdatedAt)
 	mockRestaurantRepo.AssertExpectations(t)
 }
 
+func TestRestaurantUseCase_UpdateRestaurantInvalidContacts(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	restaurant.ContactPhone = "+7 (987) 654-32"
+	assert.ErrorIs(t, useCase.UpdateRestaurant(ctx, restaurant), contact.ErrInvalidPhone)
+
+	restaurant = createTestRestaurant()
+	restaurant.ContactEmail = "info@restaurant"
+	assert.ErrorIs(t, useCase.UpdateRestaurant(ctx, restaurant), contact.ErrInvalidEmail)
+
+	mockRestaurantRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRestaurantUseCase_CreateRestaurantSlug(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("invalid slug is rejected", func(t *testing.T) {
+		mockRestaurantRepo := new(MockRestaurantRepository)
+		useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+		_, err := useCase.CreateRestaurant(ctx, &domain.Restaurant{Name: "Pasta", Slug: "Pasta Place"})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidRestaurantSlug)
+		mockRestaurantRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("taken slug is rejected", func(t *testing.T) {
+		mockRestaurantRepo := new(MockRestaurantRepository)
+		useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+		mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta", "").Return(true, nil)
+
+		_, err := useCase.CreateRestaurant(ctx, &domain.Restaurant{Name: "Pasta", Slug: "pasta"})
+
+		assert.ErrorIs(t, err, usecase.ErrRestaurantSlugTaken)
+		mockRestaurantRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("generated slug is numbered when taken", func(t *testing.T) {
+		mockRestaurantRepo := new(MockRestaurantRepository)
+		useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+		mockRestaurantRepo.On("IsSlugTaken", ctx, "pelmennaya", "").Return(true, nil)
+		mockRestaurantRepo.On("IsSlugTaken", ctx, "pelmennaya-2", "").Return(true, nil)
+		mockRestaurantRepo.On("IsSlugTaken", ctx, "pelmennaya-3", "").Return(false, nil)
+		mockRestaurantRepo.On("Create", ctx, mock.MatchedBy(func(r *domain.Restaurant) bool {
+			return r.Slug == "pelmennaya-3"
+		})).Return(nil)
+
+		_, err := useCase.CreateRestaurant(ctx, &domain.Restaurant{Name: "Пельменная"})
+
+		assert.NoError(t, err)
+		mockRestaurantRepo.AssertExpectations(t)
+	})
+}
+
+func TestRestaurantUseCase_RestaurantCurrency(t *testing.T) {
+	ctx := newTestContext()
+
+	t.Run("currency code is upper-cased", func(t *testing.T) {
+		mockRestaurantRepo := new(MockRestaurantRepository)
+		useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+		restaurant := createTestRestaurant()
+		restaurant.Currency = "eur"
+		mockRestaurantRepo.On("Update", ctx, mock.MatchedBy(func(r *domain.Restaurant) bool {
+			return r.Currency == "EUR"
+		})).Return(nil)
+
+		assert.NoError(t, useCase.UpdateRestaurant(ctx, restaurant))
+		mockRestaurantRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid currency is rejected", func(t *testing.T) {
+		mockRestaurantRepo := new(MockRestaurantRepository)
+		useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+		_, err := useCase.CreateRestaurant(ctx, &domain.Restaurant{Name: "Pasta", Currency: "euro"})
+
+		assert.ErrorIs(t, err, usecase.ErrInvalidCurrency)
+		mockRestaurantRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestaurantUseCase_UpdateRestaurantSlugTaken(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	restaurant.Slug = "pasta"
+	mockRestaurantRepo.On("IsSlugTaken", ctx, "pasta", restaurant.ID).Return(true, nil)
+
+	err := useCase.UpdateRestaurant(ctx, restaurant)
+
+	assert.ErrorIs(t, err, usecase.ErrRestaurantSlugTaken)
+	mockRestaurantRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestRestaurantUseCase_GetRestaurantBySlug(t *testing.T) {
+	ctx := newTestContext()
+	mockRestaurantRepo := new(MockRestaurantRepository)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, new(MockWorkingHoursRepository), new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
+
+	restaurant := createTestRestaurant()
+	restaurant.Slug = "test-restaurant"
+	mockRestaurantRepo.On("GetBySlug", ctx, "old-name").Return(restaurant, nil)
+
+	result, err := useCase.GetRestaurantBySlug(ctx, "old-name")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-restaurant", result.Slug)
+
+	_, err = useCase.GetRestaurantBySlug(ctx, "../etc")
+	assert.ErrorIs(t, err, usecase.ErrInvalidRestaurantSlug)
+}
+
 func TestRestaurantUseCase_DeleteRestaurant(t *testing.T) {
 
 	ctx := newTestContext()
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 
-	mockRestaurantRepo.On("Delete", ctx, restaurantID).Return(nil)
+	mockRestaurantRepo.On("Delete", ctx, domain.RestaurantID(restaurantID)).Return(nil)
 
-	err := useCase.DeleteRestaurant(ctx, restaurantID)
+	err := useCase.DeleteRestaurant(ctx, domain.RestaurantID(restaurantID))
 
 	assert.NoError(t, err)
 	mockRestaurantRepo.AssertExpectations(t)
@@ -197,7 +456,7 @@ func TestRestaurantUseCase_AddFact(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	factContent := "interesting fact about the restaurant"
@@ -205,12 +464,12 @@ func TestRestaurantUseCase_AddFact(t *testing.T) {
 		ID:           uuid.New().String(),
 		RestaurantID: restaurantID,
 		Content:      factContent,
-		CreatedAt:    time.Now(),
+		CreatedAt:    testNow,
 	}
 
-	mockRestaurantRepo.On("AddFact", ctx, restaurantID, mock.AnythingOfType("domain.Fact")).Return(expectedFact, nil)
+	mockRestaurantRepo.On("AddFact", ctx, domain.RestaurantID(restaurantID), mock.AnythingOfType("domain.Fact")).Return(expectedFact, nil)
 
-	fact, err := useCase.AddFact(ctx, restaurantID, factContent)
+	fact, err := useCase.AddFact(ctx, domain.RestaurantID(restaurantID), factContent, "")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFact, fact)
@@ -224,7 +483,7 @@ func TestRestaurantUseCase_GetFacts(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	expectedFacts := []domain.Fact{
@@ -232,19 +491,19 @@ func TestRestaurantUseCase_GetFacts(t *testing.T) {
 			ID:           uuid.New().String(),
 			RestaurantID: restaurantID,
 			Content:      "fact 1",
-			CreatedAt:    time.Now(),
+			CreatedAt:    testNow,
 		},
 		{
 			ID:           uuid.New().String(),
 			RestaurantID: restaurantID,
 			Content:      "fact 2",
-			CreatedAt:    time.Now(),
+			CreatedAt:    testNow,
 		},
 	}
 
-	mockRestaurantRepo.On("GetFacts", ctx, restaurantID).Return(expectedFacts, nil)
+	mockRestaurantRepo.On("GetFacts", ctx, domain.RestaurantID(restaurantID)).Return(expectedFacts, nil)
 
-	result, err := useCase.GetFacts(ctx, restaurantID)
+	result, err := useCase.GetFacts(ctx, domain.RestaurantID(restaurantID))
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedFacts, result)
@@ -258,7 +517,7 @@ func TestRestaurantUseCase_GetRandomFacts(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	count := 3
 	expectedFacts := []domain.Fact{
@@ -266,19 +525,19 @@ func TestRestaurantUseCase_GetRandomFacts(t *testing.T) {
 			ID:           uuid.New().String(),
 			RestaurantID: uuid.New().String(),
 			Content:      "random fact 1",
-			CreatedAt:    time.Now(),
+			CreatedAt:    testNow,
 		},
 		{
 			ID:           uuid.New().String(),
 			RestaurantID: uuid.New().String(),
 			Content:      "random fact 2",
-			CreatedAt:    time.Now(),
+			CreatedAt:    testNow,
 		},
 		{
 			ID:           uuid.New().String(),
 			RestaurantID: uuid.New().String(),
 			Content:      "random fact 3",
-			CreatedAt:    time.Now(),
+			CreatedAt:    testNow,
 		},
 	}
 
@@ -298,20 +557,20 @@ func TestRestaurantUseCase_SetWorkingHours(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	workingHours := &domain.WorkingHours{
 		WeekDay:   domain.Monday,
 		OpenTime:  "09:00",
 		CloseTime: "21:00",
-		ValidFrom: time.Now(),
-		ValidTo:   time.Now().AddDate(0, 3, 0),
+		ValidFrom: testNow,
+		ValidTo:   testNow.AddDate(0, 3, 0),
 	}
 
 	mockWorkingHoursRepo.On("SetWorkingHours", ctx, mock.AnythingOfType("*domain.WorkingHours")).Return(nil)
 
-	err := useCase.SetWorkingHours(ctx, restaurantID, workingHours)
+	err := useCase.SetWorkingHours(ctx, domain.RestaurantID(restaurantID), workingHours)
 
 	assert.NoError(t, err)
 	assert.Equal(t, restaurantID, workingHours.RestaurantID)
@@ -324,7 +583,7 @@ func TestRestaurantUseCase_GetWorkingHours(t *testing.T) {
 	mockRestaurantRepo := new(MockRestaurantRepository)
 	mockWorkingHoursRepo := new(MockWorkingHoursRepository)
 
-	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo)
+	useCase := usecase.NewRestaurantUseCase(mockRestaurantRepo, mockWorkingHoursRepo, new(MockRestaurantClosureRepository), new(MockSpecialDayRepository), new(stubTransactor), "RU", newTestClock())
 
 	restaurantID := uuid.New().String()
 	expectedWorkingHours := []*domain.WorkingHours{
@@ -334,8 +593,8 @@ func TestRestaurantUseCase_GetWorkingHours(t *testing.T) {
 			WeekDay:      domain.Monday,
 			OpenTime:     "09:00",
 			CloseTime:    "21:00",
-			ValidFrom:    time.Now(),
-			ValidTo:      time.Now().AddDate(0, 3, 0),
+			ValidFrom:    testNow,
+			ValidTo:      testNow.AddDate(0, 3, 0),
 		},
 		{
 			ID:           uuid.New().String(),
@@ -343,14 +602,14 @@ func TestRestaurantUseCase_GetWorkingHours(t *testing.T) {
 			WeekDay:      domain.Tuesday,
 			OpenTime:     "10:00",
 			CloseTime:    "22:00",
-			ValidFrom:    time.Now(),
-			ValidTo:      time.Now().AddDate(0, 3, 0),
+			ValidFrom:    testNow,
+			ValidTo:      testNow.AddDate(0, 3, 0),
 		},
 	}
 
-	mockWorkingHoursRepo.On("GetByRestaurantID", ctx, restaurantID).Return(expectedWorkingHours, nil)
+	mockWorkingHoursRepo.On("GetByRestaurantID", ctx, domain.RestaurantID(restaurantID)).Return(expectedWorkingHours, nil)
 
-	result, err := useCase.GetWorkingHours(ctx, restaurantID)
+	result, err := useCase.GetWorkingHours(ctx, domain.RestaurantID(restaurantID))
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedWorkingHours, result)
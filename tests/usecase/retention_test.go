@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRetentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRetentionRepository) PurgeReadNotifications(ctx context.Context, readBefore time.Time) (int, error) {
+	args := m.Called(ctx, readBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRetentionRepository) AnonymizeCompletedBookings(ctx context.Context, completedBefore time.Time) (int, error) {
+	args := m.Called(ctx, completedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRetentionRepository) CreateRun(ctx context.Context, run *domain.RetentionRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockRetentionRepository) ListRuns(ctx context.Context, offset, limit int) ([]*domain.RetentionRun, error) {
+	args := m.Called(ctx, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RetentionRun), args.Error(1)
+}
+
+var testRetentionPolicies = usecase.RetentionPolicies{
+	ReadNotifications: 90 * 24 * time.Hour,
+	CompletedBookings: 2 * 365 * 24 * time.Hour,
+}
+
+func TestApplyRetention(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockRetentionRepository)
+	transactor := new(stubTransactor)
+	retention := usecase.NewRetentionUseCase(repo, transactor, testRetentionPolicies, newTestClock())
+
+	repo.On("PurgeReadNotifications", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return cutoff.Equal(testNow.Add(-testRetentionPolicies.ReadNotifications))
+	})).Return(12, nil)
+	repo.On("AnonymizeCompletedBookings", mock.Anything, mock.Anything).Return(3, nil)
+	repo.On("CreateRun", mock.Anything, mock.Anything).Return(nil)
+
+	runs, err := retention.ApplyRetention(ctx, false)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, domain.RetentionPurgeReadNotifications, runs[0].Policy)
+	assert.Equal(t, 12, runs[0].Affected)
+	assert.Equal(t, domain.RetentionAnonymizeCompletedBookings, runs[1].Policy)
+	assert.Equal(t, 3, runs[1].Affected)
+	assert.False(t, runs[1].DryRun)
+	assert.Equal(t, 2, transactor.committed)
+	repo.AssertNumberOfCalls(t, "CreateRun", 2)
+}
+
+func TestApplyRetention_DryRunIsRolledBackAndAudited(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockRetentionRepository)
+	transactor := new(stubTransactor)
+	retention := usecase.NewRetentionUseCase(repo, transactor, usecase.RetentionPolicies{
+		ReadNotifications: testRetentionPolicies.ReadNotifications,
+	}, newTestClock())
+
+	repo.On("PurgeReadNotifications", mock.Anything, mock.Anything).Return(12, nil)
+	repo.On("CreateRun", mock.Anything, mock.MatchedBy(func(run *domain.RetentionRun) bool {
+		return run.DryRun && run.Affected == 12
+	})).Return(nil)
+
+	runs, err := retention.ApplyRetention(ctx, true)
+
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, 1, transactor.rolledBack)
+	assert.Zero(t, transactor.committed)
+	repo.AssertNotCalled(t, "AnonymizeCompletedBookings", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestApplyRetention_FailedPolicyDoesNotStopOthers(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockRetentionRepository)
+	retention := usecase.NewRetentionUseCase(repo, new(stubTransactor), testRetentionPolicies, newTestClock())
+
+	purgeErr := errors.New("connection lost")
+	repo.On("PurgeReadNotifications", mock.Anything, mock.Anything).Return(0, purgeErr)
+	repo.On("AnonymizeCompletedBookings", mock.Anything, mock.Anything).Return(3, nil)
+	repo.On("CreateRun", mock.Anything, mock.Anything).Return(nil)
+
+	runs, err := retention.ApplyRetention(ctx, false)
+
+	assert.ErrorIs(t, err, purgeErr)
+	require.Len(t, runs, 2)
+	assert.Equal(t, purgeErr.Error(), runs[0].Error)
+	assert.Equal(t, 3, runs[1].Affected)
+}
+
+func TestRetention_AdminsOnly(t *testing.T) {
+	repo := new(MockRetentionRepository)
+	retention := usecase.NewRetentionUseCase(repo, new(stubTransactor), testRetentionPolicies, newTestClock())
+	ctx := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1"})
+
+	_, err := retention.ApplyRetention(ctx, true)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	_, err = retention.ListRetentionRuns(ctx, 0, 20)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	repo.On("ListRuns", mock.Anything, 0, 20).Return([]*domain.RetentionRun{{ID: "run1"}}, nil)
+	runs, err := retention.ListRetentionRuns(adminContext(), 0, 20)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+	repo.AssertNotCalled(t, "PurgeReadNotifications", mock.Anything, mock.Anything)
+}
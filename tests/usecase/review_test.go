@@ -0,0 +1,311 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListByRestaurant(ctx context.Context, restaurantID string, offset, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, restaurantID, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) UpdateStatus(ctx context.Context, id string, status domain.ReviewStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) SaveReply(ctx context.Context, reply *domain.ReviewReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) CreateFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	args := m.Called(ctx, flag)
+	return args.Error(0)
+}
+
+func (m *MockReviewRepository) GetFlag(ctx context.Context, id string) (*domain.ReviewFlag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListFlags(ctx context.Context, status domain.ReviewFlagStatus, offset, limit int) ([]*domain.ReviewFlag, error) {
+	args := m.Called(ctx, status, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ReviewFlag), args.Error(1)
+}
+
+func (m *MockReviewRepository) ResolveFlag(ctx context.Context, flag *domain.ReviewFlag) error {
+	args := m.Called(ctx, flag)
+	return args.Error(0)
+}
+
+func staffContext() context.Context {
+	return tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "staff1", RestaurantIDs: []string{"restaurant1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+}
+
+func publishedReview() *domain.Review {
+	return &domain.Review{
+		ID:           "review1",
+		RestaurantID: "restaurant1",
+		UserID:       "user1",
+		BookingID:    "booking1",
+		Rating:       2,
+		Text:         "Cold soup",
+		Status:       domain.ReviewStatusPublished,
+	}
+}
+
+func TestCreateReview(t *testing.T) {
+	guest := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1", Roles: []tenant.Role{tenant.RoleUser}})
+
+	t.Run("completed booking", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		bookingRepo := new(MockBookingRepository)
+		notifier := new(MockNotificationService)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), bookingRepo, notifier, new(stubTransactor))
+
+		bookingRepo.On("GetByID", guest, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1", Status: domain.BookingStatusCompleted}, nil)
+		reviewRepo.On("Create", guest, mock.MatchedBy(func(review *domain.Review) bool {
+			return review.RestaurantID == "restaurant1" && review.Rating == 4 && review.Text == "Lovely" && review.Status == domain.ReviewStatusPublished
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Review).ID = "review1"
+		}).Return(nil)
+		notifier.On("NotifyRestaurant", guest, "restaurant1", domain.NotificationTypeNewReview, mock.Anything, mock.Anything, "review1").Return(nil)
+
+		review, err := reviews.CreateReview(guest, "booking1", 4, " Lovely ")
+
+		require.NoError(t, err)
+		assert.Equal(t, "review1", review.ID)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("booking not completed", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		bookingRepo := new(MockBookingRepository)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), bookingRepo, new(MockNotificationService), new(stubTransactor))
+
+		bookingRepo.On("GetByID", guest, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1", UserID: "user1", Status: domain.BookingStatusConfirmed}, nil)
+
+		_, err := reviews.CreateReview(guest, "booking1", 4, "")
+
+		assert.ErrorIs(t, err, usecase.ErrReviewNotAllowed)
+		reviewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("staff cannot review", func(t *testing.T) {
+		bookingRepo := new(MockBookingRepository)
+		reviews := usecase.NewReviewUseCase(new(MockReviewRepository), new(MockRestaurantRepository), bookingRepo, new(MockNotificationService), new(stubTransactor))
+
+		staff := staffContext()
+		bookingRepo.On("GetByID", staff, domain.BookingID("booking1")).Return(&domain.Booking{ID: "booking1", RestaurantID: "restaurant1", UserID: "user1", Status: domain.BookingStatusCompleted}, nil)
+
+		_, err := reviews.CreateReview(staff, "booking1", 5, "")
+
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	})
+
+	t.Run("invalid rating", func(t *testing.T) {
+		reviews := usecase.NewReviewUseCase(new(MockReviewRepository), new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		for _, rating := range []int{0, 6} {
+			_, err := reviews.CreateReview(guest, "booking1", rating, "")
+			assert.ErrorIs(t, err, usecase.ErrInvalidReview, rating)
+		}
+	})
+}
+
+func TestReplyToReview(t *testing.T) {
+	staff := staffContext()
+
+	t.Run("first reply notifies the guest", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		notifier := new(MockNotificationService)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), notifier, new(stubTransactor))
+
+		reviewRepo.On("GetByID", staff, "review1").Return(publishedReview(), nil)
+		reviewRepo.On("SaveReply", staff, &domain.ReviewReply{ReviewID: "review1", Text: "Sorry about that"}).Return(nil)
+		notifier.On("NotifyUser", staff, "user1", domain.NotificationTypeReviewReply, mock.Anything, "Sorry about that", "review1").Return(nil)
+
+		review, err := reviews.ReplyToReview(staff, "restaurant1", "review1", " Sorry about that ")
+
+		require.NoError(t, err)
+		require.NotNil(t, review.Reply)
+		assert.Equal(t, "Sorry about that", review.Reply.Text)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("edited reply is not announced", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		notifier := new(MockNotificationService)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), notifier, new(stubTransactor))
+
+		review := publishedReview()
+		review.Reply = &domain.ReviewReply{ReviewID: "review1", Text: "Sorry"}
+		reviewRepo.On("GetByID", staff, "review1").Return(review, nil)
+		reviewRepo.On("SaveReply", staff, mock.Anything).Return(nil)
+
+		_, err := reviews.ReplyToReview(staff, "restaurant1", "review1", "Sorry, the soup is fixed")
+
+		require.NoError(t, err)
+		notifier.AssertNotCalled(t, "NotifyUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("review of another restaurant", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		other := publishedReview()
+		other.RestaurantID = "restaurant2"
+		ctx := setupTestContext()
+		reviewRepo.On("GetByID", ctx, "review1").Return(other, nil)
+
+		_, err := reviews.ReplyToReview(ctx, "restaurant1", "review1", "Thanks")
+
+		require.Error(t, err)
+		assert.Equal(t, common.ErrReviewNotFound, err.Error())
+	})
+
+	t.Run("staff of another restaurant", func(t *testing.T) {
+		reviews := usecase.NewReviewUseCase(new(MockReviewRepository), new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		_, err := reviews.ReplyToReview(staff, "restaurant2", "review1", "Thanks")
+
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	})
+}
+
+func TestFlagReview(t *testing.T) {
+	staff := staffContext()
+	reviewRepo := new(MockReviewRepository)
+	reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+	reviewRepo.On("GetByID", staff, "review1").Return(publishedReview(), nil)
+	reviewRepo.On("CreateFlag", staff, mock.MatchedBy(func(flag *domain.ReviewFlag) bool {
+		return flag.Reason == "Insults the staff"
+	})).Return(nil).Once()
+	reviewRepo.On("CreateFlag", staff, mock.Anything).Return(errors.New(common.ErrReviewAlreadyFlagged))
+
+	flag, err := reviews.FlagReview(staff, "restaurant1", "review1", " Insults the staff ")
+	require.NoError(t, err)
+	assert.Equal(t, "restaurant1", flag.RestaurantID)
+	assert.Equal(t, "review1", flag.Review.ID)
+
+	_, err = reviews.FlagReview(staff, "restaurant1", "review1", "Again")
+	require.Error(t, err)
+	assert.Equal(t, common.ErrReviewAlreadyFlagged, err.Error())
+
+	_, err = reviews.FlagReview(staff, "restaurant1", "review1", "  ")
+	assert.ErrorIs(t, err, usecase.ErrInvalidReviewFlag)
+}
+
+func TestResolveReviewFlag(t *testing.T) {
+	admin := adminContext()
+
+	t.Run("upheld flag removes the review", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		notifier := new(MockNotificationService)
+		transactor := new(stubTransactor)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), notifier, transactor)
+
+		removed := publishedReview()
+		removed.Status = domain.ReviewStatusRemoved
+		reviewRepo.On("GetFlag", admin, "flag1").Return(&domain.ReviewFlag{ID: "flag1", ReviewID: "review1", RestaurantID: "restaurant1", Status: domain.ReviewFlagStatusOpen}, nil)
+		reviewRepo.On("ResolveFlag", admin, mock.MatchedBy(func(flag *domain.ReviewFlag) bool {
+			return flag.Status == domain.ReviewFlagStatusUpheld && flag.ResolutionNote == "Abusive language"
+		})).Return(nil)
+		reviewRepo.On("UpdateStatus", admin, "review1", domain.ReviewStatusRemoved).Return(nil)
+		reviewRepo.On("GetByID", admin, "review1").Return(removed, nil)
+		notifier.On("NotifyRestaurant", admin, "restaurant1", domain.NotificationTypeReviewFlagResolved, mock.Anything, mock.Anything, "review1").Return(nil)
+		notifier.On("NotifyUser", admin, "user1", domain.NotificationTypeReviewFlagResolved, mock.Anything, mock.Anything, "review1").Return(nil)
+
+		flag, err := reviews.ResolveReviewFlag(admin, "flag1", domain.ReviewFlagStatusUpheld, " Abusive language ")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReviewStatusRemoved, flag.Review.Status)
+		assert.Equal(t, 1, transactor.committed)
+		reviewRepo.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("dismissed flag keeps the review", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		notifier := new(MockNotificationService)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), notifier, new(stubTransactor))
+
+		reviewRepo.On("GetFlag", admin, "flag1").Return(&domain.ReviewFlag{ID: "flag1", ReviewID: "review1", RestaurantID: "restaurant1", Status: domain.ReviewFlagStatusOpen}, nil)
+		reviewRepo.On("ResolveFlag", admin, mock.Anything).Return(nil)
+		reviewRepo.On("GetByID", admin, "review1").Return(publishedReview(), nil)
+		notifier.On("NotifyRestaurant", admin, "restaurant1", domain.NotificationTypeReviewFlagResolved, mock.Anything, mock.Anything, "review1").Return(nil)
+
+		_, err := reviews.ResolveReviewFlag(admin, "flag1", domain.ReviewFlagStatusDismissed, "")
+
+		require.NoError(t, err)
+		reviewRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+		notifier.AssertNotCalled(t, "NotifyUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		reviewRepo := new(MockReviewRepository)
+		reviews := usecase.NewReviewUseCase(reviewRepo, new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		reviewRepo.On("GetFlag", admin, "flag1").Return(&domain.ReviewFlag{ID: "flag1", Status: domain.ReviewFlagStatusDismissed}, nil)
+
+		_, err := reviews.ResolveReviewFlag(admin, "flag1", domain.ReviewFlagStatusUpheld, "")
+
+		assert.ErrorIs(t, err, usecase.ErrReviewFlagResolved)
+	})
+
+	t.Run("admins only", func(t *testing.T) {
+		reviews := usecase.NewReviewUseCase(new(MockReviewRepository), new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		_, err := reviews.ResolveReviewFlag(staffContext(), "flag1", domain.ReviewFlagStatusUpheld, "")
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+		_, err = reviews.ListReviewFlags(staffContext(), domain.ReviewFlagStatusOpen, 0, 20)
+		assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+	})
+
+	t.Run("invalid resolution", func(t *testing.T) {
+		reviews := usecase.NewReviewUseCase(new(MockReviewRepository), new(MockRestaurantRepository), new(MockBookingRepository), new(MockNotificationService), new(stubTransactor))
+
+		_, err := reviews.ResolveReviewFlag(admin, "flag1", domain.ReviewFlagStatusOpen, "")
+		assert.ErrorIs(t, err, usecase.ErrInvalidReviewFlag)
+	})
+}
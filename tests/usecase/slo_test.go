@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSLORepository struct {
+	mock.Mock
+}
+
+func (m *MockSLORepository) AddRequestMetrics(ctx context.Context, metrics []*domain.RequestMetrics) error {
+	args := m.Called(ctx, metrics)
+	return args.Error(0)
+}
+
+func (m *MockSLORepository) SumRequestMetrics(ctx context.Context, from, to time.Time) (*domain.RequestMetrics, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RequestMetrics), args.Error(1)
+}
+
+func (m *MockSLORepository) SaveReport(ctx context.Context, report *domain.SLOReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockSLORepository) ListReports(ctx context.Context, from, to time.Time) ([]*domain.SLOReport, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SLOReport), args.Error(1)
+}
+
+var sloSettings = usecase.SLOSettings{
+	Objectives:   domain.SLOObjectives{AvailabilityTarget: 0.99, LatencyTarget: 0.9, LatencyThreshold: 300 * time.Millisecond},
+	FastBurnRate: 10,
+	MinRequests:  100,
+	AlertUserIDs: []string{"admin1"},
+}
+
+func TestSLOUseCase_FlushMetrics(t *testing.T) {
+	ctx := newTestContext()
+	sloRepo := new(MockSLORepository)
+	useCase := usecase.NewSLOUseCase(sloRepo, new(MockNotificationService), newTestClock(), sloSettings)
+
+	hour := time.Date(2026, 5, 10, 18, 0, 0, 0, time.UTC)
+	useCase.ObserveRequest(hour.Add(5*time.Minute), 200, 100*time.Millisecond)
+	useCase.ObserveRequest(hour.Add(10*time.Minute), 503, 50*time.Millisecond)
+	useCase.ObserveRequest(hour.Add(15*time.Minute), 404, time.Second)
+
+	sloRepo.On("AddRequestMetrics", ctx, mock.Anything).Return(errors.New("connection refused")).Once()
+	require.Error(t, useCase.FlushMetrics(ctx))
+
+	// The requests of a failed flush are kept and added to the ones counted after it.
+	useCase.ObserveRequest(hour.Add(20*time.Minute), 200, 100*time.Millisecond)
+	sloRepo.On("AddRequestMetrics", ctx, []*domain.RequestMetrics{{Hour: hour, Requests: 4, Errors: 1, Slow: 1}}).Return(nil).Once()
+	require.NoError(t, useCase.FlushMetrics(ctx))
+
+	require.NoError(t, useCase.FlushMetrics(ctx), "nothing is written without new requests")
+	sloRepo.AssertNumberOfCalls(t, "AddRequestMetrics", 2)
+}
+
+func TestSLOUseCase_CheckBurnRate(t *testing.T) {
+	ctx := newTestContext()
+	sloRepo := new(MockSLORepository)
+	notifier := new(MockNotificationService)
+	useCase := usecase.NewSLOUseCase(sloRepo, notifier, newTestClock(), sloSettings)
+
+	hour := time.Date(2026, 5, 10, 18, 0, 0, 0, time.UTC)
+	sloRepo.On("SumRequestMetrics", ctx, hour.Add(-time.Hour), hour.Add(time.Hour)).
+		Return(&domain.RequestMetrics{Requests: 1000, Errors: 150, Slow: 50}, nil)
+	notifier.On("NotifyUser", ctx, "admin1", domain.NotificationTypeSLOAlert, "Availability error budget is burning fast", mock.Anything, "").Return(nil)
+
+	report, err := useCase.CheckBurnRate(ctx, hour.Add(20*time.Minute))
+	require.NoError(t, err)
+	assert.InDelta(t, 15, report.AvailabilityBurnRate, 0.001)
+	assert.InDelta(t, 0.5, report.LatencyBurnRate, 0.001)
+
+	// The same hour is alerted about only once.
+	_, err = useCase.CheckBurnRate(ctx, hour.Add(40*time.Minute))
+	require.NoError(t, err)
+	notifier.AssertNumberOfCalls(t, "NotifyUser", 1)
+
+	quiet := time.Date(2026, 5, 10, 4, 0, 0, 0, time.UTC)
+	sloRepo.On("SumRequestMetrics", ctx, quiet.Add(-time.Hour), quiet.Add(time.Hour)).
+		Return(&domain.RequestMetrics{Requests: 20, Errors: 10}, nil)
+	// Hours with few requests raise no alert.
+	_, err = useCase.CheckBurnRate(ctx, quiet)
+	require.NoError(t, err)
+	notifier.AssertNumberOfCalls(t, "NotifyUser", 1)
+}
+
+func TestSLOUseCase_GenerateDailyReport(t *testing.T) {
+	ctx := newTestContext()
+	sloRepo := new(MockSLORepository)
+	notifier := new(MockNotificationService)
+	useCase := usecase.NewSLOUseCase(sloRepo, notifier, newTestClock(), sloSettings)
+
+	day := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	sloRepo.On("SumRequestMetrics", ctx, day, day.AddDate(0, 0, 1)).
+		Return(&domain.RequestMetrics{Requests: 10000, Errors: 50, Slow: 1500}, nil)
+	sloRepo.On("SaveReport", ctx, mock.MatchedBy(func(report *domain.SLOReport) bool {
+		return report.Date.Equal(day) && report.Requests == 10000
+	})).Return(nil)
+	notifier.On("NotifyUser", ctx, "admin1", domain.NotificationTypeSLOAlert, "Latency objective missed on 10.05.2026", mock.Anything, "").Return(nil)
+
+	report, err := useCase.GenerateDailyReport(ctx, day.Add(23*time.Hour))
+
+	require.NoError(t, err)
+	assert.True(t, report.AvailabilityMet())
+	assert.False(t, report.LatencyMet())
+	sloRepo.AssertExpectations(t)
+	notifier.AssertNumberOfCalls(t, "NotifyUser", 1)
+}
+
+func TestSLOUseCase_GetReports(t *testing.T) {
+	ctx := newTestContext()
+	sloRepo := new(MockSLORepository)
+	useCase := usecase.NewSLOUseCase(sloRepo, new(MockNotificationService), newTestClock(), sloSettings)
+
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "s1", RestaurantIDs: []string{"r1"}, Roles: []tenant.Role{tenant.RoleRestaurantStaff}})
+	_, err := useCase.GetReports(staffCtx, from, to)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	adminCtx := tenant.NewContext(ctx, &tenant.Principal{UserID: "admin1", Roles: []tenant.Role{tenant.RoleAdmin}})
+	_, err = useCase.GetReports(adminCtx, to, from)
+	assert.ErrorIs(t, err, usecase.ErrInvalidSLOPeriod)
+	_, err = useCase.GetReports(adminCtx, from, from.AddDate(2, 0, 0))
+	assert.ErrorIs(t, err, usecase.ErrInvalidSLOPeriod)
+
+	sloRepo.On("ListReports", adminCtx, from, to).Return([]*domain.SLOReport{
+		domain.NewSLOReport(from, domain.RequestMetrics{Requests: 1000, Errors: 30}, sloSettings.Objectives),
+		domain.NewSLOReport(to, domain.RequestMetrics{Requests: 3000, Slow: 100}, sloSettings.Objectives),
+	}, nil)
+
+	period, err := useCase.GetReports(adminCtx, from, to)
+
+	require.NoError(t, err)
+	require.Len(t, period.Reports, 2)
+	assert.Equal(t, int64(4000), period.Summary.Requests)
+	assert.InDelta(t, 0.9925, period.Summary.Availability, 0.0001)
+	assert.True(t, period.Summary.AvailabilityMet(), "a bad day is made up for by a good one")
+	assert.False(t, period.Reports[0].AvailabilityMet())
+}
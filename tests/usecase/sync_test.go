@@ -0,0 +1,84 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSyncRepository struct {
+	mock.Mock
+}
+
+func (m *MockSyncRepository) ListChanges(ctx context.Context, entity domain.ExportEntity, after domain.SyncCursor, limit int) ([]*domain.SyncChange, error) {
+	args := m.Called(ctx, entity, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SyncChange), args.Error(1)
+}
+
+func TestListSyncChanges(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockSyncRepository)
+	syncUseCase := usecase.NewSyncUseCase(repo)
+
+	changedAt := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	since := domain.SyncCursor{ChangedAt: changedAt.Add(-time.Hour)}
+	repo.On("ListChanges", mock.Anything, domain.ExportBookings, since, 3).Return([]*domain.SyncChange{
+		{ID: "booking1", ChangedAt: changedAt, Data: json.RawMessage(`{"id":"booking1"}`)},
+		{ID: "booking2", ChangedAt: changedAt.Add(time.Minute), Deleted: true},
+		{ID: "booking3", ChangedAt: changedAt.Add(2 * time.Minute)},
+	}, nil)
+
+	page, err := syncUseCase.ListChanges(ctx, domain.ExportBookings, since, 2)
+
+	require.NoError(t, err)
+	require.Len(t, page.Changes, 2)
+	assert.True(t, page.HasMore)
+	assert.True(t, page.Changes[1].Deleted)
+	assert.Equal(t, domain.SyncCursor{ChangedAt: changedAt.Add(time.Minute), ID: "booking2"}, page.Next)
+}
+
+func TestListSyncChanges_NoChangesKeepsCursor(t *testing.T) {
+	ctx := adminContext()
+	repo := new(MockSyncRepository)
+	syncUseCase := usecase.NewSyncUseCase(repo)
+
+	since := domain.SyncCursor{ChangedAt: time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC), ID: "restaurant1"}
+	repo.On("ListChanges", mock.Anything, domain.ExportRestaurants, since, usecase.MaxSyncLimit+1).
+		Return([]*domain.SyncChange{}, nil)
+
+	page, err := syncUseCase.ListChanges(ctx, domain.ExportRestaurants, since, 5000)
+
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, since, page.Next)
+}
+
+func TestListSyncChanges_Rejected(t *testing.T) {
+	repo := new(MockSyncRepository)
+	syncUseCase := usecase.NewSyncUseCase(repo)
+
+	_, err := syncUseCase.ListChanges(adminContext(), "notifications", domain.SyncCursor{}, 0)
+	assert.ErrorIs(t, err, usecase.ErrInvalidExportEntity)
+
+	ctx := tenant.NewContext(setupTestContext(), &tenant.Principal{UserID: "user1"})
+	_, err = syncUseCase.ListChanges(ctx, domain.ExportBookings, domain.SyncCursor{}, 0)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+
+	_, err = syncUseCase.ListChanges(setupTestContext(), domain.ExportUsers, domain.SyncCursor{}, 0)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied, "a request without a principal is no admin")
+
+	repo.AssertNotCalled(t, "ListChanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,288 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/common"
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/internal/tenant"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTableRepository struct {
+	mock.Mock
+}
+
+func (m *MockTableRepository) Create(ctx context.Context, table *domain.Table) error {
+	args := m.Called(ctx, table)
+	return args.Error(0)
+}
+
+func (m *MockTableRepository) GetByID(ctx context.Context, id string) (*domain.Table, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Table), args.Error(1)
+}
+
+func (m *MockTableRepository) ListByRestaurant(ctx context.Context, restaurantID string) ([]*domain.Table, error) {
+	args := m.Called(ctx, restaurantID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Table), args.Error(1)
+}
+
+func (m *MockTableRepository) Update(ctx context.Context, table *domain.Table) error {
+	args := m.Called(ctx, table)
+	return args.Error(0)
+}
+
+func (m *MockTableRepository) ListHolds(ctx context.Context, restaurantID string, date time.Time) ([]domain.TableHold, error) {
+	args := m.Called(ctx, restaurantID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TableHold), args.Error(1)
+}
+
+func (m *MockTableRepository) ListSeatedRestaurants(ctx context.Context, date time.Time) ([]string, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTableRepository) Reseat(ctx context.Context, moves map[string]string, at time.Time) error {
+	args := m.Called(ctx, moves, at)
+	return args.Error(0)
+}
+
+func (m *MockTableRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateBooking_SeatsAtTable(t *testing.T) {
+	ctx := newTestContext()
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+
+	availabilityRepo := new(MockAvailabilityRepository)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("bistro"), date).Return([]*domain.Availability{
+		{ID: "slot", RestaurantID: "bistro", Date: date, TimeSlot: "19:00", Capacity: 20, Reserved: 20},
+	}, nil)
+
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("ListByRestaurant", ctx, "bistro").Return([]*domain.Table{
+		{ID: "t6", RestaurantID: "bistro", Name: "6", Seats: 6},
+		{ID: "t2", RestaurantID: "bistro", Name: "2", Seats: 2},
+		{ID: "t4", RestaurantID: "bistro", Name: "4", Seats: 4},
+		{ID: "t4b", RestaurantID: "bistro", Name: "4b", Seats: 4},
+	}, nil)
+	tableRepo.On("ListHolds", ctx, "bistro", date).Return([]domain.TableHold{
+		{TableID: "t4", BookingID: "other", Date: date, Time: "18:30", Duration: 120},
+	}, nil)
+
+	bookingRepo := new(MockBookingRepository)
+	bookingRepo.On("Create", ctx, mock.Anything).Return(nil)
+	notificationSvc := new(MockNotificationService)
+	notificationSvc.On("NotifyRestaurant", ctx, "bistro", domain.NotificationTypeNewBooking, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	bookings := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
+	newBooking := func(guests int, tableID string) *domain.Booking {
+		return &domain.Booking{RestaurantID: "bistro", UserID: "guest", Date: date, Time: "19:00", GuestsCount: guests, TableID: tableID}
+	}
+
+	booking := newBooking(3, "")
+	_, err := bookings.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+	assert.Equal(t, "t4b", booking.TableID, "the smallest free table the party fits at")
+
+	booking = newBooking(2, "t6")
+	_, err = bookings.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+	assert.Equal(t, "t6", booking.TableID, "the table the guest asked for")
+
+	_, err = bookings.CreateBooking(ctx, newBooking(2, "t4"))
+	assert.ErrorIs(t, err, usecase.ErrTableUnavailable, "held by another booking")
+	_, err = bookings.CreateBooking(ctx, newBooking(4, "t2"))
+	assert.ErrorIs(t, err, usecase.ErrTableUnavailable, "too small for the party")
+	_, err = bookings.CreateBooking(ctx, newBooking(7, ""))
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability, "no table the party fits at")
+	_, err = bookings.CreateBooking(ctx, &domain.Booking{RestaurantID: "bistro", UserID: "guest", Date: date, Time: "20:00", GuestsCount: 2})
+	assert.ErrorIs(t, err, usecase.ErrNoAvailability, "no slot at the time")
+	bookingRepo.AssertNumberOfCalls(t, "Create", 2)
+	availabilityRepo.AssertNotCalled(t, "UpdateReservedSeats", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateBooking_WithoutTables(t *testing.T) {
+	ctx := newTestContext()
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+
+	availabilityRepo := new(MockAvailabilityRepository)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("bistro"), date).Return([]*domain.Availability{
+		{ID: "slot", RestaurantID: "bistro", Date: date, TimeSlot: "19:00", Capacity: 20},
+	}, nil)
+	availabilityRepo.On("UpdateReservedSeats", ctx, "slot", 4).Return(nil)
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("ListByRestaurant", ctx, "bistro").Return([]*domain.Table{}, nil)
+	bookingRepo := new(MockBookingRepository)
+	bookingRepo.On("Create", ctx, mock.Anything).Return(nil)
+	notificationSvc := new(MockNotificationService)
+	notificationSvc.On("NotifyRestaurant", ctx, "bistro", domain.NotificationTypeNewBooking, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	bookings := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
+
+	booking := &domain.Booking{RestaurantID: "bistro", UserID: "guest", Date: date, Time: "19:00", GuestsCount: 4}
+	_, err := bookings.CreateBooking(ctx, booking)
+	require.NoError(t, err)
+	assert.Empty(t, booking.TableID, "the party is seated by the seats of the slot alone")
+	tableRepo.AssertNotCalled(t, "ListHolds", mock.Anything, mock.Anything, mock.Anything)
+
+	_, err = bookings.CreateBooking(ctx, &domain.Booking{RestaurantID: "bistro", UserID: "guest", Date: date, Time: "19:00", GuestsCount: 4, TableID: "t1"})
+	assert.ErrorIs(t, err, usecase.ErrTableUnavailable)
+}
+
+func TestCreateBooking_TableTakenMeanwhile(t *testing.T) {
+	ctx := newTestContext()
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+
+	availabilityRepo := new(MockAvailabilityRepository)
+	availabilityRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("bistro"), date).Return([]*domain.Availability{
+		{ID: "slot", RestaurantID: "bistro", Date: date, TimeSlot: "19:00", Capacity: 20},
+	}, nil)
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("ListByRestaurant", ctx, "bistro").Return([]*domain.Table{{ID: "t2", RestaurantID: "bistro", Name: "2", Seats: 2}}, nil)
+	tableRepo.On("ListHolds", ctx, "bistro", date).Return([]domain.TableHold{}, nil)
+	bookingRepo := new(MockBookingRepository)
+	bookingRepo.On("Create", ctx, mock.Anything).Return(errors.New(common.ErrTableUnavailable))
+
+	bookings := usecase.NewBookingUseCase(bookingRepo, availabilityRepo, tableRepo, new(MockNotificationService), &stubTransactor{}, newTestClock())
+
+	_, err := bookings.CreateBooking(ctx, &domain.Booking{RestaurantID: "bistro", UserID: "guest", Date: date, Time: "19:00", GuestsCount: 2})
+	assert.ErrorIs(t, err, usecase.ErrTableUnavailable)
+	availabilityRepo.AssertNotCalled(t, "UpdateReservedSeats", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAcceptAlternative_Reseats(t *testing.T) {
+	ctx := newTestContext()
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("ListByRestaurant", ctx, "bistro").Return([]*domain.Table{
+		{ID: "t2", RestaurantID: "bistro", Name: "2", Seats: 2},
+		{ID: "t4", RestaurantID: "bistro", Name: "4", Seats: 4},
+		{ID: "t6", RestaurantID: "bistro", Name: "6", Seats: 6},
+	}, nil)
+	tableRepo.On("ListHolds", ctx, "bistro", date).Return([]domain.TableHold{
+		{TableID: "t4", BookingID: "moved", Date: date, Time: "19:00", Duration: 120},
+		{TableID: "t4", BookingID: "other", Date: date, Time: "22:00", Duration: 120},
+	}, nil)
+	bookingRepo := new(MockBookingRepository)
+	bookingRepo.On("GetByID", ctx, domain.BookingID("moved")).Return(&domain.Booking{
+		ID: "moved", RestaurantID: "bistro", UserID: "guest", Date: date, Time: "19:00", GuestsCount: 3, TableID: "t4",
+		Status: domain.BookingStatusPending,
+	}, nil)
+	for id, clock := range map[string]string{"early": "17:00", "late": "21:00"} {
+		bookingRepo.On("GetAlternativeByID", ctx, id).Return(&domain.BookingAlternative{ID: id, BookingID: "moved", Date: date, Time: clock}, nil)
+	}
+	bookingRepo.On("AcceptAlternative", ctx, mock.Anything, mock.Anything).Return(nil)
+	notificationSvc := new(MockNotificationService)
+	notificationSvc.On("NotifyRestaurant", ctx, "bistro", domain.NotificationTypeAlternativeAccepted, mock.Anything, mock.Anything, "moved").Return(nil)
+
+	bookings := usecase.NewBookingUseCase(bookingRepo, new(MockAvailabilityRepository), tableRepo, notificationSvc, &stubTransactor{}, newTestClock())
+
+	require.NoError(t, bookings.AcceptAlternative(ctx, "early"))
+	bookingRepo.AssertCalled(t, "AcceptAlternative", ctx, "early", "t4")
+	require.NoError(t, bookings.AcceptAlternative(ctx, "late"))
+	bookingRepo.AssertCalled(t, "AcceptAlternative", ctx, "late", "t6")
+}
+
+func TestTableUseCase(t *testing.T) {
+	ctx := newTestContext()
+
+	restaurantRepo := new(MockRestaurantRepository)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("bistro")).Return(&domain.Restaurant{ID: "bistro"}, nil)
+	restaurantRepo.On("GetByID", ctx, domain.RestaurantID("gone")).Return(nil, errors.New(common.ErrRestaurantNotFound))
+	tableRepo := new(MockTableRepository)
+	tables := usecase.NewTableUseCase(tableRepo, restaurantRepo, new(MockBookingRepository))
+
+	assert.ErrorIs(t, tables.CreateTable(ctx, &domain.Table{RestaurantID: "bistro", Name: " ", Seats: 2}), domain.ErrInvalidEntity)
+	assert.EqualError(t, tables.CreateTable(ctx, &domain.Table{RestaurantID: "gone", Name: "1", Seats: 2}), common.ErrRestaurantNotFound)
+
+	window := &domain.Table{RestaurantID: "bistro", Name: " Window ", Seats: 2, Zone: " Terrace "}
+	tableRepo.On("Create", ctx, window).Return(nil).Once()
+	require.NoError(t, tables.CreateTable(ctx, window))
+	assert.Equal(t, "Window", window.Name)
+	assert.Equal(t, "Terrace", window.Zone)
+
+	staffCtx := tenant.NewContext(ctx, &tenant.Principal{
+		UserID:        "user1",
+		RestaurantIDs: []string{"other"},
+		Roles:         []tenant.Role{tenant.RoleRestaurantStaff},
+	})
+	assert.ErrorIs(t, tables.CreateTable(staffCtx, &domain.Table{RestaurantID: "bistro", Name: "1", Seats: 2}), tenant.ErrAccessDenied)
+	assert.ErrorIs(t, tables.DeleteTable(staffCtx, "bistro", "t1"), tenant.ErrAccessDenied)
+
+	tableRepo.On("GetByID", ctx, "t1").Return(&domain.Table{ID: "t1", RestaurantID: "bistro", Name: "1", Seats: 2}, nil)
+	tableRepo.On("GetByID", ctx, "missing").Return(nil, errors.New(common.ErrTableNotFound))
+
+	_, err := tables.GetTable(ctx, "other", "t1")
+	assert.EqualError(t, err, common.ErrTableNotFound, "a table of another restaurant")
+	assert.EqualError(t, tables.UpdateTable(ctx, &domain.Table{ID: "missing", RestaurantID: "bistro", Name: "1", Seats: 2}), common.ErrTableNotFound)
+
+	bigger := &domain.Table{ID: "t1", RestaurantID: "bistro", Name: "1", Seats: 4}
+	tableRepo.On("Update", ctx, bigger).Return(nil).Once()
+	require.NoError(t, tables.UpdateTable(ctx, bigger))
+
+	tableRepo.On("Delete", ctx, "t1").Return(errors.New(common.ErrTableBooked)).Once()
+	assert.EqualError(t, tables.DeleteTable(ctx, "bistro", "t1"), common.ErrTableBooked)
+	tableRepo.AssertExpectations(t)
+}
+
+func TestOptimizeSeating(t *testing.T) {
+	ctx := adminContext()
+	date := time.Date(2026, time.August, 14, 0, 0, 0, 0, time.UTC)
+	now := date.Add(18*time.Hour + 30*time.Minute)
+
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("ListSeatedRestaurants", ctx, date).Return([]string{"bistro"}, nil)
+	tableRepo.On("ListByRestaurant", ctx, "bistro").Return([]*domain.Table{
+		{ID: "t2", RestaurantID: "bistro", Name: "2", Seats: 2},
+		{ID: "t4", RestaurantID: "bistro", Name: "4", Seats: 4},
+		{ID: "t6", RestaurantID: "bistro", Name: "6", Seats: 6},
+	}, nil)
+	tableRepo.On("ListHolds", ctx, "bistro", date).Return([]domain.TableHold{
+		{TableID: "t2", BookingID: "started", Date: date, Time: "18:00", Duration: 120},
+		{TableID: "t6", BookingID: "pair", Date: date, Time: "21:00", Duration: 120},
+		{TableID: "t4", BookingID: "asked", Date: date, Time: "21:00", Duration: 120},
+	}, nil)
+	tableRepo.On("Reseat", ctx, map[string]string{"pair": "t2"}, now).Return(nil)
+
+	bookingRepo := new(MockBookingRepository)
+	bookingRepo.On("GetByRestaurantAndDate", ctx, domain.RestaurantID("bistro"), date).Return([]*domain.Booking{
+		{ID: "started", RestaurantID: "bistro", Date: date, Time: "18:00", Duration: 120, GuestsCount: 1, TableID: "t2", Status: domain.BookingStatusConfirmed},
+		{ID: "pair", RestaurantID: "bistro", Date: date, Time: "21:00", Duration: 120, GuestsCount: 2, TableID: "t6", Status: domain.BookingStatusPending},
+		{ID: "asked", RestaurantID: "bistro", Date: date, Time: "21:00", Duration: 120, GuestsCount: 1, TableID: "t4", TableRequested: true, Status: domain.BookingStatusConfirmed},
+		{ID: "cancelled", RestaurantID: "bistro", Date: date, Time: "21:00", Duration: 120, GuestsCount: 2, TableID: "t2", Status: domain.BookingStatusCancelled},
+	}, nil)
+
+	tables := usecase.NewTableUseCase(tableRepo, new(MockRestaurantRepository), bookingRepo)
+
+	moved, err := tables.OptimizeSeating(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved, "the pair moves to the two-top once the party seated at it leaves; the table asked for stays")
+	tableRepo.AssertExpectations(t)
+
+	_, err = tables.OptimizeSeating(newTestContext(), now)
+	assert.ErrorIs(t, err, tenant.ErrAccessDenied)
+}
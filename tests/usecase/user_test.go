@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/flexer2006/case-back-restaurant-go/internal/contact"
 	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
 	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
 
@@ -18,7 +19,7 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id domain.UserID) (*domain.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -55,8 +56,11 @@ func createTestUser() *domain.User {
 		Name:      "test user",
 		Email:     "test@example.com",
 		Phone:     "+7 (123) 456-78-90",
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		CreatedAt: testNow,
+		UpdatedAt: testNow,
+
+		NormalizedEmail: "test@example.com",
+		NormalizedPhone: "+71234567890",
 	}
 }
 
@@ -64,15 +68,15 @@ func TestUserUseCase_GetUser(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	userID := uuid.New().String()
 	expectedUser := createTestUser()
 	expectedUser.ID = userID
 
-	mockUserRepo.On("GetByID", ctx, userID).Return(expectedUser, nil)
+	mockUserRepo.On("GetByID", ctx, domain.UserID(userID)).Return(expectedUser, nil)
 
-	result, err := useCase.GetUser(ctx, userID)
+	result, err := useCase.GetUser(ctx, domain.UserID(userID))
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, result)
@@ -83,14 +87,14 @@ func TestUserUseCase_GetUserNotFound(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	userID := uuid.New().String()
 	expectedError := errors.New("user not found")
 
-	mockUserRepo.On("GetByID", ctx, userID).Return(nil, expectedError)
+	mockUserRepo.On("GetByID", ctx, domain.UserID(userID)).Return(nil, expectedError)
 
-	result, err := useCase.GetUser(ctx, userID)
+	result, err := useCase.GetUser(ctx, domain.UserID(userID))
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -102,7 +106,7 @@ func TestUserUseCase_GetUserByEmail(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	email := "test@example.com"
 	expectedUser := createTestUser()
@@ -121,7 +125,7 @@ func TestUserUseCase_GetUserByEmailNotFound(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	email := "nonexistent@example.com"
 	expectedError := errors.New("user not found")
@@ -140,7 +144,7 @@ func TestUserUseCase_CreateUser(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	newUser := &domain.User{
 		Name:  "new user",
@@ -171,7 +175,7 @@ func TestUserUseCase_CreateUserEmailExists(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	existingUser := createTestUser()
 	newUser := &domain.User{
@@ -194,7 +198,8 @@ func TestUserUseCase_UpdateUser(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	clk := newTestClock()
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", clk)
 
 	existingUser := createTestUser()
 	updatedUser := &domain.User{
@@ -208,15 +213,16 @@ func TestUserUseCase_UpdateUser(t *testing.T) {
 
 	oldUpdateTime := updatedUser.UpdatedAt
 
-	time.Sleep(1 * time.Millisecond)
+	clk.Advance(time.Minute)
 
-	mockUserRepo.On("GetByID", ctx, updatedUser.ID).Return(existingUser, nil)
+	mockUserRepo.On("GetByID", ctx, domain.UserID(updatedUser.ID)).Return(existingUser, nil)
 	mockUserRepo.On("Update", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
 
 	err := useCase.UpdateUser(ctx, updatedUser)
 
 	assert.NoError(t, err)
 	assert.True(t, updatedUser.UpdatedAt.After(oldUpdateTime))
+	assert.Equal(t, clk.Now(), updatedUser.UpdatedAt)
 	mockUserRepo.AssertExpectations(t)
 }
 
@@ -224,11 +230,11 @@ func TestUserUseCase_UpdateUserNotFound(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	updatedUser := createTestUser()
 
-	mockUserRepo.On("GetByID", ctx, updatedUser.ID).Return(nil, usecase.ErrUserNotFound)
+	mockUserRepo.On("GetByID", ctx, domain.UserID(updatedUser.ID)).Return(nil, usecase.ErrUserNotFound)
 
 	err := useCase.UpdateUser(ctx, updatedUser)
 
@@ -241,7 +247,7 @@ func TestUserUseCase_UpdateUserEmailExists(t *testing.T) {
 	ctx := newTestContext()
 	mockUserRepo := new(MockUserRepository)
 
-	useCase := usecase.NewUserUseCase(mockUserRepo)
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
 
 	existingUser := createTestUser()
 	anotherUser := createTestUser()
@@ -257,7 +263,7 @@ func TestUserUseCase_UpdateUserEmailExists(t *testing.T) {
 		UpdatedAt: existingUser.UpdatedAt,
 	}
 
-	mockUserRepo.On("GetByID", ctx, updatedUser.ID).Return(existingUser, nil)
+	mockUserRepo.On("GetByID", ctx, domain.UserID(updatedUser.ID)).Return(existingUser, nil)
 	mockUserRepo.On("GetByEmail", ctx, updatedUser.Email).Return(anotherUser, nil)
 
 	err := useCase.UpdateUser(ctx, updatedUser)
@@ -266,3 +272,67 @@ func TestUserUseCase_UpdateUserEmailExists(t *testing.T) {
 	assert.Equal(t, usecase.ErrEmailExists, err)
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestUserUseCase_CreateUserNormalizesContacts(t *testing.T) {
+	ctx := newTestContext()
+	mockUserRepo := new(MockUserRepository)
+
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
+
+	newUser := &domain.User{
+		Name:  "new user",
+		Email: "New.User@Example.COM",
+		Phone: "8 (987) 654-32-10",
+	}
+
+	mockUserRepo.On("GetByEmail", ctx, "new.user@example.com").Return(nil, errors.New("user not found"))
+	mockUserRepo.On("Create", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+
+	_, err := useCase.CreateUser(ctx, newUser)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New.User@Example.COM", newUser.Email)
+	assert.Equal(t, "new.user@example.com", newUser.NormalizedEmail)
+	assert.Equal(t, "8 (987) 654-32-10", newUser.Phone)
+	assert.Equal(t, "+79876543210", newUser.NormalizedPhone)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserUseCase_CreateUserInvalidContacts(t *testing.T) {
+	ctx := newTestContext()
+	mockUserRepo := new(MockUserRepository)
+
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
+
+	_, err := useCase.CreateUser(ctx, &domain.User{Name: "new user", Email: "new@localhost", Phone: "+7 987 654-32-10"})
+	assert.ErrorIs(t, err, contact.ErrInvalidEmail)
+
+	_, err = useCase.CreateUser(ctx, &domain.User{Name: "new user", Email: "new@example.com", Phone: "987 65"})
+	assert.ErrorIs(t, err, contact.ErrInvalidPhone)
+
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserUseCase_UpdateUserEmailChangesCase(t *testing.T) {
+	ctx := newTestContext()
+	mockUserRepo := new(MockUserRepository)
+
+	useCase := usecase.NewUserUseCase(mockUserRepo, "RU", newTestClock())
+
+	existingUser := createTestUser()
+	updatedUser := &domain.User{
+		ID:    existingUser.ID,
+		Name:  existingUser.Name,
+		Email: "Test@Example.com",
+		Phone: existingUser.Phone,
+	}
+
+	mockUserRepo.On("GetByID", ctx, domain.UserID(updatedUser.ID)).Return(existingUser, nil)
+	mockUserRepo.On("Update", ctx, mock.AnythingOfType("*domain.User")).Return(nil)
+
+	err := useCase.UpdateUser(ctx, updatedUser)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
@@ -0,0 +1,166 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flexer2006/case-back-restaurant-go/internal/domain"
+	"github.com/flexer2006/case-back-restaurant-go/pkg/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRestaurantPerformanceRepository struct {
+	mock.Mock
+}
+
+func (m *MockRestaurantPerformanceRepository) GetBetween(ctx context.Context, from, to time.Time) ([]*domain.RestaurantPerformance, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RestaurantPerformance), args.Error(1)
+}
+
+func TestBuildWeeklyReports(t *testing.T) {
+	performanceRepo := new(MockRestaurantPerformanceRepository)
+
+	to := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+	performanceRepo.On("GetBetween", mock.Anything, from, to).Return([]*domain.RestaurantPerformance{
+		{RestaurantID: "r1", From: from, To: to, Bookings: 12},
+		{RestaurantID: "r2", From: from, To: to, Bookings: 3},
+	}, nil)
+	performanceRepo.On("GetBetween", mock.Anything, from.AddDate(0, 0, -7), from).Return([]*domain.RestaurantPerformance{
+		{RestaurantID: "r1", Bookings: 10},
+	}, nil)
+
+	uc := usecase.NewWeeklyReportUseCase(performanceRepo, new(MockNotificationSettingsRepository), new(MockEmailService), nil)
+	reports, err := uc.BuildWeeklyReports(newTestContext(), to.Add(8*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, 2, reports[0].BookingsChange())
+	assert.Equal(t, time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), reports[0].LastDay())
+	assert.Equal(t, 3, reports[1].BookingsChange(), "a restaurant without a week before compares to nothing")
+	assert.Equal(t, from, reports[1].Previous.To)
+}
+
+func TestSendWeeklyReports(t *testing.T) {
+	performanceRepo := new(MockRestaurantPerformanceRepository)
+	settingsRepo := new(MockNotificationSettingsRepository)
+	emailService := new(MockEmailService)
+
+	to := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+	performanceRepo.On("GetBetween", mock.Anything, from, to).Return([]*domain.RestaurantPerformance{
+		{RestaurantID: "r1", RestaurantName: "Bistro", ContactEmail: "bistro@example.com", From: from, To: to},
+		{RestaurantID: "r2", RestaurantName: "Opted out", ContactEmail: "out@example.com", From: from, To: to},
+		{RestaurantID: "r3", RestaurantName: "No email", From: from, To: to},
+		{RestaurantID: "r4", RestaurantName: "Failing", ContactEmail: "failing@example.com", From: from, To: to},
+	}, nil)
+	performanceRepo.On("GetBetween", mock.Anything, from.AddDate(0, 0, -7), from).Return([]*domain.RestaurantPerformance{}, nil)
+
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeRestaurant, "r1").Return(nil, errors.New("db down"))
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeRestaurant, "r2").Return(&domain.NotificationSettings{
+		Preferences: []domain.NotificationPreference{
+			{Type: domain.NotificationTypeWeeklyReport, Channel: domain.NotificationChannelEmail, Enabled: false},
+		},
+	}, nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeRestaurant, "r4").Return(&domain.NotificationSettings{}, nil)
+
+	emailService.On("SendEmail", "bistro@example.com", "Your week in review: 2025-05-04", mock.Anything).Return(nil)
+	emailService.On("SendEmail", "failing@example.com", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+	uc := usecase.NewWeeklyReportUseCase(performanceRepo, settingsRepo, emailService, nil)
+	sent, err := uc.SendWeeklyReports(newTestContext(), to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "opted out restaurants and those without an email get no report")
+
+	emailService.AssertExpectations(t)
+	emailService.AssertNotCalled(t, "SendEmail", "out@example.com", mock.Anything, mock.Anything)
+}
+
+func TestFormatWeeklyReport(t *testing.T) {
+	to := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+
+	body, err := usecase.FormatWeeklyReport(&usecase.WeeklyReport{
+		Current: &domain.RestaurantPerformance{
+			RestaurantName:      "Bistro",
+			From:                from,
+			To:                  to,
+			Bookings:            12,
+			Guests:              30,
+			Cancellations:       2,
+			SeatsReserved:       30,
+			SeatsOffered:        40,
+			Reviews:             3,
+			AverageRating:       4.33,
+			Responses:           10,
+			AverageResponseTime: 83*time.Minute + 20*time.Second,
+		},
+		Previous: &domain.RestaurantPerformance{
+			Bookings:      15,
+			SeatsReserved: 20,
+			SeatsOffered:  40,
+			Reviews:       1,
+			AverageRating: 5,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Weekly report for Bistro, Mon 2025-04-28 to Sun 2025-05-04\n\n"+
+		"Bookings: 12 (-3 on the week before), 30 guests\n"+
+		"Occupancy: 75% of the seats offered (50% the week before)\n"+
+		"Cancellations: 2 of 12 bookings\n"+
+		"Rating: 4.3 from 3 new reviews (5.0 the week before)\n"+
+		"Response time: 1h 23m on average to answer 10 booking requests\n\n"+
+		"You get this report every week. To stop it, turn off the email channel of \"weekly_report\" in your notification settings.\n", body)
+
+	body, err = usecase.FormatWeeklyReport(&usecase.WeeklyReport{
+		Current:  &domain.RestaurantPerformance{RestaurantName: "Quiet", From: from, To: to},
+		Previous: &domain.RestaurantPerformance{},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, body, "Occupancy: 0% of the seats offered\n")
+	assert.Contains(t, body, "Cancellations: 0\n")
+	assert.Contains(t, body, "Rating: no new reviews\n")
+	assert.Contains(t, body, "Response time: no booking requests answered\n")
+}
+
+func TestSendWeeklyReports_DefersDuringQuietHours(t *testing.T) {
+	performanceRepo := new(MockRestaurantPerformanceRepository)
+	settingsRepo := new(MockNotificationSettingsRepository)
+	emailService := new(MockEmailService)
+	deferredRepo := new(MockDeferredNotificationRepository)
+
+	to := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	from := to.AddDate(0, 0, -7)
+	performanceRepo.On("GetBetween", mock.Anything, from, to).Return([]*domain.RestaurantPerformance{
+		{RestaurantID: "r1", RestaurantName: "Bistro", ContactEmail: "bistro@example.com", From: from, To: to},
+	}, nil)
+	performanceRepo.On("GetBetween", mock.Anything, from.AddDate(0, 0, -7), from).Return([]*domain.RestaurantPerformance{}, nil)
+	settingsRepo.On("Get", mock.Anything, domain.RecipientTypeRestaurant, "r1").Return(&domain.NotificationSettings{
+		RecipientType: domain.RecipientTypeRestaurant,
+		RecipientID:   "r1",
+		QuietHours:    lunchQuietHours(domain.NotificationChannelEmail),
+	}, nil)
+	deferredRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.DeferredNotification) bool {
+		return n.RecipientID == "r1" && n.NotificationType == domain.NotificationTypeWeeklyReport &&
+			n.Channel == domain.NotificationChannelEmail && n.Title == "Your week in review: 2025-05-04"
+	})).Return(nil)
+
+	deferrals := newTestNotificationDeferral(deferredRepo, settingsRepo, nil, nil, nil, emailService)
+	uc := usecase.NewWeeklyReportUseCase(performanceRepo, settingsRepo, emailService, deferrals)
+	sent, err := uc.SendWeeklyReports(newTestContext(), to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "a deferred report counts as sent")
+
+	deferredRepo.AssertExpectations(t)
+	emailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+}